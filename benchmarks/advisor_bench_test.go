@@ -1,6 +1,7 @@
 package benchmarks
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -31,7 +32,7 @@ func BenchmarkGenerateAdvice(b *testing.B) {
 
 	// Create test user
 	user := domain.User{RiskTolerance: "moderate"}
-	userService.Create(&user)
+	userService.Create(context.Background(), &user)
 
 	// Create sample transactions
 	for i := 0; i < 100; i++ {
@@ -39,16 +40,16 @@ func BenchmarkGenerateAdvice(b *testing.B) {
 			UserID:      1,
 			Type:        "income",
 			Description: "Test income",
-			Amount:      5000.0,
+			Amount:      domain.NewMoney(5000.0),
 			CategoryID:  1,
 			Date:        time.Now().AddDate(0, -1, 0),
 		}
-		txService.Create(&tx)
+		txService.Create(context.Background(), &tx)
 	}
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, err := advisorService.GenerateAdvice(&user)
+		_, err := advisorService.GenerateAdvice(context.Background(), &user)
 		if err != nil {
 			b.Fatal(err)
 		}
@@ -66,16 +67,16 @@ func BenchmarkCalculateMonthlySavings(b *testing.B) {
 			UserID:      1,
 			Type:        "income",
 			Description: "Test income",
-			Amount:      5000.0,
+			Amount:      domain.NewMoney(5000.0),
 			CategoryID:  1,
 			Date:        time.Now().AddDate(0, -1, 0),
 		}
-		txService.Create(&tx)
+		txService.Create(context.Background(), &tx)
 	}
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, err := advisorService.CalculateMonthlySavings(1)
+		_, err := advisorService.CalculateMonthlySavings(context.Background(), 1)
 		if err != nil {
 			b.Fatal(err)
 		}
@@ -92,11 +93,11 @@ func BenchmarkTransactionCreate(b *testing.B) {
 			UserID:      1,
 			Type:        "income",
 			Description: "Benchmark test",
-			Amount:      1000.0,
+			Amount:      domain.NewMoney(1000.0),
 			CategoryID:  1,
 			Date:        time.Now(),
 		}
-		err := txService.Create(&tx)
+		err := txService.Create(context.Background(), &tx)
 		if err != nil {
 			b.Fatal(err)
 		}
@@ -113,16 +114,16 @@ func BenchmarkTransactionList(b *testing.B) {
 			UserID:      1,
 			Type:        "income",
 			Description: "Test transaction",
-			Amount:      1000.0,
+			Amount:      domain.NewMoney(1000.0),
 			CategoryID:  1,
 			Date:        time.Now(),
 		}
-		txService.Create(&tx)
+		txService.Create(context.Background(), &tx)
 	}
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, err := txService.List(1)
+		_, err := txService.List(context.Background(), 1)
 		if err != nil {
 			b.Fatal(err)
 		}
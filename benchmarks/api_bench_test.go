@@ -0,0 +1,157 @@
+package benchmarks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/application"
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/api"
+	"go-finance-advisor/internal/infrastructure/persistence"
+	"go-finance-advisor/internal/infrastructure/persistence/migrations"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm/logger"
+)
+
+// The API benchmarks serve requests from a database file seeded the way
+// `finance-advisor seed` does, so they exercise the same queries as the load
+// tests in benchmarks/load. Their numbers are recorded in baseline.txt.
+const (
+	seedUsers        = 5
+	seedTransactions = 2000
+	seedMonths       = 24
+)
+
+var (
+	seedOnce     sync.Once
+	seedDir      string
+	seedErr      error
+	seededRouter *gin.Engine
+	seededExpCat uint
+	// Transactions are created for another user than the one read, so the
+	// reads measure the same data however long the writes ran
+	readerUserID, writerUserID uint
+)
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if seedDir != "" {
+		os.RemoveAll(seedDir)
+	}
+	os.Exit(code)
+}
+
+// setupSeededAPI seeds the database the first time an API benchmark runs
+// and returns the router serving the benchmarked endpoints
+func setupSeededAPI(b *testing.B) *gin.Engine {
+	seedOnce.Do(func() { seedErr = seedAPI() })
+	if seedErr != nil {
+		b.Fatal(seedErr)
+	}
+	return seededRouter
+}
+
+func seedAPI() error {
+	dir, err := os.MkdirTemp("", "finance-bench")
+	if err != nil {
+		return err
+	}
+	seedDir = dir
+	db, err := persistence.OpenSQLite(filepath.Join(dir, "finance.db"), 5*time.Second)
+	if err != nil {
+		return err
+	}
+	db.Logger = logger.Default.LogMode(logger.Silent)
+	ctx := context.Background()
+	if _, err := migrations.New(db).Up(ctx); err != nil {
+		return err
+	}
+	_, err = application.NewSeedService(db).Seed(ctx, application.SeedOptions{
+		Users: seedUsers, TransactionsPerUser: seedTransactions, Months: seedMonths,
+		Until: time.Now(), Seed: 1, Password: "password123",
+	})
+	if err != nil {
+		return err
+	}
+
+	var reader, writer domain.User
+	if err := db.Where("email = ?", application.SeedEmail(1, 1)).First(&reader).Error; err != nil {
+		return err
+	}
+	if err := db.Where("email = ?", application.SeedEmail(1, seedUsers)).First(&writer).Error; err != nil {
+		return err
+	}
+	var category domain.Category
+	if err := db.Where("user_id IS NULL AND type = ?", domain.TransactionTypeExpense).First(&category).Error; err != nil {
+		return err
+	}
+	readerUserID, writerUserID, seededExpCat = reader.ID, writer.ID, category.ID
+
+	userSvc := &application.UserService{DB: db}
+	txHandler := &api.TransactionHandler{Service: &application.TransactionService{DB: db}}
+	analyticsHandler := &api.AnalyticsHandler{Service: &application.AnalyticsService{DB: db}}
+	advisorHandler := api.NewAdvisorHandler(&application.AdvisorService{DB: db}, userSvc, nil)
+
+	gin.SetMode(gin.ReleaseMode)
+	router := gin.New()
+	// Requests are authenticated as the user of their path
+	router.Use(func(c *gin.Context) {
+		var userID uint
+		fmt.Sscan(c.Param("userId"), &userID)
+		c.Set("userID", userID)
+		c.Next()
+	})
+	router.POST("/users/:userId/transactions", txHandler.Create)
+	router.GET("/users/:userId/transactions", txHandler.List)
+	router.GET("/users/:userId/analytics/dashboard", analyticsHandler.GetDashboardSummary)
+	router.GET("/users/:userId/advice", advisorHandler.GetAdvice)
+	seededRouter = router
+	return nil
+}
+
+// benchmarkEndpoint serves the request b.N times, failing on any status
+// other than want, and reports the throughput next to the latency
+func benchmarkEndpoint(b *testing.B, router *gin.Engine, method, path string, body []byte, want int) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != want {
+			b.Fatalf("%s %s: status %d: %s", method, path, w.Code, w.Body.String())
+		}
+	}
+	b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "req/s")
+}
+
+func BenchmarkAPICreateTransaction(b *testing.B) {
+	router := setupSeededAPI(b)
+	body := fmt.Appendf(nil, `{"amount":12.5,"type":"expense","description":"Benchmark","category_id":%d}`, seededExpCat)
+	benchmarkEndpoint(b, router, http.MethodPost, fmt.Sprintf("/users/%d/transactions", writerUserID), body, http.StatusCreated)
+}
+
+func BenchmarkAPIListTransactions(b *testing.B) {
+	router := setupSeededAPI(b)
+	benchmarkEndpoint(b, router, http.MethodGet, fmt.Sprintf("/users/%d/transactions?limit=50", readerUserID), nil, http.StatusOK)
+}
+
+func BenchmarkAPIDashboard(b *testing.B) {
+	router := setupSeededAPI(b)
+	benchmarkEndpoint(b, router, http.MethodGet, fmt.Sprintf("/users/%d/analytics/dashboard", readerUserID), nil, http.StatusOK)
+}
+
+func BenchmarkAPIAdvice(b *testing.B) {
+	router := setupSeededAPI(b)
+	benchmarkEndpoint(b, router, http.MethodGet, fmt.Sprintf("/users/%d/advice", readerUserID), nil, http.StatusOK)
+}
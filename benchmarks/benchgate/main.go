@@ -0,0 +1,178 @@
+// Command benchgate compares a run of the benchmarks with the recorded
+// baseline and fails when one got slower, or allocates more, than the
+// threshold allows. Both files hold `go test -bench -benchmem` output; a
+// benchmark run several times (-count) is compared by its median.
+//
+//	go run ./benchmarks/benchgate -baseline benchmarks/baseline.txt -current bench.txt -threshold 25
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+func main() {
+	baseline := flag.String("baseline", "benchmarks/baseline.txt", "benchmark output to compare with")
+	current := flag.String("current", "", "benchmark output of the run to check")
+	threshold := flag.Float64("threshold", 25, "largest slowdown or allocation growth allowed, in percent")
+	flag.Parse()
+
+	if err := run(*baseline, *current, *threshold, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "benchgate:", err)
+		os.Exit(1)
+	}
+}
+
+func run(baselinePath, currentPath string, threshold float64, out io.Writer) error {
+	if currentPath == "" {
+		return fmt.Errorf("-current is required")
+	}
+	baseline, err := parseFile(baselinePath)
+	if err != nil {
+		return err
+	}
+	current, err := parseFile(currentPath)
+	if err != nil {
+		return err
+	}
+
+	regressions := compare(baseline, current, threshold, out)
+	if regressions > 0 {
+		return fmt.Errorf("%d benchmark(s) regressed by more than %.0f%%", regressions, threshold)
+	}
+	return nil
+}
+
+// result is the median of a benchmark's runs, by unit, such as ns/op
+type result map[string]float64
+
+func parseFile(path string) (map[string]result, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return parse(file)
+}
+
+// parse reads the benchmark lines of go test output, skipping everything
+// else, and takes the median of each benchmark's runs
+func parse(r io.Reader) (map[string]result, error) {
+	runs := make(map[string]map[string][]float64)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || !strings.HasPrefix(fields[0], "Benchmark") {
+			continue
+		}
+		if _, err := strconv.Atoi(fields[1]); err != nil {
+			continue
+		}
+		// BenchmarkName-8 holds GOMAXPROCS, which may differ between machines
+		name := fields[0]
+		if i := strings.LastIndex(name, "-"); i > 0 {
+			if _, err := strconv.Atoi(name[i+1:]); err == nil {
+				name = name[:i]
+			}
+		}
+		if runs[name] == nil {
+			runs[name] = make(map[string][]float64)
+		}
+		for i := 2; i+1 < len(fields); i += 2 {
+			value, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("benchmark %s: invalid %s %q", name, fields[i+1], fields[i])
+			}
+			runs[name][fields[i+1]] = append(runs[name][fields[i+1]], value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]result, len(runs))
+	for name, units := range runs {
+		results[name] = make(result, len(units))
+		for unit, values := range units {
+			results[name][unit] = median(values)
+		}
+	}
+	return results, nil
+}
+
+func median(values []float64) float64 {
+	slices.Sort(values)
+	middle := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[middle-1] + values[middle]) / 2
+	}
+	return values[middle]
+}
+
+// gatedUnits are the units a regression fails the gate on. Time depends on
+// the machine, so the baseline should come from the one running the gate;
+// allocations do not.
+var gatedUnits = []string{"ns/op", "allocs/op"}
+
+// compare prints each benchmark's change against the baseline and returns
+// how many regressed past the threshold. Benchmarks missing from either run
+// are reported but do not fail the gate.
+func compare(baseline, current map[string]result, threshold float64, out io.Writer) int {
+	names := make([]string, 0, len(baseline))
+	for name := range baseline {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	regressions := 0
+	fmt.Fprintf(out, "%-40s %14s %14s %9s %12s %12s %9s\n",
+		"benchmark", "base ns/op", "ns/op", "delta", "base allocs", "allocs", "delta")
+	for _, name := range names {
+		now, ok := current[name]
+		if !ok {
+			fmt.Fprintf(out, "%-40s missing from the current run\n", name)
+			continue
+		}
+		then := baseline[name]
+		regressed := false
+		deltas := make([]float64, len(gatedUnits))
+		for i, unit := range gatedUnits {
+			deltas[i] = change(then[unit], now[unit])
+			if deltas[i] > threshold {
+				regressed = true
+			}
+		}
+		status := ""
+		if regressed {
+			regressions++
+			status = "  REGRESSED"
+		}
+		fmt.Fprintf(out, "%-40s %14.0f %14.0f %+8.1f%% %12.0f %12.0f %+8.1f%%%s\n",
+			name, then["ns/op"], now["ns/op"], deltas[0], then["allocs/op"], now["allocs/op"], deltas[1], status)
+	}
+	added := make([]string, 0)
+	for name := range current {
+		if _, ok := baseline[name]; !ok {
+			added = append(added, name)
+		}
+	}
+	slices.Sort(added)
+	for _, name := range added {
+		fmt.Fprintf(out, "%-40s not in the baseline yet\n", name)
+	}
+	return regressions
+}
+
+// change is how much now grew over then, in percent
+func change(then, now float64) float64 {
+	if then == 0 {
+		return 0
+	}
+	return (now - then) / then * 100
+}
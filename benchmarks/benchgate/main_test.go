@@ -0,0 +1,85 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const baselineOutput = `goos: linux
+goarch: amd64
+pkg: go-finance-advisor/benchmarks
+BenchmarkAPIListTransactions-8   	     400	   2000000 ns/op	   380.0 req/s	  460000 B/op	    4500 allocs/op
+BenchmarkAPIListTransactions-8   	     400	   3000000 ns/op	   333.3 req/s	  460000 B/op	    4500 allocs/op
+BenchmarkAPIListTransactions-8   	     400	   2500000 ns/op	   400.0 req/s	  460000 B/op	    4500 allocs/op
+BenchmarkAPIAdvice-8             	     300	   3500000 ns/op	   285.7 req/s	  810000 B/op	   13000 allocs/op
+PASS
+ok  	go-finance-advisor/benchmarks	12.345s
+`
+
+func TestParse(t *testing.T) {
+	results, err := parse(strings.NewReader(baselineOutput))
+	require.NoError(t, err)
+
+	require.Len(t, results, 2)
+	assert.Equal(t, 2500000.0, results["BenchmarkAPIListTransactions"]["ns/op"], "runs are compared by their median")
+	assert.Equal(t, 4500.0, results["BenchmarkAPIListTransactions"]["allocs/op"])
+	assert.Equal(t, 285.7, results["BenchmarkAPIAdvice"]["req/s"])
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+	baseline := write("baseline.txt", baselineOutput)
+
+	t.Run("passes within the threshold", func(t *testing.T) {
+		current := write("within.txt", `
+BenchmarkAPIListTransactions-4   	     400	   2900000 ns/op	  460000 B/op	    4500 allocs/op
+BenchmarkAPIAdvice-4             	     300	   3000000 ns/op	  810000 B/op	   13000 allocs/op
+BenchmarkAPINew-4                	     300	   1000000 ns/op	  810000 B/op	   13000 allocs/op
+`)
+		var out strings.Builder
+		require.NoError(t, run(baseline, current, 25, &out))
+		assert.Contains(t, out.String(), "BenchmarkAPINew")
+		assert.Contains(t, out.String(), "not in the baseline yet")
+		assert.NotContains(t, out.String(), "REGRESSED")
+	})
+
+	t.Run("fails on a slowdown", func(t *testing.T) {
+		current := write("slower.txt", `
+BenchmarkAPIListTransactions-4   	     200	   5000000 ns/op	  460000 B/op	    4500 allocs/op
+BenchmarkAPIAdvice-4             	     300	   3500000 ns/op	  810000 B/op	   13000 allocs/op
+`)
+		var out strings.Builder
+		err := run(baseline, current, 25, &out)
+		assert.ErrorContains(t, err, "1 benchmark(s) regressed")
+		assert.Contains(t, out.String(), "+100.0%")
+	})
+
+	t.Run("fails on more allocations", func(t *testing.T) {
+		current := write("allocs.txt", `
+BenchmarkAPIListTransactions-4   	     400	   2500000 ns/op	  460000 B/op	    4500 allocs/op
+BenchmarkAPIAdvice-4             	     300	   3500000 ns/op	  910000 B/op	   20000 allocs/op
+`)
+		var out strings.Builder
+		assert.Error(t, run(baseline, current, 25, &out))
+		assert.Contains(t, out.String(), "REGRESSED")
+	})
+
+	t.Run("reports benchmarks missing from the run", func(t *testing.T) {
+		current := write("missing.txt", `
+BenchmarkAPIAdvice-4             	     300	   3500000 ns/op	  810000 B/op	   13000 allocs/op
+`)
+		var out strings.Builder
+		require.NoError(t, run(baseline, current, 25, &out))
+		assert.Contains(t, out.String(), "missing from the current run")
+	})
+}
@@ -0,0 +1,107 @@
+// k6 load test of the API against a database filled by `finance-advisor seed`.
+// Each scenario hits one endpoint at a constant rate; the thresholds are the
+// latency budgets a run has to stay within.
+//
+//   make seed SEED_ARGS="-users 20 -transactions 2000 -months 24"
+//   make run &
+//   make load-k6
+import http from 'k6/http';
+import { check, fail } from 'k6';
+
+const BASE_URL = `${__ENV.BASE_URL || 'http://localhost:8080'}/api/v1`;
+const SEED = __ENV.SEED || '1';
+const USERS = parseInt(__ENV.USERS || '10', 10);
+const PASSWORD = __ENV.PASSWORD || 'password123';
+const DURATION = __ENV.DURATION || '1m';
+
+function scenario(exec, rate) {
+  return {
+    executor: 'constant-arrival-rate',
+    exec,
+    rate,
+    timeUnit: '1s',
+    duration: DURATION,
+    preAllocatedVUs: rate,
+    maxVUs: rate * 4,
+  };
+}
+
+export const options = {
+  scenarios: {
+    create_transaction: scenario('createTransaction', 20),
+    list_transactions: scenario('listTransactions', 50),
+    dashboard: scenario('dashboard', 20),
+    advice: scenario('advice', 5),
+  },
+  thresholds: {
+    http_req_failed: ['rate<0.01'],
+    'http_req_duration{scenario:create_transaction}': ['p(95)<50'],
+    'http_req_duration{scenario:list_transactions}': ['p(95)<100'],
+    'http_req_duration{scenario:dashboard}': ['p(95)<150'],
+    'http_req_duration{scenario:advice}': ['p(95)<250'],
+  },
+};
+
+function seedEmail(i) {
+  return `seed${SEED}-user${String(i).padStart(4, '0')}@example.com`;
+}
+
+// setup logs in the seeded users once; the scenarios spread over them
+export function setup() {
+  const sessions = [];
+  for (let i = 1; i <= USERS; i++) {
+    const res = http.post(`${BASE_URL}/auth/login`, JSON.stringify({ email: seedEmail(i), password: PASSWORD }), {
+      headers: { 'Content-Type': 'application/json' },
+    });
+    if (res.status !== 200) {
+      fail(`logging in ${seedEmail(i)} failed with ${res.status}; seed the database first`);
+    }
+    const body = res.json();
+    sessions.push({ userID: body.user.id, token: body.token });
+  }
+
+  const categories = http.get(`${BASE_URL}/categories`, { headers: auth(sessions[0]) }).json();
+  const expense = categories.find((category) => category.type === 'expense');
+  if (!expense) {
+    fail('no expense category to create transactions in');
+  }
+  return { sessions, categoryID: expense.id };
+}
+
+function auth(session) {
+  return { Authorization: `Bearer ${session.token}`, 'Content-Type': 'application/json' };
+}
+
+function session(data) {
+  return data.sessions[Math.floor(Math.random() * data.sessions.length)];
+}
+
+export function createTransaction(data) {
+  const s = session(data);
+  const body = JSON.stringify({
+    amount: (Math.random() * 100 + 1).toFixed(2),
+    type: 'expense',
+    description: 'Load test',
+    category_id: data.categoryID,
+  });
+  const res = http.post(`${BASE_URL}/users/${s.userID}/transactions`, body, { headers: auth(s) });
+  check(res, { 'created': (r) => r.status === 201 });
+}
+
+export function listTransactions(data) {
+  const s = session(data);
+  const res = http.get(`${BASE_URL}/users/${s.userID}/transactions?limit=50`, { headers: auth(s) });
+  check(res, { 'listed': (r) => r.status === 200 });
+}
+
+export function dashboard(data) {
+  const s = session(data);
+  const res = http.get(`${BASE_URL}/users/${s.userID}/analytics/dashboard`, { headers: auth(s) });
+  check(res, { 'dashboard': (r) => r.status === 200 });
+}
+
+export function advice(data) {
+  const s = session(data);
+  const res = http.get(`${BASE_URL}/users/${s.userID}/advice`, { headers: auth(s) });
+  check(res, { 'advised': (r) => r.status === 200 });
+}
@@ -0,0 +1,41 @@
+#!/usr/bin/env bash
+# Attacks the API with vegeta at a constant rate, spread over transaction
+# creation, listing, the analytics dashboard and advice, as one seeded user.
+# Needs curl, jq and vegeta, and a server whose database was seeded with
+# `finance-advisor seed`. The results are kept in $OUT for `vegeta plot`.
+#
+#   RATE=100 DURATION=1m benchmarks/load/vegeta.sh
+set -euo pipefail
+
+BASE_URL="${BASE_URL:-http://localhost:8080}/api/v1"
+SEED="${SEED:-1}"
+PASSWORD="${PASSWORD:-password123}"
+RATE="${RATE:-50}"
+DURATION="${DURATION:-30s}"
+OUT="${OUT:-vegeta-results.bin}"
+
+email=$(printf 'seed%s-user%04d@example.com' "$SEED" 1)
+login=$(curl -fsS -H 'Content-Type: application/json' \
+	-d "{\"email\":\"$email\",\"password\":\"$PASSWORD\"}" "$BASE_URL/auth/login") || {
+	echo "logging in $email failed; seed the database first" >&2
+	exit 1
+}
+token=$(jq -r .token <<<"$login")
+user=$(jq -r .user.id <<<"$login")
+category=$(curl -fsS -H "Authorization: Bearer $token" "$BASE_URL/categories" |
+	jq '[.[] | select(.type == "expense")][0].id')
+
+work=$(mktemp -d)
+trap 'rm -rf "$work"' EXIT
+printf '{"amount":"12.50","type":"expense","description":"Load test","category_id":%s}' "$category" >"$work/transaction.json"
+
+# Reads outnumber writes as they do in use
+{
+	printf 'POST %s/users/%s/transactions\nAuthorization: Bearer %s\nContent-Type: application/json\n@%s\n\n' \
+		"$BASE_URL" "$user" "$token" "$work/transaction.json"
+	for path in transactions?limit=50 transactions?limit=50 analytics/dashboard advice; do
+		printf 'GET %s/users/%s/%s\nAuthorization: Bearer %s\n\n' "$BASE_URL" "$user" "$path" "$token"
+	done
+} >"$work/targets.txt"
+
+vegeta attack -targets="$work/targets.txt" -rate="$RATE" -duration="$DURATION" | tee "$OUT" | vegeta report
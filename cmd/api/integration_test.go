@@ -0,0 +1,291 @@
+//go:build integration
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/config"
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/persistence/migrations"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm/logger"
+)
+
+// integrationAPI is the whole API, wired the way main wires it, on a real
+// HTTP server in front of a migrated SQLite file
+type integrationAPI struct {
+	url   string
+	token string
+}
+
+func newIntegrationAPI(t *testing.T) *integrationAPI {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	gin.DefaultWriter = io.Discard
+
+	dir := t.TempDir()
+	cfg := config.Default()
+	cfg.Database.DSN = filepath.Join(dir, "finance.db")
+	cfg.Storage.LocalDir = filepath.Join(dir, "uploads")
+	cfg.Auth.JWTSecret = "integration-test-secret"
+	cfg.Server.GRPCPort = 0
+
+	db, replicas, err := openDatabase(cfg.Database)
+	require.NoError(t, err)
+	db.Logger = logger.Default.LogMode(logger.Silent)
+	_, err = migrations.New(db).Up(context.Background())
+	require.NoError(t, err)
+
+	srv, err := newServer(cfg, db, replicas, nil)
+	require.NoError(t, err)
+	httpServer := httptest.NewServer(srv.router)
+	t.Cleanup(httpServer.Close)
+	return &integrationAPI{url: httpServer.URL + "/api/v1"}
+}
+
+// do sends a request with the logged in user's token, checks its status
+// and returns the body
+func (a *integrationAPI) do(t *testing.T, method, path string, body any, want int) []byte {
+	t.Helper()
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, a.url+path, reader)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if a.token != "" {
+		req.Header.Set("Authorization", "Bearer "+a.token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, want, resp.StatusCode, "%s %s: %s", method, path, data)
+	return data
+}
+
+// doJSON sends a request like do, checks the response against the named
+// schema of testdata/schemas and decodes it into out
+func (a *integrationAPI) doJSON(t *testing.T, method, path string, body any, want int, schema string, out any) {
+	t.Helper()
+	data := a.do(t, method, path, body, want)
+	assertSchema(t, schema, data)
+	if out != nil {
+		require.NoError(t, json.Unmarshal(data, out))
+	}
+}
+
+func TestIntegration_UserJourney(t *testing.T) {
+	a := newIntegrationAPI(t)
+	credentials := map[string]string{"email": "jane@example.com", "password": "correct-horse-battery"}
+
+	var session struct {
+		Token string `json:"token"`
+		User  struct {
+			ID uint `json:"id"`
+		} `json:"user"`
+	}
+	a.doJSON(t, http.MethodPost, "/auth/register",
+		map[string]string{"email": credentials["email"], "password": credentials["password"], "first_name": "Jane", "last_name": "Doe"},
+		http.StatusCreated, "session", &session)
+	a.do(t, http.MethodPost, "/auth/login", map[string]string{"email": credentials["email"], "password": "wrong-password"},
+		http.StatusUnauthorized)
+	a.doJSON(t, http.MethodPost, "/auth/login", credentials, http.StatusOK, "session", &session)
+	a.token = session.Token
+	users := fmt.Sprintf("/users/%d", session.User.ID)
+
+	var food, salary struct {
+		ID uint `json:"id"`
+	}
+	a.doJSON(t, http.MethodPost, "/categories", map[string]string{"name": "Food", "type": "expense"},
+		http.StatusCreated, "category", &food)
+	a.doJSON(t, http.MethodPost, "/categories", map[string]string{"name": "Salary", "type": "income"},
+		http.StatusCreated, "category", &salary)
+	a.doJSON(t, http.MethodGet, "/categories", nil, http.StatusOK, "categories", nil)
+
+	now := time.Now()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	day := monthStart.Format("2006-01-02")
+
+	t.Run("budget", func(t *testing.T) {
+		a.doJSON(t, http.MethodPost, users+"/budgets", map[string]any{
+			"category_id": food.ID, "amount": 100, "period": "monthly", "start_date": day,
+		}, http.StatusCreated, "budget", nil)
+	})
+
+	t.Run("transactions", func(t *testing.T) {
+		for _, transaction := range []map[string]any{
+			{"amount": 3000, "type": "income", "description": "Salary", "category_id": salary.ID, "date": day},
+			{"amount": 60, "type": "expense", "description": "Groceries", "category_id": food.ID, "date": day},
+			{"amount": 55.5, "type": "expense", "description": "Restaurant", "category_id": food.ID, "date": day},
+		} {
+			a.doJSON(t, http.MethodPost, users+"/transactions", transaction, http.StatusCreated, "transaction", nil)
+		}
+
+		var list struct {
+			Transactions []struct {
+				Description string `json:"description"`
+			} `json:"transactions"`
+		}
+		a.doJSON(t, http.MethodGet, users+"/transactions", nil, http.StatusOK, "transaction_list", &list)
+		assert.Len(t, list.Transactions, 3)
+	})
+
+	t.Run("budget alerts", func(t *testing.T) {
+		var budgets []struct {
+			Spent     domain.Money `json:"spent"`
+			Remaining domain.Money `json:"remaining"`
+		}
+		a.doJSON(t, http.MethodGet, users+"/budgets", nil, http.StatusOK, "budget_list", &budgets)
+		require.Len(t, budgets, 1)
+		assert.Equal(t, domain.NewMoney(115.5), budgets[0].Spent)
+		assert.Equal(t, domain.NewMoney(-15.5), budgets[0].Remaining)
+
+		var notifications struct {
+			Notifications []struct {
+				Type  string `json:"type"`
+				Title string `json:"title"`
+			} `json:"notifications"`
+		}
+		a.doJSON(t, http.MethodGet, users+"/notifications", nil, http.StatusOK, "notification_list", &notifications)
+		require.Len(t, notifications.Notifications, 1, "going over the budget notifies once")
+		assert.Equal(t, "Budget exceeded", notifications.Notifications[0].Title)
+	})
+
+	t.Run("report", func(t *testing.T) {
+		var report struct {
+			TotalIncome      float64 `json:"total_income"`
+			TotalExpenses    float64 `json:"total_expenses"`
+			TransactionCount int     `json:"transaction_count"`
+		}
+		a.doJSON(t, http.MethodGet, fmt.Sprintf("%s/reports/monthly/%d/%d", users, now.Year(), now.Month()), nil,
+			http.StatusOK, "report", &report)
+		assert.Equal(t, 3000.0, report.TotalIncome)
+		assert.Equal(t, 115.5, report.TotalExpenses)
+		assert.Equal(t, 3, report.TransactionCount)
+	})
+
+	t.Run("export", func(t *testing.T) {
+		data := a.do(t, http.MethodGet, "/export/transactions?format=csv", nil, http.StatusOK)
+		rows, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, rows, 4, "a header and the three transactions")
+		assert.Contains(t, strings.Join(rows[0], ","), "Description")
+	})
+
+	t.Run("other users are kept out", func(t *testing.T) {
+		a.do(t, http.MethodGet, "/users/999999/notifications", nil, http.StatusForbidden)
+		a.do(t, http.MethodGet, "/users/999999/transactions", nil, http.StatusForbidden)
+		a.do(t, http.MethodDelete, "/users/999999/transactions/trash", nil, http.StatusForbidden)
+		a.token = ""
+		a.do(t, http.MethodGet, users+"/transactions", nil, http.StatusUnauthorized)
+	})
+}
+
+// assertSchema checks a JSON document against testdata/schemas/<name>.json.
+// The schemas use the part of JSON Schema the API's responses need: type,
+// required, properties, items and minItems.
+func assertSchema(t *testing.T, name string, data []byte) {
+	t.Helper()
+	raw, err := os.ReadFile(filepath.Join("testdata", "schemas", name+".json"))
+	require.NoError(t, err)
+	var schema map[string]any
+	require.NoError(t, json.Unmarshal(raw, &schema))
+	var document any
+	require.NoError(t, json.Unmarshal(data, &document), "response is not JSON: %s", data)
+
+	for _, problem := range validateSchema(schema, document, "$") {
+		t.Errorf("schema %s: %s", name, problem)
+	}
+}
+
+func validateSchema(schema map[string]any, value any, path string) []string {
+	if want, ok := schema["type"]; ok && !schemaTypeMatches(want, value) {
+		return []string{fmt.Sprintf("%s: want %v, got %T", path, want, value)}
+	}
+
+	var problems []string
+	switch v := value.(type) {
+	case map[string]any:
+		if required, ok := schema["required"].([]any); ok {
+			for _, field := range required {
+				if _, present := v[field.(string)]; !present {
+					problems = append(problems, fmt.Sprintf("%s: missing %s", path, field))
+				}
+			}
+		}
+		properties, _ := schema["properties"].(map[string]any)
+		for field, property := range properties {
+			if fieldValue, present := v[field]; present {
+				problems = append(problems, validateSchema(property.(map[string]any), fieldValue, path+"."+field)...)
+			}
+		}
+	case []any:
+		if minItems, ok := schema["minItems"].(float64); ok && len(v) < int(minItems) {
+			problems = append(problems, fmt.Sprintf("%s: want at least %.0f items, got %d", path, minItems, len(v)))
+		}
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range v {
+				problems = append(problems, validateSchema(items, item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	}
+	return problems
+}
+
+// schemaTypeMatches reports whether value has the schema type, or one of
+// the list of types
+func schemaTypeMatches(want, value any) bool {
+	if types, ok := want.([]any); ok {
+		for _, t := range types {
+			if schemaTypeMatches(t, value) {
+				return true
+			}
+		}
+		return false
+	}
+	switch want {
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == float64(int64(n))
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	}
+	return false
+}
@@ -1,19 +1,19 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
+	"net"
 	"os"
 
-	"go-finance-advisor/internal/application"
+	"go-finance-advisor/internal/config"
 	"go-finance-advisor/internal/domain"
-	"go-finance-advisor/internal/infrastructure/api"
-	"go-finance-advisor/internal/infrastructure/middleware"
+	"go-finance-advisor/internal/infrastructure/persistence"
+	"go-finance-advisor/internal/infrastructure/persistence/migrations"
 	"go-finance-advisor/internal/pkg"
 
-	"github.com/gin-gonic/gin"
-	"github.com/glebarez/sqlite"
 	"gorm.io/gorm"
 )
 
@@ -28,6 +28,7 @@ func main() {
 	// Parse command line flags
 	versionFlag := flag.Bool("version", false, "Show version information")
 	healthFlag := flag.Bool("health", false, "Perform health check")
+	configFlag := flag.String("config", "", "Path to a YAML or TOML config file (defaults to $CONFIG_FILE)")
 	flag.Parse()
 
 	// Handle version flag
@@ -45,176 +46,135 @@ func main() {
 		os.Exit(0)
 	}
 
-	// Database setup
-	db, err := gorm.Open(sqlite.Open("finance.db"), &gorm.Config{})
+	cfg, err := config.Load(*configFlag)
 	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
-	}
-
-	// Auto migrate
-	db.AutoMigrate(&domain.User{}, &domain.Transaction{}, &domain.Category{}, &domain.Budget{}, &domain.Recommendation{})
-
-	userSvc := &application.UserService{DB: db}
-	txSvc := &application.TransactionService{DB: db}
-	advisorSvc := &application.AdvisorService{DB: db}
-	analyticsSvc := &application.AnalyticsService{DB: db}
-	budgetSvc := &application.BudgetService{DB: db}
-	categorySvc := &application.CategoryService{DB: db}
-	reportsSvc := application.NewReportsService(db)
-	exportSvc := application.NewExportService(db)
-	marketSvc := &pkg.RealTimeMarketService{}
-
-	userHandler := &api.UserHandler{Service: userSvc}
-	txHandler := &api.TransactionHandler{Service: txSvc}
-	advisorHandler := api.NewAdvisorHandler(advisorSvc, userSvc, marketSvc)
-	analyticsHandler := &api.AnalyticsHandler{Service: analyticsSvc}
-	budgetHandler := &api.BudgetHandler{Service: budgetSvc}
-	categoryHandler := &api.CategoryHandler{Service: categorySvc}
-	reportsHandler := &api.ReportsHandler{Service: reportsSvc}
-	exportHandler := api.NewExportHandler(exportSvc)
-
-	r := gin.Default()
-
-	// CORS middleware
-	r.Use(func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization")
-
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
+		log.Fatal("Failed to load configuration:", err)
+	}
+
+	db, replicas, err := openDatabase(cfg.Database)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Handle the migrate subcommand, which needs the database but no auth settings
+	migrator := migrations.New(db)
+	if flag.Arg(0) == "migrate" {
+		if err := runMigrate(context.Background(), migrator, flag.Args()[1:], os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if err := ensureSchema(context.Background(), cfg.Database, migrator); err != nil {
+		log.Fatal("Database schema check failed: ", err)
+	}
+	if flag.Arg(0) == "seed" {
+		if err := runSeed(context.Background(), db, cfg.Server.IsProduction(), flag.Args()[1:], os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	cipher, err := newCipher(cfg.Encryption)
+	if err != nil {
+		log.Fatal("Invalid encryption configuration: ", err)
+	}
+	if flag.Arg(0) == "rotate-keys" {
+		if err := runRotateKeys(context.Background(), db, cipher, os.Stdout); err != nil {
+			log.Fatal(err)
 		}
+		return
+	}
+	if cipher == nil {
+		log.Println("Warning: no encryption keys configured, account numbers, attachment paths and bank access tokens are stored in plaintext")
+	}
+
+	srv, err := newServer(cfg, db, replicas, cipher)
+	if err != nil {
+		log.Fatal(err)
+	}
+	srv.start(context.Background())
 
-		c.Next()
-	})
-
-	// Static files
-	r.Static("/web", "./web")
-	r.GET("/", func(c *gin.Context) {
-		c.Redirect(302, "/web/")
-	})
-
-	// Health check routes (public)
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"status":    "healthy",
-			"service":   "go-finance-advisor",
-			"version":   "1.0.0",
-			"timestamp": gin.H{"unix": gin.H{"seconds": 1735000000}},
-		})
-	})
-
-	r.GET("/metrics", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"uptime":          "24h",
-			"requests_total":  1000,
-			"active_users":    50,
-			"database_status": "connected",
-			"memory_usage":    "256MB",
-			"cpu_usage":       "15%",
-		})
-	})
-
-	// Routes
-	v1 := r.Group("/api/v1")
-	{
-		// Health check routes
-		v1.GET("/health", func(c *gin.Context) {
-			c.JSON(200, gin.H{
-				"status":    "healthy",
-				"service":   "go-finance-advisor",
-				"version":   "1.0.0",
-				"timestamp": gin.H{"unix": gin.H{"seconds": 1735000000}},
-			})
-		})
-
-		v1.GET("/metrics", func(c *gin.Context) {
-			c.JSON(200, gin.H{
-				"uptime":          "24h",
-				"requests_total":  1000,
-				"active_users":    50,
-				"database_status": "connected",
-				"memory_usage":    "256MB",
-				"cpu_usage":       "15%",
-			})
-		})
-
-		// Public routes
-		v1.POST("/users", userHandler.Create)
-		v1.POST("/auth/register", userHandler.Register)
-		v1.POST("/auth/login", userHandler.Login)
-
-		// Category routes (public for now)
-		v1.POST("/categories/initialize", categoryHandler.InitializeDefaultCategories)
-		v1.GET("/categories", categoryHandler.GetCategories)
-		v1.GET("/categories/:categoryId", categoryHandler.GetCategory)
-		v1.POST("/categories", categoryHandler.CreateCategory)
-		v1.PUT("/categories/:categoryId", categoryHandler.UpdateCategory)
-		v1.DELETE("/categories/:categoryId", categoryHandler.DeleteCategory)
-		v1.GET("/categories/usage", categoryHandler.GetCategoryUsage)
-		v1.GET("/categories/income", categoryHandler.GetIncomeCategories)
-		v1.GET("/categories/expense", categoryHandler.GetExpenseCategories)
-
-		// Protected routes
-		protected := v1.Group("/")
-		protected.Use(middleware.AuthMiddleware())
-		{
-			// User routes
-			protected.GET("/users/:userId", userHandler.Get)
-			protected.PUT("/users/:userId/risk", userHandler.UpdateRisk)
-
-			// Transaction routes
-			protected.POST("/users/:userId/transactions", txHandler.Create)
-			protected.GET("/users/:userId/transactions", txHandler.List)
-			protected.GET("/users/:userId/transactions/export/csv", txHandler.ExportCSV)
-			protected.GET("/users/:userId/transactions/export/pdf", txHandler.ExportPDF)
-
-			// Analytics routes
-			protected.GET("/users/:userId/analytics/metrics", analyticsHandler.GetFinancialMetrics)
-			protected.GET("/users/:userId/analytics/income-expense", analyticsHandler.GetIncomeExpenseAnalysis)
-			protected.GET("/users/:userId/analytics/categories/:categoryId", analyticsHandler.GetCategoryAnalysis)
-			protected.GET("/users/:userId/analytics/dashboard", analyticsHandler.GetDashboardSummary)
-
-			// Budget routes
-			protected.POST("/users/:userId/budgets", budgetHandler.CreateBudget)
-			protected.GET("/users/:userId/budgets", budgetHandler.GetBudgets)
-			protected.GET("/users/:userId/budgets/:budgetId", budgetHandler.GetBudget)
-			protected.PUT("/users/:userId/budgets/:budgetId", budgetHandler.UpdateBudget)
-			protected.DELETE("/users/:userId/budgets/:budgetId", budgetHandler.DeleteBudget)
-			protected.GET("/users/:userId/budgets/summary", budgetHandler.GetBudgetSummary)
-
-			// Reports routes
-			protected.GET("/users/:userId/reports/monthly/:year/:month", reportsHandler.GenerateMonthlyReport)
-			protected.GET("/users/:userId/reports/quarterly/:year/:quarter", reportsHandler.GenerateQuarterlyReport)
-			protected.GET("/users/:userId/reports/yearly/:year", reportsHandler.GenerateYearlyReport)
-			protected.GET("/users/:userId/reports", reportsHandler.GetReportsList)
-
-			// Export routes
-			protected.GET("/export/transactions", exportHandler.ExportTransactions)
-			protected.GET("/export/budgets", exportHandler.ExportBudgets)
-			protected.GET("/export/reports", exportHandler.ExportFinancialReport)
-			protected.GET("/export/all", exportHandler.ExportAllData)
-			protected.GET("/export/formats", exportHandler.GetExportFormats)
-
-			// Investment advice
-			protected.GET("/users/:userId/advice", advisorHandler.GetAdvice)
-			protected.GET("/users/:userId/advice/realtime", advisorHandler.GetRealTimeAdvice)
-			protected.GET("/market/data", advisorHandler.GetMarketData)
-			protected.GET("/market/crypto", advisorHandler.GetCryptoPrices)
-			protected.GET("/market/stocks", advisorHandler.GetStockPrices)
-			protected.GET("/market/summary", advisorHandler.GetMarketSummary)
-			protected.GET("/users/:userId/portfolio/recommendations", advisorHandler.GetPortfolioRecommendations)
-
-			// AI-powered endpoints
-			protected.GET("/users/:userId/ai/risk-assessment", advisorHandler.GetAIRiskAssessment)
-			protected.GET("/ai/market/prediction", advisorHandler.GetAIMarketPrediction)
-			protected.GET("/users/:userId/ai/portfolio/optimization", advisorHandler.GetAIPortfolioOptimization)
+	if cfg.Server.GRPCPort != 0 {
+		listener, err := net.Listen("tcp", cfg.Server.GRPCAddress())
+		if err != nil {
+			log.Fatal("Failed to listen for gRPC: ", err)
 		}
+		log.Printf("gRPC server listening on %s", cfg.Server.GRPCAddress())
+		go func() {
+			if err := srv.grpc.Serve(listener); err != nil {
+				log.Fatal(err)
+			}
+		}()
 	}
 
-	log.Println("Server listening on :8080")
-	if err := r.Run(":8080"); err != nil {
+	log.Printf("Server listening on %s", cfg.Server.Address())
+	if err := srv.router.Run(cfg.Server.Address()); err != nil {
 		log.Fatal(err)
 	}
 }
+
+// openDatabase connects to the primary database and the read replicas, with
+// the plugins every statement goes through
+func openDatabase(cfg config.DatabaseConfig) (*gorm.DB, *persistence.ReadReplicas, error) {
+	db, err := persistence.OpenSQLite(cfg.DSN, cfg.BusyTimeout.Std())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	if err := db.Use(persistence.NewQueryTimeout(cfg.QueryTimeout.Std())); err != nil {
+		return nil, nil, fmt.Errorf("failed to configure query timeout: %w", err)
+	}
+	// Requests only ever reach their own user's rows of user-owned tables
+	if err := db.Use(persistence.NewUserScope(persistence.UserOwnedTables...)); err != nil {
+		return nil, nil, fmt.Errorf("failed to configure user scoping: %w", err)
+	}
+	// Read-heavy routes may read from replicas that keep up with the primary
+	replicaDBs := make([]*gorm.DB, 0, len(cfg.Replicas))
+	for _, dsn := range cfg.Replicas {
+		replicaDB, err := persistence.OpenSQLite(dsn, cfg.BusyTimeout.Std())
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to connect to read replica: %w", err)
+		}
+		replicaDBs = append(replicaDBs, replicaDB)
+	}
+	replicas := persistence.NewReadReplicas(cfg.ReplicaMaxLag.Std(), replicaDBs...)
+	if err := db.Use(replicas); err != nil {
+		return nil, nil, fmt.Errorf("failed to configure read replicas: %w", err)
+	}
+	return db, replicas, nil
+}
+
+// blobStorage creates the storage the configured driver selects, and returns
+// it a second time when it is the local one, whose links the API serves
+func blobStorage(cfg *config.Config) (pkg.BlobStorage, *pkg.LocalStorage) {
+	if cfg.Storage.Driver == config.StorageDriverS3 {
+		return pkg.NewS3Storage(cfg.Storage.S3Endpoint, cfg.Storage.S3Region, cfg.Storage.S3Bucket,
+			cfg.Storage.S3AccessKeyID, cfg.Storage.S3SecretAccessKey, cfg.Storage.S3PathStyle), nil
+	}
+
+	baseURL := cfg.Storage.BaseURL
+	if baseURL == "" {
+		baseURL = "/api/v1/files"
+	}
+	signingKey := cfg.Storage.SigningKey
+	if signingKey == "" {
+		signingKey = cfg.Auth.JWTSecret
+	}
+	local := pkg.NewLocalStorage(cfg.Storage.LocalDir, baseURL, []byte(signingKey))
+	return local, local
+}
+
+// planLimits converts a plan's configured limits. Without billing nobody
+// could buy the features a plan leaves out, so every plan gets all of them.
+func planLimits(plan config.PlanConfig, billing bool) domain.PlanLimits {
+	features := plan.Features
+	if !billing {
+		features = domain.Features
+	}
+	return domain.PlanLimits{
+		AIRequestsPerDay:    plan.AIRequestsPerDay,
+		BankRefreshesPerDay: plan.BankRefreshesPerDay,
+		ExportMaxRows:       plan.ExportMaxRows,
+		BankSyncInterval:    plan.BankSyncInterval.Std(),
+		Features:            features,
+	}
+}
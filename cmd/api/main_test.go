@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"go-finance-advisor/internal/application"
+	"go-finance-advisor/internal/config"
 	"go-finance-advisor/internal/domain"
 	"go-finance-advisor/internal/infrastructure/api"
 	"go-finance-advisor/internal/infrastructure/middleware"
@@ -54,6 +55,13 @@ func setupTestApp(t *testing.T) (*gin.Engine, *gorm.DB) {
 
 	// Set Gin to test mode
 	gin.SetMode(gin.TestMode)
+
+	// Tokens need a signing key, which production only gets from configuration
+	authConfig := config.Default().Auth
+	authConfig.JWTSecret = "test-secret"
+	if err := middleware.ConfigureJWT(authConfig); err != nil {
+		panic(err)
+	}
 	r := gin.New()
 
 	// CORS middleware
@@ -164,6 +172,9 @@ func setupTestApp(t *testing.T) (*gin.Engine, *gorm.DB) {
 			protected.GET("/users/:userId/reports/quarterly/:year/:quarter", reportsHandler.GenerateQuarterlyReport)
 			protected.GET("/users/:userId/reports/yearly/:year", reportsHandler.GenerateYearlyReport)
 			protected.GET("/users/:userId/reports", reportsHandler.GetReportsList)
+			protected.GET("/users/:userId/reports/compare", reportsHandler.CompareReports)
+			protected.GET("/users/:userId/reports/:reportId", reportsHandler.GetReport)
+			protected.DELETE("/users/:userId/reports/:reportId", reportsHandler.DeleteReport)
 
 			// Export routes
 			protected.GET("/export/transactions", exportHandler.ExportTransactions)
@@ -195,6 +206,13 @@ func TestMain(m *testing.M) {
 	// Set Gin to test mode
 	gin.SetMode(gin.TestMode)
 
+	// Tokens need a signing key, which production only gets from configuration
+	authConfig := config.Default().Auth
+	authConfig.JWTSecret = "test-secret"
+	if err := middleware.ConfigureJWT(authConfig); err != nil {
+		panic(err)
+	}
+
 	// Run tests
 	code := m.Run()
 
@@ -314,21 +332,15 @@ func TestPublicRoutes(t *testing.T) {
 		expectedStatus int
 	}{
 		{
-			name:           "GET categories",
-			method:         "GET",
-			path:           "/api/v1/categories",
-			expectedStatus: http.StatusOK,
-		},
-		{
-			name:           "GET income categories",
+			name:           "GET health",
 			method:         "GET",
-			path:           "/api/v1/categories/income",
+			path:           "/api/v1/health",
 			expectedStatus: http.StatusOK,
 		},
 		{
-			name:           "GET expense categories",
+			name:           "GET metrics",
 			method:         "GET",
-			path:           "/api/v1/categories/expense",
+			path:           "/api/v1/metrics",
 			expectedStatus: http.StatusOK,
 		},
 	}
@@ -363,6 +375,14 @@ func TestProtectedRoutesWithoutAuth(t *testing.T) {
 			name: "GET budgets without auth",
 			path: "/api/v1/users/1/budgets",
 		},
+		{
+			name: "GET categories without auth",
+			path: "/api/v1/categories",
+		},
+		{
+			name: "GET expense categories without auth",
+			path: "/api/v1/categories/expense",
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"go-finance-advisor/internal/config"
+	"go-finance-advisor/internal/infrastructure/persistence/migrations"
+)
+
+const migrateUsage = "usage: finance-advisor migrate up | down [steps] | status | version"
+
+// runMigrate implements the migrate subcommand
+func runMigrate(ctx context.Context, m *migrations.Migrator, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := m.Up(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Applied %d migration(s)\n", applied)
+		return printVersion(ctx, m, out)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+			steps = n
+		}
+		rolledBack, err := m.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Rolled back %d migration(s)\n", rolledBack)
+		return printVersion(ctx, m, out)
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.Applied {
+				state = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(out, "%04d  %-30s %s\n", status.Version, status.Name, state)
+		}
+		return nil
+	case "version":
+		return printVersion(ctx, m, out)
+	default:
+		return fmt.Errorf("unknown migrate command %q; %s", args[0], migrateUsage)
+	}
+}
+
+func printVersion(ctx context.Context, m *migrations.Migrator, out io.Writer) error {
+	version, err := m.Version(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Schema version: %d (binary expects %d)\n", version, m.Latest())
+	return nil
+}
+
+// ensureSchema makes sure the database matches the binary before the server
+// starts, applying pending migrations first when configured to
+func ensureSchema(ctx context.Context, cfg config.DatabaseConfig, m *migrations.Migrator) error {
+	if cfg.MigrateOnStart {
+		if _, err := m.Up(ctx); err != nil {
+			return err
+		}
+	}
+
+	err := m.CheckVersion(ctx)
+	if errors.Is(err, migrations.ErrSchemaBehind) {
+		return fmt.Errorf("%w; run `finance-advisor migrate up` or set DB_MIGRATE_ON_START=true", err)
+	}
+	return err
+}
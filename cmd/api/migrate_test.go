@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"go-finance-advisor/internal/config"
+	"go-finance-advisor/internal/infrastructure/persistence/migrations"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupMigrateTest(t *testing.T) *migrations.Migrator {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "migrate.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	return migrations.New(db)
+}
+
+func TestRunMigrate(t *testing.T) {
+	m := setupMigrateTest(t)
+	ctx := context.Background()
+	var out bytes.Buffer
+
+	require.NoError(t, runMigrate(ctx, m, []string{"status"}, &out))
+	assert.Contains(t, out.String(), "0001  initial_schema")
+	assert.Contains(t, out.String(), "pending")
+
+	out.Reset()
+	require.NoError(t, runMigrate(ctx, m, []string{"up"}, &out))
+	assert.Contains(t, out.String(), fmt.Sprintf("Applied %d migration(s)", m.Latest()))
+
+	out.Reset()
+	require.NoError(t, runMigrate(ctx, m, []string{"version"}, &out))
+	assert.Contains(t, out.String(), fmt.Sprintf("Schema version: %d (binary expects %d)", m.Latest(), m.Latest()))
+
+	out.Reset()
+	require.NoError(t, runMigrate(ctx, m, []string{"down", "1"}, &out))
+	assert.Contains(t, out.String(), "Rolled back 1 migration(s)")
+
+	assert.Error(t, runMigrate(ctx, m, nil, &out))
+	assert.Error(t, runMigrate(ctx, m, []string{"sideways"}, &out))
+	assert.Error(t, runMigrate(ctx, m, []string{"down", "zero"}, &out))
+}
+
+func TestEnsureSchema(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("refuses an unmigrated database", func(t *testing.T) {
+		err := ensureSchema(ctx, config.DatabaseConfig{}, setupMigrateTest(t))
+		assert.ErrorIs(t, err, migrations.ErrSchemaBehind)
+		assert.ErrorContains(t, err, "migrate up")
+	})
+
+	t.Run("migrates on start when enabled", func(t *testing.T) {
+		m := setupMigrateTest(t)
+		require.NoError(t, ensureSchema(ctx, config.DatabaseConfig{MigrateOnStart: true}, m))
+
+		version, err := m.Version(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, m.Latest(), version)
+	})
+}
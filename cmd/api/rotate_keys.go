@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"go-finance-advisor/internal/config"
+	"go-finance-advisor/internal/infrastructure/encryption"
+	"go-finance-advisor/internal/infrastructure/persistence"
+
+	"gorm.io/gorm"
+)
+
+// newCipher builds the cipher for encrypted columns, or nil when no keys are
+// configured, which stores them in plaintext
+func newCipher(cfg config.EncryptionConfig) (*encryption.Cipher, error) {
+	if !cfg.Enabled() {
+		return nil, nil
+	}
+	keys, err := encryption.NewStaticKeyProvider(cfg.KeyID, cfg.Keys)
+	if err != nil {
+		return nil, err
+	}
+	return encryption.NewCipher(keys), nil
+}
+
+// runRotateKeys implements the rotate-keys subcommand: every encrypted column
+// is sealed again with the current key, and plaintext written before
+// encryption was enabled gets encrypted. Afterwards retired keys can be
+// removed from the configuration.
+func runRotateKeys(ctx context.Context, db *gorm.DB, cipher *encryption.Cipher, out io.Writer) error {
+	if cipher == nil {
+		return errors.New("no encryption keys configured; set ENCRYPTION_KEY_ID and ENCRYPTION_KEYS")
+	}
+	columns := []struct {
+		name string
+		repo interface {
+			Reencrypt(ctx context.Context) (int64, error)
+		}
+	}{
+		{"account numbers", persistence.NewAccountRepository(db, cipher)},
+		{"attachment paths", persistence.NewAttachmentRepository(db, cipher)},
+		{"bank access tokens", persistence.NewBankLinkRepository(db, cipher)},
+	}
+	for _, column := range columns {
+		rewritten, err := column.repo.Reencrypt(ctx)
+		if err != nil {
+			return fmt.Errorf("re-encrypting %s: %w", column.name, err)
+		}
+		fmt.Fprintf(out, "Re-encrypted %d %s\n", rewritten, column.name)
+	}
+	return nil
+}
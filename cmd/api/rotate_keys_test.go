@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-finance-advisor/internal/config"
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/persistence"
+	"go-finance-advisor/internal/infrastructure/persistence/migrations"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestRunRotateKeys(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "rotate.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+	_, err = migrations.New(db).Up(ctx)
+	require.NoError(t, err)
+
+	oldKey := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("o", 32)))
+	newKey := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("n", 32)))
+	oldCipher, err := newCipher(config.EncryptionConfig{KeyID: "old", Keys: map[string]string{"old": oldKey}})
+	require.NoError(t, err)
+	account := &domain.Account{UserID: 1, Name: "Main", Type: domain.AccountTypeChecking, AccountNumber: "12345678", Currency: "USD"}
+	require.NoError(t, persistence.NewAccountRepository(db, oldCipher).Create(ctx, account))
+
+	var out bytes.Buffer
+	assert.Error(t, runRotateKeys(ctx, db, nil, &out), "rotation needs keys")
+
+	rotated, err := newCipher(config.EncryptionConfig{KeyID: "new", Keys: map[string]string{"old": oldKey, "new": newKey}})
+	require.NoError(t, err)
+	require.NoError(t, runRotateKeys(ctx, db, rotated, &out))
+	assert.Contains(t, out.String(), "Re-encrypted 1 account numbers")
+	assert.Contains(t, out.String(), "Re-encrypted 0 attachment paths")
+
+	retired, err := newCipher(config.EncryptionConfig{KeyID: "new", Keys: map[string]string{"new": newKey}})
+	require.NoError(t, err)
+	found, err := persistence.NewAccountRepository(db, retired).GetByID(ctx, account.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "12345678", found.AccountNumber)
+}
+
+func TestNewCipher(t *testing.T) {
+	cipher, err := newCipher(config.EncryptionConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, cipher, "no keys leaves columns in plaintext")
+
+	_, err = newCipher(config.EncryptionConfig{KeyID: "k", Keys: map[string]string{"k": "short"}})
+	assert.Error(t, err)
+}
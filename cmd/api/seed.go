@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"go-finance-advisor/internal/application"
+
+	"gorm.io/gorm"
+)
+
+// runSeed implements the seed subcommand, which fills the database with fake
+// users and transactions. The same flags always generate the same data.
+func runSeed(ctx context.Context, db *gorm.DB, production bool, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	flags.SetOutput(out)
+	users := flags.Int("users", 10, "number of users to generate")
+	transactions := flags.Int("transactions", 200, "transactions per user")
+	months := flags.Int("months", 12, "how many months back transactions go")
+	seed := flags.Uint64("seed", 1, "random seed; the same seed gives the same data")
+	until := flags.String("until", time.Now().Format("2006-01-02"), "date of the latest transactions (YYYY-MM-DD)")
+	password := flags.String("password", "password123", "password of every generated user")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if production {
+		return errors.New("refusing to seed fake data in production")
+	}
+	untilDate, err := time.Parse("2006-01-02", *until)
+	if err != nil {
+		return fmt.Errorf("invalid -until %q (use YYYY-MM-DD)", *until)
+	}
+
+	started := time.Now()
+	result, err := application.NewSeedService(db).Seed(ctx, application.SeedOptions{
+		Users: *users, TransactionsPerUser: *transactions, Months: *months,
+		Until: untilDate, Seed: *seed, Password: *password,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Seeded %d user(s) with %d transaction(s) in %s (seed %d)\n",
+		result.Users, result.Transactions, time.Since(started).Round(time.Millisecond), *seed)
+	fmt.Fprintf(out, "Log in as %s ... %s with password %q\n",
+		application.SeedEmail(*seed, 1), application.SeedEmail(*seed, result.Users), *password)
+	return nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/persistence/migrations"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestRunSeed(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "seed.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+	_, err = migrations.New(db).Up(ctx)
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	args := []string{"-users", "2", "-transactions", "25", "-seed", "7", "-until", "2025-06-30"}
+	require.NoError(t, runSeed(ctx, db, false, args, &out))
+	assert.Contains(t, out.String(), "Seeded 2 user(s) with 50 transaction(s)")
+	assert.Contains(t, out.String(), "seed7-user0001@example.com")
+
+	var transactions int64
+	require.NoError(t, db.Model(&domain.Transaction{}).Count(&transactions).Error)
+	assert.Equal(t, int64(50), transactions)
+
+	assert.ErrorContains(t, runSeed(ctx, db, true, nil, &out), "production")
+	assert.ErrorContains(t, runSeed(ctx, db, false, []string{"-until", "June"}, &out), "invalid -until")
+	assert.Error(t, runSeed(ctx, db, false, []string{"-users", "0"}, &out))
+	assert.Error(t, runSeed(ctx, db, false, []string{"-bogus"}, &out))
+}
@@ -0,0 +1,728 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"go-finance-advisor/internal/application"
+	"go-finance-advisor/internal/config"
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/api"
+	"go-finance-advisor/internal/infrastructure/encryption"
+	"go-finance-advisor/internal/infrastructure/middleware"
+	"go-finance-advisor/internal/infrastructure/persistence"
+	"go-finance-advisor/internal/infrastructure/rpc"
+	"go-finance-advisor/internal/pkg"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"gorm.io/gorm"
+)
+
+// server is the API: the HTTP router, the gRPC server and the background
+// work that keeps the data they serve up to date
+type server struct {
+	router *gin.Engine
+	grpc   *grpc.Server
+	// start runs the background work until ctx is done
+	start func(ctx context.Context)
+}
+
+// newServer wires the services, handlers and routes of the API to the
+// database. Nothing runs in the background until start is called.
+func newServer(cfg *config.Config, db *gorm.DB, replicas *persistence.ReadReplicas, cipher *encryption.Cipher) (*server, error) {
+	if err := middleware.ConfigureJWT(cfg.Auth); err != nil {
+		return nil, fmt.Errorf("invalid JWT configuration: %w", err)
+	}
+
+	auditSvc := application.NewAuditService(db)
+	merchantSvc := application.NewMerchantService(db)
+	if err := merchantSvc.InitializeDefaultMerchants(context.Background()); err != nil {
+		log.Printf("Could not initialize default merchants: %v", err)
+	}
+	userSvc := &application.UserService{DB: db, Audit: auditSvc}
+	sessionSvc := &application.SessionService{DB: db, RefreshExpiry: cfg.Auth.RefreshTokenExpiry.Std()}
+	middleware.ConfigureSessions(sessionSvc)
+	analyticsCache := application.NewMemoryCache()
+	events := application.NewEventBus()
+	budgetSvc := &application.BudgetService{DB: db, Audit: auditSvc, Cache: analyticsCache, Events: events}
+	categoryCapSvc := &application.CategoryCapService{DB: db, Cache: analyticsCache}
+	preferencesSvc := &application.PreferencesService{DB: db, Cache: analyticsCache}
+	quotaSvc := application.NewQuotaService(db, map[string]domain.PlanLimits{
+		domain.PlanFree:    planLimits(cfg.Plans.Free, cfg.Billing.Enabled()),
+		domain.PlanPremium: planLimits(cfg.Plans.Premium, cfg.Billing.Enabled()),
+	})
+	billingSvc := &application.BillingService{
+		DB:              db,
+		Provider:        pkg.NewBillingProvider(cfg.Billing.StripeBaseURL, cfg.Billing.StripeSecretKey, cfg.Billing.StripeWebhookSecret),
+		PriceID:         cfg.Billing.PremiumPriceID,
+		SuccessURL:      cfg.Billing.SuccessURL,
+		CancelURL:       cfg.Billing.CancelURL,
+		PortalReturnURL: cfg.Billing.PortalReturnURL,
+	}
+	fxSvc := application.NewFXService(db, pkg.NewFrankfurterProvider(cfg.FX.ProviderURL), cfg.FX.FallbackWindow.Std())
+	txSvc := &application.TransactionService{
+		DB: db, Audit: auditSvc, Merchants: merchantSvc, Caps: categoryCapSvc, FX: fxSvc, Events: events,
+	}
+	marketSvc := pkg.NewRealTimeMarketServiceWithConfig(cfg.Market)
+	marketSvc.AI = cfg.AI
+	if cfg.Market.AlphaVantageAPIKey == config.AlphaVantageDemoKey {
+		log.Println("Warning: using the Alpha Vantage demo key, stock quotes are limited to a few sample symbols")
+	}
+	backtestSvc := application.NewBacktestService(db, marketSvc)
+	watchlistSvc := application.NewWatchlistService(db, marketSvc)
+	portfolioSvc := application.NewPortfolioService(db, backtestSvc)
+	notificationSvc := application.NewNotificationService(db)
+	priceAlertSvc := application.NewPriceAlertService(db, marketSvc, notificationSvc)
+	adviceHistorySvc := &application.AdviceHistoryService{DB: db, Backtest: backtestSvc}
+	advisorSvc := &application.AdvisorService{DB: db, History: adviceHistorySvc, Allocations: cfg.AI.AdviceAllocations}
+	retirementSvc := application.NewRetirementService(db, advisorSvc, cfg.Advisor.MaxSimulationIterations)
+	retirementSvc.Allocations = cfg.AI.RiskAllocations
+	analyticsSvc := &application.AnalyticsService{DB: db, Cache: analyticsCache, CacheTTL: cfg.Cache.AnalyticsTTL.Std()}
+	healthHistorySvc := &application.HealthHistoryService{DB: db, Analytics: analyticsSvc}
+	statsSvc := application.NewStatsService(db)
+	archiveSvc := application.NewArchiveService(db, cfg.Retention.ArchiveAfterYears, cfg.Retention.TrashPeriod.Std())
+	categorySvc := &application.CategoryService{DB: db, Audit: auditSvc, Budgets: budgetSvc, Cache: analyticsCache}
+	householdSvc := &application.HouseholdService{DB: db, Transactions: txSvc, Budgets: budgetSvc}
+	dataQualitySvc := &application.DataQualityService{DB: db, Transactions: txSvc}
+	importSvc := &application.ImportService{DB: db, Transactions: txSvc}
+	jobSvc := &application.JobService{DB: db, MaxAttempts: cfg.Jobs.MaxAttempts, RetryBackoff: cfg.Jobs.RetryBackoff.Std()}
+	bankSyncSvc := &application.BankSyncService{
+		DB: db, Links: persistence.NewBankLinkRepository(db, cipher), Transactions: txSvc, Notifications: notificationSvc,
+		Jobs: jobSvc, Quotas: quotaSvc,
+		Providers: pkg.NewBankProviders(
+			cfg.BankSync.PlaidBaseURL, cfg.BankSync.PlaidClientID, cfg.BankSync.PlaidSecret,
+			cfg.BankSync.GoCardlessBaseURL, cfg.BankSync.GoCardlessSecretID, cfg.BankSync.GoCardlessSecretKey,
+		),
+	}
+	jobSvc.Register(application.JobTypeBankSync, bankSyncSvc.RunSyncJob)
+	pushSenders, err := pkg.NewPushSenders(
+		cfg.Push.FCMBaseURL, cfg.Push.FCMCredentialsFile,
+		cfg.Push.APNsBaseURL, cfg.Push.APNsKeyFile, cfg.Push.APNsKeyID, cfg.Push.APNsTeamID, cfg.Push.APNsTopic,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid push notification configuration: %w", err)
+	}
+	if len(pushSenders) > 0 {
+		notificationSvc.Jobs, notificationSvc.Push = jobSvc, pushSenders
+		jobSvc.Register(application.JobTypePushDelivery, notificationSvc.RunPushJob)
+	}
+	walletSvc := application.NewWalletService(db, pkg.NewWalletProviders(cfg.Wallets.BTCBaseURL, cfg.Wallets.ETHBaseURL), marketSvc)
+	walletSvc.Jobs = jobSvc
+	jobSvc.Register(application.JobTypeWalletRefresh, walletSvc.RunRefreshJob)
+	goalSvc := &application.GoalService{DB: db, Events: events}
+	savingsRuleSvc := &application.SavingsRuleService{DB: db, Goals: goalSvc}
+	billSvc := &application.BillService{DB: db, Events: events}
+	loanSvc := application.NewLoanService(db)
+	incomeSourceSvc := application.NewIncomeSourceService(db)
+	scheduledTransferSvc := application.NewScheduledTransferService(db, txSvc)
+	webhookSvc := &application.WebhookService{DB: db, Jobs: jobSvc}
+	jobSvc.Register(application.JobTypeWebhookDelivery, webhookSvc.RunDeliveryJob)
+	pluginSvc := application.NewPluginService(db, txSvc)
+	pluginSvc.Jobs = jobSvc
+	jobSvc.Register(application.JobTypePluginRun, pluginSvc.RunPluginJob)
+	syncSvc := application.NewSyncService(db, txSvc)
+	var categorizerSvc *application.CategorizerService
+	if cfg.Categorizer.Enabled {
+		categorizerSvc = application.NewCategorizerService(db, cfg.Categorizer.MinConfidence, cfg.Categorizer.MinSamples)
+		categorizerSvc.Jobs = jobSvc
+		jobSvc.Register(application.JobTypeCategoryModelTraining, categorizerSvc.RunTrainingJob)
+		txSvc.Categorizer = categorizerSvc
+		importSvc.Categorizer = categorizerSvc
+	}
+	transactionTextSvc := application.NewTransactionTextService(db)
+	transactionTextSvc.Categorizer = categorizerSvc
+	var assistantSvc *application.AssistantService
+	if llm := pkg.NewLLMProvider(cfg.LLM.BaseURL, cfg.LLM.APIKey, cfg.LLM.Model, cfg.LLM.Timeout.Std()); llm != nil {
+		assistantSvc = application.NewAssistantService(db, llm)
+		assistantSvc.Analytics = analyticsSvc
+		transactionTextSvc.LLM = llm
+	}
+
+	// Cross-cutting reactions to changes, in the order they run
+	auditSvc.Subscribe(events)
+	budgetSvc.Subscribe(events)
+	savingsRuleSvc.Subscribe(events)
+	application.SubscribeCacheInvalidation(events, analyticsCache)
+	notificationSvc.Subscribe(events)
+	webhookSvc.Subscribe(events)
+	pluginSvc.Subscribe(events)
+	syncSvc.Subscribe(events)
+
+	// Demo mode seeds the sample user before any demo session can be opened
+	var demoUser *domain.User
+	demoSvc := &application.DemoService{DB: db, Budgets: budgetSvc}
+	if cfg.Demo.Enabled {
+		if demoUser, err = demoSvc.Seed(context.Background(), cfg.Demo.Email); err != nil {
+			return nil, fmt.Errorf("failed to seed the demo: %w", err)
+		}
+		log.Printf("Demo mode enabled for %s", demoUser.Email)
+	}
+
+	reportsSvc := application.NewReportsService(db)
+	exportSvc := application.NewExportService(db)
+	exportSvc.Quotas = quotaSvc
+	storage, localStorage := blobStorage(cfg)
+	exportSvc.Storage = storage
+	insightsSvc := application.NewInsightsService(db)
+	insightsSvc.CacheTTL = cfg.Cache.InsightsTTL.Std()
+	digestSvc := application.NewDigestService(db, reportsSvc, insightsSvc, budgetSvc, billSvc)
+	digestSvc.Jobs = jobSvc
+	digestSvc.UnsubscribeURL = cfg.Email.UnsubscribeURL
+	if sender := pkg.NewEmailSender(cfg.Email.Host, cfg.Email.Port, cfg.Email.Username, cfg.Email.Password, cfg.Email.From); sender != nil {
+		digestSvc.Sender = sender
+		jobSvc.Register(application.JobTypeDigest, digestSvc.RunDigestJob)
+	}
+	calendarFeedSvc := application.NewCalendarFeedService(db, billSvc, budgetSvc, []byte(cfg.Auth.JWTSecret))
+	calendarFeedSvc.BaseURL = strings.TrimRight(cfg.Server.PublicURL, "/") + "/api/v1"
+	advisorClientSvc := application.NewAdvisorClientService(db, analyticsSvc)
+	advisorClientSvc.Insights = insightsSvc
+	commentSvc := application.NewCommentService(db, notificationSvc)
+	riskAssessmentSvc := &application.RiskAssessmentService{DB: db, Audit: auditSvc}
+	if path := cfg.Advisor.RiskQuestionnaireFile; path != "" {
+		if riskAssessmentSvc.Questionnaire, err = application.LoadRiskQuestionnaire(path); err != nil {
+			return nil, fmt.Errorf("invalid risk questionnaire: %w", err)
+		}
+	}
+	receiptSvc := application.NewReceiptService(
+		db, pkg.NewOCRProvider(cfg.OCR.APIURL, cfg.OCR.APIKey), storage,
+	)
+	receiptSvc.Attachments = persistence.NewAttachmentRepository(db, cipher)
+	accountSvc := &application.AccountService{DB: db, Repo: persistence.NewAccountRepository(db, cipher), Audit: auditSvc}
+
+	userHandler := &api.UserHandler{Service: userSvc, Sessions: sessionSvc}
+	sessionHandler := api.NewSessionHandler(sessionSvc)
+	preferencesHandler := api.NewPreferencesHandler(preferencesSvc)
+	digestHandler := api.NewDigestHandler(digestSvc)
+	calendarFeedHandler := api.NewCalendarFeedHandler(calendarFeedSvc)
+	accountHandler := api.NewAccountHandler(accountSvc)
+	txHandler := &api.TransactionHandler{Service: txSvc}
+	dataQualityHandler := api.NewDataQualityHandler(dataQualitySvc)
+	importHandler := api.NewImportHandler(importSvc)
+	bankSyncHandler := api.NewBankSyncHandler(bankSyncSvc)
+	walletHandler := api.NewWalletHandler(walletSvc)
+	fxHandler := api.NewFXHandler(fxSvc)
+	advisorHandler := api.NewAdvisorHandler(advisorSvc, userSvc, marketSvc)
+	publicMarketHandler := api.NewPublicMarketHandler(marketSvc, cfg.Public.CacheTTL.Std())
+	advisorHandler.History = adviceHistorySvc
+	advisorHandler.Watchlist = watchlistSvc
+	watchlistHandler := api.NewWatchlistHandler(watchlistSvc)
+	portfolioHandler := api.NewPortfolioHandler(portfolioSvc)
+	retirementHandler := api.NewRetirementHandler(retirementSvc)
+	calculatorHandler := api.NewCalculatorHandler()
+	riskAssessmentHandler := api.NewRiskAssessmentHandler(riskAssessmentSvc)
+	priceAlertHandler := api.NewPriceAlertHandler(priceAlertSvc)
+	notificationHandler := api.NewNotificationHandler(notificationSvc)
+	adviceHistoryHandler := api.NewAdviceHistoryHandler(adviceHistorySvc)
+	advicePerformanceHandler := api.NewAdvicePerformanceHandler(backtestSvc)
+	analyticsHandler := &api.AnalyticsHandler{Service: analyticsSvc, ClientMaxAge: cfg.Cache.ClientMaxAge.Std()}
+	healthHistoryHandler := api.NewHealthHistoryHandler(healthHistorySvc)
+	statsHandler := api.NewStatsHandler(statsSvc)
+	archiveHandler := api.NewArchiveHandler(archiveSvc)
+	budgetHandler := &api.BudgetHandler{Service: budgetSvc}
+	categoryHandler := &api.CategoryHandler{Service: categorySvc}
+	categoryCapHandler := api.NewCategoryCapHandler(categoryCapSvc)
+	reportsHandler := &api.ReportsHandler{Service: reportsSvc}
+	exportHandler := api.NewExportHandler(exportSvc)
+	receiptHandler := api.NewReceiptHandler(receiptSvc)
+	txTextHandler := api.NewTransactionTextHandler(transactionTextSvc)
+	insightsHandler := api.NewInsightsHandler(insightsSvc)
+	auditHandler := api.NewAuditHandler(auditSvc)
+	merchantHandler := api.NewMerchantHandler(merchantSvc)
+	householdHandler := api.NewHouseholdHandler(householdSvc)
+	advisorClientHandler := api.NewAdvisorClientHandler(advisorClientSvc)
+	commentHandler := api.NewCommentHandler(commentSvc)
+	jobHandler := api.NewJobHandler(jobSvc)
+	aiConfigHandler := api.NewAIConfigHandler(cfg)
+	goalHandler := api.NewGoalHandler(goalSvc)
+	savingsRuleHandler := api.NewSavingsRuleHandler(savingsRuleSvc)
+	webhookHandler := api.NewWebhookHandler(webhookSvc)
+	pluginHandler := api.NewPluginHandler(pluginSvc)
+	syncHandler := api.NewSyncHandler(syncSvc)
+	billHandler := api.NewBillHandler(billSvc)
+	loanHandler := api.NewLoanHandler(loanSvc)
+	incomeSourceHandler := api.NewIncomeSourceHandler(incomeSourceSvc)
+	scheduledTransferHandler := api.NewScheduledTransferHandler(scheduledTransferSvc)
+	usageHandler := api.NewUsageHandler(quotaSvc)
+	billingHandler := api.NewBillingHandler(billingSvc)
+
+	start := func(ctx context.Context) {
+		go jobSvc.Start(ctx, cfg.Jobs.Workers, cfg.Jobs.PollInterval.Std())
+		// Keep monthly insights warm so the insights endpoint is served from cache
+		go insightsSvc.StartPrecompute(ctx, "month", cfg.Cache.InsightsTTL.Std())
+		// Permanently remove transactions that have outlived the trash retention period
+		go txSvc.StartTrashPurge(ctx, cfg.Retention.TrashPeriod.Std(), time.Hour)
+		go replicas.StartChecks(ctx, db, cfg.Database.ReplicaCheckInterval.Std())
+		// Move transactions past the archive age into the compressed archives once a day
+		go archiveSvc.StartArchiving(ctx, 24*time.Hour)
+		go budgetSvc.StartSpendingReconcile(ctx, time.Hour)
+		// Refresh today's health snapshots a few times a day; each day keeps its last one
+		go healthHistorySvc.StartSnapshots(ctx, 6*time.Hour)
+		go billSvc.StartReminders(ctx, time.Hour)
+		go scheduledTransferSvc.StartScheduling(ctx, time.Hour)
+		if digestSvc.Sender != nil {
+			go digestSvc.StartScheduling(ctx, cfg.Email.DigestInterval.Std())
+		}
+		// Write the counted requests and today's usage stats every few minutes
+		go statsSvc.StartRefreshing(ctx, 5*time.Minute)
+		go priceAlertSvc.StartPolling(ctx, cfg.Market.PriceAlertInterval.Std())
+		if demoUser != nil {
+			go demoSvc.StartReseed(ctx, cfg.Demo.Email, 24*time.Hour)
+		}
+		if len(bankSyncSvc.Providers) > 0 {
+			go bankSyncSvc.StartScheduledSync(ctx, cfg.BankSync.Interval.Std())
+		}
+		go walletSvc.StartRefreshing(ctx, cfg.Wallets.RefreshInterval.Std())
+		if categorizerSvc != nil {
+			go categorizerSvc.StartRetraining(ctx, cfg.Categorizer.RetrainInterval.Std())
+		}
+	}
+
+	r := gin.Default()
+	if err := r.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("trusted proxies: %w", err)
+	}
+	r.Use(middleware.ActivityMiddleware(statsSvc, "/api/v1"))
+	r.Use(middleware.CORSMiddleware(cfg.Server.CORSOrigins))
+	r.Use(middleware.ErrorHandler(cfg.Server.IsProduction()))
+	r.Use(middleware.BodyLimitMiddleware(cfg.Server.MaxBodyBytes))
+	r.NoRoute(func(c *gin.Context) {
+		middleware.RespondError(c, middleware.NewError(middleware.CodeNotFound, "Route not found"))
+	})
+
+	// Static files
+	r.Static("/web", "./web")
+	r.GET("/", func(c *gin.Context) {
+		c.Redirect(302, "/web/")
+	})
+
+	// Health check routes (public)
+	r.GET("/health", func(c *gin.Context) {
+		c.JSON(200, gin.H{
+			"status":    "healthy",
+			"service":   "go-finance-advisor",
+			"version":   "1.0.0",
+			"timestamp": gin.H{"unix": gin.H{"seconds": 1735000000}},
+		})
+	})
+
+	r.GET("/metrics", func(c *gin.Context) {
+		c.JSON(200, gin.H{
+			"uptime":          "24h",
+			"requests_total":  1000,
+			"active_users":    50,
+			"database_status": "connected",
+			"memory_usage":    "256MB",
+			"cpu_usage":       "15%",
+		})
+	})
+
+	// Stripe signs the exact body of its webhooks, so they skip the
+	// sanitizing of the API routes
+	r.POST("/api/v1/billing/webhook", billingHandler.Webhook)
+
+	// Routes
+	v1 := r.Group("/api/v1")
+	v1.Use(middleware.SanitizeMiddleware(middleware.DefaultFieldLimits))
+	{
+		// Health check routes
+		v1.GET("/health", func(c *gin.Context) {
+			c.JSON(200, gin.H{
+				"status":    "healthy",
+				"service":   "go-finance-advisor",
+				"version":   "1.0.0",
+				"timestamp": gin.H{"unix": gin.H{"seconds": 1735000000}},
+			})
+		})
+
+		v1.GET("/metrics", func(c *gin.Context) {
+			c.JSON(200, gin.H{
+				"uptime":          "24h",
+				"requests_total":  1000,
+				"active_users":    50,
+				"database_status": "connected",
+				"memory_usage":    "256MB",
+				"cpu_usage":       "15%",
+			})
+		})
+
+		// Public routes
+		v1.POST("/users", userHandler.Create)
+		v1.POST("/auth/register", middleware.StrictJSON(api.RegisterRequest{}), userHandler.Register)
+		v1.POST("/auth/login", middleware.StrictJSON(api.LoginRequest{}), userHandler.Login)
+		v1.POST("/auth/refresh", sessionHandler.Refresh)
+
+		// The unsubscribe links of digest emails work without logging in;
+		// mail clients post to them for one-click unsubscribes
+		v1.GET("/digest/unsubscribe", digestHandler.UnsubscribeByToken)
+		v1.POST("/digest/unsubscribe", digestHandler.UnsubscribeByToken)
+
+		// Calendar apps cannot log in; feed links carry a signed token instead
+		v1.GET("/users/:userId/calendar.ics", calendarFeedHandler.Feed)
+
+		// Download links of files on the local disk are signed, not authenticated
+		if localStorage != nil {
+			v1.GET("/files/*key", api.NewFileHandler(localStorage).Download)
+		}
+
+		// Live market data for the landing page, without login
+		public := v1.Group("/public")
+		public.Use(middleware.RateLimitMiddleware(cfg.Public.RateLimit, cfg.Public.RateWindow.Std()))
+		public.GET("/market/summary", publicMarketHandler.GetMarketSummary)
+		public.GET("/market/crypto", publicMarketHandler.GetCryptoPrices)
+
+		// Protected routes
+		protected := v1.Group("/")
+		protected.Use(middleware.AuthMiddleware())
+		// Routes of one user are that user's alone, whatever their handlers check
+		protected.Use(middleware.OwnUserMiddleware("/api/v1/users/:userId"))
+		if demoUser != nil {
+			demoHandler := api.NewDemoHandler(userSvc, sessionSvc, demoUser.ID)
+			v1.POST("/auth/demo", demoHandler.Start)
+			// The demo user can look at everything but only run simulations
+			protected.Use(middleware.DemoMiddleware(demoUser.ID,
+				"/api/v1/users/:userId/budgets/simulate", "/api/v1/users/:userId/retirement/simulate",
+				"/api/v1/calculators/rent-vs-buy"))
+		}
+		// Features and daily quotas of the users' plans
+		aiAdvisor := middleware.FeatureMiddleware(quotaSvc, domain.FeatureAIAdvisor)
+		bankSync := middleware.FeatureMiddleware(quotaSvc, domain.FeatureBankSync)
+		aiQuota := middleware.QuotaMiddleware(quotaSvc, domain.QuotaAIRequests)
+		bankRefreshQuota := middleware.QuotaMiddleware(quotaSvc, domain.QuotaBankRefreshes)
+		// Read-heavy routes may read from a replica until they write
+		replicaReads := middleware.ReplicaReadsMiddleware()
+		{
+			// User routes
+			protected.GET("/users/:userId", userHandler.Get)
+			protected.PUT("/users/:userId/risk", userHandler.UpdateRisk)
+			protected.PUT("/users/:userId/profile", userHandler.UpdateProfile)
+			protected.PUT("/users/:userId/locale", userHandler.UpdateLocale)
+			protected.GET("/users/:userId/preferences", preferencesHandler.Get)
+			protected.PUT("/users/:userId/preferences", preferencesHandler.Update)
+			protected.GET("/users/:userId/digest", digestHandler.Get)
+			protected.PUT("/users/:userId/digest", digestHandler.Subscribe)
+			protected.DELETE("/users/:userId/digest", digestHandler.Unsubscribe)
+			protected.PUT("/users/:userId/account-type", userHandler.UpdateAccountType)
+			protected.GET("/users/:userId/usage", usageHandler.Get)
+			protected.POST("/users/:userId/billing/checkout", billingHandler.Checkout)
+			protected.POST("/users/:userId/billing/portal", billingHandler.Portal)
+			protected.GET("/users/:userId/billing/invoices", billingHandler.Invoices)
+			protected.GET("/users/:userId/sessions", sessionHandler.List)
+			protected.DELETE("/users/:userId/sessions/:sessionId", sessionHandler.Revoke)
+			protected.DELETE("/users/:userId/sessions", sessionHandler.RevokeAll)
+
+			// Account routes; account numbers are stored encrypted
+			protected.GET("/users/:userId/accounts", accountHandler.List)
+			protected.POST("/users/:userId/accounts", accountHandler.Create)
+			protected.GET("/users/:userId/accounts/:accountId", accountHandler.Get)
+			protected.PUT("/users/:userId/accounts/:accountId", accountHandler.Update)
+			protected.DELETE("/users/:userId/accounts/:accountId", accountHandler.Delete)
+			protected.GET("/users/:userId/accounts/:accountId/statement", accountHandler.Statement)
+
+			// Scheduled transfers between the user's accounts
+			protected.GET("/users/:userId/scheduled-transfers", scheduledTransferHandler.List)
+			protected.POST("/users/:userId/scheduled-transfers", scheduledTransferHandler.Create)
+			protected.GET("/users/:userId/scheduled-transfers/:transferId", scheduledTransferHandler.Get)
+			protected.PUT("/users/:userId/scheduled-transfers/:transferId", scheduledTransferHandler.Update)
+			protected.DELETE("/users/:userId/scheduled-transfers/:transferId", scheduledTransferHandler.Delete)
+
+			// Category routes, scoped to the authenticated user
+			protected.GET("/categories", categoryHandler.GetCategories)
+			protected.GET("/categories/tree", categoryHandler.GetCategoryTree)
+			protected.GET("/categories/:categoryId", categoryHandler.GetCategory)
+			protected.POST("/categories", categoryHandler.CreateCategory)
+			protected.PUT("/categories/:categoryId", categoryHandler.UpdateCategory)
+			protected.DELETE("/categories/:categoryId", categoryHandler.DeleteCategory)
+			protected.GET("/categories/usage", categoryHandler.GetCategoryUsage)
+			protected.GET("/categories/income", categoryHandler.GetIncomeCategories)
+			protected.GET("/categories/expense", categoryHandler.GetExpenseCategories)
+
+			// Category spending caps
+			protected.GET("/users/:userId/category-caps", categoryCapHandler.List)
+			protected.PUT("/users/:userId/category-caps/:categoryId", categoryCapHandler.Set)
+			protected.DELETE("/users/:userId/category-caps/:categoryId", categoryCapHandler.Delete)
+
+			// Category suggestions from the user's category model
+			if categorizerSvc != nil {
+				categorizerHandler := api.NewCategorizerHandler(categorizerSvc)
+				protected.GET("/users/:userId/category-suggestions", categorizerHandler.Suggest)
+				protected.GET("/users/:userId/category-model", categorizerHandler.GetModel)
+				protected.POST("/users/:userId/category-model/train", categorizerHandler.Train)
+				protected.GET("/users/:userId/category-model/evaluation", categorizerHandler.GetEvaluation)
+			}
+
+			// Transaction routes
+			protected.POST("/users/:userId/transactions", middleware.StrictJSON(api.CreateTransactionRequest{}), txHandler.Create)
+			protected.GET("/users/:userId/transactions", txHandler.List)
+			protected.GET("/users/:userId/transactions/export/csv", replicaReads, txHandler.ExportCSV)
+			protected.GET("/users/:userId/transactions/export/pdf", replicaReads, txHandler.ExportPDF)
+			protected.POST("/users/:userId/transactions/receipt", receiptHandler.ScanReceipt)
+			protected.GET("/users/:userId/attachments/:attachmentId/link", receiptHandler.AttachmentLink)
+			protected.POST("/users/:userId/transactions/parse", middleware.StrictJSON(api.ParseTransactionRequest{}), txTextHandler.Parse)
+			protected.GET("/users/:userId/transactions/:id", txHandler.GetByID)
+			protected.PUT("/users/:userId/transactions/:id", middleware.StrictJSON(api.CreateTransactionRequest{}), txHandler.Update)
+			protected.DELETE("/users/:userId/transactions/:id", txHandler.Delete)
+			protected.POST("/users/:userId/imports/:source", importHandler.Import)
+			protected.GET("/users/:userId/import-mappings", importHandler.ListMappings)
+			protected.PUT("/users/:userId/import-mappings", importHandler.SetMapping)
+			protected.DELETE("/users/:userId/import-mappings/:mappingId", importHandler.DeleteMapping)
+			// Bank linking; users who lose the feature can still see and unlink their banks
+			protected.POST("/users/:userId/bank-links/sessions", bankSync, bankSyncHandler.StartLink)
+			protected.POST("/users/:userId/bank-links", bankSync, bankSyncHandler.Link)
+			protected.GET("/users/:userId/bank-links", bankSyncHandler.List)
+			protected.GET("/users/:userId/bank-links/:linkId", bankSyncHandler.Get)
+			protected.POST("/users/:userId/bank-links/:linkId/refresh", bankSync, bankRefreshQuota, bankSyncHandler.Refresh)
+			protected.DELETE("/users/:userId/bank-links/:linkId", bankSyncHandler.Unlink)
+
+			// Crypto wallet routes
+			protected.POST("/users/:userId/wallets", walletHandler.Add)
+			protected.GET("/users/:userId/wallets", walletHandler.List)
+			protected.GET("/users/:userId/wallets/:walletId", walletHandler.Get)
+			protected.GET("/users/:userId/wallets/:walletId/transactions", walletHandler.Transactions)
+			protected.POST("/users/:userId/wallets/:walletId/refresh", walletHandler.Refresh)
+			protected.DELETE("/users/:userId/wallets/:walletId", walletHandler.Remove)
+			protected.GET("/users/:userId/transactions/trash", txHandler.ListTrash)
+			protected.POST("/users/:userId/transactions/trash/:id/restore", txHandler.Restore)
+			protected.DELETE("/users/:userId/transactions/trash/:id", txHandler.Purge)
+			protected.DELETE("/users/:userId/transactions/trash", txHandler.EmptyTrash)
+			protected.GET("/users/:userId/transactions/archive", archiveHandler.ListArchived)
+			protected.GET("/users/:userId/transactions/duplicates", dataQualityHandler.Duplicates)
+			protected.POST("/users/:userId/transactions/duplicates/merge", dataQualityHandler.Merge)
+			protected.POST("/users/:userId/transactions/duplicates/dismiss", dataQualityHandler.Dismiss)
+			protected.GET("/users/:userId/transactions/data-quality", dataQualityHandler.Report)
+
+			// Analytics routes
+			protected.GET("/users/:userId/analytics/metrics", replicaReads, analyticsHandler.GetFinancialMetrics)
+			protected.GET("/users/:userId/analytics/income-expense", replicaReads, analyticsHandler.GetIncomeExpenseAnalysis)
+			protected.GET("/users/:userId/analytics/categories/:categoryId", replicaReads, analyticsHandler.GetCategoryAnalysis)
+			protected.GET("/users/:userId/analytics/categories/:categoryId/trend", replicaReads, analyticsHandler.GetCategoryTrend)
+			protected.GET("/users/:userId/analytics/dashboard", replicaReads, analyticsHandler.GetDashboardSummary)
+			protected.GET("/users/:userId/analytics/merchants", replicaReads, analyticsHandler.GetMerchantAnalysis)
+			protected.GET("/users/:userId/analytics/places", replicaReads, analyticsHandler.GetPlaceAnalysis)
+			protected.GET("/users/:userId/analytics/items", replicaReads, analyticsHandler.GetItemAnalysis)
+			protected.GET("/users/:userId/analytics/income-stability", replicaReads, analyticsHandler.GetIncomeStability)
+			protected.GET("/users/:userId/analytics/income-sources", replicaReads, analyticsHandler.GetIncomeBreakdown)
+			protected.POST("/users/:userId/analytics/batch", replicaReads, analyticsHandler.Batch)
+			protected.GET("/users/:userId/analytics/health/history", replicaReads, healthHistoryHandler.GetHistory)
+			protected.GET("/merchants", merchantHandler.List)
+
+			// Insights routes
+			protected.GET("/users/:userId/insights", insightsHandler.GetInsights)
+
+			// Questions about the user's finances, answered from their analytics
+			if assistantSvc != nil {
+				assistantHandler := api.NewAssistantHandler(assistantSvc)
+				protected.POST("/users/:userId/assistant", middleware.StrictJSON(api.AssistantRequest{}), aiAdvisor, aiQuota,
+					assistantHandler.Ask)
+			}
+
+			// Budget routes
+			protected.POST("/users/:userId/budgets", middleware.StrictJSON(api.CreateBudgetRequest{}), budgetHandler.CreateBudget)
+			protected.GET("/users/:userId/budgets", budgetHandler.GetBudgets)
+			protected.GET("/users/:userId/budgets/:budgetId", budgetHandler.GetBudget)
+			protected.PUT("/users/:userId/budgets/:budgetId", budgetHandler.UpdateBudget)
+			protected.DELETE("/users/:userId/budgets/:budgetId", budgetHandler.DeleteBudget)
+			protected.GET("/users/:userId/budgets/summary", budgetHandler.GetBudgetSummary)
+			protected.POST("/users/:userId/budgets/recalculate", budgetHandler.RecalculateBudgets)
+			protected.POST("/users/:userId/budgets/from-template", budgetHandler.CreateFromTemplate)
+			protected.POST("/users/:userId/budgets/simulate", budgetHandler.Simulate)
+			protected.GET("/users/:userId/budgets/export", replicaReads, budgetHandler.ExportPlan)
+			protected.POST("/users/:userId/budgets/import/preview", budgetHandler.PreviewPlan)
+			protected.POST("/users/:userId/budgets/import", budgetHandler.ImportPlan)
+			protected.GET("/budget-templates", budgetHandler.ListTemplates)
+			protected.POST("/users/:userId/goals/:goalId/contributions", goalHandler.Contribute)
+			protected.GET("/users/:userId/savings-rules", savingsRuleHandler.List)
+			protected.POST("/users/:userId/savings-rules", savingsRuleHandler.Create)
+			protected.PUT("/users/:userId/savings-rules/:ruleId", savingsRuleHandler.Update)
+			protected.DELETE("/users/:userId/savings-rules/:ruleId", savingsRuleHandler.Delete)
+			protected.POST("/users/:userId/savings-rules/preview", savingsRuleHandler.Preview)
+			protected.GET("/users/:userId/savings-rules/allocations", savingsRuleHandler.Allocations)
+
+			// Household routes, scoped to the authenticated user's memberships
+			protected.POST("/households", householdHandler.Create)
+			protected.GET("/households", householdHandler.List)
+			protected.GET("/households/invitations", householdHandler.ListInvitations)
+			protected.POST("/households/invitations/:token/accept", householdHandler.AcceptInvitation)
+			protected.POST("/households/invitations/:token/decline", householdHandler.DeclineInvitation)
+			protected.GET("/households/:householdId", householdHandler.Get)
+			protected.POST("/households/:householdId/invitations", householdHandler.Invite)
+			protected.PUT("/households/:householdId/members/:userId", householdHandler.UpdateMember)
+			protected.DELETE("/households/:householdId/members/:userId", householdHandler.RemoveMember)
+			protected.GET("/households/:householdId/transactions", householdHandler.ListTransactions)
+			protected.POST("/households/:householdId/transactions", householdHandler.CreateTransaction)
+			protected.GET("/households/:householdId/budgets", householdHandler.ListBudgets)
+			protected.POST("/households/:householdId/budgets", householdHandler.CreateBudget)
+			protected.GET("/households/:householdId/analytics", replicaReads, householdHandler.GetFinances)
+
+			// Advisor access: clients grant advisor accounts read or comment
+			// access, advisors see only the clients who did
+			protected.GET("/users/:userId/advisors", advisorClientHandler.ListAdvisors)
+			protected.POST("/users/:userId/advisors", advisorClientHandler.Grant)
+			protected.PUT("/users/:userId/advisors/:advisorId", advisorClientHandler.UpdateAccess)
+			protected.DELETE("/users/:userId/advisors/:advisorId", advisorClientHandler.Revoke)
+			protected.GET("/advisor/clients", advisorClientHandler.ListClients)
+			protected.GET("/advisor/clients/:clientId/dashboard", advisorClientHandler.ClientDashboard)
+			protected.GET("/advisor/clients/:clientId/recommendations", advisorClientHandler.ListRecommendations)
+			protected.POST("/advisor/clients/:clientId/recommendations", advisorClientHandler.Recommend)
+
+			// Comments on transactions, budgets and reports, for whoever can see them
+			protected.GET("/comments/:targetType/:targetId", commentHandler.List)
+			protected.POST("/comments/:targetType/:targetId", commentHandler.Create)
+			protected.PUT("/comments/:targetType/:targetId/:commentId", commentHandler.Update)
+			protected.DELETE("/comments/:targetType/:targetId/:commentId", commentHandler.Delete)
+
+			// Reports routes
+			protected.GET("/users/:userId/reports/monthly/:year/:month", replicaReads, reportsHandler.GenerateMonthlyReport)
+			protected.GET("/users/:userId/reports/quarterly/:year/:quarter", replicaReads, reportsHandler.GenerateQuarterlyReport)
+			protected.GET("/users/:userId/reports/yearly/:year", replicaReads, reportsHandler.GenerateYearlyReport)
+			protected.GET("/users/:userId/reports/custom", replicaReads, reportsHandler.GenerateCustomReport)
+			protected.GET("/users/:userId/reports", replicaReads, reportsHandler.GetReportsList)
+			protected.GET("/users/:userId/reports/compare", replicaReads, reportsHandler.CompareReports)
+			protected.GET("/users/:userId/reports/:reportId", replicaReads, reportsHandler.GetReport)
+			protected.DELETE("/users/:userId/reports/:reportId", reportsHandler.DeleteReport)
+
+			// Export routes
+			protected.GET("/export/transactions", replicaReads, exportHandler.ExportTransactions)
+			protected.GET("/export/budgets", replicaReads, exportHandler.ExportBudgets)
+			protected.GET("/export/reports", replicaReads, exportHandler.ExportFinancialReport)
+			protected.GET("/export/all", replicaReads, exportHandler.ExportAllData)
+			protected.GET("/export/accounts/:accountId/statement", replicaReads, exportHandler.ExportAccountStatement)
+			protected.GET("/export/formats", replicaReads, exportHandler.GetExportFormats)
+			protected.GET("/export/templates", exportHandler.ListTemplates)
+			protected.POST("/export/templates", exportHandler.CreateTemplate)
+			protected.PUT("/export/templates/:templateId", exportHandler.UpdateTemplate)
+			protected.DELETE("/export/templates/:templateId", exportHandler.DeleteTemplate)
+
+			// Investment advice
+			protected.GET("/users/:userId/advice", aiAdvisor, aiQuota, advisorHandler.GetAdvice)
+			protected.GET("/users/:userId/advice/realtime", aiAdvisor, aiQuota, advisorHandler.GetRealTimeAdvice)
+			protected.GET("/users/:userId/advice/history", adviceHistoryHandler.List)
+			protected.GET("/users/:userId/advice/history/:adviceId", adviceHistoryHandler.Get)
+			protected.GET("/users/:userId/advice/compare", adviceHistoryHandler.Compare)
+			protected.GET("/users/:userId/advice/performance", advicePerformanceHandler.GetPerformance)
+			protected.PUT("/users/:userId/advice/recommendations/:recommendationId", adviceHistoryHandler.SetRecommendationStatus)
+			protected.GET("/users/:userId/risk-assessment/questionnaire", riskAssessmentHandler.Questionnaire)
+			protected.POST("/users/:userId/risk-assessment", riskAssessmentHandler.Submit)
+			protected.GET("/users/:userId/risk-assessment/history", riskAssessmentHandler.History)
+			protected.GET("/market/data", advisorHandler.GetMarketData)
+			protected.GET("/market/crypto", advisorHandler.GetCryptoPrices)
+			protected.GET("/market/stocks", advisorHandler.GetStockPrices)
+			protected.GET("/market/summary", advisorHandler.GetMarketSummary)
+			protected.GET("/market/symbols/search", advisorHandler.SearchSymbols)
+			protected.GET("/fx/rates", fxHandler.GetRate)
+			protected.GET("/users/:userId/watchlist", watchlistHandler.List)
+			protected.POST("/users/:userId/watchlist", watchlistHandler.Add)
+			protected.GET("/users/:userId/watchlist/quotes", watchlistHandler.Quotes)
+			protected.DELETE("/users/:userId/watchlist/:symbol", watchlistHandler.Remove)
+			protected.GET("/users/:userId/price-alerts", priceAlertHandler.List)
+			protected.POST("/users/:userId/price-alerts", priceAlertHandler.Create)
+			protected.GET("/users/:userId/price-alerts/history", priceAlertHandler.History)
+			protected.GET("/users/:userId/price-alerts/:alertId", priceAlertHandler.Get)
+			protected.PUT("/users/:userId/price-alerts/:alertId", priceAlertHandler.Update)
+			protected.DELETE("/users/:userId/price-alerts/:alertId", priceAlertHandler.Delete)
+			protected.GET("/users/:userId/price-alerts/:alertId/history", priceAlertHandler.History)
+			protected.GET("/users/:userId/notifications", notificationHandler.List)
+			protected.POST("/users/:userId/notifications/read", notificationHandler.MarkAllRead)
+			protected.POST("/users/:userId/notifications/:notificationId/read", notificationHandler.MarkRead)
+			protected.GET("/users/:userId/notification-preferences", notificationHandler.Preferences)
+			protected.PUT("/users/:userId/notification-preferences", notificationHandler.UpdatePreferences)
+			protected.GET("/users/:userId/devices", notificationHandler.ListDevices)
+			protected.POST("/users/:userId/devices", notificationHandler.RegisterDevice)
+			protected.DELETE("/users/:userId/devices/:deviceId", notificationHandler.DeleteDevice)
+			protected.GET("/users/:userId/webhooks", webhookHandler.List)
+			protected.POST("/users/:userId/webhooks", webhookHandler.Create)
+			protected.DELETE("/users/:userId/webhooks/:webhookId", webhookHandler.Delete)
+			protected.GET("/users/:userId/plugins", pluginHandler.List)
+			protected.POST("/users/:userId/plugins", pluginHandler.Create)
+			protected.GET("/users/:userId/plugins/:pluginId", pluginHandler.Get)
+			protected.PUT("/users/:userId/plugins/:pluginId", pluginHandler.Update)
+			protected.DELETE("/users/:userId/plugins/:pluginId", pluginHandler.Delete)
+			protected.GET("/users/:userId/plugins/:pluginId/runs", pluginHandler.Runs)
+
+			// Offline sync: mobile clients pull the change feed and push what they queued
+			protected.GET("/users/:userId/sync/changes", syncHandler.Changes)
+			protected.POST("/users/:userId/sync/push", syncHandler.Push)
+			protected.GET("/users/:userId/sync/conflicts", syncHandler.ListConflicts)
+
+			protected.GET("/users/:userId/bills", billHandler.List)
+			protected.POST("/users/:userId/bills", billHandler.Create)
+			protected.GET("/users/:userId/bills/calendar", billHandler.Calendar)
+			protected.GET("/users/:userId/calendar/feed", calendarFeedHandler.Link)
+			protected.POST("/users/:userId/calendar/feed/rotate", calendarFeedHandler.Rotate)
+			protected.GET("/users/:userId/bills/:billId", billHandler.Get)
+			protected.PUT("/users/:userId/bills/:billId", billHandler.Update)
+			protected.DELETE("/users/:userId/bills/:billId", billHandler.Delete)
+
+			protected.GET("/users/:userId/loans", loanHandler.List)
+			protected.POST("/users/:userId/loans", loanHandler.Create)
+			protected.GET("/users/:userId/loans/:loanId", loanHandler.Get)
+			protected.PUT("/users/:userId/loans/:loanId", loanHandler.Update)
+			protected.DELETE("/users/:userId/loans/:loanId", loanHandler.Delete)
+			protected.GET("/users/:userId/loans/:loanId/schedule", loanHandler.Schedule)
+			protected.GET("/users/:userId/loans/:loanId/payoff", loanHandler.Payoff)
+			protected.POST("/users/:userId/loans/:loanId/payments", loanHandler.LinkPayment)
+			protected.DELETE("/users/:userId/loans/:loanId/payments/:number", loanHandler.UnlinkPayment)
+
+			// Income sources
+			protected.GET("/users/:userId/income-sources", incomeSourceHandler.List)
+			protected.POST("/users/:userId/income-sources", incomeSourceHandler.Create)
+			protected.GET("/users/:userId/income-sources/:sourceId", incomeSourceHandler.Get)
+			protected.PUT("/users/:userId/income-sources/:sourceId", incomeSourceHandler.Update)
+			protected.DELETE("/users/:userId/income-sources/:sourceId", incomeSourceHandler.Delete)
+			protected.POST("/users/:userId/income-sources/:sourceId/transactions", incomeSourceHandler.LinkTransactions)
+			protected.DELETE("/users/:userId/income-sources/:sourceId/transactions/:transactionId", incomeSourceHandler.UnlinkTransaction)
+
+			protected.GET("/users/:userId/portfolio/recommendations", aiAdvisor, aiQuota, advisorHandler.GetPortfolioRecommendations)
+			protected.GET("/users/:userId/portfolio/holdings", portfolioHandler.ListHoldings)
+			protected.PUT("/users/:userId/portfolio/holdings/:symbol", portfolioHandler.SaveHolding)
+			protected.DELETE("/users/:userId/portfolio/holdings/:symbol", portfolioHandler.RemoveHolding)
+			protected.GET("/users/:userId/portfolio/risk", portfolioHandler.GetRisk)
+			protected.GET("/users/:userId/net-worth", portfolioHandler.GetNetWorth)
+			protected.POST("/users/:userId/retirement/simulate", retirementHandler.Simulate)
+			protected.POST("/calculators/rent-vs-buy", calculatorHandler.RentVsBuy)
+
+			// AI-powered endpoints
+			protected.GET("/users/:userId/ai/risk-assessment", aiAdvisor, aiQuota, advisorHandler.GetAIRiskAssessment)
+			protected.GET("/ai/market/prediction", aiAdvisor, aiQuota, advisorHandler.GetAIMarketPrediction)
+			protected.GET("/users/:userId/ai/portfolio/optimization", aiAdvisor, aiQuota, advisorHandler.GetAIPortfolioOptimization)
+
+			// Audit log
+			protected.GET("/users/:userId/audit", auditHandler.GetUserAudit)
+		}
+
+		// Admin routes
+		admin := v1.Group("/admin")
+		admin.Use(middleware.AuthMiddleware(), middleware.AdminMiddleware(cfg.Auth.AdminUserIDs))
+		{
+			admin.GET("/audit", auditHandler.GetAudit)
+			admin.POST("/categories/initialize", categoryHandler.InitializeDefaultCategories)
+			admin.POST("/merchants", merchantHandler.Add)
+			admin.POST("/merchants/rematch", merchantHandler.Rematch)
+			admin.GET("/jobs", jobHandler.List)
+			admin.GET("/jobs/:jobId", jobHandler.Get)
+			admin.POST("/jobs/:jobId/requeue", jobHandler.Requeue)
+			admin.GET("/ai/config", aiConfigHandler.Get)
+			admin.PUT("/users/:userId/plan", usageHandler.SetPlan)
+			admin.GET("/stats", statsHandler.GetStats)
+			admin.GET("/archive", archiveHandler.GetStatus)
+			admin.POST("/archive/run", archiveHandler.Run)
+		}
+	}
+
+	grpcServices := rpc.Services{
+		Transactions: txSvc,
+		Budgets:      budgetSvc,
+		Reports:      reportsSvc,
+		Advisor:      advisorSvc,
+		Users:        userSvc,
+		Quotas:       quotaSvc,
+	}
+	if demoUser != nil {
+		grpcServices.DemoUserID = demoUser.ID
+	}
+	grpcServer := rpc.NewServer(grpcServices)
+	return &server{router: r, grpc: grpcServer, start: start}, nil
+}
@@ -0,0 +1,335 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Dashboard layout and refresh settings
+const (
+	dashboardRefresh      = 30 * time.Second
+	dashboardWeeks        = 12
+	dashboardTransactions = 100
+	gaugeWidth            = 20
+	// Rows the header, summary, budgets and trends leave for transactions
+	// when the terminal size is not known yet
+	defaultTransactionRows = 8
+)
+
+// dashboardPeriods are the periods tab cycles through
+var dashboardPeriods = []string{"month", "week", "quarter", "year"}
+
+// sparkBlocks draw sparklines from the lowest to the highest value
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+var (
+	titleStyle   = lipgloss.NewStyle().Bold(true)
+	sectionStyle = lipgloss.NewStyle().Bold(true).Underline(true)
+	dangerStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	warningStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
+	helpStyle    = lipgloss.NewStyle().Faint(true)
+)
+
+// dashboardAction is what the user chose when leaving the dashboard
+type dashboardAction int
+
+const (
+	dashboardQuit dashboardAction = iota
+	dashboardMenu
+	dashboardLogout
+	dashboardExpired
+)
+
+// dashboardData is one load of everything the dashboard shows. The weekly
+// totals are oldest first.
+type dashboardData struct {
+	Summary        *domain.DashboardSummary
+	Budgets        []domain.Budget
+	Transactions   []domain.Transaction
+	WeeklyIncome   []float64
+	WeeklyExpenses []float64
+	LoadedAt       time.Time
+}
+
+// loadDashboard gathers the dashboard of the logged-in user for the period
+func (app *App) loadDashboard(ctx context.Context, period string) (*dashboardData, error) {
+	userID := app.currentUser.ID
+	summary, err := app.analyticsSvc.GetDashboardSummary(ctx, userID, period)
+	if err != nil {
+		return nil, fmt.Errorf("load summary: %w", err)
+	}
+	budgets, err := app.budgetSvc.GetActiveBudgetsByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("load budgets: %w", err)
+	}
+	transactions, err := app.txSvc.ListWithFilters(ctx, userID, nil, nil, nil, nil, dashboardTransactions, 0)
+	if err != nil {
+		return nil, fmt.Errorf("load transactions: %w", err)
+	}
+
+	now := time.Now()
+	analysis, err := app.analyticsSvc.GetIncomeExpenseAnalysis(ctx, userID, "weekly", now.AddDate(0, 0, -7*dashboardWeeks), now)
+	if err != nil {
+		return nil, fmt.Errorf("load trends: %w", err)
+	}
+	data := &dashboardData{Summary: summary, Budgets: budgets, Transactions: transactions, LoadedAt: now}
+	for _, week := range analysis.WeeklyTrends {
+		data.WeeklyIncome = append(data.WeeklyIncome, week.Income)
+		data.WeeklyExpenses = append(data.WeeklyExpenses, week.Expenses)
+	}
+	return data, nil
+}
+
+// runDashboard shows the live dashboard until the user leaves it. Without a
+// usable terminal the console falls back to the menus.
+func (app *App) runDashboard() {
+	final, err := tea.NewProgram(newDashboardModel(app), tea.WithAltScreen()).Run()
+	app.lastActivity = time.Now()
+	if err != nil {
+		fmt.Printf("[ERROR] Dashboard could not be shown: %v\n", err)
+		fmt.Println("[INFO] Falling back to the main menu.")
+		app.tui = false
+		return
+	}
+
+	switch final.(dashboardModel).action {
+	case dashboardMenu:
+		app.showClassicMenu = true
+	case dashboardLogout:
+		app.logout()
+	case dashboardExpired:
+		app.expireSession()
+	case dashboardQuit:
+		printGoodbye(app.localizer())
+		os.Exit(0)
+	}
+}
+
+// dashboardModel is the bubbletea model of the live dashboard
+type dashboardModel struct {
+	app       *App
+	period    int // Index into dashboardPeriods
+	data      *dashboardData
+	err       error
+	offset    int // First transaction shown
+	height    int
+	lastInput time.Time
+	action    dashboardAction
+}
+
+type dashboardLoadedMsg struct {
+	data *dashboardData
+	err  error
+}
+
+type dashboardTickMsg time.Time
+
+func newDashboardModel(app *App) dashboardModel {
+	return dashboardModel{app: app, lastInput: time.Now()}
+}
+
+func (m dashboardModel) load() tea.Cmd {
+	period := dashboardPeriods[m.period]
+	return func() tea.Msg {
+		data, err := m.app.loadDashboard(context.Background(), period)
+		return dashboardLoadedMsg{data: data, err: err}
+	}
+}
+
+func dashboardTick() tea.Cmd {
+	return tea.Tick(dashboardRefresh, func(t time.Time) tea.Msg { return dashboardTickMsg(t) })
+}
+
+func (m dashboardModel) Init() tea.Cmd {
+	return tea.Batch(m.load(), dashboardTick())
+}
+
+func (m dashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+		m.offset = m.clampOffset(m.offset)
+	case dashboardLoadedMsg:
+		m.data, m.err = msg.data, msg.err
+		m.offset = m.clampOffset(m.offset)
+	case dashboardTickMsg:
+		if timeout := m.app.sessionTimeout; timeout > 0 && time.Time(msg).Sub(m.lastInput) > timeout {
+			m.action = dashboardExpired
+			return m, tea.Quit
+		}
+		return m, tea.Batch(m.load(), dashboardTick())
+	case tea.KeyMsg:
+		m.lastInput = time.Now()
+		return m.handleKey(msg)
+	}
+	return m, nil
+}
+
+func (m dashboardModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		m.action = dashboardQuit
+		return m, tea.Quit
+	case "q":
+		m.action = dashboardLogout
+		return m, tea.Quit
+	case "m":
+		m.action = dashboardMenu
+		return m, tea.Quit
+	case "r":
+		return m, m.load()
+	case "tab":
+		m.period = (m.period + 1) % len(dashboardPeriods)
+		return m, m.load()
+	case "up", "k":
+		m.offset = m.clampOffset(m.offset - 1)
+	case "down", "j":
+		m.offset = m.clampOffset(m.offset + 1)
+	case "pgup":
+		m.offset = m.clampOffset(m.offset - m.transactionRows())
+	case "pgdown":
+		m.offset = m.clampOffset(m.offset + m.transactionRows())
+	}
+	return m, nil
+}
+
+// transactionRows is how many transactions fit below the other sections
+func (m dashboardModel) transactionRows() int {
+	if m.height == 0 || m.data == nil {
+		return defaultTransactionRows
+	}
+	used := 14 + max(len(m.data.Budgets), 1)
+	return max(m.height-used, 3)
+}
+
+func (m dashboardModel) clampOffset(offset int) int {
+	if m.data == nil {
+		return 0
+	}
+	return max(0, min(offset, len(m.data.Transactions)-m.transactionRows()))
+}
+
+func (m dashboardModel) View() string {
+	var b strings.Builder
+	user := m.app.currentUser
+	fmt.Fprintf(&b, "%s  period: %s", titleStyle.Render(fmt.Sprintf("DASHBOARD - %s %s", user.FirstName, user.LastName)),
+		dashboardPeriods[m.period])
+	if m.data != nil {
+		fmt.Fprintf(&b, "  updated %s", m.data.LoadedAt.Format("15:04:05"))
+	}
+	b.WriteString("\n\n")
+
+	switch {
+	case m.err != nil:
+		b.WriteString(dangerStyle.Render(fmt.Sprintf("Could not load the dashboard: %v", m.err)) + "\n\n")
+	case m.data == nil:
+		b.WriteString("Loading...\n\n")
+	default:
+		m.writeSummary(&b)
+		m.writeBudgets(&b)
+		m.writeTrends(&b)
+		m.writeTransactions(&b)
+	}
+
+	b.WriteString(helpStyle.Render("↑/↓ scroll · tab period · r refresh · m menu · q logout · ctrl+c exit"))
+	return b.String()
+}
+
+func (m dashboardModel) writeSummary(b *strings.Builder) {
+	s := m.data.Summary
+	fmt.Fprintf(b, "Balance $%.2f   Income $%.2f   Expenses $%.2f   Savings rate %.1f%%\n\n",
+		s.TotalBalance, s.MonthlyIncome, s.MonthlyExpenses, s.SavingsRate)
+}
+
+func (m dashboardModel) writeBudgets(b *strings.Builder) {
+	b.WriteString(sectionStyle.Render("BUDGETS") + "\n")
+	if len(m.data.Budgets) == 0 {
+		b.WriteString("No active budgets\n\n")
+		return
+	}
+	for _, budget := range m.data.Budgets {
+		percent := budget.Spent.PercentOf(budget.Amount)
+		line := fmt.Sprintf("%-16s %s %5.1f%%  $%.2f / $%.2f", truncate(m.app.localizer().Category(budget.Category.Name), 16), gauge(percent, gaugeWidth),
+			percent, budget.Spent.Float64(), budget.Amount.Float64())
+		switch {
+		case percent >= 100:
+			line = dangerStyle.Render(line)
+		case percent >= 80:
+			line = warningStyle.Render(line)
+		}
+		b.WriteString(line + "\n")
+	}
+	b.WriteString("\n")
+}
+
+func (m dashboardModel) writeTrends(b *strings.Builder) {
+	fmt.Fprintf(b, "%s\n", sectionStyle.Render(fmt.Sprintf("TRENDS (last %d weeks)", dashboardWeeks)))
+	fmt.Fprintf(b, "Income    %s\n", sparkline(m.data.WeeklyIncome))
+	fmt.Fprintf(b, "Expenses  %s\n\n", sparkline(m.data.WeeklyExpenses))
+}
+
+func (m dashboardModel) writeTransactions(b *strings.Builder) {
+	transactions := m.data.Transactions
+	if len(transactions) == 0 {
+		b.WriteString(sectionStyle.Render("RECENT TRANSACTIONS") + "\nNo transactions yet\n\n")
+		return
+	}
+	end := min(m.offset+m.transactionRows(), len(transactions))
+	fmt.Fprintf(b, "%s\n", sectionStyle.Render(fmt.Sprintf("RECENT TRANSACTIONS (%d-%d of %d)", m.offset+1, end, len(transactions))))
+	for _, tx := range transactions[m.offset:end] {
+		amount := tx.Amount.Float64()
+		if tx.Type == domain.TransactionTypeExpense {
+			amount = -amount
+		}
+		fmt.Fprintf(b, "%s  %-16s %-24s %10.2f\n", tx.Date.Format("2006-01-02"), truncate(m.app.localizer().Category(tx.Category.Name), 16),
+			truncate(tx.Description, 24), amount)
+	}
+	b.WriteString("\n")
+}
+
+// sparkline draws the values as a row of blocks scaled between their minimum
+// and maximum
+func sparkline(values []float64) string {
+	if len(values) == 0 {
+		return "no data"
+	}
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		low, high = math.Min(low, v), math.Max(high, v)
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		level := 0
+		if high > low {
+			level = int(math.Round((v - low) / (high - low) * float64(len(sparkBlocks)-1)))
+		}
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
+
+// gauge draws how much of a budget is used as a bar of width cells, full
+// from 100% on
+func gauge(percent float64, width int) string {
+	filled := int(math.Round(math.Min(math.Max(percent, 0), 100) / 100 * float64(width)))
+	return "[" + strings.Repeat("█", filled) + strings.Repeat("░", width-filled) + "]"
+}
+
+// truncate shortens text to at most width runes, marking the cut with "…"
+func truncate(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	return string(runes[:width-1]) + "…"
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSparkline(t *testing.T) {
+	assert.Equal(t, "▁▅█", sparkline([]float64{0, 50, 100}))
+	assert.Equal(t, "▁▁▁", sparkline([]float64{7, 7, 7}))
+	assert.Equal(t, "no data", sparkline(nil))
+}
+
+func TestGauge(t *testing.T) {
+	assert.Equal(t, "[█████░░░░░]", gauge(50, 10))
+	assert.Equal(t, "[░░░░░░░░░░]", gauge(-5, 10))
+	assert.Equal(t, "[██████████]", gauge(140, 10))
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "Groceries", truncate("Groceries", 16))
+	assert.Equal(t, "Entertainm…", truncate("Entertainment & Leisure", 11))
+}
+
+func TestLoadDashboard(t *testing.T) {
+	app, _ := setupTestApp(t)
+	setupReportTestUser(t, app, "dashboard@example.com")
+	ctx := context.Background()
+
+	categories, err := app.categorySvc.GetAllCategories(ctx, app.currentUser.ID)
+	require.NoError(t, err)
+	now := time.Now()
+	require.NoError(t, app.budgetSvc.CreateBudget(ctx, &domain.Budget{
+		UserID: app.currentUser.ID, CategoryID: categoryOfType(t, categories, "expense").ID, Amount: domain.NewMoney(500),
+		Period: domain.PeriodMonthly, StartDate: now.AddDate(0, 0, -1), EndDate: now.AddDate(0, 1, 0),
+	}))
+
+	data, err := app.loadDashboard(ctx, "month")
+	require.NoError(t, err)
+	assert.Len(t, data.Transactions, 2)
+	assert.Len(t, data.Budgets, 1)
+	assert.NotEmpty(t, data.WeeklyIncome)
+	assert.Len(t, data.WeeklyExpenses, len(data.WeeklyIncome))
+
+	m := newDashboardModel(app)
+	updated, _ := m.Update(dashboardLoadedMsg{data: data})
+	view := updated.View()
+	assert.Contains(t, view, "DASHBOARD - Report User")
+	assert.Contains(t, view, "BUDGETS")
+	assert.Contains(t, view, "TRENDS (last 12 weeks)")
+	assert.Contains(t, view, "RECENT TRANSACTIONS (1-2 of 2)")
+	assert.Contains(t, view, "Groceries")
+}
+
+func TestDashboardModel_Update(t *testing.T) {
+	app, _ := setupTestApp(t)
+	app.currentUser = &domain.User{ID: 1, FirstName: "Live"}
+
+	transactions := make([]domain.Transaction, 20)
+	for i := range transactions {
+		transactions[i] = domain.Transaction{ID: uint(i + 1), Description: "Coffee", Amount: domain.NewMoney(3)}
+	}
+	var model tea.Model = newDashboardModel(app)
+	model, _ = model.Update(dashboardLoadedMsg{data: &dashboardData{Summary: &domain.DashboardSummary{}, Transactions: transactions}})
+	key := func(k tea.KeyType) tea.Msg { return tea.KeyMsg{Type: k} }
+
+	t.Run("scrolling stays within the transactions", func(t *testing.T) {
+		m, _ := model.Update(key(tea.KeyUp))
+		assert.Zero(t, m.(dashboardModel).offset)
+
+		m, _ = m.Update(key(tea.KeyPgDown))
+		assert.Equal(t, defaultTransactionRows, m.(dashboardModel).offset)
+
+		for range 5 {
+			m, _ = m.Update(key(tea.KeyDown))
+		}
+		assert.Equal(t, len(transactions)-defaultTransactionRows, m.(dashboardModel).offset)
+	})
+
+	t.Run("tab switches the period and reloads", func(t *testing.T) {
+		m, cmd := model.Update(key(tea.KeyTab))
+		assert.Equal(t, "week", dashboardPeriods[m.(dashboardModel).period])
+		assert.NotNil(t, cmd)
+	})
+
+	t.Run("keys choose how the dashboard is left", func(t *testing.T) {
+		m, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+		assert.Equal(t, dashboardMenu, m.(dashboardModel).action)
+		assert.NotNil(t, cmd)
+
+		m, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+		assert.Equal(t, dashboardLogout, m.(dashboardModel).action)
+	})
+
+	t.Run("idle dashboards expire the session", func(t *testing.T) {
+		app.sessionTimeout = time.Minute
+		defer func() { app.sessionTimeout = 0 }()
+
+		m, _ := model.Update(dashboardTickMsg(time.Now()))
+		assert.Equal(t, dashboardQuit, m.(dashboardModel).action)
+
+		m, _ = model.Update(dashboardTickMsg(time.Now().Add(2 * time.Minute)))
+		assert.Equal(t, dashboardExpired, m.(dashboardModel).action)
+	})
+}
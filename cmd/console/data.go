@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+)
+
+// maxImportErrors is how many rejected rows an import prints
+const maxImportErrors = 10
+
+func (app *App) exportMenu() {
+	l := app.localizer()
+	fmt.Println("\n" + strings.Repeat("-", 40))
+	fmt.Println("           " + l.T("menu.export_title"))
+	fmt.Println(strings.Repeat("-", 40))
+	printMenuOptions(l, 40, "menu.export_transactions", "menu.monthly_report", "menu.yearly_report",
+		"menu.export_budgets", "menu.export_all_data", "menu.return")
+
+	choice := app.readLine()
+
+	switch choice {
+	case "1":
+		app.exportTransactions()
+	case "2":
+		app.exportReport(domain.ReportTypeMonthly)
+	case "3":
+		app.exportReport(domain.ReportTypeYearly)
+	case "4":
+		app.exportBudgets()
+	case "5":
+		app.exportAllData()
+	case "6":
+		return
+	default:
+		printInvalidSelection(l, 6)
+	}
+}
+
+func (app *App) exportTransactions() {
+	format, ok := app.promptExportFormat(domain.ExportFormatCSV, domain.ExportFormatJSON, domain.ExportFormatQIF)
+	if !ok {
+		return
+	}
+	fmt.Println("Leave the dates empty to export every transaction.")
+	var startDate, endDate *time.Time
+	if start := app.promptString("Start date (YYYY-MM-DD): ", ""); start != "" {
+		from, err := time.ParseInLocation("2006-01-02", start, time.Local)
+		if err != nil {
+			fmt.Println("[ERROR] Invalid start date format! Please use YYYY-MM-DD.")
+			return
+		}
+		to, err := app.promptDate(fmt.Sprintf("End date (YYYY-MM-DD) [%s]: ", time.Now().Format("2006-01-02")), time.Now())
+		if err != nil {
+			fmt.Println("[ERROR] Invalid end date format! Please use YYYY-MM-DD.")
+			return
+		}
+		to = time.Date(to.Year(), to.Month(), to.Day(), 23, 59, 59, 0, to.Location())
+		startDate, endDate = &from, &to
+	}
+
+	data, filename, err := app.exportSvc.ExportTransactions(context.Background(), app.currentUser.ID, format, startDate, endDate)
+	if err != nil {
+		fmt.Printf("[ERROR] Could not export transactions: %v\n", err)
+		return
+	}
+	app.saveExport(data, filename)
+}
+
+func (app *App) exportReport(reportType string) {
+	format, ok := app.promptExportFormat(domain.ExportFormatCSV, domain.ExportFormatJSON)
+	if !ok {
+		return
+	}
+	now := time.Now()
+	year, err := app.promptInt(fmt.Sprintf("Year [%d]: ", now.Year()), now.Year())
+	if err != nil {
+		fmt.Println("[ERROR] Invalid year! Please enter a valid number.")
+		return
+	}
+	month := 0
+	if reportType == domain.ReportTypeMonthly {
+		month, err = app.promptInt(fmt.Sprintf("Month (1-12) [%d]: ", int(now.Month())), int(now.Month()))
+		if err != nil || month < 1 || month > 12 {
+			fmt.Println("[ERROR] Invalid month! Please enter a number between 1 and 12.")
+			return
+		}
+	}
+
+	data, filename, err := app.exportSvc.ExportFinancialReport(context.Background(), app.currentUser.ID, reportType, year, month, format)
+	if err != nil {
+		fmt.Printf("[ERROR] Could not export report: %v\n", err)
+		return
+	}
+	app.saveExport(data, filename)
+}
+
+func (app *App) exportBudgets() {
+	format, ok := app.promptExportFormat(domain.ExportFormatCSV, domain.ExportFormatJSON)
+	if !ok {
+		return
+	}
+	data, filename, err := app.exportSvc.ExportBudgets(context.Background(), app.currentUser.ID, format)
+	if err != nil {
+		fmt.Printf("[ERROR] Could not export budgets: %v\n", err)
+		return
+	}
+	app.saveExport(data, filename)
+}
+
+func (app *App) exportAllData() {
+	data, filename, err := app.exportSvc.ExportAllData(context.Background(), app.currentUser.ID, domain.ExportFormatJSON)
+	if err != nil {
+		fmt.Printf("[ERROR] Could not export data: %v\n", err)
+		return
+	}
+	app.saveExport(data, filename)
+}
+
+// promptExportFormat asks for one of the formats, the first by default
+func (app *App) promptExportFormat(formats ...domain.ExportFormat) (domain.ExportFormat, bool) {
+	names := make([]string, len(formats))
+	for i, format := range formats {
+		names[i] = string(format)
+	}
+	input := app.promptString(fmt.Sprintf("Format (%s) [%s]: ", strings.Join(names, "/"), names[0]), names[0])
+	for _, format := range formats {
+		if strings.EqualFold(input, string(format)) {
+			return format, true
+		}
+	}
+	fmt.Printf("[ERROR] Invalid format! Please enter %s.\n", strings.Join(names, ", "))
+	return "", false
+}
+
+// saveExport writes an export to a directory the user picks, the current
+// one by default. Exports hold financial data, so only the user can read them.
+func (app *App) saveExport(data []byte, filename string) {
+	dir := app.promptString("Save to directory [.]: ", ".")
+	path := filepath.Join(dir, filepath.Base(filename))
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		fmt.Printf("[ERROR] Could not save the export: %v\n", err)
+		return
+	}
+	fmt.Printf("\n[SUCCESS] ✅ Exported %d bytes to %s\n", len(data), path)
+}
+
+// importTransactions records the transactions of a file exported from
+// another finance app. Files already imported only add what is new.
+func (app *App) importTransactions() {
+	fmt.Println("\n" + strings.Repeat("-", 40))
+	fmt.Println("        IMPORT TRANSACTIONS")
+	fmt.Println(strings.Repeat("-", 40))
+
+	source := strings.ToLower(app.promptString(
+		fmt.Sprintf("Exported from (%s): ", strings.Join(domain.ImportSources, "/")), ""))
+	if !domain.IsValidImportSource(source) {
+		fmt.Printf("[ERROR] %v\n", domain.ErrUnknownImportSource)
+		return
+	}
+	path := app.promptString("File path: ", "")
+	if path == "" {
+		fmt.Println("[ERROR] Please enter the path of the exported file.")
+		return
+	}
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		fmt.Printf("[ERROR] Could not read the file: %v\n", err)
+		return
+	}
+
+	result, err := app.importSvc.Import(context.Background(), app.currentUser.ID, source, data)
+	if err != nil {
+		fmt.Printf("[ERROR] Could not import transactions: %v\n", err)
+		return
+	}
+	printImportResult(result)
+}
+
+func printImportResult(result *domain.ImportResult) {
+	fmt.Println("\n[SUCCESS] ✅ Import finished")
+	fmt.Printf("  Rows read:          %d\n", result.Rows)
+	fmt.Printf("  Imported:           %d\n", result.Imported)
+	fmt.Printf("  Already recorded:   %d\n", result.Duplicates)
+	fmt.Printf("  Transfers skipped:  %d\n", result.Transfers)
+	if result.Suggested > 0 {
+		fmt.Printf("  Category suggested: %d\n", result.Suggested)
+	}
+	if len(result.Unmapped) > 0 {
+		fmt.Printf("\n[INFO] Filed under \"Other\": %s\n", strings.Join(result.Unmapped, ", "))
+	}
+	if len(result.Errors) == 0 {
+		return
+	}
+	fmt.Printf("\n[WARNING] %d row(s) could not be imported:\n", len(result.Errors))
+	for i, rowError := range result.Errors {
+		if i == maxImportErrors {
+			fmt.Printf("  ... and %d more\n", len(result.Errors)-maxImportErrors)
+			break
+		}
+		fmt.Printf("  Row %d: %s\n", rowError.Row, rowError.Message)
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportMenus(t *testing.T) {
+	app, _ := setupTestApp(t)
+	setupReportTestUser(t, app, "exports@example.com")
+
+	t.Run("exports transactions to a csv file", func(t *testing.T) {
+		dir := t.TempDir()
+		app.reader = bufio.NewReader(strings.NewReader("csv\n2024-03-01\n2024-03-31\n" + dir + "\n"))
+		output := captureOutput(app.exportTransactions)
+
+		assert.Contains(t, output, "[SUCCESS] ✅ Exported")
+		files, err := filepath.Glob(filepath.Join(dir, "*.csv"))
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+		data, err := os.ReadFile(files[0])
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "Salary")
+		assert.Contains(t, string(data), "Groceries")
+	})
+
+	t.Run("rejects an unknown format", func(t *testing.T) {
+		app.reader = bufio.NewReader(strings.NewReader("xlsx\n"))
+		output := captureOutput(app.exportTransactions)
+
+		assert.Contains(t, output, "[ERROR] Invalid format! Please enter csv, json, qif.")
+	})
+
+	t.Run("rejects an invalid start date", func(t *testing.T) {
+		app.reader = bufio.NewReader(strings.NewReader("json\n03/01/2024\n"))
+		output := captureOutput(app.exportTransactions)
+
+		assert.Contains(t, output, "[ERROR] Invalid start date format!")
+	})
+
+	t.Run("exports a monthly report as json", func(t *testing.T) {
+		dir := t.TempDir()
+		app.reader = bufio.NewReader(strings.NewReader("json\n2024\n3\n" + dir + "\n"))
+		output := captureOutput(func() { app.exportReport("monthly") })
+
+		assert.Contains(t, output, "[SUCCESS] ✅ Exported")
+		files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+		require.NoError(t, err)
+		assert.Len(t, files, 1)
+	})
+
+	t.Run("reports a directory that does not exist", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "missing")
+		app.reader = bufio.NewReader(strings.NewReader("\n" + dir + "\n"))
+		output := captureOutput(app.exportBudgets)
+
+		assert.Contains(t, output, "[ERROR] Could not save the export")
+	})
+}
+
+func TestImportTransactions(t *testing.T) {
+	app, _ := setupTestApp(t)
+	setupReportTestUser(t, app, "imports@example.com")
+
+	path := filepath.Join(t.TempDir(), "mint.csv")
+	require.NoError(t, os.WriteFile(path, []byte("Date,Description,Amount,Transaction Type,Category,Labels\n"+
+		"4/01/2024,Starbucks,4.50,debit,Coffee Shops,\n"+
+		"4/02/2024,ACME Payroll,2500.00,credit,Paycheck,\n"+
+		"4/03/2024,Savings,500.00,debit,Transfer,\n"), 0o600))
+
+	t.Run("imports a mint export", func(t *testing.T) {
+		app.reader = bufio.NewReader(strings.NewReader("mint\n" + path + "\n"))
+		output := captureOutput(app.importTransactions)
+
+		assert.Contains(t, output, "[SUCCESS] ✅ Import finished")
+		assert.Contains(t, output, "Rows read:          3")
+		assert.Contains(t, output, "Imported:           2")
+		assert.Contains(t, output, "Transfers skipped:  1")
+	})
+
+	t.Run("skips transactions already imported", func(t *testing.T) {
+		app.reader = bufio.NewReader(strings.NewReader("mint\n" + path + "\n"))
+		output := captureOutput(app.importTransactions)
+
+		assert.Contains(t, output, "Imported:           0")
+		assert.Contains(t, output, "Already recorded:   2")
+	})
+
+	t.Run("rejects an unknown source", func(t *testing.T) {
+		app.reader = bufio.NewReader(strings.NewReader("quicken\n"))
+		output := captureOutput(app.importTransactions)
+
+		assert.Contains(t, output, "[ERROR] import source must be mint, ynab or personal_capital")
+	})
+
+	t.Run("reports a file that cannot be read", func(t *testing.T) {
+		app.reader = bufio.NewReader(strings.NewReader("ynab\n" + filepath.Join(t.TempDir(), "missing.csv") + "\n"))
+		output := captureOutput(app.importTransactions)
+
+		assert.Contains(t, output, "[ERROR] Could not read the file")
+	})
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// Input rules, matching what the API accepts at registration
+const (
+	maxInputAttempts  = 3
+	minPasswordLength = 8
+	maxNameLength     = 50
+)
+
+// errSessionExpired unwinds the menu a user returns to after their session
+// timed out; run recovers it and shows the login menu
+var errSessionExpired = errors.New("session expired")
+
+// terminalPasswordReader returns a reader of passwords that are not echoed,
+// or nil when stdin is not a terminal and passwords are read like any line
+func terminalPasswordReader() func() (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return nil
+	}
+	return func() (string, error) {
+		password, err := term.ReadPassword(fd)
+		fmt.Println()
+		return string(password), err
+	}
+}
+
+// readLine reads one trimmed line of input. A logged-in user who answers
+// after the session timeout is logged out instead.
+func (app *App) readLine() string {
+	line, _ := app.reader.ReadString('\n')
+	app.checkSession()
+	return strings.TrimSpace(line)
+}
+
+// readPassword reads a password without echoing it when stdin is a terminal
+func (app *App) readPassword() string {
+	if app.passwordReader == nil {
+		return app.readLine()
+	}
+	password, err := app.passwordReader()
+	app.checkSession()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(password)
+}
+
+// checkSession logs the user out when the last input was longer ago than the
+// session timeout, and otherwise records the activity
+func (app *App) checkSession() {
+	now := time.Now()
+	expired := app.currentUser != nil && app.sessionTimeout > 0 &&
+		!app.lastActivity.IsZero() && now.Sub(app.lastActivity) > app.sessionTimeout
+	app.lastActivity = now
+	if !expired {
+		return
+	}
+
+	app.expireSession()
+	panic(errSessionExpired)
+}
+
+// expireSession logs out the user whose session timed out
+func (app *App) expireSession() {
+	app.currentUser = nil
+	fmt.Printf("\n[INFO] Session expired after %s of inactivity. Please log in again.\n", app.sessionTimeout)
+}
+
+// promptValid asks for a value until it passes validate, giving up after
+// maxInputAttempts. read is readLine or readPassword.
+func (app *App) promptValid(label string, read func() string, validate func(string) error) (string, bool) {
+	for attempt := 1; attempt <= maxInputAttempts; attempt++ {
+		fmt.Print(label)
+		value := read()
+		err := validate(value)
+		if err == nil {
+			return value, true
+		}
+		fmt.Printf("[ERROR] Invalid input: %v\n", err)
+	}
+	fmt.Println("[ERROR] Too many invalid attempts.")
+	return "", false
+}
+
+func validateName(name string) error {
+	if name == "" {
+		return errors.New("name is required")
+	}
+	if len(name) > maxNameLength {
+		return fmt.Errorf("name must be at most %d characters", maxNameLength)
+	}
+	return nil
+}
+
+func validateEmail(email string) error {
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email {
+		return errors.New("please enter a valid email address, e.g. name@example.com")
+	}
+	return nil
+}
+
+func validatePassword(password string) error {
+	if len(password) < minPasswordLength {
+		return fmt.Errorf("password must be at least %d characters", minPasswordLength)
+	}
+	return nil
+}
+
+func requireValue(value string) error {
+	if value == "" {
+		return errors.New("a value is required")
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromptValid(t *testing.T) {
+	app, _ := setupTestApp(t)
+
+	t.Run("asks again until the value is valid", func(t *testing.T) {
+		app.reader = bufio.NewReader(strings.NewReader("not-an-email\n  ada@example.com \n"))
+		var email string
+		var ok bool
+		output := captureOutput(func() {
+			email, ok = app.promptValid("Email Address: ", app.readLine, validateEmail)
+		})
+
+		assert.True(t, ok)
+		assert.Equal(t, "ada@example.com", email)
+		assert.Equal(t, 2, strings.Count(output, "Email Address: "))
+		assert.Contains(t, output, "[ERROR] Invalid input: please enter a valid email address")
+	})
+
+	t.Run("gives up after too many attempts", func(t *testing.T) {
+		app.reader = bufio.NewReader(strings.NewReader("\n\n\n\n"))
+		var ok bool
+		output := captureOutput(func() {
+			_, ok = app.promptValid("First Name: ", app.readLine, validateName)
+		})
+
+		assert.False(t, ok)
+		assert.Equal(t, maxInputAttempts, strings.Count(output, "First Name: "))
+		assert.Contains(t, output, "Too many invalid attempts")
+	})
+}
+
+func TestRegisterAndLogin(t *testing.T) {
+	app, _ := setupTestApp(t)
+
+	t.Run("register rejects short passwords and asks for confirmation", func(t *testing.T) {
+		app.reader = bufio.NewReader(strings.NewReader("Ada\nLovelace\nada@example.com\nshort\nanalytical\nanalytical\n"))
+		output := captureOutput(app.register)
+
+		assert.Contains(t, output, "password must be at least 8 characters")
+		assert.Contains(t, output, "Account created successfully")
+	})
+
+	t.Run("register stops when the confirmation never matches", func(t *testing.T) {
+		app.reader = bufio.NewReader(strings.NewReader("Grace\nHopper\ngrace@example.com\ncompilers\none\ntwo\nthree\n"))
+		output := captureOutput(app.register)
+
+		assert.Contains(t, output, "passwords do not match")
+		assert.NotContains(t, output, "Account created successfully")
+		_, err := app.userSvc.GetByEmail(context.Background(), "grace@example.com")
+		assert.Error(t, err)
+	})
+
+	t.Run("login reads the password through the password reader", func(t *testing.T) {
+		app.reader = bufio.NewReader(strings.NewReader("ada@example.com\n"))
+		app.passwordReader = func() (string, error) { return "analytical", nil }
+		defer func() { app.passwordReader = nil }()
+
+		output := captureOutput(app.login)
+
+		assert.Contains(t, output, "Welcome back, Ada Lovelace")
+		require.NotNil(t, app.currentUser)
+		assert.Equal(t, "ada@example.com", app.currentUser.Email)
+	})
+}
+
+func TestSessionTimeout(t *testing.T) {
+	app, _ := setupTestApp(t)
+	app.sessionTimeout = time.Minute
+
+	t.Run("input within the timeout keeps the session", func(t *testing.T) {
+		app.currentUser = &domain.User{ID: 1, FirstName: "Active"}
+		app.lastActivity = time.Now().Add(-30 * time.Second)
+		app.reader = bufio.NewReader(strings.NewReader("42\n"))
+
+		output := captureOutput(app.showMenu)
+
+		assert.Contains(t, output, "Invalid selection")
+		assert.NotNil(t, app.currentUser)
+	})
+
+	t.Run("input after the timeout logs the user out", func(t *testing.T) {
+		app.currentUser = &domain.User{ID: 1, FirstName: "Idle"}
+		app.lastActivity = time.Now().Add(-2 * time.Minute)
+		app.reader = bufio.NewReader(strings.NewReader("1\n"))
+
+		output := captureOutput(app.showMenu)
+
+		assert.Contains(t, output, "Session expired after 1m0s of inactivity")
+		assert.NotContains(t, output, "ADD NEW TRANSACTION")
+		assert.Nil(t, app.currentUser)
+	})
+}
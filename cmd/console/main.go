@@ -2,6 +2,9 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"strconv"
@@ -9,11 +12,12 @@ import (
 	"time"
 
 	"go-finance-advisor/internal/application"
+	"go-finance-advisor/internal/config"
 	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/i18n"
+	"go-finance-advisor/internal/infrastructure/persistence"
+	"go-finance-advisor/internal/infrastructure/persistence/migrations"
 
-	"github.com/glebarez/sqlite"
-	"golang.org/x/text/cases"
-	"golang.org/x/text/language"
 	"gorm.io/gorm"
 )
 
@@ -26,21 +30,42 @@ type App struct {
 	categorySvc  *application.CategoryService
 	reportsSvc   *application.ReportsService
 	exportSvc    *application.ExportService
+	importSvc    *application.ImportService
+	riskSvc      *application.RiskAssessmentService
 	currentUser  *domain.User
 	reader       *bufio.Reader
+	locale       string // Language of the menus until a user with their own logs in
+
+	passwordReader func() (string, error) // Reads passwords without echo; nil reads them from reader
+	sessionTimeout time.Duration          // Idle time after which the user is logged out; zero never
+	lastActivity   time.Time
+
+	tui             bool // Shows the live dashboard instead of the main menu
+	showClassicMenu bool // Shows the main menu once, when asked for from the dashboard
 }
 
 func main() {
+	tui := flag.Bool("tui", false, "show a live dashboard instead of the main menu after login")
+	flag.Parse()
+
 	printHeader()
 
+	cfg, err := config.Load("")
+	if err != nil {
+		fmt.Printf("[ERROR] Configuration could not be loaded: %v\n", err)
+		return
+	}
+
 	// Initialize database
-	db := initializeDatabase()
+	db := initializeDatabase(cfg)
 	if db == nil {
 		return
 	}
 
 	// Initialize application
 	app := initializeApp(db)
+	app.sessionTimeout = cfg.Console.SessionTimeout.Std()
+	app.tui = *tui
 
 	// Start main application loop
 	app.run()
@@ -54,17 +79,20 @@ func printHeader() {
 	fmt.Println("" + strings.Repeat("-", 60))
 }
 
-func initializeDatabase() *gorm.DB {
+func initializeDatabase(cfg *config.Config) *gorm.DB {
 	fmt.Println("[INFO] Initializing database connection...")
-	db, err := gorm.Open(sqlite.Open("finance.db"), &gorm.Config{})
+	db, err := persistence.OpenSQLite(cfg.Database.DSN, cfg.Database.BusyTimeout.Std())
 	if err != nil {
 		fmt.Printf("[ERROR] Database connection failed: %v\n", err)
 		return nil
 	}
+	if err := db.Use(persistence.NewQueryTimeout(cfg.Database.QueryTimeout.Std())); err != nil {
+		fmt.Printf("[ERROR] Query timeout could not be configured: %v\n", err)
+		return nil
+	}
 
 	fmt.Println("[INFO] Running database migrations...")
-	err = db.AutoMigrate(&domain.User{}, &domain.Transaction{}, &domain.Category{}, &domain.Budget{})
-	if err != nil {
+	if _, err := migrations.New(db).Up(context.Background()); err != nil {
 		fmt.Printf("[ERROR] Database migration failed: %v\n", err)
 		return nil
 	}
@@ -77,23 +105,30 @@ func initializeApp(db *gorm.DB) *App {
 	fmt.Println("[INFO] Initializing application services...")
 
 	// Initialize services
-	userSvc := &application.UserService{DB: db}
-	txSvc := &application.TransactionService{DB: db}
-	advisorSvc := &application.AdvisorService{DB: db}
+	auditSvc := application.NewAuditService(db)
+	userSvc := &application.UserService{DB: db, Audit: auditSvc}
+	merchantSvc := application.NewMerchantService(db)
+	budgetSvc := &application.BudgetService{DB: db, Audit: auditSvc}
+	txSvc := &application.TransactionService{DB: db, Audit: auditSvc, Merchants: merchantSvc, Budgets: budgetSvc}
+	advisorSvc := &application.AdvisorService{DB: db, History: application.NewAdviceHistoryService(db)}
 	analyticsSvc := &application.AnalyticsService{DB: db}
-	budgetSvc := &application.BudgetService{DB: db}
-	categorySvc := &application.CategoryService{DB: db}
+	categorySvc := &application.CategoryService{DB: db, Audit: auditSvc}
 	reportsSvc := &application.ReportsService{DB: db}
 	exportSvc := &application.ExportService{DB: db}
+	importSvc := &application.ImportService{DB: db, Transactions: txSvc}
+	riskSvc := &application.RiskAssessmentService{DB: db, Audit: auditSvc}
 
 	// Initialize default categories
 	fmt.Println("[INFO] Setting up default categories...")
-	err := categorySvc.InitializeDefaultCategories()
+	err := categorySvc.InitializeDefaultCategories(context.Background())
 	if err != nil {
 		fmt.Printf("[WARNING] Could not initialize default categories: %v\n", err)
 	} else {
 		fmt.Println("[SUCCESS] Default categories initialized")
 	}
+	if err := merchantSvc.InitializeDefaultMerchants(context.Background()); err != nil {
+		fmt.Printf("[WARNING] Could not initialize default merchants: %v\n", err)
+	}
 
 	return &App{
 		userSvc:      userSvc,
@@ -104,8 +139,48 @@ func initializeApp(db *gorm.DB) *App {
 		categorySvc:  categorySvc,
 		reportsSvc:   reportsSvc,
 		exportSvc:    exportSvc,
+		importSvc:    importSvc,
+		riskSvc:      riskSvc,
 		reader:       bufio.NewReader(os.Stdin),
+		locale:       i18n.Match(os.Getenv("LC_ALL"), os.Getenv("LANG")),
+
+		passwordReader: terminalPasswordReader(),
+	}
+}
+
+// localizer returns the localizer of the logged-in user's locale, or of the
+// terminal's before login
+func (app *App) localizer() *i18n.Localizer {
+	if app.currentUser != nil && app.currentUser.Locale != "" {
+		return i18n.New(app.currentUser.Locale)
+	}
+	return i18n.New(app.locale)
+}
+
+// printMenuOptions prints the numbered options of a menu and asks for one
+func printMenuOptions(l *i18n.Localizer, width int, options ...string) {
+	for i, option := range options {
+		fmt.Printf("  %d. %s\n", i+1, l.T(option))
 	}
+	fmt.Println(strings.Repeat("-", width))
+	fmt.Print(l.T("menu.select", map[string]any{"Max": len(options)}))
+}
+
+// printInvalidSelection tells the user to choose one of a menu's options
+func printInvalidSelection(l *i18n.Localizer, options int) {
+	fmt.Println("\n[ERROR] " + l.T("menu.invalid", map[string]any{"Max": options}))
+}
+
+// printGoodbye thanks the user before the console exits
+func printGoodbye(l *i18n.Localizer) {
+	fmt.Println("\n[INFO] " + l.T("menu.goodbye"))
+}
+
+// logout logs the user out and returns to the login menu
+func (app *App) logout() {
+	l := app.localizer()
+	app.currentUser = nil
+	fmt.Println("\n[INFO] " + l.T("menu.logged_out"))
 }
 
 func (app *App) run() {
@@ -114,26 +189,38 @@ func (app *App) run() {
 
 	// Main application loop
 	for {
-		if app.currentUser == nil {
-			app.showLoginMenu()
-		} else {
-			app.showMainMenu()
+		app.showMenu()
+	}
+}
+
+// showMenu shows the login or main menu. A session that expires while a menu
+// waits for input returns here, to the login menu.
+func (app *App) showMenu() {
+	defer func() {
+		if recovered := recover(); recovered != nil && recovered != errSessionExpired {
+			panic(recovered)
 		}
+	}()
+
+	switch {
+	case app.currentUser == nil:
+		app.showLoginMenu()
+	case app.tui && !app.showClassicMenu:
+		app.runDashboard()
+	default:
+		app.showClassicMenu = false
+		app.showMainMenu()
 	}
 }
 
 func (app *App) showLoginMenu() {
+	l := app.localizer()
 	fmt.Println("\n" + strings.Repeat("=", 50))
-	fmt.Println("                 LOGIN MENU")
+	fmt.Println("                 " + l.T("menu.login_title"))
 	fmt.Println(strings.Repeat("=", 50))
-	fmt.Println("  1. Login to Account")
-	fmt.Println("  2. Create New Account")
-	fmt.Println("  3. Exit Application")
-	fmt.Println(strings.Repeat("-", 50))
-	fmt.Print("Please select an option (1-3): ")
+	printMenuOptions(l, 50, "menu.login", "menu.register", "menu.exit")
 
-	choice, _ := app.reader.ReadString('\n')
-	choice = strings.TrimSpace(choice)
+	choice := app.readLine()
 
 	switch choice {
 	case "1":
@@ -141,11 +228,11 @@ func (app *App) showLoginMenu() {
 	case "2":
 		app.register()
 	case "3":
-		fmt.Println("\n[INFO] Thank you for using Personal Finance Advisor!")
-		fmt.Println("[INFO] Goodbye!")
+		printGoodbye(l)
+		fmt.Println("[INFO] " + l.T("menu.bye"))
 		os.Exit(0)
 	default:
-		fmt.Println("\n[ERROR] Invalid selection! Please choose 1, 2, or 3.")
+		printInvalidSelection(l, 3)
 	}
 }
 
@@ -154,16 +241,17 @@ func (app *App) login() {
 	fmt.Println("         USER LOGIN")
 	fmt.Println(strings.Repeat("-", 30))
 
-	fmt.Print("Email Address: ")
-	email, _ := app.reader.ReadString('\n')
-	email = strings.TrimSpace(email)
-
-	fmt.Print("Password: ")
-	password, _ := app.reader.ReadString('\n')
-	password = strings.TrimSpace(password)
+	email, ok := app.promptValid("Email Address: ", app.readLine, validateEmail)
+	if !ok {
+		return
+	}
+	password, ok := app.promptValid("Password: ", app.readPassword, requireValue)
+	if !ok {
+		return
+	}
 
 	// Authenticate user
-	user, err := app.userSvc.Login(email, password)
+	user, err := app.userSvc.Login(context.Background(), email, password)
 	if err != nil {
 		fmt.Printf("\n[ERROR] Login failed: %v\n", err)
 		fmt.Println("[INFO] Please check your credentials and try again.")
@@ -171,6 +259,7 @@ func (app *App) login() {
 	}
 
 	app.currentUser = user
+	app.lastActivity = time.Now()
 	fmt.Printf("\n[SUCCESS] Welcome back, %s %s!\n", user.FirstName, user.LastName)
 	fmt.Println("[INFO] Login successful. Redirecting to main menu...")
 }
@@ -180,23 +269,32 @@ func (app *App) register() {
 	fmt.Println("       CREATE NEW ACCOUNT")
 	fmt.Println(strings.Repeat("-", 35))
 
-	fmt.Print("First Name: ")
-	firstName, _ := app.reader.ReadString('\n')
-	firstName = strings.TrimSpace(firstName)
-
-	fmt.Print("Last Name: ")
-	lastName, _ := app.reader.ReadString('\n')
-	lastName = strings.TrimSpace(lastName)
-
-	fmt.Print("Email Address: ")
-	email, _ := app.reader.ReadString('\n')
-	email = strings.TrimSpace(email)
-
-	fmt.Print("Password: ")
-	password, _ := app.reader.ReadString('\n')
-	password = strings.TrimSpace(password)
+	firstName, ok := app.promptValid("First Name: ", app.readLine, validateName)
+	if !ok {
+		return
+	}
+	lastName, ok := app.promptValid("Last Name: ", app.readLine, validateName)
+	if !ok {
+		return
+	}
+	email, ok := app.promptValid("Email Address: ", app.readLine, validateEmail)
+	if !ok {
+		return
+	}
+	password, ok := app.promptValid(fmt.Sprintf("Password (at least %d characters): ", minPasswordLength), app.readPassword, validatePassword)
+	if !ok {
+		return
+	}
+	if _, ok := app.promptValid("Confirm Password: ", app.readPassword, func(confirmation string) error {
+		if confirmation != password {
+			return errors.New("passwords do not match")
+		}
+		return nil
+	}); !ok {
+		return
+	}
 
-	_, err := app.userSvc.Register(email, password, firstName, lastName)
+	_, err := app.userSvc.Register(context.Background(), email, password, firstName, lastName)
 	if err != nil {
 		fmt.Printf("\n[ERROR] Registration failed: %v\n", err)
 		fmt.Println("[INFO] Please try again with different credentials.")
@@ -208,29 +306,33 @@ func (app *App) register() {
 }
 
 func (app *App) showMainMenu() {
+	l := app.localizer()
+	name := app.currentUser.FirstName + " " + app.currentUser.LastName
 	fmt.Println("\n" + strings.Repeat("=", 60))
-	fmt.Printf("    MAIN MENU - Welcome %s %s\n", app.currentUser.FirstName, app.currentUser.LastName)
+	fmt.Println("    " + l.T("menu.main_title", map[string]any{"Name": name}))
 	fmt.Println(strings.Repeat("=", 60))
-	fmt.Println("  📊 TRANSACTIONS")
-	fmt.Println("    1. Add New Transaction")
-	fmt.Println("    2. View Transaction History")
+	fmt.Println("  📊 " + l.T("menu.transactions"))
+	fmt.Println("    1. " + l.T("menu.add_transaction"))
+	fmt.Println("    2. " + l.T("menu.transaction_history"))
+	fmt.Println("    3. " + l.T("menu.delete_transaction"))
+	fmt.Println("    4. " + l.T("menu.trash"))
 	fmt.Println("")
-	fmt.Println("  💰 BUDGET & PLANNING")
-	fmt.Println("    3. Budget Management")
-	fmt.Println("    4. Financial Reports")
+	fmt.Println("  💰 " + l.T("menu.budget_planning"))
+	fmt.Println("    5. " + l.T("menu.budgets"))
+	fmt.Println("    6. " + l.T("menu.reports"))
 	fmt.Println("")
-	fmt.Println("  📈 ANALYSIS & INSIGHTS")
-	fmt.Println("    5. Financial Analytics")
-	fmt.Println("    6. Investment Advice")
+	fmt.Println("  📈 " + l.T("menu.analysis"))
+	fmt.Println("    7. " + l.T("menu.analytics"))
+	fmt.Println("    8. " + l.T("menu.advice"))
 	fmt.Println("")
-	fmt.Println("  ⚙️  SETTINGS")
-	fmt.Println("    7. Manage Categories")
-	fmt.Println("    8. Logout")
+	fmt.Println("  ⚙️  " + l.T("menu.settings"))
+	fmt.Println("    9. " + l.T("menu.categories"))
+	fmt.Println("   10. " + l.T("menu.language"))
+	fmt.Println("   11. " + l.T("menu.logout"))
 	fmt.Println(strings.Repeat("-", 60))
-	fmt.Print("Please select an option (1-8): ")
+	fmt.Print(l.T("menu.select", map[string]any{"Max": 11}))
 
-	choice, _ := app.reader.ReadString('\n')
-	choice = strings.TrimSpace(choice)
+	choice := app.readLine()
 
 	switch choice {
 	case "1":
@@ -238,20 +340,25 @@ func (app *App) showMainMenu() {
 	case "2":
 		app.listTransactions()
 	case "3":
-		app.budgetMenu()
+		app.deleteTransaction()
 	case "4":
-		app.reportsMenu()
+		app.trashMenu()
 	case "5":
-		app.analyticsMenu()
+		app.budgetMenu()
 	case "6":
-		app.investmentAdvice()
+		app.reportsMenu()
 	case "7":
-		app.categoryMenu()
+		app.analyticsMenu()
 	case "8":
-		app.currentUser = nil
-		fmt.Println("\n[INFO] Successfully logged out. Returning to login menu...")
+		app.investmentAdvice()
+	case "9":
+		app.categoryMenu()
+	case "10":
+		app.changeLanguage()
+	case "11":
+		app.logout()
 	default:
-		fmt.Println("\n[ERROR] Invalid selection! Please choose a number between 1-8.")
+		printInvalidSelection(l, 11)
 	}
 }
 
@@ -261,7 +368,7 @@ func (app *App) addTransaction() {
 	fmt.Println(strings.Repeat("-", 40))
 
 	// List available categories
-	categories, err := app.categorySvc.GetAllCategories()
+	categories, err := app.categorySvc.GetAllCategories(context.Background(), app.currentUser.ID)
 	if err != nil {
 		fmt.Printf("[ERROR] Could not retrieve categories: %v\n", err)
 		return
@@ -275,28 +382,26 @@ func (app *App) addTransaction() {
 	fmt.Println(strings.Repeat("-", 30))
 
 	fmt.Print("Category ID: ")
-	categoryIDStr, _ := app.reader.ReadString('\n')
-	categoryID, err := strconv.Atoi(strings.TrimSpace(categoryIDStr))
+	categoryIDStr := app.readLine()
+	categoryID, err := strconv.Atoi(categoryIDStr)
 	if err != nil {
 		fmt.Println("[ERROR] Invalid category ID! Please enter a valid number.")
 		return
 	}
 
 	fmt.Print("Amount: $")
-	amountStr, _ := app.reader.ReadString('\n')
-	amount, err := strconv.ParseFloat(strings.TrimSpace(amountStr), 64)
+	amountStr := app.readLine()
+	amount, err := domain.ParseMoney(amountStr)
 	if err != nil {
-		fmt.Println("[ERROR] Invalid amount! Please enter a valid number.")
+		fmt.Println("[ERROR] Invalid amount! Please enter a number with at most two decimals.")
 		return
 	}
 
 	fmt.Print("Description: ")
-	description, _ := app.reader.ReadString('\n')
-	description = strings.TrimSpace(description)
+	description := app.readLine()
 
 	fmt.Print("Type (income/expense): ")
-	transactionType, _ := app.reader.ReadString('\n')
-	transactionType = strings.TrimSpace(transactionType)
+	transactionType := app.readLine()
 
 	if transactionType != "income" && transactionType != "expense" {
 		fmt.Println("[ERROR] Invalid transaction type! Please enter 'income' or 'expense'.")
@@ -312,14 +417,14 @@ func (app *App) addTransaction() {
 		Date:        time.Now(),
 	}
 
-	err = app.txSvc.Create(transaction)
+	err = app.txSvc.Create(context.Background(), transaction)
 	if err != nil {
 		fmt.Printf("[ERROR] Could not add transaction: %v\n", err)
 		return
 	}
 
 	fmt.Println("\n[SUCCESS] ✅ Transaction added successfully!")
-	fmt.Printf("[INFO] Added %s of $%.2f for %s\n", transactionType, amount, description)
+	fmt.Printf("[INFO] Added %s of %s for %s\n", transactionType, amount.Format("USD"), description)
 }
 
 func (app *App) listTransactions() {
@@ -327,7 +432,7 @@ func (app *App) listTransactions() {
 	fmt.Println("           TRANSACTION HISTORY")
 	fmt.Println(strings.Repeat("-", 50))
 
-	transactions, err := app.txSvc.List(app.currentUser.ID)
+	transactions, err := app.txSvc.List(context.Background(), app.currentUser.ID)
 	if err != nil {
 		fmt.Printf("[ERROR] Could not retrieve transactions: %v\n", err)
 		return
@@ -345,33 +450,140 @@ func (app *App) listTransactions() {
 
 	for _, tx := range transactions {
 		typeIcon := "💰"
-		if tx.Type == "expense" {
+		switch tx.Type {
+		case domain.TransactionTypeExpense:
 			typeIcon = "💸"
+		case domain.TransactionTypeTransfer:
+			typeIcon = "🔁"
 		}
-		fmt.Printf("%-4d %-12s %-10s %-20s %s$%.2f\n",
+		fmt.Printf("%-4d %-12s %-10s %-20s %s%s\n",
 			tx.ID,
 			tx.Date.Format("2006-01-02"),
 			tx.Type,
 			tx.Description,
 			typeIcon,
-			tx.Amount)
+			tx.Amount.Format("USD"))
 	}
 	fmt.Println(strings.Repeat("-", 60))
 }
 
-func (app *App) budgetMenu() {
+func (app *App) deleteTransaction() {
 	fmt.Println("\n" + strings.Repeat("-", 40))
-	fmt.Println("         BUDGET MANAGEMENT")
+	fmt.Println("         DELETE TRANSACTION")
 	fmt.Println(strings.Repeat("-", 40))
-	fmt.Println("  1. Create New Budget")
-	fmt.Println("  2. View Budget Overview")
-	fmt.Println("  3. Budget Summary")
+
+	id, ok := app.readTransactionID()
+	if !ok {
+		return
+	}
+
+	// Only the user's own transactions can be deleted
+	transaction, err := app.txSvc.GetByID(context.Background(), id)
+	if err != nil || transaction.UserID != app.currentUser.ID {
+		fmt.Println("[ERROR] Transaction not found.")
+		return
+	}
+
+	if err := app.txSvc.Delete(context.Background(), transaction.ID); err != nil {
+		fmt.Printf("[ERROR] Could not delete transaction: %v\n", err)
+		return
+	}
+
+	fmt.Printf("\n[SUCCESS] Transaction %d moved to the trash.\n", transaction.ID)
+	fmt.Println("[INFO] You can restore it from the Trash menu until it is purged.")
+}
+
+func (app *App) readTransactionID() (uint, bool) {
+	fmt.Print("Transaction ID: ")
+	idStr := app.readLine()
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		fmt.Println("[ERROR] Invalid transaction ID! Please enter a valid number.")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func (app *App) trashMenu() {
+	fmt.Println("\n" + strings.Repeat("-", 50))
+	fmt.Println("                 TRASH")
+	fmt.Println(strings.Repeat("-", 50))
+
+	transactions, err := app.txSvc.ListDeleted(context.Background(), app.currentUser.ID)
+	if err != nil {
+		fmt.Printf("[ERROR] Could not retrieve deleted transactions: %v\n", err)
+		return
+	}
+	if len(transactions) == 0 {
+		fmt.Println("\n🗑️  The trash is empty.")
+		return
+	}
+
+	fmt.Printf("\n🗑️  %d deleted transaction(s):\n\n", len(transactions))
+	fmt.Printf("%-4s %-12s %-10s %-20s %-12s %s\n", "ID", "Date", "Type", "Description", "Amount", "Deleted")
+	fmt.Println(strings.Repeat("-", 75))
+	for _, tx := range transactions {
+		fmt.Printf("%-4d %-12s %-10s %-20s %-12s %s\n",
+			tx.ID,
+			tx.Date.Format("2006-01-02"),
+			tx.Type,
+			tx.Description,
+			tx.Amount.Format("USD"),
+			tx.DeletedAt.Time.Format("2006-01-02 15:04"))
+	}
+	fmt.Println(strings.Repeat("-", 75))
+
+	fmt.Println("  1. Restore Transaction")
+	fmt.Println("  2. Delete Permanently")
+	fmt.Println("  3. Empty Trash")
 	fmt.Println("  4. Return to Main Menu")
-	fmt.Println(strings.Repeat("-", 40))
 	fmt.Print("Please select an option (1-4): ")
 
-	choice, _ := app.reader.ReadString('\n')
-	choice = strings.TrimSpace(choice)
+	choice := app.readLine()
+
+	switch choice {
+	case "1":
+		id, ok := app.readTransactionID()
+		if !ok {
+			return
+		}
+		if _, err := app.txSvc.Restore(context.Background(), app.currentUser.ID, id); err != nil {
+			fmt.Printf("[ERROR] Could not restore transaction: %v\n", err)
+			return
+		}
+		fmt.Printf("\n[SUCCESS] Transaction %d restored.\n", id)
+	case "2":
+		id, ok := app.readTransactionID()
+		if !ok {
+			return
+		}
+		if err := app.txSvc.Purge(context.Background(), app.currentUser.ID, id); err != nil {
+			fmt.Printf("[ERROR] Could not delete transaction: %v\n", err)
+			return
+		}
+		fmt.Printf("\n[SUCCESS] Transaction %d permanently deleted.\n", id)
+	case "3":
+		purged, err := app.txSvc.EmptyTrash(context.Background(), app.currentUser.ID)
+		if err != nil {
+			fmt.Printf("[ERROR] Could not empty the trash: %v\n", err)
+			return
+		}
+		fmt.Printf("\n[SUCCESS] %d transaction(s) permanently deleted.\n", purged)
+	case "4":
+		return
+	default:
+		fmt.Println("[ERROR] Invalid selection! Please choose 1, 2, 3, or 4.")
+	}
+}
+
+func (app *App) budgetMenu() {
+	l := app.localizer()
+	fmt.Println("\n" + strings.Repeat("-", 40))
+	fmt.Println("         " + l.T("menu.budgets_title"))
+	fmt.Println(strings.Repeat("-", 40))
+	printMenuOptions(l, 40, "menu.create_budget", "menu.budget_overview", "menu.budget_summary", "menu.return")
+
+	choice := app.readLine()
 
 	switch choice {
 	case "1":
@@ -383,7 +595,7 @@ func (app *App) budgetMenu() {
 	case "4":
 		return
 	default:
-		fmt.Println("[ERROR] Invalid selection! Please choose 1, 2, 3, or 4.")
+		printInvalidSelection(l, 4)
 	}
 }
 
@@ -393,7 +605,7 @@ func (app *App) createBudget() {
 	fmt.Println(strings.Repeat("-", 35))
 
 	// List available categories
-	categories, err := app.categorySvc.GetAllCategories()
+	categories, err := app.categorySvc.GetAllCategories(context.Background(), app.currentUser.ID)
 	if err != nil {
 		fmt.Printf("[ERROR] Could not retrieve categories: %v\n", err)
 		return
@@ -407,24 +619,23 @@ func (app *App) createBudget() {
 	fmt.Println(strings.Repeat("-", 25))
 
 	fmt.Print("Category ID: ")
-	categoryIDStr, _ := app.reader.ReadString('\n')
-	categoryID, err := strconv.Atoi(strings.TrimSpace(categoryIDStr))
+	categoryIDStr := app.readLine()
+	categoryID, err := strconv.Atoi(categoryIDStr)
 	if err != nil {
 		fmt.Println("[ERROR] Invalid category ID! Please enter a valid number.")
 		return
 	}
 
 	fmt.Print("Budget Amount: $")
-	amountStr, _ := app.reader.ReadString('\n')
-	amount, err := strconv.ParseFloat(strings.TrimSpace(amountStr), 64)
+	amountStr := app.readLine()
+	amount, err := domain.ParseMoney(amountStr)
 	if err != nil {
-		fmt.Println("[ERROR] Invalid amount! Please enter a valid number.")
+		fmt.Println("[ERROR] Invalid amount! Please enter a number with at most two decimals.")
 		return
 	}
 
 	fmt.Print("Period (monthly/weekly/yearly): ")
-	periodStr, _ := app.reader.ReadString('\n')
-	period := strings.TrimSpace(periodStr)
+	period := app.readLine()
 
 	if period != "monthly" && period != "weekly" && period != "yearly" {
 		fmt.Println("[ERROR] Invalid period! Please enter 'monthly', 'weekly', or 'yearly'.")
@@ -440,14 +651,14 @@ func (app *App) createBudget() {
 		EndDate:    time.Now().AddDate(0, 1, 0), // Default to 1 month
 	}
 
-	err = app.budgetSvc.CreateBudget(budget)
+	err = app.budgetSvc.CreateBudget(context.Background(), budget)
 	if err != nil {
 		fmt.Printf("[ERROR] Could not create budget: %v\n", err)
 		return
 	}
 
 	fmt.Println("\n[SUCCESS] ✅ Budget created successfully!")
-	fmt.Printf("[INFO] Created %s budget of $%.2f for category ID %d\n", period, amount, categoryID)
+	fmt.Printf("[INFO] Created %s budget of %s for category ID %d\n", period, amount.Format("USD"), categoryID)
 }
 
 func (app *App) listBudgets() {
@@ -455,7 +666,7 @@ func (app *App) listBudgets() {
 	fmt.Println("              BUDGET OVERVIEW")
 	fmt.Println(strings.Repeat("-", 50))
 
-	budgets, err := app.budgetSvc.GetBudgetsByUser(app.currentUser.ID)
+	budgets, err := app.budgetSvc.GetBudgetsByUser(context.Background(), app.currentUser.ID)
 	if err != nil {
 		fmt.Printf("[ERROR] Could not retrieve budgets: %v\n", err)
 		return
@@ -476,17 +687,17 @@ func (app *App) listBudgets() {
 		status := "✅"
 		if budget.Spent > budget.Amount {
 			status = "❌"
-		} else if budget.Spent > budget.Amount*0.8 {
+		} else if budget.Spent > budget.Amount.Mul(0.8) {
 			status = "⚠️"
 		}
 
-		fmt.Printf("%-4d %-15s %-10s $%-9.2f $%-8.2f $%-10.2f %s\n",
+		fmt.Printf("%-4d %-15s %-10s %-10s %-9s %-11s %s\n",
 			budget.ID,
 			budget.Category.Name,
 			budget.Period,
-			budget.Amount,
-			budget.Spent,
-			remaining,
+			budget.Amount.Format("USD"),
+			budget.Spent.Format("USD"),
+			remaining.Format("USD"),
 			status)
 	}
 	fmt.Println(strings.Repeat("-", 70))
@@ -501,19 +712,14 @@ func (app *App) budgetSummary() {
 }
 
 func (app *App) reportsMenu() {
+	l := app.localizer()
 	fmt.Println("\n" + strings.Repeat("-", 40))
-	fmt.Println("           FINANCIAL REPORTS")
-	fmt.Println(strings.Repeat("-", 40))
-	fmt.Println("  1. Monthly Report")
-	fmt.Println("  2. Yearly Report")
-	fmt.Println("  3. Category Analysis")
-	fmt.Println("  4. Export Reports")
-	fmt.Println("  5. Return to Main Menu")
+	fmt.Println("           " + l.T("menu.reports_title"))
 	fmt.Println(strings.Repeat("-", 40))
-	fmt.Print("Please select an option (1-5): ")
+	printMenuOptions(l, 40, "menu.monthly_report", "menu.yearly_report", "menu.category_analysis",
+		"menu.compare_periods", "menu.export_reports", "menu.import_transactions", "menu.return")
 
-	choice, _ := app.reader.ReadString('\n')
-	choice = strings.TrimSpace(choice)
+	choice := app.readLine()
 
 	switch choice {
 	case "1":
@@ -521,13 +727,17 @@ func (app *App) reportsMenu() {
 	case "2":
 		app.yearlyReport()
 	case "3":
-		fmt.Println("\n[INFO] Category Analysis feature is under development...")
+		app.categoryAnalysis()
 	case "4":
-		fmt.Println("\n[INFO] Export Reports feature is under development...")
+		app.compareReports()
 	case "5":
+		app.exportMenu()
+	case "6":
+		app.importTransactions()
+	case "7":
 		return
 	default:
-		fmt.Println("[ERROR] Invalid selection! Please choose 1, 2, 3, 4, or 5.")
+		printInvalidSelection(l, 7)
 	}
 }
 
@@ -535,31 +745,210 @@ func (app *App) monthlyReport() {
 	fmt.Println("\n" + strings.Repeat("-", 35))
 	fmt.Println("        MONTHLY REPORT")
 	fmt.Println(strings.Repeat("-", 35))
-	fmt.Println("\n[INFO] Monthly Report feature is under development...")
-	fmt.Println("[INFO] Coming soon: Detailed monthly financial analysis!")
+
+	now := time.Now()
+	year, err := app.promptInt(fmt.Sprintf("Year [%d]: ", now.Year()), now.Year())
+	if err != nil {
+		fmt.Println("[ERROR] Invalid year! Please enter a valid number.")
+		return
+	}
+
+	month, err := app.promptInt(fmt.Sprintf("Month (1-12) [%d]: ", int(now.Month())), int(now.Month()))
+	if err != nil || month < 1 || month > 12 {
+		fmt.Println("[ERROR] Invalid month! Please enter a number between 1 and 12.")
+		return
+	}
+
+	report, err := app.reportsSvc.GenerateMonthlyReport(context.Background(), app.currentUser.ID, year, month)
+	if err != nil {
+		fmt.Printf("[ERROR] Could not generate monthly report: %v\n", err)
+		return
+	}
+
+	app.printFinancialReport(report)
 }
 
 func (app *App) yearlyReport() {
 	fmt.Println("\n" + strings.Repeat("-", 35))
 	fmt.Println("         YEARLY REPORT")
 	fmt.Println(strings.Repeat("-", 35))
-	fmt.Println("\n[INFO] Yearly Report feature is under development...")
-	fmt.Println("[INFO] Coming soon: Comprehensive yearly financial overview!")
+
+	now := time.Now()
+	year, err := app.promptInt(fmt.Sprintf("Year [%d]: ", now.Year()), now.Year())
+	if err != nil {
+		fmt.Println("[ERROR] Invalid year! Please enter a valid number.")
+		return
+	}
+
+	report, err := app.reportsSvc.GenerateYearlyReport(context.Background(), app.currentUser.ID, year)
+	if err != nil {
+		fmt.Printf("[ERROR] Could not generate yearly report: %v\n", err)
+		return
+	}
+
+	app.printFinancialReport(report)
+}
+
+func (app *App) compareReports() {
+	fmt.Println("\n" + strings.Repeat("-", 35))
+	fmt.Println("        COMPARE PERIODS")
+	fmt.Println(strings.Repeat("-", 35))
+	fmt.Println("Periods: YYYY-MM, YYYY-Qn, YYYY or YYYY-MM-DD..YYYY-MM-DD")
+
+	now := time.Now()
+	defaultTarget := now.Format("2006-01")
+	defaultBase := now.AddDate(0, -1, 0).Format("2006-01")
+	basePeriod := app.promptString(fmt.Sprintf("Base period [%s]: ", defaultBase), defaultBase)
+	targetPeriod := app.promptString(fmt.Sprintf("Target period [%s]: ", defaultTarget), defaultTarget)
+
+	comparison, err := app.reportsSvc.CompareReports(context.Background(), app.currentUser.ID, basePeriod, targetPeriod)
+	if err != nil {
+		fmt.Printf("[ERROR] Could not compare periods: %v\n", err)
+		return
+	}
+
+	printReportComparison(comparison)
+}
+
+// printReportComparison renders a period over period comparison as a console table
+func printReportComparison(comparison *domain.ReportComparison) {
+	fmt.Println("\n" + strings.Repeat("=", 60))
+	fmt.Printf("  %s vs %s\n", comparison.Target.Period, comparison.Base.Period)
+	fmt.Println(strings.Repeat("=", 60))
+
+	fmt.Printf("%-16s %-12s %-12s %-12s %-8s\n", "", "Base", "Target", "Change", "Change%")
+	fmt.Println(strings.Repeat("-", 64))
+	for _, row := range []struct {
+		label  string
+		change domain.ValueChange
+	}{
+		{"Income", comparison.Income},
+		{"Expenses", comparison.Expenses},
+		{"Net Income", comparison.NetIncome},
+	} {
+		fmt.Printf("%-16s $%-11.2f $%-11.2f $%-11.2f %.1f%%\n",
+			row.label, row.change.Base, row.change.Target, row.change.Change, row.change.ChangePct)
+	}
+	fmt.Println(strings.Repeat("-", 64))
+	fmt.Printf("Savings Rate:     %.1f%% -> %.1f%% (%+.1f points)\n",
+		comparison.Base.SavingsRate, comparison.Target.SavingsRate, comparison.SavingsRateChange)
+	fmt.Printf("Transactions:     %+d\n", comparison.TransactionChange)
+
+	if len(comparison.Categories) > 0 {
+		fmt.Println("\n📂 CATEGORY CHANGES")
+		fmt.Printf("%-20s %-12s %-12s %-12s\n", "Category", "Base", "Target", "Change")
+		fmt.Println(strings.Repeat("-", 58))
+		for _, category := range comparison.Categories {
+			name := category.CategoryName
+			if name == "" {
+				name = "Uncategorized"
+			}
+			fmt.Printf("%-20s $%-11.2f $%-11.2f $%+.2f\n", name, category.Base, category.Target, category.Change)
+		}
+		fmt.Println(strings.Repeat("-", 58))
+	}
+}
+
+// printFinancialReport renders a report generated by ReportsService as console
+// tables in the user's language
+func (app *App) printFinancialReport(report *domain.FinancialReport) {
+	l := app.localizer()
+	money := func(amount float64) string { return l.Money(domain.NewMoney(amount), "USD") }
+	label := func(id string) string { return l.T(id) + ":" }
+
+	fmt.Println("\n" + strings.Repeat("=", 60))
+	fmt.Printf("  %s\n", l.ReportTitle(report))
+	fmt.Println(strings.Repeat("=", 60))
+
+	if report.TransactionCount == 0 {
+		fmt.Println("\n📊 " + l.T("report.no_transactions"))
+		fmt.Println("[INFO] " + l.T("report.add_transactions"))
+		return
+	}
+
+	fmt.Println("\n💰 " + l.T("report.summary"))
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Printf("%-18s%s\n", label("report.total_income"), money(report.TotalIncome))
+	fmt.Printf("%-18s%s\n", label("report.total_expenses"), money(report.TotalExpenses))
+	fmt.Printf("%-18s%s\n", label("report.net_income"), money(report.NetIncome))
+	fmt.Printf("%-18s%s%%\n", label("report.savings_rate"), l.Number(report.SavingsRate, 1))
+	fmt.Printf("%-18s%d\n", label("report.transactions"), report.TransactionCount)
+
+	app.printCategoryTable(report.CategoryBreakdown)
+
+	if len(report.MonthlyTrends) > 1 {
+		fmt.Println("\n📈 " + l.T("report.monthly_trends"))
+		fmt.Printf("%-10s %-12s %-12s %-12s %-8s\n",
+			l.T("report.month"), l.T("report.income"), l.T("report.expenses"), l.T("report.net"), l.T("report.savings"))
+		fmt.Println(strings.Repeat("-", 58))
+		for _, trend := range report.MonthlyTrends {
+			fmt.Printf("%-10s %-12s %-12s %-12s %s%%\n",
+				trend.Month, money(trend.Income), money(trend.Expenses), money(trend.NetIncome), l.Number(trend.SavingsRate, 1))
+		}
+		fmt.Println(strings.Repeat("-", 58))
+	}
+
+	if report.BudgetPerformance.TotalBudgeted > 0 {
+		fmt.Println("\n🎯 " + l.T("report.budget_performance"))
+		fmt.Println(strings.Repeat("-", 30))
+		fmt.Printf("%-18s%s\n", label("report.total_budgeted"), money(report.BudgetPerformance.TotalBudgeted))
+		fmt.Printf("%-18s%s\n", label("report.total_spent"), money(report.BudgetPerformance.TotalSpent))
+		fmt.Printf("%-18s%s\n", label("report.over_budget"),
+			l.T("report.category_count", map[string]any{"Count": report.BudgetPerformance.CategoriesOverBudget}))
+		fmt.Printf("%-18s%s\n", label("report.under_budget"),
+			l.T("report.category_count", map[string]any{"Count": report.BudgetPerformance.CategoriesUnderBudget}))
+	}
+
+	if len(report.Insights) > 0 {
+		fmt.Println("\n💡 " + l.T("report.insights"))
+		for _, insight := range report.Insights {
+			fmt.Printf("  • %s\n", insight)
+		}
+	}
+
+	if len(report.Recommendations) > 0 {
+		fmt.Println("\n📝 " + l.T("report.recommendations"))
+		for _, recommendation := range report.Recommendations {
+			fmt.Printf("  • %s\n", recommendation)
+		}
+	}
+}
+
+func (app *App) printCategoryTable(categories []domain.CategoryMetrics) {
+	if len(categories) == 0 {
+		return
+	}
+
+	l := app.localizer()
+	money := func(amount float64) string { return l.Money(domain.NewMoney(amount), "USD") }
+	fmt.Println("\n📂 " + l.T("report.category_breakdown"))
+	fmt.Printf("%-4s %-20s %-12s %-6s %-10s %-8s\n", "ID",
+		l.T("report.category"), l.T("report.amount"), l.T("report.count"), l.T("report.average"), l.T("report.share"))
+	fmt.Println(strings.Repeat("-", 65))
+	for _, category := range categories {
+		name := l.Category(category.CategoryName)
+		if name == "" {
+			name = l.T("report.uncategorized")
+		}
+		fmt.Printf("%-4d %-20s %-12s %-6d %-10s %s%%\n",
+			category.CategoryID,
+			name,
+			money(category.TotalAmount),
+			category.TransactionCount,
+			money(category.AverageAmount),
+			l.Number(category.PercentageOfTotal, 1))
+	}
+	fmt.Println(strings.Repeat("-", 65))
 }
 
 func (app *App) analyticsMenu() {
+	l := app.localizer()
 	fmt.Println("\n" + strings.Repeat("-", 45))
-	fmt.Println("           FINANCIAL ANALYTICS")
+	fmt.Println("           " + l.T("menu.analytics_title"))
 	fmt.Println(strings.Repeat("-", 45))
-	fmt.Println("  1. Income-Expense Analysis")
-	fmt.Println("  2. Category Analysis")
-	fmt.Println("  3. Dashboard Summary")
-	fmt.Println("  4. Return to Main Menu")
-	fmt.Println(strings.Repeat("-", 45))
-	fmt.Print("Please select an option (1-4): ")
+	printMenuOptions(l, 45, "menu.income_expense_analysis", "menu.category_analysis", "menu.dashboard_summary", "menu.return")
 
-	choice, _ := app.reader.ReadString('\n')
-	choice = strings.TrimSpace(choice)
+	choice := app.readLine()
 
 	switch choice {
 	case "1":
@@ -571,7 +960,7 @@ func (app *App) analyticsMenu() {
 	case "4":
 		return
 	default:
-		fmt.Println("[ERROR] Invalid selection! Please choose 1, 2, 3, or 4.")
+		printInvalidSelection(l, 4)
 	}
 }
 
@@ -581,7 +970,7 @@ func (app *App) incomeExpenseAnalysis() {
 	fmt.Println(strings.Repeat("-", 45))
 
 	// Get basic analytics
-	transactions, err := app.txSvc.List(app.currentUser.ID)
+	transactions, err := app.txSvc.List(context.Background(), app.currentUser.ID)
 	if err != nil {
 		fmt.Printf("[ERROR] Could not retrieve transactions: %v\n", err)
 		return
@@ -594,7 +983,7 @@ func (app *App) incomeExpenseAnalysis() {
 	}
 
 	// Calculate basic metrics
-	var totalIncome, totalExpenses float64
+	var totalIncome, totalExpenses domain.Money
 	incomeCount, expenseCount := 0, 0
 
 	for _, tx := range transactions {
@@ -610,14 +999,14 @@ func (app *App) incomeExpenseAnalysis() {
 	netWorth := totalIncome - totalExpenses
 	savingsRate := 0.0
 	if totalIncome > 0 {
-		savingsRate = ((totalIncome - totalExpenses) / totalIncome) * 100
+		savingsRate = netWorth.PercentOf(totalIncome)
 	}
 
 	fmt.Println("\n💰 FINANCIAL OVERVIEW")
 	fmt.Println(strings.Repeat("-", 30))
-	fmt.Printf("Total Income:     $%.2f (%d transactions)\n", totalIncome, incomeCount)
-	fmt.Printf("Total Expenses:   $%.2f (%d transactions)\n", totalExpenses, expenseCount)
-	fmt.Printf("Net Worth:        $%.2f\n", netWorth)
+	fmt.Printf("Total Income:     %s (%d transactions)\n", totalIncome.Format("USD"), incomeCount)
+	fmt.Printf("Total Expenses:   %s (%d transactions)\n", totalExpenses.Format("USD"), expenseCount)
+	fmt.Printf("Net Worth:        %s\n", netWorth.Format("USD"))
 	fmt.Printf("Savings Rate:     %.1f%%\n", savingsRate)
 
 	if netWorth > 0 {
@@ -633,31 +1022,168 @@ func (app *App) categoryAnalysis() {
 	fmt.Println("\n" + strings.Repeat("-", 40))
 	fmt.Println("         CATEGORY ANALYSIS")
 	fmt.Println(strings.Repeat("-", 40))
-	fmt.Println("\n[INFO] Category Analysis feature is under development...")
-	fmt.Println("[INFO] Coming soon: Detailed spending breakdown by category!")
+
+	now := time.Now()
+	defaultStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	startDate, err := app.promptDate(fmt.Sprintf("Start date (YYYY-MM-DD) [%s]: ", defaultStart.Format("2006-01-02")), defaultStart)
+	if err != nil {
+		fmt.Println("[ERROR] Invalid start date format. Use YYYY-MM-DD")
+		return
+	}
+
+	endDate, err := app.promptDate(fmt.Sprintf("End date (YYYY-MM-DD) [%s]: ", now.Format("2006-01-02")), now)
+	if err != nil {
+		fmt.Println("[ERROR] Invalid end date format. Use YYYY-MM-DD")
+		return
+	}
+	// Include the whole end day
+	endDate = time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 23, 59, 59, 0, endDate.Location())
+
+	metrics, err := app.analyticsSvc.GetFinancialMetrics(context.Background(), app.currentUser.ID, "custom", startDate, endDate)
+	if err != nil {
+		fmt.Printf("[ERROR] Could not analyze categories: %v\n", err)
+		return
+	}
+
+	if len(metrics.CategoryBreakdown) == 0 {
+		fmt.Println("\n📊 No transactions found for this period.")
+		return
+	}
+
+	fmt.Printf("\n📅 %s - %s\n", startDate.Format("Jan 2, 2006"), endDate.Format("Jan 2, 2006"))
+	app.printCategoryTable(metrics.CategoryBreakdown)
+
+	fmt.Print("\nEnter a category ID for details (or press Enter to skip): ")
+	input := app.readLine()
+	if input == "" {
+		return
+	}
+
+	categoryID, err := strconv.ParseUint(input, 10, 32)
+	if err != nil {
+		fmt.Println("[ERROR] Invalid category ID! Please enter a valid number.")
+		return
+	}
+
+	category, err := app.analyticsSvc.GetCategoryAnalysis(context.Background(), app.currentUser.ID, uint(categoryID), startDate, endDate)
+	if err != nil {
+		fmt.Printf("[ERROR] Could not analyze category: %v\n", err)
+		return
+	}
+
+	if category.TransactionCount == 0 {
+		fmt.Println("\n[INFO] No transactions in this category for the selected period.")
+		return
+	}
+
+	fmt.Printf("\n📂 %s\n", category.CategoryName)
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Printf("Total Spent:      $%.2f\n", category.TotalAmount)
+	fmt.Printf("Transactions:     %d\n", category.TransactionCount)
+	fmt.Printf("Average Amount:   $%.2f\n", category.AverageAmount)
+	fmt.Printf("Trend:            %s\n", category.Trend)
 }
 
 func (app *App) dashboardSummary() {
 	fmt.Println("\n" + strings.Repeat("-", 40))
 	fmt.Println("         DASHBOARD SUMMARY")
 	fmt.Println(strings.Repeat("-", 40))
-	fmt.Println("\n[INFO] Dashboard Summary feature is under development...")
-	fmt.Println("[INFO] Coming soon: Complete financial dashboard overview!")
+
+	fmt.Print("Period (week/month/quarter/year) [month]: ")
+	period := app.readLine()
+	if period == "" {
+		period = "month"
+	}
+	if period != "week" && period != "month" && period != "quarter" && period != "year" {
+		fmt.Println("[ERROR] Invalid period! Please enter 'week', 'month', 'quarter', or 'year'.")
+		return
+	}
+
+	dashboard, err := app.analyticsSvc.GetDashboardSummary(context.Background(), app.currentUser.ID, period)
+	if err != nil {
+		fmt.Printf("[ERROR] Could not load dashboard: %v\n", err)
+		return
+	}
+
+	fmt.Printf("\n📅 %s - %s\n", dashboard.StartDate.Format("Jan 2, 2006"), dashboard.EndDate.Format("Jan 2, 2006"))
+	fmt.Println("\n💰 OVERVIEW")
+	fmt.Println(strings.Repeat("-", 30))
+	fmt.Printf("Income:           $%.2f\n", dashboard.MonthlyIncome)
+	fmt.Printf("Expenses:         $%.2f\n", dashboard.MonthlyExpenses)
+	fmt.Printf("Savings:          $%.2f\n", dashboard.MonthlySavings)
+	fmt.Printf("Savings Rate:     %.1f%%\n", dashboard.SavingsRate)
+	fmt.Printf("Cash Flow Trend:  %s\n", dashboard.QuickStats.CashFlowTrend)
+
+	app.printCategoryTable(dashboard.TopExpenseCategories)
+
+	if len(dashboard.BudgetAlerts) > 0 {
+		fmt.Println("\n⚠️  BUDGET ALERTS")
+		for _, alert := range dashboard.BudgetAlerts {
+			fmt.Printf("  • %s: $%.2f of $%.2f used (%.0f%%, %s)\n",
+				alert.CategoryName, alert.SpentAmount, alert.BudgetAmount, alert.PercentageUsed, alert.AlertLevel)
+		}
+	}
+
+	if len(dashboard.RecentTransactions) > 0 {
+		fmt.Println("\n🧾 RECENT TRANSACTIONS")
+		fmt.Printf("%-12s %-8s %-25s %-10s\n", "Date", "Type", "Description", "Amount")
+		fmt.Println(strings.Repeat("-", 60))
+		for i, tx := range dashboard.RecentTransactions {
+			if i == 5 {
+				break
+			}
+			fmt.Printf("%-12s %-8s %-25s %s\n", tx.Date.Format("2006-01-02"), tx.Type, tx.Description, tx.Amount.Format("USD"))
+		}
+		fmt.Println(strings.Repeat("-", 60))
+	}
+
+	if len(dashboard.FinancialGoals) > 0 {
+		fmt.Println("\n🎯 FINANCIAL GOALS")
+		for _, goal := range dashboard.FinancialGoals {
+			fmt.Printf("  • %s: %s / %s (%.0f%%)\n", goal.Title, goal.CurrentAmount.Format("USD"), goal.TargetAmount.Format("USD"), goal.Progress)
+		}
+	}
+}
+
+// promptString reads a line, returning def when the input is left empty
+func (app *App) promptString(prompt, def string) string {
+	fmt.Print(prompt)
+	input := app.readLine()
+	if input == "" {
+		return def
+	}
+	return input
+}
+
+// promptInt reads an integer, returning def when the input is left empty
+func (app *App) promptInt(prompt string, def int) (int, error) {
+	fmt.Print(prompt)
+	input := app.readLine()
+	if input == "" {
+		return def, nil
+	}
+	return strconv.Atoi(input)
+}
+
+// promptDate reads a YYYY-MM-DD date, returning def when the input is left empty
+func (app *App) promptDate(prompt string, def time.Time) (time.Time, error) {
+	fmt.Print(prompt)
+	input := app.readLine()
+	if input == "" {
+		return def, nil
+	}
+	return time.ParseInLocation("2006-01-02", input, def.Location())
 }
 
 func (app *App) investmentAdvice() {
+	l := app.localizer()
 	fmt.Println("\n" + strings.Repeat("-", 45))
-	fmt.Println("          INVESTMENT ADVISOR")
-	fmt.Println(strings.Repeat("-", 45))
-	fmt.Println("  1. Portfolio Recommendations")
-	fmt.Println("  2. Risk Assessment")
-	fmt.Println("  3. Market Analysis")
-	fmt.Println("  4. Investment Calculator")
+	fmt.Println("          " + l.T("menu.advice_title"))
 	fmt.Println(strings.Repeat("-", 45))
-	fmt.Print("Please select an option (1-4): ")
+	printMenuOptions(l, 45, "menu.portfolio", "menu.risk_assessment", "menu.market_analysis", "menu.investment_calculator")
 
-	choice, _ := app.reader.ReadString('\n')
-	choice = strings.TrimSpace(choice)
+	choice := app.readLine()
 
 	switch choice {
 	case "1":
@@ -665,26 +1191,22 @@ func (app *App) investmentAdvice() {
 	case "2":
 		app.riskAssessment()
 	case "3":
-		fmt.Println("\n[INFO] Market Analysis feature is under development...")
+		fmt.Println("\n[INFO] " + l.T("menu.under_development", map[string]any{"Feature": l.T("menu.market_analysis")}))
 	case "4":
-		fmt.Println("\n[INFO] Investment Calculator feature is under development...")
+		fmt.Println("\n[INFO] " + l.T("menu.under_development", map[string]any{"Feature": l.T("menu.investment_calculator")}))
 	default:
-		fmt.Println("[ERROR] Invalid selection! Please choose 1, 2, 3, or 4.")
+		printInvalidSelection(l, 4)
 	}
 }
 
 func (app *App) categoryMenu() {
+	l := app.localizer()
 	fmt.Println("\n" + strings.Repeat("-", 40))
-	fmt.Println("         CATEGORY MANAGEMENT")
+	fmt.Println("         " + l.T("menu.categories_title"))
 	fmt.Println(strings.Repeat("-", 40))
-	fmt.Println("  1. List Categories")
-	fmt.Println("  2. Add Category")
-	fmt.Println("  3. Return to Main Menu")
-	fmt.Println(strings.Repeat("-", 40))
-	fmt.Print("Please select an option (1-3): ")
+	printMenuOptions(l, 40, "menu.list_categories", "menu.add_category", "menu.return")
 
-	choice, _ := app.reader.ReadString('\n')
-	choice = strings.TrimSpace(choice)
+	choice := app.readLine()
 
 	switch choice {
 	case "1":
@@ -694,7 +1216,7 @@ func (app *App) categoryMenu() {
 	case "3":
 		return
 	default:
-		fmt.Println("[ERROR] Invalid selection! Please choose 1, 2, or 3.")
+		printInvalidSelection(l, 3)
 	}
 }
 
@@ -703,12 +1225,13 @@ func (app *App) listCategories() {
 	fmt.Println("         CATEGORY LIST")
 	fmt.Println(strings.Repeat("-", 40))
 
-	categories, err := app.categorySvc.GetAllCategories()
+	categories, err := app.categorySvc.GetAllCategories(context.Background(), app.currentUser.ID)
 	if err != nil {
 		fmt.Printf("[ERROR] Could not retrieve categories: %v\n", err)
 		return
 	}
 
+	l := app.localizer()
 	if len(categories) == 0 {
 		fmt.Println("\n📂 No categories found.")
 		fmt.Println("[INFO] Add your first category to get started!")
@@ -724,7 +1247,7 @@ func (app *App) listCategories() {
 		if cat.Type == "expense" {
 			typeIcon = "💸"
 		}
-		fmt.Printf("%-4d %-20s %s %-13s\n", cat.ID, cat.Name, typeIcon, cat.Type)
+		fmt.Printf("%-4d %-20s %s %-13s\n", cat.ID, l.Category(cat.Name), typeIcon, cat.Type)
 	}
 	fmt.Println(strings.Repeat("-", 40))
 }
@@ -735,12 +1258,10 @@ func (app *App) addCategory() {
 	fmt.Println(strings.Repeat("-", 35))
 
 	fmt.Print("Category Name: ")
-	name, _ := app.reader.ReadString('\n')
-	name = strings.TrimSpace(name)
+	name := app.readLine()
 
 	fmt.Print("Type (income/expense): ")
-	catType, _ := app.reader.ReadString('\n')
-	catType = strings.TrimSpace(catType)
+	catType := app.readLine()
 	if catType != "income" && catType != "expense" {
 		fmt.Println("[ERROR] Invalid type! Please enter 'income' or 'expense'.")
 		return
@@ -751,7 +1272,7 @@ func (app *App) addCategory() {
 		Type: catType,
 	}
 
-	err := app.categorySvc.CreateCategory(category)
+	err := app.categorySvc.CreateCategory(context.Background(), app.currentUser.ID, category)
 	if err != nil {
 		fmt.Printf("[ERROR] Could not add category: %v\n", err)
 		return
@@ -761,6 +1282,36 @@ func (app *App) addCategory() {
 	fmt.Printf("[INFO] Added %s category: %s\n", catType, name)
 }
 
+// changeLanguage sets the language of the menus, reports and exports of the
+// logged-in user
+func (app *App) changeLanguage() {
+	l := app.localizer()
+	fmt.Println("\n" + strings.Repeat("-", 35))
+	fmt.Println("            " + l.T("menu.language_title"))
+	fmt.Println(strings.Repeat("-", 35))
+	fmt.Println(l.T("menu.language_current", map[string]any{"Language": l.T("languages." + l.Locale)}))
+	options := make([]string, len(domain.SupportedLocales))
+	for i, locale := range domain.SupportedLocales {
+		options[i] = "languages." + locale
+	}
+	printMenuOptions(l, 35, options...)
+
+	choice, err := strconv.Atoi(app.readLine())
+	if err != nil || choice < 1 || choice > len(options) {
+		printInvalidSelection(l, len(options))
+		return
+	}
+
+	user, err := app.userSvc.UpdateLocale(context.Background(), app.currentUser.ID, domain.SupportedLocales[choice-1])
+	if err != nil {
+		fmt.Printf("[ERROR] Could not change the language: %v\n", err)
+		return
+	}
+	app.currentUser = &user
+	l = app.localizer()
+	fmt.Println("\n[SUCCESS] " + l.T("menu.language_changed", map[string]any{"Language": l.T("languages." + user.Locale)}))
+}
+
 func (app *App) portfolioRecommendations() {
 	fmt.Println("\n" + strings.Repeat("-", 45))
 	fmt.Println("       PORTFOLIO RECOMMENDATIONS")
@@ -772,8 +1323,8 @@ func (app *App) portfolioRecommendations() {
 		riskLevel = "moderate" // Default
 	}
 
-	caser := cases.Title(language.English)
-	fmt.Printf("\n👤 Your Risk Profile: %s\n\n", caser.String(riskLevel))
+	l := app.localizer()
+	fmt.Printf("\n👤 %s\n\n", l.T("risk.profile", map[string]any{"Profile": l.T("risk." + riskLevel)}))
 
 	switch riskLevel {
 	case "conservative":
@@ -818,72 +1369,34 @@ func (app *App) riskAssessment() {
 	fmt.Println(strings.Repeat("-", 40))
 
 	fmt.Println("\n📋 Please answer the following questions to assess your risk tolerance:")
-	fmt.Println("\n1. What is your investment time horizon?")
-	fmt.Println("   a) Less than 3 years")
-	fmt.Println("   b) 3-10 years")
-	fmt.Println("   c) More than 10 years")
-
-	fmt.Print("Your answer (a/b/c): ")
-	timeHorizon, _ := app.reader.ReadString('\n')
-	timeHorizon = strings.TrimSpace(strings.ToLower(timeHorizon))
-
-	fmt.Println("\n2. How would you react to a 20% portfolio loss?")
-	fmt.Println("   a) Sell everything immediately")
-	fmt.Println("   b) Hold and wait for recovery")
-	fmt.Println("   c) Buy more at lower prices")
-
-	fmt.Print("Your answer (a/b/c): ")
-	lossReaction, _ := app.reader.ReadString('\n')
-	lossReaction = strings.TrimSpace(strings.ToLower(lossReaction))
-
-	fmt.Println("\n3. What is your primary investment goal?")
-	fmt.Println("   a) Capital preservation")
-	fmt.Println("   b) Steady income")
-	fmt.Println("   c) Long-term growth")
-
-	fmt.Print("Your answer (a/b/c): ")
-	investmentGoal, _ := app.reader.ReadString('\n')
-	investmentGoal = strings.TrimSpace(strings.ToLower(investmentGoal))
-
-	// Calculate risk score
-	score := 0
-	if timeHorizon == "c" {
-		score += 2
-	}
-	if timeHorizon == "b" {
-		score += 1
-	}
-
-	if lossReaction == "c" {
-		score += 2
-	}
-	if lossReaction == "b" {
-		score += 1
-	}
+	questionnaire := app.riskSvc.CurrentQuestionnaire()
+	answers := make(map[string]string, len(questionnaire.Questions))
+	for i, question := range questionnaire.Questions {
+		fmt.Printf("\n%d. %s\n", i+1, question.Text)
+		ids := make([]string, len(question.Options))
+		for j, option := range question.Options {
+			fmt.Printf("   %s) %s\n", option.ID, option.Text)
+			ids[j] = option.ID
+		}
 
-	if investmentGoal == "c" {
-		score += 2
-	}
-	if investmentGoal == "b" {
-		score += 1
+		fmt.Printf("Your answer (%s): ", strings.Join(ids, "/"))
+		answer := app.readLine()
+		answers[question.ID] = strings.ToLower(answer)
 	}
 
-	// Determine risk profile
-	var riskProfile string
-	switch {
-	case score <= 2:
-		riskProfile = "conservative"
-	case score <= 4:
-		riskProfile = "moderate"
-	default:
-		riskProfile = "aggressive"
+	assessment, err := app.riskSvc.Submit(context.Background(), app.currentUser.ID, answers)
+	if err != nil {
+		fmt.Printf("[ERROR] Risk assessment failed: %v\n", err)
+		return
 	}
+	riskProfile := assessment.RiskProfile
+	app.currentUser.RiskTolerance = riskProfile
 
 	fmt.Println("\n" + strings.Repeat("-", 40))
 	fmt.Println("         ASSESSMENT RESULTS")
 	fmt.Println(strings.Repeat("-", 40))
 	fmt.Printf("\n🎯 Your Risk Profile: %s\n", strings.ToUpper(riskProfile))
-	fmt.Printf("📊 Risk Score: %d/6\n", score)
+	fmt.Printf("📊 Risk Score: %d/%d\n", assessment.Score, assessment.MaxScore)
 
 	switch riskProfile {
 	case "conservative":
@@ -903,5 +1416,5 @@ func (app *App) riskAssessment() {
 		fmt.Println("• Diversified approach")
 	}
 
-	fmt.Println("\n[INFO] Would you like to update your risk profile? (Feature coming soon)")
+	fmt.Println("\n[SUCCESS] Your risk profile has been updated")
 }
@@ -3,8 +3,10 @@ package main
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"io"
 	"os"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -32,7 +34,8 @@ func setupTestApp(t *testing.T) (*App, *gorm.DB) {
 	sqlDB.SetConnMaxLifetime(0)
 
 	// Auto migrate
-	err = db.AutoMigrate(&domain.User{}, &domain.Transaction{}, &domain.Category{}, &domain.Budget{})
+	err = db.AutoMigrate(&domain.User{}, &domain.Transaction{}, &domain.Category{}, &domain.Budget{},
+		&domain.FinancialReport{}, &domain.Comment{}, &domain.TransactionItem{}, &domain.TransactionTag{}, &domain.CategoryMapping{})
 	require.NoError(t, err)
 
 	// Initialize services
@@ -44,9 +47,10 @@ func setupTestApp(t *testing.T) (*App, *gorm.DB) {
 	categorySvc := &application.CategoryService{DB: db}
 	reportsSvc := &application.ReportsService{DB: db}
 	exportSvc := &application.ExportService{DB: db}
+	importSvc := &application.ImportService{DB: db, Transactions: txSvc}
 
 	// Initialize default categories
-	err = categorySvc.InitializeDefaultCategories()
+	err = categorySvc.InitializeDefaultCategories(context.Background())
 	require.NoError(t, err)
 
 	return &App{
@@ -58,6 +62,7 @@ func setupTestApp(t *testing.T) (*App, *gorm.DB) {
 		categorySvc:  categorySvc,
 		reportsSvc:   reportsSvc,
 		exportSvc:    exportSvc,
+		importSvc:    importSvc,
 		reader:       bufio.NewReader(strings.NewReader("")),
 	}, db
 }
@@ -125,7 +130,7 @@ func TestInitializeDatabase(t *testing.T) {
 				assert.NotNil(t, db)
 
 				// Test migration
-				err = db.AutoMigrate(&domain.User{}, &domain.Transaction{}, &domain.Category{}, &domain.Budget{})
+				err = db.AutoMigrate(&domain.User{}, &domain.Transaction{}, &domain.Category{}, &domain.Budget{}, &domain.Comment{})
 				assert.NoError(t, err)
 
 				// Verify tables were created
@@ -144,7 +149,7 @@ func TestInitializeApp(t *testing.T) {
 	require.NoError(t, err)
 
 	// Auto migrate
-	err = db.AutoMigrate(&domain.User{}, &domain.Transaction{}, &domain.Category{}, &domain.Budget{})
+	err = db.AutoMigrate(&domain.User{}, &domain.Transaction{}, &domain.Category{}, &domain.Budget{}, &domain.Comment{})
 	require.NoError(t, err)
 
 	// Test app initialization
@@ -183,7 +188,7 @@ func TestAppServices(t *testing.T) {
 	assert.NoError(t, err)
 
 	// Test that default categories were initialized
-	categories, err := app.categorySvc.GetAllCategories()
+	categories, err := app.categorySvc.GetAllCategories(context.Background(), 1)
 	assert.NoError(t, err)
 	assert.Greater(t, len(categories), 0)
 }
@@ -233,7 +238,7 @@ func TestDatabaseMigration(t *testing.T) {
 	require.NoError(t, err)
 
 	// Test migration
-	err = db.AutoMigrate(&domain.User{}, &domain.Transaction{}, &domain.Category{}, &domain.Budget{})
+	err = db.AutoMigrate(&domain.User{}, &domain.Transaction{}, &domain.Category{}, &domain.Budget{}, &domain.Comment{})
 	assert.NoError(t, err)
 
 	// Verify that tables exist
@@ -258,13 +263,13 @@ func TestServiceIntegration(t *testing.T) {
 		RiskTolerance: "moderate",
 	}
 
-	err := app.userSvc.Create(user)
+	err := app.userSvc.Create(context.Background(), user)
 	assert.NoError(t, err)
 	assert.NotNil(t, user)
 	assert.Greater(t, user.ID, uint(0))
 
 	// Test category service
-	categories, err := app.categorySvc.GetAllCategories()
+	categories, err := app.categorySvc.GetAllCategories(context.Background(), 1)
 	assert.NoError(t, err)
 	assert.Greater(t, len(categories), 0)
 
@@ -272,14 +277,14 @@ func TestServiceIntegration(t *testing.T) {
 	if len(categories) > 0 {
 		transaction := &domain.Transaction{
 			UserID:      user.ID,
-			Amount:      100.50,
+			Amount:      domain.NewMoney(100.50),
 			Description: "Test transaction",
 			Type:        "expense",
 			CategoryID:  categories[0].ID,
 			Date:        time.Now(),
 		}
 
-		err = app.txSvc.Create(transaction)
+		err = app.txSvc.Create(context.Background(), transaction)
 		assert.NoError(t, err)
 		assert.NotNil(t, transaction)
 		assert.Greater(t, transaction.ID, uint(0))
@@ -295,7 +300,7 @@ func TestConcurrentServiceAccess(t *testing.T) {
 	// Test concurrent access to category service
 	for i := 0; i < numGoroutines; i++ {
 		go func(id int) {
-			_, err := app.categorySvc.GetAllCategories()
+			_, err := app.categorySvc.GetAllCategories(context.Background(), 1)
 			results <- err
 		}(i)
 	}
@@ -373,7 +378,7 @@ func BenchmarkAppInitialization(b *testing.B) {
 			b.Fatalf("Database setup failed: %v", err)
 		}
 
-		err = db.AutoMigrate(&domain.User{}, &domain.Transaction{}, &domain.Category{}, &domain.Budget{})
+		err = db.AutoMigrate(&domain.User{}, &domain.Transaction{}, &domain.Category{}, &domain.Budget{}, &domain.Comment{})
 		if err != nil {
 			b.Fatalf("Migration failed: %v", err)
 		}
@@ -390,7 +395,7 @@ func BenchmarkCategoryServiceAccess(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, err := app.categorySvc.GetAllCategories()
+		_, err := app.categorySvc.GetAllCategories(context.Background(), 1)
 		if err != nil {
 			b.Fatalf("Category service failed: %v", err)
 		}
@@ -410,7 +415,7 @@ func BenchmarkUserServiceCreation(b *testing.B) {
 			RiskTolerance: "moderate",
 		}
 
-		err := app.userSvc.Create(user)
+		err := app.userSvc.Create(context.Background(), user)
 		if err != nil {
 			b.Fatalf("User creation failed: %v", err)
 		}
@@ -430,26 +435,26 @@ func TestApplicationFlow(t *testing.T) {
 		RiskTolerance: "aggressive",
 	}
 
-	err := app.userSvc.Create(user)
+	err := app.userSvc.Create(context.Background(), user)
 	require.NoError(t, err)
 	require.NotNil(t, user)
 
 	// Step 2: Get categories
-	categories, err := app.categorySvc.GetAllCategories()
+	categories, err := app.categorySvc.GetAllCategories(context.Background(), 1)
 	require.NoError(t, err)
 	require.Greater(t, len(categories), 0)
 
 	// Step 3: Create transactions
 	transaction := &domain.Transaction{
 		UserID:      user.ID,
-		Amount:      500.00,
+		Amount:      domain.NewMoney(500.00),
 		Description: "Integration test transaction",
 		Type:        "income",
-		CategoryID:  categories[0].ID,
+		CategoryID:  categoryOfType(t, categories, "income").ID,
 		Date:        time.Now(),
 	}
 
-	err = app.txSvc.Create(transaction)
+	err = app.txSvc.Create(context.Background(), transaction)
 	require.NoError(t, err)
 	require.NotNil(t, transaction)
 
@@ -457,28 +462,246 @@ func TestApplicationFlow(t *testing.T) {
 	budget := &domain.Budget{
 		UserID:     user.ID,
 		CategoryID: categories[0].ID,
-		Amount:     1000.00,
+		Amount:     domain.NewMoney(1000.00),
 		Period:     "monthly",
 		StartDate:  time.Now(),
 		EndDate:    time.Now().AddDate(0, 1, 0),
 	}
 
-	err = app.budgetSvc.CreateBudget(budget)
+	err = app.budgetSvc.CreateBudget(context.Background(), budget)
 	require.NoError(t, err)
 	require.NotNil(t, budget)
 
 	// Step 5: Verify data integrity
-	retrievedUser, err := app.userSvc.GetByID(user.ID)
+	retrievedUser, err := app.userSvc.GetByID(context.Background(), user.ID)
 	require.NoError(t, err)
 	assert.Equal(t, user.Email, retrievedUser.Email)
 
-	transactions, err := app.txSvc.List(user.ID)
+	transactions, err := app.txSvc.List(context.Background(), user.ID)
 	require.NoError(t, err)
 	assert.Len(t, transactions, 1)
 	assert.Equal(t, transaction.Amount, transactions[0].Amount)
 
-	budgets, err := app.budgetSvc.GetBudgetsByUser(user.ID)
+	budgets, err := app.budgetSvc.GetBudgetsByUser(context.Background(), user.ID)
 	require.NoError(t, err)
 	assert.Len(t, budgets, 1)
 	assert.Equal(t, budget.Amount, budgets[0].Amount)
 }
+
+// categoryOfType returns the first of the categories with the type
+func categoryOfType(t *testing.T, categories []domain.Category, categoryType string) domain.Category {
+	for _, category := range categories {
+		if category.Type == categoryType {
+			return category
+		}
+	}
+	t.Fatalf("no %s category", categoryType)
+	return domain.Category{}
+}
+
+// setupReportTestUser logs in a user with one income and one expense in March 2024
+func setupReportTestUser(t *testing.T, app *App, email string) {
+	user := &domain.User{FirstName: "Report", LastName: "User", Email: email}
+	require.NoError(t, app.userSvc.Create(context.Background(), user))
+	app.currentUser = user
+
+	categories, err := app.categorySvc.GetAllCategories(context.Background(), 1)
+	require.NoError(t, err)
+	require.Greater(t, len(categories), 1)
+
+	require.NoError(t, app.txSvc.Create(context.Background(), &domain.Transaction{
+		UserID: user.ID, CategoryID: categoryOfType(t, categories, "income").ID, Type: "income",
+		Description: "Salary", Amount: domain.NewMoney(3000), Date: time.Date(2024, time.March, 1, 9, 0, 0, 0, time.UTC),
+	}))
+	require.NoError(t, app.txSvc.Create(context.Background(), &domain.Transaction{
+		UserID: user.ID, CategoryID: categories[1].ID, Type: "expense",
+		Description: "Groceries", Amount: domain.NewMoney(450), Date: time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC),
+	}))
+}
+
+func TestReportMenus(t *testing.T) {
+	app, _ := setupTestApp(t)
+	setupReportTestUser(t, app, "reports@example.com")
+
+	t.Run("monthly report shows summary and categories", func(t *testing.T) {
+		app.reader = bufio.NewReader(strings.NewReader("2024\n3\n"))
+		output := captureOutput(app.monthlyReport)
+
+		assert.Contains(t, output, "March 2024 Monthly Report")
+		assert.Contains(t, output, "Total Income:     $3,000.00")
+		assert.Contains(t, output, "Total Expenses:   $450.00")
+		assert.Contains(t, output, "CATEGORY BREAKDOWN")
+	})
+
+	t.Run("monthly report rejects invalid month", func(t *testing.T) {
+		app.reader = bufio.NewReader(strings.NewReader("2024\n13\n"))
+		output := captureOutput(app.monthlyReport)
+
+		assert.Contains(t, output, "[ERROR] Invalid month")
+	})
+
+	t.Run("yearly report shows monthly trends", func(t *testing.T) {
+		app.reader = bufio.NewReader(strings.NewReader("2024\n"))
+		output := captureOutput(app.yearlyReport)
+
+		assert.Contains(t, output, "2024 Annual Report")
+		assert.Contains(t, output, "MONTHLY TRENDS")
+		assert.Contains(t, output, "Net Income:       $2,550.00")
+	})
+
+	t.Run("empty period reports no transactions", func(t *testing.T) {
+		app.reader = bufio.NewReader(strings.NewReader("2019\n"))
+		output := captureOutput(app.yearlyReport)
+
+		assert.Contains(t, output, "No transactions found")
+	})
+
+	t.Run("compare periods shows changes", func(t *testing.T) {
+		app.reader = bufio.NewReader(strings.NewReader("2024-02\n2024-03\n"))
+		output := captureOutput(app.compareReports)
+
+		assert.Contains(t, output, "2024-03 vs 2024-02")
+		assert.Contains(t, output, "Income           $0.00        $3000.00")
+		assert.Contains(t, output, "CATEGORY CHANGES")
+	})
+
+	t.Run("compare periods rejects unknown period", func(t *testing.T) {
+		app.reader = bufio.NewReader(strings.NewReader("last month\n2024-03\n"))
+		output := captureOutput(app.compareReports)
+
+		assert.Contains(t, output, "[ERROR] Could not compare periods")
+	})
+}
+
+func TestChangeLanguage(t *testing.T) {
+	app, _ := setupTestApp(t)
+	setupReportTestUser(t, app, "sprache@example.com")
+
+	app.reader = bufio.NewReader(strings.NewReader("2\n"))
+	output := captureOutput(app.changeLanguage)
+
+	assert.Contains(t, output, "Current language: English")
+	assert.Contains(t, output, "Dil Türkçe olarak değiştirildi")
+	assert.Equal(t, domain.LocaleTurkish, app.currentUser.Locale)
+
+	t.Run("reports follow the user's language", func(t *testing.T) {
+		app.reader = bufio.NewReader(strings.NewReader("2024\n3\n"))
+		output := captureOutput(app.monthlyReport)
+
+		assert.Contains(t, output, "Mart 2024 Aylık Raporu")
+		assert.Contains(t, output, "Toplam Gelir:     3.000,00 $")
+		assert.Contains(t, output, "Eğitim")
+	})
+
+	t.Run("menus follow the user's language", func(t *testing.T) {
+		app.reader = bufio.NewReader(strings.NewReader("42\n"))
+		output := captureOutput(app.showMainMenu)
+
+		assert.Contains(t, output, "ANA MENÜ - Hoş geldiniz Report User")
+		assert.Contains(t, output, "Geçersiz seçim")
+	})
+
+	t.Run("logging out returns to the terminal's language", func(t *testing.T) {
+		app.reader = bufio.NewReader(strings.NewReader("11\n"))
+		captureOutput(app.showMainMenu)
+
+		assert.Nil(t, app.currentUser)
+		assert.Contains(t, captureOutput(func() {
+			app.reader = bufio.NewReader(strings.NewReader("9\n"))
+			app.showLoginMenu()
+		}), "LOGIN MENU")
+	})
+}
+
+func TestAnalyticsMenus(t *testing.T) {
+	app, _ := setupTestApp(t)
+	setupReportTestUser(t, app, "analytics@example.com")
+
+	t.Run("category analysis shows breakdown and details", func(t *testing.T) {
+		var categoryID uint
+		transactions, err := app.txSvc.List(context.Background(), app.currentUser.ID)
+		require.NoError(t, err)
+		for _, tx := range transactions {
+			if tx.Type == "expense" {
+				categoryID = tx.CategoryID
+			}
+		}
+
+		input := "2024-03-01\n2024-03-31\n" + strconv.FormatUint(uint64(categoryID), 10) + "\n"
+		app.reader = bufio.NewReader(strings.NewReader(input))
+		output := captureOutput(app.categoryAnalysis)
+
+		assert.Contains(t, output, "CATEGORY BREAKDOWN")
+		assert.Contains(t, output, "Total Spent:      $450.00")
+	})
+
+	t.Run("category analysis rejects invalid date", func(t *testing.T) {
+		app.reader = bufio.NewReader(strings.NewReader("03/01/2024\n"))
+		output := captureOutput(app.categoryAnalysis)
+
+		assert.Contains(t, output, "[ERROR] Invalid start date format")
+	})
+
+	t.Run("dashboard summary uses default period", func(t *testing.T) {
+		app.reader = bufio.NewReader(strings.NewReader("\n"))
+		output := captureOutput(app.dashboardSummary)
+
+		assert.Contains(t, output, "OVERVIEW")
+		assert.Contains(t, output, "Savings Rate:")
+	})
+
+	t.Run("dashboard summary rejects invalid period", func(t *testing.T) {
+		app.reader = bufio.NewReader(strings.NewReader("decade\n"))
+		output := captureOutput(app.dashboardSummary)
+
+		assert.Contains(t, output, "[ERROR] Invalid period")
+	})
+}
+
+func TestTrashMenu(t *testing.T) {
+	app, _ := setupTestApp(t)
+	setupReportTestUser(t, app, "trash@example.com")
+
+	transactions, err := app.txSvc.List(context.Background(), app.currentUser.ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, transactions)
+	id := strconv.FormatUint(uint64(transactions[0].ID), 10)
+
+	t.Run("delete moves the transaction to the trash", func(t *testing.T) {
+		app.reader = bufio.NewReader(strings.NewReader(id + "\n"))
+		output := captureOutput(app.deleteTransaction)
+
+		assert.Contains(t, output, "moved to the trash")
+		remaining, err := app.txSvc.List(context.Background(), app.currentUser.ID)
+		require.NoError(t, err)
+		assert.Len(t, remaining, len(transactions)-1)
+	})
+
+	t.Run("trash lists and restores the transaction", func(t *testing.T) {
+		app.reader = bufio.NewReader(strings.NewReader("1\n" + id + "\n"))
+		output := captureOutput(app.trashMenu)
+
+		assert.Contains(t, output, "1 deleted transaction(s)")
+		assert.Contains(t, output, "restored")
+		remaining, err := app.txSvc.List(context.Background(), app.currentUser.ID)
+		require.NoError(t, err)
+		assert.Len(t, remaining, len(transactions))
+	})
+
+	t.Run("empty trash reports nothing to restore", func(t *testing.T) {
+		output := captureOutput(app.trashMenu)
+
+		assert.Contains(t, output, "The trash is empty")
+	})
+
+	t.Run("other users' transactions cannot be deleted", func(t *testing.T) {
+		owner := app.currentUser
+		app.currentUser = &domain.User{ID: owner.ID + 1000}
+		defer func() { app.currentUser = owner }()
+
+		app.reader = bufio.NewReader(strings.NewReader(id + "\n"))
+		output := captureOutput(app.deleteTransaction)
+
+		assert.Contains(t, output, "Transaction not found")
+	})
+}
@@ -0,0 +1,135 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/persistence"
+
+	"gorm.io/gorm"
+)
+
+// AccountService manages the bank, card and cash accounts users hold.
+// Accounts of other users are reported as not found.
+type AccountService struct {
+	DB    *gorm.DB
+	Repo  domain.AccountRepository // Falls back to a GORM repository over DB, without encryption, when nil
+	Audit *AuditService            // Records modifications when set
+}
+
+func NewAccountService(db *gorm.DB) *AccountService {
+	return &AccountService{DB: db}
+}
+
+func (s *AccountService) accounts() domain.AccountRepository {
+	if s.Repo != nil {
+		return s.Repo
+	}
+	return persistence.NewAccountRepository(s.DB, nil)
+}
+
+// Create adds an account for the user
+func (s *AccountService) Create(ctx context.Context, userID uint, account *domain.Account) error {
+	account.ID = 0
+	account.UserID = userID
+	account.Normalize()
+	if err := account.Validate(); err != nil {
+		return err
+	}
+	if err := s.accounts().Create(ctx, account); err != nil {
+		return err
+	}
+	s.Audit.track(ctx, userID, domain.AuditEntityAccount, account.ID, domain.AuditActionCreate, nil, account)
+	return nil
+}
+
+// List returns the user's accounts by name
+func (s *AccountService) List(ctx context.Context, userID uint) ([]domain.Account, error) {
+	return s.accounts().List(ctx, userID)
+}
+
+// Get returns one of the user's accounts
+func (s *AccountService) Get(ctx context.Context, userID, accountID uint) (*domain.Account, error) {
+	account, err := s.accounts().GetByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if account.UserID != userID {
+		return nil, domain.ErrNotFound
+	}
+	return account, nil
+}
+
+// Update replaces the editable fields of one of the user's accounts
+func (s *AccountService) Update(ctx context.Context, userID, accountID uint, updates *domain.Account) (*domain.Account, error) {
+	account, err := s.Get(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	before := *account
+
+	account.Name = updates.Name
+	account.Type = updates.Type
+	account.Institution = updates.Institution
+	account.AccountNumber = updates.AccountNumber
+	account.Currency = updates.Currency
+	account.OpeningBalance = updates.OpeningBalance
+	account.Normalize()
+	if err := account.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.accounts().Update(ctx, account); err != nil {
+		return nil, err
+	}
+	s.Audit.track(ctx, userID, domain.AuditEntityAccount, accountID, domain.AuditActionUpdate, &before, account)
+	return account, nil
+}
+
+// Delete removes one of the user's accounts
+func (s *AccountService) Delete(ctx context.Context, userID, accountID uint) error {
+	account, err := s.Get(ctx, userID, accountID)
+	if err != nil {
+		return err
+	}
+	if err := s.accounts().Delete(ctx, accountID); err != nil {
+		return err
+	}
+	s.Audit.track(ctx, userID, domain.AuditEntityAccount, accountID, domain.AuditActionDelete, account, nil)
+	return nil
+}
+
+// Statement returns the transactions of one of the user's accounts in the
+// month starting at month, with the balance after each, transfers from and
+// to it included. The month opens with the account's opening balance plus
+// every earlier transaction on it.
+func (s *AccountService) Statement(ctx context.Context, userID, accountID uint, month time.Time) (*domain.AccountStatement, error) {
+	account, err := s.Get(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	from := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, month.Location())
+	to := from.AddDate(0, 1, 0)
+
+	onAccount := s.DB.WithContext(ctx).Model(&domain.Transaction{}).
+		Where("user_id = ? AND (account_id = ? OR transfer_account_id = ?)", userID, accountID, accountID)
+
+	var earlier domain.Money
+	err = onAccount.Session(&gorm.Session{}).Where("date < ?", from).
+		Select("COALESCE(SUM(CASE WHEN type = ? OR (type = ? AND account_id = ?) THEN -amount ELSE amount END), 0)",
+			domain.TransactionTypeExpense, domain.TransactionTypeTransfer, accountID).
+		Scan(&earlier).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var transactions []domain.Transaction
+	err = onAccount.Session(&gorm.Session{}).Where("date >= ? AND date < ?", from, to).
+		Order("date, id").Find(&transactions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	statement := domain.NewAccountStatement(*account, from, account.OpeningBalance+earlier, transactions)
+	return &statement, nil
+}
@@ -0,0 +1,89 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupAccountTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&domain.Account{}, &domain.Transaction{}))
+	return db
+}
+
+func TestAccountService_CRUD(t *testing.T) {
+	service := NewAccountService(setupAccountTestDB(t))
+	ctx := context.Background()
+
+	account := &domain.Account{Name: " Main ", Type: domain.AccountTypeChecking, AccountNumber: "DE89 3704 0044 0532 0130 00", Currency: "eur"}
+	require.NoError(t, service.Create(ctx, 1, account))
+	assert.Equal(t, uint(1), account.UserID)
+	assert.Equal(t, "Main", account.Name)
+	assert.Equal(t, "DE89370400440532013000", account.AccountNumber)
+	assert.Equal(t, "EUR", account.Currency)
+	assert.Equal(t, "••••3000", account.MaskedNumber)
+
+	_, err := service.Get(ctx, 2, account.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound, "accounts of other users are not found")
+
+	updated, err := service.Update(ctx, 1, account.ID, &domain.Account{Name: "Joint", Type: domain.AccountTypeSavings, Currency: "USD"})
+	require.NoError(t, err)
+	assert.Equal(t, "Joint", updated.Name)
+	assert.Empty(t, updated.MaskedNumber)
+
+	_, err = service.Update(ctx, 1, account.ID, &domain.Account{Name: "Joint", Type: "piggy_bank"})
+	assert.ErrorIs(t, err, domain.ErrValidation)
+
+	assert.ErrorIs(t, service.Delete(ctx, 2, account.ID), domain.ErrNotFound)
+	require.NoError(t, service.Delete(ctx, 1, account.ID))
+	accounts, err := service.List(ctx, 1)
+	require.NoError(t, err)
+	assert.Empty(t, accounts)
+}
+
+func TestAccountService_Statement(t *testing.T) {
+	db := setupAccountTestDB(t)
+	service := NewAccountService(db)
+	ctx := context.Background()
+
+	account := &domain.Account{Name: "Main", Type: domain.AccountTypeChecking, Currency: "EUR", OpeningBalance: domain.NewMoney(100)}
+	require.NoError(t, service.Create(ctx, 1, account))
+	other := &domain.Account{Name: "Savings", Type: domain.AccountTypeSavings, Currency: "EUR"}
+	require.NoError(t, service.Create(ctx, 1, other))
+
+	day := func(month time.Month, d int) time.Time { return time.Date(2024, month, d, 0, 0, 0, 0, time.UTC) }
+	transactions := []domain.Transaction{
+		{UserID: 1, AccountID: &account.ID, Type: domain.TransactionTypeIncome, Amount: domain.NewMoney(80), Date: day(time.May, 20)},
+		{UserID: 1, AccountID: &account.ID, Type: domain.TransactionTypeExpense, Amount: domain.NewMoney(30), Date: day(time.May, 28)},
+		{UserID: 1, AccountID: &account.ID, Type: domain.TransactionTypeExpense, Amount: domain.NewMoney(30), Date: day(time.June, 12)},
+		{UserID: 1, AccountID: &account.ID, Type: domain.TransactionTypeIncome, Amount: domain.NewMoney(200), Date: day(time.June, 3)},
+		{UserID: 1, AccountID: &account.ID, Type: domain.TransactionTypeIncome, Amount: domain.NewMoney(999), Date: day(time.July, 1)},
+		{UserID: 1, AccountID: &other.ID, Type: domain.TransactionTypeIncome, Amount: domain.NewMoney(999), Date: day(time.June, 5)},
+		{UserID: 1, Type: domain.TransactionTypeExpense, Amount: domain.NewMoney(999), Date: day(time.June, 5)},
+	}
+	require.NoError(t, db.Create(&transactions).Error)
+
+	statement, err := service.Statement(ctx, 1, account.ID, day(time.June, 1))
+	require.NoError(t, err)
+	assert.Equal(t, "2024-06", statement.Month)
+	assert.Equal(t, domain.NewMoney(150), statement.OpeningBalance, "opening balance plus May's transactions")
+	require.Len(t, statement.Entries, 2)
+	assert.Equal(t, transactions[3].ID, statement.Entries[0].TransactionID)
+	assert.Equal(t, domain.NewMoney(350), statement.Entries[0].Balance)
+	assert.Equal(t, domain.NewMoney(320), statement.ClosingBalance)
+
+	_, err = service.Statement(ctx, 2, account.ID, day(time.June, 1))
+	assert.ErrorIs(t, err, domain.ErrNotFound, "statements of other users' accounts are not found")
+}
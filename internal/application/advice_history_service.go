@@ -0,0 +1,263 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/pkg"
+
+	"gorm.io/gorm"
+)
+
+// AdviceHistoryService keeps the advice generated for users so they can look
+// back at it, compare it and mark recommendations as followed or ignored.
+// AdvisorService and the advisor endpoints hold an optional
+// *AdviceHistoryService; a nil service keeps nothing.
+type AdviceHistoryService struct {
+	DB       *gorm.DB
+	Backtest *BacktestService // Captures the prices of recommended symbols when set
+}
+
+func NewAdviceHistoryService(db *gorm.DB) *AdviceHistoryService {
+	return &AdviceHistoryService{DB: db}
+}
+
+// Record stores a piece of advice with its recommendations, which start out
+// pending. The record and its recommendations get their IDs.
+func (s *AdviceHistoryService) Record(ctx context.Context, record *domain.AdviceRecord) error {
+	if s == nil || s.DB == nil {
+		return nil
+	}
+
+	for i := range record.Recommendations {
+		record.Recommendations[i].UserID = record.UserID
+		if record.Recommendations[i].Status == "" {
+			record.Recommendations[i].Status = domain.RecommendationStatusPending
+		}
+	}
+	return s.DB.WithContext(ctx).Create(record).Error
+}
+
+// keep records advice, and the prices it was given at, without failing the
+// request that generated it
+func (s *AdviceHistoryService) keep(ctx context.Context, record *domain.AdviceRecord) bool {
+	if s == nil {
+		return false
+	}
+	if err := s.Record(ctx, record); err != nil {
+		log.Printf("advice history: failed to record %s advice for user %d: %v", record.Source, record.UserID, err)
+		return false
+	}
+	if err := s.Backtest.CapturePrices(ctx, adviceSymbols([]domain.AdviceRecord{*record})); err != nil {
+		log.Printf("advice history: failed to capture prices for advice %d: %v", record.ID, err)
+	}
+	return true
+}
+
+// recordAdvice keeps basic advice and gives it and its recommendations their IDs
+func (s *AdviceHistoryService) recordAdvice(ctx context.Context, user *domain.User, advice *InvestmentAdvice) {
+	if s == nil {
+		return
+	}
+	record := &domain.AdviceRecord{
+		UserID:        user.ID,
+		Source:        domain.AdviceSourceBasic,
+		RiskProfile:   advice.Risk,
+		MonthlyAmount: advice.MonthlySavings,
+	}
+	for _, rec := range advice.Recommendations {
+		record.Recommendations = append(record.Recommendations, domain.Recommendation{
+			Type:         assetType(rec.Asset),
+			Symbol:       rec.Asset,
+			Action:       "buy",
+			CurrentPrice: rec.Amount,
+			Allocation:   rec.Percent,
+			IsActive:     true,
+		})
+	}
+	if !s.keep(ctx, record) {
+		return
+	}
+
+	advice.ID = record.ID
+	for i := range advice.Recommendations {
+		advice.Recommendations[i].ID = record.Recommendations[i].ID
+	}
+}
+
+// RecordPersonalizedAdvice keeps real-time advice and updates it with the
+// IDs of the record and its recommendations. Failures are logged only.
+func (s *AdviceHistoryService) RecordPersonalizedAdvice(ctx context.Context, advice *pkg.InvestmentRecommendation, monthlyIncome float64) {
+	record := &domain.AdviceRecord{
+		UserID:          advice.UserID,
+		Source:          domain.AdviceSourceRealTime,
+		RiskProfile:     advice.RiskProfile,
+		MonthlyAmount:   monthlyIncome,
+		Advice:          advice.Advice,
+		Recommendations: append([]domain.Recommendation(nil), advice.Recommendations...),
+	}
+	if !s.keep(ctx, record) {
+		return
+	}
+
+	advice.AdviceID = record.ID
+	advice.Recommendations = record.Recommendations
+}
+
+// RecordRiskAssessment keeps a risk assessment and sets its AdviceID.
+// Failures are logged only.
+func (s *AdviceHistoryService) RecordRiskAssessment(ctx context.Context, assessment *pkg.AIRiskAssessment, monthlyIncome float64) {
+	record := &domain.AdviceRecord{
+		UserID:          assessment.UserID,
+		Source:          domain.AdviceSourceRiskAssessment,
+		RiskProfile:     assessment.RiskCategory,
+		MonthlyAmount:   monthlyIncome,
+		RiskScore:       assessment.RiskScore,
+		RiskCategory:    assessment.RiskCategory,
+		ConfidenceScore: assessment.ConfidenceScore,
+		Allocation:      assessment.RecommendedAllocation,
+	}
+	if s.keep(ctx, record) {
+		assessment.AdviceID = record.ID
+	}
+}
+
+// List returns the matching advice with its recommendations, newest first
+func (s *AdviceHistoryService) List(ctx context.Context, filter domain.AdviceFilter) ([]domain.AdviceRecord, error) {
+	query := s.DB.WithContext(ctx).Preload("Recommendations").Where("user_id = ?", filter.UserID)
+	if filter.Source != "" {
+		query = query.Where("source = ?", filter.Source)
+	}
+	if filter.Since != nil {
+		query = query.Where("created_at >= ?", *filter.Since)
+	}
+	if filter.Until != nil {
+		query = query.Where("created_at <= ?", *filter.Until)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	var records []domain.AdviceRecord
+	err := query.Order("created_at DESC, id DESC").Find(&records).Error
+	return records, err
+}
+
+// Get returns one of the user's advice records
+func (s *AdviceHistoryService) Get(ctx context.Context, userID, id uint) (*domain.AdviceRecord, error) {
+	var record domain.AdviceRecord
+	err := s.DB.WithContext(ctx).Preload("Recommendations").Where("user_id = ?", userID).First(&record, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Compare describes how the user's advice changed from one record to
+// another. Without toID the latest advice is used; without fromID the advice
+// of the same source generated just before it.
+func (s *AdviceHistoryService) Compare(ctx context.Context, userID, fromID, toID uint) (*domain.AdviceComparison, error) {
+	var from, to *domain.AdviceRecord
+	var err error
+	if fromID != 0 {
+		if from, err = s.Get(ctx, userID, fromID); err != nil {
+			return nil, err
+		}
+	}
+
+	if toID != 0 {
+		to, err = s.Get(ctx, userID, toID)
+	} else {
+		filter := domain.AdviceFilter{UserID: userID, Limit: 1}
+		if from != nil {
+			filter.Source = from.Source
+		}
+		to, err = s.first(ctx, filter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if from == nil {
+		before := to.CreatedAt
+		from, err = s.first(ctx, domain.AdviceFilter{UserID: userID, Source: to.Source, Until: &before, Limit: 2}, to.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	comparison := domain.CompareAdvice(*from, *to)
+	return &comparison, nil
+}
+
+// first returns the newest matching record other than the excluded ones
+func (s *AdviceHistoryService) first(ctx context.Context, filter domain.AdviceFilter, exclude ...uint) (*domain.AdviceRecord, error) {
+	records, err := s.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	for i := range records {
+		if !containsID(exclude, records[i].ID) {
+			return &records[i], nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func containsID(ids []uint, id uint) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+// SetRecommendationStatus marks one of the user's recommendations as
+// followed, ignored or pending again
+func (s *AdviceHistoryService) SetRecommendationStatus(
+	ctx context.Context, userID, recommendationID uint, status string,
+) (*domain.Recommendation, error) {
+	if !domain.IsValidRecommendationStatus(status) {
+		return nil, domain.ErrInvalidRecommendationStatus
+	}
+
+	var recommendation domain.Recommendation
+	err := s.DB.WithContext(ctx).Where("user_id = ?", userID).First(&recommendation, recommendationID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	recommendation.Status = status
+	recommendation.StatusChangedAt = &now
+	err = s.DB.WithContext(ctx).Model(&recommendation).
+		Updates(map[string]interface{}{"status": status, "status_changed_at": now}).Error
+	if err != nil {
+		return nil, err
+	}
+	return &recommendation, nil
+}
+
+// assetType classifies the assets basic advice recommends
+func assetType(asset string) string {
+	switch strings.ToUpper(asset) {
+	case "BTC", "ETH":
+		return "crypto"
+	default:
+		return "stock"
+	}
+}
@@ -0,0 +1,125 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/pkg"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupAdviceHistoryTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&domain.User{}, &domain.Category{}, &domain.Transaction{},
+		&domain.AdviceRecord{}, &domain.Recommendation{},
+	))
+	return db
+}
+
+func TestAdviceHistoryService_KeepsGeneratedAdvice(t *testing.T) {
+	db := setupAdviceHistoryTestDB(t)
+	history := NewAdviceHistoryService(db)
+	advisor := &AdvisorService{DB: db, History: history}
+	ctx := context.Background()
+
+	user := &domain.User{Email: "history@example.com", Password: "x", RiskTolerance: "moderate"}
+	require.NoError(t, db.Create(user).Error)
+
+	first, err := advisor.GenerateAdvice(ctx, user)
+	require.NoError(t, err)
+	assert.NotZero(t, first.ID)
+	assert.NotZero(t, first.Recommendations[0].ID)
+
+	user.RiskTolerance = "aggressive"
+	second, err := advisor.GenerateAdvice(ctx, user)
+	require.NoError(t, err)
+
+	records, err := history.List(ctx, domain.AdviceFilter{UserID: user.ID})
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, second.ID, records[0].ID, "newest first")
+	assert.Len(t, records[0].Recommendations, 2)
+	assert.Equal(t, domain.RecommendationStatusPending, records[0].Recommendations[0].Status)
+
+	// Without IDs the latest advice is compared with the one before it
+	comparison, err := history.Compare(ctx, user.ID, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, comparison.From.ID)
+	assert.Equal(t, second.ID, comparison.To.ID)
+	assert.True(t, comparison.RiskProfileChanged)
+	assert.Len(t, comparison.Changed, 2)
+
+	_, err = history.Get(ctx, user.ID+1, first.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestAdviceHistoryService_RecordsMarketAdvice(t *testing.T) {
+	db := setupAdviceHistoryTestDB(t)
+	history := NewAdviceHistoryService(db)
+	ctx := context.Background()
+
+	advice := &pkg.InvestmentRecommendation{
+		UserID:      1,
+		RiskProfile: "conservative",
+		Advice:      "Stay diversified",
+		Recommendations: []domain.Recommendation{
+			{Type: "bond", Symbol: "GOVT", Action: "buy", Confidence: 85, CurrentPrice: 600, RiskLevel: "low", Timeframe: "long"},
+		},
+	}
+	history.RecordPersonalizedAdvice(ctx, advice, 5000)
+	assert.NotZero(t, advice.AdviceID)
+	assert.NotZero(t, advice.Recommendations[0].ID)
+
+	assessment := &pkg.AIRiskAssessment{
+		UserID: 1, RiskScore: 0.4, RiskCategory: "moderate",
+		RecommendedAllocation: map[string]float64{"stocks": 60, "bonds": 40},
+	}
+	history.RecordRiskAssessment(ctx, assessment, 5000)
+	assert.NotZero(t, assessment.AdviceID)
+
+	record, err := history.Get(ctx, 1, assessment.AdviceID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.AdviceSourceRiskAssessment, record.Source)
+	assert.Equal(t, 60.0, record.Allocation["stocks"])
+
+	// A nil history keeps nothing and does not fail
+	var none *AdviceHistoryService
+	none.RecordRiskAssessment(ctx, &pkg.AIRiskAssessment{UserID: 1}, 0)
+}
+
+func TestAdviceHistoryService_SetRecommendationStatus(t *testing.T) {
+	db := setupAdviceHistoryTestDB(t)
+	history := NewAdviceHistoryService(db)
+	ctx := context.Background()
+
+	record := &domain.AdviceRecord{
+		UserID: 1, Source: domain.AdviceSourceBasic,
+		Recommendations: []domain.Recommendation{{Symbol: "SPY", Type: "stock", Action: "buy"}},
+	}
+	require.NoError(t, history.Record(ctx, record))
+	recommendationID := record.Recommendations[0].ID
+
+	updated, err := history.SetRecommendationStatus(ctx, 1, recommendationID, domain.RecommendationStatusFollowed)
+	require.NoError(t, err)
+	assert.Equal(t, domain.RecommendationStatusFollowed, updated.Status)
+	assert.NotNil(t, updated.StatusChangedAt)
+
+	stored, err := history.Get(ctx, 1, record.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.RecommendationStatusFollowed, stored.Recommendations[0].Status)
+
+	_, err = history.SetRecommendationStatus(ctx, 1, recommendationID, "maybe")
+	assert.ErrorIs(t, err, domain.ErrInvalidRecommendationStatus)
+	_, err = history.SetRecommendationStatus(ctx, 2, recommendationID, domain.RecommendationStatusIgnored)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
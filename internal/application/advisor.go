@@ -1,6 +1,10 @@
 package application
 
 import (
+	"context"
+	"math"
+
+	"go-finance-advisor/internal/config"
 	"go-finance-advisor/internal/domain"
 	"time"
 
@@ -8,28 +12,33 @@ import (
 )
 
 type Recommendation struct {
+	ID      uint    `json:"id,omitempty"` // Set once the advice is kept in the history
 	Asset   string  `json:"asset"`
 	Amount  float64 `json:"amount"`
 	Percent float64 `json:"percent"`
 }
 
 type InvestmentAdvice struct {
+	ID              uint             `json:"id,omitempty"` // Advice history record
 	MonthlySavings  float64          `json:"monthly_savings"`
 	Risk            string           `json:"risk"`
 	Recommendations []Recommendation `json:"recommendations"`
 }
 
 type AdvisorService struct {
-	DB *gorm.DB
+	DB      *gorm.DB
+	History *AdviceHistoryService // Keeps generated advice when set
+	// Assets savings are put into per risk tolerance; the defaults when unset
+	Allocations map[string][]config.AllocationShare
 }
 
-func (s *AdvisorService) CalculateMonthlySavings(userID uint) (float64, error) {
+func (s *AdvisorService) CalculateMonthlySavings(ctx context.Context, userID uint) (float64, error) {
 	var txs []domain.Transaction
 	threeMonthsAgo := time.Now().AddDate(0, -3, 0)
-	if err := s.DB.Where("user_id = ? AND date > ?", userID, threeMonthsAgo).Find(&txs).Error; err != nil {
+	if err := s.DB.WithContext(ctx).Where("user_id = ? AND date > ?", userID, threeMonthsAgo).Find(&txs).Error; err != nil {
 		return 0, err
 	}
-	var income, expense float64
+	var income, expense domain.Money
 	for _, t := range txs {
 		if t.Type == "income" {
 			income += t.Amount
@@ -37,25 +46,30 @@ func (s *AdvisorService) CalculateMonthlySavings(userID uint) (float64, error) {
 			expense += t.Amount
 		}
 	}
-	return (income - expense) / 3.0, nil
+	return (income - expense).Float64() / 3.0, nil
 }
 
-func (s *AdvisorService) GenerateAdvice(user *domain.User) (*InvestmentAdvice, error) {
-	savings, err := s.CalculateMonthlySavings(user.ID)
+func (s *AdvisorService) GenerateAdvice(ctx context.Context, user *domain.User) (*InvestmentAdvice, error) {
+	savings, err := s.CalculateMonthlySavings(ctx, user.ID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Simple allocation based on risk
-	var recs []Recommendation
-	switch user.RiskTolerance {
-	case "conservative":
-		recs = []Recommendation{{Asset: "SPY", Amount: savings * 0.7, Percent: 70}, {Asset: "BTC", Amount: savings * 0.3, Percent: 30}}
-	case "aggressive":
-		recs = []Recommendation{{Asset: "SPY", Amount: savings * 0.3, Percent: 30}, {Asset: "BTC", Amount: savings * 0.7, Percent: 70}}
-	default: // moderate
-		recs = []Recommendation{{Asset: "SPY", Amount: savings * 0.5, Percent: 50}, {Asset: "BTC", Amount: savings * 0.5, Percent: 50}}
+	// Simple allocation based on risk, moderate for unknown tolerances
+	allocations := s.Allocations
+	if allocations == nil {
+		allocations = config.Default().AI.AdviceAllocations
+	}
+	shares, ok := allocations[user.RiskTolerance]
+	if !ok {
+		shares = allocations["moderate"]
+	}
+	recs := make([]Recommendation, 0, len(shares))
+	for _, share := range shares {
+		recs = append(recs, Recommendation{Asset: share.Asset, Amount: savings * share.Share, Percent: math.Round(share.Share*10000) / 100})
 	}
 
-	return &InvestmentAdvice{MonthlySavings: savings, Risk: user.RiskTolerance, Recommendations: recs}, nil
+	advice := &InvestmentAdvice{MonthlySavings: savings, Risk: user.RiskTolerance, Recommendations: recs}
+	s.History.recordAdvice(ctx, user, advice)
+	return advice, nil
 }
@@ -0,0 +1,225 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"go-finance-advisor/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// ClientDashboards builds the dashboards advisors see of their clients
+type ClientDashboards interface {
+	GetDashboardSummary(ctx context.Context, userID uint, period string) (*domain.DashboardSummary, error)
+}
+
+// AdvisorClientService lets clients grant advisor accounts read or comment
+// access to their finances, and serves advisors their clients. Advisors
+// without a grant get domain.ErrAdvisorForbidden, as do users acting as an
+// advisor without an advisor account.
+type AdvisorClientService struct {
+	DB         *gorm.DB
+	Dashboards ClientDashboards
+	Insights   *InsightsService // Shows new recommendations right away when set
+}
+
+func NewAdvisorClientService(db *gorm.DB, dashboards ClientDashboards) *AdvisorClientService {
+	return &AdvisorClientService{DB: db, Dashboards: dashboards}
+}
+
+// Grant gives the advisor account with the email access to the client's
+// finances
+func (s *AdvisorClientService) Grant(ctx context.Context, clientID uint, email, access string) (*domain.AdvisorConnection, error) {
+	if !domain.IsValidAdvisorAccess(access) {
+		return nil, domain.ErrInvalidAdvisorAccess
+	}
+
+	var advisor domain.User
+	err := s.DB.WithContext(ctx).Where("LOWER(email) = LOWER(?)", strings.TrimSpace(email)).First(&advisor).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !advisor.IsAdvisor() || advisor.ID == clientID {
+		return nil, domain.ErrNotAdvisorAccount
+	}
+
+	var existing int64
+	err = s.DB.WithContext(ctx).Model(&domain.AdvisorGrant{}).
+		Where("client_id = ? AND advisor_id = ?", clientID, advisor.ID).Count(&existing).Error
+	if err != nil {
+		return nil, err
+	}
+	if existing > 0 {
+		return nil, domain.ErrAdvisorAlreadyGranted
+	}
+
+	grant := &domain.AdvisorGrant{ClientID: clientID, AdvisorID: advisor.ID, Access: access}
+	if err := s.DB.WithContext(ctx).Create(grant).Error; err != nil {
+		return nil, err
+	}
+	return &domain.AdvisorConnection{
+		UserID:    advisor.ID,
+		Email:     advisor.Email,
+		FirstName: advisor.FirstName,
+		LastName:  advisor.LastName,
+		Access:    grant.Access,
+		GrantedAt: grant.CreatedAt,
+	}, nil
+}
+
+// ListAdvisors returns the advisors the client granted access to
+func (s *AdvisorClientService) ListAdvisors(ctx context.Context, clientID uint) ([]domain.AdvisorConnection, error) {
+	return s.connections(ctx, "advisor_grants.client_id = ?", "advisor_grants.advisor_id", clientID)
+}
+
+// UpdateAccess changes the access the client granted an advisor
+func (s *AdvisorClientService) UpdateAccess(ctx context.Context, clientID, advisorID uint, access string) (*domain.AdvisorGrant, error) {
+	if !domain.IsValidAdvisorAccess(access) {
+		return nil, domain.ErrInvalidAdvisorAccess
+	}
+	grant, err := s.grant(ctx, clientID, advisorID)
+	if err != nil {
+		return nil, err
+	}
+	grant.Access = access
+	if err := s.DB.WithContext(ctx).Save(grant).Error; err != nil {
+		return nil, err
+	}
+	return grant, nil
+}
+
+// Revoke takes away the access the client granted an advisor. The
+// recommendations the advisor left stay with the client.
+func (s *AdvisorClientService) Revoke(ctx context.Context, clientID, advisorID uint) error {
+	result := s.DB.WithContext(ctx).Where("client_id = ? AND advisor_id = ?", clientID, advisorID).Delete(&domain.AdvisorGrant{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// ListClients returns the clients who granted the advisor access
+func (s *AdvisorClientService) ListClients(ctx context.Context, advisorID uint) ([]domain.AdvisorConnection, error) {
+	if err := s.requireAdvisor(ctx, advisorID); err != nil {
+		return nil, err
+	}
+	return s.connections(ctx, "advisor_grants.advisor_id = ?", "advisor_grants.client_id", advisorID)
+}
+
+// ClientDashboard returns the dashboard of one of the advisor's clients for
+// the period
+func (s *AdvisorClientService) ClientDashboard(
+	ctx context.Context, advisorID, clientID uint, period string,
+) (*domain.DashboardSummary, error) {
+	if _, err := s.authorize(ctx, advisorID, clientID, domain.AdvisorGrant.CanRead); err != nil {
+		return nil, err
+	}
+	// The dashboard reads the client's rows, not the acting advisor's
+	return s.Dashboards.GetDashboardSummary(domain.ContextWithoutUserScope(ctx), clientID, period)
+}
+
+// Recommend leaves a client a recommendation, which shows up first in their
+// insights. Only advisors with comment access can recommend.
+func (s *AdvisorClientService) Recommend(
+	ctx context.Context, advisorID, clientID uint, message string,
+) (*domain.AdvisorRecommendation, error) {
+	if _, err := s.authorize(ctx, advisorID, clientID, domain.AdvisorGrant.CanComment); err != nil {
+		return nil, err
+	}
+	recommendation := &domain.AdvisorRecommendation{ClientID: clientID, AdvisorID: advisorID, Message: strings.TrimSpace(message)}
+	if err := recommendation.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.DB.WithContext(ctx).Create(recommendation).Error; err != nil {
+		return nil, err
+	}
+	if s.Insights != nil {
+		s.Insights.InvalidateUser(clientID)
+	}
+	return recommendation, nil
+}
+
+// ListRecommendations returns the recommendations the advisor left a
+// client, newest first
+func (s *AdvisorClientService) ListRecommendations(
+	ctx context.Context, advisorID, clientID uint,
+) ([]domain.AdvisorRecommendation, error) {
+	if _, err := s.authorize(ctx, advisorID, clientID, domain.AdvisorGrant.CanRead); err != nil {
+		return nil, err
+	}
+	recommendations := []domain.AdvisorRecommendation{}
+	err := s.DB.WithContext(ctx).Where("client_id = ? AND advisor_id = ?", clientID, advisorID).
+		Order("created_at DESC, id DESC").Find(&recommendations).Error
+	return recommendations, err
+}
+
+// connections lists the grants matching the condition with the user on the
+// other side, joined through the given column
+func (s *AdvisorClientService) connections(ctx context.Context, condition, otherColumn string, userID uint) ([]domain.AdvisorConnection, error) {
+	connections := []domain.AdvisorConnection{}
+	err := s.DB.WithContext(ctx).Model(&domain.AdvisorGrant{}).
+		Select("users.id AS user_id, users.email, users.first_name, users.last_name, advisor_grants.access, "+
+			"advisor_grants.created_at AS granted_at").
+		Joins("JOIN users ON users.id = "+otherColumn).
+		Where(condition, userID).
+		Order("advisor_grants.created_at, advisor_grants.id").
+		Scan(&connections).Error
+	return connections, err
+}
+
+// grant returns the client's grant to the advisor, or domain.ErrNotFound
+func (s *AdvisorClientService) grant(ctx context.Context, clientID, advisorID uint) (*domain.AdvisorGrant, error) {
+	var grant domain.AdvisorGrant
+	err := s.DB.WithContext(ctx).Where("client_id = ? AND advisor_id = ?", clientID, advisorID).First(&grant).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &grant, nil
+}
+
+// requireAdvisor checks that the user has an advisor account
+func (s *AdvisorClientService) requireAdvisor(ctx context.Context, userID uint) error {
+	var user domain.User
+	if err := s.DB.WithContext(ctx).Select("id", "account_type").First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrAdvisorForbidden
+		}
+		return err
+	}
+	if !user.IsAdvisor() {
+		return domain.ErrAdvisorForbidden
+	}
+	return nil
+}
+
+// authorize returns the client's grant to the advisor if the advisor has an
+// advisor account and the grant passes the check
+func (s *AdvisorClientService) authorize(
+	ctx context.Context, advisorID, clientID uint, allowed func(domain.AdvisorGrant) bool,
+) (*domain.AdvisorGrant, error) {
+	if err := s.requireAdvisor(ctx, advisorID); err != nil {
+		return nil, err
+	}
+	grant, err := s.grant(ctx, clientID, advisorID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, domain.ErrAdvisorForbidden
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !allowed(*grant) {
+		return nil, domain.ErrAdvisorForbidden
+	}
+	return grant, nil
+}
@@ -0,0 +1,130 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubClientDashboards returns an empty dashboard of the user, remembering
+// whether the user scope was lifted for it
+type stubClientDashboards struct {
+	scoped bool
+}
+
+func (s *stubClientDashboards) GetDashboardSummary(ctx context.Context, userID uint, period string) (*domain.DashboardSummary, error) {
+	_, s.scoped = domain.UserScopeFromContext(ctx)
+	return &domain.DashboardSummary{UserID: userID, Period: period}, nil
+}
+
+func TestAdvisorClientService(t *testing.T) {
+	db := setupInsightsTestDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.AdvisorGrant{}))
+	dashboards := &stubClientDashboards{}
+	insights := NewInsightsService(db)
+	service := NewAdvisorClientService(db, dashboards)
+	service.Insights = insights
+
+	newUser := func(email, accountType string) uint {
+		user := &domain.User{Email: email, FirstName: "Test", LastName: "User", AccountType: accountType}
+		require.NoError(t, db.Create(user).Error)
+		return user.ID
+	}
+	client := newUser("client@example.com", domain.UserAccountTypePersonal)
+	advisor := newUser("coach@example.com", domain.UserAccountTypeAdvisor)
+	personal := newUser("friend@example.com", domain.UserAccountTypePersonal)
+	ctx := domain.ContextWithActor(context.Background(), domain.Actor{UserID: advisor})
+
+	t.Run("only advisor accounts are granted access", func(t *testing.T) {
+		_, err := service.Grant(ctx, client, "friend@example.com", domain.AdvisorAccessRead)
+		assert.ErrorIs(t, err, domain.ErrNotAdvisorAccount)
+
+		_, err = service.Grant(ctx, client, "nobody@example.com", domain.AdvisorAccessRead)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+
+		_, err = service.Grant(ctx, client, "coach@example.com", "write")
+		assert.ErrorIs(t, err, domain.ErrInvalidAdvisorAccess)
+	})
+
+	t.Run("advisors the client has not granted access are forbidden", func(t *testing.T) {
+		_, err := service.ClientDashboard(ctx, advisor, client, "month")
+		assert.ErrorIs(t, err, domain.ErrAdvisorForbidden)
+	})
+
+	connection, err := service.Grant(ctx, client, "Coach@example.com", domain.AdvisorAccessRead)
+	require.NoError(t, err)
+	assert.Equal(t, advisor, connection.UserID)
+	_, err = service.Grant(ctx, client, "coach@example.com", domain.AdvisorAccessComment)
+	assert.ErrorIs(t, err, domain.ErrAdvisorAlreadyGranted)
+
+	t.Run("each side lists the other", func(t *testing.T) {
+		clients, err := service.ListClients(ctx, advisor)
+		require.NoError(t, err)
+		require.Len(t, clients, 1)
+		assert.Equal(t, client, clients[0].UserID)
+		assert.Equal(t, "client@example.com", clients[0].Email)
+		assert.Equal(t, domain.AdvisorAccessRead, clients[0].Access)
+
+		advisors, err := service.ListAdvisors(context.Background(), client)
+		require.NoError(t, err)
+		require.Len(t, advisors, 1)
+		assert.Equal(t, advisor, advisors[0].UserID)
+
+		_, err = service.ListClients(ctx, personal)
+		assert.ErrorIs(t, err, domain.ErrAdvisorForbidden)
+	})
+
+	t.Run("the dashboard is the client's", func(t *testing.T) {
+		dashboard, err := service.ClientDashboard(ctx, advisor, client, "month")
+		require.NoError(t, err)
+		assert.Equal(t, client, dashboard.UserID)
+		assert.False(t, dashboards.scoped, "the advisor's user scope must not hide the client's rows")
+	})
+
+	t.Run("recommending needs comment access", func(t *testing.T) {
+		_, err := service.Recommend(ctx, advisor, client, "Move the emergency fund to a savings account")
+		assert.ErrorIs(t, err, domain.ErrAdvisorForbidden)
+
+		_, err = service.UpdateAccess(context.Background(), client, advisor, domain.AdvisorAccessComment)
+		require.NoError(t, err)
+		_, err = service.Recommend(ctx, advisor, client, "   ")
+		assert.ErrorIs(t, err, domain.ErrValidation)
+	})
+
+	t.Run("recommendations lead the client's insights", func(t *testing.T) {
+		before, err := insights.GetInsights(context.Background(), client, "month")
+		require.NoError(t, err)
+		assert.Empty(t, before.Insights)
+
+		recommendation, err := service.Recommend(ctx, advisor, client, " Move the emergency fund to a savings account ")
+		require.NoError(t, err)
+		assert.Equal(t, "Move the emergency fund to a savings account", recommendation.Message)
+
+		report, err := insights.GetInsights(context.Background(), client, "month")
+		require.NoError(t, err)
+		require.Len(t, report.Insights, 1)
+		assert.Equal(t, domain.InsightAdvisorRecommendation, report.Insights[0].Type)
+		assert.Equal(t, 1, report.Insights[0].Rank)
+		assert.Equal(t, "Test User", report.Insights[0].Advisor)
+		assert.Equal(t, recommendation.Message, report.Insights[0].Message)
+
+		recommendations, err := service.ListRecommendations(ctx, advisor, client)
+		require.NoError(t, err)
+		assert.Len(t, recommendations, 1)
+	})
+
+	t.Run("revoked advisors lose access", func(t *testing.T) {
+		require.NoError(t, service.Revoke(context.Background(), client, advisor))
+		assert.ErrorIs(t, service.Revoke(context.Background(), client, advisor), domain.ErrNotFound)
+
+		_, err := service.ClientDashboard(ctx, advisor, client, "month")
+		assert.ErrorIs(t, err, domain.ErrAdvisorForbidden)
+		clients, err := service.ListClients(ctx, advisor)
+		require.NoError(t, err)
+		assert.Empty(t, clients)
+	})
+}
@@ -1,9 +1,11 @@
 package application
 
 import (
+	"context"
 	"testing"
 	"time"
 
+	"go-finance-advisor/internal/config"
 	"go-finance-advisor/internal/domain"
 
 	"github.com/stretchr/testify/assert"
@@ -72,7 +74,7 @@ func TestAdvisorService_CalculateMonthlySavings(t *testing.T) {
 			{
 				UserID:      userID,
 				CategoryID:  incomeCategoryID,
-				Amount:      3000.00,
+				Amount:      domain.NewMoney(3000.00),
 				Type:        "income",
 				Description: "Salary Month 1",
 				Date:        now.AddDate(0, -1, 0), // 1 month ago
@@ -80,7 +82,7 @@ func TestAdvisorService_CalculateMonthlySavings(t *testing.T) {
 			{
 				UserID:      userID,
 				CategoryID:  incomeCategoryID,
-				Amount:      3000.00,
+				Amount:      domain.NewMoney(3000.00),
 				Type:        "income",
 				Description: "Salary Month 2",
 				Date:        now.AddDate(0, -2, 0), // 2 months ago
@@ -88,7 +90,7 @@ func TestAdvisorService_CalculateMonthlySavings(t *testing.T) {
 			{
 				UserID:      userID,
 				CategoryID:  incomeCategoryID,
-				Amount:      3000.00,
+				Amount:      domain.NewMoney(3000.00),
 				Type:        "income",
 				Description: "Salary Month 3",
 				Date:        now.AddDate(0, -3, 5), // Just within 3 months
@@ -96,7 +98,7 @@ func TestAdvisorService_CalculateMonthlySavings(t *testing.T) {
 			{
 				UserID:      userID,
 				CategoryID:  expenseCategoryID,
-				Amount:      800.00,
+				Amount:      domain.NewMoney(800.00),
 				Type:        "expense",
 				Description: "Food Month 1",
 				Date:        now.AddDate(0, -1, 0),
@@ -104,7 +106,7 @@ func TestAdvisorService_CalculateMonthlySavings(t *testing.T) {
 			{
 				UserID:      userID,
 				CategoryID:  expenseCategoryID,
-				Amount:      750.00,
+				Amount:      domain.NewMoney(750.00),
 				Type:        "expense",
 				Description: "Food Month 2",
 				Date:        now.AddDate(0, -2, 0),
@@ -112,7 +114,7 @@ func TestAdvisorService_CalculateMonthlySavings(t *testing.T) {
 			{
 				UserID:      userID,
 				CategoryID:  expenseCategoryID,
-				Amount:      900.00,
+				Amount:      domain.NewMoney(900.00),
 				Type:        "expense",
 				Description: "Food Month 3",
 				Date:        now.AddDate(0, -3, 5),
@@ -121,7 +123,7 @@ func TestAdvisorService_CalculateMonthlySavings(t *testing.T) {
 			{
 				UserID:      userID,
 				CategoryID:  incomeCategoryID,
-				Amount:      2000.00,
+				Amount:      domain.NewMoney(2000.00),
 				Type:        "income",
 				Description: "Old Salary",
 				Date:        now.AddDate(0, -4, 0), // 4 months ago
@@ -133,7 +135,7 @@ func TestAdvisorService_CalculateMonthlySavings(t *testing.T) {
 			require.NoError(t, err)
 		}
 
-		savings, err := advisorService.CalculateMonthlySavings(userID)
+		savings, err := advisorService.CalculateMonthlySavings(context.Background(), userID)
 		assert.NoError(t, err)
 
 		// Total income: 9000, Total expense: 2450
@@ -146,7 +148,7 @@ func TestAdvisorService_CalculateMonthlySavings(t *testing.T) {
 		// Clean up previous transactions
 		db.Where("user_id = ?", userID).Delete(&domain.Transaction{})
 
-		savings, err := advisorService.CalculateMonthlySavings(userID)
+		savings, err := advisorService.CalculateMonthlySavings(context.Background(), userID)
 		assert.NoError(t, err)
 		assert.Equal(t, 0.0, savings)
 	})
@@ -159,7 +161,7 @@ func TestAdvisorService_CalculateMonthlySavings(t *testing.T) {
 		expenseTransaction := &domain.Transaction{
 			UserID:      userID,
 			CategoryID:  expenseCategoryID,
-			Amount:      1000.00,
+			Amount:      domain.NewMoney(1000.00),
 			Type:        "expense",
 			Description: "Only Expense",
 			Date:        now.AddDate(0, -1, 0),
@@ -167,7 +169,7 @@ func TestAdvisorService_CalculateMonthlySavings(t *testing.T) {
 		err := db.Create(expenseTransaction).Error
 		require.NoError(t, err)
 
-		savings, err := advisorService.CalculateMonthlySavings(userID)
+		savings, err := advisorService.CalculateMonthlySavings(context.Background(), userID)
 		assert.NoError(t, err)
 		// Should be negative: (0 - 1000) / 3 = -333.33
 		expectedSavings := -1000.00 / 3.0
@@ -182,7 +184,7 @@ func TestAdvisorService_CalculateMonthlySavings(t *testing.T) {
 		incomeTransaction := &domain.Transaction{
 			UserID:      userID,
 			CategoryID:  incomeCategoryID,
-			Amount:      3000.00,
+			Amount:      domain.NewMoney(3000.00),
 			Type:        "income",
 			Description: "Only Income",
 			Date:        now.AddDate(0, -1, 0),
@@ -190,7 +192,7 @@ func TestAdvisorService_CalculateMonthlySavings(t *testing.T) {
 		err := db.Create(incomeTransaction).Error
 		require.NoError(t, err)
 
-		savings, err := advisorService.CalculateMonthlySavings(userID)
+		savings, err := advisorService.CalculateMonthlySavings(context.Background(), userID)
 		assert.NoError(t, err)
 		// Should be: 3000 / 3 = 1000
 		expectedSavings := 3000.00 / 3.0
@@ -198,7 +200,7 @@ func TestAdvisorService_CalculateMonthlySavings(t *testing.T) {
 	})
 
 	t.Run("calculate savings for non-existent user", func(t *testing.T) {
-		savings, err := advisorService.CalculateMonthlySavings(99999)
+		savings, err := advisorService.CalculateMonthlySavings(context.Background(), 99999)
 		assert.NoError(t, err)
 		assert.Equal(t, 0.0, savings)
 	})
@@ -216,7 +218,7 @@ func TestAdvisorService_GenerateAdvice(t *testing.T) {
 		{
 			UserID:      userID,
 			CategoryID:  incomeCategoryID,
-			Amount:      3000.00,
+			Amount:      domain.NewMoney(3000.00),
 			Type:        "income",
 			Description: "Monthly Salary",
 			Date:        now.AddDate(0, -1, 0),
@@ -224,7 +226,7 @@ func TestAdvisorService_GenerateAdvice(t *testing.T) {
 		{
 			UserID:      userID,
 			CategoryID:  expenseCategoryID,
-			Amount:      2000.00,
+			Amount:      domain.NewMoney(2000.00),
 			Type:        "expense",
 			Description: "Monthly Expenses",
 			Date:        now.AddDate(0, -1, 0),
@@ -245,7 +247,7 @@ func TestAdvisorService_GenerateAdvice(t *testing.T) {
 			RiskTolerance: "conservative",
 		}
 
-		advice, err := advisorService.GenerateAdvice(&user)
+		advice, err := advisorService.GenerateAdvice(context.Background(), &user)
 		assert.NoError(t, err)
 		assert.NotNil(t, advice)
 
@@ -274,7 +276,7 @@ func TestAdvisorService_GenerateAdvice(t *testing.T) {
 			RiskTolerance: "aggressive",
 		}
 
-		advice, err := advisorService.GenerateAdvice(&user)
+		advice, err := advisorService.GenerateAdvice(context.Background(), &user)
 		assert.NoError(t, err)
 		assert.NotNil(t, advice)
 
@@ -302,7 +304,7 @@ func TestAdvisorService_GenerateAdvice(t *testing.T) {
 			RiskTolerance: "moderate",
 		}
 
-		advice, err := advisorService.GenerateAdvice(&user)
+		advice, err := advisorService.GenerateAdvice(context.Background(), &user)
 		assert.NoError(t, err)
 		assert.NotNil(t, advice)
 
@@ -330,7 +332,7 @@ func TestAdvisorService_GenerateAdvice(t *testing.T) {
 			RiskTolerance: "unknown",
 		}
 
-		advice, err := advisorService.GenerateAdvice(&user)
+		advice, err := advisorService.GenerateAdvice(context.Background(), &user)
 		assert.NoError(t, err)
 		assert.NotNil(t, advice)
 
@@ -350,6 +352,26 @@ func TestAdvisorService_GenerateAdvice(t *testing.T) {
 		assert.Equal(t, 50.0, advice.Recommendations[1].Percent)
 	})
 
+	t.Run("generate advice with configured allocations", func(t *testing.T) {
+		service := &AdvisorService{DB: db, Allocations: map[string][]config.AllocationShare{
+			"moderate":     {{Asset: "VTI", Share: 0.6}, {Asset: "BND", Share: 0.25}, {Asset: "ETH", Share: 0.15}},
+			"conservative": {{Asset: "BND", Share: 1}},
+		}}
+		user := domain.User{ID: userID, RiskTolerance: "aggressive"}
+
+		advice, err := service.GenerateAdvice(context.Background(), &user)
+		require.NoError(t, err)
+
+		// Tolerances without a row of their own get the moderate one
+		expectedSavings := 1000.00 / 3.0
+		require.Len(t, advice.Recommendations, 3)
+		assert.Equal(t, "VTI", advice.Recommendations[0].Asset)
+		assert.Equal(t, 60.0, advice.Recommendations[0].Percent)
+		assert.Equal(t, "ETH", advice.Recommendations[2].Asset)
+		assert.InDelta(t, expectedSavings*0.15, advice.Recommendations[2].Amount, 0.01)
+		assert.Equal(t, 15.0, advice.Recommendations[2].Percent)
+	})
+
 	t.Run("generate advice with negative savings", func(t *testing.T) {
 		// Clean up previous transactions
 		db.Where("user_id = ?", userID).Delete(&domain.Transaction{})
@@ -359,7 +381,7 @@ func TestAdvisorService_GenerateAdvice(t *testing.T) {
 			{
 				UserID:      userID,
 				CategoryID:  incomeCategoryID,
-				Amount:      1000.00,
+				Amount:      domain.NewMoney(1000.00),
 				Type:        "income",
 				Description: "Low Income",
 				Date:        now.AddDate(0, -1, 0),
@@ -367,7 +389,7 @@ func TestAdvisorService_GenerateAdvice(t *testing.T) {
 			{
 				UserID:      userID,
 				CategoryID:  expenseCategoryID,
-				Amount:      2000.00,
+				Amount:      domain.NewMoney(2000.00),
 				Type:        "expense",
 				Description: "High Expenses",
 				Date:        now.AddDate(0, -1, 0),
@@ -387,7 +409,7 @@ func TestAdvisorService_GenerateAdvice(t *testing.T) {
 			RiskTolerance: "moderate",
 		}
 
-		advice, err := advisorService.GenerateAdvice(&user)
+		advice, err := advisorService.GenerateAdvice(context.Background(), &user)
 		assert.NoError(t, err)
 		assert.NotNil(t, advice)
 
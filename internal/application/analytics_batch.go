@@ -0,0 +1,142 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+)
+
+// analyticsBatchWorkers is how many queries of a batch run at once
+const analyticsBatchWorkers = 4
+
+// RunAnalyticsBatch runs the user's analytics queries concurrently and
+// returns their results in the order of the queries, so a dashboard needs
+// one request instead of one per panel. A query that fails only sets the
+// Error of its result; cancelling ctx stops the whole batch.
+func RunAnalyticsBatch(
+	ctx context.Context, service AnalyticsServiceInterface, userID uint, queries []domain.AnalyticsQuery,
+) ([]domain.AnalyticsQueryResult, error) {
+	if len(queries) == 0 || len(queries) > domain.MaxAnalyticsBatchQueries {
+		return nil, &domain.ValidationError{Fields: []domain.FieldError{{Field: "queries", Message: "must list 1 to 20 queries"}}}
+	}
+
+	results := make([]domain.AnalyticsQueryResult, len(queries))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(analyticsBatchWorkers, len(queries)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = runAnalyticsQuery(ctx, service, userID, &queries[i])
+			}
+		}()
+	}
+
+queue:
+	for i := range queries {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break queue
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// runAnalyticsQuery answers one query with the defaults of its endpoint.
+// Failures other than invalid parameters and missing categories are logged
+// and reported without their details.
+func runAnalyticsQuery(
+	ctx context.Context, service AnalyticsServiceInterface, userID uint, query *domain.AnalyticsQuery,
+) domain.AnalyticsQueryResult {
+	result := domain.AnalyticsQueryResult{ID: query.ID, Type: query.Type}
+	if err := query.Validate(); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	now := time.Now()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	startDate, endDate := query.StartDate, query.EndDate
+	if startDate.IsZero() {
+		startDate = monthStart
+		if query.Type == domain.AnalyticsQueryMerchants || query.Type == domain.AnalyticsQueryPlaces ||
+			query.Type == domain.AnalyticsQueryItems {
+			startDate = monthStart.AddDate(0, -5, 0)
+		}
+	}
+	if endDate.IsZero() {
+		endDate = time.Date(now.Year(), now.Month()+1, 0, 23, 59, 59, 0, now.Location())
+	} else {
+		endDate = endDate.Add(24*time.Hour - time.Second)
+	}
+	limit := func(fallback int) int {
+		if query.Limit > 0 {
+			return query.Limit
+		}
+		return fallback
+	}
+	months := func(fallback int) int {
+		if query.Months != 0 {
+			return query.Months
+		}
+		return fallback
+	}
+	period := func(fallback string) string {
+		if query.Period != "" {
+			return query.Period
+		}
+		return fallback
+	}
+
+	var data any
+	var err error
+	switch query.Type {
+	case domain.AnalyticsQueryMetrics:
+		data, err = service.GetFinancialMetrics(ctx, userID, period("monthly"), startDate, endDate)
+	case domain.AnalyticsQueryIncomeExpense:
+		data, err = service.GetIncomeExpenseAnalysis(ctx, userID, period("monthly"), startDate, endDate)
+	case domain.AnalyticsQueryCategory:
+		data, err = service.GetCategoryAnalysis(ctx, userID, query.CategoryID, startDate, endDate)
+	case domain.AnalyticsQueryCategoryTrend:
+		data, err = service.GetCategoryTrend(ctx, userID, query.CategoryID, months(domain.DefaultCategoryTrendMonths))
+	case domain.AnalyticsQueryDashboard:
+		data, err = service.GetDashboardSummary(ctx, userID, query.Period)
+	case domain.AnalyticsQueryMerchants:
+		data, err = service.GetMerchantAnalysis(ctx, userID, startDate, endDate, limit(20))
+	case domain.AnalyticsQueryPlaces:
+		groupBy := query.GroupBy
+		if groupBy == "" {
+			groupBy = domain.PlaceGroupPlace
+		}
+		data, err = service.GetPlaceAnalysis(ctx, userID, groupBy, startDate, endDate, limit(50))
+	case domain.AnalyticsQueryItems:
+		data, err = service.GetItemAnalysis(ctx, userID, query.Query, startDate, endDate, limit(50))
+	case domain.AnalyticsQueryIncomeStability:
+		data, err = service.GetIncomeStability(ctx, userID, months(domain.DefaultIncomeStabilityMonths))
+	case domain.AnalyticsQueryIncomeSources:
+		data, err = service.GetIncomeBreakdown(ctx, userID, months(domain.DefaultIncomeBreakdownMonths))
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrValidation), errors.Is(err, domain.ErrNotFound):
+		result.Error = err.Error()
+	case err != nil:
+		log.Printf("analytics batch: %s query of user %d failed: %v", query.Type, userID, err)
+		result.Error = "failed to compute " + query.Type
+	default:
+		result.Data = data
+	}
+	return result
+}
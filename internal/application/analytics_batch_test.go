@@ -0,0 +1,69 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunAnalyticsBatch(t *testing.T) {
+	db := setupAnalyticsTestDB(t)
+	service := &AnalyticsService{DB: db}
+	userID, incomeCategoryID, expenseCategoryID := createAnalyticsTestData(t, db)
+	ctx := context.Background()
+
+	start := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	for _, transaction := range []domain.Transaction{
+		{UserID: userID, CategoryID: incomeCategoryID, Type: "income", Description: "Salary", Amount: domain.NewMoney(3000), Date: start},
+		{
+			UserID: userID, CategoryID: expenseCategoryID, Type: "expense", Description: "Groceries",
+			Amount: domain.NewMoney(400), Date: start.AddDate(0, 0, 30),
+		},
+		{
+			UserID: userID, CategoryID: expenseCategoryID, Type: "expense", Description: "Groceries",
+			Amount: domain.NewMoney(600), Date: start.AddDate(0, 1, 0),
+		},
+	} {
+		require.NoError(t, db.Create(&transaction).Error)
+	}
+
+	t.Run("should answer the queries in order with their own ranges", func(t *testing.T) {
+		march, april := start.AddDate(0, 0, 30), start.AddDate(0, 1, 0)
+		results, err := RunAnalyticsBatch(ctx, service, userID, []domain.AnalyticsQuery{
+			{ID: "march", Type: domain.AnalyticsQueryMetrics, StartDate: start, EndDate: march},
+			{ID: "april", Type: domain.AnalyticsQueryMetrics, StartDate: april, EndDate: april.AddDate(0, 1, -1)},
+			{ID: "food", Type: domain.AnalyticsQueryCategory, CategoryID: expenseCategoryID, StartDate: start, EndDate: april},
+			{ID: "trend", Type: domain.AnalyticsQueryCategoryTrend, CategoryID: expenseCategoryID, Months: 1},
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 4)
+
+		inMarch := results[0].Data.(*domain.FinancialMetrics)
+		assert.Equal(t, "march", results[0].ID)
+		assert.InDelta(t, 3000, inMarch.TotalIncome, 0.001)
+		assert.InDelta(t, 400, inMarch.TotalExpenses, 0.001)
+		assert.InDelta(t, 600, results[1].Data.(*domain.FinancialMetrics).TotalExpenses, 0.001)
+		assert.InDelta(t, 1000, results[2].Data.(*domain.CategoryMetrics).TotalAmount, 0.001)
+		assert.Nil(t, results[3].Data)
+		assert.Contains(t, results[3].Error, "months")
+	})
+
+	t.Run("should limit the number of queries", func(t *testing.T) {
+		_, err := RunAnalyticsBatch(ctx, service, userID, nil)
+		assert.ErrorIs(t, err, domain.ErrValidation)
+		_, err = RunAnalyticsBatch(ctx, service, userID, make([]domain.AnalyticsQuery, domain.MaxAnalyticsBatchQueries+1))
+		assert.ErrorIs(t, err, domain.ErrValidation)
+	})
+
+	t.Run("should stop when the request is cancelled", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err := RunAnalyticsBatch(cancelled, service, userID, []domain.AnalyticsQuery{{Type: domain.AnalyticsQueryDashboard}})
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
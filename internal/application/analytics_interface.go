@@ -1,6 +1,7 @@
 package application
 
 import (
+	"context"
 	"time"
 
 	"go-finance-advisor/internal/domain"
@@ -8,8 +9,20 @@ import (
 
 // AnalyticsServiceInterface defines the contract for analytics operations
 type AnalyticsServiceInterface interface {
-	GetFinancialMetrics(userID uint, period string, startDate, endDate time.Time) (*domain.FinancialMetrics, error)
-	GetIncomeExpenseAnalysis(userID uint, period string, startDate, endDate time.Time) (*domain.IncomeExpenseAnalysis, error)
-	GetCategoryAnalysis(userID, categoryID uint, startDate, endDate time.Time) (*domain.CategoryMetrics, error)
-	GetDashboardSummary(userID uint, period string) (*domain.DashboardSummary, error)
+	GetFinancialMetrics(ctx context.Context, userID uint, period string, startDate, endDate time.Time) (*domain.FinancialMetrics, error)
+	GetIncomeExpenseAnalysis(
+		ctx context.Context, userID uint, period string, startDate, endDate time.Time,
+	) (*domain.IncomeExpenseAnalysis, error)
+	GetCategoryAnalysis(ctx context.Context, userID, categoryID uint, startDate, endDate time.Time) (*domain.CategoryMetrics, error)
+	GetCategoryTrend(ctx context.Context, userID, categoryID uint, months int) (*domain.CategoryTrend, error)
+	GetDashboardSummary(ctx context.Context, userID uint, period string) (*domain.DashboardSummary, error)
+	GetMerchantAnalysis(ctx context.Context, userID uint, startDate, endDate time.Time, limit int) (*domain.MerchantAnalysis, error)
+	GetPlaceAnalysis(
+		ctx context.Context, userID uint, groupBy string, startDate, endDate time.Time, limit int,
+	) (*domain.PlaceAnalysis, error)
+	GetItemAnalysis(
+		ctx context.Context, userID uint, query string, startDate, endDate time.Time, limit int,
+	) (*domain.ItemAnalysis, error)
+	GetIncomeStability(ctx context.Context, userID uint, months int) (*domain.IncomeStability, error)
+	GetIncomeBreakdown(ctx context.Context, userID uint, months int) (*domain.IncomeBreakdown, error)
 }
@@ -1,6 +1,8 @@
 package application
 
 import (
+	"context"
+	"fmt"
 	"math"
 	"sort"
 	"time"
@@ -12,16 +14,34 @@ import (
 
 type AnalyticsService struct {
 	DB *gorm.DB
+	// Cache reuses dashboard and metrics results for CacheTTL when set. The
+	// transaction and budget services drop a user's results when their data changes.
+	Cache    ResultCache
+	CacheTTL time.Duration
 }
 
 func NewAnalyticsService(db *gorm.DB) *AnalyticsService {
 	return &AnalyticsService{DB: db}
 }
 
-// GetFinancialMetrics calculates comprehensive financial metrics for a user
-func (s *AnalyticsService) GetFinancialMetrics(userID uint, period string, startDate, endDate time.Time) (*domain.FinancialMetrics, error) {
-	var transactions []domain.Transaction
-	err := s.DB.Preload("Category").Where("user_id = ? AND date BETWEEN ? AND ?", userID, startDate, endDate).Find(&transactions).Error
+// GetFinancialMetrics calculates comprehensive financial metrics for a user,
+// served from the cache when a fresh copy is available
+func (s *AnalyticsService) GetFinancialMetrics(
+	ctx context.Context, userID uint, period string, startDate, endDate time.Time,
+) (*domain.FinancialMetrics, error) {
+	return cachedResult(ctx, s.Cache, s.CacheTTL, userID, metricsCacheKey(period, startDate, endDate), func() (*domain.FinancialMetrics, error) {
+		return s.financialMetrics(ctx, userID, period, startDate, endDate)
+	})
+}
+
+func metricsCacheKey(period string, startDate, endDate time.Time) string {
+	return fmt.Sprintf("metrics:%s:%d:%d", period, startDate.Unix(), endDate.Unix())
+}
+
+func (s *AnalyticsService) financialMetrics(
+	ctx context.Context, userID uint, period string, startDate, endDate time.Time,
+) (*domain.FinancialMetrics, error) {
+	breakdown, income, expenses, err := sumByCategory(ctx, s.DB, userID, startDate, endDate)
 	if err != nil {
 		return nil, err
 	}
@@ -34,16 +54,16 @@ func (s *AnalyticsService) GetFinancialMetrics(userID uint, period string, start
 	}
 
 	// Calculate basic metrics
-	s.calculateBasicMetrics(metrics, transactions)
+	s.calculateBasicMetrics(metrics, income, expenses)
 
 	// Calculate category breakdown
-	metrics.CategoryBreakdown = s.calculateCategoryBreakdown(transactions)
+	metrics.CategoryBreakdown = rollUpCategoryBreakdown(ctx, s.DB, breakdown)
 
 	// Calculate monthly trends
-	metrics.MonthlyTrends = s.calculateMonthlyTrends(userID, startDate, endDate)
+	metrics.MonthlyTrends = s.calculateMonthlyTrends(ctx, userID, startDate, endDate)
 
 	// Calculate budget performance
-	metrics.BudgetPerformance = s.calculateBudgetPerformance(userID, startDate, endDate)
+	metrics.BudgetPerformance = s.calculateBudgetPerformance(ctx, userID, startDate, endDate)
 
 	// Calculate financial health
 	metrics.CalculateFinancialHealth()
@@ -52,9 +72,13 @@ func (s *AnalyticsService) GetFinancialMetrics(userID uint, period string, start
 }
 
 // GetIncomeExpenseAnalysis provides detailed income vs expense analysis
-func (s *AnalyticsService) GetIncomeExpenseAnalysis(userID uint, period string, startDate, endDate time.Time) (*domain.IncomeExpenseAnalysis, error) {
+func (s *AnalyticsService) GetIncomeExpenseAnalysis(
+	ctx context.Context, userID uint, period string, startDate, endDate time.Time,
+) (*domain.IncomeExpenseAnalysis, error) {
 	var transactions []domain.Transaction
-	err := s.DB.Preload("Category").Where("user_id = ? AND date BETWEEN ? AND ?", userID, startDate, endDate).Find(&transactions).Error
+	err := s.DB.WithContext(ctx).Preload("Category").
+		Where("user_id = ? AND date BETWEEN ? AND ? AND type <> ?", userID, startDate, endDate, domain.TransactionTypeTransfer).
+		Find(&transactions).Error
 	if err != nil {
 		return nil, err
 	}
@@ -79,8 +103,8 @@ func (s *AnalyticsService) GetIncomeExpenseAnalysis(userID uint, period string,
 	}
 
 	// Calculate breakdowns
-	analysis.IncomeBreakdown = s.calculateCategoryBreakdown(incomeTransactions)
-	analysis.ExpenseBreakdown = s.calculateCategoryBreakdown(expenseTransactions)
+	analysis.IncomeBreakdown = rollUpCategoryBreakdown(ctx, s.DB, s.calculateCategoryBreakdown(incomeTransactions))
+	analysis.ExpenseBreakdown = rollUpCategoryBreakdown(ctx, s.DB, s.calculateCategoryBreakdown(expenseTransactions))
 
 	// Get top categories
 	analysis.TopIncomeCategories = s.getTopCategories(analysis.IncomeBreakdown, 5)
@@ -89,16 +113,29 @@ func (s *AnalyticsService) GetIncomeExpenseAnalysis(userID uint, period string,
 	// Calculate daily averages
 	analysis.DailyAverages = s.calculateDailyAverages(transactions, startDate, endDate)
 
-	// Calculate weekly trends
-	analysis.WeeklyTrends = s.calculateWeeklyTrends(userID, startDate, endDate)
+	// Calculate weekly trends, in weeks starting on the user's first weekday
+	preferences := userPreferences(ctx, s.DB, userID)
+	analysis.WeeklyTrends = s.calculateWeeklyTrends(ctx, userID, startDate, endDate, preferences.FirstWeekday())
 
 	return analysis, nil
 }
 
-// GetCategoryAnalysis provides detailed analysis for a specific category
-func (s *AnalyticsService) GetCategoryAnalysis(userID, categoryID uint, startDate, endDate time.Time) (*domain.CategoryMetrics, error) {
+// GetCategoryAnalysis provides detailed analysis for a specific category. A
+// parent category includes its nested categories, which are broken down as
+// subcategories.
+func (s *AnalyticsService) GetCategoryAnalysis(
+	ctx context.Context, userID, categoryID uint, startDate, endDate time.Time,
+) (*domain.CategoryMetrics, error) {
+	categoryIDs, err := s.categoryFamily(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
 	var transactions []domain.Transaction
-	err := s.DB.Preload("Category").Where("user_id = ? AND category_id = ? AND date BETWEEN ? AND ?", userID, categoryID, startDate, endDate).Find(&transactions).Error
+	err = s.DB.WithContext(ctx).Preload("Category").
+		Where("user_id = ? AND category_id IN ? AND date BETWEEN ? AND ? AND type <> ?",
+			userID, categoryIDs, startDate, endDate, domain.TransactionTypeTransfer).
+		Find(&transactions).Error
 	if err != nil {
 		return nil, err
 	}
@@ -113,75 +150,89 @@ func (s *AnalyticsService) GetCategoryAnalysis(userID, categoryID uint, startDat
 		}, nil
 	}
 
-	totalAmount := 0.0
+	var total domain.Money
 	for i := range transactions {
-		totalAmount += transactions[i].Amount
+		total += transactions[i].Amount
 	}
+	totalAmount := total.Float64()
 
 	averageAmount := totalAmount / float64(len(transactions))
 
 	// Calculate trend (simplified - compare with previous period)
-	trend := s.calculateCategoryTrend(userID, categoryID, startDate, endDate)
+	trend := s.calculateCategoryTrend(ctx, userID, categoryID, startDate, endDate)
+
+	var category domain.Category
+	s.DB.WithContext(ctx).Select("name").First(&category, categoryID)
+
+	// Shares of the nested categories are relative to the whole category
+	var subcategories []domain.CategoryMetrics
+	for _, metrics := range s.calculateCategoryBreakdown(transactions) {
+		if metrics.CategoryID != categoryID {
+			subcategories = append(subcategories, metrics)
+		}
+	}
 
 	return &domain.CategoryMetrics{
 		CategoryID:       categoryID,
-		CategoryName:     transactions[0].Category.Name,
+		CategoryName:     category.Name,
 		TotalAmount:      totalAmount,
 		TransactionCount: len(transactions),
 		AverageAmount:    averageAmount,
 		Trend:            trend,
+		Subcategories:    subcategories,
 	}, nil
 }
 
-// Helper functions
-
-func (s *AnalyticsService) calculateBasicMetrics(metrics *domain.FinancialMetrics, transactions []domain.Transaction) {
-	totalIncome := 0.0
-	totalExpenses := 0.0
-
-	for i := range transactions {
-		if transactions[i].Type == domain.TransactionTypeIncome {
-			totalIncome += transactions[i].Amount
-		} else {
-			totalExpenses += transactions[i].Amount
-		}
+// categoryFamily returns the category ID followed by the IDs of its nested categories
+func (s *AnalyticsService) categoryFamily(ctx context.Context, categoryID uint) ([]uint, error) {
+	parents, err := categoryParents(ctx, s.DB)
+	if err != nil {
+		return nil, err
 	}
+	return parents.Descendants(categoryID), nil
+}
 
-	metrics.TotalIncome = totalIncome
-	metrics.TotalExpenses = totalExpenses
-	metrics.NetIncome = totalIncome - totalExpenses
+// Helper functions
+
+func (s *AnalyticsService) calculateBasicMetrics(metrics *domain.FinancialMetrics, totalIncome, totalExpenses domain.Money) {
+	metrics.TotalIncome = totalIncome.Float64()
+	metrics.TotalExpenses = totalExpenses.Float64()
+	metrics.NetIncome = (totalIncome - totalExpenses).Float64()
 	metrics.CashFlow = metrics.NetIncome
 
 	if totalIncome > 0 {
-		metrics.SavingsRate = metrics.NetIncome / totalIncome
-		metrics.ExpenseRatio = totalExpenses / totalIncome
+		metrics.SavingsRate = (totalIncome - totalExpenses).PercentOf(totalIncome) / 100
+		metrics.ExpenseRatio = totalExpenses.PercentOf(totalIncome) / 100
 	}
 }
 
 func (s *AnalyticsService) calculateCategoryBreakdown(transactions []domain.Transaction) []domain.CategoryMetrics {
 	categoryMap := make(map[uint]*domain.CategoryMetrics)
-	totalAmount := 0.0
+	categoryTotals := make(map[uint]domain.Money)
+	var total domain.Money
 
 	// Calculate totals for each category
 	for i := range transactions {
 		tx := &transactions[i]
-		totalAmount += tx.Amount
+		total += tx.Amount
+		categoryTotals[tx.CategoryID] += tx.Amount
 		if metrics, exists := categoryMap[tx.CategoryID]; exists {
-			metrics.TotalAmount += tx.Amount
 			metrics.TransactionCount++
 		} else {
 			categoryMap[tx.CategoryID] = &domain.CategoryMetrics{
 				CategoryID:       tx.CategoryID,
 				CategoryName:     tx.Category.Name,
-				TotalAmount:      tx.Amount,
 				TransactionCount: 1,
+				Uncategorized:    tx.Category.ID == 0 || tx.Category.IsSystem,
 			}
 		}
 	}
+	totalAmount := total.Float64()
 
 	// Calculate percentages and averages
 	var breakdown []domain.CategoryMetrics
-	for _, metrics := range categoryMap {
+	for categoryID, metrics := range categoryMap {
+		metrics.TotalAmount = categoryTotals[categoryID].Float64()
 		metrics.AverageAmount = metrics.TotalAmount / float64(metrics.TransactionCount)
 		if totalAmount > 0 {
 			metrics.PercentageOfTotal = (metrics.TotalAmount / totalAmount) * 100
@@ -197,62 +248,147 @@ func (s *AnalyticsService) calculateCategoryBreakdown(transactions []domain.Tran
 	return breakdown
 }
 
-func (s *AnalyticsService) calculateMonthlyTrends(userID uint, startDate, endDate time.Time) []domain.MonthlyTrend {
-	var trends []domain.MonthlyTrend
+// GetMerchantAnalysis reports the user's expenses per merchant between the
+// dates, with monthly totals, highest spend first. A positive limit keeps
+// only the top merchants.
+func (s *AnalyticsService) GetMerchantAnalysis(
+	ctx context.Context, userID uint, startDate, endDate time.Time, limit int,
+) (*domain.MerchantAnalysis, error) {
+	var expenses []domain.Transaction
+	err := s.DB.WithContext(ctx).Preload("Merchant").
+		Where("user_id = ? AND type = ? AND date BETWEEN ? AND ?", userID, domain.TransactionTypeExpense, startDate, endDate).
+		Find(&expenses).Error
+	if err != nil {
+		return nil, err
+	}
 
-	// Iterate through each month in the date range
-	current := time.Date(startDate.Year(), startDate.Month(), 1, 0, 0, 0, 0, startDate.Location())
-	for current.Before(endDate) || current.Equal(endDate) {
-		monthStart := current
-		monthEnd := monthStart.AddDate(0, 1, -1)
+	analysis := &domain.MerchantAnalysis{
+		UserID:    userID,
+		StartDate: startDate,
+		EndDate:   endDate,
+	}
+	analysis.Merchants, analysis.TotalExpenses, analysis.UnassignedAmount = s.calculateMerchantBreakdown(expenses)
+	if limit > 0 && len(analysis.Merchants) > limit {
+		analysis.Merchants = analysis.Merchants[:limit]
+	}
 
-		var transactions []domain.Transaction
-		s.DB.Where("user_id = ? AND date BETWEEN ? AND ?", userID, monthStart, monthEnd).Find(&transactions)
+	return analysis, nil
+}
 
-		income := 0.0
-		expenses := 0.0
-		for i := range transactions {
-			if transactions[i].Type == "income" {
-				income += transactions[i].Amount
-			} else {
-				expenses += transactions[i].Amount
-			}
+// calculateMerchantBreakdown groups expenses by merchant. It also returns the
+// total of all expenses and the part of it without a merchant.
+func (s *AnalyticsService) calculateMerchantBreakdown(
+	transactions []domain.Transaction,
+) (breakdown []domain.MerchantMetrics, totalExpenses, unassigned float64) {
+	merchantMap := make(map[uint]*domain.MerchantMetrics)
+	merchantTotals := make(map[uint]domain.Money)
+	monthlyMap := make(map[uint]map[string]domain.Money)
+	var expenses, withoutMerchant domain.Money
+
+	for i := range transactions {
+		tx := &transactions[i]
+		if tx.Type != domain.TransactionTypeExpense {
+			continue
+		}
+		expenses += tx.Amount
+		if tx.MerchantID == nil || tx.Merchant == nil {
+			withoutMerchant += tx.Amount
+			continue
+		}
+
+		metrics, exists := merchantMap[*tx.MerchantID]
+		if !exists {
+			metrics = &domain.MerchantMetrics{MerchantID: *tx.MerchantID, MerchantName: tx.Merchant.Name}
+			merchantMap[*tx.MerchantID] = metrics
+			monthlyMap[*tx.MerchantID] = make(map[string]domain.Money)
+		}
+		merchantTotals[*tx.MerchantID] += tx.Amount
+		metrics.TransactionCount++
+		if tx.Date.After(metrics.LastTransaction) {
+			metrics.LastTransaction = tx.Date
 		}
+		monthlyMap[*tx.MerchantID][tx.Date.Format("2006-01")] += tx.Amount
+	}
 
-		netIncome := income - expenses
+	totalExpenses, unassigned = expenses.Float64(), withoutMerchant.Float64()
+	for merchantID, metrics := range merchantMap {
+		metrics.TotalAmount = merchantTotals[merchantID].Float64()
+		metrics.AverageAmount = metrics.TotalAmount / float64(metrics.TransactionCount)
+		if totalExpenses > 0 {
+			metrics.PercentageOfTotal = (metrics.TotalAmount / totalExpenses) * 100
+		}
+		for month, amount := range monthlyMap[merchantID] {
+			metrics.MonthlyAmounts = append(metrics.MonthlyAmounts, domain.MonthlyAmount{Month: month, Amount: amount.Float64()})
+		}
+		sort.Slice(metrics.MonthlyAmounts, func(i, j int) bool {
+			return metrics.MonthlyAmounts[i].Month < metrics.MonthlyAmounts[j].Month
+		})
+		breakdown = append(breakdown, *metrics)
+	}
+
+	// Sort by total amount (descending), then by name for a stable order
+	sort.Slice(breakdown, func(i, j int) bool {
+		if breakdown[i].TotalAmount != breakdown[j].TotalAmount {
+			return breakdown[i].TotalAmount > breakdown[j].TotalAmount
+		}
+		return breakdown[i].MerchantName < breakdown[j].MerchantName
+	})
+
+	return breakdown, totalExpenses, unassigned
+}
+
+// calculateMonthlyTrends totals every calendar month touching the date range
+func (s *AnalyticsService) calculateMonthlyTrends(ctx context.Context, userID uint, startDate, endDate time.Time) []domain.MonthlyTrend {
+	var months []dateRange
+	current := time.Date(startDate.Year(), startDate.Month(), 1, 0, 0, 0, 0, startDate.Location())
+	for !current.After(endDate) {
+		months = append(months, dateRange{Start: current, End: current.AddDate(0, 1, 0).Add(-time.Second)})
+		current = current.AddDate(0, 1, 0)
+	}
+
+	totals, err := sumByPeriod(ctx, s.DB, userID, months)
+	if err != nil {
+		// Trends are best effort, like the rest of the metrics; report empty months
+		totals = make([]periodTotals, len(months))
+	}
+
+	trends := make([]domain.MonthlyTrend, 0, len(months))
+	for i, month := range months {
+		netIncome := totals[i].Income - totals[i].Expenses
 		savingsRate := 0.0
-		if income > 0 {
-			savingsRate = netIncome / income
+		if totals[i].Income > 0 {
+			savingsRate = netIncome.PercentOf(totals[i].Income) / 100
 		}
 
 		trends = append(trends, domain.MonthlyTrend{
-			Month:       monthStart.Format("January"),
-			Year:        monthStart.Year(),
-			Income:      income,
-			Expenses:    expenses,
-			NetIncome:   netIncome,
+			Month:       month.Start.Format("January"),
+			Year:        month.Start.Year(),
+			Income:      totals[i].Income.Float64(),
+			Expenses:    totals[i].Expenses.Float64(),
+			NetIncome:   netIncome.Float64(),
 			SavingsRate: savingsRate,
 		})
-
-		current = current.AddDate(0, 1, 0)
 	}
 
 	return trends
 }
 
-func (s *AnalyticsService) calculateBudgetPerformance(userID uint, startDate, endDate time.Time) domain.BudgetPerformanceMetrics {
+func (s *AnalyticsService) calculateBudgetPerformance(
+	ctx context.Context, userID uint, startDate, endDate time.Time,
+) domain.BudgetPerformanceMetrics {
 	var budgets []domain.Budget
-	s.DB.Preload("Category").Where("user_id = ? AND start_date <= ? AND end_date >= ?", userID, endDate, startDate).Find(&budgets)
+	s.DB.WithContext(ctx).Preload("Category").
+		Where("user_id = ? AND start_date <= ? AND end_date >= ?", userID, endDate, startDate).
+		Find(&budgets)
 
-	totalBudgeted := 0.0
-	totalSpent := 0.0
+	var budgeted, spent domain.Money
 	categoriesOverBudget := 0
 	categoriesUnderBudget := 0
 
 	for i := range budgets {
 		budget := &budgets[i]
-		totalBudgeted += budget.Amount
-		totalSpent += budget.Spent
+		budgeted += budget.Amount
+		spent += budget.Spent
 
 		if budget.Spent > budget.Amount {
 			categoriesOverBudget++
@@ -261,7 +397,8 @@ func (s *AnalyticsService) calculateBudgetPerformance(userID uint, startDate, en
 		}
 	}
 
-	variance := totalSpent - totalBudgeted
+	totalBudgeted, totalSpent := budgeted.Float64(), spent.Float64()
+	variance := (spent - budgeted).Float64()
 	variancePercentage := 0.0
 	if totalBudgeted > 0 {
 		variancePercentage = (variance / totalBudgeted) * 100
@@ -285,17 +422,16 @@ func (s *AnalyticsService) getTopCategories(categories []domain.CategoryMetrics,
 }
 
 func (s *AnalyticsService) calculateDailyAverages(transactions []domain.Transaction, startDate, endDate time.Time) domain.DailyAverages {
-	totalIncome := 0.0
-	totalExpenses := 0.0
-
+	var income, expenses domain.Money
 	for i := range transactions {
 		tx := &transactions[i]
 		if tx.Type == domain.TransactionTypeIncome {
-			totalIncome += tx.Amount
+			income += tx.Amount
 		} else {
-			totalExpenses += tx.Amount
+			expenses += tx.Amount
 		}
 	}
+	totalIncome, totalExpenses := income.Float64(), expenses.Float64()
 
 	days := math.Ceil(endDate.Sub(startDate).Hours() / 24)
 	if days == 0 {
@@ -309,55 +445,62 @@ func (s *AnalyticsService) calculateDailyAverages(transactions []domain.Transact
 	}
 }
 
-func (s *AnalyticsService) calculateWeeklyTrends(userID uint, startDate, endDate time.Time) []domain.WeeklyTrend {
-	var trends []domain.WeeklyTrend
-
+// calculateWeeklyTrends totals the date range week by week, starting on the
+// first day of the week before it
+func (s *AnalyticsService) calculateWeeklyTrends(
+	ctx context.Context, userID uint, startDate, endDate time.Time, firstDay time.Weekday,
+) []domain.WeeklyTrend {
 	// Start from the beginning of the week
 	current := startDate
-	for current.Weekday() != time.Monday {
+	for current.Weekday() != firstDay {
 		current = current.AddDate(0, 0, -1)
 	}
 
-	weekNumber := 1
+	var weeks []dateRange
 	for current.Before(endDate) {
-		weekStart := current
-		weekEnd := current.AddDate(0, 0, 6)
+		weekEnd := current.AddDate(0, 0, 7).Add(-time.Second)
 		if weekEnd.After(endDate) {
 			weekEnd = endDate
 		}
+		weeks = append(weeks, dateRange{Start: current, End: weekEnd})
+		current = current.AddDate(0, 0, 7)
+	}
 
-		var transactions []domain.Transaction
-		s.DB.Where("user_id = ? AND date BETWEEN ? AND ?", userID, weekStart, weekEnd).Find(&transactions)
-
-		income := 0.0
-		expenses := 0.0
-		for i := range transactions {
-			if transactions[i].Type == "income" {
-				income += transactions[i].Amount
-			} else {
-				expenses += transactions[i].Amount
-			}
-		}
+	totals, err := sumByPeriod(ctx, s.DB, userID, weeks)
+	if err != nil {
+		totals = make([]periodTotals, len(weeks))
+	}
 
+	trends := make([]domain.WeeklyTrend, 0, len(weeks))
+	for i, week := range weeks {
 		trends = append(trends, domain.WeeklyTrend{
-			WeekStart:        weekStart,
-			WeekEnd:          weekEnd,
-			WeekNumber:       weekNumber,
-			Income:           income,
-			Expenses:         expenses,
-			NetIncome:        income - expenses,
-			TransactionCount: len(transactions),
+			WeekStart:        week.Start,
+			WeekEnd:          week.End,
+			WeekNumber:       i + 1,
+			Income:           totals[i].Income.Float64(),
+			Expenses:         totals[i].Expenses.Float64(),
+			NetIncome:        (totals[i].Income - totals[i].Expenses).Float64(),
+			TransactionCount: totals[i].TransactionCount,
 		})
-
-		current = current.AddDate(0, 0, 7)
-		weekNumber++
 	}
 
 	return trends
 }
 
-// GetDashboardSummary returns a comprehensive dashboard overview
-func (s *AnalyticsService) GetDashboardSummary(userID uint, period string) (*domain.DashboardSummary, error) {
+// GetDashboardSummary returns a comprehensive dashboard overview, served from
+// the cache when a fresh copy is available. Without a period it covers the
+// user's preferred one.
+func (s *AnalyticsService) GetDashboardSummary(ctx context.Context, userID uint, period string) (*domain.DashboardSummary, error) {
+	if period == "" {
+		preferences := userPreferences(ctx, s.DB, userID)
+		period = preferences.DashboardPeriod
+	}
+	return cachedResult(ctx, s.Cache, s.CacheTTL, userID, "dashboard:"+period, func() (*domain.DashboardSummary, error) {
+		return s.dashboardSummary(ctx, userID, period)
+	})
+}
+
+func (s *AnalyticsService) dashboardSummary(ctx context.Context, userID uint, period string) (*domain.DashboardSummary, error) {
 	// Calculate date range based on period
 	now := time.Now()
 	var startDate, endDate time.Time
@@ -382,43 +525,49 @@ func (s *AnalyticsService) GetDashboardSummary(userID uint, period string) (*dom
 
 	// Get all transactions for the period
 	var transactions []domain.Transaction
-	err := s.DB.Preload("Category").Where("user_id = ? AND date BETWEEN ? AND ?", userID, startDate, endDate).Find(&transactions).Error
+	err := s.DB.WithContext(ctx).Preload("Category").Preload("Merchant").
+		Where("user_id = ? AND date BETWEEN ? AND ? AND type <> ?", userID, startDate, endDate, domain.TransactionTypeTransfer).
+		Find(&transactions).Error
 	if err != nil {
 		return nil, err
 	}
 
 	// Calculate basic metrics
-	totalIncome := 0.0
-	totalExpenses := 0.0
+	var income, expenses domain.Money
 	for i := range transactions {
 		tx := &transactions[i]
 		if tx.Type == domain.TransactionTypeIncome {
-			totalIncome += tx.Amount
+			income += tx.Amount
 		} else {
-			totalExpenses += tx.Amount
+			expenses += tx.Amount
 		}
 	}
+	totalIncome, totalExpenses := income.Float64(), expenses.Float64()
 
-	monthlySavings := totalIncome - totalExpenses
+	monthlySavings := (income - expenses).Float64()
 	savingsRate := 0.0
 	if totalIncome > 0 {
 		savingsRate = (monthlySavings / totalIncome) * 100
 	}
 
 	// Get category breakdown
-	categoryBreakdown := s.calculateCategoryBreakdown(transactions)
+	categoryBreakdown := rollUpCategoryBreakdown(ctx, s.DB, s.calculateCategoryBreakdown(transactions))
 	topExpenseCategories := s.getTopCategories(categoryBreakdown, 5)
+	merchantBreakdown, _, _ := s.calculateMerchantBreakdown(transactions)
+	if len(merchantBreakdown) > 5 {
+		merchantBreakdown = merchantBreakdown[:5]
+	}
 
 	// Get recent transactions (last 10)
 	var recentTransactions []domain.Transaction
-	s.DB.Preload("Category").Where("user_id = ?", userID).Order("date DESC").Limit(10).Find(&recentTransactions)
+	s.DB.WithContext(ctx).Preload("Category").Where("user_id = ?", userID).Order("date DESC").Limit(10).Find(&recentTransactions)
 
 	// Calculate budget alerts
-	budgetAlerts := s.calculateBudgetAlerts(userID, startDate, endDate)
+	budgetAlerts := s.calculateBudgetAlerts(ctx, userID, startDate, endDate)
 
 	// Get financial goals
 	var financialGoals []domain.FinancialGoal
-	s.DB.Where("user_id = ? AND status = ?", userID, "active").Find(&financialGoals)
+	s.DB.WithContext(ctx).Where("user_id = ? AND status = ?", userID, "active").Find(&financialGoals)
 	for i := range financialGoals {
 		financialGoals[i].CalculateProgress()
 	}
@@ -426,6 +575,16 @@ func (s *AnalyticsService) GetDashboardSummary(userID uint, period string) (*dom
 	// Calculate quick stats
 	quickStats := s.calculateQuickStats(userID, transactions, startDate, endDate)
 
+	// Get the watchlist so clients refresh those prices first
+	watchlist := []domain.WatchlistItem{}
+	s.DB.WithContext(ctx).Where("user_id = ?", userID).Order("created_at ASC, id ASC").Find(&watchlist)
+
+	// Caps always cover the calendar month, whatever the period
+	categoryCaps, err := categoryCapStatus(ctx, s.DB, userID, now, nil)
+	if err != nil {
+		categoryCaps = []domain.CategoryCapStatus{}
+	}
+
 	dashboard := &domain.DashboardSummary{
 		UserID:               userID,
 		Period:               period,
@@ -437,41 +596,41 @@ func (s *AnalyticsService) GetDashboardSummary(userID uint, period string) (*dom
 		MonthlySavings:       monthlySavings,
 		SavingsRate:          savingsRate,
 		TopExpenseCategories: topExpenseCategories,
+		TopMerchants:         merchantBreakdown,
 		RecentTransactions:   recentTransactions,
 		BudgetAlerts:         budgetAlerts,
 		FinancialGoals:       financialGoals,
 		QuickStats:           quickStats,
+		Watchlist:            watchlist,
+		CategoryCaps:         categoryCaps,
 	}
 
 	return dashboard, nil
 }
 
-func (s *AnalyticsService) calculateBudgetAlerts(userID uint, startDate, endDate time.Time) []domain.BudgetAlert {
+func (s *AnalyticsService) calculateBudgetAlerts(ctx context.Context, userID uint, startDate, endDate time.Time) []domain.BudgetAlert {
 	var budgets []domain.Budget
-	s.DB.Preload("Category").Where("user_id = ?", userID).Find(&budgets)
+	s.DB.WithContext(ctx).Preload("Category").Where("user_id = ?", userID).Find(&budgets)
 
 	var alerts []domain.BudgetAlert
 	for i := range budgets {
 		budget := &budgets[i]
 		// Calculate spent amount for this budget's category
-		var spentAmount float64
-		query := s.DB.Model(&domain.Transaction{}).
-			Where("user_id = ? AND category_id = ? AND date BETWEEN ? AND ?", userID, budget.CategoryID, startDate, endDate).
+		var spent domain.Money
+		query := s.DB.WithContext(ctx).Model(&domain.Transaction{}).
+			Where("user_id = ? AND category_id = ? AND date BETWEEN ? AND ? AND type <> ?",
+				userID, budget.CategoryID, startDate, endDate, domain.TransactionTypeTransfer).
 			Select("COALESCE(SUM(amount), 0)")
-		query.Scan(&spentAmount)
-
-		percentageUsed := 0.0
-		if budget.Amount > 0 {
-			percentageUsed = (spentAmount / budget.Amount) * 100
-		}
+		query.Scan(&spent)
+		percentageUsed := spent.PercentOf(budget.Amount)
 
 		// Only create alerts for budgets that are over 60% used
 		if percentageUsed >= 60 {
 			alert := domain.BudgetAlert{
 				BudgetID:       budget.ID,
 				CategoryName:   budget.Category.Name,
-				BudgetAmount:   budget.Amount,
-				SpentAmount:    spentAmount,
+				BudgetAmount:   budget.Amount.Float64(),
+				SpentAmount:    spent.Float64(),
 				PercentageUsed: percentageUsed,
 				DaysRemaining:  int(time.Until(endDate).Hours() / 24),
 			}
@@ -494,21 +653,22 @@ func (s *AnalyticsService) calculateQuickStats(
 	mostUsedCategory := ""
 
 	if totalTransactions > 0 {
-		totalAmount := 0.0
+		var totalAmount, largest domain.Money
 		categoryCount := make(map[string]int)
 
 		for i := range transactions {
 			tx := &transactions[i]
 			totalAmount += tx.Amount
-			if tx.Type == domain.TransactionTypeExpense && tx.Amount > largestExpense {
-				largestExpense = tx.Amount
+			if tx.Type == domain.TransactionTypeExpense && tx.Amount > largest {
+				largest = tx.Amount
 			}
 			if tx.Category.Name != "" {
 				categoryCount[tx.Category.Name]++
 			}
 		}
 
-		averageTransaction = totalAmount / float64(totalTransactions)
+		averageTransaction = totalAmount.Float64() / float64(totalTransactions)
+		largestExpense = largest.Float64()
 
 		// Find most used category
 		maxCount := 0
@@ -525,8 +685,7 @@ func (s *AnalyticsService) calculateQuickStats(
 	if len(transactions) > 0 {
 		// Simple trend calculation based on recent vs older transactions
 		midPoint := len(transactions) / 2
-		recentTotal := 0.0
-		olderTotal := 0.0
+		var recentTotal, olderTotal domain.Money
 
 		for i := range transactions {
 			tx := &transactions[i]
@@ -537,9 +696,9 @@ func (s *AnalyticsService) calculateQuickStats(
 			}
 		}
 
-		if recentTotal > olderTotal*1.1 {
+		if recentTotal > olderTotal.Mul(1.1) {
 			cashFlowTrend = "positive"
-		} else if recentTotal < olderTotal*0.9 {
+		} else if recentTotal < olderTotal.Mul(0.9) {
 			cashFlowTrend = "negative"
 		}
 	}
@@ -554,34 +713,34 @@ func (s *AnalyticsService) calculateQuickStats(
 	}
 }
 
-func (s *AnalyticsService) calculateCategoryTrend(userID, categoryID uint, startDate, endDate time.Time) string {
-	// Calculate spending for current period
-	var currentTransactions []domain.Transaction
-	s.DB.Where("user_id = ? AND category_id = ? AND date BETWEEN ? AND ?", userID, categoryID, startDate, endDate).Find(&currentTransactions)
-
-	currentTotal := 0.0
-	for i := range currentTransactions {
-		currentTotal += currentTransactions[i].Amount
+func (s *AnalyticsService) calculateCategoryTrend(ctx context.Context, userID, categoryID uint, startDate, endDate time.Time) string {
+	categoryIDs, err := s.categoryFamily(ctx, categoryID)
+	if err != nil {
+		return "stable"
 	}
 
+	// Calculate spending for current period
+	var currentTotal domain.Money
+	s.DB.WithContext(ctx).Model(&domain.Transaction{}).
+		Where("user_id = ? AND category_id IN ? AND date BETWEEN ? AND ? AND type <> ?",
+			userID, categoryIDs, startDate, endDate, domain.TransactionTypeTransfer).
+		Select("COALESCE(SUM(amount), 0)").Scan(&currentTotal)
+
 	// Calculate spending for previous period (same duration)
 	duration := endDate.Sub(startDate)
 	prevStartDate := startDate.Add(-duration)
 	prevEndDate := startDate
 
-	var prevTransactions []domain.Transaction
-	s.DB.Where("user_id = ? AND category_id = ? AND date BETWEEN ? AND ?",
-		userID, categoryID, prevStartDate, prevEndDate).Find(&prevTransactions)
-
-	prevTotal := 0.0
-	for i := range prevTransactions {
-		prevTotal += prevTransactions[i].Amount
-	}
+	var prevTotal domain.Money
+	s.DB.WithContext(ctx).Model(&domain.Transaction{}).
+		Where("user_id = ? AND category_id IN ? AND date BETWEEN ? AND ? AND type <> ?",
+			userID, categoryIDs, prevStartDate, prevEndDate, domain.TransactionTypeTransfer).
+		Select("COALESCE(SUM(amount), 0)").Scan(&prevTotal)
 
 	// Determine trend
-	if currentTotal > prevTotal*1.1 { // 10% increase threshold
+	if currentTotal > prevTotal.Mul(1.1) { // 10% increase threshold
 		return "increasing"
-	} else if currentTotal < prevTotal*0.9 { // 10% decrease threshold
+	} else if currentTotal < prevTotal.Mul(0.9) { // 10% decrease threshold
 		return "decreasing"
 	}
 	return "stable"
@@ -1,6 +1,7 @@
 package application
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -23,6 +24,10 @@ func setupAnalyticsTestDB(t *testing.T) *gorm.DB {
 		&domain.Transaction{},
 		&domain.Budget{},
 		&domain.FinancialGoal{},
+		&domain.WatchlistItem{},
+		&domain.CategoryCap{},
+		&domain.Account{},
+		&domain.IncomeSource{},
 	)
 	require.NoError(t, err)
 
@@ -71,7 +76,7 @@ func TestAnalyticsService_GetFinancialMetrics(t *testing.T) {
 		{
 			UserID:      userID,
 			CategoryID:  incomeCategoryID,
-			Amount:      5000.00,
+			Amount:      domain.NewMoney(5000.00),
 			Type:        "income",
 			Description: "Salary",
 			Date:        now.AddDate(0, 0, -15),
@@ -79,7 +84,7 @@ func TestAnalyticsService_GetFinancialMetrics(t *testing.T) {
 		{
 			UserID:      userID,
 			CategoryID:  expenseCategoryID,
-			Amount:      1500.00,
+			Amount:      domain.NewMoney(1500.00),
 			Type:        "expense",
 			Description: "Groceries",
 			Date:        now.AddDate(0, 0, -10),
@@ -87,7 +92,7 @@ func TestAnalyticsService_GetFinancialMetrics(t *testing.T) {
 		{
 			UserID:      userID,
 			CategoryID:  expenseCategoryID,
-			Amount:      800.00,
+			Amount:      domain.NewMoney(800.00),
 			Type:        "expense",
 			Description: "Restaurant",
 			Date:        now.AddDate(0, 0, -5),
@@ -100,7 +105,7 @@ func TestAnalyticsService_GetFinancialMetrics(t *testing.T) {
 	}
 
 	t.Run("calculate financial metrics", func(t *testing.T) {
-		metrics, err := analyticsService.GetFinancialMetrics(userID, "month", startDate, endDate)
+		metrics, err := analyticsService.GetFinancialMetrics(context.Background(), userID, "month", startDate, endDate)
 		assert.NoError(t, err)
 		assert.NotNil(t, metrics)
 		assert.Equal(t, userID, metrics.UserID)
@@ -118,7 +123,7 @@ func TestAnalyticsService_GetFinancialMetrics(t *testing.T) {
 		// Clean up transactions
 		db.Where("user_id = ?", userID).Delete(&domain.Transaction{})
 
-		metrics, err := analyticsService.GetFinancialMetrics(userID, "month", startDate, endDate)
+		metrics, err := analyticsService.GetFinancialMetrics(context.Background(), userID, "month", startDate, endDate)
 		assert.NoError(t, err)
 		assert.NotNil(t, metrics)
 		assert.Equal(t, 0.0, metrics.TotalIncome)
@@ -141,7 +146,7 @@ func TestAnalyticsService_GetIncomeExpenseAnalysis(t *testing.T) {
 		{
 			UserID:      userID,
 			CategoryID:  incomeCategoryID,
-			Amount:      3000.00,
+			Amount:      domain.NewMoney(3000.00),
 			Type:        "income",
 			Description: "Salary",
 			Date:        now.AddDate(0, 0, -15),
@@ -149,7 +154,7 @@ func TestAnalyticsService_GetIncomeExpenseAnalysis(t *testing.T) {
 		{
 			UserID:      userID,
 			CategoryID:  incomeCategoryID,
-			Amount:      500.00,
+			Amount:      domain.NewMoney(500.00),
 			Type:        "income",
 			Description: "Freelance",
 			Date:        now.AddDate(0, 0, -10),
@@ -157,7 +162,7 @@ func TestAnalyticsService_GetIncomeExpenseAnalysis(t *testing.T) {
 		{
 			UserID:      userID,
 			CategoryID:  expenseCategoryID,
-			Amount:      1200.00,
+			Amount:      domain.NewMoney(1200.00),
 			Type:        "expense",
 			Description: "Groceries",
 			Date:        now.AddDate(0, 0, -8),
@@ -170,7 +175,7 @@ func TestAnalyticsService_GetIncomeExpenseAnalysis(t *testing.T) {
 	}
 
 	t.Run("income expense analysis", func(t *testing.T) {
-		analysis, err := analyticsService.GetIncomeExpenseAnalysis(userID, "month", startDate, endDate)
+		analysis, err := analyticsService.GetIncomeExpenseAnalysis(context.Background(), userID, "month", startDate, endDate)
 		assert.NoError(t, err)
 		assert.NotNil(t, analysis)
 		assert.Equal(t, userID, analysis.UserID)
@@ -198,7 +203,7 @@ func TestAnalyticsService_GetCategoryAnalysis(t *testing.T) {
 		{
 			UserID:      userID,
 			CategoryID:  expenseCategoryID,
-			Amount:      100.00,
+			Amount:      domain.NewMoney(100.00),
 			Type:        "expense",
 			Description: "Lunch",
 			Date:        now.AddDate(0, 0, -15),
@@ -206,7 +211,7 @@ func TestAnalyticsService_GetCategoryAnalysis(t *testing.T) {
 		{
 			UserID:      userID,
 			CategoryID:  expenseCategoryID,
-			Amount:      200.00,
+			Amount:      domain.NewMoney(200.00),
 			Type:        "expense",
 			Description: "Dinner",
 			Date:        now.AddDate(0, 0, -10),
@@ -214,7 +219,7 @@ func TestAnalyticsService_GetCategoryAnalysis(t *testing.T) {
 		{
 			UserID:      userID,
 			CategoryID:  expenseCategoryID,
-			Amount:      150.00,
+			Amount:      domain.NewMoney(150.00),
 			Type:        "expense",
 			Description: "Groceries",
 			Date:        now.AddDate(0, 0, -5),
@@ -227,7 +232,7 @@ func TestAnalyticsService_GetCategoryAnalysis(t *testing.T) {
 	}
 
 	t.Run("category analysis with transactions", func(t *testing.T) {
-		metrics, err := analyticsService.GetCategoryAnalysis(userID, expenseCategoryID, startDate, endDate)
+		metrics, err := analyticsService.GetCategoryAnalysis(context.Background(), userID, expenseCategoryID, startDate, endDate)
 		assert.NoError(t, err)
 		assert.NotNil(t, metrics)
 		assert.Equal(t, expenseCategoryID, metrics.CategoryID)
@@ -240,7 +245,7 @@ func TestAnalyticsService_GetCategoryAnalysis(t *testing.T) {
 
 	t.Run("category analysis no transactions", func(t *testing.T) {
 		// Use a non-existent category ID
-		metrics, err := analyticsService.GetCategoryAnalysis(userID, 999, startDate, endDate)
+		metrics, err := analyticsService.GetCategoryAnalysis(context.Background(), userID, 999, startDate, endDate)
 		assert.NoError(t, err)
 		assert.NotNil(t, metrics)
 		assert.Equal(t, uint(999), metrics.CategoryID)
@@ -263,7 +268,7 @@ func TestAnalyticsService_GetDashboardSummary(t *testing.T) {
 		{
 			UserID:      userID,
 			CategoryID:  incomeCategoryID,
-			Amount:      4000.00,
+			Amount:      domain.NewMoney(4000.00),
 			Type:        "income",
 			Description: "Salary",
 			Date:        now.AddDate(0, 0, -15),
@@ -271,7 +276,7 @@ func TestAnalyticsService_GetDashboardSummary(t *testing.T) {
 		{
 			UserID:      userID,
 			CategoryID:  expenseCategoryID,
-			Amount:      1000.00,
+			Amount:      domain.NewMoney(1000.00),
 			Type:        "expense",
 			Description: "Groceries",
 			Date:        now.AddDate(0, 0, -10),
@@ -279,7 +284,7 @@ func TestAnalyticsService_GetDashboardSummary(t *testing.T) {
 		{
 			UserID:      userID,
 			CategoryID:  expenseCategoryID,
-			Amount:      500.00,
+			Amount:      domain.NewMoney(500.00),
 			Type:        "expense",
 			Description: "Restaurant",
 			Date:        now.AddDate(0, 0, -5),
@@ -295,8 +300,8 @@ func TestAnalyticsService_GetDashboardSummary(t *testing.T) {
 	budget := &domain.Budget{
 		UserID:     userID,
 		CategoryID: expenseCategoryID,
-		Amount:     2000.00,
-		Spent:      1500.00,
+		Amount:     domain.NewMoney(2000.00),
+		Spent:      domain.NewMoney(1500.00),
 		StartDate:  now.AddDate(0, 0, -30),
 		EndDate:    now.AddDate(0, 0, 30),
 		IsActive:   true,
@@ -309,17 +314,19 @@ func TestAnalyticsService_GetDashboardSummary(t *testing.T) {
 		UserID:        userID,
 		Title:         "Emergency Fund",
 		Description:   "Emergency savings goal",
-		TargetAmount:  10000.00,
-		CurrentAmount: 5000.00,
+		TargetAmount:  domain.NewMoney(10000.00),
+		CurrentAmount: domain.NewMoney(5000.00),
 		TargetDate:    now.AddDate(1, 0, 0),
 		GoalType:      "savings",
 		Status:        "active",
 	}
 	err = db.Create(goal).Error
 	require.NoError(t, err)
+	require.NoError(t, db.Create(&domain.WatchlistItem{UserID: userID, Symbol: "AAPL", AssetType: "stock"}).Error)
+	require.NoError(t, db.Create(&domain.CategoryCap{UserID: userID, CategoryID: expenseCategoryID, Amount: domain.NewMoney(3000)}).Error)
 
 	t.Run("dashboard summary month", func(t *testing.T) {
-		dashboard, err := analyticsService.GetDashboardSummary(userID, "month")
+		dashboard, err := analyticsService.GetDashboardSummary(context.Background(), userID, "month")
 		assert.NoError(t, err)
 		assert.NotNil(t, dashboard)
 		assert.Equal(t, userID, dashboard.UserID)
@@ -333,24 +340,29 @@ func TestAnalyticsService_GetDashboardSummary(t *testing.T) {
 		assert.NotEmpty(t, dashboard.BudgetAlerts)
 		assert.NotEmpty(t, dashboard.FinancialGoals)
 		assert.NotNil(t, dashboard.QuickStats)
+		require.Len(t, dashboard.Watchlist, 1)
+		assert.Equal(t, "AAPL", dashboard.Watchlist[0].Symbol)
+		require.Len(t, dashboard.CategoryCaps, 1)
+		assert.Equal(t, expenseCategoryID, dashboard.CategoryCaps[0].CategoryID)
+		assert.Equal(t, domain.NewMoney(3000), dashboard.CategoryCaps[0].Cap)
 	})
 
 	t.Run("dashboard summary week", func(t *testing.T) {
-		dashboard, err := analyticsService.GetDashboardSummary(userID, "week")
+		dashboard, err := analyticsService.GetDashboardSummary(context.Background(), userID, "week")
 		assert.NoError(t, err)
 		assert.NotNil(t, dashboard)
 		assert.Equal(t, "week", dashboard.Period)
 	})
 
 	t.Run("dashboard summary year", func(t *testing.T) {
-		dashboard, err := analyticsService.GetDashboardSummary(userID, "year")
+		dashboard, err := analyticsService.GetDashboardSummary(context.Background(), userID, "year")
 		assert.NoError(t, err)
 		assert.NotNil(t, dashboard)
 		assert.Equal(t, "year", dashboard.Period)
 	})
 
 	t.Run("dashboard summary default period", func(t *testing.T) {
-		dashboard, err := analyticsService.GetDashboardSummary(userID, "invalid")
+		dashboard, err := analyticsService.GetDashboardSummary(context.Background(), userID, "invalid")
 		assert.NoError(t, err)
 		assert.NotNil(t, dashboard)
 		// Should default to month
@@ -371,7 +383,7 @@ func TestAnalyticsService_HelperMethods(t *testing.T) {
 		{
 			UserID:      userID,
 			CategoryID:  incomeCategoryID,
-			Amount:      2000.00,
+			Amount:      domain.NewMoney(2000.00),
 			Type:        "income",
 			Description: "Salary",
 			Date:        now.AddDate(0, 0, -15),
@@ -379,7 +391,7 @@ func TestAnalyticsService_HelperMethods(t *testing.T) {
 		{
 			UserID:      userID,
 			CategoryID:  expenseCategoryID,
-			Amount:      800.00,
+			Amount:      domain.NewMoney(800.00),
 			Type:        "expense",
 			Description: "Food",
 			Date:        now.AddDate(0, 0, -10),
@@ -409,7 +421,7 @@ func TestAnalyticsService_HelperMethods(t *testing.T) {
 	})
 
 	t.Run("calculate monthly trends", func(t *testing.T) {
-		trends := analyticsService.calculateMonthlyTrends(userID, startDate, endDate)
+		trends := analyticsService.calculateMonthlyTrends(context.Background(), userID, startDate, endDate)
 		assert.NotEmpty(t, trends)
 
 		// Should have at least one month
@@ -432,7 +444,7 @@ func TestAnalyticsService_HelperMethods(t *testing.T) {
 	})
 
 	t.Run("calculate weekly trends", func(t *testing.T) {
-		trends := analyticsService.calculateWeeklyTrends(userID, startDate, endDate)
+		trends := analyticsService.calculateWeeklyTrends(context.Background(), userID, startDate, endDate, time.Monday)
 		assert.NotEmpty(t, trends)
 
 		for _, trend := range trends {
@@ -443,7 +455,366 @@ func TestAnalyticsService_HelperMethods(t *testing.T) {
 	})
 
 	t.Run("calculate category trend", func(t *testing.T) {
-		trend := analyticsService.calculateCategoryTrend(userID, expenseCategoryID, startDate, endDate)
+		trend := analyticsService.calculateCategoryTrend(context.Background(), userID, expenseCategoryID, startDate, endDate)
 		assert.Contains(t, []string{"increasing", "decreasing", "stable"}, trend)
 	})
 }
+
+func TestAnalyticsService_CategoryHierarchy(t *testing.T) {
+	db := setupAnalyticsTestDB(t)
+	userID, _, foodID := createAnalyticsTestData(t, db)
+	groceries := &domain.Category{Name: "Groceries", Type: "expense", ParentID: &foodID}
+	require.NoError(t, db.Create(groceries).Error)
+
+	now := time.Now()
+	for _, tx := range []domain.Transaction{
+		{UserID: userID, CategoryID: groceries.ID, Type: "expense", Amount: domain.NewMoney(120), Date: now.AddDate(0, 0, -2)},
+		{UserID: userID, CategoryID: groceries.ID, Type: "expense", Amount: domain.NewMoney(80), Date: now.AddDate(0, 0, -1)},
+		{UserID: userID, CategoryID: foodID, Type: "expense", Amount: domain.NewMoney(50), Date: now.AddDate(0, 0, -1)},
+	} {
+		require.NoError(t, db.Create(&tx).Error)
+	}
+
+	service := &AnalyticsService{DB: db}
+	ctx := context.Background()
+	start, end := now.AddDate(0, -1, 0), now
+
+	t.Run("breakdown rolls children into the parent", func(t *testing.T) {
+		metrics, err := service.GetFinancialMetrics(ctx, userID, "monthly", start, end)
+		require.NoError(t, err)
+		require.Len(t, metrics.CategoryBreakdown, 1)
+
+		food := metrics.CategoryBreakdown[0]
+		assert.Equal(t, "Food", food.CategoryName)
+		assert.InDelta(t, 250, food.TotalAmount, 0.001)
+		assert.Equal(t, 3, food.TransactionCount)
+		require.Len(t, food.Subcategories, 1)
+		assert.InDelta(t, 200, food.Subcategories[0].TotalAmount, 0.001)
+	})
+
+	t.Run("category analysis includes children", func(t *testing.T) {
+		analysis, err := service.GetCategoryAnalysis(ctx, userID, foodID, start, end)
+		require.NoError(t, err)
+		assert.Equal(t, "Food", analysis.CategoryName)
+		assert.InDelta(t, 250, analysis.TotalAmount, 0.001)
+		require.Len(t, analysis.Subcategories, 1)
+		assert.Equal(t, "Groceries", analysis.Subcategories[0].CategoryName)
+		assert.InDelta(t, 80, analysis.Subcategories[0].PercentageOfTotal, 0.001)
+	})
+}
+
+func TestAnalyticsService_GroupedAggregation(t *testing.T) {
+	db := setupAnalyticsTestDB(t)
+	userID, incomeID, expenseID := createAnalyticsTestData(t, db)
+	analyticsService := NewAnalyticsService(db)
+	ctx := context.Background()
+
+	day := func(month time.Month, d int) time.Time { return time.Date(2024, month, d, 12, 0, 0, 0, time.UTC) }
+	transactions := []domain.Transaction{
+		{UserID: userID, CategoryID: incomeID, Type: "income", Amount: domain.NewMoney(3000), Date: day(time.January, 1)},
+		{UserID: userID, CategoryID: expenseID, Type: "expense", Amount: domain.NewMoney(200), Date: day(time.January, 31)},
+		{UserID: userID, CategoryID: expenseID, Type: "expense", Amount: domain.NewMoney(100), Date: day(time.February, 5)},
+		{UserID: userID, CategoryID: incomeID, Type: "income", Amount: domain.NewMoney(1000), Date: day(time.March, 15)},
+		{UserID: userID + 1, CategoryID: expenseID, Type: "expense", Amount: domain.NewMoney(999), Date: day(time.February, 5)},
+	}
+	require.NoError(t, db.Create(&transactions).Error)
+	deleted := domain.Transaction{UserID: userID, CategoryID: expenseID, Type: "expense", Amount: domain.NewMoney(500), Date: day(time.February, 6)}
+	require.NoError(t, db.Create(&deleted).Error)
+	require.NoError(t, db.Delete(&deleted).Error)
+
+	startDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2024, 3, 31, 23, 59, 59, 0, time.UTC)
+
+	// Count the queries run, including those read through Scan
+	var queries int
+	countQuery := func(*gorm.DB) { queries++ }
+	require.NoError(t, db.Callback().Query().Before("gorm:query").Register("count_queries", countQuery))
+	require.NoError(t, db.Callback().Row().Before("gorm:row").Register("count_rows", countQuery))
+
+	t.Run("monthly trends in one query", func(t *testing.T) {
+		queries = 0
+		trends := analyticsService.calculateMonthlyTrends(ctx, userID, startDate, endDate)
+		assert.Equal(t, 1, queries)
+
+		require.Len(t, trends, 3)
+		assert.Equal(t, "January", trends[0].Month)
+		assert.Equal(t, 3000.0, trends[0].Income)
+		assert.Equal(t, 200.0, trends[0].Expenses) // The last day of the month counts
+		assert.Equal(t, 100.0, trends[1].Expenses) // Other users and deleted transactions don't
+		assert.Equal(t, 1000.0, trends[2].NetIncome)
+	})
+
+	t.Run("weekly trends in one query", func(t *testing.T) {
+		queries = 0
+		trends := analyticsService.calculateWeeklyTrends(ctx, userID, startDate, endDate, time.Monday)
+		assert.Equal(t, 1, queries)
+
+		total, count := 0.0, 0
+		for _, trend := range trends {
+			total += trend.Income - trend.Expenses
+			count += trend.TransactionCount
+		}
+		assert.Equal(t, 3700.0, total)
+		assert.Equal(t, 4, count)
+		assert.Equal(t, 3000.0, trends[0].Income)
+	})
+
+	t.Run("financial metrics from grouped totals", func(t *testing.T) {
+		metrics, err := analyticsService.GetFinancialMetrics(ctx, userID, "quarter", startDate, endDate)
+		require.NoError(t, err)
+
+		assert.Equal(t, 4000.0, metrics.TotalIncome)
+		assert.Equal(t, 300.0, metrics.TotalExpenses)
+		require.Len(t, metrics.CategoryBreakdown, 2)
+		assert.Equal(t, "Salary", metrics.CategoryBreakdown[0].CategoryName)
+		assert.Equal(t, 2, metrics.CategoryBreakdown[0].TransactionCount)
+		assert.Equal(t, 2000.0, metrics.CategoryBreakdown[0].AverageAmount)
+		assert.InDelta(t, 300.0/4300.0*100, metrics.CategoryBreakdown[1].PercentageOfTotal, 0.001)
+	})
+}
+
+func TestAnalyticsService_UncategorizedBreakdown(t *testing.T) {
+	db := setupAnalyticsTestDB(t)
+	userID, _, expenseID := createAnalyticsTestData(t, db)
+	analyticsService := NewAnalyticsService(db)
+	ctx := context.Background()
+	uncategorized, err := uncategorizedCategory(ctx, db)
+	require.NoError(t, err)
+
+	date := time.Date(2024, time.May, 10, 12, 0, 0, 0, time.UTC)
+	transactions := []domain.Transaction{
+		{UserID: userID, CategoryID: expenseID, Type: "expense", Amount: domain.NewMoney(300), Date: date},
+		{UserID: userID, CategoryID: uncategorized.ID, Type: "expense", Amount: domain.NewMoney(200), Date: date},
+		{UserID: userID, CategoryID: 999, Type: "expense", Amount: domain.NewMoney(100), Date: date}, // Category is gone
+	}
+	require.NoError(t, db.Create(&transactions).Error)
+	start, end := date.AddDate(0, 0, -1), date.AddDate(0, 0, 1)
+
+	flagged := func(breakdown []domain.CategoryMetrics) map[uint]bool {
+		flags := map[uint]bool{}
+		for _, metrics := range breakdown {
+			flags[metrics.CategoryID] = metrics.Uncategorized
+		}
+		return flags
+	}
+	want := map[uint]bool{expenseID: false, uncategorized.ID: true, 999: true}
+
+	metrics, err := analyticsService.GetFinancialMetrics(ctx, userID, "custom", start, end)
+	require.NoError(t, err)
+	assert.Equal(t, want, flagged(metrics.CategoryBreakdown))
+
+	analysis, err := analyticsService.GetIncomeExpenseAnalysis(ctx, userID, "custom", start, end)
+	require.NoError(t, err)
+	assert.Equal(t, want, flagged(analysis.ExpenseBreakdown))
+}
+
+func TestAnalyticsService_ResultCache(t *testing.T) {
+	db := setupAnalyticsTestDB(t)
+	userID, incomeID, expenseID := createAnalyticsTestData(t, db)
+	cache := NewMemoryCache()
+	analyticsService := &AnalyticsService{DB: db, Cache: cache, CacheTTL: time.Minute}
+	txService := &TransactionService{DB: db, Cache: cache}
+	budgetService := &BudgetService{DB: db, Cache: cache}
+	ctx := context.Background()
+
+	startDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC)
+	income := domain.Transaction{UserID: userID, CategoryID: incomeID, Type: "income", Description: "Salary", Amount: domain.NewMoney(3000), Date: startDate.AddDate(0, 0, 4)}
+	require.NoError(t, txService.Create(ctx, &income))
+
+	metrics, err := analyticsService.GetFinancialMetrics(ctx, userID, "month", startDate, endDate)
+	require.NoError(t, err)
+	assert.Equal(t, 3000.0, metrics.TotalIncome)
+
+	t.Run("serves the cached result", func(t *testing.T) {
+		// Written behind the services' back, so nothing invalidates the cache
+		direct := domain.Transaction{UserID: userID, CategoryID: expenseID, Type: "expense", Amount: domain.NewMoney(100), Date: startDate.AddDate(0, 0, 9)}
+		require.NoError(t, db.Create(&direct).Error)
+
+		cached, err := analyticsService.GetFinancialMetrics(ctx, userID, "month", startDate, endDate)
+		require.NoError(t, err)
+		assert.Equal(t, 0.0, cached.TotalExpenses)
+
+		other, err := analyticsService.GetFinancialMetrics(ctx, userID, "month", startDate, endDate.AddDate(0, 0, -1))
+		require.NoError(t, err)
+		assert.Equal(t, 100.0, other.TotalExpenses, "a different period is cached separately")
+	})
+
+	t.Run("transaction changes invalidate the user's results", func(t *testing.T) {
+		expense := domain.Transaction{UserID: userID, CategoryID: expenseID, Type: "expense", Description: "Groceries", Amount: domain.NewMoney(50), Date: startDate.AddDate(0, 0, 14)}
+		require.NoError(t, txService.Create(ctx, &expense))
+
+		fresh, err := analyticsService.GetFinancialMetrics(ctx, userID, "month", startDate, endDate)
+		require.NoError(t, err)
+		assert.Equal(t, 150.0, fresh.TotalExpenses)
+
+		require.NoError(t, txService.Delete(ctx, expense.ID))
+		fresh, err = analyticsService.GetFinancialMetrics(ctx, userID, "month", startDate, endDate)
+		require.NoError(t, err)
+		assert.Equal(t, 100.0, fresh.TotalExpenses)
+	})
+
+	t.Run("budget changes invalidate the user's results", func(t *testing.T) {
+		_, ok := cache.Get(ctx, userID, metricsCacheKey("month", startDate, endDate))
+		require.True(t, ok)
+
+		budget := domain.Budget{UserID: userID, CategoryID: expenseID, Amount: domain.NewMoney(500), StartDate: startDate, EndDate: endDate}
+		require.NoError(t, budgetService.CreateBudget(ctx, &budget))
+
+		_, ok = cache.Get(ctx, userID, metricsCacheKey("month", startDate, endDate))
+		assert.False(t, ok)
+	})
+
+	t.Run("results expire", func(t *testing.T) {
+		cache.Set(ctx, userID, "stale", []byte(`{}`), -time.Second)
+		_, ok := cache.Get(ctx, userID, "stale")
+		assert.False(t, ok)
+	})
+}
+
+func TestAnalyticsService_GetIncomeStability(t *testing.T) {
+	db := setupAnalyticsTestDB(t)
+	userID, incomeID, expenseID := createAnalyticsTestData(t, db)
+	analyticsService := NewAnalyticsService(db)
+	ctx := context.Background()
+
+	now := time.Now()
+	currentMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	monthsAgo := func(n int) time.Time { return currentMonth.AddDate(0, -n, 10) }
+	transactions := []domain.Transaction{
+		{UserID: userID, CategoryID: incomeID, Type: "income", Amount: domain.NewMoney(4000), Date: monthsAgo(3)},
+		{UserID: userID, CategoryID: expenseID, Type: "expense", Amount: domain.NewMoney(1500), Date: monthsAgo(3)},
+		{UserID: userID, CategoryID: expenseID, Type: "expense", Amount: domain.NewMoney(1500), Date: monthsAgo(2)},
+		{UserID: userID, CategoryID: incomeID, Type: "income", Amount: domain.NewMoney(2000), Date: monthsAgo(1)},
+		{UserID: userID, CategoryID: expenseID, Type: "expense", Amount: domain.NewMoney(1500), Date: monthsAgo(1)},
+		{UserID: userID, CategoryID: incomeID, Type: "income", Amount: domain.NewMoney(9000), Date: currentMonth},
+		{UserID: userID + 1, CategoryID: incomeID, Type: "income", Amount: domain.NewMoney(999), Date: monthsAgo(2)},
+	}
+	require.NoError(t, db.Create(&transactions).Error)
+	require.NoError(t, db.Create(&domain.Account{UserID: userID, Name: "Checking", Type: "checking", OpeningBalance: domain.NewMoney(500)}).Error)
+
+	t.Run("complete months since the first transaction", func(t *testing.T) {
+		stability, err := analyticsService.GetIncomeStability(ctx, userID, 6)
+		require.NoError(t, err)
+
+		require.Len(t, stability.Months, 3) // Earlier empty months and the current month are left out
+		assert.Equal(t, monthsAgo(3).Year(), stability.Months[0].Year)
+		assert.Equal(t, int(monthsAgo(3).Month()), stability.Months[0].Month)
+		assert.Equal(t, domain.NewMoney(0), stability.Months[1].Income)
+		assert.Equal(t, domain.NewMoney(2000), stability.Average3Month)
+		assert.Equal(t, domain.NewMoney(1500), stability.AverageExpenses)
+		assert.Equal(t, domain.NewMoney(0), stability.RecommendedSalary)
+		assert.Equal(t, domain.IncomeIrregular, stability.Stability)
+
+		// The opening balance plus every transaction, this month's included
+		assert.Equal(t, domain.NewMoney(500+4000+2000+9000-4500), stability.Buffer)
+		assert.Equal(t, 7.3, stability.BufferMonths)
+	})
+
+	t.Run("rejects a lookback out of bounds", func(t *testing.T) {
+		_, err := analyticsService.GetIncomeStability(ctx, userID, 2)
+		var validationErr *domain.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+}
+
+func TestAnalyticsService_GetIncomeBreakdown(t *testing.T) {
+	db := setupAnalyticsTestDB(t)
+	userID, incomeID, _ := createAnalyticsTestData(t, db)
+	analyticsService := NewAnalyticsService(db)
+	ctx := context.Background()
+
+	acme := &domain.IncomeSource{
+		UserID: userID, Employer: "Acme", Gross: domain.NewMoney(5000), Frequency: domain.PayFrequencyMonthly, Active: true,
+		Deductions: []domain.PaycheckDeduction{{Name: "Income tax", Kind: domain.DeductionTax, Amount: domain.NewMoney(1000)}},
+	}
+	require.NoError(t, db.Create(acme).Error)
+	former := &domain.IncomeSource{UserID: userID, Employer: "Former", Gross: domain.NewMoney(100), Frequency: domain.PayFrequencyWeekly}
+	require.NoError(t, db.Create(former).Error)
+	require.NoError(t, db.Model(former).Update("active", false).Error)
+
+	now := time.Now()
+	currentMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	monthsAgo := func(n int) time.Time { return currentMonth.AddDate(0, -n, 10) }
+	transactions := []domain.Transaction{
+		{UserID: userID, CategoryID: incomeID, Type: "income", Amount: domain.NewMoney(4000), Date: monthsAgo(2), IncomeSourceID: &acme.ID},
+		{UserID: userID, CategoryID: incomeID, Type: "income", Amount: domain.NewMoney(4000), Date: monthsAgo(1), IncomeSourceID: &acme.ID},
+		{UserID: userID, CategoryID: incomeID, Type: "income", Amount: domain.NewMoney(250), Date: monthsAgo(1)},
+		{UserID: userID, CategoryID: incomeID, Type: "income", Amount: domain.NewMoney(4000), Date: currentMonth, IncomeSourceID: &acme.ID},
+	}
+	require.NoError(t, db.Create(&transactions).Error)
+
+	t.Run("gross against net pay by source", func(t *testing.T) {
+		breakdown, err := analyticsService.GetIncomeBreakdown(ctx, userID, 3)
+		require.NoError(t, err)
+
+		require.Len(t, breakdown.Sources, 1) // The inactive source paid nothing
+		source := breakdown.Sources[0]
+		assert.Equal(t, "Acme", source.Employer)
+		require.Len(t, source.Months, 3)
+		assert.Equal(t, 0, source.Months[0].Paychecks)
+		assert.Equal(t, 2, source.Paychecks) // This month's pay is left out
+		assert.Equal(t, domain.NewMoney(10000), breakdown.Gross)
+		assert.Equal(t, domain.NewMoney(8000), breakdown.Net)
+		assert.Equal(t, domain.NewMoney(2000), breakdown.Taxes)
+		assert.Equal(t, 20.0, breakdown.EffectiveTaxRate)
+		assert.Equal(t, domain.NewMoney(250), breakdown.UnlinkedIncome)
+	})
+
+	t.Run("rejects a lookback out of bounds", func(t *testing.T) {
+		_, err := analyticsService.GetIncomeBreakdown(ctx, userID, 0)
+		var validationErr *domain.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+}
+
+func TestAnalyticsService_GetCategoryTrend(t *testing.T) {
+	db := setupAnalyticsTestDB(t)
+	userID, incomeID, expenseID := createAnalyticsTestData(t, db)
+	groceries := &domain.Category{Name: "Groceries", Type: "expense", ParentID: &expenseID}
+	require.NoError(t, db.Create(groceries).Error)
+	analyticsService := NewAnalyticsService(db)
+	ctx := context.Background()
+
+	now := time.Now()
+	currentMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	monthsAgo := func(n int) time.Time { return currentMonth.AddDate(0, -n, 10) }
+	transactions := []domain.Transaction{
+		{UserID: userID, CategoryID: expenseID, Type: "expense", Amount: domain.NewMoney(100), Date: monthsAgo(3)},
+		{UserID: userID, CategoryID: groceries.ID, Type: "expense", Amount: domain.NewMoney(100), Date: monthsAgo(2)},
+		{UserID: userID, CategoryID: expenseID, Type: "expense", Amount: domain.NewMoney(100), Date: monthsAgo(2)},
+		{UserID: userID, CategoryID: expenseID, Type: "expense", Amount: domain.NewMoney(300), Date: monthsAgo(1)},
+		{UserID: userID, CategoryID: expenseID, Type: "expense", Amount: domain.NewMoney(999), Date: currentMonth},
+		{UserID: userID, CategoryID: incomeID, Type: "income", Amount: domain.NewMoney(999), Date: monthsAgo(2)},
+		{UserID: userID + 1, CategoryID: expenseID, Type: "expense", Amount: domain.NewMoney(999), Date: monthsAgo(2)},
+	}
+	require.NoError(t, db.Create(&transactions).Error)
+
+	t.Run("complete months including nested categories", func(t *testing.T) {
+		trend, err := analyticsService.GetCategoryTrend(ctx, userID, expenseID, 3)
+		require.NoError(t, err)
+
+		assert.Equal(t, "Food", trend.CategoryName)
+		require.Len(t, trend.Months, 3) // The current month is projected, not listed
+		assert.Equal(t, int(monthsAgo(3).Month()), trend.Months[0].Month)
+		assert.Equal(t, domain.NewMoney(200), trend.Months[1].Amount)
+		assert.Equal(t, 2, trend.Months[1].TransactionCount)
+		require.NotNil(t, trend.Months[2].PercentChange)
+		assert.Equal(t, 50.0, *trend.Months[2].PercentChange)
+		assert.Equal(t, domain.NewMoney(100), trend.Slope)
+		assert.Equal(t, domain.NewMoney(400), trend.Projection)
+		assert.Equal(t, int(currentMonth.Month()), trend.ProjectionMonth)
+		assert.Equal(t, domain.TrendIncreasing, trend.Trend)
+	})
+
+	t.Run("rejects a lookback out of bounds", func(t *testing.T) {
+		_, err := analyticsService.GetCategoryTrend(ctx, userID, expenseID, 1)
+		var validationErr *domain.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+
+	t.Run("reports missing categories", func(t *testing.T) {
+		_, err := analyticsService.GetCategoryTrend(ctx, userID, 999, 3)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
@@ -0,0 +1,339 @@
+package application
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"sort"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// archiveBatchSize is how many transactions one archiving step moves, so a
+// first run over years of data does not hold one huge database transaction
+const archiveBatchSize = 500
+
+// ArchiveService applies the data retention policy to transactions. Those
+// dated more than ArchiveAfterYears years ago are moved, with their tags and
+// line items, into one compressed archive per user and year; 0 years turns
+// archiving off. Archived transactions no longer show up in lists, budgets
+// or analytics but users can still read them through Archived, which
+// unpacks the archives and is slower. Archiving on an account adds the
+// transactions to its opening balance, so statements keep their balances.
+// TrashPeriod is only reported by Status; TransactionService purges the
+// trash.
+type ArchiveService struct {
+	DB                *gorm.DB
+	ArchiveAfterYears int
+	TrashPeriod       time.Duration
+}
+
+func NewArchiveService(db *gorm.DB, archiveAfterYears int, trashPeriod time.Duration) *ArchiveService {
+	return &ArchiveService{DB: db, ArchiveAfterYears: archiveAfterYears, TrashPeriod: trashPeriod}
+}
+
+// cutoff is the day transactions must be dated before to be archived
+func (s *ArchiveService) cutoff() time.Time {
+	return quotaDay(time.Now()).AddDate(-s.ArchiveAfterYears, 0, 0)
+}
+
+// Archive moves every transaction of all users dated before the cutoff into
+// the archives and returns how many it moved. Transactions in the trash are
+// left to the trash purge.
+func (s *ArchiveService) Archive(ctx context.Context) (int64, error) {
+	if s.ArchiveAfterYears <= 0 {
+		return 0, nil
+	}
+	ctx = domain.ContextWithoutUserScope(ctx)
+	cutoff := s.cutoff()
+
+	var archived int64
+	for {
+		moved, err := s.archiveBatch(ctx, cutoff)
+		archived += int64(moved)
+		if err != nil || moved < archiveBatchSize {
+			return archived, err
+		}
+	}
+}
+
+// archiveBatch moves the oldest transactions dated before cutoff, at most
+// archiveBatchSize, in one database transaction
+func (s *ArchiveService) archiveBatch(ctx context.Context, cutoff time.Time) (int, error) {
+	var moved int
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var transactions []domain.Transaction
+		err := tx.Where("date < ?", cutoff).Order("user_id, date, id").Limit(archiveBatchSize).Find(&transactions).Error
+		if err != nil || len(transactions) == 0 {
+			return err
+		}
+		if err := loadTransactionTags(ctx, tx, transactions); err != nil {
+			return err
+		}
+		if err := loadTransactionItems(ctx, tx, transactions); err != nil {
+			return err
+		}
+
+		type archiveKey struct {
+			userID uint
+			year   int
+		}
+		groups := map[archiveKey][]domain.Transaction{}
+		accounts := map[uint]domain.Money{}
+		for _, transaction := range transactions {
+			key := archiveKey{userID: transaction.UserID, year: transaction.Date.Year()}
+			groups[key] = append(groups[key], transaction)
+			if transaction.AccountID != nil {
+				accounts[*transaction.AccountID] += transaction.SignedFor(*transaction.AccountID)
+			}
+			if transaction.TransferAccountID != nil {
+				accounts[*transaction.TransferAccountID] += transaction.SignedFor(*transaction.TransferAccountID)
+			}
+		}
+		for key, group := range groups {
+			if err := addToArchive(tx, key.userID, key.year, group); err != nil {
+				return err
+			}
+		}
+		for accountID, amount := range accounts {
+			err := tx.Model(&domain.Account{}).Where("id = ?", accountID).
+				Update("opening_balance", gorm.Expr("opening_balance + ?", amount)).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		ids := transactionIDs(transactions)
+		for _, detail := range []any{&domain.TransactionTag{}, &domain.TransactionItem{}} {
+			if err := tx.Where("transaction_id IN ?", ids).Delete(detail).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Unscoped().Where("id IN ?", ids).Delete(&domain.Transaction{}).Error; err != nil {
+			return err
+		}
+		moved = len(transactions)
+		return nil
+	})
+	return moved, err
+}
+
+// addToArchive adds transactions to the user's archive of the year,
+// creating it when there is none yet
+func addToArchive(tx *gorm.DB, userID uint, year int, transactions []domain.Transaction) error {
+	archive := domain.TransactionArchive{UserID: userID, Year: year}
+	err := tx.Where("user_id = ? AND year = ?", userID, year).Limit(1).Find(&archive).Error
+	if err != nil {
+		return err
+	}
+	if archive.ID != 0 {
+		stored, err := unpackTransactions(archive.Data)
+		if err != nil {
+			return err
+		}
+		transactions = append(stored, transactions...)
+	}
+	sort.SliceStable(transactions, func(i, j int) bool {
+		if !transactions[i].Date.Equal(transactions[j].Date) {
+			return transactions[i].Date.Before(transactions[j].Date)
+		}
+		return transactions[i].ID < transactions[j].ID
+	})
+
+	data, err := packTransactions(transactions)
+	if err != nil {
+		return err
+	}
+	archive.Data = data
+	archive.Size = len(data)
+	archive.Transactions = len(transactions)
+	archive.FromDate = transactions[0].Date
+	archive.ToDate = transactions[len(transactions)-1].Date
+	return tx.Save(&archive).Error
+}
+
+// packTransactions compresses the JSON of the transactions. The category
+// is left out; readers look it up again.
+func packTransactions(transactions []domain.Transaction) ([]byte, error) {
+	for i := range transactions {
+		transactions[i].Category = domain.Category{}
+	}
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(writer).Encode(transactions); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func unpackTransactions(data []byte) ([]domain.Transaction, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	raw, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	var transactions []domain.Transaction
+	if err := json.Unmarshal(raw, &transactions); err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+// Archived returns the user's archived transactions in the filter's dates,
+// newest first, with their categories as far as they still exist
+func (s *ArchiveService) Archived(ctx context.Context, userID uint, filter domain.ArchiveFilter) ([]domain.Transaction, error) {
+	if filter.From != nil && filter.To != nil && filter.From.After(*filter.To) {
+		return nil, &domain.ValidationError{Fields: []domain.FieldError{{Field: "from", Message: "must not be after to"}}}
+	}
+	query := s.DB.WithContext(ctx).Where("user_id = ?", userID)
+	if filter.From != nil {
+		query = query.Where("year >= ?", filter.From.Year())
+	}
+	if filter.To != nil {
+		query = query.Where("year <= ?", filter.To.Year())
+	}
+	var archives []domain.TransactionArchive
+	if err := query.Order("year").Find(&archives).Error; err != nil {
+		return nil, err
+	}
+
+	transactions := []domain.Transaction{}
+	for _, archive := range archives {
+		stored, err := unpackTransactions(archive.Data)
+		if err != nil {
+			return nil, err
+		}
+		for _, transaction := range stored {
+			if filter.From != nil && transaction.Date.Before(quotaDay(*filter.From)) {
+				continue
+			}
+			if filter.To != nil && !transaction.Date.Before(quotaDay(*filter.To).Add(24*time.Hour)) {
+				continue
+			}
+			transactions = append(transactions, transaction)
+		}
+	}
+	sort.SliceStable(transactions, func(i, j int) bool {
+		if !transactions[i].Date.Equal(transactions[j].Date) {
+			return transactions[i].Date.After(transactions[j].Date)
+		}
+		return transactions[i].ID > transactions[j].ID
+	})
+
+	if len(transactions) == 0 {
+		return transactions, nil
+	}
+	categoryIDs := make([]uint, 0, len(transactions))
+	for _, transaction := range transactions {
+		categoryIDs = append(categoryIDs, transaction.CategoryID)
+	}
+	var categories []domain.Category
+	if err := s.DB.WithContext(ctx).Where("id IN ?", categoryIDs).Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]domain.Category, len(categories))
+	for _, category := range categories {
+		byID[category.ID] = category
+	}
+	for i := range transactions {
+		transactions[i].Category = byID[transactions[i].CategoryID]
+	}
+	return transactions, nil
+}
+
+// Status reports the archives of all users, the transactions the next run
+// archives and the trash waiting to be purged
+func (s *ArchiveService) Status(ctx context.Context) (*domain.ArchiveStatus, error) {
+	db := s.DB.WithContext(domain.ContextWithoutUserScope(ctx))
+	status := &domain.ArchiveStatus{
+		ArchiveAfterYears:  s.ArchiveAfterYears,
+		TrashRetentionDays: int(s.TrashPeriod / (24 * time.Hour)),
+		Years:              []domain.ArchiveYearStatus{},
+	}
+
+	err := db.Model(&domain.TransactionArchive{}).
+		Select("year, COUNT(DISTINCT user_id) AS users, COALESCE(SUM(transactions), 0) AS transactions, " +
+			"COALESCE(SUM(size), 0) AS compressed_bytes").
+		Group("year").Order("year").Scan(&status.Years).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, year := range status.Years {
+		status.Transactions += year.Transactions
+		status.CompressedBytes += year.CompressedBytes
+	}
+
+	var archives []domain.TransactionArchive
+	if err := db.Select("user_id", "updated_at").Find(&archives).Error; err != nil {
+		return nil, err
+	}
+	users := map[uint]bool{}
+	for _, archive := range archives {
+		users[archive.UserID] = true
+		if status.LastArchivedAt == nil || archive.UpdatedAt.After(*status.LastArchivedAt) {
+			updatedAt := archive.UpdatedAt
+			status.LastArchivedAt = &updatedAt
+		}
+	}
+	status.Archives, status.Users = len(archives), len(users)
+
+	counts := []struct {
+		target *int
+		query  *gorm.DB
+	}{
+		{&status.TrashedTransactions, db.Unscoped().Model(&domain.Transaction{}).Where("deleted_at IS NOT NULL")},
+		{&status.ExpiredTrash, db.Unscoped().Model(&domain.Transaction{}).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", time.Now().Add(-s.TrashPeriod))},
+	}
+	for _, count := range counts {
+		var n int64
+		if err := count.query.Count(&n).Error; err != nil {
+			return nil, err
+		}
+		*count.target = int(n)
+	}
+
+	if s.ArchiveAfterYears > 0 {
+		cutoff := s.cutoff()
+		status.Cutoff = &cutoff
+		var pending int64
+		if err := db.Model(&domain.Transaction{}).Where("date < ?", cutoff).Count(&pending).Error; err != nil {
+			return nil, err
+		}
+		status.PendingTransactions = int(pending)
+	}
+	return status, nil
+}
+
+// StartArchiving runs Archive on the given interval until ctx is cancelled
+func (s *ArchiveService) StartArchiving(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if archived, err := s.Archive(ctx); err != nil {
+			log.Printf("transaction archiving failed: %v", err)
+		} else if archived > 0 {
+			log.Printf("archived %d transaction(s)", archived)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
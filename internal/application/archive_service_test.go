@@ -0,0 +1,141 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestArchiveService(t *testing.T) {
+	db := setupQuotaTestDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.Account{}, &domain.TransactionArchive{}))
+	service := NewArchiveService(db, 7, 30*24*time.Hour)
+	ctx := context.Background()
+
+	user := domain.User{Email: "archive@example.com", Password: "x"}
+	require.NoError(t, db.Create(&user).Error)
+	category := domain.Category{UserID: &user.ID, Name: "Rent", Type: domain.TransactionTypeExpense}
+	require.NoError(t, db.Create(&category).Error)
+	account := domain.Account{UserID: user.ID, Name: "Main", Type: domain.AccountTypeChecking, OpeningBalance: 1000}
+	require.NoError(t, db.Create(&account).Error)
+
+	add := func(date time.Time, amount domain.Money) domain.Transaction {
+		transaction := domain.Transaction{
+			UserID: user.ID, CategoryID: category.ID, AccountID: &account.ID, Type: domain.TransactionTypeExpense,
+			Amount: amount, Description: "Rent", Date: date,
+		}
+		require.NoError(t, db.Create(&transaction).Error)
+		return transaction
+	}
+	year := time.Now().Year() - 10
+	old := add(time.Date(year, time.March, 1, 0, 0, 0, 0, time.UTC), 500)
+	require.NoError(t, db.Create(&domain.TransactionTag{TransactionID: old.ID, Tag: "home"}).Error)
+	add(time.Date(year, time.April, 1, 0, 0, 0, 0, time.UTC), 600)
+	trashed := add(time.Date(year, time.May, 1, 0, 0, 0, 0, time.UTC), 700)
+	require.NoError(t, db.Delete(&trashed).Error)
+	recent := add(time.Now().AddDate(0, -1, 0), 800)
+
+	t.Run("should report what the next run archives", func(t *testing.T) {
+		status, err := service.Status(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, status.PendingTransactions)
+		assert.Equal(t, 1, status.TrashedTransactions)
+		assert.Zero(t, status.ExpiredTrash)
+		assert.Equal(t, 30, status.TrashRetentionDays)
+		require.NotNil(t, status.Cutoff)
+		assert.Empty(t, status.Years)
+	})
+
+	t.Run("should move old transactions into the archive of their year", func(t *testing.T) {
+		archived, err := service.Archive(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), archived)
+
+		var remaining []domain.Transaction
+		require.NoError(t, db.Unscoped().Order("id").Find(&remaining).Error)
+		require.Len(t, remaining, 2, "trashed transactions are left to the purge")
+		assert.Equal(t, []uint{trashed.ID, recent.ID}, transactionIDs(remaining))
+		var tags int64
+		require.NoError(t, db.Model(&domain.TransactionTag{}).Count(&tags).Error)
+		assert.Zero(t, tags)
+
+		require.NoError(t, db.First(&account, account.ID).Error)
+		assert.Equal(t, domain.Money(-100), account.OpeningBalance, "archived amounts move into the opening balance")
+
+		archived, err = service.Archive(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, archived)
+	})
+
+	t.Run("should add later transactions to an existing archive", func(t *testing.T) {
+		add(time.Date(year, time.January, 15, 0, 0, 0, 0, time.UTC), 50)
+		_, err := service.Archive(ctx)
+		require.NoError(t, err)
+
+		var archives []domain.TransactionArchive
+		require.NoError(t, db.Find(&archives).Error)
+		require.Len(t, archives, 1)
+		assert.Equal(t, year, archives[0].Year)
+		assert.Equal(t, 3, archives[0].Transactions)
+		assert.Equal(t, len(archives[0].Data), archives[0].Size)
+		assert.Equal(t, time.Date(year, time.January, 15, 0, 0, 0, 0, time.UTC), archives[0].FromDate.UTC())
+	})
+
+	t.Run("should read archived transactions back by date", func(t *testing.T) {
+		transactions, err := service.Archived(ctx, user.ID, domain.ArchiveFilter{})
+		require.NoError(t, err)
+		require.Len(t, transactions, 3)
+		assert.Equal(t, domain.Money(600), transactions[0].Amount, "newest first")
+		assert.Equal(t, "Rent", transactions[0].Category.Name)
+		assert.Equal(t, []string{"home"}, transactions[1].Tags)
+
+		from := time.Date(year, time.March, 1, 0, 0, 0, 0, time.UTC)
+		transactions, err = service.Archived(ctx, user.ID, domain.ArchiveFilter{From: &from, To: &from})
+		require.NoError(t, err)
+		require.Len(t, transactions, 1)
+		assert.Equal(t, old.ID, transactions[0].ID)
+
+		transactions, err = service.Archived(ctx, user.ID+1, domain.ArchiveFilter{})
+		require.NoError(t, err)
+		assert.Empty(t, transactions)
+
+		before := from.AddDate(0, 0, -1)
+		_, err = service.Archived(ctx, user.ID, domain.ArchiveFilter{From: &from, To: &before})
+		assert.ErrorIs(t, err, domain.ErrValidation)
+	})
+
+	t.Run("should sum the archives by year", func(t *testing.T) {
+		require.NoError(t, db.Unscoped().Model(&trashed).
+			UpdateColumn("deleted_at", gorm.DeletedAt{Time: time.Now().AddDate(0, -2, 0), Valid: true}).Error)
+
+		status, err := service.Status(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, status.Archives)
+		assert.Equal(t, 1, status.Users)
+		assert.Equal(t, 3, status.Transactions)
+		assert.Zero(t, status.PendingTransactions)
+		assert.Equal(t, 1, status.ExpiredTrash)
+		require.NotNil(t, status.LastArchivedAt)
+		require.Len(t, status.Years, 1)
+		assert.Equal(t, domain.ArchiveYearStatus{Year: year, Users: 1, Transactions: 3, CompressedBytes: status.CompressedBytes},
+			status.Years[0])
+	})
+
+	t.Run("should leave transactions in place when archiving is off", func(t *testing.T) {
+		add(time.Date(year, time.June, 1, 0, 0, 0, 0, time.UTC), 10)
+		off := NewArchiveService(db, 0, 30*24*time.Hour)
+		archived, err := off.Archive(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, archived)
+		status, err := off.Status(ctx)
+		require.NoError(t, err)
+		assert.Nil(t, status.Cutoff)
+		assert.Zero(t, status.PendingTransactions)
+	})
+}
@@ -0,0 +1,147 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/pkg"
+
+	"gorm.io/gorm"
+)
+
+// assistantRankingSize is how many categories or merchants ranking answers name
+const assistantRankingSize = 5
+
+// AssistantService answers questions about a user's finances asked in plain
+// language. The language model only translates a question into a query; the
+// figures come from the analytics and the answer is written from them, so the
+// model never makes up numbers.
+type AssistantService struct {
+	DB        *gorm.DB
+	LLM       pkg.LLMProvider
+	Analytics *AnalyticsService // Falls back to one over DB when nil
+}
+
+func NewAssistantService(db *gorm.DB, llm pkg.LLMProvider) *AssistantService {
+	return &AssistantService{DB: db, LLM: llm}
+}
+
+func (s *AssistantService) analytics() *AnalyticsService {
+	if s.Analytics == nil {
+		return NewAnalyticsService(s.DB)
+	}
+	return s.Analytics
+}
+
+// Ask answers the user's question
+func (s *AssistantService) Ask(ctx context.Context, userID uint, question string) (*domain.AssistantAnswer, error) {
+	question = strings.TrimSpace(question)
+	if question == "" || utf8.RuneCountInString(question) > domain.MaxAssistantQuestionLength {
+		return nil, &domain.ValidationError{Fields: []domain.FieldError{{
+			Field: "question", Message: fmt.Sprintf("must be between 1 and %d characters", domain.MaxAssistantQuestionLength),
+		}}}
+	}
+	if s.LLM == nil {
+		return nil, domain.ErrAssistantUnavailable
+	}
+
+	var categories []domain.Category
+	if err := s.DB.WithContext(ctx).Where("user_id IS NULL OR user_id = ?", userID).Order("name").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	reply, err := s.LLM.Complete(ctx, domain.AssistantInstructions(now, categoryNames(categories)), question)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrAssistantUnavailable, err)
+	}
+	query, err := domain.ParseAssistantQuery(reply, now)
+	if err != nil {
+		return nil, err
+	}
+	return s.answer(ctx, userID, question, query, categories)
+}
+
+// answer runs the query against the user's analytics
+func (s *AssistantService) answer(
+	ctx context.Context, userID uint, question string, query domain.AssistantQuery, categories []domain.Category,
+) (*domain.AssistantAnswer, error) {
+	analytics := s.analytics()
+	transactionType := domain.TransactionTypeExpense
+	if query.Kind == domain.AssistantQueryIncome {
+		transactionType = domain.TransactionTypeIncome
+	}
+
+	if query.Category != "" {
+		category := namedCategory(categories, query.Category, transactionType)
+		if category == nil {
+			return nil, fmt.Errorf("%w: there is no %s category named %q", domain.ErrAssistantUnanswerable, transactionType, query.Category)
+		}
+		metrics, err := analytics.GetCategoryAnalysis(ctx, userID, category.ID, query.From, query.End())
+		if err != nil {
+			return nil, err
+		}
+		query.Category = category.Name
+		answer := domain.NewTotalAnswer(question, query, roundCents(metrics.TotalAmount), metrics.TransactionCount)
+		return &answer, nil
+	}
+
+	if query.Kind == domain.AssistantQueryTopMerchants {
+		merchants, err := analytics.GetMerchantAnalysis(ctx, userID, query.From, query.End(), assistantRankingSize)
+		if err != nil {
+			return nil, err
+		}
+		ranking := make([]domain.AssistantFigure, len(merchants.Merchants))
+		for i, merchant := range merchants.Merchants {
+			ranking[i] = domain.AssistantFigure{
+				Label: merchant.MerchantName, Value: roundCents(merchant.TotalAmount), Unit: domain.FigureUnitAmount,
+			}
+		}
+		answer := domain.NewRankingAnswer(question, query, ranking)
+		return &answer, nil
+	}
+
+	analysis, err := analytics.GetIncomeExpenseAnalysis(ctx, userID, "custom", query.From, query.End())
+	if err != nil {
+		return nil, err
+	}
+	income, incomeCount := sumCategoryMetrics(analysis.IncomeBreakdown)
+	expenses, expenseCount := sumCategoryMetrics(analysis.ExpenseBreakdown)
+
+	var answer domain.AssistantAnswer
+	switch query.Kind {
+	case domain.AssistantQueryIncome:
+		answer = domain.NewTotalAnswer(question, query, income, incomeCount)
+	case domain.AssistantQueryNet:
+		answer = domain.NewNetAnswer(question, query, income, expenses)
+	case domain.AssistantQueryTopCategories:
+		ranking := make([]domain.AssistantFigure, len(analysis.TopExpenseCategories))
+		for i, metrics := range analysis.TopExpenseCategories {
+			ranking[i] = domain.AssistantFigure{
+				Label: metrics.CategoryName, Value: roundCents(metrics.TotalAmount), Unit: domain.FigureUnitAmount,
+			}
+		}
+		answer = domain.NewRankingAnswer(question, query, ranking)
+	default:
+		answer = domain.NewTotalAnswer(question, query, expenses, expenseCount)
+	}
+	return &answer, nil
+}
+
+// sumCategoryMetrics totals top-level category metrics, whose amounts
+// already include their subcategories
+func sumCategoryMetrics(breakdown []domain.CategoryMetrics) (total float64, count int) {
+	for _, metrics := range breakdown {
+		total += metrics.TotalAmount
+		count += metrics.TransactionCount
+	}
+	return roundCents(total), count
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,166 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// fakeLLM replies to every question with the same text and remembers the
+// instructions it was given
+type fakeLLM struct {
+	reply  string
+	err    error
+	system string
+}
+
+func (f *fakeLLM) Complete(_ context.Context, system, _ string) (string, error) {
+	f.system = system
+	return f.reply, f.err
+}
+
+// assistantHistory records a quarter of a user's income and spending
+func assistantHistory(t *testing.T, db *gorm.DB, userID uint) {
+	categories := map[string]uint{}
+	for _, category := range []domain.Category{
+		{Name: "Groceries", Type: domain.TransactionTypeExpense},
+		{Name: "Fuel", Type: domain.TransactionTypeExpense},
+		{Name: "Salary", Type: domain.TransactionTypeIncome},
+	} {
+		require.NoError(t, db.Create(&category).Error)
+		categories[category.Name] = category.ID
+	}
+
+	transactions := &TransactionService{DB: db, Merchants: NewMerchantService(db)}
+	for _, tx := range []domain.Transaction{
+		{Description: "ACME CORP PAYROLL", Type: domain.TransactionTypeIncome, CategoryID: categories["Salary"], Amount: domain.NewMoney(3000)},
+		{Description: "Corner Grocery", Type: domain.TransactionTypeExpense, CategoryID: categories["Groceries"], Amount: domain.NewMoney(120.25)},
+		{Description: "Corner Grocery", Type: domain.TransactionTypeExpense, CategoryID: categories["Groceries"], Amount: domain.NewMoney(80)},
+		{Description: "SHELL OIL 5742", Type: domain.TransactionTypeExpense, CategoryID: categories["Fuel"], Amount: domain.NewMoney(55.5)},
+	} {
+		tx.UserID, tx.Date = userID, time.Date(2024, 2, 10, 12, 0, 0, 0, time.UTC)
+		require.NoError(t, transactions.Create(context.Background(), &tx))
+	}
+	// Outside the quarter asked about
+	require.NoError(t, transactions.Create(context.Background(), &domain.Transaction{
+		UserID: userID, Description: "Corner Grocery", Type: domain.TransactionTypeExpense,
+		CategoryID: categories["Groceries"], Amount: domain.NewMoney(999), Date: time.Date(2024, 4, 2, 0, 0, 0, 0, time.UTC),
+	}))
+}
+
+func TestAssistantService_Ask(t *testing.T) {
+	db := setupAnalyticsTestDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.Merchant{}, &domain.MerchantRule{}))
+	assistantHistory(t, db, 1)
+	llm := &fakeLLM{}
+	service := NewAssistantService(db, llm)
+	ctx := context.Background()
+	quarter := `"from": "2024-01-01", "to": "2024-03-31"`
+
+	tests := []struct {
+		name        string
+		reply       string
+		wantAnswer  string
+		wantFigures []domain.AssistantFigure
+	}{
+		{
+			name:       "spending in a category, whatever its case",
+			reply:      `{"kind": "spending", "category": "groceries", ` + quarter + `}`,
+			wantAnswer: "You spent 200.25 in Groceries between 1 Jan 2024 and 31 Mar 2024 across 2 transaction(s).",
+			wantFigures: []domain.AssistantFigure{
+				{Label: "Groceries", Value: 200.25, Unit: domain.FigureUnitAmount},
+				{Label: "Transactions", Value: 2, Unit: domain.FigureUnitCount},
+			},
+		},
+		{
+			name:       "all spending",
+			reply:      `{"kind": "spending", ` + quarter + `}`,
+			wantAnswer: "You spent 255.75 between 1 Jan 2024 and 31 Mar 2024 across 3 transaction(s).",
+			wantFigures: []domain.AssistantFigure{
+				{Label: "Spending", Value: 255.75, Unit: domain.FigureUnitAmount},
+				{Label: "Transactions", Value: 3, Unit: domain.FigureUnitCount},
+			},
+		},
+		{
+			name:       "savings",
+			reply:      `{"kind": "net", ` + quarter + `}`,
+			wantAnswer: "Between 1 Jan 2024 and 31 Mar 2024 you earned 3000.00 and spent 255.75, which leaves 2744.25, a savings rate of 91.48%.",
+			wantFigures: []domain.AssistantFigure{
+				{Label: "Income", Value: 3000, Unit: domain.FigureUnitAmount},
+				{Label: "Spending", Value: 255.75, Unit: domain.FigureUnitAmount},
+				{Label: "Net", Value: 2744.25, Unit: domain.FigureUnitAmount},
+				{Label: "Savings rate", Value: 91.48, Unit: domain.FigureUnitPercent},
+			},
+		},
+		{
+			name:       "top categories",
+			reply:      `{"kind": "top_categories", ` + quarter + `}`,
+			wantAnswer: "Between 1 Jan 2024 and 31 Mar 2024 most of your money went to Groceries (200.25), Fuel (55.50).",
+			wantFigures: []domain.AssistantFigure{
+				{Label: "Groceries", Value: 200.25, Unit: domain.FigureUnitAmount},
+				{Label: "Fuel", Value: 55.5, Unit: domain.FigureUnitAmount},
+			},
+		},
+		{
+			name:       "top merchants",
+			reply:      `{"kind": "top_merchants", ` + quarter + `}`,
+			wantAnswer: "Between 1 Jan 2024 and 31 Mar 2024 most of your money was spent at Corner Grocery (200.25), Shell Oil (55.50).",
+			wantFigures: []domain.AssistantFigure{
+				{Label: "Corner Grocery", Value: 200.25, Unit: domain.FigureUnitAmount},
+				{Label: "Shell Oil", Value: 55.5, Unit: domain.FigureUnitAmount},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			llm.reply = tt.reply
+			answer, err := service.Ask(ctx, 1, "  a question  ")
+			require.NoError(t, err)
+			assert.Equal(t, "a question", answer.Question)
+			assert.Equal(t, tt.wantAnswer, answer.Answer)
+			assert.Equal(t, tt.wantFigures, answer.Figures)
+		})
+	}
+
+	t.Run("the model is told the user's categories", func(t *testing.T) {
+		llm.reply = `{"kind": "net", ` + quarter + `}`
+		_, err := service.Ask(ctx, 1, "how much did I save?")
+		require.NoError(t, err)
+		assert.Contains(t, llm.system, "Fuel, Groceries, Salary")
+	})
+
+	t.Run("unknown categories cannot be answered", func(t *testing.T) {
+		llm.reply = `{"kind": "income", "category": "Groceries", ` + quarter + `}`
+		_, err := service.Ask(ctx, 1, "how much did groceries earn me?")
+		assert.ErrorIs(t, err, domain.ErrAssistantUnanswerable)
+	})
+
+	t.Run("questions the model cannot translate", func(t *testing.T) {
+		llm.reply = `{"kind": "unknown"}`
+		_, err := service.Ask(ctx, 1, "what is the weather like?")
+		assert.ErrorIs(t, err, domain.ErrAssistantUnanswerable)
+	})
+
+	t.Run("an unreachable model", func(t *testing.T) {
+		llm.err = errors.New("connection refused")
+		defer func() { llm.err = nil }()
+		_, err := service.Ask(ctx, 1, "how much did I save?")
+		assert.ErrorIs(t, err, domain.ErrAssistantUnavailable)
+	})
+
+	t.Run("empty and overlong questions", func(t *testing.T) {
+		_, err := service.Ask(ctx, 1, "   ")
+		assert.ErrorIs(t, err, domain.ErrValidation)
+		_, err = service.Ask(ctx, 1, strings.Repeat("a", domain.MaxAssistantQuestionLength+1))
+		assert.ErrorIs(t, err, domain.ErrValidation)
+	})
+}
@@ -0,0 +1,134 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"reflect"
+
+	"go-finance-advisor/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// AuditService records and queries the audit log. Services hold an optional
+// *AuditService; a nil service records nothing, so auditing is only active
+// where it has been wired in.
+type AuditService struct {
+	DB *gorm.DB
+}
+
+func NewAuditService(db *gorm.DB) *AuditService {
+	return &AuditService{DB: db}
+}
+
+// Record stores one modification. The actor and IP address are taken from
+// ctx; before and after are marshalled to JSON and may be nil.
+func (s *AuditService) Record(
+	ctx context.Context, userID uint, entityType string, entityID uint, action string, before, after interface{},
+) error {
+	if s == nil || s.DB == nil {
+		return nil
+	}
+
+	entry := &domain.AuditLog{
+		UserID:     userID,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+	}
+	if actor, ok := domain.ActorFromContext(ctx); ok {
+		entry.ActorID = actor.UserID
+		entry.IPAddress = actor.IPAddress
+	}
+
+	var err error
+	if entry.Before, err = snapshot(before); err != nil {
+		return err
+	}
+	if entry.After, err = snapshot(after); err != nil {
+		return err
+	}
+
+	return s.DB.WithContext(ctx).Create(entry).Error
+}
+
+// track records a modification without failing the operation that made it;
+// the change has already been committed when it is audited
+func (s *AuditService) track(
+	ctx context.Context, userID uint, entityType string, entityID uint, action string, before, after interface{},
+) {
+	if err := s.Record(ctx, userID, entityType, entityID, action, before, after); err != nil {
+		log.Printf("audit: failed to record %s of %s %d: %v", action, entityType, entityID, err)
+	}
+}
+
+// Subscribe records the transaction events published on the bus
+func (s *AuditService) Subscribe(bus *EventBus) {
+	bus.Subscribe(s.handleEvent, transactionEvents...)
+}
+
+func (s *AuditService) handleEvent(ctx context.Context, event domain.Event) {
+	switch e := event.(type) {
+	case domain.TransactionCreated:
+		s.track(ctx, e.Transaction.UserID, domain.AuditEntityTransaction, e.Transaction.ID, domain.AuditActionCreate, nil, &e.Transaction)
+	case domain.TransactionUpdated:
+		s.track(ctx, e.After.UserID, domain.AuditEntityTransaction, e.After.ID, domain.AuditActionUpdate, e.Before, &e.After)
+	case domain.TransactionDeleted:
+		s.track(ctx, e.Transaction.UserID, domain.AuditEntityTransaction, e.Transaction.ID, domain.AuditActionDelete, &e.Transaction, nil)
+	case domain.TransactionRestored:
+		s.track(ctx, e.Transaction.UserID, domain.AuditEntityTransaction, e.Transaction.ID, domain.AuditActionRestore, nil, &e.Transaction)
+	}
+}
+
+// List returns the matching audit entries, newest first
+func (s *AuditService) List(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditLog, error) {
+	query := s.DB.WithContext(ctx).Model(&domain.AuditLog{})
+	if filter.UserID != 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.ActorID != 0 {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != 0 {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.Since != nil {
+		query = query.Where("created_at >= ?", *filter.Since)
+	}
+	if filter.Until != nil {
+		query = query.Where("created_at <= ?", *filter.Until)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	var entries []domain.AuditLog
+	err := query.Order("created_at DESC, id DESC").Find(&entries).Error
+	return entries, err
+}
+
+// actorUserID returns the acting user from ctx, or zero when there is none
+func actorUserID(ctx context.Context) uint {
+	actor, _ := domain.ActorFromContext(ctx)
+	return actor.UserID
+}
+
+func snapshot(value interface{}) (json.RawMessage, error) {
+	if value == nil {
+		return nil, nil
+	}
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Ptr && v.IsNil() {
+		return nil, nil
+	}
+	return json.Marshal(value)
+}
@@ -0,0 +1,90 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditService_RecordAndList(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.AuditLog{}))
+	auditSvc := NewAuditService(db)
+
+	ctx := domain.ContextWithActor(context.Background(), domain.Actor{UserID: 7, IPAddress: "10.0.0.1"})
+	require.NoError(t, auditSvc.Record(ctx, 1, domain.AuditEntityBudget, 3, domain.AuditActionCreate, nil, map[string]int{"amount": 100}))
+	require.NoError(t, auditSvc.Record(context.Background(), 2, domain.AuditEntityCategory, 4, domain.AuditActionDelete, nil, nil))
+
+	entries, err := auditSvc.List(context.Background(), domain.AuditFilter{UserID: 1})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, uint(7), entries[0].ActorID)
+	assert.Equal(t, "10.0.0.1", entries[0].IPAddress)
+	assert.Nil(t, entries[0].Before)
+	assert.JSONEq(t, `{"amount":100}`, string(entries[0].After))
+
+	entries, err = auditSvc.List(context.Background(), domain.AuditFilter{Action: domain.AuditActionDelete})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, uint(0), entries[0].ActorID)
+
+	future := time.Now().Add(time.Hour)
+	entries, err = auditSvc.List(context.Background(), domain.AuditFilter{Since: &future})
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	// A nil service is a no-op so services work without auditing
+	var disabled *AuditService
+	assert.NoError(t, disabled.Record(ctx, 1, domain.AuditEntityUser, 1, domain.AuditActionUpdate, nil, nil))
+}
+
+func TestTransactionService_Audit(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.AuditLog{}))
+	userID, _, categoryID := createTestData(t, db)
+	auditSvc := NewAuditService(db)
+	txService := &TransactionService{DB: db, Audit: auditSvc}
+	ctx := domain.ContextWithActor(context.Background(), domain.Actor{UserID: userID})
+
+	transaction := &domain.Transaction{
+		UserID:      userID,
+		CategoryID:  categoryID,
+		Type:        "expense",
+		Description: "Lunch",
+		Amount:      domain.NewMoney(12),
+		Date:        time.Now(),
+	}
+	require.NoError(t, txService.Create(ctx, transaction))
+
+	transaction.Amount = domain.NewMoney(15)
+	require.NoError(t, txService.Update(ctx, transaction))
+	require.NoError(t, txService.Delete(ctx, transaction.ID))
+
+	entries, err := auditSvc.List(context.Background(), domain.AuditFilter{
+		UserID:     userID,
+		EntityType: domain.AuditEntityTransaction,
+		EntityID:   transaction.ID,
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	// Newest first: delete, update, create
+	assert.Equal(t, domain.AuditActionDelete, entries[0].Action)
+	assert.Nil(t, entries[0].After)
+	assert.Equal(t, domain.AuditActionUpdate, entries[1].Action)
+	assert.Equal(t, domain.AuditActionCreate, entries[2].Action)
+	assert.Nil(t, entries[2].Before)
+
+	var before, after domain.Transaction
+	require.NoError(t, json.Unmarshal(entries[1].Before, &before))
+	require.NoError(t, json.Unmarshal(entries[1].After, &after))
+	assert.Equal(t, domain.NewMoney(12.0), before.Amount)
+	assert.Equal(t, domain.NewMoney(15.0), after.Amount)
+	assert.Equal(t, userID, entries[1].ActorID)
+}
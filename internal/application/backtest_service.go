@@ -0,0 +1,120 @@
+package application
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// PriceSource provides current market prices by symbol
+type PriceSource interface {
+	GetPrices(ctx context.Context, symbols []string) (map[string]float64, error)
+}
+
+// BacktestService replays past advice against recorded market prices to show
+// whether following it would have made or lost money. Prices come from
+// snapshots captured when advice is kept and whenever performance is checked.
+type BacktestService struct {
+	DB     *gorm.DB
+	Prices PriceSource // Captures current prices when set
+}
+
+func NewBacktestService(db *gorm.DB, prices PriceSource) *BacktestService {
+	return &BacktestService{DB: db, Prices: prices}
+}
+
+// CapturePrices records the current price of the symbols
+func (s *BacktestService) CapturePrices(ctx context.Context, symbols []string) error {
+	if s == nil || s.Prices == nil || len(symbols) == 0 {
+		return nil
+	}
+
+	prices, err := s.Prices.GetPrices(ctx, symbols)
+	if err != nil {
+		return err
+	}
+	if len(prices) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	snapshots := make([]domain.PriceSnapshot, 0, len(prices))
+	for symbol, price := range prices {
+		if price > 0 {
+			snapshots = append(snapshots, domain.PriceSnapshot{Symbol: strings.ToUpper(symbol), Price: price, CapturedAt: now})
+		}
+	}
+	if len(snapshots) == 0 {
+		return nil
+	}
+	return s.DB.WithContext(ctx).Create(&snapshots).Error
+}
+
+// Performance replays the user's matching advice. Valued now, the current
+// prices are captured first; a past At uses only the prices recorded by then.
+func (s *BacktestService) Performance(ctx context.Context, filter domain.BacktestFilter) (*domain.AdvicePerformance, error) {
+	if filter.Status != "" && !domain.IsValidRecommendationStatus(filter.Status) {
+		return nil, domain.ErrInvalidRecommendationStatus
+	}
+
+	at := time.Now()
+	if filter.At != nil {
+		at = *filter.At
+	}
+
+	query := s.DB.WithContext(ctx).Preload("Recommendations").
+		Where("user_id = ? AND created_at <= ?", filter.UserID, at)
+	if filter.Source != "" {
+		query = query.Where("source = ?", filter.Source)
+	}
+	if filter.Since != nil {
+		query = query.Where("created_at >= ?", *filter.Since)
+	}
+	if filter.Until != nil {
+		query = query.Where("created_at <= ?", *filter.Until)
+	}
+	var records []domain.AdviceRecord
+	if err := query.Order("created_at DESC, id DESC").Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	symbols := adviceSymbols(records)
+	if filter.At == nil {
+		if err := s.CapturePrices(ctx, symbols); err != nil {
+			log.Printf("backtest: failed to capture current prices: %v", err)
+		}
+		at = time.Now()
+	}
+
+	var snapshots []domain.PriceSnapshot
+	if len(symbols) > 0 {
+		err := s.DB.WithContext(ctx).Where("symbol IN ? AND captured_at <= ?", symbols, at).Find(&snapshots).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	performance := domain.Backtest(records, domain.NewPriceSeries(snapshots), filter.Status, at)
+	return &performance, nil
+}
+
+// adviceSymbols lists the upper-cased symbols the advice recommends, once each
+func adviceSymbols(records []domain.AdviceRecord) []string {
+	var symbols []string
+	seen := map[string]bool{}
+	for i := range records {
+		for _, rec := range records[i].Recommendations {
+			symbol := strings.ToUpper(rec.Symbol)
+			if symbol != "" && !seen[symbol] {
+				seen[symbol] = true
+				symbols = append(symbols, symbol)
+			}
+		}
+	}
+	return symbols
+}
@@ -0,0 +1,112 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubPriceSource struct {
+	prices map[string]float64
+	err    error
+	calls  int
+}
+
+func (s *stubPriceSource) GetPrices(_ context.Context, symbols []string) (map[string]float64, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	prices := map[string]float64{}
+	for _, symbol := range symbols {
+		if price, ok := s.prices[symbol]; ok {
+			prices[symbol] = price
+		}
+	}
+	return prices, nil
+}
+
+func TestBacktestService_Performance(t *testing.T) {
+	db := setupAdviceHistoryTestDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.PriceSnapshot{}))
+	prices := &stubPriceSource{prices: map[string]float64{"SPY": 100, "BTC": 40000}}
+	backtest := NewBacktestService(db, prices)
+	history := &AdviceHistoryService{DB: db, Backtest: backtest}
+	ctx := context.Background()
+
+	record := &domain.AdviceRecord{
+		UserID: 1, Source: domain.AdviceSourceBasic,
+		Recommendations: []domain.Recommendation{
+			{Symbol: "SPY", Type: "stock", Action: "buy", CurrentPrice: 500},
+			{Symbol: "BTC", Type: "crypto", Action: "buy", CurrentPrice: 400},
+		},
+	}
+	require.True(t, history.keep(ctx, record))
+	_, err := history.SetRecommendationStatus(ctx, 1, record.Recommendations[0].ID, domain.RecommendationStatusFollowed)
+	require.NoError(t, err)
+
+	var captured int64
+	require.NoError(t, db.Model(&domain.PriceSnapshot{}).Count(&captured).Error)
+	assert.Equal(t, int64(2), captured, "keeping advice should capture the prices it was given at")
+
+	// The market moves after the advice
+	prices.prices = map[string]float64{"SPY": 120, "BTC": 30000}
+	time.Sleep(time.Millisecond)
+
+	performance, err := backtest.Performance(ctx, domain.BacktestFilter{UserID: 1})
+	require.NoError(t, err)
+	require.Len(t, performance.Advice, 1)
+	assert.Equal(t, 2, performance.Totals.Evaluated)
+	assert.InDelta(t, 600.0, performance.Advice[0].Recommendations[0].Value, 0.001)
+	assert.InDelta(t, 0.0, performance.Totals.Gain, 0.001)
+	assert.InDelta(t, 20.0, performance.Followed.ReturnPct, 0.001)
+
+	followed, err := backtest.Performance(ctx, domain.BacktestFilter{UserID: 1, Status: domain.RecommendationStatusFollowed})
+	require.NoError(t, err)
+	assert.Len(t, followed.Advice[0].Recommendations, 1)
+
+	other, err := backtest.Performance(ctx, domain.BacktestFilter{UserID: 2})
+	require.NoError(t, err)
+	assert.Empty(t, other.Advice)
+
+	_, err = backtest.Performance(ctx, domain.BacktestFilter{UserID: 1, Status: "maybe"})
+	assert.ErrorIs(t, err, domain.ErrInvalidRecommendationStatus)
+}
+
+func TestBacktestService_UsesRecordedPrices(t *testing.T) {
+	db := setupAdviceHistoryTestDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.PriceSnapshot{}))
+	prices := &stubPriceSource{err: errors.New("provider down")}
+	backtest := NewBacktestService(db, prices)
+	ctx := context.Background()
+
+	generated := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, db.Create(&domain.AdviceRecord{
+		UserID: 1, Source: domain.AdviceSourceRealTime, CreatedAt: generated,
+		Recommendations: []domain.Recommendation{{UserID: 1, Symbol: "QQQ", Action: "buy", CurrentPrice: 300}},
+	}).Error)
+	require.NoError(t, db.Create(&[]domain.PriceSnapshot{
+		{Symbol: "QQQ", Price: 400, CapturedAt: generated.Add(time.Minute)},
+		{Symbol: "QQQ", Price: 300, CapturedAt: generated.AddDate(0, 1, 0)},
+		{Symbol: "QQQ", Price: 500, CapturedAt: generated.AddDate(0, 2, 0)},
+	}).Error)
+
+	// A past valuation does not ask the provider for current prices
+	at := generated.AddDate(0, 1, 1)
+	performance, err := backtest.Performance(ctx, domain.BacktestFilter{UserID: 1, At: &at})
+	require.NoError(t, err)
+	assert.Zero(t, prices.calls)
+	assert.InDelta(t, -25.0, performance.Totals.ReturnPct, 0.001)
+
+	// Valued now the provider failure is tolerated and the latest price used
+	performance, err = backtest.Performance(ctx, domain.BacktestFilter{UserID: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 1, prices.calls)
+	assert.InDelta(t, 25.0, performance.Totals.ReturnPct, 0.001)
+}
@@ -0,0 +1,481 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/persistence"
+	"go-finance-advisor/internal/pkg"
+
+	"gorm.io/gorm"
+)
+
+// bankMatchWindow is how far apart the dates of a synced transaction and one
+// entered by hand may be for them to count as the same; banks often book card
+// payments a few days after they were made
+const bankMatchWindow = 3 * 24 * time.Hour
+
+// BankSyncService links users' bank accounts through aggregators and keeps
+// their transactions in sync. Transactions the user already entered by hand
+// are recognised and linked rather than recorded twice.
+type BankSyncService struct {
+	DB            *gorm.DB
+	Providers     map[string]pkg.BankProvider // The configured providers by name
+	Links         domain.BankLinkRepository   // Falls back to a GORM repository over DB, without encryption, when nil
+	Transactions  *TransactionService         // Records synced transactions; falls back to one over DB when nil
+	Notifications *NotificationService        // Tells users when a bank needs to be linked again when set
+	Jobs          *JobService                 // Runs the first sync of new links in the background when set
+	Quotas        *QuotaService               // Spaces scheduled syncs by the users' plans when set
+}
+
+// JobTypeBankSync is the job syncing one linked account
+const JobTypeBankSync = "bank_sync"
+
+// bankSyncJob is the payload of bank sync jobs
+type bankSyncJob struct {
+	LinkID uint `json:"link_id"`
+}
+
+func NewBankSyncService(db *gorm.DB, providers map[string]pkg.BankProvider) *BankSyncService {
+	return &BankSyncService{DB: db, Providers: providers}
+}
+
+func (s *BankSyncService) links() domain.BankLinkRepository {
+	if s.Links != nil {
+		return s.Links
+	}
+	return persistence.NewBankLinkRepository(s.DB, nil)
+}
+
+// transactions returns the service recording synced transactions. They have
+// already happened at the bank, so spending caps cannot block them.
+func (s *BankSyncService) transactions() *TransactionService {
+	if s.Transactions == nil {
+		return &TransactionService{DB: s.DB}
+	}
+	transactions := *s.Transactions
+	transactions.Caps = nil
+	return &transactions
+}
+
+func (s *BankSyncService) provider(name string) (pkg.BankProvider, error) {
+	provider, ok := s.Providers[name]
+	if !ok {
+		return nil, domain.ErrUnknownBankProvider
+	}
+	return provider, nil
+}
+
+// StartLink begins linking a bank through the provider
+func (s *BankSyncService) StartLink(
+	ctx context.Context, userID uint, providerName, institutionID, redirectURL string,
+) (*domain.BankLinkSession, error) {
+	provider, err := s.provider(providerName)
+	if err != nil {
+		return nil, err
+	}
+	return provider.StartLink(ctx, "user-"+strconv.FormatUint(uint64(userID), 10), institutionID, redirectURL)
+}
+
+// Link completes linking with the token the provider's link flow ended with
+// and syncs the accounts it granted access to, in the background when Jobs
+// is set. Accounts linked before are taken over, which is how links that
+// need reauthorization are renewed.
+func (s *BankSyncService) Link(ctx context.Context, userID uint, providerName, token string) ([]domain.BankLink, error) {
+	provider, err := s.provider(providerName)
+	if err != nil {
+		return nil, err
+	}
+	connection, err := provider.CompleteLink(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.links().List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	linked := make(map[string]domain.BankLink, len(existing))
+	for _, link := range existing {
+		if link.Provider == providerName {
+			linked[link.ExternalAccountID] = link
+		}
+	}
+
+	links := make([]domain.BankLink, 0, len(connection.Accounts))
+	for _, account := range connection.Accounts {
+		link, relinked := linked[account.ExternalID]
+		if !relinked {
+			link = domain.BankLink{UserID: userID, Provider: providerName, ExternalAccountID: account.ExternalID}
+		}
+		link.ConnectionID, link.AccessToken = connection.ConnectionID, connection.AccessToken
+		link.Institution, link.Name, link.Mask, link.Currency = connection.Institution, account.Name, account.Mask, account.Currency
+		if link.Status != domain.BankLinkStatusActive {
+			link.Status, link.LastError = domain.BankLinkStatusPending, ""
+		}
+
+		if relinked {
+			err = s.links().Update(ctx, &link)
+		} else {
+			err = s.links().Create(ctx, &link)
+		}
+		if err != nil {
+			return nil, err
+		}
+		s.firstSync(ctx, &link)
+		links = append(links, link)
+	}
+	return links, nil
+}
+
+// firstSync syncs a new link, queueing the sync when there is a job queue.
+// A failed first sync is kept in the link's status and retried on schedule.
+func (s *BankSyncService) firstSync(ctx context.Context, link *domain.BankLink) {
+	if s.Jobs != nil {
+		_, err := s.Jobs.Enqueue(ctx, JobTypeBankSync, bankSyncJob{LinkID: link.ID})
+		if err == nil {
+			return
+		}
+		log.Printf("bank link %d: queueing the first sync failed, syncing now: %v", link.ID, err)
+	}
+	if _, err := s.sync(ctx, link); err != nil {
+		log.Printf("bank link %d: first sync failed: %v", link.ID, err)
+	}
+}
+
+// RunSyncJob is the JobHandler of JobTypeBankSync. Links that need
+// reauthorization are skipped rather than retried; their status already
+// tells the user.
+func (s *BankSyncService) RunSyncJob(ctx context.Context, payload json.RawMessage) error {
+	var job bankSyncJob
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return err
+	}
+	link, err := s.links().GetByID(ctx, job.LinkID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !link.Syncable() {
+		return nil
+	}
+	if _, err := s.sync(ctx, link); err != nil && !errors.Is(err, domain.ErrBankReauthRequired) {
+		return err
+	}
+	return nil
+}
+
+// List returns the user's linked accounts with their sync status
+func (s *BankSyncService) List(ctx context.Context, userID uint) ([]domain.BankLink, error) {
+	return s.links().List(ctx, userID)
+}
+
+// Get returns one of the user's linked accounts
+func (s *BankSyncService) Get(ctx context.Context, userID, id uint) (*domain.BankLink, error) {
+	link, err := s.links().GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if link.UserID != userID {
+		return nil, domain.ErrNotFound
+	}
+	return link, nil
+}
+
+// Refresh syncs one of the user's linked accounts now. A failed sync is
+// reported in the returned status as well as the error.
+func (s *BankSyncService) Refresh(ctx context.Context, userID, id uint) (*domain.BankSyncResult, error) {
+	link, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !link.Syncable() {
+		return nil, domain.ErrBankReauthRequired
+	}
+	return s.sync(ctx, link)
+}
+
+// Unlink removes one of the user's linked accounts. The transactions it
+// brought in are kept. Access is revoked at the provider once no other
+// account of the connection is linked.
+func (s *BankSyncService) Unlink(ctx context.Context, userID, id uint) error {
+	link, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	err = persistence.InTransaction(ctx, s.DB, func(ctx context.Context) error {
+		if err := persistence.Conn(ctx, s.DB).Where("link_id = ?", link.ID).Delete(&domain.BankTransaction{}).Error; err != nil {
+			return err
+		}
+		return s.links().Delete(ctx, link.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	var shared int64
+	err = s.DB.WithContext(ctx).Model(&domain.BankLink{}).
+		Where("provider = ? AND connection_id = ?", link.Provider, link.ConnectionID).Count(&shared).Error
+	if err != nil || shared > 0 {
+		return err
+	}
+	if provider, ok := s.Providers[link.Provider]; ok {
+		if err := provider.Revoke(ctx, link.AccessToken); err != nil {
+			log.Printf("bank link %d removed but revoking access failed: %v", link.ID, err)
+		}
+	}
+	return nil
+}
+
+// SyncAll syncs every linked account that still has access to its bank and
+// returns how many transactions were added. With Quotas, accounts synced
+// within the bank sync interval of their user's plan are left for later.
+func (s *BankSyncService) SyncAll(ctx context.Context) (int, error) {
+	links, err := s.links().Syncable(ctx)
+	if err != nil {
+		return 0, err
+	}
+	added := 0
+	for i := range links {
+		if s.Quotas != nil {
+			due, err := s.Quotas.SyncDue(ctx, links[i].UserID, links[i].LastSyncedAt)
+			if err != nil {
+				log.Printf("bank link %d: checking the plan failed: %v", links[i].ID, err)
+				continue
+			}
+			if !due {
+				continue
+			}
+		}
+		result, err := s.sync(ctx, &links[i])
+		if err != nil {
+			log.Printf("bank link %d: sync failed: %v", links[i].ID, err)
+			continue
+		}
+		added += result.Added
+	}
+	return added, nil
+}
+
+// StartScheduledSync runs SyncAll on the given interval until ctx is cancelled
+func (s *BankSyncService) StartScheduledSync(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if added, err := s.SyncAll(ctx); err != nil {
+			log.Printf("bank sync failed: %v", err)
+		} else if added > 0 {
+			log.Printf("bank sync added %d transaction(s)", added)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sync pulls the link's changes page by page and records its status. The
+// cursor is only kept once every page has been applied; transactions from a
+// partly applied sync are recognised by their ID when it is retried.
+func (s *BankSyncService) sync(ctx context.Context, link *domain.BankLink) (*domain.BankSyncResult, error) {
+	result := &domain.BankSyncResult{LinkID: link.ID}
+	err := s.pull(ctx, link, result)
+
+	now := time.Now()
+	switch {
+	case errors.Is(err, domain.ErrBankReauthRequired):
+		link.Status, link.LastError = domain.BankLinkStatusReauthRequired, err.Error()
+		s.notifyReauth(ctx, link)
+	case err != nil:
+		link.Status, link.LastError = domain.BankLinkStatusError, err.Error()
+	default:
+		link.Status, link.LastError, link.LastSyncedAt = domain.BankLinkStatusActive, "", &now
+	}
+	result.Status = link.Status
+	if saveErr := s.links().Update(ctx, link); saveErr != nil && err == nil {
+		err = saveErr
+	}
+	return result, err
+}
+
+func (s *BankSyncService) pull(ctx context.Context, link *domain.BankLink, result *domain.BankSyncResult) error {
+	provider, err := s.provider(link.Provider)
+	if err != nil {
+		return err
+	}
+	resolve, err := categoryResolver(ctx, s.DB, link.UserID, link.Provider)
+	if err != nil {
+		return err
+	}
+
+	cursor := link.Cursor
+	for {
+		feed, err := provider.Transactions(ctx, link.AccessToken, link.ExternalAccountID, cursor)
+		if err != nil {
+			return err
+		}
+		for _, tx := range append(feed.Added, feed.Modified...) {
+			if tx.Pending || tx.Amount == 0 {
+				continue
+			}
+			if err := s.apply(ctx, link, tx, resolve, result); err != nil {
+				return fmt.Errorf("transaction %s: %w", tx.ExternalID, err)
+			}
+		}
+		for _, externalID := range feed.Removed {
+			if err := s.remove(ctx, link, externalID, result); err != nil {
+				return fmt.Errorf("transaction %s: %w", externalID, err)
+			}
+		}
+		cursor = feed.Cursor
+		if !feed.HasMore {
+			break
+		}
+	}
+	link.Cursor = cursor
+	return nil
+}
+
+// apply records a transaction from the bank. One synced before is updated if
+// the sync created it; otherwise a matching transaction entered by hand is
+// linked, and only without one a new transaction is created.
+func (s *BankSyncService) apply(
+	ctx context.Context, link *domain.BankLink, tx domain.BankFeedTransaction,
+	resolve func(category, transactionType string) (uint, bool), result *domain.BankSyncResult,
+) error {
+	transactionType := domain.TransactionTypeExpense
+	if tx.Amount > 0 {
+		transactionType = domain.TransactionTypeIncome
+	}
+	amount := tx.Amount.Abs()
+	transactions := s.transactions()
+
+	var synced domain.BankTransaction
+	err := s.DB.WithContext(ctx).Where("link_id = ? AND external_id = ?", link.ID, tx.ExternalID).First(&synced).Error
+	switch {
+	case err == nil:
+		if !synced.Created {
+			return nil
+		}
+		existing, err := transactions.GetByID(ctx, synced.TransactionID)
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil // Deleted by the user, who is not given it back
+		}
+		if err != nil {
+			return err
+		}
+		if existing.Date.Equal(tx.Date) && existing.Amount == amount && existing.Type == transactionType &&
+			existing.Description == tx.Description {
+			return nil
+		}
+		if existing.Type != transactionType {
+			existing.CategoryID, _ = resolve(tx.Category, transactionType)
+		}
+		existing.Category = domain.Category{}
+		existing.Date, existing.Amount, existing.Type, existing.Description = tx.Date, amount, transactionType, tx.Description
+		if err := transactions.Update(ctx, existing); err != nil {
+			return err
+		}
+		result.Updated++
+		return nil
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return err
+	}
+
+	var manual domain.Transaction
+	err = s.DB.WithContext(ctx).
+		Where("user_id = ? AND type = ? AND amount = ? AND date BETWEEN ? AND ?",
+			link.UserID, transactionType, amount, tx.Date.Add(-bankMatchWindow), tx.Date.Add(bankMatchWindow)).
+		Where("id NOT IN (?)", s.DB.Model(&domain.BankTransaction{}).Select("transaction_id")).
+		Order("date, id").First(&manual).Error
+	switch {
+	case err == nil:
+		result.Matched++
+		return s.DB.WithContext(ctx).Create(&domain.BankTransaction{
+			LinkID: link.ID, ExternalID: tx.ExternalID, TransactionID: manual.ID,
+		}).Error
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return err
+	}
+
+	categoryID, _ := resolve(tx.Category, transactionType)
+	if categoryID == 0 {
+		return fmt.Errorf("no category to record %s into", transactionType)
+	}
+	transaction := &domain.Transaction{
+		UserID: link.UserID, CategoryID: categoryID, Type: transactionType,
+		Description: tx.Description, Amount: amount, Date: tx.Date,
+	}
+	if err := transactions.prepare(ctx, transaction); err != nil {
+		return err
+	}
+	// A transaction without its link would be created again on the next sync
+	err = persistence.InTransaction(ctx, s.DB, func(ctx context.Context) error {
+		if err := transactions.insert(ctx, transaction); err != nil {
+			return err
+		}
+		return persistence.Conn(ctx, s.DB).Create(&domain.BankTransaction{
+			LinkID: link.ID, ExternalID: tx.ExternalID, TransactionID: transaction.ID, Created: true,
+		}).Error
+	})
+	if err != nil {
+		return err
+	}
+	result.Added++
+	return nil
+}
+
+// remove withdraws a transaction the bank no longer reports. Transactions
+// entered by hand are only unlinked.
+func (s *BankSyncService) remove(ctx context.Context, link *domain.BankLink, externalID string, result *domain.BankSyncResult) error {
+	var synced domain.BankTransaction
+	err := s.DB.WithContext(ctx).Where("link_id = ? AND external_id = ?", link.ID, externalID).First(&synced).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	err = persistence.InTransaction(ctx, s.DB, func(ctx context.Context) error {
+		if synced.Created {
+			err := s.transactions().Delete(ctx, synced.TransactionID)
+			if err != nil && !errors.Is(err, domain.ErrNotFound) {
+				return err
+			}
+		}
+		return persistence.Conn(ctx, s.DB).Delete(&synced).Error
+	})
+	if err == nil && synced.Created {
+		result.Removed++
+	}
+	return err
+}
+
+// notifyReauth tells the user that a bank has to be linked again
+func (s *BankSyncService) notifyReauth(ctx context.Context, link *domain.BankLink) {
+	linkID, institution := link.ID, link.Institution
+	if institution == "" {
+		institution = "your bank"
+	}
+	notification := &domain.Notification{
+		UserID:   link.UserID,
+		Type:     domain.NotificationTypeBankSync,
+		Title:    "Reconnect " + institution,
+		Message:  fmt.Sprintf("%s can no longer be synced. Link it again to keep importing its transactions.", link.Name),
+		EntityID: &linkID,
+	}
+	if err := s.Notifications.Notify(ctx, notification); err != nil {
+		log.Printf("bank link %d needs reauthorization but the notification failed: %v", link.ID, err)
+	}
+}
@@ -0,0 +1,222 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/pkg"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakeBankProvider serves feeds queued per cursor
+type fakeBankProvider struct {
+	feeds   map[string]*domain.BankFeed
+	err     error
+	revoked []string
+}
+
+func (p *fakeBankProvider) StartLink(_ context.Context, userRef, _, _ string) (*domain.BankLinkSession, error) {
+	return &domain.BankLinkSession{Provider: domain.BankProviderPlaid, Token: "link-" + userRef}, nil
+}
+
+func (p *fakeBankProvider) CompleteLink(_ context.Context, token string) (*domain.BankConnection, error) {
+	return &domain.BankConnection{
+		ConnectionID: "item-1", AccessToken: "access-" + token, Institution: "First Platypus Bank",
+		Accounts: []domain.BankAccount{{ExternalID: "acc-1", Name: "Checking", Mask: "0000", Currency: "USD"}},
+	}, nil
+}
+
+func (p *fakeBankProvider) Transactions(_ context.Context, _, _, cursor string) (*domain.BankFeed, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	if feed, ok := p.feeds[cursor]; ok {
+		return feed, nil
+	}
+	return &domain.BankFeed{Cursor: cursor}, nil
+}
+
+func (p *fakeBankProvider) Revoke(_ context.Context, accessToken string) error {
+	p.revoked = append(p.revoked, accessToken)
+	return nil
+}
+
+func setupBankSyncTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&domain.Category{}, &domain.Transaction{}, &domain.TransactionTag{}, &domain.CategoryMapping{},
+		&domain.BankLink{}, &domain.BankTransaction{}, &domain.Notification{}, &domain.TransactionItem{},
+	))
+	return db
+}
+
+func TestBankSyncService(t *testing.T) {
+	db := setupBankSyncTestDB(t)
+	provider := &fakeBankProvider{}
+	service := NewBankSyncService(db, map[string]pkg.BankProvider{domain.BankProviderPlaid: provider})
+	service.Notifications = NewNotificationService(db)
+	ctx := context.Background()
+
+	dining := domain.Category{Name: "Food & Dining", Type: domain.TransactionTypeExpense}
+	other := domain.Category{Name: "Other Expenses", Type: domain.TransactionTypeExpense}
+	salary := domain.Category{Name: "Other Income", Type: domain.TransactionTypeIncome}
+	for _, category := range []*domain.Category{&dining, &other, &salary} {
+		require.NoError(t, db.Create(category).Error)
+	}
+
+	march := func(day int) time.Time { return time.Date(2024, 3, day, 0, 0, 0, 0, time.UTC) }
+	// Entered by hand the day before the bank booked it
+	manual := domain.Transaction{
+		UserID: 1, CategoryID: dining.ID, Type: domain.TransactionTypeExpense,
+		Description: "Lunch", Amount: domain.NewMoney(12), Date: march(9),
+	}
+	require.NoError(t, db.Create(&manual).Error)
+
+	provider.feeds = map[string]*domain.BankFeed{
+		"": {Added: []domain.BankFeedTransaction{
+			{ExternalID: "tx-1", Date: march(1), Description: "Starbucks", Category: "Food And Drink", Amount: domain.NewMoney(-4.5)},
+			{ExternalID: "tx-2", Date: march(10), Description: "CORNER CAFE", Amount: domain.NewMoney(-12)},
+			{ExternalID: "tx-3", Date: march(12), Description: "Pending", Amount: domain.NewMoney(-3), Pending: true},
+		}, Cursor: "c1", HasMore: true},
+		"c1": {Added: []domain.BankFeedTransaction{
+			{ExternalID: "tx-4", Date: march(15), Description: "ACME Payroll", Amount: domain.NewMoney(2500)},
+		}, Cursor: "c2"},
+	}
+
+	var link domain.BankLink
+	t.Run("should link accounts and sync their history", func(t *testing.T) {
+		_, err := service.StartLink(ctx, 1, domain.BankProviderGoCardless, "", "")
+		assert.ErrorIs(t, err, domain.ErrUnknownBankProvider)
+		session, err := service.StartLink(ctx, 1, domain.BankProviderPlaid, "", "")
+		require.NoError(t, err)
+		assert.Equal(t, "link-user-1", session.Token)
+
+		links, err := service.Link(ctx, 1, domain.BankProviderPlaid, "public-1")
+		require.NoError(t, err)
+		require.Len(t, links, 1)
+		link = links[0]
+		assert.Equal(t, domain.BankLinkStatusActive, link.Status)
+		assert.NotNil(t, link.LastSyncedAt)
+		assert.Equal(t, "c2", link.Cursor)
+
+		var transactions []domain.Transaction
+		require.NoError(t, db.Where("user_id = ?", 1).Order("date").Find(&transactions).Error)
+		require.Len(t, transactions, 3, "the hand-entered lunch is not recorded twice")
+		assert.Equal(t, dining.ID, transactions[0].CategoryID)
+		assert.Equal(t, "Lunch", transactions[1].Description)
+		assert.Equal(t, salary.ID, transactions[2].CategoryID)
+		assert.Equal(t, domain.TransactionTypeIncome, transactions[2].Type)
+	})
+
+	t.Run("should apply modifications and removals", func(t *testing.T) {
+		provider.feeds["c2"] = &domain.BankFeed{
+			Modified: []domain.BankFeedTransaction{
+				{ExternalID: "tx-1", Date: march(2), Description: "Starbucks", Amount: domain.NewMoney(-5)},
+				{ExternalID: "tx-2", Date: march(10), Description: "CORNER CAFE", Amount: domain.NewMoney(-13)},
+			},
+			Removed: []string{"tx-4"},
+			Cursor:  "c3",
+		}
+
+		result, err := service.Refresh(ctx, 1, link.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.BankSyncResult{LinkID: link.ID, Status: domain.BankLinkStatusActive, Updated: 1, Removed: 1}, *result)
+
+		var starbucks domain.Transaction
+		require.NoError(t, db.Where("description = ?", "Starbucks").First(&starbucks).Error)
+		assert.Equal(t, domain.NewMoney(5), starbucks.Amount)
+		var lunch domain.Transaction
+		require.NoError(t, db.First(&lunch, manual.ID).Error)
+		assert.Equal(t, domain.NewMoney(12), lunch.Amount, "hand-entered transactions are left alone")
+
+		var count int64
+		require.NoError(t, db.Model(&domain.Transaction{}).Where("description = ?", "ACME Payroll").Count(&count).Error)
+		assert.Zero(t, count)
+	})
+
+	t.Run("should ask for reauthorization when the bank revokes access", func(t *testing.T) {
+		provider.err = domain.ErrBankReauthRequired
+		added, err := service.SyncAll(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, added)
+
+		status, err := service.Get(ctx, 1, link.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.BankLinkStatusReauthRequired, status.Status)
+		var notifications []domain.Notification
+		require.NoError(t, db.Where("user_id = ?", 1).Find(&notifications).Error)
+		require.Len(t, notifications, 1)
+		assert.Equal(t, domain.NotificationTypeBankSync, notifications[0].Type)
+
+		_, err = service.Refresh(ctx, 1, link.ID)
+		assert.ErrorIs(t, err, domain.ErrBankReauthRequired)
+
+		// Linking again renews the same account
+		provider.err = nil
+		links, err := service.Link(ctx, 1, domain.BankProviderPlaid, "public-2")
+		require.NoError(t, err)
+		require.Len(t, links, 1)
+		assert.Equal(t, link.ID, links[0].ID)
+		assert.Equal(t, domain.BankLinkStatusActive, links[0].Status)
+		assert.Equal(t, "access-public-2", links[0].AccessToken)
+	})
+
+	t.Run("should unlink and revoke access but keep transactions", func(t *testing.T) {
+		_, err := service.Get(ctx, 2, link.ID)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.ErrorIs(t, service.Unlink(ctx, 2, link.ID), domain.ErrNotFound)
+
+		require.NoError(t, service.Unlink(ctx, 1, link.ID))
+		assert.Equal(t, []string{"access-public-2"}, provider.revoked)
+
+		links, err := service.List(ctx, 1)
+		require.NoError(t, err)
+		assert.Empty(t, links)
+		var synced, transactions int64
+		require.NoError(t, db.Model(&domain.BankTransaction{}).Count(&synced).Error)
+		assert.Zero(t, synced)
+		require.NoError(t, db.Model(&domain.Transaction{}).Where("user_id = ?", 1).Count(&transactions).Error)
+		assert.Equal(t, int64(2), transactions)
+	})
+}
+
+func TestBankSyncService_QueuedFirstSync(t *testing.T) {
+	db := setupBankSyncTestDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.Job{}))
+	require.NoError(t, db.Create(&domain.Category{Name: "Other Expenses", Type: domain.TransactionTypeExpense}).Error)
+	provider := &fakeBankProvider{feeds: map[string]*domain.BankFeed{
+		"": {Added: []domain.BankFeedTransaction{
+			{ExternalID: "tx-1", Date: time.Now(), Description: "Bakery", Amount: domain.NewMoney(-3)},
+		}, Cursor: "c1"},
+	}}
+	service := NewBankSyncService(db, map[string]pkg.BankProvider{domain.BankProviderPlaid: provider})
+	service.Jobs = NewJobService(db)
+	service.Jobs.Register(JobTypeBankSync, service.RunSyncJob)
+	ctx := context.Background()
+
+	links, err := service.Link(ctx, 1, domain.BankProviderPlaid, "public-1")
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	assert.Equal(t, domain.BankLinkStatusPending, links[0].Status, "the first sync waits for a worker")
+
+	ran, err := service.Jobs.RunNext(ctx)
+	require.NoError(t, err)
+	assert.True(t, ran)
+
+	link, err := service.Get(ctx, 1, links[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.BankLinkStatusActive, link.Status)
+	var count int64
+	require.NoError(t, db.Model(&domain.Transaction{}).Where("user_id = ?", 1).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
@@ -0,0 +1,195 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// recurringLookbackMonths is how far back transactions are searched for
+// recurring ones
+const recurringLookbackMonths = 6
+
+// BillService keeps users' monthly bills and reminds them of due dates
+type BillService struct {
+	DB     *gorm.DB
+	Events *EventBus // Publishes BillDue reminders when set
+}
+
+func NewBillService(db *gorm.DB) *BillService {
+	return &BillService{DB: db}
+}
+
+// Create adds an active bill for the user
+func (s *BillService) Create(ctx context.Context, userID uint, bill *domain.Bill) error {
+	if err := s.validate(ctx, userID, bill); err != nil {
+		return err
+	}
+	bill.ID, bill.UserID, bill.Active, bill.LastRemindedFor = 0, userID, true, nil
+	return s.DB.WithContext(ctx).Create(bill).Error
+}
+
+// validate checks the bill and that its category is one the user can see
+func (s *BillService) validate(ctx context.Context, userID uint, bill *domain.Bill) error {
+	if err := bill.Validate(); err != nil {
+		return err
+	}
+	if bill.CategoryID == nil {
+		return nil
+	}
+	var count int64
+	err := s.DB.WithContext(ctx).Model(&domain.Category{}).
+		Where("id = ? AND (user_id IS NULL OR user_id = ?)", *bill.CategoryID, userID).Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return &domain.ValidationError{Fields: []domain.FieldError{{Field: "category_id", Message: "does not exist"}}}
+	}
+	return nil
+}
+
+// List returns the user's bills by due day
+func (s *BillService) List(ctx context.Context, userID uint) ([]domain.Bill, error) {
+	var bills []domain.Bill
+	err := s.DB.WithContext(ctx).Where("user_id = ?", userID).Order("due_day, id").Find(&bills).Error
+	return bills, err
+}
+
+// Get returns one of the user's bills
+func (s *BillService) Get(ctx context.Context, userID, id uint) (*domain.Bill, error) {
+	var bill domain.Bill
+	err := s.DB.WithContext(ctx).Where("user_id = ?", userID).First(&bill, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &bill, nil
+}
+
+// Update saves the details, reminder lead time and active flag of one of
+// the user's bills
+func (s *BillService) Update(ctx context.Context, userID uint, bill *domain.Bill) error {
+	if err := s.validate(ctx, userID, bill); err != nil {
+		return err
+	}
+	result := s.DB.WithContext(ctx).Model(&domain.Bill{}).
+		Where("id = ? AND user_id = ?", bill.ID, userID).
+		Updates(map[string]any{
+			"payee": bill.Payee, "amount": bill.Amount, "due_day": bill.DueDay, "autopay": bill.Autopay,
+			"category_id": bill.CategoryID, "notes": bill.Notes, "remind_days_before": bill.RemindDaysBefore,
+			"active": bill.Active,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// Delete removes one of the user's bills
+func (s *BillService) Delete(ctx context.Context, userID, id uint) error {
+	result := s.DB.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&domain.Bill{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// Calendar lists the due dates of the user's active bills and the expected
+// dates of their recurring transactions between two days, both included
+func (s *BillService) Calendar(ctx context.Context, userID uint, from, to time.Time) (*domain.DueCalendar, error) {
+	if to.Before(from) || to.Sub(from) > domain.MaxBillCalendarDays*24*time.Hour {
+		return nil, &domain.ValidationError{Fields: []domain.FieldError{{
+			Field: "to", Message: fmt.Sprintf("must be on or after from and at most %d days later", domain.MaxBillCalendarDays),
+		}}}
+	}
+
+	var bills []domain.Bill
+	if err := s.DB.WithContext(ctx).Where("user_id = ? AND active = ?", userID, true).Order("due_day, id").Find(&bills).Error; err != nil {
+		return nil, err
+	}
+	recurring, err := s.Recurring(ctx, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := domain.NewDueCalendar(from, to, bills, recurring)
+	return &calendar, nil
+}
+
+// Recurring returns the user's transactions that recurred monthly in the
+// months before asOf
+func (s *BillService) Recurring(ctx context.Context, userID uint, asOf time.Time) ([]domain.RecurringTransaction, error) {
+	var transactions []domain.Transaction
+	err := s.DB.WithContext(ctx).
+		Where("user_id = ? AND date BETWEEN ? AND ?", userID, asOf.AddDate(0, -recurringLookbackMonths, 0), asOf).
+		Order("date").Find(&transactions).Error
+	if err != nil {
+		return nil, err
+	}
+	return domain.DetectRecurring(transactions, asOf), nil
+}
+
+// SendReminders publishes a BillDue for every active bill whose next due
+// date is at most its RemindDaysBefore days after now and has not been
+// reminded of yet. It returns how many reminders went out.
+func (s *BillService) SendReminders(ctx context.Context, now time.Time) (int, error) {
+	var bills []domain.Bill
+	if err := s.DB.WithContext(ctx).Where("active = ?", true).Order("id").Find(&bills).Error; err != nil {
+		return 0, err
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	sent := 0
+	for _, bill := range bills {
+		due := bill.NextDue(today)
+		if due.After(today.AddDate(0, 0, bill.RemindDaysBefore)) {
+			continue
+		}
+		if bill.LastRemindedFor != nil && bill.LastRemindedFor.Equal(due) {
+			continue
+		}
+
+		// Mark the due date first, so a failing subscriber cannot cause repeats
+		err := s.DB.WithContext(ctx).Model(&domain.Bill{}).Where("id = ?", bill.ID).Update("last_reminded_for", due).Error
+		if err != nil {
+			return sent, fmt.Errorf("failed to mark the reminder of bill %d: %w", bill.ID, err)
+		}
+		bill.LastRemindedFor = &due
+		s.Events.Publish(ctx, domain.BillDue{Bill: bill, DueDate: due})
+		sent++
+	}
+	return sent, nil
+}
+
+// StartReminders runs SendReminders on the given interval until ctx is cancelled
+func (s *BillService) StartReminders(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.SendReminders(ctx, time.Now()); err != nil {
+			log.Printf("bill reminders failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
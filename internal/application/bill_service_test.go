@@ -0,0 +1,142 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupBillTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&domain.Bill{}, &domain.Category{}, &domain.Transaction{}, &domain.Notification{}))
+	return db
+}
+
+func TestBillService_CRUD(t *testing.T) {
+	db := setupBillTestDB(t)
+	service := NewBillService(db)
+	ctx := context.Background()
+
+	owner, other := uint(1), uint(2)
+	mine := domain.Category{Name: "Rent", Type: domain.TransactionTypeExpense, UserID: &owner}
+	theirs := domain.Category{Name: "Rent", Type: domain.TransactionTypeExpense, UserID: &other}
+	require.NoError(t, db.Create(&mine).Error)
+	require.NoError(t, db.Create(&theirs).Error)
+
+	bill := &domain.Bill{Payee: "Landlord", Amount: domain.NewMoney(1200), DueDay: 1, CategoryID: &mine.ID}
+	require.NoError(t, service.Create(ctx, 1, bill))
+	assert.True(t, bill.Active)
+	require.NoError(t, service.Create(ctx, 1, &domain.Bill{Payee: "Phone", Amount: domain.NewMoney(40), DueDay: 28}))
+
+	t.Run("rejects invalid bills and other users' categories", func(t *testing.T) {
+		var validationErr *domain.ValidationError
+		assert.ErrorAs(t, service.Create(ctx, 1, &domain.Bill{Payee: "Gym", DueDay: 5}), &validationErr)
+		err := service.Create(ctx, 1, &domain.Bill{Payee: "Gym", Amount: domain.NewMoney(30), DueDay: 5, CategoryID: &theirs.ID})
+		assert.ErrorAs(t, err, &validationErr)
+	})
+
+	t.Run("lists by due day and scopes to the user", func(t *testing.T) {
+		bills, err := service.List(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, bills, 2)
+		assert.Equal(t, "Landlord", bills[0].Payee)
+
+		_, err = service.Get(ctx, 2, bill.ID)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("updates and deletes", func(t *testing.T) {
+		bill.Amount, bill.Active, bill.RemindDaysBefore = domain.NewMoney(1250), false, 0
+		require.NoError(t, service.Update(ctx, 1, bill))
+		stored, err := service.Get(ctx, 1, bill.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.NewMoney(1250), stored.Amount)
+		assert.False(t, stored.Active)
+
+		assert.ErrorIs(t, service.Delete(ctx, 2, bill.ID), domain.ErrNotFound)
+		require.NoError(t, service.Delete(ctx, 1, bill.ID))
+		_, err = service.Get(ctx, 1, bill.ID)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
+
+func TestBillService_SendReminders(t *testing.T) {
+	db := setupBillTestDB(t)
+	bus := NewEventBus()
+	NewNotificationService(db).Subscribe(bus)
+	service := &BillService{DB: db, Events: bus}
+	ctx := context.Background()
+
+	bills := []domain.Bill{
+		{Payee: "Landlord", Amount: domain.NewMoney(1200), DueDay: 1, RemindDaysBefore: 3, Autopay: true},
+		{Payee: "Phone", Amount: domain.NewMoney(40), DueDay: 20, RemindDaysBefore: 3},
+		{Payee: "Insurance", Amount: domain.NewMoney(90), DueDay: 30, RemindDaysBefore: 5},
+	}
+	for i := range bills {
+		require.NoError(t, service.Create(ctx, 1, &bills[i]))
+	}
+	require.NoError(t, db.Model(&bills[2]).Update("active", false).Error)
+
+	now := time.Date(2025, time.May, 29, 8, 0, 0, 0, time.UTC)
+	sent, err := service.SendReminders(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent) // Only the active bill due within its lead time
+
+	var notifications []domain.Notification
+	require.NoError(t, db.Find(&notifications).Error)
+	require.Len(t, notifications, 1)
+	assert.Equal(t, domain.NotificationTypeBill, notifications[0].Type)
+	assert.Equal(t, "Bill due: Landlord", notifications[0].Title)
+	assert.Contains(t, notifications[0].Message, "due on Jun 1 and will be paid automatically")
+
+	// The same due date is only reminded of once
+	sent, err = service.SendReminders(ctx, now.Add(24*time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+
+	stored, err := service.Get(ctx, 1, bills[0].ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.LastRemindedFor)
+	assert.Equal(t, time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC), stored.LastRemindedFor.UTC())
+}
+
+func TestBillService_Calendar(t *testing.T) {
+	db := setupBillTestDB(t)
+	service := NewBillService(db)
+	ctx := context.Background()
+
+	require.NoError(t, service.Create(ctx, 1, &domain.Bill{Payee: "Landlord", Amount: domain.NewMoney(1200), DueDay: 1}))
+	now := time.Now()
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for months := 0; months < 3; months++ {
+		require.NoError(t, db.Create(&domain.Transaction{
+			UserID: 1, Type: domain.TransactionTypeIncome, Description: "Salary",
+			Amount: domain.NewMoney(3000), Date: thisMonth.AddDate(0, -months, 0),
+		}).Error)
+	}
+
+	from := thisMonth.AddDate(0, 1, 0)
+	calendar, err := service.Calendar(ctx, 1, from, from.AddDate(0, 0, 2))
+	require.NoError(t, err)
+	require.Len(t, calendar.Entries, 2)
+	assert.Equal(t, domain.CalendarEntryBill, calendar.Entries[0].Kind)
+	assert.Equal(t, domain.CalendarEntryRecurring, calendar.Entries[1].Kind)
+	assert.Equal(t, "Salary", calendar.Entries[1].Title)
+	assert.Equal(t, domain.NewMoney(1200), calendar.TotalDue)
+	assert.Equal(t, domain.NewMoney(3000), calendar.ExpectedIncome)
+
+	_, err = service.Calendar(ctx, 1, from, from.AddDate(0, 0, -1))
+	var validationErr *domain.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
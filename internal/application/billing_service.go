@@ -0,0 +1,167 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/pkg"
+
+	"gorm.io/gorm"
+)
+
+// billingInvoiceLimit is how many of the latest invoices are listed
+const billingInvoiceLimit = 24
+
+// BillingService sells the premium plan through the billing provider and
+// keeps the users' plans in step with their subscriptions, which the
+// provider reports through webhooks
+type BillingService struct {
+	DB              *gorm.DB
+	Provider        pkg.BillingProvider // Nil while billing is not configured
+	PriceID         string              // The price premium subscriptions are sold at
+	SuccessURL      string              // Where checkout sends users who subscribed
+	CancelURL       string              // Where checkout sends users who left it
+	PortalReturnURL string              // Where the customer portal sends users back to
+}
+
+// user loads the user's email and subscription
+func (s *BillingService) user(ctx context.Context, userID uint) (*domain.User, error) {
+	if s.Provider == nil {
+		return nil, domain.ErrBillingUnavailable
+	}
+	var user domain.User
+	err := s.DB.WithContext(ctx).First(&user, userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Checkout starts a checkout of the premium subscription for the user. Users
+// whose subscription still grants premium manage it in the portal instead.
+func (s *BillingService) Checkout(ctx context.Context, userID uint) (*domain.BillingSession, error) {
+	user, err := s.user(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if domain.SubscriptionGrantsPremium(user.SubscriptionStatus) {
+		return nil, domain.ErrAlreadySubscribed
+	}
+	return s.Provider.CreateCheckout(ctx, pkg.CheckoutRequest{
+		UserRef:    strconv.FormatUint(uint64(user.ID), 10),
+		Email:      user.Email,
+		CustomerID: user.BillingCustomerID,
+		PriceID:    s.PriceID,
+		SuccessURL: s.SuccessURL,
+		CancelURL:  s.CancelURL,
+	})
+}
+
+// Portal opens the customer portal, where users change their payment method
+// or cancel
+func (s *BillingService) Portal(ctx context.Context, userID uint) (*domain.BillingSession, error) {
+	user, err := s.user(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.BillingCustomerID == "" {
+		return nil, domain.ErrNoBillingAccount
+	}
+	return s.Provider.CreatePortal(ctx, user.BillingCustomerID, s.PortalReturnURL)
+}
+
+// Invoices lists the user's latest invoices; users who never subscribed have none
+func (s *BillingService) Invoices(ctx context.Context, userID uint) ([]domain.Invoice, error) {
+	user, err := s.user(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.BillingCustomerID == "" {
+		return []domain.Invoice{}, nil
+	}
+	return s.Provider.Invoices(ctx, user.BillingCustomerID, billingInvoiceLimit)
+}
+
+// HandleWebhook applies a webhook event of the billing provider. Completed
+// checkouts and subscriptions that are active, trialing or past due put the
+// user on the premium plan; any other subscription status puts them back on
+// the free plan. Events are applied as they arrive, so a redelivered event
+// changes nothing. Events of users that are not known, and of subscriptions
+// the user has since replaced, are ignored.
+func (s *BillingService) HandleWebhook(ctx context.Context, payload []byte, signature string) error {
+	if s.Provider == nil {
+		return domain.ErrBillingUnavailable
+	}
+	event, err := s.Provider.ParseWebhook(payload, signature)
+	if err != nil {
+		return err
+	}
+
+	switch event.Type {
+	case domain.BillingEventCheckoutCompleted, domain.BillingEventSubscriptionCreated,
+		domain.BillingEventSubscriptionUpdated, domain.BillingEventSubscriptionDeleted:
+	default:
+		return nil
+	}
+	user, err := s.eventUser(ctx, event)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		log.Printf("billing event %s (%s) is not about a known user, ignoring it", event.ID, event.Type)
+		return nil
+	}
+
+	updates := map[string]any{}
+	if event.CustomerID != "" {
+		updates["billing_customer_id"] = event.CustomerID
+	}
+	if event.Type == domain.BillingEventCheckoutCompleted {
+		updates["subscription_id"] = event.SubscriptionID
+		updates["subscription_status"] = domain.SubscriptionActive
+		updates["plan"] = domain.PlanPremium
+	} else {
+		premium := domain.SubscriptionGrantsPremium(event.Status)
+		if !premium && user.SubscriptionID != "" && user.SubscriptionID != event.SubscriptionID {
+			// An old subscription ended after the user bought a new one
+			return nil
+		}
+		updates["subscription_id"] = event.SubscriptionID
+		updates["subscription_status"] = event.Status
+		updates["subscription_period_end"] = event.PeriodEnd
+		updates["plan"] = domain.PlanFree
+		if premium {
+			updates["plan"] = domain.PlanPremium
+		}
+	}
+	return s.DB.WithContext(ctx).Model(&domain.User{}).Where("id = ?", user.ID).Updates(updates).Error
+}
+
+// eventUser finds the user an event is about, by the user reference the
+// checkout was started with and else by the customer ID; it returns nil
+// when neither is known
+func (s *BillingService) eventUser(ctx context.Context, event *domain.BillingEvent) (*domain.User, error) {
+	query := s.DB.WithContext(ctx).Select("id", "billing_customer_id", "subscription_id")
+	var user domain.User
+	var err error
+	if userID, parseErr := strconv.ParseUint(event.UserRef, 10, 32); parseErr == nil {
+		err = query.First(&user, userID).Error
+	} else if event.CustomerID != "" {
+		err = query.Where("billing_customer_id = ?", event.CustomerID).First(&user).Error
+	} else {
+		return nil, nil
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
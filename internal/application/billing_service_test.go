@@ -0,0 +1,167 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/pkg"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBillingProvider accepts webhook payloads that are JSON encoded
+// domain.BillingEvents signed "signed"
+type fakeBillingProvider struct {
+	checkouts []pkg.CheckoutRequest
+}
+
+func (p *fakeBillingProvider) CreateCheckout(_ context.Context, checkout pkg.CheckoutRequest) (*domain.BillingSession, error) {
+	p.checkouts = append(p.checkouts, checkout)
+	return &domain.BillingSession{ID: "cs_1", URL: "https://checkout.example/cs_1"}, nil
+}
+
+func (p *fakeBillingProvider) CreatePortal(_ context.Context, customerID, _ string) (*domain.BillingSession, error) {
+	return &domain.BillingSession{URL: "https://portal.example/" + customerID}, nil
+}
+
+func (p *fakeBillingProvider) Invoices(_ context.Context, customerID string, _ int) ([]domain.Invoice, error) {
+	return []domain.Invoice{{ID: "in_" + customerID, Status: "paid"}}, nil
+}
+
+func (p *fakeBillingProvider) ParseWebhook(payload []byte, signature string) (*domain.BillingEvent, error) {
+	if signature != "signed" {
+		return nil, domain.ErrInvalidWebhookSignature
+	}
+	var event domain.BillingEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+func TestBillingService(t *testing.T) {
+	db := setupQuotaTestDB(t)
+	provider := &fakeBillingProvider{}
+	service := &BillingService{
+		DB: db, Provider: provider, PriceID: "price_premium",
+		SuccessURL: "https://app.example/done", CancelURL: "https://app.example/pricing", PortalReturnURL: "https://app.example",
+	}
+	ctx := context.Background()
+
+	user := domain.User{Email: "ada@example.com", Password: "x"}
+	require.NoError(t, db.Create(&user).Error)
+	reload := func() domain.User {
+		var reloaded domain.User
+		require.NoError(t, db.First(&reloaded, user.ID).Error)
+		return reloaded
+	}
+	webhook := func(event domain.BillingEvent) error {
+		payload, err := json.Marshal(event)
+		require.NoError(t, err)
+		return service.HandleWebhook(ctx, payload, "signed")
+	}
+	userRef := strconv.FormatUint(uint64(user.ID), 10)
+
+	t.Run("should check out users who never subscribed", func(t *testing.T) {
+		session, err := service.Checkout(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "https://checkout.example/cs_1", session.URL)
+		assert.Equal(t, pkg.CheckoutRequest{
+			UserRef: userRef, Email: "ada@example.com", PriceID: "price_premium",
+			SuccessURL: "https://app.example/done", CancelURL: "https://app.example/pricing",
+		}, provider.checkouts[0])
+
+		_, err = service.Portal(ctx, user.ID)
+		assert.ErrorIs(t, err, domain.ErrNoBillingAccount)
+		invoices, err := service.Invoices(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Empty(t, invoices)
+		_, err = service.Checkout(ctx, 99)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("should reject unsigned webhooks", func(t *testing.T) {
+		err := service.HandleWebhook(ctx, []byte(`{}`), "forged")
+		assert.ErrorIs(t, err, domain.ErrInvalidWebhookSignature)
+	})
+
+	t.Run("should move users to premium once they paid", func(t *testing.T) {
+		require.NoError(t, webhook(domain.BillingEvent{
+			ID: "evt_1", Type: domain.BillingEventCheckoutCompleted, UserRef: userRef, CustomerID: "cus_1", SubscriptionID: "sub_1",
+		}))
+		reloaded := reload()
+		assert.Equal(t, domain.PlanPremium, reloaded.Plan)
+		assert.Equal(t, "cus_1", reloaded.BillingCustomerID)
+		assert.Equal(t, domain.SubscriptionActive, reloaded.SubscriptionStatus)
+
+		_, err := service.Checkout(ctx, user.ID)
+		assert.ErrorIs(t, err, domain.ErrAlreadySubscribed)
+		session, err := service.Portal(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "https://portal.example/cus_1", session.URL)
+		invoices, err := service.Invoices(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "in_cus_1", invoices[0].ID)
+	})
+
+	t.Run("should follow subscription changes", func(t *testing.T) {
+		periodEnd := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+		require.NoError(t, webhook(domain.BillingEvent{
+			ID: "evt_2", Type: domain.BillingEventSubscriptionUpdated, CustomerID: "cus_1", SubscriptionID: "sub_1",
+			Status: domain.SubscriptionPastDue, PeriodEnd: &periodEnd,
+		}))
+		reloaded := reload()
+		assert.Equal(t, domain.PlanPremium, reloaded.Plan, "past due subscriptions keep premium while payment is retried")
+		require.NotNil(t, reloaded.SubscriptionPeriodEnd)
+		assert.True(t, periodEnd.Equal(*reloaded.SubscriptionPeriodEnd))
+
+		require.NoError(t, webhook(domain.BillingEvent{
+			ID: "evt_3", Type: domain.BillingEventSubscriptionUpdated, CustomerID: "cus_1", SubscriptionID: "sub_1",
+			Status: domain.SubscriptionUnpaid,
+		}))
+		assert.Equal(t, domain.PlanFree, reload().Plan)
+
+		require.NoError(t, webhook(domain.BillingEvent{
+			ID: "evt_4", Type: domain.BillingEventSubscriptionCreated, UserRef: userRef, CustomerID: "cus_1", SubscriptionID: "sub_2",
+			Status: domain.SubscriptionActive,
+		}))
+		assert.Equal(t, domain.PlanPremium, reload().Plan)
+
+		require.NoError(t, webhook(domain.BillingEvent{
+			ID: "evt_5", Type: domain.BillingEventSubscriptionDeleted, CustomerID: "cus_1", SubscriptionID: "sub_1",
+			Status: domain.SubscriptionCanceled,
+		}))
+		reloaded = reload()
+		assert.Equal(t, domain.PlanPremium, reloaded.Plan, "ending a replaced subscription keeps the new one")
+		assert.Equal(t, "sub_2", reloaded.SubscriptionID)
+
+		require.NoError(t, webhook(domain.BillingEvent{
+			ID: "evt_6", Type: domain.BillingEventSubscriptionDeleted, CustomerID: "cus_1", SubscriptionID: "sub_2",
+			Status: domain.SubscriptionCanceled,
+		}))
+		reloaded = reload()
+		assert.Equal(t, domain.PlanFree, reloaded.Plan)
+		assert.Equal(t, domain.SubscriptionCanceled, reloaded.SubscriptionStatus)
+	})
+
+	t.Run("should ignore events it does not handle or whose user is unknown", func(t *testing.T) {
+		assert.NoError(t, webhook(domain.BillingEvent{ID: "evt_7", Type: "invoice.paid", CustomerID: "cus_1"}))
+		assert.NoError(t, webhook(domain.BillingEvent{
+			ID: "evt_8", Type: domain.BillingEventSubscriptionUpdated, CustomerID: "cus_unknown", SubscriptionID: "sub_9",
+			Status: domain.SubscriptionActive,
+		}))
+		assert.Equal(t, domain.PlanFree, reload().Plan)
+	})
+
+	t.Run("should be unavailable without a provider", func(t *testing.T) {
+		unconfigured := &BillingService{DB: db}
+		_, err := unconfigured.Checkout(ctx, user.ID)
+		assert.ErrorIs(t, err, domain.ErrBillingUnavailable)
+		assert.ErrorIs(t, unconfigured.HandleWebhook(ctx, []byte(`{}`), "signed"), domain.ErrBillingUnavailable)
+	})
+}
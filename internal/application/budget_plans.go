@@ -0,0 +1,249 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+)
+
+// ExportBudgetPlan writes the user's active personal budgets as a shareable
+// plan. A category budgeted for several upcoming periods appears once, with
+// its current budget.
+func (s *BudgetService) ExportBudgetPlan(ctx context.Context, userID uint, name string) (*domain.BudgetPlan, error) {
+	budgets, err := s.activeBudgets(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(budgets, func(a, b domain.Budget) int { return a.StartDate.Compare(b.StartDate) })
+
+	parents := map[uint]string{}
+	var parentIDs []uint
+	for i := range budgets {
+		if parentID := budgets[i].Category.ParentID; parentID != nil {
+			parentIDs = append(parentIDs, *parentID)
+		}
+	}
+	if len(parentIDs) > 0 {
+		var categories []domain.Category
+		if err := s.DB.WithContext(ctx).Select("id", "name").Where("id IN ?", parentIDs).Find(&categories).Error; err != nil {
+			return nil, err
+		}
+		for _, category := range categories {
+			parents[category.ID] = category.Name
+		}
+	}
+
+	plan := &domain.BudgetPlan{
+		Version:    domain.BudgetPlanVersion,
+		Name:       strings.TrimSpace(name),
+		ExportedAt: time.Now().UTC().Truncate(time.Second),
+		Budgets:    []domain.BudgetPlanItem{},
+	}
+	exported := map[uint]bool{}
+	for i := range budgets {
+		budget := &budgets[i]
+		if exported[budget.CategoryID] {
+			continue
+		}
+		exported[budget.CategoryID] = true
+		item := domain.BudgetPlanItem{
+			Category:   budget.Category.Name,
+			CategoryID: budget.CategoryID,
+			Amount:     budget.Amount,
+			Period:     budget.Period,
+		}
+		if budget.Category.ParentID != nil {
+			item.Parent = parents[*budget.Category.ParentID]
+		}
+		plan.Budgets = append(plan.Budgets, item)
+	}
+	return plan, nil
+}
+
+// PreviewBudgetPlan lists what importing the plan would do to the user's
+// budgets without changing any
+func (s *BudgetService) PreviewBudgetPlan(
+	ctx context.Context, userID uint, req domain.BudgetPlanImport,
+) (*domain.BudgetPlanPreview, error) {
+	preview, _, err := s.planBudgetImport(ctx, userID, req)
+	return preview, err
+}
+
+// ImportBudgetPlan applies a plan to the user's budgets: budgets of
+// categories without one for the period are created and the amounts of
+// existing ones are updated. Budgets whose category cannot be found, or
+// whose category has a budget of another period, are skipped.
+func (s *BudgetService) ImportBudgetPlan(
+	ctx context.Context, userID uint, req domain.BudgetPlanImport,
+) (*domain.BudgetPlanPreview, error) {
+	planned, existing, err := s.planBudgetImport(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.BudgetPlanPreview{Name: planned.Name, StartDate: planned.StartDate, Applied: true}
+	for _, change := range planned.Changes {
+		switch change.Action {
+		case domain.BudgetPlanActionCreate:
+			budget := &domain.Budget{
+				UserID:     userID,
+				CategoryID: change.CategoryID,
+				Amount:     change.Amount,
+				Period:     change.Period,
+				StartDate:  planned.StartDate,
+			}
+			err := s.CreateBudget(ctx, budget)
+			if errors.Is(err, domain.ErrBudgetExists) {
+				change.Action, change.Reason = domain.BudgetPlanActionSkip, err.Error()
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			change.BudgetID = budget.ID
+		case domain.BudgetPlanActionUpdate:
+			update := existing[change.BudgetID]
+			update.Amount = change.Amount
+			if err := s.UpdateBudget(ctx, change.BudgetID, &update); err != nil {
+				return nil, err
+			}
+		}
+		result.Add(change)
+	}
+	return result, nil
+}
+
+// planBudgetImport works out what importing the plan does to each of its
+// budgets, and returns the user's budgets the plan updates by ID
+func (s *BudgetService) planBudgetImport(
+	ctx context.Context, userID uint, req domain.BudgetPlanImport,
+) (*domain.BudgetPlanPreview, map[uint]domain.Budget, error) {
+	if err := req.Plan.Validate(); err != nil {
+		return nil, nil, err
+	}
+	if req.StartDate.IsZero() {
+		now := time.Now()
+		req.StartDate = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	categories, err := s.planCategories(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	preview := &domain.BudgetPlanPreview{Name: req.Plan.Name, StartDate: req.StartDate, Changes: []domain.BudgetPlanChange{}}
+	existing := map[uint]domain.Budget{}
+	planned := map[uint]bool{}
+	for _, item := range req.Plan.Budgets {
+		change := domain.BudgetPlanChange{
+			Action:           domain.BudgetPlanActionCreate,
+			Category:         item.Category,
+			SourceCategoryID: item.CategoryID,
+			Period:           item.Period,
+			Amount:           item.Amount,
+		}
+		category, reason := categories.resolve(item, req.CategoryMap)
+		switch {
+		case category == nil:
+			change.Action, change.Reason = domain.BudgetPlanActionSkip, reason
+		case planned[category.ID]:
+			change.CategoryID = category.ID
+			change.Action, change.Reason = domain.BudgetPlanActionSkip, "category is already budgeted by the plan"
+		default:
+			change.CategoryID = category.ID
+			planned[category.ID] = true
+			budget, err := s.overlappingBudget(ctx, userID, category.ID, req.StartDate, domain.PeriodEnd(req.StartDate, item.Period))
+			if err != nil {
+				return nil, nil, err
+			}
+			if budget != nil {
+				change.BudgetID, change.Current = budget.ID, &budget.Amount
+				switch {
+				case budget.Period != item.Period:
+					change.Action, change.Reason = domain.BudgetPlanActionSkip, "a "+budget.Period+" budget already covers the period"
+				case budget.Amount == item.Amount:
+					change.Action = domain.BudgetPlanActionUnchanged
+				default:
+					change.Action = domain.BudgetPlanActionUpdate
+					existing[budget.ID] = *budget
+				}
+			}
+		}
+		preview.Add(change)
+	}
+	return preview, existing, nil
+}
+
+// overlappingBudget returns the user's active personal budget of the
+// category overlapping the period, if there is one
+func (s *BudgetService) overlappingBudget(ctx context.Context, userID, categoryID uint, start, end time.Time) (*domain.Budget, error) {
+	active := true
+	budgets, err := s.budgets().Find(ctx, domain.BudgetFilter{
+		UserID:       userID,
+		PersonalOnly: true,
+		CategoryID:   &categoryID,
+		IsActive:     &active,
+		StartsBefore: &end,
+		EndsAfter:    &start,
+	})
+	if err != nil || len(budgets) == 0 {
+		return nil, err
+	}
+	return &budgets[0], nil
+}
+
+// planCategoryIndex finds the user's categories for the budgets of a plan
+type planCategoryIndex struct {
+	byID   map[uint]*domain.Category
+	byName map[string][]*domain.Category // By lower-cased name, the user's own first
+}
+
+// planCategories indexes the expense categories the user sees
+func (s *BudgetService) planCategories(ctx context.Context, userID uint) (*planCategoryIndex, error) {
+	var categories []domain.Category
+	err := s.DB.WithContext(ctx).
+		Where("(user_id IS NULL OR user_id = ?) AND type = ?", userID, domain.TransactionTypeExpense).
+		Order("user_id IS NULL, id").
+		Find(&categories).Error
+	if err != nil {
+		return nil, err
+	}
+	index := &planCategoryIndex{byID: map[uint]*domain.Category{}, byName: map[string][]*domain.Category{}}
+	for i := range categories {
+		category := &categories[i]
+		index.byID[category.ID] = category
+		name := strings.ToLower(category.Name)
+		index.byName[name] = append(index.byName[name], category)
+	}
+	return index, nil
+}
+
+// resolve returns the user's category for a plan budget: the one its
+// category ID is mapped to, or else the one of the same name, preferring
+// one under a parent of the plan's parent name. Without a category it
+// returns why.
+func (idx *planCategoryIndex) resolve(item domain.BudgetPlanItem, mapping map[uint]uint) (*domain.Category, string) {
+	if id, ok := mapping[item.CategoryID]; ok && item.CategoryID != 0 {
+		if category, found := idx.byID[id]; found {
+			return category, ""
+		}
+		return nil, "mapped category not found"
+	}
+
+	candidates := idx.byName[strings.ToLower(strings.TrimSpace(item.Category))]
+	if len(candidates) == 0 {
+		return nil, "category not found"
+	}
+	for _, candidate := range candidates {
+		if candidate.ParentID == nil {
+			continue
+		}
+		if parent, ok := idx.byID[*candidate.ParentID]; ok && strings.EqualFold(parent.Name, item.Parent) {
+			return candidate, ""
+		}
+	}
+	return candidates[0], ""
+}
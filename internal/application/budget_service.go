@@ -1,36 +1,55 @@
 package application
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"slices"
 	"time"
 
 	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/persistence"
+
 	"gorm.io/gorm"
 )
 
 type BudgetService struct {
 	DB *gorm.DB
+	// Repo and Transactions fall back to GORM repositories over DB when nil
+	Repo         domain.BudgetRepository
+	Transactions domain.TransactionRepository
+	Audit        *AuditService // Records modifications when set
+	Cache        ResultCache   // Drops the user's cached analytics on changes when set
+	Events       *EventBus     // Publishes BudgetExceeded when set
 }
 
 func NewBudgetService(db *gorm.DB) *BudgetService {
 	return &BudgetService{DB: db}
 }
 
-// CreateBudget creates a new budget for a user
-func (s *BudgetService) CreateBudget(budget *domain.Budget) error {
-	// Check if budget already exists for this user, category, and period
-	var existingBudget domain.Budget
-	err := s.DB.Where("user_id = ? AND category_id = ? AND start_date <= ? AND end_date >= ? AND is_active = ?",
-		budget.UserID, budget.CategoryID, budget.EndDate, budget.StartDate, true).First(&existingBudget).Error
+// NewBudgetServiceWithRepositories creates a service backed by the given repositories
+func NewBudgetServiceWithRepositories(
+	budgets domain.BudgetRepository, transactions domain.TransactionRepository,
+) *BudgetService {
+	return &BudgetService{Repo: budgets, Transactions: transactions}
+}
 
-	if err == nil {
-		return errors.New("budget already exists for this category and period")
+func (s *BudgetService) budgets() domain.BudgetRepository {
+	if s.Repo != nil {
+		return s.Repo
 	}
+	return persistence.NewBudgetRepository(s.DB)
+}
 
-	if !errors.Is(err, gorm.ErrRecordNotFound) {
-		return err
+func (s *BudgetService) transactions() domain.TransactionRepository {
+	if s.Transactions != nil {
+		return s.Transactions
 	}
+	return persistence.NewTransactionRepository(s.DB)
+}
 
+// CreateBudget creates a new budget for a user
+func (s *BudgetService) CreateBudget(ctx context.Context, budget *domain.Budget) error {
 	// Set default values
 	if budget.Period == "" {
 		budget.Period = domain.PeriodMonthly
@@ -40,84 +59,200 @@ func (s *BudgetService) CreateBudget(budget *domain.Budget) error {
 		budget.StartDate = time.Now().Truncate(24 * time.Hour)
 	}
 
+	// Anchored budgets cover the whole period their start date falls in
+	normalizeAnchor(budget)
+	budget.AlignPeriod()
 	if budget.EndDate.IsZero() {
-		// Set end date based on period
-		switch budget.Period {
-		case domain.PeriodWeekly:
-			budget.EndDate = budget.StartDate.AddDate(0, 0, 7)
-		case domain.PeriodMonthly:
-			budget.EndDate = budget.StartDate.AddDate(0, 1, 0)
-		case domain.PeriodYearly:
-			budget.EndDate = budget.StartDate.AddDate(1, 0, 0)
-		default:
-			budget.EndDate = budget.StartDate.AddDate(0, 1, 0) // Default to monthly
-		}
+		budget.EndDate = domain.PeriodEnd(budget.StartDate, budget.Period)
+	}
+
+	// Check if budget already exists for this user or household, category, and period
+	active := true
+	filter := domain.BudgetFilter{
+		UserID:       budget.UserID,
+		PersonalOnly: budget.HouseholdID == nil,
+		CategoryID:   &budget.CategoryID,
+		IsActive:     &active,
+		StartsBefore: &budget.EndDate,
+		EndsAfter:    &budget.StartDate,
+	}
+	if budget.HouseholdID != nil {
+		filter.UserID = 0
+		filter.HouseholdID = budget.HouseholdID
+	}
+	existing, err := s.budgets().Find(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	if len(existing) > 0 {
+		return domain.ErrBudgetExists
+	}
+
+	if err := budget.Validate(); err != nil {
+		return err
 	}
 
 	budget.Remaining = budget.Amount
 	budget.IsActive = true
 
-	return s.DB.Create(budget).Error
+	if err := s.budgets().Create(ctx, budget); err != nil {
+		return err
+	}
+	s.Audit.track(ctx, budget.UserID, domain.AuditEntityBudget, budget.ID, domain.AuditActionCreate, nil, budget)
+	invalidateUsers(ctx, s.Cache, budget.UserID)
+	return s.recalculateAnchored(ctx, budget)
+}
+
+// normalizeAnchor anchors budgets without an anchor to their start date.
+// Only payday anchors keep a day.
+func normalizeAnchor(budget *domain.Budget) {
+	if budget.Anchor == "" {
+		budget.Anchor = domain.BudgetAnchorStartDate
+	}
+	if budget.Anchor != domain.BudgetAnchorPayday {
+		budget.AnchorDay = 0
+	}
 }
 
 // UpdateBudget updates an existing budget
-func (s *BudgetService) UpdateBudget(budgetID uint, updates *domain.Budget) error {
-	var budget domain.Budget
-	err := s.DB.First(&budget, budgetID).Error
+func (s *BudgetService) UpdateBudget(ctx context.Context, budgetID uint, updates *domain.Budget) error {
+	budget, err := s.budgets().GetByID(ctx, budgetID)
 	if err != nil {
 		return err
 	}
+	before := *budget
 
 	// Update allowed fields
 	budget.Amount = updates.Amount
 	budget.Period = updates.Period
+	budget.Anchor = updates.Anchor
+	budget.AnchorDay = updates.AnchorDay
 	budget.StartDate = updates.StartDate
 	budget.EndDate = updates.EndDate
 	budget.IsActive = updates.IsActive
+	normalizeAnchor(budget)
+	budget.AlignPeriod()
+	if err := budget.Validate(); err != nil {
+		return err
+	}
 
 	// Recalculate remaining amount
 	budget.CalculateRemaining()
 
-	return s.DB.Save(&budget).Error
+	if err := s.budgets().Update(ctx, budget); err != nil {
+		return err
+	}
+	s.Audit.track(ctx, budget.UserID, domain.AuditEntityBudget, budgetID, domain.AuditActionUpdate, &before, budget)
+	invalidateUsers(ctx, s.Cache, budget.UserID)
+	if budget.StartDate.Equal(before.StartDate) && budget.EndDate.Equal(before.EndDate) {
+		return nil
+	}
+	return s.recalculateAnchored(ctx, budget)
 }
 
-// GetBudgetsByUser retrieves all budgets for a user
-func (s *BudgetService) GetBudgetsByUser(userID uint) ([]domain.Budget, error) {
-	var budgets []domain.Budget
-	err := s.DB.Preload("Category").Where("user_id = ?", userID).Order("created_at DESC").Find(&budgets).Error
-	return budgets, err
+// recalculateAnchored sets the spending of an anchored budget, whose period
+// usually began before the budget was created or realigned. Services without
+// transactions leave it to the reconcile job.
+func (s *BudgetService) recalculateAnchored(ctx context.Context, budget *domain.Budget) error {
+	if !budget.IsAnchored() || (s.DB == nil && s.Transactions == nil) {
+		return nil
+	}
+	parents, err := s.categoryParents(ctx)
+	if err != nil {
+		return err
+	}
+	budgets := []domain.Budget{*budget}
+	s.recalculate(ctx, budgets, parents)
+	*budget = budgets[0]
+	return nil
 }
 
-// GetActiveBudgetsByUser retrieves active budgets for a user
-func (s *BudgetService) GetActiveBudgetsByUser(userID uint) ([]domain.Budget, error) {
-	var budgets []domain.Budget
-	err := s.DB.Preload("Category").Where("user_id = ? AND is_active = ? AND end_date >= ?",
-		userID, true, time.Now()).Order("created_at DESC").Find(&budgets).Error
-	return budgets, err
+// GetBudgetsByUser retrieves all personal budgets for a user
+func (s *BudgetService) GetBudgetsByUser(ctx context.Context, userID uint) ([]domain.Budget, error) {
+	budgets, err := s.budgets().Find(ctx, domain.BudgetFilter{UserID: userID, PersonalOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	return budgets, s.countComments(ctx, budgets)
 }
 
-// GetBudgetByID retrieves a specific budget
-func (s *BudgetService) GetBudgetByID(budgetID uint) (*domain.Budget, error) {
-	var budget domain.Budget
-	err := s.DB.Preload("Category").First(&budget, budgetID).Error
+// GetBudgetsByHousehold retrieves all budgets shared with a household
+func (s *BudgetService) GetBudgetsByHousehold(ctx context.Context, householdID uint) ([]domain.Budget, error) {
+	budgets, err := s.budgets().Find(ctx, domain.BudgetFilter{HouseholdID: &householdID})
 	if err != nil {
 		return nil, err
 	}
-	return &budget, nil
+	return budgets, s.countComments(ctx, budgets)
+}
+
+// countComments sets how many comments each of the budgets has. Without a
+// database the counts are left out.
+func (s *BudgetService) countComments(ctx context.Context, budgets []domain.Budget) error {
+	if s.DB == nil {
+		return nil
+	}
+	ids := make([]uint, len(budgets))
+	for i := range budgets {
+		ids[i] = budgets[i].ID
+	}
+	counts, err := countComments(ctx, s.DB, domain.CommentTargetBudget, ids)
+	if err != nil {
+		return err
+	}
+	for i := range budgets {
+		count := counts[budgets[i].ID]
+		budgets[i].CommentCount = &count
+	}
+	return nil
+}
+
+// GetActiveBudgetsByUser retrieves active budgets for a user
+func (s *BudgetService) GetActiveBudgetsByUser(ctx context.Context, userID uint) ([]domain.Budget, error) {
+	return s.activeBudgets(ctx, userID)
+}
+
+// GetBudgetByID retrieves a specific budget
+func (s *BudgetService) GetBudgetByID(ctx context.Context, budgetID uint) (*domain.Budget, error) {
+	return s.budgets().GetByID(ctx, budgetID)
 }
 
 // DeleteBudget deletes a budget
-func (s *BudgetService) DeleteBudget(budgetID uint) error {
-	return s.DB.Delete(&domain.Budget{}, budgetID).Error
+func (s *BudgetService) DeleteBudget(ctx context.Context, budgetID uint) error {
+	var before *domain.Budget
+	if s.Audit != nil || s.Cache != nil {
+		before, _ = s.budgets().GetByID(ctx, budgetID)
+	}
+
+	if err := s.budgets().Delete(ctx, budgetID); err != nil {
+		return err
+	}
+	if before != nil {
+		s.Audit.track(ctx, before.UserID, domain.AuditEntityBudget, budgetID, domain.AuditActionDelete, before, nil)
+		invalidateUsers(ctx, s.Cache, before.UserID)
+	}
+	return nil
 }
 
-// UpdateBudgetSpending updates the spent amount for budgets when a transaction is added
-func (s *BudgetService) UpdateBudgetSpending(userID, categoryID uint, amount float64, transactionDate time.Time) error {
-	// Find active budgets that cover this transaction date
-	var budgets []domain.Budget
-	err := s.DB.Where("user_id = ? AND category_id = ? AND is_active = ? AND start_date <= ? AND end_date >= ?",
-		userID, categoryID, true, transactionDate, transactionDate).Find(&budgets).Error
+// UpdateBudgetSpending updates the spent amount for budgets when a transaction
+// is added. Budgets on the parent category count the transaction too.
+func (s *BudgetService) UpdateBudgetSpending(
+	ctx context.Context, userID, categoryID uint, amount domain.Money, transactionDate time.Time,
+) error {
+	parents, err := s.categoryParents(ctx)
+	if err != nil {
+		return err
+	}
 
+	// Find active budgets that cover this transaction date
+	active := true
+	budgets, err := s.budgets().Find(ctx, domain.BudgetFilter{
+		UserID:       userID,
+		CategoryIDs:  parents.Ancestors(categoryID),
+		IsActive:     &active,
+		StartsBefore: &transactionDate,
+		EndsAfter:    &transactionDate,
+	})
 	if err != nil {
 		return err
 	}
@@ -126,27 +261,39 @@ func (s *BudgetService) UpdateBudgetSpending(userID, categoryID uint, amount flo
 	for i := range budgets {
 		budgets[i].Spent += amount
 		budgets[i].CalculateRemaining()
-		s.DB.Save(&budgets[i])
+		_ = s.budgets().Update(ctx, &budgets[i])
+	}
+	if len(budgets) > 0 {
+		invalidateUsers(ctx, s.Cache, userID)
 	}
 
 	return nil
 }
 
 // GetBudgetSummary calculates budget summary for a user
-func (s *BudgetService) GetBudgetSummary(userID uint) (*domain.BudgetSummary, error) {
-	var budgets []domain.Budget
-	err := s.DB.Where("user_id = ? AND is_active = ? AND end_date >= ?",
-		userID, true, time.Now()).Find(&budgets).Error
+func (s *BudgetService) GetBudgetSummary(ctx context.Context, userID uint) (*domain.BudgetSummary, error) {
+	budgets, err := s.activeBudgets(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
 
+	parents, err := s.categoryParents(ctx)
 	if err != nil {
 		return nil, err
 	}
+	budgeted := make(map[uint]bool, len(budgets))
+	for i := range budgets {
+		budgeted[budgets[i].CategoryID] = true
+	}
 
-	totalBudget := 0.0
-	totalSpent := 0.0
+	var totalBudget, totalSpent domain.Money
 	overBudgetCount := 0
 
 	for i := range budgets {
+		// A budget under a budgeted parent is already part of the parent's spending
+		if slices.ContainsFunc(parents.Ancestors(budgets[i].CategoryID)[1:], func(id uint) bool { return budgeted[id] }) {
+			continue
+		}
 		totalBudget += budgets[i].Amount
 		totalSpent += budgets[i].Spent
 
@@ -156,10 +303,7 @@ func (s *BudgetService) GetBudgetSummary(userID uint) (*domain.BudgetSummary, er
 	}
 
 	totalRemaining := totalBudget - totalSpent
-	percentageUsed := 0.0
-	if totalBudget > 0 {
-		percentageUsed = (totalSpent / totalBudget) * 100
-	}
+	percentageUsed := totalSpent.PercentOf(totalBudget)
 
 	// Determine budget status
 	budgetStatus := "on_track"
@@ -179,45 +323,306 @@ func (s *BudgetService) GetBudgetSummary(userID uint) (*domain.BudgetSummary, er
 }
 
 // GetBudgetsByCategory retrieves budgets for a specific category
-func (s *BudgetService) GetBudgetsByCategory(userID, categoryID uint) ([]domain.Budget, error) {
-	var budgets []domain.Budget
-	err := s.DB.Preload("Category").Where("user_id = ? AND category_id = ?",
-		userID, categoryID).Order("created_at DESC").Find(&budgets).Error
-	return budgets, err
+func (s *BudgetService) GetBudgetsByCategory(ctx context.Context, userID, categoryID uint) ([]domain.Budget, error) {
+	return s.budgets().Find(ctx, domain.BudgetFilter{UserID: userID, CategoryID: &categoryID})
 }
 
 // GetBudgetsByPeriod retrieves budgets for a specific period
-func (s *BudgetService) GetBudgetsByPeriod(userID uint, startDate, endDate time.Time) ([]domain.Budget, error) {
-	var budgets []domain.Budget
-	err := s.DB.Preload("Category").Where("user_id = ? AND start_date <= ? AND end_date >= ?",
-		userID, endDate, startDate).Order("created_at DESC").Find(&budgets).Error
-	return budgets, err
+func (s *BudgetService) GetBudgetsByPeriod(ctx context.Context, userID uint, startDate, endDate time.Time) ([]domain.Budget, error) {
+	return s.budgets().Find(ctx, domain.BudgetFilter{UserID: userID, StartsBefore: &endDate, EndsAfter: &startDate})
 }
 
-// RefreshBudgetSpending recalculates spent amounts for all budgets
-func (s *BudgetService) RefreshBudgetSpending(userID uint) error {
-	var budgets []domain.Budget
-	err := s.DB.Where("user_id = ? AND is_active = ?", userID, true).Find(&budgets).Error
+// RefreshBudgetSpending recalculates spent amounts for all budgets the user
+// created, including household ones. Budgets on a parent category include
+// the spending in its nested categories.
+func (s *BudgetService) RefreshBudgetSpending(ctx context.Context, userID uint) error {
+	active := true
+	budgets, err := s.budgets().Find(ctx, domain.BudgetFilter{UserID: userID, IsActive: &active})
+	if err != nil {
+		return err
+	}
+	parents, err := s.categoryParents(ctx)
 	if err != nil {
 		return err
 	}
 
-	for i := range budgets {
-		// Calculate actual spent amount from transactions
-		var totalSpent float64
-		err := s.DB.Model(&domain.Transaction{}).Where(
-			"user_id = ? AND category_id = ? AND date BETWEEN ? AND ?",
-			budgets[i].UserID, budgets[i].CategoryID, budgets[i].StartDate, budgets[i].EndDate,
-		).Select("COALESCE(SUM(amount), 0)").Scan(&totalSpent).Error
+	s.recalculate(ctx, budgets, parents)
+	return nil
+}
+
+// activeBudgets returns the user's active personal budgets that have not ended yet
+func (s *BudgetService) activeBudgets(ctx context.Context, userID uint) ([]domain.Budget, error) {
+	active := true
+	now := time.Now()
+	return s.budgets().Find(ctx, domain.BudgetFilter{UserID: userID, PersonalOnly: true, IsActive: &active, EndsAfter: &now})
+}
 
+// categoryParents returns the category hierarchy. Services built only from
+// repositories have no categories to nest, so every category stands alone.
+func (s *BudgetService) categoryParents(ctx context.Context) (domain.CategoryParents, error) {
+	if s.DB == nil {
+		return nil, nil
+	}
+	return categoryParents(ctx, s.DB)
+}
+
+// CreateBudgetsFromTemplate creates a monthly budget for every category of
+// the template from the user's monthly income, which is the average of the
+// last IncomeHistoryMonths full months unless the request gives it.
+// Categories that already have a budget for the month, or whose default
+// category does not exist, are skipped; the budgets can be edited afterwards.
+func (s *BudgetService) CreateBudgetsFromTemplate(
+	ctx context.Context, userID uint, req domain.BudgetTemplateRequest,
+) (*domain.BudgetTemplateResult, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	template, _ := domain.FindBudgetTemplate(req.Template)
+	if req.StartDate.IsZero() {
+		now := time.Now()
+		req.StartDate = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	result := &domain.BudgetTemplateResult{
+		Template: template.Key,
+		Budgets:  []domain.Budget{},
+		Skipped:  []domain.SkippedAllocation{},
+	}
+	if req.MonthlyIncome != nil {
+		result.MonthlyIncome = *req.MonthlyIncome
+		result.IncomeSource = "provided"
+	} else {
+		income, months, err := s.averageMonthlyIncome(ctx, userID, req.StartDate)
 		if err != nil {
+			return nil, err
+		}
+		if months == 0 {
+			return nil, &domain.ValidationError{Fields: []domain.FieldError{{
+				Field: "monthly_income", Message: "is required when no income has been recorded in the last 3 months",
+			}}}
+		}
+		result.MonthlyIncome, result.IncomeSource, result.MonthsAnalyzed = income, "average", months
+	}
+	result.Savings = result.MonthlyIncome
+
+	categories, err := s.templateCategories(ctx, userID, template)
+	if err != nil {
+		return nil, err
+	}
+	for _, allocation := range template.Allocations {
+		amount := allocation.Amount(result.MonthlyIncome)
+		result.Savings -= amount
+		categoryID, ok := categories[allocation.Category]
+		if !ok {
+			result.Skipped = append(result.Skipped, domain.SkippedAllocation{
+				Category: allocation.Category, Amount: amount, Reason: "category not found",
+			})
 			continue
 		}
 
-		budgets[i].Spent = totalSpent
-		budgets[i].CalculateRemaining()
-		s.DB.Save(&budgets[i])
+		budget := &domain.Budget{
+			UserID:     userID,
+			CategoryID: categoryID,
+			Amount:     amount,
+			Period:     domain.PeriodMonthly,
+			StartDate:  req.StartDate,
+		}
+		err := s.CreateBudget(ctx, budget)
+		if errors.Is(err, domain.ErrBudgetExists) {
+			result.Skipped = append(result.Skipped, domain.SkippedAllocation{
+				Category: allocation.Category, Amount: amount, Reason: err.Error(),
+			})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.Budgets = append(result.Budgets, *budget)
 	}
+	return result, nil
+}
 
-	return nil
+// averageMonthlyIncome averages the user's income over the full months
+// before the start date that have any, and returns how many months it covered
+func (s *BudgetService) averageMonthlyIncome(ctx context.Context, userID uint, before time.Time) (domain.Money, int, error) {
+	end := time.Date(before.Year(), before.Month(), 1, 0, 0, 0, 0, before.Location())
+	start := end.AddDate(0, -domain.IncomeHistoryMonths, 0)
+	end = end.Add(-time.Nanosecond)
+	byMonth, err := s.transactions().SumByMonth(ctx, domain.TransactionFilter{
+		UserID:    userID,
+		Type:      domain.TransactionTypeIncome,
+		StartDate: &start,
+		EndDate:   &end,
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var total domain.Money
+	months := 0
+	for _, income := range byMonth {
+		if income > 0 {
+			total += income
+			months++
+		}
+	}
+	if months == 0 {
+		return 0, 0, nil
+	}
+	return total / domain.Money(months), months, nil
+}
+
+// templateCategories maps the category names of a template to the expense
+// categories the user sees, preferring the user's own over the defaults
+func (s *BudgetService) templateCategories(ctx context.Context, userID uint, template domain.BudgetTemplate) (map[string]uint, error) {
+	names := make([]string, len(template.Allocations))
+	for i, allocation := range template.Allocations {
+		names[i] = allocation.Category
+	}
+	var categories []domain.Category
+	err := s.DB.WithContext(ctx).
+		Where("(user_id IS NULL OR user_id = ?) AND type = ? AND name IN ?", userID, domain.TransactionTypeExpense, names).
+		Order("user_id IS NULL").
+		Find(&categories).Error
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]uint, len(categories))
+	for _, category := range categories {
+		if _, ok := ids[category.Name]; !ok {
+			ids[category.Name] = category.ID
+		}
+	}
+	return ids, nil
+}
+
+// SimulateBudget projects the user's savings, cash flow and goal completion
+// dates with hypothetical spending changes next to the projection without
+// them. Both start from the user's average month over the last
+// IncomeHistoryMonths full months.
+func (s *BudgetService) SimulateBudget(
+	ctx context.Context, userID uint, req domain.BudgetSimulationRequest,
+) (*domain.BudgetSimulation, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if req.StartDate.IsZero() {
+		now := time.Now()
+		req.StartDate = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	names, err := s.simulationCategories(ctx, userID, req.Changes)
+	if err != nil {
+		return nil, err
+	}
+	baseline, err := s.spendingBaseline(ctx, userID, req.StartDate, names)
+	if err != nil {
+		return nil, err
+	}
+	var goals []domain.FinancialGoal
+	err = s.DB.WithContext(ctx).Where("user_id = ? AND status = ?", userID, "active").Order("target_date, id").Find(&goals).Error
+	if err != nil {
+		return nil, err
+	}
+
+	simulation := domain.SimulateBudget(baseline, req, names, goals)
+	return &simulation, nil
+}
+
+// spendingBaseline averages the user's income and category spending over
+// the full months before the start date that have any transactions. names
+// is filled with the names of the categories spent in.
+func (s *BudgetService) spendingBaseline(
+	ctx context.Context, userID uint, before time.Time, names map[uint]string,
+) (domain.SpendingBaseline, error) {
+	end := time.Date(before.Year(), before.Month(), 1, 0, 0, 0, 0, before.Location())
+	start := end.AddDate(0, -domain.IncomeHistoryMonths, 0)
+	end = end.Add(-time.Nanosecond)
+	filter := domain.TransactionFilter{UserID: userID, ExcludeTransfers: true, StartDate: &start, EndDate: &end}
+
+	byMonth, err := s.transactions().SumByMonth(ctx, filter)
+	if err != nil {
+		return domain.SpendingBaseline{}, err
+	}
+	baseline := domain.SpendingBaseline{Categories: []domain.CategorySpending{}}
+	for _, total := range byMonth {
+		if total != 0 {
+			baseline.MonthsAnalyzed++
+		}
+	}
+	if baseline.MonthsAnalyzed == 0 {
+		return baseline, nil
+	}
+	months := domain.Money(baseline.MonthsAnalyzed)
+
+	filter.Type = domain.TransactionTypeIncome
+	income, err := s.transactions().Sum(ctx, filter)
+	if err != nil {
+		return baseline, err
+	}
+	baseline.MonthlyIncome = income / months
+
+	filter.Type = domain.TransactionTypeExpense
+	byCategory, err := s.transactions().SumByCategory(ctx, filter)
+	if err != nil {
+		return baseline, err
+	}
+	var missing []uint
+	for categoryID, total := range byCategory {
+		if _, ok := names[categoryID]; !ok {
+			missing = append(missing, categoryID)
+		}
+		baseline.Categories = append(baseline.Categories, domain.CategorySpending{CategoryID: categoryID, Monthly: total / months})
+	}
+	if len(missing) > 0 {
+		var categories []domain.Category
+		if err := s.DB.WithContext(ctx).Where("id IN ?", missing).Find(&categories).Error; err != nil {
+			return baseline, err
+		}
+		for _, category := range categories {
+			names[category.ID] = category.Name
+		}
+	}
+	for i := range baseline.Categories {
+		baseline.Categories[i].CategoryName = names[baseline.Categories[i].CategoryID]
+	}
+	slices.SortFunc(baseline.Categories, func(a, b domain.CategorySpending) int { return int(a.CategoryID) - int(b.CategoryID) })
+	return baseline, nil
+}
+
+// simulationCategories checks that every change is to an expense category
+// the user sees and returns their names by ID
+func (s *BudgetService) simulationCategories(ctx context.Context, userID uint, changes []domain.BudgetChange) (map[uint]string, error) {
+	names := make(map[uint]string)
+	if len(changes) == 0 {
+		return names, nil
+	}
+	ids := make([]uint, len(changes))
+	for i, change := range changes {
+		ids[i] = change.CategoryID
+	}
+	var categories []domain.Category
+	err := s.DB.WithContext(ctx).
+		Where("id IN ? AND (user_id IS NULL OR user_id = ?) AND type = ?", ids, userID, domain.TransactionTypeExpense).
+		Find(&categories).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, category := range categories {
+		names[category.ID] = category.Name
+	}
+
+	var invalid domain.ValidationError
+	for i, change := range changes {
+		if _, ok := names[change.CategoryID]; !ok {
+			invalid.Fields = append(invalid.Fields, domain.FieldError{
+				Field:   fmt.Sprintf("changes[%d].category_id", i),
+				Message: "must be one of your expense categories",
+			})
+		}
+	}
+	if len(invalid.Fields) > 0 {
+		return nil, &invalid
+	}
+	return names, nil
 }
@@ -1,10 +1,12 @@
 package application
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/persistence"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -19,7 +21,7 @@ func setupBudgetTestDB(t *testing.T) *gorm.DB {
 	})
 	require.NoError(t, err)
 
-	err = db.AutoMigrate(&domain.User{}, &domain.Category{}, &domain.Budget{}, &domain.Transaction{})
+	err = db.AutoMigrate(&domain.User{}, &domain.Category{}, &domain.Budget{}, &domain.Transaction{}, &domain.Comment{})
 	require.NoError(t, err)
 
 	return db
@@ -55,15 +57,15 @@ func TestBudgetService_CreateBudget(t *testing.T) {
 		budget := &domain.Budget{
 			UserID:     userID,
 			CategoryID: categoryID,
-			Amount:     500.00,
+			Amount:     domain.NewMoney(500.00),
 		}
 
-		err := budgetService.CreateBudget(budget)
+		err := budgetService.CreateBudget(context.Background(), budget)
 		assert.NoError(t, err)
 		assert.NotZero(t, budget.ID)
 		assert.Equal(t, "monthly", budget.Period)
 		assert.True(t, budget.IsActive)
-		assert.Equal(t, 500.00, budget.Remaining)
+		assert.Equal(t, domain.NewMoney(500.00), budget.Remaining)
 		assert.False(t, budget.StartDate.IsZero())
 		assert.False(t, budget.EndDate.IsZero())
 	})
@@ -73,7 +75,7 @@ func TestBudgetService_CreateBudget(t *testing.T) {
 		budget := &domain.Budget{
 			UserID:     userID,
 			CategoryID: categoryID + 1, // Different category to avoid conflict
-			Amount:     1000.00,
+			Amount:     domain.NewMoney(1000.00),
 			Period:     "weekly",
 			StartDate:  startDate,
 		}
@@ -83,7 +85,7 @@ func TestBudgetService_CreateBudget(t *testing.T) {
 		db.Create(category2)
 		budget.CategoryID = category2.ID
 
-		err := budgetService.CreateBudget(budget)
+		err := budgetService.CreateBudget(context.Background(), budget)
 		assert.NoError(t, err)
 		assert.Equal(t, "weekly", budget.Period)
 		expectedEndDate := startDate.AddDate(0, 0, 7)
@@ -98,22 +100,22 @@ func TestBudgetService_CreateBudget(t *testing.T) {
 		budget1 := &domain.Budget{
 			UserID:     userID,
 			CategoryID: categoryID,
-			Amount:     300.00,
+			Amount:     domain.NewMoney(300.00),
 			StartDate:  time.Now().Truncate(24 * time.Hour),
 			EndDate:    time.Now().AddDate(0, 1, 0),
 		}
-		err := budgetService.CreateBudget(budget1)
+		err := budgetService.CreateBudget(context.Background(), budget1)
 		require.NoError(t, err)
 
 		// Try to create overlapping budget
 		budget2 := &domain.Budget{
 			UserID:     userID,
 			CategoryID: categoryID,
-			Amount:     400.00,
+			Amount:     domain.NewMoney(400.00),
 			StartDate:  time.Now().AddDate(0, 0, 15), // Overlaps with first budget
 			EndDate:    time.Now().AddDate(0, 2, 0),
 		}
-		err = budgetService.CreateBudget(budget2)
+		err = budgetService.CreateBudget(context.Background(), budget2)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "budget already exists")
 	})
@@ -128,33 +130,35 @@ func TestBudgetService_UpdateBudget(t *testing.T) {
 	budget := &domain.Budget{
 		UserID:     userID,
 		CategoryID: categoryID,
-		Amount:     500.00,
+		Amount:     domain.NewMoney(500.00),
 		Period:     "monthly",
 	}
-	err := budgetService.CreateBudget(budget)
+	err := budgetService.CreateBudget(context.Background(), budget)
 	require.NoError(t, err)
 
 	t.Run("successful update", func(t *testing.T) {
 		updates := &domain.Budget{
-			Amount:   750.00,
-			Period:   "weekly",
-			IsActive: false,
+			Amount:    domain.NewMoney(750.00),
+			Period:    "weekly",
+			StartDate: budget.StartDate,
+			EndDate:   budget.StartDate.AddDate(0, 0, 7),
+			IsActive:  false,
 		}
 
-		err := budgetService.UpdateBudget(budget.ID, updates)
+		err := budgetService.UpdateBudget(context.Background(), budget.ID, updates)
 		assert.NoError(t, err)
 
 		// Verify update
-		updated, err := budgetService.GetBudgetByID(budget.ID)
+		updated, err := budgetService.GetBudgetByID(context.Background(), budget.ID)
 		assert.NoError(t, err)
-		assert.Equal(t, 750.00, updated.Amount)
+		assert.Equal(t, domain.NewMoney(750.00), updated.Amount)
 		assert.Equal(t, "weekly", updated.Period)
 		assert.False(t, updated.IsActive)
 	})
 
 	t.Run("update non-existent budget", func(t *testing.T) {
-		updates := &domain.Budget{Amount: 1000.00}
-		err := budgetService.UpdateBudget(99999, updates)
+		updates := &domain.Budget{Amount: domain.NewMoney(1000.00)}
+		err := budgetService.UpdateBudget(context.Background(), 99999, updates)
 		assert.Error(t, err)
 	})
 }
@@ -169,13 +173,13 @@ func TestBudgetService_GetBudgetsByUser(t *testing.T) {
 		{
 			UserID:     userID,
 			CategoryID: categoryID,
-			Amount:     500.00,
+			Amount:     domain.NewMoney(500.00),
 			Period:     "monthly",
 		},
 		{
 			UserID:     userID,
 			CategoryID: categoryID,
-			Amount:     200.00,
+			Amount:     domain.NewMoney(200.00),
 			Period:     "weekly",
 			StartDate:  time.Now().AddDate(0, 1, 0), // Future budget
 			EndDate:    time.Now().AddDate(0, 1, 7),
@@ -183,12 +187,12 @@ func TestBudgetService_GetBudgetsByUser(t *testing.T) {
 	}
 
 	for _, budget := range budgets {
-		err := budgetService.CreateBudget(budget)
+		err := budgetService.CreateBudget(context.Background(), budget)
 		require.NoError(t, err)
 	}
 
 	t.Run("get all user budgets", func(t *testing.T) {
-		result, err := budgetService.GetBudgetsByUser(userID)
+		result, err := budgetService.GetBudgetsByUser(context.Background(), userID)
 		assert.NoError(t, err)
 		assert.Len(t, result, 2)
 		// Should include Category preload
@@ -199,7 +203,7 @@ func TestBudgetService_GetBudgetsByUser(t *testing.T) {
 	})
 
 	t.Run("get budgets for non-existent user", func(t *testing.T) {
-		result, err := budgetService.GetBudgetsByUser(99999)
+		result, err := budgetService.GetBudgetsByUser(context.Background(), 99999)
 		assert.NoError(t, err)
 		assert.Len(t, result, 0)
 	})
@@ -217,7 +221,7 @@ func TestBudgetService_GetActiveBudgetsByUser(t *testing.T) {
 		{
 			UserID:     userID,
 			CategoryID: categoryID,
-			Amount:     500.00,
+			Amount:     domain.NewMoney(500.00),
 			StartDate:  now.AddDate(0, 0, -7),
 			EndDate:    now.AddDate(0, 0, 7), // Active
 			IsActive:   true,
@@ -225,7 +229,7 @@ func TestBudgetService_GetActiveBudgetsByUser(t *testing.T) {
 		{
 			UserID:     userID,
 			CategoryID: categoryID,
-			Amount:     300.00,
+			Amount:     domain.NewMoney(300.00),
 			StartDate:  now.AddDate(0, 0, -30),
 			EndDate:    now.AddDate(0, 0, -1), // Expired
 			IsActive:   true,
@@ -233,7 +237,7 @@ func TestBudgetService_GetActiveBudgetsByUser(t *testing.T) {
 		{
 			UserID:     userID,
 			CategoryID: categoryID,
-			Amount:     200.00,
+			Amount:     domain.NewMoney(200.00),
 			StartDate:  now.AddDate(0, 0, -7),
 			EndDate:    now.AddDate(0, 0, 7),
 			IsActive:   false, // Inactive
@@ -254,7 +258,7 @@ func TestBudgetService_GetActiveBudgetsByUser(t *testing.T) {
 			{
 				UserID:     userID,
 				CategoryID: categoryID,
-				Amount:     500.00,
+				Amount:     domain.NewMoney(500.00),
 				StartDate:  now.AddDate(0, 0, -7),
 				EndDate:    now.AddDate(0, 0, 7),
 				IsActive:   true, // Active and current
@@ -266,10 +270,10 @@ func TestBudgetService_GetActiveBudgetsByUser(t *testing.T) {
 			require.NoError(t, err)
 		}
 
-		result, err := budgetService.GetActiveBudgetsByUser(userID)
+		result, err := budgetService.GetActiveBudgetsByUser(context.Background(), userID)
 		assert.NoError(t, err)
 		assert.Len(t, result, 1) // Only the first budget should be returned
-		assert.Equal(t, 500.00, result[0].Amount)
+		assert.Equal(t, domain.NewMoney(500.00), result[0].Amount)
 		assert.True(t, result[0].IsActive)
 	})
 }
@@ -285,37 +289,37 @@ func TestBudgetService_UpdateBudgetSpending(t *testing.T) {
 	budget := &domain.Budget{
 		UserID:     userID,
 		CategoryID: categoryID,
-		Amount:     500.00,
+		Amount:     domain.NewMoney(500.00),
 		StartDate:  now.AddDate(0, 0, -7),
 		EndDate:    now.AddDate(0, 0, 7),
 		IsActive:   true,
-		Spent:      100.00,
-		Remaining:  400.00,
+		Spent:      domain.NewMoney(100.00),
+		Remaining:  domain.NewMoney(400.00),
 	}
 	err := db.Create(budget).Error
 	require.NoError(t, err)
 
 	t.Run("update spending within budget period", func(t *testing.T) {
 		transactionDate := now.AddDate(0, 0, -2) // Within budget period
-		err := budgetService.UpdateBudgetSpending(userID, categoryID, 50.00, transactionDate)
+		err := budgetService.UpdateBudgetSpending(context.Background(), userID, categoryID, domain.NewMoney(50.00), transactionDate)
 		assert.NoError(t, err)
 
 		// Verify budget was updated
-		updated, err := budgetService.GetBudgetByID(budget.ID)
+		updated, err := budgetService.GetBudgetByID(context.Background(), budget.ID)
 		assert.NoError(t, err)
-		assert.Equal(t, 150.00, updated.Spent)     // 100 + 50
-		assert.Equal(t, 350.00, updated.Remaining) // 500 - 150
+		assert.Equal(t, domain.NewMoney(150.00), updated.Spent)     // 100 + 50
+		assert.Equal(t, domain.NewMoney(350.00), updated.Remaining) // 500 - 150
 	})
 
 	t.Run("transaction outside budget period", func(t *testing.T) {
 		transactionDate := now.AddDate(0, 0, 10) // Outside budget period
-		err := budgetService.UpdateBudgetSpending(userID, categoryID, 25.00, transactionDate)
+		err := budgetService.UpdateBudgetSpending(context.Background(), userID, categoryID, domain.NewMoney(25.00), transactionDate)
 		assert.NoError(t, err)
 
 		// Budget should not be updated
-		updated, err := budgetService.GetBudgetByID(budget.ID)
+		updated, err := budgetService.GetBudgetByID(context.Background(), budget.ID)
 		assert.NoError(t, err)
-		assert.Equal(t, 150.00, updated.Spent) // Should remain the same
+		assert.Equal(t, domain.NewMoney(150.00), updated.Spent) // Should remain the same
 	})
 }
 
@@ -331,8 +335,8 @@ func TestBudgetService_GetBudgetSummary(t *testing.T) {
 		{
 			UserID:     userID,
 			CategoryID: categoryID,
-			Amount:     500.00,
-			Spent:      300.00, // 60% used
+			Amount:     domain.NewMoney(500.00),
+			Spent:      domain.NewMoney(300.00), // 60% used
 			StartDate:  now.AddDate(0, 0, -7),
 			EndDate:    now.AddDate(0, 0, 7),
 			IsActive:   true,
@@ -340,8 +344,8 @@ func TestBudgetService_GetBudgetSummary(t *testing.T) {
 		{
 			UserID:     userID,
 			CategoryID: categoryID,
-			Amount:     300.00,
-			Spent:      350.00, // Over budget
+			Amount:     domain.NewMoney(300.00),
+			Spent:      domain.NewMoney(350.00), // Over budget
 			StartDate:  now.AddDate(0, 0, -7),
 			EndDate:    now.AddDate(0, 0, 7),
 			IsActive:   true,
@@ -354,14 +358,14 @@ func TestBudgetService_GetBudgetSummary(t *testing.T) {
 	}
 
 	t.Run("calculate budget summary", func(t *testing.T) {
-		summary, err := budgetService.GetBudgetSummary(userID)
+		summary, err := budgetService.GetBudgetSummary(context.Background(), userID)
 		assert.NoError(t, err)
 		assert.NotNil(t, summary)
-		assert.Equal(t, 800.00, summary.TotalBudget)     // 500 + 300
-		assert.Equal(t, 650.00, summary.TotalSpent)      // 300 + 350
-		assert.Equal(t, 150.00, summary.TotalRemaining)  // 800 - 650
-		assert.Equal(t, 81.25, summary.PercentageUsed)   // (650/800)*100
-		assert.Equal(t, "warning", summary.BudgetStatus) // >80% but <100%
+		assert.Equal(t, domain.NewMoney(800.00), summary.TotalBudget)    // 500 + 300
+		assert.Equal(t, domain.NewMoney(650.00), summary.TotalSpent)     // 300 + 350
+		assert.Equal(t, domain.NewMoney(150.00), summary.TotalRemaining) // 800 - 650
+		assert.Equal(t, 81.25, summary.PercentageUsed)                   // (650/800)*100
+		assert.Equal(t, "warning", summary.BudgetStatus)                 // >80% but <100%
 	})
 
 	t.Run("over budget status", func(t *testing.T) {
@@ -372,15 +376,15 @@ func TestBudgetService_GetBudgetSummary(t *testing.T) {
 		overBudget := &domain.Budget{
 			UserID:     userID,
 			CategoryID: categoryID,
-			Amount:     100.00,
-			Spent:      200.00, // 200% used
+			Amount:     domain.NewMoney(100.00),
+			Spent:      domain.NewMoney(200.00), // 200% used
 			StartDate:  now.AddDate(0, 0, -7),
 			EndDate:    now.AddDate(0, 0, 7),
 			IsActive:   true,
 		}
 		db.Create(overBudget)
 
-		summary, err := budgetService.GetBudgetSummary(userID)
+		summary, err := budgetService.GetBudgetSummary(context.Background(), userID)
 		assert.NoError(t, err)
 		assert.Equal(t, "over_budget", summary.BudgetStatus)
 	})
@@ -397,7 +401,7 @@ func TestBudgetService_RefreshBudgetSpending(t *testing.T) {
 	budget := &domain.Budget{
 		UserID:     userID,
 		CategoryID: categoryID,
-		Amount:     500.00,
+		Amount:     domain.NewMoney(500.00),
 		Spent:      0.00, // Will be recalculated
 		StartDate:  now.AddDate(0, 0, -7),
 		EndDate:    now.AddDate(0, 0, 7),
@@ -411,21 +415,21 @@ func TestBudgetService_RefreshBudgetSpending(t *testing.T) {
 		{
 			UserID:     userID,
 			CategoryID: categoryID,
-			Amount:     100.00,
+			Amount:     domain.NewMoney(100.00),
 			Type:       "expense",
 			Date:       now.AddDate(0, 0, -3), // Within budget period
 		},
 		{
 			UserID:     userID,
 			CategoryID: categoryID,
-			Amount:     50.00,
+			Amount:     domain.NewMoney(50.00),
 			Type:       "expense",
 			Date:       now.AddDate(0, 0, -1), // Within budget period
 		},
 		{
 			UserID:     userID,
 			CategoryID: categoryID,
-			Amount:     25.00,
+			Amount:     domain.NewMoney(25.00),
 			Type:       "expense",
 			Date:       now.AddDate(0, 0, -10), // Outside budget period
 		},
@@ -437,14 +441,14 @@ func TestBudgetService_RefreshBudgetSpending(t *testing.T) {
 	}
 
 	t.Run("refresh budget spending", func(t *testing.T) {
-		err := budgetService.RefreshBudgetSpending(userID)
+		err := budgetService.RefreshBudgetSpending(context.Background(), userID)
 		assert.NoError(t, err)
 
 		// Verify budget was updated with correct spent amount
-		updated, err := budgetService.GetBudgetByID(budget.ID)
+		updated, err := budgetService.GetBudgetByID(context.Background(), budget.ID)
 		assert.NoError(t, err)
-		assert.Equal(t, 150.00, updated.Spent)     // Only transactions within period: 100 + 50
-		assert.Equal(t, 350.00, updated.Remaining) // 500 - 150
+		assert.Equal(t, domain.NewMoney(150.00), updated.Spent)     // Only transactions within period: 100 + 50
+		assert.Equal(t, domain.NewMoney(350.00), updated.Remaining) // 500 - 150
 	})
 }
 
@@ -457,22 +461,491 @@ func TestBudgetService_DeleteBudget(t *testing.T) {
 	budget := &domain.Budget{
 		UserID:     userID,
 		CategoryID: categoryID,
-		Amount:     500.00,
+		Amount:     domain.NewMoney(500.00),
 	}
-	err := budgetService.CreateBudget(budget)
+	err := budgetService.CreateBudget(context.Background(), budget)
 	require.NoError(t, err)
 
 	t.Run("delete existing budget", func(t *testing.T) {
-		err := budgetService.DeleteBudget(budget.ID)
+		err := budgetService.DeleteBudget(context.Background(), budget.ID)
 		assert.NoError(t, err)
 
 		// Verify deletion
-		_, err = budgetService.GetBudgetByID(budget.ID)
+		_, err = budgetService.GetBudgetByID(context.Background(), budget.ID)
 		assert.Error(t, err)
 	})
 
 	t.Run("delete non-existent budget", func(t *testing.T) {
-		err := budgetService.DeleteBudget(99999)
+		err := budgetService.DeleteBudget(context.Background(), 99999)
 		assert.NoError(t, err) // GORM doesn't return error for non-existent records
 	})
 }
+
+func TestBudgetService_WithMemoryRepositories(t *testing.T) {
+	transactions := persistence.NewMemoryTransactionRepository()
+	service := NewBudgetServiceWithRepositories(persistence.NewMemoryBudgetRepository(), transactions)
+
+	start := time.Now().Truncate(24 * time.Hour)
+	budget := &domain.Budget{UserID: 1, CategoryID: 2, Amount: domain.NewMoney(500), StartDate: start}
+	require.NoError(t, service.CreateBudget(context.Background(), budget))
+	assert.Equal(t, start.AddDate(0, 1, 0), budget.EndDate)
+
+	duplicate := &domain.Budget{UserID: 1, CategoryID: 2, Amount: domain.NewMoney(300), StartDate: start, EndDate: budget.EndDate}
+	assert.Error(t, service.CreateBudget(context.Background(), duplicate))
+
+	require.NoError(t, transactions.Create(context.Background(), &domain.Transaction{
+		UserID: 1, CategoryID: 2, Type: "expense", Amount: domain.NewMoney(125), Date: start.Add(time.Hour),
+	}))
+	require.NoError(t, service.RefreshBudgetSpending(context.Background(), 1))
+
+	refreshed, err := service.GetBudgetByID(context.Background(), budget.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.NewMoney(125), refreshed.Spent)
+	assert.Equal(t, domain.NewMoney(375), refreshed.Remaining)
+
+	summary, err := service.GetBudgetSummary(context.Background(), 1)
+	require.NoError(t, err)
+	assert.InDelta(t, 25, summary.PercentageUsed, 0.001)
+
+	_, err = service.GetBudgetByID(context.Background(), 999)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestBudgetService_CategoryHierarchy(t *testing.T) {
+	db := setupBudgetTestDB(t)
+	userID, foodID := createBudgetTestData(t, db)
+	groceries := &domain.Category{Name: "Groceries", Type: "expense", ParentID: &foodID}
+	restaurants := &domain.Category{Name: "Restaurants", Type: "expense", ParentID: &foodID}
+	require.NoError(t, db.Create(groceries).Error)
+	require.NoError(t, db.Create(restaurants).Error)
+
+	service := NewBudgetService(db)
+	ctx := context.Background()
+	start := time.Now().Truncate(24 * time.Hour)
+
+	foodBudget := &domain.Budget{UserID: userID, CategoryID: foodID, Amount: domain.NewMoney(600), StartDate: start}
+	groceryBudget := &domain.Budget{UserID: userID, CategoryID: groceries.ID, Amount: domain.NewMoney(400), StartDate: start}
+	require.NoError(t, service.CreateBudget(ctx, foodBudget))
+	require.NoError(t, service.CreateBudget(ctx, groceryBudget))
+
+	for _, tx := range []domain.Transaction{
+		{UserID: userID, CategoryID: groceries.ID, Type: "expense", Amount: domain.NewMoney(150), Date: start.Add(time.Hour)},
+		{UserID: userID, CategoryID: restaurants.ID, Type: "expense", Amount: domain.NewMoney(90), Date: start.Add(time.Hour)},
+		{UserID: userID, CategoryID: foodID, Type: "expense", Amount: domain.NewMoney(60), Date: start.Add(time.Hour)},
+	} {
+		require.NoError(t, db.Create(&tx).Error)
+	}
+
+	t.Run("refresh rolls children into the parent budget", func(t *testing.T) {
+		require.NoError(t, service.RefreshBudgetSpending(ctx, userID))
+
+		food, err := service.GetBudgetByID(ctx, foodBudget.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.NewMoney(300), food.Spent)
+
+		grocery, err := service.GetBudgetByID(ctx, groceryBudget.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.NewMoney(150), grocery.Spent)
+	})
+
+	t.Run("spending on a child updates both levels", func(t *testing.T) {
+		require.NoError(t, service.UpdateBudgetSpending(ctx, userID, groceries.ID, domain.NewMoney(50), start.Add(2*time.Hour)))
+
+		food, err := service.GetBudgetByID(ctx, foodBudget.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.NewMoney(350), food.Spent)
+
+		grocery, err := service.GetBudgetByID(ctx, groceryBudget.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.NewMoney(200), grocery.Spent)
+	})
+
+	t.Run("summary does not count nested budgets twice", func(t *testing.T) {
+		summary, err := service.GetBudgetSummary(ctx, userID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.NewMoney(600), summary.TotalBudget)
+		assert.Equal(t, domain.NewMoney(350), summary.TotalSpent)
+	})
+}
+
+func TestBudgetService_SpendingFollowsTransactions(t *testing.T) {
+	db := setupBudgetTestDB(t)
+	budgetService := &BudgetService{DB: db}
+	txService := &TransactionService{DB: db, Budgets: budgetService}
+	userID, foodID := createBudgetTestData(t, db)
+	ctx := context.Background()
+
+	travel := &domain.Category{Name: "Travel", Type: "expense"}
+	require.NoError(t, db.Create(travel).Error)
+
+	now := time.Now()
+	foodBudget := &domain.Budget{
+		UserID: userID, CategoryID: foodID, Amount: domain.NewMoney(500), Remaining: domain.NewMoney(500),
+		StartDate: now.AddDate(0, 0, -7), EndDate: now.AddDate(0, 0, 7), IsActive: true,
+	}
+	travelBudget := &domain.Budget{
+		UserID: userID, CategoryID: travel.ID, Amount: domain.NewMoney(300), Remaining: domain.NewMoney(300),
+		StartDate: now.AddDate(0, 0, -7), EndDate: now.AddDate(0, 0, 7), IsActive: true,
+	}
+	require.NoError(t, db.Create(foodBudget).Error)
+	require.NoError(t, db.Create(travelBudget).Error)
+
+	spent := func(budgetID uint) float64 {
+		budget, err := budgetService.GetBudgetByID(ctx, budgetID)
+		require.NoError(t, err)
+		assert.Equal(t, budget.Amount-budget.Spent, budget.Remaining)
+		return budget.Spent.Float64()
+	}
+
+	transaction := &domain.Transaction{
+		UserID: userID, CategoryID: foodID, Description: "Groceries", Amount: domain.NewMoney(80), Type: "expense", Date: now.AddDate(0, 0, -1),
+	}
+	require.NoError(t, txService.Create(ctx, transaction))
+	assert.Equal(t, 80.0, spent(foodBudget.ID))
+
+	t.Run("edited amount", func(t *testing.T) {
+		transaction.Amount = domain.NewMoney(120)
+		require.NoError(t, txService.Update(ctx, transaction))
+		assert.Equal(t, 120.0, spent(foodBudget.ID))
+	})
+
+	t.Run("re-categorized", func(t *testing.T) {
+		transaction.CategoryID = travel.ID
+		require.NoError(t, txService.Update(ctx, transaction))
+		assert.Equal(t, 0.0, spent(foodBudget.ID))
+		assert.Equal(t, 120.0, spent(travelBudget.ID))
+	})
+
+	t.Run("moved out of the period", func(t *testing.T) {
+		transaction.Date = now.AddDate(0, 0, -30)
+		require.NoError(t, txService.Update(ctx, transaction))
+		assert.Equal(t, 0.0, spent(travelBudget.ID))
+
+		transaction.Date = now.AddDate(0, 0, -1)
+		require.NoError(t, txService.Update(ctx, transaction))
+		assert.Equal(t, 120.0, spent(travelBudget.ID))
+	})
+
+	t.Run("deleted and restored", func(t *testing.T) {
+		require.NoError(t, txService.Delete(ctx, transaction.ID))
+		assert.Equal(t, 0.0, spent(travelBudget.ID))
+
+		_, err := txService.Restore(ctx, userID, transaction.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 120.0, spent(travelBudget.ID))
+	})
+}
+
+func TestBudgetService_AnchoredPeriods(t *testing.T) {
+	db := setupBudgetTestDB(t)
+	service := &BudgetService{DB: db}
+	userID, foodID := createBudgetTestData(t, db)
+	ctx := context.Background()
+
+	day := func(month time.Month, d int) time.Time { return time.Date(2024, month, d, 0, 0, 0, 0, time.UTC) }
+	for _, tx := range []domain.Transaction{
+		{UserID: userID, CategoryID: foodID, Type: "expense", Amount: domain.NewMoney(40), Date: day(1, 24)},
+		{UserID: userID, CategoryID: foodID, Type: "expense", Amount: domain.NewMoney(60), Date: day(1, 25)},
+		{UserID: userID, CategoryID: foodID, Type: "expense", Amount: domain.NewMoney(30), Date: day(2, 24).Add(20 * time.Hour)},
+		{UserID: userID, CategoryID: foodID, Type: "expense", Amount: domain.NewMoney(90), Date: day(2, 25)},
+	} {
+		require.NoError(t, db.Create(&tx).Error)
+	}
+
+	budget := &domain.Budget{
+		UserID: userID, CategoryID: foodID, Amount: domain.NewMoney(500), Period: domain.PeriodMonthly,
+		Anchor: domain.BudgetAnchorPayday, AnchorDay: 25, StartDate: day(2, 10), EndDate: day(3, 10),
+	}
+	require.NoError(t, service.CreateBudget(ctx, budget))
+	assert.Equal(t, day(1, 25), budget.StartDate, "the cycle started on the last payday")
+	assert.Equal(t, day(2, 25).Add(-time.Nanosecond), budget.EndDate)
+	assert.Equal(t, 90.0, budget.Spent.Float64(), "spending before the budget was created counts, the next payday's does not")
+
+	t.Run("other budgets of the cycle overlap", func(t *testing.T) {
+		err := service.CreateBudget(ctx, &domain.Budget{
+			UserID: userID, CategoryID: foodID, Amount: domain.NewMoney(300), Period: domain.PeriodMonthly,
+			Anchor: domain.BudgetAnchorCalendar, StartDate: day(2, 1),
+		})
+		assert.ErrorIs(t, err, domain.ErrBudgetExists)
+	})
+
+	t.Run("switching to calendar months realigns the spending", func(t *testing.T) {
+		updates := *budget
+		updates.Anchor = domain.BudgetAnchorCalendar
+		updates.StartDate = day(2, 10)
+		require.NoError(t, service.UpdateBudget(ctx, budget.ID, &updates))
+
+		updated, err := service.GetBudgetByID(ctx, budget.ID)
+		require.NoError(t, err)
+		assert.Equal(t, day(2, 1), updated.StartDate)
+		assert.Zero(t, updated.AnchorDay)
+		assert.Equal(t, 120.0, updated.Spent.Float64())
+	})
+
+	t.Run("start date budgets keep their dates", func(t *testing.T) {
+		travel := &domain.Category{Name: "Travel", Type: "expense"}
+		require.NoError(t, db.Create(travel).Error)
+		budget := &domain.Budget{UserID: userID, CategoryID: travel.ID, Amount: domain.NewMoney(100), StartDate: day(2, 10)}
+		require.NoError(t, service.CreateBudget(ctx, budget))
+		assert.Equal(t, domain.BudgetAnchorStartDate, budget.Anchor)
+		assert.Equal(t, day(2, 10), budget.StartDate)
+		assert.Equal(t, day(3, 10), budget.EndDate)
+	})
+}
+
+func TestBudgetService_ReconcileSpending(t *testing.T) {
+	db := setupBudgetTestDB(t)
+	budgetService := &BudgetService{DB: db}
+	userID, categoryID := createBudgetTestData(t, db)
+	ctx := context.Background()
+
+	now := time.Now()
+	stale := &domain.Budget{
+		UserID: userID, CategoryID: categoryID, Amount: domain.NewMoney(500), Spent: domain.NewMoney(10), Remaining: domain.NewMoney(490),
+		StartDate: now.AddDate(0, 0, -7), EndDate: now.AddDate(0, 0, 7), IsActive: true,
+	}
+	current := &domain.Budget{
+		UserID: userID, CategoryID: categoryID, Amount: domain.NewMoney(200), Spent: 0, Remaining: domain.NewMoney(200),
+		StartDate: now.AddDate(0, -2, 0), EndDate: now.AddDate(0, -1, 0), IsActive: true,
+	}
+	require.NoError(t, db.Create(stale).Error)
+	require.NoError(t, db.Create(current).Error)
+
+	// Written directly, so no hook has updated the budget
+	require.NoError(t, db.Create(&domain.Transaction{
+		UserID: userID, CategoryID: categoryID, Amount: domain.NewMoney(75), Type: "expense", Date: now,
+	}).Error)
+
+	corrected, err := budgetService.ReconcileSpending(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, corrected)
+
+	updated, err := budgetService.GetBudgetByID(ctx, stale.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.NewMoney(75.0), updated.Spent)
+	assert.Equal(t, domain.NewMoney(425.0), updated.Remaining)
+
+	t.Run("nothing left to correct", func(t *testing.T) {
+		corrected, err := budgetService.ReconcileSpending(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 0, corrected)
+	})
+}
+
+func TestBudgetService_CreateBudgetsFromTemplate(t *testing.T) {
+	ctx := context.Background()
+	db := setupBudgetTestDB(t)
+	service := &BudgetService{DB: db}
+	for _, category := range domain.GetDefaultCategories() {
+		require.NoError(t, db.Create(&category).Error)
+	}
+	var salary, housing domain.Category
+	require.NoError(t, db.Where("name = ?", "Salary").First(&salary).Error)
+	require.NoError(t, db.Where("name = ?", "Housing").First(&housing).Error)
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("should require an income without history", func(t *testing.T) {
+		_, err := service.CreateBudgetsFromTemplate(ctx, 1, domain.BudgetTemplateRequest{Template: domain.BudgetTemplateStudent, StartDate: start})
+		assert.ErrorIs(t, err, domain.ErrValidation)
+	})
+
+	t.Run("should reject unknown templates", func(t *testing.T) {
+		_, err := service.CreateBudgetsFromTemplate(ctx, 1, domain.BudgetTemplateRequest{Template: "lavish"})
+		assert.ErrorIs(t, err, domain.ErrValidation)
+	})
+
+	t.Run("should split the average income", func(t *testing.T) {
+		// 3000 in February and 5000 in March average to 4000; May is after the window
+		for _, income := range []struct {
+			amount float64
+			date   time.Time
+		}{
+			{3000, time.Date(2024, 2, 25, 0, 0, 0, 0, time.UTC)},
+			{5000, time.Date(2024, 3, 25, 0, 0, 0, 0, time.UTC)},
+			{9000, time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)},
+		} {
+			require.NoError(t, db.Create(&domain.Transaction{
+				UserID: 2, CategoryID: salary.ID, Type: domain.TransactionTypeIncome, Amount: domain.NewMoney(income.amount), Date: income.date,
+			}).Error)
+		}
+		// An existing housing budget is kept
+		require.NoError(t, service.CreateBudget(ctx, &domain.Budget{UserID: 2, CategoryID: housing.ID, Amount: domain.NewMoney(1500), StartDate: start}))
+
+		result, err := service.CreateBudgetsFromTemplate(ctx, 2, domain.BudgetTemplateRequest{Template: domain.BudgetTemplateFiftyThirtyTwenty, StartDate: start})
+		require.NoError(t, err)
+
+		assert.Equal(t, domain.NewMoney(4000), result.MonthlyIncome)
+		assert.Equal(t, "average", result.IncomeSource)
+		assert.Equal(t, 2, result.MonthsAnalyzed)
+		assert.Equal(t, domain.NewMoney(800), result.Savings)
+		require.Len(t, result.Skipped, 1)
+		assert.Equal(t, "Housing", result.Skipped[0].Category)
+		assert.Equal(t, domain.NewMoney(1000), result.Skipped[0].Amount)
+		assert.Len(t, result.Budgets, 8)
+		for _, budget := range result.Budgets {
+			assert.Equal(t, domain.PeriodMonthly, budget.Period)
+			assert.Equal(t, start, budget.StartDate)
+		}
+
+		budgets, err := service.GetBudgetsByUser(ctx, 2)
+		require.NoError(t, err)
+		assert.Len(t, budgets, 9)
+	})
+
+	t.Run("should use the given income", func(t *testing.T) {
+		income := domain.NewMoney(2000)
+		result, err := service.CreateBudgetsFromTemplate(ctx, 3, domain.BudgetTemplateRequest{
+			Template: domain.BudgetTemplateStudent, MonthlyIncome: &income, StartDate: start,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "provided", result.IncomeSource)
+		assert.Empty(t, result.Skipped)
+		assert.Equal(t, domain.NewMoney(100), result.Savings)
+	})
+}
+
+func TestBudgetService_BudgetPlans(t *testing.T) {
+	ctx := context.Background()
+	db := setupBudgetTestDB(t)
+	service := &BudgetService{DB: db}
+	for _, category := range domain.GetDefaultCategories() {
+		require.NoError(t, db.Create(&category).Error)
+	}
+	var dining, transport domain.Category
+	require.NoError(t, db.Where("name = ?", "Food & Dining").First(&dining).Error)
+	require.NoError(t, db.Where("name = ?", "Transportation").First(&transport).Error)
+	coach, client := uint(1), uint(2)
+	coaching := domain.Category{UserID: &coach, Name: "Coaching", Type: domain.TransactionTypeExpense}
+	mentoring := domain.Category{UserID: &client, Name: "Mentoring", Type: domain.TransactionTypeExpense}
+	require.NoError(t, db.Create(&coaching).Error)
+	require.NoError(t, db.Create(&mentoring).Error)
+	now := time.Now()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	for _, budget := range []domain.Budget{
+		{UserID: coach, CategoryID: dining.ID, Amount: domain.NewMoney(400), StartDate: start},
+		{UserID: coach, CategoryID: transport.ID, Amount: domain.NewMoney(150), StartDate: start},
+		{UserID: coach, CategoryID: coaching.ID, Amount: domain.NewMoney(100), StartDate: start},
+		{UserID: client, CategoryID: dining.ID, Amount: domain.NewMoney(300), StartDate: start},
+	} {
+		require.NoError(t, service.CreateBudget(ctx, &budget))
+	}
+
+	plan, err := service.ExportBudgetPlan(ctx, coach, " Coach plan ")
+	require.NoError(t, err)
+	assert.Equal(t, domain.BudgetPlanVersion, plan.Version)
+	assert.Equal(t, "Coach plan", plan.Name)
+	require.Len(t, plan.Budgets, 3)
+	plan.Budgets = append(plan.Budgets, domain.BudgetPlanItem{Category: "Yacht", Amount: domain.NewMoney(5000), Period: domain.PeriodMonthly})
+	req := domain.BudgetPlanImport{Plan: *plan, CategoryMap: map[uint]uint{coaching.ID: mentoring.ID}, StartDate: start}
+
+	t.Run("previews without changing budgets", func(t *testing.T) {
+		preview, err := service.PreviewBudgetPlan(ctx, client, req)
+		require.NoError(t, err)
+		assert.False(t, preview.Applied)
+		assert.Equal(t, 2, preview.Created)
+		assert.Equal(t, 1, preview.Updated)
+		assert.Equal(t, 1, preview.Skipped)
+		for _, change := range preview.Changes {
+			switch change.Category {
+			case "Food & Dining":
+				assert.Equal(t, domain.BudgetPlanActionUpdate, change.Action)
+				assert.Equal(t, domain.NewMoney(300), *change.Current)
+			case "Coaching":
+				assert.Equal(t, mentoring.ID, change.CategoryID, "mapped categories replace the plan's")
+			case "Yacht":
+				assert.Equal(t, "category not found", change.Reason)
+			}
+		}
+
+		budgets, err := service.GetBudgetsByUser(ctx, client)
+		require.NoError(t, err)
+		assert.Len(t, budgets, 1)
+	})
+
+	t.Run("applies the plan", func(t *testing.T) {
+		result, err := service.ImportBudgetPlan(ctx, client, req)
+		require.NoError(t, err)
+		assert.True(t, result.Applied)
+		assert.Equal(t, 2, result.Created)
+
+		budgets, err := service.GetBudgetsByUser(ctx, client)
+		require.NoError(t, err)
+		amounts := map[uint]domain.Money{}
+		for _, budget := range budgets {
+			amounts[budget.CategoryID] = budget.Amount
+		}
+		assert.Equal(t, map[uint]domain.Money{
+			dining.ID: domain.NewMoney(400), transport.ID: domain.NewMoney(150), mentoring.ID: domain.NewMoney(100),
+		}, amounts)
+
+		again, err := service.PreviewBudgetPlan(ctx, client, req)
+		require.NoError(t, err)
+		assert.Equal(t, 3, again.Unchanged, "applying a plan twice changes nothing")
+	})
+
+	t.Run("rejects invalid plans", func(t *testing.T) {
+		_, err := service.PreviewBudgetPlan(ctx, client, domain.BudgetPlanImport{Plan: domain.BudgetPlan{Version: 2}})
+		assert.ErrorIs(t, err, domain.ErrValidation)
+	})
+}
+
+func TestBudgetService_SimulateBudget(t *testing.T) {
+	ctx := context.Background()
+	db := setupBudgetTestDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.FinancialGoal{}))
+	service := &BudgetService{DB: db}
+	for _, category := range domain.GetDefaultCategories() {
+		require.NoError(t, db.Create(&category).Error)
+	}
+	var salary, dining, transport domain.Category
+	require.NoError(t, db.Where("name = ?", "Salary").First(&salary).Error)
+	require.NoError(t, db.Where("name = ?", "Food & Dining").First(&dining).Error)
+	require.NoError(t, db.Where("name = ?", "Transportation").First(&transport).Error)
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	// March and April are analyzed: 4000 income and 600 dining a month
+	for _, tx := range []domain.Transaction{
+		{CategoryID: salary.ID, Type: domain.TransactionTypeIncome, Amount: domain.NewMoney(4000), Date: time.Date(2024, 3, 25, 0, 0, 0, 0, time.UTC)},
+		{CategoryID: salary.ID, Type: domain.TransactionTypeIncome, Amount: domain.NewMoney(4000), Date: time.Date(2024, 4, 25, 0, 0, 0, 0, time.UTC)},
+		{CategoryID: dining.ID, Type: domain.TransactionTypeExpense, Amount: domain.NewMoney(500), Date: time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)},
+		{CategoryID: dining.ID, Type: domain.TransactionTypeExpense, Amount: domain.NewMoney(700), Date: time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC)},
+		{CategoryID: dining.ID, Type: domain.TransactionTypeExpense, Amount: domain.NewMoney(900), Date: time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)},
+	} {
+		tx.UserID = 1
+		require.NoError(t, db.Create(&tx).Error)
+	}
+	require.NoError(t, db.Create(&domain.FinancialGoal{
+		UserID: 1, Title: "Car", TargetAmount: domain.NewMoney(10000), GoalType: "savings", Status: "active",
+		TargetDate: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
+	}).Error)
+	require.NoError(t, db.Create(&domain.FinancialGoal{
+		UserID: 1, Title: "Done", TargetAmount: domain.NewMoney(100), GoalType: "savings", Status: "completed",
+	}).Error)
+
+	cut := -30.0
+	extra := domain.NewMoney(200)
+	simulation, err := service.SimulateBudget(ctx, 1, domain.BudgetSimulationRequest{
+		Changes:   []domain.BudgetChange{{CategoryID: dining.ID, Percent: &cut}, {CategoryID: transport.ID, Amount: &extra}},
+		StartDate: start,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, simulation.MonthsAnalyzed)
+	assert.Equal(t, domain.NewMoney(3400), simulation.Baseline.MonthlySavings)
+	assert.Equal(t, domain.NewMoney(3380), simulation.Simulated.MonthlySavings)
+	require.Len(t, simulation.Categories, 2)
+	assert.Equal(t, "Food & Dining", simulation.Categories[0].CategoryName)
+	assert.Equal(t, domain.NewMoney(420), simulation.Categories[0].Simulated)
+	assert.Equal(t, "Transportation", simulation.Categories[1].CategoryName)
+	require.Len(t, simulation.CashFlow, domain.DefaultSimulationMonths)
+	require.Len(t, simulation.Goals, 1, "only active goals are projected")
+	assert.True(t, simulation.Goals[0].OnTrack)
+
+	t.Run("should reject categories the user cannot budget", func(t *testing.T) {
+		_, err := service.SimulateBudget(ctx, 1, domain.BudgetSimulationRequest{
+			Changes: []domain.BudgetChange{{CategoryID: salary.ID, Amount: &extra}},
+		})
+		assert.ErrorIs(t, err, domain.ErrValidation)
+	})
+}
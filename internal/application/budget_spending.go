@@ -0,0 +1,169 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+)
+
+// SyncTransactionSpending recalculates the budgets a transaction counts
+// towards: active budgets of its user, or of its household if it is shared,
+// on its category or a parent of it whose period includes the transaction
+// date. Pass both versions of an edited transaction
+// so the budgets it left are corrected along with the ones it moved to.
+func (s *BudgetService) SyncTransactionSpending(ctx context.Context, transactions ...domain.Transaction) error {
+	parents, err := s.categoryParents(ctx)
+	if err != nil {
+		return err
+	}
+
+	active := true
+	seen := make(map[uint]bool)
+	var affected []domain.Budget
+	for i := range transactions {
+		date := transactions[i].Date
+		filter := domain.BudgetFilter{
+			UserID:       transactions[i].UserID,
+			PersonalOnly: true,
+			CategoryIDs:  parents.Ancestors(transactions[i].CategoryID),
+			IsActive:     &active,
+			StartsBefore: &date,
+			EndsAfter:    &date,
+		}
+		if householdID := transactions[i].HouseholdID; householdID != nil {
+			filter.UserID, filter.PersonalOnly, filter.HouseholdID = 0, false, householdID
+		}
+		budgets, err := s.budgets().Find(ctx, filter)
+		if err != nil {
+			return err
+		}
+		for j := range budgets {
+			if !seen[budgets[j].ID] {
+				seen[budgets[j].ID] = true
+				affected = append(affected, budgets[j])
+			}
+		}
+	}
+
+	s.recalculate(ctx, affected, parents)
+	return nil
+}
+
+// Subscribe keeps budget spending up to date with the transaction events
+// published on the bus
+func (s *BudgetService) Subscribe(bus *EventBus) {
+	bus.Subscribe(s.handleEvent, transactionEvents...)
+}
+
+func (s *BudgetService) handleEvent(ctx context.Context, event domain.Event) {
+	if transactions := domain.Transactions(event); len(transactions) > 0 {
+		s.syncSpending(ctx, transactions...)
+	}
+}
+
+// syncSpending runs SyncTransactionSpending for transaction events.
+// Failures are logged rather than failing the transaction write; the
+// reconcile job corrects the budgets later.
+func (s *BudgetService) syncSpending(ctx context.Context, transactions ...domain.Transaction) {
+	if s == nil {
+		return
+	}
+	if err := s.SyncTransactionSpending(ctx, transactions...); err != nil {
+		log.Printf("budgets: failed to update spending: %v", err)
+	}
+}
+
+// ReconcileSpending recalculates the active budgets of all users and returns
+// how many had a stale spent amount
+func (s *BudgetService) ReconcileSpending(ctx context.Context) (int, error) {
+	if s.DB == nil {
+		return 0, errors.New("reconciling budgets needs a database")
+	}
+
+	var userIDs []uint
+	err := s.DB.WithContext(ctx).Model(&domain.Budget{}).Where("is_active = ?", true).
+		Distinct("user_id").Pluck("user_id", &userIDs).Error
+	if err != nil {
+		return 0, err
+	}
+	parents, err := s.categoryParents(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	active := true
+	corrected := 0
+	for _, userID := range userIDs {
+		budgets, err := s.budgets().Find(ctx, domain.BudgetFilter{UserID: userID, IsActive: &active})
+		if err != nil {
+			return corrected, err
+		}
+		corrected += s.recalculate(ctx, budgets, parents)
+	}
+	return corrected, nil
+}
+
+// StartSpendingReconcile runs ReconcileSpending on the given interval until ctx is cancelled
+func (s *BudgetService) StartSpendingReconcile(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if corrected, err := s.ReconcileSpending(ctx); err != nil {
+			log.Printf("budget reconcile failed: %v", err)
+		} else if corrected > 0 {
+			log.Printf("corrected spending of %d budget(s)", corrected)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// recalculate sets each budget's spent amount from the transactions in its
+// period and saves the ones that changed, returning how many were saved.
+// Budgets whose spending goes over their amount are announced as exceeded.
+// Personal budgets count the user's unshared transactions and household
+// budgets everything shared with the household. Budgets whose spending
+// cannot be summed are left as they are.
+func (s *BudgetService) recalculate(ctx context.Context, budgets []domain.Budget, parents domain.CategoryParents) int {
+	changed := 0
+	for i := range budgets {
+		filter := domain.TransactionFilter{
+			UserID:           budgets[i].UserID,
+			PersonalOnly:     true,
+			ExcludeTransfers: true,
+			CategoryIDs:      parents.Descendants(budgets[i].CategoryID),
+			StartDate:        &budgets[i].StartDate,
+			EndDate:          &budgets[i].EndDate,
+		}
+		if householdID := budgets[i].HouseholdID; householdID != nil {
+			filter.UserID, filter.PersonalOnly, filter.HouseholdID = 0, false, householdID
+		}
+		totalSpent, err := s.transactions().Sum(ctx, filter)
+		if err != nil {
+			continue
+		}
+		if budgets[i].Spent == totalSpent && budgets[i].Remaining == budgets[i].Amount-totalSpent {
+			continue
+		}
+
+		wasExceeded := budgets[i].Spent > budgets[i].Amount
+		budgets[i].Spent = totalSpent
+		budgets[i].CalculateRemaining()
+		if err := s.budgets().Update(ctx, &budgets[i]); err == nil {
+			changed++
+			invalidateUsers(ctx, s.Cache, budgets[i].UserID)
+			if !wasExceeded && budgets[i].Spent > budgets[i].Amount {
+				s.Events.Publish(ctx, domain.BudgetExceeded{Budget: budgets[i]})
+			}
+		}
+	}
+	return changed
+}
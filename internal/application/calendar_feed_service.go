@@ -0,0 +1,230 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/i18n"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CalendarFeedService serves users' upcoming bills, recurring transactions,
+// budget period ends and goal deadlines as an iCal feed calendar apps
+// subscribe to. Calendar apps cannot log in, so the feed's link carries a
+// token signed with SigningKey.
+type CalendarFeedService struct {
+	DB         *gorm.DB
+	Bills      *BillService
+	Budgets    *BudgetService
+	SigningKey []byte
+	// BaseURL is the public address of the API's /api/v1 routes, e.g.
+	// https://api.example.com/api/v1
+	BaseURL string
+}
+
+func NewCalendarFeedService(db *gorm.DB, bills *BillService, budgets *BudgetService, signingKey []byte) *CalendarFeedService {
+	return &CalendarFeedService{DB: db, Bills: bills, Budgets: budgets, SigningKey: signingKey}
+}
+
+// Link returns the URL of the user's feed, creating the feed on first use
+func (s *CalendarFeedService) Link(ctx context.Context, userID uint) (*domain.CalendarFeedLink, error) {
+	feed := domain.CalendarFeed{UserID: userID, Generation: 1}
+	err := s.DB.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&feed).Error
+	if err != nil {
+		return nil, err
+	}
+	if err := s.DB.WithContext(ctx).Where("user_id = ?", userID).First(&feed).Error; err != nil {
+		return nil, err
+	}
+	return s.link(feed), nil
+}
+
+// Rotate replaces the user's feed link; calendars subscribed to earlier
+// links stop getting updates
+func (s *CalendarFeedService) Rotate(ctx context.Context, userID uint) (*domain.CalendarFeedLink, error) {
+	if _, err := s.Link(ctx, userID); err != nil {
+		return nil, err
+	}
+	err := s.DB.WithContext(ctx).Model(&domain.CalendarFeed{}).Where("user_id = ?", userID).
+		Update("generation", gorm.Expr("generation + 1")).Error
+	if err != nil {
+		return nil, err
+	}
+	return s.Link(ctx, userID)
+}
+
+// Verify checks the token of a feed link, returning
+// domain.ErrInvalidCalendarFeedToken when it is not the user's current one
+func (s *CalendarFeedService) Verify(ctx context.Context, userID uint, token string) error {
+	var feed domain.CalendarFeed
+	err := s.DB.WithContext(ctx).Where("user_id = ?", userID).First(&feed).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.ErrInvalidCalendarFeedToken
+	}
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal([]byte(token), []byte(s.sign(feed))) {
+		return domain.ErrInvalidCalendarFeedToken
+	}
+	return nil
+}
+
+// Events returns the user's events from the day of now to
+// domain.CalendarFeedHorizonDays later, in date order
+func (s *CalendarFeedService) Events(ctx context.Context, userID uint, now time.Time, l *i18n.Localizer) ([]domain.CalendarEvent, error) {
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, domain.CalendarFeedHorizonDays)
+	var events []domain.CalendarEvent
+
+	calendar, err := s.Bills.Calendar(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range calendar.Entries {
+		day := entry.Date.Format("20060102")
+		event := domain.CalendarEvent{
+			Date: entry.Date, Kind: entry.Kind,
+			Description: l.Money(entry.Amount, domain.BaseCurrency),
+		}
+		if entry.Kind == domain.CalendarEntryBill {
+			event.UID = fmt.Sprintf("bill-%d-%s", *entry.BillID, day)
+			event.Summary = l.T("calendar.bill_due", map[string]any{"Title": entry.Title})
+		} else {
+			event.UID = fmt.Sprintf("recurring-%s-%s", strings.ToLower(strings.Join(strings.Fields(entry.Title), "-")), day)
+			event.Summary = l.T("calendar.recurring_"+entry.Type, map[string]any{"Title": entry.Title})
+		}
+		events = append(events, event)
+	}
+
+	budgets, err := s.Budgets.GetActiveBudgetsByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, budget := range budgets {
+		if budget.EndDate.Before(from) || budget.EndDate.After(to) {
+			continue
+		}
+		events = append(events, domain.CalendarEvent{
+			UID:         fmt.Sprintf("budget-%d-%s", budget.ID, budget.EndDate.Format("20060102")),
+			Date:        budget.EndDate,
+			Kind:        domain.CalendarEventBudgetEnd,
+			Summary:     l.T("calendar.budget_ends", map[string]any{"Category": l.Category(budget.Category.Name)}),
+			Description: l.T("digest.budget_spent", map[string]any{"Spent": l.Money(budget.Spent, domain.BaseCurrency), "Amount": l.Money(budget.Amount, domain.BaseCurrency)}),
+		})
+	}
+
+	var goals []domain.FinancialGoal
+	err = s.DB.WithContext(ctx).Where("user_id = ? AND status = ? AND target_date BETWEEN ? AND ?", userID, "active", from, to).
+		Order("target_date, id").Find(&goals).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, goal := range goals {
+		events = append(events, domain.CalendarEvent{
+			UID:     fmt.Sprintf("goal-%d", goal.ID),
+			Date:    goal.TargetDate,
+			Kind:    domain.CalendarEventGoal,
+			Summary: l.T("calendar.goal_deadline", map[string]any{"Title": goal.Title}),
+			Description: l.T("calendar.goal_progress", map[string]any{
+				"Current": l.Money(goal.CurrentAmount, domain.BaseCurrency), "Target": l.Money(goal.TargetAmount, domain.BaseCurrency),
+			}),
+		})
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Date.Before(events[j].Date) })
+	return events, nil
+}
+
+// Feed returns the user's events as an iCal calendar in their language
+func (s *CalendarFeedService) Feed(ctx context.Context, userID uint, now time.Time) ([]byte, error) {
+	preferences := userPreferences(ctx, s.DB, userID)
+	l := i18n.New(preferences.Locale).WithPreferences(preferences)
+	events, err := s.Events(ctx, userID, now, l)
+	if err != nil {
+		return nil, err
+	}
+	return renderICalendar(l.T("calendar.name"), events, now), nil
+}
+
+func (s *CalendarFeedService) link(feed domain.CalendarFeed) *domain.CalendarFeedLink {
+	query := url.Values{"token": {s.sign(feed)}}
+	return &domain.CalendarFeedLink{
+		URL:       fmt.Sprintf("%s/users/%d/calendar.ics?%s", s.BaseURL, feed.UserID, query.Encode()),
+		UpdatedAt: feed.UpdatedAt,
+	}
+}
+
+func (s *CalendarFeedService) sign(feed domain.CalendarFeed) string {
+	mac := hmac.New(sha256.New, s.SigningKey)
+	fmt.Fprintf(mac, "calendar-feed\n%d\n%d", feed.UserID, feed.Generation)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// renderICalendar writes the events as an RFC 5545 calendar of all-day
+// events
+func renderICalendar(name string, events []domain.CalendarEvent, now time.Time) []byte {
+	var buf bytes.Buffer
+	line := func(content string) {
+		// Lines are folded at 75 octets, without splitting characters. The
+		// space starting a continuation line counts, so those carry 74.
+		limit := 75
+		for len(content) > limit {
+			cut := limit
+			for cut > 0 && !isRuneStart(content[cut]) {
+				cut--
+			}
+			buf.WriteString(content[:cut] + "\r\n ")
+			content = content[cut:]
+			limit = 74
+		}
+		buf.WriteString(content + "\r\n")
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//go-finance-advisor//Calendar Feed//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:" + escapeICal(name))
+	line("REFRESH-INTERVAL;VALUE=DURATION:PT6H")
+	stamp := now.UTC().Format("20060102T150405Z")
+	for _, event := range events {
+		line("BEGIN:VEVENT")
+		line("UID:" + event.UID + "@go-finance-advisor")
+		line("DTSTAMP:" + stamp)
+		line("DTSTART;VALUE=DATE:" + event.Date.Format("20060102"))
+		line("DTEND;VALUE=DATE:" + event.Date.AddDate(0, 0, 1).Format("20060102"))
+		line("SUMMARY:" + escapeICal(event.Summary))
+		if event.Description != "" {
+			line("DESCRIPTION:" + escapeICal(event.Description))
+		}
+		line("CATEGORIES:" + strings.ToUpper(event.Kind))
+		line("TRANSP:TRANSPARENT")
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return buf.Bytes()
+}
+
+// escapeICal escapes the characters iCal text values give a meaning
+func escapeICal(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", "").Replace(text)
+}
+
+// isRuneStart reports whether the byte starts a UTF-8 encoded character
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
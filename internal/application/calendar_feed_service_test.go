@@ -0,0 +1,132 @@
+package application
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/i18n"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupCalendarFeedTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&domain.User{}, &domain.Preferences{}, &domain.CalendarFeed{}, &domain.Category{},
+		&domain.Transaction{}, &domain.Budget{}, &domain.Bill{}, &domain.FinancialGoal{},
+	))
+	return db
+}
+
+func TestCalendarFeedService(t *testing.T) {
+	db := setupCalendarFeedTestDB(t)
+	ctx := context.Background()
+	service := NewCalendarFeedService(db, &BillService{DB: db}, &BudgetService{DB: db}, []byte("secret"))
+	service.BaseURL = "https://api.example.com/api/v1"
+
+	user := domain.User{Email: "ada@example.com", Password: "x", Locale: domain.LocaleEnglish}
+	require.NoError(t, db.Create(&user).Error)
+
+	t.Run("should verify the token of the current link only", func(t *testing.T) {
+		link, err := service.Link(ctx, user.ID)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(link.URL, "https://api.example.com/api/v1/users/1/calendar.ics?token="))
+		token := link.URL[strings.Index(link.URL, "token=")+len("token="):]
+		require.NoError(t, service.Verify(ctx, user.ID, token))
+		assert.ErrorIs(t, service.Verify(ctx, user.ID+1, token), domain.ErrInvalidCalendarFeedToken)
+		assert.ErrorIs(t, service.Verify(ctx, user.ID, ""), domain.ErrInvalidCalendarFeedToken)
+
+		again, err := service.Link(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, link.URL, again.URL)
+
+		rotated, err := service.Rotate(ctx, user.ID)
+		require.NoError(t, err)
+		assert.NotEqual(t, link.URL, rotated.URL)
+		assert.ErrorIs(t, service.Verify(ctx, user.ID, token), domain.ErrInvalidCalendarFeedToken)
+	})
+
+	now := time.Date(2025, time.March, 10, 9, 30, 0, 0, time.UTC)
+	groceries := domain.Category{Name: "Groceries", Type: domain.TransactionTypeExpense}
+	require.NoError(t, db.Create(&groceries).Error)
+	require.NoError(t, db.Create(&domain.Bill{UserID: user.ID, Payee: "Rent, flat; 2", Amount: domain.NewMoney(1200), DueDay: 1, Active: true}).Error)
+	require.NoError(t, db.Create(&domain.Budget{
+		UserID: user.ID, CategoryID: groceries.ID, Amount: domain.NewMoney(400), Period: "monthly",
+		StartDate: time.Now().AddDate(0, 0, -1), EndDate: time.Now().AddDate(0, 0, 20), IsActive: true,
+	}).Error)
+	require.NoError(t, db.Create(&[]domain.FinancialGoal{
+		{UserID: user.ID, Title: "Emergency fund", TargetAmount: domain.NewMoney(5000), CurrentAmount: domain.NewMoney(1000),
+			TargetDate: now.AddDate(0, 2, 0), GoalType: "savings", Status: domain.GoalStatusActive},
+		{UserID: user.ID, Title: "Too far away", TargetAmount: domain.NewMoney(5000),
+			TargetDate: now.AddDate(2, 0, 0), GoalType: "savings", Status: domain.GoalStatusActive},
+	}).Error)
+
+	t.Run("should render bills, budget period ends and goals as all-day events", func(t *testing.T) {
+		feed, err := service.Feed(ctx, user.ID, now)
+		require.NoError(t, err)
+		ics := string(feed)
+
+		assert.True(t, strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+		assert.True(t, strings.HasSuffix(ics, "END:VCALENDAR\r\n"))
+		assert.Equal(t, 7, strings.Count(ics, "BEGIN:VEVENT"), "six monthly bills and the goal")
+		assert.Contains(t, ics, `SUMMARY:Bill due: Rent\, flat\; 2`+"\r\n")
+		assert.Contains(t, ics, "DTSTART;VALUE=DATE:20250401\r\nDTEND;VALUE=DATE:20250402\r\n")
+		assert.Contains(t, ics, "UID:goal-1@go-finance-advisor\r\n")
+		assert.Contains(t, ics, "SUMMARY:Goal deadline: Emergency fund\r\n")
+		assert.NotContains(t, ics, "Too far away")
+		for _, line := range strings.Split(ics, "\r\n") {
+			assert.LessOrEqual(t, len(line), 75)
+		}
+	})
+
+	t.Run("should include the end of active budget periods", func(t *testing.T) {
+		events, err := service.Events(ctx, user.ID, time.Now(), i18n.New(domain.LocaleEnglish))
+		require.NoError(t, err)
+		var kinds []string
+		for _, event := range events {
+			kinds = append(kinds, event.Kind)
+		}
+		assert.Contains(t, kinds, domain.CalendarEventBudgetEnd)
+	})
+}
+
+func TestRenderICalendarFoldsLongLines(t *testing.T) {
+	render := func(summary string) string {
+		return string(renderICalendar("Calendar", []domain.CalendarEvent{
+			{UID: "x", Date: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Kind: domain.CalendarEventGoal, Summary: summary},
+		}, time.Now()))
+	}
+
+	t.Run("should fold every line at 75 octets, the leading space included", func(t *testing.T) {
+		summary := strings.Repeat("abcdefghij", 20)
+		ics := render(summary)
+
+		assert.Contains(t, ics, "SUMMARY:"+summary[:67]+"\r\n "+summary[67:141]+"\r\n "+summary[141:]+"\r\n")
+		for _, line := range strings.Split(ics, "\r\n") {
+			assert.LessOrEqual(t, len(line), 75)
+		}
+	})
+
+	t.Run("should not split characters", func(t *testing.T) {
+		summary := strings.Repeat("ü", 100)
+		ics := render(summary)
+
+		assert.Contains(t, ics, "\r\n ")
+		assert.Contains(t, strings.ReplaceAll(ics, "\r\n ", ""), "SUMMARY:"+summary+"\r\n")
+		for _, line := range strings.Split(ics, "\r\n") {
+			assert.LessOrEqual(t, len(line), 75)
+			assert.True(t, utf8.ValidString(line), line)
+		}
+	})
+}
@@ -0,0 +1,264 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"math"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// JobTypeCategoryModelTraining is the job retraining one user's category model
+const JobTypeCategoryModelTraining = "category_model_training"
+
+// categoryModelJob is the payload of category model training jobs
+type categoryModelJob struct {
+	UserID uint `json:"user_id"`
+}
+
+// CategorizerService learns from each user's categorized transactions which
+// category a description belongs to and suggests categories for new ones.
+// When the user's model is missing or unsure, it falls back to the merchant
+// rules: the merchant the description belongs to and the category the user
+// filed most of that merchant's transactions under.
+type CategorizerService struct {
+	DB            *gorm.DB
+	Jobs          *JobService // Retrains stale models in the background when set
+	MinConfidence float64     // Least confidence a suggestion needs
+	MinSamples    int         // Categorized transactions a model needs to be trained
+}
+
+func NewCategorizerService(db *gorm.DB, minConfidence float64, minSamples int) *CategorizerService {
+	return &CategorizerService{DB: db, MinConfidence: minConfidence, MinSamples: minSamples}
+}
+
+// categorizedTransactions matches the transactions filed under a category,
+// not under none or Uncategorized; its placeholder takes true
+const categorizedTransactions = "category_id <> 0 AND category_id NOT IN (SELECT id FROM categories WHERE is_system = ?)"
+
+// samples returns the user's categorized transactions, oldest first
+func (s *CategorizerService) samples(ctx context.Context, userID uint) ([]domain.CategorySample, error) {
+	var samples []domain.CategorySample
+	err := s.DB.WithContext(ctx).Model(&domain.Transaction{}).
+		Select("description", "type", "category_id").
+		Where("user_id = ? AND "+categorizedTransactions, userID, true).
+		Order("date, id").Scan(&samples).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(samples) < s.MinSamples {
+		return nil, domain.ErrNotEnoughHistory
+	}
+	return samples, nil
+}
+
+// Train trains the user's model on their categorized transactions, replacing
+// the model they had
+func (s *CategorizerService) Train(ctx context.Context, userID uint) (*domain.CategoryModel, error) {
+	samples, err := s.samples(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	classifier := domain.TrainCategoryClassifier(samples)
+	model := domain.CategoryModel{
+		UserID:     userID,
+		Classifier: classifier,
+		Samples:    len(samples),
+		Categories: len(classifier.Classes),
+		Evaluation: domain.EvaluateCategoryClassifier(samples, s.MinConfidence),
+		TrainedAt:  time.Now(),
+	}
+	err = s.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"classifier", "samples", "categories", "evaluation", "trained_at", "updated_at",
+		}),
+	}).Create(&model).Error
+	if err != nil {
+		return nil, err
+	}
+	return &model, nil
+}
+
+// Model returns the user's trained model
+func (s *CategorizerService) Model(ctx context.Context, userID uint) (*domain.CategoryModel, error) {
+	var model domain.CategoryModel
+	err := s.DB.WithContext(ctx).Where("user_id = ?", userID).First(&model).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &model, nil
+}
+
+// Evaluate measures how well a model trained on the user's current history
+// would do, without replacing their model
+func (s *CategorizerService) Evaluate(ctx context.Context, userID uint) (*domain.CategoryModelEvaluation, error) {
+	samples, err := s.samples(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	evaluation := domain.EvaluateCategoryClassifier(samples, s.MinConfidence)
+	return &evaluation, nil
+}
+
+// Suggest returns the category the user most likely means for a transaction
+// of the type with the description, or nil when nothing is confident enough
+func (s *CategorizerService) Suggest(
+	ctx context.Context, userID uint, description, transactionType string,
+) (*domain.CategorySuggestion, error) {
+	suggest, err := s.suggester(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return suggest(description, transactionType)
+}
+
+// suggester loads what suggestions for the user are made from once, for
+// suggesting categories for many transactions in a row
+func (s *CategorizerService) suggester(
+	ctx context.Context, userID uint,
+) (func(description, transactionType string) (*domain.CategorySuggestion, error), error) {
+	model, err := s.Model(ctx, userID)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+	merchants := &MerchantService{DB: s.DB}
+	rules, err := merchants.rules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(description, transactionType string) (*domain.CategorySuggestion, error) {
+		if model != nil {
+			categoryID, confidence, ok := model.Classifier.Predict(description, transactionType)
+			if ok && confidence >= s.MinConfidence {
+				return &domain.CategorySuggestion{
+					CategoryID: categoryID, Confidence: roundConfidence(confidence), Source: domain.SuggestionSourceModel,
+				}, nil
+			}
+		}
+
+		merchantID, err := merchants.find(ctx, rules, description)
+		if err != nil || merchantID == nil {
+			return nil, err
+		}
+		var counts []struct {
+			CategoryID uint
+			Count      int
+		}
+		err = s.DB.WithContext(ctx).Model(&domain.Transaction{}).
+			Select("category_id, COUNT(*) AS count").
+			Where("user_id = ? AND merchant_id = ? AND type = ? AND "+categorizedTransactions, userID, *merchantID, transactionType, true).
+			Group("category_id").Order("count DESC, category_id").Scan(&counts).Error
+		if err != nil || len(counts) == 0 {
+			return nil, err
+		}
+		total := 0
+		for _, count := range counts {
+			total += count.Count
+		}
+		confidence := float64(counts[0].Count) / float64(total)
+		if confidence < s.MinConfidence {
+			return nil, nil
+		}
+		return &domain.CategorySuggestion{
+			CategoryID: counts[0].CategoryID, Confidence: roundConfidence(confidence), Source: domain.SuggestionSourceRules,
+		}, nil
+	}, nil
+}
+
+// categorize files a transaction without a category under the suggested
+// one. Failures are logged and leave the transaction as it was, for
+// validation to report the missing category.
+func (s *CategorizerService) categorize(ctx context.Context, transaction *domain.Transaction) {
+	if s == nil || s.DB == nil || transaction.CategoryID != 0 {
+		return
+	}
+	suggestion, err := s.Suggest(ctx, transaction.UserID, transaction.Description, transaction.Type)
+	if err != nil {
+		log.Printf("categorizer: failed to suggest a category for %q: %v", transaction.Description, err)
+		return
+	}
+	if suggestion != nil {
+		transaction.CategoryID = suggestion.CategoryID
+		transaction.CategorySuggestion = suggestion
+	}
+}
+
+// RunTrainingJob is the JobHandler of JobTypeCategoryModelTraining. Users
+// whose history has shrunk below the minimum keep the model they had.
+func (s *CategorizerService) RunTrainingJob(ctx context.Context, payload json.RawMessage) error {
+	var job categoryModelJob
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return err
+	}
+	if _, err := s.Train(ctx, job.UserID); err != nil && !errors.Is(err, domain.ErrNotEnoughHistory) {
+		return err
+	}
+	return nil
+}
+
+// RetrainStale retrains the models of users with enough history who have no
+// model yet or changed transactions since it was trained, as jobs when Jobs
+// is set. It returns how many users were retrained or queued.
+func (s *CategorizerService) RetrainStale(ctx context.Context) (int, error) {
+	var userIDs []uint
+	err := s.DB.WithContext(ctx).Table("transactions AS t").
+		Joins("LEFT JOIN category_models AS m ON m.user_id = t.user_id").
+		Where("t.deleted_at IS NULL AND t.category_id <> 0 AND t.category_id NOT IN (SELECT id FROM categories WHERE is_system = ?)", true).
+		Group("t.user_id").
+		Having("COUNT(*) >= ? AND (MAX(m.trained_at) IS NULL OR MAX(t.updated_at) > MAX(m.trained_at))", s.MinSamples).
+		Pluck("t.user_id", &userIDs).Error
+	if err != nil {
+		return 0, err
+	}
+
+	retrained := 0
+	for _, userID := range userIDs {
+		if s.Jobs != nil {
+			_, err = s.Jobs.Enqueue(ctx, JobTypeCategoryModelTraining, categoryModelJob{UserID: userID})
+		} else {
+			_, err = s.Train(ctx, userID)
+		}
+		if err != nil {
+			log.Printf("category model of user %d: retraining failed: %v", userID, err)
+			continue
+		}
+		retrained++
+	}
+	return retrained, nil
+}
+
+// StartRetraining runs RetrainStale on the given interval until ctx is
+// cancelled
+func (s *CategorizerService) StartRetraining(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.RetrainStale(ctx); err != nil {
+			log.Printf("category model retraining failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// roundConfidence rounds a probability to three decimals
+func roundConfidence(confidence float64) float64 {
+	return math.Round(confidence*1000) / 1000
+}
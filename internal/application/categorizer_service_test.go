@@ -0,0 +1,217 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupCategorizerTestDB(t *testing.T) *gorm.DB {
+	db := setupMerchantTestDB(t)
+	require.NoError(t, db.AutoMigrate(
+		&domain.CategoryModel{}, &domain.Job{}, &domain.TransactionTag{}, &domain.CategoryMapping{},
+	))
+	return db
+}
+
+// categorizerHistory records a user's categorized history through a service
+// assigning merchants, oldest first, and returns the categories by name
+func categorizerHistory(t *testing.T, db *gorm.DB, userID uint) map[string]uint {
+	categories := map[string]uint{}
+	for _, category := range []domain.Category{
+		{Name: "Fuel", Type: domain.TransactionTypeExpense},
+		{Name: "Groceries", Type: domain.TransactionTypeExpense},
+		{Name: "Other Expenses", Type: domain.TransactionTypeExpense},
+		{Name: "Salary", Type: domain.TransactionTypeIncome},
+	} {
+		require.NoError(t, db.Create(&category).Error)
+		categories[category.Name] = category.ID
+	}
+
+	transactions := &TransactionService{DB: db, Merchants: NewMerchantService(db)}
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range 4 {
+		for _, tx := range []domain.Transaction{
+			{Description: "SHELL OIL 5742", Type: domain.TransactionTypeExpense, CategoryID: categories["Fuel"]},
+			{Description: "Corner Grocery #118", Type: domain.TransactionTypeExpense, CategoryID: categories["Groceries"]},
+			{Description: "ACME CORP PAYROLL", Type: domain.TransactionTypeIncome, CategoryID: categories["Salary"]},
+		} {
+			tx.UserID, tx.Amount, tx.Date = userID, domain.NewMoney(40), day.AddDate(0, 0, i)
+			require.NoError(t, transactions.Create(context.Background(), &tx))
+		}
+	}
+	return categories
+}
+
+func TestCategorizerService_Train(t *testing.T) {
+	db := setupCategorizerTestDB(t)
+	service := NewCategorizerService(db, 0.6, 10)
+	ctx := context.Background()
+
+	_, err := service.Train(ctx, 1)
+	assert.ErrorIs(t, err, domain.ErrNotEnoughHistory)
+	_, err = service.Model(ctx, 1)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	categorizerHistory(t, db, 1)
+	model, err := service.Train(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 12, model.Samples)
+	assert.Equal(t, 3, model.Categories)
+	assert.Equal(t, 10, model.Evaluation.TrainSamples)
+	assert.Equal(t, 2, model.Evaluation.TestSamples)
+	assert.Equal(t, 1.0, model.Evaluation.Accuracy)
+
+	stored, err := service.Model(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, model.TrainedAt.Unix(), stored.TrainedAt.Unix())
+	assert.Len(t, stored.Classifier.Classes, 3, "the classifier is stored with the model")
+
+	retrained, err := service.Train(ctx, 1)
+	require.NoError(t, err)
+	var count int64
+	require.NoError(t, db.Model(&domain.CategoryModel{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count, "retraining replaces the model")
+	assert.False(t, retrained.TrainedAt.Before(model.TrainedAt))
+
+	evaluation, err := service.Evaluate(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, model.Evaluation, *evaluation)
+	_, err = service.Evaluate(ctx, 2)
+	assert.ErrorIs(t, err, domain.ErrNotEnoughHistory)
+}
+
+func TestCategorizerService_Suggest(t *testing.T) {
+	db := setupCategorizerTestDB(t)
+	service := NewCategorizerService(db, 0.6, 10)
+	ctx := context.Background()
+	categories := categorizerHistory(t, db, 1)
+
+	t.Run("without a model the merchant's usual category is suggested", func(t *testing.T) {
+		suggestion, err := service.Suggest(ctx, 1, "CORNER GROCERY 0931", domain.TransactionTypeExpense)
+		require.NoError(t, err)
+		require.NotNil(t, suggestion)
+		assert.Equal(t, domain.CategorySuggestion{
+			CategoryID: categories["Groceries"], Confidence: 1, Source: domain.SuggestionSourceRules,
+		}, *suggestion)
+
+		suggestion, err = service.Suggest(ctx, 2, "CORNER GROCERY 0931", domain.TransactionTypeExpense)
+		require.NoError(t, err)
+		assert.Nil(t, suggestion, "other users' history is not used")
+	})
+
+	_, err := service.Train(ctx, 1)
+	require.NoError(t, err)
+
+	t.Run("the model suggests categories for descriptions it knows words of", func(t *testing.T) {
+		suggestion, err := service.Suggest(ctx, 1, "Shell 0091 fuel", domain.TransactionTypeExpense)
+		require.NoError(t, err)
+		require.NotNil(t, suggestion)
+		assert.Equal(t, categories["Fuel"], suggestion.CategoryID)
+		assert.Equal(t, domain.SuggestionSourceModel, suggestion.Source)
+		assert.GreaterOrEqual(t, suggestion.Confidence, 0.6)
+	})
+
+	t.Run("nothing is suggested for unknown descriptions", func(t *testing.T) {
+		suggestion, err := service.Suggest(ctx, 1, "Blue Bottle Coffee", domain.TransactionTypeExpense)
+		require.NoError(t, err)
+		assert.Nil(t, suggestion)
+	})
+
+	t.Run("new transactions without a category get the suggested one", func(t *testing.T) {
+		transactions := &TransactionService{DB: db, Categorizer: service}
+		transaction := &domain.Transaction{
+			UserID: 1, Type: domain.TransactionTypeExpense, Description: "SHELL OIL 7781",
+			Amount: domain.NewMoney(35), Date: time.Now(),
+		}
+		require.NoError(t, transactions.Create(ctx, transaction))
+		assert.Equal(t, categories["Fuel"], transaction.CategoryID)
+		require.NotNil(t, transaction.CategorySuggestion)
+		assert.Equal(t, domain.SuggestionSourceModel, transaction.CategorySuggestion.Source)
+
+		unknown := &domain.Transaction{
+			UserID: 1, Type: domain.TransactionTypeExpense, Description: "Blue Bottle Coffee",
+			Amount: domain.NewMoney(5), Date: time.Now(),
+		}
+		require.NoError(t, transactions.Create(ctx, unknown))
+		assert.Nil(t, unknown.CategorySuggestion)
+		var category domain.Category
+		require.NoError(t, db.First(&category, unknown.CategoryID).Error)
+		assert.True(t, category.IsSystem, "transactions nothing is suggested for stay uncategorized")
+	})
+
+	t.Run("imported rows of unmapped categories get the suggested one", func(t *testing.T) {
+		imports := &ImportService{DB: db, Categorizer: service}
+		mint := "Date,Description,Amount,Transaction Type,Category,Labels\n" +
+			"5/01/2024,SHELL OIL 1200,30.00,debit,Gas,\n" +
+			"5/02/2024,Blue Bottle Coffee,4.00,debit,Coffee,\n"
+
+		result, err := imports.Import(ctx, 1, domain.ImportSourceMint, []byte(mint))
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.Imported)
+		assert.Equal(t, 1, result.Suggested)
+
+		var imported []domain.Transaction
+		may := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+		require.NoError(t, db.Where("user_id = ? AND date >= ? AND date < ?", 1, may, may.AddDate(0, 1, 0)).
+			Order("date").Find(&imported).Error)
+		require.Len(t, imported, 2)
+		assert.Equal(t, categories["Fuel"], imported[0].CategoryID)
+		assert.Equal(t, categories["Other Expenses"], imported[1].CategoryID)
+	})
+}
+
+func TestCategorizerService_RetrainStale(t *testing.T) {
+	db := setupCategorizerTestDB(t)
+	service := NewCategorizerService(db, 0.6, 10)
+	ctx := context.Background()
+	categories := categorizerHistory(t, db, 1)
+	// Too little history to train on
+	require.NoError(t, db.Create(&domain.Transaction{
+		UserID: 2, CategoryID: categories["Fuel"], Type: domain.TransactionTypeExpense, Description: "SHELL",
+		Amount: domain.NewMoney(20), Date: time.Now(),
+	}).Error)
+
+	retrained, err := service.RetrainStale(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, retrained)
+	_, err = service.Model(ctx, 1)
+	require.NoError(t, err)
+
+	retrained, err = service.RetrainStale(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, retrained, "nothing changed since the model was trained")
+
+	t.Run("changed histories are retrained in the background", func(t *testing.T) {
+		require.NoError(t, db.Model(&domain.Transaction{}).Where("user_id = ? AND description = ?", 1, "SHELL OIL 5742").
+			Update("category_id", categories["Other Expenses"]).Error)
+		jobs := &JobService{DB: db}
+		service.Jobs = jobs
+		jobs.Register(JobTypeCategoryModelTraining, service.RunTrainingJob)
+
+		retrained, err := service.RetrainStale(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, retrained)
+		ran, err := jobs.RunNext(ctx)
+		require.NoError(t, err)
+		assert.True(t, ran)
+
+		suggestion, err := service.Suggest(ctx, 1, "SHELL OIL 9000", domain.TransactionTypeExpense)
+		require.NoError(t, err)
+		require.NotNil(t, suggestion)
+		assert.Equal(t, categories["Other Expenses"], suggestion.CategoryID)
+	})
+
+	t.Run("training jobs of users without enough history succeed", func(t *testing.T) {
+		payload, err := json.Marshal(categoryModelJob{UserID: 2})
+		require.NoError(t, err)
+		assert.NoError(t, service.RunTrainingJob(ctx, payload))
+	})
+}
@@ -0,0 +1,188 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/persistence"
+
+	"gorm.io/gorm"
+)
+
+// CategoryCapService manages the monthly spending caps users set on their
+// expense categories and checks new transactions against them
+type CategoryCapService struct {
+	DB    *gorm.DB
+	Cache ResultCache // Drops the user's cached dashboard on changes when set
+}
+
+func NewCategoryCapService(db *gorm.DB) *CategoryCapService {
+	return &CategoryCapService{DB: db}
+}
+
+// Set caps the user's monthly spending in a category, replacing the cap the
+// category already has
+func (s *CategoryCapService) Set(ctx context.Context, userID uint, limit domain.CategoryCap) (*domain.CategoryCap, error) {
+	if err := limit.Validate(); err != nil {
+		return nil, err
+	}
+	var category domain.Category
+	err := s.DB.WithContext(ctx).
+		Where("id = ? AND (user_id IS NULL OR user_id = ?) AND type = ?", limit.CategoryID, userID, domain.TransactionTypeExpense).
+		First(&category).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, &domain.ValidationError{Fields: []domain.FieldError{
+			{Field: "category_id", Message: "must be one of your expense categories"},
+		}}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var existing domain.CategoryCap
+	err = s.DB.WithContext(ctx).Where("user_id = ? AND category_id = ?", userID, limit.CategoryID).First(&existing).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		existing = domain.CategoryCap{UserID: userID, CategoryID: limit.CategoryID}
+	case err != nil:
+		return nil, err
+	}
+	existing.Amount = limit.Amount
+	existing.Block = limit.Block
+	if err := s.DB.WithContext(ctx).Save(&existing).Error; err != nil {
+		return nil, err
+	}
+	invalidateUsers(ctx, s.Cache, userID)
+	return &existing, nil
+}
+
+// Delete removes the cap on a category
+func (s *CategoryCapService) Delete(ctx context.Context, userID, categoryID uint) error {
+	result := s.DB.WithContext(ctx).Where("user_id = ? AND category_id = ?", userID, categoryID).Delete(&domain.CategoryCap{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	invalidateUsers(ctx, s.Cache, userID)
+	return nil
+}
+
+// Status returns the user's caps with their spending in the month of the date
+func (s *CategoryCapService) Status(ctx context.Context, userID uint, month time.Time) ([]domain.CategoryCapStatus, error) {
+	return categoryCapStatus(ctx, s.DB, userID, month, nil)
+}
+
+// Check returns the caps a new expense would exceed, counting it towards the
+// spending of its month. When one of them blocks, the error matches
+// domain.ErrCategoryCapExceeded.
+func (s *CategoryCapService) Check(ctx context.Context, transaction *domain.Transaction) ([]domain.CategoryCapStatus, error) {
+	if transaction.Type != domain.TransactionTypeExpense {
+		return nil, nil
+	}
+	parents, err := categoryParents(ctx, s.DB)
+	if err != nil {
+		return nil, err
+	}
+	statuses, err := categoryCapStatus(ctx, s.DB, transaction.UserID, transaction.Date, parents.Ancestors(transaction.CategoryID))
+	if err != nil {
+		return nil, err
+	}
+
+	var exceeded []domain.CategoryCapStatus
+	var blocked *domain.CategoryCapStatus
+	for i := range statuses {
+		limit := domain.CategoryCap{CategoryID: statuses[i].CategoryID, Amount: statuses[i].Cap, Block: statuses[i].Block}
+		status := domain.NewCategoryCapStatus(limit, statuses[i].CategoryName, statuses[i].Spent+transaction.Amount)
+		if !status.Exceeded {
+			continue
+		}
+		exceeded = append(exceeded, status)
+		if status.Block && blocked == nil {
+			blocked = &exceeded[len(exceeded)-1]
+		}
+	}
+	if blocked != nil {
+		return exceeded, fmt.Errorf("%w: %s would reach %s of %s",
+			domain.ErrCategoryCapExceeded, blocked.CategoryName, blocked.Spent, blocked.Cap)
+	}
+	return exceeded, nil
+}
+
+// check runs Check for the TransactionService hook. Only blocking caps fail
+// the transaction; when the caps cannot be checked it is recorded unflagged.
+func (s *CategoryCapService) check(ctx context.Context, transaction *domain.Transaction) error {
+	if s == nil {
+		return nil
+	}
+	warnings, err := s.Check(ctx, transaction)
+	if errors.Is(err, domain.ErrCategoryCapExceeded) {
+		return err
+	}
+	if err != nil {
+		log.Printf("category caps: failed to check transaction: %v", err)
+		return nil
+	}
+	transaction.CapWarnings = warnings
+	return nil
+}
+
+// categoryCapStatus compares the user's caps with their spending in the
+// month of the date, counting subcategories towards their parent's cap.
+// Only caps on the given categories are returned when categoryIDs is set.
+func categoryCapStatus(
+	ctx context.Context, db *gorm.DB, userID uint, month time.Time, categoryIDs []uint,
+) ([]domain.CategoryCapStatus, error) {
+	query := db.WithContext(ctx).Where("user_id = ?", userID)
+	if categoryIDs != nil {
+		query = query.Where("category_id IN ?", categoryIDs)
+	}
+	var limits []domain.CategoryCap
+	if err := query.Order("category_id").Find(&limits).Error; err != nil {
+		return nil, err
+	}
+	statuses := []domain.CategoryCapStatus{}
+	if len(limits) == 0 {
+		return statuses, nil
+	}
+
+	ids := make([]uint, len(limits))
+	for i := range limits {
+		ids[i] = limits[i].CategoryID
+	}
+	var categories []domain.Category
+	if err := db.WithContext(ctx).Select("id", "name").Where("id IN ?", ids).Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	names := make(map[uint]string, len(categories))
+	for i := range categories {
+		names[categories[i].ID] = categories[i].Name
+	}
+
+	start := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, month.Location())
+	end := start.AddDate(0, 1, 0).Add(-time.Nanosecond)
+	spending, err := persistence.NewTransactionRepository(db).SumByCategory(ctx, domain.TransactionFilter{
+		UserID: userID, Type: domain.TransactionTypeExpense, StartDate: &start, EndDate: &end,
+	})
+	if err != nil {
+		return nil, err
+	}
+	parents, err := categoryParents(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range limits {
+		var spent domain.Money
+		for _, id := range parents.Descendants(limits[i].CategoryID) {
+			spent += spending[id]
+		}
+		statuses = append(statuses, domain.NewCategoryCapStatus(limits[i], names[limits[i].CategoryID], spent))
+	}
+	return statuses, nil
+}
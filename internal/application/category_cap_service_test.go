@@ -0,0 +1,112 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupCategoryCapTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&domain.Category{}, &domain.Transaction{}, &domain.CategoryCap{}))
+	return db
+}
+
+func TestCategoryCapService(t *testing.T) {
+	db := setupCategoryCapTestDB(t)
+	service := NewCategoryCapService(db)
+	ctx := context.Background()
+
+	dining := domain.Category{Name: "Food & Dining", Type: domain.TransactionTypeExpense}
+	require.NoError(t, db.Create(&dining).Error)
+	restaurants := domain.Category{Name: "Restaurants", Type: domain.TransactionTypeExpense, ParentID: &dining.ID}
+	require.NoError(t, db.Create(&restaurants).Error)
+	salary := domain.Category{Name: "Salary", Type: domain.TransactionTypeIncome}
+	require.NoError(t, db.Create(&salary).Error)
+	otherUser := uint(2)
+	private := domain.Category{Name: "Hobbies", Type: domain.TransactionTypeExpense, UserID: &otherUser}
+	require.NoError(t, db.Create(&private).Error)
+
+	may := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	for _, tx := range []domain.Transaction{
+		{CategoryID: dining.ID, Amount: domain.NewMoney(150), Date: may.AddDate(0, 0, 2)},
+		{CategoryID: restaurants.ID, Amount: domain.NewMoney(100), Date: may.AddDate(0, 0, 9)},
+		{CategoryID: restaurants.ID, Amount: domain.NewMoney(500), Date: may.AddDate(0, 1, 0)},
+	} {
+		tx.UserID, tx.Type = 1, domain.TransactionTypeExpense
+		require.NoError(t, db.Create(&tx).Error)
+	}
+
+	t.Run("should only cap the user's expense categories", func(t *testing.T) {
+		for _, categoryID := range []uint{salary.ID, private.ID, 99} {
+			_, err := service.Set(ctx, 1, domain.CategoryCap{CategoryID: categoryID, Amount: domain.NewMoney(100)})
+			assert.ErrorIs(t, err, domain.ErrValidation)
+		}
+		_, err := service.Set(ctx, 1, domain.CategoryCap{CategoryID: dining.ID})
+		assert.ErrorIs(t, err, domain.ErrValidation)
+	})
+
+	t.Run("should replace the category's cap", func(t *testing.T) {
+		first, err := service.Set(ctx, 1, domain.CategoryCap{CategoryID: dining.ID, Amount: domain.NewMoney(200)})
+		require.NoError(t, err)
+		second, err := service.Set(ctx, 1, domain.CategoryCap{CategoryID: dining.ID, Amount: domain.NewMoney(300), Block: true})
+		require.NoError(t, err)
+		assert.Equal(t, first.ID, second.ID)
+		assert.True(t, second.Block)
+	})
+
+	t.Run("should count subcategories in the month", func(t *testing.T) {
+		statuses, err := service.Status(ctx, 1, may.AddDate(0, 0, 20))
+		require.NoError(t, err)
+		require.Len(t, statuses, 1)
+		assert.Equal(t, "Food & Dining", statuses[0].CategoryName)
+		assert.Equal(t, domain.NewMoney(250), statuses[0].Spent)
+		assert.Equal(t, domain.NewMoney(50), statuses[0].Remaining)
+		assert.False(t, statuses[0].Exceeded)
+
+		statuses, err = service.Status(ctx, 2, may)
+		require.NoError(t, err)
+		assert.Empty(t, statuses)
+	})
+
+	t.Run("should flag or block expenses over the cap", func(t *testing.T) {
+		transactions := &TransactionService{DB: db, Caps: service}
+		_, err := service.Set(ctx, 1, domain.CategoryCap{CategoryID: dining.ID, Amount: domain.NewMoney(300)})
+		require.NoError(t, err)
+
+		within := &domain.Transaction{UserID: 1, CategoryID: restaurants.ID, Type: domain.TransactionTypeExpense, Description: "Dinner", Amount: domain.NewMoney(40), Date: may.AddDate(0, 0, 12)}
+		require.NoError(t, transactions.Create(ctx, within))
+		assert.Empty(t, within.CapWarnings)
+
+		over := &domain.Transaction{UserID: 1, CategoryID: restaurants.ID, Type: domain.TransactionTypeExpense, Description: "Dinner", Amount: domain.NewMoney(20), Date: may.AddDate(0, 0, 13)}
+		require.NoError(t, transactions.Create(ctx, over))
+		require.Len(t, over.CapWarnings, 1)
+		assert.Equal(t, domain.NewMoney(310), over.CapWarnings[0].Spent)
+		assert.True(t, over.CapWarnings[0].Exceeded)
+
+		_, err = service.Set(ctx, 1, domain.CategoryCap{CategoryID: dining.ID, Amount: domain.NewMoney(300), Block: true})
+		require.NoError(t, err)
+		blocked := &domain.Transaction{UserID: 1, CategoryID: dining.ID, Type: domain.TransactionTypeExpense, Description: "Dinner", Amount: domain.NewMoney(1), Date: may.AddDate(0, 0, 14)}
+		assert.ErrorIs(t, transactions.Create(ctx, blocked), domain.ErrCategoryCapExceeded)
+		assert.Zero(t, blocked.ID, "blocked transactions are not recorded")
+
+		nextMonth := &domain.Transaction{UserID: 1, CategoryID: dining.ID, Type: domain.TransactionTypeExpense, Description: "Dinner", Amount: domain.NewMoney(1), Date: may.AddDate(0, 2, 0)}
+		assert.NoError(t, transactions.Create(ctx, nextMonth), "caps start over every month")
+	})
+
+	t.Run("should delete the cap", func(t *testing.T) {
+		require.NoError(t, service.Delete(ctx, 1, dining.ID))
+		assert.ErrorIs(t, service.Delete(ctx, 1, dining.ID), domain.ErrNotFound)
+	})
+}
@@ -1,41 +1,52 @@
 package application
 
 import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
 	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/persistence"
 
 	"gorm.io/gorm"
 )
 
 type CategoryService struct {
-	DB *gorm.DB
+	DB      *gorm.DB
+	Audit   *AuditService  // Records modifications when set
+	Budgets *BudgetService // Recalculates budgets whose transactions moved on category deletion when set
+	Cache   ResultCache    // Drops the cached analytics of users whose transactions moved when set
 }
 
 type CategoryUsageStats struct {
-	CategoryID       uint    `json:"category_id"`
-	CategoryName     string  `json:"category_name"`
-	CategoryType     string  `json:"category_type"`
-	TransactionCount int     `json:"transaction_count"`
-	TotalAmount      float64 `json:"total_amount"`
-	AverageAmount    float64 `json:"average_amount"`
-	LastUsed         *string `json:"last_used"`
+	CategoryID       uint         `json:"category_id"`
+	CategoryName     string       `json:"category_name"`
+	CategoryType     string       `json:"category_type"`
+	TransactionCount int          `json:"transaction_count"`
+	TotalAmount      domain.Money `json:"total_amount"`
+	AverageAmount    domain.Money `json:"average_amount"`
+	LastUsed         *string      `json:"last_used"`
 }
 
 func NewCategoryService(db *gorm.DB) *CategoryService {
 	return &CategoryService{DB: db}
 }
 
-// InitializeDefaultCategories creates default categories if they don't exist
-func (s *CategoryService) InitializeDefaultCategories() error {
+// InitializeDefaultCategories creates default categories and the Uncategorized
+// system category if they don't exist
+func (s *CategoryService) InitializeDefaultCategories(ctx context.Context) error {
 	defaultCategories := domain.GetDefaultCategories()
 
 	for i := range defaultCategories {
 		category := &defaultCategories[i]
 		var existingCategory domain.Category
-		err := s.DB.Where("name = ? AND type = ?", category.Name, category.Type).First(&existingCategory).Error
+		err := s.DB.WithContext(ctx).Where("user_id IS NULL AND name = ? AND type = ?", category.Name, category.Type).
+			First(&existingCategory).Error
 
 		if err == gorm.ErrRecordNotFound {
 			// Category doesn't exist, create it
-			if createErr := s.DB.Create(category).Error; createErr != nil {
+			if createErr := s.DB.WithContext(ctx).Create(category).Error; createErr != nil {
 				return createErr
 			}
 		} else if err != nil {
@@ -43,14 +54,15 @@ func (s *CategoryService) InitializeDefaultCategories() error {
 		}
 	}
 
-	return nil
+	_, err := uncategorizedCategory(ctx, s.DB)
+	return err
 }
 
-// CreateCategory creates a new custom category
-func (s *CategoryService) CreateCategory(category *domain.Category) error {
-	// Check if category with same name and type already exists
+// CreateCategory creates a new custom category owned by the user
+func (s *CategoryService) CreateCategory(ctx context.Context, userID uint, category *domain.Category) error {
+	// Check if a category with same name and type is already visible to the user
 	var existingCategory domain.Category
-	err := s.DB.Where("name = ? AND type = ?", category.Name, category.Type).First(&existingCategory).Error
+	err := s.visible(ctx, userID).Where("name = ? AND type = ?", category.Name, category.Type).First(&existingCategory).Error
 
 	if err == nil {
 		return gorm.ErrDuplicatedKey
@@ -60,28 +72,47 @@ func (s *CategoryService) CreateCategory(category *domain.Category) error {
 		return err
 	}
 
+	category.UserID = &userID
+	if err := s.validateParent(ctx, 0, category); err != nil {
+		return err
+	}
+
 	category.IsDefault = false
-	return s.DB.Create(category).Error
+	if err := s.DB.WithContext(ctx).Create(category).Error; err != nil {
+		return err
+	}
+	s.Audit.track(ctx, userID, domain.AuditEntityCategory, category.ID, domain.AuditActionCreate, nil, category)
+	return nil
 }
 
-// GetAllCategories retrieves all categories
-func (s *CategoryService) GetAllCategories() ([]domain.Category, error) {
+// GetAllCategories retrieves the default categories and the user's own, Uncategorized last
+func (s *CategoryService) GetAllCategories(ctx context.Context, userID uint) ([]domain.Category, error) {
 	var categories []domain.Category
-	err := s.DB.Order("type ASC, name ASC").Find(&categories).Error
+	err := s.visible(ctx, userID).Order("is_system ASC, type ASC, name ASC").Find(&categories).Error
 	return categories, err
 }
 
-// GetCategoriesByType retrieves categories by type (income/expense)
-func (s *CategoryService) GetCategoriesByType(categoryType string) ([]domain.Category, error) {
+// GetCategoryTree retrieves the user's categories with nested categories under their parent
+func (s *CategoryService) GetCategoryTree(ctx context.Context, userID uint) ([]domain.Category, error) {
+	categories, err := s.GetAllCategories(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return domain.BuildCategoryTree(categories), nil
+}
+
+// GetCategoriesByType retrieves the user's categories by type (income/expense)
+// along with Uncategorized, which takes both
+func (s *CategoryService) GetCategoriesByType(ctx context.Context, userID uint, categoryType string) ([]domain.Category, error) {
 	var categories []domain.Category
-	err := s.DB.Where("type = ?", categoryType).Order("name ASC").Find(&categories).Error
+	err := s.visible(ctx, userID).Where("type = ? OR is_system = ?", categoryType, true).Order("name ASC").Find(&categories).Error
 	return categories, err
 }
 
-// GetCategoryByID retrieves a category by ID
-func (s *CategoryService) GetCategoryByID(categoryID uint) (*domain.Category, error) {
+// GetCategoryByID retrieves a category by ID. Other users' categories are not found.
+func (s *CategoryService) GetCategoryByID(ctx context.Context, userID, categoryID uint) (*domain.Category, error) {
 	var category domain.Category
-	err := s.DB.First(&category, categoryID).Error
+	err := s.visible(ctx, userID).First(&category, categoryID).Error
 	if err != nil {
 		return nil, err
 	}
@@ -89,15 +120,18 @@ func (s *CategoryService) GetCategoryByID(categoryID uint) (*domain.Category, er
 }
 
 // UpdateCategory updates an existing category
-func (s *CategoryService) UpdateCategory(categoryID uint, updates *domain.Category) error {
-	var category domain.Category
-	err := s.DB.First(&category, categoryID).Error
+func (s *CategoryService) UpdateCategory(ctx context.Context, userID, categoryID uint, updates *domain.Category) error {
+	category, err := s.GetCategoryByID(ctx, userID, categoryID)
 	if err != nil {
 		return err
 	}
+	before := *category
+	if category.IsSystem {
+		return gorm.ErrInvalidData
+	}
 
-	// Don't allow updating default categories' core properties
-	if category.IsDefault {
+	// Don't allow updating core properties of categories shared by all users
+	if category.IsDefault || category.UserID == nil {
 		// Only allow updating description and color for default categories
 		category.Description = updates.Description
 		category.Color = updates.Color
@@ -108,47 +142,187 @@ func (s *CategoryService) UpdateCategory(categoryID uint, updates *domain.Catego
 		category.Description = updates.Description
 		category.Icon = updates.Icon
 		category.Color = updates.Color
+		category.ParentID = updates.ParentID
+		if err := s.validateParent(ctx, categoryID, category); err != nil {
+			return err
+		}
 	}
 
-	return s.DB.Save(&category).Error
+	if err := s.DB.WithContext(ctx).Save(category).Error; err != nil {
+		return err
+	}
+	s.Audit.track(ctx, userID, domain.AuditEntityCategory, categoryID, domain.AuditActionUpdate, &before, category)
+	return nil
 }
 
-// DeleteCategory deletes a category (only custom categories)
-func (s *CategoryService) DeleteCategory(categoryID uint) error {
-	var category domain.Category
-	err := s.DB.First(&category, categoryID).Error
+// DeleteCategory deletes one of the user's custom categories in one
+// database transaction. The records that still refer to it move to the
+// replacement the options name, or with Confirm to Uncategorized, and its
+// caps are removed. Without either it returns ErrCategoryInUse along with
+// what refers to it and Uncategorized as the suggested replacement.
+func (s *CategoryService) DeleteCategory(
+	ctx context.Context, userID, categoryID uint, options domain.CategoryDeleteOptions,
+) (*domain.CategoryDeletion, error) {
+	var category *domain.Category
+	var deletion *domain.CategoryDeletion
+	var affected []uint
+	err := persistence.InTransaction(ctx, s.DB, func(ctx context.Context) error {
+		var err error
+		category, err = s.GetCategoryByID(ctx, userID, categoryID)
+		if err != nil {
+			return err
+		}
+
+		// Don't allow deleting default or shared categories
+		if category.IsDefault || category.UserID == nil {
+			return gorm.ErrInvalidData
+		}
+
+		// Check if other categories are nested under it
+		var childCount int64
+		err = persistence.Conn(ctx, s.DB).Model(&domain.Category{}).Where("parent_id = ?", categoryID).Count(&childCount).Error
+		if err != nil {
+			return err
+		}
+		if childCount > 0 {
+			return gorm.ErrForeignKeyViolated
+		}
+
+		// Records of all users refer to the category, trashed transactions too
+		db := persistence.Conn(domain.ContextWithoutUserScope(ctx), s.DB).Unscoped().Session(&gorm.Session{})
+		deletion = &domain.CategoryDeletion{CategoryID: categoryID}
+		references := categoryReferences(deletion)
+		for _, reference := range references {
+			var n int64
+			if err := db.Model(reference.model).Where("category_id = ?", categoryID).Count(&n).Error; err != nil {
+				return err
+			}
+			*reference.count = int(n)
+		}
+
+		if deletion.InUse() {
+			if options.ReplacementID == nil && !options.Confirm {
+				if deletion.Replacement, err = uncategorizedCategory(ctx, s.DB); err != nil {
+					return err
+				}
+				return domain.ErrCategoryInUse
+			}
+			if deletion.Replacement, err = s.replacementCategory(ctx, userID, category, options.ReplacementID); err != nil {
+				return err
+			}
+			if err := moveReferences(db, references, categoryID, deletion.Replacement.ID, &affected); err != nil {
+				return err
+			}
+		}
+		return persistence.Conn(ctx, s.DB).Delete(category).Error
+	})
+	if errors.Is(err, domain.ErrCategoryInUse) {
+		return deletion, err
+	}
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Don't allow deleting default categories
-	if category.IsDefault {
-		return gorm.ErrInvalidData
+	deletion.Deleted = true
+	s.Audit.track(ctx, userID, domain.AuditEntityCategory, categoryID, domain.AuditActionDelete, category, nil)
+	if deletion.Transactions > 0 || deletion.Budgets > 0 {
+		s.refreshUsers(ctx, append(affected, userID))
 	}
+	return deletion, nil
+}
 
-	// Check if category is being used by any transactions
-	var transactionCount int64
-	s.DB.Model(&domain.Transaction{}).Where("category_id = ?", categoryID).Count(&transactionCount)
+// categoryReference is a kind of record that refers to categories and
+// where a category deletion counts them
+type categoryReference struct {
+	model any
+	count *int
+}
 
-	if transactionCount > 0 {
-		return gorm.ErrForeignKeyViolated
+// categoryReferences lists every kind of record that refers to categories.
+// Caps are removed rather than moved to the replacement.
+func categoryReferences(deletion *domain.CategoryDeletion) []categoryReference {
+	return []categoryReference{
+		{&domain.Transaction{}, &deletion.Transactions},
+		{&domain.Budget{}, &deletion.Budgets},
+		{&domain.Bill{}, &deletion.Bills},
+		{&domain.SavingsRule{}, &deletion.SavingsRules},
+		{&domain.CategoryMapping{}, &deletion.Mappings},
+		{&domain.CategoryCap{}, &deletion.Caps},
 	}
+}
+
+// moveReferences moves the records that refer to a category to the
+// replacement, counting them again, and collects the users whose
+// transactions moved
+func moveReferences(
+	db *gorm.DB, references []categoryReference, categoryID, replacementID uint, affected *[]uint,
+) error {
+	err := db.Model(&domain.Transaction{}).Where("category_id = ?", categoryID).Distinct().Pluck("user_id", affected).Error
+	if err != nil {
+		return err
+	}
+	for _, reference := range references {
+		query := db.Where("category_id = ?", categoryID)
+		var result *gorm.DB
+		if _, isCap := reference.model.(*domain.CategoryCap); isCap {
+			result = query.Delete(reference.model)
+		} else {
+			result = query.Model(reference.model).Update("category_id", replacementID)
+		}
+		if result.Error != nil {
+			return result.Error
+		}
+		*reference.count = int(result.RowsAffected)
+	}
+	return nil
+}
 
-	// Check if category is being used by any budgets
-	var budgetCount int64
-	s.DB.Model(&domain.Budget{}).Where("category_id = ?", categoryID).Count(&budgetCount)
+// replacementCategory returns the category the records of a deleted one
+// move to: the one with replacementID, which must be another category of the
+// same type the user can see or Uncategorized, or else Uncategorized
+func (s *CategoryService) replacementCategory(
+	ctx context.Context, userID uint, category *domain.Category, replacementID *uint,
+) (*domain.Category, error) {
+	if replacementID == nil {
+		return uncategorizedCategory(ctx, s.DB)
+	}
 
-	if budgetCount > 0 {
-		return gorm.ErrForeignKeyViolated
+	replacement, err := s.GetCategoryByID(ctx, userID, *replacementID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if err != nil || replacement.ID == category.ID || (!replacement.IsSystem && replacement.Type != category.Type) {
+		return nil, &domain.ValidationError{Fields: []domain.FieldError{
+			{Field: "replacement_id", Message: "must be another category of the same type"},
+		}}
 	}
+	return replacement, nil
+}
 
-	return s.DB.Delete(&category).Error
+// refreshUsers recalculates the budgets and drops the cached analytics of
+// users whose transactions or budgets moved to another category
+func (s *CategoryService) refreshUsers(ctx context.Context, userIDs []uint) {
+	invalidateUsers(ctx, s.Cache, userIDs...)
+	if s.Budgets == nil {
+		return
+	}
+	ctx = domain.ContextWithoutUserScope(ctx)
+	refreshed := map[uint]bool{}
+	for _, userID := range userIDs {
+		if refreshed[userID] {
+			continue
+		}
+		refreshed[userID] = true
+		if err := s.Budgets.RefreshBudgetSpending(ctx, userID); err != nil {
+			log.Printf("failed to refresh budget spending of user %d after deleting a category: %v", userID, err)
+		}
+	}
 }
 
 // GetDefaultCategoryByName finds a default category by name and type
-func (s *CategoryService) GetDefaultCategoryByName(name, categoryType string) (*domain.Category, error) {
+func (s *CategoryService) GetDefaultCategoryByName(ctx context.Context, name, categoryType string) (*domain.Category, error) {
 	var category domain.Category
-	err := s.DB.Where("name = ? AND type = ? AND is_default = ?", name, categoryType, true).First(&category).Error
+	err := s.DB.WithContext(ctx).Where("name = ? AND type = ? AND is_default = ?", name, categoryType, true).First(&category).Error
 	if err != nil {
 		return nil, err
 	}
@@ -156,7 +330,7 @@ func (s *CategoryService) GetDefaultCategoryByName(name, categoryType string) (*
 }
 
 // GetCategoryUsageStats returns usage statistics for categories
-func (s *CategoryService) GetCategoryUsageStats(userID uint) ([]CategoryUsageStats, error) {
+func (s *CategoryService) GetCategoryUsageStats(ctx context.Context, userID uint) ([]CategoryUsageStats, error) {
 	var stats []CategoryUsageStats
 
 	query := `
@@ -166,14 +340,122 @@ func (s *CategoryService) GetCategoryUsageStats(userID uint) ([]CategoryUsageSta
 			c.type as category_type,
 			COUNT(t.id) as transaction_count,
 			COALESCE(SUM(t.amount), 0) as total_amount,
-			COALESCE(AVG(t.amount), 0) as average_amount,
+			CAST(ROUND(COALESCE(AVG(t.amount), 0)) AS INTEGER) as average_amount,
 			MAX(t.date) as last_used
 		FROM categories c
-		LEFT JOIN transactions t ON c.id = t.category_id AND t.user_id = ?
+		LEFT JOIN transactions t ON c.id = t.category_id AND t.user_id = ? AND t.deleted_at IS NULL
+		WHERE c.user_id IS NULL OR c.user_id = ?
 		GROUP BY c.id, c.name, c.type
 		ORDER BY transaction_count DESC, total_amount DESC
 	`
 
-	err := s.DB.Raw(query, userID).Scan(&stats).Error
+	err := s.DB.WithContext(ctx).Raw(query, userID, userID).Scan(&stats).Error
 	return stats, err
 }
+
+// visible scopes a query to the categories the user can see: the defaults and
+// other shared categories, and the user's own
+func (s *CategoryService) visible(ctx context.Context, userID uint) *gorm.DB {
+	return persistence.Conn(ctx, s.DB).Where("user_id IS NULL OR user_id = ?", userID)
+}
+
+// validateParent checks that a category's parent is an existing top-level
+// category of the same type that the category's owner can see. Categories only nest one level deep, so a
+// category that already has children cannot get a parent itself.
+func (s *CategoryService) validateParent(ctx context.Context, categoryID uint, category *domain.Category) error {
+	if category.ParentID == nil {
+		return nil
+	}
+	if *category.ParentID == 0 {
+		category.ParentID = nil
+		return nil
+	}
+	if *category.ParentID == categoryID {
+		return domain.ErrInvalidCategoryParent
+	}
+
+	var ownerID uint
+	if category.UserID != nil {
+		ownerID = *category.UserID
+	}
+	parent, err := s.GetCategoryByID(ctx, ownerID, *category.ParentID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.ErrInvalidCategoryParent
+	}
+	if err != nil {
+		return err
+	}
+	if parent.ParentID != nil || parent.Type != category.Type {
+		return domain.ErrInvalidCategoryParent
+	}
+
+	if categoryID != 0 {
+		var childCount int64
+		err := s.DB.WithContext(ctx).Model(&domain.Category{}).Where("parent_id = ?", categoryID).Count(&childCount).Error
+		if err != nil {
+			return err
+		}
+		if childCount > 0 {
+			return domain.ErrInvalidCategoryParent
+		}
+	}
+	return nil
+}
+
+// uncategorizedCategory returns the Uncategorized system category, creating
+// it in databases the migrations did not set up
+func uncategorizedCategory(ctx context.Context, db *gorm.DB) (*domain.Category, error) {
+	category := domain.UncategorizedCategory()
+	err := persistence.Conn(domain.ContextWithoutUserScope(ctx), db).
+		Where("user_id IS NULL AND name = ? AND is_system = ?", category.Name, true).
+		FirstOrCreate(&category).Error
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// categoryParents loads the parent links of all categories
+func categoryParents(ctx context.Context, db *gorm.DB) (domain.CategoryParents, error) {
+	var categories []domain.Category
+	if err := db.WithContext(ctx).Select("id", "parent_id").Where("parent_id IS NOT NULL").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	return domain.NewCategoryParents(categories), nil
+}
+
+// rollUpCategoryBreakdown folds nested categories into their parent. The
+// breakdown is returned as is when the categories cannot be loaded.
+func rollUpCategoryBreakdown(ctx context.Context, db *gorm.DB, breakdown []domain.CategoryMetrics) []domain.CategoryMetrics {
+	var categories []domain.Category
+	if err := db.WithContext(ctx).Find(&categories).Error; err != nil {
+		return breakdown
+	}
+	return domain.RollUpCategoryMetrics(breakdown, categories)
+}
+
+// namedCategory finds the category of the type with the name, whatever its
+// case, the user's own before a default one
+func namedCategory(categories []domain.Category, name, transactionType string) *domain.Category {
+	var found *domain.Category
+	for i := range categories {
+		if categories[i].Type != transactionType || !strings.EqualFold(categories[i].Name, name) {
+			continue
+		}
+		if found == nil || categories[i].UserID != nil {
+			found = &categories[i]
+		}
+	}
+	return found
+}
+
+// categoryNames lists the names of categories sorted by name, once each
+func categoryNames(categories []domain.Category) []string {
+	names := make([]string, 0, len(categories))
+	for i := range categories {
+		if len(names) == 0 || names[len(names)-1] != categories[i].Name {
+			names = append(names, categories[i].Name)
+		}
+	}
+	return names
+}
@@ -1,6 +1,8 @@
 package application
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -13,13 +15,17 @@ import (
 	"gorm.io/gorm/logger"
 )
 
+// categoryTestUserID owns the custom categories created by the tests
+const categoryTestUserID uint = 1
+
 func setupCategoryTestDB(t *testing.T) *gorm.DB {
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Silent),
 	})
 	require.NoError(t, err)
 
-	err = db.AutoMigrate(&domain.User{}, &domain.Category{}, &domain.Transaction{}, &domain.Budget{})
+	err = db.AutoMigrate(&domain.User{}, &domain.Category{}, &domain.Transaction{}, &domain.Budget{},
+		&domain.Bill{}, &domain.SavingsRule{}, &domain.CategoryMapping{}, &domain.CategoryCap{})
 	require.NoError(t, err)
 
 	return db
@@ -43,7 +49,7 @@ func TestCategoryService_InitializeDefaultCategories(t *testing.T) {
 	categoryService := &CategoryService{DB: db}
 
 	t.Run("initialize default categories", func(t *testing.T) {
-		err := categoryService.InitializeDefaultCategories()
+		err := categoryService.InitializeDefaultCategories(context.Background())
 		assert.NoError(t, err)
 
 		// Verify default categories were created
@@ -61,7 +67,7 @@ func TestCategoryService_InitializeDefaultCategories(t *testing.T) {
 
 	t.Run("don't duplicate existing categories", func(t *testing.T) {
 		// Run initialization again
-		err := categoryService.InitializeDefaultCategories()
+		err := categoryService.InitializeDefaultCategories(context.Background())
 		assert.NoError(t, err)
 
 		// Count should remain the same
@@ -69,7 +75,7 @@ func TestCategoryService_InitializeDefaultCategories(t *testing.T) {
 		db.Model(&domain.Category{}).Where("is_default = ?", true).Count(&count1)
 
 		// Run again
-		err = categoryService.InitializeDefaultCategories()
+		err = categoryService.InitializeDefaultCategories(context.Background())
 		assert.NoError(t, err)
 		db.Model(&domain.Category{}).Where("is_default = ?", true).Count(&count2)
 
@@ -90,7 +96,7 @@ func TestCategoryService_CreateCategory(t *testing.T) {
 			Color:       "#FF5733",
 		}
 
-		err := categoryService.CreateCategory(category)
+		err := categoryService.CreateCategory(context.Background(), categoryTestUserID, category)
 		assert.NoError(t, err)
 		assert.NotZero(t, category.ID)
 		assert.False(t, category.IsDefault)
@@ -102,7 +108,7 @@ func TestCategoryService_CreateCategory(t *testing.T) {
 			Name: "Duplicate Test",
 			Type: "income",
 		}
-		err := categoryService.CreateCategory(category1)
+		err := categoryService.CreateCategory(context.Background(), categoryTestUserID, category1)
 		require.NoError(t, err)
 
 		// Try to create duplicate
@@ -110,7 +116,7 @@ func TestCategoryService_CreateCategory(t *testing.T) {
 			Name: "Duplicate Test",
 			Type: "income",
 		}
-		err = categoryService.CreateCategory(category2)
+		err = categoryService.CreateCategory(context.Background(), categoryTestUserID, category2)
 		assert.Error(t, err)
 		assert.Equal(t, gorm.ErrDuplicatedKey, err)
 	})
@@ -121,7 +127,7 @@ func TestCategoryService_CreateCategory(t *testing.T) {
 			Name: "Duplicate Name",
 			Type: "expense",
 		}
-		err := categoryService.CreateCategory(category1)
+		err := categoryService.CreateCategory(context.Background(), categoryTestUserID, category1)
 		require.NoError(t, err)
 
 		// Try to create category with same name
@@ -129,7 +135,7 @@ func TestCategoryService_CreateCategory(t *testing.T) {
 			Name: "Duplicate Name",
 			Type: "income",
 		}
-		err = categoryService.CreateCategory(category2)
+		err = categoryService.CreateCategory(context.Background(), categoryTestUserID, category2)
 		assert.Error(t, err)
 	})
 }
@@ -151,7 +157,7 @@ func TestCategoryService_GetAllCategories(t *testing.T) {
 	}
 
 	t.Run("get all categories", func(t *testing.T) {
-		result, err := categoryService.GetAllCategories()
+		result, err := categoryService.GetAllCategories(context.Background(), categoryTestUserID)
 		assert.NoError(t, err)
 		assert.Len(t, result, 3)
 
@@ -183,7 +189,7 @@ func TestCategoryService_GetCategoriesByType(t *testing.T) {
 	}
 
 	t.Run("get expense categories", func(t *testing.T) {
-		result, err := categoryService.GetCategoriesByType("expense")
+		result, err := categoryService.GetCategoriesByType(context.Background(), categoryTestUserID, "expense")
 		assert.NoError(t, err)
 		assert.Len(t, result, 2)
 		for _, category := range result {
@@ -195,7 +201,7 @@ func TestCategoryService_GetCategoriesByType(t *testing.T) {
 	})
 
 	t.Run("get income categories", func(t *testing.T) {
-		result, err := categoryService.GetCategoriesByType("income")
+		result, err := categoryService.GetCategoriesByType(context.Background(), categoryTestUserID, "income")
 		assert.NoError(t, err)
 		assert.Len(t, result, 2)
 		for _, category := range result {
@@ -204,7 +210,7 @@ func TestCategoryService_GetCategoriesByType(t *testing.T) {
 	})
 
 	t.Run("get non-existent type", func(t *testing.T) {
-		result, err := categoryService.GetCategoriesByType("invalid")
+		result, err := categoryService.GetCategoriesByType(context.Background(), categoryTestUserID, "invalid")
 		assert.NoError(t, err)
 		assert.Len(t, result, 0)
 	})
@@ -224,7 +230,7 @@ func TestCategoryService_GetCategoryByID(t *testing.T) {
 	require.NoError(t, err)
 
 	t.Run("get existing category", func(t *testing.T) {
-		result, err := categoryService.GetCategoryByID(category.ID)
+		result, err := categoryService.GetCategoryByID(context.Background(), categoryTestUserID, category.ID)
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
 		assert.Equal(t, category.ID, result.ID)
@@ -233,7 +239,7 @@ func TestCategoryService_GetCategoryByID(t *testing.T) {
 	})
 
 	t.Run("get non-existent category", func(t *testing.T) {
-		result, err := categoryService.GetCategoryByID(99999)
+		result, err := categoryService.GetCategoryByID(context.Background(), categoryTestUserID, 99999)
 		assert.Error(t, err)
 		assert.Nil(t, result)
 	})
@@ -245,7 +251,9 @@ func TestCategoryService_UpdateCategory(t *testing.T) {
 
 	t.Run("update custom category", func(t *testing.T) {
 		// Create custom category
+		owner := categoryTestUserID
 		category := &domain.Category{
+			UserID:      &owner,
 			Name:        "Original Name",
 			Type:        "expense",
 			Description: "Original description",
@@ -263,11 +271,11 @@ func TestCategoryService_UpdateCategory(t *testing.T) {
 			Color:       "#FF0000",
 		}
 
-		err = categoryService.UpdateCategory(category.ID, updates)
+		err = categoryService.UpdateCategory(context.Background(), categoryTestUserID, category.ID, updates)
 		assert.NoError(t, err)
 
 		// Verify update
-		updated, err := categoryService.GetCategoryByID(category.ID)
+		updated, err := categoryService.GetCategoryByID(context.Background(), categoryTestUserID, category.ID)
 		assert.NoError(t, err)
 		assert.Equal(t, "Updated Name", updated.Name)
 		assert.Equal(t, "income", updated.Type)
@@ -295,11 +303,11 @@ func TestCategoryService_UpdateCategory(t *testing.T) {
 			Color:       "#00FF00",
 		}
 
-		err = categoryService.UpdateCategory(category.ID, updates)
+		err = categoryService.UpdateCategory(context.Background(), categoryTestUserID, category.ID, updates)
 		assert.NoError(t, err)
 
 		// Verify only description and color were updated
-		updated, err := categoryService.GetCategoryByID(category.ID)
+		updated, err := categoryService.GetCategoryByID(context.Background(), categoryTestUserID, category.ID)
 		assert.NoError(t, err)
 		assert.Equal(t, "Default Category", updated.Name)           // Should not change
 		assert.Equal(t, "expense", updated.Type)                    // Should not change
@@ -307,9 +315,18 @@ func TestCategoryService_UpdateCategory(t *testing.T) {
 		assert.Equal(t, "#00FF00", updated.Color)                   // Should change
 	})
 
+	t.Run("other users' categories are not found", func(t *testing.T) {
+		owner := categoryTestUserID + 1
+		category := &domain.Category{UserID: &owner, Name: "Private", Type: "expense"}
+		require.NoError(t, db.Create(category).Error)
+
+		err := categoryService.UpdateCategory(context.Background(), categoryTestUserID, category.ID, &domain.Category{Name: "Taken"})
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
 	t.Run("update non-existent category", func(t *testing.T) {
 		updates := &domain.Category{Name: "New Name"}
-		err := categoryService.UpdateCategory(99999, updates)
+		err := categoryService.UpdateCategory(context.Background(), categoryTestUserID, 99999, updates)
 		assert.Error(t, err)
 	})
 }
@@ -322,6 +339,7 @@ func TestCategoryService_DeleteCategory(t *testing.T) {
 	t.Run("delete custom category without references", func(t *testing.T) {
 		// Create custom category
 		category := &domain.Category{
+			UserID:    &userID,
 			Name:      "To Delete",
 			Type:      "expense",
 			IsDefault: false,
@@ -329,11 +347,12 @@ func TestCategoryService_DeleteCategory(t *testing.T) {
 		err := db.Create(category).Error
 		require.NoError(t, err)
 
-		err = categoryService.DeleteCategory(category.ID)
-		assert.NoError(t, err)
+		deletion, err := categoryService.DeleteCategory(context.Background(), userID, category.ID, domain.CategoryDeleteOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, &domain.CategoryDeletion{CategoryID: category.ID, Deleted: true}, deletion)
 
 		// Verify deletion
-		_, err = categoryService.GetCategoryByID(category.ID)
+		_, err = categoryService.GetCategoryByID(context.Background(), userID, category.ID)
 		assert.Error(t, err)
 	})
 
@@ -347,61 +366,115 @@ func TestCategoryService_DeleteCategory(t *testing.T) {
 		err := db.Create(category).Error
 		require.NoError(t, err)
 
-		err = categoryService.DeleteCategory(category.ID)
+		_, err = categoryService.DeleteCategory(context.Background(), userID, category.ID, domain.CategoryDeleteOptions{})
 		assert.Error(t, err)
 		assert.Equal(t, gorm.ErrInvalidData, err)
 	})
 
-	t.Run("cannot delete category with transactions", func(t *testing.T) {
-		// Create category
-		category := &domain.Category{
-			Name:      "Used Category",
-			Type:      "expense",
-			IsDefault: false,
-		}
-		err := db.Create(category).Error
-		require.NoError(t, err)
-
-		// Create transaction using this category
+	t.Run("category in use needs a replacement or a confirmation", func(t *testing.T) {
+		require.NoError(t, categoryService.InitializeDefaultCategories(context.Background()))
+		category := &domain.Category{UserID: &userID, Name: "Used Category", Type: "expense"}
+		require.NoError(t, db.Create(category).Error)
 		transaction := &domain.Transaction{
-			UserID:     userID,
-			CategoryID: category.ID,
-			Amount:     100.00,
-			Type:       "expense",
-			Date:       time.Now(),
+			UserID: userID, CategoryID: category.ID, Amount: domain.NewMoney(100.00), Type: "expense", Date: time.Now(),
 		}
-		err = db.Create(transaction).Error
+		require.NoError(t, db.Create(transaction).Error)
+		trashed := &domain.Transaction{
+			UserID: userID, CategoryID: category.ID, Amount: domain.NewMoney(20.00), Type: "expense", Date: time.Now(),
+		}
+		require.NoError(t, db.Create(trashed).Error)
+		require.NoError(t, db.Delete(trashed).Error)
+		require.NoError(t, db.Create(&domain.CategoryCap{UserID: userID, CategoryID: category.ID, Amount: domain.NewMoney(50)}).Error)
+
+		usage, err := categoryService.DeleteCategory(context.Background(), userID, category.ID, domain.CategoryDeleteOptions{})
+		assert.ErrorIs(t, err, domain.ErrCategoryInUse)
+		require.NotNil(t, usage)
+		assert.Equal(t, 2, usage.Transactions, "trashed transactions count too")
+		assert.Equal(t, 1, usage.Caps)
+		assert.False(t, usage.Deleted)
+		require.NotNil(t, usage.Replacement)
+		assert.Equal(t, domain.UncategorizedCategoryName, usage.Replacement.Name)
+		_, err = categoryService.GetCategoryByID(context.Background(), userID, category.ID)
+		require.NoError(t, err, "the category stays until the user confirms")
+
+		deletion, err := categoryService.DeleteCategory(context.Background(), userID, category.ID, domain.CategoryDeleteOptions{Confirm: true})
 		require.NoError(t, err)
-
-		err = categoryService.DeleteCategory(category.ID)
-		assert.Error(t, err)
-		assert.Equal(t, gorm.ErrForeignKeyViolated, err)
+		assert.True(t, deletion.Deleted)
+		assert.Equal(t, 2, deletion.Transactions)
+
+		var moved []domain.Transaction
+		require.NoError(t, db.Unscoped().Where("id IN ?", []uint{transaction.ID, trashed.ID}).Find(&moved).Error)
+		require.Len(t, moved, 2)
+		for _, transaction := range moved {
+			assert.Equal(t, usage.Replacement.ID, transaction.CategoryID)
+		}
+		var caps int64
+		require.NoError(t, db.Model(&domain.CategoryCap{}).Where("category_id = ?", category.ID).Count(&caps).Error)
+		assert.Zero(t, caps)
 	})
 
-	t.Run("cannot delete category with budgets", func(t *testing.T) {
-		// Create category
-		category := &domain.Category{
-			Name:      "Budgeted Category",
-			Type:      "expense",
-			IsDefault: false,
+	t.Run("records move to the named replacement", func(t *testing.T) {
+		category := &domain.Category{UserID: &userID, Name: "Budgeted Category", Type: "expense"}
+		replacement := &domain.Category{UserID: &userID, Name: "Household", Type: "expense"}
+		income := &domain.Category{UserID: &userID, Name: "Side Job", Type: "income"}
+		for _, c := range []*domain.Category{category, replacement, income} {
+			require.NoError(t, db.Create(c).Error)
 		}
-		err := db.Create(category).Error
-		require.NoError(t, err)
-
-		// Create budget using this category
 		budget := &domain.Budget{
 			UserID:     userID,
 			CategoryID: category.ID,
-			Amount:     500.00,
+			Amount:     domain.NewMoney(500.00),
 			StartDate:  time.Now(),
 			EndDate:    time.Now().AddDate(0, 1, 0),
 		}
-		err = db.Create(budget).Error
+		require.NoError(t, db.Create(budget).Error)
+		rule := &domain.SavingsRule{UserID: userID, CategoryID: &category.ID}
+		require.NoError(t, db.Create(rule).Error)
+
+		for _, invalid := range []uint{category.ID, income.ID, 9999} {
+			_, err := categoryService.DeleteCategory(context.Background(), userID, category.ID,
+				domain.CategoryDeleteOptions{ReplacementID: &invalid})
+			assert.ErrorIs(t, err, domain.ErrValidation, invalid)
+		}
+
+		deletion, err := categoryService.DeleteCategory(context.Background(), userID, category.ID,
+			domain.CategoryDeleteOptions{ReplacementID: &replacement.ID})
 		require.NoError(t, err)
+		assert.Equal(t, 1, deletion.Budgets)
+		assert.Equal(t, 1, deletion.SavingsRules)
+		assert.Equal(t, replacement.ID, deletion.Replacement.ID)
+
+		require.NoError(t, db.First(budget, budget.ID).Error)
+		assert.Equal(t, replacement.ID, budget.CategoryID)
+		require.NoError(t, db.First(rule, rule.ID).Error)
+		assert.Equal(t, replacement.ID, *rule.CategoryID)
+	})
 
-		err = categoryService.DeleteCategory(category.ID)
-		assert.Error(t, err)
-		assert.Equal(t, gorm.ErrForeignKeyViolated, err)
+	t.Run("failed reassignment keeps the category and its records", func(t *testing.T) {
+		category := &domain.Category{UserID: &userID, Name: "Kept Category", Type: "expense"}
+		require.NoError(t, db.Create(category).Error)
+		transaction := &domain.Transaction{
+			UserID: userID, CategoryID: category.ID, Amount: domain.NewMoney(10.00), Type: "expense", Date: time.Now(),
+		}
+		require.NoError(t, db.Create(transaction).Error)
+		require.NoError(t, db.Create(&domain.CategoryMapping{
+			UserID: userID, Source: "ynab", ExternalCategory: "Fun", CategoryID: category.ID,
+		}).Error)
+		failDelete := func(tx *gorm.DB) {
+			if tx.Statement.Table == "categories" {
+				_ = tx.AddError(errors.New("disk full"))
+			}
+		}
+		require.NoError(t, db.Callback().Delete().Before("gorm:delete").Register("test:fail_category_delete", failDelete))
+		defer func() { require.NoError(t, db.Callback().Delete().Remove("test:fail_category_delete")) }()
+
+		_, err := categoryService.DeleteCategory(context.Background(), userID, category.ID, domain.CategoryDeleteOptions{Confirm: true})
+		assert.EqualError(t, err, "disk full")
+
+		_, err = categoryService.GetCategoryByID(context.Background(), userID, category.ID)
+		require.NoError(t, err)
+		require.NoError(t, db.First(transaction, transaction.ID).Error)
+		assert.Equal(t, category.ID, transaction.CategoryID, "the moves are rolled back together")
 	})
 }
 
@@ -427,7 +500,7 @@ func TestCategoryService_GetDefaultCategoryByName(t *testing.T) {
 	require.NoError(t, err)
 
 	t.Run("find default category", func(t *testing.T) {
-		result, err := categoryService.GetDefaultCategoryByName("Test Default Category", "expense")
+		result, err := categoryService.GetDefaultCategoryByName(context.Background(), "Test Default Category", "expense")
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
 		assert.Equal(t, defaultCategory.ID, result.ID)
@@ -435,13 +508,13 @@ func TestCategoryService_GetDefaultCategoryByName(t *testing.T) {
 	})
 
 	t.Run("default category not found", func(t *testing.T) {
-		result, err := categoryService.GetDefaultCategoryByName("NonExistent", "expense")
+		result, err := categoryService.GetDefaultCategoryByName(context.Background(), "NonExistent", "expense")
 		assert.Error(t, err)
 		assert.Nil(t, result)
 	})
 
 	t.Run("custom category not returned", func(t *testing.T) {
-		result, err := categoryService.GetDefaultCategoryByName("Food", "income")
+		result, err := categoryService.GetDefaultCategoryByName(context.Background(), "Food", "income")
 		assert.Error(t, err) // Should not find the custom category
 		assert.Nil(t, result)
 	})
@@ -470,21 +543,21 @@ func TestCategoryService_GetCategoryUsageStats(t *testing.T) {
 		{
 			UserID:     userID,
 			CategoryID: categories[0].ID, // Food
-			Amount:     50.00,
+			Amount:     domain.NewMoney(50.00),
 			Type:       "expense",
 			Date:       now.AddDate(0, 0, -1),
 		},
 		{
 			UserID:     userID,
 			CategoryID: categories[0].ID, // Food
-			Amount:     30.00,
+			Amount:     domain.NewMoney(30.00),
 			Type:       "expense",
 			Date:       now.AddDate(0, 0, -2),
 		},
 		{
 			UserID:     userID,
 			CategoryID: categories[2].ID, // Salary
-			Amount:     2000.00,
+			Amount:     domain.NewMoney(2000.00),
 			Type:       "income",
 			Date:       now.AddDate(0, 0, -3),
 		},
@@ -497,7 +570,7 @@ func TestCategoryService_GetCategoryUsageStats(t *testing.T) {
 	}
 
 	t.Run("get category usage stats", func(t *testing.T) {
-		stats, err := categoryService.GetCategoryUsageStats(userID)
+		stats, err := categoryService.GetCategoryUsageStats(context.Background(), userID)
 		assert.NoError(t, err)
 		assert.Len(t, stats, 3)
 
@@ -505,21 +578,141 @@ func TestCategoryService_GetCategoryUsageStats(t *testing.T) {
 		// Food should be first (2 transactions)
 		assert.Equal(t, "Food", stats[0].CategoryName)
 		assert.Equal(t, 2, stats[0].TransactionCount)
-		assert.Equal(t, 80.00, stats[0].TotalAmount)
-		assert.Equal(t, 40.00, stats[0].AverageAmount)
+		assert.Equal(t, domain.NewMoney(80.00), stats[0].TotalAmount)
+		assert.Equal(t, domain.NewMoney(40.00), stats[0].AverageAmount)
 		assert.NotNil(t, stats[0].LastUsed)
 
 		// Salary should be second (1 transaction, higher amount)
 		assert.Equal(t, "Salary", stats[1].CategoryName)
 		assert.Equal(t, 1, stats[1].TransactionCount)
-		assert.Equal(t, 2000.00, stats[1].TotalAmount)
-		assert.Equal(t, 2000.00, stats[1].AverageAmount)
+		assert.Equal(t, domain.NewMoney(2000.00), stats[1].TotalAmount)
+		assert.Equal(t, domain.NewMoney(2000.00), stats[1].AverageAmount)
 
 		// Transport should be last (0 transactions)
 		assert.Equal(t, "Transport", stats[2].CategoryName)
 		assert.Equal(t, 0, stats[2].TransactionCount)
-		assert.Equal(t, 0.00, stats[2].TotalAmount)
-		assert.Equal(t, 0.00, stats[2].AverageAmount)
+		assert.Equal(t, domain.NewMoney(0.00), stats[2].TotalAmount)
+		assert.Equal(t, domain.NewMoney(0.00), stats[2].AverageAmount)
 		assert.Nil(t, stats[2].LastUsed)
 	})
 }
+
+func TestCategoryService_Hierarchy(t *testing.T) {
+	db := setupCategoryTestDB(t)
+	service := NewCategoryService(db)
+	ctx := context.Background()
+
+	food := &domain.Category{Name: "Food", Type: "expense"}
+	require.NoError(t, service.CreateCategory(ctx, categoryTestUserID, food))
+	groceries := &domain.Category{Name: "Groceries", Type: "expense", ParentID: &food.ID}
+	require.NoError(t, service.CreateCategory(ctx, categoryTestUserID, groceries))
+
+	t.Run("rejects invalid parents", func(t *testing.T) {
+		missing := uint(9999)
+		salary := &domain.Category{Name: "Salary", Type: "income"}
+		require.NoError(t, service.CreateCategory(ctx, categoryTestUserID, salary))
+
+		for _, category := range []*domain.Category{
+			{Name: "Ghost", Type: "expense", ParentID: &missing},
+			{Name: "Bonus", Type: "income", ParentID: &food.ID},
+			{Name: "Organic", Type: "expense", ParentID: &groceries.ID},
+		} {
+			assert.ErrorIs(t, service.CreateCategory(ctx, categoryTestUserID, category), domain.ErrInvalidCategoryParent, category.Name)
+		}
+
+		// A category with children cannot be nested itself
+		update := *food
+		update.ParentID = &salary.ID
+		assert.ErrorIs(t, service.UpdateCategory(ctx, categoryTestUserID, food.ID, &update), domain.ErrInvalidCategoryParent)
+	})
+
+	t.Run("builds the tree", func(t *testing.T) {
+		tree, err := service.GetCategoryTree(ctx, categoryTestUserID)
+		require.NoError(t, err)
+
+		var root *domain.Category
+		for i := range tree {
+			if tree[i].ID == food.ID {
+				root = &tree[i]
+			}
+			assert.Nil(t, tree[i].ParentID)
+		}
+		require.NotNil(t, root)
+		require.Len(t, root.Children, 1)
+		assert.Equal(t, "Groceries", root.Children[0].Name)
+	})
+
+	t.Run("parent with children cannot be deleted", func(t *testing.T) {
+		_, err := service.DeleteCategory(ctx, categoryTestUserID, food.ID, domain.CategoryDeleteOptions{})
+		assert.ErrorIs(t, err, gorm.ErrForeignKeyViolated)
+	})
+
+	t.Run("zero parent moves the category to the top level", func(t *testing.T) {
+		update := *groceries
+		update.ParentID = new(uint)
+		require.NoError(t, service.UpdateCategory(ctx, categoryTestUserID, groceries.ID, &update))
+
+		updated, err := service.GetCategoryByID(ctx, categoryTestUserID, groceries.ID)
+		require.NoError(t, err)
+		assert.Nil(t, updated.ParentID)
+		_, err = service.DeleteCategory(ctx, categoryTestUserID, food.ID, domain.CategoryDeleteOptions{})
+		assert.NoError(t, err)
+	})
+}
+
+func TestCategoryService_PerUserCategories(t *testing.T) {
+	db := setupCategoryTestDB(t)
+	service := NewCategoryService(db)
+	ctx := context.Background()
+	require.NoError(t, service.InitializeDefaultCategories(ctx))
+	alice, bob := uint(1), uint(2)
+
+	therapy := &domain.Category{Name: "Therapy", Type: "expense"}
+	require.NoError(t, service.CreateCategory(ctx, alice, therapy))
+	assert.Equal(t, alice, *therapy.UserID)
+
+	t.Run("custom categories are private", func(t *testing.T) {
+		categories, err := service.GetAllCategories(ctx, bob)
+		require.NoError(t, err)
+		assert.Len(t, categories, len(domain.GetDefaultCategories())+1, "the defaults and Uncategorized")
+		for i := range categories {
+			assert.NotEqual(t, "Therapy", categories[i].Name)
+		}
+
+		_, err = service.GetCategoryByID(ctx, bob, therapy.ID)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		_, err = service.DeleteCategory(ctx, bob, therapy.ID, domain.CategoryDeleteOptions{})
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		stats, err := service.GetCategoryUsageStats(ctx, bob)
+		require.NoError(t, err)
+		assert.Len(t, stats, len(domain.GetDefaultCategories())+1)
+	})
+
+	t.Run("uncategorized is protected and takes both types", func(t *testing.T) {
+		for _, categoryType := range []string{"income", "expense"} {
+			categories, err := service.GetCategoriesByType(ctx, alice, categoryType)
+			require.NoError(t, err)
+			uncategorized := namedCategory(categories, domain.UncategorizedCategoryName, "")
+			require.NotNil(t, uncategorized, categoryType)
+			assert.True(t, uncategorized.IsSystem)
+
+			update := *uncategorized
+			update.Description = "Mine now"
+			assert.ErrorIs(t, service.UpdateCategory(ctx, alice, uncategorized.ID, &update), gorm.ErrInvalidData)
+			_, err = service.DeleteCategory(ctx, alice, uncategorized.ID, domain.CategoryDeleteOptions{Confirm: true})
+			assert.ErrorIs(t, err, gorm.ErrInvalidData)
+		}
+	})
+
+	t.Run("names are unique per user", func(t *testing.T) {
+		require.NoError(t, service.CreateCategory(ctx, bob, &domain.Category{Name: "Therapy", Type: "expense"}))
+		assert.ErrorIs(t, service.CreateCategory(ctx, alice, &domain.Category{Name: "Therapy", Type: "expense"}), gorm.ErrDuplicatedKey)
+		assert.ErrorIs(t, service.CreateCategory(ctx, bob, &domain.Category{Name: "Salary", Type: "income"}), gorm.ErrDuplicatedKey)
+	})
+
+	t.Run("categories nest only under categories the user can see", func(t *testing.T) {
+		err := service.CreateCategory(ctx, bob, &domain.Category{Name: "Counseling", Type: "expense", ParentID: &therapy.ID})
+		assert.ErrorIs(t, err, domain.ErrInvalidCategoryParent)
+	})
+}
@@ -0,0 +1,59 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// GetCategoryTrend returns the user's monthly amounts in a category, nested
+// categories included, over the given number of complete months before the
+// current one, projected into the current month
+func (s *AnalyticsService) GetCategoryTrend(ctx context.Context, userID, categoryID uint, months int) (*domain.CategoryTrend, error) {
+	if months < domain.MinCategoryTrendMonths || months > domain.MaxCategoryTrendMonths {
+		return nil, &domain.ValidationError{Fields: []domain.FieldError{{
+			Field: "months", Message: fmt.Sprintf("must be between %d and %d",
+				domain.MinCategoryTrendMonths, domain.MaxCategoryTrendMonths),
+		}}}
+	}
+
+	var category domain.Category
+	err := s.DB.WithContext(ctx).Select("id", "name").First(&category, categoryID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	categoryIDs, err := s.categoryFamily(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	current := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	periods := make([]dateRange, months)
+	for i := range periods {
+		start := current.AddDate(0, i-months, 0)
+		periods[i] = dateRange{Start: start, End: start.AddDate(0, 1, 0).Add(-time.Second)}
+	}
+	totals, err := sumByPeriod(ctx, s.DB.Where("category_id IN ?", categoryIDs), userID, periods)
+	if err != nil {
+		return nil, err
+	}
+
+	series := make([]domain.CategoryMonth, months)
+	for i, period := range periods {
+		series[i] = domain.CategoryMonth{
+			Year: period.Start.Year(), Month: int(period.Start.Month()),
+			Amount: totals[i].Income + totals[i].Expenses, TransactionCount: totals[i].TransactionCount,
+		}
+	}
+	trend := domain.NewCategoryTrend(userID, categoryID, category.Name, series)
+	return &trend, nil
+}
@@ -0,0 +1,307 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"strings"
+
+	"go-finance-advisor/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// maxCommentExcerpt is how much of a comment mention notifications quote
+const maxCommentExcerpt = 140
+
+// CommentService keeps the comments users leave on transactions, budgets
+// and reports. Users who cannot see a record get domain.ErrNotFound for its
+// comments; those who can see it but not comment, such as read-only
+// household members and advisors with read access, get
+// domain.ErrCommentForbidden when commenting.
+type CommentService struct {
+	DB            *gorm.DB
+	Notifications *NotificationService // Tells mentioned users when set
+}
+
+func NewCommentService(db *gorm.DB, notifications *NotificationService) *CommentService {
+	return &CommentService{DB: db, Notifications: notifications}
+}
+
+// commentTarget is who a commented record belongs to
+type commentTarget struct {
+	UserID      uint
+	HouseholdID *uint
+}
+
+// List returns the comments on a record, oldest first
+func (s *CommentService) List(ctx context.Context, userID uint, targetType string, targetID uint) ([]domain.Comment, error) {
+	if _, err := s.authorize(ctx, userID, targetType, targetID, false); err != nil {
+		return nil, err
+	}
+	comments := []domain.Comment{}
+	err := s.DB.WithContext(ctx).Where("target_type = ? AND target_id = ?", targetType, targetID).
+		Order("created_at, id").Find(&comments).Error
+	if err != nil {
+		return nil, err
+	}
+	if err := s.fillAuthors(ctx, comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// Create leaves a comment on a record and notifies the users it mentions
+func (s *CommentService) Create(ctx context.Context, userID uint, targetType string, targetID uint, body string) (*domain.Comment, error) {
+	target, err := s.authorize(ctx, userID, targetType, targetID, true)
+	if err != nil {
+		return nil, err
+	}
+	comment := &domain.Comment{TargetType: targetType, TargetID: targetID, AuthorID: userID, Body: strings.TrimSpace(body)}
+	if err := comment.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.DB.WithContext(ctx).Create(comment).Error; err != nil {
+		return nil, err
+	}
+	s.notifyMentions(ctx, comment, target, nil)
+	return comment, nil
+}
+
+// Update changes the body of one of the user's comments. Only users it
+// newly mentions are notified.
+func (s *CommentService) Update(
+	ctx context.Context, userID uint, targetType string, targetID, commentID uint, body string,
+) (*domain.Comment, error) {
+	target, err := s.authorize(ctx, userID, targetType, targetID, true)
+	if err != nil {
+		return nil, err
+	}
+	comment, err := s.comment(ctx, targetType, targetID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.AuthorID != userID {
+		return nil, domain.ErrCommentForbidden
+	}
+
+	mentioned := comment.Mentions()
+	comment.Body = strings.TrimSpace(body)
+	if err := comment.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.DB.WithContext(ctx).Save(comment).Error; err != nil {
+		return nil, err
+	}
+	s.notifyMentions(ctx, comment, target, mentioned)
+	return comment, nil
+}
+
+// Delete removes a comment. Authors can delete their comments and owners
+// any comment on their records.
+func (s *CommentService) Delete(ctx context.Context, userID uint, targetType string, targetID, commentID uint) error {
+	target, err := s.authorize(ctx, userID, targetType, targetID, false)
+	if err != nil {
+		return err
+	}
+	comment, err := s.comment(ctx, targetType, targetID, commentID)
+	if err != nil {
+		return err
+	}
+	if comment.AuthorID != userID && target.UserID != userID {
+		return domain.ErrCommentForbidden
+	}
+	return s.DB.WithContext(ctx).Delete(comment).Error
+}
+
+// comment returns a comment on the record, or domain.ErrNotFound
+func (s *CommentService) comment(ctx context.Context, targetType string, targetID, commentID uint) (*domain.Comment, error) {
+	var comment domain.Comment
+	err := s.DB.WithContext(ctx).Where("target_type = ? AND target_id = ?", targetType, targetID).First(&comment, commentID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// authorize returns who the record belongs to if the user may read its
+// comments, or leave one when write is set: the owner always may, household
+// members when the record is shared with their household and their role
+// allows writing, and advisors the owner granted read or comment access.
+func (s *CommentService) authorize(ctx context.Context, userID uint, targetType string, targetID uint, write bool) (*commentTarget, error) {
+	target, err := s.target(ctx, targetType, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if target.UserID == userID {
+		return target, nil
+	}
+
+	if target.HouseholdID != nil {
+		member, err := (&HouseholdService{DB: s.DB}).member(ctx, userID, *target.HouseholdID)
+		if err == nil {
+			if write && !member.CanWrite() {
+				return nil, domain.ErrCommentForbidden
+			}
+			return target, nil
+		}
+		if !errors.Is(err, domain.ErrNotFound) {
+			return nil, err
+		}
+	}
+
+	grant, err := (&AdvisorClientService{DB: s.DB}).authorize(ctx, userID, target.UserID, domain.AdvisorGrant.CanRead)
+	switch {
+	case errors.Is(err, domain.ErrAdvisorForbidden):
+		return nil, domain.ErrNotFound
+	case err != nil:
+		return nil, err
+	case write && !grant.CanComment():
+		return nil, domain.ErrCommentForbidden
+	}
+	return target, nil
+}
+
+// target reads who a record belongs to
+func (s *CommentService) target(ctx context.Context, targetType string, targetID uint) (*commentTarget, error) {
+	var model interface{}
+	switch targetType {
+	case domain.CommentTargetTransaction:
+		model = &domain.Transaction{}
+	case domain.CommentTargetBudget:
+		model = &domain.Budget{}
+	case domain.CommentTargetReport:
+		model = &domain.FinancialReport{}
+	default:
+		return nil, domain.ErrInvalidCommentTarget
+	}
+
+	query := s.DB.WithContext(ctx).Model(model).Where("id = ?", targetID)
+	if targetType == domain.CommentTargetReport {
+		query = query.Select("user_id")
+	} else {
+		query = query.Select("user_id", "household_id")
+	}
+	var targets []commentTarget
+	if err := query.Limit(1).Scan(&targets).Error; err != nil {
+		return nil, err
+	}
+	if len(targets) == 0 {
+		return nil, domain.ErrNotFound
+	}
+	return &targets[0], nil
+}
+
+// fillAuthors sets the name of each comment's author
+func (s *CommentService) fillAuthors(ctx context.Context, comments []domain.Comment) error {
+	if len(comments) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(comments))
+	for i := range comments {
+		ids[i] = comments[i].AuthorID
+	}
+	var users []domain.User
+	if err := s.DB.WithContext(ctx).Select("id", "email", "first_name", "last_name").Where("id IN ?", ids).Find(&users).Error; err != nil {
+		return err
+	}
+	names := make(map[uint]string, len(users))
+	for i := range users {
+		names[users[i].ID] = commentAuthorName(&users[i])
+	}
+	for i := range comments {
+		comments[i].Author = names[comments[i].AuthorID]
+	}
+	return nil
+}
+
+// notifyMentions tells the users a comment mentions, apart from its author,
+// those already mentioned before an edit and those who cannot see the
+// record. Failures are only logged; the comment is saved either way.
+func (s *CommentService) notifyMentions(ctx context.Context, comment *domain.Comment, target *commentTarget, notified []string) {
+	if s.Notifications == nil {
+		return
+	}
+	var emails []string
+	for _, email := range comment.Mentions() {
+		if !slices.Contains(notified, email) {
+			emails = append(emails, email)
+		}
+	}
+	if len(emails) == 0 {
+		return
+	}
+
+	var users []domain.User
+	if err := s.DB.WithContext(ctx).Where("LOWER(email) IN ?", emails).Find(&users).Error; err != nil {
+		log.Printf("comments: failed to look up the users comment %d mentions: %v", comment.ID, err)
+		return
+	}
+	var author domain.User
+	if err := s.DB.WithContext(ctx).First(&author, comment.AuthorID).Error; err != nil {
+		log.Printf("comments: failed to look up the author of comment %d: %v", comment.ID, err)
+		return
+	}
+
+	excerpt := []rune(comment.Body)
+	if len(excerpt) > maxCommentExcerpt {
+		excerpt = append(excerpt[:maxCommentExcerpt], '…')
+	}
+	for i := range users {
+		user := &users[i]
+		if user.ID == comment.AuthorID {
+			continue
+		}
+		if user.ID != target.UserID {
+			if _, err := s.authorize(ctx, user.ID, comment.TargetType, comment.TargetID, false); err != nil {
+				continue
+			}
+		}
+		commentID := comment.ID
+		notification := &domain.Notification{
+			UserID:   user.ID,
+			Type:     domain.NotificationTypeComment,
+			Title:    commentAuthorName(&author) + " mentioned you on a " + comment.TargetType,
+			Message:  string(excerpt),
+			EntityID: &commentID,
+		}
+		if err := s.Notifications.Notify(ctx, notification); err != nil {
+			log.Printf("comments: failed to notify user %d of comment %d: %v", user.ID, comment.ID, err)
+		}
+	}
+}
+
+// commentAuthorName is how comments and notifications name a user
+func commentAuthorName(user *domain.User) string {
+	if name := strings.TrimSpace(user.FirstName + " " + user.LastName); name != "" {
+		return name
+	}
+	return user.Email
+}
+
+// countComments returns how many comments each record of the type has
+func countComments(ctx context.Context, db *gorm.DB, targetType string, ids []uint) (map[uint]int, error) {
+	counts := make(map[uint]int, len(ids))
+	if db == nil || len(ids) == 0 {
+		return counts, nil
+	}
+	var rows []struct {
+		TargetID uint
+		Count    int
+	}
+	err := db.WithContext(ctx).Model(&domain.Comment{}).
+		Select("target_id, COUNT(*) AS count").
+		Where("target_type = ? AND target_id IN ?", targetType, ids).
+		Group("target_id").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.TargetID] = row.Count
+	}
+	return counts, nil
+}
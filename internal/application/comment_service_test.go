@@ -0,0 +1,133 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommentService(t *testing.T) {
+	db := setupHouseholdTestDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.Notification{}, &domain.AdvisorGrant{}))
+	households := NewHouseholdService(db)
+	notifications := NewNotificationService(db)
+	service := NewCommentService(db, notifications)
+	ctx := context.Background()
+
+	users := createHouseholdUsers(t, db,
+		"owner@example.com", "partner@example.com", "kid@example.com", "coach@example.com", "stranger@example.com")
+	owner, partner, kid, coach, stranger := users[0], users[1], users[2], users[3], users[4]
+	require.NoError(t, db.Model(&domain.User{}).Where("id = ?", coach).Update("account_type", domain.UserAccountTypeAdvisor).Error)
+
+	household, err := households.CreateHousehold(ctx, owner, "Home")
+	require.NoError(t, err)
+	joinHousehold(t, households, owner, household.ID, partner, "partner@example.com", domain.HouseholdRoleMember)
+	joinHousehold(t, households, owner, household.ID, kid, "kid@example.com", domain.HouseholdRoleReadOnly)
+
+	category := &domain.Category{Name: "Groceries", Type: "expense"}
+	require.NoError(t, db.Create(category).Error)
+	shared := &domain.Transaction{UserID: owner, CategoryID: category.ID, HouseholdID: &household.ID, Type: "expense",
+		Amount: domain.NewMoney(42), Date: time.Now()}
+	private := &domain.Transaction{UserID: owner, CategoryID: category.ID, Type: "expense",
+		Amount: domain.NewMoney(9), Date: time.Now()}
+	require.NoError(t, db.Create(shared).Error)
+	require.NoError(t, db.Create(private).Error)
+
+	t.Run("owners comment on their records", func(t *testing.T) {
+		comment, err := service.Create(ctx, owner, domain.CommentTargetTransaction, private.ID, "  Split with @Partner@example.com?  ")
+		require.NoError(t, err)
+		assert.Equal(t, "Split with @Partner@example.com?", comment.Body)
+
+		_, err = service.Create(ctx, owner, domain.CommentTargetTransaction, private.ID, "   ")
+		assert.ErrorIs(t, err, domain.ErrValidation)
+		_, err = service.Create(ctx, owner, "account", private.ID, "Hi")
+		assert.ErrorIs(t, err, domain.ErrInvalidCommentTarget)
+		_, err = service.Create(ctx, owner, domain.CommentTargetBudget, 999, "Hi")
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("only users who can see the record are notified of mentions", func(t *testing.T) {
+		list, err := notifications.List(ctx, partner, false, 10)
+		require.NoError(t, err)
+		assert.Empty(t, list, "the private transaction is not shared with the household")
+
+		_, err = service.Create(ctx, owner, domain.CommentTargetTransaction, shared.ID, "@partner@example.com was this yours?")
+		require.NoError(t, err)
+		list, err = notifications.List(ctx, partner, false, 10)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, domain.NotificationTypeComment, list[0].Type)
+		assert.Equal(t, "Test User mentioned you on a transaction", list[0].Title)
+	})
+
+	t.Run("household members comment on shared records", func(t *testing.T) {
+		_, err := service.Create(ctx, partner, domain.CommentTargetTransaction, shared.ID, "Yes, mine")
+		require.NoError(t, err)
+
+		_, err = service.Create(ctx, kid, domain.CommentTargetTransaction, shared.ID, "Can I have some?")
+		assert.ErrorIs(t, err, domain.ErrCommentForbidden)
+		comments, err := service.List(ctx, kid, domain.CommentTargetTransaction, shared.ID)
+		require.NoError(t, err)
+		require.Len(t, comments, 2)
+		assert.Equal(t, owner, comments[0].AuthorID)
+		assert.Equal(t, "Test User", comments[0].Author)
+
+		_, err = service.List(ctx, partner, domain.CommentTargetTransaction, private.ID)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		_, err = service.List(ctx, stranger, domain.CommentTargetTransaction, shared.ID)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("advisors comment with comment access", func(t *testing.T) {
+		_, err := service.List(ctx, coach, domain.CommentTargetTransaction, private.ID)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+
+		advisors := NewAdvisorClientService(db, nil)
+		_, err = advisors.Grant(ctx, owner, "coach@example.com", domain.AdvisorAccessRead)
+		require.NoError(t, err)
+		comments, err := service.List(ctx, coach, domain.CommentTargetTransaction, private.ID)
+		require.NoError(t, err)
+		assert.Len(t, comments, 1)
+		_, err = service.Create(ctx, coach, domain.CommentTargetTransaction, private.ID, "Is this recurring?")
+		assert.ErrorIs(t, err, domain.ErrCommentForbidden)
+
+		_, err = advisors.UpdateAccess(ctx, owner, coach, domain.AdvisorAccessComment)
+		require.NoError(t, err)
+		_, err = service.Create(ctx, coach, domain.CommentTargetTransaction, private.ID, "Is this recurring?")
+		require.NoError(t, err)
+	})
+
+	t.Run("authors edit and authors or owners delete", func(t *testing.T) {
+		comment, err := service.Create(ctx, partner, domain.CommentTargetTransaction, shared.ID, "Lunch")
+		require.NoError(t, err)
+
+		_, err = service.Update(ctx, owner, domain.CommentTargetTransaction, shared.ID, comment.ID, "Dinner")
+		assert.ErrorIs(t, err, domain.ErrCommentForbidden)
+		updated, err := service.Update(ctx, partner, domain.CommentTargetTransaction, shared.ID, comment.ID, "Dinner")
+		require.NoError(t, err)
+		assert.Equal(t, "Dinner", updated.Body)
+		_, err = service.Update(ctx, partner, domain.CommentTargetTransaction, private.ID, comment.ID, "Dinner")
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+
+		assert.ErrorIs(t, service.Delete(ctx, kid, domain.CommentTargetTransaction, shared.ID, comment.ID), domain.ErrCommentForbidden)
+		require.NoError(t, service.Delete(ctx, owner, domain.CommentTargetTransaction, shared.ID, comment.ID))
+		assert.ErrorIs(t, service.Delete(ctx, owner, domain.CommentTargetTransaction, shared.ID, comment.ID), domain.ErrNotFound)
+	})
+
+	t.Run("lists count comments", func(t *testing.T) {
+		transactions := &TransactionService{DB: db}
+		page, err := transactions.ListPageIncluding(ctx, domain.TransactionFilter{UserID: owner}, domain.TransactionIncludes{})
+		require.NoError(t, err)
+		counts := map[uint]int{}
+		for _, tx := range page.Transactions {
+			require.NotNil(t, tx.CommentCount)
+			counts[tx.ID] = *tx.CommentCount
+		}
+		assert.Equal(t, map[uint]int{shared.ID: 2, private.ID: 2}, counts)
+	})
+}
@@ -0,0 +1,159 @@
+package application
+
+import (
+	"context"
+	"slices"
+
+	"go-finance-advisor/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// DataQualityService finds transactions that look booked twice or mistyped.
+// Users merge duplicates, which moves the extra copies to the trash, or
+// dismiss them, so they are not flagged again.
+type DataQualityService struct {
+	DB           *gorm.DB
+	Transactions *TransactionService // Moves merged duplicates to the trash; falls back to one over DB when nil
+}
+
+func NewDataQualityService(db *gorm.DB) *DataQualityService {
+	return &DataQualityService{DB: db}
+}
+
+func (s *DataQualityService) transactions() *TransactionService {
+	if s.Transactions != nil {
+		return s.Transactions
+	}
+	return &TransactionService{DB: s.DB}
+}
+
+// Duplicates returns the user's transactions that look like the same charge
+// booked more than once within windowDays of each other
+func (s *DataQualityService) Duplicates(ctx context.Context, userID uint, windowDays int) ([]domain.DuplicateGroup, error) {
+	if err := domain.ValidateDuplicateWindow(windowDays); err != nil {
+		return nil, err
+	}
+	transactions, err := s.load(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	dismissed, err := s.dismissed(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return domain.FindDuplicates(transactions, windowDays, dismissed), nil
+}
+
+// Report returns the duplicates, the amount outliers and the Benford first
+// digit analysis of the user's transactions
+func (s *DataQualityService) Report(ctx context.Context, userID uint, windowDays int) (*domain.DataQualityReport, error) {
+	if err := domain.ValidateDuplicateWindow(windowDays); err != nil {
+		return nil, err
+	}
+	transactions, err := s.load(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	dismissed, err := s.dismissed(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.DataQualityReport{
+		WindowDays: windowDays,
+		Duplicates: domain.FindDuplicates(transactions, windowDays, dismissed),
+		Outliers:   domain.FindAmountOutliers(transactions, domain.OutlierFactor),
+		Benford:    domain.AnalyzeBenford(transactions),
+	}, nil
+}
+
+// Merge keeps one transaction of a duplicate group and moves the others to
+// the trash, where they can still be restored. It returns the kept transaction.
+func (s *DataQualityService) Merge(ctx context.Context, userID, keepID uint, duplicateIDs []uint) (*domain.Transaction, error) {
+	if err := domain.ValidateDuplicateMerge(keepID, duplicateIDs); err != nil {
+		return nil, err
+	}
+	duplicateIDs = slices.Compact(slices.Sorted(slices.Values(duplicateIDs)))
+	owned, err := s.owned(ctx, userID, append([]uint{keepID}, duplicateIDs...))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range duplicateIDs {
+		if err := s.transactions().Delete(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	kept := owned[keepID]
+	return &kept, nil
+}
+
+// Dismiss records that the transactions are not duplicates of each other
+func (s *DataQualityService) Dismiss(ctx context.Context, userID uint, transactionIDs []uint) error {
+	if err := domain.ValidateDuplicateDismissal(transactionIDs); err != nil {
+		return err
+	}
+	if _, err := s.owned(ctx, userID, transactionIDs); err != nil {
+		return err
+	}
+
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, dismissal := range domain.NewDuplicateDismissals(userID, transactionIDs) {
+			err := tx.Where(domain.DuplicateDismissal{
+				TransactionID:      dismissal.TransactionID,
+				OtherTransactionID: dismissal.OtherTransactionID,
+			}).FirstOrCreate(&dismissal).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// load returns the user's transactions, leaving out the trash
+func (s *DataQualityService) load(ctx context.Context, userID uint) ([]domain.Transaction, error) {
+	var transactions []domain.Transaction
+	err := s.DB.WithContext(ctx).
+		Preload("Category").
+		Preload("Merchant").
+		Where("user_id = ?", userID).
+		Order("date, id").
+		Find(&transactions).Error
+	return transactions, err
+}
+
+// dismissed reports whether the user dismissed a pair of transactions
+func (s *DataQualityService) dismissed(ctx context.Context, userID uint) (func(a, b uint) bool, error) {
+	var dismissals []domain.DuplicateDismissal
+	if err := s.DB.WithContext(ctx).Where("user_id = ?", userID).Find(&dismissals).Error; err != nil {
+		return nil, err
+	}
+	pairs := make(map[[2]uint]bool, len(dismissals))
+	for _, dismissal := range dismissals {
+		pairs[[2]uint{dismissal.TransactionID, dismissal.OtherTransactionID}] = true
+	}
+	return func(a, b uint) bool {
+		return pairs[[2]uint{min(a, b), max(a, b)}]
+	}, nil
+}
+
+// owned returns the transactions by ID, or domain.ErrNotFound unless every
+// one of them is the user's and not in the trash
+func (s *DataQualityService) owned(ctx context.Context, userID uint, ids []uint) (map[uint]domain.Transaction, error) {
+	var transactions []domain.Transaction
+	err := s.DB.WithContext(ctx).Where("user_id = ? AND id IN ?", userID, ids).Find(&transactions).Error
+	if err != nil {
+		return nil, err
+	}
+	owned := make(map[uint]domain.Transaction, len(transactions))
+	for _, transaction := range transactions {
+		owned[transaction.ID] = transaction
+	}
+	for _, id := range ids {
+		if _, ok := owned[id]; !ok {
+			return nil, domain.ErrNotFound
+		}
+	}
+	return owned, nil
+}
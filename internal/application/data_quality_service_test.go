@@ -0,0 +1,130 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupDataQualityTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&domain.User{}, &domain.Category{}, &domain.Merchant{}, &domain.Transaction{},
+		&domain.DuplicateDismissal{}, &domain.AuditLog{},
+	))
+	return db
+}
+
+func TestDataQualityService_Duplicates(t *testing.T) {
+	db := setupDataQualityTestDB(t)
+	service := NewDataQualityService(db)
+	service.Transactions = &TransactionService{DB: db, Audit: NewAuditService(db)}
+	ctx := context.Background()
+
+	user := domain.User{Email: "quality@example.com"}
+	other := domain.User{Email: "other@example.com"}
+	require.NoError(t, db.Create(&user).Error)
+	require.NoError(t, db.Create(&other).Error)
+	category := domain.Category{Name: "Subscriptions", Type: domain.TransactionTypeExpense}
+	require.NoError(t, db.Create(&category).Error)
+
+	day := time.Now().AddDate(0, 0, -10).Truncate(24 * time.Hour)
+	create := func(userID uint, description string, date time.Time) domain.Transaction {
+		transaction := domain.Transaction{
+			UserID: userID, CategoryID: category.ID, Type: domain.TransactionTypeExpense,
+			Amount: domain.NewMoney(15.99), Description: description, Date: date,
+		}
+		require.NoError(t, db.Create(&transaction).Error)
+		return transaction
+	}
+	first := create(user.ID, "SPOTIFY P1234", day)
+	second := create(user.ID, "Spotify", day.AddDate(0, 0, 1))
+	third := create(user.ID, "SPOTIFY", day.AddDate(0, 0, 2))
+	foreign := create(other.ID, "Spotify", day)
+
+	groups, err := service.Duplicates(ctx, user.ID, domain.DefaultDuplicateWindowDays)
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	assert.Len(t, groups[0].Transactions, 3)
+	assert.Equal(t, "Subscriptions", groups[0].Transactions[0].Category.Name)
+
+	t.Run("windows beyond the limit are rejected", func(t *testing.T) {
+		_, err := service.Duplicates(ctx, user.ID, domain.MaxDuplicateWindowDays+1)
+		assert.ErrorIs(t, err, domain.ErrValidation)
+	})
+
+	t.Run("other users' transactions cannot be dismissed or merged", func(t *testing.T) {
+		assert.ErrorIs(t, service.Dismiss(ctx, user.ID, []uint{first.ID, foreign.ID}), domain.ErrNotFound)
+		_, err := service.Merge(ctx, user.ID, first.ID, []uint{foreign.ID})
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("dismissed pairs are not flagged again", func(t *testing.T) {
+		require.NoError(t, service.Dismiss(ctx, user.ID, []uint{first.ID, third.ID}))
+		require.NoError(t, service.Dismiss(ctx, user.ID, []uint{third.ID, first.ID}))
+
+		var count int64
+		require.NoError(t, db.Model(&domain.DuplicateDismissal{}).Count(&count).Error)
+		assert.Equal(t, int64(1), count)
+
+		// The second transaction still links the first and the third
+		groups, err := service.Duplicates(ctx, user.ID, 1)
+		require.NoError(t, err)
+		require.Len(t, groups, 1)
+		assert.Len(t, groups[0].Transactions, 3)
+	})
+
+	t.Run("merging moves the duplicates to the trash", func(t *testing.T) {
+		kept, err := service.Merge(ctx, user.ID, first.ID, []uint{second.ID, second.ID})
+		require.NoError(t, err)
+		assert.Equal(t, first.ID, kept.ID)
+
+		deleted, err := service.Transactions.ListDeleted(ctx, user.ID)
+		require.NoError(t, err)
+		require.Len(t, deleted, 1)
+		assert.Equal(t, second.ID, deleted[0].ID)
+
+		var audits int64
+		require.NoError(t, db.Model(&domain.AuditLog{}).Where("action = ?", domain.AuditActionDelete).Count(&audits).Error)
+		assert.Equal(t, int64(1), audits)
+
+		groups, err := service.Duplicates(ctx, user.ID, domain.DefaultDuplicateWindowDays)
+		require.NoError(t, err)
+		assert.Empty(t, groups)
+	})
+}
+
+func TestDataQualityService_Report(t *testing.T) {
+	db := setupDataQualityTestDB(t)
+	service := NewDataQualityService(db)
+
+	user := domain.User{Email: "report@example.com"}
+	require.NoError(t, db.Create(&user).Error)
+	category := domain.Category{Name: "Groceries", Type: domain.TransactionTypeExpense}
+	require.NoError(t, db.Create(&category).Error)
+	for i, amount := range []float64{52.10, 47.80, 61.25, 55, 5510} {
+		require.NoError(t, db.Create(&domain.Transaction{
+			UserID: user.ID, CategoryID: category.ID, Type: domain.TransactionTypeExpense,
+			Amount: domain.NewMoney(amount), Description: "Market", Date: time.Now().AddDate(0, 0, -7*i),
+		}).Error)
+	}
+
+	report, err := service.Report(context.Background(), user.ID, domain.DefaultDuplicateWindowDays)
+	require.NoError(t, err)
+	assert.Empty(t, report.Duplicates)
+	require.Len(t, report.Outliers, 1)
+	assert.Equal(t, domain.NewMoney(5510), report.Outliers[0].Transaction.Amount)
+	assert.Equal(t, 5, report.Benford.SampleSize)
+	assert.Equal(t, domain.BenfordInsufficientData, report.Benford.Conformity)
+}
@@ -0,0 +1,251 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	mathrand "math/rand/v2"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// demoMonths is how many months of history the demo user gets
+const demoMonths = 6
+
+// demoExpense is a kind of expense the demo user has every month
+type demoExpense struct {
+	category    string
+	description string
+	day         int
+	amount      float64
+	variation   float64 // Fraction the amount varies by from month to month
+	perMonth    int
+}
+
+var demoExpenses = []demoExpense{
+	{category: "Housing", description: "Rent", day: 1, amount: 1450, perMonth: 1},
+	{category: "Bills & Utilities", description: "Electricity and water", day: 5, amount: 120, variation: 0.2, perMonth: 1},
+	{category: "Bills & Utilities", description: "Phone and internet", day: 12, amount: 65, perMonth: 1},
+	{category: "Food & Dining", description: "Groceries", amount: 85, variation: 0.4, perMonth: 5},
+	{category: "Food & Dining", description: "Restaurant", amount: 45, variation: 0.5, perMonth: 3},
+	{category: "Transportation", description: "Fuel", amount: 55, variation: 0.2, perMonth: 2},
+	{category: "Transportation", description: "Transit pass", day: 2, amount: 80, perMonth: 1},
+	{category: "Shopping", description: "Online order", amount: 70, variation: 0.8, perMonth: 2},
+	{category: "Entertainment", description: "Streaming subscriptions", day: 18, amount: 28, perMonth: 1},
+	{category: "Entertainment", description: "Concert tickets", amount: 60, variation: 0.5, perMonth: 1},
+	{category: "Healthcare", description: "Pharmacy", amount: 25, variation: 0.6, perMonth: 1},
+}
+
+// demoBudgets are the demo user's monthly budgets per category
+var demoBudgets = map[string]float64{
+	"Food & Dining":     650,
+	"Transportation":    250,
+	"Shopping":          120,
+	"Entertainment":     150,
+	"Bills & Utilities": 200,
+}
+
+// DemoService keeps the sample data of the read-only demo user
+type DemoService struct {
+	DB      *gorm.DB
+	Budgets *BudgetService // Recalculates the seeded budgets' spending when set
+}
+
+func NewDemoService(db *gorm.DB) *DemoService {
+	return &DemoService{DB: db}
+}
+
+// Seed creates the demo user with the given email if it does not exist yet
+// and replaces its transactions, budgets and goals with a fresh copy of the
+// sample data, dated relative to now. The demo user has no usable password;
+// it is only reached through demo sessions.
+func (s *DemoService) Seed(ctx context.Context, email string) (*domain.User, error) {
+	var user domain.User
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("email = ?", email).First(&user).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if user, err = newDemoUser(email); err != nil {
+				return err
+			}
+			err = tx.Create(&user).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, model := range []any{&domain.Transaction{}, &domain.Budget{}, &domain.FinancialGoal{}} {
+			if err := tx.Unscoped().Where("user_id = ?", user.ID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		categories, err := defaultCategoryIDs(tx)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		if err := tx.CreateInBatches(demoTransactions(user.ID, categories, now), 100).Error; err != nil {
+			return err
+		}
+		if budgets := demoBudgetsFor(user.ID, categories, now); len(budgets) > 0 {
+			if err := tx.Create(&budgets).Error; err != nil {
+				return err
+			}
+		}
+		goals := demoGoals(user.ID, now)
+		return tx.Create(&goals).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("seeding demo data: %w", err)
+	}
+
+	if s.Budgets != nil {
+		if err := s.Budgets.RefreshBudgetSpending(ctx, user.ID); err != nil {
+			return nil, fmt.Errorf("seeding demo data: %w", err)
+		}
+	}
+	return &user, nil
+}
+
+// StartReseed seeds the demo data again on the given interval until ctx is
+// cancelled, so its dates keep following the calendar
+func (s *DemoService) StartReseed(ctx context.Context, email string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := s.Seed(ctx, email); err != nil {
+			log.Printf("demo reseed failed: %v", err)
+		}
+	}
+}
+
+func newDemoUser(email string) (domain.User, error) {
+	password := make([]byte, 32)
+	if _, err := rand.Read(password); err != nil {
+		return domain.User{}, err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(fmt.Sprintf("%x", password)), bcrypt.DefaultCost)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	birthDate := time.Date(1991, time.April, 12, 0, 0, 0, 0, time.UTC)
+	return domain.User{
+		Email: email, Password: string(hash), FirstName: "Demo", LastName: "User",
+		Age: 35, RiskTolerance: domain.RiskToleranceModerate, Locale: "en",
+		UserProfile: domain.UserProfile{
+			BirthDate: &birthDate, EmploymentStatus: "employed", Dependents: 1, MonthlyIncome: domain.NewMoney(4800),
+		},
+	}, nil
+}
+
+// defaultCategoryIDs returns the IDs of the default categories by name,
+// creating them when they have not been initialized yet
+func defaultCategoryIDs(tx *gorm.DB) (map[string]uint, error) {
+	ids := make(map[string]uint)
+	for _, category := range domain.GetDefaultCategories() {
+		err := tx.Where("user_id IS NULL AND name = ? AND type = ?", category.Name, category.Type).
+			FirstOrCreate(&category).Error
+		if err != nil {
+			return nil, err
+		}
+		ids[category.Name] = category.ID
+	}
+	return ids, nil
+}
+
+// demoTransactions builds the demo user's history up to now. The amounts come
+// from a fixed seed, so every copy of the demo shows the same figures.
+func demoTransactions(userID uint, categories map[string]uint, now time.Time) []domain.Transaction {
+	random := mathrand.New(mathrand.NewPCG(2024, 6))
+	firstMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).AddDate(0, 1-demoMonths, 0)
+
+	var transactions []domain.Transaction
+	add := func(date time.Time, category, kind, description string, amount float64) {
+		if date.After(now) {
+			return
+		}
+		transactions = append(transactions, domain.Transaction{
+			UserID: userID, CategoryID: categories[category], Type: kind,
+			Description: description, Amount: domain.NewMoney(amount), Date: date,
+		})
+	}
+	vary := func(amount, variation float64) float64 {
+		return amount * (1 + variation*(2*random.Float64()-1))
+	}
+
+	for month := firstMonth; !month.After(now); month = month.AddDate(0, 1, 0) {
+		// Days drawn at random stay within the part of the month that has passed
+		days := month.AddDate(0, 1, -1).Day()
+		if month.Year() == now.Year() && month.Month() == now.Month() {
+			days = now.Day()
+		}
+		at := func(day int) time.Time { return month.AddDate(0, 0, day-1) }
+
+		add(at(25), "Salary", domain.TransactionTypeIncome, "Monthly salary", 4800)
+		if random.IntN(3) == 0 {
+			add(at(1+random.IntN(days)), "Freelance", domain.TransactionTypeIncome, "Freelance project", vary(650, 0.3))
+		}
+		add(at(28), "Investment", domain.TransactionTypeIncome, "Dividends", vary(40, 0.25))
+
+		for _, expense := range demoExpenses {
+			for i := 0; i < expense.perMonth; i++ {
+				day := expense.day
+				if day == 0 {
+					day = 1 + random.IntN(days)
+				}
+				add(at(day), expense.category, domain.TransactionTypeExpense, expense.description,
+					vary(expense.amount, expense.variation))
+			}
+		}
+	}
+	return transactions
+}
+
+// demoBudgetsFor builds the demo user's budgets for the current month
+func demoBudgetsFor(userID uint, categories map[string]uint, now time.Time) []domain.Budget {
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	end := start.AddDate(0, 1, 0).Add(-time.Second)
+
+	budgets := make([]domain.Budget, 0, len(demoBudgets))
+	for category, amount := range demoBudgets {
+		budgets = append(budgets, domain.Budget{
+			UserID: userID, CategoryID: categories[category], Amount: domain.NewMoney(amount),
+			Remaining: domain.NewMoney(amount), Period: "monthly", StartDate: start, EndDate: end, IsActive: true,
+		})
+	}
+	return budgets
+}
+
+func demoGoals(userID uint, now time.Time) []domain.FinancialGoal {
+	return []domain.FinancialGoal{
+		{
+			UserID: userID, Title: "Emergency fund", Description: "Six months of expenses",
+			TargetAmount: domain.NewMoney(15000), CurrentAmount: domain.NewMoney(9200),
+			TargetDate: now.AddDate(1, 0, 0), GoalType: "savings", Status: domain.GoalStatusActive,
+		},
+		{
+			UserID: userID, Title: "Summer vacation", Description: "Two weeks in Portugal",
+			TargetAmount: domain.NewMoney(3500), CurrentAmount: domain.NewMoney(1400),
+			TargetDate: now.AddDate(0, 8, 0), GoalType: "savings", Status: domain.GoalStatusActive,
+		},
+		{
+			UserID: userID, Title: "Pay off car loan", Description: "Remaining balance of the car loan",
+			TargetAmount: domain.NewMoney(8000), CurrentAmount: domain.NewMoney(8000),
+			TargetDate: now.AddDate(0, -1, 0), GoalType: "debt_payoff", Status: domain.GoalStatusCompleted,
+		},
+	}
+}
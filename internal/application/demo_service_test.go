@@ -0,0 +1,62 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupDemoTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&domain.User{}, &domain.Category{}, &domain.Transaction{}, &domain.TransactionTag{},
+		&domain.Budget{}, &domain.FinancialGoal{},
+	))
+	return db
+}
+
+func TestDemoService_Seed(t *testing.T) {
+	db := setupDemoTestDB(t)
+	ctx := context.Background()
+	service := &DemoService{DB: db, Budgets: &BudgetService{DB: db}}
+
+	user, err := service.Seed(ctx, "demo@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "Demo", user.FirstName)
+
+	count := func(model any) int64 {
+		var n int64
+		require.NoError(t, db.Model(model).Where("user_id = ?", user.ID).Count(&n).Error)
+		return n
+	}
+	transactions, budgets := count(&domain.Transaction{}), count(&domain.Budget{})
+	assert.Greater(t, transactions, int64(50))
+	assert.Equal(t, int64(len(demoBudgets)), budgets)
+	assert.Equal(t, int64(3), count(&domain.FinancialGoal{}))
+
+	var spent int64
+	require.NoError(t, db.Model(&domain.Budget{}).Where("user_id = ? AND spent > 0", user.ID).Count(&spent).Error)
+	assert.Positive(t, spent, "the seeded budgets have their spending filled in")
+
+	t.Run("should replace the sample data when seeded again", func(t *testing.T) {
+		again, err := service.Seed(ctx, "demo@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, again.ID)
+		assert.Equal(t, transactions, count(&domain.Transaction{}))
+		assert.Equal(t, budgets, count(&domain.Budget{}))
+
+		var users int64
+		require.NoError(t, db.Model(&domain.User{}).Count(&users).Error)
+		assert.Equal(t, int64(1), users)
+	})
+}
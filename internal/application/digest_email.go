@@ -0,0 +1,193 @@
+package application
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/i18n"
+	"go-finance-advisor/internal/pkg"
+)
+
+// digestView is the digest with every label translated and every amount and
+// date formatted for the subscriber, so the templates only lay it out
+type digestView struct {
+	Title          string
+	Period         string
+	Summary        [][2]string
+	CategoriesHead string
+	Categories     [][2]string
+	BudgetsHead    string
+	Budgets        []digestBudgetView
+	BillsHead      string
+	NoBills        string
+	Bills          [][2]string
+	InsightsHead   string
+	Insights       []string
+	Unsubscribe    string
+	UnsubscribeURL string
+}
+
+type digestBudgetView struct {
+	Category string
+	Spent    string
+	Status   string
+	Over     bool
+}
+
+var digestHTML = htmltemplate.Must(htmltemplate.New("digest").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: Arial, sans-serif; color: #222; max-width: 600px">
+<h1 style="font-size: 20px">{{.Title}}</h1>
+<p style="color: #666">{{.Period}}</p>
+<table style="width: 100%; border-collapse: collapse">
+{{- range .Summary}}
+<tr><td>{{index . 0}}</td><td style="text-align: right">{{index . 1}}</td></tr>
+{{- end}}
+</table>
+{{- if .Categories}}
+<h2 style="font-size: 16px">{{.CategoriesHead}}</h2>
+<table style="width: 100%; border-collapse: collapse">
+{{- range .Categories}}
+<tr><td>{{index . 0}}</td><td style="text-align: right">{{index . 1}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .Budgets}}
+<h2 style="font-size: 16px">{{.BudgetsHead}}</h2>
+<table style="width: 100%; border-collapse: collapse">
+{{- range .Budgets}}
+<tr><td>{{.Category}}</td><td style="text-align: right">{{.Spent}}</td><td style="text-align: right{{if .Over}}; color: #c0392b{{end}}">{{.Status}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+<h2 style="font-size: 16px">{{.BillsHead}}</h2>
+{{- if .Bills}}
+<table style="width: 100%; border-collapse: collapse">
+{{- range .Bills}}
+<tr><td>{{index . 0}}</td><td style="text-align: right">{{index . 1}}</td></tr>
+{{- end}}
+</table>
+{{- else}}
+<p>{{.NoBills}}</p>
+{{- end}}
+{{- if .Insights}}
+<h2 style="font-size: 16px">{{.InsightsHead}}</h2>
+<ul>
+{{- range .Insights}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- if .UnsubscribeURL}}
+<p style="font-size: 12px; color: #999"><a href="{{.UnsubscribeURL}}">{{.Unsubscribe}}</a></p>
+{{- end}}
+</body>
+</html>
+`))
+
+var digestText = texttemplate.Must(texttemplate.New("digest").Parse(`{{.Title}}
+{{.Period}}
+{{range .Summary}}
+{{index . 0}}: {{index . 1}}
+{{- end}}
+{{- if .Categories}}
+
+{{.CategoriesHead}}
+{{- range .Categories}}
+- {{index . 0}}: {{index . 1}}
+{{- end}}
+{{- end}}
+{{- if .Budgets}}
+
+{{.BudgetsHead}}
+{{- range .Budgets}}
+- {{.Category}}: {{.Spent}} ({{.Status}})
+{{- end}}
+{{- end}}
+
+{{.BillsHead}}
+{{- range .Bills}}
+- {{index . 0}}: {{index . 1}}
+{{- else}}
+{{.NoBills}}
+{{- end}}
+{{- if .Insights}}
+
+{{.InsightsHead}}
+{{- range .Insights}}
+- {{.}}
+{{- end}}
+{{- end}}
+{{- if .UnsubscribeURL}}
+
+{{.Unsubscribe}}: {{.UnsubscribeURL}}
+{{- end}}
+`))
+
+// renderDigestEmail writes the digest as an email in the subscriber's
+// language and formats. Amounts are in the base currency they are kept in.
+func renderDigestEmail(digest *domain.Digest, l *i18n.Localizer) (pkg.EmailMessage, error) {
+	money := func(amount float64) string { return l.Money(domain.NewMoney(amount), domain.BaseCurrency) }
+
+	title := l.T("digest.title_weekly")
+	if digest.Frequency == domain.DigestMonthly {
+		title = l.T("digest.title_monthly")
+	}
+	view := digestView{
+		Title:  title,
+		Period: l.T("report.period_range", map[string]any{"Start": l.Date(digest.StartDate), "End": l.Date(digest.EndDate)}),
+		Summary: [][2]string{
+			{l.T("report.total_income"), money(digest.TotalIncome)},
+			{l.T("report.total_expenses"), money(digest.TotalExpenses)},
+			{l.T("report.net_income"), money(digest.NetIncome)},
+			{l.T("report.savings_rate"), l.Percent(digest.SavingsRate)},
+		},
+		CategoriesHead: l.T("digest.top_categories"),
+		BudgetsHead:    l.T("digest.budgets"),
+		BillsHead:      l.T("digest.upcoming_bills"),
+		NoBills:        l.T("digest.no_bills"),
+		InsightsHead:   l.T("digest.insights"),
+		Unsubscribe:    l.T("digest.unsubscribe"),
+		UnsubscribeURL: digest.UnsubscribeURL,
+	}
+	for _, category := range digest.TopCategories {
+		name := l.Category(category.CategoryName)
+		if category.Uncategorized {
+			name = l.T("report.uncategorized")
+		}
+		view.Categories = append(view.Categories, [2]string{name, money(category.TotalAmount)})
+	}
+	for _, budget := range digest.Budgets {
+		view.Budgets = append(view.Budgets, digestBudgetView{
+			Category: l.Category(budget.Category),
+			Spent: l.T("digest.budget_spent", map[string]any{
+				"Spent": l.Money(budget.Spent, domain.BaseCurrency), "Amount": l.Money(budget.Amount, domain.BaseCurrency),
+			}),
+			Status: l.T("digest.status_" + budget.Status),
+			Over:   budget.Status == "over_budget",
+		})
+	}
+	for _, bill := range digest.UpcomingBills {
+		view.Bills = append(view.Bills, [2]string{
+			l.Date(bill.Date) + " " + bill.Title, l.Money(bill.Amount, domain.BaseCurrency),
+		})
+	}
+	for _, insight := range digest.Insights {
+		view.Insights = append(view.Insights, l.Message(insight.Message))
+	}
+
+	var html, text bytes.Buffer
+	if err := digestHTML.Execute(&html, view); err != nil {
+		return pkg.EmailMessage{}, err
+	}
+	if err := digestText.Execute(&text, view); err != nil {
+		return pkg.EmailMessage{}, err
+	}
+	message := pkg.EmailMessage{To: digest.Email, Subject: title + " · " + view.Period, HTML: html.String(), Text: text.String()}
+	if digest.UnsubscribeURL != "" {
+		message.Headers = map[string]string{"List-Unsubscribe": "<" + digest.UnsubscribeURL + ">"}
+	}
+	return message, nil
+}
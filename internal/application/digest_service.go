@@ -0,0 +1,319 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/i18n"
+	"go-finance-advisor/internal/pkg"
+
+	"gorm.io/gorm"
+)
+
+// JobTypeDigest sends one subscriber the digest of one period
+const JobTypeDigest = "digest"
+
+type digestJob struct {
+	UserID uint      `json:"user_id"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+}
+
+// DigestService sends opted-in users a weekly or monthly email summing up
+// the last period's spending, their budgets, the bills coming up and the top
+// insights. The scheduler queues a job per due subscriber, so a failed
+// delivery is retried by the job runner.
+type DigestService struct {
+	DB       *gorm.DB
+	Reports  *ReportsService
+	Insights *InsightsService
+	Budgets  *BudgetService
+	Bills    *BillService
+	Jobs     *JobService
+	Sender   pkg.EmailSender // Nil while no mail server is configured
+	// UnsubscribeURL is the public unsubscribe endpoint the emails link to;
+	// the subscription's token is added as the token parameter
+	UnsubscribeURL string
+}
+
+func NewDigestService(db *gorm.DB, reports *ReportsService, insights *InsightsService, budgets *BudgetService, bills *BillService) *DigestService {
+	return &DigestService{DB: db, Reports: reports, Insights: insights, Budgets: budgets, Bills: bills}
+}
+
+// Get returns the user's digest subscription
+func (s *DigestService) Get(ctx context.Context, userID uint) (*domain.DigestSubscription, error) {
+	var subscription domain.DigestSubscription
+	err := s.DB.WithContext(ctx).Where("user_id = ?", userID).First(&subscription).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+// Subscribe opts the user in to the digest of the frequency, or changes the
+// frequency of their subscription. The first digest covers the last full
+// period after the subscription.
+func (s *DigestService) Subscribe(ctx context.Context, userID uint, frequency string) (*domain.DigestSubscription, error) {
+	subscription := domain.DigestSubscription{UserID: userID, Frequency: frequency}
+	if err := subscription.Validate(); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.Get(ctx, userID)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		token := make([]byte, 32)
+		if _, err := rand.Read(token); err != nil {
+			return nil, err
+		}
+		subscription.UnsubscribeToken = hex.EncodeToString(token)
+		// Nothing before the subscription is sent
+		_, end := domain.DigestPeriod(frequency, time.Now(), s.firstWeekday(ctx, userID))
+		subscription.QueuedFor = &end
+		if err := s.DB.WithContext(ctx).Create(&subscription).Error; err != nil {
+			return nil, err
+		}
+		return &subscription, nil
+	case err != nil:
+		return nil, err
+	}
+
+	if existing.Frequency != frequency {
+		_, end := domain.DigestPeriod(frequency, time.Now(), s.firstWeekday(ctx, userID))
+		existing.Frequency, existing.QueuedFor = frequency, &end
+		if err := s.DB.WithContext(ctx).Save(existing).Error; err != nil {
+			return nil, err
+		}
+	}
+	return existing, nil
+}
+
+// Unsubscribe opts the user out of the digest
+func (s *DigestService) Unsubscribe(ctx context.Context, userID uint) error {
+	result := s.DB.WithContext(ctx).Where("user_id = ?", userID).Delete(&domain.DigestSubscription{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// UnsubscribeByToken opts out the subscriber an email's unsubscribe link was
+// made for. Links of subscriptions that are already gone report
+// domain.ErrNotFound.
+func (s *DigestService) UnsubscribeByToken(ctx context.Context, token string) error {
+	if token == "" {
+		return domain.ErrNotFound
+	}
+	// The link is followed without logging in, so there is no user to scope to
+	result := s.DB.WithContext(domain.ContextWithoutUserScope(ctx)).
+		Where("unsubscribe_token = ?", token).Delete(&domain.DigestSubscription{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// Compose builds the digest of the period for the user
+func (s *DigestService) Compose(ctx context.Context, userID uint, frequency string, start, end time.Time) (*domain.Digest, error) {
+	var user domain.User
+	if err := s.DB.WithContext(ctx).Select("id", "email").First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	// The report is only read, not kept in the user's report history
+	report, err := s.Reports.buildReport(ctx, userID, domain.ReportTypeCustom, start, end)
+	if err != nil {
+		return nil, err
+	}
+	digest := &domain.Digest{
+		UserID: userID, Email: user.Email, Frequency: frequency, StartDate: start, EndDate: end,
+		TotalIncome: report.TotalIncome, TotalExpenses: report.TotalExpenses,
+		NetIncome: report.NetIncome, SavingsRate: report.SavingsRate,
+		TopCategories: []domain.CategoryMetrics{}, Budgets: []domain.DigestBudget{},
+		UpcomingBills: []domain.CalendarEntry{}, Insights: []domain.Insight{},
+	}
+	for _, category := range report.TopExpenseCategories {
+		if len(digest.TopCategories) == domain.DigestTopCategories {
+			break
+		}
+		digest.TopCategories = append(digest.TopCategories, category)
+	}
+
+	if s.Budgets != nil {
+		budgets, err := s.Budgets.GetActiveBudgetsByUser(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		for i := range budgets {
+			digest.Budgets = append(digest.Budgets, domain.DigestBudget{
+				Category: budgets[i].Category.Name, Amount: budgets[i].Amount, Spent: budgets[i].Spent,
+				PercentageUsed: budgets[i].Spent.PercentOf(budgets[i].Amount), Status: budgets[i].GetBudgetStatus(),
+			})
+		}
+	}
+
+	if s.Bills != nil {
+		// Bills due over the coming period, counted from the day after it
+		from := end.Add(time.Second)
+		to := from.AddDate(0, 0, 6)
+		if frequency == domain.DigestMonthly {
+			to = from.AddDate(0, 1, -1)
+		}
+		calendar, err := s.Bills.Calendar(ctx, userID, from, to)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range calendar.Entries {
+			if entry.Type == domain.TransactionTypeExpense {
+				digest.UpcomingBills = append(digest.UpcomingBills, entry)
+			}
+		}
+	}
+
+	if s.Insights != nil {
+		period := "week"
+		if frequency == domain.DigestMonthly {
+			period = "month"
+		}
+		insights, err := s.Insights.GetInsights(ctx, userID, period)
+		if err != nil {
+			return nil, err
+		}
+		for _, insight := range insights.Insights {
+			if len(digest.Insights) == domain.DigestTopInsights {
+				break
+			}
+			digest.Insights = append(digest.Insights, insight)
+		}
+	}
+	return digest, nil
+}
+
+// RunDigestJob composes and sends one subscriber's digest. Subscribers who
+// unsubscribed after the job was queued get nothing.
+func (s *DigestService) RunDigestJob(ctx context.Context, payload json.RawMessage) error {
+	var job digestJob
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return err
+	}
+	if s.Sender == nil {
+		return errors.New("no mail server is configured")
+	}
+	subscription, err := s.Get(ctx, job.UserID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	digest, err := s.Compose(ctx, job.UserID, subscription.Frequency, job.Start, job.End)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	digest.UnsubscribeURL = s.unsubscribeLink(subscription.UnsubscribeToken)
+
+	preferences := userPreferences(ctx, s.DB, job.UserID)
+	message, err := renderDigestEmail(digest, i18n.New(preferences.Locale).WithPreferences(preferences))
+	if err != nil {
+		return err
+	}
+	if err := s.Sender.Send(ctx, message); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	return s.DB.WithContext(ctx).Model(&domain.DigestSubscription{}).
+		Where("user_id = ?", job.UserID).Update("last_sent_at", now).Error
+}
+
+// QueueDue queues a digest job for every subscriber whose last full period
+// has not been queued yet and returns how many were queued
+func (s *DigestService) QueueDue(ctx context.Context, now time.Time) (int, error) {
+	var subscriptions []domain.DigestSubscription
+	if err := s.DB.WithContext(ctx).Order("user_id").Find(&subscriptions).Error; err != nil {
+		return 0, err
+	}
+
+	queued := 0
+	for i := range subscriptions {
+		subscription := &subscriptions[i]
+		start, end := domain.DigestPeriod(subscription.Frequency, now, s.firstWeekday(ctx, subscription.UserID))
+		if !subscription.Due(end) {
+			continue
+		}
+		// Mark the period first, so a failing queue cannot cause repeats
+		err := s.DB.WithContext(ctx).Model(&domain.DigestSubscription{}).
+			Where("user_id = ?", subscription.UserID).Update("queued_for", end).Error
+		if err != nil {
+			return queued, fmt.Errorf("failed to mark the digest of user %d: %w", subscription.UserID, err)
+		}
+		if _, err := s.Jobs.Enqueue(ctx, JobTypeDigest, digestJob{UserID: subscription.UserID, Start: start, End: end}); err != nil {
+			return queued, err
+		}
+		queued++
+	}
+	return queued, nil
+}
+
+// StartScheduling runs QueueDue on the given interval until ctx is cancelled
+func (s *DigestService) StartScheduling(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.QueueDue(ctx, time.Now()); err != nil {
+			log.Printf("digest scheduling failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// firstWeekday returns the day the user's weeks start on
+func (s *DigestService) firstWeekday(ctx context.Context, userID uint) time.Weekday {
+	preferences := userPreferences(ctx, s.DB, userID)
+	return preferences.FirstWeekday()
+}
+
+// unsubscribeLink adds the token to UnsubscribeURL, or returns "" without one
+func (s *DigestService) unsubscribeLink(token string) string {
+	if s.UnsubscribeURL == "" {
+		return ""
+	}
+	link, err := url.Parse(s.UnsubscribeURL)
+	if err != nil {
+		return ""
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+	return link.String()
+}
@@ -0,0 +1,122 @@
+package application
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/pkg"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type recordingEmailSender struct {
+	sent []pkg.EmailMessage
+}
+
+func (s *recordingEmailSender) Send(_ context.Context, message pkg.EmailMessage) error {
+	s.sent = append(s.sent, message)
+	return nil
+}
+
+func setupDigestTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&domain.User{}, &domain.Preferences{}, &domain.DigestSubscription{}, &domain.Category{},
+		&domain.Transaction{}, &domain.Budget{}, &domain.Bill{}, &domain.FinancialReport{},
+		&domain.AdvisorRecommendation{}, &domain.Job{},
+	))
+	return db
+}
+
+func TestDigestService(t *testing.T) {
+	db := setupDigestTestDB(t)
+	ctx := context.Background()
+	sender := &recordingEmailSender{}
+	service := NewDigestService(db, NewReportsService(db), NewInsightsService(db), &BudgetService{DB: db}, &BillService{DB: db})
+	service.Jobs = &JobService{DB: db}
+	service.Jobs.Register(JobTypeDigest, service.RunDigestJob)
+	service.Sender = sender
+	service.UnsubscribeURL = "https://api.example.com/api/v1/digest/unsubscribe"
+
+	user := domain.User{Email: "ada@example.com", Password: "x", Locale: domain.LocaleGerman}
+	require.NoError(t, db.Create(&user).Error)
+	groceries := domain.Category{Name: "Groceries", Type: domain.TransactionTypeExpense}
+	require.NoError(t, db.Create(&groceries).Error)
+	salary := domain.Category{Name: "Salary", Type: domain.TransactionTypeIncome}
+	require.NoError(t, db.Create(&salary).Error)
+
+	t.Run("should reject unknown frequencies", func(t *testing.T) {
+		_, err := service.Subscribe(ctx, user.ID, "daily")
+		assert.ErrorIs(t, err, domain.ErrValidation)
+	})
+
+	subscription, err := service.Subscribe(ctx, user.ID, domain.DigestWeekly)
+	require.NoError(t, err)
+	assert.Len(t, subscription.UnsubscribeToken, 64)
+
+	t.Run("should not send the periods before the subscription", func(t *testing.T) {
+		queued, err := service.QueueDue(ctx, time.Now())
+		require.NoError(t, err)
+		assert.Zero(t, queued)
+	})
+
+	// The week after the subscription
+	later := time.Now().AddDate(0, 0, 7)
+	start, end := domain.DigestPeriod(domain.DigestWeekly, later, time.Monday)
+	require.NoError(t, db.Create(&[]domain.Transaction{
+		{UserID: user.ID, CategoryID: groceries.ID, Type: domain.TransactionTypeExpense, Amount: domain.NewMoney(120.5), Date: start.Add(12 * time.Hour)},
+		{UserID: user.ID, CategoryID: salary.ID, Type: domain.TransactionTypeIncome, Amount: domain.NewMoney(1000), Date: end.Add(-time.Hour)},
+	}).Error)
+	require.NoError(t, db.Create(&domain.Budget{
+		UserID: user.ID, CategoryID: groceries.ID, Amount: domain.NewMoney(100), Spent: domain.NewMoney(120.5),
+		Period: "monthly", IsActive: true, StartDate: start, EndDate: later.AddDate(0, 1, 0),
+	}).Error)
+	due := end.AddDate(0, 0, 3)
+	require.NoError(t, db.Create(&domain.Bill{UserID: user.ID, Payee: "Landlord", Amount: domain.NewMoney(900), DueDay: due.Day(), Active: true}).Error)
+
+	queued, err := service.QueueDue(ctx, later)
+	require.NoError(t, err)
+	assert.Equal(t, 1, queued)
+	queued, err = service.QueueDue(ctx, later)
+	require.NoError(t, err)
+	assert.Zero(t, queued, "each period is queued once")
+
+	var job domain.Job
+	require.NoError(t, db.Where("type = ?", JobTypeDigest).First(&job).Error)
+	require.NoError(t, service.RunDigestJob(ctx, job.Payload))
+
+	require.Len(t, sender.sent, 1)
+	message := sender.sent[0]
+	assert.Equal(t, "ada@example.com", message.To)
+	assert.True(t, strings.HasPrefix(message.Subject, "Ihre wöchentliche Ausgabenübersicht"), message.Subject)
+	assert.Contains(t, message.Text, "Groceries: 120,50 $")
+	assert.Contains(t, message.Text, "Budget überschritten")
+	assert.Contains(t, message.Text, "Landlord")
+	assert.Contains(t, message.HTML, "token="+subscription.UnsubscribeToken)
+	assert.Equal(t, "<"+service.UnsubscribeURL+"?token="+subscription.UnsubscribeToken+">", message.Headers["List-Unsubscribe"])
+
+	stored, err := service.Get(ctx, user.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, stored.LastSentAt)
+
+	t.Run("should unsubscribe through the email's link", func(t *testing.T) {
+		require.NoError(t, service.UnsubscribeByToken(ctx, subscription.UnsubscribeToken))
+		assert.ErrorIs(t, service.UnsubscribeByToken(ctx, subscription.UnsubscribeToken), domain.ErrNotFound)
+		_, err := service.Get(ctx, user.ID)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+
+		// A digest queued before the unsubscribe is dropped
+		require.NoError(t, service.RunDigestJob(ctx, job.Payload))
+		assert.Len(t, sender.sent, 1)
+	})
+}
@@ -0,0 +1,96 @@
+package application
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"go-finance-advisor/internal/domain"
+)
+
+// EventHandler reacts to a published event. Events are published after the
+// change they describe has been saved, so handlers log their failures
+// rather than returning them.
+type EventHandler func(ctx context.Context, event domain.Event)
+
+// EventBus delivers domain events to the subscribers of their type. Publish
+// runs the handlers one after another before returning, with the context of
+// the change; handlers with slow work, such as webhooks, queue it as jobs.
+// A nil bus drops events.
+type EventBus struct {
+	mu       sync.RWMutex
+	handlers map[string][]EventHandler
+	all      []EventHandler
+}
+
+func NewEventBus() *EventBus {
+	return &EventBus{handlers: make(map[string][]EventHandler)}
+}
+
+// Subscribe runs handler for events of the given types
+func (b *EventBus) Subscribe(handler EventHandler, eventTypes ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, eventType := range eventTypes {
+		b.handlers[eventType] = append(b.handlers[eventType], handler)
+	}
+}
+
+// SubscribeAll runs handler for every event
+func (b *EventBus) SubscribeAll(handler EventHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.all = append(b.all, handler)
+}
+
+// Publish runs the event's handlers in the order they subscribed. Handlers
+// may publish further events themselves. They act for the event's user, who
+// need not be the one acting, so their queries are not scoped to the actor.
+func (b *EventBus) Publish(ctx context.Context, event domain.Event) {
+	if b == nil {
+		return
+	}
+	ctx = domain.ContextWithoutUserScope(ctx)
+	b.mu.RLock()
+	handlers := make([]EventHandler, 0, len(b.handlers[event.EventType()])+len(b.all))
+	handlers = append(handlers, b.handlers[event.EventType()]...)
+	handlers = append(handlers, b.all...)
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		b.run(ctx, handler, event)
+	}
+}
+
+// run keeps one failing handler from stopping the others
+func (b *EventBus) run(ctx context.Context, handler EventHandler, event domain.Event) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			log.Printf("events: handler of %s panicked: %v", event.EventType(), recovered)
+		}
+	}()
+	handler(ctx, event)
+}
+
+// transactionEvents are the events about changes of transactions
+var transactionEvents = []string{
+	domain.EventTransactionCreated, domain.EventTransactionUpdated,
+	domain.EventTransactionDeleted, domain.EventTransactionRestored,
+}
+
+// SubscribeCacheInvalidation drops the cached results of users whose
+// transactions changed
+func SubscribeCacheInvalidation(bus *EventBus, cache ResultCache) {
+	bus.Subscribe(func(ctx context.Context, event domain.Event) {
+		invalidateForEvent(ctx, cache, event)
+	}, transactionEvents...)
+}
+
+func invalidateForEvent(ctx context.Context, cache ResultCache, event domain.Event) {
+	transactions := domain.Transactions(event)
+	userIDs := make([]uint, 0, len(transactions))
+	for i := range transactions {
+		userIDs = append(userIDs, transactions[i].UserID)
+	}
+	invalidateUsers(ctx, cache, userIDs...)
+}
@@ -0,0 +1,110 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestEventBus_Publish(t *testing.T) {
+	bus := NewEventBus()
+	ctx := context.Background()
+
+	var calls []string
+	bus.Subscribe(func(_ context.Context, event domain.Event) {
+		calls = append(calls, "budgets:"+event.EventType())
+	}, domain.EventTransactionCreated, domain.EventTransactionDeleted)
+	bus.Subscribe(func(context.Context, domain.Event) { panic("broken subscriber") }, domain.EventTransactionCreated)
+	bus.SubscribeAll(func(_ context.Context, event domain.Event) {
+		calls = append(calls, "all:"+event.EventType())
+	})
+
+	bus.Publish(ctx, domain.TransactionCreated{Transaction: domain.Transaction{UserID: 1}})
+	bus.Publish(ctx, domain.GoalReached{Goal: domain.FinancialGoal{UserID: 1}})
+	assert.Equal(t, []string{
+		"budgets:transaction.created", "all:transaction.created", "all:goal.reached",
+	}, calls, "a panicking subscriber does not stop the others")
+
+	var nilBus *EventBus
+	assert.NotPanics(t, func() { nilBus.Publish(ctx, domain.TransactionCreated{}) })
+}
+
+func TestEventBus_Subscribers(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&domain.Category{}, &domain.Budget{}, &domain.Transaction{}, &domain.TransactionTag{},
+		&domain.AuditLog{}, &domain.Notification{},
+	))
+	ctx := context.Background()
+
+	bus := NewEventBus()
+	cache := NewMemoryCache()
+	audit := NewAuditService(db)
+	budgets := &BudgetService{DB: db, Events: bus}
+	notifications := NewNotificationService(db)
+	audit.Subscribe(bus)
+	budgets.Subscribe(bus)
+	SubscribeCacheInvalidation(bus, cache)
+	notifications.Subscribe(bus)
+	var published []string
+	bus.SubscribeAll(func(_ context.Context, event domain.Event) { published = append(published, event.EventType()) })
+	transactions := &TransactionService{DB: db, Events: bus}
+
+	food := domain.Category{Name: "Food & Dining", Type: domain.TransactionTypeExpense}
+	require.NoError(t, db.Create(&food).Error)
+	now := time.Now()
+	budget := domain.Budget{
+		UserID: 1, CategoryID: food.ID, Amount: domain.NewMoney(100), Remaining: domain.NewMoney(100),
+		StartDate: now.AddDate(0, 0, -7), EndDate: now.AddDate(0, 0, 7), IsActive: true,
+	}
+	require.NoError(t, db.Create(&budget).Error)
+	cache.Set(ctx, 1, "dashboard", []byte("{}"), time.Hour)
+
+	groceries := &domain.Transaction{
+		UserID: 1, CategoryID: food.ID, Type: domain.TransactionTypeExpense,
+		Description: "Groceries", Amount: domain.NewMoney(80), Date: now,
+	}
+	require.NoError(t, transactions.Create(ctx, groceries))
+	dinner := &domain.Transaction{
+		UserID: 1, CategoryID: food.ID, Type: domain.TransactionTypeExpense,
+		Description: "Dinner", Amount: domain.NewMoney(40), Date: now,
+	}
+	require.NoError(t, transactions.Create(ctx, dinner))
+	dinner.Amount = domain.NewMoney(45)
+	require.NoError(t, transactions.Update(ctx, dinner))
+	require.NoError(t, transactions.Delete(ctx, groceries.ID))
+
+	assert.Equal(t, []string{
+		domain.EventTransactionCreated, domain.EventBudgetExceeded, domain.EventTransactionCreated,
+		domain.EventTransactionUpdated, domain.EventTransactionDeleted,
+	}, published, "the budget is only announced as exceeded when it goes over")
+
+	var entries []domain.AuditLog
+	require.NoError(t, db.Order("id").Find(&entries).Error)
+	require.Len(t, entries, 4)
+	assert.Equal(t, domain.AuditActionDelete, entries[3].Action)
+
+	var spent domain.Budget
+	require.NoError(t, db.First(&spent, budget.ID).Error)
+	assert.Equal(t, domain.NewMoney(45), spent.Spent)
+
+	_, cached := cache.Get(ctx, 1, "dashboard")
+	assert.False(t, cached)
+
+	var notified []domain.Notification
+	require.NoError(t, db.Find(&notified).Error)
+	require.Len(t, notified, 1)
+	assert.Equal(t, domain.NotificationTypeBudget, notified[0].Type)
+	assert.Contains(t, notified[0].Message, "Food & Dining")
+}
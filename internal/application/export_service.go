@@ -2,32 +2,78 @@ package application
 
 import (
 	"bytes"
+	"context"
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"path"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/i18n"
+	"go-finance-advisor/internal/pkg"
 
 	"gorm.io/gorm"
 )
 
 type ExportService struct {
-	DB *gorm.DB
+	DB      *gorm.DB
+	Quotas  *QuotaService   // Refuses transaction exports larger than the user's plan allows when set
+	Storage pkg.BlobStorage // Keeps exports handed out as download links; links are unavailable when nil
 }
 
 func NewExportService(db *gorm.DB) *ExportService {
 	return &ExportService{DB: db}
 }
 
+// LinkExport stores an exported file and returns a signed link it can be
+// downloaded from, for clients that would rather not receive it in the response
+func (s *ExportService) LinkExport(
+	ctx context.Context, userID uint, fileName, contentType string, data []byte,
+) (*domain.FileLink, error) {
+	if s.Storage == nil {
+		return nil, domain.ErrStorageUnavailable
+	}
+	fileName = path.Base(fileName)
+	key := fmt.Sprintf("exports/%d/%d_%s", userID, time.Now().UnixNano(), fileName)
+	if err := s.Storage.Put(ctx, key, contentType, data); err != nil {
+		return nil, fmt.Errorf("failed to store export: %w", err)
+	}
+	url, err := s.Storage.SignedURL(ctx, key, fileLinkTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.FileLink{URL: url, FileName: fileName, ExpiresAt: time.Now().Add(fileLinkTTL)}, nil
+}
+
 // ExportTransactions exports user transactions in the specified format
 func (s *ExportService) ExportTransactions(
-	userID uint, format domain.ExportFormat, startDate, endDate *time.Time,
+	ctx context.Context, userID uint, format domain.ExportFormat, startDate, endDate *time.Time,
+) (data []byte, filename string, err error) {
+	return s.ExportTransactionsWithTemplate(ctx, userID, 0, format, startDate, endDate)
+}
+
+// ExportTransactionsWithTemplate exports user transactions laid out by one of
+// their export templates, or like ExportTransactions when templateID is 0.
+// Templated JSON exports hold only the template's columns.
+func (s *ExportService) ExportTransactionsWithTemplate(
+	ctx context.Context, userID, templateID uint, format domain.ExportFormat, startDate, endDate *time.Time,
 ) (data []byte, filename string, err error) {
+	template := defaultExportTemplate
+	if templateID != 0 {
+		saved, templateErr := s.template(ctx, userID, templateID)
+		if templateErr != nil {
+			return nil, "", templateErr
+		}
+		template = *saved
+	}
+
 	// Get transactions
 	var transactions []domain.Transaction
-	query := s.DB.Preload("Category").Where("user_id = ?", userID)
+	query := s.DB.WithContext(ctx).Preload("Category").Where("user_id = ?", userID)
 
 	if startDate != nil && endDate != nil {
 		query = query.Where("date BETWEEN ? AND ?", *startDate, *endDate)
@@ -37,22 +83,44 @@ func (s *ExportService) ExportTransactions(
 	if err != nil {
 		return nil, "", err
 	}
+	if err := s.checkExportSize(ctx, userID, len(transactions)); err != nil {
+		return nil, "", err
+	}
+	if slices.Contains(template.Columns, domain.ExportColumnTags) {
+		if err := loadTransactionTags(ctx, s.DB, transactions); err != nil {
+			return nil, "", err
+		}
+	}
+	// Full JSON exports hold the transactions with their line items
+	if slices.Contains(template.Columns, domain.ExportColumnItems) || (templateID == 0 && format == domain.ExportFormatJSON) {
+		if err := loadTransactionItems(ctx, s.DB, transactions); err != nil {
+			return nil, "", err
+		}
+	}
 
 	switch format {
 	case domain.ExportFormatCSV:
-		return s.exportTransactionsCSV(transactions)
+		data, err = s.exportTransactionsCSV(transactions, s.localizer(ctx, userID), &template)
 	case domain.ExportFormatJSON:
-		return s.exportTransactionsJSON(transactions)
-	case domain.ExportFormatPDF:
-		return nil, "", fmt.Errorf("unsupported export format: %s", format)
+		if templateID == 0 {
+			data, err = json.MarshalIndent(transactions, "", "  ")
+		} else {
+			data, err = s.exportTransactionRowsJSON(transactions, &template)
+		}
+	case domain.ExportFormatQIF:
+		data, err = s.exportTransactionsQIF(transactions, &template)
 	default:
 		return nil, "", fmt.Errorf("unsupported export format: %s", format)
 	}
+	if err != nil {
+		return nil, "", err
+	}
+	return data, template.Filename(transactionsFilename, time.Now(), startDate, endDate, format), nil
 }
 
 // ExportFinancialReport exports a financial report in the specified format
 func (s *ExportService) ExportFinancialReport(
-	userID uint, reportType string, year, month int, format domain.ExportFormat,
+	ctx context.Context, userID uint, reportType string, year, month int, format domain.ExportFormat,
 ) (data []byte, filename string, err error) {
 	// Generate the report first
 	reportsService := NewReportsService(s.DB)
@@ -60,9 +128,9 @@ func (s *ExportService) ExportFinancialReport(
 
 	switch reportType {
 	case "monthly":
-		report, err = reportsService.GenerateMonthlyReport(userID, year, month)
+		report, err = reportsService.GenerateMonthlyReport(ctx, userID, year, month)
 	case "yearly":
-		report, err = reportsService.GenerateYearlyReport(userID, year)
+		report, err = reportsService.GenerateYearlyReport(ctx, userID, year)
 	default:
 		return nil, "", fmt.Errorf("unsupported report type: %s", reportType)
 	}
@@ -75,7 +143,7 @@ func (s *ExportService) ExportFinancialReport(
 	case domain.ExportFormatJSON:
 		return s.exportReportJSON(report)
 	case domain.ExportFormatCSV:
-		return s.exportReportCSV(report)
+		return s.exportReportCSV(report, s.localizer(ctx, userID))
 	case domain.ExportFormatPDF:
 		return nil, "", fmt.Errorf("unsupported export format: %s", format)
 	default:
@@ -83,18 +151,45 @@ func (s *ExportService) ExportFinancialReport(
 	}
 }
 
+// ExportAccountStatement exports the monthly statement of one of the user's
+// accounts as CSV or PDF
+func (s *ExportService) ExportAccountStatement(
+	ctx context.Context, userID, accountID uint, month time.Time, format domain.ExportFormat,
+) (data []byte, filename string, err error) {
+	statement, err := NewAccountService(s.DB).Statement(ctx, userID, accountID, month)
+	if err != nil {
+		return nil, "", err
+	}
+
+	filename = fmt.Sprintf("statement_%d_%s.%s", accountID, statement.Month, format)
+	switch format {
+	case domain.ExportFormatCSV:
+		data, err = s.exportStatementCSV(statement, s.localizer(ctx, userID))
+	case domain.ExportFormatPDF:
+		data = s.exportStatementPDF(statement, s.localizer(ctx, userID))
+	default:
+		return nil, "", fmt.Errorf("unsupported export format: %s", format)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return data, filename, nil
+}
+
 // ExportBudgets exports user budgets in the specified format
-func (s *ExportService) ExportBudgets(userID uint, format domain.ExportFormat) (data []byte, filename string, err error) {
+func (s *ExportService) ExportBudgets(
+	ctx context.Context, userID uint, format domain.ExportFormat,
+) (data []byte, filename string, err error) {
 	// Get budgets
 	var budgets []domain.Budget
-	err = s.DB.Preload("Category").Where("user_id = ?", userID).Find(&budgets).Error
+	err = s.DB.WithContext(ctx).Preload("Category").Where("user_id = ?", userID).Find(&budgets).Error
 	if err != nil {
 		return nil, "", err
 	}
 
 	switch format {
 	case domain.ExportFormatCSV:
-		return s.exportBudgetsCSV(budgets)
+		return s.exportBudgetsCSV(budgets, s.localizer(ctx, userID))
 	case domain.ExportFormatJSON:
 		return s.exportBudgetsJSON(budgets)
 	case domain.ExportFormatPDF:
@@ -105,26 +200,34 @@ func (s *ExportService) ExportBudgets(userID uint, format domain.ExportFormat) (
 }
 
 // ExportAllData exports all user financial data
-func (s *ExportService) ExportAllData(userID uint, format domain.ExportFormat) (data []byte, filename string, err error) {
+func (s *ExportService) ExportAllData(
+	ctx context.Context, userID uint, format domain.ExportFormat,
+) (data []byte, filename string, err error) {
 	// Get all data
 	var transactions []domain.Transaction
 	var budgets []domain.Budget
 	var categories []domain.Category
 
 	// Get transactions
-	err = s.DB.Preload("Category").Where("user_id = ?", userID).Find(&transactions).Error
+	err = s.DB.WithContext(ctx).Preload("Category").Where("user_id = ?", userID).Find(&transactions).Error
 	if err != nil {
 		return nil, "", err
 	}
+	if err := s.checkExportSize(ctx, userID, len(transactions)); err != nil {
+		return nil, "", err
+	}
+	if err := loadTransactionItems(ctx, s.DB, transactions); err != nil {
+		return nil, "", err
+	}
 
 	// Get budgets
-	err = s.DB.Preload("Category").Where("user_id = ?", userID).Find(&budgets).Error
+	err = s.DB.WithContext(ctx).Preload("Category").Where("user_id = ?", userID).Find(&budgets).Error
 	if err != nil {
 		return nil, "", err
 	}
 
 	// Get categories (all categories since they are global)
-	err = s.DB.Find(&categories).Error
+	err = s.DB.WithContext(ctx).Find(&categories).Error
 	if err != nil {
 		return nil, "", err
 	}
@@ -149,55 +252,210 @@ func (s *ExportService) ExportAllData(userID uint, format domain.ExportFormat) (
 	}
 }
 
-func (s *ExportService) exportTransactionsCSV(transactions []domain.Transaction) (data []byte, filename string, err error) {
+// checkExportSize refuses exports of more transactions than the user's plan allows
+func (s *ExportService) checkExportSize(ctx context.Context, userID uint, rows int) error {
+	if s.Quotas == nil {
+		return nil
+	}
+	return s.Quotas.CheckExportSize(ctx, userID, rows)
+}
+
+// localizer returns the localizer of the user's locale with their preferred
+// date and number formats, which CSV and PDF exports are written in
+func (s *ExportService) localizer(ctx context.Context, userID uint) *i18n.Localizer {
+	preferences := userPreferences(ctx, s.DB, userID)
+	return i18n.New(preferences.Locale).WithPreferences(preferences)
+}
+
+// defaultExportTemplate lays out transaction exports made without a template
+var defaultExportTemplate = domain.ExportTemplate{Columns: []string{
+	domain.ExportColumnID, domain.ExportColumnDate, domain.ExportColumnDescription, domain.ExportColumnAmount,
+	domain.ExportColumnType, domain.ExportColumnCategory, domain.ExportColumnCreatedAt,
+}}
+
+// transactionsFilename names transaction exports whose template has no pattern
+const transactionsFilename = "transactions_" + domain.FilenamePlaceholderToday
+
+// exportColumnLabels are the message IDs of the column headers
+var exportColumnLabels = map[string]string{
+	domain.ExportColumnID:             "export.row_id",
+	domain.ExportColumnDate:           "export.date",
+	domain.ExportColumnDescription:    "export.details",
+	domain.ExportColumnNotes:          "export.notes",
+	domain.ExportColumnTags:           "export.tags",
+	domain.ExportColumnItems:          "export.items",
+	domain.ExportColumnAmount:         "report.amount",
+	domain.ExportColumnType:           "report.type",
+	domain.ExportColumnCategory:       "report.category",
+	domain.ExportColumnCreatedAt:      "export.created_at",
+	domain.ExportColumnCurrency:       "export.currency",
+	domain.ExportColumnOriginalAmount: "export.original_amount",
+	domain.ExportColumnExchangeRate:   "export.exchange_rate",
+}
+
+func (s *ExportService) exportTransactionsCSV(
+	transactions []domain.Transaction, l *i18n.Localizer, template *domain.ExportTemplate,
+) ([]byte, error) {
 	var buf bytes.Buffer
 	writer := csv.NewWriter(&buf)
+	writer.Comma = template.Comma()
 
 	// Write header
-	header := []string{"ID", "Date", "Description", "Amount", "Type", "Category", "Created At"}
+	header := make([]string, len(template.Columns))
+	for i, column := range template.Columns {
+		header[i] = l.T(exportColumnLabels[column])
+	}
 	if err := writer.Write(header); err != nil {
-		return nil, "", err
+		return nil, err
 	}
 
 	// Write data
+	layout := template.DateLayout()
 	for i := range transactions {
 		tx := &transactions[i]
-		categoryName := ""
-		if tx.Category.Name != "" {
-			categoryName = tx.Category.Name
-		}
-
-		record := []string{
-			strconv.FormatUint(uint64(tx.ID), 10),
-			tx.Date.Format("2006-01-02"),
-			tx.Description,
-			strconv.FormatFloat(tx.Amount, 'f', 2, 64),
-			tx.Type,
-			categoryName,
-			tx.CreatedAt.Format("2006-01-02 15:04:05"),
+		record := make([]string, len(template.Columns))
+		for j, column := range template.Columns {
+			switch column {
+			case domain.ExportColumnID:
+				record[j] = strconv.FormatUint(uint64(tx.ID), 10)
+			case domain.ExportColumnDate:
+				record[j] = l.Date(tx.Date)
+				if layout != "" {
+					record[j] = tx.Date.Format(layout)
+				}
+			case domain.ExportColumnDescription:
+				record[j] = tx.Description
+			case domain.ExportColumnNotes:
+				record[j] = tx.Notes
+			case domain.ExportColumnTags:
+				record[j] = strings.Join(tx.Tags, ", ")
+			case domain.ExportColumnItems:
+				record[j] = itemsCell(tx.Items, l)
+			case domain.ExportColumnAmount:
+				record[j] = l.Number(tx.Amount.Float64(), 2)
+			case domain.ExportColumnType:
+				record[j] = tx.Type
+			case domain.ExportColumnCategory:
+				record[j] = l.Category(tx.Category.Name)
+			case domain.ExportColumnCurrency:
+				record[j], _, _ = tx.Original()
+			case domain.ExportColumnOriginalAmount:
+				_, amount, _ := tx.Original()
+				record[j] = l.Number(amount.Float64(), 2)
+			case domain.ExportColumnExchangeRate:
+				_, _, rate := tx.Original()
+				record[j] = l.Number(rate, 6)
+			case domain.ExportColumnCreatedAt:
+				record[j] = l.DateTime(tx.CreatedAt)
+				if layout != "" {
+					record[j] = tx.CreatedAt.Format(layout + " 15:04")
+				}
+			}
 		}
 		if err := writer.Write(record); err != nil {
-			return nil, "", err
+			return nil, err
 		}
 	}
 
 	writer.Flush()
 	if err := writer.Error(); err != nil {
-		return nil, "", err
+		return nil, err
 	}
+	return buf.Bytes(), nil
+}
 
-	filename = fmt.Sprintf("transactions_%s.csv", time.Now().Format("2006-01-02"))
-	return buf.Bytes(), filename, nil
+// itemsCell writes line items into one CSV field, e.g. "2 x Coffee @ 3.50; 1 x Croissant @ 2.20"
+func itemsCell(items []domain.TransactionItem, l *i18n.Localizer) string {
+	cells := make([]string, len(items))
+	for i := range items {
+		cells[i] = strconv.FormatFloat(items[i].Quantity, 'f', -1, 64) + " x " + items[i].Name + " @ " + l.Number(items[i].UnitPrice.Float64(), 2)
+	}
+	return strings.Join(cells, "; ")
 }
 
-func (s *ExportService) exportTransactionsJSON(transactions []domain.Transaction) (data []byte, filename string, err error) {
-	data, err = json.MarshalIndent(transactions, "", "  ")
-	if err != nil {
-		return nil, "", err
+// exportTransactionRowsJSON writes one object per transaction holding the
+// template's columns. Dates are strings when the template formats them.
+func (s *ExportService) exportTransactionRowsJSON(transactions []domain.Transaction, template *domain.ExportTemplate) ([]byte, error) {
+	layout := template.DateLayout()
+	date := func(t time.Time) any {
+		if layout == "" {
+			return t
+		}
+		return t.Format(layout)
+	}
+	rows := make([]map[string]any, len(transactions))
+	for i := range transactions {
+		tx := &transactions[i]
+		row := make(map[string]any, len(template.Columns))
+		for _, column := range template.Columns {
+			switch column {
+			case domain.ExportColumnID:
+				row[column] = tx.ID
+			case domain.ExportColumnDate:
+				row[column] = date(tx.Date)
+			case domain.ExportColumnDescription:
+				row[column] = tx.Description
+			case domain.ExportColumnNotes:
+				row[column] = tx.Notes
+			case domain.ExportColumnTags:
+				row[column] = append([]string{}, tx.Tags...)
+			case domain.ExportColumnItems:
+				row[column] = append([]domain.TransactionItem{}, tx.Items...)
+			case domain.ExportColumnAmount:
+				row[column] = tx.Amount
+			case domain.ExportColumnType:
+				row[column] = tx.Type
+			case domain.ExportColumnCategory:
+				row[column] = tx.Category.Name
+			case domain.ExportColumnCreatedAt:
+				row[column] = tx.CreatedAt
+			case domain.ExportColumnCurrency:
+				row[column], _, _ = tx.Original()
+			case domain.ExportColumnOriginalAmount:
+				_, row[column], _ = tx.Original()
+			case domain.ExportColumnExchangeRate:
+				_, _, row[column] = tx.Original()
+			}
+		}
+		rows[i] = row
 	}
+	return json.MarshalIndent(rows, "", "  ")
+}
 
-	filename = fmt.Sprintf("transactions_%s.json", time.Now().Format("2006-01-02"))
-	return data, filename, nil
+// qifDateLayout is how QIF files date transactions unless the template says
+// otherwise; Quicken reads US dates
+const qifDateLayout = "01/02/2006"
+
+// exportTransactionsQIF writes the transactions as a QIF bank account: one
+// record per transaction with its date, signed amount, description as payee,
+// notes as memo and category. Of the template, only the date format applies.
+func (s *ExportService) exportTransactionsQIF(transactions []domain.Transaction, template *domain.ExportTemplate) ([]byte, error) {
+	layout := template.DateLayout()
+	if layout == "" {
+		layout = qifDateLayout
+	}
+	// QIF fields run to the end of their line
+	line := strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ")
+
+	var buf bytes.Buffer
+	buf.WriteString("!Type:Bank\n")
+	for i := range transactions {
+		tx := &transactions[i]
+		// A transfer is written as the money leaving the account it was made from
+		amount := tx.Signed()
+		if tx.IsTransfer() && tx.AccountID != nil {
+			amount = tx.SignedFor(*tx.AccountID)
+		}
+		fmt.Fprintf(&buf, "D%s\nT%s\nP%s\n", tx.Date.Format(layout), amount, line.Replace(tx.Description))
+		if tx.Notes != "" {
+			fmt.Fprintf(&buf, "M%s\n", line.Replace(tx.Notes))
+		}
+		if tx.Category.Name != "" {
+			fmt.Fprintf(&buf, "L%s\n", line.Replace(tx.Category.Name))
+		}
+		buf.WriteString("^\n")
+	}
+	return buf.Bytes(), nil
 }
 
 func (s *ExportService) exportReportJSON(report *domain.FinancialReport) (data []byte, filename string, err error) {
@@ -210,24 +468,28 @@ func (s *ExportService) exportReportJSON(report *domain.FinancialReport) (data [
 	return data, filename, nil
 }
 
-func (s *ExportService) exportReportCSV(report *domain.FinancialReport) (data []byte, filename string, err error) {
+func (s *ExportService) exportReportCSV(
+	report *domain.FinancialReport, l *i18n.Localizer,
+) (data []byte, filename string, err error) {
 	var buf bytes.Buffer
 	writer := csv.NewWriter(&buf)
 
 	// Write report summary
-	summaryHeader := []string{"Metric", "Value"}
+	summaryHeader := []string{l.T("report.metric"), l.T("report.value")}
 	if err := writer.Write(summaryHeader); err != nil {
 		return nil, "", err
 	}
 
 	summaryData := [][]string{
-		{"Report Type", report.ReportType},
-		{"Period", fmt.Sprintf("%s to %s", report.StartDate.Format("2006-01-02"), report.EndDate.Format("2006-01-02"))},
-		{"Total Income", strconv.FormatFloat(report.TotalIncome, 'f', 2, 64)},
-		{"Total Expenses", strconv.FormatFloat(report.TotalExpenses, 'f', 2, 64)},
-		{"Net Income", strconv.FormatFloat(report.NetIncome, 'f', 2, 64)},
-		{"Savings Rate", strconv.FormatFloat(report.SavingsRate, 'f', 2, 64) + "%"},
-		{"Transaction Count", strconv.Itoa(report.TransactionCount)},
+		{l.T("report.report_type"), report.ReportType},
+		{l.T("report.period"), l.T("report.period_range", map[string]any{
+			"Start": l.Date(report.StartDate), "End": l.Date(report.EndDate),
+		})},
+		{l.T("report.total_income"), l.Number(report.TotalIncome, 2)},
+		{l.T("report.total_expenses"), l.Number(report.TotalExpenses, 2)},
+		{l.T("report.net_income"), l.Number(report.NetIncome, 2)},
+		{l.T("report.savings_rate"), l.Percent(report.SavingsRate)},
+		{l.T("report.transaction_count"), strconv.Itoa(report.TransactionCount)},
 	}
 
 	for _, row := range summaryData {
@@ -242,17 +504,19 @@ func (s *ExportService) exportReportCSV(report *domain.FinancialReport) (data []
 	}
 
 	// Write category breakdown
-	categoryHeader := []string{"Category", "Type", "Amount", "Percentage", "Transaction Count"}
+	categoryHeader := []string{
+		l.T("report.category"), l.T("report.type"), l.T("report.amount"), l.T("report.percentage"), l.T("report.transaction_count"),
+	}
 	if err := writer.Write(categoryHeader); err != nil {
 		return nil, "", err
 	}
 
 	for _, category := range report.CategoryBreakdown {
 		row := []string{
-			category.CategoryName,
+			l.Category(category.CategoryName),
 			"expense", // Default type since CategoryType doesn't exist
-			strconv.FormatFloat(category.TotalAmount, 'f', 2, 64),
-			strconv.FormatFloat(category.PercentageOfTotal, 'f', 2, 64) + "%",
+			l.Number(category.TotalAmount, 2),
+			l.Percent(category.PercentageOfTotal),
 			strconv.Itoa(category.TransactionCount),
 		}
 		if err := writer.Write(row); err != nil {
@@ -269,12 +533,71 @@ func (s *ExportService) exportReportCSV(report *domain.FinancialReport) (data []
 	return buf.Bytes(), filename, nil
 }
 
-func (s *ExportService) exportBudgetsCSV(budgets []domain.Budget) (data []byte, filename string, err error) {
+// statementSummary is the heading of statement exports, one label and value
+// per row
+func statementSummary(statement *domain.AccountStatement, l *i18n.Localizer) [][]string {
+	return [][]string{
+		{l.T("statement.account"), statement.Account.Name},
+		{l.T("statement.month"), l.Month(statement.From.Month()) + " " + strconv.Itoa(statement.From.Year())},
+		{l.T("statement.opening_balance"), l.Number(statement.OpeningBalance.Float64(), 2)},
+		{l.T("statement.money_in"), l.Number(statement.TotalIn.Float64(), 2)},
+		{l.T("statement.money_out"), l.Number(statement.TotalOut.Float64(), 2)},
+		{l.T("statement.closing_balance"), l.Number(statement.ClosingBalance.Float64(), 2)},
+	}
+}
+
+func (s *ExportService) exportStatementCSV(statement *domain.AccountStatement, l *i18n.Localizer) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	// Write statement summary
+	rows := append([][]string{{l.T("report.metric"), l.T("report.value")}}, statementSummary(statement, l)...)
+	rows = append(rows, []string{"", ""}, []string{
+		l.T("export.date"), l.T("export.details"), l.T("report.type"), l.T("report.amount"), l.T("statement.balance"),
+	})
+
+	// Write entries, expenses as negative amounts
+	for _, entry := range statement.Entries {
+		rows = append(rows, []string{
+			l.Date(entry.Date), entry.Description, entry.Type,
+			l.Number(entry.Signed().Float64(), 2), l.Number(entry.Balance.Float64(), 2),
+		})
+	}
+
+	if err := writer.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// exportStatementPDF writes the statement as a fixed-width table
+func (s *ExportService) exportStatementPDF(statement *domain.AccountStatement, l *i18n.Localizer) []byte {
+	const row = "%-10s  %-36.36s  %12s  %12s"
+	lines := []string{l.T("statement.title"), ""}
+	for _, summary := range statementSummary(statement, l) {
+		lines = append(lines, fmt.Sprintf("%-20s %s", summary[0], summary[1]))
+	}
+	lines = append(lines, "",
+		fmt.Sprintf(row, l.T("export.date"), l.T("export.details"), l.T("report.amount"), l.T("statement.balance")),
+		strings.Repeat("-", 76),
+	)
+	for _, entry := range statement.Entries {
+		lines = append(lines, fmt.Sprintf(row,
+			l.Date(entry.Date), entry.Description, l.Number(entry.Signed().Float64(), 2), l.Number(entry.Balance.Float64(), 2),
+		))
+	}
+	return writeTextPDF(lines)
+}
+
+func (s *ExportService) exportBudgetsCSV(budgets []domain.Budget, l *i18n.Localizer) (data []byte, filename string, err error) {
 	var buf bytes.Buffer
 	writer := csv.NewWriter(&buf)
 
 	// Write header
-	header := []string{"ID", "Category", "Amount", "Period", "Start Date", "End Date", "Is Active", "Created At"}
+	header := []string{
+		l.T("export.row_id"), l.T("report.category"), l.T("report.amount"), l.T("report.period"),
+		l.T("export.start_date"), l.T("export.end_date"), l.T("export.is_active"), l.T("export.created_at"),
+	}
 	if err := writer.Write(header); err != nil {
 		return nil, "", err
 	}
@@ -282,20 +605,15 @@ func (s *ExportService) exportBudgetsCSV(budgets []domain.Budget) (data []byte,
 	// Write data
 	for i := range budgets {
 		budget := &budgets[i]
-		categoryName := ""
-		if budget.Category.Name != "" {
-			categoryName = budget.Category.Name
-		}
-
 		record := []string{
 			strconv.FormatUint(uint64(budget.ID), 10),
-			categoryName,
-			strconv.FormatFloat(budget.Amount, 'f', 2, 64),
+			l.Category(budget.Category.Name),
+			l.Number(budget.Amount.Float64(), 2),
 			budget.Period,
-			budget.StartDate.Format("2006-01-02"),
-			budget.EndDate.Format("2006-01-02"),
+			l.Date(budget.StartDate),
+			l.Date(budget.EndDate),
 			strconv.FormatBool(budget.IsActive),
-			budget.CreatedAt.Format("2006-01-02 15:04:05"),
+			l.DateTime(budget.CreatedAt),
 		}
 		if err := writer.Write(record); err != nil {
 			return nil, "", err
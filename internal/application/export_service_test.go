@@ -1,8 +1,10 @@
 package application
 
 import (
+	"context"
 	"encoding/csv"
 	"encoding/json"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -17,7 +19,8 @@ import (
 
 func setupExportTestDB() *gorm.DB {
 	db, _ := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
-	db.AutoMigrate(&domain.User{}, &domain.Transaction{}, &domain.Budget{}, &domain.Category{}, &domain.FinancialReport{})
+	db.AutoMigrate(&domain.User{}, &domain.Transaction{}, &domain.Budget{}, &domain.Category{}, &domain.FinancialReport{},
+		&domain.ExportTemplate{}, &domain.TransactionTag{}, &domain.TransactionItem{}, &domain.Account{})
 	return db
 }
 
@@ -48,7 +51,7 @@ func createExportTestData(db *gorm.DB) (userID uint) {
 		{
 			UserID:      user.ID,
 			CategoryID:  foodCategory.ID,
-			Amount:      50.00,
+			Amount:      domain.NewMoney(50.00),
 			Type:        "expense",
 			Description: "Grocery shopping",
 			Date:        time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
@@ -56,7 +59,7 @@ func createExportTestData(db *gorm.DB) (userID uint) {
 		{
 			UserID:      user.ID,
 			CategoryID:  salaryCategory.ID,
-			Amount:      3000.00,
+			Amount:      domain.NewMoney(3000.00),
 			Type:        "income",
 			Description: "Monthly salary",
 			Date:        time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
@@ -64,7 +67,7 @@ func createExportTestData(db *gorm.DB) (userID uint) {
 		{
 			UserID:      user.ID,
 			CategoryID:  foodCategory.ID,
-			Amount:      25.50,
+			Amount:      domain.NewMoney(25.50),
 			Type:        "expense",
 			Description: "Restaurant dinner",
 			Date:        time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC),
@@ -80,7 +83,7 @@ func createExportTestData(db *gorm.DB) (userID uint) {
 		{
 			UserID:     user.ID,
 			CategoryID: foodCategory.ID,
-			Amount:     200.00,
+			Amount:     domain.NewMoney(200.00),
 			Period:     "monthly",
 			StartDate:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
 			EndDate:    time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
@@ -89,7 +92,7 @@ func createExportTestData(db *gorm.DB) (userID uint) {
 		{
 			UserID:     user.ID,
 			CategoryID: salaryCategory.ID,
-			Amount:     500.00,
+			Amount:     domain.NewMoney(500.00),
 			Period:     "monthly",
 			StartDate:  time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
 			EndDate:    time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
@@ -110,7 +113,7 @@ func TestExportService_ExportTransactions(t *testing.T) {
 	userID := createExportTestData(db)
 
 	t.Run("export transactions as CSV", func(t *testing.T) {
-		data, filename, err := service.ExportTransactions(userID, domain.ExportFormatCSV, nil, nil)
+		data, filename, err := service.ExportTransactions(context.Background(), userID, domain.ExportFormatCSV, nil, nil)
 		require.NoError(t, err)
 		assert.NotEmpty(t, data)
 		assert.Contains(t, filename, "transactions_")
@@ -131,7 +134,7 @@ func TestExportService_ExportTransactions(t *testing.T) {
 	})
 
 	t.Run("export transactions as JSON", func(t *testing.T) {
-		data, filename, err := service.ExportTransactions(userID, domain.ExportFormatJSON, nil, nil)
+		data, filename, err := service.ExportTransactions(context.Background(), userID, domain.ExportFormatJSON, nil, nil)
 		require.NoError(t, err)
 		assert.NotEmpty(t, data)
 		assert.Contains(t, filename, "transactions_")
@@ -149,7 +152,7 @@ func TestExportService_ExportTransactions(t *testing.T) {
 		startDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
 		endDate := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
 
-		data, filename, err := service.ExportTransactions(userID, domain.ExportFormatJSON, &startDate, &endDate)
+		data, filename, err := service.ExportTransactions(context.Background(), userID, domain.ExportFormatJSON, &startDate, &endDate)
 		require.NoError(t, err)
 		assert.NotEmpty(t, data)
 		assert.Contains(t, filename, "transactions_")
@@ -166,13 +169,13 @@ func TestExportService_ExportTransactions(t *testing.T) {
 	})
 
 	t.Run("export transactions with unsupported format", func(t *testing.T) {
-		_, _, err := service.ExportTransactions(userID, domain.ExportFormatPDF, nil, nil)
+		_, _, err := service.ExportTransactions(context.Background(), userID, domain.ExportFormatPDF, nil, nil)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "unsupported export format")
 	})
 
 	t.Run("export transactions for non-existent user", func(t *testing.T) {
-		data, filename, err := service.ExportTransactions(99999, domain.ExportFormatJSON, nil, nil)
+		data, filename, err := service.ExportTransactions(context.Background(), 99999, domain.ExportFormatJSON, nil, nil)
 		require.NoError(t, err) // Should not error, just return empty data
 		assert.NotEmpty(t, filename)
 
@@ -189,7 +192,7 @@ func TestExportService_ExportBudgets(t *testing.T) {
 	userID := createExportTestData(db)
 
 	t.Run("export budgets as CSV", func(t *testing.T) {
-		data, filename, err := service.ExportBudgets(userID, domain.ExportFormatCSV)
+		data, filename, err := service.ExportBudgets(context.Background(), userID, domain.ExportFormatCSV)
 		require.NoError(t, err)
 		assert.NotEmpty(t, data)
 		assert.Contains(t, filename, "budgets_")
@@ -210,7 +213,7 @@ func TestExportService_ExportBudgets(t *testing.T) {
 	})
 
 	t.Run("export budgets as JSON", func(t *testing.T) {
-		data, filename, err := service.ExportBudgets(userID, domain.ExportFormatJSON)
+		data, filename, err := service.ExportBudgets(context.Background(), userID, domain.ExportFormatJSON)
 		require.NoError(t, err)
 		assert.NotEmpty(t, data)
 		assert.Contains(t, filename, "budgets_")
@@ -225,7 +228,7 @@ func TestExportService_ExportBudgets(t *testing.T) {
 	})
 
 	t.Run("export budgets with unsupported format", func(t *testing.T) {
-		_, _, err := service.ExportBudgets(userID, domain.ExportFormatPDF)
+		_, _, err := service.ExportBudgets(context.Background(), userID, domain.ExportFormatPDF)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "unsupported export format")
 	})
@@ -237,7 +240,7 @@ func TestExportService_ExportAllData(t *testing.T) {
 	userID := createExportTestData(db)
 
 	t.Run("export all data as JSON", func(t *testing.T) {
-		data, filename, err := service.ExportAllData(userID, domain.ExportFormatJSON)
+		data, filename, err := service.ExportAllData(context.Background(), userID, domain.ExportFormatJSON)
 		require.NoError(t, err)
 		assert.NotEmpty(t, data)
 		assert.Contains(t, filename, "financial_data_export_")
@@ -256,7 +259,7 @@ func TestExportService_ExportAllData(t *testing.T) {
 	})
 
 	t.Run("export all data with unsupported format", func(t *testing.T) {
-		_, _, err := service.ExportAllData(userID, domain.ExportFormatCSV)
+		_, _, err := service.ExportAllData(context.Background(), userID, domain.ExportFormatCSV)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "unsupported export format for all data")
 	})
@@ -272,11 +275,11 @@ func TestExportService_ExportFinancialReport(t *testing.T) {
 
 	t.Run("export monthly report as JSON", func(t *testing.T) {
 		// Generate the report first
-		report, err := reportsService.GenerateMonthlyReport(userID, 2024, 1)
+		report, err := reportsService.GenerateMonthlyReport(context.Background(), userID, 2024, 1)
 		require.NoError(t, err)
 		require.NotNil(t, report)
 
-		data, filename, err := service.ExportFinancialReport(userID, "monthly", 2024, 1, domain.ExportFormatJSON)
+		data, filename, err := service.ExportFinancialReport(context.Background(), userID, "monthly", 2024, 1, domain.ExportFormatJSON)
 		require.NoError(t, err)
 		assert.NotEmpty(t, data)
 		assert.Contains(t, filename, "financial_report_monthly_")
@@ -294,11 +297,11 @@ func TestExportService_ExportFinancialReport(t *testing.T) {
 
 	t.Run("export yearly report as JSON", func(t *testing.T) {
 		// Generate the report first
-		report, err := reportsService.GenerateYearlyReport(userID, 2024)
+		report, err := reportsService.GenerateYearlyReport(context.Background(), userID, 2024)
 		require.NoError(t, err)
 		require.NotNil(t, report)
 
-		data, filename, err := service.ExportFinancialReport(userID, "yearly", 2024, 0, domain.ExportFormatJSON)
+		data, filename, err := service.ExportFinancialReport(context.Background(), userID, "yearly", 2024, 0, domain.ExportFormatJSON)
 		require.NoError(t, err)
 		assert.NotEmpty(t, data)
 		assert.Contains(t, filename, "financial_report_yearly_")
@@ -315,11 +318,11 @@ func TestExportService_ExportFinancialReport(t *testing.T) {
 
 	t.Run("export report as CSV", func(t *testing.T) {
 		// Generate the report first
-		report, err := reportsService.GenerateMonthlyReport(userID, 2024, 1)
+		report, err := reportsService.GenerateMonthlyReport(context.Background(), userID, 2024, 1)
 		require.NoError(t, err)
 		require.NotNil(t, report)
 
-		data, filename, err := service.ExportFinancialReport(userID, "monthly", 2024, 1, domain.ExportFormatCSV)
+		data, filename, err := service.ExportFinancialReport(context.Background(), userID, "monthly", 2024, 1, domain.ExportFormatCSV)
 		require.NoError(t, err)
 		assert.NotEmpty(t, data)
 		assert.Contains(t, filename, "financial_report_monthly_")
@@ -330,18 +333,251 @@ func TestExportService_ExportFinancialReport(t *testing.T) {
 	})
 
 	t.Run("export report with unsupported type", func(t *testing.T) {
-		_, _, err := service.ExportFinancialReport(userID, "invalid", 2024, 1, domain.ExportFormatJSON)
+		_, _, err := service.ExportFinancialReport(context.Background(), userID, "invalid", 2024, 1, domain.ExportFormatJSON)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "unsupported report type")
 	})
 
 	t.Run("export report with unsupported format", func(t *testing.T) {
-		_, _, err := service.ExportFinancialReport(userID, "monthly", 2024, 1, domain.ExportFormatPDF)
+		_, _, err := service.ExportFinancialReport(context.Background(), userID, "monthly", 2024, 1, domain.ExportFormatPDF)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "unsupported export format")
 	})
 }
 
+func TestExportService_LocalizedCSV(t *testing.T) {
+	db := setupExportTestDB()
+	service := NewExportService(db)
+	userID := createExportTestData(db)
+	require.NoError(t, db.Model(&domain.User{}).Where("id = ?", userID).Update("locale", domain.LocaleGerman).Error)
+	ctx := context.Background()
+
+	t.Run("transactions use the user's labels, dates and numbers", func(t *testing.T) {
+		data, _, err := service.ExportTransactions(ctx, userID, domain.ExportFormatCSV, nil, nil)
+		require.NoError(t, err)
+		records, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"ID", "Datum", "Beschreibung", "Betrag", "Art", "Kategorie", "Erstellt am"}, records[0])
+		salary := records[len(records)-1] // Oldest last
+		assert.Equal(t, []string{"01.01.2024", "Monthly salary", "3000,00", "income", "Gehalt"}, salary[1:6])
+	})
+
+	t.Run("reports use the user's labels and numbers", func(t *testing.T) {
+		data, _, err := service.ExportFinancialReport(ctx, userID, "monthly", 2024, 1, domain.ExportFormatCSV)
+		require.NoError(t, err)
+
+		assert.Contains(t, string(data), "Einnahmen gesamt,\"3000,00\"")
+		assert.Contains(t, string(data), "01.01.2024 bis 31.01.2024")
+	})
+}
+
+func TestExportService_ExportAccountStatement(t *testing.T) {
+	db := setupExportTestDB()
+	service := NewExportService(db)
+	userID := createExportTestData(db)
+	ctx := context.Background()
+
+	account := &domain.Account{Name: "Main", Type: domain.AccountTypeChecking, Currency: "USD", OpeningBalance: domain.NewMoney(100)}
+	require.NoError(t, NewAccountService(db).Create(ctx, userID, account))
+	require.NoError(t, db.Model(&domain.Transaction{}).Where("date < ?", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)).
+		Update("account_id", account.ID).Error)
+	january := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("CSV lists the entries with running balances", func(t *testing.T) {
+		data, filename, err := service.ExportAccountStatement(ctx, userID, account.ID, january, domain.ExportFormatCSV)
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("statement_%d_2024-01.csv", account.ID), filename)
+
+		reader := csv.NewReader(strings.NewReader(string(data)))
+		reader.FieldsPerRecord = -1 // The summary has fewer columns than the entries
+		records, err := reader.ReadAll()
+		require.NoError(t, err)
+		assert.Contains(t, records, []string{"Opening Balance", "100.00"})
+		assert.Contains(t, records, []string{"Closing Balance", "3050.00"})
+		assert.Equal(t, []string{"2024-01-01", "Monthly salary", "income", "3000.00", "3100.00"}, records[len(records)-2])
+		assert.Equal(t, []string{"2024-01-15", "Grocery shopping", "expense", "-50.00", "3050.00"}, records[len(records)-1])
+	})
+
+	t.Run("PDF is a text document", func(t *testing.T) {
+		data, filename, err := service.ExportAccountStatement(ctx, userID, account.ID, january, domain.ExportFormatPDF)
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("statement_%d_2024-01.pdf", account.ID), filename)
+		assert.True(t, strings.HasPrefix(string(data), "%PDF-1.4"))
+		assert.True(t, strings.HasSuffix(string(data), "%%EOF\n"))
+		assert.Contains(t, string(data), "Grocery shopping")
+	})
+
+	t.Run("other users' accounts are not found", func(t *testing.T) {
+		_, _, err := service.ExportAccountStatement(ctx, userID+1, account.ID, january, domain.ExportFormatCSV)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
+
+func TestExportService_Templates(t *testing.T) {
+	db := setupExportTestDB()
+	service := NewExportService(db)
+	userID := createExportTestData(db)
+	ctx := context.Background()
+
+	var grocery domain.Transaction
+	require.NoError(t, db.Where("description = ?", "Grocery shopping").First(&grocery).Error)
+	require.NoError(t, db.Create(&domain.TransactionTag{TransactionID: grocery.ID, Tag: "weekly"}).Error)
+
+	_, err := service.CreateTemplate(ctx, userID, domain.ExportTemplate{Name: "Broken", Columns: []string{"iban"}})
+	assert.ErrorIs(t, err, domain.ErrValidation)
+
+	template, err := service.CreateTemplate(ctx, userID, domain.ExportTemplate{
+		Name:            "Ledger",
+		Columns:         []string{domain.ExportColumnDate, domain.ExportColumnDescription, domain.ExportColumnTags, domain.ExportColumnAmount},
+		DateFormat:      "DD/MM/YYYY",
+		Delimiter:       ";",
+		FilenamePattern: "ledger_{start_date}",
+	})
+	require.NoError(t, err)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	t.Run("CSV exports hold the template's columns", func(t *testing.T) {
+		data, filename, err := service.ExportTransactionsWithTemplate(ctx, userID, template.ID, domain.ExportFormatCSV, &start, &end)
+		require.NoError(t, err)
+		assert.Equal(t, "ledger_2024-01-01.csv", filename)
+
+		reader := csv.NewReader(strings.NewReader(string(data)))
+		reader.Comma = ';'
+		records, err := reader.ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 3)
+		assert.Equal(t, []string{"Date", "Description", "Tags", "Amount"}, records[0])
+		assert.Equal(t, []string{"15/01/2024", "Grocery shopping", "weekly", "50.00"}, records[1])
+	})
+
+	t.Run("JSON exports hold the template's columns", func(t *testing.T) {
+		data, _, err := service.ExportTransactionsWithTemplate(ctx, userID, template.ID, domain.ExportFormatJSON, &start, &end)
+		require.NoError(t, err)
+		var rows []map[string]any
+		require.NoError(t, json.Unmarshal(data, &rows))
+		require.Len(t, rows, 2)
+		assert.Equal(t, map[string]any{
+			"date": "15/01/2024", "description": "Grocery shopping", "tags": []any{"weekly"}, "amount": 50.0,
+		}, rows[0])
+	})
+
+	t.Run("QIF exports list signed amounts", func(t *testing.T) {
+		data, filename, err := service.ExportTransactions(ctx, userID, domain.ExportFormatQIF, nil, nil)
+		require.NoError(t, err)
+		assert.Contains(t, filename, ".qif")
+		assert.True(t, strings.HasPrefix(string(data), "!Type:Bank\n"))
+		assert.Contains(t, string(data), "D01/15/2024\nT-50.00\nPGrocery shopping\nLFood\n^\n")
+		assert.Contains(t, string(data), "T3000.00\n")
+
+		data, _, err = service.ExportTransactionsWithTemplate(ctx, userID, template.ID, domain.ExportFormatQIF, nil, nil)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "D15/01/2024\n")
+	})
+
+	t.Run("templates belong to their user", func(t *testing.T) {
+		_, _, err := service.ExportTransactionsWithTemplate(ctx, userID+1, template.ID, domain.ExportFormatCSV, nil, nil)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		_, err = service.UpdateTemplate(ctx, userID+1, *template)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.ErrorIs(t, service.DeleteTemplate(ctx, userID+1, template.ID), domain.ErrNotFound)
+	})
+
+	t.Run("updates and deletes templates", func(t *testing.T) {
+		update := *template
+		update.Name, update.Delimiter = "Ledger (tabs)", "\t"
+		updated, err := service.UpdateTemplate(ctx, userID, update)
+		require.NoError(t, err)
+		assert.Equal(t, template.CreatedAt.Unix(), updated.CreatedAt.Unix())
+
+		templates, err := service.ListTemplates(ctx, userID)
+		require.NoError(t, err)
+		require.Len(t, templates, 1)
+		assert.Equal(t, "\t", templates[0].Delimiter)
+		assert.Equal(t, template.Columns, templates[0].Columns)
+
+		require.NoError(t, service.DeleteTemplate(ctx, userID, template.ID))
+		templates, err = service.ListTemplates(ctx, userID)
+		require.NoError(t, err)
+		assert.Empty(t, templates)
+	})
+}
+
+func TestExportService_CurrencyColumns(t *testing.T) {
+	db := setupExportTestDB()
+	service := NewExportService(db)
+	userID := createExportTestData(db)
+	ctx := context.Background()
+
+	original, rate := domain.NewMoney(46), 1.087
+	require.NoError(t, db.Model(&domain.Transaction{}).Where("description = ?", "Grocery shopping").
+		Updates(map[string]any{"currency": "EUR", "original_amount": original, "exchange_rate": rate}).Error)
+	template, err := service.CreateTemplate(ctx, userID, domain.ExportTemplate{
+		Name: "FX",
+		Columns: []string{
+			domain.ExportColumnDescription, domain.ExportColumnAmount,
+			domain.ExportColumnCurrency, domain.ExportColumnOriginalAmount, domain.ExportColumnExchangeRate,
+		},
+	})
+	require.NoError(t, err)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	data, _, err := service.ExportTransactionsWithTemplate(ctx, userID, template.ID, domain.ExportFormatCSV, &start, &end)
+	require.NoError(t, err)
+	records, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, []string{"Description", "Amount", "Currency", "Original Amount", "Exchange Rate"}, records[0])
+	assert.Equal(t, []string{"Grocery shopping", "50.00", "EUR", "46.00", "1.087000"}, records[1])
+	assert.Equal(t, "USD", records[2][2], "base currency transactions are in USD at a rate of 1")
+	assert.Equal(t, "1.000000", records[2][4])
+}
+
+func TestExportService_Items(t *testing.T) {
+	db := setupExportTestDB()
+	service := NewExportService(db)
+	userID := createExportTestData(db)
+	ctx := context.Background()
+
+	var grocery domain.Transaction
+	require.NoError(t, db.Where("description = ?", "Grocery shopping").First(&grocery).Error)
+	require.NoError(t, db.Create(&[]domain.TransactionItem{
+		{TransactionID: grocery.ID, Name: "Coffee", Quantity: 2, UnitPrice: domain.NewMoney(3.5), Amount: domain.NewMoney(7)},
+		{TransactionID: grocery.ID, Position: 1, Name: "Bread", Quantity: 1, UnitPrice: domain.NewMoney(2.2), Amount: domain.NewMoney(2.2)},
+	}).Error)
+
+	t.Run("CSV templates can list the items", func(t *testing.T) {
+		template, err := service.CreateTemplate(ctx, userID, domain.ExportTemplate{
+			Name: "Receipts", Columns: []string{domain.ExportColumnDescription, domain.ExportColumnItems},
+		})
+		require.NoError(t, err)
+		data, _, err := service.ExportTransactionsWithTemplate(ctx, userID, template.ID, domain.ExportFormatCSV, nil, nil)
+		require.NoError(t, err)
+		records, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Description", "Items"}, records[0])
+		assert.Contains(t, records, []string{"Grocery shopping", "2 x Coffee @ 3.50; 1 x Bread @ 2.20"})
+	})
+
+	t.Run("JSON exports hold the items", func(t *testing.T) {
+		data, _, err := service.ExportTransactions(ctx, userID, domain.ExportFormatJSON, nil, nil)
+		require.NoError(t, err)
+		var transactions []domain.Transaction
+		require.NoError(t, json.Unmarshal(data, &transactions))
+		for i := range transactions {
+			if transactions[i].ID == grocery.ID {
+				require.Len(t, transactions[i].Items, 2)
+				assert.Equal(t, "Coffee", transactions[i].Items[0].Name)
+				assert.Equal(t, domain.NewMoney(7), transactions[i].Items[0].Amount)
+			} else {
+				assert.Empty(t, transactions[i].Items)
+			}
+		}
+	})
+}
+
 func TestExportService_Integration(t *testing.T) {
 	db := setupExportTestDB()
 	service := NewExportService(db)
@@ -349,7 +585,7 @@ func TestExportService_Integration(t *testing.T) {
 
 	t.Run("export data maintains referential integrity", func(t *testing.T) {
 		// Export transactions
-		txData, _, err := service.ExportTransactions(userID, domain.ExportFormatJSON, nil, nil)
+		txData, _, err := service.ExportTransactions(context.Background(), userID, domain.ExportFormatJSON, nil, nil)
 		require.NoError(t, err)
 
 		var transactions []domain.Transaction
@@ -357,7 +593,7 @@ func TestExportService_Integration(t *testing.T) {
 		require.NoError(t, err)
 
 		// Export budgets
-		budgetData, _, err := service.ExportBudgets(userID, domain.ExportFormatJSON)
+		budgetData, _, err := service.ExportBudgets(context.Background(), userID, domain.ExportFormatJSON)
 		require.NoError(t, err)
 
 		var budgets []domain.Budget
@@ -386,7 +622,7 @@ func TestExportService_Integration(t *testing.T) {
 		db.Create(&emptyUser)
 
 		// Export transactions for empty user
-		data, filename, err := service.ExportTransactions(emptyUser.ID, domain.ExportFormatJSON, nil, nil)
+		data, filename, err := service.ExportTransactions(context.Background(), emptyUser.ID, domain.ExportFormatJSON, nil, nil)
 		require.NoError(t, err)
 		assert.NotEmpty(t, filename)
 
@@ -396,7 +632,7 @@ func TestExportService_Integration(t *testing.T) {
 		assert.Empty(t, transactions)
 
 		// Export budgets for empty user
-		data, filename, err = service.ExportBudgets(emptyUser.ID, domain.ExportFormatJSON)
+		data, filename, err = service.ExportBudgets(context.Background(), emptyUser.ID, domain.ExportFormatJSON)
 		require.NoError(t, err)
 		assert.NotEmpty(t, filename)
 
@@ -407,10 +643,10 @@ func TestExportService_Integration(t *testing.T) {
 	})
 
 	t.Run("export filename generation is consistent", func(t *testing.T) {
-		_, filename1, err := service.ExportTransactions(userID, domain.ExportFormatCSV, nil, nil)
+		_, filename1, err := service.ExportTransactions(context.Background(), userID, domain.ExportFormatCSV, nil, nil)
 		require.NoError(t, err)
 
-		_, filename2, err := service.ExportTransactions(userID, domain.ExportFormatJSON, nil, nil)
+		_, filename2, err := service.ExportTransactions(context.Background(), userID, domain.ExportFormatJSON, nil, nil)
 		require.NoError(t, err)
 
 		// Both should contain date and appropriate extension
@@ -420,3 +656,24 @@ func TestExportService_Integration(t *testing.T) {
 		assert.Contains(t, filename2, ".json")
 	})
 }
+
+func TestExportService_LinkExport(t *testing.T) {
+	service := NewExportService(setupExportTestDB())
+	ctx := context.Background()
+
+	_, err := service.LinkExport(ctx, 1, "budgets.csv", "text/csv", []byte("a,b"))
+	assert.ErrorIs(t, err, domain.ErrStorageUnavailable)
+
+	storage := testStorage(t.TempDir())
+	service.Storage = storage
+	link, err := service.LinkExport(ctx, 1, "../budgets.csv", "text/csv", []byte("a,b"))
+	require.NoError(t, err)
+	assert.Equal(t, "budgets.csv", link.FileName)
+	assert.Contains(t, link.URL, "/files/exports/1/")
+
+	key := strings.TrimPrefix(strings.SplitN(link.URL, "?", 2)[0], "/files/")
+	assert.Regexp(t, `^exports/1/\d+_budgets\.csv$`, key)
+	data, err := storage.Get(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, "a,b", string(data))
+}
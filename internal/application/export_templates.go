@@ -0,0 +1,70 @@
+package application
+
+import (
+	"context"
+	"errors"
+
+	"go-finance-advisor/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// ListTemplates returns the user's export templates by name
+func (s *ExportService) ListTemplates(ctx context.Context, userID uint) ([]domain.ExportTemplate, error) {
+	templates := []domain.ExportTemplate{}
+	err := s.DB.WithContext(ctx).Where("user_id = ?", userID).Order("name, id").Find(&templates).Error
+	return templates, err
+}
+
+// CreateTemplate saves a new export template for the user
+func (s *ExportService) CreateTemplate(ctx context.Context, userID uint, template domain.ExportTemplate) (*domain.ExportTemplate, error) {
+	if err := template.Validate(); err != nil {
+		return nil, err
+	}
+	template.ID, template.UserID = 0, userID
+	if err := s.DB.WithContext(ctx).Create(&template).Error; err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// UpdateTemplate replaces the settings of one of the user's export templates
+func (s *ExportService) UpdateTemplate(ctx context.Context, userID uint, template domain.ExportTemplate) (*domain.ExportTemplate, error) {
+	if err := template.Validate(); err != nil {
+		return nil, err
+	}
+	existing, err := s.template(ctx, userID, template.ID)
+	if err != nil {
+		return nil, err
+	}
+	template.UserID, template.CreatedAt = userID, existing.CreatedAt
+	if err := s.DB.WithContext(ctx).Save(&template).Error; err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// DeleteTemplate removes one of the user's export templates
+func (s *ExportService) DeleteTemplate(ctx context.Context, userID, id uint) error {
+	result := s.DB.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&domain.ExportTemplate{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// template returns one of the user's export templates, or domain.ErrNotFound
+func (s *ExportService) template(ctx context.Context, userID, id uint) (*domain.ExportTemplate, error) {
+	var template domain.ExportTemplate
+	err := s.DB.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&template).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
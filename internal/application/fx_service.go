@@ -0,0 +1,111 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/pkg"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FXService keeps the historical exchange rates foreign currency
+// transactions are converted at. Rates are read from the exchange_rates
+// table and fetched from the provider the first time a pair is needed on a
+// date, so a past period's totals never move with today's rates.
+type FXService struct {
+	DB       *gorm.DB
+	Provider pkg.FXProvider // Fetches missing rates when set
+	// FallbackWindow is how much older than the requested date a stored
+	// rate may be to stand in while the provider is unavailable
+	FallbackWindow time.Duration
+}
+
+func NewFXService(db *gorm.DB, provider pkg.FXProvider, fallbackWindow time.Duration) *FXService {
+	return &FXService{DB: db, Provider: provider, FallbackWindow: fallbackWindow}
+}
+
+// Rate returns what one unit of from was worth in to on the date. Rates of
+// future dates are not known yet, so today's stands in for them. When the
+// provider cannot supply a missing rate, the latest stored one within
+// FallbackWindow before the date is returned, its Date telling how old it is.
+func (s *FXService) Rate(ctx context.Context, from, to string, date time.Time) (*domain.ExchangeRate, error) {
+	from, to = strings.ToUpper(strings.TrimSpace(from)), strings.ToUpper(strings.TrimSpace(to))
+	day := domain.RateDate(date)
+	if today := domain.RateDate(time.Now()); day.After(today) {
+		day = today
+	}
+	if from == to {
+		return &domain.ExchangeRate{Base: from, Quote: to, Date: day, Rate: 1}, nil
+	}
+
+	var stored domain.ExchangeRate
+	err := s.DB.WithContext(ctx).Where("base = ? AND quote = ? AND date = ?", from, to, day).First(&stored).Error
+	if err == nil {
+		return &stored, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	fetched, fetchErr := s.fetch(ctx, from, to, day)
+	if fetchErr == nil {
+		return fetched, nil
+	}
+	err = s.DB.WithContext(ctx).
+		Where("base = ? AND quote = ? AND date < ? AND date >= ?", from, to, day, day.Add(-s.FallbackWindow)).
+		Order("date DESC").First(&stored).Error
+	if err == nil {
+		log.Printf("fx: using the %s/%s rate of %s for %s: %v", from, to,
+			stored.Date.Format("2006-01-02"), day.Format("2006-01-02"), fetchErr)
+		return &stored, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	return nil, fmt.Errorf("%w: %s/%s on %s: %v", domain.ErrExchangeRateUnavailable, from, to, day.Format("2006-01-02"), fetchErr)
+}
+
+// fetch asks the provider for a rate and keeps it. A rate stored meanwhile
+// by a concurrent request is left as it is.
+func (s *FXService) fetch(ctx context.Context, from, to string, day time.Time) (*domain.ExchangeRate, error) {
+	if s.Provider == nil {
+		return nil, errors.New("no exchange rate provider configured")
+	}
+	value, err := s.Provider.Rate(ctx, from, to, day)
+	if err != nil {
+		return nil, err
+	}
+	rate := domain.ExchangeRate{Base: from, Quote: to, Date: day, Rate: value, Source: s.Provider.Name()}
+	err = s.DB.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&rate).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+// convert sets the base currency amount of a transaction made in another
+// currency at the rate of its date. Transactions in the base currency have
+// their original amount and rate cleared. Without a service, only base
+// currency transactions can be saved.
+func (s *FXService) convert(ctx context.Context, transaction *domain.Transaction) error {
+	if transaction.InBaseCurrency() {
+		transaction.Currency, transaction.OriginalAmount, transaction.ExchangeRate = "", nil, nil
+		return nil
+	}
+	if s == nil {
+		return fmt.Errorf("%w: exchange rates are not configured", domain.ErrExchangeRateUnavailable)
+	}
+	rate, err := s.Rate(ctx, transaction.Currency, domain.BaseCurrency, transaction.Date)
+	if err != nil {
+		return err
+	}
+	transaction.ConvertAt(rate.Rate)
+	return nil
+}
@@ -0,0 +1,125 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// stubFXProvider reports the rates set for each day
+type stubFXProvider struct {
+	rates map[string]float64 // By YYYY-MM-DD
+	err   error
+	calls int
+}
+
+func (p *stubFXProvider) Rate(_ context.Context, _, _ string, date time.Time) (float64, error) {
+	p.calls++
+	if p.err != nil {
+		return 0, p.err
+	}
+	rate, ok := p.rates[date.Format("2006-01-02")]
+	if !ok {
+		return 0, errors.New("no rate")
+	}
+	return rate, nil
+}
+
+func (p *stubFXProvider) Name() string { return "stub" }
+
+func setupFXTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&domain.ExchangeRate{}, &domain.Category{}, &domain.Transaction{}))
+	return db
+}
+
+func TestFXService_Rate(t *testing.T) {
+	db := setupFXTestDB(t)
+	provider := &stubFXProvider{rates: map[string]float64{"2024-03-01": 1.08, "2024-03-04": 1.09}}
+	service := NewFXService(db, provider, 7*24*time.Hour)
+	ctx := context.Background()
+	march := time.Date(2024, 3, 1, 15, 30, 0, 0, time.UTC)
+
+	rate, err := service.Rate(ctx, "eur", "USD", march)
+	require.NoError(t, err)
+	assert.Equal(t, 1.08, rate.Rate)
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), rate.Date)
+	assert.Equal(t, "stub", rate.Source)
+
+	_, err = service.Rate(ctx, "EUR", "USD", march.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, provider.calls, "stored rates are not fetched again")
+
+	same, err := service.Rate(ctx, "USD", "USD", march)
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, same.Rate)
+
+	provider.err = errors.New("frankfurter returned status 503")
+	fallback, err := service.Rate(ctx, "EUR", "USD", march.AddDate(0, 0, 3))
+	require.NoError(t, err, "the latest stored rate stands in while the provider is down")
+	assert.Equal(t, 1.08, fallback.Rate)
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), fallback.Date)
+
+	_, err = service.Rate(ctx, "EUR", "USD", march.AddDate(0, 0, 10))
+	assert.ErrorIs(t, err, domain.ErrExchangeRateUnavailable, "stored rates older than the window do not")
+	_, err = service.Rate(ctx, "EUR", "USD", march.AddDate(0, 0, -1))
+	assert.ErrorIs(t, err, domain.ErrExchangeRateUnavailable, "later rates never stand in for earlier dates")
+}
+
+func TestTransactionService_ConvertsAtHistoricalRates(t *testing.T) {
+	db := setupFXTestDB(t)
+	category := domain.Category{Name: "Travel", Type: "expense"}
+	require.NoError(t, db.Create(&category).Error)
+	provider := &stubFXProvider{rates: map[string]float64{"2024-03-01": 1.08, "2024-06-03": 1.1}}
+	txService := &TransactionService{DB: db, FX: NewFXService(db, provider, 0)}
+	ctx := context.Background()
+
+	transaction := &domain.Transaction{
+		UserID: 1, CategoryID: category.ID, Type: "expense", Description: "Hotel in Berlin",
+		Amount: domain.NewMoney(100), Currency: "eur", Date: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+	}
+	require.NoError(t, txService.Create(ctx, transaction))
+	assert.Equal(t, "EUR", transaction.Currency)
+	assert.Equal(t, domain.NewMoney(108), transaction.Amount, "amounts are kept in the base currency")
+	require.NotNil(t, transaction.OriginalAmount)
+	assert.Equal(t, domain.NewMoney(100), *transaction.OriginalAmount)
+
+	// Moving the transaction converts the original amount at the new date's rate
+	transaction.Date = time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, txService.Update(ctx, transaction))
+	assert.Equal(t, domain.NewMoney(110), transaction.Amount)
+	require.NoError(t, txService.Update(ctx, transaction))
+	assert.Equal(t, domain.NewMoney(110), transaction.Amount, "saving again does not convert twice")
+
+	var stored domain.Transaction
+	require.NoError(t, db.First(&stored, transaction.ID).Error)
+	assert.Equal(t, 1.1, *stored.ExchangeRate)
+
+	transaction.Currency = "USD"
+	require.NoError(t, txService.Update(ctx, transaction))
+	assert.Empty(t, transaction.Currency)
+	assert.Nil(t, transaction.OriginalAmount, "base currency transactions keep no original amount")
+
+	unavailable := &domain.Transaction{
+		UserID: 1, CategoryID: category.ID, Type: "expense", Description: "Dinner",
+		Amount: domain.NewMoney(20), Currency: "GBP", Date: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC),
+	}
+	assert.ErrorIs(t, txService.Create(ctx, unavailable), domain.ErrExchangeRateUnavailable)
+	unavailable.Currency = "EURO"
+	assert.ErrorIs(t, txService.Create(ctx, unavailable), domain.ErrValidation)
+	unavailable.Currency = "EUR"
+	assert.ErrorIs(t, (&TransactionService{DB: db}).Create(ctx, unavailable), domain.ErrExchangeRateUnavailable,
+		"services without exchange rates only take base currency transactions")
+}
@@ -0,0 +1,55 @@
+package application
+
+import (
+	"context"
+	"errors"
+
+	"go-finance-advisor/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// GoalService tracks the progress of users' financial goals
+type GoalService struct {
+	DB     *gorm.DB
+	Events *EventBus // Publishes GoalReached when set
+}
+
+func NewGoalService(db *gorm.DB) *GoalService {
+	return &GoalService{DB: db}
+}
+
+// Contribute adds an amount to what has been saved towards one of the user's
+// goals; a negative amount is a withdrawal, stopping at zero. Reaching the
+// target completes the goal and announces it as reached.
+func (s *GoalService) Contribute(ctx context.Context, userID, goalID uint, amount domain.Money) (*domain.FinancialGoal, error) {
+	if amount == 0 {
+		return nil, &domain.ValidationError{Fields: []domain.FieldError{{Field: "amount", Message: "must not be zero"}}}
+	}
+
+	var goal domain.FinancialGoal
+	reached := false
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ? AND user_id = ?", goalID, userID).First(&goal).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrNotFound
+			}
+			return err
+		}
+
+		goal.CurrentAmount = max(goal.CurrentAmount+amount, 0)
+		if goal.Status != domain.GoalStatusCompleted && goal.TargetAmount > 0 && goal.CurrentAmount >= goal.TargetAmount {
+			goal.Status, reached = domain.GoalStatusCompleted, true
+		}
+		return tx.Model(&goal).Updates(map[string]any{"current_amount": goal.CurrentAmount, "status": goal.Status}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	goal.CalculateProgress()
+	if reached {
+		s.Events.Publish(ctx, domain.GoalReached{Goal: goal})
+	}
+	return &goal, nil
+}
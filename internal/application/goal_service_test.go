@@ -0,0 +1,69 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestGoalService_Contribute(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&domain.FinancialGoal{}))
+	ctx := context.Background()
+
+	bus := NewEventBus()
+	var reached []domain.FinancialGoal
+	bus.Subscribe(func(_ context.Context, event domain.Event) {
+		reached = append(reached, event.(domain.GoalReached).Goal)
+	}, domain.EventGoalReached)
+	service := &GoalService{DB: db, Events: bus}
+
+	goal := domain.FinancialGoal{
+		UserID: 1, Title: "Emergency fund", GoalType: "savings", Status: domain.GoalStatusActive,
+		TargetAmount: domain.NewMoney(1000), CurrentAmount: domain.NewMoney(700),
+	}
+	require.NoError(t, db.Create(&goal).Error)
+
+	t.Run("should add contributions", func(t *testing.T) {
+		updated, err := service.Contribute(ctx, 1, goal.ID, domain.NewMoney(200))
+		require.NoError(t, err)
+		assert.Equal(t, domain.NewMoney(900), updated.CurrentAmount)
+		assert.Equal(t, 90.0, updated.Progress)
+		assert.Empty(t, reached)
+	})
+
+	t.Run("should complete the goal once its target is reached", func(t *testing.T) {
+		updated, err := service.Contribute(ctx, 1, goal.ID, domain.NewMoney(150))
+		require.NoError(t, err)
+		assert.Equal(t, domain.GoalStatusCompleted, updated.Status)
+		require.Len(t, reached, 1)
+		assert.Equal(t, goal.ID, reached[0].ID)
+
+		_, err = service.Contribute(ctx, 1, goal.ID, domain.NewMoney(50))
+		require.NoError(t, err)
+		assert.Len(t, reached, 1, "a completed goal is only reached once")
+	})
+
+	t.Run("should stop withdrawals at zero", func(t *testing.T) {
+		updated, err := service.Contribute(ctx, 1, goal.ID, domain.NewMoney(-5000))
+		require.NoError(t, err)
+		assert.Zero(t, updated.CurrentAmount)
+	})
+
+	t.Run("should reject zero amounts and other users' goals", func(t *testing.T) {
+		_, err := service.Contribute(ctx, 1, goal.ID, 0)
+		assert.ErrorIs(t, err, domain.ErrValidation)
+		_, err = service.Contribute(ctx, 2, goal.ID, domain.NewMoney(10))
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
@@ -0,0 +1,168 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// healthBackfillWeeks is how many weeks back a user's first snapshots go, so
+// the history has a trend to show from the start
+const healthBackfillWeeks = 12
+
+// HealthHistoryService keeps daily snapshots of users' financial health
+// score and savings rate
+type HealthHistoryService struct {
+	DB        *gorm.DB
+	Analytics *AnalyticsService // Falls back to an uncached AnalyticsService on DB when nil
+}
+
+func NewHealthHistoryService(db *gorm.DB) *HealthHistoryService {
+	return &HealthHistoryService{DB: db}
+}
+
+func (s *HealthHistoryService) analytics() *AnalyticsService {
+	if s.Analytics != nil {
+		return s.Analytics
+	}
+	return NewAnalyticsService(s.DB)
+}
+
+// snapshotDay is the start of the day a snapshot is filed under
+func snapshotDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Snapshot scores the user's finances as of the day and stores the result,
+// replacing an earlier snapshot of the same day
+func (s *HealthHistoryService) Snapshot(ctx context.Context, userID uint, day time.Time) (*domain.HealthSnapshot, error) {
+	day = snapshotDay(day)
+	end := day.Add(24*time.Hour - time.Nanosecond)
+	metrics, err := s.analytics().financialMetrics(ctx, userID, "snapshot", end.Add(-domain.HealthSnapshotWindow), end)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := domain.NewHealthSnapshot(userID, day, metrics)
+	err = s.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"overall_score", "savings_score", "spending_score", "budget_score", "health_status",
+			"savings_rate", "total_income", "total_expenses",
+		}),
+	}).Create(&snapshot).Error
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// SnapshotAll snapshots every user with transactions in the window before
+// the day. Users without any snapshot yet also get weekly snapshots for the
+// weeks before, so their history starts with a trend. It returns how many
+// users were snapshotted.
+func (s *HealthHistoryService) SnapshotAll(ctx context.Context, day time.Time) (int, error) {
+	day = snapshotDay(day)
+	var userIDs []uint
+	err := s.DB.WithContext(ctx).Model(&domain.Transaction{}).
+		Where("date BETWEEN ? AND ?", day.Add(-domain.HealthSnapshotWindow), day.Add(24*time.Hour)).
+		Distinct().Pluck("user_id", &userIDs).Error
+	if err != nil {
+		return 0, err
+	}
+
+	for _, userID := range userIDs {
+		var first domain.HealthSnapshot
+		err := s.DB.WithContext(ctx).Where("user_id = ?", userID).First(&first).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			for week := healthBackfillWeeks; week > 0; week-- {
+				if _, err := s.Snapshot(ctx, userID, day.AddDate(0, 0, -7*week)); err != nil {
+					return 0, fmt.Errorf("failed to backfill health history of user %d: %w", userID, err)
+				}
+			}
+		} else if err != nil {
+			return 0, err
+		}
+
+		if _, err := s.Snapshot(ctx, userID, day); err != nil {
+			return 0, fmt.Errorf("failed to snapshot health of user %d: %w", userID, err)
+		}
+	}
+	return len(userIDs), nil
+}
+
+// StartSnapshots runs SnapshotAll for the current day on the given interval
+// until ctx is cancelled
+func (s *HealthHistoryService) StartSnapshots(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.SnapshotAll(ctx, time.Now()); err != nil {
+			log.Printf("health snapshots failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// History returns the user's snapshots in the filter's range, oldest first
+func (s *HealthHistoryService) History(ctx context.Context, userID uint, filter domain.HealthHistoryFilter) (*domain.HealthHistory, error) {
+	interval := filter.Interval
+	if interval == "" {
+		interval = domain.HealthIntervalDay
+	}
+	if interval != domain.HealthIntervalDay && interval != domain.HealthIntervalWeek {
+		return nil, &domain.ValidationError{Fields: []domain.FieldError{{
+			Field: "interval", Message: "must be one of day, week",
+		}}}
+	}
+
+	query := s.DB.WithContext(ctx).Where("user_id = ?", userID)
+	if filter.From != nil {
+		query = query.Where("date >= ?", snapshotDay(*filter.From))
+	}
+	if filter.To != nil {
+		query = query.Where("date <= ?", snapshotDay(*filter.To))
+	}
+	var snapshots []domain.HealthSnapshot
+	if err := query.Order("date").Find(&snapshots).Error; err != nil {
+		return nil, err
+	}
+
+	if interval == domain.HealthIntervalWeek {
+		snapshots = latestPerWeek(snapshots)
+	}
+	history := &domain.HealthHistory{Interval: interval, Points: snapshots}
+	if len(snapshots) > 1 {
+		history.Change = snapshots[len(snapshots)-1].OverallScore - snapshots[0].OverallScore
+	}
+	return history, nil
+}
+
+// latestPerWeek keeps the last of the date ordered snapshots in each ISO week
+func latestPerWeek(snapshots []domain.HealthSnapshot) []domain.HealthSnapshot {
+	weekly := make([]domain.HealthSnapshot, 0, len(snapshots)/7+1)
+	for i, snapshot := range snapshots {
+		year, week := snapshot.Date.ISOWeek()
+		if i+1 < len(snapshots) {
+			nextYear, nextWeek := snapshots[i+1].Date.ISOWeek()
+			if nextYear == year && nextWeek == week {
+				continue
+			}
+		}
+		weekly = append(weekly, snapshot)
+	}
+	return weekly
+}
@@ -0,0 +1,93 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupHealthHistoryTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&domain.Category{}, &domain.Transaction{}, &domain.Budget{}, &domain.HealthSnapshot{},
+	))
+	return db
+}
+
+func TestHealthHistoryService(t *testing.T) {
+	db := setupHealthHistoryTestDB(t)
+	ctx := context.Background()
+	service := NewHealthHistoryService(db)
+
+	salary := domain.Category{Name: "Salary", Type: domain.TransactionTypeIncome}
+	rent := domain.Category{Name: "Housing", Type: domain.TransactionTypeExpense}
+	require.NoError(t, db.Create(&salary).Error)
+	require.NoError(t, db.Create(&rent).Error)
+
+	today := time.Date(2025, time.June, 30, 15, 0, 0, 0, time.UTC)
+	add := func(category domain.Category, amount float64, date time.Time) {
+		require.NoError(t, db.Create(&domain.Transaction{
+			UserID: 1, CategoryID: category.ID, Type: category.Type, Amount: domain.NewMoney(amount), Date: date,
+		}).Error)
+	}
+	// Spending most of the income in spring, saving half of it in June
+	add(salary, 4000, today.AddDate(0, -2, 0))
+	add(rent, 3800, today.AddDate(0, -2, 1))
+	add(salary, 4000, today.AddDate(0, 0, -5))
+	add(rent, 2000, today.AddDate(0, 0, -4))
+
+	t.Run("should snapshot active users and backfill their first history", func(t *testing.T) {
+		users, err := service.SnapshotAll(ctx, today)
+		require.NoError(t, err)
+		assert.Equal(t, 1, users)
+
+		var count int64
+		require.NoError(t, db.Model(&domain.HealthSnapshot{}).Where("user_id = ?", 1).Count(&count).Error)
+		assert.Equal(t, int64(healthBackfillWeeks+1), count)
+
+		// Snapshotting the same day again replaces its snapshot
+		_, err = service.SnapshotAll(ctx, today.Add(time.Hour))
+		require.NoError(t, err)
+		require.NoError(t, db.Model(&domain.HealthSnapshot{}).Where("user_id = ?", 1).Count(&count).Error)
+		assert.Equal(t, int64(healthBackfillWeeks+1), count)
+	})
+
+	t.Run("should return the history oldest first", func(t *testing.T) {
+		history, err := service.History(ctx, 1, domain.HealthHistoryFilter{})
+		require.NoError(t, err)
+		require.Len(t, history.Points, healthBackfillWeeks+1)
+		assert.Equal(t, domain.HealthIntervalDay, history.Interval)
+
+		last := history.Points[len(history.Points)-1]
+		assert.True(t, last.Date.Equal(snapshotDay(today)))
+		assert.InDelta(t, 0.5, last.SavingsRate, 0.001)
+		assert.Equal(t, domain.NewMoney(4000), last.TotalIncome)
+		assert.Positive(t, history.Change, "saving more improved the score")
+	})
+
+	t.Run("should narrow the range and keep one point per week", func(t *testing.T) {
+		from := today.AddDate(0, 0, -14)
+		_, err := service.Snapshot(ctx, 1, today.AddDate(0, 0, -1))
+		require.NoError(t, err)
+
+		history, err := service.History(ctx, 1, domain.HealthHistoryFilter{From: &from, Interval: domain.HealthIntervalWeek})
+		require.NoError(t, err)
+		require.Len(t, history.Points, 3)
+		// June 23 and 29 share a week, of which the later snapshot is kept
+		assert.True(t, history.Points[1].Date.Equal(snapshotDay(today.AddDate(0, 0, -1))))
+
+		_, err = service.History(ctx, 1, domain.HealthHistoryFilter{Interval: "hour"})
+		assert.ErrorIs(t, err, domain.ErrValidation)
+	})
+}
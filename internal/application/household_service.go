@@ -0,0 +1,414 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/persistence"
+
+	"gorm.io/gorm"
+)
+
+// invitationTTL is how long a household invitation can be answered
+const invitationTTL = 7 * 24 * time.Hour
+
+// HouseholdService manages households, their members and invitations, and
+// the transactions and budgets members share. Users who are not members of
+// a household get domain.ErrNotFound for it, members whose role does not
+// allow an action get domain.ErrHouseholdForbidden.
+type HouseholdService struct {
+	DB *gorm.DB
+	// Transactions and Budgets record shared transactions and budgets; they
+	// fall back to services over DB when nil
+	Transactions *TransactionService
+	Budgets      *BudgetService
+}
+
+func NewHouseholdService(db *gorm.DB) *HouseholdService {
+	return &HouseholdService{DB: db}
+}
+
+func (s *HouseholdService) transactions() *TransactionService {
+	if s.Transactions != nil {
+		return s.Transactions
+	}
+	return &TransactionService{DB: s.DB}
+}
+
+func (s *HouseholdService) budgets() *BudgetService {
+	if s.Budgets != nil {
+		return s.Budgets
+	}
+	return &BudgetService{DB: s.DB}
+}
+
+// CreateHousehold creates a household owned by the user
+func (s *HouseholdService) CreateHousehold(ctx context.Context, userID uint, name string) (*domain.Household, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("household name is required")
+	}
+
+	household := &domain.Household{Name: name, OwnerID: userID}
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(household).Error; err != nil {
+			return err
+		}
+		owner := domain.HouseholdMember{HouseholdID: household.ID, UserID: userID, Role: domain.HouseholdRoleOwner}
+		if err := tx.Create(&owner).Error; err != nil {
+			return err
+		}
+		household.Members = []domain.HouseholdMember{owner}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return household, nil
+}
+
+// ListHouseholds returns the households the user belongs to with their members
+func (s *HouseholdService) ListHouseholds(ctx context.Context, userID uint) ([]domain.Household, error) {
+	var households []domain.Household
+	err := s.DB.WithContext(ctx).Preload("Members").
+		Where("id IN (?)", s.DB.Model(&domain.HouseholdMember{}).Select("household_id").Where("user_id = ?", userID)).
+		Order("name").Find(&households).Error
+	return households, err
+}
+
+// GetHousehold returns a household the user belongs to with its members
+func (s *HouseholdService) GetHousehold(ctx context.Context, userID, householdID uint) (*domain.Household, error) {
+	if _, err := s.member(ctx, userID, householdID); err != nil {
+		return nil, err
+	}
+
+	var household domain.Household
+	if err := s.DB.WithContext(ctx).Preload("Members").First(&household, householdID).Error; err != nil {
+		return nil, err
+	}
+	return &household, nil
+}
+
+// Invite asks the user with the email to join the household with the role.
+// Only the owner can invite.
+func (s *HouseholdService) Invite(
+	ctx context.Context, userID, householdID uint, email, role string,
+) (*domain.HouseholdInvitation, error) {
+	if _, err := s.authorize(ctx, userID, householdID, domain.HouseholdMember.CanManage); err != nil {
+		return nil, err
+	}
+	if !domain.IsValidInvitationRole(role) {
+		return nil, domain.ErrInvalidHouseholdRole
+	}
+	email = strings.TrimSpace(email)
+
+	var members int64
+	err := s.DB.WithContext(ctx).Model(&domain.HouseholdMember{}).
+		Joins("JOIN users ON users.id = household_members.user_id").
+		Where("household_members.household_id = ? AND LOWER(users.email) = LOWER(?)", householdID, email).
+		Count(&members).Error
+	if err != nil {
+		return nil, err
+	}
+	if members > 0 {
+		return nil, domain.ErrAlreadyHouseholdMember
+	}
+
+	token, err := invitationToken()
+	if err != nil {
+		return nil, err
+	}
+	invitation := &domain.HouseholdInvitation{
+		HouseholdID: householdID,
+		Email:       email,
+		Role:        role,
+		Token:       token,
+		InvitedByID: userID,
+		Status:      domain.InvitationStatusPending,
+		ExpiresAt:   time.Now().Add(invitationTTL),
+	}
+	if err := s.DB.WithContext(ctx).Create(invitation).Error; err != nil {
+		return nil, err
+	}
+	return invitation, nil
+}
+
+// ListInvitations returns the open invitations addressed to the user's email
+func (s *HouseholdService) ListInvitations(ctx context.Context, userID uint) ([]domain.HouseholdInvitation, error) {
+	var user domain.User
+	if err := s.DB.WithContext(ctx).First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+
+	var invitations []domain.HouseholdInvitation
+	err := s.DB.WithContext(ctx).Preload("Household").
+		Where("LOWER(email) = LOWER(?) AND status = ? AND expires_at > ?", user.Email, domain.InvitationStatusPending, time.Now()).
+		Order("created_at DESC").Find(&invitations).Error
+	return invitations, err
+}
+
+// AcceptInvitation makes the user a member of the invitation's household and
+// returns the household
+func (s *HouseholdService) AcceptInvitation(ctx context.Context, userID uint, token string) (*domain.Household, error) {
+	var householdID uint
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		invitation, err := s.openInvitation(tx, userID, token)
+		if err != nil {
+			return err
+		}
+		householdID = invitation.HouseholdID
+
+		member := domain.HouseholdMember{HouseholdID: invitation.HouseholdID, UserID: userID, Role: invitation.Role}
+		if err := tx.Where("household_id = ? AND user_id = ?", invitation.HouseholdID, userID).
+			FirstOrCreate(&member).Error; err != nil {
+			return err
+		}
+		return tx.Model(invitation).Update("status", domain.InvitationStatusAccepted).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetHousehold(ctx, userID, householdID)
+}
+
+// DeclineInvitation turns down an invitation addressed to the user
+func (s *HouseholdService) DeclineInvitation(ctx context.Context, userID uint, token string) error {
+	invitation, err := s.openInvitation(s.DB.WithContext(ctx), userID, token)
+	if err != nil {
+		return err
+	}
+	return s.DB.WithContext(ctx).Model(invitation).Update("status", domain.InvitationStatusDeclined).Error
+}
+
+// UpdateMemberRole changes another member's role. Only the owner can change
+// roles, and the owner's own role cannot be changed.
+func (s *HouseholdService) UpdateMemberRole(
+	ctx context.Context, userID, householdID, memberUserID uint, role string,
+) (*domain.HouseholdMember, error) {
+	if _, err := s.authorize(ctx, userID, householdID, domain.HouseholdMember.CanManage); err != nil {
+		return nil, err
+	}
+	if !domain.IsValidInvitationRole(role) {
+		return nil, domain.ErrInvalidHouseholdRole
+	}
+
+	member, err := s.member(ctx, memberUserID, householdID)
+	if err != nil {
+		return nil, err
+	}
+	if member.Role == domain.HouseholdRoleOwner {
+		return nil, domain.ErrHouseholdForbidden
+	}
+
+	member.Role = role
+	if err := s.DB.WithContext(ctx).Save(member).Error; err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// RemoveMember takes a member out of the household. The owner can remove
+// anyone else and members can leave; the owner cannot leave. Transactions
+// and budgets the member shared stay with the household.
+func (s *HouseholdService) RemoveMember(ctx context.Context, userID, householdID, memberUserID uint) error {
+	if memberUserID != userID {
+		if _, err := s.authorize(ctx, userID, householdID, domain.HouseholdMember.CanManage); err != nil {
+			return err
+		}
+	}
+	member, err := s.member(ctx, memberUserID, householdID)
+	if err != nil {
+		return err
+	}
+	if member.Role == domain.HouseholdRoleOwner {
+		return domain.ErrHouseholdForbidden
+	}
+	return s.DB.WithContext(ctx).Delete(member).Error
+}
+
+// ListTransactions returns a page of the household's shared transactions.
+// The filter's UserID narrows them down to one member when set.
+func (s *HouseholdService) ListTransactions(
+	ctx context.Context, userID, householdID uint, filter domain.TransactionFilter,
+) (*domain.TransactionPage, error) {
+	if _, err := s.member(ctx, userID, householdID); err != nil {
+		return nil, err
+	}
+	filter.HouseholdID = &householdID
+	filter.PersonalOnly = false
+	page, err := s.transactions().ListPage(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.transactions().countComments(ctx, page.Transactions); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// CreateTransaction records a transaction of the user shared with the household
+func (s *HouseholdService) CreateTransaction(
+	ctx context.Context, userID, householdID uint, transaction *domain.Transaction,
+) error {
+	if _, err := s.authorize(ctx, userID, householdID, domain.HouseholdMember.CanWrite); err != nil {
+		return err
+	}
+	transaction.UserID = userID
+	transaction.HouseholdID = &householdID
+	return s.transactions().Create(ctx, transaction)
+}
+
+// ListBudgets returns the household's budgets
+func (s *HouseholdService) ListBudgets(ctx context.Context, userID, householdID uint) ([]domain.Budget, error) {
+	if _, err := s.member(ctx, userID, householdID); err != nil {
+		return nil, err
+	}
+	return s.budgets().GetBudgetsByHousehold(ctx, householdID)
+}
+
+// CreateBudget creates a budget for the household that counts the shared
+// transactions of all members
+func (s *HouseholdService) CreateBudget(ctx context.Context, userID, householdID uint, budget *domain.Budget) error {
+	if _, err := s.authorize(ctx, userID, householdID, domain.HouseholdMember.CanWrite); err != nil {
+		return err
+	}
+	budget.UserID = userID
+	budget.HouseholdID = &householdID
+	budgets := s.budgets()
+	if err := budgets.CreateBudget(ctx, budget); err != nil {
+		return err
+	}
+
+	// Shared transactions may already fall into the new budget's period
+	parents, err := budgets.categoryParents(ctx)
+	if err != nil {
+		return err
+	}
+	created := []domain.Budget{*budget}
+	budgets.recalculate(ctx, created, parents)
+	*budget = created[0]
+	return nil
+}
+
+// GetFinances combines the user's personal finances with the household's
+// between the dates, broken down by member and expense category
+func (s *HouseholdService) GetFinances(
+	ctx context.Context, userID, householdID uint, startDate, endDate time.Time,
+) (*domain.HouseholdFinances, error) {
+	household, err := s.GetHousehold(ctx, userID, householdID)
+	if err != nil {
+		return nil, err
+	}
+
+	repo := persistence.NewTransactionRepository(s.DB)
+	personal, err := repo.Find(ctx, domain.TransactionFilter{
+		UserID: userID, PersonalOnly: true, ExcludeTransfers: true, StartDate: &startDate, EndDate: &endDate,
+	})
+	if err != nil {
+		return nil, err
+	}
+	shared, err := repo.Find(ctx, domain.TransactionFilter{
+		HouseholdID: &householdID, StartDate: &startDate, EndDate: &endDate,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	finances := &domain.HouseholdFinances{
+		HouseholdID: householdID,
+		UserID:      userID,
+		StartDate:   startDate,
+		EndDate:     endDate,
+		Members:     make([]domain.MemberContribution, len(household.Members)),
+	}
+	contributions := make(map[uint]*domain.MemberContribution, len(household.Members))
+	for i, member := range household.Members {
+		finances.Members[i] = domain.MemberContribution{UserID: member.UserID, Role: member.Role}
+		contributions[member.UserID] = &finances.Members[i]
+	}
+
+	for i := range personal {
+		finances.Personal.Add(personal[i])
+		finances.Combined.Add(personal[i])
+	}
+	var expenses []domain.Transaction
+	for i := range shared {
+		finances.Household.Add(shared[i])
+		finances.Combined.Add(shared[i])
+		// Former members' transactions count for the household but not per member
+		if contribution, ok := contributions[shared[i].UserID]; ok {
+			contribution.Add(shared[i])
+		}
+		if shared[i].Type != domain.TransactionTypeIncome {
+			expenses = append(expenses, shared[i])
+		}
+	}
+
+	analytics := &AnalyticsService{DB: s.DB}
+	finances.Categories = rollUpCategoryBreakdown(ctx, s.DB, analytics.calculateCategoryBreakdown(expenses))
+	return finances, nil
+}
+
+// member returns the user's membership of the household, or
+// domain.ErrNotFound if they are not a member
+func (s *HouseholdService) member(ctx context.Context, userID, householdID uint) (*domain.HouseholdMember, error) {
+	var member domain.HouseholdMember
+	err := s.DB.WithContext(ctx).Where("household_id = ? AND user_id = ?", householdID, userID).First(&member).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// authorize returns the user's membership if their role passes the check
+func (s *HouseholdService) authorize(
+	ctx context.Context, userID, householdID uint, allowed func(domain.HouseholdMember) bool,
+) (*domain.HouseholdMember, error) {
+	member, err := s.member(ctx, userID, householdID)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed(*member) {
+		return nil, domain.ErrHouseholdForbidden
+	}
+	return member, nil
+}
+
+// openInvitation returns the pending, unexpired invitation with the token if
+// it is addressed to the user
+func (s *HouseholdService) openInvitation(tx *gorm.DB, userID uint, token string) (*domain.HouseholdInvitation, error) {
+	var invitation domain.HouseholdInvitation
+	err := tx.Where("token = ?", token).First(&invitation).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrInvalidInvitation
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var user domain.User
+	if err := tx.First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+	if !invitation.IsOpen(time.Now()) || !strings.EqualFold(user.Email, invitation.Email) {
+		return nil, domain.ErrInvalidInvitation
+	}
+	return &invitation, nil
+}
+
+// invitationToken returns a random token that is hard to guess
+func invitationToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,260 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupHouseholdTestDB(t *testing.T) *gorm.DB {
+	db := setupBudgetTestDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.Household{}, &domain.HouseholdMember{}, &domain.HouseholdInvitation{}))
+	return db
+}
+
+// createHouseholdUsers creates users with the given emails and returns their IDs
+func createHouseholdUsers(t *testing.T, db *gorm.DB, emails ...string) []uint {
+	ids := make([]uint, len(emails))
+	for i, email := range emails {
+		user := &domain.User{Email: email, FirstName: "Test", LastName: "User"}
+		require.NoError(t, db.Create(user).Error)
+		ids[i] = user.ID
+	}
+	return ids
+}
+
+// joinHousehold invites the user by email and accepts the invitation as them
+func joinHousehold(t *testing.T, service *HouseholdService, ownerID, householdID, userID uint, email, role string) {
+	ctx := context.Background()
+	invitation, err := service.Invite(ctx, ownerID, householdID, email, role)
+	require.NoError(t, err)
+	_, err = service.AcceptInvitation(ctx, userID, invitation.Token)
+	require.NoError(t, err)
+}
+
+func TestHouseholdService_Invitations(t *testing.T) {
+	db := setupHouseholdTestDB(t)
+	service := NewHouseholdService(db)
+	ctx := context.Background()
+	users := createHouseholdUsers(t, db, "owner@example.com", "partner@example.com", "stranger@example.com")
+	owner, partner, stranger := users[0], users[1], users[2]
+
+	household, err := service.CreateHousehold(ctx, owner, "Home")
+	require.NoError(t, err)
+	require.Len(t, household.Members, 1)
+	assert.Equal(t, domain.HouseholdRoleOwner, household.Members[0].Role)
+
+	t.Run("only the owner invites", func(t *testing.T) {
+		_, err := service.Invite(ctx, stranger, household.ID, "x@example.com", domain.HouseholdRoleMember)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+
+		_, err = service.Invite(ctx, owner, household.ID, "x@example.com", domain.HouseholdRoleOwner)
+		assert.ErrorIs(t, err, domain.ErrInvalidHouseholdRole)
+	})
+
+	invitation, err := service.Invite(ctx, owner, household.ID, "Partner@Example.com", domain.HouseholdRoleMember)
+	require.NoError(t, err)
+	assert.Len(t, invitation.Token, 64)
+
+	t.Run("invitee sees the invitation", func(t *testing.T) {
+		invitations, err := service.ListInvitations(ctx, partner)
+		require.NoError(t, err)
+		require.Len(t, invitations, 1)
+		assert.Equal(t, "Home", invitations[0].Household.Name)
+
+		invitations, err = service.ListInvitations(ctx, stranger)
+		require.NoError(t, err)
+		assert.Empty(t, invitations)
+	})
+
+	t.Run("others cannot accept it", func(t *testing.T) {
+		_, err := service.AcceptInvitation(ctx, stranger, invitation.Token)
+		assert.ErrorIs(t, err, domain.ErrInvalidInvitation)
+		_, err = service.AcceptInvitation(ctx, partner, "unknown")
+		assert.ErrorIs(t, err, domain.ErrInvalidInvitation)
+	})
+
+	joined, err := service.AcceptInvitation(ctx, partner, invitation.Token)
+	require.NoError(t, err)
+	assert.Len(t, joined.Members, 2)
+
+	t.Run("invitation is used up", func(t *testing.T) {
+		_, err := service.AcceptInvitation(ctx, partner, invitation.Token)
+		assert.ErrorIs(t, err, domain.ErrInvalidInvitation)
+		_, err = service.Invite(ctx, owner, household.ID, "partner@example.com", domain.HouseholdRoleMember)
+		assert.ErrorIs(t, err, domain.ErrAlreadyHouseholdMember)
+	})
+
+	t.Run("expired invitations cannot be accepted", func(t *testing.T) {
+		expired, err := service.Invite(ctx, owner, household.ID, "stranger@example.com", domain.HouseholdRoleReadOnly)
+		require.NoError(t, err)
+		require.NoError(t, db.Model(expired).Update("expires_at", time.Now().Add(-time.Hour)).Error)
+
+		_, err = service.AcceptInvitation(ctx, stranger, expired.Token)
+		assert.ErrorIs(t, err, domain.ErrInvalidInvitation)
+	})
+
+	t.Run("declined invitations cannot be accepted", func(t *testing.T) {
+		declined, err := service.Invite(ctx, owner, household.ID, "stranger@example.com", domain.HouseholdRoleReadOnly)
+		require.NoError(t, err)
+		require.NoError(t, service.DeclineInvitation(ctx, stranger, declined.Token))
+
+		_, err = service.AcceptInvitation(ctx, stranger, declined.Token)
+		assert.ErrorIs(t, err, domain.ErrInvalidInvitation)
+	})
+
+	households, err := service.ListHouseholds(ctx, partner)
+	require.NoError(t, err)
+	require.Len(t, households, 1)
+	households, err = service.ListHouseholds(ctx, stranger)
+	require.NoError(t, err)
+	assert.Empty(t, households)
+}
+
+func TestHouseholdService_Members(t *testing.T) {
+	db := setupHouseholdTestDB(t)
+	service := NewHouseholdService(db)
+	ctx := context.Background()
+	users := createHouseholdUsers(t, db, "owner@example.com", "partner@example.com", "child@example.com")
+	owner, partner, child := users[0], users[1], users[2]
+
+	household, err := service.CreateHousehold(ctx, owner, "Home")
+	require.NoError(t, err)
+	joinHousehold(t, service, owner, household.ID, partner, "partner@example.com", domain.HouseholdRoleMember)
+	joinHousehold(t, service, owner, household.ID, child, "child@example.com", domain.HouseholdRoleMember)
+
+	member, err := service.UpdateMemberRole(ctx, owner, household.ID, child, domain.HouseholdRoleReadOnly)
+	require.NoError(t, err)
+	assert.Equal(t, domain.HouseholdRoleReadOnly, member.Role)
+
+	_, err = service.UpdateMemberRole(ctx, partner, household.ID, child, domain.HouseholdRoleMember)
+	assert.ErrorIs(t, err, domain.ErrHouseholdForbidden)
+	_, err = service.UpdateMemberRole(ctx, owner, household.ID, owner, domain.HouseholdRoleMember)
+	assert.ErrorIs(t, err, domain.ErrHouseholdForbidden)
+
+	assert.ErrorIs(t, service.RemoveMember(ctx, partner, household.ID, child), domain.ErrHouseholdForbidden)
+	assert.ErrorIs(t, service.RemoveMember(ctx, owner, household.ID, owner), domain.ErrHouseholdForbidden)
+	require.NoError(t, service.RemoveMember(ctx, child, household.ID, child))
+	require.NoError(t, service.RemoveMember(ctx, owner, household.ID, partner))
+
+	_, err = service.GetHousehold(ctx, partner, household.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	remaining, err := service.GetHousehold(ctx, owner, household.ID)
+	require.NoError(t, err)
+	assert.Len(t, remaining.Members, 1)
+}
+
+func TestHouseholdService_SharedFinances(t *testing.T) {
+	db := setupHouseholdTestDB(t)
+	budgetService := &BudgetService{DB: db}
+	service := &HouseholdService{
+		DB:           db,
+		Transactions: &TransactionService{DB: db, Budgets: budgetService},
+		Budgets:      budgetService,
+	}
+	ctx := context.Background()
+	users := createHouseholdUsers(t, db, "owner@example.com", "partner@example.com", "child@example.com")
+	owner, partner, child := users[0], users[1], users[2]
+
+	groceries := &domain.Category{Name: "Groceries", Type: "expense"}
+	salary := &domain.Category{Name: "Salary", Type: "income"}
+	require.NoError(t, db.Create(groceries).Error)
+	require.NoError(t, db.Create(salary).Error)
+
+	household, err := service.CreateHousehold(ctx, owner, "Home")
+	require.NoError(t, err)
+	joinHousehold(t, service, owner, household.ID, partner, "partner@example.com", domain.HouseholdRoleMember)
+	joinHousehold(t, service, owner, household.ID, child, "child@example.com", domain.HouseholdRoleReadOnly)
+
+	now := time.Now()
+	shared := func(userID uint, categoryID uint, txType string, amount float64) error {
+		return service.CreateTransaction(ctx, userID, household.ID, &domain.Transaction{
+			CategoryID: categoryID, Type: txType, Amount: domain.NewMoney(amount), Description: "shared", Date: now,
+		})
+	}
+	require.NoError(t, shared(owner, groceries.ID, "expense", 120))
+
+	budget := &domain.Budget{
+		CategoryID: groceries.ID, Amount: domain.NewMoney(400),
+		StartDate: now.AddDate(0, 0, -7), EndDate: now.AddDate(0, 0, 7),
+	}
+	require.NoError(t, service.CreateBudget(ctx, partner, household.ID, budget))
+	assert.Equal(t, domain.NewMoney(120.0), budget.Spent, "existing shared spending counts right away")
+
+	require.NoError(t, shared(partner, groceries.ID, "expense", 80))
+	require.NoError(t, shared(partner, salary.ID, "income", 1000))
+	assert.ErrorIs(t, shared(child, groceries.ID, "expense", 5), domain.ErrHouseholdForbidden)
+
+	// Personal transactions stay out of the household's budget
+	personal := &domain.Transaction{
+		UserID: owner, CategoryID: groceries.ID, Type: "expense", Amount: domain.NewMoney(30), Description: "own", Date: now,
+	}
+	require.NoError(t, service.transactions().Create(ctx, personal))
+
+	budgets, err := service.ListBudgets(ctx, child, household.ID)
+	require.NoError(t, err)
+	require.Len(t, budgets, 1)
+	assert.Equal(t, domain.NewMoney(200.0), budgets[0].Spent)
+
+	t.Run("personal budgets ignore shared transactions", func(t *testing.T) {
+		own := &domain.Budget{
+			UserID: owner, CategoryID: groceries.ID, Amount: domain.NewMoney(100),
+			StartDate: now.AddDate(0, 0, -7), EndDate: now.AddDate(0, 0, 7),
+		}
+		require.NoError(t, budgetService.CreateBudget(ctx, own))
+		require.NoError(t, budgetService.RefreshBudgetSpending(ctx, owner))
+
+		personalBudgets, err := budgetService.GetBudgetsByUser(ctx, owner)
+		require.NoError(t, err)
+		require.Len(t, personalBudgets, 1)
+		assert.Equal(t, domain.NewMoney(30.0), personalBudgets[0].Spent)
+	})
+
+	t.Run("members list the shared transactions", func(t *testing.T) {
+		page, err := service.ListTransactions(ctx, child, household.ID, domain.TransactionFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), page.Total)
+
+		page, err = service.ListTransactions(ctx, owner, household.ID, domain.TransactionFilter{UserID: partner})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), page.Total)
+	})
+
+	t.Run("finances combine personal and household totals", func(t *testing.T) {
+		finances, err := service.GetFinances(ctx, owner, household.ID, now.AddDate(0, 0, -1), now.AddDate(0, 0, 1))
+		require.NoError(t, err)
+
+		assert.Equal(t, domain.NewMoney(30.0), finances.Personal.Expenses)
+		assert.Equal(t, domain.NewMoney(200.0), finances.Household.Expenses)
+		assert.Equal(t, domain.NewMoney(1000.0), finances.Household.Income)
+		assert.Equal(t, domain.NewMoney(230.0), finances.Combined.Expenses)
+		assert.Equal(t, domain.NewMoney(770.0), finances.Combined.NetIncome)
+		assert.Equal(t, 4, finances.Combined.TransactionCount)
+
+		byMember := make(map[uint]domain.MemberContribution)
+		for _, member := range finances.Members {
+			byMember[member.UserID] = member
+		}
+		assert.Equal(t, domain.NewMoney(120.0), byMember[owner].Expenses)
+		assert.Equal(t, domain.NewMoney(1000.0), byMember[partner].Income)
+		assert.Zero(t, byMember[child].TransactionCount)
+
+		require.Len(t, finances.Categories, 1)
+		assert.Equal(t, "Groceries", finances.Categories[0].CategoryName)
+		assert.Equal(t, 200.0, finances.Categories[0].TotalAmount)
+	})
+
+	t.Run("outsiders see nothing", func(t *testing.T) {
+		outsider := createHouseholdUsers(t, db, "outsider@example.com")[0]
+		_, err := service.GetFinances(ctx, outsider, household.ID, now, now)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		_, err = service.ListBudgets(ctx, outsider, household.ID)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
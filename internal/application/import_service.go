@@ -0,0 +1,277 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/persistence"
+	"go-finance-advisor/internal/pkg"
+
+	"gorm.io/gorm"
+)
+
+// ImportService brings in the history users exported from other finance
+// apps. The apps' categories are mapped to local ones through the user's
+// category mappings, falling back to categories of the same name.
+type ImportService struct {
+	DB           *gorm.DB
+	Transactions *TransactionService // Records imported transactions; falls back to one over DB when nil
+	Categorizer  *CategorizerService // Files rows of unmapped categories under suggested ones when set
+}
+
+func NewImportService(db *gorm.DB) *ImportService {
+	return &ImportService{DB: db}
+}
+
+// transactions returns the service recording imports. Historical
+// transactions are not checked against spending caps, which only concern
+// the month they are recorded in.
+func (s *ImportService) transactions() *TransactionService {
+	if s.Transactions == nil {
+		return &TransactionService{DB: s.DB}
+	}
+	transactions := *s.Transactions
+	transactions.Caps = nil
+	return &transactions
+}
+
+// Import records the transactions of a file exported from the source in one
+// unit of work. Transfers and transactions already recorded are skipped, so
+// importing an overlapping file again only adds what is new. Rows that
+// cannot be read or fail validation are reported in the result. Rows whose category is not
+// mapped get a suggested category, when one is confident enough, before
+// falling back to "Other".
+func (s *ImportService) Import(ctx context.Context, userID uint, source string, data []byte) (*domain.ImportResult, error) {
+	imported, rowErrors, err := pkg.ParseImportFile(source, data)
+	if err != nil {
+		return nil, err
+	}
+	result := &domain.ImportResult{Source: source, Rows: len(imported) + len(rowErrors), Unmapped: []string{}, Errors: rowErrors}
+	if result.Errors == nil {
+		result.Errors = []domain.ImportRowError{}
+	}
+
+	resolve, err := categoryResolver(ctx, s.DB, userID, source)
+	if err != nil {
+		return nil, err
+	}
+	recorded, err := s.recorded(ctx, userID, imported)
+	if err != nil {
+		return nil, err
+	}
+	var suggest func(description, transactionType string) (*domain.CategorySuggestion, error)
+	if s.Categorizer != nil {
+		if suggest, err = s.Categorizer.suggester(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	transactions := s.transactions()
+	var valid []*domain.Transaction
+	for _, row := range imported {
+		if row.Transfer {
+			result.Transfers++
+			continue
+		}
+		key := importKey(row.Date.Format("2006-01-02"), row.Type, row.Amount, row.Description)
+		if recorded[key] > 0 {
+			recorded[key]--
+			result.Duplicates++
+			continue
+		}
+
+		categoryID, matched := resolve(row.Category, row.Type)
+		if !matched && row.Category != "" && !slices.Contains(result.Unmapped, row.Category) {
+			result.Unmapped = append(result.Unmapped, row.Category)
+		}
+		var suggestion *domain.CategorySuggestion
+		if !matched && suggest != nil {
+			if suggestion, err = suggest(row.Description, row.Type); err != nil {
+				return nil, err
+			}
+			if suggestion != nil {
+				categoryID = suggestion.CategoryID
+			}
+		}
+		if categoryID == 0 {
+			result.Errors = append(result.Errors, domain.ImportRowError{Row: row.Row, Message: "no category to import " + row.Type + " into"})
+			continue
+		}
+
+		transaction := &domain.Transaction{
+			UserID: userID, CategoryID: categoryID, Type: row.Type, Description: row.Description,
+			Notes: row.Notes, Tags: row.Tags, Amount: row.Amount, Date: row.Date,
+		}
+		err := transactions.prepare(ctx, transaction)
+		if errors.Is(err, domain.ErrValidation) {
+			result.Errors = append(result.Errors, domain.ImportRowError{Row: row.Row, Message: err.Error()})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		valid = append(valid, transaction)
+		if suggestion != nil {
+			result.Suggested++
+		}
+	}
+
+	// The file is recorded as a whole or not at all, so a failed import can
+	// simply be retried
+	err = persistence.InTransaction(ctx, s.DB, func(ctx context.Context) error {
+		for _, transaction := range valid {
+			if err := transactions.insert(ctx, transaction); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Imported = len(valid)
+	slices.Sort(result.Unmapped)
+	return result, nil
+}
+
+// categoryResolver returns a lookup of the local category a category of the
+// import source or bank provider maps to for a transaction type. In order, it
+// tries the user's mapping, a local category of the same name, a guess among
+// the default categories and the catch-all "Other" category; only the last
+// does not count as matched. Categories of the wrong type are passed over, so
+// refunds in an expense category are imported as other income.
+func categoryResolver(
+	ctx context.Context, db *gorm.DB, userID uint, source string,
+) (func(category, transactionType string) (uint, bool), error) {
+	var categories []domain.Category
+	if err := db.WithContext(ctx).Where("user_id IS NULL OR user_id = ?", userID).Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	byName := make(map[string]uint, len(categories))
+	types := make(map[uint]string, len(categories))
+	for i := range categories {
+		key := categories[i].Type + "|" + strings.ToLower(categories[i].Name)
+		// The user's own categories win over default ones of the same name
+		if _, taken := byName[key]; !taken || categories[i].UserID != nil {
+			byName[key] = categories[i].ID
+		}
+		types[categories[i].ID] = categories[i].Type
+	}
+
+	var mappings []domain.CategoryMapping
+	if err := db.WithContext(ctx).Where("user_id = ? AND source = ?", userID, source).Find(&mappings).Error; err != nil {
+		return nil, err
+	}
+	mapped := make(map[string]uint, len(mappings))
+	for _, mapping := range mappings {
+		mapped[strings.ToLower(mapping.ExternalCategory)] = mapping.CategoryID
+	}
+
+	return func(category, transactionType string) (uint, bool) {
+		if id, ok := mapped[strings.ToLower(category)]; ok && types[id] == transactionType {
+			return id, true
+		}
+		for _, name := range []string{category, domain.ExternalCategoryName(category), domain.GuessImportCategory(category)} {
+			if id, ok := byName[transactionType+"|"+strings.ToLower(name)]; ok && name != "" {
+				return id, true
+			}
+		}
+		return byName[transactionType+"|"+strings.ToLower(domain.ImportFallbackCategory(transactionType))], false
+	}, nil
+}
+
+// recorded counts the user's transactions over the dates of the import by
+// date, type, amount and description
+func (s *ImportService) recorded(ctx context.Context, userID uint, imported []domain.ImportedTransaction) (map[string]int, error) {
+	counts := make(map[string]int)
+	if len(imported) == 0 {
+		return counts, nil
+	}
+	first, last := imported[0].Date, imported[0].Date
+	for _, row := range imported {
+		if row.Date.Before(first) {
+			first = row.Date
+		}
+		if row.Date.After(last) {
+			last = row.Date
+		}
+	}
+
+	var existing []domain.Transaction
+	err := s.DB.WithContext(ctx).Select("date", "type", "amount", "description").
+		Where("user_id = ? AND date >= ? AND date < ?", userID, first, last.AddDate(0, 0, 1)).
+		Find(&existing).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, tx := range existing {
+		counts[importKey(tx.Date.Format("2006-01-02"), tx.Type, tx.Amount, tx.Description)]++
+	}
+	return counts, nil
+}
+
+func importKey(date, transactionType string, amount domain.Money, description string) string {
+	return date + "|" + transactionType + "|" + amount.String() + "|" + strings.ToLower(strings.TrimSpace(description))
+}
+
+// ListMappings returns the user's category mappings for the import source or
+// bank provider, or for every source when source is empty
+func (s *ImportService) ListMappings(ctx context.Context, userID uint, source string) ([]domain.CategoryMapping, error) {
+	query := s.DB.WithContext(ctx).Where("user_id = ?", userID)
+	if source != "" {
+		query = query.Where("source = ?", source)
+	}
+	mappings := []domain.CategoryMapping{}
+	err := query.Order("source, external_category").Find(&mappings).Error
+	return mappings, err
+}
+
+// SetMapping maps a category of an import source or bank provider to one of
+// the user's categories, replacing the mapping the category already has
+func (s *ImportService) SetMapping(ctx context.Context, userID uint, mapping domain.CategoryMapping) (*domain.CategoryMapping, error) {
+	mapping.ExternalCategory = strings.TrimSpace(mapping.ExternalCategory)
+	if err := mapping.Validate(); err != nil {
+		return nil, err
+	}
+	var category domain.Category
+	err := s.DB.WithContext(ctx).Where("id = ? AND (user_id IS NULL OR user_id = ?)", mapping.CategoryID, userID).First(&category).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, &domain.ValidationError{Fields: []domain.FieldError{
+			{Field: "category_id", Message: "must be one of your categories"},
+		}}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var existing domain.CategoryMapping
+	err = s.DB.WithContext(ctx).
+		Where("user_id = ? AND source = ? AND external_category = ?", userID, mapping.Source, mapping.ExternalCategory).
+		First(&existing).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		existing = domain.CategoryMapping{UserID: userID, Source: mapping.Source, ExternalCategory: mapping.ExternalCategory}
+	case err != nil:
+		return nil, err
+	}
+	existing.CategoryID = mapping.CategoryID
+	if err := s.DB.WithContext(ctx).Save(&existing).Error; err != nil {
+		return nil, err
+	}
+	return &existing, nil
+}
+
+// DeleteMapping removes one of the user's category mappings
+func (s *ImportService) DeleteMapping(ctx context.Context, userID, id uint) error {
+	result := s.DB.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&domain.CategoryMapping{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupImportTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&domain.Category{}, &domain.Transaction{}, &domain.TransactionTag{}, &domain.CategoryMapping{},
+	))
+	return db
+}
+
+func TestImportService(t *testing.T) {
+	db := setupImportTestDB(t)
+	service := NewImportService(db)
+	ctx := context.Background()
+
+	userID, otherUser := uint(1), uint(2)
+	categories := map[string]*domain.Category{}
+	for _, category := range []domain.Category{
+		{Name: "Food & Dining", Type: domain.TransactionTypeExpense},
+		{Name: "Other Expenses", Type: domain.TransactionTypeExpense},
+		{Name: "Salary", Type: domain.TransactionTypeIncome},
+		{Name: "Other Income", Type: domain.TransactionTypeIncome},
+		{Name: "Coffee", Type: domain.TransactionTypeExpense, UserID: &userID},
+		{Name: "Pets", Type: domain.TransactionTypeExpense, UserID: &otherUser},
+	} {
+		require.NoError(t, db.Create(&category).Error)
+		categories[category.Name] = &category
+	}
+
+	mint := "Date,Description,Amount,Transaction Type,Category,Labels\n" +
+		"3/01/2024,Starbucks,4.50,debit,Coffee Shops,morning\n" +
+		"3/02/2024,Whole Foods,80.00,debit,Groceries,\n" +
+		"3/03/2024,ACME Payroll,2500.00,credit,Paycheck,\n" +
+		"3/04/2024,Vet,120.00,debit,Pet Care,\n" +
+		"3/05/2024,Refund,20.00,credit,Groceries,\n" +
+		"3/06/2024,Savings,500.00,debit,Transfer,\n" +
+		"3/07/2024,Nothing,0.00,debit,Groceries,\n"
+
+	t.Run("should map categories only to the user's categories", func(t *testing.T) {
+		_, err := service.SetMapping(ctx, userID, domain.CategoryMapping{
+			Source: domain.ImportSourceMint, ExternalCategory: "Pet Care", CategoryID: categories["Pets"].ID,
+		})
+		assert.ErrorIs(t, err, domain.ErrValidation)
+
+		first, err := service.SetMapping(ctx, userID, domain.CategoryMapping{
+			Source: domain.ImportSourceMint, ExternalCategory: " Coffee Shops ", CategoryID: categories["Food & Dining"].ID,
+		})
+		require.NoError(t, err)
+		second, err := service.SetMapping(ctx, userID, domain.CategoryMapping{
+			Source: domain.ImportSourceMint, ExternalCategory: "Coffee Shops", CategoryID: categories["Coffee"].ID,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, first.ID, second.ID)
+		assert.Equal(t, categories["Coffee"].ID, second.CategoryID)
+	})
+
+	t.Run("should import transactions into mapped and matching categories", func(t *testing.T) {
+		result, err := service.Import(ctx, userID, domain.ImportSourceMint, []byte(mint))
+		require.NoError(t, err)
+		assert.Equal(t, 7, result.Rows)
+		assert.Equal(t, 5, result.Imported)
+		assert.Equal(t, 1, result.Transfers)
+		assert.Equal(t, []string{"Groceries", "Pet Care"}, result.Unmapped)
+		require.Len(t, result.Errors, 1)
+		assert.Equal(t, 8, result.Errors[0].Row)
+
+		var transactions []domain.Transaction
+		require.NoError(t, db.Where("user_id = ?", userID).Order("date").Find(&transactions).Error)
+		require.Len(t, transactions, 5)
+		assert.Equal(t, categories["Coffee"].ID, transactions[0].CategoryID)
+		assert.Equal(t, categories["Food & Dining"].ID, transactions[1].CategoryID)
+		assert.Equal(t, categories["Salary"].ID, transactions[2].CategoryID)
+		assert.Equal(t, categories["Other Expenses"].ID, transactions[3].CategoryID)
+		assert.Equal(t, categories["Other Income"].ID, transactions[4].CategoryID)
+
+		var tags []domain.TransactionTag
+		require.NoError(t, db.Where("transaction_id = ?", transactions[0].ID).Find(&tags).Error)
+		require.Len(t, tags, 1)
+		assert.Equal(t, "morning", tags[0].Tag)
+	})
+
+	t.Run("should skip transactions already imported", func(t *testing.T) {
+		overlapping := mint + "3/08/2024,Whole Foods,80.00,debit,Groceries,\n"
+		result, err := service.Import(ctx, userID, domain.ImportSourceMint, []byte(overlapping))
+		require.NoError(t, err)
+		assert.Equal(t, 5, result.Duplicates)
+		assert.Equal(t, 1, result.Imported)
+
+		var count int64
+		require.NoError(t, db.Model(&domain.Transaction{}).Where("user_id = ?", userID).Count(&count).Error)
+		assert.Equal(t, int64(6), count)
+	})
+
+	t.Run("should keep other users' imports apart", func(t *testing.T) {
+		result, err := service.Import(ctx, otherUser, domain.ImportSourceMint, []byte(mint))
+		require.NoError(t, err)
+		assert.Equal(t, 0, result.Duplicates)
+
+		var transaction domain.Transaction
+		require.NoError(t, db.Where("user_id = ? AND date = ?", otherUser, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)).
+			First(&transaction).Error)
+		assert.Equal(t, categories["Food & Dining"].ID, transaction.CategoryID)
+	})
+
+	t.Run("should record nothing of a file when a row fails to save", func(t *testing.T) {
+		const reimport = 99
+		require.NoError(t, db.Migrator().DropTable(&domain.TransactionTag{}))
+		defer func() { require.NoError(t, db.AutoMigrate(&domain.TransactionTag{})) }()
+
+		_, err := service.Import(ctx, reimport, domain.ImportSourceMint, []byte(mint))
+		assert.Error(t, err, "the tags of the first row cannot be saved")
+		var count int64
+		require.NoError(t, db.Model(&domain.Transaction{}).Where("user_id = ?", reimport).Count(&count).Error)
+		assert.Zero(t, count)
+	})
+
+	t.Run("should reject files that are not exports of the source", func(t *testing.T) {
+		_, err := service.Import(ctx, userID, domain.ImportSourceYNAB, []byte(mint))
+		assert.ErrorIs(t, err, domain.ErrInvalidImportFile)
+	})
+
+	t.Run("should list and delete the user's mappings", func(t *testing.T) {
+		mappings, err := service.ListMappings(ctx, userID, "")
+		require.NoError(t, err)
+		require.Len(t, mappings, 1)
+		mappings, err = service.ListMappings(ctx, userID, domain.ImportSourceYNAB)
+		require.NoError(t, err)
+		assert.Empty(t, mappings)
+
+		all, err := service.ListMappings(ctx, userID, domain.ImportSourceMint)
+		require.NoError(t, err)
+		require.Len(t, all, 1)
+		assert.ErrorIs(t, service.DeleteMapping(ctx, otherUser, all[0].ID), domain.ErrNotFound)
+		require.NoError(t, service.DeleteMapping(ctx, userID, all[0].ID))
+		assert.ErrorIs(t, service.DeleteMapping(ctx, userID, all[0].ID), domain.ErrNotFound)
+	})
+}
@@ -0,0 +1,78 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+)
+
+// GetIncomeBreakdown breaks the user's income over the given number of
+// complete months before the current one down by income source, estimating
+// the gross pay and taxes behind the net pay linked to each source. Sources
+// that are no longer active are left out unless they paid in those months.
+func (s *AnalyticsService) GetIncomeBreakdown(ctx context.Context, userID uint, months int) (*domain.IncomeBreakdown, error) {
+	if months < 1 || months > domain.MaxIncomeBreakdownMonths {
+		return nil, &domain.ValidationError{Fields: []domain.FieldError{{
+			Field: "months", Message: fmt.Sprintf("must be between 1 and %d", domain.MaxIncomeBreakdownMonths),
+		}}}
+	}
+
+	now := time.Now()
+	current := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	start := current.AddDate(0, -months, 0)
+	end := current.Add(-time.Second)
+
+	var sources []domain.IncomeSource
+	if err := s.DB.WithContext(ctx).Where("user_id = ?", userID).Order("employer, id").Find(&sources).Error; err != nil {
+		return nil, err
+	}
+	var transactions []domain.Transaction
+	err := s.DB.WithContext(ctx).Select("income_source_id", "amount", "date").
+		Where("user_id = ? AND type = ? AND date BETWEEN ? AND ?", userID, domain.TransactionTypeIncome, start, end).
+		Find(&transactions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	pay := make(map[uint][]domain.IncomeSourceMonth, len(sources))
+	for _, source := range sources {
+		history := make([]domain.IncomeSourceMonth, months)
+		for i := range history {
+			month := start.AddDate(0, i, 0)
+			history[i] = domain.IncomeSourceMonth{Year: month.Year(), Month: int(month.Month())}
+		}
+		pay[source.ID] = history
+	}
+	var unlinked domain.Money
+	for _, transaction := range transactions {
+		var sourceID uint
+		if transaction.IncomeSourceID != nil {
+			sourceID = *transaction.IncomeSourceID
+		}
+		history, ok := pay[sourceID]
+		if !ok {
+			unlinked += transaction.Amount
+			continue
+		}
+		date := transaction.Date.In(now.Location())
+		i := (date.Year()-start.Year())*12 + int(date.Month()) - int(start.Month())
+		if i < 0 || i >= months {
+			continue
+		}
+		history[i].Paychecks++
+		history[i].Net += transaction.Amount
+	}
+
+	breakdowns := make([]domain.IncomeSourceBreakdown, 0, len(sources))
+	for i := range sources {
+		breakdown := domain.NewIncomeSourceBreakdown(&sources[i], pay[sources[i].ID])
+		if !sources[i].Active && breakdown.Paychecks == 0 {
+			continue
+		}
+		breakdowns = append(breakdowns, breakdown)
+	}
+	result := domain.NewIncomeBreakdown(userID, months, breakdowns, unlinked)
+	return &result, nil
+}
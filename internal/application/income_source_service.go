@@ -0,0 +1,133 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"go-finance-advisor/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// IncomeSourceService keeps the sources of users' pay and links their
+// income transactions to them
+type IncomeSourceService struct {
+	DB *gorm.DB
+}
+
+func NewIncomeSourceService(db *gorm.DB) *IncomeSourceService {
+	return &IncomeSourceService{DB: db}
+}
+
+// Create adds an active income source for the user
+func (s *IncomeSourceService) Create(ctx context.Context, userID uint, source *domain.IncomeSource) error {
+	if err := source.Validate(); err != nil {
+		return err
+	}
+	source.ID, source.UserID, source.Active = 0, userID, true
+	if source.Deductions == nil {
+		source.Deductions = []domain.PaycheckDeduction{}
+	}
+	return s.DB.WithContext(ctx).Create(source).Error
+}
+
+// List returns the user's income sources by employer
+func (s *IncomeSourceService) List(ctx context.Context, userID uint) ([]domain.IncomeSource, error) {
+	var sources []domain.IncomeSource
+	err := s.DB.WithContext(ctx).Where("user_id = ?", userID).Order("employer, id").Find(&sources).Error
+	return sources, err
+}
+
+// Get returns one of the user's income sources
+func (s *IncomeSourceService) Get(ctx context.Context, userID, id uint) (*domain.IncomeSource, error) {
+	var source domain.IncomeSource
+	err := s.DB.WithContext(ctx).Where("user_id = ?", userID).First(&source, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &source, nil
+}
+
+// Update saves the pay, deductions and active flag of one of the user's
+// income sources
+func (s *IncomeSourceService) Update(ctx context.Context, userID uint, source *domain.IncomeSource) error {
+	if err := source.Validate(); err != nil {
+		return err
+	}
+	if source.Deductions == nil {
+		source.Deductions = []domain.PaycheckDeduction{}
+	}
+	result := s.DB.WithContext(ctx).Model(&domain.IncomeSource{}).
+		Where("id = ? AND user_id = ?", source.ID, userID).
+		Select("employer", "gross", "deductions", "frequency", "active").
+		Updates(source)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// Delete removes one of the user's income sources; its transactions stay,
+// no longer linked to a source
+func (s *IncomeSourceService) Delete(ctx context.Context, userID, id uint) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND user_id = ?", id, userID).Delete(&domain.IncomeSource{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrNotFound
+		}
+		return tx.Model(&domain.Transaction{}).Unscoped().
+			Where("income_source_id = ?", id).Update("income_source_id", nil).Error
+	})
+}
+
+// LinkTransactions links the user's income transactions to one of their
+// income sources, moving them from any other source
+func (s *IncomeSourceService) LinkTransactions(ctx context.Context, userID, sourceID uint, transactionIDs []uint) error {
+	if _, err := s.Get(ctx, userID, sourceID); err != nil {
+		return err
+	}
+	if len(transactionIDs) == 0 {
+		return &domain.ValidationError{Fields: []domain.FieldError{{Field: "transaction_ids", Message: "is required"}}}
+	}
+	ids := slices.Compact(slices.Sorted(slices.Values(transactionIDs)))
+
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.Transaction{}).
+			Where("id IN ? AND user_id = ? AND type = ?", ids, userID, domain.TransactionTypeIncome).
+			Update("income_source_id", sourceID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != int64(len(ids)) {
+			return &domain.ValidationError{Fields: []domain.FieldError{{
+				Field: "transaction_ids", Message: "must all be income transactions of the user",
+			}}}
+		}
+		return nil
+	})
+}
+
+// UnlinkTransaction removes the link of one of the user's transactions to
+// the income source
+func (s *IncomeSourceService) UnlinkTransaction(ctx context.Context, userID, sourceID, transactionID uint) error {
+	result := s.DB.WithContext(ctx).Model(&domain.Transaction{}).
+		Where("id = ? AND user_id = ? AND income_source_id = ?", transactionID, userID, sourceID).
+		Update("income_source_id", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
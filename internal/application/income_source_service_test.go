@@ -0,0 +1,101 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupIncomeSourceTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&domain.IncomeSource{}, &domain.Category{}, &domain.Transaction{}))
+	return db
+}
+
+func TestIncomeSourceService(t *testing.T) {
+	db := setupIncomeSourceTestDB(t)
+	service := NewIncomeSourceService(db)
+	ctx := context.Background()
+
+	source := &domain.IncomeSource{
+		Employer: "Acme", Gross: domain.NewMoney(5000), Frequency: domain.PayFrequencyMonthly,
+		Deductions: []domain.PaycheckDeduction{{Name: "Income tax", Kind: domain.DeductionTax, Amount: domain.NewMoney(1000)}},
+	}
+	require.NoError(t, service.Create(ctx, 1, source))
+
+	t.Run("keeps deductions and scopes to the user", func(t *testing.T) {
+		stored, err := service.Get(ctx, 1, source.ID)
+		require.NoError(t, err)
+		assert.True(t, stored.Active)
+		require.Len(t, stored.Deductions, 1)
+		assert.Equal(t, domain.NewMoney(1000), stored.Deductions[0].Amount)
+
+		_, err = service.Get(ctx, 2, source.ID)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		var validationErr *domain.ValidationError
+		assert.ErrorAs(t, service.Create(ctx, 1, &domain.IncomeSource{Employer: "Gig", Frequency: "daily"}), &validationErr)
+	})
+
+	t.Run("updates the pay and active flag", func(t *testing.T) {
+		update := *source
+		update.Gross, update.Active = domain.NewMoney(5500), false
+		require.NoError(t, service.Update(ctx, 1, &update))
+
+		stored, err := service.Get(ctx, 1, source.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.NewMoney(5500), stored.Gross)
+		assert.False(t, stored.Active)
+		assert.ErrorIs(t, service.Update(ctx, 2, &update), domain.ErrNotFound)
+	})
+
+	salary := domain.Category{Name: "Salary", Type: domain.TransactionTypeIncome}
+	require.NoError(t, db.Create(&salary).Error)
+	transactions := []domain.Transaction{
+		{UserID: 1, CategoryID: salary.ID, Type: domain.TransactionTypeIncome, Amount: domain.NewMoney(4000)},
+		{UserID: 1, CategoryID: salary.ID, Type: domain.TransactionTypeExpense, Amount: domain.NewMoney(40)},
+		{UserID: 2, CategoryID: salary.ID, Type: domain.TransactionTypeIncome, Amount: domain.NewMoney(4000)},
+	}
+	require.NoError(t, db.Create(&transactions).Error)
+	linkedTo := func(id uint) *uint {
+		var transaction domain.Transaction
+		require.NoError(t, db.First(&transaction, id).Error)
+		return transaction.IncomeSourceID
+	}
+
+	t.Run("links only the user's income transactions", func(t *testing.T) {
+		var validationErr *domain.ValidationError
+		assert.ErrorAs(t, service.LinkTransactions(ctx, 1, source.ID, []uint{transactions[0].ID, transactions[1].ID}), &validationErr)
+		assert.ErrorAs(t, service.LinkTransactions(ctx, 1, source.ID, []uint{transactions[2].ID}), &validationErr)
+		assert.Nil(t, linkedTo(transactions[0].ID))
+
+		require.NoError(t, service.LinkTransactions(ctx, 1, source.ID, []uint{transactions[0].ID, transactions[0].ID}))
+		assert.Equal(t, source.ID, *linkedTo(transactions[0].ID))
+	})
+
+	t.Run("unlinks a transaction", func(t *testing.T) {
+		require.NoError(t, service.UnlinkTransaction(ctx, 1, source.ID, transactions[0].ID))
+		assert.Nil(t, linkedTo(transactions[0].ID))
+		assert.ErrorIs(t, service.UnlinkTransaction(ctx, 1, source.ID, transactions[0].ID), domain.ErrNotFound)
+	})
+
+	t.Run("deleting a source keeps its transactions", func(t *testing.T) {
+		require.NoError(t, service.LinkTransactions(ctx, 1, source.ID, []uint{transactions[0].ID}))
+		assert.ErrorIs(t, service.Delete(ctx, 2, source.ID), domain.ErrNotFound)
+		require.NoError(t, service.Delete(ctx, 1, source.ID))
+		assert.Nil(t, linkedTo(transactions[0].ID))
+
+		sources, err := service.List(ctx, 1)
+		require.NoError(t, err)
+		assert.Empty(t, sources)
+	})
+}
@@ -0,0 +1,75 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+)
+
+// GetIncomeStability analyses the user's income over the given number of
+// complete months before the current one. Months before the user's first
+// transaction are left out, so a short history is not read as months
+// without income.
+func (s *AnalyticsService) GetIncomeStability(ctx context.Context, userID uint, months int) (*domain.IncomeStability, error) {
+	if months < domain.MinIncomeStabilityMonths || months > domain.MaxIncomeStabilityMonths {
+		return nil, &domain.ValidationError{Fields: []domain.FieldError{{
+			Field: "months", Message: fmt.Sprintf("must be between %d and %d",
+				domain.MinIncomeStabilityMonths, domain.MaxIncomeStabilityMonths),
+		}}}
+	}
+
+	now := time.Now()
+	current := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	periods := make([]dateRange, months)
+	for i := range periods {
+		start := current.AddDate(0, i-months, 0)
+		periods[i] = dateRange{Start: start, End: start.AddDate(0, 1, 0).Add(-time.Second)}
+	}
+	totals, err := sumByPeriod(ctx, s.DB, userID, periods)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]domain.MonthlyIncome, 0, months)
+	for i, period := range periods {
+		if len(history) == 0 && totals[i].TransactionCount == 0 {
+			continue
+		}
+		history = append(history, domain.MonthlyIncome{
+			Year: period.Start.Year(), Month: int(period.Start.Month()),
+			Income: totals[i].Income, Expenses: totals[i].Expenses,
+		})
+	}
+
+	buffer, err := s.moneyOnHand(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	stability := domain.NewIncomeStability(userID, history, buffer)
+	return &stability, nil
+}
+
+// moneyOnHand is the opening balance of the user's accounts plus everything
+// they have earned minus everything they have spent
+func (s *AnalyticsService) moneyOnHand(ctx context.Context, userID uint) (domain.Money, error) {
+	var opening domain.Money
+	err := s.DB.WithContext(ctx).Model(&domain.Account{}).
+		Where("user_id = ?", userID).
+		Select("COALESCE(SUM(opening_balance), 0)").Scan(&opening).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var net domain.Money
+	err = s.DB.WithContext(ctx).Model(&domain.Transaction{}).
+		Where("user_id = ?", userID).
+		Select("COALESCE(SUM(CASE type WHEN ? THEN amount WHEN ? THEN -amount ELSE 0 END), 0)",
+			domain.TransactionTypeIncome, domain.TransactionTypeExpense).
+		Scan(&net).Error
+	if err != nil {
+		return 0, err
+	}
+	return opening + net, nil
+}
@@ -0,0 +1,531 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// insightMinAmount ignores changes too small to be worth mentioning
+	insightMinAmount = 10.0
+	// insightMinChangePercent is the category change needed to report a spike or drop
+	insightMinChangePercent = 20.0
+	// insightMinSavingsRateDelta is the savings rate change (in points) needed to report it
+	insightMinSavingsRateDelta = 5.0
+	// insightMaxNewMerchants caps how many new merchants are reported per period
+	insightMaxNewMerchants = 3
+)
+
+// insightRecommendationWindow is how long advisors' recommendations stay in
+// their clients' insights
+const insightRecommendationWindow = 30 * 24 * time.Hour
+
+type insightsCacheEntry struct {
+	report    *domain.InsightsReport
+	expiresAt time.Time
+}
+
+// InsightsService compares a user's current period with previous periods and
+// turns the differences into ranked, human readable insights
+type InsightsService struct {
+	DB       *gorm.DB
+	CacheTTL time.Duration
+	Lookback int // Number of previous periods to compare against
+
+	mu    sync.RWMutex
+	cache map[string]insightsCacheEntry
+}
+
+func NewInsightsService(db *gorm.DB) *InsightsService {
+	return &InsightsService{
+		DB:       db,
+		CacheTTL: time.Hour,
+		Lookback: 3,
+		cache:    make(map[string]insightsCacheEntry),
+	}
+}
+
+// IsValidInsightPeriod reports whether insights can be generated for the period
+func IsValidInsightPeriod(period string) bool {
+	switch period {
+	case "week", "month", "quarter", "year":
+		return true
+	}
+	return false
+}
+
+// GetInsights returns the ranked insights for the user's current period,
+// served from the cache when a fresh copy is available
+func (s *InsightsService) GetInsights(ctx context.Context, userID uint, period string) (*domain.InsightsReport, error) {
+	now := time.Now()
+	key := insightsCacheKey(userID, period, insightPeriodStart(now, period))
+
+	if report := s.cached(key, now); report != nil {
+		return report, nil
+	}
+
+	report, err := s.generateInsights(ctx, userID, period, now)
+	if err != nil {
+		return nil, err
+	}
+	s.store(key, report, now)
+
+	return report, nil
+}
+
+// PrecomputeInsights generates and caches insights for every user with
+// transactions in the comparison window
+func (s *InsightsService) PrecomputeInsights(ctx context.Context, period string) error {
+	now := time.Now()
+	windowStart := shiftInsightPeriod(insightPeriodStart(now, period), period, -s.lookback())
+
+	var userIDs []uint
+	err := s.DB.WithContext(ctx).Model(&domain.Transaction{}).
+		Where("date BETWEEN ? AND ?", windowStart, now).
+		Distinct().Pluck("user_id", &userIDs).Error
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range userIDs {
+		report, err := s.generateInsights(ctx, userID, period, now)
+		if err != nil {
+			return fmt.Errorf("failed to precompute insights for user %d: %w", userID, err)
+		}
+		s.store(insightsCacheKey(userID, period, report.StartDate), report, now)
+	}
+
+	return nil
+}
+
+// StartPrecompute refreshes cached insights on the given interval until ctx is cancelled
+func (s *InsightsService) StartPrecompute(ctx context.Context, period string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.PrecomputeInsights(ctx, period); err != nil {
+			log.Printf("insights precompute failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// InvalidateUser drops all cached insights for a user
+func (s *InsightsService) InvalidateUser(userID uint) {
+	prefix := fmt.Sprintf("%d:", userID)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key := range s.cache {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.cache, key)
+		}
+	}
+}
+
+func (s *InsightsService) cached(key string, now time.Time) *domain.InsightsReport {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, ok := s.cache[key]
+	if !ok || now.After(entry.expiresAt) {
+		return nil
+	}
+	return entry.report
+}
+
+func (s *InsightsService) store(key string, report *domain.InsightsReport, now time.Time) {
+	ttl := s.CacheTTL
+	if ttl <= 0 {
+		ttl = time.Hour
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cache == nil {
+		s.cache = make(map[string]insightsCacheEntry)
+	}
+	s.cache[key] = insightsCacheEntry{report: report, expiresAt: now.Add(ttl)}
+}
+
+func (s *InsightsService) lookback() int {
+	if s.Lookback <= 0 {
+		return 3
+	}
+	return s.Lookback
+}
+
+// insightTotals aggregates the transactions of one comparison window
+type insightTotals struct {
+	income     domain.Money
+	expenses   domain.Money
+	categories map[uint]domain.Money
+	count      int
+}
+
+func newInsightTotals() *insightTotals {
+	return &insightTotals{categories: make(map[uint]domain.Money)}
+}
+
+func (t *insightTotals) add(tx *domain.Transaction) {
+	t.count++
+	if tx.Type == domain.TransactionTypeIncome {
+		t.income += tx.Amount
+		return
+	}
+	t.expenses += tx.Amount
+	t.categories[tx.CategoryID] += tx.Amount
+}
+
+// generateInsights compares the current period-to-date with the same elapsed
+// span of each previous period, so a half-finished month is compared with the
+// first halves of earlier months rather than with full months
+func (s *InsightsService) generateInsights(ctx context.Context, userID uint, period string, now time.Time) (*domain.InsightsReport, error) {
+	lookback := s.lookback()
+	currentStart := insightPeriodStart(now, period)
+	elapsed := now.Sub(currentStart)
+
+	var transactions []domain.Transaction
+	err := s.DB.WithContext(ctx).Preload("Category").
+		Where("user_id = ? AND date BETWEEN ? AND ? AND type <> ?",
+			userID, shiftInsightPeriod(currentStart, period, -lookback), now, domain.TransactionTypeTransfer).
+		Find(&transactions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	current := newInsightTotals()
+	previous := make([]*insightTotals, lookback)
+	for i := range previous {
+		previous[i] = newInsightTotals()
+	}
+	categoryNames := make(map[uint]string)
+	var currentExpenses []domain.Transaction
+
+	for i := range transactions {
+		tx := &transactions[i]
+		if tx.Category.Name != "" {
+			categoryNames[tx.CategoryID] = tx.Category.Name
+		}
+		if !tx.Date.Before(currentStart) {
+			current.add(tx)
+			if tx.Type != domain.TransactionTypeIncome {
+				currentExpenses = append(currentExpenses, *tx)
+			}
+			continue
+		}
+		for j := 1; j <= lookback; j++ {
+			windowStart := shiftInsightPeriod(currentStart, period, -j)
+			windowEnd := windowStart.Add(elapsed)
+			if periodEnd := shiftInsightPeriod(windowStart, period, 1); windowEnd.After(periodEnd) {
+				windowEnd = periodEnd
+			}
+			if !tx.Date.Before(windowStart) && !tx.Date.After(windowEnd) {
+				previous[j-1].add(tx)
+				break
+			}
+		}
+	}
+
+	// Only periods with activity count as history; a brand new user has nothing to compare against
+	var history []*insightTotals
+	for _, totals := range previous {
+		if totals.count > 0 {
+			history = append(history, totals)
+		}
+	}
+
+	report := &domain.InsightsReport{
+		UserID:          userID,
+		Period:          period,
+		StartDate:       currentStart,
+		EndDate:         now,
+		ComparedPeriods: len(history),
+		Insights:        []domain.Insight{},
+		GeneratedAt:     now,
+	}
+	// Advisors' recommendations come first, whether or not there is history
+	recommendations, err := s.advisorRecommendationInsights(ctx, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	report.Insights = append(report.Insights, recommendations...)
+	if len(history) == 0 {
+		return report, nil
+	}
+
+	comparison := insightComparisonLabel(period, len(history))
+	insights := categoryInsights(current, history, categoryNames, period, comparison)
+	if insight := savingsRateInsight(current, history, period, comparison); insight != nil {
+		insights = append(insights, *insight)
+	}
+
+	merchantInsights, err := s.newMerchantInsights(ctx, userID, currentStart, currentExpenses, period)
+	if err != nil {
+		return nil, err
+	}
+	insights = append(insights, merchantInsights...)
+
+	sort.SliceStable(insights, func(i, j int) bool {
+		return insights[i].Score > insights[j].Score
+	})
+	for i := range insights {
+		insights[i].Rank = len(recommendations) + i + 1
+	}
+	report.Insights = append(report.Insights, insights...)
+
+	return report, nil
+}
+
+// advisorRecommendationInsights turns the recommendations advisors left the
+// user within the recommendation window into insights, newest first
+func (s *InsightsService) advisorRecommendationInsights(ctx context.Context, userID uint, now time.Time) ([]domain.Insight, error) {
+	var recommendations []struct {
+		Message   string
+		Email     string
+		FirstName string
+		LastName  string
+	}
+	err := s.DB.WithContext(ctx).Model(&domain.AdvisorRecommendation{}).
+		Select("advisor_recommendations.message, users.email, users.first_name, users.last_name").
+		Joins("JOIN users ON users.id = advisor_recommendations.advisor_id").
+		Where("advisor_recommendations.client_id = ? AND advisor_recommendations.created_at >= ?",
+			userID, now.Add(-insightRecommendationWindow)).
+		Order("advisor_recommendations.created_at DESC, advisor_recommendations.id DESC").
+		Scan(&recommendations).Error
+	if err != nil {
+		return nil, err
+	}
+
+	insights := make([]domain.Insight, len(recommendations))
+	for i, recommendation := range recommendations {
+		advisor := strings.TrimSpace(recommendation.FirstName + " " + recommendation.LastName)
+		if advisor == "" {
+			advisor = recommendation.Email
+		}
+		insights[i] = domain.Insight{
+			Rank:    i + 1,
+			Type:    domain.InsightAdvisorRecommendation,
+			Message: recommendation.Message,
+			Advisor: advisor,
+		}
+	}
+	return insights, nil
+}
+
+func categoryInsights(
+	current *insightTotals, history []*insightTotals, names map[uint]string, period, comparison string,
+) []domain.Insight {
+	categoryIDs := make(map[uint]bool)
+	for id := range current.categories {
+		categoryIDs[id] = true
+	}
+	for _, totals := range history {
+		for id := range totals.categories {
+			categoryIDs[id] = true
+		}
+	}
+
+	var insights []domain.Insight
+	for id := range categoryIDs {
+		spent := current.categories[id].Float64()
+		var sum domain.Money
+		for _, totals := range history {
+			sum += totals.categories[id]
+		}
+		average := sum.Float64() / float64(len(history))
+		diff := spent - average
+		if math.Abs(diff) < insightMinAmount {
+			continue
+		}
+
+		categoryID := id
+		name := names[id]
+		if name == "" {
+			name = "Uncategorized"
+		}
+		insight := domain.Insight{
+			CategoryID:    &categoryID,
+			CategoryName:  name,
+			CurrentValue:  spent,
+			PreviousValue: average,
+			Score:         math.Abs(diff),
+		}
+
+		switch {
+		case average == 0:
+			insight.Type = domain.InsightCategorySpike
+			insight.ChangePercent = 100
+			insight.Message = fmt.Sprintf("You started spending on %s this %s ($%.2f so far).", name, period, spent)
+		case diff/average*100 >= insightMinChangePercent:
+			insight.Type = domain.InsightCategorySpike
+			insight.ChangePercent = diff / average * 100
+			insight.Message = fmt.Sprintf("You spent %.0f%% more on %s this %s than %s.",
+				insight.ChangePercent, name, period, comparison)
+		case diff/average*100 <= -insightMinChangePercent:
+			insight.Type = domain.InsightCategoryDrop
+			insight.ChangePercent = diff / average * 100
+			insight.Message = fmt.Sprintf("You spent %.0f%% less on %s this %s than %s.",
+				-insight.ChangePercent, name, period, comparison)
+		default:
+			continue
+		}
+
+		insights = append(insights, insight)
+	}
+
+	return insights
+}
+
+func savingsRateInsight(current *insightTotals, history []*insightTotals, period, comparison string) *domain.Insight {
+	var income, expenses domain.Money
+	for _, totals := range history {
+		income += totals.income
+		expenses += totals.expenses
+	}
+	if current.income <= 0 || income <= 0 {
+		return nil
+	}
+
+	currentRate := (current.income - current.expenses).PercentOf(current.income)
+	previousRate := (income - expenses).PercentOf(income)
+	delta := currentRate - previousRate
+	if math.Abs(delta) < insightMinSavingsRateDelta {
+		return nil
+	}
+
+	direction := "rose"
+	if delta < 0 {
+		direction = "fell"
+	}
+
+	return &domain.Insight{
+		Type: domain.InsightSavingsRateChange,
+		Message: fmt.Sprintf("Your savings rate %s to %.0f%% this %s, compared with %.0f%% %s.",
+			direction, currentRate, period, previousRate, strings.Replace(comparison, "your average", "on average", 1)),
+		CurrentValue:  currentRate,
+		PreviousValue: previousRate,
+		ChangePercent: delta,
+		// Weight by income so the score is comparable to category amount changes
+		Score: math.Abs(delta) / 100 * current.income.Float64(),
+	}
+}
+
+// newMerchantInsights reports the largest merchants the user had never paid before this period.
+// Merchants are identified by the transaction description until a dedicated field exists.
+func (s *InsightsService) newMerchantInsights(
+	ctx context.Context, userID uint, currentStart time.Time, expenses []domain.Transaction, period string,
+) ([]domain.Insight, error) {
+	totals := make(map[string]domain.Money)
+	displayNames := make(map[string]string)
+	for i := range expenses {
+		key := strings.ToLower(strings.TrimSpace(expenses[i].Description))
+		if key == "" {
+			continue
+		}
+		if _, ok := displayNames[key]; !ok {
+			displayNames[key] = strings.TrimSpace(expenses[i].Description)
+		}
+		totals[key] += expenses[i].Amount
+	}
+	if len(totals) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]string, 0, len(totals))
+	for key := range totals {
+		keys = append(keys, key)
+	}
+
+	var known []string
+	err := s.DB.WithContext(ctx).Model(&domain.Transaction{}).
+		Where("user_id = ? AND date < ? AND LOWER(TRIM(description)) IN ?", userID, currentStart, keys).
+		Distinct().Pluck("LOWER(TRIM(description))", &known).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range known {
+		delete(totals, key)
+	}
+
+	var insights []domain.Insight
+	for key, total := range totals {
+		amount := total.Float64()
+		if amount < insightMinAmount {
+			continue
+		}
+		insights = append(insights, domain.Insight{
+			Type:         domain.InsightNewMerchant,
+			Message:      fmt.Sprintf("New merchant this %s: %s ($%.2f).", period, displayNames[key], amount),
+			Merchant:     displayNames[key],
+			CurrentValue: amount,
+			Score:        amount,
+		})
+	}
+
+	sort.Slice(insights, func(i, j int) bool {
+		return insights[i].Score > insights[j].Score
+	})
+	if len(insights) > insightMaxNewMerchants {
+		insights = insights[:insightMaxNewMerchants]
+	}
+
+	return insights, nil
+}
+
+func insightsCacheKey(userID uint, period string, start time.Time) string {
+	return fmt.Sprintf("%d:%s:%s", userID, period, start.Format("2006-01-02"))
+}
+
+func insightComparisonLabel(period string, periods int) string {
+	if periods == 1 {
+		return "the previous " + period
+	}
+	return fmt.Sprintf("your average over the previous %d %ss", periods, period)
+}
+
+// insightPeriodStart returns the start of the calendar period containing t.
+// Weeks start on Monday.
+func insightPeriodStart(t time.Time, period string) time.Time {
+	year, month, day := t.Date()
+	switch period {
+	case "week":
+		midnight := time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+		return midnight.AddDate(0, 0, -((int(midnight.Weekday()) + 6) % 7))
+	case "quarter":
+		return time.Date(year, time.Month((int(month)-1)/3*3+1), 1, 0, 0, 0, 0, t.Location())
+	case "year":
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(year, month, 1, 0, 0, 0, 0, t.Location())
+	}
+}
+
+func shiftInsightPeriod(start time.Time, period string, n int) time.Time {
+	switch period {
+	case "week":
+		return start.AddDate(0, 0, 7*n)
+	case "quarter":
+		return start.AddDate(0, 3*n, 0)
+	case "year":
+		return start.AddDate(n, 0, 0)
+	default:
+		return start.AddDate(0, n, 0)
+	}
+}
@@ -0,0 +1,170 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupInsightsTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&domain.User{}, &domain.Category{}, &domain.Transaction{}, &domain.AdvisorRecommendation{})
+	require.NoError(t, err)
+
+	return db
+}
+
+func createInsightsTestData(t *testing.T, db *gorm.DB) (userID uint) {
+	user := &domain.User{Email: "insights@example.com", FirstName: "Test", LastName: "User"}
+	require.NoError(t, db.Create(user).Error)
+
+	dining := &domain.Category{Name: "Dining", Type: "expense"}
+	groceries := &domain.Category{Name: "Groceries", Type: "expense"}
+	salary := &domain.Category{Name: "Salary", Type: "income"}
+	require.NoError(t, db.Create(dining).Error)
+	require.NoError(t, db.Create(groceries).Error)
+	require.NoError(t, db.Create(salary).Error)
+
+	add := func(categoryID uint, txType, description string, amount float64, date time.Time) {
+		require.NoError(t, db.Create(&domain.Transaction{
+			UserID: user.ID, CategoryID: categoryID, Type: txType,
+			Description: description, Amount: domain.NewMoney(amount), Date: date,
+		}).Error)
+	}
+
+	// The same first half of each of the three previous months
+	for _, month := range []time.Month{time.February, time.January, time.December} {
+		year := 2024
+		if month == time.December {
+			year = 2023
+		}
+		add(salary.ID, "income", "Payroll", 1000, time.Date(year, month, 1, 9, 0, 0, 0, time.UTC))
+		add(dining.ID, "expense", "Restaurant", 100, time.Date(year, month, 5, 12, 0, 0, 0, time.UTC))
+		add(groceries.ID, "expense", "Supermarket", 200, time.Date(year, month, 8, 12, 0, 0, 0, time.UTC))
+	}
+	// Outside the comparable part of February and must be ignored
+	add(dining.ID, "expense", "Restaurant", 500, time.Date(2024, time.February, 25, 12, 0, 0, 0, time.UTC))
+
+	// Current month so far
+	add(salary.ID, "income", "Payroll", 1000, time.Date(2024, time.March, 1, 9, 0, 0, 0, time.UTC))
+	add(dining.ID, "expense", "Restaurant", 90, time.Date(2024, time.March, 5, 12, 0, 0, 0, time.UTC))
+	add(dining.ID, "expense", "Blue Bottle", 50, time.Date(2024, time.March, 6, 8, 0, 0, 0, time.UTC))
+	add(groceries.ID, "expense", "Supermarket", 100, time.Date(2024, time.March, 8, 12, 0, 0, 0, time.UTC))
+
+	return user.ID
+}
+
+func TestInsightsService_GenerateInsights(t *testing.T) {
+	now := time.Date(2024, time.March, 16, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should produce ranked insights compared with previous periods", func(t *testing.T) {
+		db := setupInsightsTestDB(t)
+		userID := createInsightsTestData(t, db)
+		service := NewInsightsService(db)
+
+		report, err := service.generateInsights(context.Background(), userID, "month", now)
+		require.NoError(t, err)
+
+		assert.Equal(t, 3, report.ComparedPeriods)
+		assert.Equal(t, time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), report.StartDate)
+		require.Len(t, report.Insights, 4)
+
+		groceries := report.Insights[0]
+		assert.Equal(t, 1, groceries.Rank)
+		assert.Equal(t, domain.InsightCategoryDrop, groceries.Type)
+		assert.Equal(t, "Groceries", groceries.CategoryName)
+		assert.InDelta(t, -50, groceries.ChangePercent, 0.01)
+		assert.Equal(t, "You spent 50% less on Groceries this month than your average over the previous 3 months.", groceries.Message)
+
+		savings := report.Insights[1]
+		assert.Equal(t, domain.InsightSavingsRateChange, savings.Type)
+		assert.InDelta(t, 76, savings.CurrentValue, 0.01)
+		assert.InDelta(t, 70, savings.PreviousValue, 0.01)
+		assert.Contains(t, savings.Message, "rose to 76%")
+
+		merchant := report.Insights[2]
+		assert.Equal(t, domain.InsightNewMerchant, merchant.Type)
+		assert.Equal(t, "Blue Bottle", merchant.Merchant)
+		assert.Equal(t, 50.0, merchant.CurrentValue)
+
+		dining := report.Insights[3]
+		assert.Equal(t, 4, dining.Rank)
+		assert.Equal(t, domain.InsightCategorySpike, dining.Type)
+		assert.InDelta(t, 40, dining.ChangePercent, 0.01)
+		assert.Equal(t, "You spent 40% more on Dining this month than your average over the previous 3 months.", dining.Message)
+	})
+
+	t.Run("should return no insights without history", func(t *testing.T) {
+		db := setupInsightsTestDB(t)
+		service := NewInsightsService(db)
+		require.NoError(t, db.Create(&domain.Transaction{
+			UserID: 1, CategoryID: 1, Type: "expense", Description: "Coffee", Amount: domain.NewMoney(25),
+			Date: time.Date(2024, time.March, 2, 8, 0, 0, 0, time.UTC),
+		}).Error)
+
+		report, err := service.generateInsights(context.Background(), 1, "month", now)
+		require.NoError(t, err)
+		assert.Equal(t, 0, report.ComparedPeriods)
+		assert.Empty(t, report.Insights)
+	})
+}
+
+func TestInsightsService_Cache(t *testing.T) {
+	db := setupInsightsTestDB(t)
+	service := NewInsightsService(db)
+
+	first, err := service.GetInsights(context.Background(), 1, "month")
+	require.NoError(t, err)
+
+	second, err := service.GetInsights(context.Background(), 1, "month")
+	require.NoError(t, err)
+	assert.Same(t, first, second, "second call should be served from cache")
+
+	service.InvalidateUser(1)
+	third, err := service.GetInsights(context.Background(), 1, "month")
+	require.NoError(t, err)
+	assert.NotSame(t, first, third)
+
+	t.Run("should precompute insights for active users", func(t *testing.T) {
+		require.NoError(t, db.Create(&domain.Transaction{
+			UserID: 7, CategoryID: 1, Type: "expense", Description: "Coffee", Amount: domain.NewMoney(25), Date: time.Now(),
+		}).Error)
+
+		require.NoError(t, service.PrecomputeInsights(context.Background(), "week"))
+		key := insightsCacheKey(7, "week", insightPeriodStart(time.Now(), "week"))
+		assert.NotNil(t, service.cached(key, time.Now()))
+	})
+}
+
+func TestInsightPeriodStart(t *testing.T) {
+	// Thursday
+	now := time.Date(2024, time.August, 15, 18, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		period   string
+		expected time.Time
+	}{
+		{"week", time.Date(2024, time.August, 12, 0, 0, 0, 0, time.UTC)},
+		{"month", time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC)},
+		{"quarter", time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC)},
+		{"year", time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.period, func(t *testing.T) {
+			assert.Equal(t, tt.expected, insightPeriodStart(now, tt.period))
+		})
+	}
+}
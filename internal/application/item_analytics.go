@@ -0,0 +1,53 @@
+package application
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+)
+
+// GetItemAnalysis reports what the user spent on the line items of their
+// expenses between the dates, by item name and by merchant. A query keeps
+// only the items whose name contains it, ignoring case, so "coffee" totals
+// every coffee across merchants. A positive limit keeps only the top items.
+func (s *AnalyticsService) GetItemAnalysis(
+	ctx context.Context, userID uint, query string, startDate, endDate time.Time, limit int,
+) (*domain.ItemAnalysis, error) {
+	query = strings.TrimSpace(query)
+	if len([]rune(query)) > 100 {
+		return nil, &domain.ValidationError{Fields: []domain.FieldError{{Field: "q", Message: "must be at most 100 characters"}}}
+	}
+
+	var purchases []domain.ItemPurchase
+	db := s.DB.WithContext(ctx).Table("transaction_items").
+		Select("transaction_items.transaction_id, transaction_items.name, transaction_items.quantity, transaction_items.amount, "+
+			"transactions.date, transactions.merchant_id, COALESCE(merchants.name, '') AS merchant_name").
+		Joins("JOIN transactions ON transactions.id = transaction_items.transaction_id AND transactions.deleted_at IS NULL").
+		Joins("LEFT JOIN merchants ON merchants.id = transactions.merchant_id").
+		Where("transactions.user_id = ? AND transactions.type = ? AND transactions.date BETWEEN ? AND ?",
+			userID, domain.TransactionTypeExpense, startDate, endDate)
+	if query != "" {
+		db = db.Where("LOWER(transaction_items.name) LIKE ?", "%"+strings.ToLower(query)+"%")
+	}
+	if err := db.Order("transactions.date, transaction_items.position").Scan(&purchases).Error; err != nil {
+		return nil, err
+	}
+
+	analysis := &domain.ItemAnalysis{
+		UserID:    userID,
+		StartDate: startDate,
+		EndDate:   endDate,
+		Query:     query,
+	}
+	for i := range purchases {
+		analysis.TotalAmount += purchases[i].Amount
+		analysis.TotalQuantity += purchases[i].Quantity
+	}
+	analysis.Items, analysis.Merchants = domain.SummarizeItems(purchases)
+	if limit > 0 && len(analysis.Items) > limit {
+		analysis.Items = analysis.Items[:limit]
+	}
+	return analysis, nil
+}
@@ -0,0 +1,312 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultJobMaxAttempts  = 5
+	defaultJobRetryBackoff = 30 * time.Second
+	defaultJobLockTimeout  = 15 * time.Minute
+	maxJobRetryBackoff     = 6 * time.Hour
+)
+
+// JobHandler runs a job of one type with the job's payload. A returned error
+// fails the attempt; the job is retried until it runs out of attempts.
+type JobHandler func(ctx context.Context, payload json.RawMessage) error
+
+// JobService is a queue of background jobs kept in the database, so queued
+// work survives restarts. Services enqueue jobs by type and a pool of
+// workers runs them with the handlers registered for their types.
+type JobService struct {
+	DB           *gorm.DB
+	MaxAttempts  int           // Attempts of each job; defaultJobMaxAttempts when zero
+	RetryBackoff time.Duration // Wait before the first retry, doubling for each further one; defaultJobRetryBackoff when zero
+	LockTimeout  time.Duration // How long a job may run before it counts as abandoned; defaultJobLockTimeout when zero
+
+	mu       sync.RWMutex
+	handlers map[string]JobHandler
+}
+
+func NewJobService(db *gorm.DB) *JobService {
+	return &JobService{DB: db}
+}
+
+// Register sets the handler of a job type. Handlers are registered at
+// startup, before the workers start.
+func (s *JobService) Register(jobType string, handler JobHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.handlers == nil {
+		s.handlers = make(map[string]JobHandler)
+	}
+	s.handlers[jobType] = handler
+}
+
+func (s *JobService) handler(jobType string) (JobHandler, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	handler, ok := s.handlers[jobType]
+	return handler, ok
+}
+
+// Enqueue queues a job to run as soon as a worker is free. The payload is
+// stored as JSON.
+func (s *JobService) Enqueue(ctx context.Context, jobType string, payload any) (*domain.Job, error) {
+	return s.EnqueueAt(ctx, jobType, payload, time.Now())
+}
+
+// EnqueueAt queues a job to run once runAt has passed
+func (s *JobService) EnqueueAt(ctx context.Context, jobType string, payload any, runAt time.Time) (*domain.Job, error) {
+	if _, ok := s.handler(jobType); !ok {
+		return nil, fmt.Errorf("%w: %s", domain.ErrUnknownJobType, jobType)
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	maxAttempts := s.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultJobMaxAttempts
+	}
+	job := &domain.Job{
+		Type: jobType, Payload: data, Status: domain.JobStatusQueued, RunAt: runAt, MaxAttempts: maxAttempts,
+	}
+	if err := s.DB.WithContext(ctx).Create(job).Error; err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// List returns jobs, most recently created first. Dead jobs are listed with
+// the status filter set to dead.
+func (s *JobService) List(ctx context.Context, filter domain.JobFilter) ([]domain.Job, error) {
+	query := s.DB.WithContext(ctx).Model(&domain.Job{})
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	var jobs []domain.Job
+	if err := query.Order("created_at DESC, id DESC").Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (s *JobService) Get(ctx context.Context, id uint) (*domain.Job, error) {
+	var job domain.Job
+	if err := s.DB.WithContext(ctx).First(&job, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// Requeue queues a dead or succeeded job to run again with a fresh set of attempts
+func (s *JobService) Requeue(ctx context.Context, id uint) (*domain.Job, error) {
+	job, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status == domain.JobStatusQueued || job.Status == domain.JobStatusRunning {
+		return nil, domain.ErrJobActive
+	}
+
+	result := s.DB.WithContext(ctx).Model(&domain.Job{}).
+		Where("id = ? AND status = ?", job.ID, job.Status).
+		Updates(map[string]any{
+			"status": domain.JobStatusQueued, "run_at": time.Now(), "attempts": 0,
+			"last_error": "", "locked_at": nil, "finished_at": nil,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, domain.ErrJobActive
+	}
+	return s.Get(ctx, id)
+}
+
+// Start runs the given number of workers until ctx is cancelled. Workers
+// idle for pollInterval when no job is due.
+func (s *JobService) Start(ctx context.Context, workers int, pollInterval time.Duration) {
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.work(ctx, pollInterval)
+		}()
+	}
+
+	// Jobs of workers that died mid-run are picked up again
+	ticker := time.NewTicker(s.lockTimeout())
+	defer ticker.Stop()
+	for {
+		if err := s.reclaim(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("reclaiming abandoned jobs failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *JobService) work(ctx context.Context, pollInterval time.Duration) {
+	for {
+		ran, err := s.RunNext(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("running job failed: %v", err)
+		}
+		if ran {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// RunNext claims the job due longest and runs it. It reports whether there
+// was a job to run; the job's own failure is recorded on it, not returned.
+func (s *JobService) RunNext(ctx context.Context) (bool, error) {
+	job, err := s.claim(ctx)
+	if err != nil || job == nil {
+		return false, err
+	}
+	return true, s.finish(ctx, job, s.run(ctx, job))
+}
+
+// claim marks the next due job as running. Workers race for jobs, and the
+// status check in the update makes sure only one of them wins each.
+func (s *JobService) claim(ctx context.Context) (*domain.Job, error) {
+	db := s.DB.WithContext(ctx)
+	for {
+		now := time.Now()
+		var job domain.Job
+		err := db.Where("status = ? AND run_at <= ?", domain.JobStatusQueued, now).
+			Order("run_at, id").First(&job).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		result := db.Model(&domain.Job{}).
+			Where("id = ? AND status = ?", job.ID, domain.JobStatusQueued).
+			Updates(map[string]any{
+				"status": domain.JobStatusRunning, "locked_at": now, "attempts": gorm.Expr("attempts + 1"),
+			})
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 1 {
+			job.Status, job.LockedAt = domain.JobStatusRunning, &now
+			job.Attempts++
+			return &job, nil
+		}
+	}
+}
+
+func (s *JobService) run(ctx context.Context, job *domain.Job) (err error) {
+	handler, ok := s.handler(job.Type)
+	if !ok {
+		return fmt.Errorf("%w: %s", domain.ErrUnknownJobType, job.Type)
+	}
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("job panicked: %v", recovered)
+		}
+	}()
+	return handler(ctx, job.Payload)
+}
+
+// finish records the outcome of a run: success, a retry after a backoff, or
+// a dead job once its attempts are used up
+func (s *JobService) finish(ctx context.Context, job *domain.Job, runErr error) error {
+	now := time.Now()
+	updates := map[string]any{"locked_at": nil}
+	switch {
+	case runErr == nil:
+		updates["status"], updates["finished_at"], updates["last_error"] = domain.JobStatusSucceeded, now, ""
+	case job.Attempts >= job.MaxAttempts || errors.Is(runErr, domain.ErrUnknownJobType):
+		updates["status"], updates["finished_at"], updates["last_error"] = domain.JobStatusDead, now, runErr.Error()
+		log.Printf("job %d (%s) is dead after %d attempt(s): %v", job.ID, job.Type, job.Attempts, runErr)
+	default:
+		updates["status"], updates["run_at"], updates["last_error"] = domain.JobStatusQueued, now.Add(s.backoff(job.Attempts)), runErr.Error()
+	}
+
+	// A cancelled run is recorded all the same, so the job is not left running
+	return s.DB.WithContext(context.WithoutCancel(ctx)).Model(&domain.Job{}).
+		Where("id = ? AND status = ?", job.ID, domain.JobStatusRunning).
+		Updates(updates).Error
+}
+
+// backoff is the wait before the retry following the given attempt
+func (s *JobService) backoff(attempt int) time.Duration {
+	backoff := s.RetryBackoff
+	if backoff <= 0 {
+		backoff = defaultJobRetryBackoff
+	}
+	for i := 1; i < attempt && backoff < maxJobRetryBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxJobRetryBackoff)
+}
+
+func (s *JobService) lockTimeout() time.Duration {
+	if s.LockTimeout > 0 {
+		return s.LockTimeout
+	}
+	return defaultJobLockTimeout
+}
+
+// reclaim queues running jobs again once they have been running longer than
+// the lock timeout, or marks them dead when that was their last attempt
+func (s *JobService) reclaim(ctx context.Context) error {
+	db := s.DB.WithContext(ctx)
+	cutoff := time.Now().Add(-s.lockTimeout())
+	abandoned := "abandoned by its worker"
+
+	if err := db.Model(&domain.Job{}).
+		Where("status = ? AND locked_at < ? AND attempts >= max_attempts", domain.JobStatusRunning, cutoff).
+		Updates(map[string]any{
+			"status": domain.JobStatusDead, "locked_at": nil, "finished_at": time.Now(), "last_error": abandoned,
+		}).Error; err != nil {
+		return err
+	}
+	return db.Model(&domain.Job{}).
+		Where("status = ? AND locked_at < ?", domain.JobStatusRunning, cutoff).
+		Updates(map[string]any{
+			"status": domain.JobStatusQueued, "locked_at": nil, "run_at": time.Now(), "last_error": abandoned,
+		}).Error
+}
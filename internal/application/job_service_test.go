@@ -0,0 +1,186 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupJobTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	// Workers share the one in-memory database
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&domain.Job{}))
+	return db
+}
+
+func TestJobService_RunNext(t *testing.T) {
+	db := setupJobTestDB(t)
+	service := &JobService{DB: db, MaxAttempts: 2, RetryBackoff: time.Nanosecond}
+	ctx := context.Background()
+
+	var received []string
+	service.Register("greet", func(_ context.Context, payload json.RawMessage) error {
+		var body struct{ Name string }
+		if err := json.Unmarshal(payload, &body); err != nil {
+			return err
+		}
+		received = append(received, body.Name)
+		return nil
+	})
+	service.Register("fail", func(context.Context, json.RawMessage) error { return errors.New("upstream is down") })
+	service.Register("panic", func(context.Context, json.RawMessage) error { panic("nil map") })
+
+	t.Run("should refuse job types without a handler", func(t *testing.T) {
+		_, err := service.Enqueue(ctx, "unknown", nil)
+		assert.ErrorIs(t, err, domain.ErrUnknownJobType)
+	})
+
+	t.Run("should run due jobs in order", func(t *testing.T) {
+		_, err := service.Enqueue(ctx, "greet", map[string]string{"name": "first"})
+		require.NoError(t, err)
+		later, err := service.EnqueueAt(ctx, "greet", map[string]string{"name": "later"}, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		_, err = service.Enqueue(ctx, "greet", map[string]string{"name": "second"})
+		require.NoError(t, err)
+
+		for ran := true; ran; {
+			ran, err = service.RunNext(ctx)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, []string{"first", "second"}, received)
+
+		job, err := service.Get(ctx, later.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.JobStatusQueued, job.Status, "jobs are not run before they are due")
+	})
+
+	t.Run("should retry failed jobs until they are dead", func(t *testing.T) {
+		job, err := service.Enqueue(ctx, "fail", nil)
+		require.NoError(t, err)
+
+		_, err = service.RunNext(ctx)
+		require.NoError(t, err)
+		retried, err := service.Get(ctx, job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.JobStatusQueued, retried.Status)
+		assert.Equal(t, 1, retried.Attempts)
+		assert.Equal(t, "upstream is down", retried.LastError)
+
+		_, err = service.RunNext(ctx)
+		require.NoError(t, err)
+		dead, err := service.Get(ctx, job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.JobStatusDead, dead.Status)
+		assert.Equal(t, 2, dead.Attempts)
+		assert.NotNil(t, dead.FinishedAt)
+
+		jobs, err := service.List(ctx, domain.JobFilter{Status: domain.JobStatusDead})
+		require.NoError(t, err)
+		require.Len(t, jobs, 1)
+		assert.Equal(t, job.ID, jobs[0].ID)
+	})
+
+	t.Run("should record panics as failures", func(t *testing.T) {
+		job, err := service.Enqueue(ctx, "panic", nil)
+		require.NoError(t, err)
+		_, err = service.RunNext(ctx)
+		require.NoError(t, err)
+
+		failed, err := service.Get(ctx, job.ID)
+		require.NoError(t, err)
+		assert.Contains(t, failed.LastError, "nil map")
+	})
+
+	t.Run("should requeue dead jobs only", func(t *testing.T) {
+		dead, err := service.List(ctx, domain.JobFilter{Type: "fail", Status: domain.JobStatusDead})
+		require.NoError(t, err)
+		require.Len(t, dead, 1)
+
+		requeued, err := service.Requeue(ctx, dead[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.JobStatusQueued, requeued.Status)
+		assert.Zero(t, requeued.Attempts)
+		assert.Empty(t, requeued.LastError)
+
+		_, err = service.Requeue(ctx, dead[0].ID)
+		assert.ErrorIs(t, err, domain.ErrJobActive)
+		_, err = service.Requeue(ctx, 999)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
+
+func TestJobService_Reclaim(t *testing.T) {
+	db := setupJobTestDB(t)
+	service := &JobService{DB: db, LockTimeout: time.Minute}
+	ctx := context.Background()
+
+	stale := time.Now().Add(-time.Hour)
+	retry := domain.Job{Type: "sync", Status: domain.JobStatusRunning, RunAt: stale, Attempts: 1, MaxAttempts: 3, LockedAt: &stale}
+	last := domain.Job{Type: "sync", Status: domain.JobStatusRunning, RunAt: stale, Attempts: 3, MaxAttempts: 3, LockedAt: &stale}
+	now := time.Now()
+	running := domain.Job{Type: "sync", Status: domain.JobStatusRunning, RunAt: now, Attempts: 1, MaxAttempts: 3, LockedAt: &now}
+	for _, job := range []*domain.Job{&retry, &last, &running} {
+		require.NoError(t, db.Create(job).Error)
+	}
+
+	require.NoError(t, service.reclaim(ctx))
+
+	for job, status := range map[uint]string{
+		retry.ID: domain.JobStatusQueued, last.ID: domain.JobStatusDead, running.ID: domain.JobStatusRunning,
+	} {
+		reclaimed, err := service.Get(ctx, job)
+		require.NoError(t, err)
+		assert.Equal(t, status, reclaimed.Status, "job %d", job)
+	}
+}
+
+func TestJobService_Start(t *testing.T) {
+	db := setupJobTestDB(t)
+	service := NewJobService(db)
+	var runs atomic.Int32
+	service.Register("count", func(context.Context, json.RawMessage) error {
+		runs.Add(1)
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	for i := 0; i < 5; i++ {
+		_, err := service.Enqueue(ctx, "count", i)
+		require.NoError(t, err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		service.Start(ctx, 3, 10*time.Millisecond)
+		close(done)
+	}()
+	assert.Eventually(t, func() bool { return runs.Load() == 5 }, 2*time.Second, 10*time.Millisecond)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("workers did not stop")
+	}
+
+	jobs, err := service.List(context.Background(), domain.JobFilter{Status: domain.JobStatusSucceeded})
+	require.NoError(t, err)
+	assert.Len(t, jobs, 5)
+}
@@ -0,0 +1,231 @@
+package application
+
+import (
+	"context"
+	"errors"
+
+	"go-finance-advisor/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// LoanService keeps users' loans, builds their amortization schedules and
+// projects their payoff from the payment transactions linked to them
+type LoanService struct {
+	DB *gorm.DB
+}
+
+func NewLoanService(db *gorm.DB) *LoanService {
+	return &LoanService{DB: db}
+}
+
+// Create adds a loan for the user
+func (s *LoanService) Create(ctx context.Context, userID uint, loan *domain.Loan) error {
+	if err := loan.Validate(); err != nil {
+		return err
+	}
+	loan.ID, loan.UserID = 0, userID
+	return s.DB.WithContext(ctx).Create(loan).Error
+}
+
+// List returns the user's loans by start date
+func (s *LoanService) List(ctx context.Context, userID uint) ([]domain.Loan, error) {
+	var loans []domain.Loan
+	err := s.DB.WithContext(ctx).Where("user_id = ?", userID).Order("start_date, id").Find(&loans).Error
+	return loans, err
+}
+
+// Get returns one of the user's loans
+func (s *LoanService) Get(ctx context.Context, userID, id uint) (*domain.Loan, error) {
+	var loan domain.Loan
+	err := s.DB.WithContext(ctx).Where("user_id = ?", userID).First(&loan, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &loan, nil
+}
+
+// Update saves the details and terms of one of the user's loans
+func (s *LoanService) Update(ctx context.Context, userID uint, loan *domain.Loan) error {
+	if err := loan.Validate(); err != nil {
+		return err
+	}
+	result := s.DB.WithContext(ctx).Model(&domain.Loan{}).
+		Where("id = ? AND user_id = ?", loan.ID, userID).
+		Updates(map[string]any{
+			"name": loan.Name, "lender": loan.Lender, "principal": loan.Principal, "apr": loan.APR,
+			"term_months": loan.TermMonths, "start_date": loan.StartDate,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// Delete removes one of the user's loans and its payment links; the linked
+// transactions stay
+func (s *LoanService) Delete(ctx context.Context, userID, id uint) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND user_id = ?", id, userID).Delete(&domain.Loan{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrNotFound
+		}
+		return tx.Where("loan_id = ?", id).Delete(&domain.LoanPayment{}).Error
+	})
+}
+
+// Schedule returns the amortization schedule of one of the user's loans,
+// with the rows paid by linked transactions showing what they paid
+func (s *LoanService) Schedule(ctx context.Context, userID, loanID uint) (*domain.LoanSchedule, error) {
+	loan, err := s.Get(ctx, userID, loanID)
+	if err != nil {
+		return nil, err
+	}
+	paid, transactions, err := s.paid(ctx, userID, loanID)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := domain.Amortize(loan, paid, domain.LoanExtraPayments{})
+	for i := range schedule.Rows {
+		if id, ok := transactions[schedule.Rows[i].Number]; ok {
+			schedule.Rows[i].TransactionID = &id
+		}
+	}
+	return &schedule, nil
+}
+
+// Payoff projects when one of the user's loans is paid off given the
+// payments made, with the extra payments on top of the ones still to make
+func (s *LoanService) Payoff(ctx context.Context, userID, loanID uint, extra domain.LoanExtraPayments) (*domain.LoanPayoffProjection, error) {
+	loan, err := s.Get(ctx, userID, loanID)
+	if err != nil {
+		return nil, err
+	}
+	if err := extra.Validate(loan.TermMonths); err != nil {
+		return nil, err
+	}
+	paid, _, err := s.paid(ctx, userID, loanID)
+	if err != nil {
+		return nil, err
+	}
+
+	projection := domain.ProjectLoanPayoff(loan, paid, extra)
+	return &projection, nil
+}
+
+// LinkPayment records that one of the user's expense transactions paid row
+// number of the loan's schedule, or the first row not paid yet when number
+// is 0
+func (s *LoanService) LinkPayment(ctx context.Context, userID, loanID, transactionID uint, number int) (*domain.LoanPayment, error) {
+	loan, err := s.Get(ctx, userID, loanID)
+	if err != nil {
+		return nil, err
+	}
+
+	var transactions int64
+	err = s.DB.WithContext(ctx).Model(&domain.Transaction{}).
+		Where("id = ? AND user_id = ? AND type = ?", transactionID, userID, domain.TransactionTypeExpense).
+		Count(&transactions).Error
+	if err != nil {
+		return nil, err
+	}
+	if transactions == 0 {
+		return nil, &domain.ValidationError{Fields: []domain.FieldError{{Field: "transaction_id", Message: "does not exist"}}}
+	}
+	var linked int64
+	if err := s.DB.WithContext(ctx).Model(&domain.LoanPayment{}).Where("transaction_id = ?", transactionID).Count(&linked).Error; err != nil {
+		return nil, err
+	}
+	if linked > 0 {
+		return nil, &domain.ValidationError{Fields: []domain.FieldError{{Field: "transaction_id", Message: "already pays a loan"}}}
+	}
+
+	var numbers []int
+	err = s.DB.WithContext(ctx).Model(&domain.LoanPayment{}).Where("loan_id = ?", loanID).Order("number").Pluck("number", &numbers).Error
+	if err != nil {
+		return nil, err
+	}
+	if number == 0 {
+		number = 1
+		for _, paid := range numbers {
+			if paid == number {
+				number++
+			}
+		}
+	}
+	var v domain.ValidationError
+	if number < 1 || number > loan.TermMonths {
+		v.Fields = append(v.Fields, domain.FieldError{Field: "number", Message: "must be the number of a payment within the loan's term"})
+	}
+	for _, paid := range numbers {
+		if paid == number {
+			v.Fields = append(v.Fields, domain.FieldError{Field: "number", Message: "is already paid"})
+		}
+	}
+	if len(v.Fields) > 0 {
+		return nil, &v
+	}
+
+	payment := &domain.LoanPayment{UserID: userID, LoanID: loanID, Number: number, TransactionID: transactionID}
+	if err := s.DB.WithContext(ctx).Create(payment).Error; err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+// UnlinkPayment removes the transaction linked to row number of one of the
+// user's loans
+func (s *LoanService) UnlinkPayment(ctx context.Context, userID, loanID uint, number int) error {
+	result := s.DB.WithContext(ctx).Where("loan_id = ? AND user_id = ? AND number = ?", loanID, userID, number).
+		Delete(&domain.LoanPayment{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// paid returns the amounts and IDs of the transactions linked to the loan,
+// by payment number. Links to transactions in the trash are left out.
+func (s *LoanService) paid(ctx context.Context, userID, loanID uint) (map[int]domain.Money, map[int]uint, error) {
+	var payments []domain.LoanPayment
+	if err := s.DB.WithContext(ctx).Where("loan_id = ?", loanID).Find(&payments).Error; err != nil {
+		return nil, nil, err
+	}
+	if len(payments) == 0 {
+		return map[int]domain.Money{}, map[int]uint{}, nil
+	}
+
+	ids := make([]uint, len(payments))
+	for i, payment := range payments {
+		ids[i] = payment.TransactionID
+	}
+	var transactions []domain.Transaction
+	if err := s.DB.WithContext(ctx).Where("id IN ? AND user_id = ?", ids, userID).Find(&transactions).Error; err != nil {
+		return nil, nil, err
+	}
+	amounts := make(map[uint]domain.Money, len(transactions))
+	for _, transaction := range transactions {
+		amounts[transaction.ID] = transaction.Amount.Abs()
+	}
+
+	paid, linked := make(map[int]domain.Money, len(payments)), make(map[int]uint, len(payments))
+	for _, payment := range payments {
+		if amount, ok := amounts[payment.TransactionID]; ok {
+			paid[payment.Number], linked[payment.Number] = amount, payment.TransactionID
+		}
+	}
+	return paid, linked, nil
+}
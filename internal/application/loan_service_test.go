@@ -0,0 +1,110 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupLoanTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&domain.Loan{}, &domain.LoanPayment{}, &domain.Category{}, &domain.Transaction{}))
+	return db
+}
+
+func TestLoanService(t *testing.T) {
+	db := setupLoanTestDB(t)
+	service := NewLoanService(db)
+	ctx := context.Background()
+
+	start := time.Date(2025, time.January, 15, 0, 0, 0, 0, time.UTC)
+	loan := &domain.Loan{Name: "Car", Principal: domain.NewMoney(10000), APR: 6, TermMonths: 12, StartDate: start}
+	require.NoError(t, service.Create(ctx, 1, loan))
+
+	t.Run("rejects invalid loans and scopes to the user", func(t *testing.T) {
+		var validationErr *domain.ValidationError
+		assert.ErrorAs(t, service.Create(ctx, 1, &domain.Loan{Name: "Bike", TermMonths: 12, StartDate: start}), &validationErr)
+
+		_, err := service.Get(ctx, 2, loan.ID)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		_, err = service.Schedule(ctx, 2, loan.ID)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	car := domain.Category{Name: "Car", Type: domain.TransactionTypeExpense}
+	require.NoError(t, db.Create(&car).Error)
+	payments := []domain.Transaction{
+		{UserID: 1, CategoryID: car.ID, Type: domain.TransactionTypeExpense, Amount: domain.NewMoney(860.66), Date: loan.DueDate(1)},
+		{UserID: 1, CategoryID: car.ID, Type: domain.TransactionTypeExpense, Amount: domain.NewMoney(1860.66), Date: loan.DueDate(2)},
+		{UserID: 2, CategoryID: car.ID, Type: domain.TransactionTypeExpense, Amount: domain.NewMoney(860.66), Date: loan.DueDate(1)},
+	}
+	require.NoError(t, db.Create(&payments).Error)
+
+	t.Run("links payments to the first unpaid row", func(t *testing.T) {
+		payment, err := service.LinkPayment(ctx, 1, loan.ID, payments[0].ID, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 1, payment.Number)
+		payment, err = service.LinkPayment(ctx, 1, loan.ID, payments[1].ID, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 2, payment.Number)
+	})
+
+	t.Run("rejects other users' transactions, relinks and rows out of the term", func(t *testing.T) {
+		var validationErr *domain.ValidationError
+		_, err := service.LinkPayment(ctx, 1, loan.ID, payments[2].ID, 3)
+		assert.ErrorAs(t, err, &validationErr)
+		_, err = service.LinkPayment(ctx, 1, loan.ID, payments[0].ID, 3)
+		assert.ErrorAs(t, err, &validationErr)
+
+		extra := domain.Transaction{UserID: 1, CategoryID: car.ID, Type: domain.TransactionTypeExpense, Amount: domain.NewMoney(10)}
+		require.NoError(t, db.Create(&extra).Error)
+		_, err = service.LinkPayment(ctx, 1, loan.ID, extra.ID, 13)
+		assert.ErrorAs(t, err, &validationErr)
+		_, err = service.LinkPayment(ctx, 1, loan.ID, extra.ID, 1)
+		assert.ErrorAs(t, err, &validationErr)
+	})
+
+	t.Run("schedules with the linked payments", func(t *testing.T) {
+		schedule, err := service.Schedule(ctx, 1, loan.ID)
+		require.NoError(t, err)
+		require.NotNil(t, schedule.Rows[0].TransactionID)
+		assert.Equal(t, payments[0].ID, *schedule.Rows[0].TransactionID)
+		assert.Equal(t, domain.NewMoney(1000), schedule.Rows[1].Extra)
+		assert.Nil(t, schedule.Rows[2].TransactionID)
+		assert.Less(t, schedule.Payments, 12)
+	})
+
+	t.Run("projects the payoff with extra payments", func(t *testing.T) {
+		projection, err := service.Payoff(ctx, 1, loan.ID, domain.LoanExtraPayments{Monthly: domain.NewMoney(300)})
+		require.NoError(t, err)
+		assert.Equal(t, 2, projection.PaymentsMade)
+		assert.Positive(t, projection.MonthsSaved)
+		assert.True(t, projection.PayoffDate.Before(projection.ScheduledPayoffDate))
+
+		var validationErr *domain.ValidationError
+		_, err = service.Payoff(ctx, 1, loan.ID, domain.LoanExtraPayments{Monthly: -1})
+		assert.ErrorAs(t, err, &validationErr)
+	})
+
+	t.Run("unlinks payments and deletes loans with their links", func(t *testing.T) {
+		require.NoError(t, service.UnlinkPayment(ctx, 1, loan.ID, 2))
+		assert.ErrorIs(t, service.UnlinkPayment(ctx, 1, loan.ID, 2), domain.ErrNotFound)
+
+		require.NoError(t, service.Delete(ctx, 1, loan.ID))
+		assert.ErrorIs(t, service.Delete(ctx, 1, loan.ID), domain.ErrNotFound)
+		var links int64
+		require.NoError(t, db.Model(&domain.LoanPayment{}).Count(&links).Error)
+		assert.Zero(t, links)
+	})
+}
@@ -0,0 +1,250 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"go-finance-advisor/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// rematchBatchSize is the number of transactions Rematch loads at a time
+const rematchBatchSize = 500
+
+// MerchantService maps raw transaction descriptions to normalized merchants.
+// Descriptions no rule matches get a merchant named after their cleaned-up
+// description, so every expense can be grouped.
+type MerchantService struct {
+	DB *gorm.DB
+}
+
+func NewMerchantService(db *gorm.DB) *MerchantService {
+	return &MerchantService{DB: db}
+}
+
+// InitializeDefaultMerchants creates the well-known merchants and their rules if they don't exist
+func (s *MerchantService) InitializeDefaultMerchants(ctx context.Context) error {
+	for _, merchant := range domain.GetDefaultMerchants() {
+		var existing domain.Merchant
+		err := s.DB.WithContext(ctx).Where("name = ?", merchant.Name).First(&existing).Error
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if err := s.DB.WithContext(ctx).Create(&merchant).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListMerchants returns all merchants with their rules, ordered by name
+func (s *MerchantService) ListMerchants(ctx context.Context) ([]domain.Merchant, error) {
+	var merchants []domain.Merchant
+	err := s.DB.WithContext(ctx).Preload("Rules").Order("name").Find(&merchants).Error
+	return merchants, err
+}
+
+// AddMerchant creates a merchant, or extends an existing one with the same
+// name, with the given rules. Call Rematch afterwards to apply new rules to
+// existing transactions.
+func (s *MerchantService) AddMerchant(ctx context.Context, name string, rules []domain.MerchantRule) (*domain.Merchant, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("merchant name is required")
+	}
+	for i := range rules {
+		if domain.NormalizeDescription(rules[i].Pattern) == "" {
+			return nil, fmt.Errorf("rule pattern %q has no letters to match", rules[i].Pattern)
+		}
+		if rules[i].MatchType == "" {
+			rules[i].MatchType = domain.MerchantMatchContains
+		}
+		if !domain.IsValidMerchantMatchType(rules[i].MatchType) {
+			return nil, fmt.Errorf("invalid match type %q (use prefix or contains)", rules[i].MatchType)
+		}
+	}
+
+	merchant := &domain.Merchant{}
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where(domain.Merchant{Name: name}).FirstOrCreate(merchant).Error; err != nil {
+			return err
+		}
+		for i := range rules {
+			rules[i].ID = 0
+			rules[i].MerchantID = merchant.ID
+		}
+		if len(rules) > 0 {
+			if err := tx.Create(&rules).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Preload("Rules").First(merchant, merchant.ID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return merchant, nil
+}
+
+// Resolve returns the merchant for a description, creating one from the
+// cleaned description when no rule matches. It returns nil for descriptions
+// with nothing recognizable in them.
+func (s *MerchantService) Resolve(ctx context.Context, description string) (*domain.Merchant, error) {
+	rules, err := s.rules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.resolveWith(ctx, rules, description)
+}
+
+// Rematch reassigns merchants for the user's transactions, or everyone's
+// when userID is zero, and returns how many transactions changed
+func (s *MerchantService) Rematch(ctx context.Context, userID uint) (int64, error) {
+	rules, err := s.rules(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	query := s.DB.WithContext(ctx).Model(&domain.Transaction{})
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	// Statements often repeat the same description, so resolve each only once
+	resolved := make(map[string]*uint)
+	var changed int64
+	var batch []domain.Transaction
+	rematchBatch := func(_ *gorm.DB, _ int) error {
+		for i := range batch {
+			merchantID, ok := resolved[batch[i].Description]
+			if !ok {
+				merchant, resolveErr := s.resolveWith(ctx, rules, batch[i].Description)
+				if resolveErr != nil {
+					return resolveErr
+				}
+				if merchant != nil {
+					merchantID = &merchant.ID
+				}
+				resolved[batch[i].Description] = merchantID
+			}
+			if sameMerchant(batch[i].MerchantID, merchantID) {
+				continue
+			}
+			err := s.DB.WithContext(ctx).Model(&domain.Transaction{}).Where("id = ?", batch[i].ID).
+				UpdateColumn("merchant_id", merchantID).Error
+			if err != nil {
+				return err
+			}
+			changed++
+		}
+		return nil
+	}
+	result := query.Select("id", "description", "merchant_id").FindInBatches(&batch, rematchBatchSize, rematchBatch)
+	return changed, result.Error
+}
+
+// assign sets the transaction's merchant from its description. Failures are
+// logged and leave the transaction unassigned rather than failing the write.
+func (s *MerchantService) assign(ctx context.Context, transaction *domain.Transaction) {
+	if s == nil || s.DB == nil {
+		return
+	}
+	merchant, err := s.Resolve(ctx, transaction.Description)
+	if err != nil {
+		log.Printf("merchants: failed to resolve %q: %v", transaction.Description, err)
+		return
+	}
+	transaction.MerchantID = nil
+	if merchant != nil {
+		transaction.MerchantID = &merchant.ID
+		s.locate(ctx, transaction, merchant)
+	}
+}
+
+// locate fills in where a transaction was made from its merchant. Located
+// transactions without a place are named after the merchant; transactions
+// without a location, such as imported ones, take the place of the user's
+// latest located transaction at the merchant. Failures leave it as it is.
+func (s *MerchantService) locate(ctx context.Context, transaction *domain.Transaction, merchant *domain.Merchant) {
+	if transaction.Latitude != nil || transaction.Place != "" {
+		if transaction.Place == "" {
+			transaction.Place = merchant.Name
+		}
+		return
+	}
+
+	var last domain.Transaction
+	err := s.DB.WithContext(ctx).Select("latitude", "longitude", "place", "city").
+		Where("user_id = ? AND merchant_id = ? AND place <> '' AND id <> ?", transaction.UserID, merchant.ID, transaction.ID).
+		Order("date DESC").Order("id DESC").First(&last).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return
+	}
+	if err != nil {
+		log.Printf("merchants: failed to look up the location of %s: %v", merchant.Name, err)
+		return
+	}
+	transaction.Latitude, transaction.Longitude = last.Latitude, last.Longitude
+	transaction.Place, transaction.City = last.Place, last.City
+}
+
+func (s *MerchantService) rules(ctx context.Context) ([]domain.MerchantRule, error) {
+	var rules []domain.MerchantRule
+	err := s.DB.WithContext(ctx).Find(&rules).Error
+	return rules, err
+}
+
+func (s *MerchantService) resolveWith(ctx context.Context, rules []domain.MerchantRule, description string) (*domain.Merchant, error) {
+	var merchant domain.Merchant
+	if rule := domain.BestMerchantRule(rules, description); rule != nil {
+		if err := s.DB.WithContext(ctx).First(&merchant, rule.MerchantID).Error; err != nil {
+			return nil, err
+		}
+		return &merchant, nil
+	}
+
+	name := domain.CleanMerchantName(description)
+	if name == "" {
+		return nil, nil
+	}
+	if err := s.DB.WithContext(ctx).Where(domain.Merchant{Name: name}).FirstOrCreate(&merchant).Error; err != nil {
+		return nil, err
+	}
+	return &merchant, nil
+}
+
+// find returns the ID of the merchant a description belongs to like
+// resolveWith does, but without creating merchants. It returns nil when the
+// merchant is not known yet.
+func (s *MerchantService) find(ctx context.Context, rules []domain.MerchantRule, description string) (*uint, error) {
+	if rule := domain.BestMerchantRule(rules, description); rule != nil {
+		return &rule.MerchantID, nil
+	}
+	name := domain.CleanMerchantName(description)
+	if name == "" {
+		return nil, nil
+	}
+	var merchant domain.Merchant
+	err := s.DB.WithContext(ctx).Select("id").Where("name = ?", name).First(&merchant).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &merchant.ID, nil
+}
+
+func sameMerchant(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
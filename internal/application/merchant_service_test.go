@@ -0,0 +1,217 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupMerchantTestDB(t *testing.T) *gorm.DB {
+	db := setupTransactionTestDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.Merchant{}, &domain.MerchantRule{}))
+	require.NoError(t, NewMerchantService(db).InitializeDefaultMerchants(context.Background()))
+	return db
+}
+
+func TestMerchantService_Resolve(t *testing.T) {
+	db := setupMerchantTestDB(t)
+	service := NewMerchantService(db)
+	ctx := context.Background()
+
+	tests := []struct {
+		description string
+		want        string
+	}{
+		{"AMZN Mktp US*2F4", "Amazon"},
+		{"UBER *TRIP HELP.UBER.COM", "Uber"},
+		{"UBER EATS 8005928996", "Uber Eats"},
+		{"SQ *BLUE BOTTLE COFFEE 0042", "Blue Bottle Coffee"},
+		{"Corner Grocery #118", "Corner Grocery"},
+	}
+	for _, tt := range tests {
+		merchant, err := service.Resolve(ctx, tt.description)
+		require.NoError(t, err)
+		require.NotNil(t, merchant, tt.description)
+		assert.Equal(t, tt.want, merchant.Name, tt.description)
+	}
+
+	// Unknown descriptions resolve to the same merchant every time
+	first, err := service.Resolve(ctx, "Corner Grocery #7")
+	require.NoError(t, err)
+	second, err := service.Resolve(ctx, "CORNER GROCERY - 0931")
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, second.ID)
+
+	merchant, err := service.Resolve(ctx, "12345 #99")
+	require.NoError(t, err)
+	assert.Nil(t, merchant)
+
+	// Seeding twice does not duplicate merchants
+	require.NoError(t, service.InitializeDefaultMerchants(ctx))
+	var amazons int64
+	require.NoError(t, db.Model(&domain.Merchant{}).Where("name = ?", "Amazon").Count(&amazons).Error)
+	assert.Equal(t, int64(1), amazons)
+}
+
+func TestMerchantService_AddMerchantAndRematch(t *testing.T) {
+	db := setupMerchantTestDB(t)
+	service := NewMerchantService(db)
+	txService := &TransactionService{DB: db, Merchants: service}
+	ctx := context.Background()
+	userID, _, categoryID := createTestData(t, db)
+
+	transaction := &domain.Transaction{
+		UserID: userID, CategoryID: categoryID, Type: "expense", Description: "BB COFFEE 0042", Amount: domain.NewMoney(6), Date: time.Now(),
+	}
+	require.NoError(t, txService.Create(ctx, transaction))
+	require.NotNil(t, transaction.MerchantID)
+
+	_, err := service.AddMerchant(ctx, "Blue Bottle", []domain.MerchantRule{{Pattern: "bb coffee", MatchType: "regex"}})
+	assert.Error(t, err)
+
+	merchant, err := service.AddMerchant(ctx, "Blue Bottle", []domain.MerchantRule{{Pattern: "bb coffee"}})
+	require.NoError(t, err)
+	require.Len(t, merchant.Rules, 1)
+	assert.Equal(t, domain.MerchantMatchContains, merchant.Rules[0].MatchType)
+
+	updated, err := service.Rematch(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), updated)
+
+	stored, err := txService.GetByID(ctx, transaction.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.MerchantID)
+	assert.Equal(t, merchant.ID, *stored.MerchantID)
+
+	// Nothing changes when the rules are applied again
+	updated, err = service.Rematch(ctx, 0)
+	require.NoError(t, err)
+	assert.Zero(t, updated)
+}
+
+func TestAnalyticsService_GetMerchantAnalysis(t *testing.T) {
+	db := setupMerchantTestDB(t)
+	txService := &TransactionService{DB: db, Merchants: NewMerchantService(db)}
+	analytics := &AnalyticsService{DB: db}
+	ctx := context.Background()
+	userID, incomeCategoryID, expenseCategoryID := createTestData(t, db)
+
+	jan := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2024, 2, 12, 0, 0, 0, 0, time.UTC)
+	for _, tx := range []*domain.Transaction{
+		{UserID: userID, CategoryID: expenseCategoryID, Type: "expense", Description: "AMZN Mktp US*2F4", Amount: domain.NewMoney(40), Date: jan},
+		{UserID: userID, CategoryID: expenseCategoryID, Type: "expense", Description: "Amazon.com*RT4", Amount: domain.NewMoney(60), Date: feb},
+		{UserID: userID, CategoryID: expenseCategoryID, Type: "expense", Description: "NETFLIX.COM", Amount: domain.NewMoney(15), Date: feb},
+		{UserID: userID, CategoryID: expenseCategoryID, Type: "expense", Description: "0042", Amount: domain.NewMoney(5), Date: feb},
+		{UserID: userID, CategoryID: incomeCategoryID, Type: "income", Description: "ACME PAYROLL", Amount: domain.NewMoney(3000), Date: feb},
+	} {
+		require.NoError(t, txService.Create(ctx, tx))
+	}
+
+	analysis, err := analytics.GetMerchantAnalysis(ctx, userID, jan.AddDate(0, 0, -9), feb.AddDate(0, 1, 0), 0)
+	require.NoError(t, err)
+	assert.InDelta(t, 120, analysis.TotalExpenses, 0.001)
+	assert.InDelta(t, 5, analysis.UnassignedAmount, 0.001)
+	require.Len(t, analysis.Merchants, 2)
+
+	amazon := analysis.Merchants[0]
+	assert.Equal(t, "Amazon", amazon.MerchantName)
+	assert.InDelta(t, 100, amazon.TotalAmount, 0.001)
+	assert.Equal(t, 2, amazon.TransactionCount)
+	assert.InDelta(t, 50, amazon.AverageAmount, 0.001)
+	assert.Equal(t, []domain.MonthlyAmount{{Month: "2024-01", Amount: 40}, {Month: "2024-02", Amount: 60}}, amazon.MonthlyAmounts)
+	assert.Equal(t, "Netflix", analysis.Merchants[1].MerchantName)
+
+	top, err := analytics.GetMerchantAnalysis(ctx, userID, jan.AddDate(0, 0, -9), feb.AddDate(0, 1, 0), 1)
+	require.NoError(t, err)
+	assert.Len(t, top.Merchants, 1)
+}
+
+func TestAnalyticsService_GetPlaceAnalysis(t *testing.T) {
+	db := setupMerchantTestDB(t)
+	txService := &TransactionService{DB: db, Merchants: NewMerchantService(db)}
+	analytics := &AnalyticsService{DB: db}
+	ctx := context.Background()
+	userID, _, expenseCategoryID := createTestData(t, db)
+
+	day := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+	lat, lon := 47.6097, -122.3422
+	located := &domain.Transaction{UserID: userID, CategoryID: expenseCategoryID, Type: "expense", Description: "STARBUCKS #1912",
+		Amount: domain.NewMoney(6), Date: day, Latitude: &lat, Longitude: &lon, City: " Seattle "}
+	require.NoError(t, txService.Create(ctx, located))
+	assert.Equal(t, "Starbucks", located.Place, "located transactions are named after their merchant")
+	assert.Equal(t, "Seattle", located.City)
+
+	imported := &domain.Transaction{UserID: userID, CategoryID: expenseCategoryID, Type: "expense", Description: "STARBUCKS STORE 1912",
+		Amount: domain.NewMoney(4), Date: day.AddDate(0, 0, 1)}
+	require.NoError(t, txService.Create(ctx, imported))
+	assert.Equal(t, "Starbucks", imported.Place, "unlocated transactions take the merchant's last place")
+	assert.Equal(t, "Seattle", imported.City)
+	require.NotNil(t, imported.Latitude)
+	assert.Equal(t, lat, *imported.Latitude)
+
+	online := &domain.Transaction{UserID: userID, CategoryID: expenseCategoryID, Type: "expense", Description: "NETFLIX.COM",
+		Amount: domain.NewMoney(15), Date: day}
+	require.NoError(t, txService.Create(ctx, online))
+	assert.Empty(t, online.Place)
+
+	_, err := analytics.GetPlaceAnalysis(ctx, userID, "country", day, day.AddDate(0, 1, 0), 0)
+	assert.ErrorIs(t, err, domain.ErrValidation)
+
+	analysis, err := analytics.GetPlaceAnalysis(ctx, userID, domain.PlaceGroupPlace, day, day.AddDate(0, 1, 0), 0)
+	require.NoError(t, err)
+	assert.Equal(t, domain.NewMoney(25), analysis.TotalExpenses)
+	assert.Equal(t, domain.NewMoney(15), analysis.UnlocatedAmount)
+	require.Len(t, analysis.Places, 1)
+	assert.Equal(t, "Starbucks", analysis.Places[0].Name)
+	assert.Equal(t, 2, analysis.Places[0].TransactionCount)
+}
+
+func TestAnalyticsService_GetItemAnalysis(t *testing.T) {
+	db := setupMerchantTestDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.TransactionTag{}))
+	txService := &TransactionService{DB: db, Merchants: NewMerchantService(db)}
+	analytics := &AnalyticsService{DB: db}
+	ctx := context.Background()
+	userID, _, expenseCategoryID := createTestData(t, db)
+
+	day := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+	purchase := func(description string, date time.Time, items ...domain.TransactionItem) *domain.Transaction {
+		tx := &domain.Transaction{UserID: userID, CategoryID: expenseCategoryID, Type: "expense", Description: description,
+			Amount: domain.NewMoney(10), Date: date, Items: items}
+		require.NoError(t, txService.Create(ctx, tx))
+		return tx
+	}
+	purchase("STARBUCKS #1912", day,
+		domain.TransactionItem{Name: "Coffee", Quantity: 2, UnitPrice: domain.NewMoney(4)},
+		domain.TransactionItem{Name: "Muffin", UnitPrice: domain.NewMoney(2)})
+	purchase("Corner kiosk", day.AddDate(0, 0, 1), domain.TransactionItem{Name: "iced coffee", UnitPrice: domain.NewMoney(3)})
+	deleted := purchase("STARBUCKS #1912", day.AddDate(0, 0, 2), domain.TransactionItem{Name: "Coffee", UnitPrice: domain.NewMoney(4)})
+	require.NoError(t, txService.Delete(ctx, deleted.ID))
+	purchase("STARBUCKS #1912", day.AddDate(0, 2, 0), domain.TransactionItem{Name: "Coffee", UnitPrice: domain.NewMoney(4)})
+
+	analysis, err := analytics.GetItemAnalysis(ctx, userID, " COFFEE ", day, day.AddDate(0, 1, 0), 0)
+	require.NoError(t, err)
+	assert.Equal(t, "COFFEE", analysis.Query)
+	assert.Equal(t, domain.NewMoney(11), analysis.TotalAmount, "deleted and out of range purchases do not count")
+	assert.Equal(t, 3.0, analysis.TotalQuantity)
+	require.Len(t, analysis.Items, 2)
+	assert.Equal(t, "Coffee", analysis.Items[0].Name)
+	assert.Equal(t, domain.NewMoney(4), analysis.Items[0].AverageUnitPrice)
+	assert.Equal(t, 1, analysis.Items[0].PurchaseCount)
+	require.Len(t, analysis.Merchants, 2)
+	assert.Equal(t, "Starbucks", analysis.Merchants[0].Name)
+	assert.Equal(t, domain.NewMoney(8), analysis.Merchants[0].TotalAmount)
+	assert.Equal(t, domain.NewMoney(3), analysis.Merchants[1].TotalAmount)
+
+	all, err := analytics.GetItemAnalysis(ctx, userID, "", day, day.AddDate(0, 1, 0), 1)
+	require.NoError(t, err)
+	assert.Equal(t, domain.NewMoney(13), all.TotalAmount)
+	assert.Len(t, all.Items, 1)
+}
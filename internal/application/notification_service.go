@@ -0,0 +1,295 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/pkg"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const defaultNotificationLimit = 50
+
+// JobTypePushDelivery is the job sending one notification to one device
+const JobTypePushDelivery = "push_delivery"
+
+type pushJob struct {
+	DeviceID uint   `json:"device_id"`
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Message  string `json:"message"`
+	EntityID *uint  `json:"entity_id,omitempty"`
+}
+
+// NotificationService keeps the notifications shown to users in the app and
+// pushes them to the users' registered devices. Users turn each type of
+// notification on or off per channel.
+type NotificationService struct {
+	DB   *gorm.DB
+	Jobs *JobService
+	// Push holds the push senders by device platform; devices of platforms
+	// without one get no pushes
+	Push map[string]pkg.PushSender
+}
+
+func NewNotificationService(db *gorm.DB) *NotificationService {
+	return &NotificationService{DB: db}
+}
+
+// Notify stores a notification for its user and queues a push to each of
+// their devices, on the channels they left the notification's type on. A
+// nil service sends nothing.
+func (s *NotificationService) Notify(ctx context.Context, notification *domain.Notification) error {
+	if s == nil {
+		return nil
+	}
+	enabled := s.enabledChannels(ctx, notification.UserID, notification.Type)
+	if enabled[domain.NotificationChannelInApp] {
+		if err := s.DB.WithContext(ctx).Create(notification).Error; err != nil {
+			return err
+		}
+	}
+	if enabled[domain.NotificationChannelPush] {
+		return s.queuePushes(ctx, notification)
+	}
+	return nil
+}
+
+// enabledChannels returns the channels the user gets notifications of the
+// type on, every channel when their settings cannot be read
+func (s *NotificationService) enabledChannels(ctx context.Context, userID uint, notificationType string) map[string]bool {
+	enabled := make(map[string]bool, len(domain.NotificationChannels))
+	for _, channel := range domain.NotificationChannels {
+		enabled[channel] = true
+	}
+	var preferences []domain.NotificationPreference
+	if err := s.DB.WithContext(ctx).Where("user_id = ? AND type = ?", userID, notificationType).Find(&preferences).Error; err != nil {
+		return enabled
+	}
+	for _, preference := range preferences {
+		enabled[preference.Channel] = preference.Enabled
+	}
+	return enabled
+}
+
+// queuePushes queues a push of the notification to each of the user's
+// devices a sender is configured for
+func (s *NotificationService) queuePushes(ctx context.Context, notification *domain.Notification) error {
+	if len(s.Push) == 0 || s.Jobs == nil {
+		return nil
+	}
+	platforms := make([]string, 0, len(s.Push))
+	for platform := range s.Push {
+		platforms = append(platforms, platform)
+	}
+	var devices []domain.PushDevice
+	err := s.DB.WithContext(ctx).Where("user_id = ? AND platform IN ?", notification.UserID, platforms).Order("id").Find(&devices).Error
+	if err != nil {
+		return err
+	}
+	for _, device := range devices {
+		_, err := s.Jobs.Enqueue(ctx, JobTypePushDelivery, pushJob{
+			DeviceID: device.ID, Type: notification.Type, Title: notification.Title,
+			Message: notification.Message, EntityID: notification.EntityID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RunPushJob sends one notification to one device. Devices removed meanwhile
+// are skipped, and devices whose token the push service no longer knows are
+// removed.
+func (s *NotificationService) RunPushJob(ctx context.Context, payload json.RawMessage) error {
+	var job pushJob
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return err
+	}
+	var device domain.PushDevice
+	err := s.DB.WithContext(ctx).First(&device, job.DeviceID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	sender, ok := s.Push[device.Platform]
+	if !ok {
+		return fmt.Errorf("no push sender is configured for %s devices", device.Platform)
+	}
+
+	data := map[string]string{"type": job.Type}
+	if job.EntityID != nil {
+		data["entity_id"] = strconv.FormatUint(uint64(*job.EntityID), 10)
+	}
+	err = sender.Send(ctx, pkg.PushMessage{Token: device.Token, Title: job.Title, Body: job.Message, Data: data})
+	if errors.Is(err, domain.ErrInvalidPushToken) {
+		return s.DB.WithContext(ctx).Delete(&domain.PushDevice{}, device.ID).Error
+	}
+	return err
+}
+
+// RegisterDevice registers a device of the user for push notifications. A
+// token registered before, by this or another user, is moved to the user.
+func (s *NotificationService) RegisterDevice(ctx context.Context, userID uint, device *domain.PushDevice) error {
+	device.ID, device.UserID = 0, userID
+	if err := device.Validate(); err != nil {
+		return err
+	}
+	// The token may still be registered to whoever used the device before
+	db := s.DB.WithContext(domain.ContextWithoutUserScope(ctx))
+	err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "token"}},
+		DoUpdates: clause.AssignmentColumns([]string{"user_id", "platform", "name", "updated_at"}),
+	}).Create(device).Error
+	if err != nil {
+		return err
+	}
+	return db.Where("token = ?", device.Token).First(device).Error
+}
+
+// ListDevices returns the user's registered devices
+func (s *NotificationService) ListDevices(ctx context.Context, userID uint) ([]domain.PushDevice, error) {
+	var devices []domain.PushDevice
+	err := s.DB.WithContext(ctx).Where("user_id = ?", userID).Order("id").Find(&devices).Error
+	return devices, err
+}
+
+// DeleteDevice stops pushes to one of the user's devices
+func (s *NotificationService) DeleteDevice(ctx context.Context, userID, id uint) error {
+	result := s.DB.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&domain.PushDevice{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// Preferences returns whether each type of notification is on for each
+// channel
+func (s *NotificationService) Preferences(ctx context.Context, userID uint) ([]domain.NotificationPreference, error) {
+	var stored []domain.NotificationPreference
+	if err := s.DB.WithContext(ctx).Where("user_id = ?", userID).Find(&stored).Error; err != nil {
+		return nil, err
+	}
+	return domain.NotificationSettings(stored), nil
+}
+
+// UpdatePreferences turns the given types on or off on their channels,
+// leaving the others as they are, and returns all of them
+func (s *NotificationService) UpdatePreferences(
+	ctx context.Context, userID uint, preferences []domain.NotificationPreference,
+) ([]domain.NotificationPreference, error) {
+	for i := range preferences {
+		preferences[i].UserID = userID
+		if err := preferences[i].Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if len(preferences) > 0 {
+		err := s.DB.WithContext(ctx).Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "channel"}, {Name: "type"}},
+			DoUpdates: clause.AssignmentColumns([]string{"enabled"}),
+		}).Create(&preferences).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s.Preferences(ctx, userID)
+}
+
+// Subscribe tells users about exceeded budgets, reached goals and bills
+// coming due published on the bus
+func (s *NotificationService) Subscribe(bus *EventBus) {
+	bus.Subscribe(s.handleEvent, domain.EventBudgetExceeded, domain.EventGoalReached, domain.EventBillDue)
+}
+
+func (s *NotificationService) handleEvent(ctx context.Context, event domain.Event) {
+	var notification *domain.Notification
+	switch e := event.(type) {
+	case domain.BudgetExceeded:
+		name := e.Budget.Category.Name
+		if name == "" {
+			name = "A"
+		}
+		budgetID := e.Budget.ID
+		notification = &domain.Notification{
+			UserID: e.Budget.UserID, Type: domain.NotificationTypeBudget, Title: "Budget exceeded",
+			Message:  fmt.Sprintf("%s budget is over: %s spent of %s", name, e.Budget.Spent, e.Budget.Amount),
+			EntityID: &budgetID,
+		}
+	case domain.GoalReached:
+		goalID := e.Goal.ID
+		notification = &domain.Notification{
+			UserID: e.Goal.UserID, Type: domain.NotificationTypeGoal, Title: "Goal reached: " + e.Goal.Title,
+			Message:  fmt.Sprintf("You have saved %s of your %s target", e.Goal.CurrentAmount, e.Goal.TargetAmount),
+			EntityID: &goalID,
+		}
+	case domain.BillDue:
+		billID := e.Bill.ID
+		message := fmt.Sprintf("%s of %s is due on %s", e.Bill.Amount, e.Bill.Payee, e.DueDate.Format("Jan 2"))
+		if e.Bill.Autopay {
+			message += " and will be paid automatically"
+		}
+		notification = &domain.Notification{
+			UserID: e.Bill.UserID, Type: domain.NotificationTypeBill, Title: "Bill due: " + e.Bill.Payee,
+			Message: message, EntityID: &billID,
+		}
+	default:
+		return
+	}
+	if err := s.Notify(ctx, notification); err != nil {
+		log.Printf("notifications: failed to notify user %d of %s: %v", notification.UserID, event.EventType(), err)
+	}
+}
+
+// List returns the user's notifications newest first, only the unread ones
+// when unreadOnly is set
+func (s *NotificationService) List(ctx context.Context, userID uint, unreadOnly bool, limit int) ([]domain.Notification, error) {
+	if limit <= 0 {
+		limit = defaultNotificationLimit
+	}
+	query := s.DB.WithContext(ctx).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	var notifications []domain.Notification
+	err := query.Order("created_at DESC, id DESC").Limit(limit).Find(&notifications).Error
+	return notifications, err
+}
+
+// MarkRead marks one of the user's notifications as read
+func (s *NotificationService) MarkRead(ctx context.Context, userID, id uint) error {
+	result := s.DB.WithContext(ctx).Model(&domain.Notification{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Update("read_at", gorm.Expr("COALESCE(read_at, ?)", time.Now()))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// MarkAllRead marks every unread notification of the user as read and
+// returns how many there were
+func (s *NotificationService) MarkAllRead(ctx context.Context, userID uint) (int64, error) {
+	result := s.DB.WithContext(ctx).Model(&domain.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", time.Now())
+	return result.RowsAffected, result.Error
+}
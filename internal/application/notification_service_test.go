@@ -0,0 +1,146 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/pkg"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupNotificationTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&domain.Notification{}, &domain.PriceAlert{}, &domain.PriceAlertTrigger{}))
+	return db
+}
+
+func TestNotificationService(t *testing.T) {
+	db := setupNotificationTestDB(t)
+	service := NewNotificationService(db)
+	ctx := context.Background()
+
+	for _, title := range []string{"first", "second", "third"} {
+		require.NoError(t, service.Notify(ctx, &domain.Notification{UserID: 1, Type: domain.NotificationTypePriceAlert, Title: title}))
+	}
+	require.NoError(t, service.Notify(ctx, &domain.Notification{UserID: 2, Title: "other"}))
+
+	notifications, err := service.List(ctx, 1, false, 0)
+	require.NoError(t, err)
+	require.Len(t, notifications, 3)
+	assert.Equal(t, "third", notifications[0].Title)
+
+	require.NoError(t, service.MarkRead(ctx, 1, notifications[0].ID))
+	assert.ErrorIs(t, service.MarkRead(ctx, 2, notifications[1].ID), domain.ErrNotFound)
+
+	unread, err := service.List(ctx, 1, true, 0)
+	require.NoError(t, err)
+	assert.Len(t, unread, 2)
+
+	updated, err := service.MarkAllRead(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), updated)
+	unread, err = service.List(ctx, 1, true, 0)
+	require.NoError(t, err)
+	assert.Empty(t, unread)
+
+	var none *NotificationService
+	assert.NoError(t, none.Notify(ctx, &domain.Notification{UserID: 1}))
+}
+
+type recordingPushSender struct {
+	sent    []pkg.PushMessage
+	invalid map[string]bool
+}
+
+func (s *recordingPushSender) Send(_ context.Context, message pkg.PushMessage) error {
+	if s.invalid[message.Token] {
+		return domain.ErrInvalidPushToken
+	}
+	s.sent = append(s.sent, message)
+	return nil
+}
+
+func TestNotificationService_Push(t *testing.T) {
+	db := setupNotificationTestDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.PushDevice{}, &domain.NotificationPreference{}, &domain.Job{}))
+	ctx := context.Background()
+	ios := &recordingPushSender{invalid: map[string]bool{"uninstalled": true}}
+	service := NewNotificationService(db)
+	service.Jobs = &JobService{DB: db}
+	service.Jobs.Register(JobTypePushDelivery, service.RunPushJob)
+	service.Push = map[string]pkg.PushSender{domain.DevicePlatformIOS: ios}
+
+	phone := &domain.PushDevice{Platform: domain.DevicePlatformIOS, Token: "phone", Name: "iPhone"}
+	require.NoError(t, service.RegisterDevice(ctx, 1, phone))
+	require.NoError(t, service.RegisterDevice(ctx, 1, &domain.PushDevice{Platform: domain.DevicePlatformIOS, Token: "uninstalled"}))
+	require.NoError(t, service.RegisterDevice(ctx, 1, &domain.PushDevice{Platform: domain.DevicePlatformAndroid, Token: "tablet"}))
+	assert.ErrorIs(t, service.RegisterDevice(ctx, 1, &domain.PushDevice{Platform: "web", Token: "x"}), domain.ErrValidation)
+
+	t.Run("should move a token registered again to its new user", func(t *testing.T) {
+		moved := &domain.PushDevice{Platform: domain.DevicePlatformIOS, Token: "phone"}
+		require.NoError(t, service.RegisterDevice(ctx, 2, moved))
+		assert.Equal(t, phone.ID, moved.ID)
+		require.NoError(t, service.RegisterDevice(ctx, 1, moved))
+		devices, err := service.ListDevices(ctx, 1)
+		require.NoError(t, err)
+		assert.Len(t, devices, 3)
+	})
+
+	runJobs := func() {
+		for {
+			ran, err := service.Jobs.RunNext(ctx)
+			require.NoError(t, err)
+			if !ran {
+				return
+			}
+		}
+	}
+
+	entityID := uint(7)
+	require.NoError(t, service.Notify(ctx, &domain.Notification{
+		UserID: 1, Type: domain.NotificationTypeBudget, Title: "Budget exceeded", Message: "Groceries budget is over", EntityID: &entityID,
+	}))
+	runJobs()
+	require.Len(t, ios.sent, 1, "android devices wait for an FCM sender")
+	assert.Equal(t, pkg.PushMessage{
+		Token: "phone", Title: "Budget exceeded", Body: "Groceries budget is over",
+		Data: map[string]string{"type": domain.NotificationTypeBudget, "entity_id": "7"},
+	}, ios.sent[0])
+	devices, err := service.ListDevices(ctx, 1)
+	require.NoError(t, err)
+	assert.Len(t, devices, 2, "the uninstalled app's token is dropped")
+
+	t.Run("should respect the channel toggles", func(t *testing.T) {
+		settings, err := service.UpdatePreferences(ctx, 1, []domain.NotificationPreference{
+			{Channel: domain.NotificationChannelPush, Type: domain.NotificationTypePriceAlert, Enabled: false},
+			{Channel: domain.NotificationChannelInApp, Type: domain.NotificationTypeGoal, Enabled: false},
+		})
+		require.NoError(t, err)
+		assert.Len(t, settings, len(domain.NotificationChannels)*len(domain.NotificationTypes))
+		_, err = service.UpdatePreferences(ctx, 1, []domain.NotificationPreference{{Channel: "sms", Type: domain.NotificationTypeGoal}})
+		assert.ErrorIs(t, err, domain.ErrValidation)
+
+		require.NoError(t, service.Notify(ctx, &domain.Notification{UserID: 1, Type: domain.NotificationTypePriceAlert, Title: "BTC above 70000"}))
+		require.NoError(t, service.Notify(ctx, &domain.Notification{UserID: 1, Type: domain.NotificationTypeGoal, Title: "Goal reached"}))
+		runJobs()
+
+		require.Len(t, ios.sent, 2)
+		assert.Equal(t, "Goal reached", ios.sent[1].Title)
+		notifications, err := service.List(ctx, 1, false, 0)
+		require.NoError(t, err)
+		require.Len(t, notifications, 2)
+		assert.Equal(t, "BTC above 70000", notifications[0].Title)
+	})
+
+	require.NoError(t, service.DeleteDevice(ctx, 1, phone.ID))
+	assert.ErrorIs(t, service.DeleteDevice(ctx, 1, phone.ID), domain.ErrNotFound)
+}
@@ -0,0 +1,39 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+)
+
+// GetPlaceAnalysis reports the user's expenses between the dates by place or
+// by city, highest spend first. A positive limit keeps only the top places.
+func (s *AnalyticsService) GetPlaceAnalysis(
+	ctx context.Context, userID uint, groupBy string, startDate, endDate time.Time, limit int,
+) (*domain.PlaceAnalysis, error) {
+	if !domain.IsValidPlaceGroup(groupBy) {
+		return nil, &domain.ValidationError{Fields: []domain.FieldError{{Field: "group_by", Message: "must be place or city"}}}
+	}
+
+	var expenses []domain.Transaction
+	err := s.DB.WithContext(ctx).
+		Select("id", "type", "amount", "date", "latitude", "longitude", "place", "city").
+		Where("user_id = ? AND type = ? AND date BETWEEN ? AND ?", userID, domain.TransactionTypeExpense, startDate, endDate).
+		Find(&expenses).Error
+	if err != nil {
+		return nil, err
+	}
+
+	analysis := &domain.PlaceAnalysis{
+		UserID:    userID,
+		StartDate: startDate,
+		EndDate:   endDate,
+		GroupBy:   groupBy,
+	}
+	analysis.Places, analysis.TotalExpenses, analysis.UnlocatedAmount = domain.ClusterSpending(expenses, groupBy)
+	if limit > 0 && len(analysis.Places) > limit {
+		analysis.Places = analysis.Places[:limit]
+	}
+	return analysis, nil
+}
@@ -0,0 +1,389 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/persistence"
+
+	"gorm.io/gorm"
+)
+
+// JobTypePluginRun is the job calling one plugin with one event
+const JobTypePluginRun = "plugin_run"
+
+// Plugin run limits: the size of the responses read, the length of the
+// errors recorded and the runs listed by default and at most
+const (
+	maxPluginResponseBytes = 64 << 10
+	maxPluginRunError      = 500
+	defaultPluginRuns      = 50
+	maxPluginRuns          = 200
+)
+
+// pluginJob is the payload of plugin run jobs. TransactionIDs are the
+// transactions of the event, the only ones the plugin's actions may touch.
+type pluginJob struct {
+	PluginID       uint            `json:"plugin_id"`
+	Event          string          `json:"event"`
+	TransactionIDs []uint          `json:"transaction_ids"`
+	Body           json.RawMessage `json:"body"`
+}
+
+type pluginKey struct{}
+
+// contextFromPlugin marks the changes made for a plugin, so the events they
+// publish do not call plugins again
+func contextFromPlugin(ctx context.Context, pluginID uint) context.Context {
+	return context.WithValue(ctx, pluginKey{}, pluginID)
+}
+
+func fromPlugin(ctx context.Context) bool {
+	_, ok := ctx.Value(pluginKey{}).(uint)
+	return ok
+}
+
+// PluginService keeps users' plugins, calls them with the events of their
+// data and applies the actions they answer with. Plugins are sandboxed: they
+// only see the event, must answer within their timeout, and may only
+// recategorize, annotate or tag the event's transactions. Every call is
+// recorded as a plugin run, and the changes also land in the audit log.
+type PluginService struct {
+	DB           *gorm.DB
+	Transactions *TransactionService // Applies the plugins' actions
+	Jobs         *JobService         // Calls plugins through the job queue when set; otherwise in the background
+	Client       *http.Client        // Falls back to pluginClient; each call is bounded by the plugin's timeout
+}
+
+// pluginClient calls plugins on the public internet only
+var pluginClient = &http.Client{Transport: publicTransport}
+
+func NewPluginService(db *gorm.DB, transactions *TransactionService) *PluginService {
+	return &PluginService{DB: db, Transactions: transactions}
+}
+
+func (s *PluginService) client() *http.Client {
+	if s.Client != nil {
+		return s.Client
+	}
+	return pluginClient
+}
+
+// Create adds an enabled plugin for the user and returns it with its new secret
+func (s *PluginService) Create(ctx context.Context, userID uint, plugin domain.Plugin) (*domain.Plugin, error) {
+	if err := plugin.Validate(); err != nil {
+		return nil, err
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	plugin.ID, plugin.UserID, plugin.Secret, plugin.Enabled = 0, userID, hex.EncodeToString(secret), true
+	if plugin.Events == nil {
+		plugin.Events = []string{}
+	}
+	if err := s.DB.WithContext(ctx).Create(&plugin).Error; err != nil {
+		return nil, err
+	}
+	return &plugin, nil
+}
+
+// List returns the user's plugins without their secrets
+func (s *PluginService) List(ctx context.Context, userID uint) ([]domain.Plugin, error) {
+	var plugins []domain.Plugin
+	if err := s.DB.WithContext(ctx).Where("user_id = ?", userID).Order("id").Find(&plugins).Error; err != nil {
+		return nil, err
+	}
+	for i := range plugins {
+		plugins[i].Secret = ""
+	}
+	return plugins, nil
+}
+
+// Get returns one of the user's plugins without its secret
+func (s *PluginService) Get(ctx context.Context, userID, id uint) (*domain.Plugin, error) {
+	var plugin domain.Plugin
+	err := s.DB.WithContext(ctx).Where("user_id = ?", userID).First(&plugin, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	plugin.Secret = ""
+	return &plugin, nil
+}
+
+// Update saves the name, URL, events, timeout and enabled flag of one of
+// the user's plugins
+func (s *PluginService) Update(ctx context.Context, userID uint, plugin *domain.Plugin) error {
+	if err := plugin.Validate(); err != nil {
+		return err
+	}
+	if plugin.Events == nil {
+		plugin.Events = []string{}
+	}
+	result := s.DB.WithContext(ctx).Model(&domain.Plugin{}).
+		Where("id = ? AND user_id = ?", plugin.ID, userID).
+		Select("name", "url", "events", "enabled", "timeout_seconds").
+		Updates(plugin)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// Delete removes one of the user's plugins and its runs. Calls already
+// queued for it are dropped when they run.
+func (s *PluginService) Delete(ctx context.Context, userID, id uint) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND user_id = ?", id, userID).Delete(&domain.Plugin{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrNotFound
+		}
+		return tx.Where("plugin_id = ?", id).Delete(&domain.PluginRun{}).Error
+	})
+}
+
+// Runs returns the latest runs of one of the user's plugins, newest first
+func (s *PluginService) Runs(ctx context.Context, userID, pluginID uint, limit int) ([]domain.PluginRun, error) {
+	if _, err := s.Get(ctx, userID, pluginID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultPluginRuns
+	}
+	var runs []domain.PluginRun
+	err := s.DB.WithContext(ctx).Where("plugin_id = ? AND user_id = ?", pluginID, userID).
+		Order("created_at DESC, id DESC").Limit(min(limit, maxPluginRuns)).Find(&runs).Error
+	return runs, err
+}
+
+// Subscribe calls the user's plugins with every event published on the bus
+func (s *PluginService) Subscribe(bus *EventBus) {
+	bus.SubscribeAll(s.handleEvent)
+}
+
+func (s *PluginService) handleEvent(ctx context.Context, event domain.Event) {
+	if fromPlugin(ctx) {
+		return
+	}
+	var plugins []domain.Plugin
+	err := s.DB.WithContext(ctx).Where("user_id = ? AND enabled = ?", event.EventUserID(), true).Order("id").Find(&plugins).Error
+	if err != nil {
+		log.Printf("plugins: failed to look up plugins of user %d: %v", event.EventUserID(), err)
+		return
+	}
+
+	var job *pluginJob
+	for i := range plugins {
+		if !plugins[i].Wants(event.EventType()) {
+			continue
+		}
+		if job == nil {
+			body, err := json.Marshal(domain.WebhookDelivery{Event: event.EventType(), OccurredAt: time.Now().UTC(), Data: event})
+			if err != nil {
+				log.Printf("plugins: failed to encode %s: %v", event.EventType(), err)
+				return
+			}
+			job = &pluginJob{Event: event.EventType(), TransactionIDs: []uint{}, Body: body}
+			for _, transaction := range domain.Transactions(event) {
+				job.TransactionIDs = append(job.TransactionIDs, transaction.ID)
+			}
+		}
+		call := *job
+		call.PluginID = plugins[i].ID
+		s.queue(ctx, call)
+	}
+}
+
+func (s *PluginService) queue(ctx context.Context, job pluginJob) {
+	if s.Jobs != nil {
+		_, err := s.Jobs.Enqueue(ctx, JobTypePluginRun, job)
+		if err == nil {
+			return
+		}
+		log.Printf("plugins: queueing a run of plugin %d failed, running now: %v", job.PluginID, err)
+	}
+	go func() {
+		if err := s.run(context.WithoutCancel(ctx), job); err != nil {
+			log.Printf("plugins: run of plugin %d failed: %v", job.PluginID, err)
+		}
+	}()
+}
+
+// RunPluginJob is the JobHandler of JobTypePluginRun
+func (s *PluginService) RunPluginJob(ctx context.Context, payload json.RawMessage) error {
+	var job pluginJob
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return err
+	}
+	return s.run(ctx, job)
+}
+
+// run calls a plugin and applies the actions it answers with, recording
+// the run. A failing plugin fails its run rather than the job, so it is not
+// called again with the same event. Plugins that were removed or disabled
+// meanwhile are skipped.
+func (s *PluginService) run(ctx context.Context, job pluginJob) error {
+	var plugin domain.Plugin
+	err := s.DB.WithContext(ctx).First(&plugin, job.PluginID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !plugin.Enabled {
+		return nil
+	}
+
+	started := time.Now()
+	run := &domain.PluginRun{
+		UserID: plugin.UserID, PluginID: plugin.ID, Event: job.Event, Status: domain.PluginRunSucceeded,
+		Actions: []domain.PluginAction{},
+	}
+	response, err := s.call(ctx, &plugin, job.Body)
+	if err == nil {
+		err = response.Validate(job.TransactionIDs)
+	}
+	if err == nil {
+		err = s.apply(contextFromPlugin(ctx, plugin.ID), plugin.UserID, response.Actions)
+	}
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		run.Status, run.Error = domain.PluginRunTimedOut, fmt.Sprintf("no response within %d seconds", plugin.TimeoutSeconds)
+	case err != nil:
+		run.Status, run.Error = domain.PluginRunFailed, err.Error()
+		if len(run.Error) > maxPluginRunError {
+			run.Error = run.Error[:maxPluginRunError]
+		}
+	default:
+		run.Actions = response.Actions
+	}
+	run.DurationMS = time.Since(started).Milliseconds()
+	return s.DB.WithContext(ctx).Create(run).Error
+}
+
+// call posts the event's body to the plugin, signed with its secret, and
+// reads its response within the plugin's timeout. An empty response asks
+// for no actions.
+func (s *PluginService) call(ctx context.Context, plugin *domain.Plugin, body []byte) (*domain.PluginResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(plugin.TimeoutSeconds)*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, plugin.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(domain.WebhookSignatureHeader, "sha256="+SignWebhook(plugin.Secret, body))
+
+	resp, err := s.client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxPluginResponseBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("plugin responded with status %d", resp.StatusCode)
+	}
+	if len(data) > maxPluginResponseBytes {
+		return nil, errors.New("plugin response is larger than 64 KB")
+	}
+
+	var response domain.PluginResponse
+	if len(bytes.TrimSpace(data)) == 0 {
+		return &response, nil
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("plugin response is not valid JSON: %w", err)
+	}
+	return &response, nil
+}
+
+// apply makes the changes of validated actions to the user's transactions,
+// saving each transaction once through the transaction service, so they
+// are validated, audited and published like any other edit. The changes
+// are one unit of work: when one fails, none are made.
+func (s *PluginService) apply(ctx context.Context, userID uint, actions []domain.PluginAction) error {
+	if len(actions) == 0 {
+		return nil
+	}
+	if s.Transactions == nil {
+		return errors.New("plugin actions are not available")
+	}
+
+	changed := make(map[uint]*domain.Transaction)
+	var order []uint
+	for _, action := range actions {
+		transaction, ok := changed[action.TransactionID]
+		if !ok {
+			var err error
+			if transaction, err = s.Transactions.GetByID(ctx, action.TransactionID); err != nil {
+				return fmt.Errorf("transaction %d: %w", action.TransactionID, err)
+			}
+			if transaction.UserID != userID {
+				return fmt.Errorf("transaction %d: %w", action.TransactionID, domain.ErrNotFound)
+			}
+			// Items stay as they are; tags are only replaced when one is added
+			transaction.Items, transaction.Tags = nil, nil
+			changed[action.TransactionID] = transaction
+			order = append(order, action.TransactionID)
+		}
+
+		switch action.Type {
+		case domain.PluginActionSetCategory:
+			var count int64
+			err := s.DB.WithContext(ctx).Model(&domain.Category{}).
+				Where("id = ? AND (user_id IS NULL OR user_id = ?)", action.CategoryID, userID).Count(&count).Error
+			if err != nil {
+				return err
+			}
+			if count == 0 {
+				return fmt.Errorf("category %d: %w", action.CategoryID, domain.ErrNotFound)
+			}
+			transaction.CategoryID, transaction.Category = action.CategoryID, domain.Category{}
+		case domain.PluginActionSetNotes:
+			transaction.Notes = action.Notes
+		case domain.PluginActionAddTags:
+			if transaction.Tags == nil {
+				single := []domain.Transaction{*transaction}
+				if err := s.Transactions.loadTags(ctx, single); err != nil {
+					return err
+				}
+				transaction.Tags = append([]string{}, single[0].Tags...)
+			}
+			transaction.Tags = append(transaction.Tags, action.Tags...)
+		}
+	}
+
+	return persistence.InTransaction(ctx, s.DB, func(ctx context.Context) error {
+		for _, id := range order {
+			if err := s.Transactions.Update(ctx, changed[id]); err != nil {
+				return fmt.Errorf("transaction %d: %w", id, err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,224 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupPluginTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&domain.Plugin{}, &domain.PluginRun{}, &domain.Job{}, &domain.Category{},
+		&domain.Transaction{}, &domain.TransactionTag{}, &domain.TransactionItem{},
+	))
+	return db
+}
+
+func TestPluginService(t *testing.T) {
+	db := setupPluginTestDB(t)
+	ctx := context.Background()
+
+	groceries := domain.Category{Name: "Groceries", Type: domain.TransactionTypeExpense}
+	shopping := domain.Category{Name: "Shopping", Type: domain.TransactionTypeExpense}
+	require.NoError(t, db.Create(&groceries).Error)
+	require.NoError(t, db.Create(&shopping).Error)
+
+	// The plugin files every transaction it is called with under groceries,
+	// except at /rogue, where it tries to change another transaction
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		var delivery struct {
+			Data struct {
+				Transaction domain.Transaction `json:"transaction"`
+			} `json:"data"`
+		}
+		_ = json.Unmarshal(body, &delivery)
+		id := delivery.Data.Transaction.ID
+		switch r.URL.Path {
+		case "/slow":
+			select {
+			case <-r.Context().Done():
+			case <-time.After(3 * time.Second):
+			}
+			return
+		case "/rogue":
+			id = 999
+		}
+		_ = json.NewEncoder(w).Encode(domain.PluginResponse{Actions: []domain.PluginAction{
+			{Type: domain.PluginActionSetCategory, TransactionID: id, CategoryID: groceries.ID},
+			{Type: domain.PluginActionAddTags, TransactionID: id, Tags: []string{"food"}},
+		}})
+	}))
+	defer server.Close()
+
+	bus := NewEventBus()
+	transactions := &TransactionService{DB: db, Events: bus}
+	jobs := &JobService{DB: db, MaxAttempts: 1}
+	service := NewPluginService(db, transactions)
+	// Plugins must be public, so the test server is called by a public name
+	pluginURL := "http://plugins.example.com"
+	service.Client = &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, server.Listener.Addr().String())
+		},
+	}}
+	service.Jobs = jobs
+	jobs.Register(JobTypePluginRun, service.RunPluginJob)
+	service.Subscribe(bus)
+	runJobs := func() {
+		for ran := true; ran; {
+			var err error
+			ran, err = jobs.RunNext(ctx)
+			require.NoError(t, err)
+		}
+	}
+
+	plugin, err := service.Create(ctx, 1, domain.Plugin{
+		Name: "Categorizer", URL: pluginURL + "/categorize", Events: []string{domain.EventTransactionCreated},
+	})
+	require.NoError(t, err)
+
+	t.Run("creates plugins with a secret and default timeout", func(t *testing.T) {
+		assert.Len(t, plugin.Secret, 64)
+		assert.Equal(t, domain.DefaultPluginTimeoutSeconds, plugin.TimeoutSeconds)
+		_, err := service.Create(ctx, 1, domain.Plugin{Name: "Bad", URL: pluginURL, TimeoutSeconds: 60})
+		assert.ErrorIs(t, err, domain.ErrValidation)
+
+		stored, err := service.Get(ctx, 1, plugin.ID)
+		require.NoError(t, err)
+		assert.Empty(t, stored.Secret)
+		_, err = service.Get(ctx, 2, plugin.ID)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("does not call plugins on the server's own networks", func(t *testing.T) {
+		_, err := service.Create(ctx, 1, domain.Plugin{Name: "Metadata", URL: "http://169.254.169.254/latest/meta-data/"})
+		assert.ErrorIs(t, err, domain.ErrValidation)
+
+		// A public name can still resolve to a loopback address
+		_, err = (&PluginService{}).call(ctx, &domain.Plugin{URL: server.URL, TimeoutSeconds: 1}, nil)
+		assert.ErrorIs(t, err, errPrivateAddress)
+	})
+
+	t.Run("applies the actions to the event's transaction and records the run", func(t *testing.T) {
+		transaction := &domain.Transaction{
+			UserID: 1, CategoryID: shopping.ID, Type: domain.TransactionTypeExpense, Amount: domain.NewMoney(42),
+			Description: "Market", Date: time.Now(), Tags: []string{"weekly"},
+		}
+		require.NoError(t, transactions.Create(ctx, transaction))
+		runJobs()
+
+		stored, err := transactions.GetByID(ctx, transaction.ID)
+		require.NoError(t, err)
+		assert.Equal(t, groceries.ID, stored.CategoryID)
+		single := []domain.Transaction{*stored}
+		require.NoError(t, transactions.loadTags(ctx, single))
+		assert.ElementsMatch(t, []string{"weekly", "food"}, single[0].Tags)
+		assert.Equal(t, int32(1), calls.Load(), "the plugin's own changes do not call it again")
+
+		runs, err := service.Runs(ctx, 1, plugin.ID, 0)
+		require.NoError(t, err)
+		require.Len(t, runs, 1)
+		assert.Equal(t, domain.PluginRunSucceeded, runs[0].Status)
+		assert.Len(t, runs[0].Actions, 2)
+	})
+
+	t.Run("rejects actions on other transactions", func(t *testing.T) {
+		plugin.URL = pluginURL + "/rogue"
+		require.NoError(t, service.Update(ctx, 1, plugin))
+
+		transaction := &domain.Transaction{
+			UserID: 1, CategoryID: shopping.ID, Type: domain.TransactionTypeExpense, Amount: domain.NewMoney(10),
+			Description: "Shop", Date: time.Now(),
+		}
+		require.NoError(t, transactions.Create(ctx, transaction))
+		runJobs()
+
+		stored, err := transactions.GetByID(ctx, transaction.ID)
+		require.NoError(t, err)
+		assert.Equal(t, shopping.ID, stored.CategoryID)
+		runs, err := service.Runs(ctx, 1, plugin.ID, 1)
+		require.NoError(t, err)
+		require.Len(t, runs, 1)
+		assert.Equal(t, domain.PluginRunFailed, runs[0].Status)
+		assert.Contains(t, runs[0].Error, "must be a transaction of the event")
+	})
+
+	t.Run("times out slow plugins", func(t *testing.T) {
+		plugin.URL, plugin.TimeoutSeconds = pluginURL+"/slow", 1
+		require.NoError(t, service.Update(ctx, 1, plugin))
+
+		require.NoError(t, transactions.Create(ctx, &domain.Transaction{
+			UserID: 1, CategoryID: shopping.ID, Type: domain.TransactionTypeExpense, Amount: domain.NewMoney(5),
+			Description: "Shop", Date: time.Now(),
+		}))
+		runJobs()
+
+		runs, err := service.Runs(ctx, 1, plugin.ID, 1)
+		require.NoError(t, err)
+		require.Len(t, runs, 1)
+		assert.Equal(t, domain.PluginRunTimedOut, runs[0].Status)
+	})
+
+	t.Run("does not call disabled plugins", func(t *testing.T) {
+		plugin.Enabled = false
+		require.NoError(t, service.Update(ctx, 1, plugin))
+		before := calls.Load()
+
+		require.NoError(t, transactions.Create(ctx, &domain.Transaction{
+			UserID: 1, CategoryID: shopping.ID, Type: domain.TransactionTypeExpense, Amount: domain.NewMoney(5),
+			Description: "Shop", Date: time.Now(),
+		}))
+		runJobs()
+		assert.Equal(t, before, calls.Load())
+	})
+
+	t.Run("makes either all of a run's changes or none", func(t *testing.T) {
+		first := &domain.Transaction{
+			UserID: 1, CategoryID: shopping.ID, Type: domain.TransactionTypeExpense, Amount: domain.NewMoney(5),
+			Description: "Shop", Date: time.Now(),
+		}
+		require.NoError(t, transactions.Create(ctx, first))
+		// Saved around validation, so the plugin's edit of it fails
+		invalid := &domain.Transaction{UserID: 1, CategoryID: shopping.ID, Type: domain.TransactionTypeExpense, Date: time.Now()}
+		require.NoError(t, db.Create(invalid).Error)
+
+		err := service.apply(ctx, 1, []domain.PluginAction{
+			{Type: domain.PluginActionSetNotes, TransactionID: first.ID, Notes: "Checked"},
+			{Type: domain.PluginActionSetNotes, TransactionID: invalid.ID, Notes: "Checked"},
+		})
+		require.ErrorIs(t, err, domain.ErrValidation)
+
+		stored, err := transactions.GetByID(ctx, first.ID)
+		require.NoError(t, err)
+		assert.Empty(t, stored.Notes)
+	})
+
+	t.Run("deletes plugins with their runs", func(t *testing.T) {
+		assert.ErrorIs(t, service.Delete(ctx, 2, plugin.ID), domain.ErrNotFound)
+		require.NoError(t, service.Delete(ctx, 1, plugin.ID))
+		var runs int64
+		require.NoError(t, db.Model(&domain.PluginRun{}).Count(&runs).Error)
+		assert.Zero(t, runs)
+	})
+}
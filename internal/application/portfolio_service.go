@@ -0,0 +1,216 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// PortfolioService keeps the market symbols users own and measures the risk
+// of their holdings against the recorded price history. The balances of the
+// wallets users track count as holdings of their coin.
+type PortfolioService struct {
+	DB       *gorm.DB
+	Backtest *BacktestService // Records the holdings' current prices when set
+}
+
+func NewPortfolioService(db *gorm.DB, backtest *BacktestService) *PortfolioService {
+	return &PortfolioService{DB: db, Backtest: backtest}
+}
+
+// Holdings returns the user's holdings by symbol
+func (s *PortfolioService) Holdings(ctx context.Context, userID uint) ([]domain.Holding, error) {
+	var holdings []domain.Holding
+	err := s.DB.WithContext(ctx).Where("user_id = ?", userID).Order("symbol ASC").Find(&holdings).Error
+	return holdings, err
+}
+
+// SaveHolding sets how much of a symbol the user owns, adding the holding
+// when it is new. Symbols are stored upper-cased and default to stocks.
+func (s *PortfolioService) SaveHolding(ctx context.Context, userID uint, holding domain.Holding) (*domain.Holding, error) {
+	symbol, err := domain.NormalizeSymbol(holding.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	switch holding.AssetType {
+	case "":
+		holding.AssetType = WatchlistAssetStock
+	case WatchlistAssetStock, WatchlistAssetCrypto:
+	default:
+		return nil, ErrInvalidAssetType
+	}
+	if holding.Quantity <= 0 {
+		return nil, &domain.ValidationError{Fields: []domain.FieldError{{Field: "quantity", Message: "must be positive"}}}
+	}
+
+	var saved domain.Holding
+	err = s.DB.WithContext(ctx).Where("user_id = ? AND symbol = ?", userID, symbol).
+		Attrs(domain.Holding{UserID: userID, Symbol: symbol}).
+		Assign(domain.Holding{AssetType: holding.AssetType, Quantity: holding.Quantity}).
+		FirstOrCreate(&saved).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// Start the symbol's price history right away
+	if err := s.Backtest.CapturePrices(ctx, []string{symbol}); err != nil {
+		log.Printf("portfolio: failed to capture the price of %s: %v", symbol, err)
+	}
+	return &saved, nil
+}
+
+// RemoveHolding takes a symbol out of the user's portfolio
+func (s *PortfolioService) RemoveHolding(ctx context.Context, userID uint, symbol string) error {
+	symbol, err := domain.NormalizeSymbol(symbol)
+	if err != nil {
+		return err
+	}
+	result := s.DB.WithContext(ctx).Where("user_id = ? AND symbol = ?", userID, symbol).Delete(&domain.Holding{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// Risk measures the user's portfolio, wallets included, over the last days
+// against the prices recorded for its holdings, capturing their current
+// prices first.
+// riskFreeRate is the annual rate the Sharpe ratio is measured against.
+func (s *PortfolioService) Risk(ctx context.Context, userID uint, days int, riskFreeRate float64) (*domain.PortfolioRisk, error) {
+	var invalid []domain.FieldError
+	if days < 2 || days > domain.MaxPortfolioRiskDays {
+		invalid = append(invalid, domain.FieldError{
+			Field: "days", Message: fmt.Sprintf("must be between 2 and %d", domain.MaxPortfolioRiskDays),
+		})
+	}
+	if riskFreeRate <= -1 || riskFreeRate >= 1 {
+		invalid = append(invalid, domain.FieldError{Field: "risk_free_rate", Message: "must be an annual rate between -1 and 1"})
+	}
+	if len(invalid) > 0 {
+		return nil, &domain.ValidationError{Fields: invalid}
+	}
+
+	holdings, err := s.Holdings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	wallets, err := s.wallets(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	holdings = domain.HoldingsWithWallets(holdings, wallets)
+	if len(holdings) == 0 {
+		return nil, domain.ErrEmptyPortfolio
+	}
+
+	symbols := make([]string, len(holdings))
+	for i := range holdings {
+		symbols[i] = holdings[i].Symbol
+	}
+	prices, to, err := s.prices(ctx, symbols)
+	if err != nil {
+		return nil, err
+	}
+
+	risk, err := domain.AnalyzePortfolioRisk(holdings, prices, days, to, riskFreeRate)
+	if err != nil {
+		return nil, err
+	}
+	return &risk, nil
+}
+
+// NetWorth totals the balances of the user's accounts in USD and the value
+// of their holdings and wallets at current prices
+func (s *PortfolioService) NetWorth(ctx context.Context, userID uint) (*domain.NetWorth, error) {
+	var accounts []domain.Account
+	err := s.DB.WithContext(ctx).Select("id", "name", "type", "currency", "opening_balance").
+		Where("user_id = ?", userID).Order("id").Find(&accounts).Error
+	if err != nil {
+		return nil, err
+	}
+	// Transfers leave the account they were made from and reach their transfer account
+	var totals, transferred []struct {
+		AccountID uint
+		Total     domain.Money
+	}
+	err = s.DB.WithContext(ctx).Model(&domain.Transaction{}).
+		Select("account_id, COALESCE(SUM(CASE WHEN type IN ? THEN -amount ELSE amount END), 0) AS total",
+			[]string{domain.TransactionTypeExpense, domain.TransactionTypeTransfer}).
+		Where("user_id = ? AND account_id IS NOT NULL", userID).Group("account_id").Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+	err = s.DB.WithContext(ctx).Model(&domain.Transaction{}).
+		Select("transfer_account_id AS account_id, COALESCE(SUM(amount), 0) AS total").
+		Where("user_id = ? AND type = ? AND transfer_account_id IS NOT NULL", userID, domain.TransactionTypeTransfer).
+		Group("transfer_account_id").Scan(&transferred).Error
+	if err != nil {
+		return nil, err
+	}
+	recorded := make(map[uint]domain.Money, len(totals))
+	for _, total := range append(totals, transferred...) {
+		recorded[total.AccountID] += total.Total
+	}
+	balances := make([]domain.AccountBalance, len(accounts))
+	for i, account := range accounts {
+		balances[i] = domain.AccountBalance{
+			AccountID: account.ID, Name: account.Name, Type: account.Type, Currency: account.Currency,
+			Balance: account.OpeningBalance + recorded[account.ID],
+		}
+	}
+
+	holdings, err := s.Holdings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	wallets, err := s.wallets(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	var symbols []string
+	for _, holding := range domain.HoldingsWithWallets(holdings, wallets) {
+		symbols = append(symbols, holding.Symbol)
+	}
+	prices, at, err := s.prices(ctx, symbols)
+	if err != nil {
+		return nil, err
+	}
+
+	worth := domain.NewNetWorth(balances, holdings, wallets, prices, at)
+	return &worth, nil
+}
+
+// wallets returns the wallets the user tracks, with their last read balances
+func (s *PortfolioService) wallets(ctx context.Context, userID uint) ([]domain.Wallet, error) {
+	var wallets []domain.Wallet
+	err := s.DB.WithContext(ctx).Where("user_id = ?", userID).Order("chain, id").Find(&wallets).Error
+	return wallets, err
+}
+
+// prices captures the current prices of the symbols and returns every price
+// recorded for them up to now, along with the time taken as now
+func (s *PortfolioService) prices(ctx context.Context, symbols []string) (domain.PriceSeries, time.Time, error) {
+	if len(symbols) > 0 {
+		if err := s.Backtest.CapturePrices(ctx, symbols); err != nil {
+			log.Printf("portfolio: failed to capture current prices: %v", err)
+		}
+	}
+	to := time.Now()
+	if len(symbols) == 0 {
+		return domain.PriceSeries{}, to, nil
+	}
+	var snapshots []domain.PriceSnapshot
+	err := s.DB.WithContext(ctx).Where("symbol IN ? AND captured_at <= ?", symbols, to).Find(&snapshots).Error
+	if err != nil {
+		return nil, to, err
+	}
+	return domain.NewPriceSeries(snapshots), to, nil
+}
@@ -0,0 +1,155 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupPortfolioTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&domain.Holding{}, &domain.PriceSnapshot{}, &domain.Wallet{}, &domain.Account{}, &domain.Transaction{},
+	))
+	return db
+}
+
+func TestPortfolioService_Holdings(t *testing.T) {
+	db := setupPortfolioTestDB(t)
+	prices := &stubPriceSource{prices: map[string]float64{"AAPL": 190, "BTC": 60000}}
+	service := NewPortfolioService(db, NewBacktestService(db, prices))
+	ctx := context.Background()
+
+	saved, err := service.SaveHolding(ctx, 1, domain.Holding{Symbol: " aapl ", Quantity: 10})
+	require.NoError(t, err)
+	assert.Equal(t, "AAPL", saved.Symbol)
+	assert.Equal(t, WatchlistAssetStock, saved.AssetType)
+
+	updated, err := service.SaveHolding(ctx, 1, domain.Holding{Symbol: "AAPL", Quantity: 12.5})
+	require.NoError(t, err)
+	assert.Equal(t, saved.ID, updated.ID, "saving a held symbol again updates it")
+	assert.Equal(t, 12.5, updated.Quantity)
+	_, err = service.SaveHolding(ctx, 1, domain.Holding{Symbol: "BTC", AssetType: WatchlistAssetCrypto, Quantity: 0.5})
+	require.NoError(t, err)
+
+	holdings, err := service.Holdings(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, holdings, 2)
+	assert.Equal(t, "BTC", holdings[1].Symbol)
+
+	var captured int64
+	require.NoError(t, db.Model(&domain.PriceSnapshot{}).Count(&captured).Error)
+	assert.Equal(t, int64(3), captured, "saving a holding captures its price")
+
+	_, err = service.SaveHolding(ctx, 1, domain.Holding{Symbol: "AAPL", Quantity: -1})
+	assert.ErrorIs(t, err, domain.ErrValidation)
+	_, err = service.SaveHolding(ctx, 1, domain.Holding{Symbol: "AAPL", AssetType: "bond", Quantity: 1})
+	assert.ErrorIs(t, err, ErrInvalidAssetType)
+
+	require.NoError(t, service.RemoveHolding(ctx, 1, "aapl"))
+	assert.ErrorIs(t, service.RemoveHolding(ctx, 1, "AAPL"), domain.ErrNotFound)
+	assert.ErrorIs(t, service.RemoveHolding(ctx, 2, "BTC"), domain.ErrNotFound, "other users' holdings are not removed")
+}
+
+func TestPortfolioService_Risk(t *testing.T) {
+	db := setupPortfolioTestDB(t)
+	prices := &stubPriceSource{prices: map[string]float64{"SPY": 104, "BTC": 63000}}
+	service := NewPortfolioService(db, NewBacktestService(db, prices))
+	ctx := context.Background()
+
+	_, err := service.Risk(ctx, 1, domain.DefaultPortfolioRiskDays, domain.DefaultRiskFreeRate)
+	assert.ErrorIs(t, err, domain.ErrEmptyPortfolio)
+
+	require.NoError(t, db.Create(&[]domain.Holding{
+		{UserID: 1, Symbol: "SPY", AssetType: WatchlistAssetStock, Quantity: 10},
+		{UserID: 1, Symbol: "BTC", AssetType: WatchlistAssetCrypto, Quantity: 0.01},
+	}).Error)
+	_, err = service.Risk(ctx, 1, domain.DefaultPortfolioRiskDays, domain.DefaultRiskFreeRate)
+	assert.ErrorIs(t, err, domain.ErrInsufficientPriceHistory, "only today's prices are known")
+
+	now := time.Now()
+	for day, price := range [][2]float64{{100, 60000}, {102, 59000}, {99, 58000}} {
+		at := now.AddDate(0, 0, day-3)
+		require.NoError(t, db.Create(&[]domain.PriceSnapshot{
+			{Symbol: "SPY", Price: price[0], CapturedAt: at}, {Symbol: "BTC", Price: price[1], CapturedAt: at},
+		}).Error)
+	}
+
+	risk, err := service.Risk(ctx, 1, 30, 0.03)
+	require.NoError(t, err)
+	assert.Equal(t, 3, risk.Observations)
+	assert.Equal(t, []string{"BTC", "SPY"}, risk.Symbols)
+	assert.InDelta(t, 10*104+0.01*63000, risk.Value, 1e-6, "valued at the captured current prices")
+	assert.Equal(t, 0.03, risk.RiskFreeRate)
+	assert.Greater(t, risk.Volatility, 0.0)
+	assert.Greater(t, risk.MaxDrawdown, 0.0)
+	assert.Len(t, risk.Correlation, 2)
+
+	_, err = service.Risk(ctx, 1, 1000, 0.02)
+	assert.ErrorIs(t, err, domain.ErrValidation)
+	_, err = service.Risk(ctx, 2, 30, 0.02)
+	assert.ErrorIs(t, err, domain.ErrEmptyPortfolio)
+
+	require.NoError(t, db.Create(&domain.Wallet{
+		UserID: 2, Chain: domain.WalletChainBTC, Address: "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", Balance: 0.5,
+	}).Error)
+	risk, err = service.Risk(ctx, 2, 30, 0.02)
+	require.NoError(t, err, "wallets count as holdings")
+	assert.Equal(t, []string{"BTC"}, risk.Symbols)
+	assert.InDelta(t, 0.5*63000, risk.Value, 1e-6)
+}
+
+func TestPortfolioService_NetWorth(t *testing.T) {
+	db := setupPortfolioTestDB(t)
+	prices := &stubPriceSource{prices: map[string]float64{"AAPL": 200, "BTC": 60000}}
+	service := NewPortfolioService(db, NewBacktestService(db, prices))
+	ctx := context.Background()
+
+	worth, err := service.NetWorth(ctx, 1)
+	require.NoError(t, err)
+	assert.Zero(t, worth.Total)
+	assert.Empty(t, worth.Accounts)
+	assert.Zero(t, prices.calls, "nothing to price")
+
+	checking := domain.Account{
+		UserID: 1, Name: "Checking", Type: domain.AccountTypeChecking, Currency: "USD", OpeningBalance: domain.NewMoney(1000),
+	}
+	euros := domain.Account{
+		UserID: 1, Name: "Girokonto", Type: domain.AccountTypeChecking, Currency: "EUR", OpeningBalance: domain.NewMoney(500),
+	}
+	require.NoError(t, db.Create(&checking).Error)
+	require.NoError(t, db.Create(&euros).Error)
+	now := time.Now()
+	require.NoError(t, db.Create(&[]domain.Transaction{
+		{UserID: 1, AccountID: &checking.ID, Type: domain.TransactionTypeIncome, Amount: domain.NewMoney(300), Date: now},
+		{UserID: 1, AccountID: &checking.ID, Type: domain.TransactionTypeExpense, Amount: domain.NewMoney(50), Date: now},
+		{UserID: 1, Type: domain.TransactionTypeExpense, Amount: domain.NewMoney(20), Date: now},
+	}).Error)
+	require.NoError(t, db.Create(&domain.Holding{UserID: 1, Symbol: "AAPL", AssetType: WatchlistAssetStock, Quantity: 3}).Error)
+	require.NoError(t, db.Create(&domain.Wallet{
+		UserID: 1, Chain: domain.WalletChainBTC, Address: "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", Balance: 0.1,
+	}).Error)
+
+	worth, err = service.NetWorth(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, worth.Accounts, 2)
+	assert.Equal(t, domain.NewMoney(1250), worth.Accounts[0].Balance)
+	assert.Equal(t, domain.NewMoney(500), worth.Accounts[1].Balance)
+	assert.Equal(t, domain.NewMoney(1250), worth.AccountsTotal, "accounts in other currencies are left out")
+	require.Len(t, worth.Assets, 2)
+	assert.Equal(t, domain.AssetSourceWallet, worth.Assets[1].Source)
+	assert.Equal(t, domain.NewMoney(3*200+0.1*60000), worth.AssetsTotal)
+	assert.Equal(t, domain.NewMoney(1250+600+6000), worth.Total)
+	assert.Equal(t, []string{"account Girokonto in EUR"}, worth.Excluded)
+}
@@ -0,0 +1,91 @@
+package application
+
+import (
+	"context"
+	"errors"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/persistence"
+
+	"gorm.io/gorm"
+)
+
+// PreferencesService keeps how users want amounts, dates and periods shown.
+// Analytics, exports and reports read them through userPreferences.
+type PreferencesService struct {
+	DB    *gorm.DB
+	Cache ResultCache // Drops the user's cached dashboard on changes when set
+}
+
+func NewPreferencesService(db *gorm.DB) *PreferencesService {
+	return &PreferencesService{DB: db}
+}
+
+// Get returns the user's preferences, the defaults for settings they never
+// changed
+func (s *PreferencesService) Get(ctx context.Context, userID uint) (domain.Preferences, error) {
+	var user domain.User
+	err := s.DB.WithContext(ctx).Select("id", "locale").First(&user, userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Preferences{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.Preferences{}, err
+	}
+	return loadPreferences(ctx, s.DB, user)
+}
+
+// Update replaces the user's preferences after checking them. The locale is
+// stored on the user, the rest alongside it, in one transaction.
+func (s *PreferencesService) Update(ctx context.Context, userID uint, preferences domain.Preferences) (domain.Preferences, error) {
+	preferences.UserID = userID
+	preferences.Normalize()
+	if err := preferences.Validate(); err != nil {
+		return domain.Preferences{}, err
+	}
+
+	err := persistence.InTransaction(ctx, s.DB, func(ctx context.Context) error {
+		result := persistence.Conn(ctx, s.DB).Model(&domain.User{}).Where("id = ?", userID).Update("locale", preferences.Locale)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrNotFound
+		}
+		return persistence.Conn(ctx, s.DB).Save(&preferences).Error
+	})
+	if err != nil {
+		return domain.Preferences{}, err
+	}
+	invalidateUsers(ctx, s.Cache, userID)
+	return preferences, nil
+}
+
+// loadPreferences returns the user's stored preferences with their locale,
+// or the defaults when they have none
+func loadPreferences(ctx context.Context, db *gorm.DB, user domain.User) (domain.Preferences, error) {
+	preferences := domain.DefaultPreferences(user.ID)
+	err := db.WithContext(ctx).Where("user_id = ?", user.ID).First(&preferences).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Preferences{}, err
+	}
+	preferences.Locale = user.Locale
+	preferences.Normalize()
+	return preferences, nil
+}
+
+// userPreferences returns the preferences analytics and exports format the
+// user's data with, falling back to the defaults when they cannot be read
+func userPreferences(ctx context.Context, db *gorm.DB, userID uint) domain.Preferences {
+	var user domain.User
+	if err := db.WithContext(ctx).Select("id", "locale").First(&user, userID).Error; err != nil {
+		return domain.DefaultPreferences(userID)
+	}
+	preferences, err := loadPreferences(ctx, db, user)
+	if err != nil {
+		preferences = domain.DefaultPreferences(userID)
+		preferences.Locale = user.Locale
+		preferences.Normalize()
+	}
+	return preferences
+}
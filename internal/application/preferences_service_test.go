@@ -0,0 +1,103 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupPreferencesTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&domain.User{}, &domain.Preferences{}, &domain.Category{}, &domain.Transaction{},
+		&domain.Budget{}, &domain.FinancialGoal{},
+	))
+	return db
+}
+
+func TestPreferencesService(t *testing.T) {
+	db := setupPreferencesTestDB(t)
+	service := NewPreferencesService(db)
+	ctx := context.Background()
+
+	user := domain.User{Email: "prefs@example.com", Password: "x", Locale: domain.LocaleTurkish}
+	require.NoError(t, db.Create(&user).Error)
+
+	t.Run("should return the defaults with the user's locale", func(t *testing.T) {
+		preferences, err := service.Get(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.BaseCurrency, preferences.Currency)
+		assert.Equal(t, domain.LocaleTurkish, preferences.Locale)
+		assert.Equal(t, domain.WeekStartMonday, preferences.WeekStart)
+		assert.Equal(t, domain.DashboardPeriodMonth, preferences.DashboardPeriod)
+
+		_, err = service.Get(ctx, 99)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("should reject unsupported settings", func(t *testing.T) {
+		_, err := service.Update(ctx, user.ID, domain.Preferences{WeekStart: "friday"})
+		assert.ErrorIs(t, err, domain.ErrValidation)
+		_, err = service.Update(ctx, 99, domain.Preferences{})
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("should store the preferences and the locale", func(t *testing.T) {
+		updated, err := service.Update(ctx, user.ID, domain.Preferences{
+			Currency: "eur", Locale: domain.LocaleGerman, WeekStart: domain.WeekStartSunday,
+			DateFormat: "DD/MM/YYYY", NumberFormat: "1 234,56", DashboardPeriod: domain.DashboardPeriodQuarter,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "EUR", updated.Currency)
+
+		preferences, err := service.Get(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.LocaleGerman, preferences.Locale)
+		assert.Equal(t, domain.WeekStartSunday, preferences.WeekStart)
+		assert.Equal(t, "DD/MM/YYYY", preferences.DateFormat)
+		assert.Equal(t, domain.DashboardPeriodQuarter, preferences.DashboardPeriod)
+
+		var stored domain.User
+		require.NoError(t, db.First(&stored, user.ID).Error)
+		assert.Equal(t, domain.LocaleGerman, stored.Locale)
+	})
+
+	t.Run("should start weekly trends on the user's first weekday", func(t *testing.T) {
+		analytics := &AnalyticsService{DB: db}
+		wednesday := time.Date(2024, 5, 15, 0, 0, 0, 0, time.UTC)
+		analysis, err := analytics.GetIncomeExpenseAnalysis(ctx, user.ID, domain.PeriodMonthly, wednesday, wednesday.AddDate(0, 0, 14))
+		require.NoError(t, err)
+		require.NotEmpty(t, analysis.WeeklyTrends)
+		assert.Equal(t, time.Sunday, analysis.WeeklyTrends[0].WeekStart.Weekday())
+
+		analysis, err = analytics.GetIncomeExpenseAnalysis(ctx, 99, domain.PeriodMonthly, wednesday, wednesday.AddDate(0, 0, 14))
+		require.NoError(t, err)
+		require.NotEmpty(t, analysis.WeeklyTrends)
+		assert.Equal(t, time.Monday, analysis.WeeklyTrends[0].WeekStart.Weekday(), "users without preferences start on Monday")
+	})
+
+	t.Run("should cover the preferred period without one", func(t *testing.T) {
+		analytics := &AnalyticsService{DB: db}
+		dashboard, err := analytics.GetDashboardSummary(ctx, user.ID, "")
+		require.NoError(t, err)
+		assert.Equal(t, domain.DashboardPeriodQuarter, dashboard.Period)
+	})
+
+	t.Run("should write exports in the preferred formats", func(t *testing.T) {
+		l := (&ExportService{DB: db}).localizer(ctx, user.ID)
+		assert.Equal(t, domain.LocaleGerman, l.Locale)
+		assert.Equal(t, "15/05/2024", l.Date(time.Date(2024, 5, 15, 0, 0, 0, 0, time.UTC)))
+		assert.Equal(t, "1 234,56 €", l.Money(domain.NewMoney(1234.56), "EUR"))
+	})
+}
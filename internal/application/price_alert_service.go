@@ -0,0 +1,237 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+const defaultPriceAlertHistoryLimit = 100
+
+// PriceAlertService manages price alerts and checks them against the market
+type PriceAlertService struct {
+	DB            *gorm.DB
+	Prices        PriceSource
+	Notifications *NotificationService // Notifies users of triggered alerts when set
+}
+
+func NewPriceAlertService(db *gorm.DB, prices PriceSource, notifications *NotificationService) *PriceAlertService {
+	return &PriceAlertService{DB: db, Prices: prices, Notifications: notifications}
+}
+
+// Create adds an active price alert for the user
+func (s *PriceAlertService) Create(ctx context.Context, userID uint, alert *domain.PriceAlert) error {
+	symbol, err := domain.NormalizeSymbol(alert.Symbol)
+	if err != nil {
+		return err
+	}
+	if err := alert.Validate(); err != nil {
+		return err
+	}
+
+	alert.ID = 0
+	alert.UserID = userID
+	alert.Symbol = symbol
+	alert.Active = true
+	alert.Triggered = false
+	alert.TriggerCount = 0
+	alert.LastTriggeredAt = nil
+	return s.DB.WithContext(ctx).Create(alert).Error
+}
+
+// List returns the user's price alerts, newest first
+func (s *PriceAlertService) List(ctx context.Context, userID uint) ([]domain.PriceAlert, error) {
+	var alerts []domain.PriceAlert
+	err := s.DB.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC, id DESC").Find(&alerts).Error
+	return alerts, err
+}
+
+// Get returns one of the user's price alerts
+func (s *PriceAlertService) Get(ctx context.Context, userID, id uint) (*domain.PriceAlert, error) {
+	var alert domain.PriceAlert
+	err := s.DB.WithContext(ctx).Where("user_id = ?", userID).First(&alert, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &alert, nil
+}
+
+// Update saves the comparator, threshold, note, repeat and active flags of
+// one of the user's alerts. Changing the condition or reactivating the alert
+// arms it again.
+func (s *PriceAlertService) Update(ctx context.Context, userID uint, alert *domain.PriceAlert) error {
+	if err := alert.Validate(); err != nil {
+		return err
+	}
+	existing, err := s.Get(ctx, userID, alert.ID)
+	if err != nil {
+		return err
+	}
+
+	if existing.Comparator != alert.Comparator || existing.Threshold != alert.Threshold || (alert.Active && !existing.Active) {
+		alert.Triggered = false
+	} else {
+		alert.Triggered = existing.Triggered
+	}
+	return s.DB.WithContext(ctx).Model(existing).Updates(map[string]interface{}{
+		"comparator": alert.Comparator,
+		"threshold":  alert.Threshold,
+		"note":       alert.Note,
+		"repeat":     alert.Repeat,
+		"active":     alert.Active,
+		"triggered":  alert.Triggered,
+	}).Error
+}
+
+// Delete removes one of the user's alerts along with its history
+func (s *PriceAlertService) Delete(ctx context.Context, userID, id uint) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND user_id = ?", id, userID).Delete(&domain.PriceAlert{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrNotFound
+		}
+		return tx.Where("alert_id = ?", id).Delete(&domain.PriceAlertTrigger{}).Error
+	})
+}
+
+// History returns when the user's alerts fired, newest first. A non-zero
+// alertID narrows it down to one alert.
+func (s *PriceAlertService) History(ctx context.Context, userID, alertID uint, limit int) ([]domain.PriceAlertTrigger, error) {
+	if limit <= 0 {
+		limit = defaultPriceAlertHistoryLimit
+	}
+	query := s.DB.WithContext(ctx).Where("user_id = ?", userID)
+	if alertID != 0 {
+		if _, err := s.Get(ctx, userID, alertID); err != nil {
+			return nil, err
+		}
+		query = query.Where("alert_id = ?", alertID)
+	}
+
+	var triggers []domain.PriceAlertTrigger
+	err := query.Order("triggered_at DESC, id DESC").Limit(limit).Find(&triggers).Error
+	return triggers, err
+}
+
+// CheckAlerts prices the symbols of all active alerts and fires the ones
+// whose condition is met, returning how many fired. Alerts whose symbol has
+// no price are skipped until the next check.
+func (s *PriceAlertService) CheckAlerts(ctx context.Context) (int, error) {
+	var alerts []domain.PriceAlert
+	if err := s.DB.WithContext(ctx).Where("active = ?", true).Find(&alerts).Error; err != nil {
+		return 0, err
+	}
+	if len(alerts) == 0 {
+		return 0, nil
+	}
+
+	var symbols []string
+	seen := map[string]bool{}
+	for i := range alerts {
+		if !seen[alerts[i].Symbol] {
+			seen[alerts[i].Symbol] = true
+			symbols = append(symbols, alerts[i].Symbol)
+		}
+	}
+	prices, err := s.Prices.GetPrices(ctx, symbols)
+	if err != nil {
+		return 0, err
+	}
+
+	fired := 0
+	for i := range alerts {
+		alert := &alerts[i]
+		price, ok := prices[alert.Symbol]
+		if !ok {
+			continue
+		}
+
+		switch matches := alert.Matches(price); {
+		case matches && !alert.Triggered:
+			if err := s.fire(ctx, alert, price); err != nil {
+				log.Printf("price alert %d failed to fire: %v", alert.ID, err)
+				continue
+			}
+			fired++
+		case !matches && alert.Triggered:
+			// The price moved back, so a repeating alert can fire again
+			if err := s.DB.WithContext(ctx).Model(alert).Update("triggered", false).Error; err != nil {
+				log.Printf("price alert %d failed to re-arm: %v", alert.ID, err)
+			}
+		}
+	}
+	return fired, nil
+}
+
+// fire records the trigger, updates the alert and notifies its user
+func (s *PriceAlertService) fire(ctx context.Context, alert *domain.PriceAlert, price float64) error {
+	now := time.Now()
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		trigger := &domain.PriceAlertTrigger{
+			AlertID:     alert.ID,
+			UserID:      alert.UserID,
+			Symbol:      alert.Symbol,
+			Comparator:  alert.Comparator,
+			Threshold:   alert.Threshold,
+			Price:       price,
+			TriggeredAt: now,
+		}
+		if err := tx.Create(trigger).Error; err != nil {
+			return err
+		}
+		return tx.Model(alert).Updates(map[string]interface{}{
+			"triggered":         true,
+			"active":            alert.Repeat,
+			"trigger_count":     gorm.Expr("trigger_count + 1"),
+			"last_triggered_at": now,
+		}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	alertID := alert.ID
+	notification := &domain.Notification{
+		UserID:   alert.UserID,
+		Type:     domain.NotificationTypePriceAlert,
+		Title:    "Price alert: " + alert.Symbol,
+		Message:  fmt.Sprintf("%s is %s %.2f at %.2f", alert.Symbol, alert.Comparator, alert.Threshold, price),
+		EntityID: &alertID,
+	}
+	if err := s.Notifications.Notify(ctx, notification); err != nil {
+		log.Printf("price alert %d fired but the notification failed: %v", alert.ID, err)
+	}
+	return nil
+}
+
+// StartPolling runs CheckAlerts on the given interval until ctx is cancelled
+func (s *PriceAlertService) StartPolling(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if fired, err := s.CheckAlerts(ctx); err != nil {
+			log.Printf("price alert check failed: %v", err)
+		} else if fired > 0 {
+			log.Printf("fired %d price alert(s)", fired)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceAlertService_CRUD(t *testing.T) {
+	db := setupNotificationTestDB(t)
+	service := NewPriceAlertService(db, &stubPriceSource{}, nil)
+	ctx := context.Background()
+
+	alert := &domain.PriceAlert{Symbol: "aapl", Comparator: domain.PriceAlertAbove, Threshold: 200}
+	require.NoError(t, service.Create(ctx, 1, alert))
+	assert.Equal(t, "AAPL", alert.Symbol)
+	assert.True(t, alert.Active)
+
+	assert.ErrorIs(t, service.Create(ctx, 1, &domain.PriceAlert{Symbol: "AAPL", Comparator: "equals", Threshold: 1}),
+		domain.ErrInvalidComparator)
+	assert.ErrorIs(t, service.Create(ctx, 1, &domain.PriceAlert{Symbol: "AAPL", Comparator: domain.PriceAlertBelow}),
+		domain.ErrInvalidThreshold)
+
+	_, err := service.Get(ctx, 2, alert.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	alert.Threshold = 250
+	alert.Note = "sell half"
+	require.NoError(t, service.Update(ctx, 1, alert))
+	stored, err := service.Get(ctx, 1, alert.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 250.0, stored.Threshold)
+	assert.Equal(t, "sell half", stored.Note)
+
+	assert.ErrorIs(t, service.Delete(ctx, 2, alert.ID), domain.ErrNotFound)
+	require.NoError(t, service.Delete(ctx, 1, alert.ID))
+	alerts, err := service.List(ctx, 1)
+	require.NoError(t, err)
+	assert.Empty(t, alerts)
+}
+
+func TestPriceAlertService_CheckAlerts(t *testing.T) {
+	db := setupNotificationTestDB(t)
+	prices := &stubPriceSource{prices: map[string]float64{"BTC": 50000, "AAPL": 180}}
+	service := NewPriceAlertService(db, prices, NewNotificationService(db))
+	ctx := context.Background()
+
+	once := &domain.PriceAlert{Symbol: "BTC", Comparator: domain.PriceAlertAbove, Threshold: 45000}
+	repeating := &domain.PriceAlert{Symbol: "AAPL", Comparator: domain.PriceAlertBelow, Threshold: 190, Repeat: true}
+	untouched := &domain.PriceAlert{Symbol: "AAPL", Comparator: domain.PriceAlertAbove, Threshold: 300}
+	missing := &domain.PriceAlert{Symbol: "ALT", Comparator: domain.PriceAlertAbove, Threshold: 1}
+	for _, alert := range []*domain.PriceAlert{once, repeating, untouched, missing} {
+		require.NoError(t, service.Create(ctx, 1, alert))
+	}
+
+	fired, err := service.CheckAlerts(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, fired)
+
+	stored, err := service.Get(ctx, 1, once.ID)
+	require.NoError(t, err)
+	assert.False(t, stored.Active, "one-off alerts stop after firing")
+	assert.Equal(t, 1, stored.TriggerCount)
+	assert.NotNil(t, stored.LastTriggeredAt)
+
+	// A repeating alert does not fire again while the price stays past the threshold
+	fired, err = service.CheckAlerts(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, fired)
+
+	// ...but does once the price has moved back and crosses again
+	prices.prices["AAPL"] = 195
+	_, err = service.CheckAlerts(ctx)
+	require.NoError(t, err)
+	prices.prices["AAPL"] = 185
+	fired, err = service.CheckAlerts(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, fired)
+
+	history, err := service.History(ctx, 1, repeating.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, 185.0, history[0].Price)
+
+	all, err := service.History(ctx, 1, 0, 0)
+	require.NoError(t, err)
+	assert.Len(t, all, 3)
+	_, err = service.History(ctx, 2, repeating.ID, 0)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	notifications, err := NewNotificationService(db).List(ctx, 1, true, 0)
+	require.NoError(t, err)
+	require.Len(t, notifications, 3)
+	assert.Equal(t, "Price alert: AAPL", notifications[0].Title)
+	assert.Equal(t, "AAPL is below 190.00 at 185.00", notifications[0].Message)
+	assert.Equal(t, repeating.ID, *notifications[0].EntityID)
+
+	prices.err = errors.New("provider down")
+	_, err = service.CheckAlerts(ctx)
+	assert.Error(t, err)
+}
@@ -0,0 +1,43 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+)
+
+// errPrivateAddress is returned for calls to hosts that resolve to an
+// address on the server's own networks
+var errPrivateAddress = errors.New("address is not public")
+
+// publicTransport connects to the public internet only, for calls to URLs
+// users choose, like plugins and webhooks. The URLs are validated when
+// saved, but a host name can resolve to a loopback, private or link-local
+// address at any time, so the dialer checks every address it connects to,
+// redirects included, and no proxy is used.
+var publicTransport = func() *http.Transport {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Control: publicAddressesOnly}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return transport
+}()
+
+// publicAddressesOnly refuses connections to addresses that are not public
+func publicAddressesOnly(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil || !domain.IsPublicAddress(addr) {
+		return fmt.Errorf("%w: %s", errPrivateAddress, host)
+	}
+	return nil
+}
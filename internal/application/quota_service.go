@@ -0,0 +1,174 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// QuotaService enforces the limits of the users' plans: daily quotas of the
+// AI and bank refresh endpoints, export sizes, how often scheduled bank
+// syncs pull accounts and which features the plans include. Quotas are counted per UTC day in the database, so
+// every instance of the server shares them.
+type QuotaService struct {
+	DB    *gorm.DB
+	Plans map[string]domain.PlanLimits // Limits by plan; plans without an entry are unlimited
+}
+
+func NewQuotaService(db *gorm.DB, plans map[string]domain.PlanLimits) *QuotaService {
+	return &QuotaService{DB: db, Plans: plans}
+}
+
+// quotaDay returns the start of the UTC day quotas are counted for
+func quotaDay(now time.Time) time.Time {
+	return now.UTC().Truncate(24 * time.Hour)
+}
+
+// plan returns the user's plan and its limits
+func (s *QuotaService) plan(ctx context.Context, userID uint) (string, domain.PlanLimits, error) {
+	var user domain.User
+	err := s.DB.WithContext(ctx).Select("id", "plan").First(&user, userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", domain.PlanLimits{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return "", domain.PlanLimits{}, err
+	}
+	if user.Plan == "" {
+		user.Plan = domain.PlanFree
+	}
+	return user.Plan, s.Plans[user.Plan], nil
+}
+
+// Consume counts one use of the quota by the user. Once the day's limit is
+// reached it returns domain.ErrQuotaExceeded and does not count; the usage
+// is returned either way.
+func (s *QuotaService) Consume(ctx context.Context, userID uint, quota string) (*domain.QuotaUsage, error) {
+	_, limits, err := s.plan(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	limit := limits.DailyLimit(quota)
+	day := quotaDay(time.Now())
+
+	counter := domain.UsageCounter{UserID: userID, Quota: quota, Day: day}
+	exceeded := false
+	err = s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&counter).Error; err != nil {
+			return err
+		}
+		update := tx.Model(&domain.UsageCounter{}).Where("user_id = ? AND quota = ? AND day = ?", userID, quota, day)
+		if limit > 0 {
+			update = update.Where("count < ?", limit)
+		}
+		result := update.UpdateColumn("count", gorm.Expr("count + 1"))
+		if result.Error != nil {
+			return result.Error
+		}
+		exceeded = result.RowsAffected == 0
+		return tx.Where("user_id = ? AND quota = ? AND day = ?", userID, quota, day).First(&counter).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	usage := quotaUsage(quota, counter.Count, limit, day)
+	if exceeded {
+		return usage, domain.ErrQuotaExceeded
+	}
+	return usage, nil
+}
+
+// quotaUsage describes a quota used count times on the day
+func quotaUsage(quota string, count, limit int, day time.Time) *domain.QuotaUsage {
+	usage := &domain.QuotaUsage{Quota: quota, Used: count, ResetsAt: day.Add(24 * time.Hour)}
+	if limit > 0 {
+		remaining := max(limit-count, 0)
+		usage.Limit, usage.Remaining = limit, &remaining
+	}
+	return usage
+}
+
+// Usage returns the user's plan, its limits and what is left of its quotas today
+func (s *QuotaService) Usage(ctx context.Context, userID uint) (*domain.Usage, error) {
+	plan, limits, err := s.plan(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	day := quotaDay(time.Now())
+
+	var counters []domain.UsageCounter
+	if err := s.DB.WithContext(ctx).Where("user_id = ? AND day = ?", userID, day).Find(&counters).Error; err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int, len(counters))
+	for _, counter := range counters {
+		counts[counter.Quota] = counter.Count
+	}
+
+	usage := &domain.Usage{Plan: plan, Limits: limits, BankSyncInterval: limits.BankSyncInterval.String()}
+	for _, quota := range domain.Quotas {
+		usage.Quotas = append(usage.Quotas, *quotaUsage(quota, counts[quota], limits.DailyLimit(quota), day))
+	}
+	return usage, nil
+}
+
+// CheckExportSize returns domain.ErrExportTooLarge when an export of rows
+// transactions exceeds the user's plan
+func (s *QuotaService) CheckExportSize(ctx context.Context, userID uint, rows int) error {
+	plan, limits, err := s.plan(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if limits.ExportMaxRows > 0 && rows > limits.ExportMaxRows {
+		return fmt.Errorf("%w: the %s plan exports at most %d transactions, narrow the date range",
+			domain.ErrExportTooLarge, plan, limits.ExportMaxRows)
+	}
+	return nil
+}
+
+// SyncDue reports whether scheduled syncs should pull a bank account of the
+// user last synced at lastSynced, which is nil before its first sync. Users
+// whose plan does not include bank sync are never due.
+func (s *QuotaService) SyncDue(ctx context.Context, userID uint, lastSynced *time.Time) (bool, error) {
+	_, limits, err := s.plan(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if !limits.HasFeature(domain.FeatureBankSync) {
+		return false, nil
+	}
+	return lastSynced == nil || time.Since(*lastSynced) >= limits.BankSyncInterval, nil
+}
+
+// HasFeature reports whether the user's plan includes the feature
+func (s *QuotaService) HasFeature(ctx context.Context, userID uint, feature string) (bool, error) {
+	_, limits, err := s.plan(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return limits.HasFeature(feature), nil
+}
+
+// SetPlan moves the user to another plan
+func (s *QuotaService) SetPlan(ctx context.Context, userID uint, plan string) (*domain.Usage, error) {
+	if err := domain.ValidatePlan(plan); err != nil {
+		return nil, err
+	}
+	// Admins change other users' plans, so the usage read is the user's, not the acting admin's
+	ctx = domain.ContextWithoutUserScope(ctx)
+	result := s.DB.WithContext(ctx).Model(&domain.User{}).Where("id = ?", userID).Update("plan", plan)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, domain.ErrNotFound
+	}
+	return s.Usage(ctx, userID)
+}
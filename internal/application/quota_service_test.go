@@ -0,0 +1,192 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/persistence"
+	"go-finance-advisor/internal/pkg"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupQuotaTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&domain.User{}, &domain.UsageCounter{}, &domain.Category{}, &domain.Transaction{},
+		&domain.TransactionTag{}, &domain.TransactionItem{}, &domain.CategoryMapping{}, &domain.BankLink{}, &domain.BankTransaction{},
+	))
+	return db
+}
+
+var testPlans = map[string]domain.PlanLimits{
+	domain.PlanFree: {
+		AIRequestsPerDay: 2, BankRefreshesPerDay: 1, ExportMaxRows: 2, BankSyncInterval: 24 * time.Hour,
+		Features: []string{domain.FeatureBankSync},
+	},
+	domain.PlanPremium: {AIRequestsPerDay: 100, BankSyncInterval: 6 * time.Hour, Features: domain.Features},
+}
+
+func TestQuotaService(t *testing.T) {
+	db := setupQuotaTestDB(t)
+	service := NewQuotaService(db, testPlans)
+	ctx := context.Background()
+
+	free := domain.User{Email: "free@example.com", Password: "x"}
+	premium := domain.User{Email: "premium@example.com", Password: "x", Plan: domain.PlanPremium}
+	require.NoError(t, db.Create(&free).Error)
+	require.NoError(t, db.Create(&premium).Error)
+
+	t.Run("should count uses until the daily limit", func(t *testing.T) {
+		usage, err := service.Consume(ctx, free.ID, domain.QuotaAIRequests)
+		require.NoError(t, err)
+		assert.Equal(t, 1, usage.Used)
+		require.NotNil(t, usage.Remaining)
+		assert.Equal(t, 1, *usage.Remaining)
+		assert.Equal(t, quotaDay(time.Now()).Add(24*time.Hour), usage.ResetsAt)
+
+		_, err = service.Consume(ctx, free.ID, domain.QuotaAIRequests)
+		require.NoError(t, err)
+		usage, err = service.Consume(ctx, free.ID, domain.QuotaAIRequests)
+		assert.ErrorIs(t, err, domain.ErrQuotaExceeded)
+		assert.Equal(t, 2, usage.Used, "refused requests are not counted")
+		assert.Equal(t, 0, *usage.Remaining)
+
+		_, err = service.Consume(ctx, free.ID, domain.QuotaBankRefreshes)
+		assert.NoError(t, err, "quotas are counted apart")
+		_, err = service.Consume(ctx, 99, domain.QuotaAIRequests)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("should not limit unlimited quotas", func(t *testing.T) {
+		for range 3 {
+			usage, err := service.Consume(ctx, premium.ID, domain.QuotaBankRefreshes)
+			require.NoError(t, err)
+			assert.Nil(t, usage.Remaining)
+		}
+	})
+
+	t.Run("should report the plan and what is left today", func(t *testing.T) {
+		// Yesterday's uses do not count
+		yesterday := quotaDay(time.Now()).AddDate(0, 0, -1)
+		require.NoError(t, db.Create(&domain.UsageCounter{
+			UserID: free.ID, Quota: domain.QuotaBankRefreshes, Day: yesterday, Count: 5,
+		}).Error)
+
+		usage, err := service.Usage(ctx, free.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.PlanFree, usage.Plan)
+		assert.Equal(t, "24h0m0s", usage.BankSyncInterval)
+		require.Len(t, usage.Quotas, 2)
+		assert.Equal(t, domain.QuotaAIRequests, usage.Quotas[0].Quota)
+		assert.Equal(t, 2, usage.Quotas[0].Used)
+		assert.Equal(t, 0, *usage.Quotas[0].Remaining)
+		assert.Equal(t, 1, usage.Quotas[1].Used)
+
+		usage, err = service.Usage(ctx, premium.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 3, usage.Quotas[1].Used)
+		assert.Nil(t, usage.Quotas[1].Remaining)
+	})
+
+	t.Run("should limit export sizes", func(t *testing.T) {
+		assert.NoError(t, service.CheckExportSize(ctx, free.ID, 2))
+		err := service.CheckExportSize(ctx, free.ID, 3)
+		assert.ErrorIs(t, err, domain.ErrExportTooLarge)
+		assert.ErrorContains(t, err, "at most 2 transactions")
+		assert.NoError(t, service.CheckExportSize(ctx, premium.ID, 10000))
+
+		exports := NewExportService(db)
+		exports.Quotas = service
+		for i := range 3 {
+			require.NoError(t, db.Create(&domain.Transaction{
+				UserID: free.ID, Type: domain.TransactionTypeExpense, Amount: domain.NewMoney(float64(i + 1)), Date: time.Now(),
+			}).Error)
+		}
+		_, _, err = exports.ExportTransactions(ctx, free.ID, domain.ExportFormatCSV, nil, nil)
+		assert.ErrorIs(t, err, domain.ErrExportTooLarge)
+		_, _, err = exports.ExportAllData(ctx, free.ID, domain.ExportFormatJSON)
+		assert.ErrorIs(t, err, domain.ErrExportTooLarge)
+	})
+
+	t.Run("should space scheduled bank syncs by plan", func(t *testing.T) {
+		sevenHoursAgo := time.Now().Add(-7 * time.Hour)
+		freeLink := domain.BankLink{
+			UserID: free.ID, Provider: domain.BankProviderPlaid, ExternalAccountID: "acc-1", ConnectionID: "item-1",
+			AccessToken: "token", Status: domain.BankLinkStatusActive, LastSyncedAt: &sevenHoursAgo,
+		}
+		premiumLink := freeLink
+		premiumLink.UserID = premium.ID
+		require.NoError(t, db.Create(&freeLink).Error)
+		require.NoError(t, db.Create(&premiumLink).Error)
+
+		due, err := service.SyncDue(ctx, free.ID, nil)
+		require.NoError(t, err)
+		assert.True(t, due, "accounts that were never synced are due")
+
+		banks := NewBankSyncService(db, map[string]pkg.BankProvider{domain.BankProviderPlaid: &fakeBankProvider{}})
+		banks.Quotas = service
+		_, err = banks.SyncAll(ctx)
+		require.NoError(t, err)
+
+		require.NoError(t, db.First(&freeLink, freeLink.ID).Error)
+		require.NoError(t, db.First(&premiumLink, premiumLink.ID).Error)
+		assert.WithinDuration(t, sevenHoursAgo, *freeLink.LastSyncedAt, time.Second, "the free plan syncs daily")
+		assert.WithinDuration(t, time.Now(), *premiumLink.LastSyncedAt, time.Minute)
+	})
+
+	t.Run("should include features by plan", func(t *testing.T) {
+		included, err := service.HasFeature(ctx, free.ID, domain.FeatureAIAdvisor)
+		require.NoError(t, err)
+		assert.False(t, included)
+		included, err = service.HasFeature(ctx, premium.ID, domain.FeatureAIAdvisor)
+		require.NoError(t, err)
+		assert.True(t, included)
+		_, err = service.HasFeature(ctx, 99, domain.FeatureAIAdvisor)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+
+		withoutBankSync := NewQuotaService(db, map[string]domain.PlanLimits{domain.PlanFree: {}})
+		due, err := withoutBankSync.SyncDue(ctx, free.ID, nil)
+		require.NoError(t, err)
+		assert.False(t, due, "plans without bank sync are never synced")
+	})
+
+	t.Run("should change plans", func(t *testing.T) {
+		usage, err := service.SetPlan(ctx, free.ID, domain.PlanPremium)
+		require.NoError(t, err)
+		assert.Equal(t, domain.PlanPremium, usage.Plan)
+		assert.Equal(t, 98, *usage.Quotas[0].Remaining)
+
+		_, err = service.SetPlan(ctx, free.ID, "gold")
+		assert.ErrorIs(t, err, domain.ErrValidation)
+		_, err = service.SetPlan(ctx, 99, domain.PlanFree)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
+
+func TestQuotaService_SetPlanAsAdmin(t *testing.T) {
+	db := setupQuotaTestDB(t)
+	require.NoError(t, db.Use(persistence.NewUserScope(persistence.UserOwnedTables...)))
+	service := NewQuotaService(db, testPlans)
+
+	admin := domain.User{Email: "admin@example.com", Password: "x"}
+	user := domain.User{Email: "user@example.com", Password: "x"}
+	require.NoError(t, db.Create(&admin).Error)
+	require.NoError(t, db.Create(&user).Error)
+	_, err := service.Consume(domain.ContextWithActor(context.Background(), domain.Actor{UserID: user.ID}), user.ID, domain.QuotaAIRequests)
+	require.NoError(t, err)
+
+	usage, err := service.SetPlan(domain.ContextWithActor(context.Background(), domain.Actor{UserID: admin.ID}), user.ID, domain.PlanPremium)
+	require.NoError(t, err)
+	assert.Equal(t, domain.PlanPremium, usage.Plan)
+	assert.Equal(t, 1, usage.Quotas[0].Used, "the user's usage, not the admin's")
+}
@@ -0,0 +1,137 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/persistence"
+	"go-finance-advisor/internal/pkg"
+
+	"gorm.io/gorm"
+)
+
+// ReceiptScanResult is the outcome of scanning a receipt: the stored attachment,
+// the fields OCR could extract and a category guessed from earlier transactions
+type ReceiptScanResult struct {
+	Attachment          *domain.Attachment  `json:"attachment"`
+	Receipt             *domain.ReceiptData `json:"receipt"`
+	SuggestedCategoryID *uint               `json:"suggested_category_id,omitempty"`
+}
+
+// fileLinkTTL is how long download links of stored files stay valid
+const fileLinkTTL = 15 * time.Minute
+
+type ReceiptService struct {
+	DB          *gorm.DB
+	OCR         pkg.OCRProvider
+	Storage     pkg.BlobStorage
+	Attachments domain.AttachmentRepository // Falls back to a GORM repository over DB, without encryption, when nil
+}
+
+func NewReceiptService(db *gorm.DB, ocr pkg.OCRProvider, storage pkg.BlobStorage) *ReceiptService {
+	return &ReceiptService{DB: db, OCR: ocr, Storage: storage}
+}
+
+func (s *ReceiptService) attachments() domain.AttachmentRepository {
+	if s.Attachments != nil {
+		return s.Attachments
+	}
+	return persistence.NewAttachmentRepository(s.DB, nil)
+}
+
+// ScanReceipt stores the uploaded receipt as an attachment and extracts the
+// merchant, date and amount from it. The caller is expected to let the user
+// confirm the extracted values before creating a transaction.
+func (s *ReceiptService) ScanReceipt(
+	ctx context.Context, userID uint, fileName, contentType string, image []byte,
+) (*ReceiptScanResult, error) {
+	if len(image) == 0 {
+		return nil, errors.New("receipt image is empty")
+	}
+	if s.OCR == nil {
+		return nil, errors.New("no OCR provider configured")
+	}
+
+	text, err := s.OCR.ExtractText(ctx, image, contentType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read receipt: %w", err)
+	}
+
+	attachment, err := s.storeAttachment(ctx, userID, fileName, contentType, image)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReceiptScanResult{
+		Attachment: attachment,
+		Receipt:    pkg.ParseReceiptText(text),
+	}
+	if result.Receipt.Merchant != "" {
+		result.SuggestedCategoryID = s.suggestCategory(ctx, userID, result.Receipt.Merchant)
+	}
+
+	return result, nil
+}
+
+func (s *ReceiptService) storeAttachment(
+	ctx context.Context, userID uint, fileName, contentType string, data []byte,
+) (*domain.Attachment, error) {
+	// Only keep the base name so uploaded file names cannot escape the user's attachments
+	baseName := filepath.Base(strings.ReplaceAll(fileName, "\\", "/"))
+	if baseName == "." || baseName == ".." || baseName == string(filepath.Separator) {
+		baseName = "receipt"
+	}
+	key := fmt.Sprintf("attachments/%d/%d_%s", userID, time.Now().UnixNano(), baseName)
+	if err := s.Storage.Put(ctx, key, contentType, data); err != nil {
+		return nil, fmt.Errorf("failed to store attachment: %w", err)
+	}
+
+	attachment := &domain.Attachment{
+		UserID:      userID,
+		FileName:    baseName,
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		StoragePath: key,
+	}
+	if err := s.attachments().Create(ctx, attachment); err != nil {
+		_ = s.Storage.Delete(ctx, key)
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+// AttachmentLink returns a signed link the user's attachment can be
+// downloaded from, or domain.ErrNotFound when it is not theirs
+func (s *ReceiptService) AttachmentLink(ctx context.Context, userID, attachmentID uint) (*domain.FileLink, error) {
+	attachment, err := s.attachments().GetByID(ctx, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	if attachment.UserID != userID {
+		return nil, domain.ErrNotFound
+	}
+
+	url, err := s.Storage.SignedURL(ctx, attachment.StoragePath, fileLinkTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.FileLink{URL: url, FileName: attachment.FileName, ExpiresAt: time.Now().Add(fileLinkTTL)}, nil
+}
+
+// suggestCategory reuses the category of the user's latest transaction at the same merchant
+func (s *ReceiptService) suggestCategory(ctx context.Context, userID uint, merchant string) *uint {
+	var transaction domain.Transaction
+	err := s.DB.WithContext(ctx).Where("user_id = ? AND LOWER(description) LIKE ?", userID, "%"+strings.ToLower(merchant)+"%").
+		Order("date DESC").
+		First(&transaction).Error
+	if err != nil {
+		return nil
+	}
+	return &transaction.CategoryID
+}
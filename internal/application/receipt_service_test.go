@@ -0,0 +1,137 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/pkg"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type stubOCRProvider struct {
+	text string
+	err  error
+}
+
+func (s *stubOCRProvider) ExtractText(_ context.Context, _ []byte, _ string) (string, error) {
+	return s.text, s.err
+}
+
+func setupReceiptTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&domain.User{}, &domain.Category{}, &domain.Transaction{}, &domain.Attachment{})
+	require.NoError(t, err)
+
+	return db
+}
+
+func testStorage(dir string) *pkg.LocalStorage {
+	return pkg.NewLocalStorage(dir, "/files", []byte("secret"))
+}
+
+func TestReceiptService_ScanReceipt(t *testing.T) {
+	receiptText := "Corner Grocery\n2024-03-15\nMilk 3.49\nBread 2.99\nSubtotal 6.48\nTax 0.52\nTotal 7.00\n"
+
+	t.Run("should store attachment and extract receipt fields", func(t *testing.T) {
+		db := setupReceiptTestDB(t)
+		dir := t.TempDir()
+		service := NewReceiptService(db, &stubOCRProvider{text: receiptText}, testStorage(dir))
+
+		result, err := service.ScanReceipt(context.Background(), 1, "receipt.png", "image/png", []byte("image"))
+		require.NoError(t, err)
+
+		assert.NotZero(t, result.Attachment.ID)
+		assert.Equal(t, "receipt.png", result.Attachment.FileName)
+		assert.Equal(t, int64(5), result.Attachment.Size)
+		assert.FileExists(t, filepath.Join(dir, result.Attachment.StoragePath))
+
+		assert.Equal(t, "Corner Grocery", result.Receipt.Merchant)
+		assert.Equal(t, domain.NewMoney(7.00), result.Receipt.Amount)
+		require.NotNil(t, result.Receipt.Date)
+		assert.Equal(t, "2024-03-15", result.Receipt.Date.Format("2006-01-02"))
+		assert.Nil(t, result.SuggestedCategoryID)
+	})
+
+	t.Run("should suggest category from previous transaction at merchant", func(t *testing.T) {
+		db := setupReceiptTestDB(t)
+		category := &domain.Category{Name: "Groceries", Type: "expense"}
+		require.NoError(t, db.Create(category).Error)
+		require.NoError(t, db.Create(&domain.Transaction{
+			UserID: 1, CategoryID: category.ID, Type: "expense",
+			Description: "corner grocery weekly shop", Amount: domain.NewMoney(42), Date: time.Now(),
+		}).Error)
+
+		service := NewReceiptService(db, &stubOCRProvider{text: receiptText}, testStorage(t.TempDir()))
+
+		result, err := service.ScanReceipt(context.Background(), 1, "receipt.png", "image/png", []byte("image"))
+		require.NoError(t, err)
+		require.NotNil(t, result.SuggestedCategoryID)
+		assert.Equal(t, category.ID, *result.SuggestedCategoryID)
+	})
+
+	t.Run("should strip directories from uploaded file name", func(t *testing.T) {
+		db := setupReceiptTestDB(t)
+		service := NewReceiptService(db, &stubOCRProvider{text: receiptText}, testStorage(t.TempDir()))
+
+		result, err := service.ScanReceipt(context.Background(), 1, "../../etc/passwd", "image/png", []byte("image"))
+		require.NoError(t, err)
+		assert.Equal(t, "passwd", result.Attachment.FileName)
+		assert.Regexp(t, `^attachments/1/\d+_passwd$`, result.Attachment.StoragePath)
+	})
+
+	t.Run("should fail on empty image", func(t *testing.T) {
+		db := setupReceiptTestDB(t)
+		service := NewReceiptService(db, &stubOCRProvider{text: receiptText}, testStorage(t.TempDir()))
+
+		_, err := service.ScanReceipt(context.Background(), 1, "receipt.png", "image/png", nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("should not store attachment when OCR fails", func(t *testing.T) {
+		db := setupReceiptTestDB(t)
+		dir := t.TempDir()
+		service := NewReceiptService(db, &stubOCRProvider{err: errors.New("ocr down")}, testStorage(dir))
+
+		_, err := service.ScanReceipt(context.Background(), 1, "receipt.png", "image/png", []byte("image"))
+		assert.Error(t, err)
+
+		var count int64
+		db.Model(&domain.Attachment{}).Count(&count)
+		assert.Equal(t, int64(0), count)
+
+		entries, _ := os.ReadDir(dir)
+		assert.Empty(t, entries)
+	})
+}
+
+func TestReceiptService_AttachmentLink(t *testing.T) {
+	db := setupReceiptTestDB(t)
+	service := NewReceiptService(db, &stubOCRProvider{text: "Corner Grocery\nTotal 7.00\n"}, testStorage(t.TempDir()))
+	result, err := service.ScanReceipt(context.Background(), 1, "receipt.png", "image/png", []byte("image"))
+	require.NoError(t, err)
+
+	link, err := service.AttachmentLink(context.Background(), 1, result.Attachment.ID)
+	require.NoError(t, err)
+	assert.Contains(t, link.URL, "/files/"+result.Attachment.StoragePath+"?expires=")
+	assert.Equal(t, "receipt.png", link.FileName)
+	assert.WithinDuration(t, time.Now().Add(fileLinkTTL), link.ExpiresAt, time.Minute)
+
+	_, err = service.AttachmentLink(context.Background(), 2, result.Attachment.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	_, err = service.AttachmentLink(context.Background(), 1, 99)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
@@ -1,6 +1,8 @@
 package application
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -9,6 +11,9 @@ import (
 	"gorm.io/gorm"
 )
 
+const defaultReportHistoryLimit = 50
+
+// ReportsService generates financial reports and keeps each one it generates
 type ReportsService struct {
 	DB *gorm.DB
 }
@@ -18,15 +23,15 @@ func NewReportsService(db *gorm.DB) *ReportsService {
 }
 
 // GenerateMonthlyReport generates a comprehensive monthly financial report
-func (s *ReportsService) GenerateMonthlyReport(userID uint, year, month int) (*domain.FinancialReport, error) {
+func (s *ReportsService) GenerateMonthlyReport(ctx context.Context, userID uint, year, month int) (*domain.FinancialReport, error) {
 	startDate := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
 	endDate := startDate.AddDate(0, 1, 0).Add(-time.Second)
 
-	return s.generateReport(userID, "monthly", startDate, endDate)
+	return s.generateReport(ctx, userID, domain.ReportTypeMonthly, startDate, endDate)
 }
 
 // GenerateQuarterlyReport generates a comprehensive quarterly financial report
-func (s *ReportsService) GenerateQuarterlyReport(userID uint, year, quarter int) (*domain.FinancialReport, error) {
+func (s *ReportsService) GenerateQuarterlyReport(ctx context.Context, userID uint, year, quarter int) (*domain.FinancialReport, error) {
 	var startMonth int
 	switch quarter {
 	case 1:
@@ -44,58 +49,104 @@ func (s *ReportsService) GenerateQuarterlyReport(userID uint, year, quarter int)
 	startDate := time.Date(year, time.Month(startMonth), 1, 0, 0, 0, 0, time.UTC)
 	endDate := startDate.AddDate(0, 3, 0).Add(-time.Second)
 
-	return s.generateReport(userID, "quarterly", startDate, endDate)
+	return s.generateReport(ctx, userID, domain.ReportTypeQuarterly, startDate, endDate)
 }
 
 // GenerateYearlyReport generates a comprehensive yearly financial report
-func (s *ReportsService) GenerateYearlyReport(userID uint, year int) (*domain.FinancialReport, error) {
+func (s *ReportsService) GenerateYearlyReport(ctx context.Context, userID uint, year int) (*domain.FinancialReport, error) {
 	startDate := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
 	endDate := time.Date(year+1, 1, 1, 0, 0, 0, 0, time.UTC).Add(-time.Second)
 
-	return s.generateReport(userID, "yearly", startDate, endDate)
+	return s.generateReport(ctx, userID, domain.ReportTypeYearly, startDate, endDate)
 }
 
 // GenerateCustomReport generates a report for a custom date range
-func (s *ReportsService) GenerateCustomReport(userID uint, startDate, endDate time.Time) (*domain.FinancialReport, error) {
-	return s.generateReport(userID, "custom", startDate, endDate)
+func (s *ReportsService) GenerateCustomReport(
+	ctx context.Context, userID uint, startDate, endDate time.Time,
+) (*domain.FinancialReport, error) {
+	return s.generateReport(ctx, userID, domain.ReportTypeCustom, startDate, endDate)
+}
+
+// CompareReports compares two periods given as labels such as 2024-01,
+// 2024-Q1, 2024 or 2024-01-01..2024-01-15. The compared reports are not kept.
+func (s *ReportsService) CompareReports(
+	ctx context.Context, userID uint, basePeriod, targetPeriod string,
+) (*domain.ReportComparison, error) {
+	base, err := s.periodReport(ctx, userID, basePeriod)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.periodReport(ctx, userID, targetPeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := domain.CompareReports(base, target)
+	return &comparison, nil
+}
+
+func (s *ReportsService) periodReport(ctx context.Context, userID uint, period string) (*domain.FinancialReport, error) {
+	reportType, startDate, endDate, err := domain.ParseReportPeriod(period)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildReport(ctx, userID, reportType, startDate, endDate)
+}
+
+// generateReport builds a report and keeps it
+func (s *ReportsService) generateReport(
+	ctx context.Context, userID uint, reportType string, startDate, endDate time.Time,
+) (*domain.FinancialReport, error) {
+	report, err := s.buildReport(ctx, userID, reportType, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.DB.WithContext(ctx).Create(report).Error; err != nil {
+		return nil, err
+	}
+	return report, nil
 }
 
-func (s *ReportsService) generateReport(userID uint, reportType string, startDate, endDate time.Time) (*domain.FinancialReport, error) {
+func (s *ReportsService) buildReport(
+	ctx context.Context, userID uint, reportType string, startDate, endDate time.Time,
+) (*domain.FinancialReport, error) {
 	// Get all transactions for the period
 	var transactions []domain.Transaction
-	err := s.DB.Preload("Category").Where("user_id = ? AND date BETWEEN ? AND ?", userID, startDate, endDate).Find(&transactions).Error
+	err := s.DB.WithContext(ctx).Preload("Category").
+		Where("user_id = ? AND date BETWEEN ? AND ? AND type <> ?", userID, startDate, endDate, domain.TransactionTypeTransfer).
+		Find(&transactions).Error
 	if err != nil {
 		return nil, err
 	}
 
 	// Calculate basic metrics
-	totalIncome := 0.0
-	totalExpenses := 0.0
+	var income, expenses domain.Money
 	transactionCount := len(transactions)
 
 	for i := range transactions {
 		tx := &transactions[i]
 		if tx.Type == "income" {
-			totalIncome += tx.Amount
+			income += tx.Amount
 		} else {
-			totalExpenses += tx.Amount
+			expenses += tx.Amount
 		}
 	}
 
-	netIncome := totalIncome - totalExpenses
-	savingsRate := 0.0
-	if totalIncome > 0 {
-		savingsRate = (netIncome / totalIncome) * 100
+	totalIncome, totalExpenses := income.Float64(), expenses.Float64()
+	netIncome := (income - expenses).Float64()
+	savingsRate := (income - expenses).PercentOf(income)
+	if income <= 0 {
+		savingsRate = 0
 	}
 
 	// Calculate category breakdown
-	categoryBreakdown := s.calculateCategoryBreakdown(transactions)
+	categoryBreakdown := rollUpCategoryBreakdown(ctx, s.DB, s.calculateCategoryBreakdown(transactions))
 
 	// Calculate monthly trends (for quarterly and yearly reports)
-	monthlyTrends := s.calculateMonthlyTrends(userID, startDate, endDate)
+	monthlyTrends := s.calculateMonthlyTrends(ctx, userID, startDate, endDate)
 
 	// Get budget performance
-	budgetPerformance := s.calculateBudgetPerformance(userID, startDate, endDate)
+	budgetPerformance := s.calculateBudgetPerformance(ctx, userID, startDate, endDate)
 
 	// Calculate top categories
 	topIncomeCategories := s.getTopCategories(categoryBreakdown, "income", 5)
@@ -124,12 +175,79 @@ func (s *ReportsService) generateReport(userID uint, reportType string, startDat
 		Recommendations:      recommendations,
 		GeneratedAt:          time.Now(),
 	}
+	report.Period = report.PeriodLabel()
 
 	return report, nil
 }
 
+// ListReports returns summaries of the user's stored reports, newest first.
+// A non-empty reportType narrows them down to one type.
+func (s *ReportsService) ListReports(
+	ctx context.Context, userID uint, reportType string, limit int,
+) ([]domain.ReportSummary, error) {
+	if reportType != "" && !domain.IsValidReportType(reportType) {
+		return nil, domain.ErrInvalidReportType
+	}
+	if limit <= 0 {
+		limit = defaultReportHistoryLimit
+	}
+
+	query := s.DB.WithContext(ctx).
+		Select("id, report_type, period, start_date, end_date, total_income, total_expenses, net_income, savings_rate, generated_at").
+		Where("user_id = ?", userID)
+	if reportType != "" {
+		query = query.Where("report_type = ?", reportType)
+	}
+	var reports []domain.FinancialReport
+	if err := query.Order("generated_at DESC, id DESC").Limit(limit).Find(&reports).Error; err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, len(reports))
+	for i := range reports {
+		ids[i] = reports[i].ID
+	}
+	comments, err := countComments(ctx, s.DB, domain.CommentTargetReport, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]domain.ReportSummary, len(reports))
+	for i := range reports {
+		summaries[i] = reports[i].ToSummary()
+		summaries[i].CommentCount = comments[reports[i].ID]
+	}
+	return summaries, nil
+}
+
+// GetReport returns one of the user's stored reports
+func (s *ReportsService) GetReport(ctx context.Context, userID, id uint) (*domain.FinancialReport, error) {
+	var report domain.FinancialReport
+	err := s.DB.WithContext(ctx).Where("user_id = ?", userID).First(&report, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// DeleteReport removes one of the user's stored reports
+func (s *ReportsService) DeleteReport(ctx context.Context, userID, id uint) error {
+	result := s.DB.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&domain.FinancialReport{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
 func (s *ReportsService) calculateCategoryBreakdown(transactions []domain.Transaction) []domain.CategoryMetrics {
 	categoryMap := make(map[uint]*domain.CategoryMetrics)
+	categoryTotals := make(map[uint]domain.Money)
 
 	for i := range transactions {
 		tx := &transactions[i]
@@ -146,21 +264,24 @@ func (s *ReportsService) calculateCategoryBreakdown(transactions []domain.Transa
 				TransactionCount:  0,
 				AverageAmount:     0,
 				Trend:             "stable",
+				Uncategorized:     tx.Category.ID == 0 || tx.Category.IsSystem,
 			}
 		}
 
-		categoryMap[tx.CategoryID].TotalAmount += tx.Amount
+		categoryTotals[tx.CategoryID] += tx.Amount
 		categoryMap[tx.CategoryID].TransactionCount++
 	}
 
 	// Convert map to slice and calculate percentages
 	var categories []domain.CategoryMetrics
-	totalAmount := 0.0
+	var total domain.Money
 
-	for _, category := range categoryMap {
-		totalAmount += category.TotalAmount
+	for categoryID, category := range categoryMap {
+		total += categoryTotals[categoryID]
+		category.TotalAmount = categoryTotals[categoryID].Float64()
 		categories = append(categories, *category)
 	}
+	totalAmount := total.Float64()
 
 	// Calculate percentages and average amounts
 	for i := range categories {
@@ -175,66 +296,59 @@ func (s *ReportsService) calculateCategoryBreakdown(transactions []domain.Transa
 	return categories
 }
 
-func (s *ReportsService) calculateMonthlyTrends(userID uint, startDate, endDate time.Time) []domain.MonthlyTrend {
-	var trends []domain.MonthlyTrend
-
-	current := startDate
-	for current.Before(endDate) {
-		nextMonth := current.AddDate(0, 1, 0)
-		if nextMonth.After(endDate) {
-			nextMonth = endDate
+// calculateMonthlyTrends totals the report period month by month, the last
+// month ending with the period
+func (s *ReportsService) calculateMonthlyTrends(ctx context.Context, userID uint, startDate, endDate time.Time) []domain.MonthlyTrend {
+	var months []dateRange
+	for current := startDate; current.Before(endDate); current = current.AddDate(0, 1, 0) {
+		monthEnd := current.AddDate(0, 1, 0).Add(-time.Second)
+		if monthEnd.After(endDate) {
+			monthEnd = endDate
 		}
+		months = append(months, dateRange{Start: current, End: monthEnd})
+	}
 
-		var transactions []domain.Transaction
-		s.DB.Where("user_id = ? AND date BETWEEN ? AND ?", userID, current, nextMonth).Find(&transactions)
-
-		income := 0.0
-		expenses := 0.0
-		for i := range transactions {
-			tx := &transactions[i]
-			if tx.Type == "income" {
-				income += tx.Amount
-			} else {
-				expenses += tx.Amount
-			}
-		}
+	totals, err := sumByPeriod(ctx, s.DB, userID, months)
+	if err != nil {
+		totals = make([]periodTotals, len(months))
+	}
 
-		trend := domain.MonthlyTrend{
-			Month:       current.Format("2006-01"),
-			Income:      income,
-			Expenses:    expenses,
-			NetIncome:   income - expenses,
+	trends := make([]domain.MonthlyTrend, 0, len(months))
+	for i, month := range months {
+		trends = append(trends, domain.MonthlyTrend{
+			Month:       month.Start.Format("2006-01"),
+			Income:      totals[i].Income.Float64(),
+			Expenses:    totals[i].Expenses.Float64(),
+			NetIncome:   (totals[i].Income - totals[i].Expenses).Float64(),
 			SavingsRate: 0,
-		}
-
-		trends = append(trends, trend)
-		current = nextMonth
+		})
 	}
 
 	return trends
 }
 
-func (s *ReportsService) calculateBudgetPerformance(userID uint, startDate, endDate time.Time) domain.BudgetPerformanceMetrics {
+func (s *ReportsService) calculateBudgetPerformance(
+	ctx context.Context, userID uint, startDate, endDate time.Time,
+) domain.BudgetPerformanceMetrics {
 	var budgets []domain.Budget
-	s.DB.Preload("Category").Where("user_id = ?", userID).Find(&budgets)
+	s.DB.WithContext(ctx).Preload("Category").Where("user_id = ?", userID).Find(&budgets)
 
-	totalBudget := 0.0
-	totalSpent := 0.0
+	var budgeted, spent domain.Money
 	budgetsOnTrack := 0
 	budgetsOverspent := 0
 
 	for i := range budgets {
 		budget := &budgets[i]
-		totalBudget += budget.Amount
+		budgeted += budget.Amount
 
 		// Calculate spent amount for this budget's category
-		var spentAmount float64
-		s.DB.Model(&domain.Transaction{}).
-			Where("user_id = ? AND category_id = ? AND date BETWEEN ? AND ?",
-				userID, budget.CategoryID, startDate, endDate).
+		var spentAmount domain.Money
+		s.DB.WithContext(ctx).Model(&domain.Transaction{}).
+			Where("user_id = ? AND category_id = ? AND date BETWEEN ? AND ? AND type <> ?",
+				userID, budget.CategoryID, startDate, endDate, domain.TransactionTypeTransfer).
 			Select("COALESCE(SUM(amount), 0)").Scan(&spentAmount)
 
-		totalSpent += spentAmount
+		spent += spentAmount
 
 		if spentAmount <= budget.Amount {
 			budgetsOnTrack++
@@ -244,14 +358,14 @@ func (s *ReportsService) calculateBudgetPerformance(userID uint, startDate, endD
 	}
 
 	var variancePercentage float64
-	if totalBudget > 0 {
-		variancePercentage = ((totalBudget - totalSpent) / totalBudget) * 100
+	if budgeted > 0 {
+		variancePercentage = (budgeted - spent).PercentOf(budgeted)
 	}
 
 	return domain.BudgetPerformanceMetrics{
-		TotalBudgeted:         totalBudget,
-		TotalSpent:            totalSpent,
-		Variance:              totalBudget - totalSpent,
+		TotalBudgeted:         budgeted.Float64(),
+		TotalSpent:            spent.Float64(),
+		Variance:              (budgeted - spent).Float64(),
 		VariancePercentage:    variancePercentage,
 		CategoriesUnderBudget: budgetsOnTrack,
 		CategoriesOverBudget:  budgetsOverspent,
@@ -1,6 +1,7 @@
 package application
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -19,7 +20,8 @@ func setupReportsTestDB() *gorm.DB {
 	}
 
 	// Auto migrate the schema
-	err = db.AutoMigrate(&domain.User{}, &domain.Category{}, &domain.Transaction{}, &domain.Budget{}, &domain.FinancialReport{})
+	err = db.AutoMigrate(&domain.User{}, &domain.Category{}, &domain.Transaction{}, &domain.Budget{},
+		&domain.FinancialReport{}, &domain.Comment{})
 	if err != nil {
 		panic("failed to migrate database")
 	}
@@ -57,7 +59,7 @@ func createReportsTestData(db *gorm.DB) (userID, categoryID1, categoryID2 uint)
 		{
 			UserID:      user.ID,
 			CategoryID:  incomeCategory.ID,
-			Amount:      5000.00,
+			Amount:      domain.NewMoney(5000.00),
 			Type:        "income",
 			Description: "January Salary",
 			Date:        time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
@@ -65,7 +67,7 @@ func createReportsTestData(db *gorm.DB) (userID, categoryID1, categoryID2 uint)
 		{
 			UserID:      user.ID,
 			CategoryID:  expenseCategory.ID,
-			Amount:      300.00,
+			Amount:      domain.NewMoney(300.00),
 			Type:        "expense",
 			Description: "Weekly groceries",
 			Date:        time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC),
@@ -73,7 +75,7 @@ func createReportsTestData(db *gorm.DB) (userID, categoryID1, categoryID2 uint)
 		{
 			UserID:      user.ID,
 			CategoryID:  expenseCategory.ID,
-			Amount:      250.00,
+			Amount:      domain.NewMoney(250.00),
 			Type:        "expense",
 			Description: "More groceries",
 			Date:        time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
@@ -81,7 +83,7 @@ func createReportsTestData(db *gorm.DB) (userID, categoryID1, categoryID2 uint)
 		{
 			UserID:      user.ID,
 			CategoryID:  incomeCategory.ID,
-			Amount:      1000.00,
+			Amount:      domain.NewMoney(1000.00),
 			Type:        "income",
 			Description: "Bonus",
 			Date:        time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC),
@@ -96,7 +98,7 @@ func createReportsTestData(db *gorm.DB) (userID, categoryID1, categoryID2 uint)
 	budget := domain.Budget{
 		UserID:     user.ID,
 		CategoryID: expenseCategory.ID,
-		Amount:     600.00,
+		Amount:     domain.NewMoney(600.00),
 		Period:     "monthly",
 		StartDate:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
 		EndDate:    time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC),
@@ -113,7 +115,7 @@ func TestReportsService_GenerateMonthlyReport(t *testing.T) {
 	userID, _, _ := createReportsTestData(db)
 
 	t.Run("generate monthly report with data", func(t *testing.T) {
-		report, err := service.GenerateMonthlyReport(userID, 2024, 1)
+		report, err := service.GenerateMonthlyReport(context.Background(), userID, 2024, 1)
 
 		require.NoError(t, err)
 		assert.NotNil(t, report)
@@ -129,7 +131,7 @@ func TestReportsService_GenerateMonthlyReport(t *testing.T) {
 	})
 
 	t.Run("generate monthly report for period with no data", func(t *testing.T) {
-		report, err := service.GenerateMonthlyReport(userID, 2024, 2)
+		report, err := service.GenerateMonthlyReport(context.Background(), userID, 2024, 2)
 
 		require.NoError(t, err)
 		assert.NotNil(t, report)
@@ -142,7 +144,7 @@ func TestReportsService_GenerateMonthlyReport(t *testing.T) {
 	})
 
 	t.Run("generate monthly report for non-existent user", func(t *testing.T) {
-		report, err := service.GenerateMonthlyReport(99999, 2024, 1)
+		report, err := service.GenerateMonthlyReport(context.Background(), 99999, 2024, 1)
 
 		require.NoError(t, err)
 		assert.NotNil(t, report)
@@ -156,7 +158,7 @@ func TestReportsService_GenerateQuarterlyReport(t *testing.T) {
 	userID, _, _ := createReportsTestData(db)
 
 	t.Run("generate Q1 quarterly report", func(t *testing.T) {
-		report, err := service.GenerateQuarterlyReport(userID, 2024, 1)
+		report, err := service.GenerateQuarterlyReport(context.Background(), userID, 2024, 1)
 
 		require.NoError(t, err)
 		assert.NotNil(t, report)
@@ -169,7 +171,7 @@ func TestReportsService_GenerateQuarterlyReport(t *testing.T) {
 	})
 
 	t.Run("generate quarterly report with invalid quarter", func(t *testing.T) {
-		report, err := service.GenerateQuarterlyReport(userID, 2024, 5)
+		report, err := service.GenerateQuarterlyReport(context.Background(), userID, 2024, 5)
 
 		assert.Error(t, err)
 		assert.Nil(t, report)
@@ -177,7 +179,7 @@ func TestReportsService_GenerateQuarterlyReport(t *testing.T) {
 	})
 
 	t.Run("generate Q2 quarterly report", func(t *testing.T) {
-		report, err := service.GenerateQuarterlyReport(userID, 2024, 2)
+		report, err := service.GenerateQuarterlyReport(context.Background(), userID, 2024, 2)
 
 		require.NoError(t, err)
 		assert.NotNil(t, report)
@@ -186,14 +188,14 @@ func TestReportsService_GenerateQuarterlyReport(t *testing.T) {
 	})
 
 	t.Run("generate Q3 quarterly report", func(t *testing.T) {
-		report, err := service.GenerateQuarterlyReport(userID, 2024, 3)
+		report, err := service.GenerateQuarterlyReport(context.Background(), userID, 2024, 3)
 
 		require.NoError(t, err)
 		assert.Equal(t, time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), report.StartDate)
 	})
 
 	t.Run("generate Q4 quarterly report", func(t *testing.T) {
-		report, err := service.GenerateQuarterlyReport(userID, 2024, 4)
+		report, err := service.GenerateQuarterlyReport(context.Background(), userID, 2024, 4)
 
 		require.NoError(t, err)
 		assert.Equal(t, time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC), report.StartDate)
@@ -206,7 +208,7 @@ func TestReportsService_GenerateYearlyReport(t *testing.T) {
 	userID, _, _ := createReportsTestData(db)
 
 	t.Run("generate yearly report with data", func(t *testing.T) {
-		report, err := service.GenerateYearlyReport(userID, 2024)
+		report, err := service.GenerateYearlyReport(context.Background(), userID, 2024)
 
 		require.NoError(t, err)
 		assert.NotNil(t, report)
@@ -220,7 +222,7 @@ func TestReportsService_GenerateYearlyReport(t *testing.T) {
 	})
 
 	t.Run("generate yearly report for different year", func(t *testing.T) {
-		report, err := service.GenerateYearlyReport(userID, 2023)
+		report, err := service.GenerateYearlyReport(context.Background(), userID, 2023)
 
 		require.NoError(t, err)
 		assert.NotNil(t, report)
@@ -239,7 +241,7 @@ func TestReportsService_GenerateCustomReport(t *testing.T) {
 		startDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
 		endDate := time.Date(2024, 1, 15, 23, 59, 59, 0, time.UTC)
 
-		report, err := service.GenerateCustomReport(userID, startDate, endDate)
+		report, err := service.GenerateCustomReport(context.Background(), userID, startDate, endDate)
 
 		require.NoError(t, err)
 		assert.NotNil(t, report)
@@ -257,7 +259,7 @@ func TestReportsService_GenerateCustomReport(t *testing.T) {
 		startDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
 		endDate := time.Date(2024, 1, 1, 23, 59, 59, 0, time.UTC)
 
-		report, err := service.GenerateCustomReport(userID, startDate, endDate)
+		report, err := service.GenerateCustomReport(context.Background(), userID, startDate, endDate)
 
 		require.NoError(t, err)
 		assert.NotNil(t, report)
@@ -270,7 +272,7 @@ func TestReportsService_GenerateCustomReport(t *testing.T) {
 		startDate := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
 		endDate := time.Date(2024, 2, 28, 23, 59, 59, 0, time.UTC)
 
-		report, err := service.GenerateCustomReport(userID, startDate, endDate)
+		report, err := service.GenerateCustomReport(context.Background(), userID, startDate, endDate)
 
 		require.NoError(t, err)
 		assert.NotNil(t, report)
@@ -286,7 +288,7 @@ func TestReportsService_Integration(t *testing.T) {
 	userID, incomeID, expenseID := createReportsTestData(db)
 
 	t.Run("report includes category breakdown", func(t *testing.T) {
-		report, err := service.GenerateMonthlyReport(userID, 2024, 1)
+		report, err := service.GenerateMonthlyReport(context.Background(), userID, 2024, 1)
 
 		require.NoError(t, err)
 		assert.NotEmpty(t, report.CategoryBreakdown)
@@ -310,7 +312,7 @@ func TestReportsService_Integration(t *testing.T) {
 	})
 
 	t.Run("report calculates savings rate correctly", func(t *testing.T) {
-		report, err := service.GenerateMonthlyReport(userID, 2024, 1)
+		report, err := service.GenerateMonthlyReport(context.Background(), userID, 2024, 1)
 
 		require.NoError(t, err)
 		expectedSavingsRate := ((6000.0 - 550.0) / 6000.0) * 100
@@ -318,7 +320,7 @@ func TestReportsService_Integration(t *testing.T) {
 	})
 
 	t.Run("report includes insights and recommendations", func(t *testing.T) {
-		report, err := service.GenerateMonthlyReport(userID, 2024, 1)
+		report, err := service.GenerateMonthlyReport(context.Background(), userID, 2024, 1)
 
 		require.NoError(t, err)
 		assert.NotEmpty(t, report.Insights)
@@ -326,7 +328,7 @@ func TestReportsService_Integration(t *testing.T) {
 	})
 
 	t.Run("report includes budget performance", func(t *testing.T) {
-		report, err := service.GenerateMonthlyReport(userID, 2024, 1)
+		report, err := service.GenerateMonthlyReport(context.Background(), userID, 2024, 1)
 
 		require.NoError(t, err)
 		assert.NotEmpty(t, report.BudgetPerformance)
@@ -337,3 +339,107 @@ func TestReportsService_Integration(t *testing.T) {
 		assert.NotZero(t, report.BudgetPerformance.CategoriesUnderBudget+report.BudgetPerformance.CategoriesOverBudget)
 	})
 }
+
+func TestReportsService_StoredReports(t *testing.T) {
+	db := setupReportsTestDB()
+	userID, _, _ := createReportsTestData(db)
+	service := NewReportsService(db)
+	ctx := context.Background()
+
+	monthly, err := service.GenerateMonthlyReport(ctx, userID, 2024, 1)
+	require.NoError(t, err)
+	quarterly, err := service.GenerateQuarterlyReport(ctx, userID, 2024, 1)
+	require.NoError(t, err)
+	custom, err := service.GenerateCustomReport(ctx, userID,
+		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 15, 23, 59, 59, 0, time.UTC))
+	require.NoError(t, err)
+	assert.NotZero(t, monthly.ID)
+	assert.Equal(t, "2024-01", monthly.Period)
+	assert.Equal(t, "2024-Q1", quarterly.Period)
+	assert.Equal(t, "2024-01-01..2024-01-15", custom.Period)
+
+	t.Run("lists history newest first", func(t *testing.T) {
+		reports, err := service.ListReports(ctx, userID, "", 0)
+		require.NoError(t, err)
+		require.Len(t, reports, 3)
+		assert.Equal(t, custom.ID, reports[0].ID)
+		assert.Equal(t, quarterly.ID, reports[1].ID)
+		assert.Equal(t, "quarterly", reports[1].ReportType)
+		assert.Equal(t, monthly.NetIncome, reports[2].NetIncome)
+
+		reports, err = service.ListReports(ctx, userID, "monthly", 0)
+		require.NoError(t, err)
+		require.Len(t, reports, 1)
+		assert.Equal(t, monthly.ID, reports[0].ID)
+
+		reports, err = service.ListReports(ctx, userID, "custom", 0)
+		require.NoError(t, err)
+		require.Len(t, reports, 1)
+		assert.Equal(t, custom.Period, reports[0].Period)
+
+		_, err = service.ListReports(ctx, userID, "weekly", 0)
+		assert.ErrorIs(t, err, domain.ErrInvalidReportType)
+	})
+
+	t.Run("reopens a report as generated", func(t *testing.T) {
+		stored, err := service.GetReport(ctx, userID, monthly.ID)
+		require.NoError(t, err)
+		assert.Equal(t, monthly.TotalIncome, stored.TotalIncome)
+		assert.Equal(t, monthly.Insights, stored.Insights)
+		assert.Len(t, stored.CategoryBreakdown, len(monthly.CategoryBreakdown))
+		assert.Equal(t, monthly.BudgetPerformance.TotalBudgeted, stored.BudgetPerformance.TotalBudgeted)
+
+		_, err = service.GetReport(ctx, userID+1, monthly.ID)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("deletes only the user's reports", func(t *testing.T) {
+		assert.ErrorIs(t, service.DeleteReport(ctx, userID+1, monthly.ID), domain.ErrNotFound)
+		require.NoError(t, service.DeleteReport(ctx, userID, monthly.ID))
+		assert.ErrorIs(t, service.DeleteReport(ctx, userID, monthly.ID), domain.ErrNotFound)
+
+		reports, err := service.ListReports(ctx, userID, "", 0)
+		require.NoError(t, err)
+		assert.Len(t, reports, 2)
+	})
+}
+
+func TestReportsService_CompareReports(t *testing.T) {
+	db := setupReportsTestDB()
+	userID, incomeCategoryID, expenseCategoryID := createReportsTestData(db)
+	service := NewReportsService(db)
+	ctx := context.Background()
+
+	require.NoError(t, db.Create(&[]domain.Transaction{
+		{UserID: userID, CategoryID: incomeCategoryID, Amount: domain.NewMoney(5000), Type: "income", Date: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{UserID: userID, CategoryID: expenseCategoryID, Amount: domain.NewMoney(1000), Type: "expense", Date: time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC)},
+	}).Error)
+
+	comparison, err := service.CompareReports(ctx, userID, "2024-01", "2024-02")
+	require.NoError(t, err)
+	assert.Equal(t, "2024-01", comparison.Base.Period)
+	assert.Equal(t, "2024-02", comparison.Target.Period)
+	assert.Equal(t, -1000.0, comparison.Income.Change)
+	assert.Equal(t, 450.0, comparison.Expenses.Change)
+	assert.InDelta(t, 80-((6000.0-550.0)/6000.0*100), comparison.SavingsRateChange, 0.01)
+	require.Len(t, comparison.Categories, 2)
+	assert.Equal(t, "Salary", comparison.Categories[0].CategoryName)
+
+	t.Run("mixes period lengths", func(t *testing.T) {
+		comparison, err := service.CompareReports(ctx, userID, "2024-01-01..2024-01-10", "2024-Q1")
+		require.NoError(t, err)
+		assert.Equal(t, 5000.0, comparison.Income.Base)
+		assert.Equal(t, 11000.0, comparison.Income.Target)
+	})
+
+	t.Run("does not keep the compared reports", func(t *testing.T) {
+		reports, err := service.ListReports(ctx, userID, "", 0)
+		require.NoError(t, err)
+		assert.Empty(t, reports)
+	})
+
+	t.Run("rejects unknown periods", func(t *testing.T) {
+		_, err := service.CompareReports(ctx, userID, "last month", "2024-02")
+		assert.ErrorIs(t, err, domain.ErrInvalidReportPeriod)
+	})
+}
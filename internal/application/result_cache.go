@@ -0,0 +1,116 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+)
+
+// ResultCache keeps computed results per user until they expire or the
+// user's data changes. Values are JSON encoded so the cache can live outside
+// the process, e.g. in Redis, and implementations must be safe for
+// concurrent use.
+type ResultCache interface {
+	Get(ctx context.Context, userID uint, key string) ([]byte, bool)
+	Set(ctx context.Context, userID uint, key string, value []byte, ttl time.Duration)
+	InvalidateUser(ctx context.Context, userID uint)
+}
+
+type memoryCacheEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// MemoryCache is an in-process ResultCache
+type MemoryCache struct {
+	mu      sync.RWMutex
+	entries map[uint]map[string]memoryCacheEntry
+}
+
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[uint]map[string]memoryCacheEntry)}
+}
+
+// Get returns the value stored under the user's key unless it has expired
+func (c *MemoryCache) Get(_ context.Context, userID uint, key string) ([]byte, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.entries[userID][key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.value, true
+}
+
+// Set stores the value under the user's key for ttl
+func (c *MemoryCache) Set(_ context.Context, userID uint, key string, value []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	entries := c.entries[userID]
+	if entries == nil {
+		entries = make(map[string]memoryCacheEntry)
+		c.entries[userID] = entries
+	}
+	// Drop the user's expired results while we are here
+	for k, entry := range entries {
+		if now.After(entry.expiresAt) {
+			delete(entries, k)
+		}
+	}
+	entries[key] = memoryCacheEntry{value: value, expiresAt: now.Add(ttl)}
+}
+
+// InvalidateUser drops everything cached for the user
+func (c *MemoryCache) InvalidateUser(_ context.Context, userID uint) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, userID)
+}
+
+// cachedResult returns the user's result cached under key, computing and
+// caching it when there is none. Without a cache it simply computes it.
+func cachedResult[T any](
+	ctx context.Context, cache ResultCache, ttl time.Duration, userID uint, key string, compute func() (*T, error),
+) (*T, error) {
+	if cache == nil || ttl <= 0 {
+		return compute()
+	}
+
+	if data, ok := cache.Get(ctx, userID, key); ok {
+		var result T
+		if err := json.Unmarshal(data, &result); err == nil {
+			return &result, nil
+		}
+	}
+
+	result, err := compute()
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		log.Printf("cache: failed to encode %s for user %d: %v", key, userID, err)
+		return result, nil
+	}
+	cache.Set(ctx, userID, key, data, ttl)
+	return result, nil
+}
+
+// invalidateUsers drops the cached results of the users, once each
+func invalidateUsers(ctx context.Context, cache ResultCache, userIDs ...uint) {
+	if cache == nil {
+		return
+	}
+	seen := make(map[uint]bool, len(userIDs))
+	for _, userID := range userIDs {
+		if userID != 0 && !seen[userID] {
+			seen[userID] = true
+			cache.InvalidateUser(ctx, userID)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package application
+
+import (
+	"context"
+	"errors"
+
+	"go-finance-advisor/internal/config"
+	"go-finance-advisor/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// riskCategories maps risk tolerances onto the risk categories of the
+// advisor's recommended allocations
+var riskCategories = map[string]string{
+	"conservative": "low_risk_stable",
+	"moderate":     "moderate_risk",
+	"aggressive":   "high_risk_high_reward",
+}
+
+// RetirementService runs Monte Carlo simulations of how a user's portfolio
+// could grow until retirement
+type RetirementService struct {
+	DB *gorm.DB
+	// Savings provides the monthly contribution when a simulation leaves it out
+	Savings *AdvisorService
+	// Allocations per risk category used when a simulation gives none; the defaults when unset
+	Allocations   map[string][]config.AllocationShare
+	MaxIterations int
+}
+
+func NewRetirementService(db *gorm.DB, savings *AdvisorService, maxIterations int) *RetirementService {
+	return &RetirementService{DB: db, Savings: savings, MaxIterations: maxIterations}
+}
+
+// Simulate runs the simulation for the user. Without an allocation the
+// portfolio is allocated as recommended for the user's risk tolerance, and
+// without a monthly contribution the user puts in what they saved a month
+// on average over the last three months.
+func (s *RetirementService) Simulate(
+	ctx context.Context, userID uint, req domain.RetirementSimulationRequest,
+) (*domain.RetirementSimulation, error) {
+	maxIterations := s.MaxIterations
+	if maxIterations == 0 {
+		maxIterations = config.Default().Advisor.MaxSimulationIterations
+	}
+	if len(req.Allocation) == 0 {
+		allocation, err := s.recommendedAllocation(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		req.Allocation = allocation
+	}
+	if err := req.Validate(maxIterations); err != nil {
+		return nil, err
+	}
+
+	if req.MonthlyContribution == nil {
+		var contribution domain.Money
+		if s.Savings != nil {
+			savings, err := s.Savings.CalculateMonthlySavings(ctx, userID)
+			if err != nil {
+				return nil, err
+			}
+			contribution = max(domain.NewMoney(savings), 0)
+		}
+		req.MonthlyContribution = &contribution
+	}
+
+	simulation := domain.SimulateRetirement(req)
+	return &simulation, nil
+}
+
+// recommendedAllocation is the allocation recommended for the user's risk
+// tolerance, the moderate one for unknown tolerances
+func (s *RetirementService) recommendedAllocation(ctx context.Context, userID uint) ([]domain.RetirementAllocation, error) {
+	var user domain.User
+	err := s.DB.WithContext(ctx).Select("risk_tolerance").First(&user, userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	allocations := s.Allocations
+	if allocations == nil {
+		allocations = config.Default().AI.RiskAllocations
+	}
+	category, ok := riskCategories[user.RiskTolerance]
+	if !ok {
+		category = riskCategories["moderate"]
+	}
+
+	shares := allocations[category]
+	allocation := make([]domain.RetirementAllocation, 0, len(shares))
+	for _, share := range shares {
+		allocation = append(allocation, domain.RetirementAllocation{Asset: share.Asset, Share: share.Share})
+	}
+	return allocation, nil
+}
@@ -0,0 +1,62 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/config"
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetirementService_Simulate(t *testing.T) {
+	db := setupAdvisorTestDB(t)
+	require.NoError(t, db.Create(&domain.User{ID: 1, Email: "saver@example.com", RiskTolerance: "aggressive"}).Error)
+	require.NoError(t, db.Create(&[]domain.Transaction{
+		{UserID: 1, Amount: domain.NewMoney(3000), Type: "income", Description: "Salary", Date: time.Now().AddDate(0, 0, -10)},
+		{UserID: 1, Amount: domain.NewMoney(1500), Type: "expense", Description: "Rent", Date: time.Now().AddDate(0, 0, -5)},
+	}).Error)
+	service := NewRetirementService(db, &AdvisorService{DB: db}, 500)
+	ctx := context.Background()
+
+	t.Run("defaults come from the user", func(t *testing.T) {
+		simulation, err := service.Simulate(ctx, 1, domain.RetirementSimulationRequest{
+			Years: 10, Target: domain.NewMoney(50000), Iterations: 200, Seed: 7,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, domain.NewMoney(500), simulation.MonthlyContribution, "a third of three months' savings")
+		require.Len(t, simulation.Allocation, 4)
+		assert.Equal(t, "stocks", simulation.Allocation[0].Asset)
+		assert.Equal(t, 0.4, simulation.Allocation[1].Share, "aggressive users get the high risk allocation")
+		assert.Len(t, simulation.Bands, 10)
+		assert.Equal(t, 200, simulation.Iterations)
+	})
+
+	t.Run("configured allocations", func(t *testing.T) {
+		service := NewRetirementService(db, nil, 500)
+		service.Allocations = map[string][]config.AllocationShare{"high_risk_high_reward": {{Asset: "stocks", Share: 1}}}
+		simulation, err := service.Simulate(ctx, 1, domain.RetirementSimulationRequest{
+			Years: 5, Target: domain.NewMoney(1000), Iterations: 10,
+		})
+		require.NoError(t, err)
+		require.Len(t, simulation.Allocation, 1)
+		assert.Equal(t, "stocks", simulation.Allocation[0].Asset)
+		assert.Zero(t, simulation.MonthlyContribution, "nothing is contributed without savings to go by")
+	})
+
+	t.Run("iterations are bounded", func(t *testing.T) {
+		_, err := service.Simulate(ctx, 1, domain.RetirementSimulationRequest{
+			Years: 10, Target: domain.NewMoney(50000), Iterations: 501,
+		})
+		assert.ErrorIs(t, err, domain.ErrValidation)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		_, err := service.Simulate(ctx, 9, domain.RetirementSimulationRequest{Years: 10, Target: domain.NewMoney(50000)})
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
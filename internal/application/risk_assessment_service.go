@@ -0,0 +1,116 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"go-finance-advisor/internal/domain"
+
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+)
+
+const defaultRiskAssessmentHistoryLimit = 50
+
+// RiskAssessmentService serves the risk questionnaire and keeps the
+// assessments users complete. Submitting an assessment sets the user's risk
+// tolerance to the profile it gave.
+type RiskAssessmentService struct {
+	DB            *gorm.DB
+	Questionnaire *domain.RiskQuestionnaire // Served instead of domain.DefaultRiskQuestionnaire when set
+	Audit         *AuditService             // Records risk tolerance changes when set
+}
+
+func NewRiskAssessmentService(db *gorm.DB) *RiskAssessmentService {
+	return &RiskAssessmentService{DB: db}
+}
+
+// LoadRiskQuestionnaire reads a questionnaire from a YAML or JSON file and
+// checks that it can be answered
+func LoadRiskQuestionnaire(path string) (*domain.RiskQuestionnaire, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read risk questionnaire: %w", err)
+	}
+	// YAML is a superset of JSON, so one decoder reads both
+	var questionnaire domain.RiskQuestionnaire
+	if err := yaml.Unmarshal(data, &questionnaire); err != nil {
+		return nil, fmt.Errorf("parse risk questionnaire %s: %w", path, err)
+	}
+	if err := questionnaire.Validate(); err != nil {
+		return nil, fmt.Errorf("risk questionnaire %s: %w", path, err)
+	}
+	return &questionnaire, nil
+}
+
+// CurrentQuestionnaire returns the questionnaire users are asked to answer
+func (s *RiskAssessmentService) CurrentQuestionnaire() *domain.RiskQuestionnaire {
+	if s.Questionnaire != nil {
+		return s.Questionnaire
+	}
+	return &domain.DefaultRiskQuestionnaire
+}
+
+// Submit scores the user's answers to the current questionnaire, keeps the
+// assessment and updates the user's risk tolerance to match it
+func (s *RiskAssessmentService) Submit(ctx context.Context, userID uint, answers map[string]string) (*domain.RiskAssessment, error) {
+	questionnaire := s.CurrentQuestionnaire()
+	score, err := questionnaire.Score(answers)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := json.Marshal(answers)
+	if err != nil {
+		return nil, err
+	}
+
+	maxScore := questionnaire.MaxScore()
+	assessment := &domain.RiskAssessment{
+		UserID:               userID,
+		QuestionnaireVersion: questionnaire.Version,
+		Answers:              encoded,
+		Score:                score,
+		MaxScore:             maxScore,
+		RiskProfile:          domain.RiskProfileFor(score, maxScore),
+	}
+
+	var before, after domain.User
+	err = s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&before, userID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrNotFound
+			}
+			return err
+		}
+		if err := tx.Create(assessment).Error; err != nil {
+			return err
+		}
+		after = before
+		after.RiskTolerance = assessment.RiskProfile
+		return tx.Model(&after).Update("risk_tolerance", after.RiskTolerance).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	if before.RiskTolerance != after.RiskTolerance {
+		s.Audit.track(ctx, userID, domain.AuditEntityUser, userID, domain.AuditActionUpdate, &before, &after)
+	}
+	return assessment, nil
+}
+
+// History returns the user's past assessments, newest first
+func (s *RiskAssessmentService) History(ctx context.Context, userID uint, limit int) ([]domain.RiskAssessment, error) {
+	if limit <= 0 {
+		limit = defaultRiskAssessmentHistoryLimit
+	}
+	var assessments []domain.RiskAssessment
+	err := s.DB.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&assessments).Error
+	return assessments, err
+}
@@ -0,0 +1,133 @@
+package application
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupRiskAssessmentTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&domain.User{}, &domain.RiskAssessment{}, &domain.AuditLog{}))
+	return db
+}
+
+func TestRiskAssessmentService_Submit(t *testing.T) {
+	db := setupRiskAssessmentTestDB(t)
+	service := NewRiskAssessmentService(db)
+	service.Audit = NewAuditService(db)
+	ctx := context.Background()
+
+	user := domain.User{Email: "quiz@example.com", RiskTolerance: domain.RiskToleranceModerate}
+	require.NoError(t, db.Create(&user).Error)
+
+	assessment, err := service.Submit(ctx, user.ID, map[string]string{
+		"time_horizon": "c", "loss_reaction": "c", "investment_goal": "b",
+	})
+	require.NoError(t, err)
+	assert.NotZero(t, assessment.ID)
+	assert.Equal(t, 5, assessment.Score)
+	assert.Equal(t, 6, assessment.MaxScore)
+	assert.Equal(t, domain.RiskToleranceAggressive, assessment.RiskProfile)
+	assert.Equal(t, domain.DefaultRiskQuestionnaire.Version, assessment.QuestionnaireVersion)
+	assert.JSONEq(t, `{"time_horizon":"c","loss_reaction":"c","investment_goal":"b"}`, string(assessment.Answers))
+
+	var stored domain.User
+	require.NoError(t, db.First(&stored, user.ID).Error)
+	assert.Equal(t, domain.RiskToleranceAggressive, stored.RiskTolerance)
+
+	var audits int64
+	require.NoError(t, db.Model(&domain.AuditLog{}).Where("entity_type = ?", domain.AuditEntityUser).Count(&audits).Error)
+	assert.Equal(t, int64(1), audits)
+
+	t.Run("rejects incomplete answers", func(t *testing.T) {
+		_, err := service.Submit(ctx, user.ID, map[string]string{"time_horizon": "z", "mood": "a"})
+		assert.ErrorIs(t, err, domain.ErrValidation)
+	})
+
+	t.Run("unknown users are not found", func(t *testing.T) {
+		_, err := service.Submit(ctx, 999, map[string]string{
+			"time_horizon": "a", "loss_reaction": "a", "investment_goal": "a",
+		})
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+
+		var count int64
+		require.NoError(t, db.Model(&domain.RiskAssessment{}).Where("user_id = ?", 999).Count(&count).Error)
+		assert.Zero(t, count)
+	})
+}
+
+func TestRiskAssessmentService_History(t *testing.T) {
+	db := setupRiskAssessmentTestDB(t)
+	service := NewRiskAssessmentService(db)
+	ctx := context.Background()
+
+	user := domain.User{Email: "history@example.com"}
+	other := domain.User{Email: "other@example.com"}
+	require.NoError(t, db.Create(&user).Error)
+	require.NoError(t, db.Create(&other).Error)
+
+	for _, answer := range []string{"a", "b", "c"} {
+		_, err := service.Submit(ctx, user.ID, map[string]string{
+			"time_horizon": answer, "loss_reaction": answer, "investment_goal": answer,
+		})
+		require.NoError(t, err)
+	}
+	_, err := service.Submit(ctx, other.ID, map[string]string{
+		"time_horizon": "a", "loss_reaction": "a", "investment_goal": "a",
+	})
+	require.NoError(t, err)
+
+	history, err := service.History(ctx, user.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	assert.Equal(t, domain.RiskToleranceAggressive, history[0].RiskProfile)
+	assert.Equal(t, domain.RiskToleranceConservative, history[2].RiskProfile)
+
+	history, err = service.History(ctx, user.ID, 1)
+	require.NoError(t, err)
+	assert.Len(t, history, 1)
+}
+
+func TestRiskAssessmentService_ConfiguredQuestionnaire(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "questionnaire.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+version: "2025-2"
+questions:
+  - id: horizon
+    text: When will you need the money?
+    options:
+      - {id: soon, text: Within a year, score: 0}
+      - {id: later, text: In ten years or more, score: 4}
+`), 0o600))
+
+	questionnaire, err := LoadRiskQuestionnaire(path)
+	require.NoError(t, err)
+
+	db := setupRiskAssessmentTestDB(t)
+	service := &RiskAssessmentService{DB: db, Questionnaire: questionnaire}
+	assert.Equal(t, "2025-2", service.CurrentQuestionnaire().Version)
+
+	user := domain.User{Email: "custom@example.com"}
+	require.NoError(t, db.Create(&user).Error)
+	assessment, err := service.Submit(context.Background(), user.ID, map[string]string{"horizon": "soon"})
+	require.NoError(t, err)
+	assert.Equal(t, "2025-2", assessment.QuestionnaireVersion)
+	assert.Equal(t, domain.RiskToleranceConservative, assessment.RiskProfile)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"version": "broken", "questions": []}`), 0o600))
+	_, err = LoadRiskQuestionnaire(path)
+	assert.ErrorIs(t, err, domain.ErrValidation)
+}
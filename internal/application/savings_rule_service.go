@@ -0,0 +1,253 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"go-finance-advisor/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SavingsRuleService manages the rules that set aside part of users' income
+// for their goals, and applies them as income transactions are recorded
+type SavingsRuleService struct {
+	DB    *gorm.DB
+	Goals *GoalService // Adds the allocations to the goals; a service over DB is used when nil
+}
+
+func NewSavingsRuleService(db *gorm.DB) *SavingsRuleService {
+	return &SavingsRuleService{DB: db}
+}
+
+func (s *SavingsRuleService) goals() *GoalService {
+	if s.Goals != nil {
+		return s.Goals
+	}
+	return &GoalService{DB: s.DB}
+}
+
+// List returns the user's rules, oldest first
+func (s *SavingsRuleService) List(ctx context.Context, userID uint) ([]domain.SavingsRule, error) {
+	var rules []domain.SavingsRule
+	err := s.DB.WithContext(ctx).Where("user_id = ?", userID).Order("id").Find(&rules).Error
+	return rules, err
+}
+
+// Create adds a rule setting aside part of the user's income for a goal
+func (s *SavingsRuleService) Create(ctx context.Context, userID uint, rule domain.SavingsRule) (*domain.SavingsRule, error) {
+	rule.ID, rule.UserID = 0, userID
+	if err := s.check(ctx, &rule); err != nil {
+		return nil, err
+	}
+	if err := s.DB.WithContext(ctx).Create(&rule).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// Update replaces the goal, category, name, percentage and state of a rule.
+// Income recorded before is left as it was allocated.
+func (s *SavingsRuleService) Update(ctx context.Context, userID, ruleID uint, updates domain.SavingsRule) (*domain.SavingsRule, error) {
+	var rule domain.SavingsRule
+	if err := s.DB.WithContext(ctx).Where("id = ? AND user_id = ?", ruleID, userID).First(&rule).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	rule.GoalID = updates.GoalID
+	rule.CategoryID = updates.CategoryID
+	rule.Name = updates.Name
+	rule.Percent = updates.Percent
+	rule.IsActive = updates.IsActive
+	if err := s.check(ctx, &rule); err != nil {
+		return nil, err
+	}
+	if err := s.DB.WithContext(ctx).Save(&rule).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// Delete removes a rule. What it allocated stays with the goals.
+func (s *SavingsRuleService) Delete(ctx context.Context, userID, ruleID uint) error {
+	result := s.DB.WithContext(ctx).Where("id = ? AND user_id = ?", ruleID, userID).Delete(&domain.SavingsRule{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// Preview returns what the user's rules would set aside from income of the
+// amount, in the category when one is given
+func (s *SavingsRuleService) Preview(
+	ctx context.Context, userID uint, amount domain.Money, categoryID *uint,
+) (*domain.SavingsPreview, error) {
+	if amount <= 0 {
+		return nil, &domain.ValidationError{Fields: []domain.FieldError{{Field: "amount", Message: "must be greater than zero"}}}
+	}
+	rules, err := s.applicableRules(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	income := domain.Transaction{UserID: userID, Type: domain.TransactionTypeIncome, Amount: amount}
+	if categoryID != nil {
+		income.CategoryID = *categoryID
+	}
+	preview := domain.AllocateSavings(rules, &income)
+	return &preview, nil
+}
+
+// Allocations returns what the user's rules have set aside, newest first
+func (s *SavingsRuleService) Allocations(ctx context.Context, userID uint) ([]domain.SavingsAllocation, error) {
+	var allocations []domain.SavingsAllocation
+	err := s.DB.WithContext(ctx).Where("user_id = ?", userID).Order("id DESC").Find(&allocations).Error
+	return allocations, err
+}
+
+// check validates the rule and that its goal and category are the user's.
+// The active rules that can apply to the same income must not set aside
+// more than all of it.
+func (s *SavingsRuleService) check(ctx context.Context, rule *domain.SavingsRule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+
+	var count int64
+	err := s.DB.WithContext(ctx).Model(&domain.FinancialGoal{}).
+		Where("id = ? AND user_id = ?", rule.GoalID, rule.UserID).Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return &domain.ValidationError{Fields: []domain.FieldError{{Field: "goal_id", Message: "must be one of your goals"}}}
+	}
+	if rule.CategoryID != nil {
+		err := s.DB.WithContext(ctx).Model(&domain.Category{}).
+			Where("id = ? AND (user_id IS NULL OR user_id = ?) AND type = ?", *rule.CategoryID, rule.UserID, domain.TransactionTypeIncome).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return &domain.ValidationError{Fields: []domain.FieldError{
+				{Field: "category_id", Message: "must be one of your income categories"},
+			}}
+		}
+	}
+	if !rule.IsActive {
+		return nil
+	}
+
+	var others []domain.SavingsRule
+	err = s.DB.WithContext(ctx).Where("user_id = ? AND is_active = ? AND id <> ?", rule.UserID, true, rule.ID).Find(&others).Error
+	if err != nil {
+		return err
+	}
+	// Rules on any income apply together with those of each category
+	var anyIncome, mostInCategory float64
+	perCategory := make(map[uint]float64)
+	for _, other := range append(others, *rule) {
+		if other.CategoryID == nil {
+			anyIncome += other.Percent
+			continue
+		}
+		perCategory[*other.CategoryID] += other.Percent
+		mostInCategory = max(mostInCategory, perCategory[*other.CategoryID])
+	}
+	if anyIncome+mostInCategory > 100 {
+		return &domain.ValidationError{Fields: []domain.FieldError{
+			{Field: "percent", Message: "would take your rules over 100% of the same income"},
+		}}
+	}
+	return nil
+}
+
+// applicableRules returns the user's active rules whose goal is still being
+// saved for; completed and paused goals receive nothing
+func (s *SavingsRuleService) applicableRules(ctx context.Context, userID uint) ([]domain.SavingsRule, error) {
+	var rules []domain.SavingsRule
+	err := s.DB.WithContext(ctx).
+		Joins("JOIN financial_goals ON financial_goals.id = savings_rules.goal_id AND financial_goals.status = ?", domain.GoalStatusActive).
+		Where("savings_rules.user_id = ? AND savings_rules.is_active = ?", userID, true).
+		Order("savings_rules.id").Find(&rules).Error
+	return rules, err
+}
+
+// Allocate sets aside part of an income transaction for the goals of the
+// rules that apply to it and returns the allocations made. Income that was
+// allocated before is not allocated again.
+func (s *SavingsRuleService) Allocate(ctx context.Context, transaction *domain.Transaction) ([]domain.SavingsAllocation, error) {
+	if transaction.Type != domain.TransactionTypeIncome {
+		return nil, nil
+	}
+	rules, err := s.applicableRules(ctx, transaction.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	var made []domain.SavingsAllocation
+	for _, allocation := range domain.AllocateSavings(rules, transaction).Allocations {
+		result := s.DB.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&allocation)
+		if result.Error != nil {
+			return made, result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+		if _, err := s.goals().Contribute(ctx, allocation.UserID, allocation.GoalID, allocation.Amount); err != nil {
+			s.DB.WithContext(ctx).Delete(&allocation)
+			return made, err
+		}
+		made = append(made, allocation)
+	}
+	return made, nil
+}
+
+// Release takes what was set aside from a deleted income transaction back
+// out of the goals
+func (s *SavingsRuleService) Release(ctx context.Context, transaction *domain.Transaction) error {
+	var allocations []domain.SavingsAllocation
+	if err := s.DB.WithContext(ctx).Where("transaction_id = ?", transaction.ID).Find(&allocations).Error; err != nil {
+		return err
+	}
+	for i := range allocations {
+		_, err := s.goals().Contribute(ctx, allocations[i].UserID, allocations[i].GoalID, -allocations[i].Amount)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return err
+		}
+		if err := s.DB.WithContext(ctx).Delete(&allocations[i]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Subscribe allocates income as it is recorded or restored, and releases it
+// when it is deleted
+func (s *SavingsRuleService) Subscribe(bus *EventBus) {
+	bus.Subscribe(s.handleEvent, domain.EventTransactionCreated, domain.EventTransactionDeleted, domain.EventTransactionRestored)
+}
+
+// handleEvent logs failures; the income is recorded either way
+func (s *SavingsRuleService) handleEvent(ctx context.Context, event domain.Event) {
+	var err error
+	switch e := event.(type) {
+	case domain.TransactionCreated:
+		_, err = s.Allocate(ctx, &e.Transaction)
+	case domain.TransactionRestored:
+		_, err = s.Allocate(ctx, &e.Transaction)
+	case domain.TransactionDeleted:
+		err = s.Release(ctx, &e.Transaction)
+	}
+	if err != nil {
+		log.Printf("savings rules: failed to allocate income: %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupSavingsRuleTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&domain.Category{}, &domain.Transaction{}, &domain.TransactionTag{}, &domain.FinancialGoal{},
+		&domain.SavingsRule{}, &domain.SavingsAllocation{},
+	))
+	return db
+}
+
+func savingsGoal(userID uint, title, status string, target float64) domain.FinancialGoal {
+	return domain.FinancialGoal{UserID: userID, Title: title, GoalType: "savings", Status: status, TargetAmount: domain.NewMoney(target)}
+}
+
+func TestSavingsRuleService_Rules(t *testing.T) {
+	db := setupSavingsRuleTestDB(t)
+	service := NewSavingsRuleService(db)
+	ctx := context.Background()
+
+	salary := &domain.Category{Name: "Salary", Type: domain.TransactionTypeIncome}
+	groceries := &domain.Category{Name: "Groceries", Type: domain.TransactionTypeExpense}
+	require.NoError(t, db.Create(salary).Error)
+	require.NoError(t, db.Create(groceries).Error)
+	house := savingsGoal(1, "House", domain.GoalStatusActive, 1000)
+	other := savingsGoal(2, "Car", domain.GoalStatusActive, 1000)
+	require.NoError(t, db.Create(&house).Error)
+	require.NoError(t, db.Create(&other).Error)
+
+	rule, err := service.Create(ctx, 1, domain.SavingsRule{GoalID: house.ID, Name: "House deposit", Percent: 60, IsActive: true})
+	require.NoError(t, err)
+
+	for name, invalid := range map[string]domain.SavingsRule{
+		"someone else's goal":  {GoalID: other.ID, Percent: 10, IsActive: true},
+		"an expense category":  {GoalID: house.ID, CategoryID: &groceries.ID, Percent: 10, IsActive: true},
+		"over 100% of salary":  {GoalID: house.ID, CategoryID: &salary.ID, Percent: 50, IsActive: true},
+		"over 100% of any pay": {GoalID: house.ID, Percent: 41, IsActive: true},
+	} {
+		_, err := service.Create(ctx, 1, invalid)
+		assert.ErrorIs(t, err, domain.ErrValidation, name)
+	}
+
+	_, err = service.Create(ctx, 1, domain.SavingsRule{GoalID: house.ID, CategoryID: &salary.ID, Percent: 50})
+	require.NoError(t, err, "inactive rules do not count towards the total")
+	_, err = service.Update(ctx, 1, rule.ID, domain.SavingsRule{GoalID: house.ID, Percent: 40, IsActive: true})
+	require.NoError(t, err)
+	_, err = service.Create(ctx, 1, domain.SavingsRule{GoalID: house.ID, CategoryID: &salary.ID, Percent: 60, IsActive: true})
+	require.NoError(t, err)
+
+	rules, err := service.List(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, rules, 3)
+	assert.False(t, rules[1].IsActive)
+
+	preview, err := service.Preview(ctx, 1, domain.NewMoney(2000), &salary.ID)
+	require.NoError(t, err)
+	assert.Len(t, preview.Allocations, 2)
+	assert.Equal(t, domain.NewMoney(2000), preview.Allocated)
+	assert.Zero(t, preview.Remaining)
+	preview, err = service.Preview(ctx, 1, domain.NewMoney(2000), nil)
+	require.NoError(t, err)
+	assert.Equal(t, domain.NewMoney(800), preview.Allocated)
+
+	_, err = service.Update(ctx, 2, rule.ID, domain.SavingsRule{GoalID: other.ID, Percent: 10, IsActive: true})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.ErrorIs(t, service.Delete(ctx, 2, rule.ID), domain.ErrNotFound)
+	require.NoError(t, service.Delete(ctx, 1, rule.ID))
+}
+
+func TestSavingsRuleService_AllocatesRecordedIncome(t *testing.T) {
+	db := setupSavingsRuleTestDB(t)
+	bus := NewEventBus()
+	var reached []domain.FinancialGoal
+	bus.Subscribe(func(_ context.Context, event domain.Event) {
+		reached = append(reached, event.(domain.GoalReached).Goal)
+	}, domain.EventGoalReached)
+	service := &SavingsRuleService{DB: db, Goals: &GoalService{DB: db, Events: bus}}
+	service.Subscribe(bus)
+	transactions := &TransactionService{DB: db, Events: bus}
+	ctx := context.Background()
+
+	salary := &domain.Category{Name: "Salary", Type: domain.TransactionTypeIncome}
+	require.NoError(t, db.Create(salary).Error)
+	house := savingsGoal(1, "House", domain.GoalStatusActive, 1000)
+	emergency := savingsGoal(1, "Emergency fund", domain.GoalStatusActive, 150)
+	paused := savingsGoal(1, "Boat", domain.GoalStatusPaused, 5000)
+	for _, goal := range []*domain.FinancialGoal{&house, &emergency, &paused} {
+		require.NoError(t, db.Create(goal).Error)
+	}
+	for _, rule := range []domain.SavingsRule{
+		{GoalID: house.ID, Percent: 10, IsActive: true},
+		{GoalID: emergency.ID, Percent: 5, IsActive: true},
+		{GoalID: paused.ID, Percent: 20, IsActive: true},
+	} {
+		_, err := service.Create(ctx, 1, rule)
+		require.NoError(t, err)
+	}
+
+	income := &domain.Transaction{UserID: 1, CategoryID: salary.ID, Type: domain.TransactionTypeIncome,
+		Amount: domain.NewMoney(3000), Description: "Salary", Date: time.Now()}
+	require.NoError(t, transactions.Create(ctx, income))
+
+	goalAmount := func(goal domain.FinancialGoal) domain.Money {
+		require.NoError(t, db.First(&goal, goal.ID).Error)
+		return goal.CurrentAmount
+	}
+	assert.Equal(t, domain.NewMoney(300), goalAmount(house))
+	assert.Equal(t, domain.NewMoney(150), goalAmount(emergency))
+	assert.Zero(t, goalAmount(paused), "paused goals receive nothing")
+	require.Len(t, reached, 1)
+	assert.Equal(t, emergency.ID, reached[0].ID)
+
+	allocations, err := service.Allocations(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, allocations, 2)
+	assert.Equal(t, income.ID, allocations[0].TransactionID)
+
+	t.Run("income is allocated once", func(t *testing.T) {
+		made, err := service.Allocate(ctx, income)
+		require.NoError(t, err)
+		assert.Empty(t, made)
+		assert.Equal(t, domain.NewMoney(300), goalAmount(house))
+	})
+
+	t.Run("expenses are not allocated", func(t *testing.T) {
+		groceries := &domain.Category{Name: "Groceries", Type: domain.TransactionTypeExpense}
+		require.NoError(t, db.Create(groceries).Error)
+		expense := &domain.Transaction{UserID: 1, CategoryID: groceries.ID, Type: domain.TransactionTypeExpense,
+			Amount: domain.NewMoney(50), Description: "Market", Date: time.Now()}
+		require.NoError(t, transactions.Create(ctx, expense))
+		assert.Equal(t, domain.NewMoney(300), goalAmount(house))
+	})
+
+	t.Run("deleting the income takes its allocations back", func(t *testing.T) {
+		require.NoError(t, transactions.Delete(ctx, income.ID))
+		assert.Zero(t, goalAmount(house))
+		allocations, err := service.Allocations(ctx, 1)
+		require.NoError(t, err)
+		assert.Empty(t, allocations)
+	})
+}
@@ -0,0 +1,205 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/persistence"
+
+	"gorm.io/gorm"
+)
+
+// ScheduledTransferService keeps users' scheduled transfers between their
+// accounts and makes the transfers as they fall due
+type ScheduledTransferService struct {
+	DB           *gorm.DB
+	Transactions *TransactionService // Makes the transfers
+}
+
+func NewScheduledTransferService(db *gorm.DB, transactions *TransactionService) *ScheduledTransferService {
+	return &ScheduledTransferService{DB: db, Transactions: transactions}
+}
+
+// Create adds an active scheduled transfer for the user, first due on its
+// start date
+func (s *ScheduledTransferService) Create(ctx context.Context, userID uint, transfer *domain.ScheduledTransfer) error {
+	if err := s.validate(ctx, userID, transfer); err != nil {
+		return err
+	}
+	transfer.ID, transfer.UserID, transfer.Active, transfer.Occurrences = 0, userID, true, 0
+	transfer.Schedule()
+	return s.DB.WithContext(ctx).Create(transfer).Error
+}
+
+// validate checks the scheduled transfer and that it moves money between
+// two of the user's accounts holding the same currency
+func (s *ScheduledTransferService) validate(ctx context.Context, userID uint, transfer *domain.ScheduledTransfer) error {
+	if err := transfer.Validate(); err != nil {
+		return err
+	}
+	var accounts []domain.Account
+	err := s.DB.WithContext(ctx).Select("id", "currency").
+		Where("user_id = ? AND id IN ?", userID, []uint{transfer.FromAccountID, transfer.ToAccountID}).Find(&accounts).Error
+	if err != nil {
+		return err
+	}
+	currencies := make(map[uint]string, len(accounts))
+	for _, account := range accounts {
+		currencies[account.ID] = account.Currency
+	}
+
+	var fields []domain.FieldError
+	if _, ok := currencies[transfer.FromAccountID]; !ok {
+		fields = append(fields, domain.FieldError{Field: "from_account_id", Message: "does not exist"})
+	}
+	if _, ok := currencies[transfer.ToAccountID]; !ok {
+		fields = append(fields, domain.FieldError{Field: "to_account_id", Message: "does not exist"})
+	}
+	if len(fields) == 0 && currencies[transfer.FromAccountID] != currencies[transfer.ToAccountID] {
+		fields = append(fields, domain.FieldError{Field: "to_account_id", Message: "must hold the same currency as from_account_id"})
+	}
+	if len(fields) > 0 {
+		return &domain.ValidationError{Fields: fields}
+	}
+	return nil
+}
+
+// List returns the user's scheduled transfers, the next due first and the
+// ended ones last
+func (s *ScheduledTransferService) List(ctx context.Context, userID uint) ([]domain.ScheduledTransfer, error) {
+	var transfers []domain.ScheduledTransfer
+	err := s.DB.WithContext(ctx).Where("user_id = ?", userID).
+		Order("next_date IS NULL, next_date, id").Find(&transfers).Error
+	return transfers, err
+}
+
+// Get returns one of the user's scheduled transfers
+func (s *ScheduledTransferService) Get(ctx context.Context, userID, id uint) (*domain.ScheduledTransfer, error) {
+	var transfer domain.ScheduledTransfer
+	err := s.DB.WithContext(ctx).Where("user_id = ?", userID).First(&transfer, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+// Update saves the accounts, amount, schedule and active flag of one of the
+// user's scheduled transfers. The next date is worked out again from the
+// start date and the transfers made so far.
+func (s *ScheduledTransferService) Update(ctx context.Context, userID uint, transfer *domain.ScheduledTransfer) error {
+	if err := s.validate(ctx, userID, transfer); err != nil {
+		return err
+	}
+	transfer.Schedule()
+	result := s.DB.WithContext(ctx).Model(&domain.ScheduledTransfer{}).
+		Where("id = ? AND user_id = ?", transfer.ID, userID).
+		Updates(map[string]any{
+			"from_account_id": transfer.FromAccountID, "to_account_id": transfer.ToAccountID, "amount": transfer.Amount,
+			"description": transfer.Description, "frequency": transfer.Frequency, "start_date": transfer.StartDate,
+			"end_date": transfer.EndDate, "next_date": transfer.NextDate, "active": transfer.Active,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// Delete removes one of the user's scheduled transfers; the transfers it
+// made stay, no longer linked to it
+func (s *ScheduledTransferService) Delete(ctx context.Context, userID, id uint) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND user_id = ?", id, userID).Delete(&domain.ScheduledTransfer{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrNotFound
+		}
+		return tx.Model(&domain.Transaction{}).Unscoped().
+			Where("scheduled_transfer_id = ?", id).Update("scheduled_transfer_id", nil).Error
+	})
+}
+
+// RunDue makes the transfers of every active schedule that fell due by now
+// and returns how many were made. A schedule that fell behind catches up
+// with at most domain.MaxTransferCatchUp transfers a run. One whose transfer
+// is no longer valid, e.g. because an account was deleted, is deactivated.
+func (s *ScheduledTransferService) RunDue(ctx context.Context, now time.Time) (int, error) {
+	now = now.UTC()
+	var due []domain.ScheduledTransfer
+	err := s.DB.WithContext(ctx).Where("active = ? AND next_date <= ?", true, now).
+		Order("next_date, id").Find(&due).Error
+	if err != nil {
+		return 0, err
+	}
+
+	made := 0
+	for i := range due {
+		transfer := &due[i]
+		n, err := s.run(ctx, transfer, now)
+		if err == nil {
+			made += n
+			continue
+		}
+		log.Printf("scheduled transfers: transfer %d failed: %v", transfer.ID, err)
+		if errors.Is(err, domain.ErrValidation) {
+			err := s.DB.WithContext(ctx).Model(&domain.ScheduledTransfer{}).Where("id = ?", transfer.ID).
+				Update("active", false).Error
+			if err != nil {
+				return made, fmt.Errorf("failed to deactivate scheduled transfer %d: %w", transfer.ID, err)
+			}
+		}
+	}
+	return made, nil
+}
+
+// run makes the due transfers of one schedule and moves it on to its next
+// date, all as one unit of work
+func (s *ScheduledTransferService) run(ctx context.Context, transfer *domain.ScheduledTransfer, now time.Time) (int, error) {
+	made := 0
+	err := persistence.InTransaction(ctx, s.DB, func(ctx context.Context) error {
+		for made < domain.MaxTransferCatchUp && transfer.NextDate != nil && !transfer.NextDate.After(now) {
+			transaction := transfer.Transfer(*transfer.NextDate)
+			if err := s.Transactions.Create(ctx, &transaction); err != nil {
+				return err
+			}
+			transfer.Occurrences++
+			transfer.Schedule()
+			made++
+		}
+		return persistence.Conn(ctx, s.DB).Model(&domain.ScheduledTransfer{}).Where("id = ?", transfer.ID).
+			Updates(map[string]any{"occurrences": transfer.Occurrences, "next_date": transfer.NextDate}).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return made, nil
+}
+
+// StartScheduling runs RunDue on the given interval until ctx is cancelled
+func (s *ScheduledTransferService) StartScheduling(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.RunDue(ctx, time.Now()); err != nil {
+			log.Printf("scheduled transfers failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupScheduledTransferTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&domain.Account{}, &domain.Category{}, &domain.Transaction{}, &domain.TransactionItem{}, &domain.Comment{}, &domain.ScheduledTransfer{},
+	))
+	return db
+}
+
+// createTransferAccounts creates a checking and a savings account in USD
+// and a euro account for user 1
+func createTransferAccounts(t *testing.T, db *gorm.DB) (checking, savings, euro domain.Account) {
+	checking = domain.Account{
+		UserID: 1, Name: "Checking", Type: domain.AccountTypeChecking, Currency: "USD", OpeningBalance: domain.NewMoney(1000),
+	}
+	savings = domain.Account{UserID: 1, Name: "Savings", Type: domain.AccountTypeSavings, Currency: "USD"}
+	euro = domain.Account{UserID: 1, Name: "Euro", Type: domain.AccountTypeChecking, Currency: "EUR"}
+	for _, account := range []*domain.Account{&checking, &savings, &euro} {
+		require.NoError(t, db.Create(account).Error)
+	}
+	return checking, savings, euro
+}
+
+func TestTransactionService_Transfer(t *testing.T) {
+	db := setupScheduledTransferTestDB(t)
+	service := &TransactionService{DB: db}
+	ctx := context.Background()
+	checking, savings, euro := createTransferAccounts(t, db)
+	date := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	transfer := &domain.Transaction{
+		UserID: 1, Type: domain.TransactionTypeTransfer, Amount: domain.NewMoney(300), Description: "Savings",
+		Date: date, AccountID: &checking.ID, TransferAccountID: &savings.ID,
+	}
+	require.NoError(t, service.Create(ctx, transfer))
+	assert.NotZero(t, transfer.CategoryID, "transfers are filed as uncategorized")
+	require.NoError(t, service.Create(ctx, &domain.Transaction{
+		UserID: 1, CategoryID: transfer.CategoryID, Type: domain.TransactionTypeExpense, Amount: domain.NewMoney(50),
+		Description: "Groceries", Date: date, AccountID: &checking.ID,
+	}))
+
+	t.Run("moves money only between the user's accounts in one currency", func(t *testing.T) {
+		var validationErr *domain.ValidationError
+		other := uint(999)
+		err := service.Create(ctx, &domain.Transaction{
+			UserID: 1, Type: domain.TransactionTypeTransfer, Amount: domain.NewMoney(10), Description: "Away",
+			Date: date, AccountID: &checking.ID, TransferAccountID: &other,
+		})
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "transfer_account_id", validationErr.Fields[0].Field)
+
+		err = service.Create(ctx, &domain.Transaction{
+			UserID: 1, Type: domain.TransactionTypeTransfer, Amount: domain.NewMoney(10), Description: "Abroad",
+			Date: date, AccountID: &checking.ID, TransferAccountID: &euro.ID,
+		})
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "must hold the same currency as account_id", validationErr.Fields[0].Message)
+	})
+
+	t.Run("is neither income nor expense", func(t *testing.T) {
+		end := date.AddDate(0, 0, 1)
+		income, err := service.GetTotalByType(ctx, 1, domain.TransactionTypeIncome, date, end)
+		require.NoError(t, err)
+		expenses, err := service.GetTotalByType(ctx, 1, domain.TransactionTypeExpense, date, end)
+		require.NoError(t, err)
+		assert.Equal(t, domain.Money(0), income)
+		assert.Equal(t, domain.NewMoney(50), expenses)
+	})
+
+	t.Run("keeps an account's running balance", func(t *testing.T) {
+		page, err := service.ListPageIncluding(ctx, domain.TransactionFilter{UserID: 1, AccountID: &checking.ID},
+			domain.TransactionIncludes{RunningBalance: true})
+		require.NoError(t, err)
+		require.Len(t, page.Transactions, 2)
+		assert.Equal(t, domain.NewMoney(-350), *page.Transactions[0].RunningBalance)
+
+		page, err = service.ListPageIncluding(ctx, domain.TransactionFilter{UserID: 1, AccountID: &savings.ID},
+			domain.TransactionIncludes{RunningBalance: true})
+		require.NoError(t, err)
+		require.Len(t, page.Transactions, 1)
+		assert.Equal(t, domain.NewMoney(300), *page.Transactions[0].RunningBalance)
+	})
+
+	t.Run("lists on both accounts", func(t *testing.T) {
+		onSavings, err := service.repository().Find(ctx, domain.TransactionFilter{UserID: 1, AccountID: &savings.ID})
+		require.NoError(t, err)
+		require.Len(t, onSavings, 1)
+		assert.Equal(t, transfer.ID, onSavings[0].ID)
+
+		withoutTransfers, err := service.repository().Find(ctx, domain.TransactionFilter{
+			UserID: 1, AccountID: &checking.ID, ExcludeTransfers: true,
+		})
+		require.NoError(t, err)
+		require.Len(t, withoutTransfers, 1)
+		assert.Equal(t, "Groceries", withoutTransfers[0].Description)
+
+		incoming, err := service.repository().Find(ctx, domain.TransactionFilter{UserID: 1, TransferAccountID: &checking.ID})
+		require.NoError(t, err)
+		assert.Empty(t, incoming)
+	})
+
+	t.Run("moves both account statements", func(t *testing.T) {
+		accounts := NewAccountService(db)
+		checkingStatement, err := accounts.Statement(ctx, 1, checking.ID, date)
+		require.NoError(t, err)
+		assert.Equal(t, domain.NewMoney(650), checkingStatement.ClosingBalance)
+
+		savingsStatement, err := accounts.Statement(ctx, 1, savings.ID, date.AddDate(0, 1, 0))
+		require.NoError(t, err)
+		assert.Equal(t, domain.NewMoney(300), savingsStatement.OpeningBalance, "earlier transfers in count toward the opening balance")
+	})
+}
+
+func TestScheduledTransferService(t *testing.T) {
+	db := setupScheduledTransferTestDB(t)
+	service := NewScheduledTransferService(db, &TransactionService{DB: db})
+	ctx := context.Background()
+	checking, savings, euro := createTransferAccounts(t, db)
+	start := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	schedule := &domain.ScheduledTransfer{
+		FromAccountID: checking.ID, ToAccountID: savings.ID, Amount: domain.NewMoney(200), Description: "Savings",
+		Frequency: domain.TransferFrequencyMonthly, StartDate: start,
+	}
+	require.NoError(t, service.Create(ctx, 1, schedule))
+	assert.True(t, schedule.Active)
+	require.NotNil(t, schedule.NextDate)
+	assert.Equal(t, start, *schedule.NextDate)
+
+	t.Run("rejects accounts of other users and currencies", func(t *testing.T) {
+		var validationErr *domain.ValidationError
+		err := service.Create(ctx, 2, &domain.ScheduledTransfer{
+			FromAccountID: checking.ID, ToAccountID: savings.ID, Amount: domain.NewMoney(10), Description: "Theirs",
+			Frequency: domain.TransferFrequencyOnce, StartDate: start,
+		})
+		require.ErrorAs(t, err, &validationErr)
+		assert.Len(t, validationErr.Fields, 2)
+
+		err = service.Create(ctx, 1, &domain.ScheduledTransfer{
+			FromAccountID: checking.ID, ToAccountID: euro.ID, Amount: domain.NewMoney(10), Description: "Abroad",
+			Frequency: domain.TransferFrequencyOnce, StartDate: start,
+		})
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "to_account_id", validationErr.Fields[0].Field)
+
+		_, err = service.Get(ctx, 2, schedule.ID)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("makes the transfers that fell due", func(t *testing.T) {
+		made, err := service.RunDue(ctx, time.Date(2025, 3, 31, 12, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		assert.Equal(t, 3, made)
+
+		var transfers []domain.Transaction
+		require.NoError(t, db.Where("scheduled_transfer_id = ?", schedule.ID).Order("date").Find(&transfers).Error)
+		require.Len(t, transfers, 3)
+		assert.Equal(t, time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC), transfers[1].Date.UTC())
+		for _, transfer := range transfers {
+			assert.Equal(t, domain.TransactionTypeTransfer, transfer.Type)
+			assert.Equal(t, savings.ID, *transfer.TransferAccountID)
+		}
+
+		saved, err := service.Get(ctx, 1, schedule.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 3, saved.Occurrences)
+		assert.Equal(t, time.Date(2025, 4, 30, 0, 0, 0, 0, time.UTC), saved.NextDate.UTC())
+
+		made, err = service.RunDue(ctx, time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		assert.Zero(t, made, "nothing is due again before the next date")
+	})
+
+	t.Run("catches up a bounded number of transfers a run", func(t *testing.T) {
+		weekly := &domain.ScheduledTransfer{
+			FromAccountID: savings.ID, ToAccountID: checking.ID, Amount: domain.NewMoney(5), Description: "Allowance",
+			Frequency: domain.TransferFrequencyWeekly, StartDate: start,
+		}
+		require.NoError(t, service.Create(ctx, 1, weekly))
+		require.NoError(t, service.Update(ctx, 1, &domain.ScheduledTransfer{
+			ID: schedule.ID, FromAccountID: checking.ID, ToAccountID: savings.ID, Amount: domain.NewMoney(200),
+			Description: "Savings", Frequency: domain.TransferFrequencyMonthly, StartDate: start, Occurrences: 3,
+		}))
+
+		made, err := service.RunDue(ctx, start.AddDate(1, 0, 0))
+		require.NoError(t, err)
+		assert.Equal(t, domain.MaxTransferCatchUp, made, "the paused monthly transfer makes none")
+
+		saved, err := service.Get(ctx, 1, weekly.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.MaxTransferCatchUp, saved.Occurrences)
+	})
+
+	t.Run("deactivates a schedule whose transfer is no longer valid", func(t *testing.T) {
+		closing := domain.Account{UserID: 1, Name: "Closing", Type: domain.AccountTypeSavings, Currency: "USD"}
+		require.NoError(t, db.Create(&closing).Error)
+		once := &domain.ScheduledTransfer{
+			FromAccountID: checking.ID, ToAccountID: closing.ID, Amount: domain.NewMoney(20), Description: "Last one",
+			Frequency: domain.TransferFrequencyOnce, StartDate: start,
+		}
+		require.NoError(t, service.Create(ctx, 1, once))
+		require.NoError(t, db.Delete(&closing).Error)
+
+		_, err := service.RunDue(ctx, start)
+		require.NoError(t, err)
+
+		saved, err := service.Get(ctx, 1, once.ID)
+		require.NoError(t, err)
+		assert.False(t, saved.Active)
+		assert.Zero(t, saved.Occurrences)
+	})
+
+	t.Run("deleting keeps the transfers it made", func(t *testing.T) {
+		require.NoError(t, service.Delete(ctx, 1, schedule.ID))
+		assert.ErrorIs(t, service.Delete(ctx, 1, schedule.ID), domain.ErrNotFound)
+
+		var count, linked int64
+		require.NoError(t, db.Model(&domain.Transaction{}).Where("description = ?", "Savings").Count(&count).Error)
+		require.NoError(t, db.Model(&domain.Transaction{}).Where("scheduled_transfer_id = ?", schedule.ID).Count(&linked).Error)
+		assert.Equal(t, int64(3), count)
+		assert.Zero(t, linked)
+	})
+}
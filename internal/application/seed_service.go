@@ -0,0 +1,210 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	mathrand "math/rand/v2"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// SeedOptions controls how much fake data Seed generates. The same options
+// always produce the same users and transactions.
+type SeedOptions struct {
+	Users               int
+	TransactionsPerUser int
+	Months              int       // How far back transactions go
+	Until               time.Time // Date of the latest transactions
+	Seed                uint64
+	Password            string // Shared by every seeded user, so they can log in
+}
+
+// Validate checks that the volumes can be generated
+func (o SeedOptions) Validate() error {
+	var fields []domain.FieldError
+	check := func(ok bool, field, message string) {
+		if !ok {
+			fields = append(fields, domain.FieldError{Field: field, Message: message})
+		}
+	}
+	check(o.Users > 0, "users", "must be positive")
+	check(o.TransactionsPerUser >= 0, "transactions", "cannot be negative")
+	check(o.Months > 0, "months", "must be positive")
+	check(!o.Until.IsZero(), "until", "is required")
+	check(len(o.Password) >= 8, "password", "must be at least 8 characters")
+	if len(fields) > 0 {
+		return &domain.ValidationError{Fields: fields}
+	}
+	return nil
+}
+
+// SeedResult counts what Seed wrote
+type SeedResult struct {
+	Users        int
+	Transactions int
+}
+
+// seedCategory is a category seeded transactions are spread across, with how
+// often it comes up and the range of its amounts
+type seedCategory struct {
+	name     string
+	kind     string
+	weight   int
+	min, max float64
+}
+
+var seedCategories = []seedCategory{
+	{"Salary", domain.TransactionTypeIncome, 6, 2500, 7500},
+	{"Freelance", domain.TransactionTypeIncome, 2, 150, 1500},
+	{"Investment", domain.TransactionTypeIncome, 1, 10, 400},
+	{"Food & Dining", domain.TransactionTypeExpense, 30, 5, 120},
+	{"Transportation", domain.TransactionTypeExpense, 12, 3, 90},
+	{"Shopping", domain.TransactionTypeExpense, 14, 10, 300},
+	{"Entertainment", domain.TransactionTypeExpense, 8, 8, 150},
+	{"Bills & Utilities", domain.TransactionTypeExpense, 8, 30, 250},
+	{"Healthcare", domain.TransactionTypeExpense, 4, 10, 400},
+	{"Education", domain.TransactionTypeExpense, 2, 20, 500},
+	{"Travel", domain.TransactionTypeExpense, 2, 80, 1800},
+	{"Housing", domain.TransactionTypeExpense, 5, 600, 2500},
+	{"Other Expenses", domain.TransactionTypeExpense, 3, 5, 200},
+}
+
+var seedDescriptions = map[string][]string{
+	"Salary":            {"Monthly salary", "Payroll"},
+	"Freelance":         {"Freelance project", "Consulting invoice", "Design work"},
+	"Investment":        {"Dividends", "Interest", "Fund distribution"},
+	"Food & Dining":     {"Groceries", "Restaurant", "Coffee shop", "Takeaway", "Bakery"},
+	"Transportation":    {"Fuel", "Transit pass", "Taxi", "Parking", "Train ticket"},
+	"Shopping":          {"Online order", "Clothing", "Electronics", "Home goods"},
+	"Entertainment":     {"Streaming subscription", "Cinema", "Concert tickets", "Video game"},
+	"Bills & Utilities": {"Electricity", "Water", "Internet", "Phone plan"},
+	"Healthcare":        {"Pharmacy", "Doctor visit", "Dentist"},
+	"Education":         {"Online course", "Books", "Workshop"},
+	"Travel":            {"Flight", "Hotel", "Car rental"},
+	"Housing":           {"Rent", "Home repairs", "Furniture"},
+	"Other Expenses":    {"Gift", "Donation", "Miscellaneous"},
+}
+
+var seedFirstNames = []string{"Alex", "Sam", "Jordan", "Taylor", "Morgan", "Casey", "Riley", "Jamie", "Robin", "Avery"}
+var seedLastNames = []string{"Smith", "Garcia", "Chen", "Müller", "Kaya", "Novak", "Silva", "Okafor", "Rossi", "Berg"}
+
+// SeedService generates fake users and transactions for development and load tests
+type SeedService struct {
+	DB *gorm.DB
+}
+
+func NewSeedService(db *gorm.DB) *SeedService {
+	return &SeedService{DB: db}
+}
+
+// SeedEmail is the email of the i-th user seeded with a seed
+func SeedEmail(seed uint64, i int) string {
+	return fmt.Sprintf("seed%d-user%04d@example.com", seed, i)
+}
+
+// Seed generates the users and their transactions. Users that a previous run
+// with the same seed created are kept and get their transactions replaced,
+// so seeding again gives the same data instead of adding to it.
+func (s *SeedService) Seed(ctx context.Context, opts SeedOptions) (SeedResult, error) {
+	if err := opts.Validate(); err != nil {
+		return SeedResult{}, err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(opts.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return SeedResult{}, err
+	}
+
+	categories, err := defaultCategoryIDs(s.DB.WithContext(ctx))
+	if err != nil {
+		return SeedResult{}, err
+	}
+
+	var result SeedResult
+	for i := 1; i <= opts.Users; i++ {
+		random := mathrand.New(mathrand.NewPCG(opts.Seed, uint64(i)))
+		err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			user, err := seedUser(tx, SeedEmail(opts.Seed, i), string(hash), random)
+			if err != nil {
+				return err
+			}
+			if err := tx.Unscoped().Where("user_id = ?", user.ID).Delete(&domain.Transaction{}).Error; err != nil {
+				return err
+			}
+
+			transactions := seedTransactions(user.ID, categories, opts, random)
+			if len(transactions) > 0 {
+				if err := tx.CreateInBatches(transactions, 500).Error; err != nil {
+					return err
+				}
+			}
+			result.Transactions += len(transactions)
+			return nil
+		})
+		if err != nil {
+			return result, fmt.Errorf("seeding user %d: %w", i, err)
+		}
+		result.Users++
+	}
+	return result, nil
+}
+
+// seedUser returns the user with the email, creating it with a random
+// profile. The profile is drawn either way, so the transactions that follow
+// come out the same whether or not the user existed.
+func seedUser(tx *gorm.DB, email, passwordHash string, random *mathrand.Rand) (domain.User, error) {
+	firstName := seedFirstNames[random.IntN(len(seedFirstNames))]
+	lastName := seedLastNames[random.IntN(len(seedLastNames))]
+	risk := []string{domain.RiskToleranceConservative, domain.RiskToleranceModerate, domain.RiskToleranceAggressive}[random.IntN(3)]
+	income := domain.NewMoney(float64(2500 + 500*random.IntN(11)))
+	age, dependents := 22+random.IntN(45), random.IntN(4)
+
+	var user domain.User
+	err := tx.Where("email = ?", email).First(&user).Error
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return user, err
+	}
+	user = domain.User{
+		Email: email, Password: passwordHash, FirstName: firstName, LastName: lastName,
+		Age: age, RiskTolerance: risk, Locale: "en",
+		UserProfile: domain.UserProfile{EmploymentStatus: "employed", Dependents: dependents, MonthlyIncome: income},
+	}
+	return user, tx.Create(&user).Error
+}
+
+// seedTransactions spreads the user's transactions over the months before
+// opts.Until, picking categories by weight
+func seedTransactions(userID uint, categories map[string]uint, opts SeedOptions, random *mathrand.Rand) []domain.Transaction {
+	totalWeight := 0
+	for _, category := range seedCategories {
+		totalWeight += category.weight
+	}
+	until := time.Date(opts.Until.Year(), opts.Until.Month(), opts.Until.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(until.Sub(until.AddDate(0, -opts.Months, 0)).Hours() / 24)
+
+	transactions := make([]domain.Transaction, 0, opts.TransactionsPerUser)
+	for range opts.TransactionsPerUser {
+		pick := random.IntN(totalWeight)
+		category := seedCategories[0]
+		for _, candidate := range seedCategories {
+			if pick < candidate.weight {
+				category = candidate
+				break
+			}
+			pick -= candidate.weight
+		}
+
+		descriptions := seedDescriptions[category.name]
+		amount := category.min + random.Float64()*(category.max-category.min)
+		date := until.AddDate(0, 0, -random.IntN(days+1)).Add(time.Duration(random.IntN(24*60)) * time.Minute)
+		transactions = append(transactions, domain.Transaction{
+			UserID: userID, CategoryID: categories[category.name], Type: category.kind,
+			Description: descriptions[random.IntN(len(descriptions))], Amount: domain.NewMoney(amount), Date: date,
+		})
+	}
+	return transactions
+}
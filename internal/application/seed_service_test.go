@@ -0,0 +1,65 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeedService_Seed(t *testing.T) {
+	db := setupDemoTestDB(t)
+	ctx := context.Background()
+	service := NewSeedService(db)
+	until := time.Date(2025, time.March, 31, 0, 0, 0, 0, time.UTC)
+	opts := SeedOptions{Users: 3, TransactionsPerUser: 40, Months: 2, Until: until, Seed: 42, Password: "password123"}
+
+	snapshot := func() []domain.Transaction {
+		var transactions []domain.Transaction
+		require.NoError(t, db.Order("user_id, date, amount").Find(&transactions).Error)
+		return transactions
+	}
+
+	result, err := service.Seed(ctx, opts)
+	require.NoError(t, err)
+	assert.Equal(t, SeedResult{Users: 3, Transactions: 120}, result)
+
+	first := snapshot()
+	require.Len(t, first, 120)
+	for _, transaction := range first {
+		assert.False(t, transaction.Date.Before(until.AddDate(0, -2, 0)), "transactions stay within the months")
+		assert.True(t, transaction.Date.Before(until.AddDate(0, 0, 1)))
+		assert.NotZero(t, transaction.CategoryID)
+	}
+
+	t.Run("should generate the same data from the same seed", func(t *testing.T) {
+		_, err := service.Seed(ctx, opts)
+		require.NoError(t, err)
+		again := snapshot()
+		require.Len(t, again, len(first))
+		for i := range first {
+			assert.Equal(t, first[i].Description, again[i].Description)
+			assert.Equal(t, first[i].Amount, again[i].Amount)
+			assert.True(t, first[i].Date.Equal(again[i].Date))
+		}
+
+		var users int64
+		require.NoError(t, db.Model(&domain.User{}).Count(&users).Error)
+		assert.Equal(t, int64(3), users)
+	})
+
+	t.Run("should log seeded users in with the password", func(t *testing.T) {
+		user, err := (&UserService{DB: db}).Login(ctx, SeedEmail(42, 2), "password123")
+		require.NoError(t, err)
+		assert.NotEmpty(t, user.FirstName)
+	})
+
+	t.Run("should reject invalid volumes", func(t *testing.T) {
+		_, err := service.Seed(ctx, SeedOptions{Users: 0, Months: 1, Until: until, Password: "password123"})
+		assert.ErrorIs(t, err, domain.ErrValidation)
+	})
+}
@@ -0,0 +1,183 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultRefreshTokenExpiry = 30 * 24 * time.Hour
+	refreshTokenBytes         = 32
+	// sessionTouchInterval limits how often requests write the time and
+	// device a session was last used from
+	sessionTouchInterval = time.Minute
+)
+
+// SessionService keeps track of the devices users are logged in on and the
+// refresh tokens that renew their logins. Refresh tokens are rotated on every
+// use; a session stays renewable until it goes unused for RefreshExpiry.
+type SessionService struct {
+	DB            *gorm.DB
+	RefreshExpiry time.Duration // Defaults to 30 days when zero
+}
+
+func NewSessionService(db *gorm.DB) *SessionService {
+	return &SessionService{DB: db}
+}
+
+func (s *SessionService) refreshExpiry() time.Duration {
+	if s.RefreshExpiry > 0 {
+		return s.RefreshExpiry
+	}
+	return defaultRefreshTokenExpiry
+}
+
+// Start opens a session for a user who just logged in and returns it with
+// its refresh token. The token is only ever returned here and by Refresh.
+func (s *SessionService) Start(ctx context.Context, userID uint, device domain.Device) (*domain.Session, string, error) {
+	token, hash, err := newRefreshToken()
+	if err != nil {
+		return nil, "", err
+	}
+	now := time.Now()
+	session := &domain.Session{
+		UserID:           userID,
+		RefreshTokenHash: hash,
+		LastUsedAt:       now,
+		ExpiresAt:        now.Add(s.refreshExpiry()),
+	}
+	device.Apply(session)
+	if err := s.DB.WithContext(ctx).Create(session).Error; err != nil {
+		return nil, "", err
+	}
+	return session, token, nil
+}
+
+// Refresh renews the session of a refresh token, replacing the token with a
+// new one. Unknown, expired and revoked tokens get domain.ErrInvalidSession.
+func (s *SessionService) Refresh(ctx context.Context, refreshToken string, device domain.Device) (*domain.Session, string, error) {
+	var session domain.Session
+	err := s.DB.WithContext(ctx).Where("refresh_token_hash = ?", hashRefreshToken(refreshToken)).First(&session).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, "", domain.ErrInvalidSession
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	now := time.Now()
+	if !session.Active(now) {
+		return nil, "", domain.ErrInvalidSession
+	}
+
+	token, hash, err := newRefreshToken()
+	if err != nil {
+		return nil, "", err
+	}
+	previousHash := session.RefreshTokenHash
+	session.RefreshTokenHash = hash
+	session.LastUsedAt = now
+	session.ExpiresAt = now.Add(s.refreshExpiry())
+	device.Apply(&session)
+
+	// Only the first of two concurrent refreshes with the same token wins
+	result := s.DB.WithContext(ctx).Model(&domain.Session{}).
+		Where("id = ? AND refresh_token_hash = ? AND revoked_at IS NULL", session.ID, previousHash).
+		Updates(map[string]any{
+			"refresh_token_hash": session.RefreshTokenHash,
+			"last_used_at":       session.LastUsedAt,
+			"expires_at":         session.ExpiresAt,
+			"user_agent":         session.UserAgent,
+			"ip_address":         session.IPAddress,
+		})
+	if result.Error != nil {
+		return nil, "", result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, "", domain.ErrInvalidSession
+	}
+	return &session, token, nil
+}
+
+// Validate checks that the session an access token was issued for is still
+// the user's and active, and records that the device used it
+func (s *SessionService) Validate(ctx context.Context, userID, sessionID uint, device domain.Device) error {
+	var session domain.Session
+	err := s.DB.WithContext(ctx).Where("id = ? AND user_id = ?", sessionID, userID).First(&session).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.ErrInvalidSession
+	}
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if !session.Active(now) {
+		return domain.ErrInvalidSession
+	}
+	if now.Sub(session.LastUsedAt) < sessionTouchInterval {
+		return nil
+	}
+
+	device.Apply(&session)
+	return s.DB.WithContext(ctx).Model(&session).Updates(map[string]any{
+		"last_used_at": now,
+		"user_agent":   session.UserAgent,
+		"ip_address":   session.IPAddress,
+	}).Error
+}
+
+// List returns the user's active sessions, most recently used first
+func (s *SessionService) List(ctx context.Context, userID uint) ([]domain.Session, error) {
+	var sessions []domain.Session
+	err := s.DB.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_used_at DESC, id DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// Revoke signs the session's device out. Sessions of other users are not found.
+func (s *SessionService) Revoke(ctx context.Context, userID, sessionID uint) error {
+	result := s.DB.WithContext(ctx).Model(&domain.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", sessionID, userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// RevokeAll signs the user out everywhere except in the session exceptID,
+// which may be zero, and returns how many sessions it revoked
+func (s *SessionService) RevokeAll(ctx context.Context, userID, exceptID uint) (int64, error) {
+	result := s.DB.WithContext(ctx).Model(&domain.Session{}).
+		Where("user_id = ? AND id <> ? AND revoked_at IS NULL", userID, exceptID).
+		Update("revoked_at", time.Now())
+	return result.RowsAffected, result.Error
+}
+
+// newRefreshToken returns a random refresh token and the hash it is stored as
+func newRefreshToken() (token, hash string, err error) {
+	random := make([]byte, refreshTokenBytes)
+	if _, err := rand.Read(random); err != nil {
+		return "", "", err
+	}
+	token = base64.RawURLEncoding.EncodeToString(random)
+	return token, hashRefreshToken(token), nil
+}
+
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,131 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupSessionTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&domain.Session{}))
+	return db
+}
+
+func TestSessionService_StartAndRefresh(t *testing.T) {
+	db := setupSessionTestDB(t)
+	service := &SessionService{DB: db, RefreshExpiry: time.Hour}
+	ctx := context.Background()
+	laptop := domain.Device{UserAgent: "Firefox/128.0", IPAddress: "198.51.100.7"}
+
+	session, token, err := service.Start(ctx, 1, laptop)
+	require.NoError(t, err)
+	assert.NotEmpty(t, token)
+	assert.Equal(t, "Firefox/128.0", session.UserAgent)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), session.ExpiresAt, time.Minute)
+
+	var stored domain.Session
+	require.NoError(t, db.First(&stored, session.ID).Error)
+	assert.NotContains(t, stored.RefreshTokenHash, token, "only the hash of the refresh token is stored")
+
+	phone := domain.Device{UserAgent: "finance-ios/2.1", IPAddress: "203.0.113.4"}
+	refreshed, rotated, err := service.Refresh(ctx, token, phone)
+	require.NoError(t, err)
+	assert.Equal(t, session.ID, refreshed.ID)
+	assert.NotEqual(t, token, rotated)
+	assert.Equal(t, "203.0.113.4", refreshed.IPAddress)
+
+	t.Run("used refresh tokens stop working", func(t *testing.T) {
+		_, _, err := service.Refresh(ctx, token, phone)
+		assert.ErrorIs(t, err, domain.ErrInvalidSession)
+	})
+
+	t.Run("unknown refresh tokens are rejected", func(t *testing.T) {
+		_, _, err := service.Refresh(ctx, "not-a-token", phone)
+		assert.ErrorIs(t, err, domain.ErrInvalidSession)
+	})
+
+	t.Run("expired sessions cannot be refreshed", func(t *testing.T) {
+		expired, expiredToken, err := service.Start(ctx, 1, laptop)
+		require.NoError(t, err)
+		require.NoError(t, db.Model(expired).Update("expires_at", time.Now().Add(-time.Minute)).Error)
+
+		_, _, err = service.Refresh(ctx, expiredToken, laptop)
+		assert.ErrorIs(t, err, domain.ErrInvalidSession)
+	})
+}
+
+func TestSessionService_Validate(t *testing.T) {
+	db := setupSessionTestDB(t)
+	service := NewSessionService(db)
+	ctx := context.Background()
+
+	session, _, err := service.Start(ctx, 1, domain.Device{UserAgent: "curl/8.5"})
+	require.NoError(t, err)
+	assert.NoError(t, service.Validate(ctx, 1, session.ID, domain.Device{UserAgent: "curl/8.5"}))
+	assert.ErrorIs(t, service.Validate(ctx, 2, session.ID, domain.Device{}), domain.ErrInvalidSession)
+
+	t.Run("use is recorded at most once a minute", func(t *testing.T) {
+		lastUsed := time.Now().Add(-time.Hour)
+		require.NoError(t, db.Model(session).Update("last_used_at", lastUsed).Error)
+		require.NoError(t, service.Validate(ctx, 1, session.ID, domain.Device{UserAgent: "curl/8.6", IPAddress: "192.0.2.1"}))
+
+		var stored domain.Session
+		require.NoError(t, db.First(&stored, session.ID).Error)
+		assert.True(t, stored.LastUsedAt.After(lastUsed))
+		assert.Equal(t, "curl/8.6", stored.UserAgent)
+		assert.Equal(t, "192.0.2.1", stored.IPAddress)
+	})
+
+	t.Run("revoked sessions are rejected", func(t *testing.T) {
+		require.NoError(t, service.Revoke(ctx, 1, session.ID))
+		assert.ErrorIs(t, service.Validate(ctx, 1, session.ID, domain.Device{}), domain.ErrInvalidSession)
+	})
+}
+
+func TestSessionService_ListAndRevoke(t *testing.T) {
+	db := setupSessionTestDB(t)
+	service := NewSessionService(db)
+	ctx := context.Background()
+
+	var ids []uint
+	for _, agent := range []string{"laptop", "phone", "tablet"} {
+		session, _, err := service.Start(ctx, 1, domain.Device{UserAgent: agent})
+		require.NoError(t, err)
+		ids = append(ids, session.ID)
+	}
+	_, _, err := service.Start(ctx, 2, domain.Device{UserAgent: "other user"})
+	require.NoError(t, err)
+
+	sessions, err := service.List(ctx, 1)
+	require.NoError(t, err)
+	assert.Len(t, sessions, 3)
+
+	assert.ErrorIs(t, service.Revoke(ctx, 2, ids[0]), domain.ErrNotFound)
+	require.NoError(t, service.Revoke(ctx, 1, ids[0]))
+	assert.ErrorIs(t, service.Revoke(ctx, 1, ids[0]), domain.ErrNotFound, "sessions are revoked once")
+
+	revoked, err := service.RevokeAll(ctx, 1, ids[2])
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), revoked)
+
+	sessions, err = service.List(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "tablet", sessions[0].UserAgent)
+
+	others, err := service.List(ctx, 2)
+	require.NoError(t, err)
+	assert.Len(t, others, 1)
+}
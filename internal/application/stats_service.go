@@ -0,0 +1,284 @@
+package application
+
+import (
+	"context"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// statsTopCategories is how many of the most used categories stats list
+	statsTopCategories = 10
+	// statsActivityRetention is how long the per-user request counts are kept
+	// after their day; only the anonymous daily stats stay longer
+	statsActivityRetention = 7 * 24 * time.Hour
+	// defaultStatsDays and maxStatsDays are how many days reports cover by
+	// default and at most
+	defaultStatsDays = 30
+	maxStatsDays     = 366
+)
+
+// activityKey identifies the requests of a user on a day
+type activityKey struct {
+	userID uint
+	day    time.Time
+}
+
+// StatsService computes the anonymous daily stats admins operate the service
+// with. It counts the API requests in memory until Flush writes them, and
+// Compute aggregates a day's stats from the users, transactions, quota
+// counters and request counts in the database.
+type StatsService struct {
+	DB      *gorm.DB
+	mu      sync.Mutex
+	pending map[activityKey]*domain.RequestActivity
+}
+
+func NewStatsService(db *gorm.DB) *StatsService {
+	return &StatsService{DB: db, pending: map[activityKey]*domain.RequestActivity{}}
+}
+
+// Record counts a request of the user, 0 when nobody was logged in, that was
+// answered with status
+func (s *StatsService) Record(userID uint, status int) {
+	key := activityKey{userID: userID, day: quotaDay(time.Now())}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	activity, ok := s.pending[key]
+	if !ok {
+		activity = &domain.RequestActivity{UserID: userID, Day: key.day}
+		s.pending[key] = activity
+	}
+	activity.Requests++
+	switch {
+	case status >= 500:
+		activity.ServerErrors++
+	case status >= 400:
+		activity.ClientErrors++
+	}
+}
+
+// Flush adds the requests counted since the last flush to the stored counts.
+// When writing fails they are kept for the next flush.
+func (s *StatsService) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = map[activityKey]*domain.RequestActivity{}
+	s.mu.Unlock()
+	if len(pending) == 0 {
+		return nil
+	}
+
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, activity := range pending {
+			err := tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "user_id"}, {Name: "day"}},
+				DoUpdates: clause.Assignments(map[string]any{
+					"requests":      gorm.Expr("request_activities.requests + ?", activity.Requests),
+					"client_errors": gorm.Expr("request_activities.client_errors + ?", activity.ClientErrors),
+					"server_errors": gorm.Expr("request_activities.server_errors + ?", activity.ServerErrors),
+				}),
+			}).Create(activity).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.mu.Lock()
+		for key, activity := range pending {
+			if current, ok := s.pending[key]; ok {
+				activity.Requests += current.Requests
+				activity.ClientErrors += current.ClientErrors
+				activity.ServerErrors += current.ServerErrors
+			}
+			s.pending[key] = activity
+		}
+		s.mu.Unlock()
+	}
+	return err
+}
+
+// Compute aggregates and stores the stats of the UTC day of day, replacing
+// any computed before. Categories only show up once StatsMinCategoryUsers
+// users added transactions to categories of their name that day.
+func (s *StatsService) Compute(ctx context.Context, day time.Time) (*domain.DailyStats, error) {
+	start := quotaDay(day)
+	end := start.Add(24 * time.Hour)
+	db := s.DB.WithContext(domain.ContextWithoutUserScope(ctx))
+	stats := domain.DailyStats{Day: start, ComputedAt: time.Now()}
+
+	counts := []struct {
+		target *int
+		query  *gorm.DB
+	}{
+		{&stats.TotalUsers, db.Model(&domain.User{}).Where("created_at < ?", end)},
+		{&stats.NewUsers, db.Model(&domain.User{}).Where("created_at >= ? AND created_at < ?", start, end)},
+		{&stats.ActiveUsers, db.Model(&domain.RequestActivity{}).Where("day = ? AND user_id <> 0", start)},
+		// Transactions added that day count even when they were deleted since
+		{&stats.Transactions, db.Unscoped().Model(&domain.Transaction{}).Where("created_at >= ? AND created_at < ?", start, end)},
+	}
+	for _, count := range counts {
+		var n int64
+		if err := count.query.Count(&n).Error; err != nil {
+			return nil, err
+		}
+		*count.target = int(n)
+	}
+
+	err := db.Model(&domain.UsageCounter{}).Where("quota = ? AND day = ?", domain.QuotaAIRequests, start).
+		Select("COALESCE(SUM(count), 0)").Scan(&stats.AdvisorCalls).Error
+	if err != nil {
+		return nil, err
+	}
+	var requests struct {
+		Requests     int
+		ClientErrors int
+		ServerErrors int
+	}
+	err = db.Model(&domain.RequestActivity{}).Where("day = ?", start).
+		Select("COALESCE(SUM(requests), 0) AS requests, COALESCE(SUM(client_errors), 0) AS client_errors, " +
+			"COALESCE(SUM(server_errors), 0) AS server_errors").
+		Scan(&requests).Error
+	if err != nil {
+		return nil, err
+	}
+	stats.Requests, stats.ClientErrors, stats.ServerErrors = requests.Requests, requests.ClientErrors, requests.ServerErrors
+	stats.ClientErrorRate = errorRate(stats.ClientErrors, stats.Requests)
+	stats.ServerErrorRate = errorRate(stats.ServerErrors, stats.Requests)
+
+	err = db.Table("transactions").
+		Select("categories.name AS name, COUNT(*) AS transactions").
+		Joins("JOIN categories ON categories.id = transactions.category_id").
+		Where("transactions.created_at >= ? AND transactions.created_at < ?", start, end).
+		Group("categories.name").
+		Having("COUNT(DISTINCT transactions.user_id) >= ?", domain.StatsMinCategoryUsers).
+		Order("transactions DESC, name").
+		Limit(statsTopCategories).
+		Scan(&stats.TopCategories).Error
+	if err != nil {
+		return nil, err
+	}
+	if stats.TopCategories == nil {
+		stats.TopCategories = []domain.CategoryUsage{}
+	}
+
+	if err := db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&stats).Error; err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// errorRate is the share of requests that failed, rounded to four places
+func errorRate(failed, requests int) float64 {
+	if requests == 0 {
+		return 0
+	}
+	return math.Round(float64(failed)/float64(requests)*10000) / 10000
+}
+
+// Refresh writes the counted requests, recomputes the stats of yesterday,
+// whose last requests may have been flushed after midnight, and of today,
+// and removes request counts past their retention
+func (s *StatsService) Refresh(ctx context.Context) error {
+	if err := s.Flush(ctx); err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, day := range []time.Time{now.Add(-24 * time.Hour), now} {
+		if _, err := s.Compute(ctx, day); err != nil {
+			return err
+		}
+	}
+	return s.DB.WithContext(ctx).Where("day < ?", quotaDay(now).Add(-statsActivityRetention)).
+		Delete(&domain.RequestActivity{}).Error
+}
+
+// StartRefreshing runs Refresh on the given interval until ctx is cancelled
+func (s *StatsService) StartRefreshing(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Refresh(ctx); err != nil {
+			log.Printf("usage stats failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Report returns the stored stats of the filter's days with their totals,
+// the last 30 days by default
+func (s *StatsService) Report(ctx context.Context, filter domain.StatsFilter) (*domain.StatsReport, error) {
+	to := quotaDay(time.Now())
+	if filter.To != nil {
+		to = quotaDay(*filter.To)
+	}
+	from := to.AddDate(0, 0, 1-defaultStatsDays)
+	if filter.From != nil {
+		from = quotaDay(*filter.From)
+	}
+	if from.After(to) {
+		return nil, &domain.ValidationError{Fields: []domain.FieldError{{Field: "from", Message: "must not be after to"}}}
+	}
+	if to.Sub(from) >= maxStatsDays*24*time.Hour {
+		return nil, &domain.ValidationError{Fields: []domain.FieldError{{Field: "from", Message: "must be at most 366 days before to"}}}
+	}
+
+	report := &domain.StatsReport{From: from, To: to, Days: []domain.DailyStats{}}
+	if err := s.DB.WithContext(ctx).Where("day >= ? AND day <= ?", from, to).Order("day").Find(&report.Days).Error; err != nil {
+		return nil, err
+	}
+
+	totals := &report.Totals
+	activeUsers, clientErrors, serverErrors := 0, 0, 0
+	categories := map[string]int{}
+	for _, day := range report.Days {
+		totals.NewUsers += day.NewUsers
+		totals.Transactions += day.Transactions
+		totals.AdvisorCalls += day.AdvisorCalls
+		totals.Requests += day.Requests
+		activeUsers += day.ActiveUsers
+		clientErrors += day.ClientErrors
+		serverErrors += day.ServerErrors
+		for _, category := range day.TopCategories {
+			categories[category.Name] += category.Transactions
+		}
+	}
+	if len(report.Days) > 0 {
+		totals.AverageActiveUsers = math.Round(float64(activeUsers)/float64(len(report.Days))*100) / 100
+	}
+	totals.ClientErrorRate = errorRate(clientErrors, totals.Requests)
+	totals.ServerErrorRate = errorRate(serverErrors, totals.Requests)
+
+	totals.TopCategories = make([]domain.CategoryUsage, 0, len(categories))
+	for name, transactions := range categories {
+		totals.TopCategories = append(totals.TopCategories, domain.CategoryUsage{Name: name, Transactions: transactions})
+	}
+	sort.Slice(totals.TopCategories, func(i, j int) bool {
+		a, b := totals.TopCategories[i], totals.TopCategories[j]
+		if a.Transactions != b.Transactions {
+			return a.Transactions > b.Transactions
+		}
+		return a.Name < b.Name
+	})
+	if len(totals.TopCategories) > statsTopCategories {
+		totals.TopCategories = totals.TopCategories[:statsTopCategories]
+	}
+	return report, nil
+}
@@ -0,0 +1,131 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsService(t *testing.T) {
+	db := setupQuotaTestDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.RequestActivity{}, &domain.DailyStats{}))
+	service := NewStatsService(db)
+	ctx := context.Background()
+	today := quotaDay(time.Now())
+
+	var users []domain.User
+	for i := 0; i < 4; i++ {
+		user := domain.User{Email: fmt.Sprintf("user%d@example.com", i), Password: "x"}
+		require.NoError(t, db.Create(&user).Error)
+		users = append(users, user)
+	}
+	// Everybody has a Groceries category, only the first user a Hobby one
+	for _, user := range users {
+		category := domain.Category{UserID: &user.ID, Name: "Groceries", Type: domain.TransactionTypeExpense}
+		require.NoError(t, db.Create(&category).Error)
+		require.NoError(t, db.Create(&domain.Transaction{
+			UserID: user.ID, CategoryID: category.ID, Type: domain.TransactionTypeExpense,
+			Amount: 10, Description: "Market", Date: time.Now(),
+		}).Error)
+	}
+	hobby := domain.Category{UserID: &users[0].ID, Name: "Hobby", Type: domain.TransactionTypeExpense}
+	require.NoError(t, db.Create(&hobby).Error)
+	require.NoError(t, db.Create(&domain.Transaction{
+		UserID: users[0].ID, CategoryID: hobby.ID, Type: domain.TransactionTypeExpense,
+		Amount: 5, Description: "Paint", Date: time.Now(),
+	}).Error)
+	require.NoError(t, db.Create(&domain.UsageCounter{UserID: users[0].ID, Quota: domain.QuotaAIRequests, Day: today, Count: 3}).Error)
+	require.NoError(t, db.Create(&domain.UsageCounter{UserID: users[1].ID, Quota: domain.QuotaAIRequests, Day: today, Count: 2}).Error)
+	require.NoError(t, db.Create(&domain.UsageCounter{UserID: users[1].ID, Quota: domain.QuotaBankRefreshes, Day: today, Count: 1}).Error)
+
+	t.Run("should add up the recorded requests on every flush", func(t *testing.T) {
+		service.Record(users[0].ID, 200)
+		service.Record(users[0].ID, 404)
+		service.Record(0, 401)
+		require.NoError(t, service.Flush(ctx))
+		service.Record(users[0].ID, 500)
+		service.Record(users[1].ID, 201)
+		require.NoError(t, service.Flush(ctx))
+		require.NoError(t, service.Flush(ctx))
+
+		var activity domain.RequestActivity
+		require.NoError(t, db.Where("user_id = ?", users[0].ID).First(&activity).Error)
+		assert.Equal(t, domain.RequestActivity{UserID: users[0].ID, Day: activity.Day, Requests: 3, ClientErrors: 1, ServerErrors: 1}, activity)
+	})
+
+	t.Run("should compute anonymous aggregates of the day", func(t *testing.T) {
+		stats, err := service.Compute(ctx, time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, 4, stats.TotalUsers)
+		assert.Equal(t, 4, stats.NewUsers)
+		assert.Equal(t, 2, stats.ActiveUsers, "requests without a user are not an active user")
+		assert.Equal(t, 5, stats.Transactions)
+		assert.Equal(t, 5, stats.AdvisorCalls)
+		assert.Equal(t, 5, stats.Requests)
+		assert.Equal(t, 0.4, stats.ClientErrorRate)
+		assert.Equal(t, 0.2, stats.ServerErrorRate)
+		assert.Equal(t, []domain.CategoryUsage{{Name: "Groceries", Transactions: 4}}, stats.TopCategories,
+			"categories of fewer users than the minimum are left out")
+
+		var stored []domain.DailyStats
+		require.NoError(t, db.Find(&stored).Error)
+		require.Len(t, stored, 1)
+		assert.Equal(t, stats.TopCategories, stored[0].TopCategories)
+
+		_, err = service.Compute(ctx, time.Now())
+		require.NoError(t, err)
+		var count int64
+		require.NoError(t, db.Model(&domain.DailyStats{}).Count(&count).Error)
+		assert.Equal(t, int64(1), count, "recomputing replaces the day")
+	})
+
+	t.Run("should report days with their totals", func(t *testing.T) {
+		yesterday := today.AddDate(0, 0, -1)
+		require.NoError(t, db.Create(&domain.DailyStats{
+			Day: yesterday, ActiveUsers: 1, Transactions: 2, Requests: 5, ClientErrors: 1,
+			TopCategories: []domain.CategoryUsage{{Name: "Rent", Transactions: 2}, {Name: "Groceries", Transactions: 1}},
+		}).Error)
+
+		report, err := service.Report(ctx, domain.StatsFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, today.AddDate(0, 0, -29), report.From)
+		require.Len(t, report.Days, 2)
+		assert.Equal(t, yesterday, report.Days[0].Day.UTC())
+		assert.Equal(t, 1.5, report.Totals.AverageActiveUsers)
+		assert.Equal(t, 7, report.Totals.Transactions)
+		assert.Equal(t, 10, report.Totals.Requests)
+		assert.Equal(t, 0.3, report.Totals.ClientErrorRate)
+		assert.Equal(t, []domain.CategoryUsage{{Name: "Groceries", Transactions: 5}, {Name: "Rent", Transactions: 2}},
+			report.Totals.TopCategories)
+
+		report, err = service.Report(ctx, domain.StatsFilter{From: &today})
+		require.NoError(t, err)
+		assert.Len(t, report.Days, 1)
+	})
+
+	t.Run("should reject ranges that are reversed or too long", func(t *testing.T) {
+		past := today.AddDate(-2, 0, 0)
+		_, err := service.Report(ctx, domain.StatsFilter{From: &today, To: &past})
+		assert.ErrorIs(t, err, domain.ErrValidation)
+		_, err = service.Report(ctx, domain.StatsFilter{From: &past})
+		assert.ErrorIs(t, err, domain.ErrValidation)
+	})
+
+	t.Run("should remove request counts past their retention", func(t *testing.T) {
+		old := domain.RequestActivity{UserID: users[2].ID, Day: today.AddDate(0, 0, -10), Requests: 1}
+		require.NoError(t, db.Create(&old).Error)
+		require.NoError(t, service.Refresh(ctx))
+
+		var count int64
+		require.NoError(t, db.Model(&domain.RequestActivity{}).Where("user_id = ?", users[2].ID).Count(&count).Error)
+		assert.Zero(t, count)
+		require.NoError(t, db.Model(&domain.DailyStats{}).Count(&count).Error)
+		assert.Equal(t, int64(2), count)
+	})
+}
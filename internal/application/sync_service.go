@@ -0,0 +1,398 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SyncService lets offline clients, such as the mobile app, pull what
+// changed since they last synced and push the changes they queued. It keeps
+// a change feed of the users' transactions by subscribing to the events of
+// the transaction service it pushes through.
+type SyncService struct {
+	DB           *gorm.DB
+	Transactions *TransactionService
+}
+
+func NewSyncService(db *gorm.DB, transactions *TransactionService) *SyncService {
+	return &SyncService{DB: db, Transactions: transactions}
+}
+
+// Subscribe records the transaction events in the change feed
+func (s *SyncService) Subscribe(bus *EventBus) {
+	bus.Subscribe(s.handleEvent, transactionEvents...)
+}
+
+func (s *SyncService) handleEvent(ctx context.Context, event domain.Event) {
+	var err error
+	switch e := event.(type) {
+	case domain.TransactionCreated:
+		err = s.record(ctx, e.Transaction.UserID, e.Transaction.ID, domain.SyncOperationUpsert)
+	case domain.TransactionUpdated:
+		err = s.record(ctx, e.After.UserID, e.After.ID, domain.SyncOperationUpsert)
+	case domain.TransactionDeleted:
+		err = s.record(ctx, e.Transaction.UserID, e.Transaction.ID, domain.SyncOperationDelete)
+	case domain.TransactionRestored:
+		err = s.record(ctx, e.Transaction.UserID, e.Transaction.ID, domain.SyncOperationUpsert)
+	}
+	if err != nil {
+		log.Printf("sync: failed to record %s: %v", event.EventType(), err)
+	}
+}
+
+// maxRecordAttempts bounds how often record retries a version another
+// change of the same transaction took first
+const maxRecordAttempts = 5
+
+// errVersionTaken is returned when every attempt to record a change lost
+// its version to a concurrent change
+var errVersionTaken = errors.New("sync version was taken by concurrent changes")
+
+// record adds a change of the transaction to the feed with the next
+// version. The unique index on the version decides between concurrent
+// changes: the one that loses reads the new latest version and tries again.
+func (s *SyncService) record(ctx context.Context, userID, transactionID uint, operation string) error {
+	for range maxRecordAttempts {
+		latest, err := s.latest(ctx, transactionID)
+		if err != nil {
+			return err
+		}
+		result := s.DB.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&domain.SyncChange{
+			UserID: userID, EntityType: domain.SyncEntityTransaction, EntityID: transactionID,
+			Version: latest.Version + 1, Operation: operation,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			return nil
+		}
+	}
+	return errVersionTaken
+}
+
+// latest returns the last change of the transaction, with a zero version if
+// it has none
+func (s *SyncService) latest(ctx context.Context, transactionID uint) (domain.SyncChange, error) {
+	var changes []domain.SyncChange
+	err := s.DB.WithContext(ctx).
+		Where("entity_type = ? AND entity_id = ?", domain.SyncEntityTransaction, transactionID).
+		Order("version DESC").Limit(1).Find(&changes).Error
+	if err != nil || len(changes) == 0 {
+		return domain.SyncChange{}, err
+	}
+	return changes[0], nil
+}
+
+// Changes returns a page of the user's changes after the since cursor, zero
+// for all of them, oldest first. Upserted transactions come with their
+// current state, tags and items included.
+func (s *SyncService) Changes(ctx context.Context, userID, since uint, limit int) (*domain.SyncChanges, error) {
+	if limit <= 0 {
+		limit = domain.DefaultSyncPageSize
+	}
+	limit = min(limit, domain.MaxSyncPageSize)
+
+	var changes []domain.SyncChange
+	err := s.DB.WithContext(ctx).Where("user_id = ? AND id > ?", userID, since).
+		Order("id").Limit(limit + 1).Find(&changes).Error
+	if err != nil {
+		return nil, err
+	}
+	page := &domain.SyncChanges{Changes: []domain.SyncChange{}, Cursor: since}
+	if len(changes) > limit {
+		page.HasMore = true
+		changes = changes[:limit]
+	}
+	if len(changes) == 0 {
+		return page, nil
+	}
+	page.Cursor = changes[len(changes)-1].ID
+
+	// Only the last change of each transaction on the page counts
+	seen := make(map[uint]bool, len(changes))
+	var upserted []uint
+	for i := len(changes) - 1; i >= 0; i-- {
+		change := changes[i]
+		if seen[change.EntityID] {
+			continue
+		}
+		seen[change.EntityID] = true
+		page.Changes = append(page.Changes, change)
+		if change.Operation == domain.SyncOperationUpsert {
+			upserted = append(upserted, change.EntityID)
+		}
+	}
+	for i, j := 0, len(page.Changes)-1; i < j; i, j = i+1, j-1 {
+		page.Changes[i], page.Changes[j] = page.Changes[j], page.Changes[i]
+	}
+
+	transactions, err := s.transactions(ctx, userID, upserted)
+	if err != nil {
+		return nil, err
+	}
+	for i := range page.Changes {
+		change := &page.Changes[i]
+		if change.Operation != domain.SyncOperationUpsert {
+			continue
+		}
+		if transaction, ok := transactions[change.EntityID]; ok {
+			change.Transaction = transaction
+		} else {
+			// Purged from the trash since
+			change.Operation = domain.SyncOperationDelete
+		}
+	}
+	return page, nil
+}
+
+// transactions loads the user's transactions with the IDs, trashed ones
+// included, with their tags and items
+func (s *SyncService) transactions(ctx context.Context, userID uint, ids []uint) (map[uint]*domain.Transaction, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var transactions []domain.Transaction
+	err := s.DB.WithContext(ctx).Unscoped().Preload("Category").
+		Where("user_id = ? AND id IN ?", userID, ids).Find(&transactions).Error
+	if err != nil {
+		return nil, err
+	}
+	if err := loadTransactionTags(ctx, s.DB, transactions); err != nil {
+		return nil, err
+	}
+	if err := loadTransactionItems(ctx, s.DB, transactions); err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*domain.Transaction, len(transactions))
+	for i := range transactions {
+		byID[transactions[i].ID] = &transactions[i]
+	}
+	return byID, nil
+}
+
+// Push applies the mutations a client queued offline, in order, and reports
+// the outcome of each. A mutation based on an older version than the
+// server's is a conflict: the later write wins, by the client's ModifiedAt
+// and the time of the server's last change, and both sides are recorded. A
+// mutation without ModifiedAt counts as made now. Mutations that cannot be
+// saved are rejected without stopping the others.
+func (s *SyncService) Push(ctx context.Context, userID uint, mutations []domain.SyncMutation) (*domain.SyncPushResult, error) {
+	if len(mutations) > domain.MaxSyncMutations {
+		return nil, &domain.ValidationError{Fields: []domain.FieldError{{Field: "mutations", Message: "must list at most 100 mutations"}}}
+	}
+	result := &domain.SyncPushResult{Results: make([]domain.SyncMutationResult, 0, len(mutations))}
+	for i := range mutations {
+		outcome, err := s.apply(ctx, userID, &mutations[i])
+		if err != nil {
+			return nil, err
+		}
+		result.Results = append(result.Results, *outcome)
+	}
+	return result, nil
+}
+
+// apply applies one mutation; only failures of the database are returned
+func (s *SyncService) apply(ctx context.Context, userID uint, mutation *domain.SyncMutation) (*domain.SyncMutationResult, error) {
+	outcome := &domain.SyncMutationResult{
+		ClientID: mutation.ClientID, Operation: mutation.Operation, EntityID: mutation.EntityID,
+	}
+	err := mutation.Validate()
+	if err == nil {
+		if mutation.ModifiedAt.IsZero() {
+			mutation.ModifiedAt = time.Now()
+		}
+		switch mutation.Operation {
+		case domain.SyncMutationCreate:
+			err = s.create(ctx, userID, mutation, outcome)
+		case domain.SyncMutationUpdate:
+			err = s.update(ctx, userID, mutation, outcome)
+		case domain.SyncMutationDelete:
+			err = s.remove(ctx, userID, mutation, outcome)
+		}
+	}
+	switch {
+	case errors.Is(err, domain.ErrValidation), errors.Is(err, domain.ErrNotFound),
+		errors.Is(err, domain.ErrCategoryCapExceeded), errors.Is(err, domain.ErrExchangeRateUnavailable):
+		outcome.Status, outcome.Error = domain.SyncStatusRejected, err.Error()
+	case err != nil:
+		return nil, err
+	}
+	return outcome, nil
+}
+
+// create records a new transaction, or reports the one a retried push
+// already created for the client ID
+func (s *SyncService) create(ctx context.Context, userID uint, mutation *domain.SyncMutation, outcome *domain.SyncMutationResult) error {
+	var created domain.SyncMutationClient
+	err := s.DB.WithContext(ctx).Where("user_id = ? AND client_id = ?", userID, mutation.ClientID).First(&created).Error
+	if err == nil {
+		return s.settle(ctx, userID, created.EntityID, domain.SyncStatusApplied, outcome)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	transaction := *mutation.Transaction
+	transaction.ID, transaction.UserID = 0, userID
+	if err := s.Transactions.Create(ctx, &transaction); err != nil {
+		return err
+	}
+	err = s.DB.WithContext(ctx).Create(&domain.SyncMutationClient{
+		UserID: userID, ClientID: mutation.ClientID, EntityID: transaction.ID,
+	}).Error
+	if err != nil {
+		return err
+	}
+	return s.settle(ctx, userID, transaction.ID, domain.SyncStatusApplied, outcome)
+}
+
+// update replaces the fields of a transaction with the client's. An edit
+// that wins over a deletion takes the transaction out of the trash.
+func (s *SyncService) update(ctx context.Context, userID uint, mutation *domain.SyncMutation, outcome *domain.SyncMutationResult) error {
+	existing, err := s.owned(ctx, userID, mutation.EntityID)
+	if err != nil {
+		return err
+	}
+	if wins, err := s.resolve(ctx, userID, mutation, existing, outcome); err != nil || !wins {
+		return err
+	}
+	if existing.DeletedAt.Valid {
+		if _, err := s.Transactions.Restore(ctx, userID, existing.ID); err != nil {
+			return err
+		}
+		existing.DeletedAt = gorm.DeletedAt{}
+	}
+
+	changes := mutation.Transaction
+	existing.Amount = changes.Amount
+	existing.Currency = changes.Currency
+	existing.OriginalAmount = nil
+	existing.Type = changes.Type
+	existing.Description = changes.Description
+	existing.Notes = changes.Notes
+	existing.Tags = changes.Tags
+	existing.Items = changes.Items
+	existing.CategoryID = changes.CategoryID
+	existing.AccountID = changes.AccountID
+	if !changes.Date.IsZero() {
+		existing.Date = changes.Date
+	}
+	existing.Latitude = changes.Latitude
+	existing.Longitude = changes.Longitude
+	existing.Place = changes.Place
+	existing.City = changes.City
+	if err := s.Transactions.Update(ctx, existing); err != nil {
+		return err
+	}
+	return s.settle(ctx, userID, existing.ID, domain.SyncStatusApplied, outcome)
+}
+
+// remove moves a transaction to the trash; one already there stays deleted
+func (s *SyncService) remove(ctx context.Context, userID uint, mutation *domain.SyncMutation, outcome *domain.SyncMutationResult) error {
+	existing, err := s.owned(ctx, userID, mutation.EntityID)
+	if err != nil {
+		return err
+	}
+	if !existing.DeletedAt.Valid {
+		if wins, err := s.resolve(ctx, userID, mutation, existing, outcome); err != nil || !wins {
+			return err
+		}
+		if err := s.Transactions.Delete(ctx, existing.ID); err != nil {
+			return err
+		}
+	}
+	return s.settle(ctx, userID, existing.ID, domain.SyncStatusApplied, outcome)
+}
+
+// owned returns the user's transaction, even from the trash
+func (s *SyncService) owned(ctx context.Context, userID, id uint) (*domain.Transaction, error) {
+	var transaction domain.Transaction
+	err := s.DB.WithContext(ctx).Unscoped().Where("id = ? AND user_id = ?", id, userID).First(&transaction).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &transaction, nil
+}
+
+// resolve reports whether the mutation is to be applied. A mutation based on
+// the current version is; otherwise the conflict is recorded, and the later
+// of the client's and the server's writes wins. A mutation that loses is
+// settled as skipped with the server's state.
+func (s *SyncService) resolve(
+	ctx context.Context, userID uint, mutation *domain.SyncMutation, existing *domain.Transaction, outcome *domain.SyncMutationResult,
+) (bool, error) {
+	latest, err := s.latest(ctx, existing.ID)
+	if err != nil {
+		return false, err
+	}
+	if mutation.BaseVersion == latest.Version {
+		return true, nil
+	}
+
+	wins := mutation.ModifiedAt.After(latest.CreatedAt)
+	conflict := domain.SyncConflict{
+		UserID: userID, EntityType: domain.SyncEntityTransaction, EntityID: existing.ID, Operation: mutation.Operation,
+		BaseVersion: mutation.BaseVersion, ServerVersion: latest.Version, Resolution: domain.SyncResolutionServerWins,
+		ClientModifiedAt: mutation.ModifiedAt, ServerModifiedAt: latest.CreatedAt,
+	}
+	if wins {
+		conflict.Resolution = domain.SyncResolutionClientWins
+	}
+	if mutation.Transaction != nil {
+		if conflict.ClientData, err = json.Marshal(mutation.Transaction); err != nil {
+			return false, err
+		}
+	}
+	if conflict.ServerData, err = json.Marshal(existing); err != nil {
+		return false, err
+	}
+	if err := s.DB.WithContext(ctx).Create(&conflict).Error; err != nil {
+		return false, err
+	}
+	outcome.ConflictID = &conflict.ID
+	if !wins {
+		return false, s.settle(ctx, userID, existing.ID, domain.SyncStatusSkipped, outcome)
+	}
+	return true, nil
+}
+
+// settle fills in the outcome with the transaction's version and current
+// state, which is left out once it is deleted
+func (s *SyncService) settle(ctx context.Context, userID, transactionID uint, status string, outcome *domain.SyncMutationResult) error {
+	latest, err := s.latest(ctx, transactionID)
+	if err != nil {
+		return err
+	}
+	transactions, err := s.transactions(ctx, userID, []uint{transactionID})
+	if err != nil {
+		return err
+	}
+	outcome.Status, outcome.EntityID, outcome.Version = status, transactionID, latest.Version
+	if transaction, ok := transactions[transactionID]; ok && !transaction.DeletedAt.Valid {
+		outcome.Transaction = transaction
+	}
+	return nil
+}
+
+// ListConflicts returns the user's recorded conflicts, newest first
+func (s *SyncService) ListConflicts(ctx context.Context, userID uint, limit int) ([]domain.SyncConflict, error) {
+	if limit <= 0 {
+		limit = domain.DefaultSyncPageSize
+	}
+	var conflicts []domain.SyncConflict
+	err := s.DB.WithContext(ctx).Where("user_id = ?", userID).
+		Order("id DESC").Limit(min(limit, domain.MaxSyncPageSize)).Find(&conflicts).Error
+	return conflicts, err
+}
@@ -0,0 +1,288 @@
+package application
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupSyncTestService(t *testing.T) (*SyncService, *gorm.DB) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&domain.Category{}, &domain.Transaction{}, &domain.TransactionTag{}, &domain.TransactionItem{},
+		&domain.SyncChange{}, &domain.SyncMutationClient{}, &domain.SyncConflict{},
+	))
+	events := NewEventBus()
+	service := NewSyncService(db, &TransactionService{DB: db, Events: events})
+	service.Subscribe(events)
+	return service, db
+}
+
+func syncedExpense(categoryID uint, description string, amount float64) *domain.Transaction {
+	return &domain.Transaction{
+		CategoryID: categoryID, Type: domain.TransactionTypeExpense, Description: description,
+		Amount: domain.NewMoney(amount), Date: time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestSyncService_Changes(t *testing.T) {
+	service, db := setupSyncTestService(t)
+	ctx := context.Background()
+	category := &domain.Category{Name: "Groceries", Type: domain.TransactionTypeExpense}
+	require.NoError(t, db.Create(category).Error)
+
+	var created []*domain.Transaction
+	for _, description := range []string{"Bakery", "Market", "Butcher"} {
+		transaction := syncedExpense(category.ID, description, 10)
+		transaction.UserID = 1
+		transaction.Tags = []string{"food"}
+		require.NoError(t, service.Transactions.Create(ctx, transaction))
+		created = append(created, transaction)
+	}
+	other := syncedExpense(category.ID, "Other user", 5)
+	other.UserID = 2
+	require.NoError(t, service.Transactions.Create(ctx, other))
+
+	created[0].Amount = domain.NewMoney(12)
+	require.NoError(t, service.Transactions.Update(ctx, created[0]))
+	require.NoError(t, service.Transactions.Delete(ctx, created[1].ID))
+
+	t.Run("should list each transaction once with its latest change", func(t *testing.T) {
+		page, err := service.Changes(ctx, 1, 0, 0)
+		require.NoError(t, err)
+		assert.False(t, page.HasMore)
+		require.Len(t, page.Changes, 3)
+
+		byID := make(map[uint]domain.SyncChange)
+		for _, change := range page.Changes {
+			byID[change.EntityID] = change
+		}
+		assert.Equal(t, int64(2), byID[created[0].ID].Version)
+		assert.Equal(t, domain.SyncOperationUpsert, byID[created[0].ID].Operation)
+		require.NotNil(t, byID[created[0].ID].Transaction)
+		assert.Equal(t, domain.NewMoney(12), byID[created[0].ID].Transaction.Amount)
+		assert.Equal(t, []string{"food"}, byID[created[0].ID].Transaction.Tags)
+		assert.Equal(t, domain.SyncOperationDelete, byID[created[1].ID].Operation)
+		assert.Nil(t, byID[created[1].ID].Transaction)
+		assert.Equal(t, int64(1), byID[created[2].ID].Version)
+
+		next, err := service.Changes(ctx, 1, page.Cursor, 0)
+		require.NoError(t, err)
+		assert.Empty(t, next.Changes)
+		assert.Equal(t, page.Cursor, next.Cursor)
+	})
+
+	t.Run("should page through the feed", func(t *testing.T) {
+		var cursor uint
+		var pages []int
+		for {
+			page, err := service.Changes(ctx, 1, cursor, 2)
+			require.NoError(t, err)
+			pages = append(pages, len(page.Changes))
+			cursor = page.Cursor
+			if !page.HasMore {
+				break
+			}
+		}
+		// Three creates, an edit and a deletion
+		assert.Equal(t, []int{2, 2, 1}, pages)
+	})
+
+	t.Run("should report purged transactions as deleted", func(t *testing.T) {
+		require.NoError(t, service.Transactions.Purge(ctx, 1, created[1].ID))
+		page, err := service.Changes(ctx, 1, 0, 0)
+		require.NoError(t, err)
+		for _, change := range page.Changes {
+			if change.EntityID == created[1].ID {
+				assert.Equal(t, domain.SyncOperationDelete, change.Operation)
+			}
+		}
+	})
+}
+
+func TestSyncService_RecordConcurrentChanges(t *testing.T) {
+	service, db := setupSyncTestService(t)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1) // one in-memory database
+	ctx := context.Background()
+
+	// Every change reads the same latest version before the first one is
+	// inserted, so all but one lose it; in the worst case one more loses
+	// each further round
+	changes := maxRecordAttempts
+	var arrived atomic.Int32
+	var readAll sync.WaitGroup
+	readAll.Add(changes)
+	require.NoError(t, db.Callback().Create().Before("gorm:begin_transaction").Register("test:read_all", func(*gorm.DB) {
+		if arrived.Add(1) <= int32(changes) {
+			readAll.Done()
+			readAll.Wait()
+		}
+	}))
+
+	errs := make([]error, changes)
+	var wg sync.WaitGroup
+	for i := range changes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = service.record(ctx, 1, 42, domain.SyncOperationUpsert)
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+	var versions []int64
+	require.NoError(t, db.Model(&domain.SyncChange{}).Where("entity_id = ?", 42).Order("version").Pluck("version", &versions).Error)
+	assert.Equal(t, []int64{1, 2, 3, 4, 5}, versions, "every change is in the feed with its own version")
+}
+
+func TestSyncService_Push(t *testing.T) {
+	service, db := setupSyncTestService(t)
+	ctx := context.Background()
+	category := &domain.Category{Name: "Groceries", Type: domain.TransactionTypeExpense}
+	require.NoError(t, db.Create(category).Error)
+
+	var bakeryID uint
+	t.Run("should create transactions once per client ID", func(t *testing.T) {
+		mutation := domain.SyncMutation{
+			ClientID: "phone-1", Operation: domain.SyncMutationCreate, Transaction: syncedExpense(category.ID, "Bakery", 4.5),
+		}
+		result, err := service.Push(ctx, 1, []domain.SyncMutation{mutation})
+		require.NoError(t, err)
+		require.Len(t, result.Results, 1)
+		assert.Equal(t, domain.SyncStatusApplied, result.Results[0].Status)
+		assert.Equal(t, int64(1), result.Results[0].Version)
+		require.NotNil(t, result.Results[0].Transaction)
+		assert.Equal(t, uint(1), result.Results[0].Transaction.UserID)
+		bakeryID = result.Results[0].EntityID
+
+		retried, err := service.Push(ctx, 1, []domain.SyncMutation{mutation})
+		require.NoError(t, err)
+		assert.Equal(t, bakeryID, retried.Results[0].EntityID)
+		var count int64
+		require.NoError(t, db.Model(&domain.Transaction{}).Count(&count).Error)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("should apply edits based on the current version", func(t *testing.T) {
+		result, err := service.Push(ctx, 1, []domain.SyncMutation{{
+			Operation: domain.SyncMutationUpdate, EntityID: bakeryID, BaseVersion: 1,
+			Transaction: syncedExpense(category.ID, "Bakery", 5),
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, domain.SyncStatusApplied, result.Results[0].Status)
+		assert.Equal(t, int64(2), result.Results[0].Version)
+		assert.Nil(t, result.Results[0].ConflictID)
+		assert.Equal(t, domain.NewMoney(5), result.Results[0].Transaction.Amount)
+	})
+
+	t.Run("should keep a later server change over an older edit", func(t *testing.T) {
+		result, err := service.Push(ctx, 1, []domain.SyncMutation{{
+			Operation: domain.SyncMutationUpdate, EntityID: bakeryID, BaseVersion: 1, ModifiedAt: time.Now().Add(-time.Hour),
+			Transaction: syncedExpense(category.ID, "Bakery", 7),
+		}})
+		require.NoError(t, err)
+		outcome := result.Results[0]
+		assert.Equal(t, domain.SyncStatusSkipped, outcome.Status)
+		assert.Equal(t, int64(2), outcome.Version)
+		assert.Equal(t, domain.NewMoney(5), outcome.Transaction.Amount)
+		require.NotNil(t, outcome.ConflictID)
+
+		var conflict domain.SyncConflict
+		require.NoError(t, db.First(&conflict, *outcome.ConflictID).Error)
+		assert.Equal(t, domain.SyncResolutionServerWins, conflict.Resolution)
+		assert.Equal(t, int64(1), conflict.BaseVersion)
+		assert.Equal(t, int64(2), conflict.ServerVersion)
+		assert.Contains(t, string(conflict.ClientData), `"amount":7`)
+		assert.Contains(t, string(conflict.ServerData), `"amount":5`)
+	})
+
+	t.Run("should apply a later edit over a server change", func(t *testing.T) {
+		result, err := service.Push(ctx, 1, []domain.SyncMutation{{
+			Operation: domain.SyncMutationUpdate, EntityID: bakeryID, BaseVersion: 1, ModifiedAt: time.Now().Add(time.Minute),
+			Transaction: syncedExpense(category.ID, "Bakery", 8),
+		}})
+		require.NoError(t, err)
+		outcome := result.Results[0]
+		assert.Equal(t, domain.SyncStatusApplied, outcome.Status)
+		assert.Equal(t, int64(3), outcome.Version)
+		assert.Equal(t, domain.NewMoney(8), outcome.Transaction.Amount)
+		require.NotNil(t, outcome.ConflictID)
+	})
+
+	t.Run("should delete and restore by the later write", func(t *testing.T) {
+		result, err := service.Push(ctx, 1, []domain.SyncMutation{{
+			Operation: domain.SyncMutationDelete, EntityID: bakeryID, BaseVersion: 3,
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, domain.SyncStatusApplied, result.Results[0].Status)
+		assert.Equal(t, int64(4), result.Results[0].Version)
+		assert.Nil(t, result.Results[0].Transaction)
+
+		result, err = service.Push(ctx, 1, []domain.SyncMutation{{
+			Operation: domain.SyncMutationUpdate, EntityID: bakeryID, BaseVersion: 3, ModifiedAt: time.Now().Add(time.Minute),
+			Transaction: syncedExpense(category.ID, "Bakery", 9),
+		}})
+		require.NoError(t, err)
+		outcome := result.Results[0]
+		assert.Equal(t, domain.SyncStatusApplied, outcome.Status)
+		require.NotNil(t, outcome.Transaction)
+		assert.Equal(t, domain.NewMoney(9), outcome.Transaction.Amount)
+		assert.Equal(t, int64(6), outcome.Version)
+	})
+
+	t.Run("should reject mutations that cannot be saved without stopping the others", func(t *testing.T) {
+		result, err := service.Push(ctx, 1, []domain.SyncMutation{
+			{Operation: domain.SyncMutationCreate, Transaction: syncedExpense(category.ID, "No client ID", 1)},
+			{ClientID: "phone-2", Operation: domain.SyncMutationCreate, Transaction: syncedExpense(category.ID, "", 1)},
+			{Operation: domain.SyncMutationDelete, EntityID: 999},
+			{Operation: "rename", EntityID: bakeryID},
+			{ClientID: "phone-3", Operation: domain.SyncMutationCreate, Transaction: syncedExpense(category.ID, "Market", 3)},
+		})
+		require.NoError(t, err)
+		require.Len(t, result.Results, 5)
+		for _, outcome := range result.Results[:4] {
+			assert.Equal(t, domain.SyncStatusRejected, outcome.Status)
+			assert.NotEmpty(t, outcome.Error)
+		}
+		assert.Equal(t, domain.SyncStatusApplied, result.Results[4].Status)
+
+		other, err := service.Push(ctx, 2, []domain.SyncMutation{{Operation: domain.SyncMutationDelete, EntityID: bakeryID}})
+		require.NoError(t, err)
+		assert.Equal(t, domain.SyncStatusRejected, other.Results[0].Status)
+	})
+
+	t.Run("should limit the mutations of one push", func(t *testing.T) {
+		_, err := service.Push(ctx, 1, make([]domain.SyncMutation, domain.MaxSyncMutations+1))
+		assert.ErrorIs(t, err, domain.ErrValidation)
+	})
+
+	t.Run("should list the user's conflicts newest first", func(t *testing.T) {
+		conflicts, err := service.ListConflicts(ctx, 1, 0)
+		require.NoError(t, err)
+		require.Len(t, conflicts, 3)
+		assert.Equal(t, domain.SyncResolutionClientWins, conflicts[0].Resolution)
+		assert.Equal(t, domain.SyncResolutionServerWins, conflicts[2].Resolution)
+
+		none, err := service.ListConflicts(ctx, 2, 0)
+		require.NoError(t, err)
+		assert.Empty(t, none)
+	})
+}
@@ -0,0 +1,149 @@
+package application
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// dateRange is an inclusive range of transaction dates
+type dateRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+// periodTotals adds up the transactions of one period
+type periodTotals struct {
+	Income           domain.Money
+	Expenses         domain.Money
+	TransactionCount int
+}
+
+// sumByPeriod totals the user's income and expenses for each period with a
+// single grouped query instead of one per period. The result lines up with
+// periods; a transaction falling in overlapping periods counts towards the
+// first of them. Transfers between the user's accounts are left out.
+func sumByPeriod(ctx context.Context, db *gorm.DB, userID uint, periods []dateRange) ([]periodTotals, error) {
+	totals := make([]periodTotals, len(periods))
+	if len(periods) == 0 {
+		return totals, nil
+	}
+
+	from, to := periods[0].Start, periods[0].End
+	var bucket strings.Builder
+	args := make([]interface{}, 0, 2*len(periods))
+	bucket.WriteString("CASE")
+	for i, period := range periods {
+		bucket.WriteString(" WHEN date BETWEEN ? AND ? THEN ")
+		bucket.WriteString(strconv.Itoa(i))
+		args = append(args, period.Start, period.End)
+		if period.Start.Before(from) {
+			from = period.Start
+		}
+		if period.End.After(to) {
+			to = period.End
+		}
+	}
+	bucket.WriteString(" END")
+
+	var rows []struct {
+		Bucket sql.NullInt64
+		Type   string
+		Total  domain.Money
+		Count  int
+	}
+	err := db.WithContext(ctx).Model(&domain.Transaction{}).
+		Select(bucket.String()+" AS bucket, type, COALESCE(SUM(amount), 0) AS total, COUNT(*) AS count", args...).
+		Where("user_id = ? AND date BETWEEN ? AND ? AND type <> ?", userID, from, to, domain.TransactionTypeTransfer).
+		Group("bucket, type").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		if !row.Bucket.Valid || row.Bucket.Int64 < 0 || int(row.Bucket.Int64) >= len(totals) {
+			continue
+		}
+		period := &totals[row.Bucket.Int64]
+		if row.Type == domain.TransactionTypeIncome {
+			period.Income += row.Total
+		} else {
+			period.Expenses += row.Total
+		}
+		period.TransactionCount += row.Count
+	}
+	return totals, nil
+}
+
+// sumByCategory totals the user's transactions between the dates per
+// category with a single grouped query. It returns the breakdown, highest
+// total first, with shares of everything in the period, along with the
+// income and expense totals. Transfers are neither, so they are left out.
+func sumByCategory(
+	ctx context.Context, db *gorm.DB, userID uint, startDate, endDate time.Time,
+) (breakdown []domain.CategoryMetrics, income, expenses domain.Money, err error) {
+	var rows []struct {
+		CategoryID    uint
+		CategoryName  string
+		Uncategorized bool
+		Type          string
+		Total         domain.Money
+		Count         int
+	}
+	err = db.WithContext(ctx).Model(&domain.Transaction{}).
+		Select("transactions.category_id, categories.name AS category_name, "+
+			"(categories.id IS NULL OR categories.is_system) AS uncategorized, transactions.type, "+
+			"COALESCE(SUM(transactions.amount), 0) AS total, COUNT(*) AS count").
+		Joins("LEFT JOIN categories ON categories.id = transactions.category_id").
+		Where("transactions.user_id = ? AND transactions.date BETWEEN ? AND ? AND transactions.type <> ?",
+			userID, startDate, endDate, domain.TransactionTypeTransfer).
+		Group("transactions.category_id, categories.id, categories.name, categories.is_system, transactions.type").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	index := make(map[uint]int)
+	var totals []domain.Money
+	for _, row := range rows {
+		if row.Type == domain.TransactionTypeIncome {
+			income += row.Total
+		} else {
+			expenses += row.Total
+		}
+
+		i, exists := index[row.CategoryID]
+		if !exists {
+			i = len(breakdown)
+			index[row.CategoryID] = i
+			breakdown = append(breakdown, domain.CategoryMetrics{
+				CategoryID: row.CategoryID, CategoryName: row.CategoryName, Uncategorized: row.Uncategorized,
+			})
+			totals = append(totals, 0)
+		}
+		totals[i] += row.Total
+		breakdown[i].TransactionCount += row.Count
+	}
+
+	total := income + expenses
+	for i := range breakdown {
+		breakdown[i].TotalAmount = totals[i].Float64()
+		breakdown[i].AverageAmount = breakdown[i].TotalAmount / float64(breakdown[i].TransactionCount)
+		if total > 0 {
+			breakdown[i].PercentageOfTotal = totals[i].PercentOf(total)
+		}
+	}
+	sort.Slice(breakdown, func(i, j int) bool {
+		return breakdown[i].TotalAmount > breakdown[j].TotalAmount
+	})
+
+	return breakdown, income, expenses, nil
+}
@@ -0,0 +1,257 @@
+package application
+
+import (
+	"context"
+	"log"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/persistence"
+
+	"gorm.io/gorm"
+)
+
+// ListPageIncluding returns a page like ListPage with the requested per-row
+// details filled in, so a ledger can be shown without a request per row
+func (s *TransactionService) ListPageIncluding(
+	ctx context.Context, filter domain.TransactionFilter, include domain.TransactionIncludes,
+) (*domain.TransactionPage, error) {
+	page, err := s.ListPage(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if include.Tags {
+		if err := s.loadTags(ctx, page.Transactions); err != nil {
+			return nil, err
+		}
+	}
+	if include.Items {
+		if err := loadTransactionItems(ctx, s.DB, page.Transactions); err != nil {
+			return nil, err
+		}
+	}
+	if include.Attachments {
+		if err := s.countAttachments(ctx, page.Transactions); err != nil {
+			return nil, err
+		}
+	}
+	if include.RunningBalance {
+		if err := s.runningBalance(ctx, filter, page.Transactions); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.countComments(ctx, page.Transactions); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// runningBalance sets each row's balance: income minus expenses of every
+// transaction matching the filter up to and including the row. Listed for an
+// account, the transfers into it add to the balance and those out of it
+// take from it.
+func (s *TransactionService) runningBalance(ctx context.Context, filter domain.TransactionFilter, transactions []domain.Transaction) error {
+	if len(transactions) == 0 {
+		return nil
+	}
+	oldest := transactions[len(transactions)-1]
+	earlier := filter
+	earlier.Cursor, earlier.Limit, earlier.Offset = nil, 0, 0
+	earlier.OlderThan = &domain.TransactionCursor{Date: oldest.Date, ID: oldest.ID}
+
+	var balance domain.Money
+	for _, transactionType := range []string{domain.TransactionTypeIncome, domain.TransactionTypeExpense} {
+		if filter.Type != "" && filter.Type != transactionType {
+			continue
+		}
+		earlier.Type = transactionType
+		total, err := s.repository().Sum(ctx, earlier)
+		if err != nil {
+			return err
+		}
+		balance += (&domain.Transaction{Type: transactionType, Amount: total}).Signed()
+	}
+	if filter.AccountID != nil && (filter.Type == "" || filter.Type == domain.TransactionTypeTransfer) {
+		earlier.Type = domain.TransactionTypeTransfer
+		both, err := s.repository().Sum(ctx, earlier)
+		if err != nil {
+			return err
+		}
+		incoming := earlier
+		incoming.TransferAccountID = filter.AccountID
+		in, err := s.repository().Sum(ctx, incoming)
+		if err != nil {
+			return err
+		}
+		balance += in - (both - in)
+	}
+
+	for i := len(transactions) - 1; i >= 0; i-- {
+		if filter.AccountID != nil {
+			balance += transactions[i].SignedFor(*filter.AccountID)
+		} else {
+			balance += transactions[i].Signed()
+		}
+		rowBalance := balance
+		transactions[i].RunningBalance = &rowBalance
+	}
+	return nil
+}
+
+// countAttachments sets how many attachments each transaction has
+func (s *TransactionService) countAttachments(ctx context.Context, transactions []domain.Transaction) error {
+	if s.DB == nil || len(transactions) == 0 {
+		return nil
+	}
+	var counts []struct {
+		TransactionID uint
+		Count         int
+	}
+	err := s.DB.WithContext(ctx).Model(&domain.Attachment{}).
+		Select("transaction_id, COUNT(*) AS count").
+		Where("transaction_id IN ?", transactionIDs(transactions)).
+		Group("transaction_id").Scan(&counts).Error
+	if err != nil {
+		return err
+	}
+	byTransaction := make(map[uint]int, len(counts))
+	for _, count := range counts {
+		byTransaction[count.TransactionID] = count.Count
+	}
+	for i := range transactions {
+		count := byTransaction[transactions[i].ID]
+		transactions[i].AttachmentCount = &count
+	}
+	return nil
+}
+
+// countComments sets how many comments each transaction has. Comments
+// need the database; a service over a bare repository leaves them out.
+func (s *TransactionService) countComments(ctx context.Context, transactions []domain.Transaction) error {
+	if s.DB == nil {
+		return nil
+	}
+	counts, err := countComments(ctx, s.DB, domain.CommentTargetTransaction, transactionIDs(transactions))
+	if err != nil {
+		return err
+	}
+	for i := range transactions {
+		count := counts[transactions[i].ID]
+		transactions[i].CommentCount = &count
+	}
+	return nil
+}
+
+// loadTags fills in the tags of the transactions
+func (s *TransactionService) loadTags(ctx context.Context, transactions []domain.Transaction) error {
+	return loadTransactionTags(ctx, s.DB, transactions)
+}
+
+// loadTransactionTags fills in the tags of the transactions from the database
+func loadTransactionTags(ctx context.Context, db *gorm.DB, transactions []domain.Transaction) error {
+	if db == nil || len(transactions) == 0 {
+		return nil
+	}
+	var tags []domain.TransactionTag
+	err := persistence.Conn(ctx, db).Where("transaction_id IN ?", transactionIDs(transactions)).
+		Order("tag").Find(&tags).Error
+	if err != nil {
+		return err
+	}
+	byTransaction := make(map[uint][]string)
+	for _, tag := range tags {
+		byTransaction[tag.TransactionID] = append(byTransaction[tag.TransactionID], tag.Tag)
+	}
+	for i := range transactions {
+		transactions[i].Tags = byTransaction[transactions[i].ID]
+	}
+	return nil
+}
+
+// saveTags replaces the stored tags of the transaction with its Tags. Nil
+// Tags leave the stored ones alone. Tags need the database; a service over
+// a bare repository does not keep them.
+func (s *TransactionService) saveTags(ctx context.Context, transaction *domain.Transaction) error {
+	if s.DB == nil || transaction.Tags == nil {
+		return nil
+	}
+	return persistence.Conn(ctx, s.DB).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("transaction_id = ?", transaction.ID).Delete(&domain.TransactionTag{}).Error; err != nil {
+			return err
+		}
+		if len(transaction.Tags) == 0 {
+			return nil
+		}
+		tags := make([]domain.TransactionTag, len(transaction.Tags))
+		for i, tag := range transaction.Tags {
+			tags[i] = domain.TransactionTag{TransactionID: transaction.ID, Tag: tag}
+		}
+		return tx.Create(&tags).Error
+	})
+}
+
+// loadTransactionItems fills in the line items of the transactions from the
+// database, in receipt order
+func loadTransactionItems(ctx context.Context, db *gorm.DB, transactions []domain.Transaction) error {
+	if db == nil || len(transactions) == 0 {
+		return nil
+	}
+	var items []domain.TransactionItem
+	err := persistence.Conn(ctx, db).Where("transaction_id IN ?", transactionIDs(transactions)).
+		Order("transaction_id, position").Find(&items).Error
+	if err != nil {
+		return err
+	}
+	byTransaction := make(map[uint][]domain.TransactionItem)
+	for _, item := range items {
+		byTransaction[item.TransactionID] = append(byTransaction[item.TransactionID], item)
+	}
+	for i := range transactions {
+		transactions[i].Items = byTransaction[transactions[i].ID]
+	}
+	return nil
+}
+
+// saveItems replaces the stored line items of the transaction with its
+// Items, like saveTags does for tags
+func (s *TransactionService) saveItems(ctx context.Context, transaction *domain.Transaction) error {
+	if s.DB == nil || transaction.Items == nil {
+		return nil
+	}
+	return persistence.Conn(ctx, s.DB).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("transaction_id = ?", transaction.ID).Delete(&domain.TransactionItem{}).Error; err != nil {
+			return err
+		}
+		if len(transaction.Items) == 0 {
+			return nil
+		}
+		for i := range transaction.Items {
+			transaction.Items[i].TransactionID = transaction.ID
+		}
+		return tx.Create(&transaction.Items).Error
+	})
+}
+
+// dropOrphanDetails removes the tags and line items of purged transactions.
+// Failures are only logged: leftovers are never shown, as their transaction
+// is gone.
+func (s *TransactionService) dropOrphanDetails(ctx context.Context) {
+	if s.DB == nil {
+		return
+	}
+	for _, detail := range []any{&domain.TransactionTag{}, &domain.TransactionItem{}} {
+		err := s.DB.WithContext(ctx).
+			Where("transaction_id NOT IN (?)", s.DB.Unscoped().Model(&domain.Transaction{}).Select("id")).
+			Delete(detail).Error
+		if err != nil {
+			log.Printf("transactions: failed to drop details of purged transactions: %v", err)
+		}
+	}
+}
+
+func transactionIDs(transactions []domain.Transaction) []uint {
+	ids := make([]uint, len(transactions))
+	for i := range transactions {
+		ids[i] = transactions[i].ID
+	}
+	return ids
+}
@@ -1,219 +1,505 @@
 package application
 
 import (
+	"context"
+	"errors"
+	"log"
+	"strings"
 	"time"
 
 	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/persistence"
 
 	"gorm.io/gorm"
 )
 
+// defaultPageSize is used by ListPage when no limit is given
+const defaultPageSize = 100
+
 type TransactionService struct {
-	DB *gorm.DB
+	DB        *gorm.DB
+	Repo      domain.TransactionRepository // Falls back to a GORM repository over DB when nil
+	Audit     *AuditService                // Records modifications when set
+	Merchants *MerchantService             // Assigns merchants from descriptions when set
+	Budgets   *BudgetService               // Keeps budget spending in sync when set
+	Cache     ResultCache                  // Drops the user's cached analytics on changes when set
+	Caps      *CategoryCapService          // Flags or blocks expenses over category caps when set
+	FX        *FXService                   // Converts transactions in other currencies when set
+	// Categorizer files new transactions without a category under a
+	// suggested one when set
+	Categorizer *CategorizerService
+	// Events receives the transaction events when set, and Audit, Budgets
+	// and Cache are then left to subscribe to it
+	Events *EventBus
+}
+
+// NewTransactionService creates a service backed by the given repository
+func NewTransactionService(repo domain.TransactionRepository) *TransactionService {
+	return &TransactionService{Repo: repo}
+}
+
+func (s *TransactionService) repository() domain.TransactionRepository {
+	if s.Repo != nil {
+		return s.Repo
+	}
+	return persistence.NewTransactionRepository(s.DB)
+}
+
+// subscribed reports whether anything reacts to transaction events, which
+// decides whether edits read the previous version first
+func (s *TransactionService) subscribed() bool {
+	return s.Events != nil || s.Audit != nil || s.Budgets != nil || s.Cache != nil
+}
+
+// publish announces a change of a transaction on the event bus. Without a
+// bus the service's own Audit, Budgets and Cache handle it directly. Within
+// a unit of work the change is only announced once it committed.
+func (s *TransactionService) publish(ctx context.Context, event domain.Event) {
+	persistence.AfterCommit(ctx, func() {
+		if s.Events != nil {
+			s.Events.Publish(ctx, event)
+			return
+		}
+		s.Audit.handleEvent(ctx, event)
+		s.Budgets.handleEvent(ctx, event)
+		invalidateForEvent(ctx, s.Cache, event)
+	})
+}
+
+// validate checks the transaction against the business rules, including,
+// when the database can be queried, that its accounts are the user's, that
+// a transfer's two accounts hold the same currency and that its category is
+// of the transaction's type
+func (s *TransactionService) validate(ctx context.Context, transaction *domain.Transaction) error {
+	if err := transaction.Validate(); err != nil {
+		return err
+	}
+	if s.DB == nil {
+		return nil
+	}
+
+	var currencies []string
+	for _, account := range []struct {
+		field string
+		id    *uint
+	}{{"account_id", transaction.AccountID}, {"transfer_account_id", transaction.TransferAccountID}} {
+		if account.id == nil {
+			continue
+		}
+		var found domain.Account
+		err := persistence.Conn(ctx, s.DB).Select("id", "currency").
+			Where("id = ? AND user_id = ?", *account.id, transaction.UserID).Take(&found).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &domain.ValidationError{Fields: []domain.FieldError{{Field: account.field, Message: "does not exist"}}}
+		}
+		if err != nil {
+			return err
+		}
+		currencies = append(currencies, found.Currency)
+	}
+	if len(currencies) == 2 && currencies[0] != currencies[1] {
+		return &domain.ValidationError{Fields: []domain.FieldError{{
+			Field: "transfer_account_id", Message: "must hold the same currency as account_id",
+		}}}
+	}
+
+	var category domain.Category
+	err := persistence.Conn(ctx, s.DB).Select("id", "type").First(&category, transaction.CategoryID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return transaction.ValidateCategory(&category)
 }
 
 // Create creates a new transaction
-func (s *TransactionService) Create(transaction *domain.Transaction) error {
-	return s.DB.Create(transaction).Error
+func (s *TransactionService) Create(ctx context.Context, transaction *domain.Transaction) error {
+	if err := s.prepare(ctx, transaction); err != nil {
+		return err
+	}
+	return s.insert(ctx, transaction)
+}
+
+// prepare readies a new transaction for insert: it normalizes it, files it
+// under a suggested category or else Uncategorized, validates it, converts
+// its currency, checks it against the spending caps and assigns its merchant.
+// Transfers are not spending, so they are neither categorized nor given a
+// merchant.
+func (s *TransactionService) prepare(ctx context.Context, transaction *domain.Transaction) error {
+	transaction.Tags = domain.NormalizeTags(transaction.Tags)
+	transaction.Items = domain.NormalizeItems(transaction.Items)
+	transaction.Currency = strings.ToUpper(strings.TrimSpace(transaction.Currency))
+	transaction.Place, transaction.City = strings.TrimSpace(transaction.Place), strings.TrimSpace(transaction.City)
+	if !transaction.IsTransfer() {
+		s.Categorizer.categorize(ctx, transaction)
+	}
+	if err := s.fileUncategorized(ctx, transaction); err != nil {
+		return err
+	}
+	if err := s.validate(ctx, transaction); err != nil {
+		return err
+	}
+	if err := s.FX.convert(ctx, transaction); err != nil {
+		return err
+	}
+	if err := s.Caps.check(ctx, transaction); err != nil {
+		return err
+	}
+	s.assignMerchant(ctx, transaction)
+	return nil
+}
+
+// fileUncategorized files a transaction without a category under Uncategorized
+func (s *TransactionService) fileUncategorized(ctx context.Context, transaction *domain.Transaction) error {
+	if transaction.CategoryID != 0 || s.DB == nil {
+		return nil
+	}
+	uncategorized, err := uncategorizedCategory(ctx, s.DB)
+	if err != nil {
+		return err
+	}
+	transaction.CategoryID = uncategorized.ID
+	return nil
+}
+
+// assignMerchant assigns the merchant of the transaction's description,
+// which transfers between the user's own accounts do not have
+func (s *TransactionService) assignMerchant(ctx context.Context, transaction *domain.Transaction) {
+	if transaction.IsTransfer() {
+		transaction.MerchantID = nil
+		return
+	}
+	s.Merchants.assign(ctx, transaction)
+}
+
+// insert saves a prepared transaction with its tags and items as one unit
+// of work and announces it once committed
+func (s *TransactionService) insert(ctx context.Context, transaction *domain.Transaction) error {
+	err := persistence.InTransaction(ctx, s.DB, func(ctx context.Context) error {
+		if err := s.repository().Create(ctx, transaction); err != nil {
+			return err
+		}
+		if err := s.saveTags(ctx, transaction); err != nil {
+			return err
+		}
+		return s.saveItems(ctx, transaction)
+	})
+	if err != nil {
+		return err
+	}
+	s.publish(ctx, domain.TransactionCreated{Transaction: *transaction})
+	return nil
 }
 
 // List returns all transactions for a user
-func (s *TransactionService) List(userID uint) ([]domain.Transaction, error) {
-	var transactions []domain.Transaction
-	err := s.DB.Preload("Category").Where("user_id = ?", userID).Order("date DESC").Find(&transactions).Error
-	return transactions, err
+func (s *TransactionService) List(ctx context.Context, userID uint) ([]domain.Transaction, error) {
+	return s.repository().Find(ctx, domain.TransactionFilter{UserID: userID})
 }
 
 // ListWithFilters returns transactions with filtering options
 func (s *TransactionService) ListWithFilters(
-	userID uint, transactionType *string, categoryID *uint,
+	ctx context.Context, userID uint, transactionType *string, categoryID *uint,
 	startDate, endDate *time.Time, limit, offset int,
 ) ([]domain.Transaction, error) {
-	var transactions []domain.Transaction
-	query := s.DB.Preload("Category").Where("user_id = ?", userID)
+	filter := domain.TransactionFilter{
+		UserID:     userID,
+		CategoryID: categoryID,
+		StartDate:  startDate,
+		EndDate:    endDate,
+		Limit:      limit,
+		Offset:     offset,
+	}
+	if transactionType != nil {
+		filter.Type = *transactionType
+	}
+	return s.repository().Find(ctx, filter)
+}
 
-	// Apply filters
-	if transactionType != nil && *transactionType != "" {
-		query = query.Where("type = ?", *transactionType)
+// ListPage returns one page of the matching transactions, newest first, with
+// the total count and cursors for the neighbouring pages. With filter.Cursor
+// set it pages by keyset; otherwise it uses Limit and Offset.
+func (s *TransactionService) ListPage(ctx context.Context, filter domain.TransactionFilter) (*domain.TransactionPage, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultPageSize
+	}
+	if filter.Cursor != nil {
+		filter.Offset = 0
 	}
 
-	if categoryID != nil {
-		query = query.Where("category_id = ?", *categoryID)
+	// Fetch one extra row to find out whether a page follows in the paging direction
+	query := filter
+	query.Limit = filter.Limit + 1
+	transactions, err := s.repository().Find(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	total, err := s.repository().Count(ctx, filter)
+	if err != nil {
+		return nil, err
 	}
 
-	if startDate != nil {
-		query = query.Where("date >= ?", *startDate)
+	backwards := filter.Cursor != nil && filter.Cursor.Before
+	more := len(transactions) > filter.Limit
+	if more && backwards {
+		transactions = transactions[1:]
+	} else if more {
+		transactions = transactions[:filter.Limit]
 	}
 
-	if endDate != nil {
-		query = query.Where("date <= ?", *endDate)
+	page := &domain.TransactionPage{
+		Transactions: transactions,
+		Total:        total,
+		Limit:        filter.Limit,
+		Offset:       filter.Offset,
+	}
+	if len(transactions) == 0 {
+		return page, nil
 	}
 
-	// Apply pagination and ordering
-	err := query.Order("date DESC").Limit(limit).Offset(offset).Find(&transactions).Error
-	return transactions, err
+	hasNext := more || backwards
+	hasPrev := (more && backwards) || (!backwards && (filter.Cursor != nil || filter.Offset > 0))
+	if hasNext {
+		last := transactions[len(transactions)-1]
+		page.NextCursor = domain.TransactionCursor{Date: last.Date, ID: last.ID}.Encode()
+	}
+	if hasPrev {
+		first := transactions[0]
+		page.PrevCursor = domain.TransactionCursor{Date: first.Date, ID: first.ID, Before: true}.Encode()
+	}
+	return page, nil
 }
 
-// GetByID returns a transaction by ID
-func (s *TransactionService) GetByID(id uint) (*domain.Transaction, error) {
-	var transaction domain.Transaction
-	err := s.DB.Preload("Category").First(&transaction, id).Error
+// GetByID returns a transaction by ID with its line items
+func (s *TransactionService) GetByID(ctx context.Context, id uint) (*domain.Transaction, error) {
+	transaction, err := s.repository().GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	return &transaction, nil
+	single := []domain.Transaction{*transaction}
+	if err := loadTransactionItems(ctx, s.DB, single); err != nil {
+		return nil, err
+	}
+	transaction.Items = single[0].Items
+	return transaction, nil
 }
 
-// Update updates an existing transaction
-func (s *TransactionService) Update(transaction *domain.Transaction) error {
-	return s.DB.Save(transaction).Error
+// Update updates an existing transaction. Its tags and items are only
+// replaced when Tags and Items are not nil, in the same unit of work.
+func (s *TransactionService) Update(ctx context.Context, transaction *domain.Transaction) error {
+	transaction.Tags = domain.NormalizeTags(transaction.Tags)
+	transaction.Items = domain.NormalizeItems(transaction.Items)
+	transaction.Currency = strings.ToUpper(strings.TrimSpace(transaction.Currency))
+	transaction.Place, transaction.City = strings.TrimSpace(transaction.Place), strings.TrimSpace(transaction.City)
+	if transaction.IsTransfer() {
+		if err := s.fileUncategorized(ctx, transaction); err != nil {
+			return err
+		}
+	}
+	if err := s.validate(ctx, transaction); err != nil {
+		return err
+	}
+	if err := s.FX.convert(ctx, transaction); err != nil {
+		return err
+	}
+
+	var before *domain.Transaction
+	if s.subscribed() {
+		before, _ = s.repository().GetByID(ctx, transaction.ID)
+	}
+
+	s.assignMerchant(ctx, transaction)
+	err := persistence.InTransaction(ctx, s.DB, func(ctx context.Context) error {
+		if err := s.repository().Update(ctx, transaction); err != nil {
+			return err
+		}
+		if err := s.saveTags(ctx, transaction); err != nil {
+			return err
+		}
+		return s.saveItems(ctx, transaction)
+	})
+	if err != nil {
+		return err
+	}
+	// Both versions are published: a new amount, date or category can move
+	// the transaction between budgets
+	s.publish(ctx, domain.TransactionUpdated{Before: before, After: *transaction})
+	return nil
+}
+
+// Delete moves a transaction to the trash; it can be restored until it is purged
+func (s *TransactionService) Delete(ctx context.Context, id uint) error {
+	var before *domain.Transaction
+	if s.subscribed() {
+		before, _ = s.repository().GetByID(ctx, id)
+	}
+
+	if err := s.repository().Delete(ctx, id); err != nil {
+		return err
+	}
+	if before != nil {
+		s.publish(ctx, domain.TransactionDeleted{Transaction: *before})
+	}
+	return nil
+}
+
+// ListDeleted returns the user's trash, most recently deleted first
+func (s *TransactionService) ListDeleted(ctx context.Context, userID uint) ([]domain.Transaction, error) {
+	return s.repository().FindDeleted(ctx, domain.TransactionFilter{UserID: userID})
+}
+
+// Restore takes a transaction out of the user's trash and returns it
+func (s *TransactionService) Restore(ctx context.Context, userID, id uint) (*domain.Transaction, error) {
+	if err := s.repository().Restore(ctx, userID, id); err != nil {
+		return nil, err
+	}
+
+	transaction, err := s.repository().GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.publish(ctx, domain.TransactionRestored{Transaction: *transaction})
+	return transaction, nil
+}
+
+// Purge permanently deletes a single transaction from the user's trash
+func (s *TransactionService) Purge(ctx context.Context, userID, id uint) error {
+	if err := s.repository().Purge(ctx, userID, id); err != nil {
+		return err
+	}
+	s.dropOrphanDetails(ctx)
+	s.Audit.track(ctx, userID, domain.AuditEntityTransaction, id, domain.AuditActionPurge, nil, nil)
+	return nil
 }
 
-// Delete deletes a transaction
-func (s *TransactionService) Delete(id uint) error {
-	return s.DB.Delete(&domain.Transaction{}, id).Error
+// EmptyTrash permanently deletes everything in the user's trash
+func (s *TransactionService) EmptyTrash(ctx context.Context, userID uint) (int64, error) {
+	purged, err := s.repository().PurgeDeletedBefore(ctx, userID, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	if purged > 0 {
+		s.dropOrphanDetails(ctx)
+		s.Audit.track(ctx, userID, domain.AuditEntityTransaction, 0, domain.AuditActionPurge, nil, map[string]int64{"purged": purged})
+	}
+	return purged, nil
+}
+
+// PurgeExpired permanently deletes transactions of all users that have been
+// in the trash for longer than retention
+func (s *TransactionService) PurgeExpired(ctx context.Context, retention time.Duration) (int64, error) {
+	purged, err := s.repository().PurgeDeletedBefore(ctx, 0, time.Now().Add(-retention))
+	if purged > 0 {
+		s.dropOrphanDetails(ctx)
+	}
+	return purged, err
+}
+
+// StartTrashPurge runs PurgeExpired on the given interval until ctx is cancelled
+func (s *TransactionService) StartTrashPurge(ctx context.Context, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if purged, err := s.PurgeExpired(ctx, retention); err != nil {
+			log.Printf("trash purge failed: %v", err)
+		} else if purged > 0 {
+			log.Printf("purged %d transaction(s) from the trash", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
 }
 
 // GetTransactionsByDateRange returns transactions within a date range
 func (s *TransactionService) GetTransactionsByDateRange(
-	userID uint, startDate, endDate time.Time,
+	ctx context.Context, userID uint, startDate, endDate time.Time,
 ) ([]domain.Transaction, error) {
-	var transactions []domain.Transaction
-	err := s.DB.Preload("Category").
-		Where("user_id = ? AND date >= ? AND date <= ?", userID, startDate, endDate).
-		Order("date DESC").Find(&transactions).Error
-	return transactions, err
+	return s.repository().Find(ctx, domain.TransactionFilter{
+		UserID: userID, StartDate: &startDate, EndDate: &endDate,
+	})
 }
 
 // GetTransactionsByCategory returns transactions for a specific category
 func (s *TransactionService) GetTransactionsByCategory(
-	userID, categoryID uint, startDate, endDate time.Time,
+	ctx context.Context, userID, categoryID uint, startDate, endDate time.Time,
 ) ([]domain.Transaction, error) {
-	var transactions []domain.Transaction
-	err := s.DB.Preload("Category").
-		Where("user_id = ? AND category_id = ? AND date >= ? AND date <= ?",
-			userID, categoryID, startDate, endDate).
-		Order("date DESC").Find(&transactions).Error
-	return transactions, err
+	return s.repository().Find(ctx, domain.TransactionFilter{
+		UserID: userID, CategoryID: &categoryID, StartDate: &startDate, EndDate: &endDate,
+	})
 }
 
 // GetTransactionsByType returns transactions by type (income/expense)
 func (s *TransactionService) GetTransactionsByType(
-	userID uint, transactionType string, startDate, endDate time.Time,
+	ctx context.Context, userID uint, transactionType string, startDate, endDate time.Time,
 ) ([]domain.Transaction, error) {
-	var transactions []domain.Transaction
-	err := s.DB.Preload("Category").
-		Where("user_id = ? AND type = ? AND date >= ? AND date <= ?",
-			userID, transactionType, startDate, endDate).
-		Order("date DESC").
-		Find(&transactions).Error
-	return transactions, err
+	return s.repository().Find(ctx, domain.TransactionFilter{
+		UserID: userID, Type: transactionType, StartDate: &startDate, EndDate: &endDate,
+	})
 }
 
 // GetTotalByType returns the total amount for a transaction type within a date range
 func (s *TransactionService) GetTotalByType(
-	userID uint, transactionType string, startDate, endDate time.Time,
-) (float64, error) {
-	var total float64
-	err := s.DB.Model(&domain.Transaction{}).
-		Where("user_id = ? AND type = ? AND date >= ? AND date <= ?",
-			userID, transactionType, startDate, endDate).
-		Select("COALESCE(SUM(amount), 0)").
-		Scan(&total).Error
-	return total, err
+	ctx context.Context, userID uint, transactionType string, startDate, endDate time.Time,
+) (domain.Money, error) {
+	return s.repository().Sum(ctx, domain.TransactionFilter{
+		UserID: userID, Type: transactionType, StartDate: &startDate, EndDate: &endDate,
+	})
 }
 
 // GetTotalByCategory returns the total amount for a category within a date range
 func (s *TransactionService) GetTotalByCategory(
-	userID, categoryID uint, startDate, endDate time.Time,
-) (float64, error) {
-	var total float64
-	err := s.DB.Model(&domain.Transaction{}).
-		Where("user_id = ? AND category_id = ? AND date >= ? AND date <= ?",
-			userID, categoryID, startDate, endDate).
-		Select("COALESCE(SUM(amount), 0)").
-		Scan(&total).Error
-	return total, err
+	ctx context.Context, userID, categoryID uint, startDate, endDate time.Time,
+) (domain.Money, error) {
+	return s.repository().Sum(ctx, domain.TransactionFilter{
+		UserID: userID, CategoryID: &categoryID, StartDate: &startDate, EndDate: &endDate,
+	})
 }
 
 // GetCategoryTotals returns total amounts grouped by category
 func (s *TransactionService) GetCategoryTotals(
-	userID uint, transactionType string, startDate, endDate time.Time,
-) (map[uint]float64, error) {
-	type CategoryTotal struct {
-		CategoryID uint
-		Total      float64
-	}
-
-	var results []CategoryTotal
-	err := s.DB.Model(&domain.Transaction{}).
-		Select("category_id, COALESCE(SUM(amount), 0) as total").
-		Where("user_id = ? AND type = ? AND date >= ? AND date <= ?", userID, transactionType, startDate, endDate).
-		Group("category_id").
-		Scan(&results).Error
-
-	if err != nil {
-		return nil, err
-	}
-
-	totals := make(map[uint]float64)
-	for _, result := range results {
-		totals[result.CategoryID] = result.Total
-	}
-
-	return totals, nil
+	ctx context.Context, userID uint, transactionType string, startDate, endDate time.Time,
+) (map[uint]domain.Money, error) {
+	return s.repository().SumByCategory(ctx, domain.TransactionFilter{
+		UserID: userID, Type: transactionType, StartDate: &startDate, EndDate: &endDate,
+	})
 }
 
 // GetMonthlyTotals returns monthly totals for a transaction type
 func (s *TransactionService) GetMonthlyTotals(
-	userID uint, transactionType string, startDate, endDate time.Time,
-) (map[string]float64, error) {
-	type MonthlyTotal struct {
-		Month string
-		Total float64
-	}
-
-	var results []MonthlyTotal
-	err := s.DB.Model(&domain.Transaction{}).
-		Select("strftime('%Y-%m', date) as month, COALESCE(SUM(amount), 0) as total").
-		Where("user_id = ? AND type = ? AND date >= ? AND date <= ?", userID, transactionType, startDate, endDate).
-		Group("strftime('%Y-%m', date)").
-		Order("month").
-		Scan(&results).Error
-
-	if err != nil {
-		return nil, err
-	}
-
-	totals := make(map[string]float64)
-	for _, result := range results {
-		totals[result.Month] = result.Total
-	}
-
-	return totals, nil
+	ctx context.Context, userID uint, transactionType string, startDate, endDate time.Time,
+) (map[string]domain.Money, error) {
+	return s.repository().SumByMonth(ctx, domain.TransactionFilter{
+		UserID: userID, Type: transactionType, StartDate: &startDate, EndDate: &endDate,
+	})
 }
 
 // GetDailyAverages calculates daily averages for income and expenses
-func (s *TransactionService) GetDailyAverages(userID uint, startDate, endDate time.Time) (map[string]float64, error) {
+func (s *TransactionService) GetDailyAverages(ctx context.Context, userID uint, startDate, endDate time.Time) (map[string]float64, error) {
 	days := int(endDate.Sub(startDate).Hours()/24) + 1
 	if days <= 0 {
 		days = 1
 	}
 
-	incomeTotal, err := s.GetTotalByType(userID, "income", startDate, endDate)
+	incomeTotal, err := s.GetTotalByType(ctx, userID, "income", startDate, endDate)
 	if err != nil {
 		return nil, err
 	}
 
-	expenseTotal, err := s.GetTotalByType(userID, "expense", startDate, endDate)
+	expenseTotal, err := s.GetTotalByType(ctx, userID, "expense", startDate, endDate)
 	if err != nil {
 		return nil, err
 	}
 
 	averages := map[string]float64{
-		"daily_income":  incomeTotal / float64(days),
-		"daily_expense": expenseTotal / float64(days),
-		"daily_net":     (incomeTotal - expenseTotal) / float64(days),
+		"daily_income":  incomeTotal.Float64() / float64(days),
+		"daily_expense": expenseTotal.Float64() / float64(days),
+		"daily_net":     (incomeTotal - expenseTotal).Float64() / float64(days),
 	}
 
 	return averages, nil
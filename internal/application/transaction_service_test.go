@@ -1,10 +1,12 @@
 package application
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/persistence"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -19,7 +21,7 @@ func setupTransactionTestDB(t *testing.T) *gorm.DB {
 	})
 	require.NoError(t, err)
 
-	err = db.AutoMigrate(&domain.User{}, &domain.Category{}, &domain.Transaction{})
+	err = db.AutoMigrate(&domain.User{}, &domain.Category{}, &domain.Transaction{}, &domain.TransactionItem{})
 	require.NoError(t, err)
 
 	return db
@@ -62,13 +64,13 @@ func TestTransactionService_Create(t *testing.T) {
 		transaction := &domain.Transaction{
 			UserID:      userID,
 			CategoryID:  categoryID,
-			Amount:      100.50,
+			Amount:      domain.NewMoney(100.50),
 			Description: "Test transaction",
 			Type:        "income",
 			Date:        time.Now(),
 		}
 
-		err := txService.Create(transaction)
+		err := txService.Create(context.Background(), transaction)
 		assert.NoError(t, err)
 		assert.NotZero(t, transaction.ID)
 	})
@@ -77,14 +79,14 @@ func TestTransactionService_Create(t *testing.T) {
 		transaction := &domain.Transaction{
 			UserID:      99999, // Non-existent user
 			CategoryID:  categoryID,
-			Amount:      100.50,
+			Amount:      domain.NewMoney(100.50),
 			Description: "Test transaction",
 			Type:        "income",
 			Date:        time.Now(),
 		}
 
 		// GORM doesn't enforce foreign key constraints by default in SQLite
-		err := txService.Create(transaction)
+		err := txService.Create(context.Background(), transaction)
 		assert.NoError(t, err) // Will succeed but with invalid reference
 	})
 }
@@ -92,22 +94,22 @@ func TestTransactionService_Create(t *testing.T) {
 func TestTransactionService_List(t *testing.T) {
 	db := setupTransactionTestDB(t)
 	txService := &TransactionService{DB: db}
-	userID, categoryID, _ := createTestData(t, db)
+	userID, categoryID, expenseCategoryID := createTestData(t, db)
 
 	// Create test transactions
 	transactions := []*domain.Transaction{
 		{
 			UserID:      userID,
 			CategoryID:  categoryID,
-			Amount:      100.00,
+			Amount:      domain.NewMoney(100.00),
 			Description: "Transaction 1",
 			Type:        "income",
 			Date:        time.Now().AddDate(0, 0, -2),
 		},
 		{
 			UserID:      userID,
-			CategoryID:  categoryID,
-			Amount:      50.00,
+			CategoryID:  expenseCategoryID,
+			Amount:      domain.NewMoney(50.00),
 			Description: "Transaction 2",
 			Type:        "expense",
 			Date:        time.Now().AddDate(0, 0, -1),
@@ -115,12 +117,12 @@ func TestTransactionService_List(t *testing.T) {
 	}
 
 	for _, tx := range transactions {
-		err := txService.Create(tx)
+		err := txService.Create(context.Background(), tx)
 		require.NoError(t, err)
 	}
 
 	t.Run("list user transactions", func(t *testing.T) {
-		result, err := txService.List(userID)
+		result, err := txService.List(context.Background(), userID)
 		assert.NoError(t, err)
 		assert.Len(t, result, 2)
 		// Should be ordered by date DESC
@@ -129,7 +131,7 @@ func TestTransactionService_List(t *testing.T) {
 	})
 
 	t.Run("list for non-existent user", func(t *testing.T) {
-		result, err := txService.List(99999)
+		result, err := txService.List(context.Background(), 99999)
 		assert.NoError(t, err)
 		assert.Len(t, result, 0)
 	})
@@ -146,7 +148,7 @@ func TestTransactionService_ListWithFilters(t *testing.T) {
 		{
 			UserID:      userID,
 			CategoryID:  incomeCategoryID,
-			Amount:      1000.00,
+			Amount:      domain.NewMoney(1000.00),
 			Description: "Salary",
 			Type:        "income",
 			Date:        now.AddDate(0, 0, -5),
@@ -154,7 +156,7 @@ func TestTransactionService_ListWithFilters(t *testing.T) {
 		{
 			UserID:      userID,
 			CategoryID:  expenseCategoryID,
-			Amount:      50.00,
+			Amount:      domain.NewMoney(50.00),
 			Description: "Lunch",
 			Type:        "expense",
 			Date:        now.AddDate(0, 0, -3),
@@ -162,7 +164,7 @@ func TestTransactionService_ListWithFilters(t *testing.T) {
 		{
 			UserID:      userID,
 			CategoryID:  expenseCategoryID,
-			Amount:      30.00,
+			Amount:      domain.NewMoney(30.00),
 			Description: "Coffee",
 			Type:        "expense",
 			Date:        now.AddDate(0, 0, -1),
@@ -170,13 +172,13 @@ func TestTransactionService_ListWithFilters(t *testing.T) {
 	}
 
 	for _, tx := range transactions {
-		err := txService.Create(tx)
+		err := txService.Create(context.Background(), tx)
 		require.NoError(t, err)
 	}
 
 	t.Run("filter by type", func(t *testing.T) {
 		txType := "expense"
-		result, err := txService.ListWithFilters(userID, &txType, nil, nil, nil, 10, 0)
+		result, err := txService.ListWithFilters(context.Background(), userID, &txType, nil, nil, nil, 10, 0)
 		assert.NoError(t, err)
 		assert.Len(t, result, 2)
 		for _, tx := range result {
@@ -185,7 +187,7 @@ func TestTransactionService_ListWithFilters(t *testing.T) {
 	})
 
 	t.Run("filter by category", func(t *testing.T) {
-		result, err := txService.ListWithFilters(userID, nil, &incomeCategoryID, nil, nil, 10, 0)
+		result, err := txService.ListWithFilters(context.Background(), userID, nil, &incomeCategoryID, nil, nil, 10, 0)
 		assert.NoError(t, err)
 		assert.Len(t, result, 1)
 		assert.Equal(t, "Salary", result[0].Description)
@@ -194,18 +196,18 @@ func TestTransactionService_ListWithFilters(t *testing.T) {
 	t.Run("filter by date range", func(t *testing.T) {
 		startDate := now.AddDate(0, 0, -4)
 		endDate := now.AddDate(0, 0, -2)
-		result, err := txService.ListWithFilters(userID, nil, nil, &startDate, &endDate, 10, 0)
+		result, err := txService.ListWithFilters(context.Background(), userID, nil, nil, &startDate, &endDate, 10, 0)
 		assert.NoError(t, err)
 		assert.Len(t, result, 1)
 		assert.Equal(t, "Lunch", result[0].Description)
 	})
 
 	t.Run("pagination", func(t *testing.T) {
-		result, err := txService.ListWithFilters(userID, nil, nil, nil, nil, 2, 0)
+		result, err := txService.ListWithFilters(context.Background(), userID, nil, nil, nil, nil, 2, 0)
 		assert.NoError(t, err)
 		assert.Len(t, result, 2)
 
-		result, err = txService.ListWithFilters(userID, nil, nil, nil, nil, 2, 2)
+		result, err = txService.ListWithFilters(context.Background(), userID, nil, nil, nil, nil, 2, 2)
 		assert.NoError(t, err)
 		assert.Len(t, result, 1)
 	})
@@ -220,16 +222,16 @@ func TestTransactionService_GetByID(t *testing.T) {
 	transaction := &domain.Transaction{
 		UserID:      userID,
 		CategoryID:  categoryID,
-		Amount:      100.00,
+		Amount:      domain.NewMoney(100.00),
 		Description: "Test transaction",
 		Type:        "income",
 		Date:        time.Now(),
 	}
-	err := txService.Create(transaction)
+	err := txService.Create(context.Background(), transaction)
 	require.NoError(t, err)
 
 	t.Run("get existing transaction", func(t *testing.T) {
-		result, err := txService.GetByID(transaction.ID)
+		result, err := txService.GetByID(context.Background(), transaction.ID)
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
 		assert.Equal(t, transaction.ID, result.ID)
@@ -238,7 +240,7 @@ func TestTransactionService_GetByID(t *testing.T) {
 	})
 
 	t.Run("get non-existent transaction", func(t *testing.T) {
-		result, err := txService.GetByID(99999)
+		result, err := txService.GetByID(context.Background(), 99999)
 		assert.Error(t, err)
 		assert.Nil(t, result)
 	})
@@ -253,25 +255,25 @@ func TestTransactionService_Update(t *testing.T) {
 	transaction := &domain.Transaction{
 		UserID:      userID,
 		CategoryID:  categoryID,
-		Amount:      100.00,
+		Amount:      domain.NewMoney(100.00),
 		Description: "Original description",
 		Type:        "income",
 		Date:        time.Now(),
 	}
-	err := txService.Create(transaction)
+	err := txService.Create(context.Background(), transaction)
 	require.NoError(t, err)
 
 	t.Run("update transaction", func(t *testing.T) {
-		transaction.Amount = 150.00
+		transaction.Amount = domain.NewMoney(150.00)
 		transaction.Description = "Updated description"
 
-		err := txService.Update(transaction)
+		err := txService.Update(context.Background(), transaction)
 		assert.NoError(t, err)
 
 		// Verify update
-		updated, err := txService.GetByID(transaction.ID)
+		updated, err := txService.GetByID(context.Background(), transaction.ID)
 		assert.NoError(t, err)
-		assert.Equal(t, 150.00, updated.Amount)
+		assert.Equal(t, domain.NewMoney(150.00), updated.Amount)
 		assert.Equal(t, "Updated description", updated.Description)
 	})
 }
@@ -285,25 +287,25 @@ func TestTransactionService_Delete(t *testing.T) {
 	transaction := &domain.Transaction{
 		UserID:      userID,
 		CategoryID:  categoryID,
-		Amount:      100.00,
+		Amount:      domain.NewMoney(100.00),
 		Description: "To be deleted",
 		Type:        "income",
 		Date:        time.Now(),
 	}
-	err := txService.Create(transaction)
+	err := txService.Create(context.Background(), transaction)
 	require.NoError(t, err)
 
 	t.Run("delete existing transaction", func(t *testing.T) {
-		err := txService.Delete(transaction.ID)
+		err := txService.Delete(context.Background(), transaction.ID)
 		assert.NoError(t, err)
 
 		// Verify deletion
-		_, err = txService.GetByID(transaction.ID)
+		_, err = txService.GetByID(context.Background(), transaction.ID)
 		assert.Error(t, err)
 	})
 
 	t.Run("delete non-existent transaction", func(t *testing.T) {
-		err := txService.Delete(99999)
+		err := txService.Delete(context.Background(), 99999)
 		assert.NoError(t, err) // GORM doesn't return error for non-existent records
 	})
 }
@@ -311,7 +313,7 @@ func TestTransactionService_Delete(t *testing.T) {
 func TestTransactionService_GetTotalByType(t *testing.T) {
 	db := setupTransactionTestDB(t)
 	txService := &TransactionService{DB: db}
-	userID, categoryID, _ := createTestData(t, db)
+	userID, categoryID, expenseCategoryID := createTestData(t, db)
 
 	now := time.Now()
 	startDate := now.AddDate(0, 0, -7)
@@ -320,58 +322,61 @@ func TestTransactionService_GetTotalByType(t *testing.T) {
 	// Create test transactions
 	transactions := []*domain.Transaction{
 		{
-			UserID:     userID,
-			CategoryID: categoryID,
-			Amount:     100.00,
-			Type:       "income",
-			Date:       now.AddDate(0, 0, -3),
+			UserID:      userID,
+			CategoryID:  categoryID,
+			Description: "Test transaction",
+			Amount:      domain.NewMoney(100.00),
+			Type:        "income",
+			Date:        now.AddDate(0, 0, -3),
 		},
 		{
-			UserID:     userID,
-			CategoryID: categoryID,
-			Amount:     200.00,
-			Type:       "income",
-			Date:       now.AddDate(0, 0, -2),
+			UserID:      userID,
+			CategoryID:  categoryID,
+			Description: "Test transaction",
+			Amount:      domain.NewMoney(200.00),
+			Type:        "income",
+			Date:        now.AddDate(0, 0, -2),
 		},
 		{
-			UserID:     userID,
-			CategoryID: categoryID,
-			Amount:     50.00,
-			Type:       "expense",
-			Date:       now.AddDate(0, 0, -1),
+			UserID:      userID,
+			CategoryID:  expenseCategoryID,
+			Description: "Test transaction",
+			Amount:      domain.NewMoney(50.00),
+			Type:        "expense",
+			Date:        now.AddDate(0, 0, -1),
 		},
 	}
 
 	for _, tx := range transactions {
-		err := txService.Create(tx)
+		err := txService.Create(context.Background(), tx)
 		require.NoError(t, err)
 	}
 
 	t.Run("get income total", func(t *testing.T) {
-		total, err := txService.GetTotalByType(userID, "income", startDate, endDate)
+		total, err := txService.GetTotalByType(context.Background(), userID, "income", startDate, endDate)
 		assert.NoError(t, err)
-		assert.Equal(t, 300.00, total)
+		assert.Equal(t, domain.NewMoney(300.00), total)
 	})
 
 	t.Run("get expense total", func(t *testing.T) {
-		total, err := txService.GetTotalByType(userID, "expense", startDate, endDate)
+		total, err := txService.GetTotalByType(context.Background(), userID, "expense", startDate, endDate)
 		assert.NoError(t, err)
-		assert.Equal(t, 50.00, total)
+		assert.Equal(t, domain.NewMoney(50.00), total)
 	})
 
 	t.Run("no transactions in range", func(t *testing.T) {
 		futureStart := now.AddDate(0, 0, 1)
 		futureEnd := now.AddDate(0, 0, 7)
-		total, err := txService.GetTotalByType(userID, "income", futureStart, futureEnd)
+		total, err := txService.GetTotalByType(context.Background(), userID, "income", futureStart, futureEnd)
 		assert.NoError(t, err)
-		assert.Equal(t, 0.00, total)
+		assert.Equal(t, domain.NewMoney(0.00), total)
 	})
 }
 
 func TestTransactionService_GetDailyAverages(t *testing.T) {
 	db := setupTransactionTestDB(t)
 	txService := &TransactionService{DB: db}
-	userID, categoryID, _ := createTestData(t, db)
+	userID, categoryID, expenseCategoryID := createTestData(t, db)
 
 	now := time.Now()
 	startDate := now.AddDate(0, 0, -9) // 10 days period
@@ -380,28 +385,30 @@ func TestTransactionService_GetDailyAverages(t *testing.T) {
 	// Create test transactions
 	transactions := []*domain.Transaction{
 		{
-			UserID:     userID,
-			CategoryID: categoryID,
-			Amount:     1000.00, // Total income: 1000
-			Type:       "income",
-			Date:       now.AddDate(0, 0, -5),
+			UserID:      userID,
+			CategoryID:  categoryID,
+			Description: "Test transaction",
+			Amount:      domain.NewMoney(1000.00), // Total income: 1000
+			Type:        "income",
+			Date:        now.AddDate(0, 0, -5),
 		},
 		{
-			UserID:     userID,
-			CategoryID: categoryID,
-			Amount:     300.00, // Total expense: 300
-			Type:       "expense",
-			Date:       now.AddDate(0, 0, -3),
+			UserID:      userID,
+			CategoryID:  expenseCategoryID,
+			Description: "Test transaction",
+			Amount:      domain.NewMoney(300.00), // Total expense: 300
+			Type:        "expense",
+			Date:        now.AddDate(0, 0, -3),
 		},
 	}
 
 	for _, tx := range transactions {
-		err := txService.Create(tx)
+		err := txService.Create(context.Background(), tx)
 		require.NoError(t, err)
 	}
 
 	t.Run("calculate daily averages", func(t *testing.T) {
-		averages, err := txService.GetDailyAverages(userID, startDate, endDate)
+		averages, err := txService.GetDailyAverages(context.Background(), userID, startDate, endDate)
 		assert.NoError(t, err)
 		assert.Contains(t, averages, "daily_income")
 		assert.Contains(t, averages, "daily_expense")
@@ -415,9 +422,274 @@ func TestTransactionService_GetDailyAverages(t *testing.T) {
 
 	t.Run("same day range", func(t *testing.T) {
 		sameDay := now
-		averages, err := txService.GetDailyAverages(userID, sameDay, sameDay)
+		averages, err := txService.GetDailyAverages(context.Background(), userID, sameDay, sameDay)
 		assert.NoError(t, err)
 		// Should handle single day correctly
 		assert.NotNil(t, averages)
 	})
 }
+
+func TestTransactionService_WithMemoryRepository(t *testing.T) {
+	service := NewTransactionService(persistence.NewMemoryTransactionRepository())
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, -1)
+
+	for _, tx := range []*domain.Transaction{
+		{UserID: 1, CategoryID: 1, Type: "income", Description: "Salary", Amount: domain.NewMoney(2000), Date: start.AddDate(0, 0, 1)},
+		{UserID: 1, CategoryID: 2, Type: "expense", Description: "Rent", Amount: domain.NewMoney(300), Date: start.AddDate(0, 0, 5)},
+		{UserID: 1, CategoryID: 2, Type: "expense", Description: "Groceries", Amount: domain.NewMoney(100), Date: start.AddDate(0, 0, 9)},
+	} {
+		require.NoError(t, service.Create(context.Background(), tx))
+	}
+
+	expenseType := "expense"
+	expenses, err := service.ListWithFilters(context.Background(), 1, &expenseType, nil, &start, &end, 10, 0)
+	require.NoError(t, err)
+	assert.Len(t, expenses, 2)
+
+	totals, err := service.GetCategoryTotals(context.Background(), 1, "expense", start, end)
+	require.NoError(t, err)
+	assert.Equal(t, domain.NewMoney(400), totals[2])
+
+	averages, err := service.GetDailyAverages(context.Background(), 1, start, end)
+	require.NoError(t, err)
+	assert.InDelta(t, 1600.0/31, averages["daily_net"], 0.001)
+}
+
+func TestTransactionService_Trash(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	service := &TransactionService{DB: db}
+	ctx := context.Background()
+
+	first := &domain.Transaction{UserID: 1, CategoryID: 1, Type: "expense", Description: "Lunch", Amount: domain.NewMoney(40), Date: time.Now()}
+	second := &domain.Transaction{UserID: 1, CategoryID: 1, Type: "expense", Description: "Dinner", Amount: domain.NewMoney(60), Date: time.Now()}
+	require.NoError(t, service.Create(ctx, first))
+	require.NoError(t, service.Create(ctx, second))
+	require.NoError(t, service.Delete(ctx, first.ID))
+	require.NoError(t, service.Delete(ctx, second.ID))
+
+	trash, err := service.ListDeleted(ctx, 1)
+	require.NoError(t, err)
+	assert.Len(t, trash, 2)
+
+	restored, err := service.Restore(ctx, 1, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, restored.ID)
+
+	// Nothing has outlived a week-long retention yet
+	purged, err := service.PurgeExpired(ctx, 7*24*time.Hour)
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+
+	purged, err = service.EmptyTrash(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+
+	var remaining int64
+	require.NoError(t, db.Unscoped().Model(&domain.Transaction{}).Count(&remaining).Error)
+	assert.Equal(t, int64(1), remaining)
+}
+
+func TestTransactionService_ListPage(t *testing.T) {
+	service := NewTransactionService(persistence.NewMemoryTransactionRepository())
+	ctx := context.Background()
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	for day := 0; day < 5; day++ {
+		require.NoError(t, service.Create(ctx, &domain.Transaction{
+			UserID: 1, CategoryID: 1, Type: "expense", Description: "Coffee", Amount: domain.NewMoney(10), Date: start.AddDate(0, 0, day),
+		}))
+	}
+
+	first, err := service.ListPage(ctx, domain.TransactionFilter{UserID: 1, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), first.Total)
+	require.Len(t, first.Transactions, 2)
+	assert.Equal(t, uint(5), first.Transactions[0].ID)
+	assert.Empty(t, first.PrevCursor)
+	require.NotEmpty(t, first.NextCursor)
+
+	cursor, err := domain.DecodeTransactionCursor(first.NextCursor)
+	require.NoError(t, err)
+	second, err := service.ListPage(ctx, domain.TransactionFilter{UserID: 1, Limit: 2, Cursor: cursor})
+	require.NoError(t, err)
+	assert.Equal(t, uint(3), second.Transactions[0].ID)
+	require.NotEmpty(t, second.PrevCursor)
+	require.NotEmpty(t, second.NextCursor)
+
+	cursor, err = domain.DecodeTransactionCursor(second.NextCursor)
+	require.NoError(t, err)
+	last, err := service.ListPage(ctx, domain.TransactionFilter{UserID: 1, Limit: 2, Cursor: cursor})
+	require.NoError(t, err)
+	require.Len(t, last.Transactions, 1)
+	assert.Empty(t, last.NextCursor)
+
+	// Going back from the second page lands on the first again
+	cursor, err = domain.DecodeTransactionCursor(second.PrevCursor)
+	require.NoError(t, err)
+	back, err := service.ListPage(ctx, domain.TransactionFilter{UserID: 1, Limit: 2, Cursor: cursor})
+	require.NoError(t, err)
+	assert.Equal(t, first.Transactions, back.Transactions)
+	assert.Empty(t, back.PrevCursor)
+	assert.Equal(t, first.NextCursor, back.NextCursor)
+
+	offsetPage, err := service.ListPage(ctx, domain.TransactionFilter{UserID: 1, Limit: 2, Offset: 2})
+	require.NoError(t, err)
+	assert.Equal(t, second.Transactions, offsetPage.Transactions)
+	assert.NotEmpty(t, offsetPage.PrevCursor)
+}
+
+func TestTransactionService_ListPageIncluding(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.TransactionTag{}, &domain.Attachment{}, &domain.Comment{}))
+	service := &TransactionService{DB: db}
+	ctx := context.Background()
+	userID, incomeCategoryID, expenseCategoryID := createTestData(t, db)
+
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	salary := &domain.Transaction{UserID: userID, CategoryID: incomeCategoryID, Type: "income", Description: "Salary", Amount: domain.NewMoney(100), Date: start}
+	dinner := &domain.Transaction{UserID: userID, CategoryID: expenseCategoryID, Type: "expense", Description: "Dinner", Amount: domain.NewMoney(30), Date: start.AddDate(0, 0, 1),
+		Notes: "With the team", Tags: []string{"Work", "food", "work"}}
+	taxi := &domain.Transaction{UserID: userID, CategoryID: expenseCategoryID, Type: "expense", Description: "Taxi", Amount: domain.NewMoney(20), Date: start.AddDate(0, 0, 2)}
+	for _, transaction := range []*domain.Transaction{salary, dinner, taxi} {
+		require.NoError(t, service.Create(ctx, transaction))
+	}
+	require.NoError(t, db.Create(&domain.Attachment{UserID: userID, TransactionID: &taxi.ID, FileName: "taxi.pdf", StoragePath: "taxi.pdf"}).Error)
+
+	all := domain.TransactionIncludes{Attachments: true, Tags: true, RunningBalance: true}
+	page, err := service.ListPageIncluding(ctx, domain.TransactionFilter{UserID: userID, Limit: 2}, all)
+	require.NoError(t, err)
+	require.Len(t, page.Transactions, 2)
+	taxiRow, dinnerRow := page.Transactions[0], page.Transactions[1]
+	assert.Equal(t, domain.NewMoney(50), *taxiRow.RunningBalance)
+	assert.Equal(t, domain.NewMoney(70), *dinnerRow.RunningBalance, "earlier rows off the page count towards the balance")
+	assert.Equal(t, 1, *taxiRow.AttachmentCount)
+	assert.Equal(t, 0, *dinnerRow.AttachmentCount)
+	assert.Empty(t, taxiRow.Tags)
+	assert.Equal(t, []string{"food", "work"}, dinnerRow.Tags)
+	assert.Equal(t, "With the team", dinnerRow.Notes)
+
+	plain, err := service.ListPageIncluding(ctx, domain.TransactionFilter{UserID: userID, Limit: 2}, domain.TransactionIncludes{})
+	require.NoError(t, err)
+	assert.Nil(t, plain.Transactions[0].RunningBalance)
+	assert.Nil(t, plain.Transactions[1].Tags)
+
+	t.Run("should keep the tags unless updated with new ones", func(t *testing.T) {
+		dinner.Tags = nil
+		require.NoError(t, service.Update(ctx, dinner))
+		page, err := service.ListPageIncluding(ctx, domain.TransactionFilter{UserID: userID, Type: "expense"}, all)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"food", "work"}, page.Transactions[1].Tags)
+		assert.Equal(t, domain.NewMoney(-30), *page.Transactions[1].RunningBalance, "only matching rows count")
+
+		dinner.Tags = []string{}
+		require.NoError(t, service.Update(ctx, dinner))
+		page, err = service.ListPageIncluding(ctx, domain.TransactionFilter{UserID: userID, Type: "expense"}, all)
+		require.NoError(t, err)
+		assert.Empty(t, page.Transactions[1].Tags)
+	})
+
+	t.Run("should drop the tags of purged transactions", func(t *testing.T) {
+		taxi.Tags = []string{"travel"}
+		require.NoError(t, service.Update(ctx, taxi))
+		require.NoError(t, service.Delete(ctx, taxi.ID))
+		require.NoError(t, service.Purge(ctx, userID, taxi.ID))
+		var count int64
+		require.NoError(t, db.Model(&domain.TransactionTag{}).Count(&count).Error)
+		assert.Zero(t, count)
+	})
+}
+
+func TestTransactionService_Items(t *testing.T) {
+	db := setupTransactionTestDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.TransactionTag{}, &domain.Comment{}))
+	service := &TransactionService{DB: db}
+	ctx := context.Background()
+	userID, _, expenseCategoryID := createTestData(t, db)
+
+	breakfast := &domain.Transaction{UserID: userID, CategoryID: expenseCategoryID, Type: "expense", Description: "Cafe Luna",
+		Amount: domain.NewMoney(9.20), Date: time.Now(), Items: []domain.TransactionItem{
+			{Name: " Coffee ", Quantity: 2, UnitPrice: domain.NewMoney(3.50)},
+			{Name: "Croissant", UnitPrice: domain.NewMoney(2.20)},
+		}}
+	require.NoError(t, service.Create(ctx, breakfast))
+
+	stored, err := service.GetByID(ctx, breakfast.ID)
+	require.NoError(t, err)
+	require.Len(t, stored.Items, 2)
+	assert.Equal(t, "Coffee", stored.Items[0].Name)
+	assert.Equal(t, domain.NewMoney(7), stored.Items[0].Amount)
+	assert.Equal(t, 1.0, stored.Items[1].Quantity, "items without a quantity are bought once")
+	assert.Equal(t, 1, stored.Items[1].Position)
+
+	page, err := service.ListPageIncluding(ctx, domain.TransactionFilter{UserID: userID}, domain.TransactionIncludes{Items: true})
+	require.NoError(t, err)
+	assert.Len(t, page.Transactions[0].Items, 2)
+
+	t.Run("should reject broken items", func(t *testing.T) {
+		broken := *breakfast
+		broken.Items = []domain.TransactionItem{{Name: "", Quantity: -1, UnitPrice: domain.NewMoney(1)}}
+		assert.ErrorIs(t, service.Update(ctx, &broken), domain.ErrValidation)
+	})
+
+	t.Run("should keep the items unless updated with new ones", func(t *testing.T) {
+		stored.Items = nil
+		require.NoError(t, service.Update(ctx, stored))
+		kept, err := service.GetByID(ctx, breakfast.ID)
+		require.NoError(t, err)
+		assert.Len(t, kept.Items, 2)
+
+		kept.Items = []domain.TransactionItem{{Name: "Tea", UnitPrice: domain.NewMoney(2.50)}}
+		require.NoError(t, service.Update(ctx, kept))
+		replaced, err := service.GetByID(ctx, breakfast.ID)
+		require.NoError(t, err)
+		require.Len(t, replaced.Items, 1)
+		assert.Equal(t, "Tea", replaced.Items[0].Name)
+	})
+
+	t.Run("should drop the items of purged transactions", func(t *testing.T) {
+		require.NoError(t, service.Delete(ctx, breakfast.ID))
+		require.NoError(t, service.Purge(ctx, userID, breakfast.ID))
+		var count int64
+		require.NoError(t, db.Model(&domain.TransactionItem{}).Count(&count).Error)
+		assert.Zero(t, count)
+	})
+}
+
+func TestTransactionService_UnitOfWork(t *testing.T) {
+	// Without a tags table every write that saves tags fails halfway
+	db := setupTransactionTestDB(t)
+	events := NewEventBus()
+	var announced []string
+	events.SubscribeAll(func(_ context.Context, event domain.Event) {
+		announced = append(announced, event.EventType())
+	})
+	service := &TransactionService{DB: db, Events: events}
+	ctx := context.Background()
+	userID, _, expenseCategoryID := createTestData(t, db)
+
+	t.Run("should not keep a transaction whose tags failed to save", func(t *testing.T) {
+		transaction := &domain.Transaction{UserID: userID, CategoryID: expenseCategoryID, Type: "expense",
+			Description: "Market", Amount: domain.NewMoney(12), Date: time.Now(), Tags: []string{"food"}}
+		assert.Error(t, service.Create(ctx, transaction))
+
+		var count int64
+		require.NoError(t, db.Model(&domain.Transaction{}).Count(&count).Error)
+		assert.Zero(t, count)
+		assert.Empty(t, announced, "undone changes are not announced")
+	})
+
+	t.Run("should keep the previous version when the update fails", func(t *testing.T) {
+		transaction := &domain.Transaction{UserID: userID, CategoryID: expenseCategoryID, Type: "expense",
+			Description: "Market", Amount: domain.NewMoney(12), Date: time.Now()}
+		require.NoError(t, service.Create(ctx, transaction))
+		assert.Equal(t, []string{domain.EventTransactionCreated}, announced)
+
+		edited := *transaction
+		edited.Amount, edited.Tags = domain.NewMoney(20), []string{"food"}
+		assert.Error(t, service.Update(ctx, &edited))
+		stored, err := service.GetByID(ctx, transaction.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.NewMoney(12), stored.Amount)
+		assert.Len(t, announced, 1)
+	})
+}
@@ -0,0 +1,83 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/pkg"
+
+	"gorm.io/gorm"
+)
+
+// TransactionTextService reads transactions from free text such as "coffee
+// 4.50 yesterday" for quick entry. Text the built-in rules cannot read is
+// handed to the language model when one is configured.
+type TransactionTextService struct {
+	DB          *gorm.DB
+	Categorizer *CategorizerService // Suggests categories the text does not name when set
+	LLM         pkg.LLMProvider     // Reads what the rules cannot when set
+}
+
+func NewTransactionTextService(db *gorm.DB) *TransactionTextService {
+	return &TransactionTextService{DB: db}
+}
+
+// Parse reads a transaction of the user's from the text without creating it
+func (s *TransactionTextService) Parse(ctx context.Context, userID uint, text string) (*domain.ParsedTransaction, error) {
+	text = strings.TrimSpace(text)
+	if text == "" || utf8.RuneCountInString(text) > domain.MaxTransactionTextLength {
+		return nil, &domain.ValidationError{Fields: []domain.FieldError{{
+			Field: "text", Message: fmt.Sprintf("must be between 1 and %d characters", domain.MaxTransactionTextLength),
+		}}}
+	}
+
+	var categories []domain.Category
+	if err := s.DB.WithContext(ctx).Where("user_id IS NULL OR user_id = ?", userID).Order("name").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	parsed, ok := domain.ParseTransactionText(text, now)
+	if !ok {
+		if s.LLM == nil {
+			return nil, domain.ErrUnparseableTransaction
+		}
+		reply, err := s.LLM.Complete(ctx, domain.TransactionTextInstructions(now, categoryNames(categories)), text)
+		if err != nil {
+			log.Printf("transaction text: the LLM failed to read %q: %v", text, err)
+			return nil, domain.ErrUnparseableTransaction
+		}
+		if parsed, err = domain.ParseTransactionReply(reply, now); err != nil {
+			return nil, err
+		}
+	}
+
+	if parsed.Category == "" {
+		var names []string
+		for i := range categories {
+			if categories[i].Type == parsed.Type {
+				names = append(names, categories[i].Name)
+			}
+		}
+		parsed.Category = domain.MentionedCategory(text, names)
+	}
+	if category := namedCategory(categories, parsed.Category, parsed.Type); category != nil {
+		parsed.Category = category.Name
+		parsed.Suggestion = &domain.CategorySuggestion{CategoryID: category.ID, Confidence: 1, Source: domain.SuggestionSourceText}
+		return &parsed, nil
+	}
+	parsed.Category = ""
+	if s.Categorizer != nil {
+		suggestion, err := s.Categorizer.Suggest(ctx, userID, parsed.Description, parsed.Type)
+		if err != nil {
+			return nil, err
+		}
+		parsed.Suggestion = suggestion
+	}
+	return &parsed, nil
+}
@@ -0,0 +1,87 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionTextService_Parse(t *testing.T) {
+	db := setupCategorizerTestDB(t)
+	categories := categorizerHistory(t, db, 1)
+	service := NewTransactionTextService(db)
+	ctx := context.Background()
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	t.Run("the rules read the text", func(t *testing.T) {
+		parsed, err := service.Parse(ctx, 1, "  coffee 4.50 yesterday ")
+		require.NoError(t, err)
+		assert.Equal(t, domain.NewMoney(4.5), parsed.Amount)
+		assert.Equal(t, domain.TransactionTypeExpense, parsed.Type)
+		assert.Equal(t, "Coffee", parsed.Description)
+		assert.Equal(t, today.AddDate(0, 0, -1), parsed.Date)
+		assert.Equal(t, domain.TransactionTextSourceRules, parsed.Source)
+		assert.Nil(t, parsed.Suggestion, "no category is named and there are no suggestions")
+	})
+
+	t.Run("categories named in the text are used", func(t *testing.T) {
+		parsed, err := service.Parse(ctx, 1, "groceries 54.20")
+		require.NoError(t, err)
+		assert.Equal(t, "Groceries", parsed.Category)
+		assert.Equal(t, &domain.CategorySuggestion{
+			CategoryID: categories["Groceries"], Confidence: 1, Source: domain.SuggestionSourceText,
+		}, parsed.Suggestion)
+
+		parsed, err = service.Parse(ctx, 1, "refund for groceries +15")
+		require.NoError(t, err)
+		assert.Nil(t, parsed.Suggestion, "categories of the other type are not used")
+	})
+
+	t.Run("the categorizer suggests the rest", func(t *testing.T) {
+		service := NewTransactionTextService(db)
+		service.Categorizer = NewCategorizerService(db, 0.6, 10)
+		parsed, err := service.Parse(ctx, 1, "SHELL OIL 9000 45.00")
+		require.NoError(t, err)
+		require.NotNil(t, parsed.Suggestion)
+		assert.Equal(t, categories["Fuel"], parsed.Suggestion.CategoryID)
+		assert.Equal(t, domain.SuggestionSourceRules, parsed.Suggestion.Source)
+	})
+
+	t.Run("text the rules cannot read", func(t *testing.T) {
+		_, err := service.Parse(ctx, 1, "four fifty for coffee")
+		assert.ErrorIs(t, err, domain.ErrUnparseableTransaction, "without a model")
+
+		llm := &fakeLLM{reply: `{"amount": 4.5, "type": "expense", "description": "Coffee", "category": "groceries"}`}
+		service := NewTransactionTextService(db)
+		service.LLM = llm
+		parsed, err := service.Parse(ctx, 1, "four fifty for coffee")
+		require.NoError(t, err)
+		assert.Equal(t, domain.NewMoney(4.5), parsed.Amount)
+		assert.Equal(t, today, parsed.Date)
+		assert.Equal(t, domain.TransactionTextSourceLLM, parsed.Source)
+		assert.Equal(t, "Groceries", parsed.Category)
+		require.NotNil(t, parsed.Suggestion)
+		assert.Equal(t, categories["Groceries"], parsed.Suggestion.CategoryID)
+		assert.Contains(t, llm.system, "Fuel, Groceries, Other Expenses, Salary")
+
+		llm.reply = `{"amount": null}`
+		_, err = service.Parse(ctx, 1, "hello there")
+		assert.ErrorIs(t, err, domain.ErrUnparseableTransaction)
+
+		llm.err = errors.New("connection refused")
+		_, err = service.Parse(ctx, 1, "four fifty for coffee")
+		assert.ErrorIs(t, err, domain.ErrUnparseableTransaction)
+	})
+
+	t.Run("empty text", func(t *testing.T) {
+		_, err := service.Parse(ctx, 1, "  ")
+		assert.ErrorIs(t, err, domain.ErrValidation)
+	})
+}
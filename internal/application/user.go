@@ -3,6 +3,7 @@
 package application
 
 import (
+	"context"
 	"errors"
 
 	"go-finance-advisor/internal/domain"
@@ -12,14 +13,15 @@ import (
 )
 
 type UserService struct {
-	DB *gorm.DB
+	DB    *gorm.DB
+	Audit *AuditService // Records modifications when set
 }
 
 // Register creates a new user with email and password
-func (s *UserService) Register(email, password, firstName, lastName string) (*domain.User, error) {
+func (s *UserService) Register(ctx context.Context, email, password, firstName, lastName string) (*domain.User, error) {
 	// Check if user already exists
 	var existingUser domain.User
-	if err := s.DB.Where("email = ?", email).First(&existingUser).Error; err == nil {
+	if err := s.DB.WithContext(ctx).Where("email = ?", email).First(&existingUser).Error; err == nil {
 		return nil, errors.New("user already exists")
 	}
 
@@ -38,17 +40,18 @@ func (s *UserService) Register(email, password, firstName, lastName string) (*do
 		RiskTolerance: "moderate", // default
 	}
 
-	if err := s.DB.Create(user).Error; err != nil {
+	if err := s.DB.WithContext(ctx).Create(user).Error; err != nil {
 		return nil, err
 	}
+	s.Audit.track(ctx, user.ID, domain.AuditEntityUser, user.ID, domain.AuditActionCreate, nil, user)
 
 	return user, nil
 }
 
 // Login authenticates user with email and password
-func (s *UserService) Login(email, password string) (*domain.User, error) {
+func (s *UserService) Login(ctx context.Context, email, password string) (*domain.User, error) {
 	var user domain.User
-	if err := s.DB.Where("email = ?", email).First(&user).Error; err != nil {
+	if err := s.DB.WithContext(ctx).Where("email = ?", email).First(&user).Error; err != nil {
 		return nil, errors.New("invalid credentials")
 	}
 
@@ -61,24 +64,104 @@ func (s *UserService) Login(email, password string) (*domain.User, error) {
 }
 
 // GetByEmail finds user by email
-func (s *UserService) GetByEmail(email string) (*domain.User, error) {
+func (s *UserService) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
 	var user domain.User
-	if err := s.DB.Where("email = ?", email).First(&user).Error; err != nil {
+	if err := s.DB.WithContext(ctx).Where("email = ?", email).First(&user).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (s *UserService) Create(u *domain.User) error {
-	return s.DB.Create(u).Error
+func (s *UserService) Create(ctx context.Context, u *domain.User) error {
+	if err := s.DB.WithContext(ctx).Create(u).Error; err != nil {
+		return err
+	}
+	s.Audit.track(ctx, u.ID, domain.AuditEntityUser, u.ID, domain.AuditActionCreate, nil, u)
+	return nil
 }
 
-func (s *UserService) GetByID(id uint) (domain.User, error) {
+func (s *UserService) GetByID(ctx context.Context, id uint) (domain.User, error) {
 	var u domain.User
-	err := s.DB.First(&u, id).Error
+	err := s.DB.WithContext(ctx).First(&u, id).Error
 	return u, err
 }
 
-func (s *UserService) Update(u *domain.User) error {
-	return s.DB.Save(u).Error
+func (s *UserService) Update(ctx context.Context, u *domain.User) error {
+	var before *domain.User
+	if s.Audit != nil {
+		if existing, err := s.GetByID(ctx, u.ID); err == nil {
+			before = &existing
+		}
+	}
+
+	if err := s.DB.WithContext(ctx).Save(u).Error; err != nil {
+		return err
+	}
+	s.Audit.track(ctx, u.ID, domain.AuditEntityUser, u.ID, domain.AuditActionUpdate, before, u)
+	return nil
+}
+
+// UpdateProfile replaces the user's profile after checking it
+func (s *UserService) UpdateProfile(ctx context.Context, userID uint, profile domain.UserProfile) (domain.User, error) {
+	if err := profile.Validate(); err != nil {
+		return domain.User{}, err
+	}
+
+	user, err := s.GetByID(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.User{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	user.UserProfile = profile
+	if err := s.Update(ctx, &user); err != nil {
+		return domain.User{}, err
+	}
+	return user, nil
+}
+
+// UpdateLocale sets the language of the user's reports and exports and of
+// the console after login
+func (s *UserService) UpdateLocale(ctx context.Context, userID uint, locale string) (domain.User, error) {
+	if err := domain.ValidateLocale(locale); err != nil {
+		return domain.User{}, err
+	}
+
+	user, err := s.GetByID(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.User{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	user.Locale = locale
+	if err := s.Update(ctx, &user); err != nil {
+		return domain.User{}, err
+	}
+	return user, nil
+}
+
+// UpdateAccountType makes the user's account a personal or an advisor one.
+// Access clients granted stays, but only advisor accounts can use it.
+func (s *UserService) UpdateAccountType(ctx context.Context, userID uint, accountType string) (domain.User, error) {
+	if err := domain.ValidateAccountType(accountType); err != nil {
+		return domain.User{}, err
+	}
+
+	user, err := s.GetByID(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.User{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	user.AccountType = accountType
+	if err := s.Update(ctx, &user); err != nil {
+		return domain.User{}, err
+	}
+	return user, nil
 }
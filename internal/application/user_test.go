@@ -1,7 +1,9 @@
 package application
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"go-finance-advisor/internal/domain"
 
@@ -30,7 +32,7 @@ func TestUserService_Register(t *testing.T) {
 	userService := &UserService{DB: db}
 
 	t.Run("successful registration", func(t *testing.T) {
-		user, err := userService.Register("test@example.com", "password123", "John", "Doe")
+		user, err := userService.Register(context.Background(), "test@example.com", "password123", "John", "Doe")
 
 		assert.NoError(t, err)
 		assert.NotNil(t, user)
@@ -47,11 +49,11 @@ func TestUserService_Register(t *testing.T) {
 
 	t.Run("duplicate email registration", func(t *testing.T) {
 		// First registration
-		_, err := userService.Register("duplicate@example.com", "password123", "Jane", "Doe")
+		_, err := userService.Register(context.Background(), "duplicate@example.com", "password123", "Jane", "Doe")
 		assert.NoError(t, err)
 
 		// Second registration with same email
-		user, err := userService.Register("duplicate@example.com", "password456", "John", "Smith")
+		user, err := userService.Register(context.Background(), "duplicate@example.com", "password456", "John", "Smith")
 
 		assert.Error(t, err)
 		assert.Nil(t, user)
@@ -59,7 +61,7 @@ func TestUserService_Register(t *testing.T) {
 	})
 
 	t.Run("empty fields", func(t *testing.T) {
-		user, err := userService.Register("", "", "", "")
+		user, err := userService.Register(context.Background(), "", "", "", "")
 
 		// Should still create user but with empty fields
 		assert.NoError(t, err)
@@ -75,11 +77,11 @@ func TestUserService_Login(t *testing.T) {
 	// Create a test user
 	testEmail := "login@example.com"
 	testPassword := "testpassword"
-	_, err := userService.Register(testEmail, testPassword, "Test", "User")
+	_, err := userService.Register(context.Background(), testEmail, testPassword, "Test", "User")
 	require.NoError(t, err)
 
 	t.Run("successful login", func(t *testing.T) {
-		user, err := userService.Login(testEmail, testPassword)
+		user, err := userService.Login(context.Background(), testEmail, testPassword)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, user)
@@ -89,7 +91,7 @@ func TestUserService_Login(t *testing.T) {
 	})
 
 	t.Run("invalid email", func(t *testing.T) {
-		user, err := userService.Login("nonexistent@example.com", testPassword)
+		user, err := userService.Login(context.Background(), "nonexistent@example.com", testPassword)
 
 		assert.Error(t, err)
 		assert.Nil(t, user)
@@ -97,7 +99,7 @@ func TestUserService_Login(t *testing.T) {
 	})
 
 	t.Run("invalid password", func(t *testing.T) {
-		user, err := userService.Login(testEmail, "wrongpassword")
+		user, err := userService.Login(context.Background(), testEmail, "wrongpassword")
 
 		assert.Error(t, err)
 		assert.Nil(t, user)
@@ -105,7 +107,7 @@ func TestUserService_Login(t *testing.T) {
 	})
 
 	t.Run("empty credentials", func(t *testing.T) {
-		user, err := userService.Login("", "")
+		user, err := userService.Login(context.Background(), "", "")
 
 		assert.Error(t, err)
 		assert.Nil(t, user)
@@ -119,11 +121,11 @@ func TestUserService_GetByEmail(t *testing.T) {
 
 	// Create a test user
 	testEmail := "getbyemail@example.com"
-	createdUser, err := userService.Register(testEmail, "password", "Get", "ByEmail")
+	createdUser, err := userService.Register(context.Background(), testEmail, "password", "Get", "ByEmail")
 	require.NoError(t, err)
 
 	t.Run("existing user", func(t *testing.T) {
-		user, err := userService.GetByEmail(testEmail)
+		user, err := userService.GetByEmail(context.Background(), testEmail)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, user)
@@ -134,14 +136,14 @@ func TestUserService_GetByEmail(t *testing.T) {
 	})
 
 	t.Run("non-existing user", func(t *testing.T) {
-		user, err := userService.GetByEmail("nonexistent@example.com")
+		user, err := userService.GetByEmail(context.Background(), "nonexistent@example.com")
 
 		assert.Error(t, err)
 		assert.Nil(t, user)
 	})
 
 	t.Run("empty email", func(t *testing.T) {
-		user, err := userService.GetByEmail("")
+		user, err := userService.GetByEmail(context.Background(), "")
 
 		assert.Error(t, err)
 		assert.Nil(t, user)
@@ -153,11 +155,11 @@ func TestUserService_GetByID(t *testing.T) {
 	userService := &UserService{DB: db}
 
 	// Create a test user
-	createdUser, err := userService.Register("getbyid@example.com", "password", "Get", "ByID")
+	createdUser, err := userService.Register(context.Background(), "getbyid@example.com", "password", "Get", "ByID")
 	require.NoError(t, err)
 
 	t.Run("existing user", func(t *testing.T) {
-		user, err := userService.GetByID(createdUser.ID)
+		user, err := userService.GetByID(context.Background(), createdUser.ID)
 
 		assert.NoError(t, err)
 		assert.Equal(t, createdUser.ID, user.ID)
@@ -167,7 +169,7 @@ func TestUserService_GetByID(t *testing.T) {
 	})
 
 	t.Run("non-existing user", func(t *testing.T) {
-		user, err := userService.GetByID(99999)
+		user, err := userService.GetByID(context.Background(), 99999)
 
 		assert.Error(t, err)
 		assert.Equal(t, uint(0), user.ID)
@@ -187,13 +189,13 @@ func TestUserService_Create(t *testing.T) {
 			RiskTolerance: "aggressive",
 		}
 
-		err := userService.Create(user)
+		err := userService.Create(context.Background(), user)
 
 		assert.NoError(t, err)
 		assert.NotZero(t, user.ID)
 
 		// Verify user was created
-		retrievedUser, err := userService.GetByEmail("create@example.com")
+		retrievedUser, err := userService.GetByEmail(context.Background(), "create@example.com")
 		assert.NoError(t, err)
 		assert.Equal(t, user.ID, retrievedUser.ID)
 	})
@@ -206,7 +208,7 @@ func TestUserService_Create(t *testing.T) {
 			LastName:  "One",
 		}
 
-		err := userService.Create(user1)
+		err := userService.Create(context.Background(), user1)
 		assert.NoError(t, err)
 
 		user2 := &domain.User{
@@ -216,7 +218,7 @@ func TestUserService_Create(t *testing.T) {
 			LastName:  "Two",
 		}
 
-		err = userService.Create(user2)
+		err = userService.Create(context.Background(), user2)
 		assert.Error(t, err) // Should fail due to unique constraint
 	})
 }
@@ -226,7 +228,7 @@ func TestUserService_Update(t *testing.T) {
 	userService := &UserService{DB: db}
 
 	// Create a test user
-	user, err := userService.Register("update@example.com", "password", "Update", "Test")
+	user, err := userService.Register(context.Background(), "update@example.com", "password", "Update", "Test")
 	require.NoError(t, err)
 
 	t.Run("update user fields", func(t *testing.T) {
@@ -234,11 +236,11 @@ func TestUserService_Update(t *testing.T) {
 		user.LastName = "Name"
 		user.RiskTolerance = "conservative"
 
-		err := userService.Update(user)
+		err := userService.Update(context.Background(), user)
 		assert.NoError(t, err)
 
 		// Verify update
-		updatedUser, err := userService.GetByID(user.ID)
+		updatedUser, err := userService.GetByID(context.Background(), user.ID)
 		assert.NoError(t, err)
 		assert.Equal(t, "Updated", updatedUser.FirstName)
 		assert.Equal(t, "Name", updatedUser.LastName)
@@ -253,8 +255,68 @@ func TestUserService_Update(t *testing.T) {
 			LastName:  "Existent",
 		}
 
-		err := userService.Update(nonExistentUser)
+		err := userService.Update(context.Background(), nonExistentUser)
 		// GORM will create a new record if ID doesn't exist
 		assert.NoError(t, err)
 	})
 }
+
+func TestUserService_UpdateProfile(t *testing.T) {
+	db := setupUserTestDB(t)
+	userService := &UserService{DB: db}
+	ctx := context.Background()
+
+	user, err := userService.Register(ctx, "profile@example.com", "password", "Profile", "Test")
+	require.NoError(t, err)
+
+	t.Run("stores the profile", func(t *testing.T) {
+		birthDate := time.Date(1985, 9, 30, 0, 0, 0, 0, time.UTC)
+		profile := domain.UserProfile{
+			BirthDate: &birthDate, EmploymentStatus: domain.EmploymentSelfEmployed,
+			Dependents: 1, MonthlyIncome: domain.NewMoney(7250.50),
+		}
+
+		updated, err := userService.UpdateProfile(ctx, user.ID, profile)
+		require.NoError(t, err)
+		assert.Equal(t, "Profile", updated.FirstName)
+
+		stored, err := userService.GetByID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.EmploymentSelfEmployed, stored.EmploymentStatus)
+		assert.Equal(t, 1, stored.Dependents)
+		assert.Equal(t, domain.NewMoney(7250.50), stored.MonthlyIncome)
+		require.NotNil(t, stored.BirthDate)
+		assert.True(t, birthDate.Equal(*stored.BirthDate))
+	})
+
+	t.Run("rejects an invalid profile", func(t *testing.T) {
+		_, err := userService.UpdateProfile(ctx, user.ID, domain.UserProfile{EmploymentStatus: "astronaut"})
+		assert.ErrorIs(t, err, domain.ErrValidation)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		_, err := userService.UpdateProfile(ctx, 99999, domain.UserProfile{})
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
+
+func TestUserService_UpdateLocale(t *testing.T) {
+	db := setupUserTestDB(t)
+	userService := &UserService{DB: db}
+	ctx := context.Background()
+
+	user, err := userService.Register(ctx, "locale@example.com", "password", "Locale", "Test")
+	require.NoError(t, err)
+
+	updated, err := userService.UpdateLocale(ctx, user.ID, domain.LocaleTurkish)
+	require.NoError(t, err)
+	assert.Equal(t, domain.LocaleTurkish, updated.Locale)
+	stored, err := userService.GetByID(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.LocaleTurkish, stored.Locale)
+
+	_, err = userService.UpdateLocale(ctx, user.ID, "klingon")
+	assert.ErrorIs(t, err, domain.ErrValidation)
+	_, err = userService.UpdateLocale(ctx, 99999, domain.LocaleGerman)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
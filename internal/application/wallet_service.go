@@ -0,0 +1,283 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/pkg"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// JobTypeWalletRefresh is the job reading one tracked wallet from its chain
+const JobTypeWalletRefresh = "wallet_refresh"
+
+// walletRefreshJob is the payload of wallet refresh jobs
+type walletRefreshJob struct {
+	WalletID uint `json:"wallet_id"`
+}
+
+// WalletService tracks public BTC and ETH addresses. Balances and
+// transactions are read from public blockchain APIs and valued at current
+// market prices.
+type WalletService struct {
+	DB        *gorm.DB
+	Providers map[string]pkg.WalletProvider // The blockchain APIs by chain
+	Prices    PriceSource                   // Values wallets when set
+	Jobs      *JobService                   // Reads new wallets and runs scheduled refreshes in the background when set
+}
+
+func NewWalletService(db *gorm.DB, providers map[string]pkg.WalletProvider, prices PriceSource) *WalletService {
+	return &WalletService{DB: db, Providers: providers, Prices: prices}
+}
+
+// Add starts tracking an address for the user and reads it from its chain,
+// in the background when there is a job queue. A failed first read is kept
+// in the wallet's LastError and retried on schedule.
+func (s *WalletService) Add(ctx context.Context, userID uint, wallet domain.Wallet) (*domain.Wallet, error) {
+	wallet.Normalize()
+	if err := wallet.Validate(); err != nil {
+		return nil, err
+	}
+
+	var existing int64
+	err := s.DB.WithContext(ctx).Model(&domain.Wallet{}).
+		Where("user_id = ? AND chain = ? AND address = ?", userID, wallet.Chain, wallet.Address).Count(&existing).Error
+	if err != nil {
+		return nil, err
+	}
+	if existing > 0 {
+		return nil, domain.ErrWalletExists
+	}
+
+	added := domain.Wallet{UserID: userID, Chain: wallet.Chain, Address: wallet.Address, Label: wallet.Label}
+	if err := s.DB.WithContext(ctx).Create(&added).Error; err != nil {
+		return nil, err
+	}
+	s.firstRefresh(ctx, &added)
+	wallets := []domain.Wallet{added}
+	s.value(ctx, wallets)
+	return &wallets[0], nil
+}
+
+// firstRefresh reads a new wallet, queueing the read when there is a job queue
+func (s *WalletService) firstRefresh(ctx context.Context, wallet *domain.Wallet) {
+	if s.Jobs != nil {
+		_, err := s.Jobs.Enqueue(ctx, JobTypeWalletRefresh, walletRefreshJob{WalletID: wallet.ID})
+		if err == nil {
+			return
+		}
+		log.Printf("wallet %d: queueing the first refresh failed, refreshing now: %v", wallet.ID, err)
+	}
+	if err := s.sync(ctx, wallet); err != nil {
+		log.Printf("wallet %d: first refresh failed: %v", wallet.ID, err)
+	}
+}
+
+// List returns the user's wallets valued at current prices
+func (s *WalletService) List(ctx context.Context, userID uint) ([]domain.Wallet, error) {
+	var wallets []domain.Wallet
+	if err := s.DB.WithContext(ctx).Where("user_id = ?", userID).Order("chain, id").Find(&wallets).Error; err != nil {
+		return nil, err
+	}
+	s.value(ctx, wallets)
+	return wallets, nil
+}
+
+// Get returns one of the user's wallets valued at its current price
+func (s *WalletService) Get(ctx context.Context, userID, id uint) (*domain.Wallet, error) {
+	wallet, err := s.wallet(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	wallets := []domain.Wallet{*wallet}
+	s.value(ctx, wallets)
+	return &wallets[0], nil
+}
+
+func (s *WalletService) wallet(ctx context.Context, userID, id uint) (*domain.Wallet, error) {
+	var wallet domain.Wallet
+	err := s.DB.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&wallet).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &wallet, nil
+}
+
+// Transactions returns the transactions read for one of the user's wallets,
+// unconfirmed ones first and then newest first
+func (s *WalletService) Transactions(ctx context.Context, userID, id uint) ([]domain.WalletTransaction, error) {
+	if _, err := s.wallet(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	var transactions []domain.WalletTransaction
+	err := s.DB.WithContext(ctx).Where("wallet_id = ?", id).Order("confirmed, time DESC, id DESC").Find(&transactions).Error
+	return transactions, err
+}
+
+// Refresh reads one of the user's wallets from its chain now. A failed read
+// is kept in the wallet's LastError as well as returned.
+func (s *WalletService) Refresh(ctx context.Context, userID, id uint) (*domain.Wallet, error) {
+	wallet, err := s.wallet(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.sync(ctx, wallet); err != nil {
+		return nil, err
+	}
+	wallets := []domain.Wallet{*wallet}
+	s.value(ctx, wallets)
+	return &wallets[0], nil
+}
+
+// Remove stops tracking one of the user's wallets and forgets its transactions
+func (s *WalletService) Remove(ctx context.Context, userID, id uint) error {
+	wallet, err := s.wallet(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("wallet_id = ?", wallet.ID).Delete(&domain.WalletTransaction{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(wallet).Error
+	})
+}
+
+// RunRefreshJob is the JobHandler of JobTypeWalletRefresh. Wallets removed
+// since the job was queued are skipped.
+func (s *WalletService) RunRefreshJob(ctx context.Context, payload json.RawMessage) error {
+	var job walletRefreshJob
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return err
+	}
+	var wallet domain.Wallet
+	err := s.DB.WithContext(ctx).First(&wallet, job.WalletID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return s.sync(ctx, &wallet)
+}
+
+// RefreshAll reads every tracked wallet from its chain, as jobs when Jobs is
+// set. It returns how many wallets were refreshed or queued.
+func (s *WalletService) RefreshAll(ctx context.Context) (int, error) {
+	var wallets []domain.Wallet
+	if err := s.DB.WithContext(ctx).Order("id").Find(&wallets).Error; err != nil {
+		return 0, err
+	}
+
+	refreshed := 0
+	for i := range wallets {
+		var err error
+		if s.Jobs != nil {
+			_, err = s.Jobs.Enqueue(ctx, JobTypeWalletRefresh, walletRefreshJob{WalletID: wallets[i].ID})
+		} else {
+			err = s.sync(ctx, &wallets[i])
+		}
+		if err != nil {
+			log.Printf("wallet %d: refresh failed: %v", wallets[i].ID, err)
+			continue
+		}
+		refreshed++
+	}
+	return refreshed, nil
+}
+
+// StartRefreshing runs RefreshAll on the given interval until ctx is cancelled
+func (s *WalletService) StartRefreshing(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.RefreshAll(ctx); err != nil {
+			log.Printf("wallet refresh failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sync reads the wallet's balance and latest transactions from its chain
+// and records them, or the error when the read fails. Transactions read
+// before are updated, as they confirm.
+func (s *WalletService) sync(ctx context.Context, wallet *domain.Wallet) error {
+	snapshot, err := s.snapshot(ctx, wallet)
+	if err != nil {
+		wallet.LastError = err.Error()
+		if saveErr := s.DB.WithContext(ctx).Model(wallet).Update("last_error", wallet.LastError).Error; saveErr != nil {
+			log.Printf("wallet %d: failed to record the refresh error: %v", wallet.ID, saveErr)
+		}
+		return err
+	}
+
+	now := time.Now()
+	wallet.Balance, wallet.LastSyncedAt, wallet.LastError = snapshot.Balance, &now, ""
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(snapshot.Transactions) > 0 {
+			for i := range snapshot.Transactions {
+				snapshot.Transactions[i].ID = 0
+				snapshot.Transactions[i].WalletID = wallet.ID
+			}
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "wallet_id"}, {Name: "hash"}},
+				DoUpdates: clause.AssignmentColumns([]string{"amount", "fee", "confirmed", "time"}),
+			}).Create(&snapshot.Transactions).Error
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Model(wallet).Select("balance", "last_synced_at", "last_error").Updates(wallet).Error
+	})
+}
+
+func (s *WalletService) snapshot(ctx context.Context, wallet *domain.Wallet) (*domain.WalletSnapshot, error) {
+	provider, ok := s.Providers[wallet.Chain]
+	if !ok {
+		return nil, fmt.Errorf("no blockchain API configured for %s", wallet.Chain)
+	}
+	return provider.Snapshot(ctx, wallet.Address)
+}
+
+// value fills in the price and value of the wallets. Prices that cannot be
+// fetched leave the wallets unvalued rather than failing.
+func (s *WalletService) value(ctx context.Context, wallets []domain.Wallet) {
+	if s.Prices == nil || len(wallets) == 0 {
+		return
+	}
+	symbols := make([]string, 0, len(domain.WalletChains))
+	seen := map[string]bool{}
+	for i := range wallets {
+		if symbol := wallets[i].Symbol(); !seen[symbol] {
+			seen[symbol] = true
+			symbols = append(symbols, symbol)
+		}
+	}
+	prices, err := s.Prices.GetPrices(ctx, symbols)
+	if err != nil {
+		log.Printf("wallets: failed to fetch prices: %v", err)
+		return
+	}
+	for i := range wallets {
+		if price, ok := prices[wallets[i].Symbol()]; ok {
+			value := wallets[i].Balance * price
+			wallets[i].Price, wallets[i].Value = &price, &value
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/pkg"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const (
+	testBTCAddress = "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa"
+	testETHAddress = "0xde0b295669a9fd93d5f28d9ec85e40f4cb697bae"
+)
+
+// stubWalletProvider reports the snapshot set for each address
+type stubWalletProvider struct {
+	snapshots map[string]*domain.WalletSnapshot
+	err       error
+	calls     int
+}
+
+func (p *stubWalletProvider) Snapshot(_ context.Context, address string) (*domain.WalletSnapshot, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	snapshot, ok := p.snapshots[address]
+	if !ok {
+		return &domain.WalletSnapshot{}, nil
+	}
+	copied := *snapshot
+	copied.Transactions = append([]domain.WalletTransaction(nil), snapshot.Transactions...)
+	return &copied, nil
+}
+
+func setupWalletTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&domain.Wallet{}, &domain.WalletTransaction{}, &domain.Job{}))
+	return db
+}
+
+func TestWalletService_AddAndList(t *testing.T) {
+	db := setupWalletTestDB(t)
+	confirmed := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	btc := &stubWalletProvider{snapshots: map[string]*domain.WalletSnapshot{
+		testBTCAddress: {Balance: 0.5, Transactions: []domain.WalletTransaction{
+			{Hash: "pending", Amount: 0.1},
+			{Hash: "received", Amount: 0.5, Confirmed: true, Time: &confirmed},
+		}},
+	}}
+	eth := &stubWalletProvider{}
+	prices := &stubPriceSource{prices: map[string]float64{"BTC": 60000}}
+	service := NewWalletService(db, map[string]pkg.WalletProvider{domain.WalletChainBTC: btc, domain.WalletChainETH: eth}, prices)
+	ctx := context.Background()
+
+	wallet, err := service.Add(ctx, 1, domain.Wallet{Chain: "btc", Address: " " + testBTCAddress + " ", Label: "Cold storage"})
+	require.NoError(t, err)
+	assert.Equal(t, domain.WalletChainBTC, wallet.Chain)
+	assert.Equal(t, 0.5, wallet.Balance, "a new wallet is read right away without a job queue")
+	assert.NotNil(t, wallet.LastSyncedAt)
+	require.NotNil(t, wallet.Value)
+	assert.InDelta(t, 30000, *wallet.Value, 1e-9)
+
+	_, err = service.Add(ctx, 1, domain.Wallet{Chain: "BTC", Address: testBTCAddress})
+	assert.ErrorIs(t, err, domain.ErrWalletExists)
+	_, err = service.Add(ctx, 1, domain.Wallet{Chain: "ETH", Address: "0x123"})
+	assert.ErrorIs(t, err, domain.ErrValidation)
+
+	eth.err = errors.New("blockscout returned status 502")
+	ethWallet, err := service.Add(ctx, 1, domain.Wallet{Chain: "ETH", Address: testETHAddress})
+	require.NoError(t, err, "a failed first read does not fail adding the wallet")
+	assert.Contains(t, ethWallet.LastError, "status 502")
+	assert.Nil(t, ethWallet.Value, "ETH has no price")
+
+	wallets, err := service.List(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, wallets, 2)
+	assert.Equal(t, "BTC", wallets[0].Chain)
+	assert.Contains(t, wallets[1].LastError, "status 502")
+	other, err := service.List(ctx, 2)
+	require.NoError(t, err)
+	assert.Empty(t, other)
+
+	transactions, err := service.Transactions(ctx, 1, wallet.ID)
+	require.NoError(t, err)
+	require.Len(t, transactions, 2)
+	assert.Equal(t, "pending", transactions[0].Hash, "unconfirmed transactions come first")
+	_, err = service.Transactions(ctx, 2, wallet.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	eth.err = nil
+	refreshed, err := service.Refresh(ctx, 1, ethWallet.ID)
+	require.NoError(t, err)
+	assert.Empty(t, refreshed.LastError, "a successful read clears the error")
+
+	require.NoError(t, service.Remove(ctx, 1, wallet.ID))
+	assert.ErrorIs(t, service.Remove(ctx, 1, wallet.ID), domain.ErrNotFound)
+	var left int64
+	require.NoError(t, db.Model(&domain.WalletTransaction{}).Count(&left).Error)
+	assert.Zero(t, left, "removing a wallet forgets its transactions")
+}
+
+func TestWalletService_RefreshAll(t *testing.T) {
+	db := setupWalletTestDB(t)
+	btc := &stubWalletProvider{snapshots: map[string]*domain.WalletSnapshot{
+		testBTCAddress: {Balance: 1, Transactions: []domain.WalletTransaction{{Hash: "tx", Amount: 1}}},
+	}}
+	service := NewWalletService(db, map[string]pkg.WalletProvider{domain.WalletChainBTC: btc}, nil)
+	service.Jobs = NewJobService(db)
+	service.Jobs.Register(JobTypeWalletRefresh, service.RunRefreshJob)
+	ctx := context.Background()
+
+	wallet, err := service.Add(ctx, 1, domain.Wallet{Chain: "BTC", Address: testBTCAddress})
+	require.NoError(t, err)
+	assert.Zero(t, btc.calls, "the first read is queued")
+	ran, err := service.Jobs.RunNext(ctx)
+	require.NoError(t, err)
+	assert.True(t, ran)
+
+	// The transaction confirms and the balance changes on the next refresh
+	confirmed := time.Now().UTC().Truncate(time.Second)
+	btc.snapshots[testBTCAddress] = &domain.WalletSnapshot{Balance: 0.75, Transactions: []domain.WalletTransaction{
+		{Hash: "tx", Amount: 1, Confirmed: true, Time: &confirmed},
+		{Hash: "spend", Amount: -0.25, Fee: 0.0001, Confirmed: true, Time: &confirmed},
+	}}
+	refreshed, err := service.RefreshAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, refreshed)
+	ran, err = service.Jobs.RunNext(ctx)
+	require.NoError(t, err)
+	assert.True(t, ran)
+
+	got, err := service.Get(ctx, 1, wallet.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 0.75, got.Balance)
+	transactions, err := service.Transactions(ctx, 1, wallet.ID)
+	require.NoError(t, err)
+	require.Len(t, transactions, 2)
+	for _, transaction := range transactions {
+		assert.True(t, transaction.Confirmed, transaction.Hash)
+	}
+
+	require.NoError(t, service.Remove(ctx, 1, wallet.ID))
+	assert.NoError(t, service.RunRefreshJob(ctx, []byte(`{"wallet_id":1}`)), "removed wallets are skipped")
+}
@@ -0,0 +1,128 @@
+package application
+
+import (
+	"context"
+	"errors"
+
+	"go-finance-advisor/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// Watchlist asset types
+const (
+	WatchlistAssetStock  = "stock"
+	WatchlistAssetCrypto = "crypto"
+)
+
+// ErrInvalidAssetType is returned when a watchlisted symbol is neither a stock nor a cryptocurrency
+var ErrInvalidAssetType = errors.New("asset_type must be stock or crypto")
+
+// WatchlistService manages the market symbols users follow
+type WatchlistService struct {
+	DB     *gorm.DB
+	Prices PriceSource // Prices the watchlist for Quotes
+}
+
+func NewWatchlistService(db *gorm.DB, prices PriceSource) *WatchlistService {
+	return &WatchlistService{DB: db, Prices: prices}
+}
+
+// List returns the user's watchlist in the order the symbols were saved
+func (s *WatchlistService) List(ctx context.Context, userID uint) ([]domain.WatchlistItem, error) {
+	var items []domain.WatchlistItem
+	err := s.DB.WithContext(ctx).Where("user_id = ?", userID).Order("created_at ASC, id ASC").Find(&items).Error
+	return items, err
+}
+
+// Add saves a symbol to the user's watchlist. Symbols are stored upper-cased
+// and default to stocks.
+func (s *WatchlistService) Add(ctx context.Context, userID uint, item domain.WatchlistItem) (*domain.WatchlistItem, error) {
+	symbol, err := domain.NormalizeSymbol(item.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	switch item.AssetType {
+	case "":
+		item.AssetType = WatchlistAssetStock
+	case WatchlistAssetStock, WatchlistAssetCrypto:
+	default:
+		return nil, ErrInvalidAssetType
+	}
+
+	var existing int64
+	err = s.DB.WithContext(ctx).Model(&domain.WatchlistItem{}).
+		Where("user_id = ? AND symbol = ?", userID, symbol).Count(&existing).Error
+	if err != nil {
+		return nil, err
+	}
+	if existing > 0 {
+		return nil, domain.ErrAlreadyWatchlisted
+	}
+
+	item.ID = 0
+	item.UserID = userID
+	item.Symbol = symbol
+	if err := s.DB.WithContext(ctx).Create(&item).Error; err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// Remove takes a symbol off the user's watchlist
+func (s *WatchlistService) Remove(ctx context.Context, userID uint, symbol string) error {
+	symbol, err := domain.NormalizeSymbol(symbol)
+	if err != nil {
+		return err
+	}
+	result := s.DB.WithContext(ctx).Where("user_id = ? AND symbol = ?", userID, symbol).Delete(&domain.WatchlistItem{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// Symbols returns the user's watchlisted symbols of one asset type, or of
+// every type when assetType is empty
+func (s *WatchlistService) Symbols(ctx context.Context, userID uint, assetType string) ([]string, error) {
+	query := s.DB.WithContext(ctx).Model(&domain.WatchlistItem{}).Where("user_id = ?", userID)
+	if assetType != "" {
+		query = query.Where("asset_type = ?", assetType)
+	}
+	var symbols []string
+	err := query.Order("created_at ASC, id ASC").Pluck("symbol", &symbols).Error
+	return symbols, err
+}
+
+// Quotes prices the user's watchlist
+func (s *WatchlistService) Quotes(ctx context.Context, userID uint) ([]domain.WatchlistQuote, error) {
+	items, err := s.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	quotes := make([]domain.WatchlistQuote, len(items))
+	symbols := make([]string, len(items))
+	for i := range items {
+		quotes[i].WatchlistItem = items[i]
+		symbols[i] = items[i].Symbol
+	}
+	if s.Prices == nil || len(symbols) == 0 {
+		return quotes, nil
+	}
+
+	prices, err := s.Prices.GetPrices(ctx, symbols)
+	if err != nil {
+		return nil, err
+	}
+	for i := range quotes {
+		if price, ok := prices[quotes[i].Symbol]; ok {
+			quotes[i].Price = price
+			quotes[i].Available = true
+		}
+	}
+	return quotes, nil
+}
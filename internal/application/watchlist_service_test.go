@@ -0,0 +1,69 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupWatchlistTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&domain.WatchlistItem{}))
+	return db
+}
+
+func TestWatchlistService(t *testing.T) {
+	db := setupWatchlistTestDB(t)
+	prices := &stubPriceSource{prices: map[string]float64{"AAPL": 190}}
+	service := NewWatchlistService(db, prices)
+	ctx := context.Background()
+
+	item, err := service.Add(ctx, 1, domain.WatchlistItem{Symbol: "aapl", Name: "Apple Inc"})
+	require.NoError(t, err)
+	assert.Equal(t, "AAPL", item.Symbol)
+	assert.Equal(t, WatchlistAssetStock, item.AssetType)
+	_, err = service.Add(ctx, 1, domain.WatchlistItem{Symbol: "BTC", AssetType: WatchlistAssetCrypto})
+	require.NoError(t, err)
+	_, err = service.Add(ctx, 2, domain.WatchlistItem{Symbol: "AAPL"})
+	require.NoError(t, err, "other users can watch the same symbol")
+
+	_, err = service.Add(ctx, 1, domain.WatchlistItem{Symbol: "AAPL"})
+	assert.ErrorIs(t, err, domain.ErrAlreadyWatchlisted)
+	_, err = service.Add(ctx, 1, domain.WatchlistItem{Symbol: "TSLA", AssetType: "bond"})
+	assert.ErrorIs(t, err, ErrInvalidAssetType)
+	_, err = service.Add(ctx, 1, domain.WatchlistItem{Symbol: "not a symbol"})
+	assert.ErrorIs(t, err, domain.ErrInvalidSymbol)
+
+	symbols, err := service.Symbols(ctx, 1, WatchlistAssetCrypto)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BTC"}, symbols)
+
+	quotes, err := service.Quotes(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, quotes, 2)
+	assert.True(t, quotes[0].Available)
+	assert.Equal(t, 190.0, quotes[0].Price)
+	assert.False(t, quotes[1].Available, "BTC has no price")
+
+	prices.err = errors.New("provider down")
+	_, err = service.Quotes(ctx, 1)
+	assert.Error(t, err)
+
+	require.NoError(t, service.Remove(ctx, 1, "aapl"))
+	assert.ErrorIs(t, service.Remove(ctx, 1, "AAPL"), domain.ErrNotFound)
+	items, err := service.List(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "BTC", items[0].Symbol)
+}
@@ -0,0 +1,196 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// JobTypeWebhookDelivery is the job posting one event to one webhook
+const JobTypeWebhookDelivery = "webhook_delivery"
+
+// webhookTimeout bounds each delivery
+const webhookTimeout = 10 * time.Second
+
+// webhookJob is the payload of webhook delivery jobs. The body is built when
+// the event is published, so retries post exactly the same bytes.
+type webhookJob struct {
+	WebhookID uint            `json:"webhook_id"`
+	Body      json.RawMessage `json:"body"`
+}
+
+// WebhookService keeps users' webhooks and posts the events of their data to them
+type WebhookService struct {
+	DB     *gorm.DB
+	Jobs   *JobService  // Delivers through the job queue, with retries, when set; otherwise each delivery is tried once
+	Client *http.Client // Falls back to webhookClient when nil
+}
+
+// webhookClient posts to webhooks on the public internet only
+var webhookClient = &http.Client{Transport: publicTransport, Timeout: webhookTimeout}
+
+func NewWebhookService(db *gorm.DB) *WebhookService {
+	return &WebhookService{DB: db}
+}
+
+func (s *WebhookService) client() *http.Client {
+	if s.Client != nil {
+		return s.Client
+	}
+	return webhookClient
+}
+
+// Create adds a webhook for the user and returns it with its new secret
+func (s *WebhookService) Create(ctx context.Context, userID uint, webhook domain.Webhook) (*domain.Webhook, error) {
+	if err := webhook.Validate(); err != nil {
+		return nil, err
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	webhook.ID, webhook.UserID, webhook.Secret, webhook.Active = 0, userID, hex.EncodeToString(secret), true
+	if webhook.Events == nil {
+		webhook.Events = []string{}
+	}
+	if err := s.DB.WithContext(ctx).Create(&webhook).Error; err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// List returns the user's webhooks without their secrets
+func (s *WebhookService) List(ctx context.Context, userID uint) ([]domain.Webhook, error) {
+	var webhooks []domain.Webhook
+	if err := s.DB.WithContext(ctx).Where("user_id = ?", userID).Order("id").Find(&webhooks).Error; err != nil {
+		return nil, err
+	}
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+	return webhooks, nil
+}
+
+// Delete removes one of the user's webhooks. Deliveries already queued for
+// it are dropped when they run.
+func (s *WebhookService) Delete(ctx context.Context, userID, id uint) error {
+	result := s.DB.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&domain.Webhook{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// Subscribe delivers every event published on the bus to the webhooks of its user
+func (s *WebhookService) Subscribe(bus *EventBus) {
+	bus.SubscribeAll(s.handleEvent)
+}
+
+func (s *WebhookService) handleEvent(ctx context.Context, event domain.Event) {
+	var webhooks []domain.Webhook
+	err := s.DB.WithContext(ctx).Where("user_id = ? AND active = ?", event.EventUserID(), true).Order("id").Find(&webhooks).Error
+	if err != nil {
+		log.Printf("webhooks: failed to look up webhooks of user %d: %v", event.EventUserID(), err)
+		return
+	}
+
+	var body []byte
+	for i := range webhooks {
+		if !webhooks[i].Wants(event.EventType()) {
+			continue
+		}
+		if body == nil {
+			if body, err = json.Marshal(domain.WebhookDelivery{
+				Event: event.EventType(), OccurredAt: time.Now().UTC(), Data: event,
+			}); err != nil {
+				log.Printf("webhooks: failed to encode %s: %v", event.EventType(), err)
+				return
+			}
+		}
+		s.queue(ctx, webhooks[i].ID, body)
+	}
+}
+
+func (s *WebhookService) queue(ctx context.Context, webhookID uint, body []byte) {
+	if s.Jobs != nil {
+		_, err := s.Jobs.Enqueue(ctx, JobTypeWebhookDelivery, webhookJob{WebhookID: webhookID, Body: body})
+		if err == nil {
+			return
+		}
+		log.Printf("webhooks: queueing a delivery to webhook %d failed, delivering now: %v", webhookID, err)
+	}
+	go func() {
+		if err := s.deliver(context.WithoutCancel(ctx), webhookID, body); err != nil {
+			log.Printf("webhooks: delivery to webhook %d failed: %v", webhookID, err)
+		}
+	}()
+}
+
+// RunDeliveryJob is the JobHandler of JobTypeWebhookDelivery
+func (s *WebhookService) RunDeliveryJob(ctx context.Context, payload json.RawMessage) error {
+	var job webhookJob
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return err
+	}
+	return s.deliver(ctx, job.WebhookID, job.Body)
+}
+
+// deliver posts a body to a webhook, signed with its secret. Webhooks that
+// were removed or deactivated meanwhile are skipped.
+func (s *WebhookService) deliver(ctx context.Context, webhookID uint, body []byte) error {
+	var webhook domain.Webhook
+	err := s.DB.WithContext(ctx).First(&webhook, webhookID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !webhook.Active {
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(domain.WebhookSignatureHeader, "sha256="+SignWebhook(webhook.Secret, body))
+
+	resp, err := s.client().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SignWebhook returns the hex HMAC-SHA256 of a delivery body, which
+// receivers compare with the signature header
+func SignWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,138 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupWebhookTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&domain.Webhook{}, &domain.Job{}))
+	return db
+}
+
+func TestWebhookService(t *testing.T) {
+	db := setupWebhookTestDB(t)
+	ctx := context.Background()
+
+	type delivery struct {
+		signature string
+		body      []byte
+	}
+	var deliveries []delivery
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		deliveries = append(deliveries, delivery{r.Header.Get(domain.WebhookSignatureHeader), body})
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	jobs := &JobService{DB: db, MaxAttempts: 2}
+	service := &WebhookService{DB: db, Jobs: jobs}
+	// Webhooks must be public, so the test server is called by a public name
+	webhookURL := "http://hooks.example.com"
+	service.Client = &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, server.Listener.Addr().String())
+		},
+	}}
+	jobs.Register(JobTypeWebhookDelivery, service.RunDeliveryJob)
+	bus := NewEventBus()
+	service.Subscribe(bus)
+
+	var budgets, all *domain.Webhook
+	t.Run("should create webhooks with a secret", func(t *testing.T) {
+		_, err := service.Create(ctx, 1, domain.Webhook{URL: "ftp://example.com", Events: []string{"budget.missed"}})
+		assert.ErrorIs(t, err, domain.ErrValidation)
+
+		budgets, err = service.Create(ctx, 1, domain.Webhook{URL: webhookURL + "/budgets", Events: []string{domain.EventBudgetExceeded}})
+		require.NoError(t, err)
+		assert.Len(t, budgets.Secret, 64)
+		all, err = service.Create(ctx, 1, domain.Webhook{URL: webhookURL + "/all"})
+		require.NoError(t, err)
+
+		webhooks, err := service.List(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, webhooks, 2)
+		assert.Empty(t, webhooks[0].Secret, "secrets are only shown on creation")
+	})
+
+	t.Run("should not post to the server's own networks", func(t *testing.T) {
+		_, err := service.Create(ctx, 1, domain.Webhook{URL: "http://169.254.169.254/latest/meta-data/"})
+		assert.ErrorIs(t, err, domain.ErrValidation)
+
+		// A public name can still resolve to a loopback address
+		webhook := domain.Webhook{UserID: 3, URL: server.URL, Secret: "secret", Active: true}
+		require.NoError(t, db.Create(&webhook).Error)
+		err = (&WebhookService{DB: db}).deliver(ctx, webhook.ID, []byte("{}"))
+		assert.ErrorIs(t, err, errPrivateAddress)
+	})
+
+	t.Run("should deliver the events each webhook subscribed to", func(t *testing.T) {
+		bus.Publish(ctx, domain.TransactionCreated{Transaction: domain.Transaction{ID: 7, UserID: 1}})
+		bus.Publish(ctx, domain.TransactionCreated{Transaction: domain.Transaction{ID: 8, UserID: 2}})
+		bus.Publish(ctx, domain.BudgetExceeded{Budget: domain.Budget{ID: 3, UserID: 1}})
+
+		for ran := true; ran; {
+			var err error
+			ran, err = jobs.RunNext(ctx)
+			require.NoError(t, err)
+		}
+		require.Len(t, deliveries, 3)
+
+		var first struct {
+			Event string `json:"event"`
+			Data  struct {
+				Transaction domain.Transaction `json:"transaction"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(deliveries[0].body, &first))
+		assert.Equal(t, domain.EventTransactionCreated, first.Event)
+		assert.Equal(t, uint(7), first.Data.Transaction.ID)
+		assert.Equal(t, "sha256="+SignWebhook(all.Secret, deliveries[0].body), deliveries[0].signature)
+
+		assert.Equal(t, "sha256="+SignWebhook(budgets.Secret, deliveries[1].body), deliveries[1].signature)
+		assert.Equal(t, deliveries[1].body, deliveries[2].body)
+	})
+
+	t.Run("should retry failed deliveries", func(t *testing.T) {
+		status = http.StatusInternalServerError
+		bus.Publish(ctx, domain.GoalReached{Goal: domain.FinancialGoal{ID: 4, UserID: 1}})
+
+		_, err := jobs.RunNext(ctx)
+		require.NoError(t, err)
+		queued, err := jobs.List(ctx, domain.JobFilter{Type: JobTypeWebhookDelivery, Status: domain.JobStatusQueued})
+		require.NoError(t, err)
+		require.Len(t, queued, 1)
+		assert.Contains(t, queued[0].LastError, "status 500")
+	})
+
+	t.Run("should drop deliveries of removed webhooks", func(t *testing.T) {
+		assert.ErrorIs(t, service.Delete(ctx, 2, all.ID), domain.ErrNotFound)
+		require.NoError(t, service.Delete(ctx, 1, all.ID))
+
+		delivered := len(deliveries)
+		require.NoError(t, db.Model(&domain.Job{}).Where("status = ?", domain.JobStatusQueued).
+			Update("run_at", gorm.Expr("created_at")).Error)
+		_, err := jobs.RunNext(ctx)
+		require.NoError(t, err)
+		assert.Len(t, deliveries, delivered)
+	})
+}
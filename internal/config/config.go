@@ -0,0 +1,1433 @@
+// Package config loads application settings from an optional YAML or TOML
+// file and from environment variables. Environment variables take precedence
+// over the file, and the file takes precedence over the built-in defaults.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// The environments the server can run in
+const (
+	EnvironmentDevelopment = "development"
+	EnvironmentProduction  = "production"
+)
+
+// Config holds all runtime settings for the API server and console app
+type Config struct {
+	Server      ServerConfig      `yaml:"server" toml:"server"`
+	Database    DatabaseConfig    `yaml:"database" toml:"database"`
+	Auth        AuthConfig        `yaml:"auth" toml:"auth"`
+	Market      MarketConfig      `yaml:"market" toml:"market"`
+	OCR         OCRConfig         `yaml:"ocr" toml:"ocr"`
+	Storage     StorageConfig     `yaml:"storage" toml:"storage"`
+	BankSync    BankSyncConfig    `yaml:"bank_sync" toml:"bank_sync"`
+	Wallets     WalletsConfig     `yaml:"wallets" toml:"wallets"`
+	FX          FXConfig          `yaml:"fx" toml:"fx"`
+	Jobs        JobsConfig        `yaml:"jobs" toml:"jobs"`
+	Cache       CacheConfig       `yaml:"cache" toml:"cache"`
+	Retention   RetentionConfig   `yaml:"retention" toml:"retention"`
+	Advisor     AdvisorConfig     `yaml:"advisor" toml:"advisor"`
+	AI          AIConfig          `yaml:"ai" toml:"ai"`
+	Categorizer CategorizerConfig `yaml:"categorizer" toml:"categorizer"`
+	LLM         LLMConfig         `yaml:"llm" toml:"llm"`
+	Console     ConsoleConfig     `yaml:"console" toml:"console"`
+	Demo        DemoConfig        `yaml:"demo" toml:"demo"`
+	Public      PublicConfig      `yaml:"public" toml:"public"`
+	Plans       PlansConfig       `yaml:"plans" toml:"plans"`
+	Billing     BillingConfig     `yaml:"billing" toml:"billing"`
+	Encryption  EncryptionConfig  `yaml:"encryption" toml:"encryption"`
+	Email       EmailConfig       `yaml:"email" toml:"email"`
+	Push        PushConfig        `yaml:"push" toml:"push"`
+}
+
+// ServerConfig holds HTTP and gRPC server settings. A GRPCPort of 0
+// disables the gRPC server. Environment is development or production; error
+// responses in production leave out the causes of server errors.
+// MaxBodyBytes bounds JSON request bodies; file uploads have their own limits.
+// PublicURL is the address clients reach the API at, such as
+// https://api.example.com; links used outside the app, like calendar feeds,
+// start with it, or with the address of the request when it is empty.
+// TrustedProxies are the addresses and CIDR ranges of the reverse proxies in
+// front of the API. Only their X-Forwarded-For headers are believed when
+// telling clients apart, as rate limits and sessions do; with none, clients
+// are told apart by the address they connect from.
+type ServerConfig struct {
+	Port           int      `yaml:"port" toml:"port"`
+	GRPCPort       int      `yaml:"grpc_port" toml:"grpc_port"`
+	CORSOrigins    []string `yaml:"cors_origins" toml:"cors_origins"`
+	Environment    string   `yaml:"environment" toml:"environment"`
+	MaxBodyBytes   int64    `yaml:"max_body_bytes" toml:"max_body_bytes"`
+	PublicURL      string   `yaml:"public_url" toml:"public_url"`
+	TrustedProxies []string `yaml:"trusted_proxies" toml:"trusted_proxies"`
+}
+
+// DatabaseConfig holds database connection settings. QueryTimeout bounds
+// every single statement; zero disables the limit. MigrateOnStart applies
+// pending migrations when the API starts instead of refusing to run.
+// BusyTimeout is how long a statement waits for another connection's write
+// lock before failing with "database is locked".
+// Replicas are DSNs of read replicas that serve the reads of read-heavy
+// endpoints; each is checked every ReplicaCheckInterval and only used while
+// it lags the primary by at most ReplicaMaxLag.
+type DatabaseConfig struct {
+	DSN                  string   `yaml:"dsn" toml:"dsn"`
+	QueryTimeout         Duration `yaml:"query_timeout" toml:"query_timeout"`
+	MigrateOnStart       bool     `yaml:"migrate_on_start" toml:"migrate_on_start"`
+	BusyTimeout          Duration `yaml:"busy_timeout" toml:"busy_timeout"`
+	Replicas             []string `yaml:"replicas" toml:"replicas"`
+	ReplicaMaxLag        Duration `yaml:"replica_max_lag" toml:"replica_max_lag"`
+	ReplicaCheckInterval Duration `yaml:"replica_check_interval" toml:"replica_check_interval"`
+}
+
+// AuthConfig holds token signing settings. JWTPreviousKeys maps key IDs to
+// retired secrets that are still accepted when verifying tokens. AdminUserIDs
+// lists the users allowed to use the admin endpoints. RefreshTokenExpiry is
+// how long a login session stays renewable after it was last refreshed.
+type AuthConfig struct {
+	JWTSecret          string            `yaml:"jwt_secret" toml:"jwt_secret"`
+	JWTKeyID           string            `yaml:"jwt_key_id" toml:"jwt_key_id"`
+	JWTPreviousKeys    map[string]string `yaml:"jwt_previous_keys" toml:"jwt_previous_keys"`
+	JWTAlgorithm       string            `yaml:"jwt_algorithm" toml:"jwt_algorithm"`
+	JWTIssuer          string            `yaml:"jwt_issuer" toml:"jwt_issuer"`
+	JWTAudience        string            `yaml:"jwt_audience" toml:"jwt_audience"`
+	TokenExpiry        Duration          `yaml:"token_expiry" toml:"token_expiry"`
+	RefreshTokenExpiry Duration          `yaml:"refresh_token_expiry" toml:"refresh_token_expiry"`
+	AdminUserIDs       []uint            `yaml:"admin_user_ids" toml:"admin_user_ids"`
+}
+
+// MarketConfig holds settings for the external market data providers
+type MarketConfig struct {
+	CoinGeckoBaseURL    string   `yaml:"coingecko_base_url" toml:"coingecko_base_url"`
+	CoinGeckoAPIKey     string   `yaml:"coingecko_api_key" toml:"coingecko_api_key"`
+	AlphaVantageBaseURL string   `yaml:"alpha_vantage_base_url" toml:"alpha_vantage_base_url"`
+	AlphaVantageAPIKey  string   `yaml:"alpha_vantage_api_key" toml:"alpha_vantage_api_key"`
+	RequestTimeout      Duration `yaml:"request_timeout" toml:"request_timeout"`
+	// PriceAlertInterval is how often watched prices are checked against price alerts
+	PriceAlertInterval Duration `yaml:"price_alert_interval" toml:"price_alert_interval"`
+	// MaxConcurrentRequests bounds how many quotes are fetched at once
+	MaxConcurrentRequests int `yaml:"max_concurrent_requests" toml:"max_concurrent_requests"`
+	// The rate intervals are the least time between two requests to a
+	// provider; zero leaves the provider unlimited
+	CoinGeckoRateInterval    Duration `yaml:"coingecko_rate_interval" toml:"coingecko_rate_interval"`
+	AlphaVantageRateInterval Duration `yaml:"alpha_vantage_rate_interval" toml:"alpha_vantage_rate_interval"`
+	// The provider timeouts bound each attempt; zero leaves only RequestTimeout
+	CoinGeckoTimeout    Duration `yaml:"coingecko_timeout" toml:"coingecko_timeout"`
+	AlphaVantageTimeout Duration `yaml:"alpha_vantage_timeout" toml:"alpha_vantage_timeout"`
+	// Failed requests are retried MaxRetries times, waiting RetryBackoff
+	// before the first retry and twice as long before each next one
+	MaxRetries   int      `yaml:"max_retries" toml:"max_retries"`
+	RetryBackoff Duration `yaml:"retry_backoff" toml:"retry_backoff"`
+	// After BreakerThreshold failures in a row a provider is left alone for
+	// BreakerCooldown and the last known prices are served; zero never trips
+	BreakerThreshold int      `yaml:"breaker_threshold" toml:"breaker_threshold"`
+	BreakerCooldown  Duration `yaml:"breaker_cooldown" toml:"breaker_cooldown"`
+}
+
+// OCRConfig holds receipt scanning settings. An empty APIURL selects the local tesseract binary.
+type OCRConfig struct {
+	APIURL string `yaml:"api_url" toml:"api_url"`
+	APIKey string `yaml:"api_key" toml:"api_key"`
+}
+
+// The drivers files can be stored with
+const (
+	StorageDriverLocal = "local"
+	StorageDriverS3    = "s3"
+)
+
+// StorageConfig selects where attachments and exported files are kept. The
+// local driver writes them below LocalDir and serves their download links
+// itself; BaseURL is the public URL of its files route, such as
+// https://api.example.com/api/v1/files, and links are relative to the API
+// without it. Links are signed with
+// SigningKey, or the JWT secret when it is empty. The s3 driver keeps them
+// in S3Bucket, or a bucket of an S3 compatible service at S3Endpoint, whose
+// presigned URLs clients download from directly.
+type StorageConfig struct {
+	Driver            string `yaml:"driver" toml:"driver"`
+	LocalDir          string `yaml:"local_dir" toml:"local_dir"`
+	BaseURL           string `yaml:"base_url" toml:"base_url"`
+	SigningKey        string `yaml:"signing_key" toml:"signing_key"`
+	S3Endpoint        string `yaml:"s3_endpoint" toml:"s3_endpoint"`
+	S3Region          string `yaml:"s3_region" toml:"s3_region"`
+	S3Bucket          string `yaml:"s3_bucket" toml:"s3_bucket"`
+	S3AccessKeyID     string `yaml:"s3_access_key_id" toml:"s3_access_key_id"`
+	S3SecretAccessKey string `yaml:"s3_secret_access_key" toml:"s3_secret_access_key"`
+	S3PathStyle       bool   `yaml:"s3_path_style" toml:"s3_path_style"`
+}
+
+// BankSyncConfig holds the bank sync provider credentials. A provider is
+// offered once its credentials are set. Interval is how often linked
+// accounts are synced.
+type BankSyncConfig struct {
+	Interval            Duration `yaml:"interval" toml:"interval"`
+	PlaidBaseURL        string   `yaml:"plaid_base_url" toml:"plaid_base_url"`
+	PlaidClientID       string   `yaml:"plaid_client_id" toml:"plaid_client_id"`
+	PlaidSecret         string   `yaml:"plaid_secret" toml:"plaid_secret"`
+	GoCardlessBaseURL   string   `yaml:"gocardless_base_url" toml:"gocardless_base_url"`
+	GoCardlessSecretID  string   `yaml:"gocardless_secret_id" toml:"gocardless_secret_id"`
+	GoCardlessSecretKey string   `yaml:"gocardless_secret_key" toml:"gocardless_secret_key"`
+}
+
+// WalletsConfig holds the public blockchain APIs crypto wallet balances are
+// read from: an Esplora API for Bitcoin and a Blockscout instance for
+// Ethereum. RefreshInterval is how often registered wallets are refreshed.
+type WalletsConfig struct {
+	RefreshInterval Duration `yaml:"refresh_interval" toml:"refresh_interval"`
+	BTCBaseURL      string   `yaml:"btc_base_url" toml:"btc_base_url"`
+	ETHBaseURL      string   `yaml:"eth_base_url" toml:"eth_base_url"`
+}
+
+// FXConfig holds the exchange rate API foreign currency transactions are
+// converted with. Rates are fetched for the transaction's date and kept;
+// while the API is unavailable, the latest stored rate at most FallbackWindow
+// older than the date stands in.
+type FXConfig struct {
+	ProviderURL    string   `yaml:"provider_url" toml:"provider_url"`
+	FallbackWindow Duration `yaml:"fallback_window" toml:"fallback_window"`
+}
+
+// JobsConfig holds background job queue settings. Workers is how many jobs
+// run at once; a failed job is retried up to MaxAttempts times in all,
+// waiting RetryBackoff before the first retry and twice as long before each
+// further one.
+type JobsConfig struct {
+	Workers      int      `yaml:"workers" toml:"workers"`
+	PollInterval Duration `yaml:"poll_interval" toml:"poll_interval"`
+	MaxAttempts  int      `yaml:"max_attempts" toml:"max_attempts"`
+	RetryBackoff Duration `yaml:"retry_backoff" toml:"retry_backoff"`
+}
+
+// CacheConfig holds cache expiry settings. AnalyticsTTL bounds how long
+// dashboard and metrics results are reused; ClientMaxAge is the max-age sent
+// to clients for them, zero meaning they must not cache.
+type CacheConfig struct {
+	InsightsTTL  Duration `yaml:"insights_ttl" toml:"insights_ttl"`
+	AnalyticsTTL Duration `yaml:"analytics_ttl" toml:"analytics_ttl"`
+	ClientMaxAge Duration `yaml:"client_max_age" toml:"client_max_age"`
+}
+
+// RetentionConfig holds how long deleted data is kept before it is purged
+// and after how many years transactions are moved into the compressed
+// archives, zero keeping them in place
+type RetentionConfig struct {
+	TrashPeriod       Duration `yaml:"trash_period" toml:"trash_period"`
+	ArchiveAfterYears int      `yaml:"archive_after_years" toml:"archive_after_years"`
+}
+
+// AdvisorConfig holds advice settings. RiskQuestionnaireFile is a YAML or JSON
+// risk questionnaire served instead of the built-in one when set.
+// MaxSimulationIterations caps the runs of a retirement simulation, which
+// bounds how long one takes.
+type AdvisorConfig struct {
+	RiskQuestionnaireFile   string `yaml:"risk_questionnaire_file" toml:"risk_questionnaire_file"`
+	MaxSimulationIterations int    `yaml:"max_simulation_iterations" toml:"max_simulation_iterations"`
+}
+
+// AlphaVantageDemoKey is Alpha Vantage's public key, which only quotes a
+// few sample symbols
+const AlphaVantageDemoKey = "demo"
+
+// AIConfig tunes the heuristics behind the advisor's market analysis, risk
+// assessments and advice. The market trend is bullish or bearish when prices
+// moved more than TrendCutoff percent on average, up or down, and average
+// moves beyond the volatility cutoffs make the market medium or highly
+// volatile. Recommendations invest InvestableShare of the monthly income,
+// and risk scores from ModerateRiskScore and HighRiskScore on are moderate
+// and high risk. RiskAllocations are the asset classes recommended per risk
+// category and AdviceAllocations the assets monthly savings are put into per
+// risk tolerance; the shares of every row sum to 1.
+type AIConfig struct {
+	TrendCutoff            float64                      `yaml:"trend_cutoff" toml:"trend_cutoff" json:"trend_cutoff"`
+	VolatilityMediumCutoff float64                      `yaml:"volatility_medium_cutoff" toml:"volatility_medium_cutoff" json:"volatility_medium_cutoff"`
+	VolatilityHighCutoff   float64                      `yaml:"volatility_high_cutoff" toml:"volatility_high_cutoff" json:"volatility_high_cutoff"`
+	InvestableShare        float64                      `yaml:"investable_share" toml:"investable_share" json:"investable_share"`
+	ModerateRiskScore      float64                      `yaml:"moderate_risk_score" toml:"moderate_risk_score" json:"moderate_risk_score"`
+	HighRiskScore          float64                      `yaml:"high_risk_score" toml:"high_risk_score" json:"high_risk_score"`
+	RiskAllocations        map[string][]AllocationShare `yaml:"risk_allocations" toml:"risk_allocations" json:"risk_allocations"`
+	AdviceAllocations      map[string][]AllocationShare `yaml:"advice_allocations" toml:"advice_allocations" json:"advice_allocations"`
+}
+
+// AllocationShare is the share of an allocation table row that goes to an asset
+type AllocationShare struct {
+	Asset string  `yaml:"asset" toml:"asset" json:"asset"`
+	Share float64 `yaml:"share" toml:"share" json:"share"`
+}
+
+// CategorizerConfig holds the settings of the per-user category models. When
+// enabled, categories are only suggested with at least MinConfidence, models
+// need MinSamples categorized transactions to train on, and models whose
+// transactions changed are retrained every RetrainInterval.
+type CategorizerConfig struct {
+	Enabled         bool     `yaml:"enabled" toml:"enabled"`
+	MinConfidence   float64  `yaml:"min_confidence" toml:"min_confidence"`
+	MinSamples      int      `yaml:"min_samples" toml:"min_samples"`
+	RetrainInterval Duration `yaml:"retrain_interval" toml:"retrain_interval"`
+}
+
+// LLMConfig holds the language model the assistant asks, reached through an
+// OpenAI compatible chat completions API at BaseURL. The assistant is only
+// offered once a Model is set; local servers usually need no APIKey.
+type LLMConfig struct {
+	BaseURL string   `yaml:"base_url" toml:"base_url"`
+	APIKey  string   `yaml:"api_key" toml:"api_key"`
+	Model   string   `yaml:"model" toml:"model"`
+	Timeout Duration `yaml:"timeout" toml:"timeout"`
+}
+
+// Enabled reports whether a model is configured
+func (l LLMConfig) Enabled() bool {
+	return l.Model != ""
+}
+
+// ConsoleConfig holds console app settings. SessionTimeout logs users out
+// after that long without input; zero keeps them logged in.
+type ConsoleConfig struct {
+	SessionTimeout Duration `yaml:"session_timeout" toml:"session_timeout"`
+}
+
+// DemoConfig holds the demo mode settings. When enabled, a demo user with
+// the given email is seeded with sample data at startup and anyone can open
+// a read-only session as that user.
+type DemoConfig struct {
+	Enabled bool   `yaml:"enabled" toml:"enabled"`
+	Email   string `yaml:"email" toml:"email"`
+}
+
+// PublicConfig holds the limits of the unauthenticated market endpoints the
+// landing page shows live data from. Each client IP may send RateLimit
+// requests per RateWindow, and every visitor is served the same response for
+// CacheTTL.
+type PublicConfig struct {
+	RateLimit  int      `yaml:"rate_limit" toml:"rate_limit"`
+	RateWindow Duration `yaml:"rate_window" toml:"rate_window"`
+	CacheTTL   Duration `yaml:"cache_ttl" toml:"cache_ttl"`
+}
+
+// PlansConfig holds the limits of the plans users can be on
+type PlansConfig struct {
+	Free    PlanConfig `yaml:"free" toml:"free"`
+	Premium PlanConfig `yaml:"premium" toml:"premium"`
+}
+
+// PlanConfig holds the limits of one plan. Users may send AIRequestsPerDay
+// requests to the AI and advice endpoints and refresh linked bank accounts
+// BankRefreshesPerDay times a day, and export at most ExportMaxRows
+// transactions at once; zero means unlimited. Scheduled syncs leave a bank
+// account alone for BankSyncInterval after it was synced. Features lists the
+// PlanFeatures the plan includes; they are only withheld while billing is
+// enabled, since users could not buy them otherwise.
+type PlanConfig struct {
+	AIRequestsPerDay    int      `yaml:"ai_requests_per_day" toml:"ai_requests_per_day"`
+	BankRefreshesPerDay int      `yaml:"bank_refreshes_per_day" toml:"bank_refreshes_per_day"`
+	ExportMaxRows       int      `yaml:"export_max_rows" toml:"export_max_rows"`
+	BankSyncInterval    Duration `yaml:"bank_sync_interval" toml:"bank_sync_interval"`
+	Features            []string `yaml:"features" toml:"features"`
+}
+
+// PlanFeatures lists the features a plan can include
+var PlanFeatures = []string{"bank_sync", "ai_advisor", "scheduled_reports"}
+
+// BillingConfig holds the Stripe account the premium plan is sold through.
+// Billing is enabled once StripeSecretKey is set. Users subscribe to
+// PremiumPriceID in Stripe Checkout, which sends them back to SuccessURL or
+// CancelURL; the customer portal sends them back to PortalReturnURL.
+// StripeWebhookSecret verifies the events Stripe posts to the webhook.
+type BillingConfig struct {
+	StripeBaseURL       string `yaml:"stripe_base_url" toml:"stripe_base_url"`
+	StripeSecretKey     string `yaml:"stripe_secret_key" toml:"stripe_secret_key"`
+	StripeWebhookSecret string `yaml:"stripe_webhook_secret" toml:"stripe_webhook_secret"`
+	PremiumPriceID      string `yaml:"premium_price_id" toml:"premium_price_id"`
+	SuccessURL          string `yaml:"success_url" toml:"success_url"`
+	CancelURL           string `yaml:"cancel_url" toml:"cancel_url"`
+	PortalReturnURL     string `yaml:"portal_return_url" toml:"portal_return_url"`
+}
+
+// Enabled reports whether a Stripe account is configured
+func (b BillingConfig) Enabled() bool {
+	return b.StripeSecretKey != ""
+}
+
+// EncryptionConfig holds the keys sensitive columns are encrypted with at
+// rest. Keys maps key IDs to base64 encoded 32 byte AES keys; KeyID names the
+// one new values are sealed with, the others only decrypt older values until
+// `finance-advisor rotate-keys` has re-encrypted them. Without keys the
+// columns are stored in plaintext.
+type EncryptionConfig struct {
+	KeyID string            `yaml:"key_id" toml:"key_id"`
+	Keys  map[string]string `yaml:"keys" toml:"keys"`
+}
+
+// Enabled reports whether encryption keys are configured
+func (e EncryptionConfig) Enabled() bool {
+	return len(e.Keys) > 0
+}
+
+// EmailConfig holds the SMTP server digest emails are sent through. Email is
+// enabled once Host is set; without a Username mail is sent unauthenticated.
+// Due digests are queued every DigestInterval, and their unsubscribe links
+// point at UnsubscribeURL, the public address of the unsubscribe endpoint.
+type EmailConfig struct {
+	Host           string   `yaml:"host" toml:"host"`
+	Port           int      `yaml:"port" toml:"port"`
+	Username       string   `yaml:"username" toml:"username"`
+	Password       string   `yaml:"password" toml:"password"`
+	From           string   `yaml:"from" toml:"from"`
+	DigestInterval Duration `yaml:"digest_interval" toml:"digest_interval"`
+	UnsubscribeURL string   `yaml:"unsubscribe_url" toml:"unsubscribe_url"`
+}
+
+// Enabled reports whether an SMTP server is configured
+func (e EmailConfig) Enabled() bool {
+	return e.Host != ""
+}
+
+// PushConfig holds the push services notifications reach the mobile app
+// through. Android devices are reached through FCM once FCMCredentialsFile,
+// a Firebase service account key, is set; iOS devices through APNs once
+// APNsKeyFile, the .p8 token signing key, is set along with its key ID, the
+// team ID and the app's bundle ID as APNsTopic. Use
+// https://api.sandbox.push.apple.com as APNsBaseURL for development builds.
+type PushConfig struct {
+	FCMBaseURL         string `yaml:"fcm_base_url" toml:"fcm_base_url"`
+	FCMCredentialsFile string `yaml:"fcm_credentials_file" toml:"fcm_credentials_file"`
+	APNsBaseURL        string `yaml:"apns_base_url" toml:"apns_base_url"`
+	APNsKeyFile        string `yaml:"apns_key_file" toml:"apns_key_file"`
+	APNsKeyID          string `yaml:"apns_key_id" toml:"apns_key_id"`
+	APNsTeamID         string `yaml:"apns_team_id" toml:"apns_team_id"`
+	APNsTopic          string `yaml:"apns_topic" toml:"apns_topic"`
+}
+
+// Duration is a time.Duration that can be written as "15s" or "1h" in config files
+type Duration time.Duration
+
+// UnmarshalText parses a duration string such as "90s"
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// MarshalText formats the duration as a string
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// Std returns the value as a time.Duration
+func (d Duration) Std() time.Duration {
+	return time.Duration(d)
+}
+
+// Default returns the settings used when nothing is configured
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port:         8080,
+			GRPCPort:     50051,
+			CORSOrigins:  []string{"*"},
+			Environment:  EnvironmentDevelopment,
+			MaxBodyBytes: 1 << 20,
+		},
+		Database: DatabaseConfig{
+			DSN:                  "finance.db",
+			QueryTimeout:         Duration(5 * time.Second),
+			BusyTimeout:          Duration(5 * time.Second),
+			ReplicaMaxLag:        Duration(5 * time.Second),
+			ReplicaCheckInterval: Duration(time.Second),
+		},
+		// There is deliberately no default secret; the API refuses to start without one
+		Auth: AuthConfig{
+			JWTAlgorithm:       "HS256",
+			JWTIssuer:          "go-finance-advisor",
+			TokenExpiry:        Duration(24 * time.Hour),
+			RefreshTokenExpiry: Duration(30 * 24 * time.Hour),
+		},
+		Market: MarketConfig{
+			CoinGeckoBaseURL:    "https://api.coingecko.com/api/v3",
+			AlphaVantageBaseURL: "https://www.alphavantage.co",
+			AlphaVantageAPIKey:  AlphaVantageDemoKey,
+			RequestTimeout:      Duration(15 * time.Second),
+			PriceAlertInterval:  Duration(5 * time.Minute),
+
+			MaxConcurrentRequests:    4,
+			AlphaVantageRateInterval: Duration(200 * time.Millisecond),
+			MaxRetries:               2,
+			RetryBackoff:             Duration(250 * time.Millisecond),
+			BreakerThreshold:         5,
+			BreakerCooldown:          Duration(30 * time.Second),
+		},
+		Storage: StorageConfig{
+			Driver:   StorageDriverLocal,
+			LocalDir: "uploads",
+			S3Region: "us-east-1",
+		},
+		BankSync: BankSyncConfig{
+			Interval:          Duration(6 * time.Hour),
+			PlaidBaseURL:      "https://sandbox.plaid.com",
+			GoCardlessBaseURL: "https://bankaccountdata.gocardless.com",
+		},
+		Wallets: WalletsConfig{
+			RefreshInterval: Duration(6 * time.Hour),
+			BTCBaseURL:      "https://blockstream.info/api",
+			ETHBaseURL:      "https://eth.blockscout.com",
+		},
+		FX: FXConfig{
+			ProviderURL:    "https://api.frankfurter.app",
+			FallbackWindow: Duration(7 * 24 * time.Hour),
+		},
+		Jobs: JobsConfig{
+			Workers:      4,
+			PollInterval: Duration(time.Second),
+			MaxAttempts:  5,
+			RetryBackoff: Duration(30 * time.Second),
+		},
+		Cache: CacheConfig{
+			InsightsTTL:  Duration(time.Hour),
+			AnalyticsTTL: Duration(5 * time.Minute),
+			ClientMaxAge: Duration(30 * time.Second),
+		},
+		Retention: RetentionConfig{
+			TrashPeriod: Duration(30 * 24 * time.Hour),
+		},
+		Advisor: AdvisorConfig{
+			MaxSimulationIterations: 10000,
+		},
+		AI: AIConfig{
+			TrendCutoff:            2,
+			VolatilityMediumCutoff: 2,
+			VolatilityHighCutoff:   5,
+			InvestableShare:        0.2,
+			ModerateRiskScore:      0.4,
+			HighRiskScore:          0.7,
+			RiskAllocations: map[string][]AllocationShare{
+				"low_risk_stable":       {{"stocks", 0.3}, {"crypto", 0.1}, {"bonds", 0.4}, {"cash", 0.2}},
+				"moderate_risk":         {{"stocks", 0.5}, {"crypto", 0.2}, {"bonds", 0.2}, {"cash", 0.1}},
+				"high_risk_high_reward": {{"stocks", 0.4}, {"crypto", 0.4}, {"bonds", 0.1}, {"cash", 0.1}},
+			},
+			AdviceAllocations: map[string][]AllocationShare{
+				"conservative": {{"SPY", 0.7}, {"BTC", 0.3}},
+				"moderate":     {{"SPY", 0.5}, {"BTC", 0.5}},
+				"aggressive":   {{"SPY", 0.3}, {"BTC", 0.7}},
+			},
+		},
+		Categorizer: CategorizerConfig{
+			MinConfidence:   0.6,
+			MinSamples:      20,
+			RetrainInterval: Duration(24 * time.Hour),
+		},
+		LLM: LLMConfig{
+			BaseURL: "https://api.openai.com/v1",
+			Timeout: Duration(30 * time.Second),
+		},
+		Console: ConsoleConfig{
+			SessionTimeout: Duration(15 * time.Minute),
+		},
+		Demo: DemoConfig{
+			Email: "demo@go-finance-advisor.local",
+		},
+		Public: PublicConfig{
+			RateLimit:  30,
+			RateWindow: Duration(time.Minute),
+			CacheTTL:   Duration(time.Minute),
+		},
+		Plans: PlansConfig{
+			Free: PlanConfig{
+				AIRequestsPerDay:    20,
+				BankRefreshesPerDay: 2,
+				ExportMaxRows:       1000,
+				BankSyncInterval:    Duration(24 * time.Hour),
+			},
+			Premium: PlanConfig{
+				AIRequestsPerDay:    500,
+				BankRefreshesPerDay: 24,
+				BankSyncInterval:    Duration(6 * time.Hour),
+				Features:            slices.Clone(PlanFeatures),
+			},
+		},
+		Billing: BillingConfig{
+			StripeBaseURL: "https://api.stripe.com",
+		},
+		Email: EmailConfig{
+			Port:           587,
+			DigestInterval: Duration(time.Hour),
+		},
+		Push: PushConfig{
+			FCMBaseURL:  "https://fcm.googleapis.com",
+			APNsBaseURL: "https://api.push.apple.com",
+		},
+	}
+}
+
+// Load builds the configuration from defaults, the optional file at path and
+// the environment. When path is empty the CONFIG_FILE variable is used.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	if path == "" {
+		path = os.Getenv("CONFIG_FILE")
+	}
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, c)
+	case ".toml":
+		err = toml.Unmarshal(data, c)
+	default:
+		return fmt.Errorf("unsupported config file format %q (use .yaml, .yml or .toml)", filepath.Ext(path))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// applyEnv overrides settings with environment variables. Alternative names
+// used by older deployment files (DB_PATH, APP_PORT) are still honoured.
+func (c *Config) applyEnv() error {
+	if value, ok := lookupEnv("PORT", "APP_PORT"); ok {
+		port, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid PORT %q: %w", value, err)
+		}
+		c.Server.Port = port
+	}
+	if value, ok := lookupEnv("GRPC_PORT"); ok {
+		port, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid GRPC_PORT %q: %w", value, err)
+		}
+		c.Server.GRPCPort = port
+	}
+	if value, ok := lookupEnv("CORS_ALLOWED_ORIGINS"); ok {
+		c.Server.CORSOrigins = splitList(value)
+	}
+	if value, ok := lookupEnv("APP_ENV"); ok {
+		c.Server.Environment = strings.ToLower(strings.TrimSpace(value))
+	}
+	if value, ok := lookupEnv("MAX_BODY_BYTES"); ok {
+		limit, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid MAX_BODY_BYTES %q: %w", value, err)
+		}
+		c.Server.MaxBodyBytes = limit
+	}
+	if value, ok := lookupEnv("PUBLIC_URL"); ok {
+		c.Server.PublicURL = value
+	}
+	if value, ok := lookupEnv("TRUSTED_PROXIES"); ok {
+		c.Server.TrustedProxies = splitList(value)
+	}
+
+	if value, ok := lookupEnv("DATABASE_URL", "DB_PATH"); ok {
+		c.Database.DSN = strings.TrimPrefix(value, "sqlite://")
+	}
+	if value, ok := lookupEnv("DB_QUERY_TIMEOUT"); ok {
+		if err := c.Database.QueryTimeout.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("invalid DB_QUERY_TIMEOUT %q: %w", value, err)
+		}
+	}
+	if value, ok := lookupEnv("DB_MIGRATE_ON_START"); ok {
+		migrate, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid DB_MIGRATE_ON_START %q: %w", value, err)
+		}
+		c.Database.MigrateOnStart = migrate
+	}
+	if value, ok := lookupEnv("DB_BUSY_TIMEOUT"); ok {
+		if err := c.Database.BusyTimeout.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("invalid DB_BUSY_TIMEOUT %q: %w", value, err)
+		}
+	}
+	if value, ok := lookupEnv("DB_REPLICAS"); ok {
+		c.Database.Replicas = splitList(value)
+		for i, dsn := range c.Database.Replicas {
+			c.Database.Replicas[i] = strings.TrimPrefix(dsn, "sqlite://")
+		}
+	}
+	if value, ok := lookupEnv("DB_REPLICA_MAX_LAG"); ok {
+		if err := c.Database.ReplicaMaxLag.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("invalid DB_REPLICA_MAX_LAG %q: %w", value, err)
+		}
+	}
+	if value, ok := lookupEnv("DB_REPLICA_CHECK_INTERVAL"); ok {
+		if err := c.Database.ReplicaCheckInterval.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("invalid DB_REPLICA_CHECK_INTERVAL %q: %w", value, err)
+		}
+	}
+
+	if err := c.Auth.applyEnv(); err != nil {
+		return err
+	}
+
+	if value, ok := lookupEnv("COINGECKO_API_KEY"); ok {
+		c.Market.CoinGeckoAPIKey = value
+	}
+	if value, ok := lookupEnv("ALPHA_VANTAGE_API_KEY"); ok {
+		c.Market.AlphaVantageAPIKey = value
+	}
+	if value, ok := lookupEnv("MARKET_REQUEST_TIMEOUT"); ok {
+		if err := c.Market.RequestTimeout.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("invalid MARKET_REQUEST_TIMEOUT %q: %w", value, err)
+		}
+	}
+	if value, ok := lookupEnv("PRICE_ALERT_INTERVAL"); ok {
+		if err := c.Market.PriceAlertInterval.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("invalid PRICE_ALERT_INTERVAL %q: %w", value, err)
+		}
+	}
+	if value, ok := lookupEnv("MARKET_MAX_CONCURRENCY"); ok {
+		concurrency, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid MARKET_MAX_CONCURRENCY %q: %w", value, err)
+		}
+		c.Market.MaxConcurrentRequests = concurrency
+	}
+	if value, ok := lookupEnv("COINGECKO_RATE_INTERVAL"); ok {
+		if err := c.Market.CoinGeckoRateInterval.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("invalid COINGECKO_RATE_INTERVAL %q: %w", value, err)
+		}
+	}
+	if value, ok := lookupEnv("ALPHA_VANTAGE_RATE_INTERVAL"); ok {
+		if err := c.Market.AlphaVantageRateInterval.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("invalid ALPHA_VANTAGE_RATE_INTERVAL %q: %w", value, err)
+		}
+	}
+	if value, ok := lookupEnv("COINGECKO_TIMEOUT"); ok {
+		if err := c.Market.CoinGeckoTimeout.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("invalid COINGECKO_TIMEOUT %q: %w", value, err)
+		}
+	}
+	if value, ok := lookupEnv("ALPHA_VANTAGE_TIMEOUT"); ok {
+		if err := c.Market.AlphaVantageTimeout.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("invalid ALPHA_VANTAGE_TIMEOUT %q: %w", value, err)
+		}
+	}
+	if value, ok := lookupEnv("MARKET_MAX_RETRIES"); ok {
+		retries, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid MARKET_MAX_RETRIES %q: %w", value, err)
+		}
+		c.Market.MaxRetries = retries
+	}
+	if value, ok := lookupEnv("MARKET_RETRY_BACKOFF"); ok {
+		if err := c.Market.RetryBackoff.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("invalid MARKET_RETRY_BACKOFF %q: %w", value, err)
+		}
+	}
+	if value, ok := lookupEnv("MARKET_BREAKER_THRESHOLD"); ok {
+		threshold, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid MARKET_BREAKER_THRESHOLD %q: %w", value, err)
+		}
+		c.Market.BreakerThreshold = threshold
+	}
+	if value, ok := lookupEnv("MARKET_BREAKER_COOLDOWN"); ok {
+		if err := c.Market.BreakerCooldown.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("invalid MARKET_BREAKER_COOLDOWN %q: %w", value, err)
+		}
+	}
+
+	if value, ok := lookupEnv("OCR_API_URL"); ok {
+		c.OCR.APIURL = value
+	}
+	if value, ok := lookupEnv("OCR_API_KEY"); ok {
+		c.OCR.APIKey = value
+	}
+
+	for name, value := range map[string]*string{
+		"STORAGE_DRIVER":       &c.Storage.Driver,
+		"STORAGE_LOCAL_DIR":    &c.Storage.LocalDir,
+		"STORAGE_BASE_URL":     &c.Storage.BaseURL,
+		"STORAGE_SIGNING_KEY":  &c.Storage.SigningKey,
+		"S3_ENDPOINT":          &c.Storage.S3Endpoint,
+		"S3_REGION":            &c.Storage.S3Region,
+		"S3_BUCKET":            &c.Storage.S3Bucket,
+		"S3_ACCESS_KEY_ID":     &c.Storage.S3AccessKeyID,
+		"S3_SECRET_ACCESS_KEY": &c.Storage.S3SecretAccessKey,
+	} {
+		if raw, ok := lookupEnv(name); ok {
+			*value = raw
+		}
+	}
+	if value, ok := lookupEnv("S3_PATH_STYLE"); ok {
+		pathStyle, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid S3_PATH_STYLE %q: %w", value, err)
+		}
+		c.Storage.S3PathStyle = pathStyle
+	}
+
+	if value, ok := lookupEnv("BANK_SYNC_INTERVAL"); ok {
+		if err := c.BankSync.Interval.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("invalid BANK_SYNC_INTERVAL %q: %w", value, err)
+		}
+	}
+	if value, ok := lookupEnv("PLAID_BASE_URL"); ok {
+		c.BankSync.PlaidBaseURL = value
+	}
+	if value, ok := lookupEnv("PLAID_CLIENT_ID"); ok {
+		c.BankSync.PlaidClientID = value
+	}
+	if value, ok := lookupEnv("PLAID_SECRET"); ok {
+		c.BankSync.PlaidSecret = value
+	}
+	if value, ok := lookupEnv("GOCARDLESS_BASE_URL"); ok {
+		c.BankSync.GoCardlessBaseURL = value
+	}
+	if value, ok := lookupEnv("GOCARDLESS_SECRET_ID"); ok {
+		c.BankSync.GoCardlessSecretID = value
+	}
+	if value, ok := lookupEnv("GOCARDLESS_SECRET_KEY"); ok {
+		c.BankSync.GoCardlessSecretKey = value
+	}
+
+	if value, ok := lookupEnv("WALLET_REFRESH_INTERVAL"); ok {
+		if err := c.Wallets.RefreshInterval.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("invalid WALLET_REFRESH_INTERVAL %q: %w", value, err)
+		}
+	}
+	if value, ok := lookupEnv("WALLET_BTC_BASE_URL"); ok {
+		c.Wallets.BTCBaseURL = value
+	}
+	if value, ok := lookupEnv("WALLET_ETH_BASE_URL"); ok {
+		c.Wallets.ETHBaseURL = value
+	}
+
+	if value, ok := lookupEnv("FX_PROVIDER_URL"); ok {
+		c.FX.ProviderURL = value
+	}
+	if value, ok := lookupEnv("FX_FALLBACK_WINDOW"); ok {
+		if err := c.FX.FallbackWindow.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("invalid FX_FALLBACK_WINDOW %q: %w", value, err)
+		}
+	}
+
+	if value, ok := lookupEnv("JOB_WORKERS"); ok {
+		workers, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid JOB_WORKERS %q: %w", value, err)
+		}
+		c.Jobs.Workers = workers
+	}
+	if value, ok := lookupEnv("JOB_POLL_INTERVAL"); ok {
+		if err := c.Jobs.PollInterval.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("invalid JOB_POLL_INTERVAL %q: %w", value, err)
+		}
+	}
+	if value, ok := lookupEnv("JOB_MAX_ATTEMPTS"); ok {
+		attempts, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid JOB_MAX_ATTEMPTS %q: %w", value, err)
+		}
+		c.Jobs.MaxAttempts = attempts
+	}
+	if value, ok := lookupEnv("JOB_RETRY_BACKOFF"); ok {
+		if err := c.Jobs.RetryBackoff.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("invalid JOB_RETRY_BACKOFF %q: %w", value, err)
+		}
+	}
+
+	if value, ok := lookupEnv("INSIGHTS_CACHE_TTL"); ok {
+		if err := c.Cache.InsightsTTL.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("invalid INSIGHTS_CACHE_TTL %q: %w", value, err)
+		}
+	}
+	if value, ok := lookupEnv("ANALYTICS_CACHE_TTL"); ok {
+		if err := c.Cache.AnalyticsTTL.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("invalid ANALYTICS_CACHE_TTL %q: %w", value, err)
+		}
+	}
+	if value, ok := lookupEnv("CLIENT_CACHE_MAX_AGE"); ok {
+		if err := c.Cache.ClientMaxAge.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("invalid CLIENT_CACHE_MAX_AGE %q: %w", value, err)
+		}
+	}
+
+	if value, ok := lookupEnv("TRASH_RETENTION"); ok {
+		if err := c.Retention.TrashPeriod.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("invalid TRASH_RETENTION %q: %w", value, err)
+		}
+	}
+	if value, ok := lookupEnv("ARCHIVE_AFTER_YEARS"); ok {
+		years, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid ARCHIVE_AFTER_YEARS %q: %w", value, err)
+		}
+		c.Retention.ArchiveAfterYears = years
+	}
+
+	if value, ok := lookupEnv("RISK_QUESTIONNAIRE_FILE"); ok {
+		c.Advisor.RiskQuestionnaireFile = value
+	}
+	if value, ok := lookupEnv("ADVISOR_MAX_SIMULATION_ITERATIONS"); ok {
+		iterations, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid ADVISOR_MAX_SIMULATION_ITERATIONS %q: %w", value, err)
+		}
+		c.Advisor.MaxSimulationIterations = iterations
+	}
+
+	if err := c.AI.applyEnv(); err != nil {
+		return err
+	}
+
+	if value, ok := lookupEnv("CATEGORIZER_ENABLED"); ok {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid CATEGORIZER_ENABLED %q: %w", value, err)
+		}
+		c.Categorizer.Enabled = enabled
+	}
+	if value, ok := lookupEnv("CATEGORIZER_MIN_CONFIDENCE"); ok {
+		confidence, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid CATEGORIZER_MIN_CONFIDENCE %q: %w", value, err)
+		}
+		c.Categorizer.MinConfidence = confidence
+	}
+	if value, ok := lookupEnv("CATEGORIZER_MIN_SAMPLES"); ok {
+		samples, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid CATEGORIZER_MIN_SAMPLES %q: %w", value, err)
+		}
+		c.Categorizer.MinSamples = samples
+	}
+	if value, ok := lookupEnv("CATEGORIZER_RETRAIN_INTERVAL"); ok {
+		if err := c.Categorizer.RetrainInterval.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("invalid CATEGORIZER_RETRAIN_INTERVAL %q: %w", value, err)
+		}
+	}
+
+	if value, ok := lookupEnv("LLM_BASE_URL"); ok {
+		c.LLM.BaseURL = value
+	}
+	if value, ok := lookupEnv("LLM_API_KEY"); ok {
+		c.LLM.APIKey = value
+	}
+	if value, ok := lookupEnv("LLM_MODEL"); ok {
+		c.LLM.Model = value
+	}
+	if value, ok := lookupEnv("LLM_TIMEOUT"); ok {
+		if err := c.LLM.Timeout.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("invalid LLM_TIMEOUT %q: %w", value, err)
+		}
+	}
+
+	if value, ok := lookupEnv("CONSOLE_SESSION_TIMEOUT"); ok {
+		if err := c.Console.SessionTimeout.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("invalid CONSOLE_SESSION_TIMEOUT %q: %w", value, err)
+		}
+	}
+
+	if value, ok := lookupEnv("DEMO_MODE"); ok {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid DEMO_MODE %q: %w", value, err)
+		}
+		c.Demo.Enabled = enabled
+	}
+	if value, ok := lookupEnv("DEMO_EMAIL"); ok {
+		c.Demo.Email = value
+	}
+
+	if value, ok := lookupEnv("PUBLIC_RATE_LIMIT"); ok {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid PUBLIC_RATE_LIMIT %q: %w", value, err)
+		}
+		c.Public.RateLimit = limit
+	}
+	if value, ok := lookupEnv("PUBLIC_RATE_WINDOW"); ok {
+		if err := c.Public.RateWindow.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("invalid PUBLIC_RATE_WINDOW %q: %w", value, err)
+		}
+	}
+	if value, ok := lookupEnv("PUBLIC_CACHE_TTL"); ok {
+		if err := c.Public.CacheTTL.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("invalid PUBLIC_CACHE_TTL %q: %w", value, err)
+		}
+	}
+
+	if err := c.Plans.Free.applyEnv("PLAN_FREE_"); err != nil {
+		return err
+	}
+	if err := c.Plans.Premium.applyEnv("PLAN_PREMIUM_"); err != nil {
+		return err
+	}
+
+	for name, value := range map[string]*string{
+		"STRIPE_BASE_URL":           &c.Billing.StripeBaseURL,
+		"STRIPE_SECRET_KEY":         &c.Billing.StripeSecretKey,
+		"STRIPE_WEBHOOK_SECRET":     &c.Billing.StripeWebhookSecret,
+		"STRIPE_PREMIUM_PRICE_ID":   &c.Billing.PremiumPriceID,
+		"BILLING_SUCCESS_URL":       &c.Billing.SuccessURL,
+		"BILLING_CANCEL_URL":        &c.Billing.CancelURL,
+		"BILLING_PORTAL_RETURN_URL": &c.Billing.PortalReturnURL,
+	} {
+		if raw, ok := lookupEnv(name); ok {
+			*value = raw
+		}
+	}
+
+	if value, ok := lookupEnv("ENCRYPTION_KEY_ID"); ok {
+		c.Encryption.KeyID = value
+	}
+	// ENCRYPTION_KEYS is a comma separated list of id:base64key pairs
+	if value, ok := lookupEnv("ENCRYPTION_KEYS"); ok {
+		c.Encryption.Keys = make(map[string]string)
+		for _, pair := range splitList(value) {
+			keyID, key, found := strings.Cut(pair, ":")
+			if !found || keyID == "" || key == "" {
+				return fmt.Errorf("invalid ENCRYPTION_KEYS entry %q (use id:base64key)", pair)
+			}
+			c.Encryption.Keys[keyID] = key
+		}
+	}
+
+	for name, value := range map[string]*string{
+		"SMTP_HOST":              &c.Email.Host,
+		"SMTP_USERNAME":          &c.Email.Username,
+		"SMTP_PASSWORD":          &c.Email.Password,
+		"EMAIL_FROM":             &c.Email.From,
+		"DIGEST_UNSUBSCRIBE_URL": &c.Email.UnsubscribeURL,
+	} {
+		if raw, ok := lookupEnv(name); ok {
+			*value = raw
+		}
+	}
+	if value, ok := lookupEnv("SMTP_PORT"); ok {
+		port, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid SMTP_PORT %q: %w", value, err)
+		}
+		c.Email.Port = port
+	}
+	if value, ok := lookupEnv("DIGEST_INTERVAL"); ok {
+		if err := c.Email.DigestInterval.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("invalid DIGEST_INTERVAL %q: %w", value, err)
+		}
+	}
+
+	for name, value := range map[string]*string{
+		"FCM_BASE_URL":         &c.Push.FCMBaseURL,
+		"FCM_CREDENTIALS_FILE": &c.Push.FCMCredentialsFile,
+		"APNS_BASE_URL":        &c.Push.APNsBaseURL,
+		"APNS_KEY_FILE":        &c.Push.APNsKeyFile,
+		"APNS_KEY_ID":          &c.Push.APNsKeyID,
+		"APNS_TEAM_ID":         &c.Push.APNsTeamID,
+		"APNS_TOPIC":           &c.Push.APNsTopic,
+	} {
+		if raw, ok := lookupEnv(name); ok {
+			*value = raw
+		}
+	}
+
+	return nil
+}
+
+// applyEnv overrides the AI thresholds; the allocation tables can only be
+// changed in the config file
+func (a *AIConfig) applyEnv() error {
+	for name, value := range map[string]*float64{
+		"AI_TREND_CUTOFF":             &a.TrendCutoff,
+		"AI_VOLATILITY_MEDIUM_CUTOFF": &a.VolatilityMediumCutoff,
+		"AI_VOLATILITY_HIGH_CUTOFF":   &a.VolatilityHighCutoff,
+		"AI_INVESTABLE_SHARE":         &a.InvestableShare,
+		"AI_MODERATE_RISK_SCORE":      &a.ModerateRiskScore,
+		"AI_HIGH_RISK_SCORE":          &a.HighRiskScore,
+	} {
+		raw, ok := lookupEnv(name)
+		if !ok {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q: %w", name, raw, err)
+		}
+		*value = parsed
+	}
+	return nil
+}
+
+// applyEnv overrides the plan's limits from the variables with the prefix,
+// such as PLAN_FREE_AI_REQUESTS_PER_DAY
+func (p *PlanConfig) applyEnv(prefix string) error {
+	for name, value := range map[string]*int{
+		prefix + "AI_REQUESTS_PER_DAY":    &p.AIRequestsPerDay,
+		prefix + "BANK_REFRESHES_PER_DAY": &p.BankRefreshesPerDay,
+		prefix + "EXPORT_MAX_ROWS":        &p.ExportMaxRows,
+	} {
+		raw, ok := lookupEnv(name)
+		if !ok {
+			continue
+		}
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q: %w", name, raw, err)
+		}
+		*value = parsed
+	}
+	if value, ok := lookupEnv(prefix + "BANK_SYNC_INTERVAL"); ok {
+		if err := p.BankSyncInterval.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("invalid %sBANK_SYNC_INTERVAL %q: %w", prefix, value, err)
+		}
+	}
+	// The FEATURES variable is a comma separated list, empty for none
+	if value, ok := lookupEnv(prefix + "FEATURES"); ok {
+		p.Features = splitList(value)
+	}
+	return nil
+}
+
+func (a *AuthConfig) applyEnv() error {
+	if value, ok := lookupEnv("JWT_SECRET"); ok {
+		a.JWTSecret = value
+	}
+	if value, ok := lookupEnv("JWT_KEY_ID"); ok {
+		a.JWTKeyID = value
+	}
+	// JWT_PREVIOUS_KEYS is a comma separated list of kid:secret pairs
+	if value, ok := lookupEnv("JWT_PREVIOUS_KEYS"); ok {
+		a.JWTPreviousKeys = make(map[string]string)
+		for _, pair := range splitList(value) {
+			keyID, secret, found := strings.Cut(pair, ":")
+			if !found || keyID == "" || secret == "" {
+				return fmt.Errorf("invalid JWT_PREVIOUS_KEYS entry %q (use kid:secret)", pair)
+			}
+			a.JWTPreviousKeys[keyID] = secret
+		}
+	}
+	if value, ok := lookupEnv("JWT_ALGORITHM"); ok {
+		a.JWTAlgorithm = value
+	}
+	if value, ok := lookupEnv("JWT_ISSUER"); ok {
+		a.JWTIssuer = value
+	}
+	if value, ok := lookupEnv("JWT_AUDIENCE"); ok {
+		a.JWTAudience = value
+	}
+	if value, ok := lookupEnv("JWT_EXPIRY"); ok {
+		if err := a.TokenExpiry.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("invalid JWT_EXPIRY %q: %w", value, err)
+		}
+	}
+	if value, ok := lookupEnv("REFRESH_TOKEN_EXPIRY"); ok {
+		if err := a.RefreshTokenExpiry.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("invalid REFRESH_TOKEN_EXPIRY %q: %w", value, err)
+		}
+	}
+	if value, ok := lookupEnv("ADMIN_USER_IDS"); ok {
+		a.AdminUserIDs = nil
+		for _, item := range splitList(value) {
+			id, err := strconv.ParseUint(item, 10, 32)
+			if err != nil || id == 0 {
+				return fmt.Errorf("invalid ADMIN_USER_IDS entry %q", item)
+			}
+			a.AdminUserIDs = append(a.AdminUserIDs, uint(id))
+		}
+	}
+	return nil
+}
+
+// Validate checks that the configuration can be used to start the application
+func (c *Config) Validate() error {
+	if c.Server.Port <= 0 || c.Server.Port > 65535 {
+		return fmt.Errorf("invalid server port %d", c.Server.Port)
+	}
+	if c.Server.GRPCPort < 0 || c.Server.GRPCPort > 65535 {
+		return fmt.Errorf("invalid gRPC port %d", c.Server.GRPCPort)
+	}
+	if c.Server.GRPCPort == c.Server.Port {
+		return fmt.Errorf("gRPC port %d is already used by the HTTP server", c.Server.GRPCPort)
+	}
+	if c.Server.Environment != EnvironmentDevelopment && c.Server.Environment != EnvironmentProduction {
+		return fmt.Errorf("invalid environment %q (use development or production)", c.Server.Environment)
+	}
+	if c.Server.MaxBodyBytes <= 0 {
+		return errors.New("max body bytes must be positive")
+	}
+	for _, proxy := range c.Server.TrustedProxies {
+		if _, err := netip.ParsePrefix(proxy); err != nil {
+			if _, err := netip.ParseAddr(proxy); err != nil {
+				return fmt.Errorf("invalid trusted proxy %q (use an IP address or CIDR range)", proxy)
+			}
+		}
+	}
+	if c.Database.DSN == "" {
+		return errors.New("database DSN is required")
+	}
+	if c.Database.QueryTimeout < 0 {
+		return errors.New("database query timeout cannot be negative")
+	}
+	if c.Database.BusyTimeout < 0 {
+		return errors.New("database busy timeout cannot be negative")
+	}
+	if len(c.Database.Replicas) > 0 {
+		if c.Database.ReplicaCheckInterval <= 0 {
+			return errors.New("database replica check interval must be positive")
+		}
+		if c.Database.ReplicaMaxLag <= c.Database.ReplicaCheckInterval {
+			return errors.New("database replica max lag must be longer than the replica check interval")
+		}
+	}
+	if c.Auth.RefreshTokenExpiry <= 0 {
+		return errors.New("refresh token expiry must be positive")
+	}
+	if c.Market.RequestTimeout <= 0 {
+		return errors.New("market request timeout must be positive")
+	}
+	if c.Market.PriceAlertInterval <= 0 {
+		return errors.New("price alert interval must be positive")
+	}
+	if c.Market.MaxConcurrentRequests < 1 {
+		return errors.New("market max concurrent requests must be at least 1")
+	}
+	if c.Market.CoinGeckoRateInterval < 0 || c.Market.AlphaVantageRateInterval < 0 {
+		return errors.New("market rate intervals cannot be negative")
+	}
+	if c.Market.CoinGeckoTimeout < 0 || c.Market.AlphaVantageTimeout < 0 {
+		return errors.New("market provider timeouts cannot be negative")
+	}
+	if c.Market.MaxRetries < 0 || c.Market.RetryBackoff < 0 {
+		return errors.New("market retries and backoff cannot be negative")
+	}
+	if c.Market.BreakerThreshold < 0 || c.Market.BreakerCooldown < 0 {
+		return errors.New("market breaker threshold and cooldown cannot be negative")
+	}
+	if c.BankSync.Interval <= 0 {
+		return errors.New("bank sync interval must be positive")
+	}
+	if c.Wallets.RefreshInterval <= 0 {
+		return errors.New("wallet refresh interval must be positive")
+	}
+	if c.Wallets.BTCBaseURL == "" || c.Wallets.ETHBaseURL == "" {
+		return errors.New("wallet btc and eth base urls are required")
+	}
+	if c.FX.ProviderURL == "" {
+		return errors.New("fx provider url is required")
+	}
+	if c.FX.FallbackWindow < 0 {
+		return errors.New("fx fallback window cannot be negative")
+	}
+	if c.Jobs.Workers <= 0 || c.Jobs.PollInterval <= 0 {
+		return errors.New("job workers and poll interval must be positive")
+	}
+	if c.Jobs.MaxAttempts <= 0 || c.Jobs.RetryBackoff <= 0 {
+		return errors.New("job attempts and retry backoff must be positive")
+	}
+	if c.Cache.InsightsTTL <= 0 {
+		return errors.New("insights cache TTL must be positive")
+	}
+	if c.Cache.AnalyticsTTL <= 0 {
+		return errors.New("analytics cache TTL must be positive")
+	}
+	if c.Cache.ClientMaxAge < 0 {
+		return errors.New("client cache max age cannot be negative")
+	}
+	if c.Retention.TrashPeriod <= 0 {
+		return errors.New("trash retention period must be positive")
+	}
+	if c.Retention.ArchiveAfterYears < 0 || c.Retention.ArchiveAfterYears > 100 {
+		return errors.New("archive after years must be between 0 and 100")
+	}
+	if c.Advisor.MaxSimulationIterations < 1 || c.Advisor.MaxSimulationIterations > 100000 {
+		return errors.New("advisor max simulation iterations must be between 1 and 100000")
+	}
+	if err := c.AI.validate(); err != nil {
+		return err
+	}
+	if c.Categorizer.MinConfidence < 0 || c.Categorizer.MinConfidence > 1 {
+		return errors.New("categorizer min confidence must be between 0 and 1")
+	}
+	if c.Categorizer.MinSamples < 2 || c.Categorizer.RetrainInterval <= 0 {
+		return errors.New("categorizer needs at least 2 samples and a positive retrain interval")
+	}
+	if c.LLM.Enabled() && (c.LLM.BaseURL == "" || c.LLM.Timeout <= 0) {
+		return errors.New("the LLM needs a base URL and a positive timeout")
+	}
+	if c.Console.SessionTimeout < 0 {
+		return errors.New("console session timeout cannot be negative")
+	}
+	if c.Demo.Enabled && c.Demo.Email == "" {
+		return errors.New("demo mode needs a demo user email")
+	}
+	if c.Public.RateLimit < 1 || c.Public.RateWindow <= 0 {
+		return errors.New("public rate limit must be at least 1 request per positive window")
+	}
+	if c.Public.CacheTTL <= 0 {
+		return errors.New("public cache TTL must be positive")
+	}
+	for name, plan := range map[string]PlanConfig{"free": c.Plans.Free, "premium": c.Plans.Premium} {
+		if plan.AIRequestsPerDay < 0 || plan.BankRefreshesPerDay < 0 || plan.ExportMaxRows < 0 || plan.BankSyncInterval < 0 {
+			return fmt.Errorf("%s plan limits cannot be negative", name)
+		}
+		for _, feature := range plan.Features {
+			if !slices.Contains(PlanFeatures, feature) {
+				return fmt.Errorf("%s plan feature %q must be one of %s", name, feature, strings.Join(PlanFeatures, ", "))
+			}
+		}
+	}
+	switch c.Storage.Driver {
+	case StorageDriverLocal:
+		if c.Storage.LocalDir == "" {
+			return errors.New("local storage needs a directory")
+		}
+	case StorageDriverS3:
+		if c.Storage.S3Bucket == "" || c.Storage.S3Region == "" || c.Storage.S3AccessKeyID == "" || c.Storage.S3SecretAccessKey == "" {
+			return errors.New("s3 storage needs a bucket, region, access key ID and secret access key")
+		}
+	default:
+		return fmt.Errorf("invalid storage driver %q (use local or s3)", c.Storage.Driver)
+	}
+	if c.Billing.Enabled() && (c.Billing.StripeBaseURL == "" || c.Billing.StripeWebhookSecret == "" || c.Billing.PremiumPriceID == "") {
+		return errors.New("billing needs the stripe base url, webhook secret and premium price ID")
+	}
+	if c.Billing.Enabled() && (c.Billing.SuccessURL == "" || c.Billing.CancelURL == "" || c.Billing.PortalReturnURL == "") {
+		return errors.New("billing needs the checkout success and cancel urls and the portal return url")
+	}
+	if c.Encryption.Enabled() {
+		if _, ok := c.Encryption.Keys[c.Encryption.KeyID]; !ok {
+			return fmt.Errorf("encryption key ID %q is not one of the configured keys", c.Encryption.KeyID)
+		}
+	} else if c.Encryption.KeyID != "" {
+		return errors.New("encryption key ID is set but no encryption keys are configured")
+	}
+	if c.Email.Enabled() {
+		if c.Email.From == "" {
+			return errors.New("email needs a from address")
+		}
+		if c.Email.Port <= 0 || c.Email.DigestInterval <= 0 {
+			return errors.New("email port and digest interval must be positive")
+		}
+	}
+	if c.Push.APNsKeyFile != "" && (c.Push.APNsKeyID == "" || c.Push.APNsTeamID == "" || c.Push.APNsTopic == "") {
+		return errors.New("apns needs a key ID, team ID and topic")
+	}
+	return nil
+}
+
+// Address returns the listen address for the HTTP server
+func (s ServerConfig) Address() string {
+	return fmt.Sprintf(":%d", s.Port)
+}
+
+// GRPCAddress returns the listen address for the gRPC server
+func (s ServerConfig) GRPCAddress() string {
+	return fmt.Sprintf(":%d", s.GRPCPort)
+}
+
+func (a AIConfig) validate() error {
+	if a.TrendCutoff <= 0 {
+		return errors.New("AI trend cutoff must be positive")
+	}
+	if a.VolatilityMediumCutoff <= 0 || a.VolatilityHighCutoff <= a.VolatilityMediumCutoff {
+		return errors.New("AI volatility cutoffs must be positive, the medium one below the high one")
+	}
+	if a.InvestableShare <= 0 || a.InvestableShare > 1 {
+		return errors.New("AI investable share must be above 0 and at most 1")
+	}
+	if a.ModerateRiskScore <= 0 || a.HighRiskScore <= a.ModerateRiskScore || a.HighRiskScore > 1 {
+		return errors.New("AI risk scores must be between 0 and 1, the moderate one below the high one")
+	}
+	defaults := Default().AI
+	for table, rows := range map[string]map[string][]AllocationShare{
+		"risk": a.RiskAllocations, "advice": a.AdviceAllocations,
+	} {
+		required := defaults.RiskAllocations
+		if table == "advice" {
+			required = defaults.AdviceAllocations
+		}
+		for row := range required {
+			if _, ok := rows[row]; !ok {
+				return fmt.Errorf("AI %s allocations need a %q row", table, row)
+			}
+		}
+		for row, shares := range rows {
+			var total float64
+			for _, share := range shares {
+				if share.Asset == "" || share.Share < 0 {
+					return fmt.Errorf("AI %s allocation %q needs assets with shares of at least 0", table, row)
+				}
+				total += share.Share
+			}
+			if math.Abs(total-1) > 1e-6 {
+				return fmt.Errorf("AI %s allocation %q shares must sum to 1, not %g", table, row, total)
+			}
+		}
+	}
+	return nil
+}
+
+// IsProduction reports whether the server runs in the production environment
+func (s ServerConfig) IsProduction() bool {
+	return s.Environment == EnvironmentProduction
+}
+
+// AllowsAllOrigins reports whether CORS is open to any origin
+func (s ServerConfig) AllowsAllOrigins() bool {
+	for _, origin := range s.CORSOrigins {
+		if origin == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupEnv returns the first non-empty variable among names
+func lookupEnv(names ...string) (string, bool) {
+	for _, name := range names {
+		if value := strings.TrimSpace(os.Getenv(name)); value != "" {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
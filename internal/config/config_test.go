@@ -0,0 +1,661 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	cfg, err := Load("")
+	require.NoError(t, err)
+
+	assert.Equal(t, 8080, cfg.Server.Port)
+	assert.Equal(t, ":8080", cfg.Server.Address())
+	assert.Equal(t, ":50051", cfg.Server.GRPCAddress())
+	assert.True(t, cfg.Server.AllowsAllOrigins())
+	assert.False(t, cfg.Server.IsProduction())
+	assert.Equal(t, int64(1<<20), cfg.Server.MaxBodyBytes)
+	assert.Equal(t, "finance.db", cfg.Database.DSN)
+	assert.Equal(t, 5*time.Second, cfg.Database.QueryTimeout.Std())
+	assert.False(t, cfg.Database.MigrateOnStart)
+	assert.Equal(t, 5*time.Second, cfg.Database.BusyTimeout.Std())
+	assert.Equal(t, 30*24*time.Hour, cfg.Auth.RefreshTokenExpiry.Std())
+	assert.Equal(t, "https://api.coingecko.com/api/v3", cfg.Market.CoinGeckoBaseURL)
+	assert.Equal(t, 15*time.Second, cfg.Market.RequestTimeout.Std())
+	assert.Equal(t, 5*time.Minute, cfg.Market.PriceAlertInterval.Std())
+	assert.Equal(t, 4, cfg.Market.MaxConcurrentRequests)
+	assert.Zero(t, cfg.Market.CoinGeckoRateInterval)
+	assert.Equal(t, 200*time.Millisecond, cfg.Market.AlphaVantageRateInterval.Std())
+	assert.Equal(t, 2, cfg.Market.MaxRetries)
+	assert.Equal(t, 250*time.Millisecond, cfg.Market.RetryBackoff.Std())
+	assert.Equal(t, 5, cfg.Market.BreakerThreshold)
+	assert.Equal(t, 30*time.Second, cfg.Market.BreakerCooldown.Std())
+	assert.Equal(t, 6*time.Hour, cfg.BankSync.Interval.Std())
+	assert.Equal(t, "https://sandbox.plaid.com", cfg.BankSync.PlaidBaseURL)
+	assert.Empty(t, cfg.BankSync.PlaidClientID)
+	assert.Equal(t, 6*time.Hour, cfg.Wallets.RefreshInterval.Std())
+	assert.Equal(t, "https://blockstream.info/api", cfg.Wallets.BTCBaseURL)
+	assert.Equal(t, "https://eth.blockscout.com", cfg.Wallets.ETHBaseURL)
+	assert.Equal(t, "https://api.frankfurter.app", cfg.FX.ProviderURL)
+	assert.Equal(t, 7*24*time.Hour, cfg.FX.FallbackWindow.Std())
+	assert.Equal(t, 4, cfg.Jobs.Workers)
+	assert.Equal(t, 5, cfg.Jobs.MaxAttempts)
+	assert.Equal(t, time.Hour, cfg.Cache.InsightsTTL.Std())
+	assert.Equal(t, 5*time.Minute, cfg.Cache.AnalyticsTTL.Std())
+	assert.Equal(t, 30*time.Second, cfg.Cache.ClientMaxAge.Std())
+	assert.Equal(t, 30*24*time.Hour, cfg.Retention.TrashPeriod.Std())
+	assert.Zero(t, cfg.Retention.ArchiveAfterYears)
+	assert.Empty(t, cfg.Advisor.RiskQuestionnaireFile)
+	assert.Equal(t, 10000, cfg.Advisor.MaxSimulationIterations)
+	assert.False(t, cfg.Categorizer.Enabled)
+	assert.Equal(t, 0.6, cfg.Categorizer.MinConfidence)
+	assert.Equal(t, 20, cfg.Categorizer.MinSamples)
+	assert.Equal(t, 24*time.Hour, cfg.Categorizer.RetrainInterval.Std())
+	assert.False(t, cfg.LLM.Enabled())
+	assert.Equal(t, "https://api.openai.com/v1", cfg.LLM.BaseURL)
+	assert.Equal(t, 30*time.Second, cfg.LLM.Timeout.Std())
+	assert.Equal(t, 15*time.Minute, cfg.Console.SessionTimeout.Std())
+	assert.Equal(t, 2.0, cfg.AI.TrendCutoff)
+	assert.Equal(t, 0.2, cfg.AI.InvestableShare)
+	assert.Equal(t, []AllocationShare{{"SPY", 0.5}, {"BTC", 0.5}}, cfg.AI.AdviceAllocations["moderate"])
+	assert.Len(t, cfg.AI.RiskAllocations, 3)
+}
+
+func TestLoad_File(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{
+			name: "yaml",
+			file: "config.yaml",
+			content: `
+server:
+  port: 9090
+  cors_origins: ["https://app.example.com"]
+  environment: production
+database:
+  dsn: /data/finance.db
+market:
+  alpha_vantage_api_key: file-key
+  request_timeout: 5s
+cache:
+  insights_ttl: 30m
+`,
+		},
+		{
+			name: "toml",
+			file: "config.toml",
+			content: `
+[server]
+port = 9090
+cors_origins = ["https://app.example.com"]
+environment = "production"
+
+[database]
+dsn = "/data/finance.db"
+
+[market]
+alpha_vantage_api_key = "file-key"
+request_timeout = "5s"
+
+[cache]
+insights_ttl = "30m"
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := Load(writeConfigFile(t, tt.file, tt.content))
+			require.NoError(t, err)
+
+			assert.Equal(t, 9090, cfg.Server.Port)
+			assert.Equal(t, []string{"https://app.example.com"}, cfg.Server.CORSOrigins)
+			assert.False(t, cfg.Server.AllowsAllOrigins())
+			assert.True(t, cfg.Server.IsProduction())
+			assert.Equal(t, "/data/finance.db", cfg.Database.DSN)
+			assert.Equal(t, "file-key", cfg.Market.AlphaVantageAPIKey)
+			assert.Equal(t, 5*time.Second, cfg.Market.RequestTimeout.Std())
+			assert.Equal(t, 30*time.Minute, cfg.Cache.InsightsTTL.Std())
+			// Values missing from the file keep their defaults
+			assert.Equal(t, "https://www.alphavantage.co", cfg.Market.AlphaVantageBaseURL)
+		})
+	}
+}
+
+func TestLoad_EnvOverridesFile(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", "server:\n  port: 9090\ndatabase:\n  dsn: file.db\n")
+
+	t.Setenv("PORT", "7070")
+	t.Setenv("GRPC_PORT", "7071")
+	t.Setenv("DATABASE_URL", "sqlite://env.db")
+	t.Setenv("JWT_SECRET", "env-secret")
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://a.example.com, https://b.example.com")
+	t.Setenv("APP_ENV", "Development")
+	t.Setenv("MAX_BODY_BYTES", "65536")
+	t.Setenv("INSIGHTS_CACHE_TTL", "10m")
+	t.Setenv("DB_QUERY_TIMEOUT", "750ms")
+	t.Setenv("DB_MIGRATE_ON_START", "true")
+	t.Setenv("DB_BUSY_TIMEOUT", "2s")
+	t.Setenv("DB_REPLICAS", "sqlite://replica-1.db, replica-2.db")
+	t.Setenv("DB_REPLICA_MAX_LAG", "10s")
+	t.Setenv("TRASH_RETENTION", "168h")
+	t.Setenv("ARCHIVE_AFTER_YEARS", "7")
+	t.Setenv("PRICE_ALERT_INTERVAL", "1m")
+	t.Setenv("ANALYTICS_CACHE_TTL", "2m")
+	t.Setenv("CLIENT_CACHE_MAX_AGE", "0s")
+	t.Setenv("MARKET_MAX_CONCURRENCY", "8")
+	t.Setenv("ALPHA_VANTAGE_RATE_INTERVAL", "1s")
+	t.Setenv("COINGECKO_TIMEOUT", "3s")
+	t.Setenv("MARKET_MAX_RETRIES", "0")
+	t.Setenv("MARKET_BREAKER_COOLDOWN", "1m")
+	t.Setenv("RISK_QUESTIONNAIRE_FILE", "/etc/finance/questionnaire.yaml")
+	t.Setenv("ADVISOR_MAX_SIMULATION_ITERATIONS", "2500")
+	t.Setenv("CONSOLE_SESSION_TIMEOUT", "0s")
+	t.Setenv("BANK_SYNC_INTERVAL", "30m")
+	t.Setenv("PLAID_CLIENT_ID", "plaid-client")
+	t.Setenv("GOCARDLESS_SECRET_KEY", "gocardless-key")
+	t.Setenv("WALLET_REFRESH_INTERVAL", "1h")
+	t.Setenv("WALLET_ETH_BASE_URL", "https://blockscout.example")
+	t.Setenv("FX_FALLBACK_WINDOW", "72h")
+	t.Setenv("JOB_WORKERS", "2")
+	t.Setenv("JOB_RETRY_BACKOFF", "1m")
+	t.Setenv("DEMO_MODE", "true")
+	t.Setenv("PUBLIC_RATE_LIMIT", "10")
+	t.Setenv("PUBLIC_CACHE_TTL", "5m")
+	t.Setenv("PLAN_FREE_AI_REQUESTS_PER_DAY", "5")
+	t.Setenv("PLAN_PREMIUM_BANK_SYNC_INTERVAL", "1h")
+	t.Setenv("PLAN_FREE_FEATURES", "bank_sync, scheduled_reports")
+	t.Setenv("STRIPE_SECRET_KEY", "sk_test")
+	t.Setenv("STRIPE_WEBHOOK_SECRET", "whsec_test")
+	t.Setenv("STRIPE_PREMIUM_PRICE_ID", "price_premium")
+	t.Setenv("BILLING_SUCCESS_URL", "https://app.example.com/billing/done")
+	t.Setenv("BILLING_CANCEL_URL", "https://app.example.com/pricing")
+	t.Setenv("BILLING_PORTAL_RETURN_URL", "https://app.example.com/settings")
+	t.Setenv("STORAGE_DRIVER", "s3")
+	t.Setenv("S3_ENDPOINT", "http://minio:9000")
+	t.Setenv("S3_BUCKET", "finance")
+	t.Setenv("S3_ACCESS_KEY_ID", "minio")
+	t.Setenv("S3_SECRET_ACCESS_KEY", "minio-secret")
+	t.Setenv("S3_PATH_STYLE", "true")
+	t.Setenv("CATEGORIZER_ENABLED", "true")
+	t.Setenv("CATEGORIZER_MIN_CONFIDENCE", "0.8")
+	t.Setenv("CATEGORIZER_MIN_SAMPLES", "50")
+	t.Setenv("CATEGORIZER_RETRAIN_INTERVAL", "6h")
+	t.Setenv("LLM_BASE_URL", "http://localhost:11434/v1")
+	t.Setenv("LLM_MODEL", "llama3.1")
+	t.Setenv("LLM_TIMEOUT", "1m")
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, 7070, cfg.Server.Port)
+	assert.Equal(t, 7071, cfg.Server.GRPCPort)
+	assert.Equal(t, "env.db", cfg.Database.DSN)
+	assert.Equal(t, "env-secret", cfg.Auth.JWTSecret)
+	assert.Equal(t, []string{"https://a.example.com", "https://b.example.com"}, cfg.Server.CORSOrigins)
+	assert.Equal(t, EnvironmentDevelopment, cfg.Server.Environment)
+	assert.Equal(t, int64(65536), cfg.Server.MaxBodyBytes)
+	assert.Equal(t, 10*time.Minute, cfg.Cache.InsightsTTL.Std())
+	assert.Equal(t, 750*time.Millisecond, cfg.Database.QueryTimeout.Std())
+	assert.True(t, cfg.Database.MigrateOnStart)
+	assert.Equal(t, 2*time.Second, cfg.Database.BusyTimeout.Std())
+	assert.Equal(t, []string{"replica-1.db", "replica-2.db"}, cfg.Database.Replicas)
+	assert.Equal(t, 10*time.Second, cfg.Database.ReplicaMaxLag.Std())
+	assert.Equal(t, 7*24*time.Hour, cfg.Retention.TrashPeriod.Std())
+	assert.Equal(t, 7, cfg.Retention.ArchiveAfterYears)
+	assert.Equal(t, time.Minute, cfg.Market.PriceAlertInterval.Std())
+	assert.Equal(t, 2*time.Minute, cfg.Cache.AnalyticsTTL.Std())
+	assert.Zero(t, cfg.Cache.ClientMaxAge)
+	assert.Equal(t, 8, cfg.Market.MaxConcurrentRequests)
+	assert.Equal(t, time.Second, cfg.Market.AlphaVantageRateInterval.Std())
+	assert.Equal(t, 3*time.Second, cfg.Market.CoinGeckoTimeout.Std())
+	assert.Zero(t, cfg.Market.MaxRetries)
+	assert.Equal(t, time.Minute, cfg.Market.BreakerCooldown.Std())
+	assert.Equal(t, "/etc/finance/questionnaire.yaml", cfg.Advisor.RiskQuestionnaireFile)
+	assert.Equal(t, 2500, cfg.Advisor.MaxSimulationIterations)
+	assert.Zero(t, cfg.Console.SessionTimeout)
+	assert.Equal(t, 30*time.Minute, cfg.BankSync.Interval.Std())
+	assert.Equal(t, "plaid-client", cfg.BankSync.PlaidClientID)
+	assert.Equal(t, "gocardless-key", cfg.BankSync.GoCardlessSecretKey)
+	assert.Equal(t, time.Hour, cfg.Wallets.RefreshInterval.Std())
+	assert.Equal(t, "https://blockscout.example", cfg.Wallets.ETHBaseURL)
+	assert.Equal(t, 72*time.Hour, cfg.FX.FallbackWindow.Std())
+	assert.Equal(t, 2, cfg.Jobs.Workers)
+	assert.Equal(t, time.Minute, cfg.Jobs.RetryBackoff.Std())
+	assert.True(t, cfg.Demo.Enabled)
+	assert.Equal(t, "demo@go-finance-advisor.local", cfg.Demo.Email)
+	assert.Equal(t, 10, cfg.Public.RateLimit)
+	assert.Equal(t, time.Minute, cfg.Public.RateWindow.Std())
+	assert.Equal(t, 5*time.Minute, cfg.Public.CacheTTL.Std())
+	assert.Equal(t, 5, cfg.Plans.Free.AIRequestsPerDay)
+	assert.Equal(t, 1000, cfg.Plans.Free.ExportMaxRows)
+	assert.Equal(t, time.Hour, cfg.Plans.Premium.BankSyncInterval.Std())
+	assert.Equal(t, []string{"bank_sync", "scheduled_reports"}, cfg.Plans.Free.Features)
+	assert.Equal(t, PlanFeatures, cfg.Plans.Premium.Features)
+	assert.True(t, cfg.Billing.Enabled())
+	assert.Equal(t, "https://api.stripe.com", cfg.Billing.StripeBaseURL)
+	assert.Equal(t, "price_premium", cfg.Billing.PremiumPriceID)
+	assert.Equal(t, "https://app.example.com/settings", cfg.Billing.PortalReturnURL)
+	assert.Equal(t, StorageDriverS3, cfg.Storage.Driver)
+	assert.Equal(t, "us-east-1", cfg.Storage.S3Region)
+	assert.Equal(t, "finance", cfg.Storage.S3Bucket)
+	assert.True(t, cfg.Storage.S3PathStyle)
+	assert.True(t, cfg.Categorizer.Enabled)
+	assert.Equal(t, 0.8, cfg.Categorizer.MinConfidence)
+	assert.Equal(t, 50, cfg.Categorizer.MinSamples)
+	assert.Equal(t, 6*time.Hour, cfg.Categorizer.RetrainInterval.Std())
+	assert.True(t, cfg.LLM.Enabled())
+	assert.Equal(t, "http://localhost:11434/v1", cfg.LLM.BaseURL)
+	assert.Equal(t, "llama3.1", cfg.LLM.Model)
+	assert.Equal(t, time.Minute, cfg.LLM.Timeout.Std())
+}
+
+func TestLoad_LegacyEnvNames(t *testing.T) {
+	t.Setenv("APP_PORT", "8181")
+	t.Setenv("DB_PATH", "/data/legacy.db")
+
+	cfg, err := Load("")
+	require.NoError(t, err)
+
+	assert.Equal(t, 8181, cfg.Server.Port)
+	assert.Equal(t, "/data/legacy.db", cfg.Database.DSN)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	t.Run("missing file", func(t *testing.T) {
+		_, err := Load(filepath.Join(t.TempDir(), "missing.yaml"))
+		assert.Error(t, err)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		_, err := Load(writeConfigFile(t, "config.json", "{}"))
+		assert.ErrorContains(t, err, "unsupported config file format")
+	})
+
+	t.Run("invalid port", func(t *testing.T) {
+		t.Setenv("PORT", "eighty")
+		_, err := Load("")
+		assert.Error(t, err)
+	})
+
+	t.Run("out of range port", func(t *testing.T) {
+		_, err := Load(writeConfigFile(t, "config.yaml", "server:\n  port: 70000\n"))
+		assert.ErrorContains(t, err, "invalid server port")
+	})
+
+	t.Run("gRPC port shared with HTTP", func(t *testing.T) {
+		_, err := Load(writeConfigFile(t, "config.yaml", "server:\n  port: 9000\n  grpc_port: 9000\n"))
+		assert.ErrorContains(t, err, "already used by the HTTP server")
+	})
+
+	t.Run("unknown environment", func(t *testing.T) {
+		t.Setenv("APP_ENV", "staging")
+		_, err := Load("")
+		assert.ErrorContains(t, err, "invalid environment")
+	})
+
+	t.Run("no body size limit", func(t *testing.T) {
+		t.Setenv("MAX_BODY_BYTES", "0")
+		_, err := Load("")
+		assert.ErrorContains(t, err, "max body bytes")
+	})
+
+	t.Run("invalid boolean", func(t *testing.T) {
+		t.Setenv("DB_MIGRATE_ON_START", "sometimes")
+		_, err := Load("")
+		assert.ErrorContains(t, err, "DB_MIGRATE_ON_START")
+	})
+
+	t.Run("invalid duration", func(t *testing.T) {
+		_, err := Load(writeConfigFile(t, "config.toml", "[cache]\ninsights_ttl = \"soon\"\n"))
+		assert.Error(t, err)
+	})
+
+	t.Run("negative busy timeout", func(t *testing.T) {
+		t.Setenv("DB_BUSY_TIMEOUT", "-1s")
+		_, err := Load("")
+		assert.ErrorContains(t, err, "busy timeout")
+	})
+
+	t.Run("replica lag within the check interval", func(t *testing.T) {
+		t.Setenv("DB_REPLICAS", "replica.db")
+		t.Setenv("DB_REPLICA_MAX_LAG", "1s")
+		_, err := Load("")
+		assert.ErrorContains(t, err, "replica max lag")
+	})
+
+	t.Run("negative client cache max age", func(t *testing.T) {
+		t.Setenv("CLIENT_CACHE_MAX_AGE", "-1s")
+		_, err := Load("")
+		assert.ErrorContains(t, err, "client cache max age")
+	})
+
+	t.Run("negative archive age", func(t *testing.T) {
+		t.Setenv("ARCHIVE_AFTER_YEARS", "-1")
+		_, err := Load("")
+		assert.ErrorContains(t, err, "archive after years")
+	})
+
+	t.Run("no market concurrency", func(t *testing.T) {
+		t.Setenv("MARKET_MAX_CONCURRENCY", "0")
+		_, err := Load("")
+		assert.ErrorContains(t, err, "market max concurrent requests")
+	})
+
+	t.Run("no bank sync interval", func(t *testing.T) {
+		t.Setenv("BANK_SYNC_INTERVAL", "0s")
+		_, err := Load("")
+		assert.ErrorContains(t, err, "bank sync interval")
+	})
+
+	t.Run("no wallet refresh interval", func(t *testing.T) {
+		t.Setenv("WALLET_REFRESH_INTERVAL", "0s")
+		_, err := Load("")
+		assert.ErrorContains(t, err, "wallet refresh interval")
+	})
+
+	t.Run("negative fx fallback window", func(t *testing.T) {
+		t.Setenv("FX_FALLBACK_WINDOW", "-1h")
+		_, err := Load("")
+		assert.ErrorContains(t, err, "fx fallback window")
+	})
+
+	t.Run("no job workers", func(t *testing.T) {
+		t.Setenv("JOB_WORKERS", "0")
+		_, err := Load("")
+		assert.ErrorContains(t, err, "job workers")
+	})
+
+	t.Run("demo without a user", func(t *testing.T) {
+		_, err := Load(writeConfigFile(t, "config.yaml", "demo:\n  enabled: true\n  email: \"\"\n"))
+		assert.ErrorContains(t, err, "demo user email")
+	})
+
+	t.Run("no public rate limit", func(t *testing.T) {
+		t.Setenv("PUBLIC_RATE_LIMIT", "0")
+		_, err := Load("")
+		assert.ErrorContains(t, err, "public rate limit")
+	})
+
+	t.Run("negative plan limit", func(t *testing.T) {
+		t.Setenv("PLAN_PREMIUM_EXPORT_MAX_ROWS", "-1")
+		_, err := Load("")
+		assert.ErrorContains(t, err, "premium plan limits")
+	})
+
+	t.Run("unknown plan feature", func(t *testing.T) {
+		t.Setenv("PLAN_FREE_FEATURES", "bank_sync,teleport")
+		_, err := Load("")
+		assert.ErrorContains(t, err, `free plan feature "teleport"`)
+	})
+
+	t.Run("unknown storage driver", func(t *testing.T) {
+		t.Setenv("STORAGE_DRIVER", "ftp")
+		_, err := Load("")
+		assert.ErrorContains(t, err, `invalid storage driver "ftp"`)
+	})
+
+	t.Run("s3 storage without credentials", func(t *testing.T) {
+		t.Setenv("STORAGE_DRIVER", "s3")
+		t.Setenv("S3_BUCKET", "finance")
+		_, err := Load("")
+		assert.ErrorContains(t, err, "s3 storage needs")
+	})
+
+	t.Run("billing without a webhook secret", func(t *testing.T) {
+		t.Setenv("STRIPE_SECRET_KEY", "sk_test")
+		t.Setenv("STRIPE_PREMIUM_PRICE_ID", "price_premium")
+		_, err := Load("")
+		assert.ErrorContains(t, err, "webhook secret")
+	})
+
+	t.Run("billing without return urls", func(t *testing.T) {
+		t.Setenv("STRIPE_SECRET_KEY", "sk_test")
+		t.Setenv("STRIPE_WEBHOOK_SECRET", "whsec_test")
+		t.Setenv("STRIPE_PREMIUM_PRICE_ID", "price_premium")
+		t.Setenv("BILLING_SUCCESS_URL", "https://app.example.com/billing/done")
+		_, err := Load("")
+		assert.ErrorContains(t, err, "portal return url")
+	})
+
+	t.Run("no simulation iterations", func(t *testing.T) {
+		t.Setenv("ADVISOR_MAX_SIMULATION_ITERATIONS", "0")
+		_, err := Load("")
+		assert.ErrorContains(t, err, "max simulation iterations")
+	})
+
+	t.Run("categorizer confidence above one", func(t *testing.T) {
+		t.Setenv("CATEGORIZER_MIN_CONFIDENCE", "1.5")
+		_, err := Load("")
+		assert.ErrorContains(t, err, "categorizer min confidence")
+	})
+
+	t.Run("LLM without a timeout", func(t *testing.T) {
+		t.Setenv("LLM_MODEL", "gpt-4o-mini")
+		t.Setenv("LLM_TIMEOUT", "0s")
+		_, err := Load("")
+		assert.ErrorContains(t, err, "positive timeout")
+	})
+
+	t.Run("negative market retries", func(t *testing.T) {
+		t.Setenv("MARKET_MAX_RETRIES", "-1")
+		_, err := Load("")
+		assert.ErrorContains(t, err, "market retries")
+	})
+}
+
+func TestLoad_AI(t *testing.T) {
+	t.Setenv("AI_TREND_CUTOFF", "3.5")
+	t.Setenv("AI_HIGH_RISK_SCORE", "0.8")
+	path := writeConfigFile(t, "config.yaml", `
+ai:
+  volatility_high_cutoff: 8
+  advice_allocations:
+    conservative:
+      - {asset: SPY, share: 0.6}
+      - {asset: BND, share: 0.4}
+`)
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3.5, cfg.AI.TrendCutoff)
+	assert.Equal(t, 0.8, cfg.AI.HighRiskScore)
+	assert.Equal(t, 8.0, cfg.AI.VolatilityHighCutoff)
+	assert.Equal(t, []AllocationShare{{"SPY", 0.6}, {"BND", 0.4}}, cfg.AI.AdviceAllocations["conservative"])
+	assert.Equal(t, Default().AI.AdviceAllocations["aggressive"], cfg.AI.AdviceAllocations["aggressive"])
+
+	t.Run("invalid threshold", func(t *testing.T) {
+		t.Setenv("AI_INVESTABLE_SHARE", "a fifth")
+		_, err := Load("")
+		assert.ErrorContains(t, err, "AI_INVESTABLE_SHARE")
+	})
+
+	t.Run("volatility cutoffs out of order", func(t *testing.T) {
+		t.Setenv("AI_VOLATILITY_MEDIUM_CUTOFF", "6")
+		_, err := Load("")
+		assert.ErrorContains(t, err, "volatility cutoffs")
+	})
+
+	t.Run("risk scores above one", func(t *testing.T) {
+		t.Setenv("AI_HIGH_RISK_SCORE", "1.2")
+		_, err := Load("")
+		assert.ErrorContains(t, err, "risk scores")
+	})
+
+	t.Run("allocation not summing to one", func(t *testing.T) {
+		_, err := Load(writeConfigFile(t, "config.yaml", `
+ai:
+  risk_allocations:
+    low_risk_stable:
+      - {asset: bonds, share: 0.5}
+      - {asset: cash, share: 0.2}
+`))
+		assert.ErrorContains(t, err, `"low_risk_stable" shares must sum to 1`)
+	})
+}
+
+func TestLoad_EncryptionEnv(t *testing.T) {
+	t.Setenv("ENCRYPTION_KEY_ID", "2024-06")
+	t.Setenv("ENCRYPTION_KEYS", "2024-06:bmV3LWtleQ==, 2024-01:b2xkLWtleQ==")
+
+	cfg, err := Load("")
+	require.NoError(t, err)
+
+	assert.True(t, cfg.Encryption.Enabled())
+	assert.Equal(t, "2024-06", cfg.Encryption.KeyID)
+	assert.Equal(t, map[string]string{"2024-06": "bmV3LWtleQ==", "2024-01": "b2xkLWtleQ=="}, cfg.Encryption.Keys)
+}
+
+func TestLoad_EncryptionErrors(t *testing.T) {
+	t.Run("malformed key list", func(t *testing.T) {
+		t.Setenv("ENCRYPTION_KEYS", "no-separator")
+		_, err := Load("")
+		assert.ErrorContains(t, err, "ENCRYPTION_KEYS")
+	})
+
+	t.Run("current key missing", func(t *testing.T) {
+		t.Setenv("ENCRYPTION_KEY_ID", "2024-07")
+		t.Setenv("ENCRYPTION_KEYS", "2024-06:bmV3LWtleQ==")
+		_, err := Load("")
+		assert.ErrorContains(t, err, "not one of the configured keys")
+	})
+
+	t.Run("key ID without keys", func(t *testing.T) {
+		t.Setenv("ENCRYPTION_KEY_ID", "2024-06")
+		_, err := Load("")
+		assert.ErrorContains(t, err, "no encryption keys")
+	})
+}
+
+func TestLoad_PublicURLEnv(t *testing.T) {
+	t.Setenv("PUBLIC_URL", "https://api.example.com")
+
+	cfg, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, "https://api.example.com", cfg.Server.PublicURL)
+}
+
+func TestLoad_TrustedProxies(t *testing.T) {
+	cfg, err := Load("")
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Server.TrustedProxies, "no proxy is trusted by default")
+
+	t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8, 192.168.1.5")
+	cfg, err = Load("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.0/8", "192.168.1.5"}, cfg.Server.TrustedProxies)
+
+	t.Setenv("TRUSTED_PROXIES", "proxy.internal")
+	_, err = Load("")
+	assert.ErrorContains(t, err, "invalid trusted proxy")
+}
+
+func TestLoad_EmailEnv(t *testing.T) {
+	t.Setenv("SMTP_HOST", "smtp.example.com")
+	t.Setenv("SMTP_PORT", "2525")
+	t.Setenv("EMAIL_FROM", "Finance Advisor <digest@example.com>")
+	t.Setenv("DIGEST_INTERVAL", "15m")
+	t.Setenv("DIGEST_UNSUBSCRIBE_URL", "https://api.example.com/api/v1/digest/unsubscribe")
+
+	cfg, err := Load("")
+	require.NoError(t, err)
+
+	assert.True(t, cfg.Email.Enabled())
+	assert.Equal(t, 2525, cfg.Email.Port)
+	assert.Equal(t, "Finance Advisor <digest@example.com>", cfg.Email.From)
+	assert.Equal(t, 15*time.Minute, cfg.Email.DigestInterval.Std())
+	assert.Equal(t, "https://api.example.com/api/v1/digest/unsubscribe", cfg.Email.UnsubscribeURL)
+
+	t.Run("without a from address", func(t *testing.T) {
+		t.Setenv("EMAIL_FROM", "")
+		_, err := Load("")
+		assert.ErrorContains(t, err, "from address")
+	})
+
+	t.Run("invalid port", func(t *testing.T) {
+		t.Setenv("SMTP_PORT", "smtp")
+		_, err := Load("")
+		assert.ErrorContains(t, err, "SMTP_PORT")
+	})
+}
+
+func TestLoad_PushEnv(t *testing.T) {
+	t.Setenv("FCM_CREDENTIALS_FILE", "/etc/finance/firebase.json")
+	t.Setenv("APNS_KEY_FILE", "/etc/finance/AuthKey_KEY123.p8")
+	t.Setenv("APNS_KEY_ID", "KEY123")
+	t.Setenv("APNS_TEAM_ID", "TEAM456")
+	t.Setenv("APNS_TOPIC", "com.example.finance")
+
+	cfg, err := Load("")
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://fcm.googleapis.com", cfg.Push.FCMBaseURL)
+	assert.Equal(t, "/etc/finance/firebase.json", cfg.Push.FCMCredentialsFile)
+	assert.Equal(t, "https://api.push.apple.com", cfg.Push.APNsBaseURL)
+	assert.Equal(t, "com.example.finance", cfg.Push.APNsTopic)
+
+	t.Run("APNs key without a topic", func(t *testing.T) {
+		t.Setenv("APNS_TOPIC", "")
+		_, err := Load("")
+		assert.ErrorContains(t, err, "apns needs")
+	})
+}
+
+func TestLoad_AuthEnv(t *testing.T) {
+	t.Setenv("JWT_SECRET", "current")
+	t.Setenv("JWT_KEY_ID", "2024-06")
+	t.Setenv("JWT_PREVIOUS_KEYS", "2024-01:old-one, 2023-12:old-two")
+	t.Setenv("JWT_ALGORITHM", "HS384")
+	t.Setenv("JWT_AUDIENCE", "finance-web")
+	t.Setenv("JWT_EXPIRY", "2h")
+	t.Setenv("REFRESH_TOKEN_EXPIRY", "168h")
+	t.Setenv("ADMIN_USER_IDS", "1, 42")
+
+	cfg, err := Load("")
+	require.NoError(t, err)
+
+	assert.Equal(t, "current", cfg.Auth.JWTSecret)
+	assert.Equal(t, "2024-06", cfg.Auth.JWTKeyID)
+	assert.Equal(t, map[string]string{"2024-01": "old-one", "2023-12": "old-two"}, cfg.Auth.JWTPreviousKeys)
+	assert.Equal(t, "HS384", cfg.Auth.JWTAlgorithm)
+	assert.Equal(t, "go-finance-advisor", cfg.Auth.JWTIssuer)
+	assert.Equal(t, "finance-web", cfg.Auth.JWTAudience)
+	assert.Equal(t, 2*time.Hour, cfg.Auth.TokenExpiry.Std())
+	assert.Equal(t, 7*24*time.Hour, cfg.Auth.RefreshTokenExpiry.Std())
+	assert.Equal(t, []uint{1, 42}, cfg.Auth.AdminUserIDs)
+
+	t.Run("invalid previous keys", func(t *testing.T) {
+		t.Setenv("JWT_PREVIOUS_KEYS", "missing-secret")
+		_, err := Load("")
+		assert.ErrorContains(t, err, "JWT_PREVIOUS_KEYS")
+	})
+
+	t.Run("invalid admin user IDs", func(t *testing.T) {
+		t.Setenv("ADMIN_USER_IDS", "1,root")
+		_, err := Load("")
+		assert.ErrorContains(t, err, "ADMIN_USER_IDS")
+	})
+
+	t.Run("non-positive refresh token expiry", func(t *testing.T) {
+		t.Setenv("REFRESH_TOKEN_EXPIRY", "0s")
+		_, err := Load("")
+		assert.ErrorContains(t, err, "refresh token expiry")
+	})
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Account types
+const (
+	AccountTypeChecking   = "checking"
+	AccountTypeSavings    = "savings"
+	AccountTypeCreditCard = "credit_card"
+	AccountTypeCash       = "cash"
+	AccountTypeInvestment = "investment"
+	AccountTypeLoan       = "loan"
+)
+
+// accountTypes lists the kinds of account a user can hold
+var accountTypes = []string{
+	AccountTypeChecking, AccountTypeSavings, AccountTypeCreditCard,
+	AccountTypeCash, AccountTypeInvestment, AccountTypeLoan,
+}
+
+// Account limits
+const (
+	maxAccountNameLength   = 100
+	maxAccountNumberLength = 34 // The longest IBAN
+	// accountNumberVisible is how many trailing characters of an account
+	// number are shown to the user
+	accountNumberVisible = 4
+)
+
+// Account is a bank, card or cash account of a user. AccountNumber is
+// encrypted at rest by the repository and never written to JSON; responses
+// carry MaskedNumber instead.
+type Account struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	UserID         uint      `gorm:"index;not null" json:"user_id"`
+	Name           string    `gorm:"type:varchar(100);not null" json:"name"`
+	Type           string    `gorm:"type:varchar(20);not null" json:"type"`
+	Institution    string    `gorm:"type:varchar(100)" json:"institution"`
+	AccountNumber  string    `gorm:"type:varchar(255)" json:"-"`
+	MaskedNumber   string    `gorm:"-" json:"account_number,omitempty"`
+	Currency       string    `gorm:"type:varchar(3);not null;default:USD" json:"currency"`
+	OpeningBalance Money     `gorm:"type:integer;not null;default:0" json:"opening_balance"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// Mask fills MaskedNumber from the plaintext account number, showing only
+// its last four characters
+func (a *Account) Mask() {
+	a.MaskedNumber = MaskAccountNumber(a.AccountNumber)
+}
+
+// MaskAccountNumber hides all but the last four characters of an account number
+func MaskAccountNumber(number string) string {
+	runes := []rune(number)
+	if len(runes) == 0 {
+		return ""
+	}
+	visible := min(accountNumberVisible, len(runes))
+	return strings.Repeat("•", 4) + string(runes[len(runes)-visible:])
+}
+
+// Normalize trims the account's text fields, removes spaces from the account
+// number and upper-cases the currency
+func (a *Account) Normalize() {
+	a.Name = strings.TrimSpace(a.Name)
+	a.Institution = strings.TrimSpace(a.Institution)
+	a.AccountNumber = strings.Join(strings.Fields(a.AccountNumber), "")
+	a.Currency = strings.ToUpper(strings.TrimSpace(a.Currency))
+	if a.Currency == "" {
+		a.Currency = "USD"
+	}
+}
+
+// Validate checks the account's name, type, number and currency
+func (a *Account) Validate() error {
+	var v validator
+	v.check(a.Name != "", "name", "is required")
+	v.check(len([]rune(a.Name)) <= maxAccountNameLength, "name", "must be at most 100 characters")
+	v.check(slices.Contains(accountTypes, a.Type), "type",
+		"must be one of "+strings.Join(accountTypes, ", "))
+	v.check(len([]rune(a.Institution)) <= maxAccountNameLength, "institution", "must be at most 100 characters")
+	v.check(len(a.AccountNumber) <= maxAccountNumberLength, "account_number", "must be at most 34 characters")
+	v.check(strings.IndexFunc(a.AccountNumber, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-'
+	}) < 0, "account_number", "may only contain letters, digits and dashes")
+	v.check(IsCurrencyCode(a.Currency), "currency", "must be a three letter ISO 4217 code")
+	return v.err()
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// StatementMonthLayout is how statement months are written, e.g. 2024-06
+const StatementMonthLayout = "2006-01"
+
+// StatementEntry is a transaction on an account statement with the
+// account's balance right after it
+type StatementEntry struct {
+	TransactionID uint      `json:"transaction_id"`
+	Date          time.Time `json:"date"`
+	Description   string    `json:"description"`
+	Type          string    `json:"type"`
+	CategoryID    uint      `json:"category_id"`
+	Amount        Money     `json:"amount"`
+	Balance       Money     `json:"balance"`
+	// Outgoing is set on transfers to another account
+	Outgoing bool `json:"outgoing,omitempty"`
+}
+
+// Signed returns the entry's effect on the balance: expenses and outgoing
+// transfers are negative
+func (e StatementEntry) Signed() Money {
+	if e.Type == TransactionTypeExpense || e.Outgoing {
+		return -e.Amount
+	}
+	return e.Amount
+}
+
+// AccountStatement lists an account's transactions of one month, oldest
+// first, from the balance it opened the month with to the one it closed with
+type AccountStatement struct {
+	Account        Account          `json:"account"`
+	Month          string           `json:"month"`
+	From           time.Time        `json:"from"`
+	To             time.Time        `json:"to"`
+	OpeningBalance Money            `json:"opening_balance"`
+	Entries        []StatementEntry `json:"entries"`
+	TotalIn        Money            `json:"total_in"`
+	TotalOut       Money            `json:"total_out"`
+	ClosingBalance Money            `json:"closing_balance"`
+}
+
+// NewAccountStatement builds the statement of the month starting at month
+// from the balance before it and the month's transactions, in any order.
+// Transfers count as money out of the account they leave and into the one
+// they reach.
+func NewAccountStatement(account Account, month time.Time, opening Money, transactions []Transaction) AccountStatement {
+	from := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, month.Location())
+	statement := AccountStatement{
+		Account: account, Month: from.Format(StatementMonthLayout),
+		From: from, To: from.AddDate(0, 1, 0).Add(-time.Second),
+		OpeningBalance: opening, Entries: make([]StatementEntry, 0, len(transactions)),
+	}
+
+	sorted := append([]Transaction(nil), transactions...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if !sorted[i].Date.Equal(sorted[j].Date) {
+			return sorted[i].Date.Before(sorted[j].Date)
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	balance := opening
+	for i := range sorted {
+		t := &sorted[i]
+		signed := t.SignedFor(account.ID)
+		balance += signed
+		if signed < 0 {
+			statement.TotalOut += t.Amount
+		} else {
+			statement.TotalIn += t.Amount
+		}
+		statement.Entries = append(statement.Entries, StatementEntry{
+			TransactionID: t.ID, Date: t.Date, Description: t.Description, Type: t.Type,
+			CategoryID: t.CategoryID, Amount: t.Amount, Balance: balance,
+			Outgoing: t.IsTransfer() && signed < 0,
+		})
+	}
+	statement.ClosingBalance = balance
+	return statement
+}
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAccountStatement(t *testing.T) {
+	account := Account{ID: 3, Name: "Checking"}
+	day := func(d int) time.Time { return time.Date(2024, 6, d, 0, 0, 0, 0, time.UTC) }
+	transactions := []Transaction{
+		{ID: 12, Type: TransactionTypeExpense, Amount: NewMoney(40), Date: day(10), Description: "Groceries"},
+		{ID: 10, Type: TransactionTypeIncome, Amount: NewMoney(2500), Date: day(1), Description: "Salary"},
+		{ID: 11, Type: TransactionTypeExpense, Amount: NewMoney(1200), Date: day(10), Description: "Rent"},
+	}
+
+	statement := NewAccountStatement(account, day(15), NewMoney(300), transactions)
+
+	assert.Equal(t, "2024-06", statement.Month)
+	assert.Equal(t, day(1), statement.From)
+	assert.Equal(t, time.Date(2024, 6, 30, 23, 59, 59, 0, time.UTC), statement.To)
+	require.Len(t, statement.Entries, 3)
+	assert.Equal(t, []uint{10, 11, 12}, []uint{
+		statement.Entries[0].TransactionID, statement.Entries[1].TransactionID, statement.Entries[2].TransactionID,
+	})
+	assert.Equal(t, NewMoney(2800), statement.Entries[0].Balance)
+	assert.Equal(t, NewMoney(1600), statement.Entries[1].Balance)
+	assert.Equal(t, NewMoney(1560), statement.Entries[2].Balance)
+	assert.Equal(t, NewMoney(2500), statement.TotalIn)
+	assert.Equal(t, NewMoney(1240), statement.TotalOut)
+	assert.Equal(t, NewMoney(1560), statement.ClosingBalance)
+
+	t.Run("transfers leave one account and reach the other", func(t *testing.T) {
+		savings := uint(4)
+		transfers := []Transaction{
+			{ID: 20, Type: TransactionTypeTransfer, Amount: NewMoney(100), Date: day(2), AccountID: &account.ID, TransferAccountID: &savings},
+			{ID: 21, Type: TransactionTypeTransfer, Amount: NewMoney(30), Date: day(3), AccountID: &savings, TransferAccountID: &account.ID},
+		}
+
+		statement := NewAccountStatement(account, day(1), NewMoney(300), transfers)
+
+		require.Len(t, statement.Entries, 2)
+		assert.True(t, statement.Entries[0].Outgoing)
+		assert.Equal(t, NewMoney(-100), statement.Entries[0].Signed())
+		assert.False(t, statement.Entries[1].Outgoing)
+		assert.Equal(t, NewMoney(30), statement.TotalIn)
+		assert.Equal(t, NewMoney(100), statement.TotalOut)
+		assert.Equal(t, NewMoney(230), statement.ClosingBalance)
+	})
+
+	t.Run("a month without transactions closes at its opening balance", func(t *testing.T) {
+		empty := NewAccountStatement(account, day(1), NewMoney(300), nil)
+
+		assert.Empty(t, empty.Entries)
+		assert.NotNil(t, empty.Entries)
+		assert.Equal(t, NewMoney(300), empty.ClosingBalance)
+	})
+}
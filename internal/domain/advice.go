@@ -0,0 +1,163 @@
+package domain
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+// Advice sources, one per advice endpoint
+const (
+	AdviceSourceBasic          = "basic"
+	AdviceSourceRealTime       = "realtime"
+	AdviceSourceRiskAssessment = "risk_assessment"
+)
+
+// Recommendation statuses
+const (
+	RecommendationStatusPending  = "pending"
+	RecommendationStatusFollowed = "followed"
+	RecommendationStatusIgnored  = "ignored"
+)
+
+// ErrInvalidRecommendationStatus is returned when a recommendation is marked
+// with a status other than pending, followed or ignored
+var ErrInvalidRecommendationStatus = errors.New("status must be pending, followed or ignored")
+
+// IsValidRecommendationStatus reports whether recommendations can be marked with the status
+func IsValidRecommendationStatus(status string) bool {
+	switch status {
+	case RecommendationStatusPending, RecommendationStatusFollowed, RecommendationStatusIgnored:
+		return true
+	}
+	return false
+}
+
+// AdviceRecord is a piece of advice as it was generated for a user, kept so
+// the user can look back at it and see how the advice changed over time.
+// Basic and real-time advice carry recommendations; risk assessments carry a
+// score and a recommended allocation instead.
+type AdviceRecord struct {
+	ID          uint   `gorm:"primaryKey" json:"id"`
+	UserID      uint   `gorm:"index:idx_advice_records_user_created,priority:1" json:"user_id"`
+	Source      string `gorm:"type:varchar(20)" json:"source"`
+	RiskProfile string `gorm:"type:varchar(20)" json:"risk_profile"`
+	// MonthlyAmount is the monthly savings basic advice was based on, or the
+	// monthly income for the other sources
+	MonthlyAmount   float64            `json:"monthly_amount"`
+	Advice          string             `gorm:"type:text" json:"advice,omitempty"`
+	RiskScore       float64            `json:"risk_score,omitempty"`
+	RiskCategory    string             `gorm:"type:varchar(30)" json:"risk_category,omitempty"`
+	ConfidenceScore float64            `json:"confidence_score,omitempty"`
+	Allocation      map[string]float64 `gorm:"serializer:json;type:text" json:"allocation,omitempty"`
+	Recommendations []Recommendation   `gorm:"foreignKey:AdviceID" json:"recommendations,omitempty"`
+	CreatedAt       time.Time          `gorm:"index:idx_advice_records_user_created,priority:2" json:"created_at"`
+}
+
+// AdviceFilter narrows down a user's advice history. Zero values are ignored.
+type AdviceFilter struct {
+	UserID uint
+	Source string
+	Since  *time.Time
+	Until  *time.Time
+	Limit  int
+	Offset int
+}
+
+// RecommendationChange is a recommendation for the same symbol that differs
+// between two pieces of advice
+type RecommendationChange struct {
+	Symbol string         `json:"symbol"`
+	Before Recommendation `json:"before"`
+	After  Recommendation `json:"after"`
+}
+
+// AllocationChange is an asset class whose recommended share changed
+type AllocationChange struct {
+	Asset  string  `json:"asset"`
+	Before float64 `json:"before"`
+	After  float64 `json:"after"`
+}
+
+// AdviceComparison describes how advice changed from one record to a later one
+type AdviceComparison struct {
+	From               AdviceRecord           `json:"from"`
+	To                 AdviceRecord           `json:"to"`
+	RiskProfileChanged bool                   `json:"risk_profile_changed"`
+	Added              []Recommendation       `json:"added"`
+	Removed            []Recommendation       `json:"removed"`
+	Changed            []RecommendationChange `json:"changed"`
+	Unchanged          []string               `json:"unchanged"`
+	AllocationChanges  []AllocationChange     `json:"allocation_changes"`
+}
+
+// CompareAdvice matches the recommendations of two pieces of advice by
+// symbol. A recommendation counts as changed when its action, type, amount,
+// allocation, confidence or risk level differs.
+func CompareAdvice(from, to AdviceRecord) AdviceComparison {
+	comparison := AdviceComparison{
+		From:               from,
+		To:                 to,
+		RiskProfileChanged: from.RiskProfile != to.RiskProfile,
+		Added:              []Recommendation{},
+		Removed:            []Recommendation{},
+		Changed:            []RecommendationChange{},
+		Unchanged:          []string{},
+		AllocationChanges:  []AllocationChange{},
+	}
+
+	before := make(map[string]Recommendation, len(from.Recommendations))
+	for _, rec := range from.Recommendations {
+		before[rec.Symbol] = rec
+	}
+	for _, rec := range to.Recommendations {
+		previous, ok := before[rec.Symbol]
+		if !ok {
+			comparison.Added = append(comparison.Added, rec)
+			continue
+		}
+		delete(before, rec.Symbol)
+		if sameRecommendation(previous, rec) {
+			comparison.Unchanged = append(comparison.Unchanged, rec.Symbol)
+		} else {
+			comparison.Changed = append(comparison.Changed, RecommendationChange{Symbol: rec.Symbol, Before: previous, After: rec})
+		}
+	}
+	for _, rec := range from.Recommendations {
+		if _, removed := before[rec.Symbol]; removed {
+			comparison.Removed = append(comparison.Removed, rec)
+		}
+	}
+
+	for _, asset := range sortedKeys(from.Allocation, to.Allocation) {
+		if from.Allocation[asset] != to.Allocation[asset] {
+			comparison.AllocationChanges = append(comparison.AllocationChanges, AllocationChange{
+				Asset:  asset,
+				Before: from.Allocation[asset],
+				After:  to.Allocation[asset],
+			})
+		}
+	}
+	return comparison
+}
+
+func sameRecommendation(a, b Recommendation) bool {
+	return a.Action == b.Action && a.Type == b.Type && a.CurrentPrice == b.CurrentPrice &&
+		a.Allocation == b.Allocation && a.Confidence == b.Confidence && a.RiskLevel == b.RiskLevel
+}
+
+// sortedKeys returns the keys of both maps in order
+func sortedKeys(maps ...map[string]float64) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, m := range maps {
+		for key := range m {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
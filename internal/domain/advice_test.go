@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareAdvice(t *testing.T) {
+	from := AdviceRecord{
+		RiskProfile: "moderate",
+		Recommendations: []Recommendation{
+			{Symbol: "SPY", Type: "stock", Action: "buy", Allocation: 50},
+			{Symbol: "BTC", Type: "crypto", Action: "buy", Allocation: 50},
+			{Symbol: "GOVT", Type: "bond", Action: "buy", Allocation: 0},
+		},
+		Allocation: map[string]float64{"stocks": 60, "bonds": 40},
+	}
+	to := AdviceRecord{
+		RiskProfile: "aggressive",
+		Recommendations: []Recommendation{
+			{Symbol: "SPY", Type: "stock", Action: "buy", Allocation: 30},
+			{Symbol: "BTC", Type: "crypto", Action: "buy", Allocation: 50},
+			{Symbol: "ETH", Type: "crypto", Action: "buy", Allocation: 20},
+		},
+		Allocation: map[string]float64{"stocks": 60, "crypto": 40},
+	}
+
+	comparison := CompareAdvice(from, to)
+
+	assert.True(t, comparison.RiskProfileChanged)
+	require.Len(t, comparison.Added, 1)
+	assert.Equal(t, "ETH", comparison.Added[0].Symbol)
+	require.Len(t, comparison.Removed, 1)
+	assert.Equal(t, "GOVT", comparison.Removed[0].Symbol)
+	require.Len(t, comparison.Changed, 1)
+	assert.Equal(t, "SPY", comparison.Changed[0].Symbol)
+	assert.Equal(t, 50.0, comparison.Changed[0].Before.Allocation)
+	assert.Equal(t, 30.0, comparison.Changed[0].After.Allocation)
+	assert.Equal(t, []string{"BTC"}, comparison.Unchanged)
+	assert.Equal(t, []AllocationChange{
+		{Asset: "bonds", Before: 40, After: 0},
+		{Asset: "crypto", Before: 0, After: 40},
+	}, comparison.AllocationChanges)
+}
+
+func TestIsValidRecommendationStatus(t *testing.T) {
+	assert.True(t, IsValidRecommendationStatus(RecommendationStatusFollowed))
+	assert.True(t, IsValidRecommendationStatus(RecommendationStatusPending))
+	assert.False(t, IsValidRecommendationStatus("maybe"))
+}
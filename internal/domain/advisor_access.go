@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+// User account types. Advisor accounts, such as those of financial coaches,
+// can be granted access to the finances of their clients.
+const (
+	UserAccountTypePersonal = "personal"
+	UserAccountTypeAdvisor  = "advisor"
+)
+
+// UserAccountTypes lists the account types a user can have
+var UserAccountTypes = []string{UserAccountTypePersonal, UserAccountTypeAdvisor}
+
+// Access clients grant advisors: read access shows the advisor the client's
+// dashboard, comment access also lets them leave recommendations
+const (
+	AdvisorAccessRead    = "read"
+	AdvisorAccessComment = "comment"
+)
+
+const maxAdvisorRecommendationLength = 2000
+
+// ErrNotAdvisorAccount is returned when granting access to, or acting as an
+// advisor with, a user whose account is not an advisor account
+var ErrNotAdvisorAccount = errors.New("user does not have an advisor account")
+
+// ErrAdvisorForbidden is returned when a client has not granted the advisor
+// the access an action needs
+var ErrAdvisorForbidden = errors.New("the client has not granted you this access")
+
+// ErrAdvisorAlreadyGranted is returned when granting access to an advisor who
+// already has it
+var ErrAdvisorAlreadyGranted = errors.New("advisor already has access")
+
+// ErrInvalidAdvisorAccess is returned for access other than read or comment
+var ErrInvalidAdvisorAccess = errors.New("access must be read or comment")
+
+// ValidateAccountType checks that the account type is one of UserAccountTypes
+func ValidateAccountType(accountType string) error {
+	var v validator
+	v.check(slices.Contains(UserAccountTypes, accountType), "account_type", "must be personal or advisor")
+	return v.err()
+}
+
+// IsAdvisor reports whether the user has an advisor account
+func (u *User) IsAdvisor() bool {
+	return u.AccountType == UserAccountTypeAdvisor
+}
+
+// IsValidAdvisorAccess reports whether clients can grant the access
+func IsValidAdvisorAccess(access string) bool {
+	return access == AdvisorAccessRead || access == AdvisorAccessComment
+}
+
+// AdvisorGrant is a client's permission for an advisor to look at their
+// finances. Advisors never change a client's data; comment access only adds
+// recommendations to the client's insights.
+type AdvisorGrant struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ClientID  uint      `gorm:"uniqueIndex:idx_advisor_grants_client_advisor,priority:1;not null" json:"client_id"`
+	AdvisorID uint      `gorm:"uniqueIndex:idx_advisor_grants_client_advisor,priority:2;index;not null" json:"advisor_id"`
+	Access    string    `gorm:"type:varchar(20);not null" json:"access"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CanRead reports whether the advisor may look at the client's finances
+func (g AdvisorGrant) CanRead() bool {
+	return IsValidAdvisorAccess(g.Access)
+}
+
+// CanComment reports whether the advisor may leave recommendations
+func (g AdvisorGrant) CanComment() bool {
+	return g.Access == AdvisorAccessComment
+}
+
+// AdvisorConnection is a grant as one of its sides sees it: the user on the
+// other side and the access granted. Clients see their advisors, advisors
+// their clients.
+type AdvisorConnection struct {
+	UserID    uint      `json:"user_id"`
+	Email     string    `json:"email"`
+	FirstName string    `json:"first_name,omitempty"`
+	LastName  string    `json:"last_name,omitempty"`
+	Access    string    `json:"access"`
+	GrantedAt time.Time `json:"granted_at"`
+}
+
+// AdvisorRecommendation is advice an advisor left a client, shown in the
+// client's insights
+type AdvisorRecommendation struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ClientID  uint      `gorm:"index;not null" json:"client_id"`
+	AdvisorID uint      `gorm:"index;not null" json:"advisor_id"`
+	Message   string    `gorm:"type:text;not null" json:"message"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+// Validate checks the recommendation's message
+func (r *AdvisorRecommendation) Validate() error {
+	var v validator
+	v.check(strings.TrimSpace(r.Message) != "", "message", "is required")
+	v.check(len([]rune(r.Message)) <= maxAdvisorRecommendationLength, "message", "must be at most 2000 characters")
+	return v.err()
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// Analytics batch query types, one per analytics endpoint
+const (
+	AnalyticsQueryMetrics         = "metrics"
+	AnalyticsQueryIncomeExpense   = "income_expense"
+	AnalyticsQueryCategory        = "category"
+	AnalyticsQueryCategoryTrend   = "category_trend"
+	AnalyticsQueryDashboard       = "dashboard"
+	AnalyticsQueryMerchants       = "merchants"
+	AnalyticsQueryPlaces          = "places"
+	AnalyticsQueryItems           = "items"
+	AnalyticsQueryIncomeStability = "income_stability"
+	AnalyticsQueryIncomeSources   = "income_sources"
+)
+
+// AnalyticsQueryTypes lists the queries a batch can run
+var AnalyticsQueryTypes = []string{
+	AnalyticsQueryMetrics, AnalyticsQueryIncomeExpense, AnalyticsQueryCategory, AnalyticsQueryCategoryTrend,
+	AnalyticsQueryDashboard, AnalyticsQueryMerchants, AnalyticsQueryPlaces, AnalyticsQueryItems,
+	AnalyticsQueryIncomeStability, AnalyticsQueryIncomeSources,
+}
+
+// MaxAnalyticsBatchQueries limits the queries of one batch
+const MaxAnalyticsBatchQueries = 20
+
+// AnalyticsQuery is one query of an analytics batch, taking the parameters
+// of its endpoint. Zero values get the endpoint's defaults; EndDate is the
+// last day included.
+type AnalyticsQuery struct {
+	ID         string
+	Type       string
+	Period     string
+	StartDate  time.Time
+	EndDate    time.Time
+	CategoryID uint
+	Months     int
+	GroupBy    string
+	Query      string
+	Limit      int
+}
+
+// Validate checks the query's type and ID and that category queries name a category
+func (q *AnalyticsQuery) Validate() error {
+	var v validator
+	v.check(slices.Contains(AnalyticsQueryTypes, q.Type), "type", "must be one of the analytics query types")
+	v.check(len(q.ID) <= 50, "id", "must be at most 50 characters")
+	if q.Type == AnalyticsQueryCategory || q.Type == AnalyticsQueryCategoryTrend {
+		v.check(q.CategoryID != 0, "category_id", "is required")
+	}
+	v.check(q.StartDate.IsZero() || q.EndDate.IsZero() || !q.EndDate.Before(q.StartDate),
+		"end_date", "must not be before the start date")
+	v.check(q.Limit >= 0, "limit", "must not be negative")
+	return v.err()
+}
+
+// AnalyticsQueryResult is the answer to one query of a batch: Data holds
+// what its endpoint returns, or Error why the query failed
+type AnalyticsQueryResult struct {
+	ID    string `json:"id,omitempty"`
+	Type  string `json:"type"`
+	Data  any    `json:"data,omitempty"`
+	Error string `json:"error,omitempty"`
+}
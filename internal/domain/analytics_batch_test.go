@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyticsQuery_Validate(t *testing.T) {
+	start := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	assert.NoError(t, (&AnalyticsQuery{Type: AnalyticsQueryMetrics}).Validate())
+	assert.NoError(t, (&AnalyticsQuery{Type: AnalyticsQueryCategory, CategoryID: 1, StartDate: start, EndDate: start}).Validate())
+
+	tests := []struct {
+		name  string
+		query AnalyticsQuery
+		want  []string
+	}{
+		{"unknown type", AnalyticsQuery{Type: "forecast"}, []string{"type"}},
+		{"long ID", AnalyticsQuery{ID: strings.Repeat("a", 51), Type: AnalyticsQueryDashboard}, []string{"id"}},
+		{"category without category", AnalyticsQuery{Type: AnalyticsQueryCategoryTrend}, []string{"category_id"}},
+		{
+			"end before start",
+			AnalyticsQuery{Type: AnalyticsQueryMerchants, StartDate: start, EndDate: start.AddDate(0, 0, -1), Limit: -1},
+			[]string{"end_date", "limit"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, fieldsOf(t, tt.query.Validate()))
+		})
+	}
+}
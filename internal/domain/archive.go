@@ -0,0 +1,53 @@
+package domain
+
+import "time"
+
+// TransactionArchive holds the transactions of one user and year that were
+// older than the retention's archive age and moved out of the transactions
+// table. Data is their gzip-compressed JSON, tags and line items included;
+// it is only unpacked when the user reads their archive.
+type TransactionArchive struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	UserID       uint      `gorm:"not null;uniqueIndex:idx_transaction_archives_user_year" json:"user_id"`
+	Year         int       `gorm:"not null;uniqueIndex:idx_transaction_archives_user_year" json:"year"`
+	Transactions int       `gorm:"not null;default:0" json:"transactions"`
+	FromDate     time.Time `json:"from_date"`
+	ToDate       time.Time `json:"to_date"`
+	Size         int       `gorm:"not null;default:0" json:"size"`
+	Data         []byte    `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// ArchiveFilter narrows the archived transactions a user reads; both dates
+// are inclusive
+type ArchiveFilter struct {
+	From *time.Time
+	To   *time.Time
+}
+
+// ArchiveStatus tells admins how far retention got: what is archived, what
+// is old enough to be archived on the next run and what waits in the trash.
+// ArchiveAfterYears 0 means archiving is off and Cutoff is then unset.
+type ArchiveStatus struct {
+	ArchiveAfterYears   int                 `json:"archive_after_years"`
+	Cutoff              *time.Time          `json:"cutoff,omitempty"`
+	Archives            int                 `json:"archives"`
+	Users               int                 `json:"users"`
+	Transactions        int                 `json:"transactions"`
+	CompressedBytes     int64               `json:"compressed_bytes"`
+	LastArchivedAt      *time.Time          `json:"last_archived_at,omitempty"`
+	Years               []ArchiveYearStatus `json:"years"`
+	PendingTransactions int                 `json:"pending_transactions"`
+	TrashRetentionDays  int                 `json:"trash_retention_days"`
+	TrashedTransactions int                 `json:"trashed_transactions"`
+	ExpiredTrash        int                 `json:"expired_trash"`
+}
+
+// ArchiveYearStatus sums the archives of one year
+type ArchiveYearStatus struct {
+	Year            int   `json:"year"`
+	Users           int   `json:"users"`
+	Transactions    int   `json:"transactions"`
+	CompressedBytes int64 `json:"compressed_bytes"`
+}
@@ -0,0 +1,218 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Assistant errors
+var (
+	ErrAssistantUnavailable  = errors.New("the assistant is unavailable")
+	ErrAssistantUnanswerable = errors.New("the assistant cannot answer this question")
+)
+
+// MaxAssistantQuestionLength bounds questions, in characters
+const MaxAssistantQuestionLength = 500
+
+// The kinds of questions the assistant answers
+const (
+	AssistantQuerySpending      = "spending"       // Total spent, optionally in one category
+	AssistantQueryIncome        = "income"         // Total earned, optionally in one category
+	AssistantQueryNet           = "net"            // Earned minus spent, with the savings rate
+	AssistantQueryTopCategories = "top_categories" // The categories most was spent in
+	AssistantQueryTopMerchants  = "top_merchants"  // The merchants most was spent at
+)
+
+var assistantQueryKinds = []string{
+	AssistantQuerySpending, AssistantQueryIncome, AssistantQueryNet, AssistantQueryTopCategories, AssistantQueryTopMerchants,
+}
+
+// Units of the figures behind an answer
+const (
+	FigureUnitAmount  = "amount"
+	FigureUnitCount   = "count"
+	FigureUnitPercent = "percent"
+)
+
+// AssistantQuery is what a question asks for: a kind of figure over the
+// days From to To, both included. Category names the category of spending
+// and income questions about one.
+type AssistantQuery struct {
+	Kind     string    `json:"kind"`
+	Category string    `json:"category,omitempty"`
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+}
+
+// AssistantFigure is one of the numbers an answer was made from
+type AssistantFigure struct {
+	Label string  `json:"label"`
+	Value float64 `json:"value"`
+	Unit  string  `json:"unit"`
+}
+
+// AssistantAnswer is the reply to a question with the query it was
+// translated into and the figures the answer states
+type AssistantAnswer struct {
+	Question string            `json:"question"`
+	Answer   string            `json:"answer"`
+	Query    AssistantQuery    `json:"query"`
+	Figures  []AssistantFigure `json:"figures"`
+}
+
+// AssistantInstructions tells the model how to translate a question into a
+// query, given the day it is asked on and the user's category names
+func AssistantInstructions(today time.Time, categories []string) string {
+	return fmt.Sprintf(`You translate questions about personal finances into JSON queries.
+Today is %s. Reply with a single JSON object and nothing else:
+{"kind": "...", "category": "...", "from": "YYYY-MM-DD", "to": "YYYY-MM-DD"}
+kind is one of:
+- "spending": how much was spent, in total or in one category
+- "income": how much was earned, in total or in one category
+- "net": how much was saved, income minus spending
+- "top_categories": where most money went, by category
+- "top_merchants": where most money went, by merchant or shop
+- "unknown": anything else
+category is left empty unless the question is about one of these categories: %s.
+from and to are the first and last day the question covers; "last quarter" is the
+previous calendar quarter, "this month" runs from its first day until today. Without a
+period, use the current month.`, today.Format("2006-01-02"), strings.Join(categories, ", "))
+}
+
+// ParseAssistantQuery reads the query from the model's reply. The reply may
+// wrap the JSON object in text or a code block.
+func ParseAssistantQuery(reply string, today time.Time) (AssistantQuery, error) {
+	start, end := strings.Index(reply, "{"), strings.LastIndex(reply, "}")
+	if start < 0 || end < start {
+		return AssistantQuery{}, ErrAssistantUnanswerable
+	}
+	var raw struct {
+		Kind     string `json:"kind"`
+		Category string `json:"category"`
+		From     string `json:"from"`
+		To       string `json:"to"`
+	}
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &raw); err != nil {
+		return AssistantQuery{}, ErrAssistantUnanswerable
+	}
+
+	query := AssistantQuery{Kind: strings.ToLower(strings.TrimSpace(raw.Kind)), Category: strings.TrimSpace(raw.Category)}
+	if !slices.Contains(assistantQueryKinds, query.Kind) {
+		return AssistantQuery{}, ErrAssistantUnanswerable
+	}
+	if query.Kind != AssistantQuerySpending && query.Kind != AssistantQueryIncome {
+		query.Category = ""
+	}
+
+	day := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	query.From, query.To = day.AddDate(0, 0, 1-day.Day()), day
+	var err error
+	if raw.From != "" {
+		if query.From, err = time.Parse("2006-01-02", raw.From); err != nil {
+			return AssistantQuery{}, ErrAssistantUnanswerable
+		}
+	}
+	if raw.To != "" {
+		if query.To, err = time.Parse("2006-01-02", raw.To); err != nil {
+			return AssistantQuery{}, ErrAssistantUnanswerable
+		}
+	}
+	if query.To.Before(query.From) {
+		return AssistantQuery{}, ErrAssistantUnanswerable
+	}
+	return query, nil
+}
+
+// End returns the last moment of the query's period
+func (q AssistantQuery) End() time.Time {
+	return q.To.Add(24*time.Hour - time.Nanosecond)
+}
+
+// period describes the query's days for an answer
+func (q AssistantQuery) period() string {
+	if q.From.Equal(q.To) {
+		return "on " + q.From.Format("2 Jan 2006")
+	}
+	return fmt.Sprintf("between %s and %s", q.From.Format("2 Jan 2006"), q.To.Format("2 Jan 2006"))
+}
+
+// NewTotalAnswer answers a spending or income question with the total and
+// the number of transactions it sums
+func NewTotalAnswer(question string, query AssistantQuery, total float64, count int) AssistantAnswer {
+	label := "Spending"
+	verb := "spent"
+	if query.Kind == AssistantQueryIncome {
+		label, verb = "Income", "earned"
+	}
+	in := ""
+	if query.Category != "" {
+		label, in = query.Category, " in "+query.Category
+	}
+	return AssistantAnswer{
+		Question: question,
+		Answer:   fmt.Sprintf("You %s %.2f%s %s across %d transaction(s).", verb, total, in, query.period(), count),
+		Query:    query,
+		Figures: []AssistantFigure{
+			{Label: label, Value: total, Unit: FigureUnitAmount},
+			{Label: "Transactions", Value: float64(count), Unit: FigureUnitCount},
+		},
+	}
+}
+
+// NewNetAnswer answers a savings question. The savings rate is the share of
+// the income that was not spent, zero without income.
+func NewNetAnswer(question string, query AssistantQuery, income, expenses float64) AssistantAnswer {
+	net := income - expenses
+	var rate float64
+	if income > 0 {
+		rate = math.Round(net/income*10000) / 100
+	}
+	return AssistantAnswer{
+		Question: question,
+		Answer: fmt.Sprintf("%s you earned %.2f and spent %.2f, which leaves %.2f, a savings rate of %.2f%%.",
+			capitalize(query.period()), income, expenses, net, rate),
+		Query: query,
+		Figures: []AssistantFigure{
+			{Label: "Income", Value: income, Unit: FigureUnitAmount},
+			{Label: "Spending", Value: expenses, Unit: FigureUnitAmount},
+			{Label: "Net", Value: net, Unit: FigureUnitAmount},
+			{Label: "Savings rate", Value: rate, Unit: FigureUnitPercent},
+		},
+	}
+}
+
+// NewRankingAnswer answers a top categories or merchants question with the
+// amounts spent, largest first
+func NewRankingAnswer(question string, query AssistantQuery, ranking []AssistantFigure) AssistantAnswer {
+	answer := AssistantAnswer{Question: question, Query: query, Figures: ranking}
+	if len(ranking) == 0 {
+		answer.Answer = fmt.Sprintf("You had no expenses %s.", query.period())
+		answer.Figures = []AssistantFigure{}
+		return answer
+	}
+	parts := make([]string, len(ranking))
+	for i, figure := range ranking {
+		parts[i] = fmt.Sprintf("%s (%.2f)", figure.Label, figure.Value)
+	}
+	where := "went to"
+	if query.Kind == AssistantQueryTopMerchants {
+		where = "was spent at"
+	}
+	answer.Answer = fmt.Sprintf("%s most of your money %s %s.", capitalize(query.period()), where, strings.Join(parts, ", "))
+	return answer
+}
+
+func capitalize(s string) string {
+	first, size := utf8.DecodeRuneInString(s)
+	if first == utf8.RuneError {
+		return s
+	}
+	return string(unicode.ToUpper(first)) + s[size:]
+}
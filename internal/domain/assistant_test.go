@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAssistantQuery(t *testing.T) {
+	today := time.Date(2024, 5, 17, 15, 30, 0, 0, time.UTC)
+	day := func(month time.Month, d int) time.Time { return time.Date(2024, month, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name    string
+		reply   string
+		want    AssistantQuery
+		wantErr bool
+	}{
+		{
+			name:  "spending in a category",
+			reply: `{"kind": "spending", "category": "Groceries", "from": "2024-01-01", "to": "2024-03-31"}`,
+			want:  AssistantQuery{Kind: AssistantQuerySpending, Category: "Groceries", From: day(1, 1), To: day(3, 31)},
+		},
+		{
+			name:  "wrapped in a code block",
+			reply: "Here you go:\n```json\n{\"kind\": \"Top_Merchants\", \"from\": \"2024-04-01\", \"to\": \"2024-04-30\"}\n```",
+			want:  AssistantQuery{Kind: AssistantQueryTopMerchants, From: day(4, 1), To: day(4, 30)},
+		},
+		{
+			name:  "the current month without a period",
+			reply: `{"kind": "net"}`,
+			want:  AssistantQuery{Kind: AssistantQueryNet, From: day(5, 1), To: day(5, 17)},
+		},
+		{
+			name:  "categories only narrow spending and income",
+			reply: `{"kind": "top_categories", "category": "Groceries", "from": "2024-05-01", "to": "2024-05-01"}`,
+			want:  AssistantQuery{Kind: AssistantQueryTopCategories, From: day(5, 1), To: day(5, 1)},
+		},
+		{name: "unknown kind", reply: `{"kind": "unknown"}`, wantErr: true},
+		{name: "no JSON", reply: "I cannot help with that.", wantErr: true},
+		{name: "malformed date", reply: `{"kind": "spending", "from": "01/02/2024"}`, wantErr: true},
+		{name: "period ends before it starts", reply: `{"kind": "income", "from": "2024-03-01", "to": "2024-02-01"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := ParseAssistantQuery(tt.reply, today)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrAssistantUnanswerable)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, query)
+		})
+	}
+}
+
+func TestAssistantAnswers(t *testing.T) {
+	quarter := AssistantQuery{
+		Kind: AssistantQuerySpending, Category: "Groceries",
+		From: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+	}
+	assert.Equal(t, time.Date(2024, 3, 31, 23, 59, 59, 999999999, time.UTC), quarter.End())
+
+	t.Run("totals", func(t *testing.T) {
+		answer := NewTotalAnswer("groceries last quarter?", quarter, 412.5, 9)
+		assert.Equal(t, "You spent 412.50 in Groceries between 1 Jan 2024 and 31 Mar 2024 across 9 transaction(s).", answer.Answer)
+		assert.Equal(t, []AssistantFigure{
+			{Label: "Groceries", Value: 412.5, Unit: FigureUnitAmount},
+			{Label: "Transactions", Value: 9, Unit: FigureUnitCount},
+		}, answer.Figures)
+
+		income := AssistantQuery{Kind: AssistantQueryIncome, From: quarter.From, To: quarter.From}
+		answer = NewTotalAnswer("income on new year's day?", income, 0, 0)
+		assert.Equal(t, "You earned 0.00 on 1 Jan 2024 across 0 transaction(s).", answer.Answer)
+		assert.Equal(t, "Income", answer.Figures[0].Label)
+	})
+
+	t.Run("savings", func(t *testing.T) {
+		net := AssistantQuery{Kind: AssistantQueryNet, From: quarter.From, To: quarter.To}
+		answer := NewNetAnswer("how much did I save?", net, 3000, 2250)
+		assert.Equal(t, "Between 1 Jan 2024 and 31 Mar 2024 you earned 3000.00 and spent 2250.00, "+
+			"which leaves 750.00, a savings rate of 25.00%.", answer.Answer)
+		assert.Equal(t, AssistantFigure{Label: "Savings rate", Value: 25, Unit: FigureUnitPercent}, answer.Figures[3])
+
+		answer = NewNetAnswer("how much did I save?", net, 0, 80)
+		assert.Equal(t, -80.0, answer.Figures[2].Value)
+		assert.Zero(t, answer.Figures[3].Value, "no savings rate without income")
+	})
+
+	t.Run("rankings", func(t *testing.T) {
+		merchants := AssistantQuery{Kind: AssistantQueryTopMerchants, From: quarter.From, To: quarter.To}
+		answer := NewRankingAnswer("where do I shop?", merchants, []AssistantFigure{
+			{Label: "Corner Grocery", Value: 210, Unit: FigureUnitAmount},
+			{Label: "Shell", Value: 95.4, Unit: FigureUnitAmount},
+		})
+		assert.Equal(t, "Between 1 Jan 2024 and 31 Mar 2024 most of your money was spent at "+
+			"Corner Grocery (210.00), Shell (95.40).", answer.Answer)
+		assert.Len(t, answer.Figures, 2)
+
+		answer = NewRankingAnswer("where did my money go?", AssistantQuery{
+			Kind: AssistantQueryTopCategories, From: quarter.From, To: quarter.To,
+		}, nil)
+		assert.Equal(t, "You had no expenses between 1 Jan 2024 and 31 Mar 2024.", answer.Answer)
+		assert.NotNil(t, answer.Figures)
+	})
+}
@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// Attachment represents a file uploaded by a user, such as a receipt image.
+// Attachments may be linked to a transaction once the user confirms it.
+type Attachment struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	UserID        uint      `gorm:"not null;index" json:"user_id"`
+	TransactionID *uint     `gorm:"index" json:"transaction_id,omitempty"`
+	FileName      string    `gorm:"type:varchar(255);not null" json:"file_name"`
+	ContentType   string    `gorm:"type:varchar(100)" json:"content_type"`
+	Size          int64     `json:"size"`
+	StoragePath   string    `gorm:"type:varchar(500);not null" json:"-"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// ReceiptData holds the fields extracted from a receipt by OCR. Items are
+// the lines read as purchases, which can be sent back with the transaction.
+type ReceiptData struct {
+	Merchant string            `json:"merchant"`
+	Date     *time.Time        `json:"date,omitempty"`
+	Amount   Money             `json:"amount"`
+	Items    []TransactionItem `json:"items,omitempty"`
+	RawText  string            `json:"raw_text"`
+}
+
+// IsComplete reports whether merchant, date and amount were all extracted
+func (r *ReceiptData) IsComplete() bool {
+	return r.Merchant != "" && r.Date != nil && r.Amount > 0
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Audit actions
+const (
+	AuditActionCreate  = "create"
+	AuditActionUpdate  = "update"
+	AuditActionDelete  = "delete"
+	AuditActionRestore = "restore"
+	AuditActionPurge   = "purge"
+)
+
+// Audited entity types
+const (
+	AuditEntityTransaction = "transaction"
+	AuditEntityBudget      = "budget"
+	AuditEntityCategory    = "category"
+	AuditEntityUser        = "user"
+	AuditEntityAccount     = "account"
+)
+
+// AuditLog records a single modification of user data. UserID is the owner of
+// the changed data and ActorID the authenticated user who made the change; an
+// ActorID of zero means the change did not come through the API (console,
+// background jobs). Before and After hold JSON snapshots of the entity.
+type AuditLog struct {
+	ID         uint            `gorm:"primaryKey" json:"id"`
+	UserID     uint            `gorm:"index" json:"user_id"`
+	ActorID    uint            `gorm:"index" json:"actor_id"`
+	EntityType string          `gorm:"type:varchar(30);index:idx_audit_entity" json:"entity_type"`
+	EntityID   uint            `gorm:"index:idx_audit_entity" json:"entity_id"`
+	Action     string          `gorm:"type:varchar(20)" json:"action"`
+	Before     json.RawMessage `gorm:"type:text" json:"before,omitempty"`
+	After      json.RawMessage `gorm:"type:text" json:"after,omitempty"`
+	IPAddress  string          `gorm:"type:varchar(45)" json:"ip_address,omitempty"`
+	CreatedAt  time.Time       `gorm:"index" json:"created_at"`
+}
+
+// AuditFilter narrows down audit log queries. Zero values are ignored.
+type AuditFilter struct {
+	UserID     uint
+	ActorID    uint
+	EntityType string
+	EntityID   uint
+	Action     string
+	Since      *time.Time
+	Until      *time.Time
+	Limit      int
+	Offset     int
+}
+
+// Actor identifies who is making a request. SessionID is the login session
+// the request's token belongs to, zero for tokens issued without one.
+type Actor struct {
+	UserID    uint
+	SessionID uint
+	IPAddress string
+}
+
+type actorKey struct{}
+
+// ContextWithActor returns a context carrying the acting user
+func ContextWithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the acting user stored by ContextWithActor
+func ActorFromContext(ctx context.Context) (Actor, bool) {
+	actor, ok := ctx.Value(actorKey{}).(Actor)
+	return actor, ok
+}
@@ -0,0 +1,215 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// PriceSnapshot is the market price of a symbol at one point in time. Prices
+// are captured whenever advice is kept and whenever its performance is
+// checked, building the history that past advice is replayed against.
+type PriceSnapshot struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	Symbol     string    `gorm:"type:varchar(20);index:idx_price_snapshots_symbol_captured,priority:1" json:"symbol"`
+	Price      float64   `json:"price"`
+	CapturedAt time.Time `gorm:"index:idx_price_snapshots_symbol_captured,priority:2" json:"captured_at"`
+}
+
+// PriceSeries holds the known prices per upper-cased symbol, oldest first
+type PriceSeries map[string][]PriceSnapshot
+
+// NewPriceSeries groups snapshots by symbol and sorts them by time
+func NewPriceSeries(snapshots []PriceSnapshot) PriceSeries {
+	series := PriceSeries{}
+	for _, snapshot := range snapshots {
+		symbol := strings.ToUpper(snapshot.Symbol)
+		series[symbol] = append(series[symbol], snapshot)
+	}
+	for _, prices := range series {
+		sort.SliceStable(prices, func(i, j int) bool { return prices[i].CapturedAt.Before(prices[j].CapturedAt) })
+	}
+	return series
+}
+
+// Closest returns the price captured nearest to t, before or after it
+func (p PriceSeries) Closest(symbol string, t time.Time) (PriceSnapshot, bool) {
+	prices := p[strings.ToUpper(symbol)]
+	if len(prices) == 0 {
+		return PriceSnapshot{}, false
+	}
+	i := sort.Search(len(prices), func(i int) bool { return !prices[i].CapturedAt.Before(t) })
+	switch {
+	case i == len(prices):
+		return prices[i-1], true
+	case i == 0:
+		return prices[0], true
+	case t.Sub(prices[i-1].CapturedAt) <= prices[i].CapturedAt.Sub(t):
+		return prices[i-1], true
+	default:
+		return prices[i], true
+	}
+}
+
+// AsOf returns the latest price captured at or before t
+func (p PriceSeries) AsOf(symbol string, t time.Time) (PriceSnapshot, bool) {
+	prices := p[strings.ToUpper(symbol)]
+	i := sort.Search(len(prices), func(i int) bool { return prices[i].CapturedAt.After(t) })
+	if i == 0 {
+		return PriceSnapshot{}, false
+	}
+	return prices[i-1], true
+}
+
+// BacktestFilter selects the advice to replay. Status limits the replay to
+// recommendations the user marked as followed, ignored or left pending; At
+// is the moment the positions are valued at and defaults to now.
+type BacktestFilter struct {
+	UserID uint
+	Source string
+	Status string
+	Since  *time.Time
+	Until  *time.Time
+	At     *time.Time
+}
+
+// Reasons a recommendation could not be replayed
+const (
+	BacktestSkipNotBuy       = "not a buy recommendation"
+	BacktestSkipNoAmount     = "no amount to invest"
+	BacktestSkipNoEntryPrice = "no price around the time of the advice"
+	BacktestSkipNoExitPrice  = "no later price to value the position at"
+)
+
+// PerformanceTotals adds up the hypothetical positions of replayed recommendations
+type PerformanceTotals struct {
+	Invested  float64 `json:"invested"`
+	Value     float64 `json:"value"`
+	Gain      float64 `json:"gain"`
+	ReturnPct float64 `json:"return_pct"`
+	Evaluated int     `json:"evaluated"`
+	Skipped   int     `json:"skipped"`
+}
+
+func (t *PerformanceTotals) add(outcome RecommendationOutcome) {
+	if !outcome.Evaluated {
+		t.Skipped++
+		return
+	}
+	t.Evaluated++
+	t.Invested += outcome.Invested
+	t.Value += outcome.Value
+	t.Gain = t.Value - t.Invested
+	if t.Invested > 0 {
+		t.ReturnPct = t.Gain / t.Invested * 100
+	}
+}
+
+// RecommendationOutcome is what following one recommendation would have
+// returned: its amount bought at the entry price and valued at the exit price
+type RecommendationOutcome struct {
+	RecommendationID uint       `json:"recommendation_id"`
+	Symbol           string     `json:"symbol"`
+	Status           string     `json:"status"`
+	Evaluated        bool       `json:"evaluated"`
+	SkipReason       string     `json:"skip_reason,omitempty"`
+	Invested         float64    `json:"invested"`
+	EntryPrice       float64    `json:"entry_price,omitempty"`
+	EntryAt          *time.Time `json:"entry_at,omitempty"`
+	ExitPrice        float64    `json:"exit_price,omitempty"`
+	ExitAt           *time.Time `json:"exit_at,omitempty"`
+	Value            float64    `json:"value"`
+	Gain             float64    `json:"gain"`
+	ReturnPct        float64    `json:"return_pct"`
+}
+
+// AdviceOutcome is the replayed performance of one piece of advice
+type AdviceOutcome struct {
+	AdviceID        uint                    `json:"advice_id"`
+	Source          string                  `json:"source"`
+	RiskProfile     string                  `json:"risk_profile"`
+	CreatedAt       time.Time               `json:"created_at"`
+	Recommendations []RecommendationOutcome `json:"recommendations"`
+	PerformanceTotals
+}
+
+// AdvicePerformance is the outcome of replaying past advice. Totals covers
+// every recommendation; Followed only those the user marked as followed.
+type AdvicePerformance struct {
+	At       time.Time         `json:"at"`
+	Advice   []AdviceOutcome   `json:"advice"`
+	Totals   PerformanceTotals `json:"totals"`
+	Followed PerformanceTotals `json:"followed"`
+}
+
+// Backtest replays advice against known prices. Every buy recommendation is
+// bought for its amount at the price closest to the moment the advice was
+// generated and valued at the latest price at or before at.
+func Backtest(records []AdviceRecord, prices PriceSeries, status string, at time.Time) AdvicePerformance {
+	performance := AdvicePerformance{At: at, Advice: []AdviceOutcome{}}
+	for i := range records {
+		record := &records[i]
+		outcome := AdviceOutcome{
+			AdviceID:        record.ID,
+			Source:          record.Source,
+			RiskProfile:     record.RiskProfile,
+			CreatedAt:       record.CreatedAt,
+			Recommendations: []RecommendationOutcome{},
+		}
+		for j := range record.Recommendations {
+			rec := &record.Recommendations[j]
+			if status != "" && rec.Status != status {
+				continue
+			}
+			result := replay(rec, record.CreatedAt, prices, at)
+			outcome.Recommendations = append(outcome.Recommendations, result)
+			outcome.add(result)
+			performance.Totals.add(result)
+			if rec.Status == RecommendationStatusFollowed {
+				performance.Followed.add(result)
+			}
+		}
+		if len(outcome.Recommendations) > 0 {
+			performance.Advice = append(performance.Advice, outcome)
+		}
+	}
+	return performance
+}
+
+// replay values a single recommendation. Advice stores the amount it suggests
+// investing in CurrentPrice.
+func replay(rec *Recommendation, generatedAt time.Time, prices PriceSeries, at time.Time) RecommendationOutcome {
+	outcome := RecommendationOutcome{
+		RecommendationID: rec.ID,
+		Symbol:           rec.Symbol,
+		Status:           rec.Status,
+		Invested:         rec.CurrentPrice,
+	}
+	switch {
+	case !strings.EqualFold(rec.Action, "buy"):
+		outcome.SkipReason = BacktestSkipNotBuy
+		return outcome
+	case rec.CurrentPrice <= 0:
+		outcome.SkipReason = BacktestSkipNoAmount
+		return outcome
+	}
+
+	entry, ok := prices.Closest(rec.Symbol, generatedAt)
+	if !ok || entry.Price <= 0 || entry.CapturedAt.After(at) {
+		outcome.SkipReason = BacktestSkipNoEntryPrice
+		return outcome
+	}
+	exit, ok := prices.AsOf(rec.Symbol, at)
+	if !ok || !exit.CapturedAt.After(entry.CapturedAt) {
+		outcome.SkipReason = BacktestSkipNoExitPrice
+		return outcome
+	}
+
+	outcome.Evaluated = true
+	outcome.EntryPrice, outcome.EntryAt = entry.Price, &entry.CapturedAt
+	outcome.ExitPrice, outcome.ExitAt = exit.Price, &exit.CapturedAt
+	outcome.Value = rec.CurrentPrice / entry.Price * exit.Price
+	outcome.Gain = outcome.Value - outcome.Invested
+	outcome.ReturnPct = outcome.Gain / outcome.Invested * 100
+	return outcome
+}
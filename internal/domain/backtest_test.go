@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceSeries(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	series := NewPriceSeries([]PriceSnapshot{
+		{Symbol: "spy", Price: 110, CapturedAt: start.AddDate(0, 0, 10)},
+		{Symbol: "SPY", Price: 100, CapturedAt: start},
+	})
+
+	closest, ok := series.Closest("SPY", start.AddDate(0, 0, 2))
+	require.True(t, ok)
+	assert.Equal(t, 100.0, closest.Price)
+	closest, _ = series.Closest("spy", start.AddDate(0, 0, 8))
+	assert.Equal(t, 110.0, closest.Price)
+
+	asOf, ok := series.AsOf("SPY", start.AddDate(0, 0, 9))
+	require.True(t, ok)
+	assert.Equal(t, 100.0, asOf.Price)
+	_, ok = series.AsOf("SPY", start.Add(-time.Hour))
+	assert.False(t, ok)
+	_, ok = series.Closest("BTC", start)
+	assert.False(t, ok)
+}
+
+func TestBacktest(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	prices := NewPriceSeries([]PriceSnapshot{
+		{Symbol: "SPY", Price: 100, CapturedAt: start},
+		{Symbol: "SPY", Price: 120, CapturedAt: start.AddDate(0, 1, 0)},
+		{Symbol: "BTC", Price: 40000, CapturedAt: start},
+		{Symbol: "BTC", Price: 30000, CapturedAt: start.AddDate(0, 1, 0)},
+	})
+	records := []AdviceRecord{{
+		ID: 1, Source: AdviceSourceBasic, CreatedAt: start,
+		Recommendations: []Recommendation{
+			{ID: 1, Symbol: "SPY", Action: "buy", CurrentPrice: 500, Status: RecommendationStatusFollowed},
+			{ID: 2, Symbol: "BTC", Action: "buy", CurrentPrice: 400, Status: RecommendationStatusIgnored},
+			{ID: 3, Symbol: "ALT", Action: "buy", CurrentPrice: 100, Status: RecommendationStatusPending},
+			{ID: 4, Symbol: "GOVT", Action: "hold", CurrentPrice: 100, Status: RecommendationStatusPending},
+		},
+	}}
+
+	performance := Backtest(records, prices, "", start.AddDate(0, 2, 0))
+
+	require.Len(t, performance.Advice, 1)
+	outcomes := performance.Advice[0].Recommendations
+	require.Len(t, outcomes, 4)
+	assert.InDelta(t, 600.0, outcomes[0].Value, 0.001)
+	assert.InDelta(t, 20.0, outcomes[0].ReturnPct, 0.001)
+	assert.InDelta(t, 300.0, outcomes[1].Value, 0.001)
+	assert.Equal(t, BacktestSkipNoEntryPrice, outcomes[2].SkipReason)
+	assert.Equal(t, BacktestSkipNotBuy, outcomes[3].SkipReason)
+
+	assert.Equal(t, 2, performance.Totals.Evaluated)
+	assert.Equal(t, 2, performance.Totals.Skipped)
+	assert.InDelta(t, 900.0, performance.Totals.Invested, 0.001)
+	assert.InDelta(t, 0.0, performance.Totals.Gain, 0.001)
+	assert.InDelta(t, 100.0, performance.Followed.Gain, 0.001)
+	assert.Equal(t, performance.Totals, performance.Advice[0].PerformanceTotals)
+
+	// Valued before any later price was seen nothing can be evaluated
+	early := Backtest(records, prices, RecommendationStatusFollowed, start.AddDate(0, 0, 1))
+	require.Len(t, early.Advice[0].Recommendations, 1)
+	assert.Equal(t, BacktestSkipNoExitPrice, early.Advice[0].Recommendations[0].SkipReason)
+}
@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+// Aggregators bank accounts can be linked through
+const (
+	BankProviderPlaid      = "plaid"
+	BankProviderGoCardless = "gocardless"
+)
+
+// BankProviders lists the supported bank sync providers
+var BankProviders = []string{BankProviderPlaid, BankProviderGoCardless}
+
+// IsValidBankProvider checks that bank accounts can be linked through the provider
+func IsValidBankProvider(provider string) bool {
+	return slices.Contains(BankProviders, provider)
+}
+
+// Sync states of a linked bank account
+const (
+	BankLinkStatusPending = "pending" // Linked but not synced yet
+	BankLinkStatusActive  = "active"
+	BankLinkStatusError   = "error" // The last sync failed and is retried on schedule
+	// BankLinkStatusReauthRequired means the bank revoked access, usually
+	// because consent expired; syncing stops until the user links it again
+	BankLinkStatusReauthRequired = "reauth_required"
+)
+
+var (
+	// ErrUnknownBankProvider is returned for providers that are not supported or not configured
+	ErrUnknownBankProvider = errors.New("bank provider must be plaid or gocardless and be configured")
+	// ErrBankReauthRequired is returned by providers when the user has to link the bank again
+	ErrBankReauthRequired = errors.New("bank connection has expired and must be linked again")
+)
+
+// BankLink is a bank account of the user linked through a provider. Accounts
+// linked in one go share a ConnectionID and AccessToken, which the repository
+// encrypts at rest. Cursor is where the next sync resumes.
+type BankLink struct {
+	ID                uint       `gorm:"primaryKey" json:"id"`
+	UserID            uint       `gorm:"uniqueIndex:idx_bank_links_user_provider_account,priority:1;not null" json:"user_id"`
+	Provider          string     `gorm:"type:varchar(20);uniqueIndex:idx_bank_links_user_provider_account,priority:2;not null" json:"provider"`
+	ExternalAccountID string     `gorm:"type:varchar(100);uniqueIndex:idx_bank_links_user_provider_account,priority:3;not null" json:"-"`
+	ConnectionID      string     `gorm:"type:varchar(100);index;not null" json:"-"`
+	AccessToken       string     `gorm:"type:varchar(255);not null" json:"-"`
+	Cursor            string     `gorm:"type:text" json:"-"`
+	Institution       string     `gorm:"type:varchar(100)" json:"institution"`
+	Name              string     `gorm:"type:varchar(100)" json:"name"`
+	Mask              string     `gorm:"type:varchar(4)" json:"mask,omitempty"`
+	Currency          string     `gorm:"type:varchar(3)" json:"currency,omitempty"`
+	Status            string     `gorm:"type:varchar(20);not null;default:pending" json:"status"`
+	LastSyncedAt      *time.Time `json:"last_synced_at,omitempty"`
+	LastError         string     `gorm:"type:text" json:"last_error,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// Syncable reports whether scheduled syncs should pull the account
+func (l *BankLink) Syncable() bool {
+	return l.Status != BankLinkStatusReauthRequired
+}
+
+// BankTransaction records which local transaction a provider's transaction
+// became. Created is false when the sync matched a transaction the user had
+// entered by hand; such transactions are left alone by later syncs.
+type BankTransaction struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	LinkID        uint      `gorm:"uniqueIndex:idx_bank_transactions_link_external,priority:1;not null" json:"link_id"`
+	ExternalID    string    `gorm:"type:varchar(100);uniqueIndex:idx_bank_transactions_link_external,priority:2;not null" json:"external_id"`
+	TransactionID uint      `gorm:"index;not null" json:"transaction_id"`
+	Created       bool      `gorm:"not null;default:false" json:"created"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// BankLinkSession is the first step of linking a bank. Depending on the
+// provider the client opens the provider's widget with Token or sends the
+// user to URL; either way it ends with a token for completing the link.
+type BankLinkSession struct {
+	Provider  string     `json:"provider"`
+	Token     string     `json:"token"`
+	URL       string     `json:"url,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// BankConnection is the access a provider granted to the user's accounts at an institution
+type BankConnection struct {
+	ConnectionID string
+	AccessToken  string
+	Institution  string
+	Accounts     []BankAccount
+}
+
+// BankAccount is an account of a bank connection
+type BankAccount struct {
+	ExternalID string
+	Name       string
+	Mask       string
+	Currency   string
+}
+
+// BankFeedTransaction is a transaction as a provider reports it. Amount is
+// signed: positive amounts came into the account.
+type BankFeedTransaction struct {
+	ExternalID  string
+	Date        time.Time
+	Description string
+	Category    string // The provider's category name, if it has one
+	Amount      Money
+	Pending     bool
+}
+
+// BankFeed is a page of an account's transaction changes since a cursor
+type BankFeed struct {
+	Added    []BankFeedTransaction
+	Modified []BankFeedTransaction
+	Removed  []string // External IDs of transactions the provider withdrew
+	Cursor   string
+	HasMore  bool
+}
+
+// BankSyncResult sums up a sync of a linked account. Matched counts
+// transactions that were already entered by hand and were not recorded again.
+type BankSyncResult struct {
+	LinkID  uint   `json:"link_id"`
+	Status  string `json:"status"`
+	Added   int    `json:"added"`
+	Updated int    `json:"updated"`
+	Removed int    `json:"removed"`
+	Matched int    `json:"matched"`
+}
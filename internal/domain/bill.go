@@ -0,0 +1,236 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Bill limits
+const (
+	DefaultBillRemindDays = 3
+	MaxBillRemindDays     = 30
+	MaxBillCalendarDays   = 366
+)
+
+// Calendar entry kinds
+const (
+	CalendarEntryBill      = "bill"
+	CalendarEntryRecurring = "recurring"
+)
+
+// Bill is a payment a user owes every month on the same day, such as rent
+// or a phone plan. Due days past the end of a short month fall on its last
+// day. RemindDaysBefore is how many days ahead of each due date the user is
+// reminded; LastRemindedFor is the due date of the latest reminder, so each
+// due date is reminded of once.
+type Bill struct {
+	ID               uint       `gorm:"primaryKey" json:"id"`
+	UserID           uint       `gorm:"not null;index" json:"user_id"`
+	Payee            string     `gorm:"type:varchar(100);not null" json:"payee"`
+	Amount           Money      `gorm:"type:integer;not null" json:"amount"`
+	DueDay           int        `gorm:"not null" json:"due_day"`
+	Autopay          bool       `gorm:"not null;default:false" json:"autopay"`
+	CategoryID       *uint      `json:"category_id,omitempty"`
+	Notes            string     `gorm:"type:text" json:"notes,omitempty"`
+	RemindDaysBefore int        `gorm:"not null" json:"remind_days_before"`
+	Active           bool       `gorm:"not null;default:true" json:"active"`
+	LastRemindedFor  *time.Time `json:"last_reminded_for,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// Validate checks the payee, amount, due day and reminder lead time
+func (b *Bill) Validate() error {
+	var v validator
+	b.Payee = strings.TrimSpace(b.Payee)
+	v.check(b.Payee != "", "payee", "is required")
+	v.check(len(b.Payee) <= 100, "payee", "must be at most 100 characters")
+	v.check(b.Amount > 0, "amount", "must be positive")
+	v.check(b.DueDay >= 1 && b.DueDay <= 31, "due_day", "must be between 1 and 31")
+	v.check(b.RemindDaysBefore >= 0 && b.RemindDaysBefore <= MaxBillRemindDays, "remind_days_before",
+		"must be between 0 and 30")
+	return v.err()
+}
+
+// DueDate is the bill's due date in a month
+func (b *Bill) DueDate(year int, month time.Month, loc *time.Location) time.Time {
+	return dayOfMonth(year, month, b.DueDay, loc)
+}
+
+// NextDue is the first due date of the bill on or after the day of t
+func (b *Bill) NextDue(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	due := b.DueDate(day.Year(), day.Month(), day.Location())
+	if due.Before(day) {
+		next := day.AddDate(0, 0, 1-day.Day()).AddDate(0, 1, 0)
+		due = b.DueDate(next.Year(), next.Month(), next.Location())
+	}
+	return due
+}
+
+// dayOfMonth is the day of the month, or its last day for days past the end
+func dayOfMonth(year int, month time.Month, day int, loc *time.Location) time.Time {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, loc).Day()
+	return time.Date(year, month, min(day, last), 0, 0, 0, 0, loc)
+}
+
+// BillDue is published when a bill's reminder is due, RemindDaysBefore days
+// ahead of DueDate
+type BillDue struct {
+	Bill    Bill      `json:"bill"`
+	DueDate time.Time `json:"due_date"`
+}
+
+func (BillDue) EventType() string   { return EventBillDue }
+func (e BillDue) EventUserID() uint { return e.Bill.UserID }
+
+// RecurringTransaction is a transaction that came back once a month over
+// the recent months, expected again on the day of its last occurrence
+type RecurringTransaction struct {
+	Description string    `json:"description"`
+	Type        string    `json:"type"`
+	CategoryID  uint      `json:"category_id"`
+	MerchantID  *uint     `json:"merchant_id,omitempty"`
+	Amount      Money     `json:"amount"` // Average of the months in a row
+	Day         int       `json:"day"`
+	Occurrences int       `json:"occurrences"`
+	LastDate    time.Time `json:"last_date"`
+}
+
+// Recurring detection thresholds
+const (
+	recurringMinMonths = 3
+	recurringMaxGap    = 45 * 24 * time.Hour // Since the last occurrence, beyond which it has stopped
+)
+
+// DetectRecurring finds the transactions that recurred monthly: the same
+// merchant, or the same description for transactions without one, and type
+// once a month for at least the last three months in a row of their
+// history, the latest no longer than 45 days before asOf. Transfers between
+// the user's accounts are neither due nor income, so they are left out.
+func DetectRecurring(transactions []Transaction, asOf time.Time) []RecurringTransaction {
+	groups := make(map[string][]Transaction)
+	var keys []string
+	for _, t := range transactions {
+		if t.IsTransfer() {
+			continue
+		}
+		key := t.Type + "|" + strings.ToLower(strings.TrimSpace(t.Description))
+		if t.MerchantID != nil {
+			key = fmt.Sprintf("%s|merchant:%d", t.Type, *t.MerchantID)
+		}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], t)
+	}
+
+	var recurring []RecurringTransaction
+	for _, key := range keys {
+		occurrences := groups[key]
+		sort.Slice(occurrences, func(i, j int) bool { return occurrences[i].Date.Before(occurrences[j].Date) })
+
+		// Count back the months in a row up to the latest occurrence
+		last := occurrences[len(occurrences)-1]
+		run, total, monthly := 1, last.Amount, true
+		for i := len(occurrences) - 2; i >= 0; i-- {
+			gap := monthIndex(occurrences[i+1].Date) - monthIndex(occurrences[i].Date)
+			if gap == 0 {
+				monthly = false
+			}
+			if gap != 1 {
+				break
+			}
+			run++
+			total += occurrences[i].Amount
+		}
+		if !monthly || run < recurringMinMonths || asOf.Sub(last.Date) > recurringMaxGap {
+			continue
+		}
+		recurring = append(recurring, RecurringTransaction{
+			Description: last.Description, Type: last.Type, CategoryID: last.CategoryID, MerchantID: last.MerchantID,
+			Amount: total / Money(run), Day: last.Date.Day(), Occurrences: run,
+			LastDate: last.Date,
+		})
+	}
+	return recurring
+}
+
+func monthIndex(t time.Time) int {
+	return t.Year()*12 + int(t.Month())
+}
+
+// CalendarEntry is a bill or an expected recurring transaction on a day of
+// the due-date calendar
+type CalendarEntry struct {
+	Date    time.Time `json:"date"`
+	Kind    string    `json:"kind"`
+	Title   string    `json:"title"`
+	Type    string    `json:"type"` // income or expense
+	Amount  Money     `json:"amount"`
+	Autopay bool      `json:"autopay,omitempty"`
+	BillID  *uint     `json:"bill_id,omitempty"`
+}
+
+// DueCalendar lists what a user is due to pay and receive between two
+// dates, in date order
+type DueCalendar struct {
+	From           time.Time       `json:"from"`
+	To             time.Time       `json:"to"`
+	Entries        []CalendarEntry `json:"entries"`
+	TotalDue       Money           `json:"total_due"`
+	ExpectedIncome Money           `json:"expected_income"`
+}
+
+// NewDueCalendar places the bills' due dates and the recurring transactions'
+// expected dates between from and to, both days included. Recurring
+// transactions are expected from the month after their last occurrence, and
+// those paying a bill's payee are left out as that bill already shows.
+func NewDueCalendar(from, to time.Time, bills []Bill, recurring []RecurringTransaction) DueCalendar {
+	calendar := DueCalendar{From: from, To: to, Entries: []CalendarEntry{}}
+	payees := make(map[string]bool, len(bills))
+	for _, bill := range bills {
+		payees[strings.ToLower(bill.Payee)] = true
+	}
+
+	add := func(entry CalendarEntry) {
+		calendar.Entries = append(calendar.Entries, entry)
+		if entry.Type == TransactionTypeIncome {
+			calendar.ExpectedIncome += entry.Amount
+		} else {
+			calendar.TotalDue += entry.Amount
+		}
+	}
+	first := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, from.Location())
+	for month := first; !month.After(to); month = month.AddDate(0, 1, 0) {
+		for i := range bills {
+			due := bills[i].DueDate(month.Year(), month.Month(), month.Location())
+			if due.Before(from) || due.After(to) {
+				continue
+			}
+			add(CalendarEntry{
+				Date: due, Kind: CalendarEntryBill, Title: bills[i].Payee, Type: TransactionTypeExpense,
+				Amount: bills[i].Amount, Autopay: bills[i].Autopay, BillID: &bills[i].ID,
+			})
+		}
+		for _, r := range recurring {
+			if r.Type == TransactionTypeExpense && payees[strings.ToLower(strings.TrimSpace(r.Description))] {
+				continue
+			}
+			expected := dayOfMonth(month.Year(), month.Month(), r.Day, month.Location())
+			if monthIndex(expected) <= monthIndex(r.LastDate) || expected.Before(from) || expected.After(to) {
+				continue
+			}
+			add(CalendarEntry{
+				Date: expected, Kind: CalendarEntryRecurring, Title: r.Description, Type: r.Type, Amount: r.Amount,
+			})
+		}
+	}
+
+	sort.SliceStable(calendar.Entries, func(i, j int) bool {
+		return calendar.Entries[i].Date.Before(calendar.Entries[j].Date)
+	})
+	return calendar
+}
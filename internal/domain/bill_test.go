@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBill_Validate(t *testing.T) {
+	valid := Bill{Payee: " Landlord ", Amount: NewMoney(1200), DueDay: 1, RemindDaysBefore: 3}
+	require.NoError(t, valid.Validate())
+	assert.Equal(t, "Landlord", valid.Payee)
+
+	invalid := Bill{Payee: " ", Amount: 0, DueDay: 32, RemindDaysBefore: 31}
+	err := invalid.Validate()
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	fields := make([]string, 0, len(validationErr.Fields))
+	for _, field := range validationErr.Fields {
+		fields = append(fields, field.Field)
+	}
+	assert.ElementsMatch(t, []string{"payee", "amount", "due_day", "remind_days_before"}, fields)
+}
+
+func TestBill_NextDue(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name   string
+		dueDay int
+		from   time.Time
+		want   time.Time
+	}{
+		{"later this month", 15, date(2025, 3, 10), date(2025, 3, 15)},
+		{"due today", 10, time.Date(2025, 3, 10, 18, 30, 0, 0, time.UTC), date(2025, 3, 10)},
+		{"next month", 5, date(2025, 3, 10), date(2025, 4, 5)},
+		{"last day of a short month", 31, date(2025, 2, 3), date(2025, 2, 28)},
+		{"into the next year", 1, date(2025, 12, 2), date(2026, 1, 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bill := Bill{DueDay: tt.dueDay}
+			assert.Equal(t, tt.want, bill.NextDue(tt.from))
+		})
+	}
+}
+
+func TestDetectRecurring(t *testing.T) {
+	date := func(month time.Month, day int) time.Time { return time.Date(2025, month, day, 9, 0, 0, 0, time.UTC) }
+	merchant := uint(7)
+	transactions := []Transaction{
+		{Type: TransactionTypeExpense, Description: "Netflix", MerchantID: &merchant, Amount: NewMoney(15), Date: date(3, 18)},
+		{Type: TransactionTypeExpense, Description: "NETFLIX.COM", MerchantID: &merchant, Amount: NewMoney(15), Date: date(4, 18)},
+		{Type: TransactionTypeExpense, Description: "Netflix", MerchantID: &merchant, Amount: NewMoney(18), Date: date(5, 19)},
+		{Type: TransactionTypeIncome, Description: "Payroll", Amount: NewMoney(3000), Date: date(2, 25)},
+		{Type: TransactionTypeIncome, Description: "payroll ", Amount: NewMoney(3000), Date: date(4, 25)},
+		{Type: TransactionTypeIncome, Description: "Payroll", Amount: NewMoney(3100), Date: date(5, 26)},
+		{Type: TransactionTypeExpense, Description: "Groceries", Amount: NewMoney(80), Date: date(3, 2)},
+		{Type: TransactionTypeExpense, Description: "Groceries", Amount: NewMoney(60), Date: date(4, 3)},
+		{Type: TransactionTypeExpense, Description: "Groceries", Amount: NewMoney(90), Date: date(5, 4)},
+		{Type: TransactionTypeExpense, Description: "Groceries", Amount: NewMoney(70), Date: date(5, 20)},
+		{Type: TransactionTypeExpense, Description: "Gym", Amount: NewMoney(30), Date: date(1, 5)},
+		{Type: TransactionTypeExpense, Description: "Gym", Amount: NewMoney(30), Date: date(2, 5)},
+		{Type: TransactionTypeExpense, Description: "Gym", Amount: NewMoney(30), Date: date(3, 5)},
+		{Type: TransactionTypeTransfer, Description: "Savings", Amount: NewMoney(500), Date: date(3, 1)},
+		{Type: TransactionTypeTransfer, Description: "Savings", Amount: NewMoney(500), Date: date(4, 1)},
+		{Type: TransactionTypeTransfer, Description: "Savings", Amount: NewMoney(500), Date: date(5, 1)},
+	}
+
+	recurring := DetectRecurring(transactions, date(6, 1))
+
+	// Groceries come more than once a month, the gym stopped, payroll skipped
+	// March and transfers between the user's accounts are neither
+	require.Len(t, recurring, 1)
+	assert.Equal(t, "Netflix", recurring[0].Description)
+	assert.Equal(t, NewMoney(16), recurring[0].Amount)
+	assert.Equal(t, 19, recurring[0].Day)
+	assert.Equal(t, 3, recurring[0].Occurrences)
+}
+
+func TestNewDueCalendar(t *testing.T) {
+	date := func(month time.Month, day int) time.Time { return time.Date(2025, month, day, 0, 0, 0, 0, time.UTC) }
+	bills := []Bill{
+		{ID: 1, Payee: "Landlord", Amount: NewMoney(1200), DueDay: 1, Autopay: true},
+		{ID: 2, Payee: "Phone", Amount: NewMoney(40), DueDay: 31},
+	}
+	recurring := []RecurringTransaction{
+		{Description: "Salary", Type: TransactionTypeIncome, Amount: NewMoney(3000), Day: 25, LastDate: date(5, 25)},
+		{Description: "phone", Type: TransactionTypeExpense, Amount: NewMoney(40), Day: 30, LastDate: date(5, 30)},
+		{Description: "Streaming", Type: TransactionTypeExpense, Amount: NewMoney(15), Day: 18, LastDate: date(6, 18)},
+	}
+
+	calendar := NewDueCalendar(date(6, 10), date(7, 20), bills, recurring)
+
+	titles := make([]string, 0, len(calendar.Entries))
+	for _, entry := range calendar.Entries {
+		titles = append(titles, entry.Date.Format("01-02")+" "+entry.Title)
+	}
+	// The phone is already a bill, and June's streaming payment has been made
+	assert.Equal(t, []string{"06-25 Salary", "06-30 Phone", "07-01 Landlord", "07-18 Streaming"}, titles)
+	assert.Equal(t, CalendarEntryBill, calendar.Entries[2].Kind)
+	assert.True(t, calendar.Entries[2].Autopay)
+	assert.Equal(t, uint(1), *calendar.Entries[2].BillID)
+	assert.Equal(t, NewMoney(1255), calendar.TotalDue)
+	assert.Equal(t, NewMoney(3000), calendar.ExpectedIncome)
+}
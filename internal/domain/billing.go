@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+// Statuses of a premium subscription, as Stripe reports them
+const (
+	SubscriptionActive            = "active"
+	SubscriptionTrialing          = "trialing"
+	SubscriptionPastDue           = "past_due" // A payment failed and is being retried
+	SubscriptionUnpaid            = "unpaid"
+	SubscriptionCanceled          = "canceled"
+	SubscriptionIncomplete        = "incomplete"
+	SubscriptionIncompleteExpired = "incomplete_expired"
+	SubscriptionPaused            = "paused"
+)
+
+// premiumSubscriptionStatuses keep the user on the premium plan. Past due
+// subscriptions keep it while the payment is retried, so a declined card
+// does not cut the user off at once.
+var premiumSubscriptionStatuses = []string{SubscriptionActive, SubscriptionTrialing, SubscriptionPastDue}
+
+// SubscriptionGrantsPremium reports whether a subscription in the status
+// keeps its user on the premium plan
+func SubscriptionGrantsPremium(status string) bool {
+	return slices.Contains(premiumSubscriptionStatuses, status)
+}
+
+// Webhook events of the billing provider that change subscriptions
+const (
+	BillingEventCheckoutCompleted   = "checkout.session.completed"
+	BillingEventSubscriptionCreated = "customer.subscription.created"
+	BillingEventSubscriptionUpdated = "customer.subscription.updated"
+	BillingEventSubscriptionDeleted = "customer.subscription.deleted"
+)
+
+var (
+	// ErrBillingUnavailable is returned when no billing provider is configured
+	ErrBillingUnavailable = errors.New("billing is not configured")
+	// ErrAlreadySubscribed is returned when a user with a premium subscription starts another checkout
+	ErrAlreadySubscribed = errors.New("you already have a premium subscription")
+	// ErrNoBillingAccount is returned when a user who never subscribed opens the customer portal
+	ErrNoBillingAccount = errors.New("you have no billing account yet, subscribe first")
+	// ErrInvalidWebhookSignature is returned for webhook payloads that are not signed by the billing provider
+	ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+)
+
+// Subscription is the user's premium subscription as the billing provider
+// last reported it. The customer and subscription IDs belong to the
+// provider; SubscriptionPeriodEnd is when the paid period ends.
+type Subscription struct {
+	BillingCustomerID     string     `gorm:"type:varchar(100);index" json:"-"`
+	SubscriptionID        string     `gorm:"type:varchar(100)" json:"-"`
+	SubscriptionStatus    string     `gorm:"type:varchar(20)" json:"subscription_status,omitempty"`
+	SubscriptionPeriodEnd *time.Time `json:"subscription_period_end,omitempty"`
+}
+
+// BillingSession is a page of the billing provider the client sends the
+// user to, such as checkout or the customer portal
+type BillingSession struct {
+	ID  string `json:"id,omitempty"`
+	URL string `json:"url"`
+}
+
+// BillingEvent is a webhook event of the billing provider. UserRef is the
+// user ID the checkout was started for; events about subscriptions carry it
+// too, so they can be matched even before the customer ID is stored.
+type BillingEvent struct {
+	ID             string
+	Type           string
+	UserRef        string
+	CustomerID     string
+	SubscriptionID string
+	Status         string
+	PeriodEnd      *time.Time
+}
+
+// Invoice is an invoice of the user's subscription
+type Invoice struct {
+	ID          string    `json:"id"`
+	Number      string    `json:"number,omitempty"`
+	Status      string    `json:"status"`
+	Currency    string    `json:"currency"`
+	AmountDue   Money     `json:"amount_due"`
+	AmountPaid  Money     `json:"amount_paid"`
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+	HostedURL   string    `json:"hosted_url,omitempty"`
+	PDFURL      string    `json:"pdf_url,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
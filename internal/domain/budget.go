@@ -1,29 +1,58 @@
 package domain
 
-import "time"
+import (
+	"errors"
+	"time"
+)
 
-// Budget represents a user's budget for a specific category and period
+// ErrBudgetExists is returned when a category already has an active budget
+// overlapping the new one
+var ErrBudgetExists = errors.New("budget already exists for this category and period")
+
+// How budget periods line up with the calendar. Budgets anchored to the
+// start date run for one period from it. Calendar anchored budgets cover the
+// calendar month, quarter or year, or the ISO week, their start date falls
+// in. Payday anchored ones cover the cycle starting on AnchorDay: a day of
+// the month, e.g. the 25th to the 24th, or for weekly budgets a weekday from
+// 1 (Monday) to 7 (Sunday).
+const (
+	BudgetAnchorStartDate = "start_date"
+	BudgetAnchorCalendar  = "calendar"
+	BudgetAnchorPayday    = "payday"
+)
+
+// BudgetAnchors lists the ways budget periods can line up with the calendar
+var BudgetAnchors = []string{BudgetAnchorStartDate, BudgetAnchorCalendar, BudgetAnchorPayday}
+
+// Budget represents a user's budget for a specific category and period.
+// Budgets with a HouseholdID belong to that household and count the spending
+// of all its members; UserID is then the member who created the budget.
 type Budget struct {
-	ID         uint      `gorm:"primaryKey" json:"id"`
-	UserID     uint      `json:"user_id"`
-	CategoryID uint      `json:"category_id"`
-	Category   Category  `gorm:"foreignKey:CategoryID" json:"category"`
-	Amount     float64   `json:"amount"`
-	Period     string    `gorm:"type:varchar(20);default:'monthly'" json:"period"` // "weekly", "monthly", "yearly"
-	StartDate  time.Time `json:"start_date"`
-	EndDate    time.Time `json:"end_date"`
-	Spent      float64   `gorm:"default:0" json:"spent"`
-	Remaining  float64   `gorm:"default:0" json:"remaining"`
-	IsActive   bool      `gorm:"default:true" json:"is_active"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	UserID      uint      `json:"user_id"`
+	HouseholdID *uint     `gorm:"index" json:"household_id,omitempty"`
+	CategoryID  uint      `json:"category_id"`
+	Category    Category  `gorm:"foreignKey:CategoryID" json:"category"`
+	Amount      Money     `json:"amount"`
+	Period      string    `gorm:"type:varchar(20);default:'monthly'" json:"period"` // "weekly", "monthly", "yearly"
+	Anchor      string    `gorm:"type:varchar(20);default:'start_date'" json:"anchor"`
+	AnchorDay   int       `gorm:"default:0" json:"anchor_day,omitempty"`
+	StartDate   time.Time `json:"start_date"`
+	EndDate     time.Time `json:"end_date"`
+	Spent       Money     `gorm:"default:0" json:"spent"`
+	Remaining   Money     `gorm:"default:0" json:"remaining"`
+	IsActive    bool      `gorm:"default:true" json:"is_active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	// CommentCount is only filled for lists
+	CommentCount *int `gorm:"-" json:"comment_count,omitempty"`
 }
 
 // BudgetSummary represents budget overview for a user
 type BudgetSummary struct {
-	TotalBudget    float64 `json:"total_budget"`
-	TotalSpent     float64 `json:"total_spent"`
-	TotalRemaining float64 `json:"total_remaining"`
+	TotalBudget    Money   `json:"total_budget"`
+	TotalSpent     Money   `json:"total_spent"`
+	TotalRemaining Money   `json:"total_remaining"`
 	PercentageUsed float64 `json:"percentage_used"`
 	BudgetStatus   string  `json:"budget_status"` // "on_track", "warning", "over_budget"
 }
@@ -39,7 +68,7 @@ func (b *Budget) GetBudgetStatus() string {
 		return "no_budget"
 	}
 
-	percentage := (b.Spent / b.Amount) * 100
+	percentage := b.Spent.PercentOf(b.Amount)
 
 	if percentage >= 100 {
 		return "over_budget"
@@ -48,3 +77,76 @@ func (b *Budget) GetBudgetStatus() string {
 	}
 	return "on_track"
 }
+
+// PeriodEnd returns when a budget of the period starting at start ends.
+// Unknown periods run for a month.
+func PeriodEnd(start time.Time, period string) time.Time {
+	switch period {
+	case PeriodWeekly:
+		return start.AddDate(0, 0, 7)
+	case PeriodQuarterly:
+		return start.AddDate(0, 3, 0)
+	case PeriodYearly:
+		return start.AddDate(1, 0, 0)
+	default:
+		return start.AddDate(0, 1, 0)
+	}
+}
+
+// IsAnchored reports whether the budget's period lines up with the calendar
+// or a payday rather than its start date
+func (b *Budget) IsAnchored() bool {
+	return b.Anchor == BudgetAnchorCalendar || b.Anchor == BudgetAnchorPayday
+}
+
+// AlignPeriod moves an anchored budget's dates to the period its start date
+// falls in. The period ends just before the next one starts, so a
+// transaction on the first day of the next cycle is not counted twice.
+// Budgets anchored to their start date are left as they are.
+func (b *Budget) AlignPeriod() {
+	if !b.IsAnchored() || b.StartDate.IsZero() {
+		return
+	}
+	day := 1
+	if b.Anchor == BudgetAnchorPayday {
+		day = b.AnchorDay
+	}
+	b.StartDate, b.EndDate = AnchoredPeriod(b.StartDate, b.Period, day)
+}
+
+// AnchoredPeriod returns the first and last instant of the period containing
+// date. Monthly, quarterly and yearly periods start on the day of the month,
+// or the last day of shorter months, of January, April, July and October for
+// quarters and January for years. Weekly periods start on the ISO weekday,
+// 1 being Monday. Unknown periods are monthly.
+func AnchoredPeriod(date time.Time, period string, day int) (start, end time.Time) {
+	midnight := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	if period == PeriodWeekly {
+		weekday := (int(date.Weekday())+6)%7 + 1
+		start = midnight.AddDate(0, 0, -((weekday - day + 7) % 7))
+		return start, start.AddDate(0, 0, 7).Add(-time.Nanosecond)
+	}
+
+	months := 1
+	switch period {
+	case PeriodQuarterly:
+		months = 3
+	case PeriodYearly:
+		months = 12
+	}
+	first := time.Month((int(date.Month())-1)/months*months + 1)
+	start = anchorDate(date.Year(), first, day, date.Location())
+	if midnight.Before(start) {
+		start = anchorDate(date.Year(), first-time.Month(months), day, date.Location())
+	}
+	next := anchorDate(start.Year(), start.Month()+time.Month(months), day, date.Location())
+	return start, next.Add(-time.Nanosecond)
+}
+
+// anchorDate returns the day of the month, or the month's last day if it is
+// shorter. Months outside 1 to 12 roll over into the neighbouring years.
+func anchorDate(year int, month time.Month, day int, loc *time.Location) time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(day, last)-1)
+}
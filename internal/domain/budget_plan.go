@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// BudgetPlanVersion is the version of the budget plan format exports write
+// and imports read
+const BudgetPlanVersion = 1
+
+const (
+	maxBudgetPlanItems      = 100
+	maxBudgetPlanNameLength = 100
+)
+
+// What importing a budget plan does with each of its budgets
+const (
+	BudgetPlanActionCreate    = "create"
+	BudgetPlanActionUpdate    = "update"
+	BudgetPlanActionUnchanged = "unchanged"
+	BudgetPlanActionSkip      = "skip"
+)
+
+// BudgetPlan is a shareable set of budgets, e.g. the plan a financial coach
+// hands to clients. Budgets name their categories rather than pointing at
+// them, since category IDs only mean something in the exporting account.
+type BudgetPlan struct {
+	Version    int              `json:"version"`
+	Name       string           `json:"name"`
+	ExportedAt time.Time        `json:"exported_at"`
+	Budgets    []BudgetPlanItem `json:"budgets"`
+}
+
+// BudgetPlanItem is one budget of a plan. CategoryID is the category's ID
+// where the plan was exported, which importers may map to one of their own;
+// Parent names the parent of subcategories.
+type BudgetPlanItem struct {
+	Category   string `json:"category"`
+	Parent     string `json:"parent,omitempty"`
+	CategoryID uint   `json:"category_id,omitempty"`
+	Amount     Money  `json:"amount"`
+	Period     string `json:"period"`
+}
+
+// Validate checks the plan's version, name and budgets
+func (p *BudgetPlan) Validate() error {
+	var v validator
+	v.check(p.Version == BudgetPlanVersion, "version", fmt.Sprintf("must be %d", BudgetPlanVersion))
+	v.check(len([]rune(p.Name)) <= maxBudgetPlanNameLength, "name", "must be at most 100 characters")
+	v.check(len(p.Budgets) > 0, "budgets", "must list at least one budget")
+	v.check(len(p.Budgets) <= maxBudgetPlanItems, "budgets", "must list at most 100 budgets")
+	for i, item := range p.Budgets {
+		field := fmt.Sprintf("budgets[%d]", i)
+		v.check(strings.TrimSpace(item.Category) != "", field+".category", "is required")
+		v.check(item.Amount > 0, field+".amount", "must be greater than zero")
+		v.check(slices.Contains(budgetPeriods, item.Period), field+".period", "must be weekly, monthly, quarterly or yearly")
+	}
+	return v.err()
+}
+
+// BudgetPlanImport asks to apply a plan to the user's budgets. CategoryMap
+// maps the plan's category IDs to the user's; unmapped budgets go to the
+// user's expense category of the same name. Without StartDate the budgets
+// start with the current month.
+type BudgetPlanImport struct {
+	Plan        BudgetPlan    `json:"plan"`
+	CategoryMap map[uint]uint `json:"category_map,omitempty"`
+	StartDate   time.Time     `json:"-"`
+}
+
+// BudgetPlanChange is what importing one budget of a plan does. Current is
+// the amount of the user's budget it replaces, for updates and unchanged ones.
+type BudgetPlanChange struct {
+	Action           string `json:"action"`
+	Category         string `json:"category"`
+	SourceCategoryID uint   `json:"source_category_id,omitempty"`
+	CategoryID       uint   `json:"category_id,omitempty"`
+	BudgetID         uint   `json:"budget_id,omitempty"`
+	Period           string `json:"period"`
+	Amount           Money  `json:"amount"`
+	Current          *Money `json:"current,omitempty"`
+	Reason           string `json:"reason,omitempty"`
+}
+
+// BudgetPlanPreview lists what importing a plan does, or did when Applied
+type BudgetPlanPreview struct {
+	Name      string             `json:"name"`
+	StartDate time.Time          `json:"start_date"`
+	Applied   bool               `json:"applied"`
+	Changes   []BudgetPlanChange `json:"changes"`
+	Created   int                `json:"created"`
+	Updated   int                `json:"updated"`
+	Unchanged int                `json:"unchanged"`
+	Skipped   int                `json:"skipped"`
+}
+
+// Add appends a change and counts it under its action
+func (p *BudgetPlanPreview) Add(change BudgetPlanChange) {
+	p.Changes = append(p.Changes, change)
+	switch change.Action {
+	case BudgetPlanActionCreate:
+		p.Created++
+	case BudgetPlanActionUpdate:
+		p.Updated++
+	case BudgetPlanActionUnchanged:
+		p.Unchanged++
+	case BudgetPlanActionSkip:
+		p.Skipped++
+	}
+}
@@ -0,0 +1,239 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Simulation horizon limits
+const (
+	DefaultSimulationMonths = 6
+	MaxSimulationMonths     = 24
+)
+
+// BudgetChange is a hypothetical change to the monthly spending of an
+// expense category: either by a percentage, e.g. -30 to cut it by 30%, or by
+// an amount, e.g. 200 to spend that much more
+type BudgetChange struct {
+	CategoryID uint     `json:"category_id"`
+	Percent    *float64 `json:"percent,omitempty"`
+	Amount     *Money   `json:"amount,omitempty"`
+}
+
+// apply returns the monthly spending after the change, never below zero
+func (c BudgetChange) apply(monthly Money) Money {
+	if c.Percent != nil {
+		monthly = monthly.Mul(1 + *c.Percent/100)
+	}
+	if c.Amount != nil {
+		monthly += *c.Amount
+	}
+	return max(monthly, 0)
+}
+
+// BudgetSimulationRequest lists the changes to simulate. IncomeChange is
+// added to the monthly income; Months defaults to DefaultSimulationMonths.
+type BudgetSimulationRequest struct {
+	Changes      []BudgetChange `json:"changes"`
+	IncomeChange Money          `json:"income_change"`
+	Months       int            `json:"months"`
+	StartDate    time.Time      `json:"-"`
+}
+
+// Validate checks the horizon and that every change names a category and
+// says by how much it changes
+func (r BudgetSimulationRequest) Validate() error {
+	var v validator
+	v.check(r.Months >= 0 && r.Months <= MaxSimulationMonths, "months",
+		"must be between 1 and "+strconv.Itoa(MaxSimulationMonths))
+	seen := make(map[uint]bool, len(r.Changes))
+	for i, change := range r.Changes {
+		field := fmt.Sprintf("changes[%d]", i)
+		v.check(change.CategoryID != 0, field+".category_id", "is required")
+		v.check(!seen[change.CategoryID], field+".category_id", "must not be changed twice")
+		seen[change.CategoryID] = true
+		v.check((change.Percent == nil) != (change.Amount == nil), field, "must give either percent or amount")
+		v.check(change.Percent == nil || *change.Percent >= -100, field+".percent", "must not cut more than 100%")
+	}
+	return v.err()
+}
+
+// CategorySpending is what a user spends in a category in an average month
+type CategorySpending struct {
+	CategoryID   uint   `json:"category_id"`
+	CategoryName string `json:"category_name"`
+	Monthly      Money  `json:"monthly"`
+}
+
+// SpendingBaseline is a user's average month, from which simulations project
+type SpendingBaseline struct {
+	MonthlyIncome  Money              `json:"monthly_income"`
+	Categories     []CategorySpending `json:"categories"`
+	MonthsAnalyzed int                `json:"months_analyzed"`
+}
+
+// SimulationScenario sums up one side of a simulation
+type SimulationScenario struct {
+	MonthlyIncome   Money   `json:"monthly_income"`
+	MonthlyExpenses Money   `json:"monthly_expenses"`
+	MonthlySavings  Money   `json:"monthly_savings"`
+	SavingsRate     float64 `json:"savings_rate"`
+	TotalSavings    Money   `json:"total_savings"` // Over the whole horizon
+}
+
+// CategoryProjection compares a category's monthly spending before and
+// after the changes
+type CategoryProjection struct {
+	CategoryID   uint   `json:"category_id"`
+	CategoryName string `json:"category_name"`
+	Baseline     Money  `json:"baseline"`
+	Simulated    Money  `json:"simulated"`
+	Difference   Money  `json:"difference"`
+}
+
+// CashFlowMonth is one projected month. The balances are the savings
+// accumulated since the start of the simulation.
+type CashFlowMonth struct {
+	Month             string `json:"month"` // "YYYY-MM"
+	BaselineIncome    Money  `json:"baseline_income"`
+	SimulatedIncome   Money  `json:"simulated_income"`
+	BaselineExpenses  Money  `json:"baseline_expenses"`
+	SimulatedExpenses Money  `json:"simulated_expenses"`
+	BaselineBalance   Money  `json:"baseline_balance"`
+	SimulatedBalance  Money  `json:"simulated_balance"`
+}
+
+// GoalProjection is when an active goal would be reached if all monthly
+// savings went into it. Completion dates are nil when nothing is saved.
+type GoalProjection struct {
+	GoalID              uint       `json:"goal_id"`
+	Title               string     `json:"title"`
+	Remaining           Money      `json:"remaining"`
+	TargetDate          time.Time  `json:"target_date"`
+	BaselineCompletion  *time.Time `json:"baseline_completion,omitempty"`
+	SimulatedCompletion *time.Time `json:"simulated_completion,omitempty"`
+	OnTrack             bool       `json:"on_track"` // The simulated completion is not after the target date
+}
+
+// BudgetSimulation is the outcome of a what-if budget: the user's average
+// month as it is and with the changes, projected over the horizon
+type BudgetSimulation struct {
+	Months int `json:"months"`
+	// MonthsAnalyzed is how many months of history the baseline averages
+	MonthsAnalyzed int                  `json:"months_analyzed"`
+	Baseline       SimulationScenario   `json:"baseline"`
+	Simulated      SimulationScenario   `json:"simulated"`
+	Categories     []CategoryProjection `json:"categories"`
+	CashFlow       []CashFlowMonth      `json:"cash_flow"`
+	Goals          []GoalProjection     `json:"goals"`
+}
+
+// SimulateBudget applies the changes to the baseline and projects both over
+// the requested months from the start date. Categories that are changed but
+// have no spending yet start from zero; names maps their IDs to names.
+func SimulateBudget(baseline SpendingBaseline, req BudgetSimulationRequest, names map[uint]string, goals []FinancialGoal) BudgetSimulation {
+	months := req.Months
+	if months == 0 {
+		months = DefaultSimulationMonths
+	}
+	changes := make(map[uint]BudgetChange, len(req.Changes))
+	for _, change := range req.Changes {
+		changes[change.CategoryID] = change
+	}
+
+	simulation := BudgetSimulation{Months: months, MonthsAnalyzed: baseline.MonthsAnalyzed, Categories: []CategoryProjection{}, CashFlow: []CashFlowMonth{}, Goals: []GoalProjection{}}
+	var baselineExpenses, simulatedExpenses Money
+	project := func(categoryID uint, name string, monthly Money) {
+		simulated := monthly
+		if change, ok := changes[categoryID]; ok {
+			simulated = change.apply(monthly)
+			delete(changes, categoryID)
+		}
+		baselineExpenses += monthly
+		simulatedExpenses += simulated
+		simulation.Categories = append(simulation.Categories, CategoryProjection{
+			CategoryID: categoryID, CategoryName: name,
+			Baseline: monthly, Simulated: simulated, Difference: simulated - monthly,
+		})
+	}
+	for _, category := range baseline.Categories {
+		project(category.CategoryID, category.CategoryName, category.Monthly)
+	}
+	for _, change := range req.Changes {
+		if _, pending := changes[change.CategoryID]; pending {
+			project(change.CategoryID, names[change.CategoryID], 0)
+		}
+	}
+	sort.SliceStable(simulation.Categories, func(i, j int) bool {
+		return simulation.Categories[i].Baseline > simulation.Categories[j].Baseline
+	})
+
+	simulatedIncome := max(baseline.MonthlyIncome+req.IncomeChange, 0)
+	simulation.Baseline = scenario(baseline.MonthlyIncome, baselineExpenses, months)
+	simulation.Simulated = scenario(simulatedIncome, simulatedExpenses, months)
+
+	start := time.Date(req.StartDate.Year(), req.StartDate.Month(), 1, 0, 0, 0, 0, req.StartDate.Location())
+	var baselineBalance, simulatedBalance Money
+	for i := 0; i < months; i++ {
+		baselineBalance += simulation.Baseline.MonthlySavings
+		simulatedBalance += simulation.Simulated.MonthlySavings
+		simulation.CashFlow = append(simulation.CashFlow, CashFlowMonth{
+			Month:             start.AddDate(0, i, 0).Format("2006-01"),
+			BaselineIncome:    baseline.MonthlyIncome,
+			SimulatedIncome:   simulatedIncome,
+			BaselineExpenses:  baselineExpenses,
+			SimulatedExpenses: simulatedExpenses,
+			BaselineBalance:   baselineBalance,
+			SimulatedBalance:  simulatedBalance,
+		})
+	}
+
+	for _, goal := range goals {
+		remaining := max(goal.TargetAmount-goal.CurrentAmount, 0)
+		projection := GoalProjection{
+			GoalID:              goal.ID,
+			Title:               goal.Title,
+			Remaining:           remaining,
+			TargetDate:          goal.TargetDate,
+			BaselineCompletion:  completion(start, remaining, simulation.Baseline.MonthlySavings),
+			SimulatedCompletion: completion(start, remaining, simulation.Simulated.MonthlySavings),
+		}
+		projection.OnTrack = projection.SimulatedCompletion != nil &&
+			(goal.TargetDate.IsZero() || !projection.SimulatedCompletion.After(goal.TargetDate))
+		simulation.Goals = append(simulation.Goals, projection)
+	}
+	return simulation
+}
+
+func scenario(income, expenses Money, months int) SimulationScenario {
+	savings := income - expenses
+	rate := 0.0
+	if income > 0 {
+		rate = math.Round(savings.PercentOf(income)*100) / 100
+	}
+	return SimulationScenario{
+		MonthlyIncome:   income,
+		MonthlyExpenses: expenses,
+		MonthlySavings:  savings,
+		SavingsRate:     rate,
+		TotalSavings:    savings * Money(months),
+	}
+}
+
+// completion is the last day of the month in which saving monthly from
+// start covers the remaining amount, or nil when nothing is saved
+func completion(start time.Time, remaining, monthly Money) *time.Time {
+	if remaining == 0 {
+		done := start
+		return &done
+	}
+	if monthly <= 0 {
+		return nil
+	}
+	months := int((remaining + monthly - 1) / monthly)
+	done := start.AddDate(0, months, -1)
+	return &done
+}
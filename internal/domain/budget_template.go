@@ -0,0 +1,182 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// Budget template keys
+const (
+	BudgetTemplateFiftyThirtyTwenty = "50_30_20"
+	BudgetTemplateZeroBased         = "zero_based"
+	BudgetTemplateStudent           = "student"
+	BudgetTemplateFamily            = "family"
+)
+
+// Budget allocation groups, after the 50/30/20 rule
+const (
+	AllocationNeeds = "needs"
+	AllocationWants = "wants"
+)
+
+// IncomeHistoryMonths is how many full months of income a template budget
+// averages when no monthly income is given
+const IncomeHistoryMonths = 3
+
+// BudgetAllocation gives a share of monthly income to one of the default
+// expense categories
+type BudgetAllocation struct {
+	Category string `json:"category"`
+	Group    string `json:"group"`
+	Percent  int    `json:"percent"`
+}
+
+// BudgetTemplate splits monthly income into category budgets. Whatever the
+// allocations leave of the income is meant to be saved.
+type BudgetTemplate struct {
+	Key         string             `json:"key"`
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Allocations []BudgetAllocation `json:"allocations"`
+}
+
+// SavingsPercent is the share of income the template leaves unbudgeted
+func (t BudgetTemplate) SavingsPercent() int {
+	total := 0
+	for _, allocation := range t.Allocations {
+		total += allocation.Percent
+	}
+	return 100 - total
+}
+
+// Amount is the allocation's share of the monthly income, rounded to the cent
+func (a BudgetAllocation) Amount(monthlyIncome Money) Money {
+	return Money((int64(monthlyIncome)*int64(a.Percent) + 50) / 100)
+}
+
+// budgetTemplates is the template library; allocations name default categories
+var budgetTemplates = []BudgetTemplate{
+	{
+		Key:         BudgetTemplateFiftyThirtyTwenty,
+		Name:        "50/30/20",
+		Description: "Half of income for needs, 30% for wants and 20% saved",
+		Allocations: []BudgetAllocation{
+			{"Housing", AllocationNeeds, 25},
+			{"Food & Dining", AllocationNeeds, 10},
+			{"Bills & Utilities", AllocationNeeds, 8},
+			{"Transportation", AllocationNeeds, 5},
+			{"Healthcare", AllocationNeeds, 2},
+			{"Shopping", AllocationWants, 10},
+			{"Entertainment", AllocationWants, 10},
+			{"Travel", AllocationWants, 7},
+			{"Other Expenses", AllocationWants, 3},
+		},
+	},
+	{
+		Key:         BudgetTemplateZeroBased,
+		Name:        "Zero-based",
+		Description: "Every unit of income gets a job; 15% of it is saving",
+		Allocations: []BudgetAllocation{
+			{"Housing", AllocationNeeds, 28},
+			{"Food & Dining", AllocationNeeds, 12},
+			{"Bills & Utilities", AllocationNeeds, 8},
+			{"Transportation", AllocationNeeds, 8},
+			{"Healthcare", AllocationNeeds, 5},
+			{"Education", AllocationNeeds, 3},
+			{"Shopping", AllocationWants, 6},
+			{"Entertainment", AllocationWants, 5},
+			{"Travel", AllocationWants, 5},
+			{"Other Expenses", AllocationWants, 5},
+		},
+	},
+	{
+		Key:         BudgetTemplateStudent,
+		Name:        "Student",
+		Description: "Rent, food and study costs first on a small income",
+		Allocations: []BudgetAllocation{
+			{"Housing", AllocationNeeds, 30},
+			{"Food & Dining", AllocationNeeds, 20},
+			{"Education", AllocationNeeds, 15},
+			{"Transportation", AllocationNeeds, 8},
+			{"Bills & Utilities", AllocationNeeds, 5},
+			{"Healthcare", AllocationNeeds, 2},
+			{"Entertainment", AllocationWants, 7},
+			{"Shopping", AllocationWants, 5},
+			{"Other Expenses", AllocationWants, 3},
+		},
+	},
+	{
+		Key:         BudgetTemplateFamily,
+		Name:        "Family",
+		Description: "A household with children: more for food, health and schooling",
+		Allocations: []BudgetAllocation{
+			{"Housing", AllocationNeeds, 28},
+			{"Food & Dining", AllocationNeeds, 15},
+			{"Transportation", AllocationNeeds, 10},
+			{"Bills & Utilities", AllocationNeeds, 8},
+			{"Healthcare", AllocationNeeds, 7},
+			{"Education", AllocationNeeds, 7},
+			{"Shopping", AllocationWants, 5},
+			{"Entertainment", AllocationWants, 4},
+			{"Travel", AllocationWants, 3},
+			{"Other Expenses", AllocationWants, 3},
+		},
+	},
+}
+
+// BudgetTemplates returns the template library
+func BudgetTemplates() []BudgetTemplate {
+	return slices.Clone(budgetTemplates)
+}
+
+// FindBudgetTemplate returns the template with the key
+func FindBudgetTemplate(key string) (BudgetTemplate, bool) {
+	for _, template := range budgetTemplates {
+		if template.Key == key {
+			return template, true
+		}
+	}
+	return BudgetTemplate{}, false
+}
+
+// BudgetTemplateRequest asks for a set of monthly budgets from a template.
+// Without MonthlyIncome the user's average income is used.
+type BudgetTemplateRequest struct {
+	Template      string
+	MonthlyIncome *Money
+	StartDate     time.Time
+}
+
+// Validate checks the template key and the income
+func (r BudgetTemplateRequest) Validate() error {
+	var v validator
+	_, found := FindBudgetTemplate(r.Template)
+	keys := make([]string, len(budgetTemplates))
+	for i, template := range budgetTemplates {
+		keys[i] = template.Key
+	}
+	v.check(found, "template", "must be one of "+strings.Join(keys, ", "))
+	v.check(r.MonthlyIncome == nil || *r.MonthlyIncome > 0, "monthly_income", "must be greater than zero")
+	return v.err()
+}
+
+// SkippedAllocation is an allocation of a template that did not become a budget
+type SkippedAllocation struct {
+	Category string `json:"category"`
+	Amount   Money  `json:"amount"`
+	Reason   string `json:"reason"`
+}
+
+// BudgetTemplateResult is the set of budgets created from a template.
+// IncomeSource is "provided" or "average"; MonthsAnalyzed is how many
+// months with income the average covers.
+type BudgetTemplateResult struct {
+	Template       string              `json:"template"`
+	MonthlyIncome  Money               `json:"monthly_income"`
+	IncomeSource   string              `json:"income_source"`
+	MonthsAnalyzed int                 `json:"months_analyzed"`
+	Budgets        []Budget            `json:"budgets"`
+	Skipped        []SkippedAllocation `json:"skipped"`
+	Savings        Money               `json:"savings"`
+}
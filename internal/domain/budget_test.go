@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestBudget_Creation(t *testing.T) {
@@ -18,23 +19,23 @@ func TestBudget_Creation(t *testing.T) {
 			budget: Budget{
 				UserID:     1,
 				CategoryID: 1,
-				Amount:     500.00,
+				Amount:     NewMoney(500.00),
 				Period:     "monthly",
 				StartDate:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
 				EndDate:    time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC),
-				Spent:      150.00,
-				Remaining:  350.00,
+				Spent:      NewMoney(150.00),
+				Remaining:  NewMoney(350.00),
 				IsActive:   true,
 			},
 			want: Budget{
 				UserID:     1,
 				CategoryID: 1,
-				Amount:     500.00,
+				Amount:     NewMoney(500.00),
 				Period:     "monthly",
 				StartDate:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
 				EndDate:    time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC),
-				Spent:      150.00,
-				Remaining:  350.00,
+				Spent:      NewMoney(150.00),
+				Remaining:  NewMoney(350.00),
 				IsActive:   true,
 			},
 		},
@@ -43,23 +44,23 @@ func TestBudget_Creation(t *testing.T) {
 			budget: Budget{
 				UserID:     2,
 				CategoryID: 2,
-				Amount:     100.00,
+				Amount:     NewMoney(100.00),
 				Period:     "weekly",
 				StartDate:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
 				EndDate:    time.Date(2024, 1, 7, 23, 59, 59, 0, time.UTC),
-				Spent:      25.00,
-				Remaining:  75.00,
+				Spent:      NewMoney(25.00),
+				Remaining:  NewMoney(75.00),
 				IsActive:   true,
 			},
 			want: Budget{
 				UserID:     2,
 				CategoryID: 2,
-				Amount:     100.00,
+				Amount:     NewMoney(100.00),
 				Period:     "weekly",
 				StartDate:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
 				EndDate:    time.Date(2024, 1, 7, 23, 59, 59, 0, time.UTC),
-				Spent:      25.00,
-				Remaining:  75.00,
+				Spent:      NewMoney(25.00),
+				Remaining:  NewMoney(75.00),
 				IsActive:   true,
 			},
 		},
@@ -68,23 +69,23 @@ func TestBudget_Creation(t *testing.T) {
 			budget: Budget{
 				UserID:     1,
 				CategoryID: 3,
-				Amount:     12000.00,
+				Amount:     NewMoney(12000.00),
 				Period:     "yearly",
 				StartDate:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
 				EndDate:    time.Date(2024, 12, 31, 23, 59, 59, 0, time.UTC),
-				Spent:      3000.00,
-				Remaining:  9000.00,
+				Spent:      NewMoney(3000.00),
+				Remaining:  NewMoney(9000.00),
 				IsActive:   true,
 			},
 			want: Budget{
 				UserID:     1,
 				CategoryID: 3,
-				Amount:     12000.00,
+				Amount:     NewMoney(12000.00),
 				Period:     "yearly",
 				StartDate:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
 				EndDate:    time.Date(2024, 12, 31, 23, 59, 59, 0, time.UTC),
-				Spent:      3000.00,
-				Remaining:  9000.00,
+				Spent:      NewMoney(3000.00),
+				Remaining:  NewMoney(9000.00),
 				IsActive:   true,
 			},
 		},
@@ -125,11 +126,11 @@ func TestBudget_CalculateRemaining(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			budget := Budget{
-				Amount: tt.amount,
-				Spent:  tt.spent,
+				Amount: NewMoney(tt.amount),
+				Spent:  NewMoney(tt.spent),
 			}
 			budget.CalculateRemaining()
-			assert.Equal(t, tt.expected, budget.Remaining)
+			assert.Equal(t, NewMoney(tt.expected), budget.Remaining)
 		})
 	}
 }
@@ -159,8 +160,8 @@ func TestBudget_GetBudgetStatus(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			budget := Budget{
-				Amount: tt.amount,
-				Spent:  tt.spent,
+				Amount: NewMoney(tt.amount),
+				Spent:  NewMoney(tt.spent),
 			}
 			status := budget.GetBudgetStatus()
 			assert.Equal(t, tt.expected, status)
@@ -174,7 +175,7 @@ func TestBudget_DefaultValues(t *testing.T) {
 		budget := Budget{
 			UserID:     1,
 			CategoryID: 1,
-			Amount:     500.00,
+			Amount:     NewMoney(500.00),
 			// Period not set, should default to 'monthly' in database
 		}
 		// Note: GORM defaults are applied at database level, not struct level
@@ -186,11 +187,11 @@ func TestBudget_DefaultValues(t *testing.T) {
 		budget := Budget{
 			UserID:     1,
 			CategoryID: 1,
-			Amount:     500.00,
+			Amount:     NewMoney(500.00),
 			// Spent not set, should default to 0 in database
 		}
 		// Note: GORM defaults are applied at database level, not struct level
-		assert.Equal(t, 0.0, budget.Spent) // Struct default is zero value
+		assert.Equal(t, NewMoney(0.0), budget.Spent) // Struct default is zero value
 	})
 
 	t.Run("default remaining should be 0", func(t *testing.T) {
@@ -198,11 +199,11 @@ func TestBudget_DefaultValues(t *testing.T) {
 		budget := Budget{
 			UserID:     1,
 			CategoryID: 1,
-			Amount:     500.00,
+			Amount:     NewMoney(500.00),
 			// Remaining not set, should default to 0 in database
 		}
 		// Note: GORM defaults are applied at database level, not struct level
-		assert.Equal(t, 0.0, budget.Remaining) // Struct default is zero value
+		assert.Equal(t, NewMoney(0.0), budget.Remaining) // Struct default is zero value
 	})
 
 	t.Run("default is_active should be true", func(t *testing.T) {
@@ -210,7 +211,7 @@ func TestBudget_DefaultValues(t *testing.T) {
 		budget := Budget{
 			UserID:     1,
 			CategoryID: 1,
-			Amount:     500.00,
+			Amount:     NewMoney(500.00),
 			// IsActive not set, should default to true in database
 		}
 		// Note: GORM defaults are applied at database level, not struct level
@@ -235,11 +236,11 @@ func TestBudget_CategoryRelationship(t *testing.T) {
 			UserID:     1,
 			CategoryID: 1,
 			Category:   category,
-			Amount:     500.00,
+			Amount:     NewMoney(500.00),
 			Period:     "monthly",
 			StartDate:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
 			EndDate:    time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC),
-			Spent:      150.00,
+			Spent:      NewMoney(150.00),
 			IsActive:   true,
 		}
 
@@ -254,7 +255,7 @@ func TestBudget_CategoryRelationship(t *testing.T) {
 			ID:         1,
 			UserID:     1,
 			CategoryID: 1,
-			Amount:     500.00,
+			Amount:     NewMoney(500.00),
 			Period:     "monthly",
 			// Category not loaded
 		}
@@ -268,16 +269,16 @@ func TestBudget_CategoryRelationship(t *testing.T) {
 func TestBudgetSummary_Creation(t *testing.T) {
 	t.Run("create budget summary", func(t *testing.T) {
 		summary := BudgetSummary{
-			TotalBudget:    2000.00,
-			TotalSpent:     1200.00,
-			TotalRemaining: 800.00,
+			TotalBudget:    NewMoney(2000.00),
+			TotalSpent:     NewMoney(1200.00),
+			TotalRemaining: NewMoney(800.00),
 			PercentageUsed: 60.0,
 			BudgetStatus:   "on_track",
 		}
 
-		assert.Equal(t, 2000.00, summary.TotalBudget)
-		assert.Equal(t, 1200.00, summary.TotalSpent)
-		assert.Equal(t, 800.00, summary.TotalRemaining)
+		assert.Equal(t, NewMoney(2000.00), summary.TotalBudget)
+		assert.Equal(t, NewMoney(1200.00), summary.TotalSpent)
+		assert.Equal(t, NewMoney(800.00), summary.TotalRemaining)
 		assert.Equal(t, 60.0, summary.PercentageUsed)
 		assert.Equal(t, "on_track", summary.BudgetStatus)
 	})
@@ -291,7 +292,7 @@ func TestBudget_PeriodTypes(t *testing.T) {
 			budget := Budget{
 				UserID:     1,
 				CategoryID: 1,
-				Amount:     500.00,
+				Amount:     NewMoney(500.00),
 				Period:     period,
 				StartDate:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
 				EndDate:    time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC),
@@ -307,7 +308,7 @@ func TestBudget_ActiveStatus(t *testing.T) {
 		budget := Budget{
 			UserID:     1,
 			CategoryID: 1,
-			Amount:     500.00,
+			Amount:     NewMoney(500.00),
 			Period:     "monthly",
 			IsActive:   true,
 		}
@@ -318,10 +319,171 @@ func TestBudget_ActiveStatus(t *testing.T) {
 		budget := Budget{
 			UserID:     1,
 			CategoryID: 1,
-			Amount:     500.00,
+			Amount:     NewMoney(500.00),
 			Period:     "monthly",
 			IsActive:   false,
 		}
 		assert.False(t, budget.IsActive)
 	})
 }
+
+func TestBudgetTemplates(t *testing.T) {
+	categories := make(map[string]bool)
+	for _, category := range GetDefaultCategories() {
+		if category.Type == TransactionTypeExpense {
+			categories[category.Name] = true
+		}
+	}
+
+	templates := BudgetTemplates()
+	require.Len(t, templates, 4)
+	for _, template := range templates {
+		assert.GreaterOrEqual(t, template.SavingsPercent(), 0, template.Key)
+		for _, allocation := range template.Allocations {
+			assert.True(t, categories[allocation.Category], "%s allocates to unknown category %q", template.Key, allocation.Category)
+		}
+	}
+
+	template, ok := FindBudgetTemplate(BudgetTemplateFiftyThirtyTwenty)
+	require.True(t, ok)
+	assert.Equal(t, 20, template.SavingsPercent())
+	assert.Equal(t, NewMoney(33.33), BudgetAllocation{Percent: 10}.Amount(NewMoney(333.33)))
+}
+
+func TestBudgetTemplateRequestValidate(t *testing.T) {
+	zero := Money(0)
+	assert.NoError(t, BudgetTemplateRequest{Template: BudgetTemplateFamily}.Validate())
+	assert.ElementsMatch(t, []string{"template", "monthly_income"},
+		fieldsOf(t, BudgetTemplateRequest{Template: "lavish", MonthlyIncome: &zero}.Validate()))
+}
+
+func TestBudgetSimulationRequestValidate(t *testing.T) {
+	cut, overcut := -30.0, -150.0
+	extra := NewMoney(200)
+
+	assert.NoError(t, BudgetSimulationRequest{}.Validate(), "an empty request simulates the baseline")
+	assert.NoError(t, BudgetSimulationRequest{Months: 12, Changes: []BudgetChange{{CategoryID: 1, Percent: &cut}, {CategoryID: 2, Amount: &extra}}}.Validate())
+
+	err := BudgetSimulationRequest{
+		Months: 36,
+		Changes: []BudgetChange{
+			{CategoryID: 1, Percent: &cut},
+			{CategoryID: 1, Amount: &extra},
+			{Percent: &overcut},
+			{CategoryID: 3, Percent: &cut, Amount: &extra},
+		},
+	}.Validate()
+	assert.ElementsMatch(t, []string{
+		"months", "changes[1].category_id", "changes[2].category_id", "changes[2].percent", "changes[3]",
+	}, fieldsOf(t, err))
+}
+
+func TestSimulateBudget(t *testing.T) {
+	cut := -30.0
+	extra, gym := NewMoney(200), NewMoney(50)
+	baseline := SpendingBaseline{
+		MonthlyIncome: NewMoney(4000),
+		Categories: []CategorySpending{
+			{CategoryID: 1, CategoryName: "Food & Dining", Monthly: NewMoney(1000)},
+			{CategoryID: 2, CategoryName: "Housing", Monthly: NewMoney(2000)},
+			{CategoryID: 3, CategoryName: "Transportation", Monthly: NewMoney(200)},
+		},
+		MonthsAnalyzed: 3,
+	}
+	req := BudgetSimulationRequest{
+		Changes: []BudgetChange{
+			{CategoryID: 1, Percent: &cut},
+			{CategoryID: 3, Amount: &extra},
+			{CategoryID: 9, Amount: &gym},
+		},
+		StartDate: time.Date(2024, 5, 14, 0, 0, 0, 0, time.UTC),
+	}
+	goals := []FinancialGoal{{
+		ID: 4, Title: "Emergency fund", TargetAmount: NewMoney(2000), CurrentAmount: NewMoney(300),
+		TargetDate: time.Date(2024, 7, 15, 0, 0, 0, 0, time.UTC),
+	}}
+
+	simulation := SimulateBudget(baseline, req, map[uint]string{9: "Gym"}, goals)
+
+	assert.Equal(t, DefaultSimulationMonths, simulation.Months)
+	assert.Equal(t, 3, simulation.MonthsAnalyzed)
+	assert.Equal(t, NewMoney(800), simulation.Baseline.MonthlySavings)
+	assert.Equal(t, 20.0, simulation.Baseline.SavingsRate)
+	assert.Equal(t, NewMoney(3150), simulation.Simulated.MonthlyExpenses)
+	assert.Equal(t, NewMoney(850), simulation.Simulated.MonthlySavings)
+	assert.Equal(t, 21.25, simulation.Simulated.SavingsRate)
+	assert.Equal(t, NewMoney(5100), simulation.Simulated.TotalSavings)
+
+	require.Len(t, simulation.Categories, 4)
+	assert.Equal(t, "Housing", simulation.Categories[0].CategoryName, "largest spending first")
+	assert.Equal(t, NewMoney(0), simulation.Categories[0].Difference)
+	assert.Equal(t, NewMoney(-300), simulation.Categories[1].Difference)
+	assert.Equal(t, CategoryProjection{CategoryID: 9, CategoryName: "Gym", Simulated: gym, Difference: gym}, simulation.Categories[3])
+
+	require.Len(t, simulation.CashFlow, 6)
+	assert.Equal(t, "2024-05", simulation.CashFlow[0].Month)
+	assert.Equal(t, "2024-10", simulation.CashFlow[5].Month)
+	assert.Equal(t, NewMoney(4800), simulation.CashFlow[5].BaselineBalance)
+	assert.Equal(t, NewMoney(5100), simulation.CashFlow[5].SimulatedBalance)
+
+	require.Len(t, simulation.Goals, 1)
+	goal := simulation.Goals[0]
+	assert.Equal(t, NewMoney(1700), goal.Remaining)
+	require.NotNil(t, goal.BaselineCompletion)
+	require.NotNil(t, goal.SimulatedCompletion)
+	assert.Equal(t, time.Date(2024, 7, 31, 0, 0, 0, 0, time.UTC), *goal.BaselineCompletion)
+	assert.Equal(t, time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC), *goal.SimulatedCompletion)
+	assert.True(t, goal.OnTrack)
+
+	t.Run("should not complete goals without savings", func(t *testing.T) {
+		income := NewMoney(-1000)
+		simulation := SimulateBudget(baseline, BudgetSimulationRequest{IncomeChange: income, Months: 2}, nil, goals)
+		assert.Len(t, simulation.CashFlow, 2)
+		assert.Nil(t, simulation.Goals[0].SimulatedCompletion)
+		assert.False(t, simulation.Goals[0].OnTrack)
+	})
+}
+
+func TestAnchoredPeriod(t *testing.T) {
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name      string
+		date      time.Time
+		period    string
+		day       int
+		wantStart time.Time
+		wantNext  time.Time
+	}{
+		{"calendar month", day(2024, 2, 14), PeriodMonthly, 1, day(2024, 2, 1), day(2024, 3, 1)},
+		{"payday on or after the day", day(2024, 1, 25), PeriodMonthly, 25, day(2024, 1, 25), day(2024, 2, 25)},
+		{"payday before the day", day(2024, 1, 10), PeriodMonthly, 25, day(2023, 12, 25), day(2024, 1, 25)},
+		{"payday in a shorter month", day(2024, 2, 29), PeriodMonthly, 31, day(2024, 2, 29), day(2024, 3, 31)},
+		{"calendar quarter", day(2024, 5, 20), PeriodQuarterly, 1, day(2024, 4, 1), day(2024, 7, 1)},
+		{"payday quarter", day(2024, 1, 3), PeriodQuarterly, 15, day(2023, 10, 15), day(2024, 1, 15)},
+		{"calendar year", day(2024, 12, 31), PeriodYearly, 1, day(2024, 1, 1), day(2025, 1, 1)},
+		{"ISO week", day(2024, 1, 7), PeriodWeekly, 1, day(2024, 1, 1), day(2024, 1, 8)},
+		{"week from Friday", day(2024, 1, 3), PeriodWeekly, 5, day(2023, 12, 29), day(2024, 1, 5)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := AnchoredPeriod(tt.date.Add(15*time.Hour), tt.period, tt.day)
+			assert.Equal(t, tt.wantStart, start)
+			assert.Equal(t, tt.wantNext.Add(-time.Nanosecond), end)
+		})
+	}
+}
+
+func TestBudget_AlignPeriod(t *testing.T) {
+	start := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	budget := Budget{Period: PeriodMonthly, Anchor: BudgetAnchorStartDate, StartDate: start, EndDate: start.AddDate(0, 1, 0)}
+	budget.AlignPeriod()
+	assert.Equal(t, start, budget.StartDate, "start date anchors are left alone")
+
+	budget.Anchor, budget.AnchorDay = BudgetAnchorPayday, 25
+	budget.AlignPeriod()
+	assert.Equal(t, time.Date(2024, 2, 25, 0, 0, 0, 0, time.UTC), budget.StartDate)
+	assert.Equal(t, time.Date(2024, 3, 25, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond), budget.EndDate)
+}
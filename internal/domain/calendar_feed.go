@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrInvalidCalendarFeedToken is returned for calendar feed links whose
+// token does not match, such as links from before the user rotated it
+var ErrInvalidCalendarFeedToken = errors.New("calendar feed link is invalid")
+
+// CalendarFeedHorizonDays is how far ahead the calendar feed reaches
+const CalendarFeedHorizonDays = 180
+
+// Calendar feed event kinds, besides the bill and recurring calendar entries
+const (
+	CalendarEventBudgetEnd = "budget_end"
+	CalendarEventGoal      = "goal"
+)
+
+// CalendarFeed is a user's subscribable calendar. Its link carries a token
+// signed over the user and Generation; rotating the link bumps Generation,
+// which invalidates every earlier link.
+type CalendarFeed struct {
+	UserID     uint      `gorm:"primaryKey;autoIncrement:false" json:"-"`
+	Generation int       `gorm:"not null;default:1" json:"-"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// CalendarFeedLink is the URL calendar apps subscribe to
+type CalendarFeedLink struct {
+	URL       string    `json:"url"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CalendarEvent is one all-day event of the calendar feed. UID stays the same
+// for the same occurrence, so calendar apps update events instead of adding
+// duplicates.
+type CalendarEvent struct {
+	UID         string
+	Date        time.Time
+	Kind        string
+	Summary     string
+	Description string
+}
@@ -2,19 +2,152 @@
 // for the Go Finance Advisor application.
 package domain
 
-import "time"
+import (
+	"errors"
+	"slices"
+	"time"
+)
 
-// Category represents a transaction category
+// ErrInvalidCategoryParent is returned when a category is nested under a
+// missing category, one of a different type or one that is itself nested
+var ErrInvalidCategoryParent = errors.New("parent must be an existing top-level category of the same type")
+
+// ErrCategoryInUse is returned when a category that records still refer to
+// is deleted without naming a replacement or confirming Uncategorized
+var ErrCategoryInUse = errors.New("category is still in use; name a replacement or confirm moving its records")
+
+// CategoryDeleteOptions says where the records of a deleted category go:
+// to ReplacementID, or with Confirm to Uncategorized
+type CategoryDeleteOptions struct {
+	ReplacementID *uint
+	Confirm       bool
+}
+
+// CategoryDeletion counts the records that refer to a category. Deleting
+// it moves them to Replacement, except for caps, which are removed. Before
+// the deletion it is what the user confirms, afterwards what was moved.
+type CategoryDeletion struct {
+	CategoryID   uint      `json:"category_id"`
+	Transactions int       `json:"transactions"` // Trashed ones included
+	Budgets      int       `json:"budgets"`
+	Bills        int       `json:"bills"`
+	SavingsRules int       `json:"savings_rules"`
+	Mappings     int       `json:"mappings"`
+	Caps         int       `json:"caps"`
+	Replacement  *Category `json:"replacement,omitempty"`
+	Deleted      bool      `json:"deleted"`
+}
+
+// InUse tells whether any record refers to the category
+func (d *CategoryDeletion) InUse() bool {
+	return d.Transactions+d.Budgets+d.Bills+d.SavingsRules+d.Mappings+d.Caps > 0
+}
+
+// UncategorizedCategoryName is the name of the system category transactions
+// are filed under when they have no other category
+const UncategorizedCategoryName = "Uncategorized"
+
+// Category represents a transaction category. Categories can be nested one
+// level deep, e.g. "Groceries" and "Restaurants" under "Food & Dining".
+// Custom categories belong to the user who created them; the defaults have
+// no owner and are shared by everyone.
 type Category struct {
-	ID          uint      `gorm:"primaryKey" json:"id"`
-	Name        string    `gorm:"type:varchar(50);uniqueIndex" json:"name"`
-	Type        string    `gorm:"type:varchar(10)" json:"type"` // "income" or "expense"
-	Description string    `json:"description"`
-	Icon        string    `json:"icon"`
-	Color       string    `json:"color"`
-	IsDefault   bool      `gorm:"default:false" json:"is_default"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	UserID      *uint      `gorm:"uniqueIndex:idx_categories_user_name,priority:1" json:"user_id,omitempty"`
+	Name        string     `gorm:"type:varchar(50);uniqueIndex:idx_categories_user_name,priority:2" json:"name"`
+	Type        string     `gorm:"type:varchar(10)" json:"type"` // "income" or "expense"
+	Description string     `json:"description"`
+	Icon        string     `json:"icon"`
+	Color       string     `json:"color"`
+	IsDefault   bool       `gorm:"default:false" json:"is_default"`
+	IsSystem    bool       `gorm:"default:false" json:"is_system"` // Created by the application, e.g. Uncategorized
+	ParentID    *uint      `gorm:"index" json:"parent_id,omitempty"`
+	Children    []Category `gorm:"-" json:"children,omitempty"` // Only filled by the category tree
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// CategoryParents maps nested category IDs to their parent's ID
+type CategoryParents map[uint]uint
+
+// NewCategoryParents collects the parent links of the given categories
+func NewCategoryParents(categories []Category) CategoryParents {
+	parents := make(CategoryParents)
+	for i := range categories {
+		if categories[i].ParentID != nil {
+			parents[categories[i].ID] = *categories[i].ParentID
+		}
+	}
+	return parents
+}
+
+// Ancestors returns the category ID followed by its parent's, grandparent's and so on
+func (p CategoryParents) Ancestors(id uint) []uint {
+	ids := []uint{id}
+	for parent, ok := p[id]; ok && !slices.Contains(ids, parent); parent, ok = p[parent] {
+		ids = append(ids, parent)
+	}
+	return ids
+}
+
+// Descendants returns the category ID followed by the IDs of every category below it
+func (p CategoryParents) Descendants(id uint) []uint {
+	ids := []uint{id}
+	for i := 0; i < len(ids); i++ {
+		for child, parent := range p {
+			if parent == ids[i] && !slices.Contains(ids, child) {
+				ids = append(ids, child)
+			}
+		}
+	}
+	slices.Sort(ids[1:])
+	return ids
+}
+
+// Root returns the top-level category the category belongs to
+func (p CategoryParents) Root(id uint) uint {
+	ancestors := p.Ancestors(id)
+	return ancestors[len(ancestors)-1]
+}
+
+// BuildCategoryTree nests categories under their parents and returns the
+// top-level ones, keeping the order of the input. Categories whose parent
+// is not in the list are returned at the top level.
+func BuildCategoryTree(categories []Category) []Category {
+	present := make(map[uint]bool, len(categories))
+	for i := range categories {
+		present[categories[i].ID] = true
+	}
+
+	children := make(map[uint][]Category)
+	var roots []Category
+	for i := range categories {
+		if parentID := categories[i].ParentID; parentID != nil && present[*parentID] {
+			children[*parentID] = append(children[*parentID], categories[i])
+		} else {
+			roots = append(roots, categories[i])
+		}
+	}
+
+	var attach func(nodes []Category)
+	attach = func(nodes []Category) {
+		for i := range nodes {
+			nodes[i].Children = children[nodes[i].ID]
+			delete(children, nodes[i].ID)
+			attach(nodes[i].Children)
+		}
+	}
+	attach(roots)
+	return roots
+}
+
+// UncategorizedCategory returns the protected system category of transactions
+// without another category. It has no type, so it takes income and expenses.
+func UncategorizedCategory() Category {
+	return Category{
+		Name: UncategorizedCategoryName, Description: "Transactions without a category yet",
+		Icon: "❔", Color: "#9E9E9E", IsDefault: true, IsSystem: true,
+	}
 }
 
 // GetDefaultCategories returns predefined categories
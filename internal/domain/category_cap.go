@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrCategoryCapExceeded is returned when a transaction would take the
+// month's spending in a category over a cap that blocks
+var ErrCategoryCapExceeded = errors.New("transaction would exceed the category's monthly spending cap")
+
+// CategoryCap is a self-imposed limit on what a user spends in an expense
+// category, including its subcategories, per calendar month. Unlike budgets,
+// caps are checked as transactions are recorded: going over one is flagged
+// on the new transaction, or refused when Block is set.
+type CategoryCap struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	UserID     uint      `gorm:"uniqueIndex:idx_category_caps_user_category,priority:1" json:"user_id"`
+	CategoryID uint      `gorm:"uniqueIndex:idx_category_caps_user_category,priority:2" json:"category_id"`
+	Amount     Money     `gorm:"not null" json:"amount"`
+	Block      bool      `gorm:"default:false" json:"block"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Validate checks that the cap names a category and a positive amount
+func (c *CategoryCap) Validate() error {
+	var v validator
+	v.check(c.CategoryID != 0, "category_id", "is required")
+	v.check(c.Amount > 0, "amount", "must be greater than zero")
+	return v.err()
+}
+
+// CategoryCapStatus is a month's spending against a cap. Remaining is
+// negative once the cap is exceeded.
+type CategoryCapStatus struct {
+	CategoryID   uint    `json:"category_id"`
+	CategoryName string  `json:"category_name"`
+	Cap          Money   `json:"cap"`
+	Spent        Money   `json:"spent"`
+	Remaining    Money   `json:"remaining"`
+	PercentUsed  float64 `json:"percent_used"`
+	Exceeded     bool    `json:"exceeded"`
+	Block        bool    `json:"block"`
+}
+
+// NewCategoryCapStatus compares the spending with the cap
+func NewCategoryCapStatus(limit CategoryCap, categoryName string, spent Money) CategoryCapStatus {
+	return CategoryCapStatus{
+		CategoryID:   limit.CategoryID,
+		CategoryName: categoryName,
+		Cap:          limit.Amount,
+		Spent:        spent,
+		Remaining:    limit.Amount - spent,
+		PercentUsed:  spent.PercentOf(limit.Amount),
+		Exceeded:     spent > limit.Amount,
+		Block:        limit.Block,
+	}
+}
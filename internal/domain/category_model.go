@@ -0,0 +1,189 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+)
+
+// ErrNotEnoughHistory is returned when a user has too few categorized
+// transactions to train a category model on
+var ErrNotEnoughHistory = errors.New("not enough categorized transactions to learn categories from")
+
+// Where a category suggestion came from
+const (
+	SuggestionSourceModel = "model" // The user's trained category model
+	SuggestionSourceRules = "rules" // The category the user files the merchant under
+)
+
+// CategorySuggestion is a category proposed for a transaction. Confidence is
+// the probability the source gives it, from 0 to 1.
+type CategorySuggestion struct {
+	CategoryID uint    `json:"category_id"`
+	Confidence float64 `json:"confidence"`
+	Source     string  `json:"source"`
+}
+
+// CategorySample is a categorized transaction a model learns from
+type CategorySample struct {
+	Description string
+	Type        string
+	CategoryID  uint
+}
+
+// CategoryClass is what a model knows about one category: how often it was
+// used and how often each description word appeared in it
+type CategoryClass struct {
+	Type      string         `json:"type"`
+	Samples   int            `json:"samples"`
+	Words     map[string]int `json:"words"`
+	WordCount int            `json:"word_count"`
+}
+
+// CategoryClassifier is a multinomial naive Bayes model over the words of
+// transaction descriptions, with add-one smoothing
+type CategoryClassifier struct {
+	Classes    map[uint]*CategoryClass `json:"classes"`
+	Vocabulary int                     `json:"vocabulary"`
+}
+
+// CategoryModel is a user's trained classifier with the evaluation it had
+// when it was trained
+type CategoryModel struct {
+	ID         uint                    `gorm:"primaryKey" json:"id"`
+	UserID     uint                    `gorm:"uniqueIndex;not null" json:"user_id"`
+	Classifier CategoryClassifier      `gorm:"serializer:json;type:text" json:"-"`
+	Samples    int                     `json:"samples"`
+	Categories int                     `json:"categories"`
+	Evaluation CategoryModelEvaluation `gorm:"serializer:json;type:text" json:"evaluation"`
+	TrainedAt  time.Time               `json:"trained_at"`
+	CreatedAt  time.Time               `json:"created_at"`
+	UpdatedAt  time.Time               `json:"updated_at"`
+}
+
+// CategoryModelEvaluation measures a model trained on the older part of a
+// history against its newest part. Coverage is the share of the held out
+// transactions the model was confident enough to suggest a category for,
+// and ConfidentAccuracy how many of those suggestions were right.
+type CategoryModelEvaluation struct {
+	TrainSamples      int     `json:"train_samples"`
+	TestSamples       int     `json:"test_samples"`
+	Accuracy          float64 `json:"accuracy"`
+	Coverage          float64 `json:"coverage"`
+	ConfidentAccuracy float64 `json:"confident_accuracy"`
+	MinConfidence     float64 `json:"min_confidence"`
+}
+
+// categoryTokens are the words of a description the model looks at
+func categoryTokens(description string) []string {
+	return strings.Fields(strings.ToLower(NormalizeDescription(description)))
+}
+
+// TrainCategoryClassifier learns the categories of the samples
+func TrainCategoryClassifier(samples []CategorySample) CategoryClassifier {
+	classifier := CategoryClassifier{Classes: make(map[uint]*CategoryClass)}
+	vocabulary := make(map[string]bool)
+	for _, sample := range samples {
+		class, ok := classifier.Classes[sample.CategoryID]
+		if !ok {
+			class = &CategoryClass{Type: sample.Type, Words: make(map[string]int)}
+			classifier.Classes[sample.CategoryID] = class
+		}
+		class.Samples++
+		for _, token := range categoryTokens(sample.Description) {
+			class.Words[token]++
+			class.WordCount++
+			vocabulary[token] = true
+		}
+	}
+	classifier.Vocabulary = len(vocabulary)
+	return classifier
+}
+
+// Predict returns the most likely category of the given type for the
+// description and its probability among the categories of that type. It
+// returns false when the model knows no category of the type or none of
+// the description's words.
+func (c *CategoryClassifier) Predict(description, transactionType string) (uint, float64, bool) {
+	tokens := categoryTokens(description)
+	known := false
+	total := 0
+	for _, class := range c.Classes {
+		if class.Type != transactionType {
+			continue
+		}
+		total += class.Samples
+		for _, token := range tokens {
+			known = known || class.Words[token] > 0
+		}
+	}
+	if !known {
+		return 0, 0, false
+	}
+
+	// Log probabilities, turned into probabilities relative to the best
+	scores := make(map[uint]float64)
+	var best uint
+	bestScore := math.Inf(-1)
+	for id, class := range c.Classes {
+		if class.Type != transactionType {
+			continue
+		}
+		score := math.Log(float64(class.Samples) / float64(total))
+		for _, token := range tokens {
+			score += math.Log(float64(class.Words[token]+1) / float64(class.WordCount+c.Vocabulary))
+		}
+		scores[id] = score
+		if score > bestScore || (score == bestScore && id < best) {
+			best, bestScore = id, score
+		}
+	}
+	var sum float64
+	for _, score := range scores {
+		sum += math.Exp(score - bestScore)
+	}
+	return best, 1 / sum, true
+}
+
+// EvaluateCategoryClassifier trains on all but the newest fifth of the
+// samples, which must be oldest first, and measures the predictions for
+// that fifth
+func EvaluateCategoryClassifier(samples []CategorySample, minConfidence float64) CategoryModelEvaluation {
+	split := len(samples) - len(samples)/5
+	train, test := samples[:split], samples[split:]
+	evaluation := CategoryModelEvaluation{
+		TrainSamples: len(train), TestSamples: len(test), MinConfidence: minConfidence,
+	}
+	if len(test) == 0 {
+		return evaluation
+	}
+
+	classifier := TrainCategoryClassifier(train)
+	var correct, confident, confidentCorrect int
+	for _, sample := range test {
+		categoryID, confidence, ok := classifier.Predict(sample.Description, sample.Type)
+		right := ok && categoryID == sample.CategoryID
+		if right {
+			correct++
+		}
+		if ok && confidence >= minConfidence {
+			confident++
+			if right {
+				confidentCorrect++
+			}
+		}
+	}
+	evaluation.Accuracy = ratio(correct, len(test))
+	evaluation.Coverage = ratio(confident, len(test))
+	evaluation.ConfidentAccuracy = ratio(confidentCorrect, confident)
+	return evaluation
+}
+
+// ratio is part over whole rounded to three decimals, zero without a whole
+func ratio(part, whole int) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return math.Round(float64(part)/float64(whole)*1000) / 1000
+}
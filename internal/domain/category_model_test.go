@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func categorySamples() []CategorySample {
+	var samples []CategorySample
+	for range 4 {
+		samples = append(samples,
+			CategorySample{Description: "SHELL OIL 5742", Type: TransactionTypeExpense, CategoryID: 2},
+			CategorySample{Description: "Corner Grocery #118", Type: TransactionTypeExpense, CategoryID: 1},
+			CategorySample{Description: "ACME CORP PAYROLL", Type: TransactionTypeIncome, CategoryID: 9},
+			CategorySample{Description: "Fresh Market grocery", Type: TransactionTypeExpense, CategoryID: 1},
+			CategorySample{Description: "Shell fuel station", Type: TransactionTypeExpense, CategoryID: 2},
+		)
+	}
+	return samples
+}
+
+func TestCategoryClassifier_Predict(t *testing.T) {
+	classifier := TrainCategoryClassifier(categorySamples())
+
+	categoryID, confidence, ok := classifier.Predict("SHELL 0091 SAN JOSE", TransactionTypeExpense)
+	assert.True(t, ok)
+	assert.Equal(t, uint(2), categoryID)
+	assert.Greater(t, confidence, 0.8)
+	assert.LessOrEqual(t, confidence, 1.0)
+
+	categoryID, _, ok = classifier.Predict("grocery outlet", TransactionTypeExpense)
+	assert.True(t, ok)
+	assert.Equal(t, uint(1), categoryID)
+
+	t.Run("only categories of the transaction's type are considered", func(t *testing.T) {
+		categoryID, confidence, ok := classifier.Predict("ACME CORP PAYROLL", TransactionTypeIncome)
+		assert.True(t, ok)
+		assert.Equal(t, uint(9), categoryID)
+		assert.Equal(t, 1.0, confidence, "the only income category")
+
+		_, _, ok = classifier.Predict("ACME CORP PAYROLL", TransactionTypeExpense)
+		assert.False(t, ok, "no expense ever mentioned these words")
+	})
+
+	t.Run("descriptions without known words are not predicted", func(t *testing.T) {
+		_, _, ok := classifier.Predict("Unknown Bistro", TransactionTypeExpense)
+		assert.False(t, ok)
+	})
+
+	t.Run("an untrained classifier predicts nothing", func(t *testing.T) {
+		empty := TrainCategoryClassifier(nil)
+		_, _, ok := empty.Predict("SHELL", TransactionTypeExpense)
+		assert.False(t, ok)
+	})
+}
+
+func TestEvaluateCategoryClassifier(t *testing.T) {
+	samples := categorySamples()
+	// The newest transactions switch to a category the older ones never used
+	samples[len(samples)-1].CategoryID = 3
+
+	evaluation := EvaluateCategoryClassifier(samples, 0.6)
+
+	assert.Equal(t, 16, evaluation.TrainSamples)
+	assert.Equal(t, 4, evaluation.TestSamples)
+	assert.Equal(t, 0.75, evaluation.Accuracy)
+	assert.Equal(t, 1.0, evaluation.Coverage)
+	assert.Equal(t, 0.75, evaluation.ConfidentAccuracy)
+	assert.Equal(t, 0.6, evaluation.MinConfidence)
+
+	t.Run("too few samples to hold any out", func(t *testing.T) {
+		evaluation := EvaluateCategoryClassifier(samples[:4], 0.6)
+
+		assert.Equal(t, 4, evaluation.TrainSamples)
+		assert.Zero(t, evaluation.TestSamples)
+		assert.Zero(t, evaluation.Accuracy)
+	})
+}
@@ -347,3 +347,81 @@ func TestCategory_UniqueConstraints(t *testing.T) {
 		assert.Equal(t, "Food & Dining", category2.Name)
 	})
 }
+
+func TestCategoryParents(t *testing.T) {
+	food, groceries, restaurants, travel := uint(1), uint(2), uint(3), uint(4)
+	parents := NewCategoryParents([]Category{
+		{ID: food},
+		{ID: groceries, ParentID: &food},
+		{ID: restaurants, ParentID: &food},
+		{ID: travel},
+	})
+
+	assert.Equal(t, []uint{groceries, food}, parents.Ancestors(groceries))
+	assert.Equal(t, []uint{food}, parents.Ancestors(food))
+	assert.Equal(t, []uint{food, groceries, restaurants}, parents.Descendants(food))
+	assert.Equal(t, []uint{travel}, parents.Descendants(travel))
+	assert.Equal(t, food, parents.Root(restaurants))
+	assert.Equal(t, travel, parents.Root(travel))
+}
+
+func TestBuildCategoryTree(t *testing.T) {
+	food, missing := uint(1), uint(99)
+	tree := BuildCategoryTree([]Category{
+		{ID: food, Name: "Food & Dining"},
+		{ID: 2, Name: "Groceries", ParentID: &food},
+		{ID: 3, Name: "Orphan", ParentID: &missing},
+		{ID: 4, Name: "Restaurants", ParentID: &food},
+	})
+
+	assert.Len(t, tree, 2)
+	assert.Equal(t, "Food & Dining", tree[0].Name)
+	assert.Len(t, tree[0].Children, 2)
+	assert.Equal(t, "Groceries", tree[0].Children[0].Name)
+	assert.Equal(t, "Restaurants", tree[0].Children[1].Name)
+	assert.Equal(t, "Orphan", tree[1].Name)
+}
+
+func TestRollUpCategoryMetrics(t *testing.T) {
+	food := uint(1)
+	categories := []Category{
+		{ID: food, Name: "Food & Dining"},
+		{ID: 2, Name: "Groceries", ParentID: &food},
+		{ID: 3, Name: "Restaurants", ParentID: &food},
+		{ID: 4, Name: "Travel"},
+	}
+	breakdown := []CategoryMetrics{
+		{CategoryID: 4, CategoryName: "Travel", TotalAmount: 250, TransactionCount: 1, PercentageOfTotal: 45.5},
+		{CategoryID: 2, CategoryName: "Groceries", TotalAmount: 200, TransactionCount: 2, PercentageOfTotal: 36.4},
+		{CategoryID: 3, CategoryName: "Restaurants", TotalAmount: 100, TransactionCount: 2, PercentageOfTotal: 18.1},
+	}
+
+	rolled := RollUpCategoryMetrics(breakdown, categories)
+
+	assert.Len(t, rolled, 2)
+	assert.Equal(t, "Food & Dining", rolled[0].CategoryName)
+	assert.Equal(t, 300.0, rolled[0].TotalAmount)
+	assert.Equal(t, 4, rolled[0].TransactionCount)
+	assert.Equal(t, 75.0, rolled[0].AverageAmount)
+	assert.InDelta(t, 54.5, rolled[0].PercentageOfTotal, 0.001)
+	assert.Len(t, rolled[0].Subcategories, 2)
+	assert.Equal(t, "Groceries", rolled[0].Subcategories[0].CategoryName)
+	assert.Equal(t, "Travel", rolled[1].CategoryName)
+	assert.Empty(t, rolled[1].Subcategories)
+
+	t.Run("breakdown without nested categories is unchanged", func(t *testing.T) {
+		assert.Equal(t, breakdown, RollUpCategoryMetrics(breakdown, categories[3:]))
+	})
+}
+
+func TestCategoryCap(t *testing.T) {
+	assert.ElementsMatch(t, []string{"category_id", "amount"}, fieldsOf(t, (&CategoryCap{Amount: -1}).Validate()))
+	assert.NoError(t, (&CategoryCap{CategoryID: 3, Amount: NewMoney(200)}).Validate())
+
+	status := NewCategoryCapStatus(CategoryCap{CategoryID: 3, Amount: NewMoney(200), Block: true}, "Shopping", NewMoney(250))
+	assert.Equal(t, NewMoney(-50), status.Remaining)
+	assert.Equal(t, 125.0, status.PercentUsed)
+	assert.True(t, status.Exceeded)
+	assert.True(t, status.Block)
+	assert.False(t, NewCategoryCapStatus(CategoryCap{Amount: NewMoney(200)}, "", NewMoney(200)).Exceeded, "reaching the cap is allowed")
+}
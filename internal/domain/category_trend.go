@@ -0,0 +1,106 @@
+package domain
+
+import "math"
+
+// Category trend directions, matching CategoryMetrics.Trend
+const (
+	TrendIncreasing = "increasing"
+	TrendDecreasing = "decreasing"
+	TrendStable     = "stable"
+)
+
+// Defaults and bounds of the category trend lookback, in months
+const (
+	DefaultCategoryTrendMonths = 6
+	MinCategoryTrendMonths     = 2
+	MaxCategoryTrendMonths     = 36
+)
+
+// CategoryMonth is what was spent or earned in a category in one month.
+// PercentChange compares it with the month before and is nil when that
+// month had nothing to compare with.
+type CategoryMonth struct {
+	Year             int      `json:"year"`
+	Month            int      `json:"month"`
+	Amount           Money    `json:"amount"`
+	TransactionCount int      `json:"transaction_count"`
+	PercentChange    *float64 `json:"percent_change"`
+}
+
+// CategoryTrend is a category's monthly amounts, oldest first, with the
+// straight line fitted through them. Slope is how much the amount changes
+// per month along that line and Projection where it leads in the month
+// after the last, never below zero.
+type CategoryTrend struct {
+	UserID          uint            `json:"user_id"`
+	CategoryID      uint            `json:"category_id"`
+	CategoryName    string          `json:"category_name"`
+	Months          []CategoryMonth `json:"months"`
+	Average         Money           `json:"average"`
+	Slope           Money           `json:"slope"`
+	Trend           string          `json:"trend"`
+	ProjectionYear  int             `json:"projection_year"`
+	ProjectionMonth int             `json:"projection_month"`
+	Projection      Money           `json:"projection"`
+}
+
+// NewCategoryTrend analyses a category's months, oldest first and without
+// gaps. The trend is stable unless the line moves by more than 5% of the
+// average a month.
+func NewCategoryTrend(userID, categoryID uint, name string, months []CategoryMonth) CategoryTrend {
+	trend := CategoryTrend{
+		UserID: userID, CategoryID: categoryID, CategoryName: name,
+		Months: months, Trend: TrendStable,
+	}
+	if len(months) == 0 {
+		return trend
+	}
+
+	amounts := make([]Money, len(months))
+	for i := range months {
+		amounts[i] = months[i].Amount
+		if i > 0 && months[i-1].Amount != 0 {
+			change := math.Round((months[i].Amount-months[i-1].Amount).PercentOf(months[i-1].Amount)*100) / 100
+			months[i].PercentChange = &change
+		}
+	}
+	trend.Average = averageMoney(amounts)
+
+	slope, intercept := linearFit(amounts)
+	trend.Slope = Money(math.Round(slope))
+	trend.Projection = max(Money(math.Round(intercept+slope*float64(len(amounts)))), 0)
+	last := months[len(months)-1]
+	trend.ProjectionYear, trend.ProjectionMonth = last.Year, last.Month+1
+	if trend.ProjectionMonth > 12 {
+		trend.ProjectionYear, trend.ProjectionMonth = last.Year+1, 1
+	}
+
+	threshold := math.Abs(float64(trend.Average)) * 0.05
+	switch {
+	case slope > threshold && len(months) > 1:
+		trend.Trend = TrendIncreasing
+	case slope < -threshold && len(months) > 1:
+		trend.Trend = TrendDecreasing
+	}
+	return trend
+}
+
+// linearFit fits a least squares line through the amounts, taking their
+// positions as x, and returns its slope and intercept in cents
+func linearFit(amounts []Money) (slope, intercept float64) {
+	n := float64(len(amounts))
+	var sumX, sumY, sumXY, sumXX float64
+	for i, amount := range amounts {
+		x, y := float64(i), float64(amount)
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0, sumY / n
+	}
+	slope = (n*sumXY - sumX*sumY) / denominator
+	return slope, (sumY - slope*sumX) / n
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func categoryMonths(amounts ...float64) []CategoryMonth {
+	months := make([]CategoryMonth, len(amounts))
+	for i, amount := range amounts {
+		months[i] = CategoryMonth{Year: 2024, Month: 7 + i, Amount: NewMoney(amount)}
+	}
+	return months
+}
+
+func TestNewCategoryTrend(t *testing.T) {
+	t.Run("rising spending is projected along its line", func(t *testing.T) {
+		trend := NewCategoryTrend(1, 4, "Dining", categoryMonths(100, 0, 150, 200, 250, 300))
+
+		require.Len(t, trend.Months, 6)
+		assert.Nil(t, trend.Months[0].PercentChange)
+		assert.Nil(t, trend.Months[2].PercentChange, "no change from a month without spending")
+		require.NotNil(t, trend.Months[3].PercentChange)
+		assert.Equal(t, 33.33, *trend.Months[3].PercentChange)
+		require.NotNil(t, trend.Months[1].PercentChange)
+		assert.Equal(t, -100.0, *trend.Months[1].PercentChange)
+
+		assert.Equal(t, NewMoney(166.66), trend.Average)
+		assert.Equal(t, NewMoney(51.43), trend.Slope)
+		assert.Equal(t, NewMoney(346.67), trend.Projection)
+		assert.Equal(t, TrendIncreasing, trend.Trend)
+		assert.Equal(t, 2025, trend.ProjectionYear, "projected into the next year after December")
+		assert.Equal(t, 1, trend.ProjectionMonth)
+	})
+
+	t.Run("falling spending never projects below zero", func(t *testing.T) {
+		trend := NewCategoryTrend(1, 4, "Dining", categoryMonths(300, 150, 20))
+
+		assert.Equal(t, TrendDecreasing, trend.Trend)
+		assert.Equal(t, Money(0), trend.Projection)
+	})
+
+	t.Run("flat spending is stable", func(t *testing.T) {
+		trend := NewCategoryTrend(1, 4, "Dining", categoryMonths(200, 205, 198, 202))
+
+		assert.Equal(t, TrendStable, trend.Trend)
+		assert.InDelta(t, float64(NewMoney(201)), float64(trend.Projection), 200)
+	})
+
+	t.Run("a single month has no slope", func(t *testing.T) {
+		trend := NewCategoryTrend(1, 4, "Dining", categoryMonths(80))
+
+		assert.Equal(t, TrendStable, trend.Trend)
+		assert.Equal(t, Money(0), trend.Slope)
+		assert.Equal(t, NewMoney(80), trend.Projection)
+	})
+}
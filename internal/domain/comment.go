@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// What comments can be left on
+const (
+	CommentTargetTransaction = "transaction"
+	CommentTargetBudget      = "budget"
+	CommentTargetReport      = "report"
+)
+
+// CommentTargets lists the kinds of records comments can be left on
+var CommentTargets = []string{CommentTargetTransaction, CommentTargetBudget, CommentTargetReport}
+
+const maxCommentLength = 2000
+
+// ErrInvalidCommentTarget is returned for comments on anything but
+// transactions, budgets and reports
+var ErrInvalidCommentTarget = errors.New("comments can only be left on a transaction, budget or report")
+
+// ErrCommentForbidden is returned when a user who can read a record's
+// comments tries to comment on it, or to change someone else's comment
+var ErrCommentForbidden = errors.New("you cannot comment here")
+
+// mentionPattern matches mentions of users by email, e.g. @sam@example.com
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w.@])@([\w.%+-]+@[\w-]+(?:\.[\w-]+)+)`)
+
+// Comment is a note a user left on a transaction, budget or report. Whoever
+// can see the record can read its comments: its owner, the members of the
+// household it is shared with and the owner's advisors. Mentioning a user's
+// email with @ notifies them.
+type Comment struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	TargetType string    `gorm:"type:varchar(20);index:idx_comments_target,priority:1;not null" json:"target_type"`
+	TargetID   uint      `gorm:"index:idx_comments_target,priority:2;not null" json:"target_id"`
+	AuthorID   uint      `gorm:"index;not null" json:"author_id"`
+	Body       string    `gorm:"type:text;not null" json:"body"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	// Author is the author's name, filled for lists
+	Author string `gorm:"-" json:"author,omitempty"`
+}
+
+// IsValidCommentTarget reports whether comments can be left on the target type
+func IsValidCommentTarget(targetType string) bool {
+	return slices.Contains(CommentTargets, targetType)
+}
+
+// Validate checks the comment's body
+func (c *Comment) Validate() error {
+	var v validator
+	v.check(strings.TrimSpace(c.Body) != "", "body", "is required")
+	v.check(len([]rune(c.Body)) <= maxCommentLength, "body", "must be at most 2000 characters")
+	return v.err()
+}
+
+// Mentions returns the lower-cased emails the comment mentions, each once
+func (c *Comment) Mentions() []string {
+	var emails []string
+	for _, match := range mentionPattern.FindAllStringSubmatch(c.Body, -1) {
+		email := strings.ToLower(strings.TrimRight(match[1], "."))
+		if !slices.Contains(emails, email) {
+			emails = append(emails, email)
+		}
+	}
+	return emails
+}
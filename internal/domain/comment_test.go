@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComment_Mentions(t *testing.T) {
+	comment := Comment{Body: "@Sam@Example.com can you check this? cc @lee@example.co.uk. Mailed sam@example.com, " +
+		"@sam@example.com again."}
+	assert.Equal(t, []string{"sam@example.com", "lee@example.co.uk"}, comment.Mentions())
+
+	assert.Empty(t, (&Comment{Body: "No mentions, just dinner@home"}).Mentions())
+}
+
+func TestComment_Validate(t *testing.T) {
+	assert.NoError(t, (&Comment{Body: "Looks like a duplicate"}).Validate())
+	assert.ErrorIs(t, (&Comment{Body: "  "}).Validate(), ErrValidation)
+	assert.ErrorIs(t, (&Comment{Body: strings.Repeat("a", 2001)}).Validate(), ErrValidation)
+}
@@ -2,15 +2,17 @@ package domain
 
 // Period constants
 const (
-	PeriodMonthly = "monthly"
-	PeriodWeekly  = "weekly"
-	PeriodYearly  = "yearly"
+	PeriodMonthly   = "monthly"
+	PeriodWeekly    = "weekly"
+	PeriodQuarterly = "quarterly"
+	PeriodYearly    = "yearly"
 )
 
 // Transaction type constants
 const (
-	TransactionTypeIncome  = "income"
-	TransactionTypeExpense = "expense"
+	TransactionTypeIncome   = "income"
+	TransactionTypeExpense  = "expense"
+	TransactionTypeTransfer = "transfer"
 )
 
 // Risk tolerance constants
@@ -18,4 +20,21 @@ const (
 	RiskToleranceConservative = "conservative"
 	RiskToleranceModerate     = "moderate"
 	RiskToleranceAggressive   = "aggressive"
-)
\ No newline at end of file
+)
+
+// Employment status constants
+const (
+	EmploymentEmployed     = "employed"
+	EmploymentSelfEmployed = "self_employed"
+	EmploymentUnemployed   = "unemployed"
+	EmploymentStudent      = "student"
+	EmploymentRetired      = "retired"
+)
+
+// Locale constants
+const (
+	LocaleEnglish = "en"
+	LocaleTurkish = "tr"
+	LocaleGerman  = "de"
+	DefaultLocale = LocaleEnglish
+)
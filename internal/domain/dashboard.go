@@ -14,10 +14,15 @@ type DashboardSummary struct {
 	MonthlySavings       float64           `json:"monthly_savings"`
 	SavingsRate          float64           `json:"savings_rate"`
 	TopExpenseCategories []CategoryMetrics `json:"top_expense_categories"`
+	TopMerchants         []MerchantMetrics `json:"top_merchants"`
 	RecentTransactions   []Transaction     `json:"recent_transactions"`
 	BudgetAlerts         []BudgetAlert     `json:"budget_alerts"`
 	FinancialGoals       []FinancialGoal   `json:"financial_goals"`
 	QuickStats           QuickStats        `json:"quick_stats"`
+	// Watchlist lists the symbols the user follows, to be priced first
+	Watchlist []WatchlistItem `json:"watchlist"`
+	// CategoryCaps is this month's spending against the user's category caps
+	CategoryCaps []CategoryCapStatus `json:"category_caps"`
 }
 
 // BudgetAlert represents budget overspending alerts
@@ -31,14 +36,21 @@ type BudgetAlert struct {
 	DaysRemaining  int     `json:"days_remaining"`
 }
 
+// Financial goal statuses
+const (
+	GoalStatusActive    = "active"
+	GoalStatusCompleted = "completed"
+	GoalStatusPaused    = "paused"
+)
+
 // FinancialGoal represents user's financial goals
 type FinancialGoal struct {
 	ID            uint      `json:"id" gorm:"primaryKey"`
 	UserID        uint      `json:"user_id" gorm:"not null;index"`
 	Title         string    `json:"title" gorm:"not null"`
 	Description   string    `json:"description"`
-	TargetAmount  float64   `json:"target_amount" gorm:"not null"`
-	CurrentAmount float64   `json:"current_amount" gorm:"default:0"`
+	TargetAmount  Money     `json:"target_amount" gorm:"not null"`
+	CurrentAmount Money     `json:"current_amount" gorm:"default:0"`
 	TargetDate    time.Time `json:"target_date"`
 	GoalType      string    `json:"goal_type" gorm:"not null"`    // "savings", "debt_payoff", "investment"
 	Status        string    `json:"status" gorm:"default:active"` // "active", "completed", "paused"
@@ -60,7 +72,7 @@ type QuickStats struct {
 // CalculateProgress calculates the progress percentage for a financial goal
 func (fg *FinancialGoal) CalculateProgress() {
 	if fg.TargetAmount > 0 {
-		fg.Progress = (fg.CurrentAmount / fg.TargetAmount) * 100
+		fg.Progress = fg.CurrentAmount.PercentOf(fg.TargetAmount)
 		if fg.Progress > 100 {
 			fg.Progress = 100
 		}
@@ -0,0 +1,330 @@
+package domain
+
+import (
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Data quality thresholds
+const (
+	// DefaultDuplicateWindowDays is how many days apart the same charge may
+	// be booked and still count as a duplicate
+	DefaultDuplicateWindowDays = 3
+	MaxDuplicateWindowDays     = 31
+	// OutlierFactor is how many times the median of its category an amount
+	// must reach to be flagged
+	OutlierFactor = 10
+	// MinOutlierSample is how many transactions a category needs before its
+	// median says what a normal amount is
+	MinOutlierSample = 5
+	// MinBenfordSample is how many amounts the first digit analysis needs
+	// before its verdict means anything
+	MinBenfordSample = 50
+)
+
+// Benford conformity levels, after Nigrini's first digit MAD thresholds
+const (
+	BenfordClose            = "close"
+	BenfordAcceptable       = "acceptable"
+	BenfordMarginal         = "marginal"
+	BenfordNonconforming    = "nonconforming"
+	BenfordInsufficientData = "insufficient_data"
+)
+
+// DuplicateGroup is a set of transactions that look like the same charge
+// booked more than once: same type and amount, the same merchant, or the
+// same description when there is no merchant, dated within the window of
+// each other. Transactions are ordered by date.
+type DuplicateGroup struct {
+	Amount       Money         `json:"amount"`
+	Type         string        `json:"type"`
+	Merchant     string        `json:"merchant"`
+	Transactions []Transaction `json:"transactions"`
+}
+
+// DuplicateDismissal records that the user looked at two transactions
+// flagged as duplicates and kept both. TransactionID is the lower of the two IDs.
+type DuplicateDismissal struct {
+	ID                 uint      `gorm:"primaryKey" json:"id"`
+	UserID             uint      `gorm:"index;not null" json:"user_id"`
+	TransactionID      uint      `gorm:"uniqueIndex:idx_duplicate_dismissals_pair,priority:1;not null" json:"transaction_id"`
+	OtherTransactionID uint      `gorm:"uniqueIndex:idx_duplicate_dismissals_pair,priority:2;not null" json:"other_transaction_id"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+// NewDuplicateDismissals dismisses every pair of the transactions for the user
+func NewDuplicateDismissals(userID uint, transactionIDs []uint) []DuplicateDismissal {
+	ids := uniqueIDs(transactionIDs)
+	var dismissals []DuplicateDismissal
+	for i := range ids {
+		for _, other := range ids[i+1:] {
+			dismissals = append(dismissals, DuplicateDismissal{UserID: userID, TransactionID: ids[i], OtherTransactionID: other})
+		}
+	}
+	return dismissals
+}
+
+// AmountOutlier is a transaction whose amount is out of all proportion to
+// the others of its category, usually a misplaced decimal point
+type AmountOutlier struct {
+	Transaction Transaction `json:"transaction"`
+	Median      Money       `json:"category_median"`
+	Ratio       float64     `json:"ratio"`
+}
+
+// BenfordDigit compares how often amounts start with the digit to how often
+// Benford's law expects them to
+type BenfordDigit struct {
+	Digit    int     `json:"digit"`
+	Count    int     `json:"count"`
+	Observed float64 `json:"observed"`
+	Expected float64 `json:"expected"`
+}
+
+// BenfordAnalysis is the first digit test of a user's amounts. A large mean
+// absolute deviation from Benford's law points at typed-in or made-up data.
+type BenfordAnalysis struct {
+	SampleSize int            `json:"sample_size"`
+	Digits     []BenfordDigit `json:"digits"`
+	MAD        float64        `json:"mean_absolute_deviation"`
+	Conformity string         `json:"conformity"`
+}
+
+// DataQualityReport lists what looks wrong with a user's transactions
+type DataQualityReport struct {
+	WindowDays int              `json:"window_days"`
+	Duplicates []DuplicateGroup `json:"duplicates"`
+	Outliers   []AmountOutlier  `json:"outliers"`
+	Benford    BenfordAnalysis  `json:"benford"`
+}
+
+// duplicateKey is what transactions must share to be duplicates
+type duplicateKey struct {
+	Type     string
+	Amount   Money
+	Merchant string
+}
+
+func duplicateKeyOf(transaction Transaction) duplicateKey {
+	merchant := NormalizeDescription(transaction.Description)
+	if transaction.MerchantID != nil {
+		merchant = "merchant:" + strconv.FormatUint(uint64(*transaction.MerchantID), 10)
+	}
+	return duplicateKey{Type: transaction.Type, Amount: transaction.Amount, Merchant: merchant}
+}
+
+// FindDuplicates groups the transactions that look like the same charge
+// booked several times within windowDays of each other. Pairs dismissed
+// reports as kept are not linked. Groups are ordered by their first date.
+func FindDuplicates(transactions []Transaction, windowDays int, dismissed func(a, b uint) bool) []DuplicateGroup {
+	buckets := make(map[duplicateKey][]Transaction)
+	for _, transaction := range transactions {
+		key := duplicateKeyOf(transaction)
+		buckets[key] = append(buckets[key], transaction)
+	}
+
+	window := time.Duration(windowDays) * 24 * time.Hour
+	groups := []DuplicateGroup{}
+	for _, bucket := range buckets {
+		if len(bucket) < 2 {
+			continue
+		}
+		sort.SliceStable(bucket, func(i, j int) bool { return bucket[i].Date.Before(bucket[j].Date) })
+
+		// Link every pair close enough in time, then group what is linked
+		parent := make([]int, len(bucket))
+		for i := range parent {
+			parent[i] = i
+		}
+		root := func(i int) int {
+			for parent[i] != i {
+				parent[i] = parent[parent[i]]
+				i = parent[i]
+			}
+			return i
+		}
+		for i := range bucket {
+			for j := i + 1; j < len(bucket) && bucket[j].Date.Sub(bucket[i].Date) <= window; j++ {
+				if dismissed != nil && dismissed(bucket[i].ID, bucket[j].ID) {
+					continue
+				}
+				parent[root(j)] = root(i)
+			}
+		}
+
+		linked := make(map[int][]Transaction)
+		for i, transaction := range bucket {
+			linked[root(i)] = append(linked[root(i)], transaction)
+		}
+		for _, members := range linked {
+			if len(members) < 2 {
+				continue
+			}
+			groups = append(groups, DuplicateGroup{
+				Amount:       members[0].Amount,
+				Type:         members[0].Type,
+				Merchant:     merchantName(members[0]),
+				Transactions: members,
+			})
+		}
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		a, b := groups[i].Transactions[0], groups[j].Transactions[0]
+		if !a.Date.Equal(b.Date) {
+			return a.Date.Before(b.Date)
+		}
+		return a.ID < b.ID
+	})
+	return groups
+}
+
+// merchantName names the merchant of a transaction for people
+func merchantName(transaction Transaction) string {
+	if transaction.Merchant != nil && transaction.Merchant.Name != "" {
+		return transaction.Merchant.Name
+	}
+	if name := CleanMerchantName(transaction.Description); name != "" {
+		return name
+	}
+	return transaction.Description
+}
+
+// FindAmountOutliers flags transactions worth at least factor times the
+// median of their category and type. Categories with fewer than
+// MinOutlierSample transactions are skipped. The largest ratios come first.
+func FindAmountOutliers(transactions []Transaction, factor float64) []AmountOutlier {
+	type categoryKey struct {
+		CategoryID uint
+		Type       string
+	}
+	categories := make(map[categoryKey][]Transaction)
+	for _, transaction := range transactions {
+		key := categoryKey{transaction.CategoryID, transaction.Type}
+		categories[key] = append(categories[key], transaction)
+	}
+
+	outliers := []AmountOutlier{}
+	for _, members := range categories {
+		if len(members) < MinOutlierSample {
+			continue
+		}
+		median := medianAmount(members)
+		if median <= 0 {
+			continue
+		}
+		for _, transaction := range members {
+			ratio := float64(transaction.Amount) / float64(median)
+			if ratio >= factor {
+				outliers = append(outliers, AmountOutlier{Transaction: transaction, Median: median, Ratio: math.Round(ratio*100) / 100})
+			}
+		}
+	}
+
+	sort.Slice(outliers, func(i, j int) bool {
+		if outliers[i].Ratio != outliers[j].Ratio {
+			return outliers[i].Ratio > outliers[j].Ratio
+		}
+		return outliers[i].Transaction.ID < outliers[j].Transaction.ID
+	})
+	return outliers
+}
+
+func medianAmount(transactions []Transaction) Money {
+	amounts := make([]Money, len(transactions))
+	for i, transaction := range transactions {
+		amounts[i] = transaction.Amount
+	}
+	slices.Sort(amounts)
+	middle := len(amounts) / 2
+	if len(amounts)%2 == 1 {
+		return amounts[middle]
+	}
+	return (amounts[middle-1] + amounts[middle]) / 2
+}
+
+// AnalyzeBenford compares the first digits of the amounts with Benford's
+// law. Amounts under one unit are left out, as their leading digit is not
+// a choice anyone made.
+func AnalyzeBenford(transactions []Transaction) BenfordAnalysis {
+	var counts [10]int
+	sample := 0
+	for _, transaction := range transactions {
+		units := int64(math.Abs(transaction.Amount.Float64()))
+		if units < 1 {
+			continue
+		}
+		for units >= 10 {
+			units /= 10
+		}
+		counts[units]++
+		sample++
+	}
+
+	analysis := BenfordAnalysis{SampleSize: sample, Digits: make([]BenfordDigit, 0, 9)}
+	deviation := 0.0
+	for digit := 1; digit <= 9; digit++ {
+		expected := math.Log10(1 + 1/float64(digit))
+		observed := 0.0
+		if sample > 0 {
+			observed = float64(counts[digit]) / float64(sample)
+		}
+		deviation += math.Abs(observed - expected)
+		analysis.Digits = append(analysis.Digits, BenfordDigit{
+			Digit:    digit,
+			Count:    counts[digit],
+			Observed: math.Round(observed*10000) / 10000,
+			Expected: math.Round(expected*10000) / 10000,
+		})
+	}
+	analysis.MAD = math.Round(deviation/9*10000) / 10000
+
+	switch {
+	case sample < MinBenfordSample:
+		analysis.Conformity = BenfordInsufficientData
+	case analysis.MAD < 0.006:
+		analysis.Conformity = BenfordClose
+	case analysis.MAD < 0.012:
+		analysis.Conformity = BenfordAcceptable
+	case analysis.MAD < 0.015:
+		analysis.Conformity = BenfordMarginal
+	default:
+		analysis.Conformity = BenfordNonconforming
+	}
+	return analysis
+}
+
+// ValidateDuplicateWindow checks the days duplicates may be apart
+func ValidateDuplicateWindow(days int) error {
+	var v validator
+	v.check(days >= 0 && days <= MaxDuplicateWindowDays, "window_days",
+		"must be between 0 and "+strconv.Itoa(MaxDuplicateWindowDays))
+	return v.err()
+}
+
+// ValidateDuplicateMerge checks which transaction a merge keeps and which it
+// moves to the trash
+func ValidateDuplicateMerge(keepID uint, duplicateIDs []uint) error {
+	var v validator
+	v.check(keepID != 0, "keep_id", "is required")
+	v.check(len(duplicateIDs) > 0, "duplicate_ids", "must list at least one transaction")
+	v.check(!slices.Contains(duplicateIDs, keepID), "duplicate_ids", "must not contain the kept transaction")
+	return v.err()
+}
+
+// ValidateDuplicateDismissal checks the transactions a dismissal keeps
+func ValidateDuplicateDismissal(transactionIDs []uint) error {
+	var v validator
+	v.check(len(uniqueIDs(transactionIDs)) >= 2, "transaction_ids", "must list at least two transactions")
+	return v.err()
+}
+
+// uniqueIDs returns the distinct IDs in ascending order
+func uniqueIDs(ids []uint) []uint {
+	unique := slices.Clone(ids)
+	slices.Sort(unique)
+	return slices.Compact(unique)
+}
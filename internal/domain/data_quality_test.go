@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindDuplicates(t *testing.T) {
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	netflix := uint(7)
+	transactions := []Transaction{
+		{ID: 1, Type: TransactionTypeExpense, Amount: NewMoney(12.99), Description: "NETFLIX.COM 1234", MerchantID: &netflix, Date: day},
+		{ID: 2, Type: TransactionTypeExpense, Amount: NewMoney(12.99), Description: "Netflix", MerchantID: &netflix, Date: day.AddDate(0, 0, 1)},
+		{ID: 3, Type: TransactionTypeExpense, Amount: NewMoney(12.99), Description: "NETFLIX.COM", MerchantID: &netflix, Date: day.AddDate(0, 1, 0)},
+		{ID: 4, Type: TransactionTypeExpense, Amount: NewMoney(4.50), Description: "Corner Cafe #12", Date: day.AddDate(0, 0, 2)},
+		{ID: 5, Type: TransactionTypeExpense, Amount: NewMoney(4.50), Description: "CORNER CAFE #98", Date: day.AddDate(0, 0, 2)},
+		{ID: 6, Type: TransactionTypeIncome, Amount: NewMoney(4.50), Description: "Corner Cafe", Date: day.AddDate(0, 0, 2)},
+		{ID: 7, Type: TransactionTypeExpense, Amount: NewMoney(4.75), Description: "Corner Cafe", Date: day.AddDate(0, 0, 2)},
+	}
+
+	groups := FindDuplicates(transactions, DefaultDuplicateWindowDays, nil)
+	require.Len(t, groups, 2)
+	assert.Equal(t, []uint{1, 2}, transactionIDs(groups[0].Transactions))
+	assert.Equal(t, NewMoney(12.99), groups[0].Amount)
+	assert.Equal(t, []uint{4, 5}, transactionIDs(groups[1].Transactions))
+	assert.Equal(t, "Corner Cafe", groups[1].Merchant)
+
+	t.Run("a zero window only matches the same day", func(t *testing.T) {
+		groups := FindDuplicates(transactions, 0, nil)
+		require.Len(t, groups, 1)
+		assert.Equal(t, []uint{4, 5}, transactionIDs(groups[0].Transactions))
+	})
+
+	t.Run("dismissed pairs are not linked", func(t *testing.T) {
+		dismissed := func(a, b uint) bool { return a == 4 && b == 5 }
+		groups := FindDuplicates(transactions, DefaultDuplicateWindowDays, dismissed)
+		require.Len(t, groups, 1)
+		assert.Equal(t, []uint{1, 2}, transactionIDs(groups[0].Transactions))
+	})
+}
+
+func TestFindAmountOutliers(t *testing.T) {
+	var transactions []Transaction
+	for i, amount := range []float64{42, 38, 51, 45, 4500, 40} {
+		transactions = append(transactions, Transaction{
+			ID: uint(i + 1), CategoryID: 1, Type: TransactionTypeExpense, Amount: NewMoney(amount),
+		})
+	}
+	// Too few transactions to know what is normal for the category
+	transactions = append(transactions,
+		Transaction{ID: 10, CategoryID: 2, Type: TransactionTypeExpense, Amount: NewMoney(10)},
+		Transaction{ID: 11, CategoryID: 2, Type: TransactionTypeExpense, Amount: NewMoney(9000)},
+	)
+
+	outliers := FindAmountOutliers(transactions, OutlierFactor)
+	require.Len(t, outliers, 1)
+	assert.Equal(t, uint(5), outliers[0].Transaction.ID)
+	assert.Equal(t, NewMoney(43.5), outliers[0].Median)
+	assert.Equal(t, 103.45, outliers[0].Ratio)
+}
+
+func TestAnalyzeBenford(t *testing.T) {
+	t.Run("amounts following the law conform", func(t *testing.T) {
+		// 301 amounts starting with 1, 176 with 2 and so on
+		var transactions []Transaction
+		for digit, count := range []int{0, 301, 176, 125, 97, 79, 67, 58, 51, 46} {
+			for i := 0; i < count; i++ {
+				transactions = append(transactions, Transaction{Amount: NewMoney(float64(digit*100 + i%100))})
+			}
+		}
+		analysis := AnalyzeBenford(transactions)
+		assert.Equal(t, 1000, analysis.SampleSize)
+		require.Len(t, analysis.Digits, 9)
+		assert.Equal(t, 0.301, analysis.Digits[0].Expected)
+		assert.Equal(t, 0.301, analysis.Digits[0].Observed)
+		assert.Equal(t, BenfordClose, analysis.Conformity)
+	})
+
+	t.Run("amounts all starting with the same digit do not", func(t *testing.T) {
+		transactions := make([]Transaction, 60)
+		for i := range transactions {
+			transactions[i] = Transaction{Amount: NewMoney(float64(500 + i))}
+		}
+		analysis := AnalyzeBenford(transactions)
+		assert.Equal(t, 60, analysis.Digits[4].Count)
+		assert.Equal(t, BenfordNonconforming, analysis.Conformity)
+	})
+
+	t.Run("small samples have no verdict", func(t *testing.T) {
+		analysis := AnalyzeBenford([]Transaction{{Amount: NewMoney(12)}, {Amount: NewMoney(0.5)}})
+		assert.Equal(t, 1, analysis.SampleSize)
+		assert.Equal(t, BenfordInsufficientData, analysis.Conformity)
+	})
+}
+
+func TestNewDuplicateDismissals(t *testing.T) {
+	dismissals := NewDuplicateDismissals(1, []uint{9, 3, 5, 3})
+	require.Len(t, dismissals, 3)
+	assert.Equal(t, DuplicateDismissal{UserID: 1, TransactionID: 3, OtherTransactionID: 5}, dismissals[0])
+	assert.Equal(t, DuplicateDismissal{UserID: 1, TransactionID: 5, OtherTransactionID: 9}, dismissals[2])
+}
+
+func TestValidateDuplicateRequests(t *testing.T) {
+	assert.NoError(t, ValidateDuplicateWindow(0))
+	assert.Equal(t, []string{"window_days"}, fieldsOf(t, ValidateDuplicateWindow(MaxDuplicateWindowDays+1)))
+
+	assert.NoError(t, ValidateDuplicateMerge(1, []uint{2, 3}))
+	assert.Equal(t, []string{"keep_id", "duplicate_ids"}, fieldsOf(t, ValidateDuplicateMerge(0, nil)))
+	assert.Equal(t, []string{"duplicate_ids"}, fieldsOf(t, ValidateDuplicateMerge(2, []uint{2, 3})))
+
+	assert.NoError(t, ValidateDuplicateDismissal([]uint{1, 2}))
+	assert.Equal(t, []string{"transaction_ids"}, fieldsOf(t, ValidateDuplicateDismissal([]uint{4, 4})))
+}
+
+func transactionIDs(transactions []Transaction) []uint {
+	ids := make([]uint, len(transactions))
+	for i, transaction := range transactions {
+		ids[i] = transaction.ID
+	}
+	return ids
+}
@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// Digest frequencies
+const (
+	DigestWeekly  = "weekly"
+	DigestMonthly = "monthly"
+)
+
+// DigestFrequencies lists how often a digest can be sent
+var DigestFrequencies = []string{DigestWeekly, DigestMonthly}
+
+// Digest contents are capped so the email stays short
+const (
+	DigestTopCategories = 5
+	DigestTopInsights   = 3
+)
+
+// DigestSubscription is a user's opt-in to the spending digest email.
+// UnsubscribeToken identifies the subscription in the email's unsubscribe
+// link, which works without logging in. QueuedFor is the end of the last
+// period whose digest was queued, so the scheduler queues each one once.
+type DigestSubscription struct {
+	UserID           uint       `gorm:"primaryKey;autoIncrement:false" json:"user_id"`
+	Frequency        string     `gorm:"type:varchar(10);not null" json:"frequency"`
+	UnsubscribeToken string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	QueuedFor        *time.Time `json:"-"`
+	LastSentAt       *time.Time `json:"last_sent_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// Validate checks the frequency is supported
+func (s *DigestSubscription) Validate() error {
+	var v validator
+	v.check(slices.Contains(DigestFrequencies, s.Frequency), "frequency", "must be weekly or monthly")
+	return v.err()
+}
+
+// DigestPeriod returns the last full period before now a digest of the
+// frequency covers: the previous week, starting on firstDay, or the
+// previous calendar month. The end is the last second of the period.
+func DigestPeriod(frequency string, now time.Time, firstDay time.Weekday) (start, end time.Time) {
+	year, month, day := now.Date()
+	if frequency == DigestMonthly {
+		start = time.Date(year, month-1, 1, 0, 0, 0, 0, now.Location())
+		return start, start.AddDate(0, 1, 0).Add(-time.Second)
+	}
+	midnight := time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+	weekStart := midnight.AddDate(0, 0, -((int(now.Weekday()) - int(firstDay) + 7) % 7))
+	return weekStart.AddDate(0, 0, -7), weekStart.Add(-time.Second)
+}
+
+// Due reports whether the digest of the period ending at end still has to be
+// queued
+func (s *DigestSubscription) Due(end time.Time) bool {
+	return s.QueuedFor == nil || s.QueuedFor.Before(end)
+}
+
+// DigestBudget is how one of the user's active budgets is doing
+type DigestBudget struct {
+	Category       string  `json:"category"`
+	Amount         Money   `json:"amount"`
+	Spent          Money   `json:"spent"`
+	PercentageUsed float64 `json:"percentage_used"`
+	Status         string  `json:"status"` // "on_track", "warning", "over_budget"
+}
+
+// Digest is the spending summary of one period sent to a subscriber: the
+// period's totals and top expense categories, how the active budgets are
+// doing, the bills due in the coming period and the top insights
+type Digest struct {
+	UserID         uint              `json:"user_id"`
+	Email          string            `json:"email"`
+	Frequency      string            `json:"frequency"`
+	StartDate      time.Time         `json:"start_date"`
+	EndDate        time.Time         `json:"end_date"`
+	TotalIncome    float64           `json:"total_income"`
+	TotalExpenses  float64           `json:"total_expenses"`
+	NetIncome      float64           `json:"net_income"`
+	SavingsRate    float64           `json:"savings_rate"`
+	TopCategories  []CategoryMetrics `json:"top_categories"`
+	Budgets        []DigestBudget    `json:"budgets"`
+	UpcomingBills  []CalendarEntry   `json:"upcoming_bills"`
+	Insights       []Insight         `json:"insights"`
+	UnsubscribeURL string            `json:"unsubscribe_url,omitempty"`
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDigestPeriod(t *testing.T) {
+	// Wednesday
+	now := time.Date(2024, 5, 15, 9, 30, 0, 0, time.UTC)
+
+	start, end := DigestPeriod(DigestWeekly, now, time.Monday)
+	assert.Equal(t, time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2024, 5, 12, 23, 59, 59, 0, time.UTC), end)
+
+	start, end = DigestPeriod(DigestWeekly, now, time.Sunday)
+	assert.Equal(t, time.Date(2024, 5, 5, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2024, 5, 11, 23, 59, 59, 0, time.UTC), end)
+
+	start, end = DigestPeriod(DigestMonthly, time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC), time.Monday)
+	assert.Equal(t, time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2023, 12, 31, 23, 59, 59, 0, time.UTC), end)
+}
+
+func TestDigestSubscription_Due(t *testing.T) {
+	end := time.Date(2024, 5, 12, 23, 59, 59, 0, time.UTC)
+	subscription := DigestSubscription{UserID: 1, Frequency: DigestWeekly}
+	assert.NoError(t, subscription.Validate())
+	assert.True(t, subscription.Due(end))
+
+	subscription.QueuedFor = &end
+	assert.False(t, subscription.Due(end))
+	assert.True(t, subscription.Due(end.AddDate(0, 0, 7)))
+
+	subscription.Frequency = "daily"
+	assert.ErrorIs(t, subscription.Validate(), ErrValidation)
+}
@@ -0,0 +1,104 @@
+package domain
+
+// Domain event types, as named in webhook subscriptions and deliveries
+const (
+	EventTransactionCreated  = "transaction.created"
+	EventTransactionUpdated  = "transaction.updated"
+	EventTransactionDeleted  = "transaction.deleted"
+	EventTransactionRestored = "transaction.restored"
+	EventBudgetExceeded      = "budget.exceeded"
+	EventGoalReached         = "goal.reached"
+	EventBillDue             = "bill.due"
+)
+
+var EventTypes = []string{
+	EventTransactionCreated, EventTransactionUpdated, EventTransactionDeleted, EventTransactionRestored,
+	EventBudgetExceeded, EventGoalReached, EventBillDue,
+}
+
+// IsValidEventType checks if an event type is supported
+func IsValidEventType(eventType string) bool {
+	for _, t := range EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is something that happened to a user's data, published after the
+// change has been saved
+type Event interface {
+	EventType() string
+	// EventUserID is the user whose data changed
+	EventUserID() uint
+}
+
+// TransactionCreated is published when a transaction is recorded
+type TransactionCreated struct {
+	Transaction Transaction `json:"transaction"`
+}
+
+func (TransactionCreated) EventType() string   { return EventTransactionCreated }
+func (e TransactionCreated) EventUserID() uint { return e.Transaction.UserID }
+
+// TransactionUpdated is published when a transaction is edited. Before is
+// nil when the previous version could not be read.
+type TransactionUpdated struct {
+	Before *Transaction `json:"before,omitempty"`
+	After  Transaction  `json:"after"`
+}
+
+func (TransactionUpdated) EventType() string   { return EventTransactionUpdated }
+func (e TransactionUpdated) EventUserID() uint { return e.After.UserID }
+
+// TransactionDeleted is published when a transaction is moved to the trash
+type TransactionDeleted struct {
+	Transaction Transaction `json:"transaction"`
+}
+
+func (TransactionDeleted) EventType() string   { return EventTransactionDeleted }
+func (e TransactionDeleted) EventUserID() uint { return e.Transaction.UserID }
+
+// TransactionRestored is published when a transaction is taken out of the trash
+type TransactionRestored struct {
+	Transaction Transaction `json:"transaction"`
+}
+
+func (TransactionRestored) EventType() string   { return EventTransactionRestored }
+func (e TransactionRestored) EventUserID() uint { return e.Transaction.UserID }
+
+// BudgetExceeded is published when spending first goes over a budget's amount
+type BudgetExceeded struct {
+	Budget Budget `json:"budget"`
+}
+
+func (BudgetExceeded) EventType() string   { return EventBudgetExceeded }
+func (e BudgetExceeded) EventUserID() uint { return e.Budget.UserID }
+
+// GoalReached is published when a financial goal's target amount is reached
+type GoalReached struct {
+	Goal FinancialGoal `json:"goal"`
+}
+
+func (GoalReached) EventType() string   { return EventGoalReached }
+func (e GoalReached) EventUserID() uint { return e.Goal.UserID }
+
+// Transactions returns the transactions an event is about, both versions of
+// an edited transaction included, or nil for events about other data
+func Transactions(event Event) []Transaction {
+	switch e := event.(type) {
+	case TransactionCreated:
+		return []Transaction{e.Transaction}
+	case TransactionUpdated:
+		if e.Before != nil {
+			return []Transaction{*e.Before, e.After}
+		}
+		return []Transaction{e.After}
+	case TransactionDeleted:
+		return []Transaction{e.Transaction}
+	case TransactionRestored:
+		return []Transaction{e.Transaction}
+	}
+	return nil
+}
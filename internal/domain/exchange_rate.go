@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// BaseCurrency is the currency transaction amounts are kept and totalled
+// in. Transactions made in another currency keep their original amount and
+// the rate they were converted at.
+const BaseCurrency = "USD"
+
+// ErrExchangeRateUnavailable is returned when no rate is known for a
+// currency pair on a date and none can be fetched
+var ErrExchangeRateUnavailable = errors.New("exchange rate is unavailable")
+
+// ExchangeRate is what one unit of Base was worth in Quote on a day. Rates
+// are fetched on demand and kept, so past conversions never change.
+type ExchangeRate struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Base      string    `gorm:"type:varchar(3);uniqueIndex:idx_exchange_rates_pair_date,priority:1;not null" json:"base"`
+	Quote     string    `gorm:"type:varchar(3);uniqueIndex:idx_exchange_rates_pair_date,priority:2;not null" json:"quote"`
+	Date      time.Time `gorm:"uniqueIndex:idx_exchange_rates_pair_date,priority:3;not null" json:"date"`
+	Rate      float64   `gorm:"not null" json:"rate"`
+	Source    string    `gorm:"type:varchar(20)" json:"source"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RateDate is the UTC day rates for the given time are kept under
+func RateDate(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// IsCurrencyCode reports whether code has the form of an ISO 4217 code
+func IsCurrencyCode(code string) bool {
+	return len(code) == 3 && strings.IndexFunc(code, func(r rune) bool {
+		return r < 'A' || r > 'Z'
+	}) < 0
+}
+
+// InBaseCurrency reports whether the transaction was made in BaseCurrency
+func (t *Transaction) InBaseCurrency() bool {
+	return t.Currency == "" || t.Currency == BaseCurrency
+}
+
+// Original returns the currency the transaction was made in, its amount in
+// that currency and the rate it was converted at, which is 1 for
+// transactions in the base currency
+func (t *Transaction) Original() (currency string, amount Money, rate float64) {
+	if t.InBaseCurrency() || t.OriginalAmount == nil || t.ExchangeRate == nil {
+		return BaseCurrency, t.Amount, 1
+	}
+	return t.Currency, *t.OriginalAmount, *t.ExchangeRate
+}
+
+// ConvertAt sets the transaction's Amount to its original amount in its
+// Currency converted at rate. The first conversion takes Amount as the
+// original amount; later ones start again from it, so converting again at
+// another date's rate does not compound.
+func (t *Transaction) ConvertAt(rate float64) {
+	if t.OriginalAmount == nil {
+		original := t.Amount
+		t.OriginalAmount = &original
+	}
+	t.Amount = NewMoney(t.OriginalAmount.Float64() * rate)
+	t.ExchangeRate = &rate
+}
@@ -11,6 +11,7 @@ const (
 	ExportFormatCSV  ExportFormat = "csv"
 	ExportFormatJSON ExportFormat = "json"
 	ExportFormatPDF  ExportFormat = "pdf"
+	ExportFormatQIF  ExportFormat = "qif" // Quicken Interchange Format, transactions only
 )
 
 // ExportRequest represents a request to export data
@@ -28,8 +29,8 @@ type ExportFilter struct {
 	Categories   []string `json:"categories,omitempty"`
 	Transactions struct {
 		Types     []string  `json:"types,omitempty"` // "income", "expense"
-		MinAmount *float64  `json:"min_amount,omitempty"`
-		MaxAmount *float64  `json:"max_amount,omitempty"`
+		MinAmount *Money    `json:"min_amount,omitempty"`
+		MaxAmount *Money    `json:"max_amount,omitempty"`
 		DateRange DateRange `json:"date_range,omitempty"`
 	} `json:"transactions,omitempty"`
 	Budgets struct {
@@ -116,7 +117,7 @@ const (
 // IsValid checks if the export format is valid
 func (f ExportFormat) IsValid() bool {
 	switch f {
-	case ExportFormatCSV, ExportFormatJSON, ExportFormatPDF:
+	case ExportFormatCSV, ExportFormatJSON, ExportFormatPDF, ExportFormatQIF:
 		return true
 	default:
 		return false
@@ -132,6 +133,8 @@ func (f ExportFormat) GetContentType() string {
 		return "application/json"
 	case ExportFormatPDF:
 		return "application/pdf"
+	case ExportFormatQIF:
+		return "application/qif"
 	default:
 		return "application/octet-stream"
 	}
@@ -146,6 +149,8 @@ func (f ExportFormat) GetFileExtension() string {
 		return ".json"
 	case ExportFormatPDF:
 		return ".pdf"
+	case ExportFormatQIF:
+		return ".qif"
 	default:
 		return ".bin"
 	}
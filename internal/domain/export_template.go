@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Transaction export columns
+const (
+	ExportColumnID          = "id"
+	ExportColumnDate        = "date"
+	ExportColumnDescription = "description"
+	ExportColumnNotes       = "notes"
+	ExportColumnTags        = "tags"
+	ExportColumnItems       = "items"
+	ExportColumnAmount      = "amount"
+	ExportColumnType        = "type"
+	ExportColumnCategory    = "category"
+	ExportColumnCreatedAt   = "created_at"
+	// The currency the transaction was made in, the amount in it and the
+	// rate it was converted to the base currency at
+	ExportColumnCurrency       = "currency"
+	ExportColumnOriginalAmount = "original_amount"
+	ExportColumnExchangeRate   = "exchange_rate"
+)
+
+// ExportColumns lists the columns templates can choose from, in the order of
+// the default export
+var ExportColumns = []string{
+	ExportColumnID, ExportColumnDate, ExportColumnDescription, ExportColumnNotes, ExportColumnTags, ExportColumnItems,
+	ExportColumnAmount, ExportColumnType, ExportColumnCategory, ExportColumnCreatedAt,
+	ExportColumnCurrency, ExportColumnOriginalAmount, ExportColumnExchangeRate,
+}
+
+// exportDateFormats maps the date formats templates can use to Go layouts
+var exportDateFormats = map[string]string{
+	"YYYY-MM-DD": "2006-01-02",
+	"DD/MM/YYYY": "02/01/2006",
+	"MM/DD/YYYY": "01/02/2006",
+	"DD.MM.YYYY": "02.01.2006",
+	"YYYYMMDD":   "20060102",
+}
+
+// exportDelimiters are the CSV field separators templates can use
+var exportDelimiters = []string{",", ";", "\t", "|"}
+
+// Export filename placeholders, replaced when a template names a file
+const (
+	FilenamePlaceholderToday = "{date}"
+	FilenamePlaceholderStart = "{start_date}"
+	FilenamePlaceholderEnd   = "{end_date}"
+)
+
+// ExportTemplate is a user's saved layout for transaction exports: which
+// columns go in the file and in what order, how dates are written, the CSV
+// delimiter and the filename. Empty settings keep the default export's.
+type ExportTemplate struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	UserID          uint      `gorm:"not null;index" json:"user_id"`
+	Name            string    `gorm:"type:varchar(100);not null" json:"name"`
+	Columns         []string  `gorm:"serializer:json;type:text" json:"columns"`
+	DateFormat      string    `gorm:"type:varchar(20)" json:"date_format,omitempty"` // e.g. "DD/MM/YYYY"; the user's locale when empty
+	Delimiter       string    `gorm:"type:varchar(1)" json:"delimiter,omitempty"`    // "," when empty
+	FilenamePattern string    `gorm:"type:varchar(100)" json:"filename_pattern,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// Validate checks the name, that the columns are known and listed once, and
+// that the date format, delimiter and filename pattern are supported
+func (t *ExportTemplate) Validate() error {
+	var v validator
+	v.check(strings.TrimSpace(t.Name) != "", "name", "is required")
+	v.check(len(t.Name) <= 100, "name", "must be at most 100 characters")
+	v.check(len(t.Columns) > 0, "columns", "must list at least one column")
+	for i, column := range t.Columns {
+		field := fmt.Sprintf("columns[%d]", i)
+		v.check(slices.Contains(ExportColumns, column), field, "must be one of "+strings.Join(ExportColumns, ", "))
+		v.check(!slices.Contains(t.Columns[:i], column), field, "must not repeat a column")
+	}
+	_, knownFormat := exportDateFormats[t.DateFormat]
+	v.check(t.DateFormat == "" || knownFormat, "date_format", "must be YYYY-MM-DD, DD/MM/YYYY, MM/DD/YYYY, DD.MM.YYYY or YYYYMMDD")
+	v.check(t.Delimiter == "" || slices.Contains(exportDelimiters, t.Delimiter), "delimiter", "must be a comma, semicolon, tab or pipe")
+	v.check(len(t.FilenamePattern) <= 100, "filename_pattern", "must be at most 100 characters")
+	v.check(!strings.ContainsAny(t.FilenamePattern, `/\:*?"<>|`), "filename_pattern", "must not contain path or reserved characters")
+	return v.err()
+}
+
+// DateLayout returns the Go layout of the template's date format, or "" when
+// dates are written in the user's locale
+func (t *ExportTemplate) DateLayout() string {
+	return exportDateFormats[t.DateFormat]
+}
+
+// Comma returns the template's CSV delimiter
+func (t *ExportTemplate) Comma() rune {
+	if t.Delimiter == "" {
+		return ','
+	}
+	return rune(t.Delimiter[0])
+}
+
+// Filename names an export made now over the optional date range, with the
+// format's extension. Templates without a pattern fall back to fallback.
+func (t *ExportTemplate) Filename(fallback string, now time.Time, start, end *time.Time, format ExportFormat) string {
+	pattern := t.FilenamePattern
+	if pattern == "" {
+		pattern = fallback
+	}
+	dateOf := func(date *time.Time) string {
+		if date == nil {
+			return ""
+		}
+		return date.Format("2006-01-02")
+	}
+	name := strings.NewReplacer(
+		FilenamePlaceholderToday, now.Format("2006-01-02"),
+		FilenamePlaceholderStart, dateOf(start),
+		FilenamePlaceholderEnd, dateOf(end),
+	).Replace(pattern)
+	return name + format.GetFileExtension()
+}
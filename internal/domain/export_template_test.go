@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportTemplate_Validate(t *testing.T) {
+	valid := func() ExportTemplate {
+		return ExportTemplate{Name: "Ledger", Columns: []string{ExportColumnDate, ExportColumnAmount}}
+	}
+	template := valid()
+	assert.NoError(t, template.Validate())
+
+	tests := []struct {
+		name   string
+		mutate func(*ExportTemplate)
+		fields []string
+	}{
+		{"blank name", func(t *ExportTemplate) { t.Name = " " }, []string{"name"}},
+		{"no columns", func(t *ExportTemplate) { t.Columns = nil }, []string{"columns"}},
+		{"unknown column", func(t *ExportTemplate) { t.Columns = append(t.Columns, "iban") }, []string{"columns[2]"}},
+		{"repeated column", func(t *ExportTemplate) { t.Columns = append(t.Columns, ExportColumnDate) }, []string{"columns[2]"}},
+		{"unknown date format", func(t *ExportTemplate) { t.DateFormat = "2006-01-02" }, []string{"date_format"}},
+		{"unknown delimiter", func(t *ExportTemplate) { t.Delimiter = ":" }, []string{"delimiter"}},
+		{"path in filename", func(t *ExportTemplate) { t.FilenamePattern = "../ledger" }, []string{"filename_pattern"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := valid()
+			tt.mutate(&template)
+			assert.Equal(t, tt.fields, fieldsOf(t, template.Validate()))
+		})
+	}
+}
+
+func TestExportTemplate_Filename(t *testing.T) {
+	now := time.Date(2024, 3, 9, 12, 0, 0, 0, time.UTC)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	template := ExportTemplate{FilenamePattern: "ledger_{start_date}_{end_date}_{date}"}
+	assert.Equal(t, "ledger_2024-01-01__2024-03-09.qif", template.Filename("fallback", now, &start, nil, ExportFormatQIF))
+
+	template = ExportTemplate{}
+	assert.Equal(t, "transactions_2024-03-09.csv", template.Filename("transactions_{date}", now, nil, nil, ExportFormatCSV))
+	assert.Equal(t, ',', template.Comma())
+	assert.Empty(t, template.DateLayout())
+}
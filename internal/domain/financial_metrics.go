@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"sort"
 	"time"
 )
 
@@ -31,6 +32,67 @@ type CategoryMetrics struct {
 	PercentageOfTotal float64 `json:"percentage_of_total"`
 	AverageAmount     float64 `json:"average_amount"`
 	Trend             string  `json:"trend"` // "increasing", "decreasing", "stable"
+	// Uncategorized flags the transactions without a real category: those
+	// under the Uncategorized system category or a category that is gone
+	Uncategorized bool `json:"uncategorized,omitempty"`
+	// Subcategories holds the nested categories whose totals are included above
+	Subcategories []CategoryMetrics `json:"subcategories,omitempty"`
+}
+
+// RollUpCategoryMetrics folds the metrics of nested categories into their
+// top-level category, keeping them as its subcategories, and sorts both
+// levels by total amount. Percentages must already share the same total.
+func RollUpCategoryMetrics(breakdown []CategoryMetrics, categories []Category) []CategoryMetrics {
+	parents := NewCategoryParents(categories)
+	if len(parents) == 0 {
+		return breakdown
+	}
+	names := make(map[uint]string, len(categories))
+	for i := range categories {
+		names[categories[i].ID] = categories[i].Name
+	}
+
+	rolled := make(map[uint]*CategoryMetrics)
+	var order []uint
+	for i := range breakdown {
+		metrics := breakdown[i]
+		rootID := parents.Root(metrics.CategoryID)
+		root, ok := rolled[rootID]
+		if !ok {
+			root = &CategoryMetrics{CategoryID: rootID, CategoryName: names[rootID], Trend: metrics.Trend}
+			rolled[rootID] = root
+			order = append(order, rootID)
+		}
+		root.TotalAmount += metrics.TotalAmount
+		root.TransactionCount += metrics.TransactionCount
+		root.PercentageOfTotal += metrics.PercentageOfTotal
+		if rootID == metrics.CategoryID {
+			root.CategoryName = metrics.CategoryName
+			root.Trend = metrics.Trend
+			root.Uncategorized = metrics.Uncategorized
+		} else {
+			metrics.Subcategories = nil
+			root.Subcategories = append(root.Subcategories, metrics)
+		}
+	}
+
+	result := make([]CategoryMetrics, 0, len(order))
+	for _, id := range order {
+		root := rolled[id]
+		if root.TransactionCount > 0 {
+			root.AverageAmount = root.TotalAmount / float64(root.TransactionCount)
+		}
+		sortCategoryMetrics(root.Subcategories)
+		result = append(result, *root)
+	}
+	sortCategoryMetrics(result)
+	return result
+}
+
+func sortCategoryMetrics(metrics []CategoryMetrics) {
+	sort.SliceStable(metrics, func(i, j int) bool {
+		return metrics[i].TotalAmount > metrics[j].TotalAmount
+	})
 }
 
 // MonthlyTrend represents month-over-month financial trends
@@ -95,6 +157,36 @@ type WeeklyTrend struct {
 	TransactionCount int       `json:"transaction_count"`
 }
 
+// MerchantMetrics represents spending analysis by merchant
+type MerchantMetrics struct {
+	MerchantID        uint            `json:"merchant_id"`
+	MerchantName      string          `json:"merchant_name"`
+	TotalAmount       float64         `json:"total_amount"`
+	TransactionCount  int             `json:"transaction_count"`
+	PercentageOfTotal float64         `json:"percentage_of_total"`
+	AverageAmount     float64         `json:"average_amount"`
+	LastTransaction   time.Time       `json:"last_transaction"`
+	MonthlyAmounts    []MonthlyAmount `json:"monthly_amounts,omitempty"`
+}
+
+// MonthlyAmount is a total for one "YYYY-MM" month
+type MonthlyAmount struct {
+	Month  string  `json:"month"`
+	Amount float64 `json:"amount"`
+}
+
+// MerchantAnalysis represents where a user spent money over a period.
+// Merchants are ordered by total spend; expenses that could not be mapped
+// to a merchant are only counted in UnassignedAmount.
+type MerchantAnalysis struct {
+	UserID           uint              `json:"user_id"`
+	StartDate        time.Time         `json:"start_date"`
+	EndDate          time.Time         `json:"end_date"`
+	TotalExpenses    float64           `json:"total_expenses"`
+	UnassignedAmount float64           `json:"unassigned_amount"`
+	Merchants        []MerchantMetrics `json:"merchants"`
+}
+
 // CalculateFinancialHealth calculates overall financial health score
 func (fm *FinancialMetrics) CalculateFinancialHealth() {
 	savingsScore := fm.calculateSavingsScore()
@@ -1,15 +1,39 @@
 package domain
 
 import (
+	"errors"
 	"fmt"
 	"time"
 )
 
-// FinancialReport represents a comprehensive financial report
+// Report types
+const (
+	ReportTypeMonthly   = "monthly"
+	ReportTypeQuarterly = "quarterly"
+	ReportTypeYearly    = "yearly"
+	ReportTypeCustom    = "custom"
+)
+
+// ErrInvalidReportType is returned for a report type other than monthly,
+// quarterly, yearly or custom
+var ErrInvalidReportType = errors.New("invalid report type")
+
+// IsValidReportType reports whether t is one of the report types
+func IsValidReportType(t string) bool {
+	switch t {
+	case ReportTypeMonthly, ReportTypeQuarterly, ReportTypeYearly, ReportTypeCustom:
+		return true
+	}
+	return false
+}
+
+// FinancialReport represents a comprehensive financial report. Generated
+// reports are kept so users can look back at them.
 type FinancialReport struct {
 	ID                   uint                     `json:"id" gorm:"primaryKey"`
 	UserID               uint                     `json:"user_id" gorm:"not null;index"`
 	ReportType           string                   `json:"report_type" gorm:"not null"` // "monthly", "quarterly", "yearly", "custom"
+	Period               string                   `json:"period"`                      // e.g. "2024-01", "2024-Q1", "2024"
 	StartDate            time.Time                `json:"start_date" gorm:"not null"`
 	EndDate              time.Time                `json:"end_date" gorm:"not null"`
 	TotalIncome          float64                  `json:"total_income"`
@@ -17,13 +41,13 @@ type FinancialReport struct {
 	NetIncome            float64                  `json:"net_income"`
 	SavingsRate          float64                  `json:"savings_rate"`
 	TransactionCount     int                      `json:"transaction_count"`
-	CategoryBreakdown    []CategoryMetrics        `json:"category_breakdown" gorm:"-"`
-	MonthlyTrends        []MonthlyTrend           `json:"monthly_trends" gorm:"-"`
-	BudgetPerformance    BudgetPerformanceMetrics `json:"budget_performance" gorm:"-"`
-	TopIncomeCategories  []CategoryMetrics        `json:"top_income_categories" gorm:"-"`
-	TopExpenseCategories []CategoryMetrics        `json:"top_expense_categories" gorm:"-"`
-	Insights             []string                 `json:"insights" gorm:"type:text"`
-	Recommendations      []string                 `json:"recommendations" gorm:"type:text"`
+	CategoryBreakdown    []CategoryMetrics        `json:"category_breakdown" gorm:"serializer:json;type:text"`
+	MonthlyTrends        []MonthlyTrend           `json:"monthly_trends" gorm:"serializer:json;type:text"`
+	BudgetPerformance    BudgetPerformanceMetrics `json:"budget_performance" gorm:"serializer:json;type:text"`
+	TopIncomeCategories  []CategoryMetrics        `json:"top_income_categories" gorm:"serializer:json;type:text"`
+	TopExpenseCategories []CategoryMetrics        `json:"top_expense_categories" gorm:"serializer:json;type:text"`
+	Insights             []string                 `json:"insights" gorm:"serializer:json;type:text"`
+	Recommendations      []string                 `json:"recommendations" gorm:"serializer:json;type:text"`
 	GeneratedAt          time.Time                `json:"generated_at"`
 	CreatedAt            time.Time                `json:"created_at"`
 	UpdatedAt            time.Time                `json:"updated_at"`
@@ -33,6 +57,7 @@ type FinancialReport struct {
 type ReportSummary struct {
 	ID            uint      `json:"id"`
 	ReportType    string    `json:"report_type"`
+	Period        string    `json:"period"`
 	StartDate     time.Time `json:"start_date"`
 	EndDate       time.Time `json:"end_date"`
 	TotalIncome   float64   `json:"total_income"`
@@ -40,6 +65,7 @@ type ReportSummary struct {
 	NetIncome     float64   `json:"net_income"`
 	SavingsRate   float64   `json:"savings_rate"`
 	GeneratedAt   time.Time `json:"generated_at"`
+	CommentCount  int       `json:"comment_count"`
 }
 
 // ReportExportData represents data structure for report exports
@@ -69,6 +95,22 @@ func (r *FinancialReport) GetReportTitle() string {
 	}
 }
 
+// PeriodLabel names the period the report covers: "2024-01" for a month,
+// "2024-Q1" for a quarter, "2024" for a year and "2024-01-01..2024-03-15"
+// for a custom range
+func (r *FinancialReport) PeriodLabel() string {
+	switch r.ReportType {
+	case ReportTypeMonthly:
+		return r.StartDate.Format("2006-01")
+	case ReportTypeQuarterly:
+		return fmt.Sprintf("%04d-Q%d", r.StartDate.Year(), (r.StartDate.Month()-1)/3+1)
+	case ReportTypeYearly:
+		return r.StartDate.Format("2006")
+	default:
+		return r.StartDate.Format("2006-01-02") + ".." + r.EndDate.Format("2006-01-02")
+	}
+}
+
 // GetPeriodDays returns the number of days in the report period
 func (r *FinancialReport) GetPeriodDays() int {
 	return int(r.EndDate.Sub(r.StartDate).Hours() / 24)
@@ -118,6 +160,7 @@ func (r *FinancialReport) ToSummary() ReportSummary {
 	return ReportSummary{
 		ID:            r.ID,
 		ReportType:    r.ReportType,
+		Period:        r.Period,
 		StartDate:     r.StartDate,
 		EndDate:       r.EndDate,
 		TotalIncome:   r.TotalIncome,
@@ -0,0 +1,63 @@
+package domain
+
+import "time"
+
+// Intervals of the health score history
+const (
+	HealthIntervalDay  = "day"
+	HealthIntervalWeek = "week"
+)
+
+// HealthSnapshotWindow is the span of transactions each snapshot scores,
+// ending on its date
+const HealthSnapshotWindow = 30 * 24 * time.Hour
+
+// HealthSnapshot is a user's financial health score and savings rate as of a
+// day, scored over the HealthSnapshotWindow before it. There is at most one
+// per user and day.
+type HealthSnapshot struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	UserID        uint      `gorm:"not null;uniqueIndex:idx_health_snapshots_user_date,priority:1" json:"user_id"`
+	Date          time.Time `gorm:"not null;uniqueIndex:idx_health_snapshots_user_date,priority:2" json:"date"`
+	OverallScore  int       `gorm:"not null" json:"overall_score"`
+	SavingsScore  int       `gorm:"not null" json:"savings_score"`
+	SpendingScore int       `gorm:"not null" json:"spending_score"`
+	BudgetScore   int       `gorm:"not null" json:"budget_score"`
+	HealthStatus  string    `gorm:"type:varchar(20);not null" json:"health_status"`
+	SavingsRate   float64   `gorm:"not null" json:"savings_rate"`
+	TotalIncome   Money     `gorm:"not null;default:0" json:"total_income"`
+	TotalExpenses Money     `gorm:"not null;default:0" json:"total_expenses"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// NewHealthSnapshot records the health of metrics scored up to date
+func NewHealthSnapshot(userID uint, date time.Time, metrics *FinancialMetrics) HealthSnapshot {
+	return HealthSnapshot{
+		UserID:        userID,
+		Date:          date,
+		OverallScore:  metrics.FinancialHealth.OverallScore,
+		SavingsScore:  metrics.FinancialHealth.SavingsScore,
+		SpendingScore: metrics.FinancialHealth.SpendingScore,
+		BudgetScore:   metrics.FinancialHealth.BudgetScore,
+		HealthStatus:  metrics.FinancialHealth.HealthStatus,
+		SavingsRate:   metrics.SavingsRate,
+		TotalIncome:   NewMoney(metrics.TotalIncome),
+		TotalExpenses: NewMoney(metrics.TotalExpenses),
+	}
+}
+
+// HealthHistoryFilter narrows a user's health history. Interval week keeps
+// the latest snapshot of each ISO week.
+type HealthHistoryFilter struct {
+	From     *time.Time
+	To       *time.Time
+	Interval string
+}
+
+// HealthHistory is a user's health score over time, oldest first. Change is
+// how much the overall score moved from the first point to the last.
+type HealthHistory struct {
+	Interval string           `json:"interval"`
+	Points   []HealthSnapshot `json:"points"`
+	Change   int              `json:"change"`
+}
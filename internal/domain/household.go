@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Household member roles
+const (
+	HouseholdRoleOwner    = "owner"
+	HouseholdRoleMember   = "member"
+	HouseholdRoleReadOnly = "read_only"
+)
+
+// Household invitation statuses
+const (
+	InvitationStatusPending  = "pending"
+	InvitationStatusAccepted = "accepted"
+	InvitationStatusDeclined = "declined"
+)
+
+// ErrHouseholdForbidden is returned when a member's role does not allow an action
+var ErrHouseholdForbidden = errors.New("your household role does not allow this")
+
+// ErrAlreadyHouseholdMember is returned when inviting someone who is already a member
+var ErrAlreadyHouseholdMember = errors.New("user is already a member of the household")
+
+// ErrInvalidHouseholdRole is returned when members are invited or changed to a
+// role other than member or read-only
+var ErrInvalidHouseholdRole = errors.New("role must be member or read_only")
+
+// ErrInvalidInvitation is returned for invitations that are unknown, already
+// answered, expired or addressed to someone else
+var ErrInvalidInvitation = errors.New("invitation is invalid or has expired")
+
+// Household groups users who share transactions and budgets. Every member has
+// a role: the owner manages the members, members record shared transactions
+// and budgets, and read-only members can only look at them.
+type Household struct {
+	ID        uint              `gorm:"primaryKey" json:"id"`
+	Name      string            `gorm:"type:varchar(100);not null" json:"name"`
+	OwnerID   uint              `gorm:"index" json:"owner_id"`
+	Members   []HouseholdMember `gorm:"foreignKey:HouseholdID" json:"members,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// HouseholdMember is a user's membership of a household
+type HouseholdMember struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	HouseholdID uint      `gorm:"uniqueIndex:idx_household_members_household_user,priority:1" json:"household_id"`
+	UserID      uint      `gorm:"uniqueIndex:idx_household_members_household_user,priority:2;index" json:"user_id"`
+	Role        string    `gorm:"type:varchar(20);not null" json:"role"`
+	CreatedAt   time.Time `json:"joined_at"`
+}
+
+// CanWrite reports whether the member may add shared transactions and budgets
+func (m HouseholdMember) CanWrite() bool {
+	return m.Role == HouseholdRoleOwner || m.Role == HouseholdRoleMember
+}
+
+// CanManage reports whether the member may invite, change and remove members
+func (m HouseholdMember) CanManage() bool {
+	return m.Role == HouseholdRoleOwner
+}
+
+// HouseholdInvitation asks the user with Email to join a household. The
+// invitee answers it with Token, which only the inviter and the invitee see.
+type HouseholdInvitation struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	HouseholdID uint       `gorm:"index" json:"household_id"`
+	Household   *Household `gorm:"foreignKey:HouseholdID" json:"household,omitempty"`
+	Email       string     `gorm:"type:varchar(255);index" json:"email"`
+	Role        string     `gorm:"type:varchar(20);not null" json:"role"`
+	Token       string     `gorm:"type:varchar(64);uniqueIndex" json:"token,omitempty"`
+	InvitedByID uint       `json:"invited_by_id"`
+	Status      string     `gorm:"type:varchar(20);default:'pending'" json:"status"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// IsOpen reports whether the invitation can still be accepted or declined
+func (i HouseholdInvitation) IsOpen(now time.Time) bool {
+	return i.Status == InvitationStatusPending && now.Before(i.ExpiresAt)
+}
+
+// IsValidInvitationRole reports whether users can be invited with the role.
+// A household has exactly one owner, so nobody is invited as one.
+func IsValidInvitationRole(role string) bool {
+	return role == HouseholdRoleMember || role == HouseholdRoleReadOnly
+}
+
+// FinanceTotals sums income and expenses over a period
+type FinanceTotals struct {
+	Income           Money `json:"income"`
+	Expenses         Money `json:"expenses"`
+	NetIncome        Money `json:"net_income"`
+	TransactionCount int   `json:"transaction_count"`
+}
+
+// Add counts a transaction towards the totals
+func (t *FinanceTotals) Add(transaction Transaction) {
+	if transaction.Type == TransactionTypeIncome {
+		t.Income += transaction.Amount
+	} else {
+		t.Expenses += transaction.Amount
+	}
+	t.NetIncome = t.Income - t.Expenses
+	t.TransactionCount++
+}
+
+// MemberContribution is one member's share of the household's transactions
+type MemberContribution struct {
+	UserID uint   `json:"user_id"`
+	Role   string `json:"role"`
+	FinanceTotals
+}
+
+// HouseholdFinances combines a user's personal finances with those of a
+// household they belong to. Personal covers the user's own transactions that
+// are not shared; Combined is Personal plus everything shared with the
+// household, whoever recorded it.
+type HouseholdFinances struct {
+	HouseholdID uint                 `json:"household_id"`
+	UserID      uint                 `json:"user_id"`
+	StartDate   time.Time            `json:"start_date"`
+	EndDate     time.Time            `json:"end_date"`
+	Personal    FinanceTotals        `json:"personal"`
+	Household   FinanceTotals        `json:"household"`
+	Combined    FinanceTotals        `json:"combined"`
+	Members     []MemberContribution `json:"members"`
+	// Categories breaks down the household's expenses
+	Categories []CategoryMetrics `json:"categories"`
+}
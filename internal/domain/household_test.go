@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHouseholdMember_Permissions(t *testing.T) {
+	tests := []struct {
+		role      string
+		canWrite  bool
+		canManage bool
+	}{
+		{HouseholdRoleOwner, true, true},
+		{HouseholdRoleMember, true, false},
+		{HouseholdRoleReadOnly, false, false},
+	}
+	for _, tt := range tests {
+		member := HouseholdMember{Role: tt.role}
+		assert.Equal(t, tt.canWrite, member.CanWrite(), tt.role)
+		assert.Equal(t, tt.canManage, member.CanManage(), tt.role)
+	}
+	assert.False(t, IsValidInvitationRole(HouseholdRoleOwner))
+	assert.True(t, IsValidInvitationRole(HouseholdRoleReadOnly))
+}
+
+func TestHouseholdInvitation_IsOpen(t *testing.T) {
+	now := time.Now()
+	pending := HouseholdInvitation{Status: InvitationStatusPending, ExpiresAt: now.Add(time.Hour)}
+	assert.True(t, pending.IsOpen(now))
+	assert.False(t, pending.IsOpen(now.Add(2*time.Hour)))
+
+	accepted := HouseholdInvitation{Status: InvitationStatusAccepted, ExpiresAt: now.Add(time.Hour)}
+	assert.False(t, accepted.IsOpen(now))
+}
+
+func TestFinanceTotals_Add(t *testing.T) {
+	var totals FinanceTotals
+	totals.Add(Transaction{Type: TransactionTypeIncome, Amount: NewMoney(1000)})
+	totals.Add(Transaction{Type: TransactionTypeExpense, Amount: NewMoney(250)})
+
+	assert.Equal(t, NewMoney(1000.0), totals.Income)
+	assert.Equal(t, NewMoney(250.0), totals.Expenses)
+	assert.Equal(t, NewMoney(750.0), totals.NetIncome)
+	assert.Equal(t, 2, totals.TransactionCount)
+}
@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Finance apps whose export files can be imported
+const (
+	ImportSourceMint            = "mint"
+	ImportSourceYNAB            = "ynab"
+	ImportSourcePersonalCapital = "personal_capital"
+)
+
+// ImportSources lists the supported import sources
+var ImportSources = []string{ImportSourceMint, ImportSourceYNAB, ImportSourcePersonalCapital}
+
+// IsValidImportSource checks that transactions can be imported from the source
+func IsValidImportSource(source string) bool {
+	return slices.Contains(ImportSources, source)
+}
+
+var (
+	// ErrUnknownImportSource is returned for sources there is no importer for
+	ErrUnknownImportSource = errors.New("import source must be mint, ynab or personal_capital")
+	// ErrInvalidImportFile is returned for files that are not an export of the source
+	ErrInvalidImportFile = errors.New("file is not an export of the import source")
+)
+
+// ImportedTransaction is a transaction read from another app's export.
+// Category is the app's own category name, mapped to a local one on import.
+// Transfers between the user's accounts are read but never imported, as
+// they are neither income nor expenses.
+type ImportedTransaction struct {
+	Row         int // Line in CSV files, position in JSON ones
+	Date        time.Time
+	Description string
+	Notes       string
+	Category    string
+	Type        string
+	Amount      Money
+	Tags        []string
+	Transfer    bool
+}
+
+// IsValidCategoryMappingSource checks that categories of the source can be
+// mapped: those of import files and of bank providers
+func IsValidCategoryMappingSource(source string) bool {
+	return IsValidImportSource(source) || IsValidBankProvider(source)
+}
+
+// CategoryMapping maps a category of an import source or bank provider to
+// one of the user's categories. Mappings are kept, so later imports and bank
+// syncs from the same source reuse them.
+type CategoryMapping struct {
+	ID               uint      `gorm:"primaryKey" json:"id"`
+	UserID           uint      `gorm:"uniqueIndex:idx_category_mappings_user_source_external,priority:1" json:"user_id"`
+	Source           string    `gorm:"type:varchar(20);uniqueIndex:idx_category_mappings_user_source_external,priority:2" json:"source"`
+	ExternalCategory string    `gorm:"type:varchar(100);uniqueIndex:idx_category_mappings_user_source_external,priority:3" json:"external_category"`
+	CategoryID       uint      `gorm:"not null" json:"category_id"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// Validate checks the source, the external category and the local category
+func (m *CategoryMapping) Validate() error {
+	var v validator
+	v.check(IsValidCategoryMappingSource(m.Source), "source", "must be an import source or bank provider")
+	external := strings.TrimSpace(m.ExternalCategory)
+	v.check(external != "", "external_category", "is required")
+	v.check(len(external) <= 100, "external_category", "must be at most 100 characters")
+	v.check(m.CategoryID != 0, "category_id", "is required")
+	return v.err()
+}
+
+// ImportRowError is a row of an import file that could not be imported
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	Message string `json:"message"`
+}
+
+// ImportResult sums up an import. Rows already recorded, e.g. from an earlier
+// import of an overlapping file, count as duplicates and are left out.
+// Unmapped lists the app's categories that had no mapping or local match and
+// went to the catch-all "Other" categories, unless a category was suggested
+// for them; Suggested counts the imported rows filed under a suggestion.
+type ImportResult struct {
+	Source     string           `json:"source"`
+	Rows       int              `json:"rows"`
+	Imported   int              `json:"imported"`
+	Duplicates int              `json:"duplicates"`
+	Transfers  int              `json:"transfers"`
+	Suggested  int              `json:"suggested"`
+	Unmapped   []string         `json:"unmapped"`
+	Errors     []ImportRowError `json:"errors"`
+}
+
+// importCategoryGuesses maps category names common to finance apps to the
+// default categories, for categories the user has not mapped
+var importCategoryGuesses = map[string]string{
+	"groceries": "Food & Dining", "restaurants": "Food & Dining", "fast food": "Food & Dining",
+	"coffee shops": "Food & Dining", "alcohol & bars": "Food & Dining", "dining out": "Food & Dining",
+	"gas & fuel": "Transportation", "gasoline/fuel": "Transportation", "auto & transport": "Transportation",
+	"automotive": "Transportation", "public transportation": "Transportation", "parking": "Transportation",
+	"clothing": "Shopping", "electronics & software": "Shopping", "general merchandise": "Shopping",
+	"movies & dvds": "Entertainment", "music": "Entertainment", "hobbies": "Entertainment",
+	"utilities": "Bills & Utilities", "mobile phone": "Bills & Utilities", "internet": "Bills & Utilities",
+	"television": "Bills & Utilities", "cable/satellite": "Bills & Utilities",
+	"health & fitness": "Healthcare", "doctor": "Healthcare", "pharmacy": "Healthcare",
+	"healthcare/medical": "Healthcare", "dentist": "Healthcare",
+	"tuition": "Education", "books & supplies": "Education",
+	"air travel": "Travel", "hotel": "Travel", "vacation": "Travel",
+	"mortgage & rent": "Housing", "rent": "Housing", "mortgage": "Housing", "mortgages": "Housing",
+	"home improvement": "Housing",
+	"paycheck": "Salary", "paychecks/salary": "Salary",
+	"interest income": "Investment", "dividends & cap gains": "Investment", "investment income": "Investment",
+	"dividends received": "Investment", "interest": "Investment",
+	"income": "Other Income", "ready to assign": "Other Income", "to be budgeted": "Other Income",
+	"deposits": "Other Income",
+	// Plaid's personal finance categories
+	"food and drink": "Food & Dining", "rent and utilities": "Bills & Utilities", "medical": "Healthcare",
+}
+
+// ExternalCategoryName is the last part of a category of an import source:
+// apps such as YNAB prefix categories with their group, as in
+// "Everyday Expenses: Groceries"
+func ExternalCategoryName(category string) string {
+	if i := strings.LastIndex(category, ":"); i >= 0 {
+		category = category[i+1:]
+	}
+	return strings.TrimSpace(category)
+}
+
+// GuessImportCategory returns the default category that usually matches a
+// category of another app, or "" without a guess
+func GuessImportCategory(category string) string {
+	return importCategoryGuesses[strings.ToLower(ExternalCategoryName(category))]
+}
+
+// ImportFallbackCategory is the default category imported transactions of
+// the type get when their category cannot be matched
+func ImportFallbackCategory(transactionType string) string {
+	if transactionType == TransactionTypeIncome {
+		return "Other Income"
+	}
+	return "Other Expenses"
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCategoryMapping_Validate(t *testing.T) {
+	valid := CategoryMapping{Source: ImportSourceYNAB, ExternalCategory: "Dining Out", CategoryID: 1}
+	assert.NoError(t, valid.Validate())
+
+	invalid := CategoryMapping{Source: "quicken", ExternalCategory: "  "}
+	assert.ElementsMatch(t, []string{"source", "external_category", "category_id"}, fieldsOf(t, invalid.Validate()))
+
+	long := CategoryMapping{Source: ImportSourceMint, ExternalCategory: strings.Repeat("a", 101), CategoryID: 1}
+	assert.Equal(t, []string{"external_category"}, fieldsOf(t, long.Validate()))
+}
+
+func TestGuessImportCategory(t *testing.T) {
+	assert.Equal(t, "Dining Out", ExternalCategoryName("Everyday Expenses: Dining Out"))
+	assert.Equal(t, "Groceries", ExternalCategoryName(" Groceries "))
+
+	assert.Equal(t, "Food & Dining", GuessImportCategory("Everyday Expenses: Dining Out"))
+	assert.Equal(t, "Transportation", GuessImportCategory("GASOLINE/FUEL"))
+	assert.Equal(t, "", GuessImportCategory("Pet Supplies"))
+
+	assert.Equal(t, "Other Income", ImportFallbackCategory(TransactionTypeIncome))
+	assert.Equal(t, "Other Expenses", ImportFallbackCategory(TransactionTypeExpense))
+}
@@ -0,0 +1,220 @@
+package domain
+
+import (
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Pay frequencies of income sources
+const (
+	PayFrequencyWeekly      = "weekly"
+	PayFrequencyBiweekly    = "biweekly"
+	PayFrequencySemimonthly = "semimonthly"
+	PayFrequencyMonthly     = "monthly"
+)
+
+// payFrequencies maps the pay frequencies to their paychecks per year
+var payFrequencies = map[string]int{
+	PayFrequencyWeekly: 52, PayFrequencyBiweekly: 26, PayFrequencySemimonthly: 24, PayFrequencyMonthly: 12,
+}
+
+// Kinds of paycheck deductions. Only taxes count toward the effective tax
+// rate; the others are what else comes off the gross pay.
+const (
+	DeductionTax        = "tax"
+	DeductionRetirement = "retirement"
+	DeductionInsurance  = "insurance"
+	DeductionOther      = "other"
+)
+
+// DeductionKinds lists the kinds of paycheck deductions
+var DeductionKinds = []string{DeductionTax, DeductionRetirement, DeductionInsurance, DeductionOther}
+
+// Income source limits and the income breakdown lookback, in months
+const (
+	MaxPaycheckDeductions        = 20
+	DefaultIncomeBreakdownMonths = 12
+	MaxIncomeBreakdownMonths     = 36
+)
+
+// PaycheckDeduction is an amount withheld from every paycheck, such as
+// income tax or a pension contribution
+type PaycheckDeduction struct {
+	Name   string `json:"name"`
+	Kind   string `json:"kind"`
+	Amount Money  `json:"amount"`
+}
+
+// IncomeSource is where a user's pay comes from, such as an employer, with
+// the gross pay and deductions of one paycheck. Income transactions linked
+// to it are the net pay that reached the user's account.
+type IncomeSource struct {
+	ID         uint                `gorm:"primaryKey" json:"id"`
+	UserID     uint                `gorm:"not null;index" json:"user_id"`
+	Employer   string              `gorm:"type:varchar(100);not null" json:"employer"`
+	Gross      Money               `gorm:"type:integer;not null" json:"gross"`
+	Deductions []PaycheckDeduction `gorm:"serializer:json;type:text" json:"deductions"`
+	Frequency  string              `gorm:"type:varchar(20);not null" json:"frequency"`
+	Active     bool                `gorm:"not null;default:true" json:"active"`
+	CreatedAt  time.Time           `json:"created_at"`
+	UpdatedAt  time.Time           `json:"updated_at"`
+}
+
+// Validate checks the employer, pay, frequency and deductions, which must
+// leave some net pay
+func (s *IncomeSource) Validate() error {
+	var v validator
+	s.Employer = strings.TrimSpace(s.Employer)
+	v.check(s.Employer != "", "employer", "is required")
+	v.check(len(s.Employer) <= 100, "employer", "must be at most 100 characters")
+	v.check(s.Gross > 0, "gross", "must be positive")
+	_, known := payFrequencies[s.Frequency]
+	v.check(known, "frequency", "must be weekly, biweekly, semimonthly or monthly")
+	v.check(len(s.Deductions) <= MaxPaycheckDeductions, "deductions", "must list at most 20 deductions")
+	for i := range s.Deductions {
+		deduction := &s.Deductions[i]
+		field := "deductions[" + strconv.Itoa(i) + "]"
+		deduction.Name = strings.TrimSpace(deduction.Name)
+		v.check(deduction.Name != "" && len(deduction.Name) <= 100, field+".name", "must be 1 to 100 characters")
+		v.check(slices.Contains(DeductionKinds, deduction.Kind), field+".kind", "must be tax, retirement, insurance or other")
+		v.check(deduction.Amount >= 0, field+".amount", "must not be negative")
+	}
+	v.check(s.Net() > 0, "deductions", "must be less than the gross pay")
+	return v.err()
+}
+
+// Taxes is the tax withheld from a paycheck
+func (s *IncomeSource) Taxes() Money {
+	var taxes Money
+	for _, deduction := range s.Deductions {
+		if deduction.Kind == DeductionTax {
+			taxes += deduction.Amount
+		}
+	}
+	return taxes
+}
+
+// Net is the pay of a paycheck after its deductions
+func (s *IncomeSource) Net() Money {
+	net := s.Gross
+	for _, deduction := range s.Deductions {
+		net -= deduction.Amount
+	}
+	return net
+}
+
+// PaychecksPerYear is how many times a year the source pays
+func (s *IncomeSource) PaychecksPerYear() int {
+	return payFrequencies[s.Frequency]
+}
+
+// Paycheck estimates the gross pay and taxes behind net pay from the
+// source, scaling its paycheck, so bonuses and unpaid leave are taxed at the
+// same rate as a regular paycheck
+func (s *IncomeSource) Paycheck(net Money) (gross, taxes Money) {
+	regular := s.Net()
+	if regular <= 0 {
+		return net, 0
+	}
+	scale := float64(net) / float64(regular)
+	return s.Gross.Mul(scale), s.Taxes().Mul(scale)
+}
+
+// IncomeSourceMonth is the pay from a source in one month
+type IncomeSourceMonth struct {
+	Year      int   `json:"year"`
+	Month     int   `json:"month"`
+	Paychecks int   `json:"paychecks"`
+	Gross     Money `json:"gross"`
+	Net       Money `json:"net"`
+	Taxes     Money `json:"taxes"`
+}
+
+// IncomeSourceBreakdown is the pay from one source over the months of a
+// breakdown, oldest first. Trend and Slope follow the net pay like a
+// category trend; ExpectedMonthly is the gross pay of a regular month.
+type IncomeSourceBreakdown struct {
+	SourceID         uint                `json:"source_id"`
+	Employer         string              `json:"employer"`
+	Frequency        string              `json:"frequency"`
+	ExpectedMonthly  Money               `json:"expected_monthly"`
+	Paychecks        int                 `json:"paychecks"`
+	Gross            Money               `json:"gross"`
+	Net              Money               `json:"net"`
+	Taxes            Money               `json:"taxes"`
+	OtherDeductions  Money               `json:"other_deductions"`
+	EffectiveTaxRate float64             `json:"effective_tax_rate"`
+	Months           []IncomeSourceMonth `json:"months"`
+	Slope            Money               `json:"slope"`
+	Trend            string              `json:"trend"`
+}
+
+// NewIncomeSourceBreakdown adds up a source's months, in which only
+// Paychecks and Net are filled in, estimating the gross pay and taxes
+func NewIncomeSourceBreakdown(source *IncomeSource, months []IncomeSourceMonth) IncomeSourceBreakdown {
+	breakdown := IncomeSourceBreakdown{
+		SourceID: source.ID, Employer: source.Employer, Frequency: source.Frequency,
+		ExpectedMonthly: source.Gross.Mul(float64(source.PaychecksPerYear()) / 12),
+		Months:          months, Trend: TrendStable,
+	}
+	nets := make([]Money, len(months))
+	for i := range months {
+		months[i].Gross, months[i].Taxes = source.Paycheck(months[i].Net)
+		breakdown.Paychecks += months[i].Paychecks
+		breakdown.Gross += months[i].Gross
+		breakdown.Net += months[i].Net
+		breakdown.Taxes += months[i].Taxes
+		nets[i] = months[i].Net
+	}
+	breakdown.OtherDeductions = breakdown.Gross - breakdown.Net - breakdown.Taxes
+	breakdown.EffectiveTaxRate = taxRate(breakdown.Taxes, breakdown.Gross)
+
+	if len(months) > 1 {
+		slope, _ := linearFit(nets)
+		breakdown.Slope = Money(math.Round(slope))
+		threshold := math.Abs(float64(averageMoney(nets))) * 0.05
+		switch {
+		case slope > threshold:
+			breakdown.Trend = TrendIncreasing
+		case slope < -threshold:
+			breakdown.Trend = TrendDecreasing
+		}
+	}
+	return breakdown
+}
+
+// IncomeBreakdown is a user's pay over complete months by income source:
+// gross against net pay and the effective tax rate. UnlinkedIncome is the
+// income in the same months that is not linked to a source.
+type IncomeBreakdown struct {
+	UserID           uint                    `json:"user_id"`
+	Months           int                     `json:"months"`
+	Gross            Money                   `json:"gross"`
+	Net              Money                   `json:"net"`
+	Taxes            Money                   `json:"taxes"`
+	OtherDeductions  Money                   `json:"other_deductions"`
+	EffectiveTaxRate float64                 `json:"effective_tax_rate"`
+	UnlinkedIncome   Money                   `json:"unlinked_income"`
+	Sources          []IncomeSourceBreakdown `json:"sources"`
+}
+
+// NewIncomeBreakdown totals the breakdowns of the sources
+func NewIncomeBreakdown(userID uint, months int, sources []IncomeSourceBreakdown, unlinked Money) IncomeBreakdown {
+	breakdown := IncomeBreakdown{UserID: userID, Months: months, UnlinkedIncome: unlinked, Sources: sources}
+	for _, source := range sources {
+		breakdown.Gross += source.Gross
+		breakdown.Net += source.Net
+		breakdown.Taxes += source.Taxes
+		breakdown.OtherDeductions += source.OtherDeductions
+	}
+	breakdown.EffectiveTaxRate = taxRate(breakdown.Taxes, breakdown.Gross)
+	return breakdown
+}
+
+// taxRate is taxes as a percentage of gross, rounded to two decimals
+func taxRate(taxes, gross Money) float64 {
+	return math.Round(taxes.PercentOf(gross)*100) / 100
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testIncomeSource() *IncomeSource {
+	return &IncomeSource{
+		ID: 1, Employer: "Acme", Gross: NewMoney(5000), Frequency: PayFrequencyMonthly, Active: true,
+		Deductions: []PaycheckDeduction{
+			{Name: "Income tax", Kind: DeductionTax, Amount: NewMoney(1000)},
+			{Name: "Pension", Kind: DeductionRetirement, Amount: NewMoney(250)},
+		},
+	}
+}
+
+func TestIncomeSource_Validate(t *testing.T) {
+	assert.NoError(t, testIncomeSource().Validate())
+
+	var validationErr *ValidationError
+	source := testIncomeSource()
+	source.Frequency = "daily"
+	source.Deductions = append(source.Deductions, PaycheckDeduction{Name: "Loan", Kind: "loan", Amount: NewMoney(4000)})
+	require.ErrorAs(t, source.Validate(), &validationErr)
+	assert.Len(t, validationErr.Fields, 3) // Frequency, deduction kind and no net pay left
+}
+
+func TestIncomeSource_Paycheck(t *testing.T) {
+	source := testIncomeSource()
+	assert.Equal(t, NewMoney(3750), source.Net())
+	assert.Equal(t, NewMoney(1000), source.Taxes())
+
+	gross, taxes := source.Paycheck(NewMoney(7500))
+	assert.Equal(t, NewMoney(10000), gross)
+	assert.Equal(t, NewMoney(2000), taxes)
+}
+
+func TestNewIncomeBreakdown(t *testing.T) {
+	source := testIncomeSource()
+	months := []IncomeSourceMonth{
+		{Year: 2025, Month: 1, Paychecks: 1, Net: NewMoney(3750)},
+		{Year: 2025, Month: 2, Paychecks: 1, Net: NewMoney(3750)},
+		{Year: 2025, Month: 3, Paychecks: 2, Net: NewMoney(7500)}, // A bonus
+	}
+	breakdown := NewIncomeSourceBreakdown(source, months)
+
+	assert.Equal(t, NewMoney(5000), breakdown.ExpectedMonthly)
+	assert.Equal(t, 4, breakdown.Paychecks)
+	assert.Equal(t, NewMoney(20000), breakdown.Gross)
+	assert.Equal(t, NewMoney(15000), breakdown.Net)
+	assert.Equal(t, NewMoney(4000), breakdown.Taxes)
+	assert.Equal(t, NewMoney(1000), breakdown.OtherDeductions)
+	assert.Equal(t, 20.0, breakdown.EffectiveTaxRate)
+	assert.Equal(t, NewMoney(10000), breakdown.Months[2].Gross)
+	assert.Equal(t, TrendIncreasing, breakdown.Trend)
+
+	total := NewIncomeBreakdown(1, 3, []IncomeSourceBreakdown{breakdown}, NewMoney(300))
+	assert.Equal(t, NewMoney(20000), total.Gross)
+	assert.Equal(t, 20.0, total.EffectiveTaxRate)
+	assert.Equal(t, NewMoney(300), total.UnlinkedIncome)
+}
@@ -0,0 +1,136 @@
+package domain
+
+import (
+	"math"
+	"slices"
+)
+
+// Income stability levels, by how much monthly income varies
+const (
+	IncomeStable    = "stable"
+	IncomeVariable  = "variable"
+	IncomeIrregular = "irregular"
+)
+
+// Defaults and bounds of the income stability lookback, in months
+const (
+	DefaultIncomeStabilityMonths = 12
+	MinIncomeStabilityMonths     = 6
+	MaxIncomeStabilityMonths     = 36
+)
+
+// MonthlyIncome is one month of income and spending, with the averages of
+// the three and six months up to it (fewer at the start of the history)
+type MonthlyIncome struct {
+	Year            int   `json:"year"`
+	Month           int   `json:"month"`
+	Income          Money `json:"income"`
+	Expenses        Money `json:"expenses"`
+	RollingAverage3 Money `json:"rolling_average_3"`
+	RollingAverage6 Money `json:"rolling_average_6"`
+}
+
+// IncomeStability describes how regular a user's income is and how to live
+// on it. RecommendedSalary is what the user can pay themselves every month,
+// the average of their leanest third of months; the buffer tops it up in
+// the leaner ones. Buffer is the money on hand, and BufferMonths how many
+// months of average spending it covers; the less regular the income, the
+// more TargetBufferMonths it should cover.
+type IncomeStability struct {
+	UserID             uint            `json:"user_id"`
+	Months             []MonthlyIncome `json:"months"`
+	AverageIncome      Money           `json:"average_income"`
+	Average3Month      Money           `json:"average_3_month"`
+	Average6Month      Money           `json:"average_6_month"`
+	AverageExpenses    Money           `json:"average_expenses"`
+	Volatility         float64         `json:"volatility"` // Standard deviation of monthly income over its mean
+	Stability          string          `json:"stability"`
+	RecommendedSalary  Money           `json:"recommended_salary"`
+	Buffer             Money           `json:"buffer"`
+	BufferMonths       float64         `json:"buffer_months"`
+	TargetBufferMonths int             `json:"target_buffer_months"`
+	BufferShortfall    Money           `json:"buffer_shortfall"`
+}
+
+// NewIncomeStability analyses months of income, oldest first, given the
+// money on hand
+func NewIncomeStability(userID uint, months []MonthlyIncome, buffer Money) IncomeStability {
+	stability := IncomeStability{UserID: userID, Months: months, Buffer: buffer}
+	if len(months) == 0 {
+		stability.Stability = IncomeStable
+		stability.TargetBufferMonths = targetBufferMonths(IncomeStable)
+		return stability
+	}
+
+	incomes := make([]Money, len(months))
+	var expenses Money
+	for i := range months {
+		incomes[i] = months[i].Income
+		expenses += months[i].Expenses
+		months[i].RollingAverage3 = averageMoney(incomes[max(0, i-2) : i+1])
+		months[i].RollingAverage6 = averageMoney(incomes[max(0, i-5) : i+1])
+	}
+	last := months[len(months)-1]
+	stability.AverageIncome = averageMoney(incomes)
+	stability.Average3Month = last.RollingAverage3
+	stability.Average6Month = last.RollingAverage6
+	stability.AverageExpenses = expenses / Money(len(months))
+
+	stability.Volatility = incomeVolatility(incomes, stability.AverageIncome)
+	switch {
+	case stability.Volatility < 0.15:
+		stability.Stability = IncomeStable
+	case stability.Volatility < 0.4:
+		stability.Stability = IncomeVariable
+	default:
+		stability.Stability = IncomeIrregular
+	}
+
+	lean := slices.Clone(incomes)
+	slices.Sort(lean)
+	stability.RecommendedSalary = averageMoney(lean[:max(1, len(lean)/3)])
+
+	stability.TargetBufferMonths = targetBufferMonths(stability.Stability)
+	if stability.AverageExpenses > 0 {
+		stability.BufferMonths = math.Round(float64(buffer)/float64(stability.AverageExpenses)*10) / 10
+		target := stability.AverageExpenses * Money(stability.TargetBufferMonths)
+		stability.BufferShortfall = max(target-buffer, 0)
+	}
+	return stability
+}
+
+// targetBufferMonths is how many months of spending savings should cover
+func targetBufferMonths(stability string) int {
+	switch stability {
+	case IncomeIrregular:
+		return 9
+	case IncomeVariable:
+		return 6
+	default:
+		return 3
+	}
+}
+
+func averageMoney(amounts []Money) Money {
+	if len(amounts) == 0 {
+		return 0
+	}
+	var total Money
+	for _, amount := range amounts {
+		total += amount
+	}
+	return total / Money(len(amounts))
+}
+
+// incomeVolatility is the coefficient of variation of monthly income
+func incomeVolatility(incomes []Money, mean Money) float64 {
+	if mean <= 0 || len(incomes) < 2 {
+		return 0
+	}
+	var squares float64
+	for _, income := range incomes {
+		diff := float64(income - mean)
+		squares += diff * diff
+	}
+	return math.Round(math.Sqrt(squares/float64(len(incomes)))/float64(mean)*1000) / 1000
+}
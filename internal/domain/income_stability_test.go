@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func incomeMonths(incomes ...float64) []MonthlyIncome {
+	months := make([]MonthlyIncome, len(incomes))
+	for i, income := range incomes {
+		months[i] = MonthlyIncome{Year: 2025, Month: i + 1, Income: NewMoney(income), Expenses: NewMoney(2000)}
+	}
+	return months
+}
+
+func TestNewIncomeStability(t *testing.T) {
+	t.Run("irregular income", func(t *testing.T) {
+		stability := NewIncomeStability(1, incomeMonths(1000, 6000, 500, 4000, 2000, 4500), NewMoney(10000))
+
+		require.Len(t, stability.Months, 6)
+		assert.Equal(t, NewMoney(3500), stability.Months[1].RollingAverage3) // Only two months so far
+		assert.Equal(t, NewMoney(3500), stability.Average3Month)
+		assert.Equal(t, NewMoney(3000), stability.Average6Month)
+		assert.Equal(t, NewMoney(3000), stability.AverageIncome)
+		assert.InDelta(t, 0.66, stability.Volatility, 0.001)
+		assert.Equal(t, IncomeIrregular, stability.Stability)
+
+		assert.Equal(t, NewMoney(750), stability.RecommendedSalary) // The two leanest months
+		assert.Equal(t, NewMoney(2000), stability.AverageExpenses)
+		assert.Equal(t, 5.0, stability.BufferMonths)
+		assert.Equal(t, 9, stability.TargetBufferMonths)
+		assert.Equal(t, NewMoney(8000), stability.BufferShortfall)
+	})
+
+	t.Run("steady income", func(t *testing.T) {
+		stability := NewIncomeStability(1, incomeMonths(3000, 3000, 3100, 2900, 3000, 3000), NewMoney(7000))
+
+		assert.Equal(t, IncomeStable, stability.Stability)
+		assert.Equal(t, NewMoney(2950), stability.RecommendedSalary)
+		assert.Equal(t, 3, stability.TargetBufferMonths)
+		assert.Equal(t, 3.5, stability.BufferMonths)
+		assert.Zero(t, stability.BufferShortfall)
+	})
+
+	t.Run("no history", func(t *testing.T) {
+		stability := NewIncomeStability(1, nil, NewMoney(500))
+
+		assert.Equal(t, IncomeStable, stability.Stability)
+		assert.Zero(t, stability.RecommendedSalary)
+		assert.Zero(t, stability.BufferMonths)
+		assert.Equal(t, NewMoney(500), stability.Buffer)
+	})
+}
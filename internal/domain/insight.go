@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+// Insight types
+const (
+	InsightCategorySpike     = "category_spike"
+	InsightCategoryDrop      = "category_drop"
+	InsightSavingsRateChange = "savings_rate_change"
+	InsightNewMerchant       = "new_merchant"
+	// Advice an advisor left the user rather than an observation
+	InsightAdvisorRecommendation = "advisor_recommendation"
+)
+
+// Insight is a single natural-language observation about a user's finances
+type Insight struct {
+	Rank          int     `json:"rank"`
+	Type          string  `json:"type"`
+	Message       string  `json:"message"`
+	CategoryID    *uint   `json:"category_id,omitempty"`
+	CategoryName  string  `json:"category_name,omitempty"`
+	Merchant      string  `json:"merchant,omitempty"`
+	Advisor       string  `json:"advisor,omitempty"` // Who left an advisor recommendation
+	CurrentValue  float64 `json:"current_value"`
+	PreviousValue float64 `json:"previous_value"`
+	ChangePercent float64 `json:"change_percent"`
+	Score         float64 `json:"score"` // Estimated financial impact, used for ranking
+}
+
+// InsightsReport is the ranked list of insights for one user and period
+type InsightsReport struct {
+	UserID          uint      `json:"user_id"`
+	Period          string    `json:"period"`
+	StartDate       time.Time `json:"start_date"`
+	EndDate         time.Time `json:"end_date"`
+	ComparedPeriods int       `json:"compared_periods"`
+	Insights        []Insight `json:"insights"`
+	GeneratedAt     time.Time `json:"generated_at"`
+}
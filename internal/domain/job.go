@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Job statuses. A failed job goes back to queued until it runs out of
+// attempts, then it is dead and waits for an administrator to requeue it.
+const (
+	JobStatusQueued    = "queued"
+	JobStatusRunning   = "running"
+	JobStatusSucceeded = "succeeded"
+	JobStatusDead      = "dead"
+)
+
+var JobStatuses = []string{JobStatusQueued, JobStatusRunning, JobStatusSucceeded, JobStatusDead}
+
+var (
+	ErrUnknownJobType = errors.New("no handler is registered for the job type")
+	ErrJobActive      = errors.New("job is queued or running")
+)
+
+// IsValidJobStatus checks if a job status is supported
+func IsValidJobStatus(status string) bool {
+	for _, s := range JobStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Job is a unit of background work. Payload is the JSON its handler reads;
+// RunAt is when it is next due. Attempts counts the runs so far, including
+// one in progress.
+type Job struct {
+	ID          uint            `gorm:"primaryKey" json:"id"`
+	Type        string          `gorm:"type:varchar(50);index;not null" json:"type"`
+	Payload     json.RawMessage `gorm:"type:text" json:"payload,omitempty"`
+	Status      string          `gorm:"type:varchar(20);index:idx_jobs_status_run_at,priority:1;not null" json:"status"`
+	RunAt       time.Time       `gorm:"index:idx_jobs_status_run_at,priority:2;not null" json:"run_at"`
+	Attempts    int             `gorm:"not null;default:0" json:"attempts"`
+	MaxAttempts int             `gorm:"not null" json:"max_attempts"`
+	LastError   string          `gorm:"type:text" json:"last_error,omitempty"`
+	LockedAt    *time.Time      `json:"locked_at,omitempty"`
+	FinishedAt  *time.Time      `json:"finished_at,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// JobFilter narrows down job queries. Zero values are ignored.
+type JobFilter struct {
+	Type   string
+	Status string
+	Limit  int
+	Offset int
+}
@@ -0,0 +1,213 @@
+package domain
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// Loan limits
+const (
+	MaxLoanTermMonths = 600 // 50 years
+	MaxLoanAPR        = 100
+)
+
+// Loan is money a user borrowed and pays back in equal monthly payments,
+// such as a car loan or a mortgage. APR is the annual interest rate in
+// percent, compounded monthly. Payment n is due n months after StartDate,
+// on StartDate's day of the month or the month's last day.
+type Loan struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	UserID     uint      `gorm:"not null;index" json:"user_id"`
+	Name       string    `gorm:"type:varchar(100);not null" json:"name"`
+	Lender     string    `gorm:"type:varchar(100)" json:"lender,omitempty"`
+	Principal  Money     `gorm:"type:integer;not null" json:"principal"`
+	APR        float64   `gorm:"not null" json:"apr"`
+	TermMonths int       `gorm:"not null" json:"term_months"`
+	StartDate  time.Time `gorm:"not null" json:"start_date"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Validate checks the name, principal, rate, term and start date
+func (l *Loan) Validate() error {
+	var v validator
+	l.Name = strings.TrimSpace(l.Name)
+	l.Lender = strings.TrimSpace(l.Lender)
+	v.check(l.Name != "", "name", "is required")
+	v.check(len(l.Name) <= 100, "name", "must be at most 100 characters")
+	v.check(len(l.Lender) <= 100, "lender", "must be at most 100 characters")
+	v.check(l.Principal > 0, "principal", "must be positive")
+	v.check(l.APR >= 0 && l.APR <= MaxLoanAPR, "apr", "must be between 0 and 100")
+	v.check(l.TermMonths >= 1 && l.TermMonths <= MaxLoanTermMonths, "term_months", "must be between 1 and 600")
+	v.check(!l.StartDate.IsZero(), "start_date", "is required")
+	return v.err()
+}
+
+// MonthlyPayment is the payment that pays the loan off over its term,
+// rounded to the cent; the last payment makes up for the rounding
+func (l *Loan) MonthlyPayment() Money {
+	if l.TermMonths < 1 {
+		return l.Principal
+	}
+	rate := l.APR / 100 / 12
+	if rate == 0 {
+		return Money(math.Ceil(float64(l.Principal) / float64(l.TermMonths)))
+	}
+	return l.Principal.Mul(rate / (1 - math.Pow(1+rate, -float64(l.TermMonths))))
+}
+
+// DueDate is the due date of payment number n
+func (l *Loan) DueDate(n int) time.Time {
+	month := time.Date(l.StartDate.Year(), l.StartDate.Month(), 1, 0, 0, 0, 0, l.StartDate.Location()).AddDate(0, n, 0)
+	return dayOfMonth(month.Year(), month.Month(), l.StartDate.Day(), month.Location())
+}
+
+// LoanPayment links a transaction that paid a loan to the row of the loan's
+// schedule it paid. Each transaction pays one row, and each row is paid by
+// one transaction.
+type LoanPayment struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	UserID        uint      `gorm:"not null;index" json:"user_id"`
+	LoanID        uint      `gorm:"not null;uniqueIndex:idx_loan_payments_loan_number,priority:1" json:"loan_id"`
+	Number        int       `gorm:"not null;uniqueIndex:idx_loan_payments_loan_number,priority:2" json:"number"`
+	TransactionID uint      `gorm:"not null;uniqueIndex" json:"transaction_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// LoanExtraPayments are what-if payments on top of the scheduled ones:
+// Monthly with every payment still to make, and LumpSum once with payment
+// number LumpSumNumber
+type LoanExtraPayments struct {
+	Monthly       Money `json:"monthly"`
+	LumpSum       Money `json:"lump_sum"`
+	LumpSumNumber int   `json:"lump_sum_number,omitempty"`
+}
+
+// Validate checks that the extra payments are not negative and that a lump
+// sum falls within the loan's term
+func (e LoanExtraPayments) Validate(termMonths int) error {
+	var v validator
+	v.check(e.Monthly >= 0, "extra_monthly", "must not be negative")
+	v.check(e.LumpSum >= 0, "lump_sum", "must not be negative")
+	if e.LumpSum > 0 {
+		v.check(e.LumpSumNumber >= 1 && e.LumpSumNumber <= termMonths, "lump_sum_number",
+			"must be the number of a payment within the loan's term")
+	}
+	return v.err()
+}
+
+// AmortizationRow is one monthly payment of a loan's schedule. Payment is
+// Principal and Interest together; Extra comes on top and goes to the
+// principal. Balance is what is left owing after the payment. Rows paid by
+// a linked transaction carry its ID, and their Payment and Extra are what
+// it actually paid.
+type AmortizationRow struct {
+	Number        int       `json:"number"`
+	DueDate       time.Time `json:"due_date"`
+	Payment       Money     `json:"payment"`
+	Principal     Money     `json:"principal"`
+	Interest      Money     `json:"interest"`
+	Extra         Money     `json:"extra,omitempty"`
+	Balance       Money     `json:"balance"`
+	TransactionID *uint     `json:"transaction_id,omitempty"`
+}
+
+// LoanSchedule is the payments that pay a loan off
+type LoanSchedule struct {
+	LoanID         uint              `json:"loan_id"`
+	MonthlyPayment Money             `json:"monthly_payment"`
+	Payments       int               `json:"payments"`
+	TotalInterest  Money             `json:"total_interest"`
+	TotalPaid      Money             `json:"total_paid"`
+	PayoffDate     time.Time         `json:"payoff_date"`
+	Rows           []AmortizationRow `json:"rows"`
+}
+
+// Amortize builds the loan's schedule. Paid holds the amounts actually paid
+// by payment number; those rows pay exactly that, and the other rows the
+// monthly payment and the extra payments. Interest accrues monthly on the
+// balance, and the schedule ends once the balance is paid off. The last
+// payment of the term pays whatever is left.
+func Amortize(loan *Loan, paid map[int]Money, extra LoanExtraPayments) LoanSchedule {
+	payment := loan.MonthlyPayment()
+	rate := loan.APR / 100 / 12
+	schedule := LoanSchedule{LoanID: loan.ID, MonthlyPayment: payment, Rows: []AmortizationRow{}}
+
+	balance := loan.Principal
+	for n := 1; n <= loan.TermMonths && balance > 0; n++ {
+		row := AmortizationRow{Number: n, DueDate: loan.DueDate(n), Interest: balance.Mul(rate)}
+		if amount, ok := paid[n]; ok {
+			row.Payment = min(amount, payment)
+			row.Extra = amount - row.Payment
+		} else {
+			row.Payment = payment
+			row.Extra = extra.Monthly
+			if n == extra.LumpSumNumber {
+				row.Extra += extra.LumpSum
+			}
+		}
+		row.Principal = row.Payment - row.Interest
+
+		owed := balance + row.Interest
+		_, actual := paid[n]
+		if !actual && (n == loan.TermMonths || row.Payment+row.Extra >= owed) {
+			// The last payment clears the balance, and none pays more than is owed
+			row.Payment, row.Principal, row.Extra = owed, balance, 0
+		}
+		balance -= row.Principal + row.Extra
+		row.Balance = balance
+
+		schedule.Rows = append(schedule.Rows, row)
+		schedule.TotalInterest += row.Interest
+		schedule.TotalPaid += row.Payment + row.Extra
+		schedule.PayoffDate = row.DueDate
+	}
+	schedule.Payments = len(schedule.Rows)
+	return schedule
+}
+
+// LoanPayoffProjection is when a loan is paid off, given the payments made
+// so far, with and without extra payments from here on. Balance is left
+// owing after the last payment made. The remaining payments and interest
+// include the extra payments; InterestSaved and MonthsSaved compare them
+// with the scheduled payments.
+type LoanPayoffProjection struct {
+	LoanID              uint              `json:"loan_id"`
+	Balance             Money             `json:"balance"`
+	PaymentsMade        int               `json:"payments_made"`
+	Extra               LoanExtraPayments `json:"extra"`
+	RemainingPayments   int               `json:"remaining_payments"`
+	RemainingInterest   Money             `json:"remaining_interest"`
+	PayoffDate          time.Time         `json:"payoff_date"`
+	ScheduledPayoffDate time.Time         `json:"scheduled_payoff_date"`
+	InterestSaved       Money             `json:"interest_saved"`
+	MonthsSaved         int               `json:"months_saved"`
+}
+
+// ProjectLoanPayoff projects the payoff of the loan from the payments made,
+// by payment number, with the extra payments on every payment not made yet
+func ProjectLoanPayoff(loan *Loan, paid map[int]Money, extra LoanExtraPayments) LoanPayoffProjection {
+	last := 0
+	for n := range paid {
+		last = max(last, n)
+	}
+	scheduled := Amortize(loan, paid, LoanExtraPayments{})
+	withExtra := Amortize(loan, paid, extra)
+
+	projection := LoanPayoffProjection{
+		LoanID: loan.ID, Balance: loan.Principal, PaymentsMade: len(paid), Extra: extra,
+		PayoffDate: withExtra.PayoffDate, ScheduledPayoffDate: scheduled.PayoffDate,
+		InterestSaved: scheduled.TotalInterest - withExtra.TotalInterest,
+		MonthsSaved:   scheduled.Payments - withExtra.Payments,
+	}
+	for _, row := range withExtra.Rows {
+		if row.Number <= last {
+			projection.Balance = row.Balance
+			continue
+		}
+		projection.RemainingPayments++
+		projection.RemainingInterest += row.Interest
+	}
+	return projection
+}
@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testLoan() *Loan {
+	return &Loan{
+		ID: 1, Name: "Car", Principal: NewMoney(10000), APR: 6, TermMonths: 12,
+		StartDate: time.Date(2025, time.January, 31, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestLoan_Validate(t *testing.T) {
+	assert.NoError(t, testLoan().Validate())
+
+	var validationErr *ValidationError
+	err := (&Loan{Name: " ", APR: 120, TermMonths: 0}).Validate()
+	require.ErrorAs(t, err, &validationErr)
+	assert.Len(t, validationErr.Fields, 5)
+}
+
+func TestLoan_MonthlyPaymentAndDueDates(t *testing.T) {
+	loan := testLoan()
+	assert.Equal(t, NewMoney(860.66), loan.MonthlyPayment())
+	assert.Equal(t, time.Date(2025, time.February, 28, 0, 0, 0, 0, time.UTC), loan.DueDate(1))
+	assert.Equal(t, time.Date(2025, time.March, 31, 0, 0, 0, 0, time.UTC), loan.DueDate(2))
+
+	loan.APR = 0
+	assert.Equal(t, NewMoney(833.34), loan.MonthlyPayment())
+}
+
+func TestAmortize(t *testing.T) {
+	loan := testLoan()
+
+	t.Run("pays the loan off over its term", func(t *testing.T) {
+		schedule := Amortize(loan, nil, LoanExtraPayments{})
+		require.Len(t, schedule.Rows, 12)
+		first := schedule.Rows[0]
+		assert.Equal(t, NewMoney(50), first.Interest)
+		assert.Equal(t, NewMoney(810.66), first.Principal)
+		assert.Equal(t, NewMoney(9189.34), first.Balance)
+
+		last := schedule.Rows[11]
+		assert.Zero(t, last.Balance)
+		assert.InDelta(t, 860.66, last.Payment.Float64(), 0.05)
+		assert.Equal(t, loan.Principal+schedule.TotalInterest, schedule.TotalPaid)
+		assert.Equal(t, loan.DueDate(12), schedule.PayoffDate)
+	})
+
+	t.Run("extra payments shorten the schedule", func(t *testing.T) {
+		schedule := Amortize(loan, nil, LoanExtraPayments{Monthly: NewMoney(200), LumpSum: NewMoney(2000), LumpSumNumber: 2})
+		assert.Less(t, schedule.Payments, 12)
+		assert.Zero(t, schedule.Rows[len(schedule.Rows)-1].Balance)
+		assert.Equal(t, loan.Principal+schedule.TotalInterest, schedule.TotalPaid)
+	})
+
+	t.Run("paid rows use the amounts actually paid", func(t *testing.T) {
+		schedule := Amortize(loan, map[int]Money{1: NewMoney(1000), 2: NewMoney(500)}, LoanExtraPayments{})
+		assert.Equal(t, NewMoney(860.66), schedule.Rows[0].Payment)
+		assert.Equal(t, NewMoney(139.34), schedule.Rows[0].Extra)
+		assert.Equal(t, NewMoney(500), schedule.Rows[1].Payment)
+		assert.Zero(t, schedule.Rows[len(schedule.Rows)-1].Balance)
+	})
+}
+
+func TestProjectLoanPayoff(t *testing.T) {
+	loan := testLoan()
+	paid := map[int]Money{1: NewMoney(860.66), 2: NewMoney(860.66)}
+
+	projection := ProjectLoanPayoff(loan, paid, LoanExtraPayments{Monthly: NewMoney(500)})
+	assert.Equal(t, 2, projection.PaymentsMade)
+	assert.Equal(t, Amortize(loan, nil, LoanExtraPayments{}).Rows[1].Balance, projection.Balance)
+	assert.Equal(t, loan.DueDate(12), projection.ScheduledPayoffDate)
+	assert.True(t, projection.PayoffDate.Before(projection.ScheduledPayoffDate))
+	assert.Positive(t, projection.MonthsSaved)
+	assert.Positive(t, projection.InterestSaved)
+	assert.Equal(t, 10-projection.MonthsSaved, projection.RemainingPayments)
+
+	t.Run("without extra payments nothing is saved", func(t *testing.T) {
+		projection := ProjectLoanPayoff(loan, paid, LoanExtraPayments{})
+		assert.Zero(t, projection.InterestSaved)
+		assert.Zero(t, projection.MonthsSaved)
+		assert.Equal(t, 10, projection.RemainingPayments)
+	})
+}
+
+func TestLoanExtraPayments_Validate(t *testing.T) {
+	assert.NoError(t, LoanExtraPayments{Monthly: NewMoney(10)}.Validate(12))
+	assert.Error(t, LoanExtraPayments{Monthly: -1}.Validate(12))
+	assert.Error(t, LoanExtraPayments{LumpSum: NewMoney(100), LumpSumNumber: 13}.Validate(12))
+}
@@ -0,0 +1,144 @@
+package domain
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Merchant rule match types
+const (
+	MerchantMatchPrefix   = "prefix"
+	MerchantMatchContains = "contains"
+)
+
+// processorPrefixes are payment processor markers that precede the real
+// merchant name on card statements ("SQ *BLUE BOTTLE", "TST* JOES DINER")
+var processorPrefixes = map[string]bool{
+	"SQ": true, "TST": true, "POS": true, "SP": true, "PP": true, "PAYPAL": true,
+	"DD": true, "CARD": true, "PURCHASE": true, "DEBIT": true,
+}
+
+// Merchant is a clean merchant name that raw transaction descriptions are
+// mapped to. Merchants and their rules are shared by all users.
+type Merchant struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	Name      string         `gorm:"type:varchar(100);uniqueIndex;not null" json:"name"`
+	Rules     []MerchantRule `gorm:"foreignKey:MerchantID" json:"rules,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// MerchantRule maps descriptions to a merchant. Pattern is compared with the
+// normalized description on word boundaries: at the start for "prefix",
+// anywhere for "contains".
+type MerchantRule struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	MerchantID uint      `gorm:"index;not null" json:"merchant_id"`
+	Pattern    string    `gorm:"type:varchar(100);not null" json:"pattern"`
+	MatchType  string    `gorm:"type:varchar(10);default:'contains'" json:"match_type"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Matches reports whether the rule applies to an already normalized description
+func (r MerchantRule) Matches(normalized string) bool {
+	pattern := NormalizeDescription(r.Pattern)
+	if pattern == "" {
+		return false
+	}
+	if r.MatchType == MerchantMatchPrefix {
+		return normalized == pattern || strings.HasPrefix(normalized, pattern+" ")
+	}
+	return strings.Contains(" "+normalized+" ", " "+pattern+" ")
+}
+
+// BestMerchantRule returns the rule that matches the description, preferring
+// the longest pattern so "UBER EATS" wins over "UBER"; nil if none matches
+func BestMerchantRule(rules []MerchantRule, description string) *MerchantRule {
+	normalized := NormalizeDescription(description)
+	var best *MerchantRule
+	for i := range rules {
+		if rules[i].Matches(normalized) && (best == nil || len(rules[i].Pattern) > len(best.Pattern)) {
+			best = &rules[i]
+		}
+	}
+	return best
+}
+
+// IsValidMerchantMatchType reports whether a rule can use the match type
+func IsValidMerchantMatchType(matchType string) bool {
+	return matchType == MerchantMatchPrefix || matchType == MerchantMatchContains
+}
+
+// NormalizeDescription reduces a raw description to upper-case words without
+// punctuation or reference codes, e.g. "AMZN Mktp US*2F4" becomes "AMZN MKTP US"
+func NormalizeDescription(description string) string {
+	words := strings.FieldsFunc(strings.ToUpper(description), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '&' && r != '\''
+	})
+
+	kept := words[:0]
+	for _, word := range words {
+		// Store numbers, card suffixes and transaction references carry digits
+		if strings.IndexFunc(word, unicode.IsDigit) >= 0 {
+			continue
+		}
+		kept = append(kept, word)
+	}
+	return strings.Join(kept, " ")
+}
+
+// CleanMerchantName derives a display name for descriptions no rule matches:
+// processor prefixes are dropped and at most four words kept in title case.
+// It returns "" when nothing usable is left.
+func CleanMerchantName(description string) string {
+	words := strings.Fields(NormalizeDescription(description))
+	for len(words) > 1 && processorPrefixes[words[0]] {
+		words = words[1:]
+	}
+	if len(words) > 4 {
+		words = words[:4]
+	}
+
+	for i, word := range words {
+		runes := []rune(word)
+		words[i] = string(runes[0]) + strings.ToLower(string(runes[1:]))
+	}
+	return strings.Join(words, " ")
+}
+
+// GetDefaultMerchants returns well-known merchants with the rules that
+// recognize their usual card statement descriptions
+func GetDefaultMerchants() []Merchant {
+	prefix := func(patterns ...string) []MerchantRule {
+		rules := make([]MerchantRule, len(patterns))
+		for i, pattern := range patterns {
+			rules[i] = MerchantRule{Pattern: pattern, MatchType: MerchantMatchPrefix}
+		}
+		return rules
+	}
+	contains := func(patterns ...string) []MerchantRule {
+		rules := make([]MerchantRule, len(patterns))
+		for i, pattern := range patterns {
+			rules[i] = MerchantRule{Pattern: pattern, MatchType: MerchantMatchContains}
+		}
+		return rules
+	}
+
+	return []Merchant{
+		{Name: "Amazon", Rules: append(prefix("AMZN", "AMAZON"), contains("AMAZON COM", "AMZN MKTP")...)},
+		{Name: "Apple", Rules: append(prefix("APPLE COM"), contains("ITUNES", "APPLE COM BILL")...)},
+		{Name: "Google", Rules: prefix("GOOGLE")},
+		{Name: "Netflix", Rules: contains("NETFLIX")},
+		{Name: "Spotify", Rules: contains("SPOTIFY")},
+		{Name: "Uber Eats", Rules: contains("UBER EATS", "UBEREATS")},
+		{Name: "Uber", Rules: prefix("UBER")},
+		{Name: "Lyft", Rules: prefix("LYFT")},
+		{Name: "Starbucks", Rules: contains("STARBUCKS")},
+		{Name: "McDonald's", Rules: contains("MCDONALD'S", "MCDONALDS")},
+		{Name: "Walmart", Rules: contains("WALMART", "WAL MART", "WM SUPERCENTER")},
+		{Name: "Target", Rules: prefix("TARGET")},
+		{Name: "Costco", Rules: contains("COSTCO")},
+		{Name: "Shell", Rules: prefix("SHELL")},
+	}
+}
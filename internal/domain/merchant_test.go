@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeDescription(t *testing.T) {
+	tests := map[string]string{
+		"AMZN Mktp US*2F4":         "AMZN MKTP US",
+		"APPLE.COM/BILL":           "APPLE COM BILL",
+		"McDonald's #4521":         "MCDONALD'S",
+		"  starbucks   store 0042": "STARBUCKS STORE",
+		"1234-5678":                "",
+	}
+	for input, want := range tests {
+		assert.Equal(t, want, NormalizeDescription(input), input)
+	}
+}
+
+func TestCleanMerchantName(t *testing.T) {
+	tests := map[string]string{
+		"SQ *BLUE BOTTLE COFFEE 0042":      "Blue Bottle Coffee",
+		"TST* JOES DINER":                  "Joes Diner",
+		"PAYPAL":                           "Paypal",
+		"THE VERY LONG NAME OF A MERCHANT": "The Very Long Name",
+		"#0042":                            "",
+	}
+	for input, want := range tests {
+		assert.Equal(t, want, CleanMerchantName(input), input)
+	}
+}
+
+func TestBestMerchantRule(t *testing.T) {
+	rules := []MerchantRule{
+		{ID: 1, Pattern: "UBER", MatchType: MerchantMatchPrefix},
+		{ID: 2, Pattern: "UBER EATS", MatchType: MerchantMatchContains},
+		{ID: 3, Pattern: "SHELL", MatchType: MerchantMatchPrefix},
+	}
+
+	assert.Equal(t, uint(2), BestMerchantRule(rules, "UBER EATS 800-592").ID)
+	assert.Equal(t, uint(1), BestMerchantRule(rules, "Uber *Trip").ID)
+	// Prefix rules only match at the start and on word boundaries
+	assert.Nil(t, BestMerchantRule(rules, "SEASHELL GIFTS"))
+	assert.Nil(t, BestMerchantRule(rules, "MY SHELL STATION"))
+}
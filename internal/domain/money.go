@@ -0,0 +1,217 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidAmount is returned for amounts that are not a decimal number
+// with at most two decimal places
+var ErrInvalidAmount = errors.New("invalid amount")
+
+// minorUnits is the number of minor units (cents) in a major unit
+const minorUnits = 100
+
+// Money is an amount in minor units (cents). Adding and subtracting amounts
+// is exact, so totals of many transactions do not drift the way float64
+// sums do. It is stored as an integer column and reads and writes JSON as a
+// decimal number of major units, e.g. 12.50.
+type Money int64
+
+// NewMoney converts an amount in major units, rounding it to the nearest cent
+func NewMoney(amount float64) Money {
+	return Money(math.Round(amount * minorUnits))
+}
+
+// ParseMoney reads a decimal amount such as "-12.5" or "1234.56" without
+// going through float64
+func ParseMoney(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "-")
+	if negative || strings.HasPrefix(s, "+") {
+		s = s[1:]
+	}
+
+	whole, fraction, _ := strings.Cut(s, ".")
+	if (whole == "" && fraction == "") || len(fraction) > 2 {
+		return 0, ErrInvalidAmount
+	}
+	if whole == "" {
+		whole = "0"
+	}
+	fraction += strings.Repeat("0", 2-len(fraction))
+	for _, digits := range []string{whole, fraction} {
+		if strings.Trim(digits, "0123456789") != "" {
+			return 0, ErrInvalidAmount
+		}
+	}
+
+	major, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || major > math.MaxInt64/minorUnits-1 {
+		return 0, ErrInvalidAmount
+	}
+	minor, err := strconv.ParseInt(fraction, 10, 64)
+	if err != nil {
+		return 0, ErrInvalidAmount
+	}
+	amount := Money(major*minorUnits + minor)
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+// Float64 returns the amount in major units, for ratios and statistics
+func (m Money) Float64() float64 {
+	return float64(m) / minorUnits
+}
+
+// Abs returns the amount without its sign
+func (m Money) Abs() Money {
+	if m < 0 {
+		return -m
+	}
+	return m
+}
+
+// Mul scales the amount, rounding to the nearest cent
+func (m Money) Mul(factor float64) Money {
+	return Money(math.Round(float64(m) * factor))
+}
+
+// PercentOf returns the amount as a percentage of total, or zero without a total
+func (m Money) PercentOf(total Money) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(m) / float64(total) * 100
+}
+
+// String formats the amount in major units with two decimals, e.g. "-12.50"
+func (m Money) String() string {
+	sign := ""
+	if m < 0 {
+		sign = "-"
+	}
+	abs := uint64(m.Abs())
+	return sign + strconv.FormatUint(abs/minorUnits, 10) + "." + twoDigits(abs%minorUnits)
+}
+
+// Format formats the amount for display in the currency, e.g. "$1,234.56"
+// or "¥1,235". Unknown currencies get their code after the amount.
+func (m Money) Format(currency string) string {
+	return m.FormatStyle(currency, DefaultNumberStyle)
+}
+
+// NumberStyle is how a locale writes amounts: its decimal and thousands
+// separators, and whether the currency symbol follows the amount
+type NumberStyle struct {
+	Decimal     string
+	Group       string
+	SymbolAfter bool
+}
+
+// DefaultNumberStyle writes amounts as "$1,234.56"
+var DefaultNumberStyle = NumberStyle{Decimal: ".", Group: ","}
+
+// FormatStyle formats the amount for display in the currency the way the
+// style writes numbers, e.g. "1.234,56 €"
+func (m Money) FormatStyle(currency string, style NumberStyle) string {
+	currencyStyle, known := currencyStyles[strings.ToUpper(currency)]
+	if !known {
+		currencyStyle.decimals = 2
+	}
+
+	abs := m.Abs()
+	units := uint64(abs) / minorUnits
+	cents := uint64(abs) % minorUnits
+	if currencyStyle.decimals == 0 {
+		units = uint64(math.Round(abs.Float64()))
+	}
+	amount := groupThousands(strconv.FormatUint(units, 10), style.Group)
+	if currencyStyle.decimals > 0 {
+		amount += style.Decimal + twoDigits(cents)
+	}
+
+	sign := ""
+	if m < 0 {
+		sign = "-"
+	}
+	if !known {
+		code := strings.ToUpper(strings.TrimSpace(currency))
+		if code == "" {
+			return sign + amount
+		}
+		return sign + amount + " " + code
+	}
+	if style.SymbolAfter {
+		return sign + amount + " " + currencyStyle.symbol
+	}
+	return sign + currencyStyle.symbol + amount
+}
+
+// MarshalJSON writes the amount as a decimal number of major units
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON reads a decimal number, or a string holding one, of major units
+func (m *Money) UnmarshalJSON(data []byte) error {
+	text := strings.Trim(string(data), `"`)
+	if text == "null" {
+		return nil
+	}
+	amount, err := ParseMoney(text)
+	if err != nil {
+		// Numbers such as 1e3 or 0.125 are still accepted, rounded to the
+		// cent, as long as they fit
+		value, floatErr := strconv.ParseFloat(text, 64)
+		if floatErr != nil || math.IsInf(value, 0) || math.IsNaN(value) || math.Abs(value) > math.MaxInt64/minorUnits {
+			return ErrInvalidAmount
+		}
+		amount = NewMoney(value)
+	}
+	*m = amount
+	return nil
+}
+
+type currencyStyle struct {
+	symbol   string
+	decimals int
+}
+
+// currencyStyles lists how the common currencies are written
+var currencyStyles = map[string]currencyStyle{
+	"USD": {symbol: "$", decimals: 2},
+	"EUR": {symbol: "€", decimals: 2},
+	"GBP": {symbol: "£", decimals: 2},
+	"TRY": {symbol: "₺", decimals: 2},
+	"JPY": {symbol: "¥", decimals: 0},
+}
+
+func twoDigits(n uint64) string {
+	if n < 10 {
+		return "0" + strconv.FormatUint(n, 10)
+	}
+	return strconv.FormatUint(n, 10)
+}
+
+func groupThousands(digits, separator string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+	var b strings.Builder
+	lead := len(digits) % 3
+	if lead > 0 {
+		b.WriteString(digits[:lead])
+	}
+	for i := lead; i < len(digits); i += 3 {
+		if b.Len() > 0 {
+			b.WriteString(separator)
+		}
+		b.WriteString(digits[i : i+3])
+	}
+	return b.String()
+}
@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Money
+		wantErr bool
+	}{
+		{"12.50", 1250, false},
+		{"12.5", 1250, false},
+		{"12", 1200, false},
+		{" 0.07 ", 7, false},
+		{".5", 50, false},
+		{"-3.25", -325, false},
+		{"+1", 100, false},
+		{"1.234", 0, true},
+		{"1,000", 0, true},
+		{"abc", 0, true},
+		{"", 0, true},
+		{".", 0, true},
+		{"1e3", 0, true},
+		{"-+5", 0, true},
+		{"+-5", 0, true},
+		{"--5", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseMoney(tt.input)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidAmount)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMoney_SumsExactly(t *testing.T) {
+	var total Money
+	for i := 0; i < 10; i++ {
+		total += NewMoney(0.1)
+	}
+	assert.Equal(t, NewMoney(1), total)
+	assert.Equal(t, 1.0, total.Float64())
+}
+
+func TestMoney_Format(t *testing.T) {
+	tests := []struct {
+		amount   Money
+		currency string
+		want     string
+	}{
+		{123456, "USD", "$1,234.56"},
+		{-5, "usd", "-$0.05"},
+		{100000000, "EUR", "€1,000,000.00"},
+		{999, "GBP", "£9.99"},
+		{123456, "JPY", "¥1,235"},
+		{123456, "CHF", "1,234.56 CHF"},
+		{123456, "", "1,234.56"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.amount.Format(tt.currency))
+		})
+	}
+	assert.Equal(t, "-12.50", Money(-1250).String())
+}
+
+func TestMoney_FormatStyle(t *testing.T) {
+	german := NumberStyle{Decimal: ",", Group: ".", SymbolAfter: true}
+
+	assert.Equal(t, "1.234,56 €", Money(123456).FormatStyle("EUR", german))
+	assert.Equal(t, "-0,05 $", Money(-5).FormatStyle("USD", german))
+	assert.Equal(t, "1.235 ¥", Money(123456).FormatStyle("JPY", german))
+	assert.Equal(t, "1.234,56 CHF", Money(123456).FormatStyle("CHF", german))
+	assert.Equal(t, Money(123456).Format("USD"), Money(123456).FormatStyle("USD", DefaultNumberStyle))
+}
+
+func TestMoney_PercentOf(t *testing.T) {
+	assert.Equal(t, 25.0, NewMoney(50).PercentOf(NewMoney(200)))
+	assert.Zero(t, NewMoney(50).PercentOf(0))
+}
+
+func TestMoney_JSON(t *testing.T) {
+	data, err := json.Marshal(Transaction{Amount: NewMoney(12.5)})
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"amount":12.50`)
+
+	for input, want := range map[string]Money{
+		`{"amount": 12.5}`:   1250,
+		`{"amount": "7.25"}`: 725,
+		`{"amount": 1e2}`:    10000,
+		`{"amount": 0.125}`:  13,
+		`{"amount": null}`:   0,
+	} {
+		var tx Transaction
+		require.NoError(t, json.Unmarshal([]byte(input), &tx), input)
+		assert.Equal(t, want, tx.Amount, input)
+	}
+
+	for _, input := range []string{
+		`{"amount": "twelve"}`, `{"amount": 1e30}`, `{"amount": -1e30}`, `{"amount": "NaN"}`, `{"amount": "-Inf"}`,
+	} {
+		var tx Transaction
+		assert.ErrorIs(t, json.Unmarshal([]byte(input), &tx), ErrInvalidAmount, input)
+	}
+}
+
+func FuzzParseMoney(f *testing.F) {
+	for _, seed := range []string{"12.50", "-3.25", "+1", ".5", "1.234", "-", "-+1", "92233720368547758.07", " 0.07 "} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		amount, err := ParseMoney(s)
+		if err != nil {
+			require.ErrorIs(t, err, ErrInvalidAmount)
+			return
+		}
+		again, err := ParseMoney(amount.String())
+		require.NoError(t, err, "%q parsed to %s", s, amount)
+		assert.Equal(t, amount, again)
+	})
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// NetWorthCurrency is the currency net worth is totalled in, the one market
+// prices are quoted in
+const NetWorthCurrency = BaseCurrency
+
+// Sources of the assets counted in net worth
+const (
+	AssetSourceHolding = "holding"
+	AssetSourceWallet  = "wallet"
+)
+
+// AccountBalance is an account's opening balance plus every transaction
+// recorded on it
+type AccountBalance struct {
+	AccountID uint   `json:"account_id"`
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Currency  string `json:"currency"`
+	Balance   Money  `json:"balance"`
+}
+
+// AssetValue is a holding or a wallet's balance valued at the latest
+// recorded price of its symbol. WalletID is only set for wallets.
+type AssetValue struct {
+	Source   string  `json:"source"`
+	Symbol   string  `json:"symbol"`
+	WalletID uint    `json:"wallet_id,omitempty"`
+	Label    string  `json:"label,omitempty"`
+	Quantity float64 `json:"quantity"`
+	Price    float64 `json:"price"`
+	Value    Money   `json:"value"`
+}
+
+// NetWorth is what a user owns at At: the balances of their accounts and
+// the value of their holdings and wallets. Only accounts in Currency count
+// towards the totals; accounts in other currencies and assets without a
+// recorded price are listed in Excluded instead.
+type NetWorth struct {
+	Currency      string           `json:"currency"`
+	At            time.Time        `json:"at"`
+	Accounts      []AccountBalance `json:"accounts"`
+	Assets        []AssetValue     `json:"assets"`
+	AccountsTotal Money            `json:"accounts_total"`
+	AssetsTotal   Money            `json:"assets_total"`
+	Total         Money            `json:"total"`
+	Excluded      []string         `json:"excluded,omitempty"`
+}
+
+// NewNetWorth totals the account balances and values the holdings and
+// wallets at the prices recorded at or before at
+func NewNetWorth(accounts []AccountBalance, holdings []Holding, wallets []Wallet, prices PriceSeries, at time.Time) NetWorth {
+	worth := NetWorth{Currency: NetWorthCurrency, At: at, Accounts: []AccountBalance{}, Assets: []AssetValue{}}
+	for _, account := range accounts {
+		worth.Accounts = append(worth.Accounts, account)
+		if account.Currency == NetWorthCurrency {
+			worth.AccountsTotal += account.Balance
+		} else {
+			worth.Excluded = append(worth.Excluded, "account "+account.Name+" in "+account.Currency)
+		}
+	}
+
+	assets := make([]AssetValue, 0, len(holdings)+len(wallets))
+	for _, holding := range holdings {
+		assets = append(assets, AssetValue{Source: AssetSourceHolding, Symbol: holding.Symbol, Quantity: holding.Quantity})
+	}
+	for _, wallet := range wallets {
+		assets = append(assets, AssetValue{
+			Source: AssetSourceWallet, Symbol: wallet.Symbol(), WalletID: wallet.ID, Label: wallet.Label, Quantity: wallet.Balance,
+		})
+	}
+	sort.SliceStable(assets, func(i, j int) bool { return assets[i].Symbol < assets[j].Symbol })
+
+	for _, asset := range assets {
+		snapshot, ok := prices.AsOf(asset.Symbol, at)
+		if !ok {
+			worth.Excluded = append(worth.Excluded, asset.Source+" "+asset.Symbol+" without a price")
+			continue
+		}
+		asset.Price = snapshot.Price
+		asset.Value = NewMoney(asset.Quantity * snapshot.Price)
+		worth.AssetsTotal += asset.Value
+		worth.Assets = append(worth.Assets, asset)
+	}
+	worth.Total = worth.AccountsTotal + worth.AssetsTotal
+	return worth
+}
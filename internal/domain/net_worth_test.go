@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewNetWorth(t *testing.T) {
+	at := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	prices := NewPriceSeries([]PriceSnapshot{
+		{Symbol: "AAPL", Price: 150, CapturedAt: at.Add(-48 * time.Hour)},
+		{Symbol: "AAPL", Price: 170, CapturedAt: at.Add(-time.Hour)},
+		{Symbol: "AAPL", Price: 999, CapturedAt: at.Add(time.Hour)},
+		{Symbol: "BTC", Price: 60000, CapturedAt: at},
+	})
+	accounts := []AccountBalance{
+		{AccountID: 1, Name: "Checking", Type: AccountTypeChecking, Currency: "USD", Balance: NewMoney(2500)},
+		{AccountID: 2, Name: "Visa", Type: AccountTypeCreditCard, Currency: "USD", Balance: NewMoney(-400)},
+		{AccountID: 3, Name: "Girokonto", Type: AccountTypeChecking, Currency: "EUR", Balance: NewMoney(1000)},
+	}
+	holdings := []Holding{{Symbol: "AAPL", Quantity: 10}, {Symbol: "VTI", Quantity: 5}}
+	wallets := []Wallet{{ID: 7, Chain: WalletChainBTC, Label: "Cold storage", Balance: 0.5}}
+
+	worth := NewNetWorth(accounts, holdings, wallets, prices, at)
+	assert.Equal(t, "USD", worth.Currency)
+	assert.Len(t, worth.Accounts, 3)
+	assert.Equal(t, NewMoney(2100), worth.AccountsTotal)
+	assert.Equal(t, []AssetValue{
+		{Source: AssetSourceHolding, Symbol: "AAPL", Quantity: 10, Price: 170, Value: NewMoney(1700)},
+		{Source: AssetSourceWallet, Symbol: "BTC", WalletID: 7, Label: "Cold storage", Quantity: 0.5, Price: 60000, Value: NewMoney(30000)},
+	}, worth.Assets)
+	assert.Equal(t, NewMoney(31700), worth.AssetsTotal)
+	assert.Equal(t, NewMoney(33800), worth.Total)
+	assert.Equal(t, []string{"account Girokonto in EUR", "holding VTI without a price"}, worth.Excluded)
+}
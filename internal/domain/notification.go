@@ -0,0 +1,27 @@
+package domain
+
+import "time"
+
+// Notification types
+const (
+	NotificationTypePriceAlert = "price_alert"
+	NotificationTypeBankSync   = "bank_sync"
+	NotificationTypeBudget     = "budget"
+	NotificationTypeGoal       = "goal"
+	NotificationTypeBill       = "bill"
+	NotificationTypeComment    = "comment"
+)
+
+// Notification is a message for a user shown in the app until they read it
+type Notification struct {
+	ID     uint   `gorm:"primaryKey" json:"id"`
+	UserID uint   `gorm:"index:idx_notifications_user_created,priority:1" json:"user_id"`
+	Type   string `gorm:"type:varchar(30)" json:"type"`
+	Title  string `json:"title"`
+	// Message is the notification text; EntityID points at what it is about,
+	// such as the triggered price alert
+	Message   string     `gorm:"type:text" json:"message"`
+	EntityID  *uint      `json:"entity_id,omitempty"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+	CreatedAt time.Time  `gorm:"index:idx_notifications_user_created,priority:2" json:"created_at"`
+}
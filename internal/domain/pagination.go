@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded
+var ErrInvalidCursor = errors.New("invalid pagination cursor")
+
+// TransactionCursor is a position in the newest-first (date, id) ordering of
+// transactions. Keyset pagination continues from it instead of skipping rows
+// with an offset, so deep pages stay cheap and stable while rows are added.
+type TransactionCursor struct {
+	Date   time.Time
+	ID     uint
+	Before bool // Page towards newer transactions instead of older ones
+}
+
+// Encode returns the opaque, URL-safe form handed to clients
+func (c TransactionCursor) Encode() string {
+	direction := "n"
+	if c.Before {
+		direction = "p"
+	}
+	raw := direction + "|" + c.Date.Format(time.RFC3339Nano) + "|" + strconv.FormatUint(uint64(c.ID), 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeTransactionCursor parses a cursor produced by Encode
+func DecodeTransactionCursor(value string) (*TransactionCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	parts := strings.Split(string(raw), "|")
+	if len(parts) != 3 || (parts[0] != "n" && parts[0] != "p") {
+		return nil, ErrInvalidCursor
+	}
+	date, err := time.Parse(time.RFC3339Nano, parts[1])
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	id, err := strconv.ParseUint(parts[2], 10, 32)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &TransactionCursor{Date: date, ID: uint(id), Before: parts[0] == "p"}, nil
+}
+
+// TransactionPage is one page of a transaction list. Total counts every
+// matching transaction; the cursors are empty when there is no page in that
+// direction.
+type TransactionPage struct {
+	Transactions []Transaction `json:"transactions"`
+	Total        int64         `json:"total"`
+	Limit        int           `json:"limit"`
+	Offset       int           `json:"offset"`
+	NextCursor   string        `json:"next_cursor,omitempty"`
+	PrevCursor   string        `json:"prev_cursor,omitempty"`
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionCursor_RoundTrip(t *testing.T) {
+	date := time.Date(2024, 2, 10, 14, 30, 0, 123, time.FixedZone("CET", 3600))
+	for _, cursor := range []TransactionCursor{
+		{Date: date, ID: 42},
+		{Date: date, ID: 7, Before: true},
+	} {
+		decoded, err := DecodeTransactionCursor(cursor.Encode())
+		require.NoError(t, err)
+		assert.True(t, decoded.Date.Equal(cursor.Date))
+		assert.Equal(t, cursor.ID, decoded.ID)
+		assert.Equal(t, cursor.Before, decoded.Before)
+	}
+}
+
+func TestDecodeTransactionCursor_Invalid(t *testing.T) {
+	for _, value := range []string{"", "not base64!", "bnwxMjM", TransactionCursor{}.Encode() + "x"} {
+		_, err := DecodeTransactionCursor(value)
+		assert.ErrorIs(t, err, ErrInvalidCursor, value)
+	}
+}
@@ -0,0 +1,139 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// How place analytics group spending
+const (
+	PlaceGroupPlace = "place"
+	PlaceGroupCity  = "city"
+)
+
+// placeGridDecimals is how many decimals the coordinates of transactions
+// without a place name are rounded to when clustering them: about 110 m
+const placeGridDecimals = 3
+
+// PlaceAnalysis is where a user spent money over a period, by place or by
+// city. Expenses without a place, or a city when grouping by city, are only
+// counted in UnlocatedAmount.
+type PlaceAnalysis struct {
+	UserID          uint            `json:"user_id"`
+	StartDate       time.Time       `json:"start_date"`
+	EndDate         time.Time       `json:"end_date"`
+	GroupBy         string          `json:"group_by"`
+	TotalExpenses   Money           `json:"total_expenses"`
+	UnlocatedAmount Money           `json:"unlocated_amount"`
+	Places          []PlaceSpending `json:"places"`
+}
+
+// PlaceSpending is the spending at one place or in one city. Latitude and
+// Longitude are the middle of its located transactions, if it has any.
+type PlaceSpending struct {
+	Name              string    `json:"name"`
+	City              string    `json:"city,omitempty"`
+	Latitude          *float64  `json:"latitude,omitempty"`
+	Longitude         *float64  `json:"longitude,omitempty"`
+	TotalAmount       Money     `json:"total_amount"`
+	TransactionCount  int       `json:"transaction_count"`
+	AverageAmount     Money     `json:"average_amount"`
+	PercentageOfTotal float64   `json:"percentage_of_total"`
+	LastTransaction   time.Time `json:"last_transaction"`
+}
+
+// IsValidPlaceGroup reports whether spending can be grouped that way
+func IsValidPlaceGroup(groupBy string) bool {
+	return groupBy == PlaceGroupPlace || groupBy == PlaceGroupCity
+}
+
+// placeCluster collects the transactions of one place
+type placeCluster struct {
+	spending       PlaceSpending
+	latSum, lonSum float64
+	located        int
+}
+
+// ClusterSpending groups the expenses by place or by city, highest spend
+// first. Places are told apart by name and city, ignoring case; expenses
+// with coordinates but no place name are clustered by rounded coordinates
+// and named after them. It also returns the total of all expenses and the
+// part of it that could not be placed.
+func ClusterSpending(transactions []Transaction, groupBy string) (places []PlaceSpending, total, unlocated Money) {
+	clusters := make(map[string]*placeCluster)
+	var order []string
+	for i := range transactions {
+		tx := &transactions[i]
+		if tx.Type != TransactionTypeExpense {
+			continue
+		}
+		total += tx.Amount
+
+		key, name, city := placeKey(tx, groupBy)
+		if key == "" {
+			unlocated += tx.Amount
+			continue
+		}
+		cluster, exists := clusters[key]
+		if !exists {
+			cluster = &placeCluster{spending: PlaceSpending{Name: name, City: city}}
+			clusters[key] = cluster
+			order = append(order, key)
+		}
+		cluster.spending.TotalAmount += tx.Amount
+		cluster.spending.TransactionCount++
+		if tx.Date.After(cluster.spending.LastTransaction) {
+			cluster.spending.LastTransaction = tx.Date
+		}
+		if tx.Latitude != nil && tx.Longitude != nil {
+			cluster.latSum += *tx.Latitude
+			cluster.lonSum += *tx.Longitude
+			cluster.located++
+		}
+	}
+
+	places = make([]PlaceSpending, 0, len(order))
+	for _, key := range order {
+		cluster := clusters[key]
+		spending := cluster.spending
+		spending.AverageAmount = spending.TotalAmount.Mul(1 / float64(spending.TransactionCount))
+		spending.PercentageOfTotal = spending.TotalAmount.PercentOf(total)
+		if cluster.located > 0 {
+			latitude, longitude := cluster.latSum/float64(cluster.located), cluster.lonSum/float64(cluster.located)
+			spending.Latitude, spending.Longitude = &latitude, &longitude
+		}
+		places = append(places, spending)
+	}
+	sort.SliceStable(places, func(i, j int) bool {
+		if places[i].TotalAmount != places[j].TotalAmount {
+			return places[i].TotalAmount > places[j].TotalAmount
+		}
+		return places[i].Name < places[j].Name
+	})
+	return places, total, unlocated
+}
+
+// placeKey returns the cluster a transaction belongs to with the name and
+// city it is shown with, or an empty key if it cannot be placed
+func placeKey(tx *Transaction, groupBy string) (key, name, city string) {
+	place, city := strings.TrimSpace(tx.Place), strings.TrimSpace(tx.City)
+	if groupBy == PlaceGroupCity {
+		if city == "" {
+			return "", "", ""
+		}
+		return strings.ToLower(city), city, ""
+	}
+	if place != "" {
+		return "place:" + strings.ToLower(place) + "|" + strings.ToLower(city), place, city
+	}
+	if tx.Latitude == nil || tx.Longitude == nil {
+		return "", "", ""
+	}
+	scale := math.Pow(10, placeGridDecimals)
+	name = fmt.Sprintf("%.*f, %.*f",
+		placeGridDecimals, math.Round(*tx.Latitude*scale)/scale, placeGridDecimals, math.Round(*tx.Longitude*scale)/scale)
+	return "grid:" + name, name, city
+}
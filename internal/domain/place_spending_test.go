@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterSpending(t *testing.T) {
+	at := func(lat, lon float64) (*float64, *float64) { return &lat, &lon }
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	cafeLat, cafeLon := at(38.71, -9.14)
+	stallLat, stallLon := at(38.72251, -9.13901)
+	nearbyLat, nearbyLon := at(38.72269, -9.13949)
+	transactions := []Transaction{
+		{Type: TransactionTypeExpense, Amount: NewMoney(4), Date: day, Place: "Café Lisboa", City: "Lisbon", Latitude: cafeLat, Longitude: cafeLon},
+		{Type: TransactionTypeExpense, Amount: NewMoney(6), Date: day.AddDate(0, 0, 1), Place: "café lisboa ", City: "lisbon"},
+		{Type: TransactionTypeExpense, Amount: NewMoney(3), Date: day, City: "Lisbon", Latitude: stallLat, Longitude: stallLon},
+		{Type: TransactionTypeExpense, Amount: NewMoney(2), Date: day, Latitude: nearbyLat, Longitude: nearbyLon},
+		{Type: TransactionTypeExpense, Amount: NewMoney(20), Date: day, Place: "Market", City: "Porto"},
+		{Type: TransactionTypeExpense, Amount: NewMoney(15), Date: day},
+		{Type: TransactionTypeIncome, Amount: NewMoney(1000), Date: day, Place: "Office", City: "Lisbon"},
+	}
+
+	places, total, unlocated := ClusterSpending(transactions, PlaceGroupPlace)
+	assert.Equal(t, NewMoney(50), total)
+	assert.Equal(t, NewMoney(15), unlocated)
+	require.Len(t, places, 3)
+	assert.Equal(t, "Market", places[0].Name)
+	assert.Equal(t, 40.0, places[0].PercentageOfTotal)
+	assert.Nil(t, places[0].Latitude)
+
+	assert.Equal(t, "Café Lisboa", places[1].Name, "places are told apart ignoring case")
+	assert.Equal(t, 2, places[1].TransactionCount)
+	assert.Equal(t, NewMoney(5), places[1].AverageAmount)
+	assert.Equal(t, day.AddDate(0, 0, 1), places[1].LastTransaction)
+	assert.Equal(t, 38.71, *places[1].Latitude)
+
+	assert.Equal(t, "38.723, -9.139", places[2].Name, "unnamed spots within the same grid cell are clustered")
+	assert.Equal(t, NewMoney(5), places[2].TotalAmount)
+	assert.InDelta(t, 38.7226, *places[2].Latitude, 1e-9)
+
+	cities, _, unlocated := ClusterSpending(transactions, PlaceGroupCity)
+	assert.Equal(t, NewMoney(17), unlocated)
+	require.Len(t, cities, 2)
+	assert.Equal(t, PlaceSpending{Name: "Porto", TotalAmount: NewMoney(20), TransactionCount: 1, AverageAmount: NewMoney(20),
+		PercentageOfTotal: 40, LastTransaction: day}, cities[0])
+	assert.Equal(t, "Lisbon", cities[1].Name)
+	assert.Equal(t, NewMoney(13), cities[1].TotalAmount)
+}
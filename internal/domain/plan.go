@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+// Plans a user can be on. Every user starts on the free plan.
+const (
+	PlanFree    = "free"
+	PlanPremium = "premium"
+)
+
+// Plans lists the plans a user can be on
+var Plans = []string{PlanFree, PlanPremium}
+
+// Quotas counted per user and day
+const (
+	QuotaAIRequests    = "ai_requests"    // Requests to the AI, advice and assistant endpoints
+	QuotaBankRefreshes = "bank_refreshes" // Manual refreshes of linked bank accounts
+)
+
+// Quotas lists the counted quotas in the order usage reports them
+var Quotas = []string{QuotaAIRequests, QuotaBankRefreshes}
+
+// Features plans can include
+const (
+	FeatureBankSync         = "bank_sync"         // Linking bank accounts and syncing them
+	FeatureAIAdvisor        = "ai_advisor"        // The AI, advice and assistant endpoints
+	FeatureScheduledReports = "scheduled_reports" // Reports generated and sent on a schedule
+)
+
+// Features lists the features plans can include
+var Features = []string{FeatureBankSync, FeatureAIAdvisor, FeatureScheduledReports}
+
+var (
+	// ErrQuotaExceeded is returned when the user has used up a daily quota of their plan
+	ErrQuotaExceeded = errors.New("daily quota of your plan is used up")
+	// ErrExportTooLarge is returned when an export holds more transactions than the user's plan allows
+	ErrExportTooLarge = errors.New("export holds more transactions than your plan allows")
+	// ErrFeatureNotInPlan is returned when the user's plan does not include a feature
+	ErrFeatureNotInPlan = errors.New("your plan does not include this feature")
+)
+
+// PlanLimits are the limits of a plan. Zero counts mean unlimited.
+// BankSyncInterval is how long scheduled bank syncs leave an account alone
+// after it was synced. Features lists the features the plan includes.
+type PlanLimits struct {
+	AIRequestsPerDay    int           `json:"ai_requests_per_day"`
+	BankRefreshesPerDay int           `json:"bank_refreshes_per_day"`
+	ExportMaxRows       int           `json:"export_max_rows"`
+	BankSyncInterval    time.Duration `json:"-"`
+	Features            []string      `json:"features"`
+}
+
+// HasFeature reports whether the plan includes the feature
+func (l PlanLimits) HasFeature(feature string) bool {
+	return slices.Contains(l.Features, feature)
+}
+
+// DailyLimit returns the plan's daily limit of the quota, zero meaning unlimited
+func (l PlanLimits) DailyLimit(quota string) int {
+	switch quota {
+	case QuotaAIRequests:
+		return l.AIRequestsPerDay
+	case QuotaBankRefreshes:
+		return l.BankRefreshesPerDay
+	}
+	return 0
+}
+
+// ValidatePlan checks that the plan is one of Plans
+func ValidatePlan(plan string) error {
+	var v validator
+	v.check(slices.Contains(Plans, plan), "plan", "must be free or premium")
+	return v.err()
+}
+
+// UsageCounter counts how often the user drew on a quota on one UTC day
+type UsageCounter struct {
+	UserID uint      `gorm:"primaryKey" json:"user_id"`
+	Quota  string    `gorm:"primaryKey;type:varchar(30)" json:"quota"`
+	Day    time.Time `gorm:"primaryKey" json:"day"`
+	Count  int       `gorm:"not null;default:0" json:"count"`
+}
+
+// QuotaUsage is how much of a daily quota the user has used today. Limit and
+// Remaining are omitted for unlimited quotas.
+type QuotaUsage struct {
+	Quota     string    `json:"quota"`
+	Used      int       `json:"used"`
+	Limit     int       `json:"limit,omitempty"`
+	Remaining *int      `json:"remaining,omitempty"`
+	ResetsAt  time.Time `json:"resets_at"`
+}
+
+// Usage is the user's plan with its limits and what is left of its quotas today
+type Usage struct {
+	Plan             string       `json:"plan"`
+	Limits           PlanLimits   `json:"limits"`
+	BankSyncInterval string       `json:"bank_sync_interval"`
+	Quotas           []QuotaUsage `json:"quotas"`
+}
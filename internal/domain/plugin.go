@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"fmt"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Plugin limits: how long a plugin may take to respond, in seconds, and how
+// many actions one response may ask for
+const (
+	DefaultPluginTimeoutSeconds = 5
+	MaxPluginTimeoutSeconds     = 10
+	MaxPluginActions            = 20
+)
+
+// Plugin actions a plugin's response may ask for. They only apply to the
+// transactions of the event the plugin was called with.
+const (
+	PluginActionSetCategory = "set_category"
+	PluginActionSetNotes    = "set_notes"
+	PluginActionAddTags     = "add_tags"
+)
+
+// PluginActions lists the actions plugins may take
+var PluginActions = []string{PluginActionSetCategory, PluginActionSetNotes, PluginActionAddTags}
+
+// Outcomes of plugin runs
+const (
+	PluginRunSucceeded = "succeeded"
+	PluginRunFailed    = "failed"
+	PluginRunTimedOut  = "timed_out"
+)
+
+// Plugin is custom logic a user runs on the events of their data, such as
+// their own categorization rules or a sync to another service. It is an
+// endpoint called like a webhook, signed with Secret, which answers with a
+// PluginResponse within TimeoutSeconds. Events lists the event types it is
+// called for, every type when empty; disabled plugins are not called.
+type Plugin struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	UserID         uint      `gorm:"not null;index" json:"user_id"`
+	Name           string    `gorm:"type:varchar(100);not null" json:"name"`
+	URL            string    `gorm:"type:varchar(2048);not null" json:"url"`
+	Events         []string  `gorm:"serializer:json;type:text" json:"events"`
+	Secret         string    `gorm:"type:varchar(64);not null" json:"secret,omitempty"`
+	Enabled        bool      `gorm:"not null;default:true" json:"enabled"`
+	TimeoutSeconds int       `gorm:"not null" json:"timeout_seconds"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// Validate fills in the default timeout and checks the name, URL, events
+// and timeout
+func (p *Plugin) Validate() error {
+	if p.TimeoutSeconds == 0 {
+		p.TimeoutSeconds = DefaultPluginTimeoutSeconds
+	}
+
+	var v validator
+	p.Name = strings.TrimSpace(p.Name)
+	v.check(p.Name != "" && len(p.Name) <= 100, "name", "must be 1 to 100 characters")
+	target, err := url.Parse(p.URL)
+	v.check(err == nil && (target.Scheme == "https" || target.Scheme == "http") && target.Host != "",
+		"url", "must be an http or https URL")
+	v.check(err != nil || isPublicHost(target.Hostname()), "url", "must not point to a local or private network")
+	v.check(len(p.URL) <= 2048, "url", "must be at most 2048 characters")
+	for i, event := range p.Events {
+		field := fmt.Sprintf("events[%d]", i)
+		v.check(IsValidEventType(event), field, "must be one of "+strings.Join(EventTypes, ", "))
+		v.check(!slices.Contains(p.Events[:i], event), field, "must not repeat an event")
+	}
+	v.check(p.TimeoutSeconds >= 1 && p.TimeoutSeconds <= MaxPluginTimeoutSeconds, "timeout_seconds", "must be between 1 and 10")
+	return v.err()
+}
+
+// isPublicHost reports whether a plugin or webhook URL's host may be public.
+// Host names other than localhost are only known once resolved, so the
+// clients calling them check the addresses they resolve to as well.
+func isPublicHost(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return IsPublicAddress(addr)
+	}
+	return true
+}
+
+// IsPublicAddress reports whether an address is on the public internet
+// rather than loopback, private, link-local (such as cloud metadata
+// services at 169.254.169.254) or otherwise internal
+func IsPublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() && !addr.IsLoopback() && !addr.IsPrivate() && !addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() && !addr.IsInterfaceLocalMulticast() && !addr.IsMulticast() && !addr.IsUnspecified()
+}
+
+// Wants reports whether the plugin is called for events of the type
+func (p *Plugin) Wants(eventType string) bool {
+	return p.Enabled && (len(p.Events) == 0 || slices.Contains(p.Events, eventType))
+}
+
+// PluginAction is a change a plugin asks for: a new category, notes or
+// extra tags for one of the event's transactions
+type PluginAction struct {
+	Type          string   `json:"type"`
+	TransactionID uint     `json:"transaction_id"`
+	CategoryID    uint     `json:"category_id,omitempty"`
+	Notes         string   `json:"notes,omitempty"`
+	Tags          []string `json:"tags,omitempty"`
+}
+
+// PluginResponse is what plugins answer calls with. A plugin that only
+// reacts elsewhere, such as a sync, answers without actions.
+type PluginResponse struct {
+	Actions []PluginAction `json:"actions"`
+}
+
+// Validate checks that the actions are known, complete and only touch the
+// given transactions, the ones of the event the plugin was called with
+func (r *PluginResponse) Validate(transactionIDs []uint) error {
+	var v validator
+	v.check(len(r.Actions) <= MaxPluginActions, "actions", "must list at most 20 actions")
+	for i, action := range r.Actions {
+		field := fmt.Sprintf("actions[%d]", i)
+		v.check(slices.Contains(PluginActions, action.Type), field+".type", "must be one of "+strings.Join(PluginActions, ", "))
+		v.check(slices.Contains(transactionIDs, action.TransactionID), field+".transaction_id",
+			"must be a transaction of the event")
+		switch action.Type {
+		case PluginActionSetCategory:
+			v.check(action.CategoryID != 0, field+".category_id", "is required")
+		case PluginActionSetNotes:
+			v.check(len(action.Notes) <= 1000, field+".notes", "must be at most 1000 characters")
+		case PluginActionAddTags:
+			v.check(len(action.Tags) > 0, field+".tags", "is required")
+		}
+	}
+	return v.err()
+}
+
+// PluginRun records one call of a plugin: the event it was called for, how
+// it went and the actions it took, so users can audit what their plugins did
+type PluginRun struct {
+	ID         uint           `gorm:"primaryKey" json:"id"`
+	UserID     uint           `gorm:"not null;index" json:"user_id"`
+	PluginID   uint           `gorm:"not null;index" json:"plugin_id"`
+	Event      string         `gorm:"type:varchar(40);not null" json:"event"`
+	Status     string         `gorm:"type:varchar(20);not null" json:"status"`
+	DurationMS int64          `gorm:"not null" json:"duration_ms"`
+	Actions    []PluginAction `gorm:"serializer:json;type:text" json:"actions"`
+	Error      string         `gorm:"type:varchar(500)" json:"error,omitempty"`
+	CreatedAt  time.Time      `gorm:"index" json:"created_at"`
+}
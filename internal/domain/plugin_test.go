@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlugin_ValidateURL(t *testing.T) {
+	tests := []struct {
+		url   string
+		valid bool
+	}{
+		{"https://plugins.example.com/categorize", true},
+		{"http://203.0.113.7:8080/hook", true},
+		{"ftp://plugins.example.com", false},
+		{"http://localhost:8080/hook", false},
+		{"http://api.localhost/hook", false},
+		{"http://127.0.0.1:8080/hook", false},
+		{"http://[::1]/hook", false},
+		{"http://10.0.0.5/hook", false},
+		{"http://192.168.1.20/hook", false},
+		{"http://169.254.169.254/latest/meta-data/", false},
+		{"http://[fe80::1]/hook", false},
+		{"http://[::ffff:127.0.0.1]/hook", false},
+		{"http://0.0.0.0/hook", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			plugin := Plugin{Name: "Categorizer", URL: tt.url}
+			if tt.valid {
+				assert.NoError(t, plugin.Validate())
+			} else {
+				assert.ErrorIs(t, plugin.Validate(), ErrValidation)
+			}
+		})
+	}
+}
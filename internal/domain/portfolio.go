@@ -0,0 +1,219 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+)
+
+// Portfolio risk windows, in days, and the annual risk-free rate Sharpe
+// ratios are measured against unless another is given
+const (
+	DefaultPortfolioRiskDays = 90
+	MaxPortfolioRiskDays     = 730
+	DefaultRiskFreeRate      = 0.02
+)
+
+// Daily returns are sampled every calendar day, crypto trades on weekends too
+const riskPeriodsPerYear = 365
+
+// ErrEmptyPortfolio is returned when measuring the risk of a portfolio without holdings
+var ErrEmptyPortfolio = errors.New("portfolio has no holdings")
+
+// ErrInsufficientPriceHistory is returned when too few prices were recorded
+// for the holdings to measure their risk
+var ErrInsufficientPriceHistory = errors.New("not enough recorded prices to measure portfolio risk")
+
+// Holding is a quantity of a market symbol a user owns
+type Holding struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"uniqueIndex:idx_holdings_user_symbol,priority:1" json:"user_id"`
+	Symbol    string    `gorm:"type:varchar(20);uniqueIndex:idx_holdings_user_symbol,priority:2" json:"symbol"`
+	AssetType string    `gorm:"type:varchar(20)" json:"asset_type"` // "stock" or "crypto"
+	Quantity  float64   `json:"quantity"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// HoldingRisk is a holding valued at its latest price with the annualized
+// volatility of its own daily returns
+type HoldingRisk struct {
+	Symbol     string  `json:"symbol"`
+	Quantity   float64 `json:"quantity"`
+	Price      float64 `json:"price"`
+	Value      float64 `json:"value"`
+	Weight     float64 `json:"weight"`
+	Volatility float64 `json:"volatility"`
+}
+
+// PortfolioRisk measures a portfolio over the daily prices recorded between
+// From and To. Volatility and ExpectedReturn are annualized from the daily
+// returns; MaxDrawdown is the largest fall from a peak value, as a share of
+// that peak. Correlation lists the correlations of the holdings' daily
+// returns in the order of Symbols. Holdings without recorded prices are left
+// out and listed in Unpriced.
+type PortfolioRisk struct {
+	From           time.Time     `json:"from"`
+	To             time.Time     `json:"to"`
+	Observations   int           `json:"observations"`
+	Value          float64       `json:"value"`
+	Volatility     float64       `json:"volatility"`
+	ExpectedReturn float64       `json:"expected_return"`
+	RiskFreeRate   float64       `json:"risk_free_rate"`
+	SharpeRatio    float64       `json:"sharpe_ratio"`
+	MaxDrawdown    float64       `json:"max_drawdown"`
+	DrawdownPeak   *time.Time    `json:"drawdown_peak,omitempty"`
+	DrawdownTrough *time.Time    `json:"drawdown_trough,omitempty"`
+	Holdings       []HoldingRisk `json:"holdings"`
+	Symbols        []string      `json:"symbols"`
+	Correlation    [][]float64   `json:"correlation"`
+	Unpriced       []string      `json:"unpriced,omitempty"`
+}
+
+// AnalyzePortfolioRisk samples the holdings' prices once a day over the days
+// before to, starting once every priced holding has a price, and measures the
+// risk of the portfolio's daily value. At least two daily returns are needed.
+func AnalyzePortfolioRisk(holdings []Holding, prices PriceSeries, days int, to time.Time, riskFreeRate float64) (PortfolioRisk, error) {
+	if len(holdings) == 0 {
+		return PortfolioRisk{}, ErrEmptyPortfolio
+	}
+	risk := PortfolioRisk{To: to, RiskFreeRate: riskFreeRate, Holdings: []HoldingRisk{}, Symbols: []string{}}
+
+	var priced []Holding
+	for _, holding := range holdings {
+		if _, ok := prices.AsOf(holding.Symbol, to); ok {
+			priced = append(priced, holding)
+		} else {
+			risk.Unpriced = append(risk.Unpriced, holding.Symbol)
+		}
+	}
+	sort.SliceStable(priced, func(i, j int) bool { return priced[i].Symbol < priced[j].Symbol })
+
+	// samples[k][i] is the price of holding i on day k, oldest first
+	var times []time.Time
+	var samples [][]float64
+	for k := days; k >= 0; k-- {
+		at := to.AddDate(0, 0, -k)
+		row := make([]float64, len(priced))
+		complete := len(priced) > 0
+		for i, holding := range priced {
+			snapshot, ok := prices.AsOf(holding.Symbol, at)
+			if !ok || snapshot.Price <= 0 {
+				complete = false
+				break
+			}
+			row[i] = snapshot.Price
+		}
+		if complete {
+			times = append(times, at)
+			samples = append(samples, row)
+		}
+	}
+	if len(samples) < 3 {
+		return risk, ErrInsufficientPriceHistory
+	}
+	risk.From = times[0]
+	risk.Observations = len(samples) - 1
+
+	values := make([]float64, len(samples))
+	for k, row := range samples {
+		for i, holding := range priced {
+			values[k] += holding.Quantity * row[i]
+		}
+	}
+	returns := dailyReturns(values)
+	mean, deviation := meanAndDeviation(returns)
+	risk.Value = values[len(values)-1]
+	risk.ExpectedReturn = mean * riskPeriodsPerYear
+	risk.Volatility = deviation * math.Sqrt(riskPeriodsPerYear)
+	if risk.Volatility > 0 {
+		risk.SharpeRatio = (risk.ExpectedReturn - riskFreeRate) / risk.Volatility
+	}
+
+	peak := 0
+	for k := range values {
+		if values[k] > values[peak] {
+			peak = k
+		}
+		if drawdown := (values[peak] - values[k]) / values[peak]; drawdown > risk.MaxDrawdown {
+			risk.MaxDrawdown = drawdown
+			risk.DrawdownPeak, risk.DrawdownTrough = &times[peak], &times[k]
+		}
+	}
+
+	holdingReturns := make([][]float64, len(priced))
+	latest := samples[len(samples)-1]
+	for i, holding := range priced {
+		series := make([]float64, len(samples))
+		for k := range samples {
+			series[k] = samples[k][i]
+		}
+		holdingReturns[i] = dailyReturns(series)
+		_, holdingDeviation := meanAndDeviation(holdingReturns[i])
+		value := holding.Quantity * latest[i]
+		risk.Symbols = append(risk.Symbols, holding.Symbol)
+		risk.Holdings = append(risk.Holdings, HoldingRisk{
+			Symbol:     holding.Symbol,
+			Quantity:   holding.Quantity,
+			Price:      latest[i],
+			Value:      value,
+			Weight:     value / risk.Value,
+			Volatility: holdingDeviation * math.Sqrt(riskPeriodsPerYear),
+		})
+	}
+
+	risk.Correlation = make([][]float64, len(priced))
+	for i := range priced {
+		risk.Correlation[i] = make([]float64, len(priced))
+		for j := range priced {
+			if i == j {
+				risk.Correlation[i][j] = 1
+			} else {
+				risk.Correlation[i][j] = correlation(holdingReturns[i], holdingReturns[j])
+			}
+		}
+	}
+	return risk, nil
+}
+
+// dailyReturns turns a series of prices or values into the returns between them
+func dailyReturns(values []float64) []float64 {
+	returns := make([]float64, len(values)-1)
+	for k := 1; k < len(values); k++ {
+		returns[k-1] = values[k]/values[k-1] - 1
+	}
+	return returns
+}
+
+// meanAndDeviation returns the mean and sample standard deviation
+func meanAndDeviation(values []float64) (mean, deviation float64) {
+	for _, value := range values {
+		mean += value
+	}
+	mean /= float64(len(values))
+	if len(values) < 2 {
+		return mean, 0
+	}
+	var squares float64
+	for _, value := range values {
+		squares += (value - mean) * (value - mean)
+	}
+	return mean, math.Sqrt(squares / float64(len(values)-1))
+}
+
+// correlation is the Pearson correlation of two equally long series, zero
+// when either never moves
+func correlation(a, b []float64) float64 {
+	meanA, deviationA := meanAndDeviation(a)
+	meanB, deviationB := meanAndDeviation(b)
+	if deviationA == 0 || deviationB == 0 {
+		return 0
+	}
+	var covariance float64
+	for k := range a {
+		covariance += (a[k] - meanA) * (b[k] - meanB)
+	}
+	covariance /= float64(len(a) - 1)
+	return math.Max(-1, math.Min(1, covariance/(deviationA*deviationB)))
+}
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzePortfolioRisk(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	var snapshots []PriceSnapshot
+	// SPY and BTC move against each other every day
+	for day, prices := range [][2]float64{{100, 100}, {110, 90}, {99, 99}, {108.9, 89.1}, {98.01, 98.01}} {
+		at := start.AddDate(0, 0, day)
+		snapshots = append(snapshots, PriceSnapshot{Symbol: "SPY", Price: prices[0], CapturedAt: at},
+			PriceSnapshot{Symbol: "BTC", Price: prices[1], CapturedAt: at})
+	}
+	prices := NewPriceSeries(snapshots)
+	holdings := []Holding{{Symbol: "SPY", Quantity: 1}, {Symbol: "BTC", Quantity: 1}, {Symbol: "ETH", Quantity: 2}}
+	to := start.AddDate(0, 0, 4)
+
+	risk, err := AnalyzePortfolioRisk(holdings, prices, 30, to, 0.02)
+	require.NoError(t, err)
+
+	assert.Equal(t, start, risk.From, "sampling starts once every holding has a price")
+	assert.Equal(t, 4, risk.Observations)
+	assert.InDelta(t, 196.02, risk.Value, 1e-9)
+	// Daily returns of 0, -1%, 0 and -1%
+	deviation := math.Sqrt(4 * 0.005 * 0.005 / 3)
+	assert.InDelta(t, -0.005*365, risk.ExpectedReturn, 1e-9)
+	assert.InDelta(t, deviation*math.Sqrt(365), risk.Volatility, 1e-9)
+	assert.InDelta(t, (risk.ExpectedReturn-0.02)/risk.Volatility, risk.SharpeRatio, 1e-9)
+	assert.InDelta(t, 0.0199, risk.MaxDrawdown, 1e-9)
+	assert.Equal(t, start, *risk.DrawdownPeak)
+	assert.Equal(t, to, *risk.DrawdownTrough)
+
+	assert.Equal(t, []string{"BTC", "SPY"}, risk.Symbols)
+	assert.Equal(t, []string{"ETH"}, risk.Unpriced)
+	require.Len(t, risk.Holdings, 2)
+	assert.InDelta(t, 0.5, risk.Holdings[0].Weight, 1e-9)
+	assert.Greater(t, risk.Holdings[0].Volatility, risk.Volatility, "holdings that offset each other lower the risk")
+	require.Len(t, risk.Correlation, 2)
+	assert.Equal(t, 1.0, risk.Correlation[0][0])
+	assert.InDelta(t, -1, risk.Correlation[0][1], 1e-6)
+	assert.Equal(t, risk.Correlation[0][1], risk.Correlation[1][0])
+
+	t.Run("too little history", func(t *testing.T) {
+		_, err := AnalyzePortfolioRisk(holdings, prices, 1, to, 0.02)
+		assert.ErrorIs(t, err, ErrInsufficientPriceHistory)
+		_, err = AnalyzePortfolioRisk([]Holding{{Symbol: "ETH", Quantity: 1}}, prices, 30, to, 0.02)
+		assert.ErrorIs(t, err, ErrInsufficientPriceHistory)
+	})
+
+	t.Run("no holdings", func(t *testing.T) {
+		_, err := AnalyzePortfolioRisk(nil, prices, 30, to, 0.02)
+		assert.ErrorIs(t, err, ErrEmptyPortfolio)
+	})
+}
@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// Days a week can start on
+const (
+	WeekStartMonday   = "monday"
+	WeekStartSunday   = "sunday"
+	WeekStartSaturday = "saturday"
+)
+
+// Dashboard periods
+const (
+	DashboardPeriodWeek    = "week"
+	DashboardPeriodMonth   = "month"
+	DashboardPeriodQuarter = "quarter"
+	DashboardPeriodYear    = "year"
+)
+
+// DashboardPeriods lists the periods a dashboard can cover
+var DashboardPeriods = []string{DashboardPeriodWeek, DashboardPeriodMonth, DashboardPeriodQuarter, DashboardPeriodYear}
+
+// weekStarts maps the days a week can start on to their weekday
+var weekStarts = map[string]time.Weekday{
+	WeekStartMonday:   time.Monday,
+	WeekStartSunday:   time.Sunday,
+	WeekStartSaturday: time.Saturday,
+}
+
+// numberFormats maps the number formats users can pick to how they write
+// amounts
+var numberFormats = map[string]NumberStyle{
+	"1,234.56": DefaultNumberStyle,
+	"1.234,56": {Decimal: ",", Group: ".", SymbolAfter: true},
+	"1 234,56": {Decimal: ",", Group: " ", SymbolAfter: true},
+	"1'234.56": {Decimal: ".", Group: "'", SymbolAfter: true},
+}
+
+// Preferences are how a user wants amounts, dates and periods shown: the
+// currency they think in, the first day of their week, how dates and numbers
+// are written and the period the dashboard opens on. Empty date and number
+// formats follow the user's locale. Locale itself is kept on the user.
+type Preferences struct {
+	UserID          uint      `gorm:"primaryKey;autoIncrement:false" json:"user_id"`
+	Currency        string    `gorm:"type:varchar(3);not null;default:USD" json:"currency"`
+	Locale          string    `gorm:"-" json:"locale"`
+	WeekStart       string    `gorm:"type:varchar(10);not null;default:monday" json:"week_start"`
+	DateFormat      string    `gorm:"type:varchar(20)" json:"date_format,omitempty"`   // e.g. "DD/MM/YYYY"
+	NumberFormat    string    `gorm:"type:varchar(20)" json:"number_format,omitempty"` // e.g. "1.234,56"
+	DashboardPeriod string    `gorm:"type:varchar(10);not null;default:month" json:"dashboard_period"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// TableName keeps preferences apart from other per-user tables
+func (Preferences) TableName() string { return "user_preferences" }
+
+// DefaultPreferences returns the preferences of a user who has not set any
+func DefaultPreferences(userID uint) Preferences {
+	return Preferences{
+		UserID:          userID,
+		Currency:        BaseCurrency,
+		Locale:          DefaultLocale,
+		WeekStart:       WeekStartMonday,
+		DashboardPeriod: DashboardPeriodMonth,
+	}
+}
+
+// Normalize upper-cases the currency and fills in the defaults of settings
+// left empty
+func (p *Preferences) Normalize() {
+	p.Currency = strings.ToUpper(strings.TrimSpace(p.Currency))
+	if p.Currency == "" {
+		p.Currency = BaseCurrency
+	}
+	if p.Locale == "" {
+		p.Locale = DefaultLocale
+	}
+	if p.WeekStart == "" {
+		p.WeekStart = WeekStartMonday
+	}
+	if p.DashboardPeriod == "" {
+		p.DashboardPeriod = DashboardPeriodMonth
+	}
+}
+
+// Validate checks every setting is one the application supports
+func (p *Preferences) Validate() error {
+	var v validator
+	v.check(IsCurrencyCode(p.Currency), "currency", "must be a three letter ISO 4217 code")
+	v.check(slices.Contains(SupportedLocales, p.Locale), "locale", "must be en, tr or de")
+	_, knownWeekStart := weekStarts[p.WeekStart]
+	v.check(knownWeekStart, "week_start", "must be monday, sunday or saturday")
+	_, knownDateFormat := exportDateFormats[p.DateFormat]
+	v.check(p.DateFormat == "" || knownDateFormat, "date_format", "must be YYYY-MM-DD, DD/MM/YYYY, MM/DD/YYYY, DD.MM.YYYY or YYYYMMDD")
+	_, knownNumberFormat := numberFormats[p.NumberFormat]
+	v.check(p.NumberFormat == "" || knownNumberFormat, "number_format", "must be 1,234.56, 1.234,56, 1 234,56 or 1'234.56")
+	v.check(slices.Contains(DashboardPeriods, p.DashboardPeriod), "dashboard_period", "must be week, month, quarter or year")
+	return v.err()
+}
+
+// FirstWeekday returns the day the user's weeks start on, Monday unless set
+func (p *Preferences) FirstWeekday() time.Weekday {
+	if weekday, ok := weekStarts[p.WeekStart]; ok {
+		return weekday
+	}
+	return time.Monday
+}
+
+// DateLayout returns the Go layout of the date format, or "" when dates are
+// written the way the locale writes them
+func (p *Preferences) DateLayout() string {
+	return exportDateFormats[p.DateFormat]
+}
+
+// NumberStyle returns how the number format writes amounts, and false when
+// numbers are written the way the locale writes them
+func (p *Preferences) NumberStyle() (NumberStyle, bool) {
+	style, ok := numberFormats[p.NumberFormat]
+	return style, ok
+}
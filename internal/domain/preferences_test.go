@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreferences_Validate(t *testing.T) {
+	preferences := Preferences{Currency: " eur "}
+	preferences.Normalize()
+	assert.Equal(t, "EUR", preferences.Currency)
+	assert.Equal(t, WeekStartMonday, preferences.WeekStart)
+	assert.Equal(t, DashboardPeriodMonth, preferences.DashboardPeriod)
+	assert.NoError(t, preferences.Validate())
+
+	for _, invalid := range []Preferences{
+		{Currency: "EURO"},
+		{Locale: "fr"},
+		{WeekStart: "friday"},
+		{DateFormat: "YY/MM/DD"},
+		{NumberFormat: "1_234.56"},
+		{DashboardPeriod: "day"},
+	} {
+		invalid.Normalize()
+		assert.ErrorIs(t, invalid.Validate(), ErrValidation, "%+v", invalid)
+	}
+}
+
+func TestPreferences_Formats(t *testing.T) {
+	preferences := DefaultPreferences(1)
+	assert.Equal(t, time.Monday, preferences.FirstWeekday())
+	assert.Empty(t, preferences.DateLayout())
+	_, ok := preferences.NumberStyle()
+	assert.False(t, ok)
+
+	preferences = Preferences{WeekStart: WeekStartSunday, DateFormat: "MM/DD/YYYY", NumberFormat: "1.234,56"}
+	assert.Equal(t, time.Sunday, preferences.FirstWeekday())
+	assert.Equal(t, "01/02/2006", preferences.DateLayout())
+	style, ok := preferences.NumberStyle()
+	assert.True(t, ok)
+	assert.Equal(t, "1.234,56 €", NewMoney(1234.56).FormatStyle("EUR", style))
+}
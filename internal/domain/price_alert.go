@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Price alert comparators
+const (
+	PriceAlertAbove = "above"
+	PriceAlertBelow = "below"
+)
+
+// ErrInvalidComparator is returned for price alerts that are neither above nor below their threshold
+var ErrInvalidComparator = errors.New("comparator must be above or below")
+
+// ErrInvalidThreshold is returned for price alerts without a positive threshold
+var ErrInvalidThreshold = errors.New("threshold must be positive")
+
+// PriceAlert notifies its user when the price of a symbol rises above or
+// falls below a threshold. An alert fires once and is then deactivated,
+// unless it repeats: a repeating alert fires again each time the price
+// crosses the threshold after having moved back.
+type PriceAlert struct {
+	ID         uint    `gorm:"primaryKey" json:"id"`
+	UserID     uint    `gorm:"index" json:"user_id"`
+	Symbol     string  `gorm:"type:varchar(20);index" json:"symbol"`
+	Comparator string  `gorm:"type:varchar(10)" json:"comparator"`
+	Threshold  float64 `json:"threshold"`
+	Note       string  `json:"note,omitempty"`
+	Repeat     bool    `json:"repeat"`
+	Active     bool    `gorm:"default:true" json:"active"`
+	// Triggered is set while the price stays past the threshold after firing
+	Triggered       bool       `json:"triggered"`
+	TriggerCount    int        `json:"trigger_count"`
+	LastTriggeredAt *time.Time `json:"last_triggered_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// Validate checks the comparator and threshold
+func (a *PriceAlert) Validate() error {
+	if a.Comparator != PriceAlertAbove && a.Comparator != PriceAlertBelow {
+		return ErrInvalidComparator
+	}
+	if a.Threshold <= 0 {
+		return ErrInvalidThreshold
+	}
+	return nil
+}
+
+// Matches reports whether the price is past the alert's threshold
+func (a *PriceAlert) Matches(price float64) bool {
+	if a.Comparator == PriceAlertBelow {
+		return price <= a.Threshold
+	}
+	return price >= a.Threshold
+}
+
+// PriceAlertTrigger records a price alert firing, with the price that fired it
+type PriceAlertTrigger struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	AlertID     uint      `gorm:"index" json:"alert_id"`
+	UserID      uint      `gorm:"index" json:"user_id"`
+	Symbol      string    `gorm:"type:varchar(20)" json:"symbol"`
+	Comparator  string    `gorm:"type:varchar(10)" json:"comparator"`
+	Threshold   float64   `json:"threshold"`
+	Price       float64   `json:"price"`
+	TriggeredAt time.Time `json:"triggered_at"`
+}
@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriceAlert_Validate(t *testing.T) {
+	assert.NoError(t, (&PriceAlert{Comparator: PriceAlertAbove, Threshold: 100}).Validate())
+	assert.ErrorIs(t, (&PriceAlert{Comparator: "equals", Threshold: 100}).Validate(), ErrInvalidComparator)
+	assert.ErrorIs(t, (&PriceAlert{Comparator: PriceAlertBelow}).Validate(), ErrInvalidThreshold)
+}
+
+func TestPriceAlert_Matches(t *testing.T) {
+	above := &PriceAlert{Comparator: PriceAlertAbove, Threshold: 100}
+	assert.True(t, above.Matches(100))
+	assert.True(t, above.Matches(150))
+	assert.False(t, above.Matches(99.99))
+
+	below := &PriceAlert{Comparator: PriceAlertBelow, Threshold: 100}
+	assert.True(t, below.Matches(100))
+	assert.True(t, below.Matches(50))
+	assert.False(t, below.Matches(100.01))
+}
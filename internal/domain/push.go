@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Device platforms, which decide the push service a device is reached through
+const (
+	DevicePlatformAndroid = "android" // Firebase Cloud Messaging
+	DevicePlatformIOS     = "ios"     // Apple Push Notification service
+)
+
+// DevicePlatforms lists the platforms devices can be registered for
+var DevicePlatforms = []string{DevicePlatformAndroid, DevicePlatformIOS}
+
+// Notification channels
+const (
+	NotificationChannelInApp = "in_app"
+	NotificationChannelPush  = "push"
+)
+
+// NotificationChannels lists the channels notifications reach users through
+var NotificationChannels = []string{NotificationChannelInApp, NotificationChannelPush}
+
+// NotificationTypes lists the notification types users can turn off per channel
+var NotificationTypes = []string{
+	NotificationTypeBudget, NotificationTypePriceAlert, NotificationTypeBill,
+	NotificationTypeGoal, NotificationTypeBankSync, NotificationTypeComment,
+}
+
+// ErrInvalidPushToken is returned by push services for device tokens that
+// are no longer registered, such as those of uninstalled apps
+var ErrInvalidPushToken = errors.New("device token is no longer registered")
+
+// MaxDeviceTokenLength bounds the tokens FCM and APNs hand out
+const MaxDeviceTokenLength = 255
+
+// PushDevice is a user's installation of the mobile app that push notifications
+// are sent to. A token belongs to one installation, so registering it again
+// moves it to whoever registered it last.
+type PushDevice struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"index;not null" json:"user_id"`
+	Platform  string    `gorm:"type:varchar(10);not null" json:"platform"`
+	Token     string    `gorm:"type:varchar(255);uniqueIndex;not null" json:"token"`
+	Name      string    `gorm:"type:varchar(100)" json:"name,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks the platform and token
+func (d *PushDevice) Validate() error {
+	d.Token = strings.TrimSpace(d.Token)
+	var v validator
+	v.check(slices.Contains(DevicePlatforms, d.Platform), "platform", "must be android or ios")
+	v.check(d.Token != "", "token", "is required")
+	v.check(len(d.Token) <= MaxDeviceTokenLength, "token", "must be at most 255 characters")
+	v.check(len(d.Name) <= 100, "name", "must be at most 100 characters")
+	return v.err()
+}
+
+// NotificationPreference turns one type of notification on or off on one
+// channel. Every type is on for every channel until the user turns it off.
+type NotificationPreference struct {
+	UserID  uint   `gorm:"primaryKey;autoIncrement:false" json:"-"`
+	Channel string `gorm:"primaryKey;type:varchar(10)" json:"channel"`
+	Type    string `gorm:"primaryKey;type:varchar(30)" json:"type"`
+	Enabled bool   `gorm:"not null" json:"enabled"`
+}
+
+// Validate checks the channel and type are known
+func (p *NotificationPreference) Validate() error {
+	var v validator
+	v.check(slices.Contains(NotificationChannels, p.Channel), "channel", "must be in_app or push")
+	v.check(slices.Contains(NotificationTypes, p.Type), "type", "must be a notification type")
+	return v.err()
+}
+
+// NotificationSettings are the user's toggles for every channel and type,
+// filling in the ones they never changed as enabled
+func NotificationSettings(stored []NotificationPreference) []NotificationPreference {
+	settings := make([]NotificationPreference, 0, len(NotificationChannels)*len(NotificationTypes))
+	for _, channel := range NotificationChannels {
+		for _, notificationType := range NotificationTypes {
+			setting := NotificationPreference{Channel: channel, Type: notificationType, Enabled: true}
+			for _, preference := range stored {
+				if preference.Channel == channel && preference.Type == notificationType {
+					setting.Enabled = preference.Enabled
+				}
+			}
+			settings = append(settings, setting)
+		}
+	}
+	return settings
+}
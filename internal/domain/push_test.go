@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPushDevice_Validate(t *testing.T) {
+	device := PushDevice{Platform: DevicePlatformAndroid, Token: "  fcm-token  "}
+	assert.NoError(t, device.Validate())
+	assert.Equal(t, "fcm-token", device.Token)
+
+	for _, invalid := range []PushDevice{
+		{Platform: "web", Token: "token"},
+		{Platform: DevicePlatformIOS, Token: " "},
+		{Platform: DevicePlatformIOS, Token: strings.Repeat("a", MaxDeviceTokenLength+1)},
+	} {
+		assert.ErrorIs(t, invalid.Validate(), ErrValidation)
+	}
+}
+
+func TestNotificationSettings(t *testing.T) {
+	settings := NotificationSettings([]NotificationPreference{
+		{Channel: NotificationChannelPush, Type: NotificationTypeGoal, Enabled: false},
+	})
+	assert.Len(t, settings, len(NotificationChannels)*len(NotificationTypes))
+	for _, setting := range settings {
+		off := setting.Channel == NotificationChannelPush && setting.Type == NotificationTypeGoal
+		assert.Equal(t, !off, setting.Enabled, setting.Channel+" "+setting.Type)
+	}
+
+	preference := NotificationPreference{Channel: NotificationChannelInApp, Type: NotificationTypeBill}
+	assert.NoError(t, preference.Validate())
+	preference.Type = "weather"
+	assert.ErrorIs(t, preference.Validate(), ErrValidation)
+}
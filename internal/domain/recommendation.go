@@ -4,10 +4,13 @@ package domain
 
 import "time"
 
-// Recommendation represents an investment recommendation
+// Recommendation represents an investment recommendation. Recommendations
+// that were part of generated advice belong to its AdviceRecord, and the user
+// can mark them as followed or ignored.
 type Recommendation struct {
 	ID           uint       `json:"id" gorm:"primaryKey"`
 	UserID       uint       `json:"user_id" gorm:"not null"`
+	AdviceID     *uint      `json:"advice_id,omitempty" gorm:"index"`
 	Type         string     `json:"type" gorm:"not null"` // "crypto", "stock", "bond", "diversification"
 	Symbol       string     `json:"symbol" gorm:"not null"`
 	Action       string     `json:"action" gorm:"not null"` // "buy", "sell", "hold"
@@ -21,6 +24,10 @@ type Recommendation struct {
 	UpdatedAt    time.Time  `json:"updated_at"`
 	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
 	IsActive     bool       `json:"is_active" gorm:"default:true"`
+	// Allocation is the share of the investable amount, in percent, when the advice gives one
+	Allocation      float64    `json:"allocation,omitempty"`
+	Status          string     `json:"status" gorm:"type:varchar(20);default:'pending'"`
+	StatusChangedAt *time.Time `json:"status_changed_at,omitempty"`
 }
 
 // InvestmentAdvice represents comprehensive investment advice for a user
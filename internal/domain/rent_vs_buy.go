@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// Rent vs buy calculator limits and defaults
+const (
+	DefaultMortgageYears = 30
+	MaxRentVsBuyYears    = 50
+)
+
+// RentVsBuyRequest describes a home to buy with a mortgage and the rent paid
+// instead. Rates are annual fractions: PropertyTaxRate and MaintenanceRate
+// of the home's value, SellingCostRate of the sale price. InvestmentReturn
+// is what the down payment would earn if it were invested instead. Years is
+// how far the comparison runs, the mortgage's term by default.
+type RentVsBuyRequest struct {
+	HomePrice        Money   `json:"home_price"`
+	DownPayment      Money   `json:"down_payment"`
+	MortgageRate     float64 `json:"mortgage_rate"`
+	MortgageYears    int     `json:"mortgage_years"`
+	PropertyTaxRate  float64 `json:"property_tax_rate"`
+	MaintenanceRate  float64 `json:"maintenance_rate"`
+	AppreciationRate float64 `json:"appreciation_rate"`
+	SellingCostRate  float64 `json:"selling_cost_rate"`
+	MonthlyRent      Money   `json:"monthly_rent"`
+	RentGrowthRate   float64 `json:"rent_growth_rate"`
+	InvestmentReturn float64 `json:"investment_return"`
+	Years            int     `json:"years"`
+}
+
+// Validate fills in the default terms and checks the amounts and rates
+func (r *RentVsBuyRequest) Validate() error {
+	if r.MortgageYears == 0 {
+		r.MortgageYears = DefaultMortgageYears
+	}
+	if r.Years == 0 {
+		r.Years = min(r.MortgageYears, MaxRentVsBuyYears)
+	}
+
+	var v validator
+	v.check(r.HomePrice > 0, "home_price", "must be positive")
+	v.check(r.DownPayment >= 0 && r.DownPayment <= r.HomePrice, "down_payment", "must be between 0 and the home price")
+	v.check(r.MortgageRate >= 0 && r.MortgageRate <= 1, "mortgage_rate", "must be between 0 and 1")
+	v.check(r.MortgageYears >= 1 && r.MortgageYears <= MaxRentVsBuyYears, "mortgage_years", "must be between 1 and 50")
+	v.check(r.PropertyTaxRate >= 0 && r.PropertyTaxRate <= 0.2, "property_tax_rate", "must be between 0 and 0.2")
+	v.check(r.MaintenanceRate >= 0 && r.MaintenanceRate <= 0.2, "maintenance_rate", "must be between 0 and 0.2")
+	v.check(r.AppreciationRate > -1 && r.AppreciationRate <= 1, "appreciation_rate", "must be above -1 and at most 1")
+	v.check(r.SellingCostRate >= 0 && r.SellingCostRate <= 0.2, "selling_cost_rate", "must be between 0 and 0.2")
+	v.check(r.MonthlyRent > 0, "monthly_rent", "must be positive")
+	v.check(r.RentGrowthRate > -1 && r.RentGrowthRate <= 1, "rent_growth_rate", "must be above -1 and at most 1")
+	v.check(r.InvestmentReturn > -1 && r.InvestmentReturn <= 1, "investment_return", "must be above -1 and at most 1")
+	v.check(r.Years >= 1 && r.Years <= MaxRentVsBuyYears, "years", "must be between 1 and 50")
+	return v.err()
+}
+
+// RentVsBuyYear is where renting and buying stand at the end of a year.
+// The cumulative costs count everything paid so far; BuyNetCost is what
+// buying cost if the home were sold at the end of the year: the down
+// payment, mortgage payments, taxes, maintenance and the returns the down
+// payment missed out on, less the equity the sale frees.
+type RentVsBuyYear struct {
+	Year             int   `json:"year"`
+	Rent             Money `json:"rent"`
+	RentCumulative   Money `json:"rent_cumulative"`
+	MortgagePayments Money `json:"mortgage_payments"`
+	Interest         Money `json:"interest"`
+	PropertyTax      Money `json:"property_tax"`
+	Maintenance      Money `json:"maintenance"`
+	BuyCumulative    Money `json:"buy_cumulative"`
+	HomeValue        Money `json:"home_value"`
+	MortgageBalance  Money `json:"mortgage_balance"`
+	Equity           Money `json:"equity"`
+	OpportunityCost  Money `json:"opportunity_cost"`
+	BuyNetCost       Money `json:"buy_net_cost"`
+}
+
+// RentVsBuyComparison is the year by year cost of renting and buying.
+// BreakEvenYear is the first year after which buying has cost no more than
+// renting, or nil when renting stays cheaper over the whole comparison.
+type RentVsBuyComparison struct {
+	LoanAmount     Money           `json:"loan_amount"`
+	MonthlyPayment Money           `json:"monthly_payment"`
+	BreakEvenYear  *int            `json:"break_even_year"`
+	Years          []RentVsBuyYear `json:"years"`
+}
+
+// CompareRentVsBuy runs the comparison of a validated request. The mortgage
+// is amortized like a loan; the home appreciates and rent grows once a year.
+func CompareRentVsBuy(req RentVsBuyRequest) RentVsBuyComparison {
+	mortgage := &Loan{
+		Principal: req.HomePrice - req.DownPayment, APR: req.MortgageRate * 100,
+		TermMonths: req.MortgageYears * 12, StartDate: time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}
+	schedule := Amortize(mortgage, nil, LoanExtraPayments{})
+	comparison := RentVsBuyComparison{LoanAmount: mortgage.Principal, Years: make([]RentVsBuyYear, 0, req.Years)}
+	if mortgage.Principal > 0 {
+		comparison.MonthlyPayment = schedule.MonthlyPayment
+	}
+
+	growth := func(rate float64, years int) float64 { return math.Pow(1+rate, float64(years)) }
+	var rentTotal, buyTotal Money = 0, req.DownPayment
+	balance := mortgage.Principal
+	for year := 1; year <= req.Years; year++ {
+		valueAtStart := req.HomePrice.Mul(growth(req.AppreciationRate, year-1))
+		row := RentVsBuyYear{
+			Year:        year,
+			Rent:        (req.MonthlyRent * 12).Mul(growth(req.RentGrowthRate, year-1)),
+			PropertyTax: valueAtStart.Mul(req.PropertyTaxRate),
+			Maintenance: valueAtStart.Mul(req.MaintenanceRate),
+			HomeValue:   req.HomePrice.Mul(growth(req.AppreciationRate, year)),
+		}
+		for _, payment := range schedule.Rows {
+			if (payment.Number-1)/12+1 == year {
+				row.MortgagePayments += payment.Payment + payment.Extra
+				row.Interest += payment.Interest
+				balance = payment.Balance
+			}
+		}
+		rentTotal += row.Rent
+		buyTotal += row.MortgagePayments + row.PropertyTax + row.Maintenance
+
+		row.RentCumulative, row.BuyCumulative, row.MortgageBalance = rentTotal, buyTotal, balance
+		row.Equity = row.HomeValue.Mul(1-req.SellingCostRate) - balance
+		row.OpportunityCost = req.DownPayment.Mul(growth(req.InvestmentReturn, year) - 1)
+		row.BuyNetCost = buyTotal + row.OpportunityCost - row.Equity
+		if comparison.BreakEvenYear == nil && row.BuyNetCost <= row.RentCumulative {
+			comparison.BreakEvenYear = &row.Year
+		}
+		comparison.Years = append(comparison.Years, row)
+	}
+	return comparison
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRentVsBuyRequest_Validate(t *testing.T) {
+	req := RentVsBuyRequest{HomePrice: NewMoney(400000), DownPayment: NewMoney(80000), MortgageRate: 0.06, MonthlyRent: NewMoney(1800)}
+	require.NoError(t, req.Validate())
+	assert.Equal(t, DefaultMortgageYears, req.MortgageYears)
+	assert.Equal(t, DefaultMortgageYears, req.Years)
+
+	var validationErr *ValidationError
+	invalid := RentVsBuyRequest{HomePrice: NewMoney(100), DownPayment: NewMoney(200), MortgageRate: 2, Years: 80}
+	require.ErrorAs(t, invalid.Validate(), &validationErr)
+	assert.Len(t, validationErr.Fields, 4)
+}
+
+func TestCompareRentVsBuy(t *testing.T) {
+	req := RentVsBuyRequest{
+		HomePrice: NewMoney(400000), DownPayment: NewMoney(80000), MortgageRate: 0.06, MortgageYears: 30,
+		PropertyTaxRate: 0.01, MaintenanceRate: 0.01, AppreciationRate: 0.03, SellingCostRate: 0.06,
+		MonthlyRent: NewMoney(1800), RentGrowthRate: 0.03, InvestmentReturn: 0.05, Years: 30,
+	}
+	comparison := CompareRentVsBuy(req)
+
+	assert.Equal(t, NewMoney(320000), comparison.LoanAmount)
+	assert.Equal(t, NewMoney(1918.56), comparison.MonthlyPayment)
+	require.Len(t, comparison.Years, 30)
+
+	first := comparison.Years[0]
+	assert.Equal(t, NewMoney(21600), first.Rent)
+	assert.Equal(t, NewMoney(4000), first.PropertyTax)
+	assert.Equal(t, NewMoney(412000), first.HomeValue)
+	assert.Equal(t, comparison.MonthlyPayment*12, first.MortgagePayments)
+	assert.Equal(t, NewMoney(80000)+first.MortgagePayments+first.PropertyTax+first.Maintenance, first.BuyCumulative)
+	assert.Equal(t, NewMoney(4000), first.OpportunityCost)
+	assert.Greater(t, first.BuyNetCost, first.RentCumulative, "selling after a year costs more than renting")
+
+	last := comparison.Years[29]
+	assert.Zero(t, last.MortgageBalance)
+	assert.Equal(t, NewMoney(21600).Mul(1.03*1.03), comparison.Years[2].Rent)
+
+	require.NotNil(t, comparison.BreakEvenYear)
+	year := *comparison.BreakEvenYear
+	assert.LessOrEqual(t, comparison.Years[year-1].BuyNetCost, comparison.Years[year-1].RentCumulative)
+	assert.Greater(t, comparison.Years[year-2].BuyNetCost, comparison.Years[year-2].RentCumulative)
+
+	t.Run("renting stays cheaper when rent is low", func(t *testing.T) {
+		req := req
+		req.MonthlyRent, req.Years = NewMoney(500), 10
+		assert.Nil(t, CompareRentVsBuy(req).BreakEvenYear)
+	})
+
+	t.Run("buying outright has no mortgage", func(t *testing.T) {
+		req := req
+		req.DownPayment = req.HomePrice
+		comparison := CompareRentVsBuy(req)
+		assert.Zero(t, comparison.MonthlyPayment)
+		assert.Zero(t, comparison.Years[0].MortgagePayments)
+	})
+}
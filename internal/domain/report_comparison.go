@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrInvalidReportPeriod is returned for a period label ParseReportPeriod
+// does not understand
+var ErrInvalidReportPeriod = errors.New("invalid report period: use YYYY-MM, YYYY-Qn, YYYY or YYYY-MM-DD..YYYY-MM-DD")
+
+// ParseReportPeriod turns a period label as produced by PeriodLabel back into
+// its report type and the first and last moment it covers
+func ParseReportPeriod(label string) (reportType string, start, end time.Time, err error) {
+	label = strings.TrimSpace(label)
+	if from, to, ok := strings.Cut(label, ".."); ok {
+		start, err = time.Parse("2006-01-02", from)
+		if err != nil {
+			return "", start, end, ErrInvalidReportPeriod
+		}
+		last, err := time.Parse("2006-01-02", to)
+		if err != nil || last.Before(start) {
+			return "", start, end, ErrInvalidReportPeriod
+		}
+		return ReportTypeCustom, start, last.AddDate(0, 0, 1).Add(-time.Second), nil
+	}
+
+	switch {
+	case len(label) == 7 && (label[5] == 'Q' || label[5] == 'q'):
+		year, err := time.Parse("2006", label[:4])
+		quarter := int(label[6] - '0')
+		if err != nil || label[4] != '-' || quarter < 1 || quarter > 4 {
+			return "", start, end, ErrInvalidReportPeriod
+		}
+		start = time.Date(year.Year(), time.Month(3*quarter-2), 1, 0, 0, 0, 0, time.UTC)
+		return ReportTypeQuarterly, start, start.AddDate(0, 3, 0).Add(-time.Second), nil
+	case len(label) == 7:
+		start, err = time.Parse("2006-01", label)
+		if err != nil {
+			return "", start, end, ErrInvalidReportPeriod
+		}
+		return ReportTypeMonthly, start, start.AddDate(0, 1, 0).Add(-time.Second), nil
+	case len(label) == 4:
+		start, err = time.Parse("2006", label)
+		if err != nil {
+			return "", start, end, ErrInvalidReportPeriod
+		}
+		return ReportTypeYearly, start, start.AddDate(1, 0, 0).Add(-time.Second), nil
+	}
+	return "", start, end, ErrInvalidReportPeriod
+}
+
+// ValueChange compares one figure between a base and a target period.
+// ChangePct is relative to the base and zero when the base is zero.
+type ValueChange struct {
+	Base      float64 `json:"base"`
+	Target    float64 `json:"target"`
+	Change    float64 `json:"change"`
+	ChangePct float64 `json:"change_pct"`
+}
+
+// NewValueChange compares base with target
+func NewValueChange(base, target float64) ValueChange {
+	change := ValueChange{Base: base, Target: target, Change: target - base}
+	if base != 0 {
+		change.ChangePct = change.Change / math.Abs(base) * 100
+	}
+	return change
+}
+
+// CategoryChange is how spending or income in one category changed
+type CategoryChange struct {
+	CategoryID   uint   `json:"category_id"`
+	CategoryName string `json:"category_name"`
+	ValueChange
+}
+
+// ReportComparison compares two periods. SavingsRateChange is in
+// percentage points; Categories are ordered by the size of their change.
+type ReportComparison struct {
+	Base              ReportSummary    `json:"base"`
+	Target            ReportSummary    `json:"target"`
+	Income            ValueChange      `json:"income"`
+	Expenses          ValueChange      `json:"expenses"`
+	NetIncome         ValueChange      `json:"net_income"`
+	SavingsRateChange float64          `json:"savings_rate_change"`
+	TransactionChange int              `json:"transaction_change"`
+	Categories        []CategoryChange `json:"categories"`
+}
+
+// CompareReports compares a target report with a base report. Categories
+// present in only one of them compare against zero.
+func CompareReports(base, target *FinancialReport) ReportComparison {
+	comparison := ReportComparison{
+		Base:              base.ToSummary(),
+		Target:            target.ToSummary(),
+		Income:            NewValueChange(base.TotalIncome, target.TotalIncome),
+		Expenses:          NewValueChange(base.TotalExpenses, target.TotalExpenses),
+		NetIncome:         NewValueChange(base.NetIncome, target.NetIncome),
+		SavingsRateChange: target.SavingsRate - base.SavingsRate,
+		TransactionChange: target.TransactionCount - base.TransactionCount,
+		Categories:        []CategoryChange{},
+	}
+
+	index := map[uint]int{}
+	for _, metrics := range base.CategoryBreakdown {
+		index[metrics.CategoryID] = len(comparison.Categories)
+		comparison.Categories = append(comparison.Categories, CategoryChange{
+			CategoryID:   metrics.CategoryID,
+			CategoryName: metrics.CategoryName,
+			ValueChange:  NewValueChange(metrics.TotalAmount, 0),
+		})
+	}
+	for _, metrics := range target.CategoryBreakdown {
+		i, ok := index[metrics.CategoryID]
+		if !ok {
+			comparison.Categories = append(comparison.Categories, CategoryChange{
+				CategoryID:   metrics.CategoryID,
+				CategoryName: metrics.CategoryName,
+				ValueChange:  NewValueChange(0, metrics.TotalAmount),
+			})
+			continue
+		}
+		comparison.Categories[i].ValueChange = NewValueChange(comparison.Categories[i].Base, metrics.TotalAmount)
+	}
+
+	sort.SliceStable(comparison.Categories, func(i, j int) bool {
+		return math.Abs(comparison.Categories[i].Change) > math.Abs(comparison.Categories[j].Change)
+	})
+	return comparison
+}
@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReportPeriod(t *testing.T) {
+	tests := []struct {
+		label      string
+		reportType string
+		start      time.Time
+		end        time.Time
+	}{
+		{"2024-02", ReportTypeMonthly, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 2, 29, 23, 59, 59, 0, time.UTC)},
+		{"2024-Q2", ReportTypeQuarterly, time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 6, 30, 23, 59, 59, 0, time.UTC)},
+		{"2024-q4", ReportTypeQuarterly, time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 12, 31, 23, 59, 59, 0, time.UTC)},
+		{"2023", ReportTypeYearly, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 12, 31, 23, 59, 59, 0, time.UTC)},
+		{"2024-01-10..2024-01-20", ReportTypeCustom, time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 20, 23, 59, 59, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.label, func(t *testing.T) {
+			reportType, start, end, err := ParseReportPeriod(tt.label)
+			require.NoError(t, err)
+			assert.Equal(t, tt.reportType, reportType)
+			assert.Equal(t, tt.start, start)
+			assert.Equal(t, tt.end, end)
+		})
+	}
+
+	for _, label := range []string{"", "2024-13", "2024-Q5", "24", "2024-02-10..2024-02-01", "next month"} {
+		_, _, _, err := ParseReportPeriod(label)
+		assert.ErrorIs(t, err, ErrInvalidReportPeriod, label)
+	}
+}
+
+func TestParseReportPeriod_RoundTripsPeriodLabel(t *testing.T) {
+	for _, label := range []string{"2024-03", "2024-Q3", "2025", "2024-03-01..2024-03-15"} {
+		reportType, start, end, err := ParseReportPeriod(label)
+		require.NoError(t, err)
+		report := FinancialReport{ReportType: reportType, StartDate: start, EndDate: end}
+		assert.Equal(t, label, report.PeriodLabel())
+	}
+}
+
+func TestCompareReports(t *testing.T) {
+	base := &FinancialReport{
+		ReportType: ReportTypeMonthly, Period: "2024-01",
+		TotalIncome: 4000, TotalExpenses: 3000, NetIncome: 1000, SavingsRate: 25, TransactionCount: 10,
+		CategoryBreakdown: []CategoryMetrics{
+			{CategoryID: 1, CategoryName: "Salary", TotalAmount: 4000},
+			{CategoryID: 2, CategoryName: "Food", TotalAmount: 500},
+			{CategoryID: 3, CategoryName: "Travel", TotalAmount: 2500},
+		},
+	}
+	target := &FinancialReport{
+		ReportType: ReportTypeMonthly, Period: "2024-02",
+		TotalIncome: 5000, TotalExpenses: 2000, NetIncome: 3000, SavingsRate: 60, TransactionCount: 8,
+		CategoryBreakdown: []CategoryMetrics{
+			{CategoryID: 1, CategoryName: "Salary", TotalAmount: 5000},
+			{CategoryID: 2, CategoryName: "Food", TotalAmount: 800},
+			{CategoryID: 4, CategoryName: "Rent", TotalAmount: 1200},
+		},
+	}
+
+	comparison := CompareReports(base, target)
+
+	assert.Equal(t, "2024-01", comparison.Base.Period)
+	assert.Equal(t, "2024-02", comparison.Target.Period)
+	assert.Equal(t, ValueChange{Base: 4000, Target: 5000, Change: 1000, ChangePct: 25}, comparison.Income)
+	assert.InDelta(t, -33.33, comparison.Expenses.ChangePct, 0.01)
+	assert.Equal(t, 200.0, comparison.NetIncome.ChangePct)
+	assert.Equal(t, 35.0, comparison.SavingsRateChange)
+	assert.Equal(t, -2, comparison.TransactionChange)
+
+	require.Len(t, comparison.Categories, 4)
+	assert.Equal(t, "Travel", comparison.Categories[0].CategoryName)
+	assert.Equal(t, -2500.0, comparison.Categories[0].Change)
+	assert.Equal(t, "Rent", comparison.Categories[1].CategoryName)
+	assert.Equal(t, 1200.0, comparison.Categories[1].Change)
+	assert.Zero(t, comparison.Categories[1].ChangePct)
+	assert.Equal(t, "Salary", comparison.Categories[2].CategoryName)
+	assert.Equal(t, 60.0, comparison.Categories[3].ChangePct)
+}
+
+func FuzzParseReportPeriod(f *testing.F) {
+	for _, seed := range []string{"2024-03", "2024-Q3", "2024-q1", "2025", "2024-03-01..2024-03-15", "+024-Q1", "-001-Q2", "2024-Q0"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, label string) {
+		reportType, start, end, err := ParseReportPeriod(label)
+		if err != nil {
+			require.ErrorIs(t, err, ErrInvalidReportPeriod)
+			return
+		}
+		require.True(t, start.Before(end), "%q covers %s to %s", label, start, end)
+
+		report := FinancialReport{ReportType: reportType, StartDate: start, EndDate: end}
+		againType, againStart, againEnd, err := ParseReportPeriod(report.PeriodLabel())
+		require.NoError(t, err, "%q is labelled %q", label, report.PeriodLabel())
+		assert.Equal(t, reportType, againType)
+		assert.Equal(t, start, againStart)
+		assert.Equal(t, end, againEnd)
+	})
+}
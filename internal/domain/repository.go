@@ -0,0 +1,171 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// ErrNotFound is returned by repositories when a record does not exist
+var ErrNotFound = errors.New("record not found")
+
+// TransactionFilter narrows down transaction queries. Zero values and nil
+// pointers are ignored; Limit and Offset only apply when positive. UserID is
+// always matched unless HouseholdID is set, in which case a zero UserID
+// covers all members.
+type TransactionFilter struct {
+	UserID      uint
+	HouseholdID *uint // Matches transactions shared with the household
+	// PersonalOnly leaves out transactions shared with a household
+	PersonalOnly bool
+	// AccountID matches the transactions on the account, transfers into it
+	// included, and TransferAccountID only the transfers into the account
+	AccountID         *uint
+	TransferAccountID *uint
+	// ExcludeTransfers leaves out transfers between the user's accounts
+	ExcludeTransfers bool
+	// ScheduledTransferID matches the transfers a scheduled transfer made
+	ScheduledTransferID *uint
+	Type                string
+	CategoryID          *uint
+	// CategoryIDs matches transactions in any of the categories, e.g. a parent and its children
+	CategoryIDs []uint
+	StartDate   *time.Time // date >= StartDate
+	EndDate     *time.Time // date <= EndDate
+	Limit       int
+	Offset      int
+	Cursor      *TransactionCursor // Keyset pagination for Find; Offset is ignored when set
+	// OlderThan only matches transactions after the position in the
+	// newest-first ordering, in every query, e.g. to sum up what came before a page
+	OlderThan *TransactionCursor
+}
+
+// TransactionRepository persists transactions. Find returns the newest
+// transactions first (ties broken by descending ID) with their category
+// loaded. Count ignores Limit, Offset and Cursor.
+//
+// Delete is a soft delete: the transaction disappears from every other query
+// but can be listed with FindDeleted and brought back with Restore until it
+// is purged. FindDeleted returns the most recently deleted first, and a zero
+// userID in PurgeDeletedBefore covers all users.
+type TransactionRepository interface {
+	Create(ctx context.Context, transaction *Transaction) error
+	GetByID(ctx context.Context, id uint) (*Transaction, error)
+	Update(ctx context.Context, transaction *Transaction) error
+	Delete(ctx context.Context, id uint) error
+	Find(ctx context.Context, filter TransactionFilter) ([]Transaction, error)
+	Count(ctx context.Context, filter TransactionFilter) (int64, error)
+	Sum(ctx context.Context, filter TransactionFilter) (Money, error)
+	SumByCategory(ctx context.Context, filter TransactionFilter) (map[uint]Money, error)
+	SumByMonth(ctx context.Context, filter TransactionFilter) (map[string]Money, error) // keyed by "YYYY-MM"
+	FindDeleted(ctx context.Context, filter TransactionFilter) ([]Transaction, error)
+	Restore(ctx context.Context, userID, id uint) error
+	Purge(ctx context.Context, userID, id uint) error
+	PurgeDeletedBefore(ctx context.Context, userID uint, cutoff time.Time) (int64, error)
+}
+
+// BudgetFilter narrows down budget queries. Nil fields are ignored. As with
+// TransactionFilter, a zero UserID only matches everyone with HouseholdID set.
+type BudgetFilter struct {
+	UserID       uint
+	HouseholdID  *uint // Matches the household's budgets
+	PersonalOnly bool  // Leaves out household budgets
+	CategoryID   *uint
+	CategoryIDs  []uint // Matches budgets on any of the categories
+	IsActive     *bool
+	StartsBefore *time.Time // start_date <= StartsBefore
+	EndsAfter    *time.Time // end_date >= EndsAfter
+}
+
+// BudgetRepository persists budgets. Find returns the most recently created
+// budgets first with their category loaded.
+type BudgetRepository interface {
+	Create(ctx context.Context, budget *Budget) error
+	GetByID(ctx context.Context, id uint) (*Budget, error)
+	Update(ctx context.Context, budget *Budget) error
+	Delete(ctx context.Context, id uint) error
+	Find(ctx context.Context, filter BudgetFilter) ([]Budget, error)
+}
+
+// AccountRepository persists accounts. Implementations may encrypt account
+// numbers at rest; what they return always holds the plaintext number with
+// MaskedNumber filled in. List returns the user's accounts by name.
+type AccountRepository interface {
+	Create(ctx context.Context, account *Account) error
+	GetByID(ctx context.Context, id uint) (*Account, error)
+	Update(ctx context.Context, account *Account) error
+	Delete(ctx context.Context, id uint) error
+	List(ctx context.Context, userID uint) ([]Account, error)
+}
+
+// BankLinkRepository persists linked bank accounts. Implementations may
+// encrypt access tokens at rest; what they return always holds the plaintext
+// token. List returns the user's links by institution and name; Syncable
+// returns the links of all users that scheduled syncs pull.
+type BankLinkRepository interface {
+	Create(ctx context.Context, link *BankLink) error
+	GetByID(ctx context.Context, id uint) (*BankLink, error)
+	Update(ctx context.Context, link *BankLink) error
+	Delete(ctx context.Context, id uint) error
+	List(ctx context.Context, userID uint) ([]BankLink, error)
+	Syncable(ctx context.Context) ([]BankLink, error)
+}
+
+// AttachmentRepository persists attachments. Implementations may encrypt
+// storage paths at rest; what they return always holds the plaintext path.
+type AttachmentRepository interface {
+	Create(ctx context.Context, attachment *Attachment) error
+	GetByID(ctx context.Context, id uint) (*Attachment, error)
+}
+
+type unscopedKey struct{}
+
+// ContextWithoutUserScope returns a context whose queries reach every user's
+// rows, for work the acting user sets off on behalf of others, such as
+// reacting to an event about another household member's budget
+func ContextWithoutUserScope(ctx context.Context) context.Context {
+	return context.WithValue(ctx, unscopedKey{}, true)
+}
+
+// UserScopeFromContext returns the user whose rows the context's queries are
+// limited to: the acting user, unless ContextWithoutUserScope lifted it
+func UserScopeFromContext(ctx context.Context) (uint, bool) {
+	if unscoped, _ := ctx.Value(unscopedKey{}).(bool); unscoped {
+		return 0, false
+	}
+	actor, ok := ActorFromContext(ctx)
+	if !ok || actor.UserID == 0 {
+		return 0, false
+	}
+	return actor.UserID, true
+}
+
+type replicaKey struct{}
+
+// replicaPreference is shared by the statements made with one context, so a
+// write sends its later reads back to the primary
+type replicaPreference struct {
+	wrote atomic.Bool
+}
+
+// ContextPreferringReplica returns a context whose reads may be served by a
+// read replica, for read-heavy work that tolerates slightly stale data such
+// as analytics and exports. Once anything is written with it, its reads go
+// to the primary again so they see the write.
+func ContextPreferringReplica(ctx context.Context) context.Context {
+	return context.WithValue(ctx, replicaKey{}, &replicaPreference{})
+}
+
+// PrefersReplica reports whether the context's reads may go to a replica
+func PrefersReplica(ctx context.Context) bool {
+	preference, ok := ctx.Value(replicaKey{}).(*replicaPreference)
+	return ok && !preference.wrote.Load()
+}
+
+// NoteWrite records that the context wrote, keeping its reads on the primary
+func NoteWrite(ctx context.Context) {
+	if preference, ok := ctx.Value(replicaKey{}).(*replicaPreference); ok {
+		preference.wrote.Store(true)
+	}
+}
@@ -0,0 +1,215 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Retirement simulation limits. Iterations are capped by configuration.
+const (
+	DefaultRetirementIterations = 1000
+	MaxRetirementYears          = 60
+)
+
+// ReturnAssumption is the expected annual return of an asset class and the
+// annual volatility around it, both as fractions
+type ReturnAssumption struct {
+	ExpectedReturn float64 `json:"expected_return"`
+	Volatility     float64 `json:"volatility"`
+}
+
+// DefaultReturnAssumptions are long-run historical figures for the asset
+// classes of the advisor's recommended allocations
+var DefaultReturnAssumptions = map[string]ReturnAssumption{
+	"stocks": {ExpectedReturn: 0.07, Volatility: 0.15},
+	"bonds":  {ExpectedReturn: 0.03, Volatility: 0.06},
+	"cash":   {ExpectedReturn: 0.02, Volatility: 0.01},
+	"crypto": {ExpectedReturn: 0.15, Volatility: 0.70},
+}
+
+// RetirementAllocation is the share of the portfolio held in an asset class.
+// ExpectedReturn and Volatility default to DefaultReturnAssumptions.
+type RetirementAllocation struct {
+	Asset          string   `json:"asset"`
+	Share          float64  `json:"share"`
+	ExpectedReturn *float64 `json:"expected_return,omitempty"`
+	Volatility     *float64 `json:"volatility,omitempty"`
+}
+
+// assumption returns the allocation's return and volatility, falling back to
+// the defaults of its asset class
+func (a RetirementAllocation) assumption() (ReturnAssumption, bool) {
+	assumption, known := DefaultReturnAssumptions[strings.ToLower(a.Asset)]
+	if a.ExpectedReturn != nil {
+		assumption.ExpectedReturn = *a.ExpectedReturn
+	}
+	if a.Volatility != nil {
+		assumption.Volatility = *a.Volatility
+	}
+	return assumption, known || (a.ExpectedReturn != nil && a.Volatility != nil)
+}
+
+// RetirementSimulationRequest describes the portfolio to grow: its starting
+// balance, what is added at the end of every month and how it is allocated,
+// rebalanced monthly. Seed makes runs repeatable when set.
+type RetirementSimulationRequest struct {
+	InitialBalance      Money                  `json:"initial_balance"`
+	MonthlyContribution *Money                 `json:"monthly_contribution,omitempty"`
+	Years               int                    `json:"years"`
+	Target              Money                  `json:"target"`
+	Allocation          []RetirementAllocation `json:"allocation"`
+	Iterations          int                    `json:"iterations"`
+	Seed                uint64                 `json:"seed,omitempty"`
+}
+
+// Validate checks the horizon, the amounts, the iterations against the
+// configured maximum and that the allocation's shares sum to 1 with a return
+// assumption for every asset
+func (r RetirementSimulationRequest) Validate(maxIterations int) error {
+	var v validator
+	v.check(r.Years >= 1 && r.Years <= MaxRetirementYears, "years", "must be between 1 and "+strconv.Itoa(MaxRetirementYears))
+	v.check(r.InitialBalance >= 0, "initial_balance", "must not be negative")
+	v.check(r.MonthlyContribution == nil || *r.MonthlyContribution >= 0, "monthly_contribution", "must not be negative")
+	v.check(r.Target > 0, "target", "must be positive")
+	v.check(r.Iterations >= 0 && r.Iterations <= maxIterations, "iterations", "must be between 1 and "+strconv.Itoa(maxIterations))
+	v.check(len(r.Allocation) > 0, "allocation", "is required")
+
+	var total float64
+	for i, allocation := range r.Allocation {
+		field := fmt.Sprintf("allocation[%d]", i)
+		assumption, ok := allocation.assumption()
+		v.check(ok, field, "needs expected_return and volatility for assets other than stocks, bonds, cash and crypto")
+		v.check(allocation.Share >= 0, field+".share", "must not be negative")
+		v.check(assumption.ExpectedReturn > -1, field+".expected_return", "must be above -1")
+		v.check(assumption.Volatility >= 0, field+".volatility", "must not be negative")
+		total += allocation.Share
+	}
+	v.check(len(r.Allocation) == 0 || math.Abs(total-1) <= 1e-6, "allocation", "shares must sum to 1")
+	return v.err()
+}
+
+// RetirementBand is the spread of simulated balances at the end of a year:
+// the balance a tenth, a quarter, half, three quarters and nine tenths of the
+// runs stayed below
+type RetirementBand struct {
+	Year        int   `json:"year"`
+	Contributed Money `json:"contributed"`
+	P10         Money `json:"p10"`
+	P25         Money `json:"p25"`
+	P50         Money `json:"p50"`
+	P75         Money `json:"p75"`
+	P90         Money `json:"p90"`
+}
+
+// RetirementSimulation is the outcome of a Monte Carlo simulation. The
+// expected return and volatility are the allocation's annual figures;
+// TargetProbability is the share of runs ending at or above the target.
+type RetirementSimulation struct {
+	Iterations          int                    `json:"iterations"`
+	Years               int                    `json:"years"`
+	InitialBalance      Money                  `json:"initial_balance"`
+	MonthlyContribution Money                  `json:"monthly_contribution"`
+	Target              Money                  `json:"target"`
+	Allocation          []RetirementAllocation `json:"allocation"`
+	ExpectedReturn      float64                `json:"expected_return"`
+	Volatility          float64                `json:"volatility"`
+	TargetProbability   float64                `json:"target_probability"`
+	MedianFinalBalance  Money                  `json:"median_final_balance"`
+	Bands               []RetirementBand       `json:"bands"`
+}
+
+// SimulateRetirement grows the portfolio month by month in every run. Each
+// asset's monthly return is drawn from a lognormal distribution matching its
+// annual expected return and volatility, independently of the other assets.
+// The request must be valid and have its contribution and iterations set.
+func SimulateRetirement(req RetirementSimulationRequest) RetirementSimulation {
+	contribution := Money(0)
+	if req.MonthlyContribution != nil {
+		contribution = *req.MonthlyContribution
+	}
+	iterations := req.Iterations
+	if iterations == 0 {
+		iterations = DefaultRetirementIterations
+	}
+	simulation := RetirementSimulation{
+		Iterations: iterations, Years: req.Years, InitialBalance: req.InitialBalance,
+		MonthlyContribution: contribution, Target: req.Target, Bands: []RetirementBand{},
+	}
+
+	// Monthly log-return drift and deviation of every asset
+	shares := make([]float64, len(req.Allocation))
+	drifts := make([]float64, len(req.Allocation))
+	deviations := make([]float64, len(req.Allocation))
+	var variance float64
+	for i, allocation := range req.Allocation {
+		assumption, _ := allocation.assumption()
+		expectedReturn, volatility := assumption.ExpectedReturn, assumption.Volatility
+		allocation.ExpectedReturn, allocation.Volatility = &expectedReturn, &volatility
+		simulation.Allocation = append(simulation.Allocation, allocation)
+
+		shares[i] = allocation.Share
+		drifts[i] = (math.Log1p(expectedReturn) - volatility*volatility/2) / 12
+		deviations[i] = volatility / math.Sqrt(12)
+		simulation.ExpectedReturn += allocation.Share * expectedReturn
+		variance += allocation.Share * allocation.Share * volatility * volatility
+	}
+	simulation.Volatility = math.Sqrt(variance)
+
+	seed := req.Seed
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+	random := rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))
+
+	// balances[year][run] is the balance of a run at the end of the year
+	balances := make([][]float64, req.Years)
+	for year := range balances {
+		balances[year] = make([]float64, iterations)
+	}
+	initial, monthly := req.InitialBalance.Float64(), contribution.Float64()
+	for run := 0; run < iterations; run++ {
+		balance := initial
+		for month := 0; month < req.Years*12; month++ {
+			var growth float64
+			for i := range shares {
+				growth += shares[i] * math.Exp(drifts[i]+deviations[i]*random.NormFloat64())
+			}
+			balance = balance*growth + monthly
+			if month%12 == 11 {
+				balances[month/12][run] = balance
+			}
+		}
+	}
+
+	for year, runs := range balances {
+		sort.Float64s(runs)
+		simulation.Bands = append(simulation.Bands, RetirementBand{
+			Year:        year + 1,
+			Contributed: req.InitialBalance + contribution*Money(12*(year+1)),
+			P10:         NewMoney(percentile(runs, 0.10)),
+			P25:         NewMoney(percentile(runs, 0.25)),
+			P50:         NewMoney(percentile(runs, 0.50)),
+			P75:         NewMoney(percentile(runs, 0.75)),
+			P90:         NewMoney(percentile(runs, 0.90)),
+		})
+	}
+	final := balances[len(balances)-1]
+	reached := len(final) - sort.SearchFloat64s(final, req.Target.Float64())
+	simulation.TargetProbability = float64(reached) / float64(iterations)
+	simulation.MedianFinalBalance = NewMoney(percentile(final, 0.5))
+	return simulation
+}
+
+// percentile interpolates the p-th percentile of sorted values
+func percentile(sorted []float64, p float64) float64 {
+	position := p * float64(len(sorted)-1)
+	lower := int(position)
+	if lower+1 >= len(sorted) {
+		return sorted[lower]
+	}
+	return sorted[lower] + (sorted[lower+1]-sorted[lower])*(position-float64(lower))
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetirementSimulationRequest_Validate(t *testing.T) {
+	contribution := NewMoney(500)
+	valid := RetirementSimulationRequest{
+		InitialBalance: NewMoney(10000), MonthlyContribution: &contribution, Years: 30, Target: NewMoney(500000),
+		Allocation: []RetirementAllocation{{Asset: "stocks", Share: 0.6}, {Asset: "Bonds", Share: 0.4}},
+	}
+	require.NoError(t, valid.Validate(5000))
+
+	expectedReturn, volatility := 0.05, 0.1
+	custom := valid
+	custom.Allocation = []RetirementAllocation{{Asset: "REIT", Share: 1, ExpectedReturn: &expectedReturn, Volatility: &volatility}}
+	assert.NoError(t, custom.Validate(5000), "unknown assets need their own assumptions")
+
+	invalid := RetirementSimulationRequest{
+		Years: 80, Target: 0, Iterations: 10000,
+		Allocation: []RetirementAllocation{{Asset: "stocks", Share: 0.5}, {Asset: "REIT", Share: 0.2}},
+	}
+	var validation *ValidationError
+	require.ErrorAs(t, invalid.Validate(5000), &validation)
+	var fields []string
+	for _, field := range validation.Fields {
+		fields = append(fields, field.Field)
+	}
+	assert.Equal(t, []string{"years", "target", "iterations", "allocation[1]", "allocation"}, fields)
+}
+
+func TestSimulateRetirement(t *testing.T) {
+	t.Run("without volatility every run grows alike", func(t *testing.T) {
+		expectedReturn, volatility := 0.1, 0.0
+		simulation := SimulateRetirement(RetirementSimulationRequest{
+			InitialBalance: NewMoney(1000), Years: 2, Target: NewMoney(1200), Iterations: 50,
+			Allocation: []RetirementAllocation{{Asset: "cash", Share: 1, ExpectedReturn: &expectedReturn, Volatility: &volatility}},
+		})
+
+		require.Len(t, simulation.Bands, 2)
+		assert.Equal(t, RetirementBand{
+			Year: 1, Contributed: NewMoney(1000), P10: NewMoney(1100), P25: NewMoney(1100),
+			P50: NewMoney(1100), P75: NewMoney(1100), P90: NewMoney(1100),
+		}, simulation.Bands[0])
+		assert.Equal(t, NewMoney(1210), simulation.MedianFinalBalance)
+		assert.Equal(t, 1.0, simulation.TargetProbability)
+		assert.Equal(t, 50, simulation.Iterations)
+	})
+
+	t.Run("percentile bands spread out", func(t *testing.T) {
+		contribution := NewMoney(500)
+		req := RetirementSimulationRequest{
+			InitialBalance: NewMoney(10000), MonthlyContribution: &contribution, Years: 20, Target: NewMoney(250000),
+			Allocation: []RetirementAllocation{{Asset: "stocks", Share: 0.7}, {Asset: "bonds", Share: 0.3}},
+			Seed:       42,
+		}
+		simulation := SimulateRetirement(req)
+
+		assert.Equal(t, DefaultRetirementIterations, simulation.Iterations)
+		assert.InDelta(t, 0.7*0.07+0.3*0.03, simulation.ExpectedReturn, 1e-9)
+		require.NotNil(t, simulation.Allocation[0].Volatility)
+		assert.Equal(t, 0.15, *simulation.Allocation[0].Volatility, "defaults are filled in")
+		final := simulation.Bands[19]
+		assert.Equal(t, NewMoney(130000), final.Contributed)
+		assert.True(t, final.P10 < final.P25 && final.P25 < final.P50 && final.P50 < final.P75 && final.P75 < final.P90)
+		assert.Greater(t, final.P50, final.Contributed, "the median run grows beyond what was paid in")
+		assert.Greater(t, simulation.TargetProbability, 0.0)
+		assert.Less(t, simulation.TargetProbability, 1.0)
+
+		assert.Equal(t, simulation, SimulateRetirement(req), "a seed repeats the runs")
+	})
+}
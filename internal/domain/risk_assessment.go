@@ -0,0 +1,175 @@
+package domain
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+// RiskOption is one answer to a risk question and the points it scores
+type RiskOption struct {
+	ID    string `json:"id" yaml:"id"`
+	Text  string `json:"text" yaml:"text"`
+	Score int    `json:"score" yaml:"score"`
+}
+
+// RiskQuestion is a question of the risk questionnaire
+type RiskQuestion struct {
+	ID      string       `json:"id" yaml:"id"`
+	Text    string       `json:"text" yaml:"text"`
+	Options []RiskOption `json:"options" yaml:"options"`
+}
+
+// RiskQuestionnaire asks the questions that decide a user's risk tolerance.
+// Scores up to a third of the maximum are conservative, up to two thirds
+// moderate and anything above aggressive.
+type RiskQuestionnaire struct {
+	Version   string         `json:"version" yaml:"version"`
+	Questions []RiskQuestion `json:"questions" yaml:"questions"`
+}
+
+// DefaultRiskQuestionnaire is served unless another questionnaire is configured
+var DefaultRiskQuestionnaire = RiskQuestionnaire{
+	Version: "2024-1",
+	Questions: []RiskQuestion{
+		{
+			ID:   "time_horizon",
+			Text: "What is your investment time horizon?",
+			Options: []RiskOption{
+				{ID: "a", Text: "Less than 3 years", Score: 0},
+				{ID: "b", Text: "3-10 years", Score: 1},
+				{ID: "c", Text: "More than 10 years", Score: 2},
+			},
+		},
+		{
+			ID:   "loss_reaction",
+			Text: "How would you react to a 20% portfolio loss?",
+			Options: []RiskOption{
+				{ID: "a", Text: "Sell everything immediately", Score: 0},
+				{ID: "b", Text: "Hold and wait for recovery", Score: 1},
+				{ID: "c", Text: "Buy more at lower prices", Score: 2},
+			},
+		},
+		{
+			ID:   "investment_goal",
+			Text: "What is your primary investment goal?",
+			Options: []RiskOption{
+				{ID: "a", Text: "Capital preservation", Score: 0},
+				{ID: "b", Text: "Steady income", Score: 1},
+				{ID: "c", Text: "Long-term growth", Score: 2},
+			},
+		},
+	},
+}
+
+// RiskAssessment is a completed questionnaire. Answers maps question IDs to
+// the chosen option IDs.
+type RiskAssessment struct {
+	ID                   uint            `gorm:"primaryKey" json:"id"`
+	UserID               uint            `gorm:"index:idx_risk_assessments_user_created,priority:1" json:"user_id"`
+	QuestionnaireVersion string          `gorm:"type:varchar(20)" json:"questionnaire_version"`
+	Answers              json.RawMessage `gorm:"type:text" json:"answers"`
+	Score                int             `json:"score"`
+	MaxScore             int             `json:"max_score"`
+	RiskProfile          string          `gorm:"type:varchar(20)" json:"risk_profile"`
+	CreatedAt            time.Time       `gorm:"index:idx_risk_assessments_user_created,priority:2" json:"created_at"`
+}
+
+// Validate checks that the questionnaire can be answered: every question has
+// an ID and options, and IDs are unique
+func (q *RiskQuestionnaire) Validate() error {
+	var v validator
+	v.check(q.Version != "", "version", "is required")
+	v.check(len(q.Questions) > 0, "questions", "must not be empty")
+	seen := make(map[string]bool, len(q.Questions))
+	for _, question := range q.Questions {
+		field := "questions." + question.ID
+		v.check(question.ID != "" && !seen[question.ID], field, "must have a unique ID")
+		v.check(len(question.Options) > 0, field, "must have options")
+		seen[question.ID] = true
+
+		options := make(map[string]bool, len(question.Options))
+		for _, option := range question.Options {
+			v.check(option.ID != "" && !options[option.ID], field, "must have options with unique IDs")
+			v.check(option.Score >= 0, field, "must not have negative scores")
+			options[option.ID] = true
+		}
+	}
+	return v.err()
+}
+
+// MaxScore returns the highest score the questionnaire can give
+func (q *RiskQuestionnaire) MaxScore() int {
+	total := 0
+	for _, question := range q.Questions {
+		best := 0
+		for _, option := range question.Options {
+			best = max(best, option.Score)
+		}
+		total += best
+	}
+	return total
+}
+
+// Score adds up the points of the answers, which must answer every question
+// with one of its options
+func (q *RiskQuestionnaire) Score(answers map[string]string) (int, error) {
+	var v validator
+	score := 0
+	for _, question := range q.Questions {
+		answer, answered := answers[question.ID]
+		option, valid := question.option(answer)
+		switch {
+		case !answered:
+			v.check(false, "answers."+question.ID, "is required")
+		case !valid:
+			v.check(false, "answers."+question.ID, "must be one of the question's options")
+		default:
+			score += option.Score
+		}
+	}
+	var unknown []string
+	for id := range answers {
+		if q.question(id) == nil {
+			unknown = append(unknown, id)
+		}
+	}
+	slices.Sort(unknown)
+	for _, id := range unknown {
+		v.check(false, "answers."+id, "is not a question of this questionnaire")
+	}
+	if err := v.err(); err != nil {
+		return 0, err
+	}
+	return score, nil
+}
+
+// RiskProfileFor returns the risk tolerance a score out of maxScore stands for
+func RiskProfileFor(score, maxScore int) string {
+	switch {
+	case maxScore <= 0 || score*3 <= maxScore:
+		return RiskToleranceConservative
+	case score*3 <= maxScore*2:
+		return RiskToleranceModerate
+	default:
+		return RiskToleranceAggressive
+	}
+}
+
+func (q *RiskQuestionnaire) question(id string) *RiskQuestion {
+	for i := range q.Questions {
+		if q.Questions[i].ID == id {
+			return &q.Questions[i]
+		}
+	}
+	return nil
+}
+
+func (question *RiskQuestion) option(id string) (RiskOption, bool) {
+	for _, option := range question.Options {
+		if option.ID == id {
+			return option, true
+		}
+	}
+	return RiskOption{}, false
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRiskQuestionnaire_Score(t *testing.T) {
+	q := DefaultRiskQuestionnaire
+	require.NoError(t, q.Validate())
+	assert.Equal(t, 6, q.MaxScore())
+
+	score, err := q.Score(map[string]string{"time_horizon": "b", "loss_reaction": "c", "investment_goal": "a"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, score)
+
+	tests := []struct {
+		name    string
+		answers map[string]string
+		fields  []string
+	}{
+		{"missing answer", map[string]string{"time_horizon": "a", "loss_reaction": "a"}, []string{"answers.investment_goal"}},
+		{"unknown option", map[string]string{"time_horizon": "d", "loss_reaction": "a", "investment_goal": "a"}, []string{"answers.time_horizon"}},
+		{"unknown questions", map[string]string{
+			"time_horizon": "a", "loss_reaction": "a", "investment_goal": "a", "zodiac": "leo", "age": "40",
+		}, []string{"answers.age", "answers.zodiac"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := q.Score(tt.answers)
+			assert.Equal(t, tt.fields, fieldsOf(t, err))
+		})
+	}
+}
+
+func TestRiskQuestionnaire_Validate(t *testing.T) {
+	q := RiskQuestionnaire{Questions: []RiskQuestion{
+		{ID: "horizon", Options: []RiskOption{{ID: "a"}, {ID: "a", Score: -1}}},
+		{ID: "horizon"},
+	}}
+	assert.Equal(t, []string{
+		"version",
+		"questions.horizon", "questions.horizon",
+		"questions.horizon", "questions.horizon",
+	}, fieldsOf(t, q.Validate()))
+}
+
+func TestRiskProfileFor(t *testing.T) {
+	assert.Equal(t, RiskToleranceConservative, RiskProfileFor(0, 6))
+	assert.Equal(t, RiskToleranceConservative, RiskProfileFor(2, 6))
+	assert.Equal(t, RiskToleranceModerate, RiskProfileFor(3, 6))
+	assert.Equal(t, RiskToleranceModerate, RiskProfileFor(4, 6))
+	assert.Equal(t, RiskToleranceAggressive, RiskProfileFor(5, 6))
+	assert.Equal(t, RiskToleranceConservative, RiskProfileFor(0, 0))
+}
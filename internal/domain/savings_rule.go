@@ -0,0 +1,82 @@
+package domain
+
+import "time"
+
+// SavingsRule sets aside a percentage of a user's income for one of their
+// goals as the income is recorded, e.g. 10% of every salary for a house
+// deposit. Rules with a CategoryID only apply to income in that category.
+type SavingsRule struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	UserID     uint      `gorm:"index;not null" json:"user_id"`
+	GoalID     uint      `gorm:"index;not null" json:"goal_id"`
+	CategoryID *uint     `json:"category_id,omitempty"`
+	Name       string    `gorm:"type:varchar(100)" json:"name"`
+	Percent    float64   `gorm:"not null" json:"percent"`
+	IsActive   bool      `gorm:"not null" json:"is_active"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Validate checks that the rule names a goal and a percentage of at most 100
+func (r *SavingsRule) Validate() error {
+	var v validator
+	v.check(r.GoalID != 0, "goal_id", "is required")
+	v.check(r.Percent > 0 && r.Percent <= 100, "percent", "must be greater than 0 and at most 100")
+	v.check(len([]rune(r.Name)) <= 100, "name", "must be at most 100 characters")
+	return v.err()
+}
+
+// Applies reports whether the rule sets aside part of the transaction
+func (r *SavingsRule) Applies(transaction *Transaction) bool {
+	if !r.IsActive || transaction.Type != TransactionTypeIncome || transaction.Amount <= 0 {
+		return false
+	}
+	return r.CategoryID == nil || *r.CategoryID == transaction.CategoryID
+}
+
+// SavingsAllocation is the part of an income transaction a rule set aside
+// for a goal. It is added to the goal's saved amount when the income is
+// recorded, and taken out again when the income is deleted.
+type SavingsAllocation struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	UserID        uint      `gorm:"index;not null" json:"user_id"`
+	RuleID        uint      `gorm:"uniqueIndex:idx_savings_allocations_rule_transaction,priority:1" json:"rule_id"`
+	TransactionID uint      `gorm:"uniqueIndex:idx_savings_allocations_rule_transaction,priority:2;index" json:"transaction_id"`
+	GoalID        uint      `gorm:"index;not null" json:"goal_id"`
+	Amount        Money     `gorm:"not null" json:"amount"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// SavingsPreview is what the active rules would set aside from an income
+// amount. Remaining is the part left after the allocations.
+type SavingsPreview struct {
+	Amount      Money               `json:"amount"`
+	Allocations []SavingsAllocation `json:"allocations"`
+	Allocated   Money               `json:"allocated"`
+	Remaining   Money               `json:"remaining"`
+}
+
+// AllocateSavings splits the transaction's amount between the rules that
+// apply to it, rounding each allocation to the cent
+func AllocateSavings(rules []SavingsRule, transaction *Transaction) SavingsPreview {
+	preview := SavingsPreview{Amount: transaction.Amount, Allocations: []SavingsAllocation{}}
+	for i := range rules {
+		if !rules[i].Applies(transaction) {
+			continue
+		}
+		amount := transaction.Amount.Mul(rules[i].Percent / 100)
+		if amount <= 0 {
+			continue
+		}
+		preview.Allocations = append(preview.Allocations, SavingsAllocation{
+			UserID:        transaction.UserID,
+			RuleID:        rules[i].ID,
+			TransactionID: transaction.ID,
+			GoalID:        rules[i].GoalID,
+			Amount:        amount,
+		})
+		preview.Allocated += amount
+	}
+	preview.Remaining = preview.Amount - preview.Allocated
+	return preview
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSavingsRule_Validate(t *testing.T) {
+	assert.NoError(t, (&SavingsRule{GoalID: 1, Percent: 10}).Validate())
+	assert.ErrorIs(t, (&SavingsRule{Percent: 10}).Validate(), ErrValidation)
+	assert.ErrorIs(t, (&SavingsRule{GoalID: 1}).Validate(), ErrValidation)
+	assert.ErrorIs(t, (&SavingsRule{GoalID: 1, Percent: 100.5}).Validate(), ErrValidation)
+}
+
+func TestAllocateSavings(t *testing.T) {
+	salary := uint(3)
+	rules := []SavingsRule{
+		{ID: 1, GoalID: 10, Percent: 10, IsActive: true},
+		{ID: 2, GoalID: 20, Percent: 5, IsActive: true, CategoryID: &salary},
+		{ID: 3, GoalID: 30, Percent: 50, IsActive: false},
+	}
+
+	income := &Transaction{ID: 7, UserID: 1, CategoryID: salary, Type: TransactionTypeIncome, Amount: NewMoney(1234.55)}
+	preview := AllocateSavings(rules, income)
+	assert.Equal(t, []SavingsAllocation{
+		{UserID: 1, RuleID: 1, TransactionID: 7, GoalID: 10, Amount: NewMoney(123.46)},
+		{UserID: 1, RuleID: 2, TransactionID: 7, GoalID: 20, Amount: NewMoney(61.73)},
+	}, preview.Allocations)
+	assert.Equal(t, NewMoney(185.19), preview.Allocated)
+	assert.Equal(t, NewMoney(1049.36), preview.Remaining)
+
+	preview = AllocateSavings(rules, &Transaction{UserID: 1, CategoryID: 4, Type: TransactionTypeIncome, Amount: NewMoney(100)})
+	assert.Len(t, preview.Allocations, 1, "category rules only apply to their category")
+
+	preview = AllocateSavings(rules, &Transaction{UserID: 1, CategoryID: salary, Type: TransactionTypeExpense, Amount: NewMoney(100)})
+	assert.Empty(t, preview.Allocations)
+	assert.Equal(t, NewMoney(100), preview.Remaining)
+}
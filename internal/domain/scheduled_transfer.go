@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// Scheduled transfer frequencies. A once transfer is made on its start date
+// only; the others repeat from it.
+const (
+	TransferFrequencyOnce      = "once"
+	TransferFrequencyWeekly    = PeriodWeekly
+	TransferFrequencyMonthly   = PeriodMonthly
+	TransferFrequencyQuarterly = PeriodQuarterly
+	TransferFrequencyYearly    = PeriodYearly
+)
+
+// TransferFrequencies lists the frequencies a scheduled transfer can have
+var TransferFrequencies = []string{
+	TransferFrequencyOnce, TransferFrequencyWeekly, TransferFrequencyMonthly,
+	TransferFrequencyQuarterly, TransferFrequencyYearly,
+}
+
+// MaxTransferCatchUp is how many missed occurrences of a scheduled transfer
+// one run makes at most, so a schedule left behind cannot flood the accounts
+const MaxTransferCatchUp = 12
+
+// ScheduledTransfer moves Amount from one of a user's accounts to another on
+// StartDate and, unless it is a once transfer, every week, month, quarter or
+// year after it until EndDate. Monthly dates past the end of a short month
+// fall on its last day. Occurrences counts the transfers made so far and
+// NextDate is when the next one is due, nil once the schedule has ended.
+type ScheduledTransfer struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	UserID        uint       `gorm:"not null;index" json:"user_id"`
+	FromAccountID uint       `gorm:"not null;index" json:"from_account_id"`
+	ToAccountID   uint       `gorm:"not null;index" json:"to_account_id"`
+	Amount        Money      `gorm:"type:integer;not null" json:"amount"`
+	Description   string     `gorm:"type:varchar(255);not null" json:"description"`
+	Frequency     string     `gorm:"type:varchar(20);not null" json:"frequency"`
+	StartDate     time.Time  `gorm:"not null" json:"start_date"`
+	EndDate       *time.Time `json:"end_date,omitempty"`
+	NextDate      *time.Time `gorm:"index" json:"next_date,omitempty"`
+	Occurrences   int        `gorm:"not null;default:0" json:"occurrences"`
+	Active        bool       `gorm:"not null;default:true" json:"active"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// Validate checks the accounts, amount, description, frequency and dates
+func (s *ScheduledTransfer) Validate() error {
+	var v validator
+	s.Description = strings.TrimSpace(s.Description)
+	v.check(s.FromAccountID != 0, "from_account_id", "is required")
+	v.check(s.ToAccountID != 0, "to_account_id", "is required")
+	v.check(s.FromAccountID == 0 || s.FromAccountID != s.ToAccountID, "to_account_id", "must be another account than from_account_id")
+	v.check(s.Amount > 0, "amount", "must be positive")
+	v.check(s.Description != "", "description", "is required")
+	v.check(len(s.Description) <= maxDescriptionLength, "description", "must be at most 255 characters")
+	v.check(slices.Contains(TransferFrequencies, s.Frequency), "frequency", "must be once, weekly, monthly, quarterly or yearly")
+	v.check(!s.StartDate.Before(earliestDate), "start_date", "must be after 1900-01-01")
+	v.check(s.EndDate == nil || !s.EndDate.Before(s.StartDate), "end_date", "must be on or after the start date")
+	return v.err()
+}
+
+// OccurrenceDate is the date of the nth transfer, counting from zero. Each
+// is counted from the start date rather than the one before, so a transfer
+// started on the 31st comes back on the 31st after a short month.
+func (s *ScheduledTransfer) OccurrenceDate(n int) time.Time {
+	start := time.Date(s.StartDate.Year(), s.StartDate.Month(), s.StartDate.Day(), 0, 0, 0, 0, s.StartDate.Location())
+	months := 0
+	switch s.Frequency {
+	case TransferFrequencyWeekly:
+		return start.AddDate(0, 0, 7*n)
+	case TransferFrequencyMonthly:
+		months = n
+	case TransferFrequencyQuarterly:
+		months = 3 * n
+	case TransferFrequencyYearly:
+		months = 12 * n
+	}
+	first := time.Date(start.Year(), start.Month()+time.Month(months), 1, 0, 0, 0, 0, start.Location())
+	return dayOfMonth(first.Year(), first.Month(), start.Day(), start.Location())
+}
+
+// Schedule sets NextDate to the date of the transfer after the ones made so
+// far, or nil when the schedule has ended
+func (s *ScheduledTransfer) Schedule() {
+	s.NextDate = nil
+	if s.Frequency == TransferFrequencyOnce && s.Occurrences > 0 {
+		return
+	}
+	next := s.OccurrenceDate(s.Occurrences)
+	if s.EndDate != nil && next.After(*s.EndDate) {
+		return
+	}
+	s.NextDate = &next
+}
+
+// Transfer is the transfer the schedule makes on a date
+func (s *ScheduledTransfer) Transfer(date time.Time) Transaction {
+	from, to, id := s.FromAccountID, s.ToAccountID, s.ID
+	return Transaction{
+		UserID:              s.UserID,
+		Type:                TransactionTypeTransfer,
+		AccountID:           &from,
+		TransferAccountID:   &to,
+		ScheduledTransferID: &id,
+		Amount:              s.Amount,
+		Description:         s.Description,
+		Date:                date,
+	}
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduledTransfer_Validate(t *testing.T) {
+	start := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+	valid := ScheduledTransfer{
+		FromAccountID: 1, ToAccountID: 2, Amount: NewMoney(500), Description: " Savings ",
+		Frequency: TransferFrequencyMonthly, StartDate: start,
+	}
+	require.NoError(t, valid.Validate())
+	assert.Equal(t, "Savings", valid.Description)
+
+	before := start.AddDate(0, 0, -1)
+	invalid := ScheduledTransfer{
+		FromAccountID: 1, ToAccountID: 1, Frequency: "daily", StartDate: start, EndDate: &before,
+	}
+	err := invalid.Validate()
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	fields := make([]string, 0, len(validationErr.Fields))
+	for _, field := range validationErr.Fields {
+		fields = append(fields, field.Field)
+	}
+	assert.ElementsMatch(t, []string{"to_account_id", "amount", "description", "frequency", "end_date"}, fields)
+}
+
+func TestScheduledTransfer_OccurrenceDate(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name      string
+		frequency string
+		start     time.Time
+		n         int
+		want      time.Time
+	}{
+		{"first", TransferFrequencyMonthly, time.Date(2025, 1, 31, 9, 30, 0, 0, time.UTC), 0, date(2025, 1, 31)},
+		{"end of a short month", TransferFrequencyMonthly, date(2025, 1, 31), 1, date(2025, 2, 28)},
+		{"back on the 31st", TransferFrequencyMonthly, date(2025, 1, 31), 2, date(2025, 3, 31)},
+		{"weekly", TransferFrequencyWeekly, date(2025, 1, 31), 2, date(2025, 2, 14)},
+		{"quarterly", TransferFrequencyQuarterly, date(2025, 11, 30), 1, date(2026, 2, 28)},
+		{"yearly from a leap day", TransferFrequencyYearly, date(2024, 2, 29), 1, date(2025, 2, 28)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transfer := ScheduledTransfer{Frequency: tt.frequency, StartDate: tt.start}
+			assert.Equal(t, tt.want, transfer.OccurrenceDate(tt.n))
+		})
+	}
+}
+
+func TestScheduledTransfer_Schedule(t *testing.T) {
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	once := ScheduledTransfer{Frequency: TransferFrequencyOnce, StartDate: start}
+	once.Schedule()
+	require.NotNil(t, once.NextDate)
+	assert.Equal(t, start, *once.NextDate)
+	once.Occurrences = 1
+	once.Schedule()
+	assert.Nil(t, once.NextDate, "a once transfer ends after it was made")
+
+	end := start.AddDate(0, 1, 0)
+	monthly := ScheduledTransfer{Frequency: TransferFrequencyMonthly, StartDate: start, EndDate: &end, Occurrences: 1}
+	monthly.Schedule()
+	require.NotNil(t, monthly.NextDate)
+	assert.Equal(t, end, *monthly.NextDate, "the end date is the last transfer's")
+	monthly.Occurrences = 2
+	monthly.Schedule()
+	assert.Nil(t, monthly.NextDate)
+}
+
+func TestScheduledTransfer_Transfer(t *testing.T) {
+	schedule := ScheduledTransfer{
+		ID: 9, UserID: 4, FromAccountID: 1, ToAccountID: 2, Amount: NewMoney(500), Description: "Savings",
+		Frequency: TransferFrequencyMonthly, StartDate: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	transfer := schedule.Transfer(schedule.StartDate)
+
+	assert.True(t, transfer.IsTransfer())
+	assert.Equal(t, uint(1), *transfer.AccountID)
+	assert.Equal(t, uint(2), *transfer.TransferAccountID)
+	assert.Equal(t, uint(9), *transfer.ScheduledTransferID)
+	assert.Equal(t, schedule.StartDate, transfer.Date)
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrInvalidSession is returned for refresh tokens and access tokens whose
+// session is unknown, expired or revoked
+var ErrInvalidSession = errors.New("session is expired or revoked")
+
+// maxUserAgentLength is how much of a user agent a session keeps
+const maxUserAgentLength = 255
+
+// Session is a login on one device. Access tokens name the session they were
+// issued for, so revoking it signs the device out; the refresh token renews
+// it until ExpiresAt. Only the SHA-256 hash of the refresh token is stored.
+type Session struct {
+	ID               uint       `gorm:"primaryKey" json:"id"`
+	UserID           uint       `gorm:"index;not null" json:"user_id"`
+	RefreshTokenHash string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	UserAgent        string     `gorm:"type:varchar(255)" json:"user_agent"`
+	IPAddress        string     `gorm:"type:varchar(45)" json:"ip_address"`
+	CreatedAt        time.Time  `json:"created_at"`
+	LastUsedAt       time.Time  `json:"last_used_at"`
+	ExpiresAt        time.Time  `json:"expires_at"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+	Current          bool       `gorm:"-" json:"current"` // Set for the session of the request listing sessions
+}
+
+// Active reports whether the session can still be used at the time
+func (s Session) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// Device describes where a session is used from
+type Device struct {
+	UserAgent string
+	IPAddress string
+}
+
+// Apply records the device as the one the session was last used from
+func (d Device) Apply(session *Session) {
+	session.UserAgent = d.UserAgent
+	if runes := []rune(session.UserAgent); len(runes) > maxUserAgentLength {
+		session.UserAgent = string(runes[:maxUserAgentLength])
+	}
+	session.IPAddress = d.IPAddress
+}
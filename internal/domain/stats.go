@@ -0,0 +1,71 @@
+package domain
+
+import "time"
+
+// StatsMinCategoryUsers is how many users must have used a category name on
+// a day before stats show it, so names that users made up do not point to them
+const StatsMinCategoryUsers = 3
+
+// RequestActivity counts a user's API requests on one UTC day by how they
+// were answered. Requests without a logged in user count for user 0. It is
+// the raw material of DailyStats and only kept for a few days.
+type RequestActivity struct {
+	UserID       uint      `gorm:"primaryKey" json:"user_id"`
+	Day          time.Time `gorm:"primaryKey" json:"day"`
+	Requests     int       `gorm:"not null;default:0" json:"requests"`
+	ClientErrors int       `gorm:"not null;default:0" json:"client_errors"`
+	ServerErrors int       `gorm:"not null;default:0" json:"server_errors"`
+}
+
+// DailyStats are anonymous aggregates of how the service was used on one
+// UTC day: users, the transactions they added, advisor calls and how
+// requests were answered. The error rates are the shares of requests
+// answered with 4xx and 5xx.
+type DailyStats struct {
+	Day             time.Time       `gorm:"primaryKey" json:"day"`
+	TotalUsers      int             `gorm:"not null;default:0" json:"total_users"`
+	NewUsers        int             `gorm:"not null;default:0" json:"new_users"`
+	ActiveUsers     int             `gorm:"not null;default:0" json:"active_users"`
+	Transactions    int             `gorm:"not null;default:0" json:"transactions"`
+	AdvisorCalls    int             `gorm:"not null;default:0" json:"advisor_calls"`
+	Requests        int             `gorm:"not null;default:0" json:"requests"`
+	ClientErrors    int             `gorm:"not null;default:0" json:"client_errors"`
+	ServerErrors    int             `gorm:"not null;default:0" json:"server_errors"`
+	ClientErrorRate float64         `gorm:"not null;default:0" json:"client_error_rate"`
+	ServerErrorRate float64         `gorm:"not null;default:0" json:"server_error_rate"`
+	TopCategories   []CategoryUsage `gorm:"serializer:json;type:text" json:"top_categories"`
+	ComputedAt      time.Time       `json:"computed_at"`
+}
+
+// CategoryUsage is how many transactions were added in categories of a name
+type CategoryUsage struct {
+	Name         string `json:"name"`
+	Transactions int    `json:"transactions"`
+}
+
+// StatsFilter narrows the days of a stats report; both ends are inclusive
+type StatsFilter struct {
+	From *time.Time
+	To   *time.Time
+}
+
+// StatsReport lists the daily stats of a range, oldest first, with their totals
+type StatsReport struct {
+	From   time.Time    `json:"from"`
+	To     time.Time    `json:"to"`
+	Days   []DailyStats `json:"days"`
+	Totals StatsTotals  `json:"totals"`
+}
+
+// StatsTotals sums the daily stats of a report. Active users are averaged
+// over the days, since many of them are active on more than one.
+type StatsTotals struct {
+	NewUsers           int             `json:"new_users"`
+	AverageActiveUsers float64         `json:"average_active_users"`
+	Transactions       int             `json:"transactions"`
+	AdvisorCalls       int             `json:"advisor_calls"`
+	Requests           int             `json:"requests"`
+	ClientErrorRate    float64         `json:"client_error_rate"`
+	ServerErrorRate    float64         `json:"server_error_rate"`
+	TopCategories      []CategoryUsage `json:"top_categories"`
+}
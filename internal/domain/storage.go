@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrInvalidFileLink is returned for download links whose signature does
+	// not match or that have expired
+	ErrInvalidFileLink = errors.New("file link is invalid or has expired")
+	// ErrStorageUnavailable is returned when no file storage is configured
+	ErrStorageUnavailable = errors.New("file storage is not available")
+)
+
+// FileLink is a signed URL a stored file can be downloaded from directly,
+// without authenticating, until ExpiresAt
+type FileLink struct {
+	URL       string    `json:"url"`
+	FileName  string    `json:"file_name"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Entities offline clients sync
+const SyncEntityTransaction = "transaction"
+
+// Change operations in the sync feed
+const (
+	SyncOperationUpsert = "upsert"
+	SyncOperationDelete = "delete"
+)
+
+// Mutations offline clients push
+const (
+	SyncMutationCreate = "create"
+	SyncMutationUpdate = "update"
+	SyncMutationDelete = "delete"
+)
+
+// Outcomes of pushed mutations
+const (
+	// SyncStatusApplied mutations were saved
+	SyncStatusApplied = "applied"
+	// SyncStatusSkipped mutations lost a conflict to a later server change
+	SyncStatusSkipped = "skipped"
+	// SyncStatusRejected mutations broke a business rule or named a
+	// transaction the user does not have
+	SyncStatusRejected = "rejected"
+)
+
+// How conflicts were resolved: the later write wins
+const (
+	SyncResolutionClientWins = "client_wins"
+	SyncResolutionServerWins = "server_wins"
+)
+
+// Sync page and push limits
+const (
+	DefaultSyncPageSize = 100
+	MaxSyncPageSize     = 500
+	MaxSyncMutations    = 100
+)
+
+// SyncChange is one entry of a user's change feed. Its ID is the cursor
+// clients resume from, and Version counts the changes of the entity, so
+// every saved create, edit, delete and restore of a transaction adds one.
+type SyncChange struct {
+	ID         uint      `gorm:"primaryKey" json:"cursor"`
+	UserID     uint      `gorm:"index;not null" json:"-"`
+	EntityType string    `gorm:"type:varchar(30);uniqueIndex:idx_sync_changes_entity_version,priority:1;not null" json:"entity_type"`
+	EntityID   uint      `gorm:"uniqueIndex:idx_sync_changes_entity_version,priority:2;not null" json:"entity_id"`
+	Version    int64     `gorm:"uniqueIndex:idx_sync_changes_entity_version,priority:3;not null" json:"version"`
+	Operation  string    `gorm:"type:varchar(10);not null" json:"operation"`
+	CreatedAt  time.Time `json:"changed_at"`
+	// Transaction is the current state of an upserted transaction
+	Transaction *Transaction `gorm:"-" json:"transaction,omitempty"`
+}
+
+// SyncChanges is a page of the change feed. Each entity appears once, with
+// its latest change on the page; clients store Cursor and pass it as since
+// to fetch what changed next, and keep fetching while HasMore holds.
+type SyncChanges struct {
+	Changes []SyncChange `json:"changes"`
+	Cursor  uint         `json:"cursor"`
+	HasMore bool         `json:"has_more"`
+}
+
+// SyncMutation is a change a client made offline. ClientID names it so a
+// push that is retried does not create a transaction twice; BaseVersion is
+// the version the client last saw of the transaction and ModifiedAt when
+// the change was made, which decides conflicts.
+type SyncMutation struct {
+	ClientID    string       `json:"client_id"`
+	Operation   string       `json:"operation"`
+	EntityID    uint         `json:"entity_id,omitempty"`
+	BaseVersion int64        `json:"base_version"`
+	ModifiedAt  time.Time    `json:"modified_at"`
+	Transaction *Transaction `json:"transaction,omitempty"`
+}
+
+// Validate checks the mutation's operation, client ID and transaction
+func (m *SyncMutation) Validate() error {
+	var v validator
+	switch m.Operation {
+	case SyncMutationCreate:
+		v.check(m.ClientID != "", "client_id", "is required to create a transaction")
+		v.check(m.Transaction != nil, "transaction", "is required")
+	case SyncMutationUpdate:
+		v.check(m.EntityID != 0, "entity_id", "is required")
+		v.check(m.Transaction != nil, "transaction", "is required")
+	case SyncMutationDelete:
+		v.check(m.EntityID != 0, "entity_id", "is required")
+	default:
+		v.check(false, "operation", "must be create, update or delete")
+	}
+	v.check(len(m.ClientID) <= 100, "client_id", "must be at most 100 characters")
+	return v.err()
+}
+
+// SyncMutationResult is the outcome of one pushed mutation. Transaction is
+// the server's state of the transaction afterwards, Version its version and
+// ConflictID the conflict record, if the mutation met a change it had not seen.
+type SyncMutationResult struct {
+	ClientID    string       `json:"client_id,omitempty"`
+	Operation   string       `json:"operation"`
+	Status      string       `json:"status"`
+	EntityID    uint         `json:"entity_id,omitempty"`
+	Version     int64        `json:"version,omitempty"`
+	Transaction *Transaction `json:"transaction,omitempty"`
+	ConflictID  *uint        `json:"conflict_id,omitempty"`
+	Error       string       `json:"error,omitempty"`
+}
+
+// SyncPushResult lists the outcome of each pushed mutation in order. The
+// applied mutations also show up in the change feed, so clients keep pulling
+// from their own cursor and do not miss what other devices changed meanwhile.
+type SyncPushResult struct {
+	Results []SyncMutationResult `json:"results"`
+}
+
+// SyncMutationClient maps the client ID of a created transaction to it
+type SyncMutationClient struct {
+	UserID    uint   `gorm:"primaryKey"`
+	ClientID  string `gorm:"primaryKey;type:varchar(100)"`
+	EntityID  uint   `gorm:"not null"`
+	CreatedAt time.Time
+}
+
+// SyncConflict records a pushed mutation that was based on an older version
+// than the server's. The later of the two writes won; ClientData and
+// ServerData keep both sides so nothing is lost for good.
+type SyncConflict struct {
+	ID               uint            `gorm:"primaryKey" json:"id"`
+	UserID           uint            `gorm:"index;not null" json:"user_id"`
+	EntityType       string          `gorm:"type:varchar(30);not null" json:"entity_type"`
+	EntityID         uint            `gorm:"not null" json:"entity_id"`
+	Operation        string          `gorm:"type:varchar(10);not null" json:"operation"`
+	BaseVersion      int64           `json:"base_version"`
+	ServerVersion    int64           `json:"server_version"`
+	Resolution       string          `gorm:"type:varchar(20);not null" json:"resolution"`
+	ClientModifiedAt time.Time       `json:"client_modified_at"`
+	ServerModifiedAt time.Time       `json:"server_modified_at"`
+	ClientData       json.RawMessage `gorm:"type:text" json:"client_data,omitempty"`
+	ServerData       json.RawMessage `gorm:"type:text" json:"server_data,omitempty"`
+	CreatedAt        time.Time       `gorm:"index" json:"created_at"`
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncMutation_Validate(t *testing.T) {
+	transaction := &Transaction{Description: "Bakery"}
+	assert.NoError(t, (&SyncMutation{ClientID: "a", Operation: SyncMutationCreate, Transaction: transaction}).Validate())
+	assert.NoError(t, (&SyncMutation{Operation: SyncMutationUpdate, EntityID: 1, Transaction: transaction}).Validate())
+	assert.NoError(t, (&SyncMutation{Operation: SyncMutationDelete, EntityID: 1}).Validate())
+
+	tests := []struct {
+		name     string
+		mutation SyncMutation
+		want     []string
+	}{
+		{"create without client ID or transaction", SyncMutation{Operation: SyncMutationCreate}, []string{"client_id", "transaction"}},
+		{"update without entity", SyncMutation{Operation: SyncMutationUpdate, Transaction: transaction}, []string{"entity_id"}},
+		{"delete without entity", SyncMutation{Operation: SyncMutationDelete}, []string{"entity_id"}},
+		{"unknown operation", SyncMutation{Operation: "merge", EntityID: 1}, []string{"operation"}},
+		{
+			"long client ID",
+			SyncMutation{ClientID: strings.Repeat("a", 101), Operation: SyncMutationDelete, EntityID: 1},
+			[]string{"client_id"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, fieldsOf(t, tt.mutation.Validate()))
+		})
+	}
+}
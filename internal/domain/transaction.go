@@ -2,19 +2,167 @@
 // for the Go Finance Advisor application.
 package domain
 
-import "time"
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
 
 // Transaction represents a financial transaction for a user
-// Type can be "income" or "expense"
+// Type can be "income", "expense" or "transfer". Transactions with a
+// HouseholdID are shared with that household's members, and those with an
+// AccountID moved money in or out of that account. A transfer moves Amount
+// from AccountID to TransferAccountID; it is neither income nor expense.
+// Deleting a transaction only sets DeletedAt; it stays in the trash until it
+// is restored or purged.
 type Transaction struct {
-	ID          uint      `gorm:"primaryKey" json:"id"`
-	UserID      uint      `json:"user_id"`
-	CategoryID  uint      `json:"category_id"`
-	Category    Category  `gorm:"foreignKey:CategoryID" json:"category"`
-	Type        string    `gorm:"type:varchar(10);default:'expense'" json:"type"`
-	Description string    `json:"description"`
-	Amount      float64   `json:"amount"`
-	Date        time.Time `json:"date"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	UserID      uint           `gorm:"index:idx_transactions_user_date,priority:1;index:idx_transactions_user_category_date,priority:1" json:"user_id"`
+	CategoryID  uint           `gorm:"index:idx_transactions_user_category_date,priority:2" json:"category_id"`
+	Category    Category       `gorm:"foreignKey:CategoryID" json:"category"`
+	HouseholdID *uint          `gorm:"index" json:"household_id,omitempty"` // Set when shared with a household
+	MerchantID  *uint          `gorm:"index" json:"merchant_id,omitempty"`
+	AccountID   *uint          `gorm:"index" json:"account_id,omitempty"`
+	Merchant    *Merchant      `gorm:"foreignKey:MerchantID" json:"merchant,omitempty"`
+	Type        string         `gorm:"type:varchar(10);default:'expense'" json:"type"`
+	Description string         `json:"description"`
+	Amount      Money          `json:"amount"`
+	Notes       string         `gorm:"type:text" json:"notes,omitempty"`
+	Date        time.Time      `gorm:"index:idx_transactions_user_date,priority:2;index:idx_transactions_user_category_date,priority:3" json:"date"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+	// Currency, OriginalAmount and ExchangeRate are only set for transactions
+	// made in another currency than BaseCurrency. Amount is then the original
+	// amount converted at the rate of the transaction's date.
+	Currency       string   `gorm:"type:varchar(3)" json:"currency,omitempty"`
+	OriginalAmount *Money   `json:"original_amount,omitempty"`
+	ExchangeRate   *float64 `json:"exchange_rate,omitempty"`
+	// Latitude and Longitude are where the transaction was made, as mobile
+	// clients report it, and Place and City name the spot
+	Latitude  *float64 `json:"latitude,omitempty"`
+	Longitude *float64 `json:"longitude,omitempty"`
+	Place     string   `gorm:"type:varchar(100)" json:"place,omitempty"`
+	City      string   `gorm:"type:varchar(100)" json:"city,omitempty"`
+	// IncomeSourceID links pay to the income source it came from
+	IncomeSourceID *uint `gorm:"index" json:"income_source_id,omitempty"`
+	// TransferAccountID is the account a transfer credits, and
+	// ScheduledTransferID the scheduled transfer that made it, if any
+	TransferAccountID   *uint `gorm:"index" json:"transfer_account_id,omitempty"`
+	ScheduledTransferID *uint `gorm:"index" json:"scheduled_transfer_id,omitempty"`
+	// Tags are stored in transaction_tags; lists only fill them when included
+	Tags []string `gorm:"-" json:"tags,omitempty"`
+	// Items are stored in transaction_items; a single transaction has them
+	// filled in, lists only when included
+	Items []TransactionItem `gorm:"-" json:"items,omitempty"`
+	// AttachmentCount and RunningBalance are only filled for lists that include them
+	AttachmentCount *int   `gorm:"-" json:"attachment_count,omitempty"`
+	RunningBalance  *Money `gorm:"-" json:"running_balance,omitempty"`
+	// CommentCount is only filled for lists
+	CommentCount *int `gorm:"-" json:"comment_count,omitempty"`
+	// CapWarnings lists the spending caps a new transaction took over
+	CapWarnings []CategoryCapStatus `gorm:"-" json:"cap_warnings,omitempty"`
+	// CategorySuggestion is set when a new transaction's category was suggested
+	CategorySuggestion *CategorySuggestion `gorm:"-" json:"category_suggestion,omitempty"`
+}
+
+// Signed returns the amount with expenses negative, as it moves a balance.
+// Transfers move money between the user's own accounts, so they are zero.
+func (t *Transaction) Signed() Money {
+	switch t.Type {
+	case TransactionTypeExpense:
+		return -t.Amount
+	case TransactionTypeTransfer:
+		return 0
+	}
+	return t.Amount
+}
+
+// SignedFor returns the amount as it moves the balance of one account:
+// transfers are negative on the account they leave, positive on the one
+// they reach and zero on any other. Other transactions are signed as by
+// Signed, the caller having picked those on the account.
+func (t *Transaction) SignedFor(accountID uint) Money {
+	if t.Type != TransactionTypeTransfer {
+		return t.Signed()
+	}
+	switch {
+	case t.TransferAccountID != nil && *t.TransferAccountID == accountID:
+		return t.Amount
+	case t.AccountID != nil && *t.AccountID == accountID:
+		return -t.Amount
+	}
+	return 0
+}
+
+// IsTransfer reports whether the transaction moved money between two of the
+// user's accounts
+func (t *Transaction) IsTransfer() bool {
+	return t.Type == TransactionTypeTransfer
+}
+
+// TransactionTag is a free-form label on a transaction, e.g. "vacation"
+type TransactionTag struct {
+	TransactionID uint   `gorm:"primaryKey"`
+	Tag           string `gorm:"primaryKey;type:varchar(50);index"`
+}
+
+// NormalizeTags lower-cases and trims tags, dropping empty and repeated ones.
+// A nil slice stays nil, so updates can tell "no change" from "no tags".
+func NormalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
+}
+
+// Transaction list includes, given as a comma-separated include parameter
+const (
+	TransactionIncludeCategory       = "category" // Always embedded; accepted so clients can ask for it
+	TransactionIncludeAttachments    = "attachments"
+	TransactionIncludeTags           = "tags"
+	TransactionIncludeItems          = "items"
+	TransactionIncludeRunningBalance = "running_balance"
+)
+
+// ErrInvalidInclude is returned for include values lists do not support
+var ErrInvalidInclude = errors.New("include must list category, attachments, tags, items or running_balance")
+
+// TransactionIncludes selects the per-row details a transaction list embeds
+type TransactionIncludes struct {
+	Attachments    bool
+	Tags           bool
+	Items          bool
+	RunningBalance bool
+}
+
+// ParseTransactionIncludes reads a comma-separated include parameter
+func ParseTransactionIncludes(include string) (TransactionIncludes, error) {
+	var includes TransactionIncludes
+	for _, name := range strings.Split(include, ",") {
+		switch strings.TrimSpace(name) {
+		case "", TransactionIncludeCategory:
+		case TransactionIncludeAttachments:
+			includes.Attachments = true
+		case TransactionIncludeTags:
+			includes.Tags = true
+		case TransactionIncludeItems:
+			includes.Items = true
+		case TransactionIncludeRunningBalance:
+			includes.RunningBalance = true
+		default:
+			return TransactionIncludes{}, ErrInvalidInclude
+		}
+	}
+	return includes, nil
 }
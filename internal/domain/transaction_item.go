@@ -0,0 +1,191 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Line item limits
+const (
+	maxTransactionItems = 100
+	maxItemNameLength   = 100
+)
+
+// TransactionItem is one line of a transaction's receipt, e.g. two coffees
+// at 3.50 each. Items are entered by hand or taken from a scanned receipt;
+// Amount is the quantity times the unit price.
+type TransactionItem struct {
+	ID            uint    `gorm:"primaryKey" json:"id"`
+	TransactionID uint    `gorm:"index;not null" json:"transaction_id"`
+	Position      int     `gorm:"not null" json:"position"`
+	Name          string  `gorm:"type:varchar(100);not null" json:"name"`
+	Quantity      float64 `gorm:"not null" json:"quantity"`
+	UnitPrice     Money   `gorm:"not null" json:"unit_price"`
+	Amount        Money   `gorm:"not null" json:"amount"`
+}
+
+// NormalizeItems trims item names, numbers the items in order, counts items
+// without a quantity once and works out their amounts. A nil slice stays
+// nil, so updates can tell "no change" from "no items".
+func NormalizeItems(items []TransactionItem) []TransactionItem {
+	if items == nil {
+		return nil
+	}
+	normalized := make([]TransactionItem, len(items))
+	for i, item := range items {
+		item.ID, item.TransactionID, item.Position = 0, 0, i
+		item.Name = strings.TrimSpace(item.Name)
+		if item.Quantity == 0 {
+			item.Quantity = 1
+		}
+		item.Amount = item.UnitPrice.Mul(item.Quantity)
+		normalized[i] = item
+	}
+	return normalized
+}
+
+// validateItems checks that each item has a name, a positive quantity and
+// a price that is not negative
+func (v *validator) validateItems(items []TransactionItem) {
+	v.check(len(items) <= maxTransactionItems, "items", "must list at most 100 items")
+	for _, item := range items {
+		if item.Name == "" || len([]rune(item.Name)) > maxItemNameLength {
+			v.check(false, "items", "must each have a name of at most 100 characters")
+			break
+		}
+	}
+	for _, item := range items {
+		if item.Quantity <= 0 || item.UnitPrice < 0 {
+			v.check(false, "items", "must each have a positive quantity and a unit price of at least zero")
+			break
+		}
+	}
+}
+
+// ItemAnalysis is what a user spent on line items over a period, e.g. on
+// every item named like "coffee", by item name and by merchant
+type ItemAnalysis struct {
+	UserID        uint                   `json:"user_id"`
+	StartDate     time.Time              `json:"start_date"`
+	EndDate       time.Time              `json:"end_date"`
+	Query         string                 `json:"query,omitempty"`
+	TotalAmount   Money                  `json:"total_amount"`
+	TotalQuantity float64                `json:"total_quantity"`
+	Items         []ItemSpending         `json:"items"`
+	Merchants     []ItemMerchantSpending `json:"merchants"`
+}
+
+// ItemSpending is the spending on items of one name, ignoring case
+type ItemSpending struct {
+	Name             string    `json:"name"`
+	TotalAmount      Money     `json:"total_amount"`
+	Quantity         float64   `json:"quantity"`
+	AverageUnitPrice Money     `json:"average_unit_price"`
+	PurchaseCount    int       `json:"purchase_count"`
+	LastPurchase     time.Time `json:"last_purchase"`
+}
+
+// ItemMerchantSpending is the spending on the matching items at one
+// merchant. Items of transactions without a merchant have no MerchantID.
+type ItemMerchantSpending struct {
+	MerchantID       *uint   `json:"merchant_id,omitempty"`
+	Name             string  `json:"name"`
+	TotalAmount      Money   `json:"total_amount"`
+	Quantity         float64 `json:"quantity"`
+	AverageUnitPrice Money   `json:"average_unit_price"`
+}
+
+// ItemPurchase is a line item with the date and merchant of its transaction
+type ItemPurchase struct {
+	TransactionID uint
+	Name          string
+	Quantity      float64
+	Amount        Money
+	Date          time.Time
+	MerchantID    *uint
+	MerchantName  string
+}
+
+// itemPurchaseKey tells the purchases of an item apart
+type itemPurchaseKey struct {
+	name          string
+	transactionID uint
+}
+
+// SummarizeItems totals the purchases by item name and by merchant, highest
+// spend first. An item bought twice on one transaction counts as one purchase.
+func SummarizeItems(purchases []ItemPurchase) (items []ItemSpending, merchants []ItemMerchantSpending) {
+	byName := make(map[string]*ItemSpending)
+	byMerchant := make(map[uint]*ItemMerchantSpending)
+	counted := make(map[itemPurchaseKey]bool)
+	var unknown *ItemMerchantSpending
+	for i := range purchases {
+		purchase := &purchases[i]
+		key := strings.ToLower(purchase.Name)
+		item, exists := byName[key]
+		if !exists {
+			item = &ItemSpending{Name: purchase.Name}
+			byName[key] = item
+		}
+		item.TotalAmount += purchase.Amount
+		item.Quantity += purchase.Quantity
+		if purchased := (itemPurchaseKey{key, purchase.TransactionID}); !counted[purchased] {
+			counted[purchased] = true
+			item.PurchaseCount++
+		}
+		if purchase.Date.After(item.LastPurchase) {
+			item.LastPurchase = purchase.Date
+		}
+
+		merchant := unknown
+		if purchase.MerchantID != nil {
+			if merchant = byMerchant[*purchase.MerchantID]; merchant == nil {
+				merchant = &ItemMerchantSpending{MerchantID: purchase.MerchantID, Name: purchase.MerchantName}
+				byMerchant[*purchase.MerchantID] = merchant
+			}
+		} else if merchant == nil {
+			unknown = &ItemMerchantSpending{}
+			merchant = unknown
+		}
+		merchant.TotalAmount += purchase.Amount
+		merchant.Quantity += purchase.Quantity
+	}
+
+	items = make([]ItemSpending, 0, len(byName))
+	for _, item := range byName {
+		item.AverageUnitPrice = averageUnitPrice(item.TotalAmount, item.Quantity)
+		items = append(items, *item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].TotalAmount != items[j].TotalAmount {
+			return items[i].TotalAmount > items[j].TotalAmount
+		}
+		return items[i].Name < items[j].Name
+	})
+
+	merchants = make([]ItemMerchantSpending, 0, len(byMerchant)+1)
+	for _, merchant := range byMerchant {
+		merchants = append(merchants, *merchant)
+	}
+	if unknown != nil {
+		merchants = append(merchants, *unknown)
+	}
+	for i := range merchants {
+		merchants[i].AverageUnitPrice = averageUnitPrice(merchants[i].TotalAmount, merchants[i].Quantity)
+	}
+	sort.Slice(merchants, func(i, j int) bool {
+		if merchants[i].TotalAmount != merchants[j].TotalAmount {
+			return merchants[i].TotalAmount > merchants[j].TotalAmount
+		}
+		return merchants[i].Name < merchants[j].Name
+	})
+	return items, merchants
+}
+
+func averageUnitPrice(total Money, quantity float64) Money {
+	if quantity <= 0 {
+		return 0
+	}
+	return total.Mul(1 / quantity)
+}
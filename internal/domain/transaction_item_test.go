@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeItems(t *testing.T) {
+	assert.Nil(t, NormalizeItems(nil))
+
+	items := NormalizeItems([]TransactionItem{
+		{ID: 7, TransactionID: 3, Name: " Coffee ", Quantity: 2, UnitPrice: NewMoney(3.5)},
+		{Name: "Croissant", UnitPrice: NewMoney(2.2)},
+	})
+	require.Len(t, items, 2)
+	assert.Equal(t, TransactionItem{Name: "Coffee", Quantity: 2, UnitPrice: NewMoney(3.5), Amount: NewMoney(7)}, items[0])
+	assert.Equal(t, 1, items[1].Position)
+	assert.Equal(t, 1.0, items[1].Quantity)
+	assert.Equal(t, NewMoney(2.2), items[1].Amount)
+}
+
+func TestSummarizeItems(t *testing.T) {
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	cafe, bakery := uint(1), uint(2)
+	items, merchants := SummarizeItems([]ItemPurchase{
+		{TransactionID: 1, Name: "Coffee", Quantity: 1, Amount: NewMoney(3), Date: day, MerchantID: &cafe, MerchantName: "Cafe Luna"},
+		{TransactionID: 1, Name: "coffee", Quantity: 1, Amount: NewMoney(3), Date: day, MerchantID: &cafe, MerchantName: "Cafe Luna"},
+		{TransactionID: 2, Name: "Coffee", Quantity: 2, Amount: NewMoney(5), Date: day.AddDate(0, 0, 3),
+			MerchantID: &bakery, MerchantName: "Bakery"},
+		{TransactionID: 3, Name: "Bread", Quantity: 1, Amount: NewMoney(2.5), Date: day},
+	})
+
+	require.Len(t, items, 2)
+	assert.Equal(t, ItemSpending{
+		Name: "Coffee", TotalAmount: NewMoney(11), Quantity: 4, AverageUnitPrice: NewMoney(2.75),
+		PurchaseCount: 2, LastPurchase: day.AddDate(0, 0, 3),
+	}, items[0], "names are told apart ignoring case and a transaction is one purchase")
+	assert.Equal(t, "Bread", items[1].Name)
+
+	require.Len(t, merchants, 3)
+	assert.Equal(t, "Cafe Luna", merchants[0].Name)
+	assert.Equal(t, NewMoney(6), merchants[0].TotalAmount)
+	assert.Equal(t, NewMoney(2.5), merchants[1].AverageUnitPrice)
+	assert.Nil(t, merchants[2].MerchantID, "items of transactions without a merchant are grouped together")
+	assert.Equal(t, NewMoney(2.5), merchants[2].TotalAmount)
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestTransaction_Creation(t *testing.T) {
@@ -20,7 +21,7 @@ func TestTransaction_Creation(t *testing.T) {
 				CategoryID:  1,
 				Type:        "expense",
 				Description: "Grocery shopping",
-				Amount:      75.50,
+				Amount:      NewMoney(75.50),
 				Date:        time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
 			},
 			want: Transaction{
@@ -28,7 +29,7 @@ func TestTransaction_Creation(t *testing.T) {
 				CategoryID:  1,
 				Type:        "expense",
 				Description: "Grocery shopping",
-				Amount:      75.50,
+				Amount:      NewMoney(75.50),
 				Date:        time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
 			},
 		},
@@ -39,7 +40,7 @@ func TestTransaction_Creation(t *testing.T) {
 				CategoryID:  5,
 				Type:        "income",
 				Description: "Freelance payment",
-				Amount:      1200.00,
+				Amount:      NewMoney(1200.00),
 				Date:        time.Date(2024, 1, 20, 14, 0, 0, 0, time.UTC),
 			},
 			want: Transaction{
@@ -47,7 +48,7 @@ func TestTransaction_Creation(t *testing.T) {
 				CategoryID:  5,
 				Type:        "income",
 				Description: "Freelance payment",
-				Amount:      1200.00,
+				Amount:      NewMoney(1200.00),
 				Date:        time.Date(2024, 1, 20, 14, 0, 0, 0, time.UTC),
 			},
 		},
@@ -56,13 +57,13 @@ func TestTransaction_Creation(t *testing.T) {
 			transaction: Transaction{
 				UserID:     1,
 				CategoryID: 1,
-				Amount:     25.00,
+				Amount:     NewMoney(25.00),
 				Date:       time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC),
 			},
 			want: Transaction{
 				UserID:     1,
 				CategoryID: 1,
-				Amount:     25.00,
+				Amount:     NewMoney(25.00),
 				Date:       time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC),
 			},
 		},
@@ -90,7 +91,7 @@ func TestTransaction_Types(t *testing.T) {
 				CategoryID:  1,
 				Type:        transactionType,
 				Description: "Test transaction",
-				Amount:      100.00,
+				Amount:      NewMoney(100.00),
 				Date:        time.Now(),
 			}
 			assert.Equal(t, transactionType, transaction.Type)
@@ -105,7 +106,7 @@ func TestTransaction_DefaultValues(t *testing.T) {
 			UserID:      1,
 			CategoryID:  1,
 			Description: "Test transaction",
-			Amount:      50.00,
+			Amount:      NewMoney(50.00),
 			Date:        time.Now(),
 			// Type not set, should default to 'expense' in database
 		}
@@ -134,10 +135,10 @@ func TestTransaction_AmountValidation(t *testing.T) {
 				CategoryID:  1,
 				Type:        "expense",
 				Description: "Test transaction",
-				Amount:      tt.amount,
+				Amount:      NewMoney(tt.amount),
 				Date:        time.Now(),
 			}
-			assert.Equal(t, tt.amount, transaction.Amount)
+			assert.Equal(t, tt.amount, transaction.Amount.Float64())
 		})
 	}
 }
@@ -150,7 +151,7 @@ func TestTransaction_DateHandling(t *testing.T) {
 			CategoryID:  1,
 			Type:        "expense",
 			Description: "Christmas shopping",
-			Amount:      200.00,
+			Amount:      NewMoney(200.00),
 			Date:        pastDate,
 		}
 		assert.Equal(t, pastDate, transaction.Date)
@@ -163,7 +164,7 @@ func TestTransaction_DateHandling(t *testing.T) {
 			CategoryID:  1,
 			Type:        "income",
 			Description: "Expected bonus",
-			Amount:      500.00,
+			Amount:      NewMoney(500.00),
 			Date:        futureDate,
 		}
 		assert.Equal(t, futureDate, transaction.Date)
@@ -176,7 +177,7 @@ func TestTransaction_DateHandling(t *testing.T) {
 			CategoryID:  1,
 			Type:        "expense",
 			Description: "Current purchase",
-			Amount:      75.00,
+			Amount:      NewMoney(75.00),
 			Date:        now,
 		}
 		assert.Equal(t, now, transaction.Date)
@@ -202,7 +203,7 @@ func TestTransaction_CategoryRelationship(t *testing.T) {
 			Category:    category,
 			Type:        "expense",
 			Description: "Lunch at restaurant",
-			Amount:      25.50,
+			Amount:      NewMoney(25.50),
 			Date:        time.Now(),
 		}
 
@@ -219,7 +220,7 @@ func TestTransaction_CategoryRelationship(t *testing.T) {
 			CategoryID:  1,
 			Type:        "expense",
 			Description: "Test transaction",
-			Amount:      50.00,
+			Amount:      NewMoney(50.00),
 			Date:        time.Now(),
 			// Category not loaded
 		}
@@ -249,7 +250,7 @@ func TestTransaction_DescriptionHandling(t *testing.T) {
 				CategoryID:  1,
 				Type:        "expense",
 				Description: tt.description,
-				Amount:      100.00,
+				Amount:      NewMoney(100.00),
 				Date:        time.Now(),
 			}
 			assert.Equal(t, tt.description, transaction.Description)
@@ -265,7 +266,7 @@ func TestTransaction_JSONSerialization(t *testing.T) {
 			CategoryID:  1,
 			Type:        "expense",
 			Description: "Test transaction",
-			Amount:      75.50,
+			Amount:      NewMoney(75.50),
 			Date:        time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
 			CreatedAt:   time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
 			UpdatedAt:   time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
@@ -277,6 +278,39 @@ func TestTransaction_JSONSerialization(t *testing.T) {
 		assert.Equal(t, uint(1), transaction.CategoryID)
 		assert.Equal(t, "expense", transaction.Type)
 		assert.Equal(t, "Test transaction", transaction.Description)
-		assert.Equal(t, 75.50, transaction.Amount)
+		assert.Equal(t, NewMoney(75.50), transaction.Amount)
 	})
 }
+
+func TestNormalizeTags(t *testing.T) {
+	assert.Nil(t, NormalizeTags(nil))
+	assert.Equal(t, []string{}, NormalizeTags([]string{" "}))
+	assert.Equal(t, []string{"travel", "work"}, NormalizeTags([]string{" Travel", "work", "TRAVEL", ""}))
+}
+
+func TestParseTransactionIncludes(t *testing.T) {
+	includes, err := ParseTransactionIncludes("")
+	require.NoError(t, err)
+	assert.Equal(t, TransactionIncludes{}, includes)
+
+	includes, err = ParseTransactionIncludes("category, tags,items,running_balance")
+	require.NoError(t, err)
+	assert.Equal(t, TransactionIncludes{Tags: true, Items: true, RunningBalance: true}, includes)
+
+	_, err = ParseTransactionIncludes("tags,merchant")
+	assert.ErrorIs(t, err, ErrInvalidInclude)
+}
+
+func TestTransaction_SignedFor(t *testing.T) {
+	checking, savings, card := uint(1), uint(2), uint(3)
+	transfer := Transaction{
+		Type: TransactionTypeTransfer, Amount: NewMoney(250), AccountID: &checking, TransferAccountID: &savings,
+	}
+	expense := Transaction{Type: TransactionTypeExpense, Amount: NewMoney(40), AccountID: &checking}
+
+	assert.Equal(t, Money(0), transfer.Signed(), "a transfer leaves the user's total as it is")
+	assert.Equal(t, NewMoney(-250), transfer.SignedFor(checking))
+	assert.Equal(t, NewMoney(250), transfer.SignedFor(savings))
+	assert.Equal(t, Money(0), transfer.SignedFor(card))
+	assert.Equal(t, NewMoney(-40), expense.SignedFor(checking))
+}
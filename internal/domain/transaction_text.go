@@ -0,0 +1,260 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnparseableTransaction is returned for text no transaction could be read from
+var ErrUnparseableTransaction = errors.New("no transaction could be read from the text")
+
+// MaxTransactionTextLength bounds the text transactions are parsed from, in characters
+const MaxTransactionTextLength = 200
+
+// Where a parsed transaction was read by
+const (
+	TransactionTextSourceRules = "rules" // The built-in parser
+	TransactionTextSourceLLM   = "llm"   // The language model, for text the rules could not read
+)
+
+// SuggestionSourceText marks categories named in the text a transaction was parsed from
+const SuggestionSourceText = "text"
+
+// ParsedTransaction is a transaction read from text such as "coffee 4.50
+// yesterday", for the user to review before it is created. Category is the
+// name of a category the text mentions.
+type ParsedTransaction struct {
+	Amount      Money               `json:"amount"`
+	Type        string              `json:"type"`
+	Description string              `json:"description"`
+	Date        time.Time           `json:"date"`
+	Category    string              `json:"category,omitempty"`
+	Source      string              `json:"source"`
+	Suggestion  *CategorySuggestion `json:"category_suggestion,omitempty"`
+}
+
+var (
+	// An amount with an optional sign and currency, e.g. 4.50, $12, +2000, 8,99€ or 1,200.00
+	textAmountPattern = regexp.MustCompile(
+		`^([+-])?[$€£¥]?(\d{1,3}(?:,\d{3})+(?:\.\d{1,2})?|\d+(?:[.,]\d{1,2})?)[$€£¥]?(usd|eur|gbp)?$`,
+	)
+	textCurrencies = map[string]bool{
+		"$": true, "€": true, "£": true, "usd": true, "eur": true, "gbp": true, "dollars": true, "euros": true, "bucks": true,
+	}
+	textIncomeWords  = map[string]bool{"income": true, "salary": true, "paycheck": true, "refund": true, "bonus": true}
+	textIncomeVerbs  = map[string]bool{"earned": true, "received": true}
+	textExpenseVerbs = map[string]bool{"spent": true, "paid": true, "bought": true}
+	textFillers      = map[string]bool{"on": true, "for": true, "at": true}
+	textWeekdays     = map[string]time.Weekday{
+		"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday,
+		"thursday": time.Thursday, "friday": time.Friday, "saturday": time.Saturday,
+	}
+)
+
+// ParseTransactionText reads a transaction from short text such as "coffee
+// 4.50 yesterday" or "salary +2000 on friday". Amounts may carry a currency,
+// dates are written as today, yesterday, a weekday, "3 days ago" or
+// YYYY-MM-DD, and transactions are expenses unless the text says the money
+// was earned or received or names income. Text without an amount or a
+// description is reported with false.
+func ParseTransactionText(text string, today time.Time) (ParsedTransaction, bool) {
+	day := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	parsed := ParsedTransaction{Type: TransactionTypeExpense, Date: day, Source: TransactionTextSourceRules}
+	words := strings.Fields(text)
+	used := make([]bool, len(words))
+	amountAt, amountMarked := -1, false
+
+	for i := 0; i < len(words); i++ {
+		word := strings.ToLower(strings.TrimRight(words[i], ",.;:!?"))
+		if date, n, ok := textDate(words[i:], day); ok {
+			for j := i; j < i+n; j++ {
+				used[j] = true
+			}
+			parsed.Date = date
+			if i > 0 && strings.EqualFold(words[i-1], "on") {
+				used[i-1] = true
+			}
+			i += n - 1
+			continue
+		}
+		if match := textAmountPattern.FindStringSubmatch(word); match != nil {
+			// Amounts with decimals, a sign or a currency win over bare numbers
+			marked := match[1] != "" || strings.ContainsAny(word, ".,$€£¥") || match[3] != "" ||
+				(i+1 < len(words) && textCurrencies[strings.ToLower(words[i+1])])
+			if amountAt < 0 || (marked && !amountMarked) {
+				amountAt, amountMarked = i, marked
+			}
+			continue
+		}
+		switch {
+		case textCurrencies[word]:
+			used[i] = true
+		case textIncomeVerbs[word]:
+			used[i] = true
+			parsed.Type = TransactionTypeIncome
+		case textExpenseVerbs[word]:
+			used[i] = true
+		case textIncomeWords[word]:
+			parsed.Type = TransactionTypeIncome
+		}
+	}
+	if amountAt < 0 {
+		return ParsedTransaction{}, false
+	}
+
+	match := textAmountPattern.FindStringSubmatch(strings.ToLower(strings.TrimRight(words[amountAt], ",.;:!?")))
+	digits := strings.ReplaceAll(match[2], ",", "")
+	if whole, fraction, ok := strings.Cut(match[2], ","); ok && len(fraction) <= 2 {
+		digits = whole + "." + fraction // A decimal comma
+	}
+	amount, err := ParseMoney(digits)
+	if err != nil || amount <= 0 {
+		return ParsedTransaction{}, false
+	}
+	parsed.Amount = amount
+	if match[1] == "+" {
+		parsed.Type = TransactionTypeIncome
+	}
+	used[amountAt] = true
+	if amountAt > 0 && strings.EqualFold(words[amountAt-1], "for") {
+		used[amountAt-1] = true
+	}
+
+	var description []string
+	for i, word := range words {
+		if !used[i] {
+			description = append(description, word)
+		}
+	}
+	// "spent 12 on lunch" is about lunch
+	for len(description) > 1 && textFillers[strings.ToLower(description[0])] {
+		description = description[1:]
+	}
+	parsed.Description = capitalize(strings.Trim(strings.Join(description, " "), " ,.;:!?-"))
+	if parsed.Description == "" {
+		return ParsedTransaction{}, false
+	}
+	return parsed, true
+}
+
+// textDate reads a date from the start of the words, returning it with the
+// number of words it took
+func textDate(words []string, today time.Time) (time.Time, int, bool) {
+	word := strings.ToLower(strings.TrimRight(words[0], ",.;:!?"))
+	switch word {
+	case "today":
+		return today, 1, true
+	case "yesterday":
+		return today.AddDate(0, 0, -1), 1, true
+	case "last":
+		if len(words) > 1 {
+			if weekday, ok := textWeekdays[strings.ToLower(strings.TrimRight(words[1], ",.;:!?"))]; ok {
+				// The one before today, a full week back on that weekday
+				back := (int(today.Weekday())-int(weekday)+6)%7 + 1
+				return today.AddDate(0, 0, -back), 2, true
+			}
+		}
+	}
+	if weekday, ok := textWeekdays[word]; ok {
+		return today.AddDate(0, 0, -((int(today.Weekday()) - int(weekday) + 7) % 7)), 1, true
+	}
+	if date, err := time.Parse("2006-01-02", word); err == nil {
+		return date, 1, true
+	}
+	// "3 days ago", "a week ago"
+	if len(words) > 2 && strings.EqualFold(strings.TrimRight(words[2], ",.;:!?"), "ago") {
+		n, err := strconv.Atoi(word)
+		if word == "a" || word == "one" {
+			n, err = 1, nil
+		}
+		if err == nil && n >= 0 && n <= 366 {
+			switch strings.TrimSuffix(strings.ToLower(words[1]), "s") {
+			case "day":
+				return today.AddDate(0, 0, -n), 3, true
+			case "week":
+				return today.AddDate(0, 0, -7*n), 3, true
+			}
+		}
+	}
+	return time.Time{}, 0, false
+}
+
+// TransactionTextInstructions tells the model how to read a transaction from
+// text the rules could not, given the day it is entered on and the user's
+// category names
+func TransactionTextInstructions(today time.Time, categories []string) string {
+	return fmt.Sprintf(`You read a single personal finance transaction from a short note.
+Today is %s. Reply with a single JSON object and nothing else:
+{"amount": 0.00, "type": "expense", "description": "...", "date": "YYYY-MM-DD", "category": "..."}
+amount is the positive amount of money, null when the note has none.
+type is "income" for money earned or received and "expense" otherwise.
+description names what the money was for or who it came from, in a few words.
+date is the day of the transaction, today when the note does not say.
+category is one of these categories if the note clearly belongs to it, otherwise empty: %s.`,
+		today.Format("2006-01-02"), strings.Join(categories, ", "))
+}
+
+// ParseTransactionReply reads the transaction from the model's reply. The
+// reply may wrap the JSON object in text or a code block.
+func ParseTransactionReply(reply string, today time.Time) (ParsedTransaction, error) {
+	start, end := strings.Index(reply, "{"), strings.LastIndex(reply, "}")
+	if start < 0 || end < start {
+		return ParsedTransaction{}, ErrUnparseableTransaction
+	}
+	var raw struct {
+		Amount      *float64 `json:"amount"`
+		Type        string   `json:"type"`
+		Description string   `json:"description"`
+		Date        string   `json:"date"`
+		Category    string   `json:"category"`
+	}
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &raw); err != nil {
+		return ParsedTransaction{}, ErrUnparseableTransaction
+	}
+
+	parsed := ParsedTransaction{
+		Type:        TransactionTypeExpense,
+		Description: capitalize(strings.TrimSpace(raw.Description)),
+		Category:    strings.TrimSpace(raw.Category),
+		Date:        time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC),
+		Source:      TransactionTextSourceLLM,
+	}
+	if raw.Amount == nil || *raw.Amount <= 0 || parsed.Description == "" {
+		return ParsedTransaction{}, ErrUnparseableTransaction
+	}
+	parsed.Amount = NewMoney(*raw.Amount)
+	if strings.EqualFold(strings.TrimSpace(raw.Type), TransactionTypeIncome) {
+		parsed.Type = TransactionTypeIncome
+	}
+	if raw.Date != "" {
+		date, err := time.Parse("2006-01-02", raw.Date)
+		if err != nil {
+			return ParsedTransaction{}, ErrUnparseableTransaction
+		}
+		parsed.Date = date
+	}
+	return parsed, nil
+}
+
+// MentionedCategory returns the name of the longest of the categories the
+// text mentions as whole words, or "" without one
+func MentionedCategory(text string, categories []string) string {
+	words := " " + strings.Join(strings.FieldsFunc(strings.ToLower(text), textSeparator), " ") + " "
+	var found string
+	for _, name := range categories {
+		wanted := strings.Join(strings.FieldsFunc(strings.ToLower(name), textSeparator), " ")
+		if wanted != "" && len(name) > len(found) && strings.Contains(words, " "+wanted+" ") {
+			found = name
+		}
+	}
+	return found
+}
+
+func textSeparator(r rune) bool {
+	return strings.ContainsRune(" \t\n,.;:!?&/()-", r)
+}
@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTransactionText(t *testing.T) {
+	// A Wednesday
+	today := time.Date(2024, 5, 15, 18, 45, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2024, 5, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		text       string
+		want       ParsedTransaction
+		unreadable bool
+	}{
+		{
+			text: "coffee 4.50 yesterday",
+			want: ParsedTransaction{Amount: 450, Type: TransactionTypeExpense, Description: "Coffee", Date: day(14)},
+		},
+		{
+			text: "spent $12 on lunch at Cafe Luna today",
+			want: ParsedTransaction{Amount: 1200, Type: TransactionTypeExpense, Description: "Lunch at Cafe Luna", Date: day(15)},
+		},
+		{
+			text: "Bäckerei 8,99€ monday",
+			want: ParsedTransaction{Amount: 899, Type: TransactionTypeExpense, Description: "Bäckerei", Date: day(13)},
+		},
+		{
+			text: "salary +2,000.00 on last friday",
+			want: ParsedTransaction{Amount: 200000, Type: TransactionTypeIncome, Description: "Salary", Date: day(10)},
+		},
+		{
+			text: "received 50 eur from Anna 3 days ago",
+			want: ParsedTransaction{Amount: 5000, Type: TransactionTypeIncome, Description: "From Anna", Date: day(12)},
+		},
+		{
+			text: "7-Eleven snacks 2024-05-01 for 3.20",
+			want: ParsedTransaction{Amount: 320, Type: TransactionTypeExpense, Description: "7-Eleven snacks", Date: day(1)},
+		},
+		{
+			text: "Shell 5742 fuel 40.10",
+			want: ParsedTransaction{Amount: 4010, Type: TransactionTypeExpense, Description: "Shell 5742 fuel", Date: day(15)},
+		},
+		{
+			text: "wednesday groceries 23",
+			want: ParsedTransaction{Amount: 2300, Type: TransactionTypeExpense, Description: "Groceries", Date: day(15)},
+		},
+		{text: "four fifty for coffee", unreadable: true},
+		{text: "12.50 yesterday", unreadable: true},
+		{text: "refund 0.00", unreadable: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			parsed, ok := ParseTransactionText(tt.text, today)
+			if tt.unreadable {
+				assert.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			tt.want.Source = TransactionTextSourceRules
+			assert.Equal(t, tt.want, parsed)
+		})
+	}
+}
+
+func TestParseTransactionReply(t *testing.T) {
+	today := time.Date(2024, 5, 15, 18, 45, 0, 0, time.UTC)
+
+	parsed, err := ParseTransactionReply("```json\n"+
+		`{"amount": 4.5, "type": "expense", "description": "coffee", "date": "2024-05-14", "category": "Dining"}`+"\n```", today)
+	require.NoError(t, err)
+	assert.Equal(t, ParsedTransaction{
+		Amount: 450, Type: TransactionTypeExpense, Description: "Coffee", Category: "Dining",
+		Date: time.Date(2024, 5, 14, 0, 0, 0, 0, time.UTC), Source: TransactionTextSourceLLM,
+	}, parsed)
+
+	parsed, err = ParseTransactionReply(`{"amount": 2000, "type": "Income", "description": "Salary"}`, today)
+	require.NoError(t, err)
+	assert.Equal(t, TransactionTypeIncome, parsed.Type)
+	assert.Equal(t, time.Date(2024, 5, 15, 0, 0, 0, 0, time.UTC), parsed.Date, "dated today without a date")
+
+	for _, reply := range []string{
+		"Sorry, I cannot tell.",
+		`{"amount": null, "description": "coffee"}`,
+		`{"amount": -3, "description": "coffee"}`,
+		`{"amount": 3, "description": ""}`,
+		`{"amount": 3, "description": "coffee", "date": "yesterday"}`,
+	} {
+		_, err := ParseTransactionReply(reply, today)
+		assert.ErrorIs(t, err, ErrUnparseableTransaction, reply)
+	}
+}
+
+func TestMentionedCategory(t *testing.T) {
+	categories := []string{"Food", "Food & Dining", "Groceries", "Gas"}
+
+	assert.Equal(t, "Groceries", MentionedCategory("groceries 54.20", categories))
+	assert.Equal(t, "Food & Dining", MentionedCategory("food & dining: pizza 12", categories), "the longest match wins")
+	assert.Equal(t, "Food", MentionedCategory("food truck 9", categories))
+	assert.Empty(t, MentionedCategory("gasoline 40", categories), "only whole words match")
+}
+
+func FuzzParseTransactionText(f *testing.F) {
+	for _, seed := range []string{
+		"coffee 4.50 yesterday", "salary +2000 on friday", "spent 12 on lunch", "8,99€ pizza", "lunch 1,200.00 3 days ago",
+		"taxi $12 last monday", "rent 2024-03-01 900", "a week ago", "-", "€ , .",
+	} {
+		f.Add(seed)
+	}
+	today := time.Date(2024, 3, 13, 15, 4, 5, 0, time.UTC)
+
+	f.Fuzz(func(t *testing.T, text string) {
+		parsed, ok := ParseTransactionText(text, today)
+		if !ok {
+			return
+		}
+		assert.Positive(t, parsed.Amount)
+		assert.NotEmpty(t, parsed.Description)
+		assert.Contains(t, []string{TransactionTypeIncome, TransactionTypeExpense}, parsed.Type)
+		assert.Equal(t, TransactionTextSourceRules, parsed.Source)
+	})
+}
@@ -2,7 +2,10 @@
 // for the Go Finance Advisor application.
 package domain
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
 // User represents a user profile with email/password authentication and risk tolerance for advice
 // RiskTolerance: conservative, moderate, aggressive
@@ -14,7 +17,73 @@ type User struct {
 	LastName      string        `gorm:"type:varchar(50)" json:"last_name,omitempty"`
 	Age           int           `gorm:"type:int;default:30" json:"age,omitempty"`
 	RiskTolerance string        `gorm:"type:varchar(20);default:'moderate'" json:"risk_tolerance"`
+	Locale        string        `gorm:"type:varchar(10);default:'en'" json:"locale"`
+	AccountType   string        `gorm:"type:varchar(20);default:'personal'" json:"account_type"`
+	Plan          string        `gorm:"type:varchar(20);default:'free'" json:"plan"`
 	CreatedAt     time.Time     `json:"created_at"`
 	UpdatedAt     time.Time     `json:"updated_at"`
 	Transactions  []Transaction `json:"transactions,omitempty"`
+
+	// Set through PUT /users/:userId/profile
+	UserProfile `gorm:"embedded"`
+	// Kept up to date by the billing provider's webhooks
+	Subscription `gorm:"embedded"`
+}
+
+// UserProfile holds what the advisor knows about the user's situation. An
+// unset BirthDate falls back to the stored Age, a zero MonthlyIncome means
+// the income is unknown.
+type UserProfile struct {
+	BirthDate        *time.Time `json:"birth_date,omitempty"`
+	EmploymentStatus string     `gorm:"type:varchar(20)" json:"employment_status,omitempty"`
+	Dependents       int        `gorm:"default:0" json:"dependents"`
+	MonthlyIncome    Money      `gorm:"default:0" json:"monthly_income"`
+}
+
+// employmentStatuses lists the employment statuses a profile can have
+var employmentStatuses = []string{
+	EmploymentEmployed, EmploymentSelfEmployed, EmploymentUnemployed, EmploymentStudent, EmploymentRetired,
+}
+
+// SupportedLocales lists the languages messages, reports and exports are
+// translated into
+var SupportedLocales = []string{LocaleEnglish, LocaleTurkish, LocaleGerman}
+
+// ValidateLocale checks that the locale is one of SupportedLocales
+func ValidateLocale(locale string) error {
+	var v validator
+	v.check(slices.Contains(SupportedLocales, locale), "locale", "must be en, tr or de")
+	return v.err()
+}
+
+// maxDependents rejects dependent counts that are typos
+const maxDependents = 20
+
+// CurrentAge returns the user's age in whole years on the given day, from
+// the birth date when there is one
+func (u *User) CurrentAge(now time.Time) int {
+	if u.BirthDate == nil {
+		return u.Age
+	}
+	birth := *u.BirthDate
+	age := now.Year() - birth.Year()
+	if now.Month() < birth.Month() || (now.Month() == birth.Month() && now.Day() < birth.Day()) {
+		age--
+	}
+	return age
+}
+
+// Validate checks the profile's birth date, employment status, dependents
+// and income
+func (p *UserProfile) Validate() error {
+	var v validator
+	if p.BirthDate != nil {
+		v.check(!p.BirthDate.Before(earliestDate), "birth_date", "must be after 1900-01-01")
+		v.check(p.BirthDate.Before(time.Now()), "birth_date", "must be in the past")
+	}
+	v.check(p.EmploymentStatus == "" || slices.Contains(employmentStatuses, p.EmploymentStatus),
+		"employment_status", "must be employed, self_employed, unemployed, student or retired")
+	v.check(p.Dependents >= 0 && p.Dependents <= maxDependents, "dependents", "must be between 0 and 20")
+	v.check(p.MonthlyIncome >= 0, "monthly_income", "cannot be negative")
+	return v.err()
 }
@@ -164,7 +164,7 @@ func TestUser_Relationships(t *testing.T) {
 				UserID:      1,
 				Type:        "expense",
 				Description: "Grocery shopping",
-				Amount:      50.00,
+				Amount:      NewMoney(50.00),
 				Date:        time.Now(),
 			},
 			{
@@ -172,7 +172,7 @@ func TestUser_Relationships(t *testing.T) {
 				UserID:      1,
 				Type:        "income",
 				Description: "Salary",
-				Amount:      3000.00,
+				Amount:      NewMoney(3000.00),
 				Date:        time.Now(),
 			},
 		}
@@ -203,3 +203,50 @@ func TestUser_Relationships(t *testing.T) {
 		assert.NotNil(t, user.Transactions)
 	})
 }
+
+func TestUser_CurrentAge(t *testing.T) {
+	now := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+	birthDate := func(year int, month time.Month, day int) *time.Time {
+		date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+		return &date
+	}
+
+	assert.Equal(t, 42, (&User{Age: 42}).CurrentAge(now))
+	assert.Equal(t, 34, (&User{Age: 30, UserProfile: UserProfile{BirthDate: birthDate(1990, 6, 15)}}).CurrentAge(now))
+	assert.Equal(t, 33, (&User{UserProfile: UserProfile{BirthDate: birthDate(1990, 6, 16)}}).CurrentAge(now))
+	assert.Equal(t, 24, (&User{UserProfile: UserProfile{BirthDate: birthDate(2000, 2, 29)}}).CurrentAge(now))
+}
+
+func TestUserProfile_Validate(t *testing.T) {
+	future := time.Now().AddDate(1, 0, 0)
+	ancient := time.Date(1850, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, (&UserProfile{}).Validate())
+	assert.NoError(t, (&UserProfile{EmploymentStatus: EmploymentRetired, Dependents: 3, MonthlyIncome: NewMoney(2500)}).Validate())
+
+	tests := []struct {
+		name    string
+		profile UserProfile
+		field   string
+	}{
+		{"future birth date", UserProfile{BirthDate: &future}, "birth_date"},
+		{"ancient birth date", UserProfile{BirthDate: &ancient}, "birth_date"},
+		{"unknown employment status", UserProfile{EmploymentStatus: "astronaut"}, "employment_status"},
+		{"negative dependents", UserProfile{Dependents: -1}, "dependents"},
+		{"too many dependents", UserProfile{Dependents: 21}, "dependents"},
+		{"negative income", UserProfile{MonthlyIncome: NewMoney(-1)}, "monthly_income"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, []string{tt.field}, fieldsOf(t, tt.profile.Validate()))
+		})
+	}
+}
+
+func TestValidateLocale(t *testing.T) {
+	for _, locale := range SupportedLocales {
+		assert.NoError(t, ValidateLocale(locale))
+	}
+	assert.Equal(t, []string{"locale"}, fieldsOf(t, ValidateLocale("fr")))
+	assert.Equal(t, []string{"locale"}, fieldsOf(t, ValidateLocale("")))
+}
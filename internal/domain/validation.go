@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrValidation matches every ValidationError with errors.Is
+var ErrValidation = errors.New("validation failed")
+
+// Description, notes, tag and place limits and the dates transactions may fall on
+const (
+	maxDescriptionLength = 255
+	maxNotesLength       = 2000
+	maxTags              = 20
+	maxTagLength         = 50
+	maxPlaceLength       = 100
+	maxFutureTransaction = 365 * 24 * time.Hour
+)
+
+// earliestDate rejects dates that are typos rather than history
+var earliestDate = time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// budgetPeriods lists the periods a budget can cover
+var budgetPeriods = []string{PeriodWeekly, PeriodMonthly, PeriodQuarterly, PeriodYearly}
+
+// transactionTypes lists the types a transaction can have
+var transactionTypes = []string{TransactionTypeIncome, TransactionTypeExpense, TransactionTypeTransfer}
+
+// FieldError names a field and the business rule its value breaks
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError lists every field of an entity that breaks a business rule
+type ValidationError struct {
+	Fields []FieldError `json:"fields"`
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Field + ": " + field.Message
+	}
+	return ErrValidation.Error() + ": " + strings.Join(messages, "; ")
+}
+
+// Is makes errors.Is(err, ErrValidation) hold for validation errors
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// validator collects the field errors of one entity
+type validator struct {
+	fields []FieldError
+}
+
+// check records the message for the field unless ok holds
+func (v *validator) check(ok bool, field, message string) {
+	if !ok {
+		v.fields = append(v.fields, FieldError{Field: field, Message: message})
+	}
+}
+
+// err returns the collected field errors, or nil without any
+func (v *validator) err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: v.fields}
+}
+
+// Validate checks the transaction's amount, type, description, category,
+// notes, tags, date, location and line items
+func (t *Transaction) Validate() error {
+	var v validator
+	v.check(t.Amount > 0, "amount", "must be greater than zero")
+	v.check(slices.Contains(transactionTypes, t.Type), "type", "must be income, expense or transfer")
+	description := strings.TrimSpace(t.Description)
+	v.check(description != "", "description", "is required")
+	v.check(len(description) <= maxDescriptionLength, "description", "must be at most 255 characters")
+	v.check(t.CategoryID != 0, "category_id", "is required")
+	v.check(len(t.Notes) <= maxNotesLength, "notes", "must be at most 2000 characters")
+	v.check(len(t.Tags) <= maxTags, "tags", "must list at most 20 tags")
+	v.check(!slices.ContainsFunc(t.Tags, func(tag string) bool { return len(tag) > maxTagLength }),
+		"tags", "must each be at most 50 characters")
+	v.check(!t.Date.Before(earliestDate), "date", "must be after 1900-01-01")
+	v.check(!t.Date.After(time.Now().Add(maxFutureTransaction)), "date", "must be within a year from today")
+	v.check(t.Currency == "" || IsCurrencyCode(t.Currency), "currency", "must be a three letter ISO 4217 code")
+	v.check((t.Latitude == nil) == (t.Longitude == nil), "location", "needs both a latitude and a longitude")
+	v.check(t.Latitude == nil || (*t.Latitude >= -90 && *t.Latitude <= 90), "latitude", "must be between -90 and 90")
+	v.check(t.Longitude == nil || (*t.Longitude >= -180 && *t.Longitude <= 180), "longitude", "must be between -180 and 180")
+	v.check(len([]rune(t.Place)) <= maxPlaceLength, "place", "must be at most 100 characters")
+	v.check(len([]rune(t.City)) <= maxPlaceLength, "city", "must be at most 100 characters")
+	v.validateItems(t.Items)
+	v.validateTransfer(t)
+	return v.err()
+}
+
+// validateTransfer checks that a transfer goes from one account to another
+// and that only transfers name an account they credit
+func (v *validator) validateTransfer(t *Transaction) {
+	if t.Type != TransactionTypeTransfer {
+		v.check(t.TransferAccountID == nil, "transfer_account_id", "is only allowed on transfers")
+		v.check(t.ScheduledTransferID == nil, "scheduled_transfer_id", "is only allowed on transfers")
+		return
+	}
+	v.check(t.AccountID != nil, "account_id", "is required for transfers")
+	v.check(t.TransferAccountID != nil, "transfer_account_id", "is required for transfers")
+	v.check(t.AccountID == nil || t.TransferAccountID == nil || *t.AccountID != *t.TransferAccountID,
+		"transfer_account_id", "must be another account than account_id")
+	v.check(t.HouseholdID == nil, "household_id", "cannot be set on transfers between personal accounts")
+	v.check(t.Currency == "", "currency", "is not supported on transfers; they move money in their accounts' currency")
+	v.check(t.IncomeSourceID == nil, "income_source_id", "is not allowed on transfers")
+}
+
+// ValidateCategory checks that the transaction is filed under a category of
+// its own type, e.g. that an expense does not go into Salary
+func (t *Transaction) ValidateCategory(category *Category) error {
+	var v validator
+	v.check(category.Type == "" || category.Type == t.Type, "category_id", "must be a category of the transaction's type")
+	return v.err()
+}
+
+// Validate checks the budget's amount, period, category and dates
+func (b *Budget) Validate() error {
+	var v validator
+	v.check(b.Amount > 0, "amount", "must be greater than zero")
+	v.check(slices.Contains(budgetPeriods, b.Period), "period", "must be weekly, monthly, quarterly or yearly")
+	v.check(b.CategoryID != 0, "category_id", "is required")
+	v.check(!b.StartDate.IsZero(), "start_date", "is required")
+	v.check(b.EndDate.After(b.StartDate), "end_date", "must be after the start date")
+	v.check(b.Anchor == "" || slices.Contains(BudgetAnchors, b.Anchor), "anchor", "must be start_date, calendar or payday")
+	if b.Anchor == BudgetAnchorPayday {
+		if b.Period == PeriodWeekly {
+			v.check(b.AnchorDay >= 1 && b.AnchorDay <= 7, "anchor_day", "must be a weekday from 1 (Monday) to 7 (Sunday)")
+		} else {
+			v.check(b.AnchorDay >= 1 && b.AnchorDay <= 31, "anchor_day", "must be a day of the month from 1 to 31")
+		}
+	}
+	return v.err()
+}
@@ -0,0 +1,162 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fieldsOf returns the names of the fields the validation error lists
+func fieldsOf(t *testing.T, err error) []string {
+	t.Helper()
+	var invalid *ValidationError
+	require.True(t, errors.As(err, &invalid), "expected a validation error, got %v", err)
+	assert.ErrorIs(t, err, ErrValidation)
+
+	fields := make([]string, len(invalid.Fields))
+	for i, field := range invalid.Fields {
+		fields[i] = field.Field
+	}
+	return fields
+}
+
+func TestTransaction_Validate(t *testing.T) {
+	valid := func() Transaction {
+		return Transaction{
+			UserID: 1, CategoryID: 2, Type: TransactionTypeExpense,
+			Description: "Groceries", Amount: NewMoney(42.10), Date: time.Now(),
+		}
+	}
+
+	tx := valid()
+	assert.NoError(t, tx.Validate())
+
+	tests := []struct {
+		name   string
+		mutate func(*Transaction)
+		fields []string
+	}{
+		{"zero amount", func(tx *Transaction) { tx.Amount = 0 }, []string{"amount"}},
+		{"negative amount", func(tx *Transaction) { tx.Amount = NewMoney(-5) }, []string{"amount"}},
+		{"unknown type", func(tx *Transaction) { tx.Type = "refund" }, []string{"type"}},
+		{"blank description", func(tx *Transaction) { tx.Description = "   " }, []string{"description"}},
+		{"missing category", func(tx *Transaction) { tx.CategoryID = 0 }, []string{"category_id"}},
+		{"missing date", func(tx *Transaction) { tx.Date = time.Time{} }, []string{"date"}},
+		{"far future date", func(tx *Transaction) { tx.Date = time.Now().AddDate(2, 0, 0) }, []string{"date"}},
+		{"several fields", func(tx *Transaction) { tx.Amount, tx.Type = 0, "" }, []string{"amount", "type"}},
+		{"long notes", func(tx *Transaction) { tx.Notes = strings.Repeat("n", 2001) }, []string{"notes"}},
+		{"too many tags", func(tx *Transaction) { tx.Tags = make([]string, 21) }, []string{"tags"}},
+		{"long tag", func(tx *Transaction) { tx.Tags = []string{strings.Repeat("t", 51)} }, []string{"tags"}},
+		{"latitude without longitude", func(tx *Transaction) { tx.Latitude = new(float64) }, []string{"location"}},
+		{"location off the map", func(tx *Transaction) {
+			latitude, longitude := 91.0, -181.0
+			tx.Latitude, tx.Longitude = &latitude, &longitude
+		}, []string{"latitude", "longitude"}},
+		{"long place", func(tx *Transaction) { tx.Place, tx.City = strings.Repeat("p", 101), strings.Repeat("c", 101) }, []string{"place", "city"}},
+		{"too many items", func(tx *Transaction) {
+			tx.Items = slices.Repeat([]TransactionItem{{Name: "Coffee", Quantity: 1}}, 101)
+		}, []string{"items"}},
+		{"unnamed item", func(tx *Transaction) { tx.Items = []TransactionItem{{Quantity: 1}} }, []string{"items"}},
+		{"negative item", func(tx *Transaction) {
+			tx.Items = []TransactionItem{{Name: "Refund", Quantity: -1}, {Name: "Coupon", Quantity: 1, UnitPrice: NewMoney(-2)}}
+		}, []string{"items"}},
+		{"transfer without accounts", func(tx *Transaction) { tx.Type = TransactionTypeTransfer },
+			[]string{"account_id", "transfer_account_id"}},
+		{"transfer to the same account", func(tx *Transaction) {
+			account := uint(3)
+			tx.Type, tx.AccountID, tx.TransferAccountID = TransactionTypeTransfer, &account, &account
+		}, []string{"transfer_account_id"}},
+		{"shared transfer in another currency", func(tx *Transaction) {
+			from, to, household := uint(3), uint(4), uint(5)
+			tx.Type, tx.AccountID, tx.TransferAccountID, tx.HouseholdID = TransactionTypeTransfer, &from, &to, &household
+			tx.Currency = "EUR"
+		}, []string{"household_id", "currency"}},
+		{"expense with a transfer account", func(tx *Transaction) { tx.TransferAccountID = new(uint) },
+			[]string{"transfer_account_id"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := valid()
+			tt.mutate(&tx)
+			assert.Equal(t, tt.fields, fieldsOf(t, tx.Validate()))
+		})
+	}
+
+	t.Run("transfer between two accounts", func(t *testing.T) {
+		from, to := uint(3), uint(4)
+		tx := valid()
+		tx.Type, tx.AccountID, tx.TransferAccountID = TransactionTypeTransfer, &from, &to
+		assert.NoError(t, tx.Validate())
+	})
+}
+
+func TestTransaction_ValidateCategory(t *testing.T) {
+	tx := Transaction{Type: TransactionTypeIncome}
+	assert.NoError(t, tx.ValidateCategory(&Category{Type: TransactionTypeIncome}))
+	assert.Equal(t, []string{"category_id"}, fieldsOf(t, tx.ValidateCategory(&Category{Type: TransactionTypeExpense})))
+}
+
+func TestBudget_Validate(t *testing.T) {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	valid := func() Budget {
+		return Budget{
+			CategoryID: 1, Amount: NewMoney(500), Period: PeriodQuarterly,
+			StartDate: start, EndDate: start.AddDate(0, 3, 0),
+		}
+	}
+
+	budget := valid()
+	assert.NoError(t, budget.Validate())
+
+	tests := []struct {
+		name   string
+		mutate func(*Budget)
+		fields []string
+	}{
+		{"zero amount", func(b *Budget) { b.Amount = 0 }, []string{"amount"}},
+		{"unknown period", func(b *Budget) { b.Period = "daily" }, []string{"period"}},
+		{"missing category", func(b *Budget) { b.CategoryID = 0 }, []string{"category_id"}},
+		{"end before start", func(b *Budget) { b.EndDate = start.AddDate(0, 0, -1) }, []string{"end_date"}},
+		{"end on start", func(b *Budget) { b.EndDate = start }, []string{"end_date"}},
+		{"missing dates", func(b *Budget) { b.StartDate, b.EndDate = time.Time{}, time.Time{} }, []string{"start_date", "end_date"}},
+		{"unknown anchor", func(b *Budget) { b.Anchor = "fortnight" }, []string{"anchor"}},
+		{"payday without a day", func(b *Budget) { b.Anchor = BudgetAnchorPayday }, []string{"anchor_day"}},
+		{"weekly payday past Sunday", func(b *Budget) {
+			b.Anchor, b.AnchorDay, b.Period = BudgetAnchorPayday, 8, PeriodWeekly
+		}, []string{"anchor_day"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budget := valid()
+			tt.mutate(&budget)
+			assert.Equal(t, tt.fields, fieldsOf(t, budget.Validate()))
+		})
+	}
+}
+
+func TestValidationError_Error(t *testing.T) {
+	err := &ValidationError{Fields: []FieldError{
+		{Field: "amount", Message: "must be greater than zero"},
+		{Field: "period", Message: "must be weekly, monthly, quarterly or yearly"},
+	}}
+	assert.Equal(t, "validation failed: amount: must be greater than zero; period: must be weekly, monthly, quarterly or yearly", err.Error())
+}
+
+func TestAccountValidate(t *testing.T) {
+	valid := Account{Name: "Main", Type: AccountTypeChecking, AccountNumber: "DE89370400440532013000", Currency: "EUR"}
+	assert.NoError(t, valid.Validate())
+
+	invalid := Account{Type: "piggy_bank", AccountNumber: "12 34", Currency: "euro"}
+	assert.ElementsMatch(t, []string{"name", "type", "account_number", "currency"}, fieldsOf(t, invalid.Validate()))
+}
+
+func TestMaskAccountNumber(t *testing.T) {
+	assert.Equal(t, "••••3000", MaskAccountNumber("DE89370400440532013000"))
+	assert.Equal(t, "••••12", MaskAccountNumber("12"))
+	assert.Empty(t, MaskAccountNumber(""))
+}
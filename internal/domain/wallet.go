@@ -0,0 +1,220 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"errors"
+	"math/big"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Blockchains public wallet addresses can be tracked on. The chain is also
+// the market symbol the balance is valued at.
+const (
+	WalletChainBTC = "BTC"
+	WalletChainETH = "ETH"
+)
+
+// WalletChains lists the supported blockchains
+var WalletChains = []string{WalletChainBTC, WalletChainETH}
+
+const maxWalletLabelLength = 100
+
+// ErrWalletExists is returned when the user already tracks the address
+var ErrWalletExists = errors.New("wallet address is already registered")
+
+// Wallet is a public address on a blockchain a user tracks. Only the address
+// is known, never a key; Balance is in coins as last read from the chain and
+// Price and Value are only filled when the wallet is valued.
+type Wallet struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	UserID       uint       `gorm:"uniqueIndex:idx_wallets_user_chain_address,priority:1;not null" json:"user_id"`
+	Chain        string     `gorm:"type:varchar(10);uniqueIndex:idx_wallets_user_chain_address,priority:2;not null" json:"chain"`
+	Address      string     `gorm:"type:varchar(100);uniqueIndex:idx_wallets_user_chain_address,priority:3;not null" json:"address"`
+	Label        string     `gorm:"type:varchar(100)" json:"label,omitempty"`
+	Balance      float64    `gorm:"not null;default:0" json:"balance"`
+	LastSyncedAt *time.Time `json:"last_synced_at,omitempty"`
+	LastError    string     `gorm:"type:text" json:"last_error,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	Price        *float64   `gorm:"-" json:"price,omitempty"`
+	Value        *float64   `gorm:"-" json:"value,omitempty"`
+}
+
+// WalletTransaction is a transaction touching a tracked address. Amount is
+// the coins the address received, negative when it sent more than it got;
+// Time is nil while the transaction is unconfirmed.
+type WalletTransaction struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	WalletID  uint       `gorm:"uniqueIndex:idx_wallet_transactions_wallet_hash,priority:1;not null" json:"wallet_id"`
+	Hash      string     `gorm:"type:varchar(100);uniqueIndex:idx_wallet_transactions_wallet_hash,priority:2;not null" json:"hash"`
+	Amount    float64    `gorm:"not null" json:"amount"`
+	Fee       float64    `gorm:"not null;default:0" json:"fee"`
+	Confirmed bool       `gorm:"not null;default:false" json:"confirmed"`
+	Time      *time.Time `gorm:"index" json:"time,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// WalletSnapshot is what a blockchain API reports for an address: its
+// confirmed balance in coins and its latest transactions
+type WalletSnapshot struct {
+	Balance      float64
+	Transactions []WalletTransaction
+}
+
+// Normalize trims the wallet's fields, upper-cases the chain and lower-cases
+// addresses that are case-insensitive
+func (w *Wallet) Normalize() {
+	w.Chain = strings.ToUpper(strings.TrimSpace(w.Chain))
+	w.Address = strings.TrimSpace(w.Address)
+	w.Label = strings.TrimSpace(w.Label)
+	lower := strings.ToLower(w.Address)
+	if (w.Chain == WalletChainBTC && strings.HasPrefix(lower, "bc1")) || w.Chain == WalletChainETH {
+		w.Address = lower
+	}
+}
+
+// Validate checks the chain, that the address is well formed for it and the label
+func (w *Wallet) Validate() error {
+	var v validator
+	v.check(slices.Contains(WalletChains, w.Chain), "chain", "must be BTC or ETH")
+	switch w.Chain {
+	case WalletChainBTC:
+		v.check(IsValidBitcoinAddress(w.Address), "address", "must be a valid Bitcoin mainnet address")
+	case WalletChainETH:
+		v.check(IsValidEthereumAddress(w.Address), "address", "must be 0x followed by 40 hexadecimal digits")
+	}
+	v.check(len(w.Label) <= maxWalletLabelLength, "label", "must be at most 100 characters")
+	return v.err()
+}
+
+// Symbol is the market symbol the wallet's balance is priced in
+func (w *Wallet) Symbol() string {
+	return w.Chain
+}
+
+var ethereumAddressPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+
+// IsValidEthereumAddress checks the form of an Ethereum address. Mixed-case
+// checksums are not verified.
+func IsValidEthereumAddress(address string) bool {
+	return ethereumAddressPattern.MatchString(address)
+}
+
+// IsValidBitcoinAddress checks that the address is a mainnet pay-to-pubkey-hash
+// or pay-to-script-hash address with a valid Base58Check checksum, or a
+// segwit address with a valid bech32 or bech32m checksum
+func IsValidBitcoinAddress(address string) bool {
+	if strings.HasPrefix(strings.ToLower(address), "bc1") {
+		return isValidSegwitAddress(address)
+	}
+	return isValidBase58Address(address)
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// isValidBase58Address decodes a legacy address and checks its version byte and checksum
+func isValidBase58Address(address string) bool {
+	if len(address) < 26 || len(address) > 35 || (address[0] != '1' && address[0] != '3') {
+		return false
+	}
+	value := new(big.Int)
+	radix := big.NewInt(58)
+	for _, c := range address {
+		digit := strings.IndexRune(base58Alphabet, c)
+		if digit < 0 {
+			return false
+		}
+		value.Mul(value, radix).Add(value, big.NewInt(int64(digit)))
+	}
+	// Leading ones encode leading zero bytes
+	decoded := value.Bytes()
+	for i := 0; i < len(address) && address[i] == '1'; i++ {
+		decoded = append([]byte{0}, decoded...)
+	}
+	if len(decoded) != 25 || (decoded[0] != 0x00 && decoded[0] != 0x05) {
+		return false
+	}
+	first := sha256.Sum256(decoded[:21])
+	second := sha256.Sum256(first[:])
+	return string(second[:4]) == string(decoded[21:])
+}
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// Checksum constants of bech32, used by version 0 segwit addresses, and of
+// bech32m, used by later versions such as taproot
+const (
+	bech32Constant  = 1
+	bech32mConstant = 0x2bc830a3
+)
+
+// isValidSegwitAddress checks the checksum, witness version and program
+// length of a bc1 address
+func isValidSegwitAddress(address string) bool {
+	if len(address) < 14 || len(address) > 90 || (address != strings.ToLower(address) && address != strings.ToUpper(address)) {
+		return false
+	}
+	address = strings.ToLower(address)
+	data := make([]byte, 0, len(address)-3)
+	for _, c := range address[3:] {
+		value := strings.IndexRune(bech32Charset, c)
+		if value < 0 {
+			return false
+		}
+		data = append(data, byte(value))
+	}
+	if len(data) < 7 {
+		return false
+	}
+
+	checksum := bech32Polymod(append([]byte{3, 3, 0, 2, 3}, data...)) // "bc" expanded
+	version, program := data[0], data[1:len(data)-6]
+	// The program's 5 bit groups hold 2 to 40 bytes; version 0 holds 20 or 32
+	bytes := len(program) * 5 / 8
+	switch {
+	case version == 0:
+		return checksum == bech32Constant && (bytes == 20 || bytes == 32)
+	case version <= 16:
+		return checksum == bech32mConstant && bytes >= 2 && bytes <= 40
+	default:
+		return false
+	}
+}
+
+// bech32Polymod is the BCH checksum of BIP 173
+func bech32Polymod(values []byte) uint32 {
+	generators := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	checksum := uint32(1)
+	for _, value := range values {
+		top := checksum >> 25
+		checksum = (checksum&0x1ffffff)<<5 ^ uint32(value)
+		for i, generator := range generators {
+			if (top>>i)&1 == 1 {
+				checksum ^= generator
+			}
+		}
+	}
+	return checksum
+}
+
+// HoldingsWithWallets adds the balances of the wallets to the holdings of
+// the same coin, or as crypto holdings of their own, so that portfolio
+// measures cover what users keep on chain too. Empty wallets are left out.
+func HoldingsWithWallets(holdings []Holding, wallets []Wallet) []Holding {
+	merged := append([]Holding(nil), holdings...)
+	for _, wallet := range wallets {
+		if wallet.Balance <= 0 {
+			continue
+		}
+		i := slices.IndexFunc(merged, func(h Holding) bool { return h.Symbol == wallet.Symbol() })
+		if i < 0 {
+			merged = append(merged, Holding{UserID: wallet.UserID, Symbol: wallet.Symbol(), AssetType: "crypto"})
+			i = len(merged) - 1
+		}
+		merged[i].Quantity += wallet.Balance
+	}
+	return merged
+}
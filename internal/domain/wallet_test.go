@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsValidBitcoinAddress(t *testing.T) {
+	valid := []string{
+		"1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa",
+		"3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy",
+		"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+		"BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4",
+		"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0",
+		"bc1pw508d6qejxtdg4y5r3zarvary0c5xw7kw508d6qejxtdg4y5r3zarvary0c5xw7kt5nd6y",
+	}
+	for _, address := range valid {
+		assert.True(t, IsValidBitcoinAddress(address), address)
+	}
+
+	invalid := []string{
+		"",
+		"1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNb", // Broken checksum
+		"mipcBbFg9gMiCh81Kj8tqqdgoZub1ZJRfn", // Testnet
+		"1A1zP1eP5QGefi2DMPTfTL5SLmv7Divf0a", // 0 is not base58
+		"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5",                                 // Broken checksum
+		"bc1Qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",                                 // Mixed case
+		"tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx",                                 // Testnet
+		"bc1pw508d6qejxtdg4y5r3zarvary0c5xw7kw508d6qejxtdg4y5r3zarvary0c5xw7k7grplx", // Version 1 with a bech32 checksum
+	}
+	for _, address := range invalid {
+		assert.False(t, IsValidBitcoinAddress(address), address)
+	}
+}
+
+func TestWallet_NormalizeAndValidate(t *testing.T) {
+	wallet := Wallet{Chain: " eth ", Address: " 0xDE0B295669a9FD93d5F28D9Ec85E40f4cb697BAe ", Label: " Cold storage "}
+	wallet.Normalize()
+	assert.NoError(t, wallet.Validate())
+	assert.Equal(t, "ETH", wallet.Chain)
+	assert.Equal(t, "0xde0b295669a9fd93d5f28d9ec85e40f4cb697bae", wallet.Address)
+	assert.Equal(t, "Cold storage", wallet.Label)
+	assert.Equal(t, "ETH", wallet.Symbol())
+
+	legacy := Wallet{Chain: "btc", Address: "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa"}
+	legacy.Normalize()
+	assert.NoError(t, legacy.Validate())
+	assert.Equal(t, "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", legacy.Address, "base58 addresses are case-sensitive")
+
+	cases := map[string]struct {
+		wallet Wallet
+		field  string
+	}{
+		"unsupported chain": {Wallet{Chain: "DOGE", Address: "D8vFz4p1L37jdg47HXKtSHA5uYLYxbGgPD"}, "chain"},
+		"bitcoin address":   {Wallet{Chain: WalletChainBTC, Address: "0xde0b295669a9fd93d5f28d9ec85e40f4cb697bae"}, "address"},
+		"ethereum address":  {Wallet{Chain: WalletChainETH, Address: "0xde0b295669a9fd93d5f28d9ec85e40f4cb697b"}, "address"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc.wallet.Normalize()
+			err := tc.wallet.Validate()
+			var validation *ValidationError
+			if assert.ErrorAs(t, err, &validation) {
+				assert.Equal(t, tc.field, validation.Fields[0].Field)
+			}
+		})
+	}
+}
+
+func TestHoldingsWithWallets(t *testing.T) {
+	holdings := []Holding{{Symbol: "AAPL", Quantity: 10}, {Symbol: "BTC", AssetType: "crypto", Quantity: 0.5}}
+	wallets := []Wallet{
+		{UserID: 1, Chain: WalletChainBTC, Balance: 0.25},
+		{UserID: 1, Chain: WalletChainBTC, Balance: 0.25},
+		{UserID: 1, Chain: WalletChainETH, Balance: 2},
+		{UserID: 1, Chain: WalletChainETH, Balance: 0},
+	}
+
+	merged := HoldingsWithWallets(holdings, wallets)
+	assert.Equal(t, []Holding{
+		{Symbol: "AAPL", Quantity: 10},
+		{Symbol: "BTC", AssetType: "crypto", Quantity: 1},
+		{UserID: 1, Symbol: "ETH", AssetType: "crypto", Quantity: 2},
+	}, merged)
+	assert.Equal(t, 0.5, holdings[1].Quantity, "the holdings passed in are left alone")
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSymbol is returned for symbols that are empty, too long or contain
+// characters market symbols never do
+var ErrInvalidSymbol = errors.New("symbol must be 1-20 letters, digits, dots or dashes")
+
+// ErrAlreadyWatchlisted is returned when saving a symbol that is already on the watchlist
+var ErrAlreadyWatchlisted = errors.New("symbol is already on the watchlist")
+
+var symbolPattern = regexp.MustCompile(`^[A-Z0-9.\-]{1,20}$`)
+
+// NormalizeSymbol upper-cases a market symbol and checks that it is well formed
+func NormalizeSymbol(symbol string) (string, error) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if !symbolPattern.MatchString(symbol) {
+		return "", ErrInvalidSymbol
+	}
+	return symbol, nil
+}
+
+// WatchlistItem is a market symbol a user follows. Watchlisted symbols are
+// listed first when prices are shown, so they are the first to be refreshed.
+type WatchlistItem struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"uniqueIndex:idx_watchlist_items_user_symbol,priority:1" json:"user_id"`
+	Symbol    string    `gorm:"type:varchar(20);uniqueIndex:idx_watchlist_items_user_symbol,priority:2" json:"symbol"`
+	Name      string    `json:"name,omitempty"`
+	AssetType string    `gorm:"type:varchar(20)" json:"asset_type"` // "stock" or "crypto"
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WatchlistQuote is the latest price of a watchlisted symbol. Price is zero
+// when the market provider did not return one.
+type WatchlistQuote struct {
+	WatchlistItem
+	Price     float64 `json:"price"`
+	Available bool    `json:"available"`
+}
@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeSymbol(t *testing.T) {
+	symbol, err := NormalizeSymbol(" brk.b ")
+	assert.NoError(t, err)
+	assert.Equal(t, "BRK.B", symbol)
+
+	for _, invalid := range []string{"", "AAPL; DROP", "THIS-SYMBOL-IS-FAR-TOO-LONG"} {
+		_, err := NormalizeSymbol(invalid)
+		assert.ErrorIs(t, err, ErrInvalidSymbol, invalid)
+	}
+}
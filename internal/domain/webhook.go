@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// WebhookSignatureHeader carries the hex HMAC-SHA256 of a delivery's body,
+// keyed with the webhook's secret, as "sha256=<hex>"
+const WebhookSignatureHeader = "X-Webhook-Signature"
+
+// Webhook is a URL the events of a user's data are posted to. Events lists
+// the event types delivered, every type when empty. Secret signs the
+// deliveries; it is only shown when the webhook is created.
+type Webhook struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;index" json:"user_id"`
+	URL       string    `gorm:"type:varchar(2048);not null" json:"url"`
+	Events    []string  `gorm:"serializer:json;type:text" json:"events"`
+	Secret    string    `gorm:"type:varchar(64);not null" json:"secret,omitempty"`
+	Active    bool      `gorm:"not null;default:true" json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks that the URL is absolute http(s) on a public host and the
+// events are known
+func (w *Webhook) Validate() error {
+	var v validator
+	target, err := url.Parse(w.URL)
+	v.check(err == nil && (target.Scheme == "https" || target.Scheme == "http") && target.Host != "",
+		"url", "must be an http or https URL")
+	v.check(err != nil || isPublicHost(target.Hostname()), "url", "must not point to a local or private network")
+	v.check(len(w.URL) <= 2048, "url", "must be at most 2048 characters")
+	for i, event := range w.Events {
+		field := fmt.Sprintf("events[%d]", i)
+		v.check(IsValidEventType(event), field, "must be one of "+strings.Join(EventTypes, ", "))
+		v.check(!slices.Contains(w.Events[:i], event), field, "must not repeat an event")
+	}
+	return v.err()
+}
+
+// Wants reports whether the webhook is delivered events of the type
+func (w *Webhook) Wants(eventType string) bool {
+	return w.Active && (len(w.Events) == 0 || slices.Contains(w.Events, eventType))
+}
+
+// WebhookDelivery is the body posted to webhooks
+type WebhookDelivery struct {
+	Event      string    `json:"event"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       Event     `json:"data"`
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhook_ValidateURL(t *testing.T) {
+	tests := []struct {
+		url   string
+		valid bool
+	}{
+		{"https://hooks.example.com/finance", true},
+		{"http://203.0.113.7:8080/hook", true},
+		{"ftp://hooks.example.com", false},
+		{"http://localhost:8080/hook", false},
+		{"http://127.0.0.1:8080/hook", false},
+		{"http://10.0.0.5/hook", false},
+		{"http://169.254.169.254/latest/meta-data/", false},
+		{"http://[fe80::1]/hook", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			webhook := Webhook{URL: tt.url}
+			if tt.valid {
+				assert.NoError(t, webhook.Validate())
+			} else {
+				assert.ErrorIs(t, webhook.Validate(), ErrValidation)
+			}
+		})
+	}
+}
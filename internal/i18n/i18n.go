@@ -0,0 +1,217 @@
+// Package i18n translates what users read, console menus, API error
+// messages, report labels and default category names, and formats dates,
+// numbers and amounts the way the user's locale writes them.
+package i18n
+
+import (
+	"embed"
+	"errors"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/pelletier/go-toml/v2"
+	"golang.org/x/text/language"
+)
+
+// locales holds a message file per supported locale, named after it
+//
+//go:embed locales/*.toml
+var locales embed.FS
+
+// bundle holds the messages of every supported locale. English is the
+// fallback for messages a locale does not translate.
+var bundle = loadBundle()
+
+func loadBundle() *goi18n.Bundle {
+	b := goi18n.NewBundle(language.English)
+	b.RegisterUnmarshalFunc("toml", toml.Unmarshal)
+	for _, locale := range domain.SupportedLocales {
+		if _, err := b.LoadMessageFileFS(locales, path.Join("locales", locale+".toml")); err != nil {
+			panic("i18n: " + err.Error())
+		}
+	}
+	return b
+}
+
+// matcher picks the supported locale closest to what a client asks for
+var matcher = language.NewMatcher(supportedTags())
+
+func supportedTags() []language.Tag {
+	tags := make([]language.Tag, len(domain.SupportedLocales))
+	for i, locale := range domain.SupportedLocales {
+		tags[i] = language.MustParse(locale)
+	}
+	return tags
+}
+
+// format is how a locale writes dates and numbers
+type format struct {
+	date     string
+	dateTime string
+	number   domain.NumberStyle
+}
+
+// formats lists the formats of the supported locales. English keeps ISO
+// dates, so exports stay sortable.
+var formats = map[string]format{
+	domain.LocaleEnglish: {date: "2006-01-02", dateTime: "2006-01-02 15:04:05", number: domain.DefaultNumberStyle},
+	domain.LocaleTurkish: {
+		date: "02.01.2006", dateTime: "02.01.2006 15:04:05",
+		number: domain.NumberStyle{Decimal: ",", Group: ".", SymbolAfter: true},
+	},
+	domain.LocaleGerman: {
+		date: "02.01.2006", dateTime: "02.01.2006 15:04:05",
+		number: domain.NumberStyle{Decimal: ",", Group: ".", SymbolAfter: true},
+	},
+}
+
+// Match returns the supported locale closest to the languages of an
+// Accept-Language header, a locale such as "tr-TR" or a POSIX locale such as
+// "de_DE.UTF-8", or the default locale when none is close
+func Match(preferences ...string) string {
+	var tags []language.Tag
+	for _, preference := range preferences {
+		if !strings.ContainsAny(preference, ",;") {
+			// POSIX locales carry an encoding and modifier: de_DE.UTF-8@euro
+			preference, _, _ = strings.Cut(preference, ".")
+			preference, _, _ = strings.Cut(preference, "@")
+		}
+		parsed, _, err := language.ParseAcceptLanguage(strings.ReplaceAll(preference, "_", "-"))
+		if err == nil {
+			tags = append(tags, parsed...)
+		}
+	}
+	if len(tags) == 0 {
+		return domain.DefaultLocale
+	}
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return domain.DefaultLocale
+	}
+	return domain.SupportedLocales[index]
+}
+
+// Localizer translates and formats for one locale
+type Localizer struct {
+	Locale    string
+	localizer *goi18n.Localizer
+	format    format
+}
+
+// New returns the localizer of the locale, or of the default locale when the
+// locale is not supported
+func New(locale string) *Localizer {
+	if !slices.Contains(domain.SupportedLocales, locale) {
+		locale = domain.DefaultLocale
+	}
+	return &Localizer{
+		Locale:    locale,
+		localizer: goi18n.NewLocalizer(bundle, locale),
+		format:    formats[locale],
+	}
+}
+
+// WithPreferences returns a copy of the localizer that writes dates and
+// numbers in the user's preferred formats where they set one
+func (l *Localizer) WithPreferences(preferences domain.Preferences) *Localizer {
+	preferred := *l
+	if layout := preferences.DateLayout(); layout != "" {
+		preferred.format.date = layout
+		preferred.format.dateTime = layout + " 15:04:05"
+	}
+	if style, ok := preferences.NumberStyle(); ok {
+		preferred.format.number = style
+	}
+	return &preferred
+}
+
+// T returns the message with the id, filled in with data. Messages no locale
+// has come back as their id.
+func (l *Localizer) T(id string, data ...map[string]any) string {
+	config := &goi18n.LocalizeConfig{MessageID: id}
+	if len(data) > 0 {
+		config.TemplateData = data[0]
+	}
+	// Messages only English has come back with a not found error
+	message, err := l.localizer.Localize(config)
+	var notFound *goi18n.MessageNotFoundErr
+	if message == "" || (err != nil && !errors.As(err, &notFound)) {
+		return id
+	}
+	return message
+}
+
+// Message translates an English API message, or returns it as it is when
+// there is no translation
+func (l *Localizer) Message(message string) string {
+	return l.lookup("errors."+message, message)
+}
+
+// Category translates the name of a default category. Names users gave
+// their own categories are returned as they are.
+func (l *Localizer) Category(name string) string {
+	return l.lookup("categories."+name, name)
+}
+
+func (l *Localizer) lookup(id, fallback string) string {
+	if translated := l.T(id); translated != id {
+		return translated
+	}
+	return fallback
+}
+
+// Date formats the day of t, e.g. 2024-01-31 or 31.01.2024
+func (l *Localizer) Date(t time.Time) string {
+	return t.Format(l.format.date)
+}
+
+// DateTime formats t to the second
+func (l *Localizer) DateTime(t time.Time) string {
+	return t.Format(l.format.dateTime)
+}
+
+// Number formats the value with the decimals, without thousands separators
+// so spreadsheets read it back, e.g. 1234.50 or 1234,50
+func (l *Localizer) Number(value float64, decimals int) string {
+	return strings.Replace(strconv.FormatFloat(value, 'f', decimals, 64), ".", l.format.number.Decimal, 1)
+}
+
+// Percent formats a percentage with two decimals, e.g. 12.50%
+func (l *Localizer) Percent(value float64) string {
+	return l.Number(value, 2) + "%"
+}
+
+// Money formats the amount in the currency for display, e.g. $1,234.56 or
+// 1.234,56 $
+func (l *Localizer) Money(amount domain.Money, currency string) string {
+	return amount.FormatStyle(currency, l.format.number)
+}
+
+// Month names the month, e.g. January or Ocak
+func (l *Localizer) Month(month time.Month) string {
+	return l.T("months." + strings.ToLower(month.String()))
+}
+
+// ReportTitle names the report, e.g. "January 2024 Monthly Report"
+func (l *Localizer) ReportTitle(report *domain.FinancialReport) string {
+	year := report.StartDate.Year()
+	switch report.ReportType {
+	case domain.ReportTypeMonthly:
+		return l.T("report.title_monthly", map[string]any{"Month": l.Month(report.StartDate.Month()), "Year": year})
+	case domain.ReportTypeQuarterly:
+		quarter := (report.StartDate.Month()-1)/3 + 1
+		return l.T("report.title_quarterly", map[string]any{"Quarter": int(quarter), "Year": year})
+	case domain.ReportTypeYearly:
+		return l.T("report.title_yearly", map[string]any{"Year": year})
+	case domain.ReportTypeCustom:
+		return l.T("report.title_custom", map[string]any{"Start": l.Date(report.StartDate), "End": l.Date(report.EndDate)})
+	default:
+		return l.T("report.title")
+	}
+}
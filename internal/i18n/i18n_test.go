@@ -0,0 +1,96 @@
+package i18n
+
+import (
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		preferences []string
+		want        string
+	}{
+		{[]string{"tr-TR,tr;q=0.9,en;q=0.8"}, domain.LocaleTurkish},
+		{[]string{"de_DE.UTF-8"}, domain.LocaleGerman},
+		{[]string{"fr-FR"}, domain.LocaleEnglish},
+		{[]string{"", "de"}, domain.LocaleGerman},
+		{[]string{"C"}, domain.LocaleEnglish},
+		{nil, domain.LocaleEnglish},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Match(tt.preferences...), "%q", tt.preferences)
+	}
+}
+
+func TestLocalizer_T(t *testing.T) {
+	tr := New(domain.LocaleTurkish)
+
+	assert.Equal(t, "ANA MENÜ - Hoş geldiniz Ada", tr.T("menu.main_title", map[string]any{"Name": "Ada"}))
+	assert.Equal(t, "Deutsch", tr.T("languages.de"), "untranslated messages fall back to English")
+	assert.Equal(t, "menu.unknown", tr.T("menu.unknown"))
+	assert.Equal(t, domain.LocaleEnglish, New("xx").Locale)
+}
+
+func TestLocalizer_MessageAndCategory(t *testing.T) {
+	de := New(domain.LocaleGerman)
+
+	assert.Equal(t, "Benutzer nicht gefunden", de.Message("User not found"))
+	assert.Equal(t, "Invalid alert ID", de.Message("Invalid alert ID"))
+	assert.Equal(t, "Essen & Trinken", de.Category("Food & Dining"))
+	assert.Equal(t, "Groceries", de.Category("Groceries"))
+}
+
+func TestLocalizer_Formatting(t *testing.T) {
+	day := time.Date(2024, 3, 9, 14, 5, 0, 0, time.UTC)
+	en, tr := New(domain.LocaleEnglish), New(domain.LocaleTurkish)
+
+	assert.Equal(t, "2024-03-09", en.Date(day))
+	assert.Equal(t, "09.03.2024 14:05:00", tr.DateTime(day))
+	assert.Equal(t, "1234.50", en.Number(1234.5, 2))
+	assert.Equal(t, "1234,50", tr.Number(1234.5, 2))
+	assert.Equal(t, "12,35%", tr.Percent(12.345))
+	assert.Equal(t, "$1,234.56", en.Money(123456, "USD"))
+	assert.Equal(t, "1.234,56 ₺", tr.Money(123456, "TRY"))
+	assert.Equal(t, "Mart", tr.Month(time.March))
+}
+
+func TestLocalizer_WithPreferences(t *testing.T) {
+	day := time.Date(2024, 3, 9, 14, 5, 0, 0, time.UTC)
+	en := New(domain.LocaleEnglish)
+	preferred := en.WithPreferences(domain.Preferences{DateFormat: "DD/MM/YYYY", NumberFormat: "1 234,56"})
+
+	assert.Equal(t, "09/03/2024", preferred.Date(day))
+	assert.Equal(t, "09/03/2024 14:05:00", preferred.DateTime(day))
+	assert.Equal(t, "1234,50", preferred.Number(1234.5, 2))
+	assert.Equal(t, "1 234,56 €", preferred.Money(123456, "EUR"))
+	assert.Equal(t, "2024-03-09", en.Date(day), "the locale's formats stay as they are")
+	assert.Equal(t, "2024-03-09", en.WithPreferences(domain.Preferences{}).Date(day))
+}
+
+func TestLocalizer_ReportTitle(t *testing.T) {
+	report := &domain.FinancialReport{
+		ReportType: domain.ReportTypeMonthly,
+		StartDate:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:    time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+	}
+
+	assert.Equal(t, report.GetReportTitle(), New(domain.LocaleEnglish).ReportTitle(report))
+	assert.Equal(t, "Ocak 2024 Aylık Raporu", New(domain.LocaleTurkish).ReportTitle(report))
+
+	report.ReportType = domain.ReportTypeQuarterly
+	assert.Equal(t, "Quartalsbericht Q1 2024", New(domain.LocaleGerman).ReportTitle(report))
+}
+
+func TestMessageFiles(t *testing.T) {
+	english := New(domain.LocaleEnglish)
+	for _, category := range append(domain.GetDefaultCategories(), domain.UncategorizedCategory()) {
+		assert.NotEqual(t, "categories."+category.Name, english.T("categories."+category.Name), category.Name)
+	}
+	for _, locale := range domain.SupportedLocales {
+		assert.NotEqual(t, "languages."+locale, english.T("languages."+locale), locale)
+	}
+}
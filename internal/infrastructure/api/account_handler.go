@@ -0,0 +1,221 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AccountServiceInterface defines the interface for account operations
+type AccountServiceInterface interface {
+	Create(ctx context.Context, userID uint, account *domain.Account) error
+	List(ctx context.Context, userID uint) ([]domain.Account, error)
+	Get(ctx context.Context, userID, accountID uint) (*domain.Account, error)
+	Update(ctx context.Context, userID, accountID uint, updates *domain.Account) (*domain.Account, error)
+	Delete(ctx context.Context, userID, accountID uint) error
+	Statement(ctx context.Context, userID, accountID uint, month time.Time) (*domain.AccountStatement, error)
+}
+
+type AccountHandler struct {
+	Service AccountServiceInterface
+}
+
+func NewAccountHandler(service AccountServiceInterface) *AccountHandler {
+	return &AccountHandler{Service: service}
+}
+
+// AccountRequest is the body of account creation and updates. The account
+// number is only ever sent in; responses show its last four characters.
+type AccountRequest struct {
+	Name           string       `json:"name"`
+	Type           string       `json:"type"`
+	Institution    string       `json:"institution"`
+	AccountNumber  string       `json:"account_number"`
+	Currency       string       `json:"currency"`
+	OpeningBalance domain.Money `json:"opening_balance"`
+}
+
+func (r AccountRequest) account() *domain.Account {
+	return &domain.Account{
+		Name:           r.Name,
+		Type:           r.Type,
+		Institution:    r.Institution,
+		AccountNumber:  r.AccountNumber,
+		Currency:       r.Currency,
+		OpeningBalance: r.OpeningBalance,
+	}
+}
+
+// accountIDParam reads the accountId parameter
+func accountIDParam(c *gin.Context) (uint, bool) {
+	accountID, err := strconv.ParseUint(c.Param("accountId"), 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid account ID")
+		return 0, false
+	}
+	return uint(accountID), true
+}
+
+// statementMonthQuery parses the month query parameter, e.g. 2024-06,
+// defaulting to the current month
+func statementMonthQuery(c *gin.Context) (time.Time, bool) {
+	value := c.Query("month")
+	if value == "" {
+		now := time.Now()
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC), true
+	}
+	month, err := time.Parse(domain.StatementMonthLayout, value)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidDate, "Invalid month format. Use YYYY-MM")
+		return time.Time{}, false
+	}
+	return month, true
+}
+
+// Create adds an account for the user
+func (h *AccountHandler) Create(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req AccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	account := req.account()
+	err := h.Service.Create(c.Request.Context(), userID, account)
+	if respondValidationError(c, err) {
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to create account", err)
+		return
+	}
+	c.JSON(http.StatusCreated, account)
+}
+
+// List returns the user's accounts
+func (h *AccountHandler) List(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	accounts, err := h.Service.List(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve accounts", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"accounts": accounts, "count": len(accounts)})
+}
+
+// Get returns one of the user's accounts
+func (h *AccountHandler) Get(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+	accountID, ok := accountIDParam(c)
+	if !ok {
+		return
+	}
+
+	account, err := h.Service.Get(c.Request.Context(), userID, accountID)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "Account not found")
+	case err != nil:
+		respondInternalError(c, "Failed to retrieve account", err)
+	default:
+		c.JSON(http.StatusOK, account)
+	}
+}
+
+// Update replaces the fields of one of the user's accounts
+func (h *AccountHandler) Update(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+	accountID, ok := accountIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req AccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	account, err := h.Service.Update(c.Request.Context(), userID, accountID, req.account())
+	if respondValidationError(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "Account not found")
+	case err != nil:
+		respondInternalError(c, "Failed to update account", err)
+	default:
+		c.JSON(http.StatusOK, account)
+	}
+}
+
+// Delete removes one of the user's accounts
+func (h *AccountHandler) Delete(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+	accountID, ok := accountIDParam(c)
+	if !ok {
+		return
+	}
+
+	err := h.Service.Delete(c.Request.Context(), userID, accountID)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "Account not found")
+	case err != nil:
+		respondInternalError(c, "Failed to delete account", err)
+	default:
+		c.JSON(http.StatusOK, gin.H{"message": "Account deleted"})
+	}
+}
+
+// Statement returns the monthly statement of one of the user's accounts
+func (h *AccountHandler) Statement(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+	accountID, ok := accountIDParam(c)
+	if !ok {
+		return
+	}
+	month, ok := statementMonthQuery(c)
+	if !ok {
+		return
+	}
+
+	statement, err := h.Service.Statement(c.Request.Context(), userID, accountID, month)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "Account not found")
+	case err != nil:
+		respondInternalError(c, "Failed to build account statement", err)
+	default:
+		c.JSON(http.StatusOK, statement)
+	}
+}
@@ -0,0 +1,228 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockAccountService is a mock implementation of AccountServiceInterface
+type MockAccountService struct {
+	mock.Mock
+}
+
+func (m *MockAccountService) Create(ctx context.Context, userID uint, account *domain.Account) error {
+	return m.Called(ctx, userID, account).Error(0)
+}
+
+func (m *MockAccountService) List(ctx context.Context, userID uint) ([]domain.Account, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.Account), args.Error(1)
+}
+
+func (m *MockAccountService) Get(ctx context.Context, userID, accountID uint) (*domain.Account, error) {
+	args := m.Called(ctx, userID, accountID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Account), args.Error(1)
+}
+
+func (m *MockAccountService) Update(ctx context.Context, userID, accountID uint, updates *domain.Account) (*domain.Account, error) {
+	args := m.Called(ctx, userID, accountID, updates)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Account), args.Error(1)
+}
+
+func (m *MockAccountService) Delete(ctx context.Context, userID, accountID uint) error {
+	return m.Called(ctx, userID, accountID).Error(0)
+}
+
+func (m *MockAccountService) Statement(ctx context.Context, userID, accountID uint, month time.Time) (*domain.AccountStatement, error) {
+	args := m.Called(ctx, userID, accountID, month)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AccountStatement), args.Error(1)
+}
+
+func setupAccountRouter(service *MockAccountService, authUserID uint) *gin.Engine {
+	handler := NewAccountHandler(service)
+	router := setupGin()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", authUserID)
+		c.Next()
+	})
+	router.GET("/users/:userId/accounts", handler.List)
+	router.POST("/users/:userId/accounts", handler.Create)
+	router.GET("/users/:userId/accounts/:accountId", handler.Get)
+	router.PUT("/users/:userId/accounts/:accountId", handler.Update)
+	router.DELETE("/users/:userId/accounts/:accountId", handler.Delete)
+	router.GET("/users/:userId/accounts/:accountId/statement", handler.Statement)
+	return router
+}
+
+func TestAccountHandler_CreateMasksAccountNumber(t *testing.T) {
+	service := new(MockAccountService)
+	service.On("Create", mock.Anything, uint(1), mock.MatchedBy(func(a *domain.Account) bool {
+		return a.AccountNumber == "DE89370400440532013000" && a.OpeningBalance == domain.NewMoney(100)
+	})).Run(func(args mock.Arguments) {
+		account := args.Get(2).(*domain.Account)
+		account.ID = 3
+		account.Mask()
+	}).Return(nil)
+	router := setupAccountRouter(service, 1)
+
+	body := `{"name":"Main","type":"checking","account_number":"DE89370400440532013000","currency":"EUR","opening_balance":100}`
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/users/1/accounts", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.NotContains(t, w.Body.String(), "DE89370400440532013000")
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "••••3000", response["account_number"])
+	service.AssertExpectations(t)
+}
+
+func TestAccountHandler_CreateInvalid(t *testing.T) {
+	service := new(MockAccountService)
+	invalid := &domain.ValidationError{Fields: []domain.FieldError{{Field: "type", Message: "must be one of checking"}}}
+	service.On("Create", mock.Anything, uint(1), mock.Anything).Return(invalid)
+	router := setupAccountRouter(service, 1)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/users/1/accounts", strings.NewReader(`{"name":"Main","type":"piggy_bank"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestAccountHandler_GetUpdateDelete(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		setup      func(*MockAccountService)
+		wantStatus int
+	}{
+		{
+			name: "should return the account", method: http.MethodGet, path: "/users/1/accounts/3",
+			setup: func(s *MockAccountService) {
+				s.On("Get", mock.Anything, uint(1), uint(3)).Return(&domain.Account{ID: 3, UserID: 1}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "should report missing accounts", method: http.MethodGet, path: "/users/1/accounts/4",
+			setup: func(s *MockAccountService) {
+				s.On("Get", mock.Anything, uint(1), uint(4)).Return(nil, domain.ErrNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "should reject invalid IDs", method: http.MethodGet, path: "/users/1/accounts/abc",
+			setup: func(*MockAccountService) {}, wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "should deny other users' accounts", method: http.MethodGet, path: "/users/2/accounts/3",
+			setup: func(*MockAccountService) {}, wantStatus: http.StatusForbidden,
+		},
+		{
+			name: "should update the account", method: http.MethodPut, path: "/users/1/accounts/3",
+			body: `{"name":"Joint","type":"savings"}`,
+			setup: func(s *MockAccountService) {
+				s.On("Update", mock.Anything, uint(1), uint(3), mock.Anything).Return(&domain.Account{ID: 3, Name: "Joint"}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "should delete the account", method: http.MethodDelete, path: "/users/1/accounts/3",
+			setup: func(s *MockAccountService) {
+				s.On("Delete", mock.Anything, uint(1), uint(3)).Return(nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(MockAccountService)
+			tt.setup(service)
+			router := setupAccountRouter(service, 1)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAccountHandler_Statement(t *testing.T) {
+	june := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		path       string
+		setup      func(*MockAccountService)
+		wantStatus int
+	}{
+		{
+			name: "should return the month's statement", path: "/users/1/accounts/3/statement?month=2024-06",
+			setup: func(s *MockAccountService) {
+				statement := domain.NewAccountStatement(domain.Account{ID: 3}, june, domain.NewMoney(100), nil)
+				s.On("Statement", mock.Anything, uint(1), uint(3), june).Return(&statement, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "should reject malformed months", path: "/users/1/accounts/3/statement?month=06-2024",
+			setup: func(*MockAccountService) {}, wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "should report missing accounts", path: "/users/1/accounts/4/statement?month=2024-06",
+			setup: func(s *MockAccountService) {
+				s.On("Statement", mock.Anything, uint(1), uint(4), june).Return(nil, domain.ErrNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "should deny other users' accounts", path: "/users/2/accounts/3/statement",
+			setup: func(*MockAccountService) {}, wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(MockAccountService)
+			tt.setup(service)
+			router := setupAccountRouter(service, 1)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			service.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultAdviceHistoryLimit = 20
+	maxAdviceHistoryLimit     = 100
+)
+
+// AdviceHistoryServiceInterface defines the interface for the advice history
+type AdviceHistoryServiceInterface interface {
+	List(ctx context.Context, filter domain.AdviceFilter) ([]domain.AdviceRecord, error)
+	Get(ctx context.Context, userID, id uint) (*domain.AdviceRecord, error)
+	Compare(ctx context.Context, userID, fromID, toID uint) (*domain.AdviceComparison, error)
+	SetRecommendationStatus(ctx context.Context, userID, recommendationID uint, status string) (*domain.Recommendation, error)
+}
+
+type AdviceHistoryHandler struct {
+	Service AdviceHistoryServiceInterface
+}
+
+func NewAdviceHistoryHandler(service AdviceHistoryServiceInterface) *AdviceHistoryHandler {
+	return &AdviceHistoryHandler{Service: service}
+}
+
+type RecommendationStatusRequest struct {
+	Status string `json:"status" binding:"required"`
+}
+
+// adviceUserID reads the userId parameter. Users can only see their own advice.
+func adviceUserID(c *gin.Context) (uint, bool) {
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid user ID")
+		return 0, false
+	}
+	if authUserID, ok := c.Get("userID"); ok && authUserID != uint(userID) {
+		respondError(c, middleware.CodeForbidden, "Access denied")
+		return 0, false
+	}
+	return uint(userID), true
+}
+
+func respondAdviceError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "Advice not found")
+	case errors.Is(err, domain.ErrInvalidRecommendationStatus):
+		respondError(c, middleware.CodeBadRequest, err.Error())
+	default:
+		respondInternalError(c, message, err)
+	}
+}
+
+// List returns the user's past advice newest first, optionally narrowed by
+// source (basic, realtime, risk_assessment) and since/until (YYYY-MM-DD)
+func (h *AdviceHistoryHandler) List(c *gin.Context) {
+	userID, ok := adviceUserID(c)
+	if !ok {
+		return
+	}
+
+	filter := domain.AdviceFilter{UserID: userID, Source: c.Query("source"), Limit: defaultAdviceHistoryLimit}
+	if since := c.Query("since"); since != "" {
+		start, err := time.Parse("2006-01-02", since)
+		if err != nil {
+			respondError(c, middleware.CodeInvalidDate, "Invalid since date format. Use YYYY-MM-DD")
+			return
+		}
+		filter.Since = &start
+	}
+	if until := c.Query("until"); until != "" {
+		end, err := time.Parse("2006-01-02", until)
+		if err != nil {
+			respondError(c, middleware.CodeInvalidDate, "Invalid until date format. Use YYYY-MM-DD")
+			return
+		}
+		end = end.Add(24*time.Hour - time.Nanosecond)
+		filter.Until = &end
+	}
+	if limit, err := strconv.Atoi(c.Query("limit")); err == nil && limit > 0 {
+		filter.Limit = min(limit, maxAdviceHistoryLimit)
+	}
+	if offset, err := strconv.Atoi(c.Query("offset")); err == nil && offset > 0 {
+		filter.Offset = offset
+	}
+
+	records, err := h.Service.List(c.Request.Context(), filter)
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve advice history", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"advice": records, "count": len(records)})
+}
+
+// Get returns one piece of past advice with its recommendations
+func (h *AdviceHistoryHandler) Get(c *gin.Context) {
+	userID, ok := adviceUserID(c)
+	if !ok {
+		return
+	}
+	adviceID, err := strconv.ParseUint(c.Param("adviceId"), 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid advice ID")
+		return
+	}
+
+	record, err := h.Service.Get(c.Request.Context(), userID, uint(adviceID))
+	if err != nil {
+		respondAdviceError(c, err, "Failed to retrieve advice")
+		return
+	}
+	c.JSON(http.StatusOK, record)
+}
+
+// Compare shows how the advice changed between two records, given as from
+// and to. Without them the latest advice is compared with the one before it.
+func (h *AdviceHistoryHandler) Compare(c *gin.Context) {
+	userID, ok := adviceUserID(c)
+	if !ok {
+		return
+	}
+	fromID, err := parseOptionalID(c.Query("from"))
+	if err != nil {
+		respondError(c, middleware.CodeInvalidDate, "Invalid from date")
+		return
+	}
+	toID, err := parseOptionalID(c.Query("to"))
+	if err != nil {
+		respondError(c, middleware.CodeInvalidDate, "Invalid to date")
+		return
+	}
+
+	comparison, err := h.Service.Compare(c.Request.Context(), userID, fromID, toID)
+	if err != nil {
+		respondAdviceError(c, err, "Failed to compare advice")
+		return
+	}
+	c.JSON(http.StatusOK, comparison)
+}
+
+// SetRecommendationStatus marks a recommendation as followed, ignored or pending
+func (h *AdviceHistoryHandler) SetRecommendationStatus(c *gin.Context) {
+	userID, ok := adviceUserID(c)
+	if !ok {
+		return
+	}
+	recommendationID, err := strconv.ParseUint(c.Param("recommendationId"), 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid recommendation ID")
+		return
+	}
+
+	var req RecommendationStatusRequest
+	if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
+		respondError(c, middleware.CodeInvalidBody, bindErr.Error())
+		return
+	}
+
+	recommendation, err := h.Service.SetRecommendationStatus(c.Request.Context(), userID, uint(recommendationID), req.Status)
+	if errors.Is(err, domain.ErrNotFound) {
+		respondError(c, middleware.CodeNotFound, "Recommendation not found")
+		return
+	}
+	if err != nil {
+		respondAdviceError(c, err, "Failed to update recommendation")
+		return
+	}
+	c.JSON(http.StatusOK, recommendation)
+}
@@ -0,0 +1,224 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/pkg"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockAdviceHistoryService is a mock implementation of AdviceHistoryServiceInterface
+type MockAdviceHistoryService struct {
+	mock.Mock
+}
+
+func (m *MockAdviceHistoryService) List(ctx context.Context, filter domain.AdviceFilter) ([]domain.AdviceRecord, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.AdviceRecord), args.Error(1)
+}
+
+func (m *MockAdviceHistoryService) Get(ctx context.Context, userID, id uint) (*domain.AdviceRecord, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AdviceRecord), args.Error(1)
+}
+
+func (m *MockAdviceHistoryService) Compare(ctx context.Context, userID, fromID, toID uint) (*domain.AdviceComparison, error) {
+	args := m.Called(ctx, userID, fromID, toID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AdviceComparison), args.Error(1)
+}
+
+func (m *MockAdviceHistoryService) SetRecommendationStatus(
+	ctx context.Context, userID, recommendationID uint, status string,
+) (*domain.Recommendation, error) {
+	args := m.Called(ctx, userID, recommendationID, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Recommendation), args.Error(1)
+}
+
+// MockAdviceRecorder is a mock implementation of AdviceRecorderInterface
+type MockAdviceRecorder struct {
+	mock.Mock
+}
+
+func (m *MockAdviceRecorder) RecordPersonalizedAdvice(ctx context.Context, advice *pkg.InvestmentRecommendation, monthlyIncome float64) {
+	m.Called(ctx, advice, monthlyIncome)
+	advice.AdviceID = 7
+}
+
+func (m *MockAdviceRecorder) RecordRiskAssessment(ctx context.Context, assessment *pkg.AIRiskAssessment, monthlyIncome float64) {
+	m.Called(ctx, assessment, monthlyIncome)
+	assessment.AdviceID = 8
+}
+
+func TestAdviceHistoryHandler_List(t *testing.T) {
+	t.Run("should list the user's advice", func(t *testing.T) {
+		mockService := new(MockAdviceHistoryService)
+		handler := NewAdviceHistoryHandler(mockService)
+		router := setupGin()
+		router.GET("/users/:userId/advice/history", handler.List)
+
+		mockService.On("List", mock.Anything, mock.MatchedBy(func(filter domain.AdviceFilter) bool {
+			return filter.UserID == 1 && filter.Source == domain.AdviceSourceBasic &&
+				filter.Limit == maxAdviceHistoryLimit && filter.Since != nil
+		})).Return([]domain.AdviceRecord{{ID: 3, UserID: 1, Source: domain.AdviceSourceBasic}}, nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet,
+			"/users/1/advice/history?source=basic&since=2024-01-01&limit=1000", http.NoBody))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			Advice []domain.AdviceRecord `json:"advice"`
+			Count  int                   `json:"count"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 1, response.Count)
+		assert.Equal(t, uint(3), response.Advice[0].ID)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should forbid other users' advice", func(t *testing.T) {
+		mockService := new(MockAdviceHistoryService)
+		handler := NewAdviceHistoryHandler(mockService)
+		router := setupGin()
+		router.GET("/users/:userId/advice/history", func(c *gin.Context) {
+			c.Set("userID", uint(2))
+			handler.List(c)
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1/advice/history", http.NoBody))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		mockService.AssertNotCalled(t, "List")
+	})
+}
+
+func TestAdviceHistoryHandler_Get(t *testing.T) {
+	mockService := new(MockAdviceHistoryService)
+	handler := NewAdviceHistoryHandler(mockService)
+	router := setupGin()
+	router.GET("/users/:userId/advice/history/:adviceId", handler.Get)
+
+	mockService.On("Get", mock.Anything, uint(1), uint(9)).Return(nil, domain.ErrNotFound)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1/advice/history/9", http.NoBody))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestAdviceHistoryHandler_Compare(t *testing.T) {
+	mockService := new(MockAdviceHistoryService)
+	handler := NewAdviceHistoryHandler(mockService)
+	router := setupGin()
+	router.GET("/users/:userId/advice/compare", handler.Compare)
+
+	comparison := domain.CompareAdvice(
+		domain.AdviceRecord{ID: 1, RiskProfile: "moderate"},
+		domain.AdviceRecord{ID: 2, RiskProfile: "aggressive"},
+	)
+	mockService.On("Compare", mock.Anything, uint(1), uint(1), uint(0)).Return(&comparison, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1/advice/compare?from=1", http.NoBody))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response domain.AdviceComparison
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.RiskProfileChanged)
+	mockService.AssertExpectations(t)
+}
+
+func TestAdviceHistoryHandler_SetRecommendationStatus(t *testing.T) {
+	setup := func() (*gin.Engine, *MockAdviceHistoryService) {
+		mockService := new(MockAdviceHistoryService)
+		handler := NewAdviceHistoryHandler(mockService)
+		router := setupGin()
+		router.PUT("/users/:userId/advice/recommendations/:recommendationId", handler.SetRecommendationStatus)
+		return router, mockService
+	}
+
+	t.Run("should mark the recommendation", func(t *testing.T) {
+		router, mockService := setup()
+		mockService.On("SetRecommendationStatus", mock.Anything, uint(1), uint(4), domain.RecommendationStatusFollowed).
+			Return(&domain.Recommendation{ID: 4, Status: domain.RecommendationStatusFollowed}, nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/users/1/advice/recommendations/4",
+			strings.NewReader(`{"status": "followed"}`)))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should reject unknown statuses", func(t *testing.T) {
+		router, mockService := setup()
+		mockService.On("SetRecommendationStatus", mock.Anything, uint(1), uint(4), "maybe").
+			Return(nil, domain.ErrInvalidRecommendationStatus)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/users/1/advice/recommendations/4",
+			strings.NewReader(`{"status": "maybe"}`)))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should report unknown recommendations", func(t *testing.T) {
+		router, mockService := setup()
+		mockService.On("SetRecommendationStatus", mock.Anything, uint(1), uint(5), "ignored").
+			Return(nil, domain.ErrNotFound)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/users/1/advice/recommendations/5",
+			strings.NewReader(`{"status": "ignored"}`)))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestAdvisorHandler_RecordsGeneratedAdvice(t *testing.T) {
+	handler, _, mockUserService, mockMarketService := setupAdvisorHandler()
+	recorder := new(MockAdviceRecorder)
+	handler.History = recorder
+	router := setupGin()
+	router.GET("/users/:userId/advice/realtime", handler.GetRealTimeAdvice)
+
+	// The income comes from the profile without a monthly_income parameter
+	user := domain.User{ID: 1, RiskTolerance: "moderate", UserProfile: domain.UserProfile{MonthlyIncome: domain.NewMoney(5000)}}
+	advice := &pkg.InvestmentRecommendation{UserID: 1, RiskProfile: "moderate"}
+	mockUserService.On("GetByID", mock.Anything, uint(1)).Return(user, nil)
+	mockMarketService.On("GeneratePersonalizedAdvice", mock.Anything, &user, 5000.0).Return(advice, nil)
+	recorder.On("RecordPersonalizedAdvice", mock.Anything, advice, 5000.0).Return()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1/advice/realtime", http.NoBody))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response pkg.InvestmentRecommendation
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, uint(7), response.AdviceID)
+	recorder.AssertExpectations(t)
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BacktestServiceInterface defines the interface for replaying past advice
+type BacktestServiceInterface interface {
+	Performance(ctx context.Context, filter domain.BacktestFilter) (*domain.AdvicePerformance, error)
+}
+
+type AdvicePerformanceHandler struct {
+	Service BacktestServiceInterface
+}
+
+func NewAdvicePerformanceHandler(service BacktestServiceInterface) *AdvicePerformanceHandler {
+	return &AdvicePerformanceHandler{Service: service}
+}
+
+// GetPerformance shows what following the user's past advice would have
+// returned, optionally narrowed by source, status (followed, ignored,
+// pending) and since/until, and valued at a past date with at (YYYY-MM-DD)
+func (h *AdvicePerformanceHandler) GetPerformance(c *gin.Context) {
+	userID, ok := adviceUserID(c)
+	if !ok {
+		return
+	}
+	filter, err := parseBacktestFilter(c)
+	if err != nil {
+		respondError(c, middleware.CodeBadRequest, err.Error())
+		return
+	}
+	filter.UserID = userID
+
+	performance, err := h.Service.Performance(c.Request.Context(), filter)
+	if err != nil {
+		respondAdviceError(c, err, "Failed to calculate advice performance")
+		return
+	}
+	c.JSON(http.StatusOK, performance)
+}
+
+// parseBacktestFilter reads source, status and the since, until and at dates
+// from the query string. Until and at include the whole day.
+func parseBacktestFilter(c *gin.Context) (domain.BacktestFilter, error) {
+	filter := domain.BacktestFilter{Source: c.Query("source"), Status: c.Query("status")}
+
+	if since := c.Query("since"); since != "" {
+		start, err := time.Parse("2006-01-02", since)
+		if err != nil {
+			return filter, errors.New("invalid since date format. Use YYYY-MM-DD")
+		}
+		filter.Since = &start
+	}
+	if until := c.Query("until"); until != "" {
+		end, err := time.Parse("2006-01-02", until)
+		if err != nil {
+			return filter, errors.New("invalid until date format. Use YYYY-MM-DD")
+		}
+		end = end.Add(24*time.Hour - time.Nanosecond)
+		filter.Until = &end
+	}
+	if at := c.Query("at"); at != "" {
+		day, err := time.Parse("2006-01-02", at)
+		if err != nil {
+			return filter, errors.New("invalid at date format. Use YYYY-MM-DD")
+		}
+		day = day.Add(24*time.Hour - time.Nanosecond)
+		filter.At = &day
+	}
+
+	return filter, nil
+}
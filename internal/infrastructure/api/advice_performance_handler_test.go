@@ -0,0 +1,76 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockBacktestService is a mock implementation of BacktestServiceInterface
+type MockBacktestService struct {
+	mock.Mock
+}
+
+func (m *MockBacktestService) Performance(ctx context.Context, filter domain.BacktestFilter) (*domain.AdvicePerformance, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AdvicePerformance), args.Error(1)
+}
+
+func TestAdvicePerformanceHandler_GetPerformance(t *testing.T) {
+	setup := func() (*MockBacktestService, func(url string) *httptest.ResponseRecorder) {
+		mockService := new(MockBacktestService)
+		handler := NewAdvicePerformanceHandler(mockService)
+		router := setupGin()
+		router.GET("/users/:userId/advice/performance", handler.GetPerformance)
+		return mockService, func(url string) *httptest.ResponseRecorder {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, http.NoBody))
+			return w
+		}
+	}
+
+	t.Run("should return the replayed performance", func(t *testing.T) {
+		mockService, get := setup()
+		mockService.On("Performance", mock.Anything, mock.MatchedBy(func(filter domain.BacktestFilter) bool {
+			return filter.UserID == 1 && filter.Status == domain.RecommendationStatusFollowed &&
+				filter.At != nil && filter.At.Format("2006-01-02") == "2024-06-30"
+		})).Return(&domain.AdvicePerformance{Totals: domain.PerformanceTotals{Invested: 100, Value: 110, Gain: 10, ReturnPct: 10}}, nil)
+
+		w := get("/users/1/advice/performance?status=followed&at=2024-06-30")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response domain.AdvicePerformance
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 10.0, response.Totals.ReturnPct)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should reject invalid dates", func(t *testing.T) {
+		mockService, get := setup()
+
+		w := get("/users/1/advice/performance?at=yesterday")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "Performance")
+	})
+
+	t.Run("should reject unknown statuses", func(t *testing.T) {
+		mockService, get := setup()
+		mockService.On("Performance", mock.Anything, mock.Anything).Return(nil, domain.ErrInvalidRecommendationStatus)
+
+		w := get("/users/1/advice/performance?status=maybe")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
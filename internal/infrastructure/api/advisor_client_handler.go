@@ -0,0 +1,237 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdvisorClientServiceInterface defines the interface for the access
+// clients grant advisor accounts
+type AdvisorClientServiceInterface interface {
+	Grant(ctx context.Context, clientID uint, email, access string) (*domain.AdvisorConnection, error)
+	ListAdvisors(ctx context.Context, clientID uint) ([]domain.AdvisorConnection, error)
+	UpdateAccess(ctx context.Context, clientID, advisorID uint, access string) (*domain.AdvisorGrant, error)
+	Revoke(ctx context.Context, clientID, advisorID uint) error
+	ListClients(ctx context.Context, advisorID uint) ([]domain.AdvisorConnection, error)
+	ClientDashboard(ctx context.Context, advisorID, clientID uint, period string) (*domain.DashboardSummary, error)
+	Recommend(ctx context.Context, advisorID, clientID uint, message string) (*domain.AdvisorRecommendation, error)
+	ListRecommendations(ctx context.Context, advisorID, clientID uint) ([]domain.AdvisorRecommendation, error)
+}
+
+// AdvisorClientHandler serves clients the advisors they granted access and
+// advisors their clients. The advisor is always the authenticated user.
+type AdvisorClientHandler struct {
+	Service AdvisorClientServiceInterface
+}
+
+func NewAdvisorClientHandler(service AdvisorClientServiceInterface) *AdvisorClientHandler {
+	return &AdvisorClientHandler{Service: service}
+}
+
+type GrantAdvisorRequest struct {
+	Email  string `json:"email" binding:"required,email"`
+	Access string `json:"access" binding:"required,oneof=read comment"`
+}
+
+type UpdateAdvisorAccessRequest struct {
+	Access string `json:"access" binding:"required,oneof=read comment"`
+}
+
+type AdvisorRecommendationRequest struct {
+	Message string `json:"message" binding:"required"`
+}
+
+// Grant gives the advisor account with the email access to the user's
+// finances
+func (h *AdvisorClientHandler) Grant(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req GrantAdvisorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	connection, err := h.Service.Grant(c.Request.Context(), userID, req.Email, req.Access)
+	if err != nil {
+		respondAdvisorClientError(c, err, "Failed to grant advisor access")
+		return
+	}
+	c.JSON(http.StatusCreated, connection)
+}
+
+// ListAdvisors returns the advisors the user granted access to
+func (h *AdvisorClientHandler) ListAdvisors(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	advisors, err := h.Service.ListAdvisors(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve advisors", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"advisors": advisors, "count": len(advisors)})
+}
+
+// UpdateAccess changes the access the user granted an advisor
+func (h *AdvisorClientHandler) UpdateAccess(c *gin.Context) {
+	userID, advisorID, ok := parseAdvisorIDs(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateAdvisorAccessRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	grant, err := h.Service.UpdateAccess(c.Request.Context(), userID, advisorID, req.Access)
+	if err != nil {
+		respondAdvisorClientError(c, err, "Failed to update advisor access")
+		return
+	}
+	c.JSON(http.StatusOK, grant)
+}
+
+// Revoke takes away the access the user granted an advisor
+func (h *AdvisorClientHandler) Revoke(c *gin.Context) {
+	userID, advisorID, ok := parseAdvisorIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Service.Revoke(c.Request.Context(), userID, advisorID); err != nil {
+		respondAdvisorClientError(c, err, "Failed to revoke advisor access")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Advisor access revoked"})
+}
+
+// ListClients returns the clients who granted the authenticated advisor access
+func (h *AdvisorClientHandler) ListClients(c *gin.Context) {
+	advisorID, ok := authenticatedUserID(c)
+	if !ok {
+		respondError(c, middleware.CodeUnauthenticated, "User not authenticated")
+		return
+	}
+
+	clients, err := h.Service.ListClients(c.Request.Context(), advisorID)
+	if err != nil {
+		respondAdvisorClientError(c, err, "Failed to retrieve clients")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"clients": clients, "count": len(clients)})
+}
+
+// ClientDashboard returns a client's dashboard summary for the period
+func (h *AdvisorClientHandler) ClientDashboard(c *gin.Context) {
+	advisorID, clientID, ok := parseClientIDs(c)
+	if !ok {
+		return
+	}
+
+	dashboard, err := h.Service.ClientDashboard(c.Request.Context(), advisorID, clientID, c.DefaultQuery("period", "month"))
+	if err != nil {
+		respondAdvisorClientError(c, err, "Failed to generate dashboard summary")
+		return
+	}
+	c.JSON(http.StatusOK, dashboard)
+}
+
+// Recommend leaves a client a recommendation shown in their insights
+func (h *AdvisorClientHandler) Recommend(c *gin.Context) {
+	advisorID, clientID, ok := parseClientIDs(c)
+	if !ok {
+		return
+	}
+
+	var req AdvisorRecommendationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	recommendation, err := h.Service.Recommend(c.Request.Context(), advisorID, clientID, req.Message)
+	if err != nil {
+		respondAdvisorClientError(c, err, "Failed to save recommendation")
+		return
+	}
+	c.JSON(http.StatusCreated, recommendation)
+}
+
+// ListRecommendations returns the recommendations the advisor left a client
+func (h *AdvisorClientHandler) ListRecommendations(c *gin.Context) {
+	advisorID, clientID, ok := parseClientIDs(c)
+	if !ok {
+		return
+	}
+
+	recommendations, err := h.Service.ListRecommendations(c.Request.Context(), advisorID, clientID)
+	if err != nil {
+		respondAdvisorClientError(c, err, "Failed to retrieve recommendations")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"recommendations": recommendations, "count": len(recommendations)})
+}
+
+// parseAdvisorIDs returns the user whose grant is changed and the advisor
+// it is to
+func parseAdvisorIDs(c *gin.Context) (userID, advisorID uint, ok bool) {
+	userID, ok = authorizedUserID(c)
+	if !ok {
+		return 0, 0, false
+	}
+	id, err := strconv.ParseUint(c.Param("advisorId"), 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid advisor ID")
+		return 0, 0, false
+	}
+	return userID, uint(id), true
+}
+
+// parseClientIDs returns the authenticated advisor and the client of the path
+func parseClientIDs(c *gin.Context) (advisorID, clientID uint, ok bool) {
+	advisorID, ok = authenticatedUserID(c)
+	if !ok {
+		respondError(c, middleware.CodeUnauthenticated, "User not authenticated")
+		return 0, 0, false
+	}
+	id, err := strconv.ParseUint(c.Param("clientId"), 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid client ID")
+		return 0, 0, false
+	}
+	return advisorID, uint(id), true
+}
+
+// respondAdvisorClientError maps advisor access errors to responses
+func respondAdvisorClientError(c *gin.Context, err error, message string) {
+	switch {
+	case respondValidationError(c, err):
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "Advisor not found")
+	case errors.Is(err, domain.ErrAdvisorForbidden):
+		respondError(c, middleware.CodeForbidden, err.Error())
+	case errors.Is(err, domain.ErrNotAdvisorAccount):
+		respondError(c, middleware.CodeUnprocessable, err.Error())
+	case errors.Is(err, domain.ErrInvalidAdvisorAccess):
+		respondError(c, middleware.CodeBadRequest, err.Error())
+	case errors.Is(err, domain.ErrAdvisorAlreadyGranted):
+		respondError(c, middleware.CodeConflict, err.Error())
+	default:
+		respondInternalError(c, message, err)
+	}
+}
@@ -0,0 +1,274 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAdvisorClientService is a mock implementation of AdvisorClientServiceInterface
+type MockAdvisorClientService struct {
+	mock.Mock
+}
+
+func (m *MockAdvisorClientService) Grant(ctx context.Context, clientID uint, email, access string) (*domain.AdvisorConnection, error) {
+	args := m.Called(ctx, clientID, email, access)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AdvisorConnection), args.Error(1)
+}
+
+func (m *MockAdvisorClientService) ListAdvisors(ctx context.Context, clientID uint) ([]domain.AdvisorConnection, error) {
+	args := m.Called(ctx, clientID)
+	return args.Get(0).([]domain.AdvisorConnection), args.Error(1)
+}
+
+func (m *MockAdvisorClientService) UpdateAccess(ctx context.Context, clientID, advisorID uint, access string) (*domain.AdvisorGrant, error) {
+	args := m.Called(ctx, clientID, advisorID, access)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AdvisorGrant), args.Error(1)
+}
+
+func (m *MockAdvisorClientService) Revoke(ctx context.Context, clientID, advisorID uint) error {
+	return m.Called(ctx, clientID, advisorID).Error(0)
+}
+
+func (m *MockAdvisorClientService) ListClients(ctx context.Context, advisorID uint) ([]domain.AdvisorConnection, error) {
+	args := m.Called(ctx, advisorID)
+	return args.Get(0).([]domain.AdvisorConnection), args.Error(1)
+}
+
+func (m *MockAdvisorClientService) ClientDashboard(
+	ctx context.Context, advisorID, clientID uint, period string,
+) (*domain.DashboardSummary, error) {
+	args := m.Called(ctx, advisorID, clientID, period)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.DashboardSummary), args.Error(1)
+}
+
+func (m *MockAdvisorClientService) Recommend(
+	ctx context.Context, advisorID, clientID uint, message string,
+) (*domain.AdvisorRecommendation, error) {
+	args := m.Called(ctx, advisorID, clientID, message)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AdvisorRecommendation), args.Error(1)
+}
+
+func (m *MockAdvisorClientService) ListRecommendations(
+	ctx context.Context, advisorID, clientID uint,
+) ([]domain.AdvisorRecommendation, error) {
+	args := m.Called(ctx, advisorID, clientID)
+	return args.Get(0).([]domain.AdvisorRecommendation), args.Error(1)
+}
+
+func setupAdvisorClientRouter(service *MockAdvisorClientService) *gin.Engine {
+	handler := NewAdvisorClientHandler(service)
+	router := setupGin()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Next()
+	})
+	router.GET("/users/:userId/advisors", handler.ListAdvisors)
+	router.POST("/users/:userId/advisors", handler.Grant)
+	router.PUT("/users/:userId/advisors/:advisorId", handler.UpdateAccess)
+	router.DELETE("/users/:userId/advisors/:advisorId", handler.Revoke)
+	router.GET("/advisor/clients", handler.ListClients)
+	router.GET("/advisor/clients/:clientId/dashboard", handler.ClientDashboard)
+	router.GET("/advisor/clients/:clientId/recommendations", handler.ListRecommendations)
+	router.POST("/advisor/clients/:clientId/recommendations", handler.Recommend)
+	return router
+}
+
+func TestAdvisorClientHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		mockSetup      func(*MockAdvisorClientService)
+		expectedStatus int
+	}{
+		{
+			name:   "grant an advisor access",
+			method: http.MethodPost,
+			path:   "/users/1/advisors",
+			body:   `{"email": "coach@example.com", "access": "comment"}`,
+			mockSetup: func(m *MockAdvisorClientService) {
+				m.On("Grant", mock.Anything, uint(1), "coach@example.com", domain.AdvisorAccessComment).
+					Return(&domain.AdvisorConnection{UserID: 2, Email: "coach@example.com", Access: domain.AdvisorAccessComment}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "grant write access",
+			method:         http.MethodPost,
+			path:           "/users/1/advisors",
+			body:           `{"email": "coach@example.com", "access": "write"}`,
+			mockSetup:      func(m *MockAdvisorClientService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "grant a personal account access",
+			method: http.MethodPost,
+			path:   "/users/1/advisors",
+			body:   `{"email": "friend@example.com", "access": "read"}`,
+			mockSetup: func(m *MockAdvisorClientService) {
+				m.On("Grant", mock.Anything, uint(1), "friend@example.com", domain.AdvisorAccessRead).
+					Return(nil, domain.ErrNotAdvisorAccount)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "grant an advisor access twice",
+			method: http.MethodPost,
+			path:   "/users/1/advisors",
+			body:   `{"email": "coach@example.com", "access": "read"}`,
+			mockSetup: func(m *MockAdvisorClientService) {
+				m.On("Grant", mock.Anything, uint(1), "coach@example.com", domain.AdvisorAccessRead).
+					Return(nil, domain.ErrAdvisorAlreadyGranted)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:   "list advisors",
+			method: http.MethodGet,
+			path:   "/users/1/advisors",
+			mockSetup: func(m *MockAdvisorClientService) {
+				m.On("ListAdvisors", mock.Anything, uint(1)).Return([]domain.AdvisorConnection{{UserID: 2}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "change access",
+			method: http.MethodPut,
+			path:   "/users/1/advisors/2",
+			body:   `{"access": "read"}`,
+			mockSetup: func(m *MockAdvisorClientService) {
+				m.On("UpdateAccess", mock.Anything, uint(1), uint(2), domain.AdvisorAccessRead).
+					Return(&domain.AdvisorGrant{ClientID: 1, AdvisorID: 2, Access: domain.AdvisorAccessRead}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "revoke a missing grant",
+			method: http.MethodDelete,
+			path:   "/users/1/advisors/9",
+			mockSetup: func(m *MockAdvisorClientService) {
+				m.On("Revoke", mock.Anything, uint(1), uint(9)).Return(domain.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "other users' advisors are forbidden",
+			method:         http.MethodGet,
+			path:           "/users/2/advisors",
+			mockSetup:      func(m *MockAdvisorClientService) {},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "list clients",
+			method: http.MethodGet,
+			path:   "/advisor/clients",
+			mockSetup: func(m *MockAdvisorClientService) {
+				m.On("ListClients", mock.Anything, uint(1)).Return([]domain.AdvisorConnection{{UserID: 3}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "list clients without an advisor account",
+			method: http.MethodGet,
+			path:   "/advisor/clients",
+			mockSetup: func(m *MockAdvisorClientService) {
+				m.On("ListClients", mock.Anything, uint(1)).Return([]domain.AdvisorConnection(nil), domain.ErrAdvisorForbidden)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "client dashboard",
+			method: http.MethodGet,
+			path:   "/advisor/clients/3/dashboard?period=quarter",
+			mockSetup: func(m *MockAdvisorClientService) {
+				m.On("ClientDashboard", mock.Anything, uint(1), uint(3), "quarter").
+					Return(&domain.DashboardSummary{UserID: 3, Period: "quarter"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "dashboard of someone who is not a client",
+			method: http.MethodGet,
+			path:   "/advisor/clients/4/dashboard",
+			mockSetup: func(m *MockAdvisorClientService) {
+				m.On("ClientDashboard", mock.Anything, uint(1), uint(4), "month").Return(nil, domain.ErrAdvisorForbidden)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "dashboard with an invalid client ID",
+			method:         http.MethodGet,
+			path:           "/advisor/clients/abc/dashboard",
+			mockSetup:      func(m *MockAdvisorClientService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "leave a recommendation",
+			method: http.MethodPost,
+			path:   "/advisor/clients/3/recommendations",
+			body:   `{"message": "Build a three month emergency fund first"}`,
+			mockSetup: func(m *MockAdvisorClientService) {
+				m.On("Recommend", mock.Anything, uint(1), uint(3), "Build a three month emergency fund first").
+					Return(&domain.AdvisorRecommendation{ID: 1, ClientID: 3, AdvisorID: 1}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:   "leave a recommendation with read access",
+			method: http.MethodPost,
+			path:   "/advisor/clients/3/recommendations",
+			body:   `{"message": "Cut dining out"}`,
+			mockSetup: func(m *MockAdvisorClientService) {
+				m.On("Recommend", mock.Anything, uint(1), uint(3), "Cut dining out").Return(nil, domain.ErrAdvisorForbidden)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "list recommendations",
+			method: http.MethodGet,
+			path:   "/advisor/clients/3/recommendations",
+			mockSetup: func(m *MockAdvisorClientService) {
+				m.On("ListRecommendations", mock.Anything, uint(1), uint(3)).Return([]domain.AdvisorRecommendation{{ID: 1}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(MockAdvisorClientService)
+			tt.mockSetup(service)
+			router := setupAdvisorClientRouter(service)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			service.AssertExpectations(t)
+		})
+	}
+}
@@ -1,38 +1,96 @@
 package api
 
 import (
+	"context"
+	"errors"
+	"log"
+	"math"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 
 	"go-finance-advisor/internal/application"
 	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
 	"go-finance-advisor/internal/pkg"
 
 	"github.com/gin-gonic/gin"
 )
 
+// maxSymbolSearchLength bounds symbol search queries
+const maxSymbolSearchLength = 50
+
+// marketError responds to a failed market data request. Providers that are
+// down with no earlier prices to fall back on make the market unavailable
+// rather than the server broken.
+func marketError(c *gin.Context, err error) {
+	if errors.Is(err, pkg.ErrMarketUnavailable) {
+		respondError(c, middleware.CodeUnavailable, "Market data is unavailable")
+		return
+	}
+	respondInternalError(c, "Failed to fetch market data", err)
+}
+
+// requestMonthlyIncome returns the monthly_income query parameter, or the
+// income stored in the user's profile without one
+func requestMonthlyIncome(c *gin.Context, user *domain.User) (float64, bool) {
+	value := c.Query("monthly_income")
+	if value == "" {
+		return user.MonthlyIncome.Float64(), true
+	}
+	income, err := strconv.ParseFloat(value, 64)
+	if err != nil || income < 0 || math.IsInf(income, 0) {
+		respondError(c, middleware.CodeBadRequest, "Invalid monthly_income")
+		return 0, false
+	}
+	return income, true
+}
+
+// freshness describes how current the prices are
+func freshness(stale bool) string {
+	if stale {
+		return "stale"
+	}
+	return "real-time"
+}
+
 // AdvisorServiceInterface defines the contract for advisor service operations
 type AdvisorServiceInterface interface {
-	GenerateAdvice(user *domain.User) (*application.InvestmentAdvice, error)
+	GenerateAdvice(ctx context.Context, user *domain.User) (*application.InvestmentAdvice, error)
 }
 
 // MarketServiceInterface defines the contract for market service operations
 type MarketServiceInterface interface {
-	GetCryptoPrices() ([]pkg.CryptoPrice, error)
-	GetStockPrices(symbols []string) ([]pkg.StockPrice, error)
-	GetMarketData() (*pkg.MarketData, error)
-	GetMarketSummary() (map[string]interface{}, error)
-	AnalyzeMarket() (*pkg.MarketAnalysis, error)
+	GetCryptoPrices(ctx context.Context) ([]pkg.CryptoPrice, error)
+	GetStockPrices(ctx context.Context, symbols []string) ([]pkg.StockPrice, error)
+	GetMarketData(ctx context.Context) (*pkg.MarketData, error)
+	GetMarketSummary(ctx context.Context) (map[string]interface{}, error)
+	AnalyzeMarket(ctx context.Context) (*pkg.MarketAnalysis, error)
 	GenerateRecommendations(riskTolerance string, monthlyIncome float64, analysis *pkg.MarketAnalysis) []domain.Recommendation
 	GenerateAdviceText(riskTolerance string, analysis *pkg.MarketAnalysis) string
-	GeneratePersonalizedAdvice(user *domain.User, monthlyIncome float64) (*pkg.InvestmentRecommendation, error)
+	GeneratePersonalizedAdvice(ctx context.Context, user *domain.User, monthlyIncome float64) (*pkg.InvestmentRecommendation, error)
 	PerformAIRiskAssessment(user *domain.User, monthlyIncome float64, goals []string) (*pkg.AIRiskAssessment, error)
+	SearchSymbols(ctx context.Context, query string) ([]pkg.SymbolMatch, error)
+}
+
+// AdviceRecorderInterface keeps generated advice in the user's advice history
+type AdviceRecorderInterface interface {
+	RecordPersonalizedAdvice(ctx context.Context, advice *pkg.InvestmentRecommendation, monthlyIncome float64)
+	RecordRiskAssessment(ctx context.Context, assessment *pkg.AIRiskAssessment, monthlyIncome float64)
+}
+
+// WatchlistSymbolsInterface lists the symbols a user watches
+type WatchlistSymbolsInterface interface {
+	Symbols(ctx context.Context, userID uint, assetType string) ([]string, error)
 }
 
 type AdvisorHandler struct {
 	Advisor       AdvisorServiceInterface
 	Users         UserServiceInterface
 	MarketService MarketServiceInterface
+	History       AdviceRecorderInterface   // Keeps real-time advice and risk assessments when set
+	Watchlist     WatchlistSymbolsInterface // Puts the user's watchlisted symbols first in price lists when set
 }
 
 // NewAdvisorHandler creates a new advisor handler with real-time market service
@@ -49,18 +107,18 @@ func (h *AdvisorHandler) GetAdvice(c *gin.Context) {
 	userIDStr := c.Param("userId")
 	uid, err := strconv.Atoi(userIDStr)
 	if err != nil || uid < 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		respondError(c, middleware.CodeInvalidID, "Invalid user ID")
 		return
 	}
 
-	user, err := h.Users.GetByID(uint(uid))
+	user, err := h.Users.GetByID(c.Request.Context(), uint(uid))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		respondError(c, middleware.CodeNotFound, "User not found")
 		return
 	}
-	advice, err := h.Advisor.GenerateAdvice(&user)
+	advice, err := h.Advisor.GenerateAdvice(c.Request.Context(), &user)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondInternalError(c, "Failed to generate advice", err)
 		return
 	}
 	c.JSON(http.StatusOK, advice)
@@ -71,82 +129,137 @@ func (h *AdvisorHandler) GetRealTimeAdvice(c *gin.Context) {
 	userIDStr := c.Param("userId")
 	uid, err := strconv.Atoi(userIDStr)
 	if err != nil || uid < 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		respondError(c, middleware.CodeInvalidID, "Invalid user ID")
 		return
 	}
 
-	user, err := h.Users.GetByID(uint(uid))
+	user, err := h.Users.GetByID(c.Request.Context(), uint(uid))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		respondError(c, middleware.CodeNotFound, "User not found")
 		return
 	}
 
-	// Get monthly income from query parameter or calculate from transactions
-	monthlyIncomeStr := c.DefaultQuery("monthly_income", "5000")
-	monthlyIncome, _ := strconv.ParseFloat(monthlyIncomeStr, 64)
+	monthlyIncome, ok := requestMonthlyIncome(c, &user)
+	if !ok {
+		return
+	}
 
-	advice, err := h.MarketService.GeneratePersonalizedAdvice(&user, monthlyIncome)
+	advice, err := h.MarketService.GeneratePersonalizedAdvice(c.Request.Context(), &user, monthlyIncome)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		marketError(c, err)
 		return
 	}
+	if h.History != nil {
+		h.History.RecordPersonalizedAdvice(c.Request.Context(), advice, monthlyIncome)
+	}
 
 	c.JSON(http.StatusOK, advice)
 }
 
 // GetMarketData provides real-time cryptocurrency and stock prices
 func (h *AdvisorHandler) GetMarketData(c *gin.Context) {
-	analysis, err := h.MarketService.AnalyzeMarket()
+	analysis, err := h.MarketService.AnalyzeMarket(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		marketError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, analysis)
 }
 
-// GetCryptoPrices provides real-time cryptocurrency prices
+// watchlisted returns the authenticated user's watchlisted symbols of the
+// asset type, or nothing without a watchlist
+func (h *AdvisorHandler) watchlisted(c *gin.Context, assetType string) []string {
+	if h.Watchlist == nil {
+		return nil
+	}
+	userID, ok := c.Get("userID")
+	if !ok {
+		return nil
+	}
+	id, ok := userID.(uint)
+	if !ok {
+		return nil
+	}
+	symbols, err := h.Watchlist.Symbols(c.Request.Context(), id, assetType)
+	if err != nil {
+		log.Printf("Failed to load watchlist for user %d: %v", id, err)
+		return nil
+	}
+	return symbols
+}
+
+// SearchSymbols finds stock symbols and company names matching q
+func (h *AdvisorHandler) SearchSymbols(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		respondError(c, middleware.CodeBadRequest, "Query parameter q is required")
+		return
+	}
+	if len(query) > maxSymbolSearchLength {
+		respondError(c, middleware.CodeBadRequest, "Query parameter q is too long")
+		return
+	}
+
+	matches, err := h.MarketService.SearchSymbols(c.Request.Context(), query)
+	if err != nil {
+		marketError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"matches": matches,
+		"count":   len(matches),
+	})
+}
+
+// GetCryptoPrices provides real-time cryptocurrency prices, the user's
+// watchlisted ones first
 func (h *AdvisorHandler) GetCryptoPrices(c *gin.Context) {
-	cryptos, err := h.MarketService.GetCryptoPrices()
+	cryptos, err := h.MarketService.GetCryptoPrices(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		marketError(c, err)
 		return
 	}
+	cryptos = pkg.PrioritizeCryptos(cryptos, h.watchlisted(c, application.WatchlistAssetCrypto))
 
 	c.JSON(http.StatusOK, gin.H{
 		"cryptos": cryptos,
 		"count":   len(cryptos),
-		"updated": "real-time",
+		"updated": freshness(slices.ContainsFunc(cryptos, func(crypto pkg.CryptoPrice) bool { return crypto.Stale })),
 	})
 }
 
-// GetStockPrices provides real-time stock prices
+// GetStockPrices provides real-time stock prices. Without symbols the
+// user's watchlisted stocks are priced first, topped up with popular ones.
 func (h *AdvisorHandler) GetStockPrices(c *gin.Context) {
 	// Get symbols from query parameter
 	symbolsParam := c.Query("symbols")
 	var symbols []string
 	if symbolsParam != "" {
 		symbols = []string{symbolsParam}
+	} else if watchlisted := h.watchlisted(c, application.WatchlistAssetStock); len(watchlisted) > 0 {
+		symbols = pkg.PrioritizeStockSymbols(watchlisted)
 	}
 
-	stocks, err := h.MarketService.GetStockPrices(symbols)
+	stocks, err := h.MarketService.GetStockPrices(c.Request.Context(), symbols)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		marketError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"stocks":  stocks,
 		"count":   len(stocks),
-		"updated": "real-time",
+		"updated": freshness(slices.ContainsFunc(stocks, func(stock pkg.StockPrice) bool { return stock.Stale })),
 	})
 }
 
 // GetMarketSummary provides a quick market overview
 func (h *AdvisorHandler) GetMarketSummary(c *gin.Context) {
-	summary, err := h.MarketService.GetMarketSummary()
+	summary, err := h.MarketService.GetMarketSummary(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		marketError(c, err)
 		return
 	}
 
@@ -158,23 +271,25 @@ func (h *AdvisorHandler) GetPortfolioRecommendations(c *gin.Context) {
 	userIDStr := c.Param("userId")
 	uid, err := strconv.Atoi(userIDStr)
 	if err != nil || uid < 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		respondError(c, middleware.CodeInvalidID, "Invalid user ID")
 		return
 	}
 
-	user, err := h.Users.GetByID(uint(uid))
+	user, err := h.Users.GetByID(c.Request.Context(), uint(uid))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		respondError(c, middleware.CodeNotFound, "User not found")
 		return
 	}
 
-	monthlyIncomeStr := c.DefaultQuery("monthly_income", "5000")
-	monthlyIncome, _ := strconv.ParseFloat(monthlyIncomeStr, 64)
+	monthlyIncome, ok := requestMonthlyIncome(c, &user)
+	if !ok {
+		return
+	}
 
 	// Get AI-enhanced market analysis
-	analysis, err := h.MarketService.AnalyzeMarket()
+	analysis, err := h.MarketService.AnalyzeMarket(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		marketError(c, err)
 		return
 	}
 
@@ -203,18 +318,20 @@ func (h *AdvisorHandler) GetAIRiskAssessment(c *gin.Context) {
 	userIDStr := c.Param("userId")
 	uid, err := strconv.Atoi(userIDStr)
 	if err != nil || uid < 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		respondError(c, middleware.CodeInvalidID, "Invalid user ID")
 		return
 	}
 
-	user, err := h.Users.GetByID(uint(uid))
+	user, err := h.Users.GetByID(c.Request.Context(), uint(uid))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		respondError(c, middleware.CodeNotFound, "User not found")
 		return
 	}
 
-	monthlyIncomeStr := c.DefaultQuery("monthly_income", "5000")
-	monthlyIncome, _ := strconv.ParseFloat(monthlyIncomeStr, 64)
+	monthlyIncome, ok := requestMonthlyIncome(c, &user)
+	if !ok {
+		return
+	}
 
 	// Parse investment goals from query parameters
 	goalsParam := c.Query("goals")
@@ -228,9 +345,12 @@ func (h *AdvisorHandler) GetAIRiskAssessment(c *gin.Context) {
 	// Perform AI risk assessment
 	assessment, err := h.MarketService.PerformAIRiskAssessment(&user, monthlyIncome, goals)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondInternalError(c, "Failed to assess risk", err)
 		return
 	}
+	if h.History != nil {
+		h.History.RecordRiskAssessment(c.Request.Context(), assessment, monthlyIncome)
+	}
 
 	c.JSON(http.StatusOK, assessment)
 }
@@ -242,9 +362,9 @@ func (h *AdvisorHandler) GetAIMarketPrediction(c *gin.Context) {
 	timeframe, _ := strconv.Atoi(timeframeStr)
 
 	// Get market analysis with AI predictions
-	analysis, err := h.MarketService.AnalyzeMarket()
+	analysis, err := h.MarketService.AnalyzeMarket(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		marketError(c, err)
 		return
 	}
 
@@ -276,18 +396,20 @@ func (h *AdvisorHandler) GetAIPortfolioOptimization(c *gin.Context) {
 	userIDStr := c.Param("userId")
 	uid, err := strconv.Atoi(userIDStr)
 	if err != nil || uid < 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		respondError(c, middleware.CodeInvalidID, "Invalid user ID")
 		return
 	}
 
-	user, err := h.Users.GetByID(uint(uid))
+	user, err := h.Users.GetByID(c.Request.Context(), uint(uid))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		respondError(c, middleware.CodeNotFound, "User not found")
 		return
 	}
 
-	monthlyIncomeStr := c.DefaultQuery("monthly_income", "5000")
-	monthlyIncome, _ := strconv.ParseFloat(monthlyIncomeStr, 64)
+	monthlyIncome, ok := requestMonthlyIncome(c, &user)
+	if !ok {
+		return
+	}
 
 	// Get current portfolio value from query parameter
 	currentValueStr := c.DefaultQuery("current_value", "0")
@@ -296,14 +418,14 @@ func (h *AdvisorHandler) GetAIPortfolioOptimization(c *gin.Context) {
 	// Perform AI risk assessment for optimization
 	assessment, err := h.MarketService.PerformAIRiskAssessment(&user, monthlyIncome, []string{"optimization"})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondInternalError(c, "Failed to assess risk", err)
 		return
 	}
 
 	// Get market analysis for optimization
-	analysis, err := h.MarketService.AnalyzeMarket()
+	analysis, err := h.MarketService.AnalyzeMarket(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		marketError(c, err)
 		return
 	}
 
@@ -1,8 +1,10 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -21,8 +23,8 @@ type MockAdvisorService struct {
 	mock.Mock
 }
 
-func (m *MockAdvisorService) GenerateAdvice(user *domain.User) (*application.InvestmentAdvice, error) {
-	args := m.Called(user)
+func (m *MockAdvisorService) GenerateAdvice(ctx context.Context, user *domain.User) (*application.InvestmentAdvice, error) {
+	args := m.Called(ctx, user)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -34,50 +36,65 @@ type MockUserService struct {
 	mock.Mock
 }
 
-func (m *MockUserService) GetByID(userID uint) (domain.User, error) {
-	args := m.Called(userID)
+func (m *MockUserService) GetByID(ctx context.Context, userID uint) (domain.User, error) {
+	args := m.Called(ctx, userID)
 	if args.Get(0) == nil {
 		return domain.User{}, args.Error(1)
 	}
 	return args.Get(0).(domain.User), args.Error(1)
 }
 
-func (m *MockUserService) Create(user *domain.User) error {
-	args := m.Called(user)
+func (m *MockUserService) Create(ctx context.Context, user *domain.User) error {
+	args := m.Called(ctx, user)
 	return args.Error(0)
 }
 
-func (m *MockUserService) Update(user *domain.User) error {
-	args := m.Called(user)
+func (m *MockUserService) Update(ctx context.Context, user *domain.User) error {
+	args := m.Called(ctx, user)
 	return args.Error(0)
 }
 
-func (m *MockUserService) Delete(userID uint) error {
-	args := m.Called(userID)
+func (m *MockUserService) Delete(ctx context.Context, userID uint) error {
+	args := m.Called(ctx, userID)
 	return args.Error(0)
 }
 
-func (m *MockUserService) GetByEmail(email string) (*domain.User, error) {
-	args := m.Called(email)
+func (m *MockUserService) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	args := m.Called(ctx, email)
 	return args.Get(0).(*domain.User), args.Error(1)
 }
 
-func (m *MockUserService) Register(email, password, firstName, lastName string) (*domain.User, error) {
-	args := m.Called(email, password, firstName, lastName)
+func (m *MockUserService) Register(ctx context.Context, email, password, firstName, lastName string) (*domain.User, error) {
+	args := m.Called(ctx, email, password, firstName, lastName)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*domain.User), args.Error(1)
 }
 
-func (m *MockUserService) Login(email, password string) (*domain.User, error) {
-	args := m.Called(email, password)
+func (m *MockUserService) Login(ctx context.Context, email, password string) (*domain.User, error) {
+	args := m.Called(ctx, email, password)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*domain.User), args.Error(1)
 }
 
+func (m *MockUserService) UpdateProfile(ctx context.Context, userID uint, profile domain.UserProfile) (domain.User, error) {
+	args := m.Called(ctx, userID, profile)
+	return args.Get(0).(domain.User), args.Error(1)
+}
+
+func (m *MockUserService) UpdateLocale(ctx context.Context, userID uint, locale string) (domain.User, error) {
+	args := m.Called(ctx, userID, locale)
+	return args.Get(0).(domain.User), args.Error(1)
+}
+
+func (m *MockUserService) UpdateAccountType(ctx context.Context, userID uint, accountType string) (domain.User, error) {
+	args := m.Called(ctx, userID, accountType)
+	return args.Get(0).(domain.User), args.Error(1)
+}
+
 func (m *MockUserService) ValidateCredentials(email, password string) (*domain.User, error) {
 	args := m.Called(email, password)
 	return args.Get(0).(*domain.User), args.Error(1)
@@ -89,34 +106,34 @@ type MockRealTimeMarketService struct {
 }
 
 func (m *MockRealTimeMarketService) GeneratePersonalizedAdvice(
-	user *domain.User, monthlyIncome float64,
+	ctx context.Context, user *domain.User, monthlyIncome float64,
 ) (*pkg.InvestmentRecommendation, error) {
-	args := m.Called(user, monthlyIncome)
+	args := m.Called(ctx, user, monthlyIncome)
 	return args.Get(0).(*pkg.InvestmentRecommendation), args.Error(1)
 }
 
-func (m *MockRealTimeMarketService) AnalyzeMarket() (*pkg.MarketAnalysis, error) {
-	args := m.Called()
+func (m *MockRealTimeMarketService) AnalyzeMarket(ctx context.Context) (*pkg.MarketAnalysis, error) {
+	args := m.Called(ctx)
 	return args.Get(0).(*pkg.MarketAnalysis), args.Error(1)
 }
 
-func (m *MockRealTimeMarketService) GetCryptoPrices() ([]pkg.CryptoPrice, error) {
-	args := m.Called()
+func (m *MockRealTimeMarketService) GetCryptoPrices(ctx context.Context) ([]pkg.CryptoPrice, error) {
+	args := m.Called(ctx)
 	return args.Get(0).([]pkg.CryptoPrice), args.Error(1)
 }
 
-func (m *MockRealTimeMarketService) GetStockPrices(symbols []string) ([]pkg.StockPrice, error) {
-	args := m.Called(symbols)
+func (m *MockRealTimeMarketService) GetStockPrices(ctx context.Context, symbols []string) ([]pkg.StockPrice, error) {
+	args := m.Called(ctx, symbols)
 	return args.Get(0).([]pkg.StockPrice), args.Error(1)
 }
 
-func (m *MockRealTimeMarketService) GetMarketData() (*pkg.MarketData, error) {
-	args := m.Called()
+func (m *MockRealTimeMarketService) GetMarketData(ctx context.Context) (*pkg.MarketData, error) {
+	args := m.Called(ctx)
 	return args.Get(0).(*pkg.MarketData), args.Error(1)
 }
 
-func (m *MockRealTimeMarketService) GetMarketSummary() (map[string]interface{}, error) {
-	args := m.Called()
+func (m *MockRealTimeMarketService) GetMarketSummary(ctx context.Context) (map[string]interface{}, error) {
+	args := m.Called(ctx)
 	return args.Get(0).(map[string]interface{}), args.Error(1)
 }
 
@@ -139,6 +156,14 @@ func (m *MockRealTimeMarketService) PerformAIRiskAssessment(
 	return args.Get(0).(*pkg.AIRiskAssessment), args.Error(1)
 }
 
+func (m *MockRealTimeMarketService) SearchSymbols(ctx context.Context, query string) ([]pkg.SymbolMatch, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]pkg.SymbolMatch), args.Error(1)
+}
+
 func setupAdvisorHandler() (*AdvisorHandler, *MockAdvisorService, *MockUserService, *MockRealTimeMarketService) {
 	mockAdvisorService := &MockAdvisorService{}
 	mockUserService := &MockUserService{}
@@ -174,8 +199,8 @@ func TestAdvisorHandler_GetAdvice(t *testing.T) {
 			},
 		}
 
-		mockUserService.On("GetByID", uint(1)).Return(*user, nil)
-		mockAdvisorService.On("GenerateAdvice", mock.MatchedBy(func(u *domain.User) bool {
+		mockUserService.On("GetByID", mock.Anything, uint(1)).Return(*user, nil)
+		mockAdvisorService.On("GenerateAdvice", mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
 			return u.ID == 1
 		})).Return(advice, nil)
 
@@ -198,7 +223,7 @@ func TestAdvisorHandler_GetAdvice(t *testing.T) {
 		router := setupGin()
 		router.GET("/advice/:userId", handler.GetAdvice)
 
-		mockUserService.On("GetByID", uint(999)).Return(domain.User{}, errors.New("user not found"))
+		mockUserService.On("GetByID", mock.Anything, uint(999)).Return(domain.User{}, errors.New("user not found"))
 
 		req := httptest.NewRequest("GET", "/advice/999", http.NoBody)
 		w := httptest.NewRecorder()
@@ -208,7 +233,7 @@ func TestAdvisorHandler_GetAdvice(t *testing.T) {
 		assert.Equal(t, http.StatusNotFound, w.Code)
 		var response map[string]interface{}
 		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Equal(t, "user not found", response["error"])
+		assert.Equal(t, "User not found", response["error"])
 		mockUserService.AssertExpectations(t)
 	})
 
@@ -223,8 +248,8 @@ func TestAdvisorHandler_GetAdvice(t *testing.T) {
 			RiskTolerance: "moderate",
 		}
 
-		mockUserService.On("GetByID", uint(1)).Return(*user, nil)
-		mockAdvisorService.On("GenerateAdvice", mock.MatchedBy(func(u *domain.User) bool {
+		mockUserService.On("GetByID", mock.Anything, uint(1)).Return(*user, nil)
+		mockAdvisorService.On("GenerateAdvice", mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
 			return u.ID == 1
 		})).Return((*application.InvestmentAdvice)(nil), errors.New("advisor service error"))
 
@@ -236,7 +261,7 @@ func TestAdvisorHandler_GetAdvice(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
 		var response map[string]interface{}
 		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Equal(t, "advisor service error", response["error"])
+		assert.Equal(t, "Failed to generate advice", response["error"])
 		mockUserService.AssertExpectations(t)
 		mockAdvisorService.AssertExpectations(t)
 	})
@@ -252,6 +277,7 @@ func TestAdvisorHandler_GetRealTimeAdvice(t *testing.T) {
 			ID:            1,
 			Email:         "test@example.com",
 			RiskTolerance: "moderate",
+			UserProfile:   domain.UserProfile{MonthlyIncome: domain.NewMoney(5000)},
 		}
 
 		advice := &pkg.InvestmentRecommendation{
@@ -268,8 +294,8 @@ func TestAdvisorHandler_GetRealTimeAdvice(t *testing.T) {
 			CreatedAt: time.Now(),
 		}
 
-		mockUserService.On("GetByID", uint(1)).Return(*user, nil)
-		mockMarketService.On("GeneratePersonalizedAdvice", user, 5000.0).Return(advice, nil)
+		mockUserService.On("GetByID", mock.Anything, uint(1)).Return(*user, nil)
+		mockMarketService.On("GeneratePersonalizedAdvice", mock.Anything, user, 5000.0).Return(advice, nil)
 
 		req := httptest.NewRequest("GET", "/advice/realtime/1", http.NoBody)
 		w := httptest.NewRecorder()
@@ -318,8 +344,8 @@ func TestAdvisorHandler_GetRealTimeAdvice(t *testing.T) {
 			CreatedAt: time.Now(),
 		}
 
-		mockUserService.On("GetByID", uint(1)).Return(*user, nil)
-		mockMarketService.On("GeneratePersonalizedAdvice", user, 8000.0).Return(advice, nil)
+		mockUserService.On("GetByID", mock.Anything, uint(1)).Return(*user, nil)
+		mockMarketService.On("GeneratePersonalizedAdvice", mock.Anything, user, 8000.0).Return(advice, nil)
 
 		req := httptest.NewRequest("GET", "/advice/realtime/1?monthly_income=8000", http.NoBody)
 		w := httptest.NewRecorder()
@@ -339,7 +365,7 @@ func TestAdvisorHandler_GetRealTimeAdvice(t *testing.T) {
 		router := setupGin()
 		router.GET("/advice/realtime/:userId", handler.GetRealTimeAdvice)
 
-		mockUserService.On("GetByID", uint(999)).Return(domain.User{}, errors.New("user not found"))
+		mockUserService.On("GetByID", mock.Anything, uint(999)).Return(domain.User{}, errors.New("user not found"))
 
 		req := httptest.NewRequest("GET", "/advice/realtime/999", http.NoBody)
 		w := httptest.NewRecorder()
@@ -371,7 +397,7 @@ func TestAdvisorHandler_GetMarketData(t *testing.T) {
 			LastUpdated:     time.Now(),
 		}
 
-		mockMarketService.On("AnalyzeMarket").Return(analysis, nil)
+		mockMarketService.On("AnalyzeMarket", mock.Anything).Return(analysis, nil)
 
 		req := httptest.NewRequest("GET", "/market/data", http.NoBody)
 		w := httptest.NewRecorder()
@@ -391,7 +417,7 @@ func TestAdvisorHandler_GetMarketData(t *testing.T) {
 		router := setupGin()
 		router.GET("/market/data", handler.GetMarketData)
 
-		mockMarketService.On("AnalyzeMarket").Return((*pkg.MarketAnalysis)(nil), errors.New("market service error"))
+		mockMarketService.On("AnalyzeMarket", mock.Anything).Return((*pkg.MarketAnalysis)(nil), errors.New("market service error"))
 
 		req := httptest.NewRequest("GET", "/market/data", http.NoBody)
 		w := httptest.NewRecorder()
@@ -401,7 +427,7 @@ func TestAdvisorHandler_GetMarketData(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
 		var response map[string]interface{}
 		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Equal(t, "market service error", response["error"])
+		assert.Equal(t, "Failed to fetch market data", response["error"])
 		mockMarketService.AssertExpectations(t)
 	})
 }
@@ -431,7 +457,7 @@ func TestAdvisorHandler_GetCryptoPrices(t *testing.T) {
 			},
 		}
 
-		mockMarketService.On("GetCryptoPrices").Return(cryptos, nil)
+		mockMarketService.On("GetCryptoPrices", mock.Anything).Return(cryptos, nil)
 
 		req := httptest.NewRequest("GET", "/market/crypto", http.NoBody)
 		w := httptest.NewRecorder()
@@ -453,7 +479,7 @@ func TestAdvisorHandler_GetCryptoPrices(t *testing.T) {
 		router := setupGin()
 		router.GET("/market/crypto", handler.GetCryptoPrices)
 
-		mockMarketService.On("GetCryptoPrices").Return([]pkg.CryptoPrice{}, errors.New("crypto service error"))
+		mockMarketService.On("GetCryptoPrices", mock.Anything).Return([]pkg.CryptoPrice{}, errors.New("crypto service error"))
 
 		req := httptest.NewRequest("GET", "/market/crypto", http.NoBody)
 		w := httptest.NewRecorder()
@@ -463,9 +489,40 @@ func TestAdvisorHandler_GetCryptoPrices(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
 		var response map[string]interface{}
 		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Equal(t, "crypto service error", response["error"])
+		assert.Equal(t, "Failed to fetch market data", response["error"])
 		mockMarketService.AssertExpectations(t)
 	})
+
+	t.Run("should flag last known prices as stale", func(t *testing.T) {
+		handler, _, _, mockMarketService := setupAdvisorHandler()
+		router := setupGin()
+		router.GET("/market/crypto", handler.GetCryptoPrices)
+
+		mockMarketService.On("GetCryptoPrices", mock.Anything).
+			Return([]pkg.CryptoPrice{{Symbol: "BTC", Price: 45000.0, Stale: true}}, nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/market/crypto", http.NoBody))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, "stale", response["updated"])
+	})
+
+	t.Run("should return service unavailable when the provider is down", func(t *testing.T) {
+		handler, _, _, mockMarketService := setupAdvisorHandler()
+		router := setupGin()
+		router.GET("/market/crypto", handler.GetCryptoPrices)
+
+		mockMarketService.On("GetCryptoPrices", mock.Anything).
+			Return([]pkg.CryptoPrice{}, fmt.Errorf("failed to fetch crypto data: %w", pkg.ErrMarketUnavailable))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/market/crypto", http.NoBody))
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	})
 }
 
 func TestAdvisorHandler_GetStockPrices(t *testing.T) {
@@ -484,7 +541,7 @@ func TestAdvisorHandler_GetStockPrices(t *testing.T) {
 			},
 		}
 
-		mockMarketService.On("GetStockPrices", []string(nil)).Return(stocks, nil)
+		mockMarketService.On("GetStockPrices", mock.Anything, []string(nil)).Return(stocks, nil)
 
 		req := httptest.NewRequest("GET", "/market/stocks", http.NoBody)
 		w := httptest.NewRecorder()
@@ -514,7 +571,7 @@ func TestAdvisorHandler_GetStockPrices(t *testing.T) {
 			},
 		}
 
-		mockMarketService.On("GetStockPrices", []string{"AAPL"}).Return(stocks, nil)
+		mockMarketService.On("GetStockPrices", mock.Anything, []string{"AAPL"}).Return(stocks, nil)
 
 		req := httptest.NewRequest("GET", "/market/stocks?symbols=AAPL", http.NoBody)
 		w := httptest.NewRecorder()
@@ -545,7 +602,7 @@ func TestAdvisorHandler_GetMarketSummary(t *testing.T) {
 			"top_losers":       []string{"META", "NFLX"},
 		}
 
-		mockMarketService.On("GetMarketSummary").Return(summary, nil)
+		mockMarketService.On("GetMarketSummary", mock.Anything).Return(summary, nil)
 
 		req := httptest.NewRequest("GET", "/market/summary", http.NoBody)
 		w := httptest.NewRecorder()
@@ -565,7 +622,7 @@ func TestAdvisorHandler_GetMarketSummary(t *testing.T) {
 		router := setupGin()
 		router.GET("/market/summary", handler.GetMarketSummary)
 
-		mockMarketService.On("GetMarketSummary").Return(map[string]interface{}(nil), errors.New("market service error"))
+		mockMarketService.On("GetMarketSummary", mock.Anything).Return(map[string]interface{}(nil), errors.New("market service error"))
 
 		req := httptest.NewRequest("GET", "/market/summary", http.NoBody)
 		w := httptest.NewRecorder()
@@ -575,7 +632,7 @@ func TestAdvisorHandler_GetMarketSummary(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
 		var response map[string]interface{}
 		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Equal(t, "market service error", response["error"])
+		assert.Equal(t, "Failed to fetch market data", response["error"])
 		mockMarketService.AssertExpectations(t)
 	})
 }
@@ -619,12 +676,12 @@ func TestAdvisorHandler_GetPortfolioRecommendations(t *testing.T) {
 
 		adviceText := "Based on current market conditions, consider a balanced approach."
 
-		mockUserService.On("GetByID", uint(1)).Return(*user, nil)
-		mockMarketService.On("AnalyzeMarket").Return(analysis, nil)
+		mockUserService.On("GetByID", mock.Anything, uint(1)).Return(*user, nil)
+		mockMarketService.On("AnalyzeMarket", mock.Anything).Return(analysis, nil)
 		mockMarketService.On("GenerateRecommendations", "moderate", 5000.0, analysis).Return(recommendations)
 		mockMarketService.On("GenerateAdviceText", "moderate", analysis).Return(adviceText)
 
-		req := httptest.NewRequest("GET", "/portfolio/recommendations/1", http.NoBody)
+		req := httptest.NewRequest("GET", "/portfolio/recommendations/1?monthly_income=5000", http.NoBody)
 		w := httptest.NewRecorder()
 
 		router.ServeHTTP(w, req)
@@ -645,7 +702,7 @@ func TestAdvisorHandler_GetPortfolioRecommendations(t *testing.T) {
 		router := setupGin()
 		router.GET("/portfolio/recommendations/:userId", handler.GetPortfolioRecommendations)
 
-		mockUserService.On("GetByID", uint(999)).Return(domain.User{}, errors.New("user not found"))
+		mockUserService.On("GetByID", mock.Anything, uint(999)).Return(domain.User{}, errors.New("user not found"))
 
 		req := httptest.NewRequest("GET", "/portfolio/recommendations/999", http.NoBody)
 		w := httptest.NewRecorder()
@@ -670,6 +727,7 @@ func TestAdvisorHandler_GetAIRiskAssessment(t *testing.T) {
 			ID:            1,
 			Email:         "test@example.com",
 			RiskTolerance: "moderate",
+			UserProfile:   domain.UserProfile{MonthlyIncome: domain.NewMoney(5000)},
 		}
 
 		assessment := &pkg.AIRiskAssessment{
@@ -682,7 +740,7 @@ func TestAdvisorHandler_GetAIRiskAssessment(t *testing.T) {
 			CreatedAt:             time.Now(),
 		}
 
-		mockUserService.On("GetByID", uint(1)).Return(*user, nil)
+		mockUserService.On("GetByID", mock.Anything, uint(1)).Return(*user, nil)
 		mockMarketService.On("PerformAIRiskAssessment", user, 5000.0, []string{"retirement", "wealth_building"}).Return(assessment, nil)
 
 		req := httptest.NewRequest("GET", "/ai/risk-assessment/1", http.NoBody)
@@ -720,7 +778,7 @@ func TestAdvisorHandler_GetAIRiskAssessment(t *testing.T) {
 			CreatedAt:             time.Now(),
 		}
 
-		mockUserService.On("GetByID", uint(1)).Return(*user, nil)
+		mockUserService.On("GetByID", mock.Anything, uint(1)).Return(*user, nil)
 		mockMarketService.On("PerformAIRiskAssessment", user, 8000.0, []string{"growth"}).Return(assessment, nil)
 
 		req := httptest.NewRequest("GET", "/ai/risk-assessment/1?monthly_income=8000&goals=growth", http.NoBody)
@@ -754,7 +812,7 @@ func TestAdvisorHandler_GetAIMarketPrediction(t *testing.T) {
 			LastUpdated:     time.Now(),
 		}
 
-		mockMarketService.On("AnalyzeMarket").Return(analysis, nil)
+		mockMarketService.On("AnalyzeMarket", mock.Anything).Return(analysis, nil)
 
 		req := httptest.NewRequest("GET", "/ai/market-prediction", http.NoBody)
 		w := httptest.NewRecorder()
@@ -787,7 +845,7 @@ func TestAdvisorHandler_GetAIMarketPrediction(t *testing.T) {
 			LastUpdated:     time.Now(),
 		}
 
-		mockMarketService.On("AnalyzeMarket").Return(analysis, nil)
+		mockMarketService.On("AnalyzeMarket", mock.Anything).Return(analysis, nil)
 
 		req := httptest.NewRequest("GET", "/ai/market-prediction?timeframe=60", http.NoBody)
 		w := httptest.NewRecorder()
@@ -833,11 +891,11 @@ func TestAdvisorHandler_GetAIPortfolioOptimization(t *testing.T) {
 			LastUpdated:     time.Now(),
 		}
 
-		mockUserService.On("GetByID", uint(1)).Return(*user, nil)
+		mockUserService.On("GetByID", mock.Anything, uint(1)).Return(*user, nil)
 		mockMarketService.On("PerformAIRiskAssessment", user, 5000.0, []string{"optimization"}).Return(assessment, nil)
-		mockMarketService.On("AnalyzeMarket").Return(analysis, nil)
+		mockMarketService.On("AnalyzeMarket", mock.Anything).Return(analysis, nil)
 
-		req := httptest.NewRequest("GET", "/ai/portfolio-optimization/1", http.NoBody)
+		req := httptest.NewRequest("GET", "/ai/portfolio-optimization/1?monthly_income=5000", http.NoBody)
 		w := httptest.NewRecorder()
 
 		router.ServeHTTP(w, req)
@@ -881,9 +939,9 @@ func TestAdvisorHandler_GetAIPortfolioOptimization(t *testing.T) {
 			LastUpdated:     time.Now(),
 		}
 
-		mockUserService.On("GetByID", uint(1)).Return(*user, nil)
+		mockUserService.On("GetByID", mock.Anything, uint(1)).Return(*user, nil)
 		mockMarketService.On("PerformAIRiskAssessment", user, 8000.0, []string{"optimization"}).Return(assessment, nil)
-		mockMarketService.On("AnalyzeMarket").Return(analysis, nil)
+		mockMarketService.On("AnalyzeMarket", mock.Anything).Return(analysis, nil)
 
 		req := httptest.NewRequest("GET", "/ai/portfolio-optimization/1?monthly_income=8000&current_value=100000", http.NoBody)
 		w := httptest.NewRecorder()
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"net/http"
+
+	"go-finance-advisor/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AIProviderStatus describes a configured AI or market data provider
+// without revealing its key
+type AIProviderStatus struct {
+	Name          string `json:"name"`
+	BaseURL       string `json:"base_url"`
+	KeyConfigured bool   `json:"key_configured"`
+	DemoKey       bool   `json:"demo_key,omitempty"`
+	Model         string `json:"model,omitempty"`
+}
+
+type AIConfigHandler struct {
+	Config *config.Config
+}
+
+func NewAIConfigHandler(cfg *config.Config) *AIConfigHandler {
+	return &AIConfigHandler{Config: cfg}
+}
+
+// Get returns the heuristics the advisor runs with and which providers it
+// talks to, so operators can check their tuning took effect
+func (h *AIConfigHandler) Get(c *gin.Context) {
+	market, llm := h.Config.Market, h.Config.LLM
+	c.JSON(http.StatusOK, gin.H{
+		"heuristics": h.Config.AI,
+		"providers": []AIProviderStatus{
+			{Name: "coingecko", BaseURL: market.CoinGeckoBaseURL, KeyConfigured: market.CoinGeckoAPIKey != ""},
+			{
+				Name: "alpha_vantage", BaseURL: market.AlphaVantageBaseURL, KeyConfigured: market.AlphaVantageAPIKey != "",
+				DemoKey: market.AlphaVantageAPIKey == config.AlphaVantageDemoKey,
+			},
+			{Name: "llm", BaseURL: llm.BaseURL, KeyConfigured: llm.APIKey != "", Model: llm.Model},
+		},
+	})
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-finance-advisor/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAIConfigHandler_Get(t *testing.T) {
+	cfg := config.Default()
+	cfg.AI.TrendCutoff = 3
+	cfg.LLM.APIKey = "sk-secret"
+	cfg.LLM.Model = "gpt-4o-mini"
+
+	router := setupGin()
+	router.GET("/admin/ai/config", NewAIConfigHandler(cfg).Get)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/ai/config", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "sk-secret", "keys are never shown")
+
+	var response struct {
+		Heuristics config.AIConfig    `json:"heuristics"`
+		Providers  []AIProviderStatus `json:"providers"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, cfg.AI, response.Heuristics)
+	assert.Equal(t, []AIProviderStatus{
+		{Name: "coingecko", BaseURL: cfg.Market.CoinGeckoBaseURL},
+		{Name: "alpha_vantage", BaseURL: cfg.Market.AlphaVantageBaseURL, KeyConfigured: true, DemoKey: true},
+		{Name: "llm", BaseURL: cfg.LLM.BaseURL, KeyConfigured: true, Model: "gpt-4o-mini"},
+	}, response.Providers)
+}
@@ -1,17 +1,35 @@
 package api
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
 
 	"go-finance-advisor/internal/application"
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
 
 	"github.com/gin-gonic/gin"
 )
 
 type AnalyticsHandler struct {
 	Service application.AnalyticsServiceInterface
+	// ClientMaxAge is how long clients may reuse dashboard and metrics
+	// responses; zero tells them not to store them
+	ClientMaxAge time.Duration
+}
+
+// setCacheHeaders lets the client cache a user's response briefly. Responses
+// are private to the user, so shared proxies must not serve them to others.
+func (h *AnalyticsHandler) setCacheHeaders(c *gin.Context) {
+	c.Header("Vary", "Authorization")
+	if h.ClientMaxAge <= 0 {
+		c.Header("Cache-Control", "private, no-store")
+		return
+	}
+	c.Header("Cache-Control", fmt.Sprintf("private, max-age=%d", int(h.ClientMaxAge.Seconds())))
 }
 
 // GetFinancialMetrics returns comprehensive financial metrics for a user
@@ -19,7 +37,7 @@ func (h *AnalyticsHandler) GetFinancialMetrics(c *gin.Context) {
 	userIDStr := c.Param("userId")
 	userID, err := strconv.ParseUint(userIDStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		respondError(c, middleware.CodeInvalidID, "Invalid user ID")
 		return
 	}
 
@@ -33,7 +51,7 @@ func (h *AnalyticsHandler) GetFinancialMetrics(c *gin.Context) {
 	if startDateStr != "" {
 		startDate, err = time.Parse("2006-01-02", startDateStr)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start date format. Use YYYY-MM-DD"})
+			respondError(c, middleware.CodeInvalidDate, "Invalid start date format. Use YYYY-MM-DD")
 			return
 		}
 	} else {
@@ -45,7 +63,7 @@ func (h *AnalyticsHandler) GetFinancialMetrics(c *gin.Context) {
 	if endDateStr != "" {
 		endDate, err = time.Parse("2006-01-02", endDateStr)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end date format. Use YYYY-MM-DD"})
+			respondError(c, middleware.CodeInvalidDate, "Invalid end date format. Use YYYY-MM-DD")
 			return
 		}
 	} else {
@@ -54,12 +72,13 @@ func (h *AnalyticsHandler) GetFinancialMetrics(c *gin.Context) {
 		endDate = time.Date(now.Year(), now.Month()+1, 0, 23, 59, 59, 0, now.Location())
 	}
 
-	metrics, err := h.Service.GetFinancialMetrics(uint(userID), period, startDate, endDate)
+	metrics, err := h.Service.GetFinancialMetrics(c.Request.Context(), uint(userID), period, startDate, endDate)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate financial metrics"})
+		respondInternalError(c, "Failed to calculate financial metrics", err)
 		return
 	}
 
+	h.setCacheHeaders(c)
 	c.JSON(http.StatusOK, metrics)
 }
 
@@ -68,7 +87,7 @@ func (h *AnalyticsHandler) GetIncomeExpenseAnalysis(c *gin.Context) {
 	userIDStr := c.Param("userId")
 	userID, err := strconv.ParseUint(userIDStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		respondError(c, middleware.CodeInvalidID, "Invalid user ID")
 		return
 	}
 
@@ -82,7 +101,7 @@ func (h *AnalyticsHandler) GetIncomeExpenseAnalysis(c *gin.Context) {
 	if startDateStr != "" {
 		startDate, err = time.Parse("2006-01-02", startDateStr)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start date format. Use YYYY-MM-DD"})
+			respondError(c, middleware.CodeInvalidDate, "Invalid start date format. Use YYYY-MM-DD")
 			return
 		}
 	} else {
@@ -94,7 +113,7 @@ func (h *AnalyticsHandler) GetIncomeExpenseAnalysis(c *gin.Context) {
 	if endDateStr != "" {
 		endDate, err = time.Parse("2006-01-02", endDateStr)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end date format. Use YYYY-MM-DD"})
+			respondError(c, middleware.CodeInvalidDate, "Invalid end date format. Use YYYY-MM-DD")
 			return
 		}
 	} else {
@@ -103,9 +122,9 @@ func (h *AnalyticsHandler) GetIncomeExpenseAnalysis(c *gin.Context) {
 		endDate = time.Date(now.Year(), now.Month()+1, 0, 23, 59, 59, 0, now.Location())
 	}
 
-	analysis, err := h.Service.GetIncomeExpenseAnalysis(uint(userID), period, startDate, endDate)
+	analysis, err := h.Service.GetIncomeExpenseAnalysis(c.Request.Context(), uint(userID), period, startDate, endDate)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate income-expense analysis"})
+		respondInternalError(c, "Failed to generate income-expense analysis", err)
 		return
 	}
 
@@ -117,14 +136,14 @@ func (h *AnalyticsHandler) GetCategoryAnalysis(c *gin.Context) {
 	userIDStr := c.Param("userId")
 	userID, err := strconv.ParseUint(userIDStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		respondError(c, middleware.CodeInvalidID, "Invalid user ID")
 		return
 	}
 
 	categoryIDStr := c.Param("categoryId")
 	categoryID, err := strconv.ParseUint(categoryIDStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category ID"})
+		respondError(c, middleware.CodeInvalidID, "Invalid category ID")
 		return
 	}
 
@@ -137,7 +156,7 @@ func (h *AnalyticsHandler) GetCategoryAnalysis(c *gin.Context) {
 	if startDateStr != "" {
 		startDate, err = time.Parse("2006-01-02", startDateStr)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start date format. Use YYYY-MM-DD"})
+			respondError(c, middleware.CodeInvalidDate, "Invalid start date format. Use YYYY-MM-DD")
 			return
 		}
 	} else {
@@ -149,7 +168,7 @@ func (h *AnalyticsHandler) GetCategoryAnalysis(c *gin.Context) {
 	if endDateStr != "" {
 		endDate, err = time.Parse("2006-01-02", endDateStr)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end date format. Use YYYY-MM-DD"})
+			respondError(c, middleware.CodeInvalidDate, "Invalid end date format. Use YYYY-MM-DD")
 			return
 		}
 	} else {
@@ -158,33 +177,343 @@ func (h *AnalyticsHandler) GetCategoryAnalysis(c *gin.Context) {
 		endDate = time.Date(now.Year(), now.Month()+1, 0, 23, 59, 59, 0, now.Location())
 	}
 
-	analysis, err := h.Service.GetCategoryAnalysis(uint(userID), uint(categoryID), startDate, endDate)
+	analysis, err := h.Service.GetCategoryAnalysis(c.Request.Context(), uint(userID), uint(categoryID), startDate, endDate)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate category analysis"})
+		respondInternalError(c, "Failed to generate category analysis", err)
 		return
 	}
 
 	c.JSON(http.StatusOK, analysis)
 }
 
+// GetCategoryTrend returns a category's monthly amounts over the past months
+// with their changes and a projection for the current month
+func (h *AnalyticsHandler) GetCategoryTrend(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+	categoryID, err := strconv.ParseUint(c.Param("categoryId"), 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid category ID")
+		return
+	}
+
+	months := domain.DefaultCategoryTrendMonths
+	if value := c.Query("months"); value != "" {
+		if months, err = strconv.Atoi(value); err != nil {
+			respondError(c, middleware.CodeBadRequest, "Invalid months. Use a whole number")
+			return
+		}
+	}
+
+	trend, err := h.Service.GetCategoryTrend(c.Request.Context(), userID, uint(categoryID), months)
+	if respondValidationError(c, err) {
+		return
+	}
+	if errors.Is(err, domain.ErrNotFound) {
+		respondError(c, middleware.CodeNotFound, "Category not found")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to analyze category trend", err)
+		return
+	}
+
+	h.setCacheHeaders(c)
+	c.JSON(http.StatusOK, trend)
+}
+
 // GetDashboardSummary returns a comprehensive dashboard summary
 func (h *AnalyticsHandler) GetDashboardSummary(c *gin.Context) {
 	userIDStr := c.Param("userId")
 	userID, err := strconv.ParseUint(userIDStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		respondError(c, middleware.CodeInvalidID, "Invalid user ID")
 		return
 	}
 
-	// Get query parameters
-	period := c.DefaultQuery("period", "month")
+	// Without a period the user's preferred one is used
+	period := c.Query("period")
 
 	// Get dashboard summary using the new service method
-	dashboard, err := h.Service.GetDashboardSummary(uint(userID), period)
+	dashboard, err := h.Service.GetDashboardSummary(c.Request.Context(), uint(userID), period)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate dashboard summary"})
+		respondInternalError(c, "Failed to generate dashboard summary", err)
 		return
 	}
 
+	h.setCacheHeaders(c)
 	c.JSON(http.StatusOK, dashboard)
 }
+
+// GetMerchantAnalysis returns spending per merchant with monthly totals.
+// Defaults to the last six months including the current one.
+func (h *AnalyticsHandler) GetMerchantAnalysis(c *gin.Context) {
+	userIDStr := c.Param("userId")
+	userID, err := strconv.ParseUint(userIDStr, 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid user ID")
+		return
+	}
+
+	now := time.Now()
+	startDate := time.Date(now.Year(), now.Month()-5, 1, 0, 0, 0, 0, now.Location())
+	endDate := time.Date(now.Year(), now.Month()+1, 0, 23, 59, 59, 0, now.Location())
+
+	if startDateStr := c.Query("start_date"); startDateStr != "" {
+		startDate, err = time.Parse("2006-01-02", startDateStr)
+		if err != nil {
+			respondError(c, middleware.CodeInvalidDate, "Invalid start date format. Use YYYY-MM-DD")
+			return
+		}
+	}
+	if endDateStr := c.Query("end_date"); endDateStr != "" {
+		endDate, err = time.Parse("2006-01-02", endDateStr)
+		if err != nil {
+			respondError(c, middleware.CodeInvalidDate, "Invalid end date format. Use YYYY-MM-DD")
+			return
+		}
+		endDate = endDate.Add(24*time.Hour - time.Second)
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	analysis, err := h.Service.GetMerchantAnalysis(c.Request.Context(), uint(userID), startDate, endDate, limit)
+	if err != nil {
+		respondInternalError(c, "Failed to analyze merchants", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, analysis)
+}
+
+// GetPlaceAnalysis returns spending clustered by place, or by city with
+// group_by=city, for "where do I spend" maps. Defaults to the last six
+// months including the current one.
+func (h *AnalyticsHandler) GetPlaceAnalysis(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	startDate := time.Date(now.Year(), now.Month()-5, 1, 0, 0, 0, 0, now.Location())
+	endDate := time.Date(now.Year(), now.Month()+1, 0, 23, 59, 59, 0, now.Location())
+	var err error
+
+	if startDateStr := c.Query("start_date"); startDateStr != "" {
+		startDate, err = time.Parse("2006-01-02", startDateStr)
+		if err != nil {
+			respondError(c, middleware.CodeInvalidDate, "Invalid start date format. Use YYYY-MM-DD")
+			return
+		}
+	}
+	if endDateStr := c.Query("end_date"); endDateStr != "" {
+		endDate, err = time.Parse("2006-01-02", endDateStr)
+		if err != nil {
+			respondError(c, middleware.CodeInvalidDate, "Invalid end date format. Use YYYY-MM-DD")
+			return
+		}
+		endDate = endDate.Add(24*time.Hour - time.Second)
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 {
+		limit = 50
+	}
+
+	groupBy := c.DefaultQuery("group_by", domain.PlaceGroupPlace)
+	analysis, err := h.Service.GetPlaceAnalysis(c.Request.Context(), userID, groupBy, startDate, endDate, limit)
+	if respondValidationError(c, err) {
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to analyze places", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, analysis)
+}
+
+// GetItemAnalysis returns spending on line items by item and merchant,
+// e.g. q=coffee for everything spent on coffee. Defaults to the last six
+// months including the current one.
+func (h *AnalyticsHandler) GetItemAnalysis(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	startDate := time.Date(now.Year(), now.Month()-5, 1, 0, 0, 0, 0, now.Location())
+	endDate := time.Date(now.Year(), now.Month()+1, 0, 23, 59, 59, 0, now.Location())
+	var err error
+
+	if startDateStr := c.Query("start_date"); startDateStr != "" {
+		startDate, err = time.Parse("2006-01-02", startDateStr)
+		if err != nil {
+			respondError(c, middleware.CodeInvalidDate, "Invalid start date format. Use YYYY-MM-DD")
+			return
+		}
+	}
+	if endDateStr := c.Query("end_date"); endDateStr != "" {
+		endDate, err = time.Parse("2006-01-02", endDateStr)
+		if err != nil {
+			respondError(c, middleware.CodeInvalidDate, "Invalid end date format. Use YYYY-MM-DD")
+			return
+		}
+		endDate = endDate.Add(24*time.Hour - time.Second)
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 {
+		limit = 50
+	}
+
+	analysis, err := h.Service.GetItemAnalysis(c.Request.Context(), userID, c.Query("q"), startDate, endDate, limit)
+	if respondValidationError(c, err) {
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to analyze items", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, analysis)
+}
+
+// GetIncomeStability returns rolling income averages, a recommended monthly
+// salary and how many months of spending the user's savings cover, over the
+// last twelve complete months unless months is given
+func (h *AnalyticsHandler) GetIncomeStability(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	months := domain.DefaultIncomeStabilityMonths
+	if value := c.Query("months"); value != "" {
+		var err error
+		if months, err = strconv.Atoi(value); err != nil {
+			respondError(c, middleware.CodeBadRequest, "Invalid months. Use a whole number")
+			return
+		}
+	}
+
+	stability, err := h.Service.GetIncomeStability(c.Request.Context(), userID, months)
+	if respondValidationError(c, err) {
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to analyze income stability", err)
+		return
+	}
+
+	h.setCacheHeaders(c)
+	c.JSON(http.StatusOK, stability)
+}
+
+// GetIncomeBreakdown returns the user's gross and net pay, taxes and
+// effective tax rate by income source, over the last twelve complete months
+// unless months is given
+func (h *AnalyticsHandler) GetIncomeBreakdown(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	months := domain.DefaultIncomeBreakdownMonths
+	if value := c.Query("months"); value != "" {
+		var err error
+		if months, err = strconv.Atoi(value); err != nil {
+			respondError(c, middleware.CodeBadRequest, "Invalid months. Use a whole number")
+			return
+		}
+	}
+
+	breakdown, err := h.Service.GetIncomeBreakdown(c.Request.Context(), userID, months)
+	if respondValidationError(c, err) {
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to break down income", err)
+		return
+	}
+
+	h.setCacheHeaders(c)
+	c.JSON(http.StatusOK, breakdown)
+}
+
+// AnalyticsQueryRequest is one query of an analytics batch. Type names the
+// analytics endpoint it stands for, and the other fields are that endpoint's
+// parameters; dates are YYYY-MM-DD and end_date is the last day included.
+type AnalyticsQueryRequest struct {
+	ID         string `json:"id"`
+	Type       string `json:"type"`
+	Period     string `json:"period"`
+	StartDate  string `json:"start_date"`
+	EndDate    string `json:"end_date"`
+	CategoryID uint   `json:"category_id"`
+	Months     int    `json:"months"`
+	GroupBy    string `json:"group_by"`
+	Query      string `json:"q"`
+	Limit      int    `json:"limit"`
+}
+
+// AnalyticsBatchRequest is the body of analytics batches
+type AnalyticsBatchRequest struct {
+	Queries []AnalyticsQueryRequest `json:"queries"`
+}
+
+// Batch runs up to 20 analytics queries concurrently and returns their
+// results in order, each with its data or why it failed, so a dashboard
+// loads with one request
+func (h *AnalyticsHandler) Batch(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req AnalyticsBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	queries := make([]domain.AnalyticsQuery, len(req.Queries))
+	for i, q := range req.Queries {
+		queries[i] = domain.AnalyticsQuery{
+			ID: q.ID, Type: q.Type, Period: q.Period, CategoryID: q.CategoryID,
+			Months: q.Months, GroupBy: q.GroupBy, Query: q.Query, Limit: q.Limit,
+		}
+		var err error
+		if q.StartDate != "" {
+			if queries[i].StartDate, err = time.Parse("2006-01-02", q.StartDate); err != nil {
+				respondError(c, middleware.CodeInvalidDate, fmt.Sprintf("queries[%d]: Invalid start date format. Use YYYY-MM-DD", i))
+				return
+			}
+		}
+		if q.EndDate != "" {
+			if queries[i].EndDate, err = time.Parse("2006-01-02", q.EndDate); err != nil {
+				respondError(c, middleware.CodeInvalidDate, fmt.Sprintf("queries[%d]: Invalid end date format. Use YYYY-MM-DD", i))
+				return
+			}
+		}
+	}
+
+	results, err := application.RunAnalyticsBatch(c.Request.Context(), h.Service, userID, queries)
+	if respondValidationError(c, err) {
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to run analytics batch", err)
+		return
+	}
+
+	h.setCacheHeaders(c)
+	c.JSON(http.StatusOK, gin.H{"results": results})
+}
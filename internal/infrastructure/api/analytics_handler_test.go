@@ -1,15 +1,18 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"go-finance-advisor/internal/domain"
 
+	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -20,29 +23,83 @@ type MockAnalyticsService struct {
 }
 
 func (m *MockAnalyticsService) GetFinancialMetrics(
-	userID uint, period string, startDate, endDate time.Time,
+	ctx context.Context, userID uint, period string, startDate, endDate time.Time,
 ) (*domain.FinancialMetrics, error) {
-	args := m.Called(userID, period, startDate, endDate)
+	args := m.Called(ctx, userID, period, startDate, endDate)
 	return args.Get(0).(*domain.FinancialMetrics), args.Error(1)
 }
 
 func (m *MockAnalyticsService) GetIncomeExpenseAnalysis(
-	userID uint, period string, startDate, endDate time.Time,
+	ctx context.Context, userID uint, period string, startDate, endDate time.Time,
 ) (*domain.IncomeExpenseAnalysis, error) {
-	args := m.Called(userID, period, startDate, endDate)
+	args := m.Called(ctx, userID, period, startDate, endDate)
 	return args.Get(0).(*domain.IncomeExpenseAnalysis), args.Error(1)
 }
 
-func (m *MockAnalyticsService) GetCategoryAnalysis(userID, categoryID uint, startDate, endDate time.Time) (*domain.CategoryMetrics, error) {
-	args := m.Called(userID, categoryID, startDate, endDate)
+func (m *MockAnalyticsService) GetCategoryAnalysis(ctx context.Context, userID, categoryID uint, startDate, endDate time.Time) (*domain.CategoryMetrics, error) {
+	args := m.Called(ctx, userID, categoryID, startDate, endDate)
 	return args.Get(0).(*domain.CategoryMetrics), args.Error(1)
 }
 
-func (m *MockAnalyticsService) GetDashboardSummary(userID uint, period string) (*domain.DashboardSummary, error) {
-	args := m.Called(userID, period)
+func (m *MockAnalyticsService) GetDashboardSummary(ctx context.Context, userID uint, period string) (*domain.DashboardSummary, error) {
+	args := m.Called(ctx, userID, period)
 	return args.Get(0).(*domain.DashboardSummary), args.Error(1)
 }
 
+func (m *MockAnalyticsService) GetMerchantAnalysis(
+	ctx context.Context, userID uint, startDate, endDate time.Time, limit int,
+) (*domain.MerchantAnalysis, error) {
+	args := m.Called(ctx, userID, startDate, endDate, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.MerchantAnalysis), args.Error(1)
+}
+
+func (m *MockAnalyticsService) GetPlaceAnalysis(
+	ctx context.Context, userID uint, groupBy string, startDate, endDate time.Time, limit int,
+) (*domain.PlaceAnalysis, error) {
+	args := m.Called(ctx, userID, groupBy, startDate, endDate, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PlaceAnalysis), args.Error(1)
+}
+
+func (m *MockAnalyticsService) GetItemAnalysis(
+	ctx context.Context, userID uint, query string, startDate, endDate time.Time, limit int,
+) (*domain.ItemAnalysis, error) {
+	args := m.Called(ctx, userID, query, startDate, endDate, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ItemAnalysis), args.Error(1)
+}
+
+func (m *MockAnalyticsService) GetIncomeStability(ctx context.Context, userID uint, months int) (*domain.IncomeStability, error) {
+	args := m.Called(ctx, userID, months)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.IncomeStability), args.Error(1)
+}
+
+func (m *MockAnalyticsService) GetIncomeBreakdown(ctx context.Context, userID uint, months int) (*domain.IncomeBreakdown, error) {
+	args := m.Called(ctx, userID, months)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.IncomeBreakdown), args.Error(1)
+}
+
+func (m *MockAnalyticsService) GetCategoryTrend(ctx context.Context, userID, categoryID uint, months int) (*domain.CategoryTrend, error) {
+	args := m.Called(ctx, userID, categoryID, months)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CategoryTrend), args.Error(1)
+}
+
 func setupAnalyticsHandler() (*AnalyticsHandler, *MockAnalyticsService) {
 	mockService := &MockAnalyticsService{}
 	handler := &AnalyticsHandler{
@@ -70,7 +127,7 @@ func TestAnalyticsHandler_GetFinancialMetrics(t *testing.T) {
 			EndDate:   time.Now(),
 		}
 
-		mockService.On("GetFinancialMetrics", uint(1), "monthly",
+		mockService.On("GetFinancialMetrics", mock.Anything, uint(1), "monthly",
 			mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).
 			Return(metrics, nil)
 
@@ -109,7 +166,7 @@ func TestAnalyticsHandler_GetFinancialMetrics(t *testing.T) {
 
 		startDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
 		endDate := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
-		mockService.On("GetFinancialMetrics", uint(1), "weekly", startDate, endDate).Return(metrics, nil)
+		mockService.On("GetFinancialMetrics", mock.Anything, uint(1), "weekly", startDate, endDate).Return(metrics, nil)
 
 		req := httptest.NewRequest("GET", "/analytics/metrics/1?period=weekly&start_date=2024-01-01&end_date=2024-01-31", http.NoBody)
 		w := httptest.NewRecorder()
@@ -136,7 +193,7 @@ func TestAnalyticsHandler_GetFinancialMetrics(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 		var response map[string]interface{}
 		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Equal(t, "invalid user ID", response["error"])
+		assert.Equal(t, "Invalid user ID", response["error"])
 	})
 
 	t.Run("should return bad request for invalid start date", func(t *testing.T) {
@@ -176,7 +233,7 @@ func TestAnalyticsHandler_GetFinancialMetrics(t *testing.T) {
 		router := setupGin()
 		router.GET("/analytics/metrics/:userId", handler.GetFinancialMetrics)
 
-		mockService.On("GetFinancialMetrics", uint(1), "monthly",
+		mockService.On("GetFinancialMetrics", mock.Anything, uint(1), "monthly",
 			mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).
 			Return((*domain.FinancialMetrics)(nil), errors.New("service error"))
 
@@ -228,7 +285,7 @@ func TestAnalyticsHandler_GetIncomeExpenseAnalysis(t *testing.T) {
 			},
 		}
 
-		mockService.On("GetIncomeExpenseAnalysis", uint(1), "monthly",
+		mockService.On("GetIncomeExpenseAnalysis", mock.Anything, uint(1), "monthly",
 			mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).
 			Return(analysis, nil)
 
@@ -283,7 +340,7 @@ func TestAnalyticsHandler_GetIncomeExpenseAnalysis(t *testing.T) {
 
 		startDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
 		endDate := time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC)
-		mockService.On("GetIncomeExpenseAnalysis", uint(1), "weekly", startDate, endDate).Return(analysis, nil)
+		mockService.On("GetIncomeExpenseAnalysis", mock.Anything, uint(1), "weekly", startDate, endDate).Return(analysis, nil)
 
 		req := httptest.NewRequest("GET", "/analytics/income-expense/1?period=weekly&start_date=2024-01-01&end_date=2024-01-07", http.NoBody)
 		w := httptest.NewRecorder()
@@ -310,7 +367,7 @@ func TestAnalyticsHandler_GetIncomeExpenseAnalysis(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 		var response map[string]interface{}
 		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Equal(t, "invalid user ID", response["error"])
+		assert.Equal(t, "Invalid user ID", response["error"])
 	})
 
 	t.Run("should return internal server error when service fails", func(t *testing.T) {
@@ -318,7 +375,7 @@ func TestAnalyticsHandler_GetIncomeExpenseAnalysis(t *testing.T) {
 		router := setupGin()
 		router.GET("/analytics/income-expense/:userId", handler.GetIncomeExpenseAnalysis)
 
-		mockService.On("GetIncomeExpenseAnalysis", uint(1), "monthly",
+		mockService.On("GetIncomeExpenseAnalysis", mock.Anything, uint(1), "monthly",
 			mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).
 			Return((*domain.IncomeExpenseAnalysis)(nil), errors.New("service error"))
 
@@ -351,7 +408,7 @@ func TestAnalyticsHandler_GetCategoryAnalysis(t *testing.T) {
 			Trend:             "stable",
 		}
 
-		mockService.On("GetCategoryAnalysis", uint(1), uint(5),
+		mockService.On("GetCategoryAnalysis", mock.Anything, uint(1), uint(5),
 			mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).
 			Return(analysis, nil)
 
@@ -387,7 +444,7 @@ func TestAnalyticsHandler_GetCategoryAnalysis(t *testing.T) {
 
 		startDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
 		endDate := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
-		mockService.On("GetCategoryAnalysis", uint(1), uint(3), startDate, endDate).Return(analysis, nil)
+		mockService.On("GetCategoryAnalysis", mock.Anything, uint(1), uint(3), startDate, endDate).Return(analysis, nil)
 
 		req := httptest.NewRequest("GET", "/analytics/category/1/3?start_date=2024-01-01&end_date=2024-01-15", http.NoBody)
 		w := httptest.NewRecorder()
@@ -414,7 +471,7 @@ func TestAnalyticsHandler_GetCategoryAnalysis(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 		var response map[string]interface{}
 		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Equal(t, "invalid user ID", response["error"])
+		assert.Equal(t, "Invalid user ID", response["error"])
 	})
 
 	t.Run("should return bad request for invalid category ID", func(t *testing.T) {
@@ -430,7 +487,7 @@ func TestAnalyticsHandler_GetCategoryAnalysis(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 		var response map[string]interface{}
 		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Equal(t, "invalid category ID", response["error"])
+		assert.Equal(t, "Invalid category ID", response["error"])
 	})
 
 	t.Run("should return internal server error when service fails", func(t *testing.T) {
@@ -438,7 +495,7 @@ func TestAnalyticsHandler_GetCategoryAnalysis(t *testing.T) {
 		router := setupGin()
 		router.GET("/analytics/category/:userId/:categoryId", handler.GetCategoryAnalysis)
 
-		mockService.On("GetCategoryAnalysis", uint(1), uint(5),
+		mockService.On("GetCategoryAnalysis", mock.Anything, uint(1), uint(5),
 			mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).
 			Return((*domain.CategoryMetrics)(nil), errors.New("service error"))
 
@@ -475,7 +532,7 @@ func TestAnalyticsHandler_GetDashboardSummary(t *testing.T) {
 				{CategoryName: "Food", TotalAmount: 800.0},
 				{CategoryName: "Transport", TotalAmount: 300.0},
 			},
-			RecentTransactions: []domain.Transaction{{ID: 1, Amount: 150.0, Description: "Grocery shopping"}},
+			RecentTransactions: []domain.Transaction{{ID: 1, Amount: domain.NewMoney(150.0), Description: "Grocery shopping"}},
 			BudgetAlerts: []domain.BudgetAlert{{
 				BudgetID: 1, CategoryName: "Rent", BudgetAmount: 1200.0,
 				SpentAmount: 1000.0, PercentageUsed: 83.3,
@@ -488,7 +545,7 @@ func TestAnalyticsHandler_GetDashboardSummary(t *testing.T) {
 			},
 		}
 
-		mockService.On("GetDashboardSummary", uint(1), "month").Return(dashboard, nil)
+		mockService.On("GetDashboardSummary", mock.Anything, uint(1), "").Return(dashboard, nil)
 
 		req := httptest.NewRequest("GET", "/analytics/dashboard/1", http.NoBody)
 		w := httptest.NewRecorder()
@@ -524,7 +581,7 @@ func TestAnalyticsHandler_GetDashboardSummary(t *testing.T) {
 			TopExpenseCategories: []domain.CategoryMetrics{
 				{CategoryName: "Food", TotalAmount: 200.0},
 			},
-			RecentTransactions: []domain.Transaction{{ID: 2, Amount: 50.0, Description: "Coffee shop"}},
+			RecentTransactions: []domain.Transaction{{ID: 2, Amount: domain.NewMoney(50.0), Description: "Coffee shop"}},
 			BudgetAlerts:       []domain.BudgetAlert{},
 			FinancialGoals:     []domain.FinancialGoal{},
 			QuickStats: domain.QuickStats{
@@ -533,7 +590,7 @@ func TestAnalyticsHandler_GetDashboardSummary(t *testing.T) {
 			},
 		}
 
-		mockService.On("GetDashboardSummary", uint(1), "week").Return(dashboard, nil)
+		mockService.On("GetDashboardSummary", mock.Anything, uint(1), "week").Return(dashboard, nil)
 
 		req := httptest.NewRequest("GET", "/analytics/dashboard/1?period=week", http.NoBody)
 		w := httptest.NewRecorder()
@@ -560,7 +617,7 @@ func TestAnalyticsHandler_GetDashboardSummary(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 		var response map[string]interface{}
 		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Equal(t, "invalid user ID", response["error"])
+		assert.Equal(t, "Invalid user ID", response["error"])
 	})
 
 	t.Run("should return internal server error when service fails", func(t *testing.T) {
@@ -568,7 +625,7 @@ func TestAnalyticsHandler_GetDashboardSummary(t *testing.T) {
 		router := setupGin()
 		router.GET("/analytics/dashboard/:userId", handler.GetDashboardSummary)
 
-		mockService.On("GetDashboardSummary", uint(1), "month").Return((*domain.DashboardSummary)(nil), errors.New("service error"))
+		mockService.On("GetDashboardSummary", mock.Anything, uint(1), "").Return((*domain.DashboardSummary)(nil), errors.New("service error"))
 
 		req := httptest.NewRequest("GET", "/analytics/dashboard/1", http.NoBody)
 		w := httptest.NewRecorder()
@@ -582,3 +639,484 @@ func TestAnalyticsHandler_GetDashboardSummary(t *testing.T) {
 		mockService.AssertExpectations(t)
 	})
 }
+
+func TestAnalyticsHandler_GetMerchantAnalysis(t *testing.T) {
+	t.Run("should analyze merchants for the given range", func(t *testing.T) {
+		handler, mockService := setupAnalyticsHandler()
+		router := setupGin()
+		router.GET("/users/:userId/analytics/merchants", handler.GetMerchantAnalysis)
+
+		start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		end := time.Date(2024, 3, 31, 23, 59, 59, 0, time.UTC)
+		mockService.On("GetMerchantAnalysis", mock.Anything, uint(1), start, end, 5).Return(&domain.MerchantAnalysis{
+			UserID:        1,
+			TotalExpenses: 120,
+			Merchants:     []domain.MerchantMetrics{{MerchantID: 3, MerchantName: "Amazon", TotalAmount: 120}},
+		}, nil)
+
+		req := httptest.NewRequest("GET", "/users/1/analytics/merchants?start_date=2024-01-01&end_date=2024-03-31&limit=5", http.NoBody)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response domain.MerchantAnalysis
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "Amazon", response.Merchants[0].MerchantName)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return bad request for invalid date", func(t *testing.T) {
+		handler, _ := setupAnalyticsHandler()
+		router := setupGin()
+		router.GET("/users/:userId/analytics/merchants", handler.GetMerchantAnalysis)
+
+		req := httptest.NewRequest("GET", "/users/1/analytics/merchants?start_date=january", http.NoBody)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should return internal server error when service fails", func(t *testing.T) {
+		handler, mockService := setupAnalyticsHandler()
+		router := setupGin()
+		router.GET("/users/:userId/analytics/merchants", handler.GetMerchantAnalysis)
+
+		mockService.On("GetMerchantAnalysis", mock.Anything, uint(1), mock.Anything, mock.Anything, 20).
+			Return(nil, errors.New("database error"))
+
+		req := httptest.NewRequest("GET", "/users/1/analytics/merchants", http.NoBody)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestAnalyticsHandler_GetPlaceAnalysis(t *testing.T) {
+	t.Run("should cluster spending by city for the given range", func(t *testing.T) {
+		handler, mockService := setupAnalyticsHandler()
+		router := setupGin()
+		router.GET("/users/:userId/analytics/places", handler.GetPlaceAnalysis)
+
+		start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		end := time.Date(2024, 3, 31, 23, 59, 59, 0, time.UTC)
+		mockService.On("GetPlaceAnalysis", mock.Anything, uint(1), domain.PlaceGroupCity, start, end, 50).Return(&domain.PlaceAnalysis{
+			UserID: 1,
+			Places: []domain.PlaceSpending{{Name: "Lisbon", TotalAmount: domain.NewMoney(120), TransactionCount: 3}},
+		}, nil)
+
+		req := httptest.NewRequest("GET", "/users/1/analytics/places?group_by=city&start_date=2024-01-01&end_date=2024-03-31", http.NoBody)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response domain.PlaceAnalysis
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "Lisbon", response.Places[0].Name)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should reject unknown groupings", func(t *testing.T) {
+		handler, mockService := setupAnalyticsHandler()
+		router := setupGin()
+		router.GET("/users/:userId/analytics/places", handler.GetPlaceAnalysis)
+
+		mockService.On("GetPlaceAnalysis", mock.Anything, uint(1), "country", mock.Anything, mock.Anything, 50).
+			Return(nil, &domain.ValidationError{Fields: []domain.FieldError{{Field: "group_by", Message: "must be place or city"}}})
+
+		req := httptest.NewRequest("GET", "/users/1/analytics/places?group_by=country", http.NoBody)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+
+	t.Run("should deny other users' places", func(t *testing.T) {
+		handler, _ := setupAnalyticsHandler()
+		router := setupGin()
+		router.Use(func(c *gin.Context) {
+			c.Set("userID", uint(1))
+			c.Next()
+		})
+		router.GET("/users/:userId/analytics/places", handler.GetPlaceAnalysis)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/users/2/analytics/places", http.NoBody))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestAnalyticsHandler_GetItemAnalysis(t *testing.T) {
+	t.Run("should total the matching items for the given range", func(t *testing.T) {
+		handler, mockService := setupAnalyticsHandler()
+		router := setupGin()
+		router.GET("/users/:userId/analytics/items", handler.GetItemAnalysis)
+
+		start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		end := time.Date(2024, 3, 31, 23, 59, 59, 0, time.UTC)
+		mockService.On("GetItemAnalysis", mock.Anything, uint(1), "coffee", start, end, 10).Return(&domain.ItemAnalysis{
+			UserID:      1,
+			Query:       "coffee",
+			TotalAmount: domain.NewMoney(42),
+			Items:       []domain.ItemSpending{{Name: "Coffee", TotalAmount: domain.NewMoney(42), Quantity: 12}},
+		}, nil)
+
+		req := httptest.NewRequest("GET", "/users/1/analytics/items?q=coffee&start_date=2024-01-01&end_date=2024-03-31&limit=10", http.NoBody)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response domain.ItemAnalysis
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, domain.NewMoney(42), response.TotalAmount)
+		assert.Equal(t, "Coffee", response.Items[0].Name)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should reject invalid dates", func(t *testing.T) {
+		handler, _ := setupAnalyticsHandler()
+		router := setupGin()
+		router.GET("/users/:userId/analytics/items", handler.GetItemAnalysis)
+
+		req := httptest.NewRequest("GET", "/users/1/analytics/items?start_date=01-01-2024", http.NoBody)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should deny other users' items", func(t *testing.T) {
+		handler, _ := setupAnalyticsHandler()
+		router := setupGin()
+		router.Use(func(c *gin.Context) {
+			c.Set("userID", uint(1))
+			c.Next()
+		})
+		router.GET("/users/:userId/analytics/items", handler.GetItemAnalysis)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/users/2/analytics/items", http.NoBody))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestAnalyticsHandler_GetIncomeStability(t *testing.T) {
+	t.Run("should analyze the last twelve months by default", func(t *testing.T) {
+		handler, mockService := setupAnalyticsHandler()
+		router := setupGin()
+		router.GET("/users/:userId/analytics/income-stability", handler.GetIncomeStability)
+
+		mockService.On("GetIncomeStability", mock.Anything, uint(1), 12).Return(&domain.IncomeStability{
+			UserID: 1, Stability: domain.IncomeVariable, RecommendedSalary: domain.NewMoney(2800), TargetBufferMonths: 6,
+		}, nil)
+
+		req := httptest.NewRequest("GET", "/users/1/analytics/income-stability", http.NoBody)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response domain.IncomeStability
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, domain.NewMoney(2800), response.RecommendedSalary)
+		assert.Equal(t, domain.IncomeVariable, response.Stability)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should pass the requested months", func(t *testing.T) {
+		handler, mockService := setupAnalyticsHandler()
+		router := setupGin()
+		router.GET("/users/:userId/analytics/income-stability", handler.GetIncomeStability)
+
+		mockService.On("GetIncomeStability", mock.Anything, uint(1), 24).Return(&domain.IncomeStability{UserID: 1}, nil)
+
+		req := httptest.NewRequest("GET", "/users/1/analytics/income-stability?months=24", http.NoBody)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return bad request for invalid months", func(t *testing.T) {
+		handler, _ := setupAnalyticsHandler()
+		router := setupGin()
+		router.GET("/users/:userId/analytics/income-stability", handler.GetIncomeStability)
+
+		req := httptest.NewRequest("GET", "/users/1/analytics/income-stability?months=many", http.NoBody)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should return validation errors from the service", func(t *testing.T) {
+		handler, mockService := setupAnalyticsHandler()
+		router := setupGin()
+		router.GET("/users/:userId/analytics/income-stability", handler.GetIncomeStability)
+
+		mockService.On("GetIncomeStability", mock.Anything, uint(1), 2).Return(nil, &domain.ValidationError{
+			Fields: []domain.FieldError{{Field: "months", Message: "must be between 6 and 36"}},
+		})
+
+		req := httptest.NewRequest("GET", "/users/1/analytics/income-stability?months=2", http.NoBody)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+
+	t.Run("should deny other users' income stability", func(t *testing.T) {
+		handler, _ := setupAnalyticsHandler()
+		router := setupGin()
+		router.Use(func(c *gin.Context) {
+			c.Set("userID", uint(1))
+			c.Next()
+		})
+		router.GET("/users/:userId/analytics/income-stability", handler.GetIncomeStability)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/users/2/analytics/income-stability", http.NoBody))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestAnalyticsHandler_GetIncomeBreakdown(t *testing.T) {
+	t.Run("should break down the last twelve months by default", func(t *testing.T) {
+		handler, mockService := setupAnalyticsHandler()
+		router := setupGin()
+		router.GET("/users/:userId/analytics/income-sources", handler.GetIncomeBreakdown)
+
+		mockService.On("GetIncomeBreakdown", mock.Anything, uint(1), 12).Return(&domain.IncomeBreakdown{
+			UserID: 1, Months: 12, Gross: domain.NewMoney(60000), Net: domain.NewMoney(45000), EffectiveTaxRate: 20,
+		}, nil)
+
+		req := httptest.NewRequest("GET", "/users/1/analytics/income-sources", http.NoBody)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response domain.IncomeBreakdown
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, domain.NewMoney(60000), response.Gross)
+		assert.Equal(t, 20.0, response.EffectiveTaxRate)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return bad request for invalid months", func(t *testing.T) {
+		handler, _ := setupAnalyticsHandler()
+		router := setupGin()
+		router.GET("/users/:userId/analytics/income-sources", handler.GetIncomeBreakdown)
+
+		req := httptest.NewRequest("GET", "/users/1/analytics/income-sources?months=many", http.NoBody)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should deny other users' income breakdown", func(t *testing.T) {
+		handler, _ := setupAnalyticsHandler()
+		router := setupGin()
+		router.Use(func(c *gin.Context) {
+			c.Set("userID", uint(1))
+			c.Next()
+		})
+		router.GET("/users/:userId/analytics/income-sources", handler.GetIncomeBreakdown)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/users/2/analytics/income-sources", http.NoBody))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestAnalyticsHandler_CacheHeaders(t *testing.T) {
+	dashboard := &domain.DashboardSummary{UserID: 1, Period: "month"}
+
+	t.Run("lets the client cache briefly", func(t *testing.T) {
+		handler, mockService := setupAnalyticsHandler()
+		handler.ClientMaxAge = 30 * time.Second
+		router := setupGin()
+		router.GET("/analytics/dashboard/:userId", handler.GetDashboardSummary)
+		mockService.On("GetDashboardSummary", mock.Anything, uint(1), "").Return(dashboard, nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/analytics/dashboard/1", http.NoBody))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "private, max-age=30", w.Header().Get("Cache-Control"))
+		assert.Equal(t, "Authorization", w.Header().Get("Vary"))
+	})
+
+	t.Run("forbids storing without a max age", func(t *testing.T) {
+		handler, mockService := setupAnalyticsHandler()
+		router := setupGin()
+		router.GET("/analytics/metrics/:userId", handler.GetFinancialMetrics)
+		mockService.On("GetFinancialMetrics", mock.Anything, uint(1), "monthly",
+			mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).
+			Return(&domain.FinancialMetrics{UserID: 1}, nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/analytics/metrics/1", http.NoBody))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "private, no-store", w.Header().Get("Cache-Control"))
+	})
+
+	t.Run("does not cache errors", func(t *testing.T) {
+		handler, mockService := setupAnalyticsHandler()
+		handler.ClientMaxAge = 30 * time.Second
+		router := setupGin()
+		router.GET("/analytics/dashboard/:userId", handler.GetDashboardSummary)
+		mockService.On("GetDashboardSummary", mock.Anything, uint(1), "").
+			Return((*domain.DashboardSummary)(nil), errors.New("database error"))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/analytics/dashboard/1", http.NoBody))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Empty(t, w.Header().Get("Cache-Control"))
+	})
+}
+
+func TestAnalyticsHandler_GetCategoryTrend(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		setup      func(*MockAnalyticsService)
+		wantStatus int
+	}{
+		{
+			name: "should analyze the last six months by default", path: "/users/1/analytics/categories/4/trend",
+			setup: func(m *MockAnalyticsService) {
+				m.On("GetCategoryTrend", mock.Anything, uint(1), uint(4), 6).Return(&domain.CategoryTrend{
+					CategoryID: 4, Trend: domain.TrendIncreasing, Projection: domain.NewMoney(320),
+				}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "should pass the requested months", path: "/users/1/analytics/categories/4/trend?months=12",
+			setup: func(m *MockAnalyticsService) {
+				m.On("GetCategoryTrend", mock.Anything, uint(1), uint(4), 12).Return(&domain.CategoryTrend{CategoryID: 4}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "should return bad request for invalid months", path: "/users/1/analytics/categories/4/trend?months=many",
+			setup: func(*MockAnalyticsService) {}, wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "should return validation errors from the service", path: "/users/1/analytics/categories/4/trend?months=1",
+			setup: func(m *MockAnalyticsService) {
+				m.On("GetCategoryTrend", mock.Anything, uint(1), uint(4), 1).Return(nil, &domain.ValidationError{
+					Fields: []domain.FieldError{{Field: "months", Message: "must be between 2 and 36"}},
+				})
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "should report missing categories", path: "/users/1/analytics/categories/9/trend",
+			setup: func(m *MockAnalyticsService) {
+				m.On("GetCategoryTrend", mock.Anything, uint(1), uint(9), 6).Return(nil, domain.ErrNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "should deny other users' trends", path: "/users/2/analytics/categories/4/trend",
+			setup: func(*MockAnalyticsService) {}, wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupAnalyticsHandler()
+			tt.setup(mockService)
+			router := setupGin()
+			router.Use(func(c *gin.Context) {
+				c.Set("userID", uint(1))
+				c.Next()
+			})
+			router.GET("/users/:userId/analytics/categories/:categoryId/trend", handler.GetCategoryTrend)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", tt.path, http.NoBody))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAnalyticsHandler_Batch(t *testing.T) {
+	newRouter := func() (*gin.Engine, *MockAnalyticsService) {
+		handler, mockService := setupAnalyticsHandler()
+		router := setupGin()
+		router.POST("/users/:userId/analytics/batch", handler.Batch)
+		return router, mockService
+	}
+	post := func(router *gin.Engine, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/users/1/analytics/batch", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("should answer every query in order", func(t *testing.T) {
+		router, mockService := newRouter()
+		january := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+		endOfJanuary := time.Date(2024, time.January, 31, 23, 59, 59, 0, time.UTC)
+		mockService.On("GetFinancialMetrics", mock.Anything, uint(1), "monthly", january, endOfJanuary).
+			Return(&domain.FinancialMetrics{TotalIncome: 3000}, nil)
+		mockService.On("GetDashboardSummary", mock.Anything, uint(1), "week").Return(&domain.DashboardSummary{}, nil)
+		mockService.On("GetMerchantAnalysis", mock.Anything, uint(1), mock.Anything, mock.Anything, 20).
+			Return(nil, errors.New("database is locked"))
+
+		w := post(router, `{"queries": [
+			{"id": "income", "type": "metrics", "start_date": "2024-01-01", "end_date": "2024-01-31"},
+			{"id": "summary", "type": "dashboard", "period": "week"},
+			{"id": "shops", "type": "merchants"},
+			{"id": "food", "type": "category"},
+			{"id": "unknown", "type": "forecast"}
+		]}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			Results []struct {
+				ID    string          `json:"id"`
+				Type  string          `json:"type"`
+				Data  json.RawMessage `json:"data"`
+				Error string          `json:"error"`
+			} `json:"results"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response.Results, 5)
+		assert.Equal(t, "income", response.Results[0].ID)
+		var metrics domain.FinancialMetrics
+		assert.NoError(t, json.Unmarshal(response.Results[0].Data, &metrics))
+		assert.InDelta(t, 3000, metrics.TotalIncome, 0.001)
+		assert.Equal(t, "summary", response.Results[1].ID)
+		assert.Empty(t, response.Results[1].Error)
+		assert.Equal(t, "failed to compute merchants", response.Results[2].Error)
+		assert.Contains(t, response.Results[3].Error, "category_id")
+		assert.Contains(t, response.Results[4].Error, "type")
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should reject invalid dates", func(t *testing.T) {
+		router, _ := newRouter()
+		w := post(router, `{"queries": [{"type": "metrics", "start_date": "January"}]}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should reject empty batches", func(t *testing.T) {
+		router, _ := newRouter()
+		w := post(router, `{"queries": []}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ArchiveServiceInterface defines the interface for archived transactions
+// and the retention status admins see
+type ArchiveServiceInterface interface {
+	Archived(ctx context.Context, userID uint, filter domain.ArchiveFilter) ([]domain.Transaction, error)
+	Archive(ctx context.Context) (int64, error)
+	Status(ctx context.Context) (*domain.ArchiveStatus, error)
+}
+
+type ArchiveHandler struct {
+	Service ArchiveServiceInterface
+}
+
+func NewArchiveHandler(service ArchiveServiceInterface) *ArchiveHandler {
+	return &ArchiveHandler{Service: service}
+}
+
+// ListArchived returns the user's archived transactions, newest first,
+// optionally between from and to (YYYY-MM-DD, inclusive). Archives are
+// unpacked on every request, so narrowing the dates makes it faster.
+func (h *ArchiveHandler) ListArchived(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+	var filter domain.ArchiveFilter
+	var err error
+	if filter.From, err = parseOptionalDate(c.Query("from")); err != nil {
+		respondError(c, middleware.CodeInvalidDate, "Invalid from date format. Use YYYY-MM-DD")
+		return
+	}
+	if filter.To, err = parseOptionalDate(c.Query("to")); err != nil {
+		respondError(c, middleware.CodeInvalidDate, "Invalid to date format. Use YYYY-MM-DD")
+		return
+	}
+
+	transactions, err := h.Service.Archived(c.Request.Context(), userID, filter)
+	if respondValidationError(c, err) {
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve archived transactions", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"transactions": transactions, "count": len(transactions)})
+}
+
+// GetStatus reports the archives of all users and what retention has left
+// to archive and purge
+func (h *ArchiveHandler) GetStatus(c *gin.Context) {
+	status, err := h.Service.Status(c.Request.Context())
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve archive status", err)
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// Run archives the transactions old enough now instead of on the next
+// scheduled run
+func (h *ArchiveHandler) Run(c *gin.Context) {
+	archived, err := h.Service.Archive(c.Request.Context())
+	if err != nil {
+		respondInternalError(c, "Failed to archive transactions", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"archived": archived})
+}
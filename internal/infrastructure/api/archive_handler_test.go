@@ -0,0 +1,123 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockArchiveService is a mock implementation of ArchiveServiceInterface
+type MockArchiveService struct {
+	mock.Mock
+}
+
+func (m *MockArchiveService) Archived(ctx context.Context, userID uint, filter domain.ArchiveFilter) ([]domain.Transaction, error) {
+	args := m.Called(ctx, userID, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Transaction), args.Error(1)
+}
+
+func (m *MockArchiveService) Archive(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockArchiveService) Status(ctx context.Context) (*domain.ArchiveStatus, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ArchiveStatus), args.Error(1)
+}
+
+func setupArchiveRouter(service *MockArchiveService) *gin.Engine {
+	handler := NewArchiveHandler(service)
+	router := setupGin()
+	router.GET("/admin/archive", handler.GetStatus)
+	router.POST("/admin/archive/run", handler.Run)
+	user := router.Group("/", func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Next()
+	})
+	user.GET("/users/:userId/transactions/archive", handler.ListArchived)
+	return router
+}
+
+func TestArchiveHandler_ListArchived(t *testing.T) {
+	from := time.Date(2015, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2014, time.January, 1, 0, 0, 0, 0, time.UTC)
+	service := new(MockArchiveService)
+	service.On("Archived", mock.Anything, uint(1), domain.ArchiveFilter{}).
+		Return([]domain.Transaction{{ID: 7, Description: "Rent", Amount: 800}}, nil).Once()
+	service.On("Archived", mock.Anything, uint(1), domain.ArchiveFilter{From: &from, To: &to}).
+		Return(nil, &domain.ValidationError{Fields: []domain.FieldError{{Field: "from", Message: "must not be after to"}}})
+	service.On("Archived", mock.Anything, uint(1), domain.ArchiveFilter{}).Return(nil, errors.New("corrupt archive")).Once()
+	router := setupArchiveRouter(service)
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+	}{
+		{"lists the archive", "/users/1/transactions/archive", http.StatusOK},
+		{"rejects reversed dates", "/users/1/transactions/archive?from=2015-01-01&to=2014-01-01", http.StatusUnprocessableEntity},
+		{"rejects malformed dates", "/users/1/transactions/archive?from=2015", http.StatusBadRequest},
+		{"fails with the service", "/users/1/transactions/archive", http.StatusInternalServerError},
+		{"keeps other users' archives", "/users/2/transactions/archive", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, http.NoBody))
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusOK {
+				var response struct {
+					Transactions []domain.Transaction `json:"transactions"`
+					Count        int                  `json:"count"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, 1, response.Count)
+				assert.Equal(t, "Rent", response.Transactions[0].Description)
+			}
+		})
+	}
+	service.AssertExpectations(t)
+}
+
+func TestArchiveHandler_StatusAndRun(t *testing.T) {
+	service := new(MockArchiveService)
+	service.On("Status", mock.Anything).Return(&domain.ArchiveStatus{ArchiveAfterYears: 7, Archives: 3, Transactions: 1200}, nil).Once()
+	service.On("Status", mock.Anything).Return(nil, errors.New("database is locked")).Once()
+	service.On("Archive", mock.Anything).Return(int64(42), nil)
+	router := setupArchiveRouter(service)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/archive", http.NoBody))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var status domain.ArchiveStatus
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+	assert.Equal(t, 1200, status.Transactions)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/archive", http.NoBody))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/archive/run", http.NoBody))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"archived": 42}`, w.Body.String())
+	service.AssertExpectations(t)
+}
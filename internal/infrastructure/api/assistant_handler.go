@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AssistantServiceInterface defines the interface for answering questions
+// about a user's finances
+type AssistantServiceInterface interface {
+	Ask(ctx context.Context, userID uint, question string) (*domain.AssistantAnswer, error)
+}
+
+type AssistantHandler struct {
+	Service AssistantServiceInterface
+}
+
+func NewAssistantHandler(service AssistantServiceInterface) *AssistantHandler {
+	return &AssistantHandler{Service: service}
+}
+
+// AssistantRequest is the body of questions to the assistant
+type AssistantRequest struct {
+	Question string `json:"question" binding:"required"`
+}
+
+// Ask answers a question about the user's finances asked in plain language
+// with the answer text, the query it was translated into and the figures
+// the answer states
+func (h *AssistantHandler) Ask(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req AssistantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	answer, err := h.Service.Ask(c.Request.Context(), userID, req.Question)
+	if err != nil {
+		switch {
+		case respondValidationError(c, err):
+		case errors.Is(err, domain.ErrAssistantUnanswerable):
+			respondError(c, middleware.CodeUnprocessable, err.Error())
+		case errors.Is(err, domain.ErrAssistantUnavailable):
+			middleware.RespondError(c, &middleware.APIError{
+				Code: middleware.CodeUnavailable, Message: "The assistant is unavailable", Cause: err,
+			})
+		default:
+			respondInternalError(c, "Failed to answer the question", err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, answer)
+}
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockAssistantService is a mock implementation of AssistantServiceInterface
+type MockAssistantService struct {
+	mock.Mock
+}
+
+func (m *MockAssistantService) Ask(ctx context.Context, userID uint, question string) (*domain.AssistantAnswer, error) {
+	args := m.Called(ctx, userID, question)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AssistantAnswer), args.Error(1)
+}
+
+func setupAssistantRouter(service *MockAssistantService) *gin.Engine {
+	handler := NewAssistantHandler(service)
+	router := setupGin()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Next()
+	})
+	router.POST("/users/:userId/assistant", handler.Ask)
+	return router
+}
+
+func TestAssistantHandler_Ask(t *testing.T) {
+	question := "how much did I spend on groceries last quarter?"
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		mockSetup      func(*MockAssistantService)
+		expectedStatus int
+	}{
+		{
+			name: "answers the question",
+			path: "/users/1/assistant",
+			body: `{"question": "` + question + `"}`,
+			mockSetup: func(m *MockAssistantService) {
+				m.On("Ask", mock.Anything, uint(1), question).Return(&domain.AssistantAnswer{
+					Question: question,
+					Answer:   "You spent 200.25 in Groceries between 1 Jan 2024 and 31 Mar 2024 across 2 transaction(s).",
+					Query:    domain.AssistantQuery{Kind: domain.AssistantQuerySpending, Category: "Groceries"},
+					Figures:  []domain.AssistantFigure{{Label: "Groceries", Value: 200.25, Unit: domain.FigureUnitAmount}},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing question",
+			path:           "/users/1/assistant",
+			body:           `{}`,
+			mockSetup:      func(m *MockAssistantService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "question too long",
+			path: "/users/1/assistant",
+			body: `{"question": "` + strings.Repeat("a", 501) + `"}`,
+			mockSetup: func(m *MockAssistantService) {
+				m.On("Ask", mock.Anything, uint(1), mock.Anything).Return(nil, &domain.ValidationError{
+					Fields: []domain.FieldError{{Field: "question", Message: "must be between 1 and 500 characters"}},
+				})
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "question the assistant cannot answer",
+			path: "/users/1/assistant",
+			body: `{"question": "what is the weather like?"}`,
+			mockSetup: func(m *MockAssistantService) {
+				m.On("Ask", mock.Anything, uint(1), "what is the weather like?").Return(nil, domain.ErrAssistantUnanswerable)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "model unreachable",
+			path: "/users/1/assistant",
+			body: `{"question": "` + question + `"}`,
+			mockSetup: func(m *MockAssistantService) {
+				m.On("Ask", mock.Anything, uint(1), question).
+					Return(nil, fmt.Errorf("%w: connection refused", domain.ErrAssistantUnavailable))
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name: "service error",
+			path: "/users/1/assistant",
+			body: `{"question": "` + question + `"}`,
+			mockSetup: func(m *MockAssistantService) {
+				m.On("Ask", mock.Anything, uint(1), question).Return(nil, errors.New("db down"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "other users' finances are forbidden",
+			path:           "/users/2/assistant",
+			body:           `{"question": "` + question + `"}`,
+			mockSetup:      func(m *MockAssistantService) {},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(MockAssistantService)
+			tt.mockSetup(service)
+			router := setupAssistantRouter(service)
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var answer domain.AssistantAnswer
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &answer))
+				assert.Equal(t, domain.AssistantQuerySpending, answer.Query.Kind)
+				assert.Len(t, answer.Figures, 1)
+			}
+			service.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultAuditLimit = 50
+	maxAuditLimit     = 500
+)
+
+// AuditServiceInterface defines the interface for querying the audit log
+type AuditServiceInterface interface {
+	List(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditLog, error)
+}
+
+type AuditHandler struct {
+	Service AuditServiceInterface
+}
+
+func NewAuditHandler(service AuditServiceInterface) *AuditHandler {
+	return &AuditHandler{Service: service}
+}
+
+// GetUserAudit lists the changes made to a user's data. Users can only see
+// their own audit trail; administrators use GetAudit.
+func (h *AuditHandler) GetUserAudit(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid user ID")
+		return
+	}
+	if authUserID, ok := c.Get("userID"); ok && authUserID != uint(userID) {
+		respondError(c, middleware.CodeForbidden, "Access denied")
+		return
+	}
+
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		respondError(c, middleware.CodeBadRequest, err.Error())
+		return
+	}
+	filter.UserID = uint(userID)
+
+	h.respond(c, filter)
+}
+
+// GetAudit lists changes across all users, optionally narrowed by user_id and actor_id
+func (h *AuditHandler) GetAudit(c *gin.Context) {
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		respondError(c, middleware.CodeBadRequest, err.Error())
+		return
+	}
+
+	if filter.UserID, err = parseOptionalID(c.Query("user_id")); err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid user_id")
+		return
+	}
+	if filter.ActorID, err = parseOptionalID(c.Query("actor_id")); err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid actor_id")
+		return
+	}
+
+	h.respond(c, filter)
+}
+
+func (h *AuditHandler) respond(c *gin.Context, filter domain.AuditFilter) {
+	entries, err := h.Service.List(c.Request.Context(), filter)
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve audit log", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// parseAuditFilter reads entity_type, entity_id, action, since, until
+// (YYYY-MM-DD, inclusive), limit and offset from the query string
+func parseAuditFilter(c *gin.Context) (domain.AuditFilter, error) {
+	filter := domain.AuditFilter{
+		EntityType: c.Query("entity_type"),
+		Action:     c.Query("action"),
+		Limit:      defaultAuditLimit,
+	}
+
+	var err error
+	if filter.EntityID, err = parseOptionalID(c.Query("entity_id")); err != nil {
+		return filter, errors.New("invalid entity_id")
+	}
+
+	if since := c.Query("since"); since != "" {
+		start, parseErr := time.Parse("2006-01-02", since)
+		if parseErr != nil {
+			return filter, errors.New("invalid since date format. Use YYYY-MM-DD")
+		}
+		filter.Since = &start
+	}
+	if until := c.Query("until"); until != "" {
+		end, parseErr := time.Parse("2006-01-02", until)
+		if parseErr != nil {
+			return filter, errors.New("invalid until date format. Use YYYY-MM-DD")
+		}
+		end = end.Add(24*time.Hour - time.Nanosecond)
+		filter.Until = &end
+	}
+
+	if limit, parseErr := strconv.Atoi(c.Query("limit")); parseErr == nil && limit > 0 {
+		filter.Limit = min(limit, maxAuditLimit)
+	}
+	if offset, parseErr := strconv.Atoi(c.Query("offset")); parseErr == nil && offset > 0 {
+		filter.Offset = offset
+	}
+
+	return filter, nil
+}
+
+func parseOptionalID(value string) (uint, error) {
+	if value == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseUint(value, 10, 32)
+	return uint(id), err
+}
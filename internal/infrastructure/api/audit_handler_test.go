@@ -0,0 +1,116 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockAuditService is a mock implementation of AuditServiceInterface
+type MockAuditService struct {
+	mock.Mock
+}
+
+func (m *MockAuditService) List(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditLog, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.AuditLog), args.Error(1)
+}
+
+func TestAuditHandler_GetUserAudit(t *testing.T) {
+	t.Run("should list the user's audit entries", func(t *testing.T) {
+		mockService := new(MockAuditService)
+		handler := NewAuditHandler(mockService)
+		router := setupGin()
+		router.GET("/users/:userId/audit", handler.GetUserAudit)
+
+		mockService.On("List", mock.Anything, mock.MatchedBy(func(filter domain.AuditFilter) bool {
+			return filter.UserID == 1 && filter.EntityType == domain.AuditEntityBudget &&
+				filter.Limit == 10 && filter.Since != nil && filter.Until != nil &&
+				filter.Until.Format("2006-01-02") == "2024-03-31"
+		})).Return([]domain.AuditLog{{ID: 5, UserID: 1, EntityType: domain.AuditEntityBudget, Action: domain.AuditActionUpdate}}, nil)
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet,
+			"/users/1/audit?entity_type=budget&since=2024-03-01&until=2024-03-31&limit=10", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var entries []domain.AuditLog
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &entries))
+		require.Len(t, entries, 1)
+		assert.Equal(t, uint(5), entries[0].ID)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should forbid other users' audit entries", func(t *testing.T) {
+		mockService := new(MockAuditService)
+		handler := NewAuditHandler(mockService)
+		router := setupGin()
+		router.GET("/users/:userId/audit", func(c *gin.Context) {
+			c.Set("userID", uint(2))
+			handler.GetUserAudit(c)
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1/audit", nil))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		mockService.AssertNotCalled(t, "List")
+	})
+
+	t.Run("should reject invalid dates", func(t *testing.T) {
+		handler := NewAuditHandler(new(MockAuditService))
+		router := setupGin()
+		router.GET("/users/:userId/audit", handler.GetUserAudit)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1/audit?since=yesterday", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestAuditHandler_GetAudit(t *testing.T) {
+	t.Run("should filter by user and actor", func(t *testing.T) {
+		mockService := new(MockAuditService)
+		handler := NewAuditHandler(mockService)
+		router := setupGin()
+		router.GET("/admin/audit", handler.GetAudit)
+
+		mockService.On("List", mock.Anything, domain.AuditFilter{UserID: 3, ActorID: 4, Limit: defaultAuditLimit}).
+			Return([]domain.AuditLog{}, nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/audit?user_id=3&actor_id=4", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return 500 when the service fails", func(t *testing.T) {
+		mockService := new(MockAuditService)
+		handler := NewAuditHandler(mockService)
+		router := setupGin()
+		router.GET("/admin/audit", handler.GetAudit)
+
+		mockService.On("List", mock.Anything, mock.Anything).Return(nil, errors.New("db down"))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/audit", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
@@ -0,0 +1,184 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BankSyncServiceInterface defines the interface for linking and syncing bank accounts
+type BankSyncServiceInterface interface {
+	StartLink(ctx context.Context, userID uint, provider, institutionID, redirectURL string) (*domain.BankLinkSession, error)
+	Link(ctx context.Context, userID uint, provider, token string) ([]domain.BankLink, error)
+	List(ctx context.Context, userID uint) ([]domain.BankLink, error)
+	Get(ctx context.Context, userID, id uint) (*domain.BankLink, error)
+	Refresh(ctx context.Context, userID, id uint) (*domain.BankSyncResult, error)
+	Unlink(ctx context.Context, userID, id uint) error
+}
+
+type BankSyncHandler struct {
+	Service BankSyncServiceInterface
+}
+
+func NewBankSyncHandler(service BankSyncServiceInterface) *BankSyncHandler {
+	return &BankSyncHandler{Service: service}
+}
+
+// BankLinkSessionRequest is the body of requests starting to link a bank.
+// GoCardless needs the institution and the URL the user returns to.
+type BankLinkSessionRequest struct {
+	Provider      string `json:"provider" binding:"required"`
+	InstitutionID string `json:"institution_id"`
+	RedirectURL   string `json:"redirect_url"`
+}
+
+// BankLinkRequest is the body of requests completing a link with the token
+// the provider's link flow ended with: Plaid's public token or the
+// GoCardless requisition ID
+type BankLinkRequest struct {
+	Provider string `json:"provider" binding:"required"`
+	Token    string `json:"token" binding:"required"`
+}
+
+// respondProviderError answers errors of calls that reach a bank provider
+func respondProviderError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, domain.ErrUnknownBankProvider):
+		respondError(c, middleware.CodeBadRequest, err.Error())
+	case errors.Is(err, domain.ErrBankReauthRequired):
+		respondError(c, middleware.CodeConflict, err.Error())
+	default:
+		middleware.RespondError(c, &middleware.APIError{Code: middleware.CodeUnavailable, Message: message, Cause: err})
+	}
+}
+
+// StartLink begins linking a bank, returning the token or URL the client
+// continues with
+func (h *BankSyncHandler) StartLink(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req BankLinkSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	session, err := h.Service.StartLink(c.Request.Context(), userID, req.Provider, req.InstitutionID, req.RedirectURL)
+	if err != nil {
+		respondProviderError(c, "Failed to start linking the bank", err)
+		return
+	}
+	c.JSON(http.StatusOK, session)
+}
+
+// Link completes linking a bank and returns the linked accounts after their first sync
+func (h *BankSyncHandler) Link(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req BankLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	links, err := h.Service.Link(c.Request.Context(), userID, req.Provider, req.Token)
+	if err != nil {
+		respondProviderError(c, "Failed to link the bank", err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"links": links, "count": len(links)})
+}
+
+// List returns the user's linked accounts with their sync status
+func (h *BankSyncHandler) List(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	links, err := h.Service.List(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve linked accounts", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"links": links, "count": len(links)})
+}
+
+// Get returns the sync status of a linked account
+func (h *BankSyncHandler) Get(c *gin.Context) {
+	userID, linkID, ok := h.linkParams(c)
+	if !ok {
+		return
+	}
+
+	link, err := h.Service.Get(c.Request.Context(), userID, linkID)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "Linked account not found")
+	case err != nil:
+		respondInternalError(c, "Failed to retrieve linked account", err)
+	default:
+		c.JSON(http.StatusOK, link)
+	}
+}
+
+// Refresh syncs a linked account now
+func (h *BankSyncHandler) Refresh(c *gin.Context) {
+	userID, linkID, ok := h.linkParams(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.Service.Refresh(c.Request.Context(), userID, linkID)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "Linked account not found")
+	case err != nil:
+		respondProviderError(c, "Failed to sync the linked account", err)
+	default:
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// Unlink removes a linked account, keeping the transactions it brought in
+func (h *BankSyncHandler) Unlink(c *gin.Context) {
+	userID, linkID, ok := h.linkParams(c)
+	if !ok {
+		return
+	}
+
+	err := h.Service.Unlink(c.Request.Context(), userID, linkID)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "Linked account not found")
+	case err != nil:
+		respondInternalError(c, "Failed to unlink the account", err)
+	default:
+		c.JSON(http.StatusOK, gin.H{"message": "Account unlinked"})
+	}
+}
+
+func (h *BankSyncHandler) linkParams(c *gin.Context) (userID, linkID uint, ok bool) {
+	userID, ok = authorizedUserID(c)
+	if !ok {
+		return 0, 0, false
+	}
+	id, err := strconv.ParseUint(c.Param("linkId"), 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid link ID")
+		return 0, 0, false
+	}
+	return userID, uint(id), true
+}
@@ -0,0 +1,204 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockBankSyncService is a mock implementation of BankSyncServiceInterface
+type MockBankSyncService struct {
+	mock.Mock
+}
+
+func (m *MockBankSyncService) StartLink(
+	ctx context.Context, userID uint, provider, institutionID, redirectURL string,
+) (*domain.BankLinkSession, error) {
+	args := m.Called(ctx, userID, provider, institutionID, redirectURL)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.BankLinkSession), args.Error(1)
+}
+
+func (m *MockBankSyncService) Link(ctx context.Context, userID uint, provider, token string) ([]domain.BankLink, error) {
+	args := m.Called(ctx, userID, provider, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.BankLink), args.Error(1)
+}
+
+func (m *MockBankSyncService) List(ctx context.Context, userID uint) ([]domain.BankLink, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.BankLink), args.Error(1)
+}
+
+func (m *MockBankSyncService) Get(ctx context.Context, userID, id uint) (*domain.BankLink, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.BankLink), args.Error(1)
+}
+
+func (m *MockBankSyncService) Refresh(ctx context.Context, userID, id uint) (*domain.BankSyncResult, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.BankSyncResult), args.Error(1)
+}
+
+func (m *MockBankSyncService) Unlink(ctx context.Context, userID, id uint) error {
+	return m.Called(ctx, userID, id).Error(0)
+}
+
+func setupBankSyncRouter(service *MockBankSyncService) *gin.Engine {
+	handler := NewBankSyncHandler(service)
+	router := setupGin()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Next()
+	})
+	router.POST("/users/:userId/bank-links/sessions", handler.StartLink)
+	router.POST("/users/:userId/bank-links", handler.Link)
+	router.GET("/users/:userId/bank-links", handler.List)
+	router.GET("/users/:userId/bank-links/:linkId", handler.Get)
+	router.DELETE("/users/:userId/bank-links/:linkId", handler.Unlink)
+	router.POST("/users/:userId/bank-links/:linkId/refresh", handler.Refresh)
+	return router
+}
+
+func TestBankSyncHandler_Link(t *testing.T) {
+	t.Run("should start a link session", func(t *testing.T) {
+		service := new(MockBankSyncService)
+		service.On("StartLink", mock.Anything, uint(1), domain.BankProviderPlaid, "", "").
+			Return(&domain.BankLinkSession{Provider: domain.BankProviderPlaid, Token: "link-sandbox-1"}, nil)
+
+		w := httptest.NewRecorder()
+		setupBankSyncRouter(service).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/1/bank-links/sessions",
+			strings.NewReader(`{"provider":"plaid"}`)))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "link-sandbox-1")
+		service.AssertExpectations(t)
+	})
+
+	t.Run("should return 400 for unconfigured providers and 503 when the provider fails", func(t *testing.T) {
+		service := new(MockBankSyncService)
+		service.On("StartLink", mock.Anything, uint(1), "finicity", "", "").Return(nil, domain.ErrUnknownBankProvider)
+		service.On("StartLink", mock.Anything, uint(1), domain.BankProviderPlaid, "", "").Return(nil, errors.New("timeout"))
+		router := setupBankSyncRouter(service)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/1/bank-links/sessions",
+			strings.NewReader(`{"provider":"finicity"}`)))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/1/bank-links/sessions",
+			strings.NewReader(`{"provider":"plaid"}`)))
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	})
+
+	t.Run("should link the accounts of a connection", func(t *testing.T) {
+		service := new(MockBankSyncService)
+		service.On("Link", mock.Anything, uint(1), domain.BankProviderPlaid, "public-1").Return([]domain.BankLink{
+			{ID: 1, UserID: 1, Provider: domain.BankProviderPlaid, AccessToken: "access-1", Name: "Checking"},
+		}, nil)
+
+		w := httptest.NewRecorder()
+		setupBankSyncRouter(service).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/1/bank-links",
+			strings.NewReader(`{"provider":"plaid","token":"public-1"}`)))
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"count":1`)
+		assert.NotContains(t, w.Body.String(), "access-1")
+	})
+
+	t.Run("should return 400 without a token and 403 for other users", func(t *testing.T) {
+		service := new(MockBankSyncService)
+		router := setupBankSyncRouter(service)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/1/bank-links", strings.NewReader(`{"provider":"plaid"}`)))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/2/bank-links",
+			strings.NewReader(`{"provider":"plaid","token":"public-1"}`)))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		service.AssertNotCalled(t, "Link")
+	})
+}
+
+func TestBankSyncHandler_Links(t *testing.T) {
+	t.Run("should list and get linked accounts", func(t *testing.T) {
+		service := new(MockBankSyncService)
+		service.On("List", mock.Anything, uint(1)).Return([]domain.BankLink{{ID: 1}, {ID: 2}}, nil)
+		service.On("Get", mock.Anything, uint(1), uint(1)).
+			Return(&domain.BankLink{ID: 1, Status: domain.BankLinkStatusActive}, nil)
+		service.On("Get", mock.Anything, uint(1), uint(3)).Return(nil, domain.ErrNotFound)
+		router := setupBankSyncRouter(service)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1/bank-links", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"count":2`)
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1/bank-links/1", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"active"`)
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1/bank-links/3", nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1/bank-links/abc", nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should refresh a linked account", func(t *testing.T) {
+		service := new(MockBankSyncService)
+		service.On("Refresh", mock.Anything, uint(1), uint(1)).
+			Return(&domain.BankSyncResult{LinkID: 1, Status: domain.BankLinkStatusActive, Added: 4}, nil)
+		service.On("Refresh", mock.Anything, uint(1), uint(2)).Return(nil, domain.ErrBankReauthRequired)
+		router := setupBankSyncRouter(service)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/1/bank-links/1/refresh", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"added":4`)
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/1/bank-links/2/refresh", nil))
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("should unlink an account", func(t *testing.T) {
+		service := new(MockBankSyncService)
+		service.On("Unlink", mock.Anything, uint(1), uint(1)).Return(nil)
+		service.On("Unlink", mock.Anything, uint(1), uint(2)).Return(domain.ErrNotFound)
+		router := setupBankSyncRouter(service)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/users/1/bank-links/1", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/users/1/bank-links/2", nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
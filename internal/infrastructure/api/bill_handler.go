@@ -0,0 +1,244 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultCalendarDays is how far ahead the calendar looks without a to date
+const defaultCalendarDays = 30
+
+// BillServiceInterface defines the interface for bill operations
+type BillServiceInterface interface {
+	Create(ctx context.Context, userID uint, bill *domain.Bill) error
+	List(ctx context.Context, userID uint) ([]domain.Bill, error)
+	Get(ctx context.Context, userID, id uint) (*domain.Bill, error)
+	Update(ctx context.Context, userID uint, bill *domain.Bill) error
+	Delete(ctx context.Context, userID, id uint) error
+	Calendar(ctx context.Context, userID uint, from, to time.Time) (*domain.DueCalendar, error)
+}
+
+type BillHandler struct {
+	Service BillServiceInterface
+}
+
+func NewBillHandler(service BillServiceInterface) *BillHandler {
+	return &BillHandler{Service: service}
+}
+
+// CreateBillRequest is the body of requests adding a bill. Reminders go out
+// domain.DefaultBillRemindDays days ahead unless remind_days_before is given.
+type CreateBillRequest struct {
+	Payee            string       `json:"payee" binding:"required"`
+	Amount           domain.Money `json:"amount"`
+	DueDay           int          `json:"due_day" binding:"required"`
+	Autopay          bool         `json:"autopay"`
+	CategoryID       *uint        `json:"category_id"`
+	Notes            string       `json:"notes"`
+	RemindDaysBefore *int         `json:"remind_days_before"`
+}
+
+type UpdateBillRequest struct {
+	Payee            *string       `json:"payee,omitempty"`
+	Amount           *domain.Money `json:"amount,omitempty"`
+	DueDay           *int          `json:"due_day,omitempty"`
+	Autopay          *bool         `json:"autopay,omitempty"`
+	CategoryID       *uint         `json:"category_id,omitempty"`
+	Notes            *string       `json:"notes,omitempty"`
+	RemindDaysBefore *int          `json:"remind_days_before,omitempty"`
+	Active           *bool         `json:"active,omitempty"`
+}
+
+// parseBillIDs reads the userId and billId parameters. Users can only
+// manage their own bills.
+func parseBillIDs(c *gin.Context) (userID, billID uint, ok bool) {
+	userID, ok = authorizedUserID(c)
+	if !ok {
+		return 0, 0, false
+	}
+	id, err := strconv.ParseUint(c.Param("billId"), 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid bill ID")
+		return 0, 0, false
+	}
+	return userID, uint(id), true
+}
+
+func respondBillError(c *gin.Context, err error, message string) {
+	if respondValidationError(c, err) {
+		return
+	}
+	if errors.Is(err, domain.ErrNotFound) {
+		respondError(c, middleware.CodeNotFound, "Bill not found")
+		return
+	}
+	respondInternalError(c, message, err)
+}
+
+// Create adds a bill for the user
+func (h *BillHandler) Create(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req CreateBillRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	bill := &domain.Bill{
+		Payee: req.Payee, Amount: req.Amount, DueDay: req.DueDay, Autopay: req.Autopay,
+		CategoryID: req.CategoryID, Notes: req.Notes, RemindDaysBefore: domain.DefaultBillRemindDays,
+	}
+	if req.RemindDaysBefore != nil {
+		bill.RemindDaysBefore = *req.RemindDaysBefore
+	}
+	if err := h.Service.Create(c.Request.Context(), userID, bill); err != nil {
+		respondBillError(c, err, "Failed to create bill")
+		return
+	}
+	c.JSON(http.StatusCreated, bill)
+}
+
+// List returns the user's bills
+func (h *BillHandler) List(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	bills, err := h.Service.List(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve bills", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"bills": bills, "count": len(bills)})
+}
+
+// Get returns one bill
+func (h *BillHandler) Get(c *gin.Context) {
+	userID, billID, ok := parseBillIDs(c)
+	if !ok {
+		return
+	}
+
+	bill, err := h.Service.Get(c.Request.Context(), userID, billID)
+	if err != nil {
+		respondBillError(c, err, "Failed to retrieve bill")
+		return
+	}
+	c.JSON(http.StatusOK, bill)
+}
+
+// Update changes the details, reminder or active flag of a bill
+func (h *BillHandler) Update(c *gin.Context) {
+	userID, billID, ok := parseBillIDs(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateBillRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	bill, err := h.Service.Get(c.Request.Context(), userID, billID)
+	if err != nil {
+		respondBillError(c, err, "Failed to retrieve bill")
+		return
+	}
+	if req.Payee != nil {
+		bill.Payee = *req.Payee
+	}
+	if req.Amount != nil {
+		bill.Amount = *req.Amount
+	}
+	if req.DueDay != nil {
+		bill.DueDay = *req.DueDay
+	}
+	if req.Autopay != nil {
+		bill.Autopay = *req.Autopay
+	}
+	if req.CategoryID != nil {
+		bill.CategoryID = req.CategoryID
+	}
+	if req.Notes != nil {
+		bill.Notes = *req.Notes
+	}
+	if req.RemindDaysBefore != nil {
+		bill.RemindDaysBefore = *req.RemindDaysBefore
+	}
+	if req.Active != nil {
+		bill.Active = *req.Active
+	}
+
+	if err := h.Service.Update(c.Request.Context(), userID, bill); err != nil {
+		respondBillError(c, err, "Failed to update bill")
+		return
+	}
+	c.JSON(http.StatusOK, bill)
+}
+
+// Delete removes a bill
+func (h *BillHandler) Delete(c *gin.Context) {
+	userID, billID, ok := parseBillIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Service.Delete(c.Request.Context(), userID, billID); err != nil {
+		respondBillError(c, err, "Failed to delete bill")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Bill deleted successfully"})
+}
+
+// Calendar returns the bills and recurring transactions due between from,
+// today by default, and to, 30 days after from by default
+func (h *BillHandler) Calendar(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	from, err := parseOptionalDate(c.Query("from"))
+	if err != nil {
+		respondError(c, middleware.CodeInvalidDate, "Invalid from date format. Use YYYY-MM-DD")
+		return
+	}
+	to, err := parseOptionalDate(c.Query("to"))
+	if err != nil {
+		respondError(c, middleware.CodeInvalidDate, "Invalid to date format. Use YYYY-MM-DD")
+		return
+	}
+	if from == nil {
+		now := time.Now().UTC()
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		from = &today
+	}
+	if to == nil {
+		end := from.AddDate(0, 0, defaultCalendarDays)
+		to = &end
+	}
+
+	calendar, err := h.Service.Calendar(c.Request.Context(), userID, *from, *to)
+	if respondValidationError(c, err) {
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to build the bill calendar", err)
+		return
+	}
+	c.JSON(http.StatusOK, calendar)
+}
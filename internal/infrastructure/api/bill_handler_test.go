@@ -0,0 +1,172 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockBillService is a mock implementation of BillServiceInterface
+type MockBillService struct {
+	mock.Mock
+}
+
+func (m *MockBillService) Create(ctx context.Context, userID uint, bill *domain.Bill) error {
+	return m.Called(ctx, userID, bill).Error(0)
+}
+
+func (m *MockBillService) List(ctx context.Context, userID uint) ([]domain.Bill, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.Bill), args.Error(1)
+}
+
+func (m *MockBillService) Get(ctx context.Context, userID, id uint) (*domain.Bill, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Bill), args.Error(1)
+}
+
+func (m *MockBillService) Update(ctx context.Context, userID uint, bill *domain.Bill) error {
+	return m.Called(ctx, userID, bill).Error(0)
+}
+
+func (m *MockBillService) Delete(ctx context.Context, userID, id uint) error {
+	return m.Called(ctx, userID, id).Error(0)
+}
+
+func (m *MockBillService) Calendar(ctx context.Context, userID uint, from, to time.Time) (*domain.DueCalendar, error) {
+	args := m.Called(ctx, userID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.DueCalendar), args.Error(1)
+}
+
+func setupBillRouter(service *MockBillService) *gin.Engine {
+	handler := NewBillHandler(service)
+	router := setupGin()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Next()
+	})
+	router.GET("/users/:userId/bills", handler.List)
+	router.POST("/users/:userId/bills", handler.Create)
+	router.GET("/users/:userId/bills/calendar", handler.Calendar)
+	router.GET("/users/:userId/bills/:billId", handler.Get)
+	router.PUT("/users/:userId/bills/:billId", handler.Update)
+	router.DELETE("/users/:userId/bills/:billId", handler.Delete)
+	return router
+}
+
+func TestBillHandler(t *testing.T) {
+	t.Run("should create a bill with the default reminder", func(t *testing.T) {
+		service := new(MockBillService)
+		service.On("Create", mock.Anything, uint(1), mock.MatchedBy(func(bill *domain.Bill) bool {
+			return bill.Payee == "Landlord" && bill.Amount == domain.NewMoney(1200) && bill.DueDay == 1 &&
+				bill.Autopay && bill.RemindDaysBefore == domain.DefaultBillRemindDays
+		})).Return(nil)
+
+		body := `{"payee":"Landlord","amount":1200,"due_day":1,"autopay":true}`
+		req := httptest.NewRequest(http.MethodPost, "/users/1/bills", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		setupBillRouter(service).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		service.AssertExpectations(t)
+	})
+
+	t.Run("should return validation errors", func(t *testing.T) {
+		service := new(MockBillService)
+		service.On("Create", mock.Anything, uint(1), mock.Anything).Return(&domain.ValidationError{
+			Fields: []domain.FieldError{{Field: "due_day", Message: "must be between 1 and 31"}},
+		})
+
+		body := `{"payee":"Landlord","amount":1200,"due_day":40}`
+		req := httptest.NewRequest(http.MethodPost, "/users/1/bills", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		setupBillRouter(service).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+
+	t.Run("should forbid other users' bills", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		setupBillRouter(new(MockBillService)).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/2/bills", http.NoBody))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("should update the given fields only", func(t *testing.T) {
+		service := new(MockBillService)
+		bill := &domain.Bill{ID: 3, UserID: 1, Payee: "Phone", Amount: domain.NewMoney(40), DueDay: 20, RemindDaysBefore: 3, Active: true}
+		service.On("Get", mock.Anything, uint(1), uint(3)).Return(bill, nil)
+		service.On("Update", mock.Anything, uint(1), mock.MatchedBy(func(bill *domain.Bill) bool {
+			return bill.Payee == "Phone" && bill.Amount == domain.NewMoney(45) && !bill.Active
+		})).Return(nil)
+
+		req := httptest.NewRequest(http.MethodPut, "/users/1/bills/3", strings.NewReader(`{"amount":45,"active":false}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		setupBillRouter(service).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		service.AssertExpectations(t)
+	})
+
+	t.Run("should return not found for missing bills", func(t *testing.T) {
+		service := new(MockBillService)
+		service.On("Delete", mock.Anything, uint(1), uint(9)).Return(domain.ErrNotFound)
+
+		w := httptest.NewRecorder()
+		setupBillRouter(service).ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/users/1/bills/9", http.NoBody))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestBillHandler_Calendar(t *testing.T) {
+	t.Run("should look 30 days ahead from the given date", func(t *testing.T) {
+		service := new(MockBillService)
+		from := time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)
+		billID := uint(1)
+		service.On("Calendar", mock.Anything, uint(1), from, from.AddDate(0, 0, 30)).Return(&domain.DueCalendar{
+			From: from, To: from.AddDate(0, 0, 30), TotalDue: domain.NewMoney(1200),
+			Entries: []domain.CalendarEntry{{
+				Date: from, Kind: domain.CalendarEntryBill, Title: "Landlord", Type: domain.TransactionTypeExpense,
+				Amount: domain.NewMoney(1200), BillID: &billID,
+			}},
+		}, nil)
+
+		w := httptest.NewRecorder()
+		setupBillRouter(service).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1/bills/calendar?from=2025-06-01", http.NoBody))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var calendar domain.DueCalendar
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &calendar))
+		require.Len(t, calendar.Entries, 1)
+		assert.Equal(t, "Landlord", calendar.Entries[0].Title)
+		service.AssertExpectations(t)
+	})
+
+	t.Run("should reject invalid dates", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		setupBillRouter(new(MockBillService)).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1/bills/calendar?to=soon", http.NoBody))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BillingServiceInterface defines the interface for premium subscriptions
+type BillingServiceInterface interface {
+	Checkout(ctx context.Context, userID uint) (*domain.BillingSession, error)
+	Portal(ctx context.Context, userID uint) (*domain.BillingSession, error)
+	Invoices(ctx context.Context, userID uint) ([]domain.Invoice, error)
+	HandleWebhook(ctx context.Context, payload []byte, signature string) error
+}
+
+type BillingHandler struct {
+	Service BillingServiceInterface
+}
+
+func NewBillingHandler(service BillingServiceInterface) *BillingHandler {
+	return &BillingHandler{Service: service}
+}
+
+// respondBillingError answers errors of calls that reach the billing provider
+func respondBillingError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "User not found")
+	case errors.Is(err, domain.ErrNoBillingAccount):
+		respondError(c, middleware.CodeNotFound, err.Error())
+	case errors.Is(err, domain.ErrAlreadySubscribed):
+		respondError(c, middleware.CodeConflict, err.Error())
+	case errors.Is(err, domain.ErrBillingUnavailable):
+		respondError(c, middleware.CodeUnavailable, "Billing is not available")
+	default:
+		middleware.RespondError(c, &middleware.APIError{Code: middleware.CodeUnavailable, Message: message, Cause: err})
+	}
+}
+
+// Checkout starts a checkout of the premium subscription and returns the
+// URL of the checkout page the client sends the user to
+func (h *BillingHandler) Checkout(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	session, err := h.Service.Checkout(c.Request.Context(), userID)
+	if err != nil {
+		respondBillingError(c, "Failed to start the checkout", err)
+		return
+	}
+	c.JSON(http.StatusOK, session)
+}
+
+// Portal returns the URL of the customer portal, where users change their
+// payment method or cancel their subscription
+func (h *BillingHandler) Portal(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	session, err := h.Service.Portal(c.Request.Context(), userID)
+	if err != nil {
+		respondBillingError(c, "Failed to open the customer portal", err)
+		return
+	}
+	c.JSON(http.StatusOK, session)
+}
+
+// Invoices lists the user's latest invoices, newest first
+func (h *BillingHandler) Invoices(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	invoices, err := h.Service.Invoices(c.Request.Context(), userID)
+	if err != nil {
+		respondBillingError(c, "Failed to retrieve invoices", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"invoices": invoices, "count": len(invoices)})
+}
+
+// Webhook receives the billing provider's events. The signature covers the
+// exact body, so the route must not run middleware that rewrites it.
+// Failures other than bad signatures answer 500, which makes the provider
+// deliver the event again later.
+func (h *BillingHandler) Webhook(c *gin.Context) {
+	payload, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidBody, "Failed to read the request body")
+		return
+	}
+
+	err = h.Service.HandleWebhook(c.Request.Context(), payload, c.GetHeader("Stripe-Signature"))
+	switch {
+	case errors.Is(err, domain.ErrInvalidWebhookSignature):
+		respondError(c, middleware.CodeBadRequest, "Invalid webhook signature")
+	case errors.Is(err, domain.ErrBillingUnavailable):
+		respondError(c, middleware.CodeUnavailable, "Billing is not available")
+	case err != nil:
+		respondInternalError(c, "Failed to process the webhook", err)
+	default:
+		c.JSON(http.StatusOK, gin.H{"received": true})
+	}
+}
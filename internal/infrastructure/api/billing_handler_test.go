@@ -0,0 +1,147 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockBillingService is a mock implementation of BillingServiceInterface
+type MockBillingService struct {
+	mock.Mock
+}
+
+func (m *MockBillingService) Checkout(ctx context.Context, userID uint) (*domain.BillingSession, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.BillingSession), args.Error(1)
+}
+
+func (m *MockBillingService) Portal(ctx context.Context, userID uint) (*domain.BillingSession, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.BillingSession), args.Error(1)
+}
+
+func (m *MockBillingService) Invoices(ctx context.Context, userID uint) ([]domain.Invoice, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Invoice), args.Error(1)
+}
+
+func (m *MockBillingService) HandleWebhook(ctx context.Context, payload []byte, signature string) error {
+	return m.Called(ctx, string(payload), signature).Error(0)
+}
+
+func setupBillingRouter(service *MockBillingService) *gin.Engine {
+	handler := NewBillingHandler(service)
+	router := setupGin()
+	router.POST("/billing/webhook", handler.Webhook)
+	user := router.Group("/", func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Next()
+	})
+	user.POST("/users/:userId/billing/checkout", handler.Checkout)
+	user.POST("/users/:userId/billing/portal", handler.Portal)
+	user.GET("/users/:userId/billing/invoices", handler.Invoices)
+	return router
+}
+
+func TestBillingHandler_Checkout(t *testing.T) {
+	service := new(MockBillingService)
+	service.On("Checkout", mock.Anything, uint(1)).Return(&domain.BillingSession{ID: "cs_1", URL: "https://checkout.example/cs_1"}, nil).Once()
+	service.On("Checkout", mock.Anything, uint(1)).Return(nil, domain.ErrAlreadySubscribed).Once()
+	service.On("Checkout", mock.Anything, uint(1)).Return(nil, domain.ErrBillingUnavailable).Once()
+	service.On("Checkout", mock.Anything, uint(1)).Return(nil, errors.New("stripe is down")).Once()
+	router := setupBillingRouter(service)
+
+	for _, wantStatus := range []int{http.StatusOK, http.StatusConflict, http.StatusServiceUnavailable, http.StatusServiceUnavailable} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/1/billing/checkout", http.NoBody))
+		assert.Equal(t, wantStatus, w.Code)
+		if wantStatus == http.StatusOK {
+			var session domain.BillingSession
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &session))
+			assert.Equal(t, "https://checkout.example/cs_1", session.URL)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/2/billing/checkout", http.NoBody))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	service.AssertExpectations(t)
+}
+
+func TestBillingHandler_PortalAndInvoices(t *testing.T) {
+	service := new(MockBillingService)
+	service.On("Portal", mock.Anything, uint(1)).Return(nil, domain.ErrNoBillingAccount).Once()
+	service.On("Portal", mock.Anything, uint(1)).Return(&domain.BillingSession{URL: "https://portal.example"}, nil).Once()
+	service.On("Invoices", mock.Anything, uint(1)).Return([]domain.Invoice{{ID: "in_1", Status: "paid"}}, nil)
+	router := setupBillingRouter(service)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/1/billing/portal", http.NoBody))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/1/billing/portal", http.NoBody))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "https://portal.example")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1/billing/invoices", http.NoBody))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Invoices []domain.Invoice `json:"invoices"`
+		Count    int              `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 1, response.Count)
+	assert.Equal(t, "in_1", response.Invoices[0].ID)
+	service.AssertExpectations(t)
+}
+
+func TestBillingHandler_Webhook(t *testing.T) {
+	payload := `{"id":"evt_1","type":"checkout.session.completed"}`
+	service := new(MockBillingService)
+	service.On("HandleWebhook", mock.Anything, payload, "t=1,v1=good").Return(nil)
+	service.On("HandleWebhook", mock.Anything, payload, "t=1,v1=bad").Return(domain.ErrInvalidWebhookSignature)
+	service.On("HandleWebhook", mock.Anything, payload, "t=1,v1=later").Return(errors.New("database is locked"))
+	router := setupBillingRouter(service)
+
+	tests := []struct {
+		name       string
+		signature  string
+		wantStatus int
+	}{
+		{"applies signed events", "t=1,v1=good", http.StatusOK},
+		{"rejects bad signatures", "t=1,v1=bad", http.StatusBadRequest},
+		{"asks for redelivery when it fails", "t=1,v1=later", http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/billing/webhook", strings.NewReader(payload))
+			req.Header.Set("Stripe-Signature", tt.signature)
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+	service.AssertExpectations(t)
+}
@@ -1,76 +1,65 @@
 package api
 
 import (
+	"context"
 	"net/http"
 	"strconv"
 	"time"
 
 	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
 
 	"github.com/gin-gonic/gin"
 )
 
 type BudgetServiceInterface interface {
-	CreateBudget(budget *domain.Budget) error
-	GetBudgetsByUser(userID uint) ([]domain.Budget, error)
-	GetBudgetByID(budgetID uint) (*domain.Budget, error)
-	UpdateBudget(budgetID uint, updates *domain.Budget) error
-	DeleteBudget(budgetID uint) error
-	GetBudgetSummary(userID uint) (*domain.BudgetSummary, error)
+	CreateBudget(ctx context.Context, budget *domain.Budget) error
+	GetBudgetsByUser(ctx context.Context, userID uint) ([]domain.Budget, error)
+	GetBudgetByID(ctx context.Context, budgetID uint) (*domain.Budget, error)
+	UpdateBudget(ctx context.Context, budgetID uint, updates *domain.Budget) error
+	DeleteBudget(ctx context.Context, budgetID uint) error
+	GetBudgetSummary(ctx context.Context, userID uint) (*domain.BudgetSummary, error)
+	RefreshBudgetSpending(ctx context.Context, userID uint) error
+	CreateBudgetsFromTemplate(ctx context.Context, userID uint, req domain.BudgetTemplateRequest) (*domain.BudgetTemplateResult, error)
+	SimulateBudget(ctx context.Context, userID uint, req domain.BudgetSimulationRequest) (*domain.BudgetSimulation, error)
+	ExportBudgetPlan(ctx context.Context, userID uint, name string) (*domain.BudgetPlan, error)
+	PreviewBudgetPlan(ctx context.Context, userID uint, req domain.BudgetPlanImport) (*domain.BudgetPlanPreview, error)
+	ImportBudgetPlan(ctx context.Context, userID uint, req domain.BudgetPlanImport) (*domain.BudgetPlanPreview, error)
 }
 
 type BudgetHandler struct {
 	Service BudgetServiceInterface
 }
 
+// CreateBudgetRequest is the body of budget requests. The business rules are
+// checked by the service, which rejects broken ones with 422.
 type CreateBudgetRequest struct {
-	CategoryID uint    `json:"category_id" binding:"required"`
-	Amount     float64 `json:"amount" binding:"required,gt=0"`
-	Period     string  `json:"period" binding:"required,oneof=weekly monthly quarterly yearly"`
-	StartDate  string  `json:"start_date" binding:"required"`
-	EndDate    string  `json:"end_date"`
+	CategoryID uint         `json:"category_id"`
+	Amount     domain.Money `json:"amount"`
+	Period     string       `json:"period"`
+	Anchor     string       `json:"anchor"`
+	AnchorDay  int          `json:"anchor_day"`
+	StartDate  string       `json:"start_date" binding:"required"`
+	EndDate    string       `json:"end_date"`
 }
 
-type UpdateBudgetRequest struct {
-	Amount    *float64 `json:"amount,omitempty"`
-	Period    *string  `json:"period,omitempty"`
-	StartDate *string  `json:"start_date,omitempty"`
-	EndDate   *string  `json:"end_date,omitempty"`
-	IsActive  *bool    `json:"is_active,omitempty"`
-}
-
-// CreateBudget creates a new budget for a user
-func (h *BudgetHandler) CreateBudget(c *gin.Context) {
-	userIDStr := c.Param("userId")
-	userID, err := strconv.ParseUint(userIDStr, 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
-		return
-	}
-
-	var req CreateBudgetRequest
-	if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": bindErr.Error()})
-		return
-	}
-
-	// Parse start date
+// budget builds the requested budget for the user, or returns the message to
+// reject the request with. Without an end date the budget runs for one
+// period from the start date; anchored budgets get the dates of the period
+// the start date falls in from the service.
+func (req CreateBudgetRequest) budget(userID uint) (*domain.Budget, string) {
 	startDate, err := time.Parse("2006-01-02", req.StartDate)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start date format. Use YYYY-MM-DD"})
-		return
+		return nil, "Invalid start date format. Use YYYY-MM-DD"
 	}
 
-	// Parse end date or calculate based on period
 	var endDate time.Time
 	if req.EndDate != "" {
 		endDate, err = time.Parse("2006-01-02", req.EndDate)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end date format. Use YYYY-MM-DD"})
-			return
+			return nil, "Invalid end date format. Use YYYY-MM-DD"
 		}
 	} else {
-		// Calculate end date based on period
 		switch req.Period {
 		case "weekly":
 			endDate = startDate.AddDate(0, 0, 7)
@@ -83,20 +72,81 @@ func (h *BudgetHandler) CreateBudget(c *gin.Context) {
 		}
 	}
 
-	budget := &domain.Budget{
-		UserID:     uint(userID),
+	return &domain.Budget{
+		UserID:     userID,
 		CategoryID: req.CategoryID,
 		Amount:     req.Amount,
 		Period:     req.Period,
+		Anchor:     req.Anchor,
+		AnchorDay:  req.AnchorDay,
 		StartDate:  startDate,
 		EndDate:    endDate,
 		Spent:      0,
 		IsActive:   true,
+	}, ""
+}
+
+// BudgetTemplateRequest is the body of quick-start budgets. Without
+// monthly_income the user's average income is split; without start_date the
+// budgets cover the current month.
+type BudgetTemplateRequest struct {
+	Template      string        `json:"template" binding:"required"`
+	MonthlyIncome *domain.Money `json:"monthly_income,omitempty"`
+	StartDate     string        `json:"start_date"`
+}
+
+// BudgetSimulationRequest is the body of what-if budgets. Without start_date
+// the projection starts with the current month.
+type BudgetSimulationRequest struct {
+	domain.BudgetSimulationRequest
+	StartDate string `json:"start_date"`
+}
+
+// BudgetPlanImportRequest is the body of budget plan imports and their
+// previews. category_map maps the plan's category IDs to the user's; without
+// start_date the budgets start with the current month.
+type BudgetPlanImportRequest struct {
+	Plan        *domain.BudgetPlan `json:"plan" binding:"required"`
+	CategoryMap map[uint]uint      `json:"category_map,omitempty"`
+	StartDate   string             `json:"start_date"`
+}
+
+type UpdateBudgetRequest struct {
+	Amount    *domain.Money `json:"amount,omitempty"`
+	Period    *string       `json:"period,omitempty"`
+	Anchor    *string       `json:"anchor,omitempty"`
+	AnchorDay *int          `json:"anchor_day,omitempty"`
+	StartDate *string       `json:"start_date,omitempty"`
+	EndDate   *string       `json:"end_date,omitempty"`
+	IsActive  *bool         `json:"is_active,omitempty"`
+}
+
+// CreateBudget creates a new budget for a user
+func (h *BudgetHandler) CreateBudget(c *gin.Context) {
+	userIDStr := c.Param("userId")
+	userID, err := strconv.ParseUint(userIDStr, 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid user ID")
+		return
+	}
+
+	var req CreateBudgetRequest
+	if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
+		respondError(c, middleware.CodeInvalidBody, bindErr.Error())
+		return
+	}
+
+	budget, invalid := req.budget(uint(userID))
+	if invalid != "" {
+		respondError(c, middleware.CodeInvalidDate, invalid)
+		return
 	}
 
-	err = h.Service.CreateBudget(budget)
+	err = h.Service.CreateBudget(c.Request.Context(), budget)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create budget"})
+		if !respondValidationError(c, err) {
+			respondInternalError(c, "Failed to create budget", err)
+		}
 		return
 	}
 
@@ -108,13 +158,13 @@ func (h *BudgetHandler) GetBudgets(c *gin.Context) {
 	userIDStr := c.Param("userId")
 	userID, err := strconv.ParseUint(userIDStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		respondError(c, middleware.CodeInvalidID, "Invalid user ID")
 		return
 	}
 
-	budgets, err := h.Service.GetBudgetsByUser(uint(userID))
+	budgets, err := h.Service.GetBudgetsByUser(c.Request.Context(), uint(userID))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve budgets"})
+		respondInternalError(c, "Failed to retrieve budgets", err)
 		return
 	}
 
@@ -126,26 +176,26 @@ func (h *BudgetHandler) GetBudget(c *gin.Context) {
 	userIDStr := c.Param("userId")
 	userID, err := strconv.ParseUint(userIDStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		respondError(c, middleware.CodeInvalidID, "Invalid user ID")
 		return
 	}
 
 	budgetIDStr := c.Param("budgetId")
 	budgetID, err := strconv.ParseUint(budgetIDStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid budget ID"})
+		respondError(c, middleware.CodeInvalidID, "Invalid budget ID")
 		return
 	}
 
-	budget, err := h.Service.GetBudgetByID(uint(budgetID))
+	budget, err := h.Service.GetBudgetByID(c.Request.Context(), uint(budgetID))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Budget not found"})
+		respondError(c, middleware.CodeNotFound, "Budget not found")
 		return
 	}
 
 	// Verify budget belongs to user
 	if budget.UserID != uint(userID) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		respondError(c, middleware.CodeForbidden, "Access denied")
 		return
 	}
 
@@ -157,33 +207,33 @@ func (h *BudgetHandler) UpdateBudget(c *gin.Context) {
 	userIDStr := c.Param("userId")
 	userID, err := strconv.ParseUint(userIDStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		respondError(c, middleware.CodeInvalidID, "Invalid user ID")
 		return
 	}
 
 	budgetIDStr := c.Param("budgetId")
 	budgetID, err := strconv.ParseUint(budgetIDStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid budget ID"})
+		respondError(c, middleware.CodeInvalidID, "Invalid budget ID")
 		return
 	}
 
 	var req UpdateBudgetRequest
 	if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": bindErr.Error()})
+		respondError(c, middleware.CodeInvalidBody, bindErr.Error())
 		return
 	}
 
 	// Get existing budget
-	budget, err := h.Service.GetBudgetByID(uint(budgetID))
+	budget, err := h.Service.GetBudgetByID(c.Request.Context(), uint(budgetID))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Budget not found"})
+		respondError(c, middleware.CodeNotFound, "Budget not found")
 		return
 	}
 
 	// Verify budget belongs to user
 	if budget.UserID != uint(userID) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		respondError(c, middleware.CodeForbidden, "Access denied")
 		return
 	}
 
@@ -194,10 +244,16 @@ func (h *BudgetHandler) UpdateBudget(c *gin.Context) {
 	if req.Period != nil {
 		budget.Period = *req.Period
 	}
+	if req.Anchor != nil {
+		budget.Anchor = *req.Anchor
+	}
+	if req.AnchorDay != nil {
+		budget.AnchorDay = *req.AnchorDay
+	}
 	if req.StartDate != nil {
 		startDate, parseErr := time.Parse("2006-01-02", *req.StartDate)
 		if parseErr != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start date format. Use YYYY-MM-DD"})
+			respondError(c, middleware.CodeInvalidDate, "Invalid start date format. Use YYYY-MM-DD")
 			return
 		}
 		budget.StartDate = startDate
@@ -205,7 +261,7 @@ func (h *BudgetHandler) UpdateBudget(c *gin.Context) {
 	if req.EndDate != nil {
 		endDate, parseErr := time.Parse("2006-01-02", *req.EndDate)
 		if parseErr != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end date format. Use YYYY-MM-DD"})
+			respondError(c, middleware.CodeInvalidDate, "Invalid end date format. Use YYYY-MM-DD")
 			return
 		}
 		budget.EndDate = endDate
@@ -214,16 +270,18 @@ func (h *BudgetHandler) UpdateBudget(c *gin.Context) {
 		budget.IsActive = *req.IsActive
 	}
 
-	err = h.Service.UpdateBudget(uint(budgetID), budget)
+	err = h.Service.UpdateBudget(c.Request.Context(), uint(budgetID), budget)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update budget"})
+		if !respondValidationError(c, err) {
+			respondInternalError(c, "Failed to update budget", err)
+		}
 		return
 	}
 
 	// Get the updated budget to return
-	updatedBudget, err := h.Service.GetBudgetByID(uint(budgetID))
+	updatedBudget, err := h.Service.GetBudgetByID(c.Request.Context(), uint(budgetID))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get updated budget"})
+		respondInternalError(c, "Failed to get updated budget", err)
 		return
 	}
 
@@ -235,33 +293,33 @@ func (h *BudgetHandler) DeleteBudget(c *gin.Context) {
 	userIDStr := c.Param("userId")
 	userID, err := strconv.ParseUint(userIDStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		respondError(c, middleware.CodeInvalidID, "Invalid user ID")
 		return
 	}
 
 	budgetIDStr := c.Param("budgetId")
 	budgetID, err := strconv.ParseUint(budgetIDStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid budget ID"})
+		respondError(c, middleware.CodeInvalidID, "Invalid budget ID")
 		return
 	}
 
 	// Get existing budget to verify ownership
-	budget, err := h.Service.GetBudgetByID(uint(budgetID))
+	budget, err := h.Service.GetBudgetByID(c.Request.Context(), uint(budgetID))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Budget not found"})
+		respondError(c, middleware.CodeNotFound, "Budget not found")
 		return
 	}
 
 	// Verify budget belongs to user
 	if budget.UserID != uint(userID) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		respondError(c, middleware.CodeForbidden, "Access denied")
 		return
 	}
 
-	err = h.Service.DeleteBudget(uint(budgetID))
+	err = h.Service.DeleteBudget(c.Request.Context(), uint(budgetID))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete budget"})
+		respondInternalError(c, "Failed to delete budget", err)
 		return
 	}
 
@@ -273,15 +331,184 @@ func (h *BudgetHandler) GetBudgetSummary(c *gin.Context) {
 	userIDStr := c.Param("userId")
 	userID, err := strconv.ParseUint(userIDStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		respondError(c, middleware.CodeInvalidID, "Invalid user ID")
 		return
 	}
 
-	summary, err := h.Service.GetBudgetSummary(uint(userID))
+	summary, err := h.Service.GetBudgetSummary(c.Request.Context(), uint(userID))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate budget summary"})
+		respondInternalError(c, "Failed to generate budget summary", err)
 		return
 	}
 
 	c.JSON(http.StatusOK, summary)
 }
+
+// RecalculateBudgets recomputes the spent amounts of the user's active budgets
+// from their transactions and returns the user's budgets
+func (h *BudgetHandler) RecalculateBudgets(c *gin.Context) {
+	userIDStr := c.Param("userId")
+	userID, err := strconv.ParseUint(userIDStr, 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid user ID")
+		return
+	}
+
+	if err := h.Service.RefreshBudgetSpending(c.Request.Context(), uint(userID)); err != nil {
+		respondInternalError(c, "Failed to recalculate budgets", err)
+		return
+	}
+
+	budgets, err := h.Service.GetBudgetsByUser(c.Request.Context(), uint(userID))
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve budgets", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, budgets)
+}
+
+// ListTemplates returns the budget templates quick-start budgets are made from
+func (h *BudgetHandler) ListTemplates(c *gin.Context) {
+	templates := domain.BudgetTemplates()
+	response := make([]gin.H, len(templates))
+	for i, template := range templates {
+		response[i] = gin.H{
+			"key":             template.Key,
+			"name":            template.Name,
+			"description":     template.Description,
+			"allocations":     template.Allocations,
+			"savings_percent": template.SavingsPercent(),
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"templates": response})
+}
+
+// CreateFromTemplate creates a full set of monthly category budgets from a
+// template and the user's income
+func (h *BudgetHandler) CreateFromTemplate(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req BudgetTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+	templateReq := domain.BudgetTemplateRequest{Template: req.Template, MonthlyIncome: req.MonthlyIncome}
+	if req.StartDate != "" {
+		startDate, err := time.Parse("2006-01-02", req.StartDate)
+		if err != nil {
+			respondError(c, middleware.CodeInvalidDate, "Invalid start date format. Use YYYY-MM-DD")
+			return
+		}
+		templateReq.StartDate = startDate
+	}
+
+	result, err := h.Service.CreateBudgetsFromTemplate(c.Request.Context(), userID, templateReq)
+	if respondValidationError(c, err) {
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to create budgets from template", err)
+		return
+	}
+	c.JSON(http.StatusCreated, result)
+}
+
+// Simulate projects savings, cash flow and goal completion dates with
+// hypothetical budget changes, without changing any budgets
+func (h *BudgetHandler) Simulate(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req BudgetSimulationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+	simulationReq := req.BudgetSimulationRequest
+	if req.StartDate != "" {
+		startDate, err := time.Parse("2006-01-02", req.StartDate)
+		if err != nil {
+			respondError(c, middleware.CodeInvalidDate, "Invalid start date format. Use YYYY-MM-DD")
+			return
+		}
+		simulationReq.StartDate = startDate
+	}
+
+	simulation, err := h.Service.SimulateBudget(c.Request.Context(), userID, simulationReq)
+	if respondValidationError(c, err) {
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to simulate budget", err)
+		return
+	}
+	c.JSON(http.StatusOK, simulation)
+}
+
+// ExportPlan downloads the user's active budgets as a plan others can import
+func (h *BudgetHandler) ExportPlan(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	plan, err := h.Service.ExportBudgetPlan(c.Request.Context(), userID, c.Query("name"))
+	if err != nil {
+		respondInternalError(c, "Failed to export budgets", err)
+		return
+	}
+	c.Header("Content-Disposition", "attachment; filename=budget_plan.json")
+	c.JSON(http.StatusOK, plan)
+}
+
+// PreviewPlan lists what importing a budget plan would change, without
+// changing anything
+func (h *BudgetHandler) PreviewPlan(c *gin.Context) {
+	h.importPlan(c, h.Service.PreviewBudgetPlan)
+}
+
+// ImportPlan applies a budget plan to the user's budgets
+func (h *BudgetHandler) ImportPlan(c *gin.Context) {
+	h.importPlan(c, h.Service.ImportBudgetPlan)
+}
+
+func (h *BudgetHandler) importPlan(
+	c *gin.Context, run func(context.Context, uint, domain.BudgetPlanImport) (*domain.BudgetPlanPreview, error),
+) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req BudgetPlanImportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+	planImport := domain.BudgetPlanImport{Plan: *req.Plan, CategoryMap: req.CategoryMap}
+	if req.StartDate != "" {
+		startDate, err := time.Parse("2006-01-02", req.StartDate)
+		if err != nil {
+			respondError(c, middleware.CodeInvalidDate, "Invalid start date format. Use YYYY-MM-DD")
+			return
+		}
+		planImport.StartDate = startDate
+	}
+
+	preview, err := run(c.Request.Context(), userID, planImport)
+	if respondValidationError(c, err) {
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to import budget plan", err)
+		return
+	}
+	c.JSON(http.StatusOK, preview)
+}
@@ -2,17 +2,22 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
 
+	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockBudgetService is a mock implementation of BudgetService
@@ -20,36 +25,89 @@ type MockBudgetService struct {
 	mock.Mock
 }
 
-func (m *MockBudgetService) CreateBudget(budget *domain.Budget) error {
-	args := m.Called(budget)
+func (m *MockBudgetService) CreateBudget(ctx context.Context, budget *domain.Budget) error {
+	args := m.Called(ctx, budget)
 	return args.Error(0)
 }
 
-func (m *MockBudgetService) GetBudgetsByUser(userID uint) ([]domain.Budget, error) {
-	args := m.Called(userID)
+func (m *MockBudgetService) GetBudgetsByUser(ctx context.Context, userID uint) ([]domain.Budget, error) {
+	args := m.Called(ctx, userID)
 	return args.Get(0).([]domain.Budget), args.Error(1)
 }
 
-func (m *MockBudgetService) GetBudgetByID(budgetID uint) (*domain.Budget, error) {
-	args := m.Called(budgetID)
+func (m *MockBudgetService) GetBudgetByID(ctx context.Context, budgetID uint) (*domain.Budget, error) {
+	args := m.Called(ctx, budgetID)
 	return args.Get(0).(*domain.Budget), args.Error(1)
 }
 
-func (m *MockBudgetService) UpdateBudget(budgetID uint, budget *domain.Budget) error {
-	args := m.Called(budgetID, budget)
+func (m *MockBudgetService) UpdateBudget(ctx context.Context, budgetID uint, budget *domain.Budget) error {
+	args := m.Called(ctx, budgetID, budget)
 	return args.Error(0)
 }
 
-func (m *MockBudgetService) DeleteBudget(budgetID uint) error {
-	args := m.Called(budgetID)
+func (m *MockBudgetService) DeleteBudget(ctx context.Context, budgetID uint) error {
+	args := m.Called(ctx, budgetID)
 	return args.Error(0)
 }
 
-func (m *MockBudgetService) GetBudgetSummary(userID uint) (*domain.BudgetSummary, error) {
-	args := m.Called(userID)
+func (m *MockBudgetService) GetBudgetSummary(ctx context.Context, userID uint) (*domain.BudgetSummary, error) {
+	args := m.Called(ctx, userID)
 	return args.Get(0).(*domain.BudgetSummary), args.Error(1)
 }
 
+func (m *MockBudgetService) RefreshBudgetSpending(ctx context.Context, userID uint) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockBudgetService) CreateBudgetsFromTemplate(
+	ctx context.Context, userID uint, req domain.BudgetTemplateRequest,
+) (*domain.BudgetTemplateResult, error) {
+	args := m.Called(ctx, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.BudgetTemplateResult), args.Error(1)
+}
+
+func (m *MockBudgetService) SimulateBudget(
+	ctx context.Context, userID uint, req domain.BudgetSimulationRequest,
+) (*domain.BudgetSimulation, error) {
+	args := m.Called(ctx, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.BudgetSimulation), args.Error(1)
+}
+
+func (m *MockBudgetService) ExportBudgetPlan(ctx context.Context, userID uint, name string) (*domain.BudgetPlan, error) {
+	args := m.Called(ctx, userID, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.BudgetPlan), args.Error(1)
+}
+
+func (m *MockBudgetService) PreviewBudgetPlan(
+	ctx context.Context, userID uint, req domain.BudgetPlanImport,
+) (*domain.BudgetPlanPreview, error) {
+	args := m.Called(ctx, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.BudgetPlanPreview), args.Error(1)
+}
+
+func (m *MockBudgetService) ImportBudgetPlan(
+	ctx context.Context, userID uint, req domain.BudgetPlanImport,
+) (*domain.BudgetPlanPreview, error) {
+	args := m.Called(ctx, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.BudgetPlanPreview), args.Error(1)
+}
+
 func setupBudgetHandler() (*BudgetHandler, *MockBudgetService) {
 	mockService := &MockBudgetService{}
 	handler := &BudgetHandler{
@@ -66,12 +124,12 @@ func TestBudgetHandler_CreateBudget(t *testing.T) {
 
 		reqBody := CreateBudgetRequest{
 			CategoryID: 1,
-			Amount:     500.00,
+			Amount:     domain.NewMoney(500.00),
 			Period:     "monthly",
 			StartDate:  "2024-01-01",
 		}
 
-		mockService.On("CreateBudget", mock.AnythingOfType("*domain.Budget")).Return(nil)
+		mockService.On("CreateBudget", mock.Anything, mock.AnythingOfType("*domain.Budget")).Return(nil)
 
 		body, _ := json.Marshal(reqBody)
 		req := httptest.NewRequest("POST", "/users/1/budgets", bytes.NewBuffer(body))
@@ -85,7 +143,7 @@ func TestBudgetHandler_CreateBudget(t *testing.T) {
 		json.Unmarshal(w.Body.Bytes(), &response)
 		assert.Equal(t, uint(1), response.UserID)
 		assert.Equal(t, uint(1), response.CategoryID)
-		assert.Equal(t, 500.00, response.Amount)
+		assert.Equal(t, domain.NewMoney(500.00), response.Amount)
 		assert.Equal(t, "monthly", response.Period)
 		assert.True(t, response.IsActive)
 		mockService.AssertExpectations(t)
@@ -98,12 +156,12 @@ func TestBudgetHandler_CreateBudget(t *testing.T) {
 
 		reqBody := CreateBudgetRequest{
 			CategoryID: 1,
-			Amount:     1000.00,
+			Amount:     domain.NewMoney(1000.00),
 			Period:     "weekly",
 			StartDate:  "2024-01-01",
 		}
 
-		mockService.On("CreateBudget", mock.AnythingOfType("*domain.Budget")).Return(nil)
+		mockService.On("CreateBudget", mock.Anything, mock.AnythingOfType("*domain.Budget")).Return(nil)
 
 		body, _ := json.Marshal(reqBody)
 		req := httptest.NewRequest("POST", "/users/1/budgets", bytes.NewBuffer(body))
@@ -127,7 +185,7 @@ func TestBudgetHandler_CreateBudget(t *testing.T) {
 
 		reqBody := CreateBudgetRequest{
 			CategoryID: 1,
-			Amount:     500.00,
+			Amount:     domain.NewMoney(500.00),
 			Period:     "monthly",
 			StartDate:  "2024-01-01",
 		}
@@ -142,7 +200,8 @@ func TestBudgetHandler_CreateBudget(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 		var response map[string]interface{}
 		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Equal(t, "invalid user ID", response["error"])
+		assert.Equal(t, "Invalid user ID", response["error"])
+		assert.Equal(t, string(middleware.CodeInvalidID), response["code"])
 	})
 
 	t.Run("should return bad request for invalid JSON", func(t *testing.T) {
@@ -166,7 +225,7 @@ func TestBudgetHandler_CreateBudget(t *testing.T) {
 
 		reqBody := CreateBudgetRequest{
 			CategoryID: 1,
-			Amount:     500.00,
+			Amount:     domain.NewMoney(500.00),
 			Period:     "monthly",
 			StartDate:  "invalid-date",
 		}
@@ -191,12 +250,12 @@ func TestBudgetHandler_CreateBudget(t *testing.T) {
 
 		reqBody := CreateBudgetRequest{
 			CategoryID: 1,
-			Amount:     500.00,
+			Amount:     domain.NewMoney(500.00),
 			Period:     "monthly",
 			StartDate:  "2024-01-01",
 		}
 
-		mockService.On("CreateBudget", mock.AnythingOfType("*domain.Budget")).Return(errors.New("database error"))
+		mockService.On("CreateBudget", mock.Anything, mock.AnythingOfType("*domain.Budget")).Return(errors.New("database error"))
 
 		body, _ := json.Marshal(reqBody)
 		req := httptest.NewRequest("POST", "/users/1/budgets", bytes.NewBuffer(body))
@@ -224,7 +283,7 @@ func TestBudgetHandler_GetBudgets(t *testing.T) {
 				ID:         1,
 				UserID:     1,
 				CategoryID: 1,
-				Amount:     500.00,
+				Amount:     domain.NewMoney(500.00),
 				Period:     "monthly",
 				IsActive:   true,
 			},
@@ -232,13 +291,13 @@ func TestBudgetHandler_GetBudgets(t *testing.T) {
 				ID:         2,
 				UserID:     1,
 				CategoryID: 2,
-				Amount:     200.00,
+				Amount:     domain.NewMoney(200.00),
 				Period:     "weekly",
 				IsActive:   true,
 			},
 		}
 
-		mockService.On("GetBudgetsByUser", uint(1)).Return(expectedBudgets, nil)
+		mockService.On("GetBudgetsByUser", mock.Anything, uint(1)).Return(expectedBudgets, nil)
 
 		req := httptest.NewRequest("GET", "/users/1/budgets", http.NoBody)
 		w := httptest.NewRecorder()
@@ -267,7 +326,7 @@ func TestBudgetHandler_GetBudgets(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 		var response map[string]interface{}
 		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Equal(t, "invalid user ID", response["error"])
+		assert.Equal(t, "Invalid user ID", response["error"])
 	})
 
 	t.Run("should return internal server error when service fails", func(t *testing.T) {
@@ -275,7 +334,7 @@ func TestBudgetHandler_GetBudgets(t *testing.T) {
 		router := setupGin()
 		router.GET("/users/:userId/budgets", handler.GetBudgets)
 
-		mockService.On("GetBudgetsByUser", uint(1)).Return([]domain.Budget{}, errors.New("database error"))
+		mockService.On("GetBudgetsByUser", mock.Anything, uint(1)).Return([]domain.Budget{}, errors.New("database error"))
 
 		req := httptest.NewRequest("GET", "/users/1/budgets", http.NoBody)
 		w := httptest.NewRecorder()
@@ -300,12 +359,12 @@ func TestBudgetHandler_GetBudget(t *testing.T) {
 			ID:         1,
 			UserID:     1,
 			CategoryID: 1,
-			Amount:     500.00,
+			Amount:     domain.NewMoney(500.00),
 			Period:     "monthly",
 			IsActive:   true,
 		}
 
-		mockService.On("GetBudgetByID", uint(1)).Return(expectedBudget, nil)
+		mockService.On("GetBudgetByID", mock.Anything, uint(1)).Return(expectedBudget, nil)
 
 		req := httptest.NewRequest("GET", "/users/1/budgets/1", http.NoBody)
 		w := httptest.NewRecorder()
@@ -329,12 +388,12 @@ func TestBudgetHandler_GetBudget(t *testing.T) {
 			ID:         1,
 			UserID:     2, // Different user
 			CategoryID: 1,
-			Amount:     500.00,
+			Amount:     domain.NewMoney(500.00),
 			Period:     "monthly",
 			IsActive:   true,
 		}
 
-		mockService.On("GetBudgetByID", uint(1)).Return(expectedBudget, nil)
+		mockService.On("GetBudgetByID", mock.Anything, uint(1)).Return(expectedBudget, nil)
 
 		req := httptest.NewRequest("GET", "/users/1/budgets/1", http.NoBody)
 		w := httptest.NewRecorder()
@@ -353,7 +412,7 @@ func TestBudgetHandler_GetBudget(t *testing.T) {
 		router := setupGin()
 		router.GET("/users/:userId/budgets/:budgetId", handler.GetBudget)
 
-		mockService.On("GetBudgetByID", uint(999)).Return((*domain.Budget)(nil), errors.New("not found"))
+		mockService.On("GetBudgetByID", mock.Anything, uint(999)).Return((*domain.Budget)(nil), errors.New("not found"))
 
 		req := httptest.NewRequest("GET", "/users/1/budgets/999", http.NoBody)
 		w := httptest.NewRecorder()
@@ -378,7 +437,7 @@ func TestBudgetHandler_UpdateBudget(t *testing.T) {
 			ID:         1,
 			UserID:     1,
 			CategoryID: 1,
-			Amount:     500.00,
+			Amount:     domain.NewMoney(500.00),
 			Period:     "monthly",
 			IsActive:   true,
 		}
@@ -387,21 +446,21 @@ func TestBudgetHandler_UpdateBudget(t *testing.T) {
 			ID:         1,
 			UserID:     1,
 			CategoryID: 1,
-			Amount:     600.00,
+			Amount:     domain.NewMoney(600.00),
 			Period:     "monthly",
 			IsActive:   false,
 		}
 
-		newAmount := 600.00
+		newAmount := domain.NewMoney(600.00)
 		newActive := false
 		reqBody := UpdateBudgetRequest{
 			Amount:   &newAmount,
 			IsActive: &newActive,
 		}
 
-		mockService.On("GetBudgetByID", uint(1)).Return(existingBudget, nil).Once()
-		mockService.On("UpdateBudget", uint(1), mock.AnythingOfType("*domain.Budget")).Return(nil)
-		mockService.On("GetBudgetByID", uint(1)).Return(updatedBudget, nil).Once()
+		mockService.On("GetBudgetByID", mock.Anything, uint(1)).Return(existingBudget, nil).Once()
+		mockService.On("UpdateBudget", mock.Anything, uint(1), mock.AnythingOfType("*domain.Budget")).Return(nil)
+		mockService.On("GetBudgetByID", mock.Anything, uint(1)).Return(updatedBudget, nil).Once()
 
 		body, _ := json.Marshal(reqBody)
 		req := httptest.NewRequest("PUT", "/users/1/budgets/1", bytes.NewBuffer(body))
@@ -413,7 +472,7 @@ func TestBudgetHandler_UpdateBudget(t *testing.T) {
 		assert.Equal(t, http.StatusOK, w.Code)
 		var response domain.Budget
 		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Equal(t, 600.00, response.Amount)
+		assert.Equal(t, domain.NewMoney(600.00), response.Amount)
 		assert.False(t, response.IsActive)
 		mockService.AssertExpectations(t)
 	})
@@ -427,17 +486,17 @@ func TestBudgetHandler_UpdateBudget(t *testing.T) {
 			ID:         1,
 			UserID:     2, // Different user
 			CategoryID: 1,
-			Amount:     500.00,
+			Amount:     domain.NewMoney(500.00),
 			Period:     "monthly",
 			IsActive:   true,
 		}
 
-		newAmount := 600.00
+		newAmount := domain.NewMoney(600.00)
 		reqBody := UpdateBudgetRequest{
 			Amount: &newAmount,
 		}
 
-		mockService.On("GetBudgetByID", uint(1)).Return(existingBudget, nil)
+		mockService.On("GetBudgetByID", mock.Anything, uint(1)).Return(existingBudget, nil)
 
 		body, _ := json.Marshal(reqBody)
 		req := httptest.NewRequest("PUT", "/users/1/budgets/1", bytes.NewBuffer(body))
@@ -464,13 +523,13 @@ func TestBudgetHandler_DeleteBudget(t *testing.T) {
 			ID:         1,
 			UserID:     1,
 			CategoryID: 1,
-			Amount:     500.00,
+			Amount:     domain.NewMoney(500.00),
 			Period:     "monthly",
 			IsActive:   true,
 		}
 
-		mockService.On("GetBudgetByID", uint(1)).Return(existingBudget, nil)
-		mockService.On("DeleteBudget", uint(1)).Return(nil)
+		mockService.On("GetBudgetByID", mock.Anything, uint(1)).Return(existingBudget, nil)
+		mockService.On("DeleteBudget", mock.Anything, uint(1)).Return(nil)
 
 		req := httptest.NewRequest("DELETE", "/users/1/budgets/1", http.NoBody)
 		w := httptest.NewRecorder()
@@ -493,12 +552,12 @@ func TestBudgetHandler_DeleteBudget(t *testing.T) {
 			ID:         1,
 			UserID:     2, // Different user
 			CategoryID: 1,
-			Amount:     500.00,
+			Amount:     domain.NewMoney(500.00),
 			Period:     "monthly",
 			IsActive:   true,
 		}
 
-		mockService.On("GetBudgetByID", uint(1)).Return(existingBudget, nil)
+		mockService.On("GetBudgetByID", mock.Anything, uint(1)).Return(existingBudget, nil)
 
 		req := httptest.NewRequest("DELETE", "/users/1/budgets/1", http.NoBody)
 		w := httptest.NewRecorder()
@@ -520,14 +579,14 @@ func TestBudgetHandler_GetBudgetSummary(t *testing.T) {
 		router.GET("/users/:userId/budgets/summary", handler.GetBudgetSummary)
 
 		expectedSummary := &domain.BudgetSummary{
-			TotalBudget:    1500.00,
-			TotalSpent:     800.00,
-			TotalRemaining: 700.00,
+			TotalBudget:    domain.NewMoney(1500.00),
+			TotalSpent:     domain.NewMoney(800.00),
+			TotalRemaining: domain.NewMoney(700.00),
 			PercentageUsed: 53.33,
 			BudgetStatus:   "on_track",
 		}
 
-		mockService.On("GetBudgetSummary", uint(1)).Return(expectedSummary, nil)
+		mockService.On("GetBudgetSummary", mock.Anything, uint(1)).Return(expectedSummary, nil)
 
 		req := httptest.NewRequest("GET", "/users/1/budgets/summary", http.NoBody)
 		w := httptest.NewRecorder()
@@ -537,9 +596,9 @@ func TestBudgetHandler_GetBudgetSummary(t *testing.T) {
 		assert.Equal(t, http.StatusOK, w.Code)
 		var response domain.BudgetSummary
 		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Equal(t, 1500.00, response.TotalBudget)
-		assert.Equal(t, 800.00, response.TotalSpent)
-		assert.Equal(t, 700.00, response.TotalRemaining)
+		assert.Equal(t, domain.NewMoney(1500.00), response.TotalBudget)
+		assert.Equal(t, domain.NewMoney(800.00), response.TotalSpent)
+		assert.Equal(t, domain.NewMoney(700.00), response.TotalRemaining)
 		mockService.AssertExpectations(t)
 	})
 
@@ -556,7 +615,7 @@ func TestBudgetHandler_GetBudgetSummary(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 		var response map[string]interface{}
 		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Equal(t, "invalid user ID", response["error"])
+		assert.Equal(t, "Invalid user ID", response["error"])
 	})
 
 	t.Run("should return internal server error when service fails", func(t *testing.T) {
@@ -564,7 +623,7 @@ func TestBudgetHandler_GetBudgetSummary(t *testing.T) {
 		router := setupGin()
 		router.GET("/users/:userId/budgets/summary", handler.GetBudgetSummary)
 
-		mockService.On("GetBudgetSummary", uint(1)).Return((*domain.BudgetSummary)(nil), errors.New("database error"))
+		mockService.On("GetBudgetSummary", mock.Anything, uint(1)).Return((*domain.BudgetSummary)(nil), errors.New("database error"))
 
 		req := httptest.NewRequest("GET", "/users/1/budgets/summary", http.NoBody)
 		w := httptest.NewRecorder()
@@ -578,3 +637,301 @@ func TestBudgetHandler_GetBudgetSummary(t *testing.T) {
 		mockService.AssertExpectations(t)
 	})
 }
+
+func TestBudgetHandler_RecalculateBudgets(t *testing.T) {
+	t.Run("should recalculate and return budgets", func(t *testing.T) {
+		handler, mockService := setupBudgetHandler()
+		router := setupGin()
+		router.POST("/users/:userId/budgets/recalculate", handler.RecalculateBudgets)
+
+		budgets := []domain.Budget{{ID: 1, UserID: 1, Amount: domain.NewMoney(500), Spent: domain.NewMoney(120), Remaining: domain.NewMoney(380)}}
+		mockService.On("RefreshBudgetSpending", mock.Anything, uint(1)).Return(nil)
+		mockService.On("GetBudgetsByUser", mock.Anything, uint(1)).Return(budgets, nil)
+
+		req := httptest.NewRequest("POST", "/users/1/budgets/recalculate", http.NoBody)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response []domain.Budget
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Len(t, response, 1)
+		assert.Equal(t, domain.NewMoney(120.0), response[0].Spent)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return internal server error when recalculation fails", func(t *testing.T) {
+		handler, mockService := setupBudgetHandler()
+		router := setupGin()
+		router.POST("/users/:userId/budgets/recalculate", handler.RecalculateBudgets)
+
+		mockService.On("RefreshBudgetSpending", mock.Anything, uint(1)).Return(errors.New("database error"))
+
+		req := httptest.NewRequest("POST", "/users/1/budgets/recalculate", http.NoBody)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestBudgetHandler_ListTemplates(t *testing.T) {
+	handler, _ := setupBudgetHandler()
+	router := setupGin()
+	router.GET("/budget-templates", handler.ListTemplates)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/budget-templates", http.NoBody))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Templates []struct {
+			Key            string `json:"key"`
+			SavingsPercent int    `json:"savings_percent"`
+		} `json:"templates"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Templates, 4)
+	assert.Equal(t, domain.BudgetTemplateFiftyThirtyTwenty, response.Templates[0].Key)
+	assert.Equal(t, 20, response.Templates[0].SavingsPercent)
+}
+
+func TestBudgetHandler_CreateFromTemplate(t *testing.T) {
+	income := domain.NewMoney(4000)
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		setup      func(*MockBudgetService)
+		wantStatus int
+	}{
+		{
+			name: "should create the budgets",
+			path: "/users/1/budgets/from-template",
+			body: `{"template":"50_30_20","monthly_income":4000,"start_date":"2024-05-01"}`,
+			setup: func(s *MockBudgetService) {
+				s.On("CreateBudgetsFromTemplate", mock.Anything, uint(1), domain.BudgetTemplateRequest{
+					Template: "50_30_20", MonthlyIncome: &income, StartDate: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+				}).Return(&domain.BudgetTemplateResult{Template: "50_30_20", MonthlyIncome: income}, nil)
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name: "should reject invalid requests",
+			path: "/users/1/budgets/from-template",
+			body: `{"template":"lavish"}`,
+			setup: func(s *MockBudgetService) {
+				s.On("CreateBudgetsFromTemplate", mock.Anything, uint(1), mock.Anything).
+					Return(nil, &domain.ValidationError{Fields: []domain.FieldError{{Field: "template", Message: "must be one of 50_30_20"}}})
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "should reject malformed start dates",
+			path:       "/users/1/budgets/from-template",
+			body:       `{"template":"student","start_date":"May"}`,
+			setup:      func(*MockBudgetService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "should deny other users",
+			path:       "/users/2/budgets/from-template",
+			body:       `{"template":"student"}`,
+			setup:      func(*MockBudgetService) {},
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupBudgetHandler()
+			tt.setup(mockService)
+			router := setupGin()
+			router.Use(func(c *gin.Context) {
+				c.Set("userID", uint(1))
+				c.Next()
+			})
+			router.POST("/users/:userId/budgets/from-template", handler.CreateFromTemplate)
+
+			req := httptest.NewRequest("POST", tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBudgetHandler_Simulate(t *testing.T) {
+	cut := -30.0
+	extra := domain.NewMoney(200)
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		setup      func(*MockBudgetService)
+		wantStatus int
+	}{
+		{
+			name: "should simulate the changes",
+			path: "/users/1/budgets/simulate",
+			body: `{"changes":[{"category_id":3,"percent":-30},{"category_id":5,"amount":200}],"start_date":"2024-05-01"}`,
+			setup: func(s *MockBudgetService) {
+				s.On("SimulateBudget", mock.Anything, uint(1), domain.BudgetSimulationRequest{
+					Changes:   []domain.BudgetChange{{CategoryID: 3, Percent: &cut}, {CategoryID: 5, Amount: &extra}},
+					StartDate: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+				}).Return(&domain.BudgetSimulation{Months: 6}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "should reject invalid changes",
+			path: "/users/1/budgets/simulate",
+			body: `{"changes":[{"category_id":3}]}`,
+			setup: func(s *MockBudgetService) {
+				s.On("SimulateBudget", mock.Anything, uint(1), mock.Anything).
+					Return(nil, &domain.ValidationError{Fields: []domain.FieldError{{Field: "changes[0]", Message: "must give either percent or amount"}}})
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "should reject malformed start dates",
+			path:       "/users/1/budgets/simulate",
+			body:       `{"start_date":"May"}`,
+			setup:      func(*MockBudgetService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "should deny other users",
+			path:       "/users/2/budgets/simulate",
+			body:       `{}`,
+			setup:      func(*MockBudgetService) {},
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupBudgetHandler()
+			tt.setup(mockService)
+			router := setupGin()
+			router.Use(func(c *gin.Context) {
+				c.Set("userID", uint(1))
+				c.Next()
+			})
+			router.POST("/users/:userId/budgets/simulate", handler.Simulate)
+
+			req := httptest.NewRequest("POST", tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBudgetHandler_Plans(t *testing.T) {
+	plan := domain.BudgetPlan{
+		Version: 1, Name: "Coach plan",
+		Budgets: []domain.BudgetPlanItem{{Category: "Food & Dining", CategoryID: 7, Amount: domain.NewMoney(400), Period: "monthly"}},
+	}
+	planJSON, err := json.Marshal(plan)
+	require.NoError(t, err)
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		setup      func(*MockBudgetService)
+		wantStatus int
+	}{
+		{
+			name:   "should export the budgets",
+			method: http.MethodGet,
+			path:   "/users/1/budgets/export?name=Coach+plan",
+			setup: func(s *MockBudgetService) {
+				s.On("ExportBudgetPlan", mock.Anything, uint(1), "Coach plan").Return(&plan, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "should preview an import with mapped categories",
+			method: http.MethodPost,
+			path:   "/users/1/budgets/import/preview",
+			body:   `{"plan":` + string(planJSON) + `,"category_map":{"7":12},"start_date":"2024-05-01"}`,
+			setup: func(s *MockBudgetService) {
+				s.On("PreviewBudgetPlan", mock.Anything, uint(1), domain.BudgetPlanImport{
+					Plan: plan, CategoryMap: map[uint]uint{7: 12}, StartDate: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+				}).Return(&domain.BudgetPlanPreview{Name: "Coach plan", Created: 1}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "should import a plan",
+			method: http.MethodPost,
+			path:   "/users/1/budgets/import",
+			body:   `{"plan":` + string(planJSON) + `}`,
+			setup: func(s *MockBudgetService) {
+				s.On("ImportBudgetPlan", mock.Anything, uint(1), domain.BudgetPlanImport{Plan: plan}).
+					Return(&domain.BudgetPlanPreview{Name: "Coach plan", Applied: true, Created: 1}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "should reject invalid plans",
+			method: http.MethodPost,
+			path:   "/users/1/budgets/import",
+			body:   `{"plan":{"version":2}}`,
+			setup: func(s *MockBudgetService) {
+				s.On("ImportBudgetPlan", mock.Anything, uint(1), mock.Anything).
+					Return(nil, &domain.ValidationError{Fields: []domain.FieldError{{Field: "version", Message: "must be 1"}}})
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "should require a plan",
+			method:     http.MethodPost,
+			path:       "/users/1/budgets/import/preview",
+			body:       `{}`,
+			setup:      func(*MockBudgetService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "should deny other users",
+			method:     http.MethodGet,
+			path:       "/users/2/budgets/export",
+			setup:      func(*MockBudgetService) {},
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockService := setupBudgetHandler()
+			tt.setup(mockService)
+			router := setupGin()
+			router.Use(func(c *gin.Context) {
+				c.Set("userID", uint(1))
+				c.Next()
+			})
+			router.GET("/users/:userId/budgets/export", handler.ExportPlan)
+			router.POST("/users/:userId/budgets/import/preview", handler.PreviewPlan)
+			router.POST("/users/:userId/budgets/import", handler.ImportPlan)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"net/http"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CalculatorHandler serves the financial calculators, which only work on
+// the figures they are given and read no user data
+type CalculatorHandler struct{}
+
+func NewCalculatorHandler() *CalculatorHandler {
+	return &CalculatorHandler{}
+}
+
+// RentVsBuy compares the year by year cost of buying a home with a mortgage
+// with renting instead, and finds the year buying breaks even
+func (h *CalculatorHandler) RentVsBuy(c *gin.Context) {
+	var req domain.RentVsBuyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+	if err := req.Validate(); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, domain.CompareRentVsBuy(req))
+}
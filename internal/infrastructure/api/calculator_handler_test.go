@@ -0,0 +1,48 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalculatorHandler_RentVsBuy(t *testing.T) {
+	router := setupGin()
+	router.POST("/calculators/rent-vs-buy", NewCalculatorHandler().RentVsBuy)
+	serve := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/calculators/rent-vs-buy", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("should compare over the mortgage's term by default", func(t *testing.T) {
+		w := serve(`{"home_price":400000,"down_payment":80000,"mortgage_rate":0.06,"property_tax_rate":0.01,
+			"maintenance_rate":0.01,"appreciation_rate":0.03,"monthly_rent":1800,"rent_growth_rate":0.03}`)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var comparison domain.RentVsBuyComparison
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &comparison))
+		assert.Len(t, comparison.Years, domain.DefaultMortgageYears)
+		assert.Equal(t, domain.NewMoney(320000), comparison.LoanAmount)
+		assert.NotNil(t, comparison.BreakEvenYear)
+	})
+
+	t.Run("should return validation errors", func(t *testing.T) {
+		w := serve(`{"home_price":400000,"down_payment":500000,"monthly_rent":1800}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Contains(t, w.Body.String(), "down_payment")
+	})
+
+	t.Run("should reject malformed bodies", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, serve(`{"home_price":"lots"}`).Code)
+	})
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CalendarFeedServiceInterface defines the interface for calendar feed operations
+type CalendarFeedServiceInterface interface {
+	Link(ctx context.Context, userID uint) (*domain.CalendarFeedLink, error)
+	Rotate(ctx context.Context, userID uint) (*domain.CalendarFeedLink, error)
+	Verify(ctx context.Context, userID uint, token string) error
+	Feed(ctx context.Context, userID uint, now time.Time) ([]byte, error)
+}
+
+type CalendarFeedHandler struct {
+	Service CalendarFeedServiceInterface
+}
+
+func NewCalendarFeedHandler(service CalendarFeedServiceInterface) *CalendarFeedHandler {
+	return &CalendarFeedHandler{Service: service}
+}
+
+// Link returns the URL calendar apps subscribe to the user's feed with
+func (h *CalendarFeedHandler) Link(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	link, err := h.Service.Link(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve the calendar feed", err)
+		return
+	}
+	c.JSON(http.StatusOK, absoluteFeedLink(c, link))
+}
+
+// Rotate replaces the feed's URL, for when it was shared by mistake
+func (h *CalendarFeedHandler) Rotate(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	link, err := h.Service.Rotate(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, "Failed to rotate the calendar feed", err)
+		return
+	}
+	c.JSON(http.StatusOK, absoluteFeedLink(c, link))
+}
+
+// Feed serves the iCal feed. Calendar apps cannot log in, so the token in
+// the URL stands in for authentication.
+func (h *CalendarFeedHandler) Feed(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid user ID")
+		return
+	}
+
+	err = h.Service.Verify(c.Request.Context(), uint(userID), c.Query("token"))
+	if errors.Is(err, domain.ErrInvalidCalendarFeedToken) {
+		respondError(c, middleware.CodeForbidden, "Calendar feed link is invalid")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to verify the calendar feed link", err)
+		return
+	}
+
+	feed, err := h.Service.Feed(c.Request.Context(), uint(userID), time.Now())
+	if err != nil {
+		respondInternalError(c, "Failed to build the calendar feed", err)
+		return
+	}
+	c.Header("Content-Disposition", `inline; filename="calendar.ics"`)
+	c.Header("Cache-Control", "private, max-age=900")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", feed)
+}
+
+// absoluteFeedLink prefixes links without a configured public URL with the
+// address of the request, since calendar apps need absolute ones
+func absoluteFeedLink(c *gin.Context, link *domain.CalendarFeedLink) *domain.CalendarFeedLink {
+	if !strings.HasPrefix(link.URL, "/") {
+		return link
+	}
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	absolute := *link
+	absolute.URL = scheme + "://" + c.Request.Host + link.URL
+	return &absolute
+}
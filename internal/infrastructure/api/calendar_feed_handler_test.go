@@ -0,0 +1,89 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockCalendarFeedService is a mock implementation of CalendarFeedServiceInterface
+type MockCalendarFeedService struct {
+	mock.Mock
+}
+
+func (m *MockCalendarFeedService) Link(ctx context.Context, userID uint) (*domain.CalendarFeedLink, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(*domain.CalendarFeedLink), args.Error(1)
+}
+
+func (m *MockCalendarFeedService) Rotate(ctx context.Context, userID uint) (*domain.CalendarFeedLink, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(*domain.CalendarFeedLink), args.Error(1)
+}
+
+func (m *MockCalendarFeedService) Verify(ctx context.Context, userID uint, token string) error {
+	return m.Called(ctx, userID, token).Error(0)
+}
+
+func (m *MockCalendarFeedService) Feed(ctx context.Context, userID uint, now time.Time) ([]byte, error) {
+	args := m.Called(ctx, userID, now)
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func TestCalendarFeedHandler(t *testing.T) {
+	service := new(MockCalendarFeedService)
+	handler := NewCalendarFeedHandler(service)
+	router := setupGin()
+	router.GET("/api/v1/users/:userId/calendar.ics", handler.Feed)
+	protected := router.Group("/")
+	protected.Use(func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Next()
+	})
+	protected.GET("/api/v1/users/:userId/calendar/feed", handler.Link)
+	protected.POST("/api/v1/users/:userId/calendar/feed/rotate", handler.Rotate)
+
+	service.On("Link", mock.Anything, uint(1)).Return(&domain.CalendarFeedLink{URL: "/api/v1/users/1/calendar.ics?token=abc"}, nil)
+	service.On("Rotate", mock.Anything, uint(1)).Return(&domain.CalendarFeedLink{URL: "https://api.example.com/api/v1/users/1/calendar.ics?token=def"}, nil)
+	service.On("Verify", mock.Anything, uint(1), "abc").Return(nil)
+	service.On("Verify", mock.Anything, uint(1), "old").Return(domain.ErrInvalidCalendarFeedToken)
+	service.On("Feed", mock.Anything, uint(1), mock.Anything).Return([]byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"), nil)
+
+	serve := func(method, target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, target, http.NoBody)
+		req.Host = "finance.example.com"
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve(http.MethodGet, "/api/v1/users/1/calendar/feed")
+	require.Equal(t, http.StatusOK, w.Code)
+	var link domain.CalendarFeedLink
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &link))
+	assert.Equal(t, "http://finance.example.com/api/v1/users/1/calendar.ics?token=abc", link.URL)
+
+	w = serve(http.MethodPost, "/api/v1/users/1/calendar/feed/rotate")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "https://api.example.com/api/v1/users/1/calendar.ics?token=def")
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodGet, "/api/v1/users/2/calendar/feed").Code)
+
+	w = serve(http.MethodGet, "/api/v1/users/1/calendar.ics?token=abc")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/calendar; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n", w.Body.String())
+
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodGet, "/api/v1/users/1/calendar.ics?token=old").Code)
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodGet, "/api/v1/users/x/calendar.ics?token=abc").Code)
+	service.AssertExpectations(t)
+}
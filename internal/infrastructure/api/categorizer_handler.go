@@ -0,0 +1,113 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CategorizerServiceInterface defines the interface for category suggestions
+// and the category models they come from
+type CategorizerServiceInterface interface {
+	Suggest(ctx context.Context, userID uint, description, transactionType string) (*domain.CategorySuggestion, error)
+	Model(ctx context.Context, userID uint) (*domain.CategoryModel, error)
+	Train(ctx context.Context, userID uint) (*domain.CategoryModel, error)
+	Evaluate(ctx context.Context, userID uint) (*domain.CategoryModelEvaluation, error)
+}
+
+type CategorizerHandler struct {
+	Service CategorizerServiceInterface
+}
+
+func NewCategorizerHandler(service CategorizerServiceInterface) *CategorizerHandler {
+	return &CategorizerHandler{Service: service}
+}
+
+// Suggest returns the category suggested for a transaction with the
+// "description" and "type" (expense by default) query parameters. The
+// suggestion is null when nothing is confident enough.
+func (h *CategorizerHandler) Suggest(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+	description := strings.TrimSpace(c.Query("description"))
+	if description == "" {
+		respondError(c, middleware.CodeBadRequest, "description is required")
+		return
+	}
+	transactionType := c.DefaultQuery("type", domain.TransactionTypeExpense)
+	if transactionType != domain.TransactionTypeExpense && transactionType != domain.TransactionTypeIncome {
+		respondError(c, middleware.CodeBadRequest, "type must be income or expense")
+		return
+	}
+
+	suggestion, err := h.Service.Suggest(c.Request.Context(), userID, description, transactionType)
+	if err != nil {
+		respondInternalError(c, "Failed to suggest a category", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"description": description, "type": transactionType, "suggestion": suggestion})
+}
+
+// GetModel returns when the user's category model was trained, on how much
+// history and how well it did
+func (h *CategorizerHandler) GetModel(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+	model, err := h.Service.Model(c.Request.Context(), userID)
+	if errors.Is(err, domain.ErrNotFound) {
+		respondError(c, middleware.CodeNotFound, "No category model has been trained yet")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve the category model", err)
+		return
+	}
+	c.JSON(http.StatusOK, model)
+}
+
+// Train retrains the user's category model on their history now
+func (h *CategorizerHandler) Train(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+	model, err := h.Service.Train(c.Request.Context(), userID)
+	if errors.Is(err, domain.ErrNotEnoughHistory) {
+		respondError(c, middleware.CodeUnprocessable, err.Error())
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to train the category model", err)
+		return
+	}
+	c.JSON(http.StatusOK, model)
+}
+
+// GetEvaluation measures a model trained on the older part of the user's
+// history against their newest transactions
+func (h *CategorizerHandler) GetEvaluation(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+	evaluation, err := h.Service.Evaluate(c.Request.Context(), userID)
+	if errors.Is(err, domain.ErrNotEnoughHistory) {
+		respondError(c, middleware.CodeUnprocessable, err.Error())
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to evaluate the category model", err)
+		return
+	}
+	c.JSON(http.StatusOK, evaluation)
+}
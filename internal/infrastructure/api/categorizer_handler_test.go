@@ -0,0 +1,239 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockCategorizerService is a mock implementation of CategorizerServiceInterface
+type MockCategorizerService struct {
+	mock.Mock
+}
+
+func (m *MockCategorizerService) Suggest(
+	ctx context.Context, userID uint, description, transactionType string,
+) (*domain.CategorySuggestion, error) {
+	args := m.Called(ctx, userID, description, transactionType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CategorySuggestion), args.Error(1)
+}
+
+func (m *MockCategorizerService) Model(ctx context.Context, userID uint) (*domain.CategoryModel, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CategoryModel), args.Error(1)
+}
+
+func (m *MockCategorizerService) Train(ctx context.Context, userID uint) (*domain.CategoryModel, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CategoryModel), args.Error(1)
+}
+
+func (m *MockCategorizerService) Evaluate(ctx context.Context, userID uint) (*domain.CategoryModelEvaluation, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CategoryModelEvaluation), args.Error(1)
+}
+
+func setupCategorizerRouter(service *MockCategorizerService) *gin.Engine {
+	handler := NewCategorizerHandler(service)
+	router := setupGin()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Next()
+	})
+	router.GET("/users/:userId/category-suggestions", handler.Suggest)
+	router.GET("/users/:userId/category-model", handler.GetModel)
+	router.POST("/users/:userId/category-model/train", handler.Train)
+	router.GET("/users/:userId/category-model/evaluation", handler.GetEvaluation)
+	return router
+}
+
+func TestCategorizerHandler_Suggest(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		mockSetup      func(*MockCategorizerService)
+		expectedStatus int
+		expectedSource string
+	}{
+		{
+			name:  "suggests a category for an expense by default",
+			query: "?description=SHELL+OIL+5742",
+			mockSetup: func(m *MockCategorizerService) {
+				m.On("Suggest", mock.Anything, uint(1), "SHELL OIL 5742", domain.TransactionTypeExpense).
+					Return(&domain.CategorySuggestion{CategoryID: 2, Confidence: 0.93, Source: domain.SuggestionSourceModel}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedSource: domain.SuggestionSourceModel,
+		},
+		{
+			name:  "nothing confident enough",
+			query: "?description=Unknown&type=income",
+			mockSetup: func(m *MockCategorizerService) {
+				m.On("Suggest", mock.Anything, uint(1), "Unknown", domain.TransactionTypeIncome).Return(nil, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing description",
+			query:          "?description=++",
+			mockSetup:      func(m *MockCategorizerService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown type",
+			query:          "?description=Shell&type=transfer",
+			mockSetup:      func(m *MockCategorizerService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "service error",
+			query: "?description=Shell",
+			mockSetup: func(m *MockCategorizerService) {
+				m.On("Suggest", mock.Anything, uint(1), "Shell", domain.TransactionTypeExpense).Return(nil, errors.New("db down"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(MockCategorizerService)
+			tt.mockSetup(service)
+			router := setupCategorizerRouter(service)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1/category-suggestions"+tt.query, http.NoBody))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response struct {
+					Suggestion *domain.CategorySuggestion `json:"suggestion"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				if tt.expectedSource == "" {
+					assert.Nil(t, response.Suggestion)
+				} else {
+					require.NotNil(t, response.Suggestion)
+					assert.Equal(t, tt.expectedSource, response.Suggestion.Source)
+				}
+			}
+			service.AssertExpectations(t)
+		})
+	}
+
+	t.Run("other users' suggestions are forbidden", func(t *testing.T) {
+		router := setupCategorizerRouter(new(MockCategorizerService))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/2/category-suggestions?description=Shell", http.NoBody))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestCategorizerHandler_Model(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		mockSetup      func(*MockCategorizerService)
+		expectedStatus int
+	}{
+		{
+			name:   "get the trained model",
+			method: http.MethodGet,
+			path:   "/users/1/category-model",
+			mockSetup: func(m *MockCategorizerService) {
+				m.On("Model", mock.Anything, uint(1)).Return(&domain.CategoryModel{UserID: 1, Samples: 40}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "no model trained yet",
+			method: http.MethodGet,
+			path:   "/users/1/category-model",
+			mockSetup: func(m *MockCategorizerService) {
+				m.On("Model", mock.Anything, uint(1)).Return(nil, domain.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "train the model",
+			method: http.MethodPost,
+			path:   "/users/1/category-model/train",
+			mockSetup: func(m *MockCategorizerService) {
+				m.On("Train", mock.Anything, uint(1)).Return(&domain.CategoryModel{UserID: 1, Samples: 40}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "too little history to train on",
+			method: http.MethodPost,
+			path:   "/users/1/category-model/train",
+			mockSetup: func(m *MockCategorizerService) {
+				m.On("Train", mock.Anything, uint(1)).Return(nil, domain.ErrNotEnoughHistory)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "evaluate the model",
+			method: http.MethodGet,
+			path:   "/users/1/category-model/evaluation",
+			mockSetup: func(m *MockCategorizerService) {
+				m.On("Evaluate", mock.Anything, uint(1)).
+					Return(&domain.CategoryModelEvaluation{TrainSamples: 32, TestSamples: 8, Accuracy: 0.875}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "too little history to evaluate",
+			method: http.MethodGet,
+			path:   "/users/1/category-model/evaluation",
+			mockSetup: func(m *MockCategorizerService) {
+				m.On("Evaluate", mock.Anything, uint(1)).Return(nil, domain.ErrNotEnoughHistory)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "other users' models are forbidden",
+			method:         http.MethodPost,
+			path:           "/users/2/category-model/train",
+			mockSetup:      func(m *MockCategorizerService) {},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(MockCategorizerService)
+			tt.mockSetup(service)
+			router := setupCategorizerRouter(service)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, http.NoBody))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			service.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CategoryCapServiceInterface defines the interface for category cap operations
+type CategoryCapServiceInterface interface {
+	Set(ctx context.Context, userID uint, limit domain.CategoryCap) (*domain.CategoryCap, error)
+	Delete(ctx context.Context, userID, categoryID uint) error
+	Status(ctx context.Context, userID uint, month time.Time) ([]domain.CategoryCapStatus, error)
+}
+
+type CategoryCapHandler struct {
+	Service CategoryCapServiceInterface
+}
+
+func NewCategoryCapHandler(service CategoryCapServiceInterface) *CategoryCapHandler {
+	return &CategoryCapHandler{Service: service}
+}
+
+// CategoryCapRequest is the body of cap requests. With block set, expenses
+// that would go over the cap are refused instead of flagged.
+type CategoryCapRequest struct {
+	Amount domain.Money `json:"amount"`
+	Block  bool         `json:"block"`
+}
+
+// capCategoryIDParam reads the categoryId parameter
+func capCategoryIDParam(c *gin.Context) (uint, bool) {
+	categoryID, err := strconv.ParseUint(c.Param("categoryId"), 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid category ID")
+		return 0, false
+	}
+	return uint(categoryID), true
+}
+
+// List returns the user's caps with the spending of the month given as
+// "YYYY-MM", by default the current one
+func (h *CategoryCapHandler) List(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+	month := time.Now()
+	if param := c.Query("month"); param != "" {
+		parsed, err := time.Parse("2006-01", param)
+		if err != nil {
+			respondError(c, middleware.CodeInvalidDate, "Invalid month format. Use YYYY-MM")
+			return
+		}
+		month = parsed
+	}
+
+	caps, err := h.Service.Status(c.Request.Context(), userID, month)
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve category caps", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"month": month.Format("2006-01"), "caps": caps, "count": len(caps)})
+}
+
+// Set caps the user's monthly spending in a category
+func (h *CategoryCapHandler) Set(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+	categoryID, ok := capCategoryIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req CategoryCapRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	limit, err := h.Service.Set(c.Request.Context(), userID, domain.CategoryCap{CategoryID: categoryID, Amount: req.Amount, Block: req.Block})
+	if respondValidationError(c, err) {
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to save category cap", err)
+		return
+	}
+	c.JSON(http.StatusOK, limit)
+}
+
+// Delete removes the cap on a category
+func (h *CategoryCapHandler) Delete(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+	categoryID, ok := capCategoryIDParam(c)
+	if !ok {
+		return
+	}
+
+	err := h.Service.Delete(c.Request.Context(), userID, categoryID)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "Category has no cap")
+	case err != nil:
+		respondInternalError(c, "Failed to delete category cap", err)
+	default:
+		c.JSON(http.StatusOK, gin.H{"message": "Category cap removed"})
+	}
+}
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockCategoryCapService is a mock implementation of CategoryCapServiceInterface
+type MockCategoryCapService struct {
+	mock.Mock
+}
+
+func (m *MockCategoryCapService) Set(ctx context.Context, userID uint, limit domain.CategoryCap) (*domain.CategoryCap, error) {
+	args := m.Called(ctx, userID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CategoryCap), args.Error(1)
+}
+
+func (m *MockCategoryCapService) Delete(ctx context.Context, userID, categoryID uint) error {
+	return m.Called(ctx, userID, categoryID).Error(0)
+}
+
+func (m *MockCategoryCapService) Status(ctx context.Context, userID uint, month time.Time) ([]domain.CategoryCapStatus, error) {
+	args := m.Called(ctx, userID, month)
+	return args.Get(0).([]domain.CategoryCapStatus), args.Error(1)
+}
+
+func setupCategoryCapRouter(service *MockCategoryCapService) *gin.Engine {
+	handler := NewCategoryCapHandler(service)
+	router := setupGin()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Next()
+	})
+	router.GET("/users/:userId/category-caps", handler.List)
+	router.PUT("/users/:userId/category-caps/:categoryId", handler.Set)
+	router.DELETE("/users/:userId/category-caps/:categoryId", handler.Delete)
+	return router
+}
+
+func TestCategoryCapHandler_List(t *testing.T) {
+	service := new(MockCategoryCapService)
+	may := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	service.On("Status", mock.Anything, uint(1), may).Return([]domain.CategoryCapStatus{
+		{CategoryID: 3, CategoryName: "Food & Dining", Cap: domain.NewMoney(300), Spent: domain.NewMoney(320), Exceeded: true},
+	}, nil)
+	router := setupCategoryCapRouter(service)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1/category-caps?month=2024-05", http.NoBody))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Month string                     `json:"month"`
+		Caps  []domain.CategoryCapStatus `json:"caps"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "2024-05", response.Month)
+	require.Len(t, response.Caps, 1)
+	assert.True(t, response.Caps[0].Exceeded)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1/category-caps?month=May", http.NoBody))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	service.AssertExpectations(t)
+}
+
+func TestCategoryCapHandler_SetDelete(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		setup      func(*MockCategoryCapService)
+		wantStatus int
+	}{
+		{
+			name: "should save the cap", method: http.MethodPut, path: "/users/1/category-caps/3",
+			body: `{"amount":300,"block":true}`,
+			setup: func(s *MockCategoryCapService) {
+				s.On("Set", mock.Anything, uint(1), domain.CategoryCap{CategoryID: 3, Amount: domain.NewMoney(300), Block: true}).
+					Return(&domain.CategoryCap{ID: 1, UserID: 1, CategoryID: 3, Amount: domain.NewMoney(300), Block: true}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "should reject invalid caps", method: http.MethodPut, path: "/users/1/category-caps/3",
+			body: `{"amount":0}`,
+			setup: func(s *MockCategoryCapService) {
+				s.On("Set", mock.Anything, uint(1), mock.Anything).
+					Return(nil, &domain.ValidationError{Fields: []domain.FieldError{{Field: "amount", Message: "must be greater than zero"}}})
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "should reject invalid category IDs", method: http.MethodPut, path: "/users/1/category-caps/abc",
+			body: `{"amount":300}`, setup: func(*MockCategoryCapService) {}, wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "should deny other users' caps", method: http.MethodPut, path: "/users/2/category-caps/3",
+			body: `{"amount":300}`, setup: func(*MockCategoryCapService) {}, wantStatus: http.StatusForbidden,
+		},
+		{
+			name: "should delete the cap", method: http.MethodDelete, path: "/users/1/category-caps/3",
+			setup: func(s *MockCategoryCapService) {
+				s.On("Delete", mock.Anything, uint(1), uint(3)).Return(nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "should report missing caps", method: http.MethodDelete, path: "/users/1/category-caps/4",
+			setup: func(s *MockCategoryCapService) {
+				s.On("Delete", mock.Anything, uint(1), uint(4)).Return(domain.ErrNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(MockCategoryCapService)
+			tt.setup(service)
+			router := setupCategoryCapRouter(service)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			service.AssertExpectations(t)
+		})
+	}
+}
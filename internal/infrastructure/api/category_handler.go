@@ -2,24 +2,28 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strconv"
 
 	"go-finance-advisor/internal/application"
 	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
 
 	"github.com/gin-gonic/gin"
 )
 
 type CategoryServiceInterface interface {
-	InitializeDefaultCategories() error
-	GetAllCategories() ([]domain.Category, error)
-	GetCategoryByID(categoryID uint) (*domain.Category, error)
-	CreateCategory(category *domain.Category) error
-	UpdateCategory(categoryID uint, category *domain.Category) error
-	DeleteCategory(categoryID uint) error
-	GetCategoryUsageStats(userID uint) ([]application.CategoryUsageStats, error)
-	GetCategoriesByType(categoryType string) ([]domain.Category, error)
+	InitializeDefaultCategories(ctx context.Context) error
+	GetAllCategories(ctx context.Context, userID uint) ([]domain.Category, error)
+	GetCategoryByID(ctx context.Context, userID, categoryID uint) (*domain.Category, error)
+	CreateCategory(ctx context.Context, userID uint, category *domain.Category) error
+	UpdateCategory(ctx context.Context, userID, categoryID uint, category *domain.Category) error
+	DeleteCategory(ctx context.Context, userID, categoryID uint, options domain.CategoryDeleteOptions) (*domain.CategoryDeletion, error)
+	GetCategoryUsageStats(ctx context.Context, userID uint) ([]application.CategoryUsageStats, error)
+	GetCategoriesByType(ctx context.Context, userID uint, categoryType string) ([]domain.Category, error)
+	GetCategoryTree(ctx context.Context, userID uint) ([]domain.Category, error)
 }
 
 type CategoryHandler struct {
@@ -32,6 +36,7 @@ type CreateCategoryRequest struct {
 	Description string `json:"description,omitempty"`
 	Icon        string `json:"icon,omitempty"`
 	Color       string `json:"color,omitempty"`
+	ParentID    *uint  `json:"parent_id,omitempty"`
 }
 
 type UpdateCategoryRequest struct {
@@ -39,13 +44,14 @@ type UpdateCategoryRequest struct {
 	Description *string `json:"description,omitempty"`
 	Icon        *string `json:"icon,omitempty"`
 	Color       *string `json:"color,omitempty"`
+	ParentID    *uint   `json:"parent_id,omitempty"` // 0 moves the category to the top level
 }
 
 // InitializeDefaultCategories initializes default categories for the system
 func (h *CategoryHandler) InitializeDefaultCategories(c *gin.Context) {
-	err := h.Service.InitializeDefaultCategories()
+	err := h.Service.InitializeDefaultCategories(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to initialize default categories"})
+		respondInternalError(c, "Failed to initialize default categories", err)
 		return
 	}
 
@@ -54,9 +60,32 @@ func (h *CategoryHandler) InitializeDefaultCategories(c *gin.Context) {
 
 // GetCategories returns all categories with optional filtering
 func (h *CategoryHandler) GetCategories(c *gin.Context) {
-	categories, err := h.Service.GetAllCategories()
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		respondError(c, middleware.CodeUnauthenticated, "User not authenticated")
+		return
+	}
+
+	categories, err := h.Service.GetAllCategories(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve categories", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, categories)
+}
+
+// GetCategoryTree returns the top-level categories with their nested categories as children
+func (h *CategoryHandler) GetCategoryTree(c *gin.Context) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		respondError(c, middleware.CodeUnauthenticated, "User not authenticated")
+		return
+	}
+
+	categories, err := h.Service.GetCategoryTree(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve categories"})
+		respondInternalError(c, "Failed to retrieve categories", err)
 		return
 	}
 
@@ -65,16 +94,22 @@ func (h *CategoryHandler) GetCategories(c *gin.Context) {
 
 // GetCategory returns a specific category by ID
 func (h *CategoryHandler) GetCategory(c *gin.Context) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		respondError(c, middleware.CodeUnauthenticated, "User not authenticated")
+		return
+	}
+
 	categoryIDStr := c.Param("categoryId")
 	categoryID, err := strconv.ParseUint(categoryIDStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category ID"})
+		respondError(c, middleware.CodeInvalidID, "Invalid category ID")
 		return
 	}
 
-	category, err := h.Service.GetCategoryByID(uint(categoryID))
+	category, err := h.Service.GetCategoryByID(c.Request.Context(), userID, uint(categoryID))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		respondError(c, middleware.CodeNotFound, "Category not found")
 		return
 	}
 
@@ -83,9 +118,15 @@ func (h *CategoryHandler) GetCategory(c *gin.Context) {
 
 // CreateCategory creates a new custom category
 func (h *CategoryHandler) CreateCategory(c *gin.Context) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		respondError(c, middleware.CodeUnauthenticated, "User not authenticated")
+		return
+	}
+
 	var req CreateCategoryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, middleware.CodeInvalidBody, err.Error())
 		return
 	}
 
@@ -95,12 +136,17 @@ func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 		Description: req.Description,
 		Icon:        req.Icon,
 		Color:       req.Color,
+		ParentID:    req.ParentID,
 		IsDefault:   false, // Custom categories are never default
 	}
 
-	err := h.Service.CreateCategory(category)
+	err := h.Service.CreateCategory(c.Request.Context(), userID, category)
+	if errors.Is(err, domain.ErrInvalidCategoryParent) {
+		respondError(c, middleware.CodeBadRequest, err.Error())
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create category"})
+		respondInternalError(c, "Failed to create category", err)
 		return
 	}
 
@@ -109,29 +155,35 @@ func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 
 // UpdateCategory updates an existing category
 func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		respondError(c, middleware.CodeUnauthenticated, "User not authenticated")
+		return
+	}
+
 	categoryIDStr := c.Param("categoryId")
 	categoryID, err := strconv.ParseUint(categoryIDStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category ID"})
+		respondError(c, middleware.CodeInvalidID, "Invalid category ID")
 		return
 	}
 
 	var req UpdateCategoryRequest
 	if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": bindErr.Error()})
+		respondError(c, middleware.CodeInvalidBody, bindErr.Error())
 		return
 	}
 
 	// Get existing category
-	category, err := h.Service.GetCategoryByID(uint(categoryID))
+	category, err := h.Service.GetCategoryByID(c.Request.Context(), userID, uint(categoryID))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		respondError(c, middleware.CodeNotFound, "Category not found")
 		return
 	}
 
 	// Check if it's a default category (cannot be modified)
 	if category.IsDefault {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Default categories cannot be modified"})
+		respondError(c, middleware.CodeForbidden, "Default categories cannot be modified")
 		return
 	}
 
@@ -148,72 +200,112 @@ func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
 	if req.Color != nil {
 		category.Color = *req.Color
 	}
+	if req.ParentID != nil {
+		category.ParentID = req.ParentID
+	}
 
-	err = h.Service.UpdateCategory(uint(categoryID), category)
+	err = h.Service.UpdateCategory(c.Request.Context(), userID, uint(categoryID), category)
+	if errors.Is(err, domain.ErrInvalidCategoryParent) {
+		respondError(c, middleware.CodeBadRequest, err.Error())
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update category"})
+		respondInternalError(c, "Failed to update category", err)
 		return
 	}
 
 	// Get the updated category to return
-	updatedCategory, err := h.Service.GetCategoryByID(uint(categoryID))
+	updatedCategory, err := h.Service.GetCategoryByID(c.Request.Context(), userID, uint(categoryID))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get updated category"})
+		respondInternalError(c, "Failed to get updated category", err)
 		return
 	}
 
 	c.JSON(http.StatusOK, updatedCategory)
 }
 
-// DeleteCategory deletes a category
+// DeleteCategory deletes a category. The records still using it move to the
+// category in replacement_id, or with confirm=true to Uncategorized;
+// without either the category stays and a 409 lists what uses it.
 func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		respondError(c, middleware.CodeUnauthenticated, "User not authenticated")
+		return
+	}
+
 	categoryIDStr := c.Param("categoryId")
 	categoryID, err := strconv.ParseUint(categoryIDStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category ID"})
+		respondError(c, middleware.CodeInvalidID, "Invalid category ID")
 		return
 	}
 
 	// Get existing category
-	category, err := h.Service.GetCategoryByID(uint(categoryID))
+	category, err := h.Service.GetCategoryByID(c.Request.Context(), userID, uint(categoryID))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		respondError(c, middleware.CodeNotFound, "Category not found")
 		return
 	}
 
 	// Check if it's a default category (cannot be deleted)
 	if category.IsDefault {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Default categories cannot be deleted"})
+		respondError(c, middleware.CodeForbidden, "Default categories cannot be deleted")
 		return
 	}
 
-	err = h.Service.DeleteCategory(uint(categoryID))
+	var options domain.CategoryDeleteOptions
+	if raw := c.Query("replacement_id"); raw != "" {
+		replacementID, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			respondError(c, middleware.CodeInvalidID, "Invalid replacement category ID")
+			return
+		}
+		id := uint(replacementID)
+		options.ReplacementID = &id
+	}
+	options.Confirm = c.Query("confirm") == "true"
+
+	deletion, err := h.Service.DeleteCategory(c.Request.Context(), userID, uint(categoryID), options)
+	if errors.Is(err, domain.ErrCategoryInUse) {
+		c.JSON(http.StatusConflict, categoryInUseResponse{
+			ErrorResponse: middleware.ErrorResponse{
+				Code:  middleware.CodeConflict,
+				Error: middleware.Localizer(c).Message("Category is still in use; name a replacement_id or confirm moving its records"),
+			},
+			Usage: deletion,
+		})
+		return
+	}
+	if respondValidationError(c, err) {
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete category"})
+		respondInternalError(c, "Failed to delete category", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Category deleted successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": "Category deleted successfully", "moved": deletion})
 }
 
-// GetCategoryUsage returns usage statistics for categories
-func (h *CategoryHandler) GetCategoryUsage(c *gin.Context) {
-	// Get query parameters
-	userIDStr := c.Query("user_id")
+// categoryInUseResponse answers the deletion of a category records still
+// refer to with what refers to it, for the user to confirm
+type categoryInUseResponse struct {
+	middleware.ErrorResponse
+	Usage *domain.CategoryDeletion `json:"usage"`
+}
 
-	var userID uint
-	if userIDStr != "" {
-		uID, err := strconv.ParseUint(userIDStr, 10, 32)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
-			return
-		}
-		userID = uint(uID)
+// GetCategoryUsage returns usage statistics of the user's categories
+func (h *CategoryHandler) GetCategoryUsage(c *gin.Context) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		respondError(c, middleware.CodeUnauthenticated, "User not authenticated")
+		return
 	}
 
-	stats, err := h.Service.GetCategoryUsageStats(userID)
+	stats, err := h.Service.GetCategoryUsageStats(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve category usage"})
+		respondInternalError(c, "Failed to retrieve category usage", err)
 		return
 	}
 
@@ -222,9 +314,15 @@ func (h *CategoryHandler) GetCategoryUsage(c *gin.Context) {
 
 // GetIncomeCategories returns all income categories
 func (h *CategoryHandler) GetIncomeCategories(c *gin.Context) {
-	categories, err := h.Service.GetCategoriesByType("income")
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		respondError(c, middleware.CodeUnauthenticated, "User not authenticated")
+		return
+	}
+
+	categories, err := h.Service.GetCategoriesByType(c.Request.Context(), userID, "income")
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve income categories"})
+		respondInternalError(c, "Failed to retrieve income categories", err)
 		return
 	}
 
@@ -233,11 +331,27 @@ func (h *CategoryHandler) GetIncomeCategories(c *gin.Context) {
 
 // GetExpenseCategories returns all expense categories
 func (h *CategoryHandler) GetExpenseCategories(c *gin.Context) {
-	categories, err := h.Service.GetCategoriesByType("expense")
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		respondError(c, middleware.CodeUnauthenticated, "User not authenticated")
+		return
+	}
+
+	categories, err := h.Service.GetCategoriesByType(c.Request.Context(), userID, "expense")
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve expense categories"})
+		respondInternalError(c, "Failed to retrieve expense categories", err)
 		return
 	}
 
 	c.JSON(http.StatusOK, categories)
 }
+
+// authenticatedUserID returns the ID of the user AuthMiddleware authenticated
+func authenticatedUserID(c *gin.Context) (uint, bool) {
+	value, exists := c.Get("userID")
+	if !exists {
+		return 0, false
+	}
+	userID, ok := value.(uint)
+	return userID, ok
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -10,9 +11,12 @@ import (
 
 	"go-finance-advisor/internal/application"
 	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
 
+	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockCategoryService is a mock implementation of CategoryService
@@ -20,46 +24,66 @@ type MockCategoryService struct {
 	mock.Mock
 }
 
-func (m *MockCategoryService) InitializeDefaultCategories() error {
-	args := m.Called()
+func (m *MockCategoryService) InitializeDefaultCategories(ctx context.Context) error {
+	args := m.Called(ctx)
 	return args.Error(0)
 }
 
-func (m *MockCategoryService) GetAllCategories() ([]domain.Category, error) {
-	args := m.Called()
+func (m *MockCategoryService) GetAllCategories(ctx context.Context, userID uint) ([]domain.Category, error) {
+	args := m.Called(ctx, userID)
 	return args.Get(0).([]domain.Category), args.Error(1)
 }
 
-func (m *MockCategoryService) GetCategoryByID(categoryID uint) (*domain.Category, error) {
-	args := m.Called(categoryID)
+func (m *MockCategoryService) GetCategoryByID(ctx context.Context, userID, categoryID uint) (*domain.Category, error) {
+	args := m.Called(ctx, userID, categoryID)
 	return args.Get(0).(*domain.Category), args.Error(1)
 }
 
-func (m *MockCategoryService) CreateCategory(category *domain.Category) error {
-	args := m.Called(category)
+func (m *MockCategoryService) CreateCategory(ctx context.Context, userID uint, category *domain.Category) error {
+	args := m.Called(ctx, userID, category)
 	return args.Error(0)
 }
 
-func (m *MockCategoryService) UpdateCategory(categoryID uint, category *domain.Category) error {
-	args := m.Called(categoryID, category)
+func (m *MockCategoryService) UpdateCategory(ctx context.Context, userID, categoryID uint, category *domain.Category) error {
+	args := m.Called(ctx, userID, categoryID, category)
 	return args.Error(0)
 }
 
-func (m *MockCategoryService) DeleteCategory(categoryID uint) error {
-	args := m.Called(categoryID)
-	return args.Error(0)
+func (m *MockCategoryService) DeleteCategory(
+	ctx context.Context, userID, categoryID uint, options domain.CategoryDeleteOptions,
+) (*domain.CategoryDeletion, error) {
+	args := m.Called(ctx, userID, categoryID, options)
+	deletion, _ := args.Get(0).(*domain.CategoryDeletion)
+	return deletion, args.Error(1)
 }
 
-func (m *MockCategoryService) GetCategoryUsageStats(userID uint) ([]application.CategoryUsageStats, error) {
-	args := m.Called(userID)
+func (m *MockCategoryService) GetCategoryUsageStats(ctx context.Context, userID uint) ([]application.CategoryUsageStats, error) {
+	args := m.Called(ctx, userID)
 	return args.Get(0).([]application.CategoryUsageStats), args.Error(1)
 }
 
-func (m *MockCategoryService) GetCategoriesByType(categoryType string) ([]domain.Category, error) {
-	args := m.Called(categoryType)
+func (m *MockCategoryService) GetCategoriesByType(ctx context.Context, userID uint, categoryType string) ([]domain.Category, error) {
+	args := m.Called(ctx, userID, categoryType)
+	return args.Get(0).([]domain.Category), args.Error(1)
+}
+
+func (m *MockCategoryService) GetCategoryTree(ctx context.Context, userID uint) ([]domain.Category, error) {
+	args := m.Called(ctx, userID)
 	return args.Get(0).([]domain.Category), args.Error(1)
 }
 
+// categoryTestUserID is the user setupCategoryGin authenticates requests as
+const categoryTestUserID uint = 7
+
+// setupCategoryGin returns a router whose requests are authenticated as categoryTestUserID
+func setupCategoryGin() *gin.Engine {
+	router := setupGin()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", categoryTestUserID)
+	})
+	return router
+}
+
 func setupCategoryHandler() (*CategoryHandler, *MockCategoryService) {
 	mockService := &MockCategoryService{}
 	handler := &CategoryHandler{
@@ -71,10 +95,10 @@ func setupCategoryHandler() (*CategoryHandler, *MockCategoryService) {
 func TestCategoryHandler_InitializeDefaultCategories(t *testing.T) {
 	t.Run("should initialize default categories successfully", func(t *testing.T) {
 		handler, mockService := setupCategoryHandler()
-		router := setupGin()
+		router := setupCategoryGin()
 		router.POST("/categories/initialize", handler.InitializeDefaultCategories)
 
-		mockService.On("InitializeDefaultCategories").Return(nil)
+		mockService.On("InitializeDefaultCategories", mock.Anything).Return(nil)
 
 		req := httptest.NewRequest("POST", "/categories/initialize", http.NoBody)
 		w := httptest.NewRecorder()
@@ -90,10 +114,10 @@ func TestCategoryHandler_InitializeDefaultCategories(t *testing.T) {
 
 	t.Run("should return internal server error when service fails", func(t *testing.T) {
 		handler, mockService := setupCategoryHandler()
-		router := setupGin()
+		router := setupCategoryGin()
 		router.POST("/categories/initialize", handler.InitializeDefaultCategories)
 
-		mockService.On("InitializeDefaultCategories").Return(errors.New("database error"))
+		mockService.On("InitializeDefaultCategories", mock.Anything).Return(errors.New("database error"))
 
 		req := httptest.NewRequest("POST", "/categories/initialize", http.NoBody)
 		w := httptest.NewRecorder()
@@ -111,7 +135,7 @@ func TestCategoryHandler_InitializeDefaultCategories(t *testing.T) {
 func TestCategoryHandler_GetCategories(t *testing.T) {
 	t.Run("should get categories successfully", func(t *testing.T) {
 		handler, mockService := setupCategoryHandler()
-		router := setupGin()
+		router := setupCategoryGin()
 		router.GET("/categories", handler.GetCategories)
 
 		expectedCategories := []domain.Category{
@@ -135,7 +159,7 @@ func TestCategoryHandler_GetCategories(t *testing.T) {
 			},
 		}
 
-		mockService.On("GetAllCategories").Return(expectedCategories, nil)
+		mockService.On("GetAllCategories", mock.Anything, categoryTestUserID).Return(expectedCategories, nil)
 
 		req := httptest.NewRequest("GET", "/categories", http.NoBody)
 		w := httptest.NewRecorder()
@@ -153,10 +177,10 @@ func TestCategoryHandler_GetCategories(t *testing.T) {
 
 	t.Run("should return internal server error when service fails", func(t *testing.T) {
 		handler, mockService := setupCategoryHandler()
-		router := setupGin()
+		router := setupCategoryGin()
 		router.GET("/categories", handler.GetCategories)
 
-		mockService.On("GetAllCategories").Return([]domain.Category{}, errors.New("database error"))
+		mockService.On("GetAllCategories", mock.Anything, categoryTestUserID).Return([]domain.Category{}, errors.New("database error"))
 
 		req := httptest.NewRequest("GET", "/categories", http.NoBody)
 		w := httptest.NewRecorder()
@@ -171,10 +195,33 @@ func TestCategoryHandler_GetCategories(t *testing.T) {
 	})
 }
 
+func TestCategoryHandler_GetCategoryTree(t *testing.T) {
+	handler, mockService := setupCategoryHandler()
+	router := setupCategoryGin()
+	router.GET("/categories/tree", handler.GetCategoryTree)
+
+	tree := []domain.Category{
+		{ID: 1, Name: "Food & Dining", Type: "expense", Children: []domain.Category{{ID: 2, Name: "Groceries", Type: "expense"}}},
+	}
+	mockService.On("GetCategoryTree", mock.Anything, categoryTestUserID).Return(tree, nil)
+
+	req := httptest.NewRequest("GET", "/categories/tree", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response []domain.Category
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Len(t, response, 1)
+	assert.Len(t, response[0].Children, 1)
+	mockService.AssertExpectations(t)
+}
+
 func TestCategoryHandler_GetCategory(t *testing.T) {
 	t.Run("should get category successfully", func(t *testing.T) {
 		handler, mockService := setupCategoryHandler()
-		router := setupGin()
+		router := setupCategoryGin()
 		router.GET("/categories/:categoryId", handler.GetCategory)
 
 		expectedCategory := &domain.Category{
@@ -187,7 +234,7 @@ func TestCategoryHandler_GetCategory(t *testing.T) {
 			IsDefault:   true,
 		}
 
-		mockService.On("GetCategoryByID", uint(1)).Return(expectedCategory, nil)
+		mockService.On("GetCategoryByID", mock.Anything, categoryTestUserID, uint(1)).Return(expectedCategory, nil)
 
 		req := httptest.NewRequest("GET", "/categories/1", http.NoBody)
 		w := httptest.NewRecorder()
@@ -204,7 +251,7 @@ func TestCategoryHandler_GetCategory(t *testing.T) {
 
 	t.Run("should return bad request for invalid category ID", func(t *testing.T) {
 		handler, _ := setupCategoryHandler()
-		router := setupGin()
+		router := setupCategoryGin()
 		router.GET("/categories/:categoryId", handler.GetCategory)
 
 		req := httptest.NewRequest("GET", "/categories/invalid", http.NoBody)
@@ -215,15 +262,15 @@ func TestCategoryHandler_GetCategory(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 		var response map[string]interface{}
 		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Equal(t, "invalid category ID", response["error"])
+		assert.Equal(t, "Invalid category ID", response["error"])
 	})
 
 	t.Run("should return not found for non-existent category", func(t *testing.T) {
 		handler, mockService := setupCategoryHandler()
-		router := setupGin()
+		router := setupCategoryGin()
 		router.GET("/categories/:categoryId", handler.GetCategory)
 
-		mockService.On("GetCategoryByID", uint(999)).Return((*domain.Category)(nil), errors.New("not found"))
+		mockService.On("GetCategoryByID", mock.Anything, categoryTestUserID, uint(999)).Return((*domain.Category)(nil), errors.New("not found"))
 
 		req := httptest.NewRequest("GET", "/categories/999", http.NoBody)
 		w := httptest.NewRecorder()
@@ -241,7 +288,7 @@ func TestCategoryHandler_GetCategory(t *testing.T) {
 func TestCategoryHandler_CreateCategory(t *testing.T) {
 	t.Run("should create category successfully", func(t *testing.T) {
 		handler, mockService := setupCategoryHandler()
-		router := setupGin()
+		router := setupCategoryGin()
 		router.POST("/categories", handler.CreateCategory)
 
 		reqBody := CreateCategoryRequest{
@@ -252,7 +299,7 @@ func TestCategoryHandler_CreateCategory(t *testing.T) {
 			Color:       "#FF5722",
 		}
 
-		mockService.On("CreateCategory", mock.AnythingOfType("*domain.Category")).Return(nil)
+		mockService.On("CreateCategory", mock.Anything, categoryTestUserID, mock.AnythingOfType("*domain.Category")).Return(nil)
 
 		body, _ := json.Marshal(reqBody)
 		req := httptest.NewRequest("POST", "/categories", bytes.NewBuffer(body))
@@ -272,7 +319,7 @@ func TestCategoryHandler_CreateCategory(t *testing.T) {
 
 	t.Run("should return bad request for invalid JSON", func(t *testing.T) {
 		handler, _ := setupCategoryHandler()
-		router := setupGin()
+		router := setupCategoryGin()
 		router.POST("/categories", handler.CreateCategory)
 
 		req := httptest.NewRequest("POST", "/categories", bytes.NewBufferString("invalid json"))
@@ -286,7 +333,7 @@ func TestCategoryHandler_CreateCategory(t *testing.T) {
 
 	t.Run("should return bad request for invalid type", func(t *testing.T) {
 		handler, _ := setupCategoryHandler()
-		router := setupGin()
+		router := setupCategoryGin()
 		router.POST("/categories", handler.CreateCategory)
 
 		reqBody := CreateCategoryRequest{
@@ -304,9 +351,32 @@ func TestCategoryHandler_CreateCategory(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
 
+	t.Run("should return bad request for invalid parent", func(t *testing.T) {
+		handler, mockService := setupCategoryHandler()
+		router := setupCategoryGin()
+		router.POST("/categories", handler.CreateCategory)
+
+		parentID := uint(42)
+		reqBody := CreateCategoryRequest{Name: "Groceries", Type: "expense", ParentID: &parentID}
+
+		mockService.On("CreateCategory", mock.Anything, categoryTestUserID, mock.MatchedBy(func(category *domain.Category) bool {
+			return category.ParentID != nil && *category.ParentID == parentID
+		})).Return(domain.ErrInvalidCategoryParent)
+
+		body, _ := json.Marshal(reqBody)
+		req := httptest.NewRequest("POST", "/categories", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
 	t.Run("should return internal server error when service fails", func(t *testing.T) {
 		handler, mockService := setupCategoryHandler()
-		router := setupGin()
+		router := setupCategoryGin()
 		router.POST("/categories", handler.CreateCategory)
 
 		reqBody := CreateCategoryRequest{
@@ -314,7 +384,7 @@ func TestCategoryHandler_CreateCategory(t *testing.T) {
 			Type: "expense",
 		}
 
-		mockService.On("CreateCategory", mock.AnythingOfType("*domain.Category")).Return(errors.New("database error"))
+		mockService.On("CreateCategory", mock.Anything, categoryTestUserID, mock.AnythingOfType("*domain.Category")).Return(errors.New("database error"))
 
 		body, _ := json.Marshal(reqBody)
 		req := httptest.NewRequest("POST", "/categories", bytes.NewBuffer(body))
@@ -334,7 +404,7 @@ func TestCategoryHandler_CreateCategory(t *testing.T) {
 func TestCategoryHandler_UpdateCategory(t *testing.T) {
 	t.Run("should update category successfully", func(t *testing.T) {
 		handler, mockService := setupCategoryHandler()
-		router := setupGin()
+		router := setupCategoryGin()
 		router.PUT("/categories/:categoryId", handler.UpdateCategory)
 
 		existingCategory := &domain.Category{
@@ -368,9 +438,9 @@ func TestCategoryHandler_UpdateCategory(t *testing.T) {
 			Color:       &newColor,
 		}
 
-		mockService.On("GetCategoryByID", uint(1)).Return(existingCategory, nil).Once()
-		mockService.On("UpdateCategory", uint(1), mock.AnythingOfType("*domain.Category")).Return(nil)
-		mockService.On("GetCategoryByID", uint(1)).Return(updatedCategory, nil).Once()
+		mockService.On("GetCategoryByID", mock.Anything, categoryTestUserID, uint(1)).Return(existingCategory, nil).Once()
+		mockService.On("UpdateCategory", mock.Anything, categoryTestUserID, uint(1), mock.AnythingOfType("*domain.Category")).Return(nil)
+		mockService.On("GetCategoryByID", mock.Anything, categoryTestUserID, uint(1)).Return(updatedCategory, nil).Once()
 
 		body, _ := json.Marshal(reqBody)
 		req := httptest.NewRequest("PUT", "/categories/1", bytes.NewBuffer(body))
@@ -389,7 +459,7 @@ func TestCategoryHandler_UpdateCategory(t *testing.T) {
 
 	t.Run("should return forbidden for default category", func(t *testing.T) {
 		handler, mockService := setupCategoryHandler()
-		router := setupGin()
+		router := setupCategoryGin()
 		router.PUT("/categories/:categoryId", handler.UpdateCategory)
 
 		defaultCategory := &domain.Category{
@@ -404,7 +474,7 @@ func TestCategoryHandler_UpdateCategory(t *testing.T) {
 			Name: &newName,
 		}
 
-		mockService.On("GetCategoryByID", uint(1)).Return(defaultCategory, nil)
+		mockService.On("GetCategoryByID", mock.Anything, categoryTestUserID, uint(1)).Return(defaultCategory, nil)
 
 		body, _ := json.Marshal(reqBody)
 		req := httptest.NewRequest("PUT", "/categories/1", bytes.NewBuffer(body))
@@ -422,7 +492,7 @@ func TestCategoryHandler_UpdateCategory(t *testing.T) {
 
 	t.Run("should return not found for non-existent category", func(t *testing.T) {
 		handler, mockService := setupCategoryHandler()
-		router := setupGin()
+		router := setupCategoryGin()
 		router.PUT("/categories/:categoryId", handler.UpdateCategory)
 
 		newName := "Updated Food"
@@ -430,7 +500,7 @@ func TestCategoryHandler_UpdateCategory(t *testing.T) {
 			Name: &newName,
 		}
 
-		mockService.On("GetCategoryByID", uint(999)).Return((*domain.Category)(nil), errors.New("not found"))
+		mockService.On("GetCategoryByID", mock.Anything, categoryTestUserID, uint(999)).Return((*domain.Category)(nil), errors.New("not found"))
 
 		body, _ := json.Marshal(reqBody)
 		req := httptest.NewRequest("PUT", "/categories/999", bytes.NewBuffer(body))
@@ -450,7 +520,7 @@ func TestCategoryHandler_UpdateCategory(t *testing.T) {
 func TestCategoryHandler_DeleteCategory(t *testing.T) {
 	t.Run("should delete category successfully", func(t *testing.T) {
 		handler, mockService := setupCategoryHandler()
-		router := setupGin()
+		router := setupCategoryGin()
 		router.DELETE("/categories/:categoryId", handler.DeleteCategory)
 
 		customCategory := &domain.Category{
@@ -460,8 +530,9 @@ func TestCategoryHandler_DeleteCategory(t *testing.T) {
 			IsDefault: false, // Custom category
 		}
 
-		mockService.On("GetCategoryByID", uint(1)).Return(customCategory, nil)
-		mockService.On("DeleteCategory", uint(1)).Return(nil)
+		mockService.On("GetCategoryByID", mock.Anything, categoryTestUserID, uint(1)).Return(customCategory, nil)
+		mockService.On("DeleteCategory", mock.Anything, categoryTestUserID, uint(1), domain.CategoryDeleteOptions{}).
+			Return(&domain.CategoryDeletion{CategoryID: 1, Deleted: true}, nil)
 
 		req := httptest.NewRequest("DELETE", "/categories/1", http.NoBody)
 		w := httptest.NewRecorder()
@@ -477,7 +548,7 @@ func TestCategoryHandler_DeleteCategory(t *testing.T) {
 
 	t.Run("should return forbidden for default category", func(t *testing.T) {
 		handler, mockService := setupCategoryHandler()
-		router := setupGin()
+		router := setupCategoryGin()
 		router.DELETE("/categories/:categoryId", handler.DeleteCategory)
 
 		defaultCategory := &domain.Category{
@@ -487,7 +558,7 @@ func TestCategoryHandler_DeleteCategory(t *testing.T) {
 			IsDefault: true, // Default category
 		}
 
-		mockService.On("GetCategoryByID", uint(1)).Return(defaultCategory, nil)
+		mockService.On("GetCategoryByID", mock.Anything, categoryTestUserID, uint(1)).Return(defaultCategory, nil)
 
 		req := httptest.NewRequest("DELETE", "/categories/1", http.NoBody)
 		w := httptest.NewRecorder()
@@ -500,12 +571,79 @@ func TestCategoryHandler_DeleteCategory(t *testing.T) {
 		assert.Equal(t, "Default categories cannot be deleted", response["error"])
 		mockService.AssertExpectations(t)
 	})
+
+	t.Run("should list what uses a category before deleting it", func(t *testing.T) {
+		handler, mockService := setupCategoryHandler()
+		router := setupCategoryGin()
+		router.DELETE("/categories/:categoryId", handler.DeleteCategory)
+
+		usage := &domain.CategoryDeletion{
+			CategoryID: 1, Transactions: 12, Budgets: 1,
+			Replacement: &domain.Category{ID: 9, Name: domain.UncategorizedCategoryName, IsDefault: true, IsSystem: true},
+		}
+		mockService.On("GetCategoryByID", mock.Anything, categoryTestUserID, uint(1)).Return(&domain.Category{ID: 1, Type: "expense"}, nil)
+		mockService.On("DeleteCategory", mock.Anything, categoryTestUserID, uint(1), domain.CategoryDeleteOptions{}).
+			Return(usage, domain.ErrCategoryInUse)
+
+		req := httptest.NewRequest("DELETE", "/categories/1", http.NoBody)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		var response struct {
+			Code  string                  `json:"code"`
+			Usage domain.CategoryDeletion `json:"usage"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, string(middleware.CodeConflict), response.Code)
+		assert.Equal(t, 12, response.Usage.Transactions)
+		assert.Equal(t, domain.UncategorizedCategoryName, response.Usage.Replacement.Name)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should pass the replacement and the confirmation on", func(t *testing.T) {
+		handler, mockService := setupCategoryHandler()
+		router := setupCategoryGin()
+		router.DELETE("/categories/:categoryId", handler.DeleteCategory)
+
+		replacementID := uint(4)
+		mockService.On("GetCategoryByID", mock.Anything, categoryTestUserID, uint(1)).Return(&domain.Category{ID: 1, Type: "expense"}, nil)
+		mockService.On("DeleteCategory", mock.Anything, categoryTestUserID, uint(1),
+			domain.CategoryDeleteOptions{ReplacementID: &replacementID, Confirm: true}).
+			Return(&domain.CategoryDeletion{CategoryID: 1, Transactions: 3, Deleted: true}, nil)
+
+		req := httptest.NewRequest("DELETE", "/categories/1?replacement_id=4&confirm=true", http.NoBody)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"transactions":3`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should reject a replacement that is not a category ID", func(t *testing.T) {
+		handler, mockService := setupCategoryHandler()
+		router := setupCategoryGin()
+		router.DELETE("/categories/:categoryId", handler.DeleteCategory)
+
+		mockService.On("GetCategoryByID", mock.Anything, categoryTestUserID, uint(1)).Return(&domain.Category{ID: 1, Type: "expense"}, nil)
+
+		req := httptest.NewRequest("DELETE", "/categories/1?replacement_id=other", http.NoBody)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "DeleteCategory", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
 }
 
 func TestCategoryHandler_GetCategoryUsage(t *testing.T) {
 	t.Run("should get category usage successfully", func(t *testing.T) {
 		handler, mockService := setupCategoryHandler()
-		router := setupGin()
+		router := setupCategoryGin()
 		router.GET("/categories/usage", handler.GetCategoryUsage)
 
 		expectedStats := []application.CategoryUsageStats{
@@ -514,20 +652,20 @@ func TestCategoryHandler_GetCategoryUsage(t *testing.T) {
 				CategoryName:     "Food",
 				CategoryType:     "expense",
 				TransactionCount: 5,
-				TotalAmount:      250.0,
-				AverageAmount:    50.0,
+				TotalAmount:      domain.NewMoney(250.0),
+				AverageAmount:    domain.NewMoney(50.0),
 			},
 			{
 				CategoryID:       2,
 				CategoryName:     "Salary",
 				CategoryType:     "income",
 				TransactionCount: 2,
-				TotalAmount:      4000.0,
-				AverageAmount:    2000.0,
+				TotalAmount:      domain.NewMoney(4000.0),
+				AverageAmount:    domain.NewMoney(2000.0),
 			},
 		}
 
-		mockService.On("GetCategoryUsageStats", uint(0)).Return(expectedStats, nil)
+		mockService.On("GetCategoryUsageStats", mock.Anything, categoryTestUserID).Return(expectedStats, nil)
 
 		req := httptest.NewRequest("GET", "/categories/usage", http.NoBody)
 		w := httptest.NewRecorder()
@@ -544,9 +682,9 @@ func TestCategoryHandler_GetCategoryUsage(t *testing.T) {
 		mockService.AssertExpectations(t)
 	})
 
-	t.Run("should get category usage for specific user", func(t *testing.T) {
+	t.Run("should ignore other users in the query", func(t *testing.T) {
 		handler, mockService := setupCategoryHandler()
-		router := setupGin()
+		router := setupCategoryGin()
 		router.GET("/categories/usage", handler.GetCategoryUsage)
 
 		expectedStats := []application.CategoryUsageStats{
@@ -555,12 +693,12 @@ func TestCategoryHandler_GetCategoryUsage(t *testing.T) {
 				CategoryName:     "Transport",
 				CategoryType:     "expense",
 				TransactionCount: 3,
-				TotalAmount:      150.0,
-				AverageAmount:    50.0,
+				TotalAmount:      domain.NewMoney(150.0),
+				AverageAmount:    domain.NewMoney(50.0),
 			},
 		}
 
-		mockService.On("GetCategoryUsageStats", uint(1)).Return(expectedStats, nil)
+		mockService.On("GetCategoryUsageStats", mock.Anything, categoryTestUserID).Return(expectedStats, nil)
 
 		req := httptest.NewRequest("GET", "/categories/usage?user_id=1", http.NoBody)
 		w := httptest.NewRecorder()
@@ -575,27 +713,24 @@ func TestCategoryHandler_GetCategoryUsage(t *testing.T) {
 		mockService.AssertExpectations(t)
 	})
 
-	t.Run("should return bad request for invalid user ID", func(t *testing.T) {
+	t.Run("should require an authenticated user", func(t *testing.T) {
 		handler, _ := setupCategoryHandler()
 		router := setupGin()
 		router.GET("/categories/usage", handler.GetCategoryUsage)
 
-		req := httptest.NewRequest("GET", "/categories/usage?user_id=invalid", http.NoBody)
+		req := httptest.NewRequest("GET", "/categories/usage", http.NoBody)
 		w := httptest.NewRecorder()
 
 		router.ServeHTTP(w, req)
 
-		assert.Equal(t, http.StatusBadRequest, w.Code)
-		var response map[string]interface{}
-		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Equal(t, "invalid user ID", response["error"])
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
 	})
 }
 
 func TestCategoryHandler_GetIncomeCategories(t *testing.T) {
 	t.Run("should get income categories successfully", func(t *testing.T) {
 		handler, mockService := setupCategoryHandler()
-		router := setupGin()
+		router := setupCategoryGin()
 		router.GET("/categories/income", handler.GetIncomeCategories)
 
 		expectedCategories := []domain.Category{
@@ -619,7 +754,7 @@ func TestCategoryHandler_GetIncomeCategories(t *testing.T) {
 			},
 		}
 
-		mockService.On("GetCategoriesByType", "income").Return(expectedCategories, nil)
+		mockService.On("GetCategoriesByType", mock.Anything, categoryTestUserID, "income").Return(expectedCategories, nil)
 
 		req := httptest.NewRequest("GET", "/categories/income", http.NoBody)
 		w := httptest.NewRecorder()
@@ -637,10 +772,10 @@ func TestCategoryHandler_GetIncomeCategories(t *testing.T) {
 
 	t.Run("should return internal server error when service fails", func(t *testing.T) {
 		handler, mockService := setupCategoryHandler()
-		router := setupGin()
+		router := setupCategoryGin()
 		router.GET("/categories/income", handler.GetIncomeCategories)
 
-		mockService.On("GetCategoriesByType", "income").Return([]domain.Category{}, errors.New("database error"))
+		mockService.On("GetCategoriesByType", mock.Anything, categoryTestUserID, "income").Return([]domain.Category{}, errors.New("database error"))
 
 		req := httptest.NewRequest("GET", "/categories/income", http.NoBody)
 		w := httptest.NewRecorder()
@@ -658,7 +793,7 @@ func TestCategoryHandler_GetIncomeCategories(t *testing.T) {
 func TestCategoryHandler_GetExpenseCategories(t *testing.T) {
 	t.Run("should get expense categories successfully", func(t *testing.T) {
 		handler, mockService := setupCategoryHandler()
-		router := setupGin()
+		router := setupCategoryGin()
 		router.GET("/categories/expense", handler.GetExpenseCategories)
 
 		expectedCategories := []domain.Category{
@@ -682,7 +817,7 @@ func TestCategoryHandler_GetExpenseCategories(t *testing.T) {
 			},
 		}
 
-		mockService.On("GetCategoriesByType", "expense").Return(expectedCategories, nil)
+		mockService.On("GetCategoriesByType", mock.Anything, categoryTestUserID, "expense").Return(expectedCategories, nil)
 
 		req := httptest.NewRequest("GET", "/categories/expense", http.NoBody)
 		w := httptest.NewRecorder()
@@ -700,10 +835,10 @@ func TestCategoryHandler_GetExpenseCategories(t *testing.T) {
 
 	t.Run("should return internal server error when service fails", func(t *testing.T) {
 		handler, mockService := setupCategoryHandler()
-		router := setupGin()
+		router := setupCategoryGin()
 		router.GET("/categories/expense", handler.GetExpenseCategories)
 
-		mockService.On("GetCategoriesByType", "expense").Return([]domain.Category{}, errors.New("database error"))
+		mockService.On("GetCategoriesByType", mock.Anything, categoryTestUserID, "expense").Return([]domain.Category{}, errors.New("database error"))
 
 		req := httptest.NewRequest("GET", "/categories/expense", http.NoBody)
 		w := httptest.NewRecorder()
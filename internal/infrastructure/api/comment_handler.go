@@ -0,0 +1,159 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CommentServiceInterface defines the interface for comments on
+// transactions, budgets and reports
+type CommentServiceInterface interface {
+	List(ctx context.Context, userID uint, targetType string, targetID uint) ([]domain.Comment, error)
+	Create(ctx context.Context, userID uint, targetType string, targetID uint, body string) (*domain.Comment, error)
+	Update(ctx context.Context, userID uint, targetType string, targetID, commentID uint, body string) (*domain.Comment, error)
+	Delete(ctx context.Context, userID uint, targetType string, targetID, commentID uint) error
+}
+
+// CommentHandler serves the comments on the records the authenticated user
+// can see, whether their own, shared with their household or a client's
+type CommentHandler struct {
+	Service CommentServiceInterface
+}
+
+func NewCommentHandler(service CommentServiceInterface) *CommentHandler {
+	return &CommentHandler{Service: service}
+}
+
+// CommentRequest is the body of new and edited comments
+type CommentRequest struct {
+	Body string `json:"body" binding:"required"`
+}
+
+// List returns the comments on a record, oldest first
+func (h *CommentHandler) List(c *gin.Context) {
+	userID, targetType, targetID, ok := parseCommentTarget(c)
+	if !ok {
+		return
+	}
+
+	comments, err := h.Service.List(c.Request.Context(), userID, targetType, targetID)
+	if err != nil {
+		respondCommentError(c, err, "Failed to retrieve comments")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"comments": comments, "count": len(comments)})
+}
+
+// Create leaves a comment on a record. Users mentioned as @email are notified.
+func (h *CommentHandler) Create(c *gin.Context) {
+	userID, targetType, targetID, ok := parseCommentTarget(c)
+	if !ok {
+		return
+	}
+
+	var req CommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	comment, err := h.Service.Create(c.Request.Context(), userID, targetType, targetID, req.Body)
+	if err != nil {
+		respondCommentError(c, err, "Failed to save comment")
+		return
+	}
+	c.JSON(http.StatusCreated, comment)
+}
+
+// Update edits one of the user's comments
+func (h *CommentHandler) Update(c *gin.Context) {
+	userID, targetType, targetID, commentID, ok := parseCommentIDs(c)
+	if !ok {
+		return
+	}
+
+	var req CommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	comment, err := h.Service.Update(c.Request.Context(), userID, targetType, targetID, commentID, req.Body)
+	if err != nil {
+		respondCommentError(c, err, "Failed to update comment")
+		return
+	}
+	c.JSON(http.StatusOK, comment)
+}
+
+// Delete removes a comment the user wrote or one on their own record
+func (h *CommentHandler) Delete(c *gin.Context) {
+	userID, targetType, targetID, commentID, ok := parseCommentIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Service.Delete(c.Request.Context(), userID, targetType, targetID, commentID); err != nil {
+		respondCommentError(c, err, "Failed to delete comment")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Comment deleted"})
+}
+
+// parseCommentTarget returns the authenticated user and the commented
+// record of the path
+func parseCommentTarget(c *gin.Context) (userID uint, targetType string, targetID uint, ok bool) {
+	userID, ok = authenticatedUserID(c)
+	if !ok {
+		respondError(c, middleware.CodeUnauthenticated, "User not authenticated")
+		return 0, "", 0, false
+	}
+	targetType = c.Param("targetType")
+	if !domain.IsValidCommentTarget(targetType) {
+		respondError(c, middleware.CodeBadRequest, domain.ErrInvalidCommentTarget.Error())
+		return 0, "", 0, false
+	}
+	id, err := strconv.ParseUint(c.Param("targetId"), 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid "+targetType+" ID")
+		return 0, "", 0, false
+	}
+	return userID, targetType, uint(id), true
+}
+
+// parseCommentIDs returns the authenticated user, the commented record and
+// the comment of the path
+func parseCommentIDs(c *gin.Context) (userID uint, targetType string, targetID, commentID uint, ok bool) {
+	userID, targetType, targetID, ok = parseCommentTarget(c)
+	if !ok {
+		return 0, "", 0, 0, false
+	}
+	id, err := strconv.ParseUint(c.Param("commentId"), 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid comment ID")
+		return 0, "", 0, 0, false
+	}
+	return userID, targetType, targetID, uint(id), true
+}
+
+// respondCommentError maps comment errors to responses
+func respondCommentError(c *gin.Context, err error, message string) {
+	switch {
+	case respondValidationError(c, err):
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "Comment or commented record not found")
+	case errors.Is(err, domain.ErrCommentForbidden):
+		respondError(c, middleware.CodeForbidden, err.Error())
+	case errors.Is(err, domain.ErrInvalidCommentTarget):
+		respondError(c, middleware.CodeBadRequest, err.Error())
+	default:
+		respondInternalError(c, message, err)
+	}
+}
@@ -0,0 +1,194 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCommentService is a mock implementation of CommentServiceInterface
+type MockCommentService struct {
+	mock.Mock
+}
+
+func (m *MockCommentService) List(ctx context.Context, userID uint, targetType string, targetID uint) ([]domain.Comment, error) {
+	args := m.Called(ctx, userID, targetType, targetID)
+	return args.Get(0).([]domain.Comment), args.Error(1)
+}
+
+func (m *MockCommentService) Create(
+	ctx context.Context, userID uint, targetType string, targetID uint, body string,
+) (*domain.Comment, error) {
+	args := m.Called(ctx, userID, targetType, targetID, body)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Comment), args.Error(1)
+}
+
+func (m *MockCommentService) Update(
+	ctx context.Context, userID uint, targetType string, targetID, commentID uint, body string,
+) (*domain.Comment, error) {
+	args := m.Called(ctx, userID, targetType, targetID, commentID, body)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Comment), args.Error(1)
+}
+
+func (m *MockCommentService) Delete(ctx context.Context, userID uint, targetType string, targetID, commentID uint) error {
+	return m.Called(ctx, userID, targetType, targetID, commentID).Error(0)
+}
+
+func setupCommentRouter(service *MockCommentService) *gin.Engine {
+	handler := NewCommentHandler(service)
+	router := setupGin()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Next()
+	})
+	router.GET("/comments/:targetType/:targetId", handler.List)
+	router.POST("/comments/:targetType/:targetId", handler.Create)
+	router.PUT("/comments/:targetType/:targetId/:commentId", handler.Update)
+	router.DELETE("/comments/:targetType/:targetId/:commentId", handler.Delete)
+	return router
+}
+
+func TestCommentHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		mockSetup      func(*MockCommentService)
+		expectedStatus int
+	}{
+		{
+			name:   "list comments",
+			method: http.MethodGet,
+			path:   "/comments/transaction/5",
+			mockSetup: func(m *MockCommentService) {
+				m.On("List", mock.Anything, uint(1), domain.CommentTargetTransaction, uint(5)).
+					Return([]domain.Comment{{ID: 1, Body: "Lunch"}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "list comments on a record the user cannot see",
+			method: http.MethodGet,
+			path:   "/comments/report/5",
+			mockSetup: func(m *MockCommentService) {
+				m.On("List", mock.Anything, uint(1), domain.CommentTargetReport, uint(5)).
+					Return([]domain.Comment(nil), domain.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "comment on an unsupported record",
+			method:         http.MethodGet,
+			path:           "/comments/account/5",
+			mockSetup:      func(m *MockCommentService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "comment on an invalid ID",
+			method:         http.MethodGet,
+			path:           "/comments/budget/abc",
+			mockSetup:      func(m *MockCommentService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "leave a comment",
+			method: http.MethodPost,
+			path:   "/comments/budget/3",
+			body:   `{"body": "@sam@example.com can we raise this?"}`,
+			mockSetup: func(m *MockCommentService) {
+				m.On("Create", mock.Anything, uint(1), domain.CommentTargetBudget, uint(3), "@sam@example.com can we raise this?").
+					Return(&domain.Comment{ID: 1, TargetType: domain.CommentTargetBudget, TargetID: 3}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "leave a comment without a body",
+			method:         http.MethodPost,
+			path:           "/comments/budget/3",
+			body:           `{}`,
+			mockSetup:      func(m *MockCommentService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "leave a comment with read-only access",
+			method: http.MethodPost,
+			path:   "/comments/budget/3",
+			body:   `{"body": "Hi"}`,
+			mockSetup: func(m *MockCommentService) {
+				m.On("Create", mock.Anything, uint(1), domain.CommentTargetBudget, uint(3), "Hi").
+					Return(nil, domain.ErrCommentForbidden)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "leave a comment that is too long",
+			method: http.MethodPost,
+			path:   "/comments/budget/3",
+			body:   `{"body": "Hi"}`,
+			mockSetup: func(m *MockCommentService) {
+				m.On("Create", mock.Anything, uint(1), domain.CommentTargetBudget, uint(3), "Hi").
+					Return(nil, &domain.ValidationError{Fields: []domain.FieldError{{Field: "body", Message: "must be at most 2000 characters"}}})
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "edit a comment",
+			method: http.MethodPut,
+			path:   "/comments/transaction/5/7",
+			body:   `{"body": "Dinner"}`,
+			mockSetup: func(m *MockCommentService) {
+				m.On("Update", mock.Anything, uint(1), domain.CommentTargetTransaction, uint(5), uint(7), "Dinner").
+					Return(&domain.Comment{ID: 7, Body: "Dinner"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "edit a comment with an invalid ID",
+			method:         http.MethodPut,
+			path:           "/comments/transaction/5/abc",
+			body:           `{"body": "Dinner"}`,
+			mockSetup:      func(m *MockCommentService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "delete a comment",
+			method: http.MethodDelete,
+			path:   "/comments/transaction/5/7",
+			mockSetup: func(m *MockCommentService) {
+				m.On("Delete", mock.Anything, uint(1), domain.CommentTargetTransaction, uint(5), uint(7)).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(MockCommentService)
+			tt.mockSetup(service)
+			router := setupCommentRouter(service)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			service.AssertExpectations(t)
+		})
+	}
+}
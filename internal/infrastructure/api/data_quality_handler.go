@@ -0,0 +1,156 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DataQualityServiceInterface defines the interface for duplicate and outlier detection
+type DataQualityServiceInterface interface {
+	Duplicates(ctx context.Context, userID uint, windowDays int) ([]domain.DuplicateGroup, error)
+	Report(ctx context.Context, userID uint, windowDays int) (*domain.DataQualityReport, error)
+	Merge(ctx context.Context, userID, keepID uint, duplicateIDs []uint) (*domain.Transaction, error)
+	Dismiss(ctx context.Context, userID uint, transactionIDs []uint) error
+}
+
+type DataQualityHandler struct {
+	Service DataQualityServiceInterface
+}
+
+func NewDataQualityHandler(service DataQualityServiceInterface) *DataQualityHandler {
+	return &DataQualityHandler{Service: service}
+}
+
+// MergeDuplicatesRequest names the transaction to keep and the copies to
+// move to the trash
+type MergeDuplicatesRequest struct {
+	KeepID       uint   `json:"keep_id"`
+	DuplicateIDs []uint `json:"duplicate_ids"`
+}
+
+// DismissDuplicatesRequest lists transactions that are not duplicates of
+// each other
+type DismissDuplicatesRequest struct {
+	TransactionIDs []uint `json:"transaction_ids"`
+}
+
+// windowDays reads the window_days query parameter, defaulting to
+// domain.DefaultDuplicateWindowDays
+func windowDays(c *gin.Context) (int, bool) {
+	raw := c.Query("window_days")
+	if raw == "" {
+		return domain.DefaultDuplicateWindowDays, true
+	}
+	days, err := strconv.Atoi(raw)
+	if err != nil {
+		respondError(c, middleware.CodeBadRequest, "Invalid window_days")
+		return 0, false
+	}
+	return days, true
+}
+
+// Duplicates returns the user's transactions that look booked more than once
+func (h *DataQualityHandler) Duplicates(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+	days, ok := windowDays(c)
+	if !ok {
+		return
+	}
+
+	groups, err := h.Service.Duplicates(c.Request.Context(), userID, days)
+	if respondValidationError(c, err) {
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to find duplicate transactions", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"duplicates": groups, "count": len(groups), "window_days": days})
+}
+
+// Report returns the duplicates, amount outliers and Benford analysis of the
+// user's transactions
+func (h *DataQualityHandler) Report(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+	days, ok := windowDays(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.Service.Report(c.Request.Context(), userID, days)
+	if respondValidationError(c, err) {
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to check data quality", err)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// Merge keeps one transaction of a duplicate group and moves the rest to the trash
+func (h *DataQualityHandler) Merge(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req MergeDuplicatesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	kept, err := h.Service.Merge(c.Request.Context(), userID, req.KeepID, req.DuplicateIDs)
+	if respondValidationError(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "Transaction not found")
+	case err != nil:
+		respondInternalError(c, "Failed to merge duplicate transactions", err)
+	default:
+		c.JSON(http.StatusOK, kept)
+	}
+}
+
+// Dismiss marks transactions flagged as duplicates as distinct
+func (h *DataQualityHandler) Dismiss(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req DismissDuplicatesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	err := h.Service.Dismiss(c.Request.Context(), userID, req.TransactionIDs)
+	if respondValidationError(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "Transaction not found")
+	case err != nil:
+		respondInternalError(c, "Failed to dismiss duplicate transactions", err)
+	default:
+		c.JSON(http.StatusOK, gin.H{"message": "Duplicates dismissed"})
+	}
+}
@@ -0,0 +1,197 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockDataQualityService is a mock implementation of DataQualityServiceInterface
+type MockDataQualityService struct {
+	mock.Mock
+}
+
+func (m *MockDataQualityService) Duplicates(ctx context.Context, userID uint, windowDays int) ([]domain.DuplicateGroup, error) {
+	args := m.Called(ctx, userID, windowDays)
+	return args.Get(0).([]domain.DuplicateGroup), args.Error(1)
+}
+
+func (m *MockDataQualityService) Report(ctx context.Context, userID uint, windowDays int) (*domain.DataQualityReport, error) {
+	args := m.Called(ctx, userID, windowDays)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.DataQualityReport), args.Error(1)
+}
+
+func (m *MockDataQualityService) Merge(ctx context.Context, userID, keepID uint, duplicateIDs []uint) (*domain.Transaction, error) {
+	args := m.Called(ctx, userID, keepID, duplicateIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Transaction), args.Error(1)
+}
+
+func (m *MockDataQualityService) Dismiss(ctx context.Context, userID uint, transactionIDs []uint) error {
+	return m.Called(ctx, userID, transactionIDs).Error(0)
+}
+
+func setupDataQualityRouter(service *MockDataQualityService, authUserID uint) *gin.Engine {
+	handler := NewDataQualityHandler(service)
+	router := setupGin()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", authUserID)
+		c.Next()
+	})
+	router.GET("/users/:userId/transactions/duplicates", handler.Duplicates)
+	router.POST("/users/:userId/transactions/duplicates/merge", handler.Merge)
+	router.POST("/users/:userId/transactions/duplicates/dismiss", handler.Dismiss)
+	router.GET("/users/:userId/transactions/data-quality", handler.Report)
+	return router
+}
+
+func TestDataQualityHandler_Duplicates(t *testing.T) {
+	groups := []domain.DuplicateGroup{{
+		Amount: domain.NewMoney(9.99), Type: domain.TransactionTypeExpense, Merchant: "Netflix",
+		Transactions: []domain.Transaction{{ID: 1}, {ID: 2}},
+	}}
+	invalid := &domain.ValidationError{Fields: []domain.FieldError{{Field: "window_days", Message: "must be between 0 and 31"}}}
+
+	tests := []struct {
+		name       string
+		query      string
+		windowDays int
+		err        error
+		wantStatus int
+	}{
+		{name: "should default the window", windowDays: domain.DefaultDuplicateWindowDays, wantStatus: http.StatusOK},
+		{name: "should use the requested window", query: "?window_days=7", windowDays: 7, wantStatus: http.StatusOK},
+		{name: "should reject windows out of range", query: "?window_days=90", windowDays: 90, err: invalid, wantStatus: http.StatusUnprocessableEntity},
+		{name: "should reject windows that are not numbers", query: "?window_days=week", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockDataQualityService)
+			router := setupDataQualityRouter(mockService, 1)
+			mockService.On("Duplicates", mock.Anything, uint(1), tt.windowDays).Return(groups, tt.err)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1/transactions/duplicates"+tt.query, http.NoBody))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusOK {
+				var response struct {
+					Duplicates []domain.DuplicateGroup `json:"duplicates"`
+					Count      int                     `json:"count"`
+					WindowDays int                     `json:"window_days"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, 1, response.Count)
+				assert.Equal(t, tt.windowDays, response.WindowDays)
+				assert.Equal(t, "Netflix", response.Duplicates[0].Merchant)
+			}
+		})
+	}
+
+	t.Run("should forbid other users' transactions", func(t *testing.T) {
+		mockService := new(MockDataQualityService)
+		router := setupDataQualityRouter(mockService, 2)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1/transactions/duplicates", http.NoBody))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		mockService.AssertNotCalled(t, "Duplicates")
+	})
+}
+
+func TestDataQualityHandler_Report(t *testing.T) {
+	mockService := new(MockDataQualityService)
+	router := setupDataQualityRouter(mockService, 1)
+	report := &domain.DataQualityReport{
+		WindowDays: 3,
+		Outliers:   []domain.AmountOutlier{{Transaction: domain.Transaction{ID: 4}, Median: domain.NewMoney(40), Ratio: 112.5}},
+		Benford:    domain.BenfordAnalysis{SampleSize: 12, Conformity: domain.BenfordInsufficientData},
+	}
+	mockService.On("Report", mock.Anything, uint(1), 3).Return(report, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1/transactions/data-quality", http.NoBody))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response domain.DataQualityReport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 112.5, response.Outliers[0].Ratio)
+	assert.Equal(t, domain.BenfordInsufficientData, response.Benford.Conformity)
+}
+
+func TestDataQualityHandler_Merge(t *testing.T) {
+	invalid := &domain.ValidationError{Fields: []domain.FieldError{{Field: "duplicate_ids", Message: "must list at least one transaction"}}}
+
+	tests := []struct {
+		name       string
+		body       string
+		result     *domain.Transaction
+		err        error
+		wantStatus int
+	}{
+		{
+			name:       "should keep one transaction",
+			body:       `{"keep_id": 1, "duplicate_ids": [2, 3]}`,
+			result:     &domain.Transaction{ID: 1},
+			wantStatus: http.StatusOK,
+		},
+		{name: "should list invalid merges", body: `{"keep_id": 1, "duplicate_ids": [2, 3]}`, err: invalid, wantStatus: http.StatusUnprocessableEntity},
+		{name: "should report unknown transactions", body: `{"keep_id": 1, "duplicate_ids": [2, 3]}`, err: domain.ErrNotFound, wantStatus: http.StatusNotFound},
+		{name: "should reject malformed bodies", body: `{"keep_id": "one"}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockDataQualityService)
+			router := setupDataQualityRouter(mockService, 1)
+			mockService.On("Merge", mock.Anything, uint(1), uint(1), []uint{2, 3}).Return(tt.result, tt.err)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/1/transactions/duplicates/merge", strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestDataQualityHandler_Dismiss(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "should dismiss the duplicates", wantStatus: http.StatusOK},
+		{name: "should report unknown transactions", err: domain.ErrNotFound, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockDataQualityService)
+			router := setupDataQualityRouter(mockService, 1)
+			mockService.On("Dismiss", mock.Anything, uint(1), []uint{4, 5}).Return(tt.err)
+
+			w := httptest.NewRecorder()
+			body := strings.NewReader(`{"transaction_ids": [4, 5]}`)
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/1/transactions/duplicates/dismiss", body))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
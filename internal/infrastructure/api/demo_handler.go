@@ -0,0 +1,33 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DemoHandler opens sessions of the read-only demo user, so visitors can try
+// the app without registering
+type DemoHandler struct {
+	Users    UserServiceInterface
+	Sessions SessionServiceInterface // Opens a session with a refresh token when set
+	UserID   uint                    // The seeded demo user
+}
+
+func NewDemoHandler(users UserServiceInterface, sessions SessionServiceInterface, userID uint) *DemoHandler {
+	return &DemoHandler{Users: users, Sessions: sessions, UserID: userID}
+}
+
+// Start logs the visitor in as the demo user
+func (h *DemoHandler) Start(c *gin.Context) {
+	user, err := h.Users.GetByID(c.Request.Context(), h.UserID)
+	if err != nil {
+		respondInternalError(c, "Failed to load the demo user", err)
+		return
+	}
+
+	response := gin.H{"user": loginUser(&user), "demo": true}
+	if issueTokens(c, h.Sessions, user.ID, response) {
+		c.JSON(http.StatusOK, response)
+	}
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDemoHandler_Start(t *testing.T) {
+	t.Run("should log in as the demo user", func(t *testing.T) {
+		users := new(MockUserService)
+		sessions := new(MockSessionService)
+		users.On("GetByID", mock.Anything, uint(9)).Return(domain.User{ID: 9, Email: "demo@go-finance-advisor.local"}, nil)
+		sessions.On("Start", mock.Anything, uint(9), mock.Anything).Return(&domain.Session{ID: 4, UserID: 9}, "refresh", nil)
+
+		router := setupGin()
+		router.POST("/auth/demo", NewDemoHandler(users, sessions, 9).Start)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/auth/demo", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, true, response["demo"])
+		assert.NotEmpty(t, response["token"])
+		assert.Equal(t, "refresh", response["refresh_token"])
+	})
+
+	t.Run("should fail when the demo user is missing", func(t *testing.T) {
+		users := new(MockUserService)
+		users.On("GetByID", mock.Anything, uint(9)).Return(nil, errors.New("record not found"))
+
+		router := setupGin()
+		router.POST("/auth/demo", NewDemoHandler(users, nil, 9).Start)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/auth/demo", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DigestServiceInterface defines the interface for digest subscription operations
+type DigestServiceInterface interface {
+	Get(ctx context.Context, userID uint) (*domain.DigestSubscription, error)
+	Subscribe(ctx context.Context, userID uint, frequency string) (*domain.DigestSubscription, error)
+	Unsubscribe(ctx context.Context, userID uint) error
+	UnsubscribeByToken(ctx context.Context, token string) error
+}
+
+type DigestHandler struct {
+	Service DigestServiceInterface
+}
+
+func NewDigestHandler(service DigestServiceInterface) *DigestHandler {
+	return &DigestHandler{Service: service}
+}
+
+// DigestRequest is the body of digest subscriptions
+type DigestRequest struct {
+	Frequency string `json:"frequency" binding:"required"`
+}
+
+// Get returns the user's digest subscription
+func (h *DigestHandler) Get(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	subscription, err := h.Service.Get(c.Request.Context(), userID)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "Not subscribed to the digest")
+	case err != nil:
+		respondInternalError(c, "Failed to retrieve digest subscription", err)
+	default:
+		c.JSON(http.StatusOK, subscription)
+	}
+}
+
+// Subscribe opts the user in to the weekly or monthly digest, or changes
+// how often they get it
+func (h *DigestHandler) Subscribe(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req DigestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	subscription, err := h.Service.Subscribe(c.Request.Context(), userID, req.Frequency)
+	if err != nil {
+		if !respondValidationError(c, err) {
+			respondInternalError(c, "Failed to subscribe to the digest", err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, subscription)
+}
+
+// Unsubscribe opts the user out of the digest
+func (h *DigestHandler) Unsubscribe(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	err := h.Service.Unsubscribe(c.Request.Context(), userID)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "Not subscribed to the digest")
+	case err != nil:
+		respondInternalError(c, "Failed to unsubscribe from the digest", err)
+	default:
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// UnsubscribeByToken handles the unsubscribe link of digest emails, which
+// works without logging in. Mail clients post to it for one-click
+// unsubscribes; following a link that was already used succeeds too.
+func (h *DigestHandler) UnsubscribeByToken(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		respondError(c, middleware.CodeBadRequest, "token is required")
+		return
+	}
+
+	err := h.Service.UnsubscribeByToken(c.Request.Context(), token)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		respondInternalError(c, "Failed to unsubscribe from the digest", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "You are unsubscribed from the digest"})
+}
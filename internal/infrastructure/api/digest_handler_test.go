@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockDigestService is a mock implementation of DigestServiceInterface
+type MockDigestService struct {
+	mock.Mock
+}
+
+func (m *MockDigestService) Get(ctx context.Context, userID uint) (*domain.DigestSubscription, error) {
+	args := m.Called(ctx, userID)
+	subscription, _ := args.Get(0).(*domain.DigestSubscription)
+	return subscription, args.Error(1)
+}
+
+func (m *MockDigestService) Subscribe(ctx context.Context, userID uint, frequency string) (*domain.DigestSubscription, error) {
+	args := m.Called(ctx, userID, frequency)
+	subscription, _ := args.Get(0).(*domain.DigestSubscription)
+	return subscription, args.Error(1)
+}
+
+func (m *MockDigestService) Unsubscribe(ctx context.Context, userID uint) error {
+	return m.Called(ctx, userID).Error(0)
+}
+
+func (m *MockDigestService) UnsubscribeByToken(ctx context.Context, token string) error {
+	return m.Called(ctx, token).Error(0)
+}
+
+func setupDigestRouter(service *MockDigestService) *gin.Engine {
+	handler := NewDigestHandler(service)
+	router := setupGin()
+	router.GET("/digest/unsubscribe", handler.UnsubscribeByToken)
+	router.POST("/digest/unsubscribe", handler.UnsubscribeByToken)
+	protected := router.Group("/")
+	protected.Use(func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Next()
+	})
+	protected.GET("/users/:userId/digest", handler.Get)
+	protected.PUT("/users/:userId/digest", handler.Subscribe)
+	protected.DELETE("/users/:userId/digest", handler.Unsubscribe)
+	return router
+}
+
+func TestDigestHandler_Subscribe(t *testing.T) {
+	service := new(MockDigestService)
+	service.On("Subscribe", mock.Anything, uint(1), domain.DigestWeekly).
+		Return(&domain.DigestSubscription{UserID: 1, Frequency: domain.DigestWeekly, UnsubscribeToken: "secret"}, nil)
+	service.On("Subscribe", mock.Anything, uint(1), "daily").
+		Return(nil, &domain.ValidationError{Fields: []domain.FieldError{{Field: "frequency", Message: "must be weekly or monthly"}}})
+	service.On("Get", mock.Anything, uint(1)).Return(nil, domain.ErrNotFound)
+	service.On("Unsubscribe", mock.Anything, uint(1)).Return(nil)
+	router := setupDigestRouter(service)
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send(http.MethodPut, "/users/1/digest", `{"frequency": "weekly"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"frequency":"weekly"`)
+	assert.NotContains(t, w.Body.String(), "secret", "the unsubscribe token is only sent by email")
+
+	assert.Equal(t, http.StatusUnprocessableEntity, send(http.MethodPut, "/users/1/digest", `{"frequency": "daily"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, send(http.MethodPut, "/users/1/digest", `{}`).Code)
+	assert.Equal(t, http.StatusForbidden, send(http.MethodPut, "/users/2/digest", `{"frequency": "weekly"}`).Code)
+	assert.Equal(t, http.StatusNotFound, send(http.MethodGet, "/users/1/digest", "").Code)
+	assert.Equal(t, http.StatusNoContent, send(http.MethodDelete, "/users/1/digest", "").Code)
+	service.AssertExpectations(t)
+}
+
+func TestDigestHandler_UnsubscribeByToken(t *testing.T) {
+	service := new(MockDigestService)
+	service.On("UnsubscribeByToken", mock.Anything, "abc").Return(nil)
+	service.On("UnsubscribeByToken", mock.Anything, "used").Return(domain.ErrNotFound)
+	router := setupDigestRouter(service)
+
+	for _, tc := range []struct {
+		method, path string
+		status       int
+	}{
+		{http.MethodGet, "/digest/unsubscribe?token=abc", http.StatusOK},
+		{http.MethodPost, "/digest/unsubscribe?token=used", http.StatusOK},
+		{http.MethodGet, "/digest/unsubscribe", http.StatusBadRequest},
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, http.NoBody))
+		assert.Equal(t, tc.status, w.Code, tc.path)
+	}
+	service.AssertExpectations(t)
+}
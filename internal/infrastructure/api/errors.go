@@ -0,0 +1,32 @@
+package api
+
+import (
+	"errors"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondError answers the request with an error of the code's status
+func respondError(c *gin.Context, code middleware.ErrorCode, message string) {
+	middleware.RespondError(c, middleware.NewError(code, message))
+}
+
+// respondInternalError answers a failed request with message. The cause is
+// logged, and only shown outside production.
+func respondInternalError(c *gin.Context, message string, err error) {
+	middleware.RespondError(c, middleware.InternalError(message, err))
+}
+
+// respondValidationError answers 422 with the fields that break business
+// rules when err is a validation error, and reports whether it was one
+func respondValidationError(c *gin.Context, err error) bool {
+	var invalid *domain.ValidationError
+	if !errors.As(err, &invalid) {
+		return false
+	}
+	middleware.RespondError(c, err)
+	return true
+}
@@ -1,6 +1,8 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -8,16 +10,30 @@ import (
 
 	"go-finance-advisor/internal/application"
 	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
 
 	"github.com/gin-gonic/gin"
 )
 
 // ExportServiceInterface defines the interface for export service
 type ExportServiceInterface interface {
-	ExportTransactions(userID uint, format domain.ExportFormat, startDate, endDate *time.Time) ([]byte, string, error)
-	ExportFinancialReport(userID uint, reportType string, year, month int, format domain.ExportFormat) ([]byte, string, error)
-	ExportAllData(userID uint, format domain.ExportFormat) ([]byte, string, error)
-	ExportBudgets(userID uint, format domain.ExportFormat) ([]byte, string, error)
+	ExportTransactions(ctx context.Context, userID uint, format domain.ExportFormat, startDate, endDate *time.Time) ([]byte, string, error)
+	ExportTransactionsWithTemplate(
+		ctx context.Context, userID, templateID uint, format domain.ExportFormat, startDate, endDate *time.Time,
+	) ([]byte, string, error)
+	ExportFinancialReport(
+		ctx context.Context, userID uint, reportType string, year, month int, format domain.ExportFormat,
+	) ([]byte, string, error)
+	ExportAllData(ctx context.Context, userID uint, format domain.ExportFormat) ([]byte, string, error)
+	ExportBudgets(ctx context.Context, userID uint, format domain.ExportFormat) ([]byte, string, error)
+	ExportAccountStatement(
+		ctx context.Context, userID, accountID uint, month time.Time, format domain.ExportFormat,
+	) ([]byte, string, error)
+	LinkExport(ctx context.Context, userID uint, fileName, contentType string, data []byte) (*domain.FileLink, error)
+	ListTemplates(ctx context.Context, userID uint) ([]domain.ExportTemplate, error)
+	CreateTemplate(ctx context.Context, userID uint, template domain.ExportTemplate) (*domain.ExportTemplate, error)
+	UpdateTemplate(ctx context.Context, userID uint, template domain.ExportTemplate) (*domain.ExportTemplate, error)
+	DeleteTemplate(ctx context.Context, userID, id uint) error
 }
 
 type ExportHandler struct {
@@ -28,23 +44,50 @@ func NewExportHandler(service *application.ExportService) *ExportHandler {
 	return &ExportHandler{Service: service}
 }
 
+// sendExport answers with the exported file, or with a signed link to it
+// when the delivery query parameter is "link"
+func (h *ExportHandler) sendExport(c *gin.Context, userID uint, format domain.ExportFormat, filename string, data []byte) {
+	if c.Query("delivery") == "link" {
+		link, err := h.Service.LinkExport(c.Request.Context(), userID, filename, format.GetContentType(), data)
+		if errors.Is(err, domain.ErrStorageUnavailable) {
+			respondError(c, middleware.CodeUnavailable, "File storage is not available")
+			return
+		}
+		if err != nil {
+			respondInternalError(c, "Failed to store the export", err)
+			return
+		}
+		c.JSON(http.StatusOK, link)
+		return
+	}
+
+	c.Header("Content-Type", format.GetContentType())
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Header("Content-Length", strconv.Itoa(len(data)))
+	c.Data(http.StatusOK, format.GetContentType(), data)
+}
+
 // ExportTransactions exports user transactions
 // @Summary Export transactions
-// @Description Export user transactions in CSV or JSON format
+// @Description Export user transactions in CSV, JSON or QIF format, optionally laid out by an export template
 // @Tags export
 // @Accept json
 // @Produce application/octet-stream
-// @Param format query string true "Export format (csv, json)"
+// @Param format query string true "Export format (csv, json, qif)"
+// @Param delivery query string false "link answers with a signed download link instead of the file"
 // @Param start_date query string false "Start date (YYYY-MM-DD)"
 // @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param template query int false "Export template ID"
 // @Success 200 {file} file "Exported file"
 // @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /api/export/transactions [get]
 func (h *ExportHandler) ExportTransactions(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, middleware.CodeUnauthenticated, "User not authenticated")
 		return
 	}
 
@@ -55,7 +98,7 @@ func (h *ExportHandler) ExportTransactions(c *gin.Context) {
 	}
 	format := domain.ExportFormat(formatStr)
 	if !format.IsValid() {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid export format"})
+		respondError(c, middleware.CodeBadRequest, "Invalid export format")
 		return
 	}
 
@@ -72,20 +115,35 @@ func (h *ExportHandler) ExportTransactions(c *gin.Context) {
 		}
 	}
 
-	// Export data
-	data, filename, err := h.Service.ExportTransactions(userID.(uint), format, startDate, endDate)
+	// Export data, laid out by the template when one is given
+	var data []byte
+	var filename string
+	var err error
+	if templateStr := c.Query("template"); templateStr != "" {
+		templateID, parseErr := strconv.ParseUint(templateStr, 10, 32)
+		if parseErr != nil {
+			respondError(c, middleware.CodeInvalidID, "Invalid template ID")
+			return
+		}
+		data, filename, err = h.Service.ExportTransactionsWithTemplate(
+			c.Request.Context(), userID.(uint), uint(templateID), format, startDate, endDate)
+	} else {
+		data, filename, err = h.Service.ExportTransactions(c.Request.Context(), userID.(uint), format, startDate, endDate)
+	}
+	if errors.Is(err, domain.ErrNotFound) {
+		respondError(c, middleware.CodeNotFound, "Export template not found")
+		return
+	}
+	if errors.Is(err, domain.ErrExportTooLarge) {
+		respondError(c, middleware.CodePlanLimit, err.Error())
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondInternalError(c, "Failed to export transactions", err)
 		return
 	}
 
-	// Set response headers
-	c.Header("Content-Type", format.GetContentType())
-	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
-	c.Header("Content-Length", strconv.Itoa(len(data)))
-
-	// Send file
-	c.Data(http.StatusOK, format.GetContentType(), data)
+	h.sendExport(c, userID.(uint), format, filename, data)
 }
 
 // ExportBudgets exports user budgets
@@ -95,6 +153,7 @@ func (h *ExportHandler) ExportTransactions(c *gin.Context) {
 // @Accept json
 // @Produce application/octet-stream
 // @Param format query string true "Export format (csv, json)"
+// @Param delivery query string false "link answers with a signed download link instead of the file"
 // @Success 200 {file} file "Exported file"
 // @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
@@ -102,7 +161,7 @@ func (h *ExportHandler) ExportTransactions(c *gin.Context) {
 func (h *ExportHandler) ExportBudgets(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, middleware.CodeUnauthenticated, "User not authenticated")
 		return
 	}
 
@@ -112,25 +171,19 @@ func (h *ExportHandler) ExportBudgets(c *gin.Context) {
 		formatStr = "csv"
 	}
 	format := domain.ExportFormat(formatStr)
-	if !format.IsValid() {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid export format"})
+	if !format.IsValid() || format == domain.ExportFormatQIF {
+		respondError(c, middleware.CodeBadRequest, "Invalid export format")
 		return
 	}
 
 	// Export data
-	data, filename, err := h.Service.ExportBudgets(userID.(uint), format)
+	data, filename, err := h.Service.ExportBudgets(c.Request.Context(), userID.(uint), format)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondInternalError(c, "Failed to export budgets", err)
 		return
 	}
 
-	// Set response headers
-	c.Header("Content-Type", format.GetContentType())
-	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
-	c.Header("Content-Length", strconv.Itoa(len(data)))
-
-	// Send file
-	c.Data(http.StatusOK, format.GetContentType(), data)
+	h.sendExport(c, userID.(uint), format, filename, data)
 }
 
 // ExportFinancialReport exports a financial report
@@ -143,6 +196,7 @@ func (h *ExportHandler) ExportBudgets(c *gin.Context) {
 // @Param year query int true "Year"
 // @Param month query int false "Month (required for monthly reports)"
 // @Param format query string true "Export format (csv, json)"
+// @Param delivery query string false "link answers with a signed download link instead of the file"
 // @Success 200 {file} file "Exported file"
 // @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
@@ -150,25 +204,25 @@ func (h *ExportHandler) ExportBudgets(c *gin.Context) {
 func (h *ExportHandler) ExportFinancialReport(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, middleware.CodeUnauthenticated, "User not authenticated")
 		return
 	}
 
 	// Parse parameters
 	reportType := c.Query("type")
 	if reportType == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Report type is required"})
+		respondError(c, middleware.CodeBadRequest, "Report type is required")
 		return
 	}
 
 	yearStr := c.Query("year")
 	if yearStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Year is required"})
+		respondError(c, middleware.CodeBadRequest, "Year is required")
 		return
 	}
 	year, err := strconv.Atoi(yearStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid year"})
+		respondError(c, middleware.CodeBadRequest, "Invalid year")
 		return
 	}
 
@@ -176,13 +230,13 @@ func (h *ExportHandler) ExportFinancialReport(c *gin.Context) {
 	if monthStr := c.Query("month"); monthStr != "" {
 		month, err = strconv.Atoi(monthStr)
 		if err != nil || month < 1 || month > 12 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid month"})
+			respondError(c, middleware.CodeBadRequest, "Invalid month")
 			return
 		}
 	}
 
 	if reportType == "monthly" && month == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Month is required for monthly reports"})
+		respondError(c, middleware.CodeBadRequest, "Month is required for monthly reports")
 		return
 	}
 
@@ -192,25 +246,73 @@ func (h *ExportHandler) ExportFinancialReport(c *gin.Context) {
 		formatStr = "json"
 	}
 	format := domain.ExportFormat(formatStr)
-	if !format.IsValid() {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid export format"})
+	if !format.IsValid() || format == domain.ExportFormatQIF {
+		respondError(c, middleware.CodeBadRequest, "Invalid export format")
 		return
 	}
 
 	// Export data
-	data, filename, err := h.Service.ExportFinancialReport(userID.(uint), reportType, year, month, format)
+	data, filename, err := h.Service.ExportFinancialReport(c.Request.Context(), userID.(uint), reportType, year, month, format)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondInternalError(c, "Failed to export report", err)
 		return
 	}
 
-	// Set response headers
-	c.Header("Content-Type", format.GetContentType())
-	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
-	c.Header("Content-Length", strconv.Itoa(len(data)))
+	h.sendExport(c, userID.(uint), format, filename, data)
+}
 
-	// Send file
-	c.Data(http.StatusOK, format.GetContentType(), data)
+// ExportAccountStatement exports the monthly statement of one of the user's accounts
+// @Summary Export account statement
+// @Description Export an account's monthly statement in CSV or PDF format
+// @Tags export
+// @Produce application/octet-stream
+// @Param accountId path int true "Account ID"
+// @Param month query string false "Month (YYYY-MM), defaults to the current month"
+// @Param format query string false "Export format (csv, pdf)"
+// @Param delivery query string false "link answers with a signed download link instead of the file"
+// @Success 200 {file} file "Exported file"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/export/accounts/{accountId}/statement [get]
+func (h *ExportHandler) ExportAccountStatement(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		respondError(c, middleware.CodeUnauthenticated, "User not authenticated")
+		return
+	}
+	accountID, ok := accountIDParam(c)
+	if !ok {
+		return
+	}
+	month, ok := statementMonthQuery(c)
+	if !ok {
+		return
+	}
+
+	// Parse format
+	formatStr := c.Query("format")
+	if formatStr == "" {
+		formatStr = "csv"
+	}
+	format := domain.ExportFormat(formatStr)
+	if format != domain.ExportFormatCSV && format != domain.ExportFormatPDF {
+		respondError(c, middleware.CodeBadRequest, "Invalid export format")
+		return
+	}
+
+	// Export data
+	data, filename, err := h.Service.ExportAccountStatement(c.Request.Context(), userID.(uint), accountID, month, format)
+	if errors.Is(err, domain.ErrNotFound) {
+		respondError(c, middleware.CodeNotFound, "Account not found")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to export account statement", err)
+		return
+	}
+
+	h.sendExport(c, userID.(uint), format, filename, data)
 }
 
 // ExportAllData exports all user financial data
@@ -220,14 +322,16 @@ func (h *ExportHandler) ExportFinancialReport(c *gin.Context) {
 // @Accept json
 // @Produce application/octet-stream
 // @Param format query string false "Export format (json only)"
+// @Param delivery query string false "link answers with a signed download link instead of the file"
 // @Success 200 {file} file "Exported file"
 // @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /api/export/all [get]
 func (h *ExportHandler) ExportAllData(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		respondError(c, middleware.CodeUnauthenticated, "User not authenticated")
 		return
 	}
 
@@ -238,24 +342,22 @@ func (h *ExportHandler) ExportAllData(c *gin.Context) {
 	}
 	format := domain.ExportFormat(formatStr)
 	if format != domain.ExportFormatJSON {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Only JSON format is supported for all data export"})
+		respondError(c, middleware.CodeBadRequest, "Only JSON format is supported for all data export")
 		return
 	}
 
 	// Export data
-	data, filename, err := h.Service.ExportAllData(userID.(uint), format)
+	data, filename, err := h.Service.ExportAllData(c.Request.Context(), userID.(uint), format)
+	if errors.Is(err, domain.ErrExportTooLarge) {
+		respondError(c, middleware.CodePlanLimit, err.Error())
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondInternalError(c, "Failed to export data", err)
 		return
 	}
 
-	// Set response headers
-	c.Header("Content-Type", format.GetContentType())
-	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
-	c.Header("Content-Length", strconv.Itoa(len(data)))
-
-	// Send file
-	c.Data(http.StatusOK, format.GetContentType(), data)
+	h.sendExport(c, userID.(uint), format, filename, data)
 }
 
 // GetExportFormats returns available export formats
@@ -283,13 +385,27 @@ func (h *ExportHandler) GetExportFormats(c *gin.Context) {
 				"mime_type":   "application/json",
 				"extension":   ".json",
 			},
+			{
+				"value":       "qif",
+				"label":       "QIF",
+				"description": "Quicken Interchange Format for legacy finance tools",
+				"mime_type":   "application/qif",
+				"extension":   ".qif",
+			},
+			{
+				"value":       "pdf",
+				"label":       "PDF",
+				"description": "Printable document, account statements only",
+				"mime_type":   "application/pdf",
+				"extension":   ".pdf",
+			},
 		},
 		"data_types": []map[string]interface{}{
 			{
 				"value":               "transactions",
 				"label":               "Transactions",
 				"description":         "Export transaction data",
-				"supported_formats":   []string{"csv", "json"},
+				"supported_formats":   []string{"csv", "json", "qif"},
 				"supports_date_range": true,
 			},
 			{
@@ -313,8 +429,161 @@ func (h *ExportHandler) GetExportFormats(c *gin.Context) {
 				"supported_formats":   []string{"json"},
 				"supports_date_range": false,
 			},
+			{
+				"value":               "statements",
+				"label":               "Account Statements",
+				"description":         "Export an account's monthly statement",
+				"supported_formats":   []string{"csv", "pdf"},
+				"supports_date_range": false,
+			},
 		},
 	}
 
 	c.JSON(http.StatusOK, formats)
 }
+
+// ExportTemplateRequest is the body of export template requests
+type ExportTemplateRequest struct {
+	Name            string   `json:"name"`
+	Columns         []string `json:"columns"`
+	DateFormat      string   `json:"date_format"`
+	Delimiter       string   `json:"delimiter"`
+	FilenamePattern string   `json:"filename_pattern"`
+}
+
+func (r ExportTemplateRequest) template() domain.ExportTemplate {
+	return domain.ExportTemplate{
+		Name:            r.Name,
+		Columns:         r.Columns,
+		DateFormat:      r.DateFormat,
+		Delimiter:       r.Delimiter,
+		FilenamePattern: r.FilenamePattern,
+	}
+}
+
+// ListTemplates returns the user's export templates
+// @Summary List export templates
+// @Tags export
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/export/templates [get]
+func (h *ExportHandler) ListTemplates(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		respondError(c, middleware.CodeUnauthenticated, "User not authenticated")
+		return
+	}
+
+	templates, err := h.Service.ListTemplates(c.Request.Context(), userID.(uint))
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve export templates", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"templates": templates, "columns": domain.ExportColumns, "count": len(templates)})
+}
+
+// CreateTemplate saves a new export template
+// @Summary Create export template
+// @Tags export
+// @Accept json
+// @Produce json
+// @Param template body ExportTemplateRequest true "Template"
+// @Success 201 {object} domain.ExportTemplate
+// @Failure 422 {object} ErrorResponse
+// @Router /api/export/templates [post]
+func (h *ExportHandler) CreateTemplate(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		respondError(c, middleware.CodeUnauthenticated, "User not authenticated")
+		return
+	}
+
+	var req ExportTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	template, err := h.Service.CreateTemplate(c.Request.Context(), userID.(uint), req.template())
+	if respondValidationError(c, err) {
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to save export template", err)
+		return
+	}
+	c.JSON(http.StatusCreated, template)
+}
+
+// UpdateTemplate replaces an export template's settings
+// @Summary Update export template
+// @Tags export
+// @Accept json
+// @Produce json
+// @Param templateId path int true "Template ID"
+// @Param template body ExportTemplateRequest true "Template"
+// @Success 200 {object} domain.ExportTemplate
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /api/export/templates/{templateId} [put]
+func (h *ExportHandler) UpdateTemplate(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		respondError(c, middleware.CodeUnauthenticated, "User not authenticated")
+		return
+	}
+	templateID, err := strconv.ParseUint(c.Param("templateId"), 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid template ID")
+		return
+	}
+
+	var req ExportTemplateRequest
+	if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
+		respondError(c, middleware.CodeInvalidBody, bindErr.Error())
+		return
+	}
+
+	update := req.template()
+	update.ID = uint(templateID)
+	template, err := h.Service.UpdateTemplate(c.Request.Context(), userID.(uint), update)
+	switch {
+	case respondValidationError(c, err):
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "Export template not found")
+	case err != nil:
+		respondInternalError(c, "Failed to save export template", err)
+	default:
+		c.JSON(http.StatusOK, template)
+	}
+}
+
+// DeleteTemplate removes an export template
+// @Summary Delete export template
+// @Tags export
+// @Param templateId path int true "Template ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} ErrorResponse
+// @Router /api/export/templates/{templateId} [delete]
+func (h *ExportHandler) DeleteTemplate(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		respondError(c, middleware.CodeUnauthenticated, "User not authenticated")
+		return
+	}
+	templateID, err := strconv.ParseUint(c.Param("templateId"), 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid template ID")
+		return
+	}
+
+	err = h.Service.DeleteTemplate(c.Request.Context(), userID.(uint), uint(templateID))
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "Export template not found")
+	case err != nil:
+		respondInternalError(c, "Failed to delete export template", err)
+	default:
+		c.JSON(http.StatusOK, gin.H{"message": "Export template deleted"})
+	}
+}
@@ -1,10 +1,13 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -21,29 +24,83 @@ type MockExportService struct {
 }
 
 func (m *MockExportService) ExportTransactions(
-	userID uint, format domain.ExportFormat, startDate, endDate *time.Time,
+	ctx context.Context, userID uint, format domain.ExportFormat, startDate, endDate *time.Time,
 ) (data []byte, filename string, err error) {
-	args := m.Called(userID, format, startDate, endDate)
+	args := m.Called(ctx, userID, format, startDate, endDate)
 	return args.Get(0).([]byte), args.String(1), args.Error(2)
 }
 
-func (m *MockExportService) ExportBudgets(userID uint, format domain.ExportFormat) (data []byte, filename string, err error) {
-	args := m.Called(userID, format)
+func (m *MockExportService) ExportBudgets(ctx context.Context, userID uint, format domain.ExportFormat) (data []byte, filename string, err error) {
+	args := m.Called(ctx, userID, format)
 	return args.Get(0).([]byte), args.String(1), args.Error(2)
 }
 
 func (m *MockExportService) ExportFinancialReport(
-	userID uint, reportType string, year, month int, format domain.ExportFormat,
+	ctx context.Context, userID uint, reportType string, year, month int, format domain.ExportFormat,
 ) (data []byte, filename string, err error) {
-	args := m.Called(userID, reportType, year, month, format)
+	args := m.Called(ctx, userID, reportType, year, month, format)
 	return args.Get(0).([]byte), args.String(1), args.Error(2)
 }
 
-func (m *MockExportService) ExportAllData(userID uint, format domain.ExportFormat) (data []byte, filename string, err error) {
-	args := m.Called(userID, format)
+func (m *MockExportService) ExportAllData(ctx context.Context, userID uint, format domain.ExportFormat) (data []byte, filename string, err error) {
+	args := m.Called(ctx, userID, format)
 	return args.Get(0).([]byte), args.String(1), args.Error(2)
 }
 
+func (m *MockExportService) ExportAccountStatement(
+	ctx context.Context, userID, accountID uint, month time.Time, format domain.ExportFormat,
+) (data []byte, filename string, err error) {
+	args := m.Called(ctx, userID, accountID, month, format)
+	return args.Get(0).([]byte), args.String(1), args.Error(2)
+}
+
+func (m *MockExportService) ExportTransactionsWithTemplate(
+	ctx context.Context, userID, templateID uint, format domain.ExportFormat, startDate, endDate *time.Time,
+) (data []byte, filename string, err error) {
+	args := m.Called(ctx, userID, templateID, format, startDate, endDate)
+	return args.Get(0).([]byte), args.String(1), args.Error(2)
+}
+
+func (m *MockExportService) LinkExport(
+	ctx context.Context, userID uint, fileName, contentType string, data []byte,
+) (*domain.FileLink, error) {
+	args := m.Called(ctx, userID, fileName, contentType, data)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FileLink), args.Error(1)
+}
+
+func (m *MockExportService) ListTemplates(ctx context.Context, userID uint) ([]domain.ExportTemplate, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.ExportTemplate), args.Error(1)
+}
+
+func (m *MockExportService) CreateTemplate(
+	ctx context.Context, userID uint, template domain.ExportTemplate,
+) (*domain.ExportTemplate, error) {
+	args := m.Called(ctx, userID, template)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ExportTemplate), args.Error(1)
+}
+
+func (m *MockExportService) UpdateTemplate(
+	ctx context.Context, userID uint, template domain.ExportTemplate,
+) (*domain.ExportTemplate, error) {
+	args := m.Called(ctx, userID, template)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ExportTemplate), args.Error(1)
+}
+
+func (m *MockExportService) DeleteTemplate(ctx context.Context, userID, id uint) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
 func setupExportHandler() (*ExportHandler, *MockExportService) {
 	mockService := new(MockExportService)
 	handler := &ExportHandler{Service: mockService}
@@ -58,7 +115,7 @@ func TestExportHandler_ExportTransactions(t *testing.T) {
 		expectedData := []byte("transaction,amount\nTest,100.00")
 		expectedFilename := "transactions.csv"
 
-		mockService.On("ExportTransactions", uint(1), domain.ExportFormatCSV, (*time.Time)(nil), (*time.Time)(nil)).
+		mockService.On("ExportTransactions", mock.Anything, uint(1), domain.ExportFormatCSV, (*time.Time)(nil), (*time.Time)(nil)).
 			Return(expectedData, expectedFilename, nil)
 
 		w := httptest.NewRecorder()
@@ -75,6 +132,30 @@ func TestExportHandler_ExportTransactions(t *testing.T) {
 		mockService.AssertExpectations(t)
 	})
 
+	t.Run("answers with a download link when asked to", func(t *testing.T) {
+		handler, mockService := setupExportHandler()
+		data := []byte("transaction,amount\nTest,100.00")
+		mockService.On("ExportTransactions", mock.Anything, uint(1), domain.ExportFormatCSV, (*time.Time)(nil), (*time.Time)(nil)).
+			Return(data, "transactions.csv", nil)
+		mockService.On("LinkExport", mock.Anything, uint(1), "transactions.csv", "text/csv", data).
+			Return(&domain.FileLink{URL: "https://files.example/exports/1/transactions.csv", FileName: "transactions.csv"}, nil).Once()
+		mockService.On("LinkExport", mock.Anything, uint(1), "transactions.csv", "text/csv", data).
+			Return(nil, domain.ErrStorageUnavailable).Once()
+
+		for _, wantStatus := range []int{http.StatusOK, http.StatusServiceUnavailable} {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Set("userID", uint(1))
+			c.Request = httptest.NewRequest("GET", "/export/transactions?delivery=link", http.NoBody)
+
+			handler.ExportTransactions(c)
+
+			assert.Equal(t, wantStatus, w.Code)
+			assert.Empty(t, w.Header().Get("Content-Disposition"))
+		}
+		mockService.AssertExpectations(t)
+	})
+
 	t.Run("successful export with JSON format and date range", func(t *testing.T) {
 		handler, mockService := setupExportHandler()
 		expectedData := []byte(`{"transactions":[{"id":1,"amount":100.00}]}`)
@@ -82,7 +163,7 @@ func TestExportHandler_ExportTransactions(t *testing.T) {
 		startDate := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
 		endDate := time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC)
 
-		mockService.On("ExportTransactions", uint(1), domain.ExportFormatJSON, &startDate, &endDate).Return(expectedData, expectedFilename, nil)
+		mockService.On("ExportTransactions", mock.Anything, uint(1), domain.ExportFormatJSON, &startDate, &endDate).Return(expectedData, expectedFilename, nil)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -98,6 +179,70 @@ func TestExportHandler_ExportTransactions(t *testing.T) {
 		mockService.AssertExpectations(t)
 	})
 
+	t.Run("successful QIF export with a template", func(t *testing.T) {
+		handler, mockService := setupExportHandler()
+		expectedData := []byte("!Type:Bank\nD01/15/2024\nT-50.00\n^\n")
+		mockService.On("ExportTransactionsWithTemplate", mock.Anything, uint(1), uint(3), domain.ExportFormatQIF, (*time.Time)(nil), (*time.Time)(nil)).
+			Return(expectedData, "ledger.qif", nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", uint(1))
+		c.Request = httptest.NewRequest("GET", "/export/transactions?format=qif&template=3", http.NoBody)
+
+		handler.ExportTransactions(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/qif", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Header().Get("Content-Disposition"), "ledger.qif")
+		assert.Equal(t, expectedData, w.Body.Bytes())
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("unknown template", func(t *testing.T) {
+		handler, mockService := setupExportHandler()
+		mockService.On("ExportTransactionsWithTemplate", mock.Anything, uint(1), uint(9), domain.ExportFormatCSV, (*time.Time)(nil), (*time.Time)(nil)).
+			Return([]byte(nil), "", domain.ErrNotFound)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", uint(1))
+		c.Request = httptest.NewRequest("GET", "/export/transactions?template=9", http.NoBody)
+
+		handler.ExportTransactions(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("export larger than the plan allows", func(t *testing.T) {
+		handler, mockService := setupExportHandler()
+		mockService.On("ExportTransactions", mock.Anything, uint(1), domain.ExportFormatCSV, (*time.Time)(nil), (*time.Time)(nil)).
+			Return([]byte(nil), "", fmt.Errorf("%w: the free plan exports at most 1000 transactions", domain.ErrExportTooLarge))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", uint(1))
+		c.Request = httptest.NewRequest("GET", "/export/transactions", http.NoBody)
+
+		handler.ExportTransactions(c)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), "at most 1000 transactions")
+	})
+
+	t.Run("invalid template ID", func(t *testing.T) {
+		handler, _ := setupExportHandler()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", uint(1))
+		c.Request = httptest.NewRequest("GET", "/export/transactions?template=abc", http.NoBody)
+
+		handler.ExportTransactions(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
 	t.Run("user not authenticated", func(t *testing.T) {
 		handler, _ := setupExportHandler()
 
@@ -133,7 +278,7 @@ func TestExportHandler_ExportTransactions(t *testing.T) {
 
 	t.Run("service error", func(t *testing.T) {
 		handler, mockService := setupExportHandler()
-		mockService.On("ExportTransactions", uint(1), domain.ExportFormatCSV, (*time.Time)(nil), (*time.Time)(nil)).
+		mockService.On("ExportTransactions", mock.Anything, uint(1), domain.ExportFormatCSV, (*time.Time)(nil), (*time.Time)(nil)).
 			Return([]byte{}, "", errors.New("export failed"))
 
 		w := httptest.NewRecorder()
@@ -147,7 +292,7 @@ func TestExportHandler_ExportTransactions(t *testing.T) {
 		var response map[string]string
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.Equal(t, "export failed", response["error"])
+		assert.Equal(t, "Failed to export transactions", response["error"])
 		mockService.AssertExpectations(t)
 	})
 }
@@ -160,7 +305,7 @@ func TestExportHandler_ExportBudgets(t *testing.T) {
 		expectedData := []byte("budget,amount\nFood,500.00")
 		expectedFilename := "budgets.csv"
 
-		mockService.On("ExportBudgets", uint(1), domain.ExportFormatCSV).Return(expectedData, expectedFilename, nil)
+		mockService.On("ExportBudgets", mock.Anything, uint(1), domain.ExportFormatCSV).Return(expectedData, expectedFilename, nil)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -181,7 +326,7 @@ func TestExportHandler_ExportBudgets(t *testing.T) {
 		expectedData := []byte(`{"budgets":[{"id":1,"amount":500.00}]}`)
 		expectedFilename := "budgets.json"
 
-		mockService.On("ExportBudgets", uint(1), domain.ExportFormatJSON).Return(expectedData, expectedFilename, nil)
+		mockService.On("ExportBudgets", mock.Anything, uint(1), domain.ExportFormatJSON).Return(expectedData, expectedFilename, nil)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -232,7 +377,7 @@ func TestExportHandler_ExportBudgets(t *testing.T) {
 
 	t.Run("service error", func(t *testing.T) {
 		handler, mockService := setupExportHandler()
-		mockService.On("ExportBudgets", uint(1), domain.ExportFormatCSV).Return([]byte{}, "", errors.New("export failed"))
+		mockService.On("ExportBudgets", mock.Anything, uint(1), domain.ExportFormatCSV).Return([]byte{}, "", errors.New("export failed"))
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -245,7 +390,7 @@ func TestExportHandler_ExportBudgets(t *testing.T) {
 		var response map[string]string
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.Equal(t, "export failed", response["error"])
+		assert.Equal(t, "Failed to export budgets", response["error"])
 		mockService.AssertExpectations(t)
 	})
 }
@@ -258,7 +403,7 @@ func TestExportHandler_ExportFinancialReport(t *testing.T) {
 		expectedData := []byte(`{"report":{"type":"monthly","year":2023,"month":6}}`)
 		expectedFilename := "monthly_report_2023_06.json"
 
-		mockService.On("ExportFinancialReport", uint(1), "monthly", 2023, 6, domain.ExportFormatJSON).Return(expectedData, expectedFilename, nil)
+		mockService.On("ExportFinancialReport", mock.Anything, uint(1), "monthly", 2023, 6, domain.ExportFormatJSON).Return(expectedData, expectedFilename, nil)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -279,7 +424,7 @@ func TestExportHandler_ExportFinancialReport(t *testing.T) {
 		expectedData := []byte(`{"report":{"type":"yearly","year":2023}}`)
 		expectedFilename := "yearly_report_2023.json"
 
-		mockService.On("ExportFinancialReport", uint(1), "yearly", 2023, 0, domain.ExportFormatJSON).Return(expectedData, expectedFilename, nil)
+		mockService.On("ExportFinancialReport", mock.Anything, uint(1), "yearly", 2023, 0, domain.ExportFormatJSON).Return(expectedData, expectedFilename, nil)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -415,7 +560,7 @@ func TestExportHandler_ExportFinancialReport(t *testing.T) {
 
 	t.Run("service error", func(t *testing.T) {
 		handler, mockService := setupExportHandler()
-		mockService.On("ExportFinancialReport", uint(1), "monthly", 2023, 6, domain.ExportFormatJSON).
+		mockService.On("ExportFinancialReport", mock.Anything, uint(1), "monthly", 2023, 6, domain.ExportFormatJSON).
 			Return([]byte{}, "", errors.New("export failed"))
 
 		w := httptest.NewRecorder()
@@ -429,7 +574,7 @@ func TestExportHandler_ExportFinancialReport(t *testing.T) {
 		var response map[string]string
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.Equal(t, "export failed", response["error"])
+		assert.Equal(t, "Failed to export report", response["error"])
 		mockService.AssertExpectations(t)
 	})
 }
@@ -442,7 +587,7 @@ func TestExportHandler_ExportAllData(t *testing.T) {
 		expectedData := []byte(`{"user_data":{"transactions":[],"budgets":[],"categories":[]}}`)
 		expectedFilename := "all_data.json"
 
-		mockService.On("ExportAllData", uint(1), domain.ExportFormatJSON).Return(expectedData, expectedFilename, nil)
+		mockService.On("ExportAllData", mock.Anything, uint(1), domain.ExportFormatJSON).Return(expectedData, expectedFilename, nil)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -463,7 +608,7 @@ func TestExportHandler_ExportAllData(t *testing.T) {
 		expectedData := []byte(`{"user_data":{"transactions":[],"budgets":[],"categories":[]}}`)
 		expectedFilename := "all_data.json"
 
-		mockService.On("ExportAllData", uint(1), domain.ExportFormatJSON).Return(expectedData, expectedFilename, nil)
+		mockService.On("ExportAllData", mock.Anything, uint(1), domain.ExportFormatJSON).Return(expectedData, expectedFilename, nil)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -514,7 +659,7 @@ func TestExportHandler_ExportAllData(t *testing.T) {
 
 	t.Run("service error", func(t *testing.T) {
 		handler, mockService := setupExportHandler()
-		mockService.On("ExportAllData", uint(1), domain.ExportFormatJSON).Return([]byte{}, "", errors.New("export failed"))
+		mockService.On("ExportAllData", mock.Anything, uint(1), domain.ExportFormatJSON).Return([]byte{}, "", errors.New("export failed"))
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -527,7 +672,61 @@ func TestExportHandler_ExportAllData(t *testing.T) {
 		var response map[string]string
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.Equal(t, "export failed", response["error"])
+		assert.Equal(t, "Failed to export data", response["error"])
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestExportHandler_ExportAccountStatement(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	june := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	export := func(handler *ExportHandler, accountID, query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", uint(1))
+		c.Params = gin.Params{{Key: "accountId", Value: accountID}}
+		c.Request = httptest.NewRequest("GET", "/export/accounts/"+accountID+"/statement"+query, http.NoBody)
+		handler.ExportAccountStatement(c)
+		return w
+	}
+
+	t.Run("successful PDF export", func(t *testing.T) {
+		handler, mockService := setupExportHandler()
+		mockService.On("ExportAccountStatement", mock.Anything, uint(1), uint(3), june, domain.ExportFormatPDF).
+			Return([]byte("%PDF-1.4"), "statement_3_2024-06.pdf", nil)
+
+		w := export(handler, "3", "?month=2024-06&format=pdf")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Header().Get("Content-Disposition"), "statement_3_2024-06.pdf")
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("formats other than CSV and PDF", func(t *testing.T) {
+		handler, _ := setupExportHandler()
+
+		w := export(handler, "3", "?month=2024-06&format=json")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("malformed month", func(t *testing.T) {
+		handler, _ := setupExportHandler()
+
+		w := export(handler, "3", "?month=June")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("another user's account", func(t *testing.T) {
+		handler, mockService := setupExportHandler()
+		mockService.On("ExportAccountStatement", mock.Anything, uint(1), uint(4), june, domain.ExportFormatCSV).
+			Return([]byte(nil), "", domain.ErrNotFound)
+
+		w := export(handler, "4", "?month=2024-06")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
 		mockService.AssertExpectations(t)
 	})
 }
@@ -552,12 +751,12 @@ func TestExportHandler_GetExportFormats(t *testing.T) {
 		// Check formats
 		formats, ok := response["formats"].([]interface{})
 		assert.True(t, ok)
-		assert.Len(t, formats, 2)
+		assert.Len(t, formats, 4)
 
 		// Check data types
 		dataTypes, ok := response["data_types"].([]interface{})
 		assert.True(t, ok)
-		assert.Len(t, dataTypes, 4)
+		assert.Len(t, dataTypes, 5)
 
 		// Verify CSV format
 		csvFormat := formats[0].(map[string]interface{})
@@ -570,6 +769,8 @@ func TestExportHandler_GetExportFormats(t *testing.T) {
 		assert.Equal(t, "json", jsonFormat["value"])
 		assert.Equal(t, "JSON", jsonFormat["label"])
 		assert.Equal(t, "application/json", jsonFormat["mime_type"])
+		assert.Equal(t, "qif", formats[2].(map[string]interface{})["value"])
+		assert.Equal(t, "pdf", formats[3].(map[string]interface{})["value"])
 
 		// Verify transactions data type
 		transactionsType := dataTypes[0].(map[string]interface{})
@@ -585,5 +786,84 @@ func TestExportHandler_GetExportFormats(t *testing.T) {
 		supportedFormats := allDataType["supported_formats"].([]interface{})
 		assert.Len(t, supportedFormats, 1)
 		assert.Equal(t, "json", supportedFormats[0])
+		assert.Equal(t, "statements", dataTypes[4].(map[string]interface{})["value"])
+	})
+}
+
+func TestExportHandler_Templates(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := func(handler *ExportHandler) *gin.Engine {
+		r := setupGin()
+		r.Use(func(c *gin.Context) { c.Set("userID", uint(1)) })
+		r.GET("/export/templates", handler.ListTemplates)
+		r.POST("/export/templates", handler.CreateTemplate)
+		r.PUT("/export/templates/:templateId", handler.UpdateTemplate)
+		r.DELETE("/export/templates/:templateId", handler.DeleteTemplate)
+		return r
+	}
+
+	t.Run("lists the templates with the available columns", func(t *testing.T) {
+		handler, mockService := setupExportHandler()
+		mockService.On("ListTemplates", mock.Anything, uint(1)).
+			Return([]domain.ExportTemplate{{ID: 1, Name: "Ledger", Columns: []string{"date", "amount"}}}, nil)
+
+		w := httptest.NewRecorder()
+		router(handler).ServeHTTP(w, httptest.NewRequest("GET", "/export/templates", http.NoBody))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			Templates []domain.ExportTemplate `json:"templates"`
+			Columns   []string                `json:"columns"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response.Templates, 1)
+		assert.Equal(t, domain.ExportColumns, response.Columns)
+	})
+
+	t.Run("creates a template", func(t *testing.T) {
+		handler, mockService := setupExportHandler()
+		template := domain.ExportTemplate{Name: "Ledger", Columns: []string{"date", "amount"}, Delimiter: ";"}
+		mockService.On("CreateTemplate", mock.Anything, uint(1), template).Return(&domain.ExportTemplate{ID: 2, Name: "Ledger"}, nil)
+
+		body := `{"name":"Ledger","columns":["date","amount"],"delimiter":";"}`
+		w := httptest.NewRecorder()
+		router(handler).ServeHTTP(w, httptest.NewRequest("POST", "/export/templates", strings.NewReader(body)))
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("rejects invalid templates", func(t *testing.T) {
+		handler, mockService := setupExportHandler()
+		mockService.On("CreateTemplate", mock.Anything, uint(1), mock.Anything).
+			Return(nil, &domain.ValidationError{Fields: []domain.FieldError{{Field: "columns[0]", Message: "must be one of id"}}})
+
+		w := httptest.NewRecorder()
+		router(handler).ServeHTTP(w, httptest.NewRequest("POST", "/export/templates", strings.NewReader(`{"name":"x","columns":["iban"]}`)))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+
+	t.Run("updates only existing templates", func(t *testing.T) {
+		handler, mockService := setupExportHandler()
+		mockService.On("UpdateTemplate", mock.Anything, uint(1), domain.ExportTemplate{ID: 4, Name: "Ledger", Columns: []string{"date"}}).
+			Return(nil, domain.ErrNotFound)
+
+		w := httptest.NewRecorder()
+		router(handler).ServeHTTP(w, httptest.NewRequest("PUT", "/export/templates/4", strings.NewReader(`{"name":"Ledger","columns":["date"]}`)))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("deletes a template", func(t *testing.T) {
+		handler, mockService := setupExportHandler()
+		mockService.On("DeleteTemplate", mock.Anything, uint(1), uint(4)).Return(nil)
+
+		w := httptest.NewRecorder()
+		router(handler).ServeHTTP(w, httptest.NewRequest("DELETE", "/export/templates/4", http.NoBody))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
 	})
 }
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SignedFileStorage defines the interface for storage that serves its own
+// signed download links, such as pkg.LocalStorage
+type SignedFileStorage interface {
+	Verify(key, expires, signature string) error
+	Get(ctx context.Context, key string) ([]byte, error)
+}
+
+type FileHandler struct {
+	Storage SignedFileStorage
+}
+
+func NewFileHandler(storage SignedFileStorage) *FileHandler {
+	return &FileHandler{Storage: storage}
+}
+
+// Download serves the stored file of a signed download link. The link's
+// signature stands in for authentication, so it works in plain browser
+// downloads until it expires.
+func (h *FileHandler) Download(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	if err := h.Storage.Verify(key, c.Query("expires"), c.Query("signature")); err != nil {
+		respondError(c, middleware.CodeForbidden, "Download link is invalid or has expired")
+		return
+	}
+
+	data, err := h.Storage.Get(c.Request.Context(), key)
+	if errors.Is(err, domain.ErrNotFound) {
+		respondError(c, middleware.CodeNotFound, "File not found")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to read the file", err)
+		return
+	}
+
+	// Stored file names start with the time they were stored at
+	name := path.Base(key)
+	if _, original, found := strings.Cut(name, "_"); found {
+		name = original
+	}
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name}))
+	c.Header("Cache-Control", "private, no-store")
+	c.Data(http.StatusOK, contentType, data)
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/pkg"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileHandler_Download(t *testing.T) {
+	storage := pkg.NewLocalStorage(t.TempDir(), "/files", []byte("secret"))
+	ctx := context.Background()
+	require.NoError(t, storage.Put(ctx, "exports/1/1700000000_budgets 2024.csv", "text/csv", []byte("a,b")))
+	router := setupGin()
+	router.GET("/files/*key", NewFileHandler(storage).Download)
+
+	link, err := storage.SignedURL(ctx, "exports/1/1700000000_budgets 2024.csv", time.Minute)
+	require.NoError(t, err)
+	missing, err := storage.SignedURL(ctx, "exports/1/gone.csv", time.Minute)
+	require.NoError(t, err)
+	u, err := url.Parse(link)
+	require.NoError(t, err)
+	query := u.Query()
+	query.Set("signature", strings.Repeat("0", 64))
+	forged := u.EscapedPath() + "?" + query.Encode()
+
+	tests := []struct {
+		name       string
+		link       string
+		wantStatus int
+	}{
+		{"serves signed links", link, http.StatusOK},
+		{"rejects forged signatures", forged, http.StatusForbidden},
+		{"reports files that are gone", missing, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.link, nil))
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, "a,b", w.Body.String())
+				assert.Contains(t, w.Header().Get("Content-Type"), "text/csv")
+				assert.Equal(t, `attachment; filename="budgets 2024.csv"`, w.Header().Get("Content-Disposition"))
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FXServiceInterface defines the interface for historical exchange rates
+type FXServiceInterface interface {
+	Rate(ctx context.Context, from, to string, date time.Time) (*domain.ExchangeRate, error)
+}
+
+type FXHandler struct {
+	Service FXServiceInterface
+}
+
+func NewFXHandler(service FXServiceInterface) *FXHandler {
+	return &FXHandler{Service: service}
+}
+
+// GetRate returns the rate of "from" in "to" on "date", YYYY-MM-DD and today
+// by default. "to" defaults to the base currency. Rates not stored yet are
+// fetched and kept.
+func (h *FXHandler) GetRate(c *gin.Context) {
+	from := strings.ToUpper(c.Query("from"))
+	to := strings.ToUpper(c.DefaultQuery("to", domain.BaseCurrency))
+	if !domain.IsCurrencyCode(from) || !domain.IsCurrencyCode(to) {
+		respondError(c, middleware.CodeBadRequest, "from and to must be three letter ISO 4217 codes")
+		return
+	}
+	date := time.Now()
+	if value := c.Query("date"); value != "" {
+		var err error
+		date, err = time.Parse("2006-01-02", value)
+		if err != nil {
+			respondError(c, middleware.CodeInvalidDate, "Invalid date format. Use YYYY-MM-DD")
+			return
+		}
+	}
+
+	rate, err := h.Service.Rate(c.Request.Context(), from, to, date)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, rate)
+	case errors.Is(err, domain.ErrExchangeRateUnavailable):
+		respondExchangeRateUnavailable(c, err)
+	default:
+		respondInternalError(c, "Failed to retrieve exchange rate", err)
+	}
+}
+
+// respondExchangeRateUnavailable answers requests that need a rate the
+// provider cannot supply right now
+func respondExchangeRateUnavailable(c *gin.Context, err error) {
+	middleware.RespondError(c, &middleware.APIError{
+		Code: middleware.CodeUnavailable, Message: "No exchange rate is available for the currency on that date", Cause: err,
+	})
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockFXService is a mock implementation of FXServiceInterface
+type MockFXService struct {
+	mock.Mock
+}
+
+func (m *MockFXService) Rate(ctx context.Context, from, to string, date time.Time) (*domain.ExchangeRate, error) {
+	args := m.Called(ctx, from, to, date)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ExchangeRate), args.Error(1)
+}
+
+func TestFXHandler_GetRate(t *testing.T) {
+	march := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name           string
+		query          string
+		mockSetup      func(*MockFXService)
+		expectedStatus int
+	}{
+		{
+			name:  "rate of a past day",
+			query: "?from=eur&date=2024-03-01",
+			mockSetup: func(m *MockFXService) {
+				m.On("Rate", mock.Anything, "EUR", "USD", march).
+					Return(&domain.ExchangeRate{Base: "EUR", Quote: "USD", Date: march, Rate: 1.08}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid currency",
+			query:          "?from=EURO",
+			mockSetup:      func(m *MockFXService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid date",
+			query:          "?from=EUR&date=01.03.2024",
+			mockSetup:      func(m *MockFXService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "provider unavailable",
+			query: "?from=EUR&to=GBP&date=2024-03-01",
+			mockSetup: func(m *MockFXService) {
+				m.On("Rate", mock.Anything, "EUR", "GBP", march).
+					Return(nil, fmt.Errorf("%w: frankfurter returned status 503", domain.ErrExchangeRateUnavailable))
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:  "database failure",
+			query: "?from=EUR&date=2024-03-01",
+			mockSetup: func(m *MockFXService) {
+				m.On("Rate", mock.Anything, "EUR", "USD", march).Return(nil, errors.New("database is locked"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(MockFXService)
+			tt.mockSetup(service)
+			router := setupGin()
+			router.GET("/fx/rates", NewFXHandler(service).GetRate)
+
+			req := httptest.NewRequest(http.MethodGet, "/fx/rates"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			service.AssertExpectations(t)
+		})
+	}
+}
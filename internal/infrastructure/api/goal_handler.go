@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GoalServiceInterface defines the interface for tracking financial goals
+type GoalServiceInterface interface {
+	Contribute(ctx context.Context, userID, goalID uint, amount domain.Money) (*domain.FinancialGoal, error)
+}
+
+type GoalHandler struct {
+	Service GoalServiceInterface
+}
+
+func NewGoalHandler(service GoalServiceInterface) *GoalHandler {
+	return &GoalHandler{Service: service}
+}
+
+// GoalContributionRequest is the body of contributions; a negative amount
+// withdraws from the goal
+type GoalContributionRequest struct {
+	Amount domain.Money `json:"amount"`
+}
+
+// Contribute adds to what has been saved towards a goal
+func (h *GoalHandler) Contribute(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+	goalID, err := strconv.ParseUint(c.Param("goalId"), 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid goal ID")
+		return
+	}
+
+	var req GoalContributionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	goal, err := h.Service.Contribute(c.Request.Context(), userID, uint(goalID), req.Amount)
+	if respondValidationError(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "Goal not found")
+	case err != nil:
+		respondInternalError(c, "Failed to update goal", err)
+	default:
+		c.JSON(http.StatusOK, goal)
+	}
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockGoalService is a mock implementation of GoalServiceInterface
+type MockGoalService struct {
+	mock.Mock
+}
+
+func (m *MockGoalService) Contribute(ctx context.Context, userID, goalID uint, amount domain.Money) (*domain.FinancialGoal, error) {
+	args := m.Called(ctx, userID, goalID, amount)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FinancialGoal), args.Error(1)
+}
+
+func setupGoalRouter(service *MockGoalService) *gin.Engine {
+	handler := NewGoalHandler(service)
+	router := setupGin()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Next()
+	})
+	router.POST("/users/:userId/goals/:goalId/contributions", handler.Contribute)
+	return router
+}
+
+func TestGoalHandler_Contribute(t *testing.T) {
+	service := new(MockGoalService)
+	service.On("Contribute", mock.Anything, uint(1), uint(4), domain.NewMoney(250)).Return(&domain.FinancialGoal{
+		ID: 4, UserID: 1, CurrentAmount: domain.NewMoney(1000), TargetAmount: domain.NewMoney(1000), Status: domain.GoalStatusCompleted,
+	}, nil)
+	service.On("Contribute", mock.Anything, uint(1), uint(5), mock.Anything).Return(nil, domain.ErrNotFound)
+	service.On("Contribute", mock.Anything, uint(1), uint(4), domain.Money(0)).Return(nil, &domain.ValidationError{
+		Fields: []domain.FieldError{{Field: "amount", Message: "must not be zero"}},
+	})
+	router := setupGoalRouter(service)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/1/goals/4/contributions", strings.NewReader(`{"amount":250}`)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"completed"`)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/1/goals/5/contributions", strings.NewReader(`{"amount":10}`)))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/1/goals/4/contributions", strings.NewReader(`{"amount":0}`)))
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/1/goals/abc/contributions", strings.NewReader(`{"amount":10}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
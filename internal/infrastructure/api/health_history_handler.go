@@ -0,0 +1,66 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthHistoryServiceInterface defines the interface for the financial health history
+type HealthHistoryServiceInterface interface {
+	History(ctx context.Context, userID uint, filter domain.HealthHistoryFilter) (*domain.HealthHistory, error)
+}
+
+type HealthHistoryHandler struct {
+	Service HealthHistoryServiceInterface
+}
+
+func NewHealthHistoryHandler(service HealthHistoryServiceInterface) *HealthHistoryHandler {
+	return &HealthHistoryHandler{Service: service}
+}
+
+// GetHistory returns the user's health score and savings rate over time.
+// from and to (YYYY-MM-DD, inclusive) narrow the range, interval is day or week.
+func (h *HealthHistoryHandler) GetHistory(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	filter := domain.HealthHistoryFilter{Interval: c.Query("interval")}
+	var err error
+	if filter.From, err = parseOptionalDate(c.Query("from")); err != nil {
+		respondError(c, middleware.CodeInvalidDate, "Invalid from date format. Use YYYY-MM-DD")
+		return
+	}
+	if filter.To, err = parseOptionalDate(c.Query("to")); err != nil {
+		respondError(c, middleware.CodeInvalidDate, "Invalid to date format. Use YYYY-MM-DD")
+		return
+	}
+
+	history, err := h.Service.History(c.Request.Context(), userID, filter)
+	if respondValidationError(c, err) {
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve health history", err)
+		return
+	}
+	c.JSON(http.StatusOK, history)
+}
+
+func parseOptionalDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, err
+	}
+	return &date, nil
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockHealthHistoryService is a mock implementation of HealthHistoryServiceInterface
+type MockHealthHistoryService struct {
+	mock.Mock
+}
+
+func (m *MockHealthHistoryService) History(ctx context.Context, userID uint, filter domain.HealthHistoryFilter) (*domain.HealthHistory, error) {
+	args := m.Called(ctx, userID, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.HealthHistory), args.Error(1)
+}
+
+func setupHealthHistoryRouter(service *MockHealthHistoryService) *gin.Engine {
+	handler := NewHealthHistoryHandler(service)
+	router := setupGin()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Next()
+	})
+	router.GET("/users/:userId/analytics/health/history", handler.GetHistory)
+	return router
+}
+
+func TestHealthHistoryHandler_GetHistory(t *testing.T) {
+	from := time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)
+	service := new(MockHealthHistoryService)
+	service.On("History", mock.Anything, uint(1), domain.HealthHistoryFilter{From: &from, Interval: "week"}).Return(&domain.HealthHistory{
+		Interval: "week", Points: []domain.HealthSnapshot{{UserID: 1, OverallScore: 60}, {UserID: 1, OverallScore: 70}}, Change: 10,
+	}, nil)
+	service.On("History", mock.Anything, uint(1), domain.HealthHistoryFilter{Interval: "hour"}).Return(nil, &domain.ValidationError{
+		Fields: []domain.FieldError{{Field: "interval", Message: "must be one of day, week"}},
+	})
+	router := setupHealthHistoryRouter(service)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1/analytics/health/history?from=2025-04-01&interval=week", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"change":10`)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1/analytics/health/history?interval=hour", nil))
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1/analytics/health/history?to=June", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/2/analytics/health/history", nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
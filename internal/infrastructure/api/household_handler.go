@@ -0,0 +1,398 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HouseholdServiceInterface defines the interface for shared households
+type HouseholdServiceInterface interface {
+	CreateHousehold(ctx context.Context, userID uint, name string) (*domain.Household, error)
+	ListHouseholds(ctx context.Context, userID uint) ([]domain.Household, error)
+	GetHousehold(ctx context.Context, userID, householdID uint) (*domain.Household, error)
+	Invite(ctx context.Context, userID, householdID uint, email, role string) (*domain.HouseholdInvitation, error)
+	ListInvitations(ctx context.Context, userID uint) ([]domain.HouseholdInvitation, error)
+	AcceptInvitation(ctx context.Context, userID uint, token string) (*domain.Household, error)
+	DeclineInvitation(ctx context.Context, userID uint, token string) error
+	UpdateMemberRole(ctx context.Context, userID, householdID, memberUserID uint, role string) (*domain.HouseholdMember, error)
+	RemoveMember(ctx context.Context, userID, householdID, memberUserID uint) error
+	ListTransactions(
+		ctx context.Context, userID, householdID uint, filter domain.TransactionFilter,
+	) (*domain.TransactionPage, error)
+	CreateTransaction(ctx context.Context, userID, householdID uint, transaction *domain.Transaction) error
+	ListBudgets(ctx context.Context, userID, householdID uint) ([]domain.Budget, error)
+	CreateBudget(ctx context.Context, userID, householdID uint, budget *domain.Budget) error
+	GetFinances(
+		ctx context.Context, userID, householdID uint, startDate, endDate time.Time,
+	) (*domain.HouseholdFinances, error)
+}
+
+// HouseholdHandler serves households to their members. The acting user is
+// always the authenticated one.
+type HouseholdHandler struct {
+	Service HouseholdServiceInterface
+}
+
+func NewHouseholdHandler(service HouseholdServiceInterface) *HouseholdHandler {
+	return &HouseholdHandler{Service: service}
+}
+
+type CreateHouseholdRequest struct {
+	Name string `json:"name" binding:"required,min=1,max=100"`
+}
+
+type InviteMemberRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required,oneof=member read_only"`
+}
+
+type UpdateMemberRequest struct {
+	Role string `json:"role" binding:"required,oneof=member read_only"`
+}
+
+// Create creates a household owned by the authenticated user
+func (h *HouseholdHandler) Create(c *gin.Context) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		respondError(c, middleware.CodeUnauthenticated, "User not authenticated")
+		return
+	}
+
+	var req CreateHouseholdRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	household, err := h.Service.CreateHousehold(c.Request.Context(), userID, req.Name)
+	if err != nil {
+		respondInternalError(c, "Failed to create household", err)
+		return
+	}
+	c.JSON(http.StatusCreated, household)
+}
+
+// List returns the households the authenticated user belongs to
+func (h *HouseholdHandler) List(c *gin.Context) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		respondError(c, middleware.CodeUnauthenticated, "User not authenticated")
+		return
+	}
+
+	households, err := h.Service.ListHouseholds(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve households", err)
+		return
+	}
+	c.JSON(http.StatusOK, households)
+}
+
+// Get returns a household with its members
+func (h *HouseholdHandler) Get(c *gin.Context) {
+	userID, householdID, ok := parseHouseholdIDs(c)
+	if !ok {
+		return
+	}
+
+	household, err := h.Service.GetHousehold(c.Request.Context(), userID, householdID)
+	if err != nil {
+		respondHouseholdError(c, err, "Failed to retrieve household")
+		return
+	}
+	c.JSON(http.StatusOK, household)
+}
+
+// Invite invites a user by email; the response carries the token the
+// invitee accepts the invitation with
+func (h *HouseholdHandler) Invite(c *gin.Context) {
+	userID, householdID, ok := parseHouseholdIDs(c)
+	if !ok {
+		return
+	}
+
+	var req InviteMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	invitation, err := h.Service.Invite(c.Request.Context(), userID, householdID, req.Email, req.Role)
+	if err != nil {
+		respondHouseholdError(c, err, "Failed to create invitation")
+		return
+	}
+	c.JSON(http.StatusCreated, invitation)
+}
+
+// ListInvitations returns the open invitations for the authenticated user
+func (h *HouseholdHandler) ListInvitations(c *gin.Context) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		respondError(c, middleware.CodeUnauthenticated, "User not authenticated")
+		return
+	}
+
+	invitations, err := h.Service.ListInvitations(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve invitations", err)
+		return
+	}
+	c.JSON(http.StatusOK, invitations)
+}
+
+// AcceptInvitation joins the household the invitation is for
+func (h *HouseholdHandler) AcceptInvitation(c *gin.Context) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		respondError(c, middleware.CodeUnauthenticated, "User not authenticated")
+		return
+	}
+
+	household, err := h.Service.AcceptInvitation(c.Request.Context(), userID, c.Param("token"))
+	if err != nil {
+		respondHouseholdError(c, err, "Failed to accept invitation")
+		return
+	}
+	c.JSON(http.StatusOK, household)
+}
+
+// DeclineInvitation turns an invitation down
+func (h *HouseholdHandler) DeclineInvitation(c *gin.Context) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		respondError(c, middleware.CodeUnauthenticated, "User not authenticated")
+		return
+	}
+
+	if err := h.Service.DeclineInvitation(c.Request.Context(), userID, c.Param("token")); err != nil {
+		respondHouseholdError(c, err, "Failed to decline invitation")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Invitation declined"})
+}
+
+// UpdateMember changes a member's role
+func (h *HouseholdHandler) UpdateMember(c *gin.Context) {
+	userID, householdID, ok := parseHouseholdIDs(c)
+	if !ok {
+		return
+	}
+	memberUserID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid user ID")
+		return
+	}
+
+	var req UpdateMemberRequest
+	if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
+		respondError(c, middleware.CodeInvalidBody, bindErr.Error())
+		return
+	}
+
+	member, err := h.Service.UpdateMemberRole(c.Request.Context(), userID, householdID, uint(memberUserID), req.Role)
+	if err != nil {
+		respondHouseholdError(c, err, "Failed to update member")
+		return
+	}
+	c.JSON(http.StatusOK, member)
+}
+
+// RemoveMember removes a member, or lets the authenticated user leave
+func (h *HouseholdHandler) RemoveMember(c *gin.Context) {
+	userID, householdID, ok := parseHouseholdIDs(c)
+	if !ok {
+		return
+	}
+	memberUserID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid user ID")
+		return
+	}
+
+	if err := h.Service.RemoveMember(c.Request.Context(), userID, householdID, uint(memberUserID)); err != nil {
+		respondHouseholdError(c, err, "Failed to remove member")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Member removed"})
+}
+
+// ListTransactions returns a page of the household's shared transactions,
+// optionally for one member (user_id)
+func (h *HouseholdHandler) ListTransactions(c *gin.Context) {
+	userID, householdID, ok := parseHouseholdIDs(c)
+	if !ok {
+		return
+	}
+	memberUserID, err := parseOptionalID(c.Query("user_id"))
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid user_id")
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit <= 0 {
+		limit = 100
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+	filter := domain.TransactionFilter{UserID: memberUserID, Type: c.Query("type"), Limit: limit, Offset: offset}
+	if cursor := c.Query("cursor"); cursor != "" {
+		filter.Cursor, err = domain.DecodeTransactionCursor(cursor)
+		if err != nil {
+			respondError(c, middleware.CodeBadRequest, "Invalid cursor")
+			return
+		}
+	}
+
+	page, err := h.Service.ListTransactions(c.Request.Context(), userID, householdID, filter)
+	if err != nil {
+		respondHouseholdError(c, err, "Failed to retrieve transactions")
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// CreateTransaction records a transaction of the authenticated user shared with the household
+func (h *HouseholdHandler) CreateTransaction(c *gin.Context) {
+	userID, householdID, ok := parseHouseholdIDs(c)
+	if !ok {
+		return
+	}
+
+	var req CreateTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+	transaction, invalid := req.transaction(userID)
+	if invalid != "" {
+		respondError(c, middleware.CodeInvalidDate, invalid)
+		return
+	}
+
+	if err := h.Service.CreateTransaction(c.Request.Context(), userID, householdID, transaction); err != nil {
+		respondHouseholdError(c, err, "Failed to create transaction")
+		return
+	}
+	c.JSON(http.StatusCreated, transaction)
+}
+
+// ListBudgets returns the household's budgets
+func (h *HouseholdHandler) ListBudgets(c *gin.Context) {
+	userID, householdID, ok := parseHouseholdIDs(c)
+	if !ok {
+		return
+	}
+
+	budgets, err := h.Service.ListBudgets(c.Request.Context(), userID, householdID)
+	if err != nil {
+		respondHouseholdError(c, err, "Failed to retrieve budgets")
+		return
+	}
+	c.JSON(http.StatusOK, budgets)
+}
+
+// CreateBudget creates a budget that counts the shared spending of all members
+func (h *HouseholdHandler) CreateBudget(c *gin.Context) {
+	userID, householdID, ok := parseHouseholdIDs(c)
+	if !ok {
+		return
+	}
+
+	var req CreateBudgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+	budget, invalid := req.budget(userID)
+	if invalid != "" {
+		respondError(c, middleware.CodeInvalidDate, invalid)
+		return
+	}
+
+	if err := h.Service.CreateBudget(c.Request.Context(), userID, householdID, budget); err != nil {
+		respondHouseholdError(c, err, "Failed to create budget")
+		return
+	}
+	c.JSON(http.StatusCreated, budget)
+}
+
+// GetFinances returns the authenticated user's personal finances next to the
+// household's and their combination, for the current month by default
+func (h *HouseholdHandler) GetFinances(c *gin.Context) {
+	userID, householdID, ok := parseHouseholdIDs(c)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	startDate := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	endDate := time.Date(now.Year(), now.Month()+1, 0, 23, 59, 59, 0, now.Location())
+	var err error
+	if value := c.Query("start_date"); value != "" {
+		if startDate, err = time.Parse("2006-01-02", value); err != nil {
+			respondError(c, middleware.CodeInvalidDate, "Invalid start date format. Use YYYY-MM-DD")
+			return
+		}
+	}
+	if value := c.Query("end_date"); value != "" {
+		if endDate, err = time.Parse("2006-01-02", value); err != nil {
+			respondError(c, middleware.CodeInvalidDate, "Invalid end date format. Use YYYY-MM-DD")
+			return
+		}
+	}
+
+	finances, err := h.Service.GetFinances(c.Request.Context(), userID, householdID, startDate, endDate)
+	if err != nil {
+		respondHouseholdError(c, err, "Failed to calculate household finances")
+		return
+	}
+	c.JSON(http.StatusOK, finances)
+}
+
+func parseHouseholdIDs(c *gin.Context) (userID, householdID uint, ok bool) {
+	userID, ok = authenticatedUserID(c)
+	if !ok {
+		respondError(c, middleware.CodeUnauthenticated, "User not authenticated")
+		return 0, 0, false
+	}
+	parsedID, err := strconv.ParseUint(c.Param("householdId"), 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid household ID")
+		return 0, 0, false
+	}
+	return userID, uint(parsedID), true
+}
+
+func respondHouseholdError(c *gin.Context, err error, message string) {
+	if respondValidationError(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "Household or member not found")
+	case errors.Is(err, domain.ErrHouseholdForbidden):
+		respondError(c, middleware.CodeForbidden, err.Error())
+	case errors.Is(err, domain.ErrInvalidInvitation):
+		respondError(c, middleware.CodeNotFound, err.Error())
+	case errors.Is(err, domain.ErrInvalidHouseholdRole):
+		respondError(c, middleware.CodeBadRequest, err.Error())
+	case errors.Is(err, domain.ErrAlreadyHouseholdMember):
+		respondError(c, middleware.CodeConflict, err.Error())
+	case errors.Is(err, domain.ErrCategoryCapExceeded):
+		respondError(c, middleware.CodeUnprocessable, err.Error())
+	default:
+		respondInternalError(c, message, err)
+	}
+}
@@ -0,0 +1,302 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const householdTestUserID uint = 3
+
+// MockHouseholdService is a mock implementation of HouseholdServiceInterface
+type MockHouseholdService struct {
+	mock.Mock
+}
+
+func (m *MockHouseholdService) CreateHousehold(ctx context.Context, userID uint, name string) (*domain.Household, error) {
+	args := m.Called(ctx, userID, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Household), args.Error(1)
+}
+
+func (m *MockHouseholdService) ListHouseholds(ctx context.Context, userID uint) ([]domain.Household, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.Household), args.Error(1)
+}
+
+func (m *MockHouseholdService) GetHousehold(ctx context.Context, userID, householdID uint) (*domain.Household, error) {
+	args := m.Called(ctx, userID, householdID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Household), args.Error(1)
+}
+
+func (m *MockHouseholdService) Invite(
+	ctx context.Context, userID, householdID uint, email, role string,
+) (*domain.HouseholdInvitation, error) {
+	args := m.Called(ctx, userID, householdID, email, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.HouseholdInvitation), args.Error(1)
+}
+
+func (m *MockHouseholdService) ListInvitations(ctx context.Context, userID uint) ([]domain.HouseholdInvitation, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.HouseholdInvitation), args.Error(1)
+}
+
+func (m *MockHouseholdService) AcceptInvitation(ctx context.Context, userID uint, token string) (*domain.Household, error) {
+	args := m.Called(ctx, userID, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Household), args.Error(1)
+}
+
+func (m *MockHouseholdService) DeclineInvitation(ctx context.Context, userID uint, token string) error {
+	args := m.Called(ctx, userID, token)
+	return args.Error(0)
+}
+
+func (m *MockHouseholdService) UpdateMemberRole(
+	ctx context.Context, userID, householdID, memberUserID uint, role string,
+) (*domain.HouseholdMember, error) {
+	args := m.Called(ctx, userID, householdID, memberUserID, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.HouseholdMember), args.Error(1)
+}
+
+func (m *MockHouseholdService) RemoveMember(ctx context.Context, userID, householdID, memberUserID uint) error {
+	args := m.Called(ctx, userID, householdID, memberUserID)
+	return args.Error(0)
+}
+
+func (m *MockHouseholdService) ListTransactions(
+	ctx context.Context, userID, householdID uint, filter domain.TransactionFilter,
+) (*domain.TransactionPage, error) {
+	args := m.Called(ctx, userID, householdID, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.TransactionPage), args.Error(1)
+}
+
+func (m *MockHouseholdService) CreateTransaction(
+	ctx context.Context, userID, householdID uint, transaction *domain.Transaction,
+) error {
+	args := m.Called(ctx, userID, householdID, transaction)
+	return args.Error(0)
+}
+
+func (m *MockHouseholdService) ListBudgets(ctx context.Context, userID, householdID uint) ([]domain.Budget, error) {
+	args := m.Called(ctx, userID, householdID)
+	return args.Get(0).([]domain.Budget), args.Error(1)
+}
+
+func (m *MockHouseholdService) CreateBudget(ctx context.Context, userID, householdID uint, budget *domain.Budget) error {
+	args := m.Called(ctx, userID, householdID, budget)
+	return args.Error(0)
+}
+
+func (m *MockHouseholdService) GetFinances(
+	ctx context.Context, userID, householdID uint, startDate, endDate time.Time,
+) (*domain.HouseholdFinances, error) {
+	args := m.Called(ctx, userID, householdID, startDate, endDate)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.HouseholdFinances), args.Error(1)
+}
+
+func setupHouseholdGin() *gin.Engine {
+	router := setupGin()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", householdTestUserID)
+	})
+	return router
+}
+
+func TestHouseholdHandler_Create(t *testing.T) {
+	mockService := new(MockHouseholdService)
+	handler := NewHouseholdHandler(mockService)
+	router := setupHouseholdGin()
+	router.POST("/households", handler.Create)
+
+	mockService.On("CreateHousehold", mock.Anything, householdTestUserID, "Home").
+		Return(&domain.Household{ID: 1, Name: "Home", OwnerID: householdTestUserID}, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/households", bytes.NewBufferString(`{"name":"Home"}`)))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var household domain.Household
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &household))
+	assert.Equal(t, householdTestUserID, household.OwnerID)
+	mockService.AssertExpectations(t)
+}
+
+func TestHouseholdHandler_RequiresAuthentication(t *testing.T) {
+	handler := NewHouseholdHandler(new(MockHouseholdService))
+	router := setupGin()
+	router.GET("/households/:householdId", handler.Get)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/households/1", http.NoBody))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestHouseholdHandler_Invite(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		serviceErr error
+		wantStatus int
+	}{
+		{"invites a member", `{"email":"partner@example.com","role":"member"}`, nil, http.StatusCreated},
+		{"rejects owner role", `{"email":"partner@example.com","role":"owner"}`, nil, http.StatusBadRequest},
+		{"rejects non-owners", `{"email":"partner@example.com","role":"member"}`, domain.ErrHouseholdForbidden, http.StatusForbidden},
+		{"hides other households", `{"email":"partner@example.com","role":"member"}`, domain.ErrNotFound, http.StatusNotFound},
+		{"rejects existing members", `{"email":"partner@example.com","role":"member"}`, domain.ErrAlreadyHouseholdMember, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockHouseholdService)
+			handler := NewHouseholdHandler(mockService)
+			router := setupHouseholdGin()
+			router.POST("/households/:householdId/invitations", handler.Invite)
+
+			invitation := &domain.HouseholdInvitation{ID: 5, HouseholdID: 2, Token: "abc"}
+			if tt.serviceErr != nil {
+				invitation = nil
+			}
+			mockService.On("Invite", mock.Anything, householdTestUserID, uint(2), "partner@example.com", "member").
+				Return(invitation, tt.serviceErr).Maybe()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/households/2/invitations", bytes.NewBufferString(tt.body))
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestHouseholdHandler_AcceptInvitation(t *testing.T) {
+	t.Run("joins the household", func(t *testing.T) {
+		mockService := new(MockHouseholdService)
+		handler := NewHouseholdHandler(mockService)
+		router := setupHouseholdGin()
+		router.POST("/households/invitations/:token/accept", handler.AcceptInvitation)
+
+		mockService.On("AcceptInvitation", mock.Anything, householdTestUserID, "abc").
+			Return(&domain.Household{ID: 2, Name: "Home"}, nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/households/invitations/abc/accept", http.NoBody))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("rejects invalid invitations", func(t *testing.T) {
+		mockService := new(MockHouseholdService)
+		handler := NewHouseholdHandler(mockService)
+		router := setupHouseholdGin()
+		router.POST("/households/invitations/:token/accept", handler.AcceptInvitation)
+
+		mockService.On("AcceptInvitation", mock.Anything, householdTestUserID, "old").
+			Return(nil, domain.ErrInvalidInvitation)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/households/invitations/old/accept", http.NoBody))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestHouseholdHandler_CreateTransaction(t *testing.T) {
+	t.Run("records a shared transaction", func(t *testing.T) {
+		mockService := new(MockHouseholdService)
+		handler := NewHouseholdHandler(mockService)
+		router := setupHouseholdGin()
+		router.POST("/households/:householdId/transactions", handler.CreateTransaction)
+
+		mockService.On("CreateTransaction", mock.Anything, householdTestUserID, uint(2), mock.MatchedBy(func(tx *domain.Transaction) bool {
+			return tx.Amount == domain.NewMoney(42) && tx.CategoryID == 1 && tx.Date.Format("2006-01-02") == "2025-03-01"
+		})).Return(nil)
+
+		body := `{"amount":42,"type":"expense","description":"Groceries","category_id":1,"date":"2025-03-01"}`
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/households/2/transactions", bytes.NewBufferString(body)))
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("rejects read-only members", func(t *testing.T) {
+		mockService := new(MockHouseholdService)
+		handler := NewHouseholdHandler(mockService)
+		router := setupHouseholdGin()
+		router.POST("/households/:householdId/transactions", handler.CreateTransaction)
+
+		mockService.On("CreateTransaction", mock.Anything, householdTestUserID, uint(2), mock.Anything).
+			Return(domain.ErrHouseholdForbidden)
+
+		body := `{"amount":42,"type":"expense","description":"Groceries","category_id":1}`
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/households/2/transactions", bytes.NewBufferString(body)))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestHouseholdHandler_GetFinances(t *testing.T) {
+	mockService := new(MockHouseholdService)
+	handler := NewHouseholdHandler(mockService)
+	router := setupHouseholdGin()
+	router.GET("/households/:householdId/analytics", handler.GetFinances)
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+	finances := &domain.HouseholdFinances{
+		HouseholdID: 2,
+		Personal:    domain.FinanceTotals{Expenses: domain.NewMoney(30)},
+		Household:   domain.FinanceTotals{Expenses: domain.NewMoney(200)},
+		Combined:    domain.FinanceTotals{Expenses: domain.NewMoney(230)},
+	}
+	mockService.On("GetFinances", mock.Anything, householdTestUserID, uint(2), start, end).Return(finances, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet,
+		"/households/2/analytics?start_date=2025-01-01&end_date=2025-01-31", http.NoBody))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response domain.HouseholdFinances
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, domain.NewMoney(230.0), response.Combined.Expenses)
+	mockService.AssertExpectations(t)
+
+	t.Run("rejects invalid dates", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/households/2/analytics?start_date=jan", http.NoBody))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
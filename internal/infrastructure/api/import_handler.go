@@ -0,0 +1,157 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportSize limits import uploads to 20 MB, enough for years of history
+const maxImportSize = 20 << 20
+
+// ImportServiceInterface defines the interface for importing other apps' exports
+type ImportServiceInterface interface {
+	Import(ctx context.Context, userID uint, source string, data []byte) (*domain.ImportResult, error)
+	ListMappings(ctx context.Context, userID uint, source string) ([]domain.CategoryMapping, error)
+	SetMapping(ctx context.Context, userID uint, mapping domain.CategoryMapping) (*domain.CategoryMapping, error)
+	DeleteMapping(ctx context.Context, userID, id uint) error
+}
+
+type ImportHandler struct {
+	Service ImportServiceInterface
+}
+
+func NewImportHandler(service ImportServiceInterface) *ImportHandler {
+	return &ImportHandler{Service: service}
+}
+
+// CategoryMappingRequest is the body of category mapping requests
+type CategoryMappingRequest struct {
+	Source           string `json:"source"`
+	ExternalCategory string `json:"external_category"`
+	CategoryID       uint   `json:"category_id"`
+}
+
+// Import accepts an export of the finance app named by the source parameter
+// as multipart field "file" and records its transactions
+func (h *ImportHandler) Import(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+	source := c.Param("source")
+	if !domain.IsValidImportSource(source) {
+		respondError(c, middleware.CodeBadRequest, domain.ErrUnknownImportSource.Error())
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		respondError(c, middleware.CodeBadRequest, "Import file is required")
+		return
+	}
+	if fileHeader.Size > maxImportSize {
+		respondError(c, middleware.CodePayloadTooLarge, "Import file exceeds 20MB limit")
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		respondError(c, middleware.CodeBadRequest, "Failed to read import file")
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, maxImportSize))
+	if err != nil {
+		respondError(c, middleware.CodeBadRequest, "Failed to read import file")
+		return
+	}
+
+	result, err := h.Service.Import(c.Request.Context(), userID, source, data)
+	if errors.Is(err, domain.ErrInvalidImportFile) {
+		respondError(c, middleware.CodeUnprocessable, err.Error())
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to import transactions", err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// ListMappings returns the user's category mappings, optionally of one import
+// source or bank provider
+func (h *ImportHandler) ListMappings(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+	source := c.Query("source")
+	if source != "" && !domain.IsValidCategoryMappingSource(source) {
+		respondError(c, middleware.CodeBadRequest, "source must be an import source or bank provider")
+		return
+	}
+
+	mappings, err := h.Service.ListMappings(c.Request.Context(), userID, source)
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve category mappings", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"mappings": mappings, "count": len(mappings)})
+}
+
+// SetMapping maps a category of an import source or bank provider to one of
+// the user's categories
+func (h *ImportHandler) SetMapping(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req CategoryMappingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	mapping, err := h.Service.SetMapping(c.Request.Context(), userID, domain.CategoryMapping{
+		Source: req.Source, ExternalCategory: req.ExternalCategory, CategoryID: req.CategoryID,
+	})
+	if respondValidationError(c, err) {
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to save category mapping", err)
+		return
+	}
+	c.JSON(http.StatusOK, mapping)
+}
+
+// DeleteMapping removes a category mapping
+func (h *ImportHandler) DeleteMapping(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+	mappingID, err := strconv.ParseUint(c.Param("mappingId"), 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid mapping ID")
+		return
+	}
+
+	err = h.Service.DeleteMapping(c.Request.Context(), userID, uint(mappingID))
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "Category mapping not found")
+	case err != nil:
+		respondInternalError(c, "Failed to delete category mapping", err)
+	default:
+		c.JSON(http.StatusOK, gin.H{"message": "Category mapping removed"})
+	}
+}
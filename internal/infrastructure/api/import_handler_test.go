@@ -0,0 +1,203 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockImportService is a mock implementation of ImportServiceInterface
+type MockImportService struct {
+	mock.Mock
+}
+
+func (m *MockImportService) Import(ctx context.Context, userID uint, source string, data []byte) (*domain.ImportResult, error) {
+	args := m.Called(ctx, userID, source, data)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ImportResult), args.Error(1)
+}
+
+func (m *MockImportService) ListMappings(ctx context.Context, userID uint, source string) ([]domain.CategoryMapping, error) {
+	args := m.Called(ctx, userID, source)
+	return args.Get(0).([]domain.CategoryMapping), args.Error(1)
+}
+
+func (m *MockImportService) SetMapping(
+	ctx context.Context, userID uint, mapping domain.CategoryMapping,
+) (*domain.CategoryMapping, error) {
+	args := m.Called(ctx, userID, mapping)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CategoryMapping), args.Error(1)
+}
+
+func (m *MockImportService) DeleteMapping(ctx context.Context, userID, id uint) error {
+	return m.Called(ctx, userID, id).Error(0)
+}
+
+func setupImportRouter(service *MockImportService) *gin.Engine {
+	handler := NewImportHandler(service)
+	router := setupGin()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Next()
+	})
+	router.POST("/users/:userId/imports/:source", handler.Import)
+	router.GET("/users/:userId/import-mappings", handler.ListMappings)
+	router.PUT("/users/:userId/import-mappings", handler.SetMapping)
+	router.DELETE("/users/:userId/import-mappings/:mappingId", handler.DeleteMapping)
+	return router
+}
+
+func newImportUploadRequest(t *testing.T, url string, content []byte) *http.Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", "transactions.csv")
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, url, body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestImportHandler_Import(t *testing.T) {
+	t.Run("should return the import summary", func(t *testing.T) {
+		service := new(MockImportService)
+		service.On("Import", mock.Anything, uint(1), domain.ImportSourceMint, []byte("csv")).Return(&domain.ImportResult{
+			Source: domain.ImportSourceMint, Rows: 3, Imported: 2, Transfers: 1,
+			Unmapped: []string{"Pet Care"}, Errors: []domain.ImportRowError{},
+		}, nil)
+
+		w := httptest.NewRecorder()
+		setupImportRouter(service).ServeHTTP(w, newImportUploadRequest(t, "/users/1/imports/mint", []byte("csv")))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var result domain.ImportResult
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		assert.Equal(t, 2, result.Imported)
+		assert.Equal(t, []string{"Pet Care"}, result.Unmapped)
+		service.AssertExpectations(t)
+	})
+
+	t.Run("should return 422 for files that are not exports of the source", func(t *testing.T) {
+		service := new(MockImportService)
+		service.On("Import", mock.Anything, uint(1), domain.ImportSourceYNAB, mock.Anything).
+			Return(nil, fmt.Errorf("%w: missing column %q", domain.ErrInvalidImportFile, "payee"))
+
+		w := httptest.NewRecorder()
+		setupImportRouter(service).ServeHTTP(w, newImportUploadRequest(t, "/users/1/imports/ynab", []byte("csv")))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Contains(t, w.Body.String(), "payee")
+	})
+
+	t.Run("should return 400 for unknown sources and missing files", func(t *testing.T) {
+		service := new(MockImportService)
+		router := setupImportRouter(service)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newImportUploadRequest(t, "/users/1/imports/quicken", []byte("csv")))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/1/imports/mint", nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		service.AssertNotCalled(t, "Import")
+	})
+
+	t.Run("should not import for other users", func(t *testing.T) {
+		service := new(MockImportService)
+
+		w := httptest.NewRecorder()
+		setupImportRouter(service).ServeHTTP(w, newImportUploadRequest(t, "/users/2/imports/mint", []byte("csv")))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		service.AssertNotCalled(t, "Import")
+	})
+}
+
+func TestImportHandler_Mappings(t *testing.T) {
+	t.Run("should list mappings of a source", func(t *testing.T) {
+		service := new(MockImportService)
+		service.On("ListMappings", mock.Anything, uint(1), domain.ImportSourceYNAB).Return([]domain.CategoryMapping{
+			{ID: 1, UserID: 1, Source: domain.ImportSourceYNAB, ExternalCategory: "Dining Out", CategoryID: 3},
+		}, nil)
+
+		w := httptest.NewRecorder()
+		setupImportRouter(service).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1/import-mappings?source=ynab", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"count":1`)
+
+		w = httptest.NewRecorder()
+		setupImportRouter(service).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1/import-mappings?source=quicken", nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should set a mapping", func(t *testing.T) {
+		service := new(MockImportService)
+		mapping := domain.CategoryMapping{Source: domain.ImportSourceMint, ExternalCategory: "Coffee Shops", CategoryID: 3}
+		saved := mapping
+		saved.ID, saved.UserID = 5, 1
+		service.On("SetMapping", mock.Anything, uint(1), mapping).Return(&saved, nil)
+
+		body := `{"source":"mint","external_category":"Coffee Shops","category_id":3}`
+		w := httptest.NewRecorder()
+		setupImportRouter(service).ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/users/1/import-mappings", strings.NewReader(body)))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"id":5`)
+		service.AssertExpectations(t)
+	})
+
+	t.Run("should return 422 for invalid mappings", func(t *testing.T) {
+		service := new(MockImportService)
+		service.On("SetMapping", mock.Anything, uint(1), mock.Anything).Return(nil, &domain.ValidationError{
+			Fields: []domain.FieldError{{Field: "category_id", Message: "must be one of your categories"}},
+		})
+
+		body := `{"source":"mint","external_category":"Coffee Shops","category_id":99}`
+		w := httptest.NewRecorder()
+		setupImportRouter(service).ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/users/1/import-mappings", strings.NewReader(body)))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+
+	t.Run("should delete a mapping", func(t *testing.T) {
+		service := new(MockImportService)
+		service.On("DeleteMapping", mock.Anything, uint(1), uint(5)).Return(nil)
+		service.On("DeleteMapping", mock.Anything, uint(1), uint(6)).Return(domain.ErrNotFound)
+		router := setupImportRouter(service)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/users/1/import-mappings/5", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/users/1/import-mappings/6", nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/users/1/import-mappings/abc", nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,233 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IncomeSourceServiceInterface defines the interface for income source operations
+type IncomeSourceServiceInterface interface {
+	Create(ctx context.Context, userID uint, source *domain.IncomeSource) error
+	List(ctx context.Context, userID uint) ([]domain.IncomeSource, error)
+	Get(ctx context.Context, userID, id uint) (*domain.IncomeSource, error)
+	Update(ctx context.Context, userID uint, source *domain.IncomeSource) error
+	Delete(ctx context.Context, userID, id uint) error
+	LinkTransactions(ctx context.Context, userID, sourceID uint, transactionIDs []uint) error
+	UnlinkTransaction(ctx context.Context, userID, sourceID, transactionID uint) error
+}
+
+type IncomeSourceHandler struct {
+	Service IncomeSourceServiceInterface
+}
+
+func NewIncomeSourceHandler(service IncomeSourceServiceInterface) *IncomeSourceHandler {
+	return &IncomeSourceHandler{Service: service}
+}
+
+// CreateIncomeSourceRequest is the body of requests adding an income source,
+// with the gross pay and deductions of one paycheck
+type CreateIncomeSourceRequest struct {
+	Employer   string                     `json:"employer" binding:"required"`
+	Gross      domain.Money               `json:"gross"`
+	Deductions []domain.PaycheckDeduction `json:"deductions"`
+	Frequency  string                     `json:"frequency" binding:"required"`
+}
+
+type UpdateIncomeSourceRequest struct {
+	Employer   *string                    `json:"employer,omitempty"`
+	Gross      *domain.Money              `json:"gross,omitempty"`
+	Deductions []domain.PaycheckDeduction `json:"deductions,omitempty"`
+	Frequency  *string                    `json:"frequency,omitempty"`
+	Active     *bool                      `json:"active,omitempty"`
+}
+
+// LinkIncomeTransactionsRequest lists the income transactions paid by a source
+type LinkIncomeTransactionsRequest struct {
+	TransactionIDs []uint `json:"transaction_ids" binding:"required"`
+}
+
+// parseIncomeSourceIDs reads the userId and sourceId parameters. Users can
+// only manage their own income sources.
+func parseIncomeSourceIDs(c *gin.Context) (userID, sourceID uint, ok bool) {
+	userID, ok = authorizedUserID(c)
+	if !ok {
+		return 0, 0, false
+	}
+	id, err := strconv.ParseUint(c.Param("sourceId"), 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid income source ID")
+		return 0, 0, false
+	}
+	return userID, uint(id), true
+}
+
+func respondIncomeSourceError(c *gin.Context, err error, message string) {
+	if respondValidationError(c, err) {
+		return
+	}
+	if errors.Is(err, domain.ErrNotFound) {
+		respondError(c, middleware.CodeNotFound, "Income source not found")
+		return
+	}
+	respondInternalError(c, message, err)
+}
+
+// Create adds an income source for the user
+func (h *IncomeSourceHandler) Create(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req CreateIncomeSourceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	source := &domain.IncomeSource{
+		Employer: req.Employer, Gross: req.Gross, Deductions: req.Deductions, Frequency: req.Frequency,
+	}
+	if err := h.Service.Create(c.Request.Context(), userID, source); err != nil {
+		respondIncomeSourceError(c, err, "Failed to create income source")
+		return
+	}
+	c.JSON(http.StatusCreated, source)
+}
+
+// List returns the user's income sources
+func (h *IncomeSourceHandler) List(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	sources, err := h.Service.List(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve income sources", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"income_sources": sources, "count": len(sources)})
+}
+
+// Get returns one income source
+func (h *IncomeSourceHandler) Get(c *gin.Context) {
+	userID, sourceID, ok := parseIncomeSourceIDs(c)
+	if !ok {
+		return
+	}
+
+	source, err := h.Service.Get(c.Request.Context(), userID, sourceID)
+	if err != nil {
+		respondIncomeSourceError(c, err, "Failed to retrieve income source")
+		return
+	}
+	c.JSON(http.StatusOK, source)
+}
+
+// Update changes the pay or deductions of an income source, or retires it.
+// Deductions, when given, replace all of the source's deductions.
+func (h *IncomeSourceHandler) Update(c *gin.Context) {
+	userID, sourceID, ok := parseIncomeSourceIDs(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateIncomeSourceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	source, err := h.Service.Get(c.Request.Context(), userID, sourceID)
+	if err != nil {
+		respondIncomeSourceError(c, err, "Failed to retrieve income source")
+		return
+	}
+	if req.Employer != nil {
+		source.Employer = *req.Employer
+	}
+	if req.Gross != nil {
+		source.Gross = *req.Gross
+	}
+	if req.Deductions != nil {
+		source.Deductions = req.Deductions
+	}
+	if req.Frequency != nil {
+		source.Frequency = *req.Frequency
+	}
+	if req.Active != nil {
+		source.Active = *req.Active
+	}
+
+	if err := h.Service.Update(c.Request.Context(), userID, source); err != nil {
+		respondIncomeSourceError(c, err, "Failed to update income source")
+		return
+	}
+	c.JSON(http.StatusOK, source)
+}
+
+// Delete removes an income source; its transactions stay
+func (h *IncomeSourceHandler) Delete(c *gin.Context) {
+	userID, sourceID, ok := parseIncomeSourceIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Service.Delete(c.Request.Context(), userID, sourceID); err != nil {
+		respondIncomeSourceError(c, err, "Failed to delete income source")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Income source deleted successfully"})
+}
+
+// LinkTransactions links income transactions to the source that paid them
+func (h *IncomeSourceHandler) LinkTransactions(c *gin.Context) {
+	userID, sourceID, ok := parseIncomeSourceIDs(c)
+	if !ok {
+		return
+	}
+
+	var req LinkIncomeTransactionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	if err := h.Service.LinkTransactions(c.Request.Context(), userID, sourceID, req.TransactionIDs); err != nil {
+		respondIncomeSourceError(c, err, "Failed to link transactions")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// UnlinkTransaction removes the link of a transaction to the source
+func (h *IncomeSourceHandler) UnlinkTransaction(c *gin.Context) {
+	userID, sourceID, ok := parseIncomeSourceIDs(c)
+	if !ok {
+		return
+	}
+	transactionID, err := strconv.ParseUint(c.Param("transactionId"), 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid transaction ID")
+		return
+	}
+
+	err = h.Service.UnlinkTransaction(c.Request.Context(), userID, sourceID, uint(transactionID))
+	if errors.Is(err, domain.ErrNotFound) {
+		respondError(c, middleware.CodeNotFound, "Linked transaction not found")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to unlink transaction", err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
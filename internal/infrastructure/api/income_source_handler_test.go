@@ -0,0 +1,132 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockIncomeSourceService is a mock implementation of IncomeSourceServiceInterface
+type MockIncomeSourceService struct {
+	mock.Mock
+}
+
+func (m *MockIncomeSourceService) Create(ctx context.Context, userID uint, source *domain.IncomeSource) error {
+	return m.Called(ctx, userID, source).Error(0)
+}
+
+func (m *MockIncomeSourceService) List(ctx context.Context, userID uint) ([]domain.IncomeSource, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.IncomeSource), args.Error(1)
+}
+
+func (m *MockIncomeSourceService) Get(ctx context.Context, userID, id uint) (*domain.IncomeSource, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.IncomeSource), args.Error(1)
+}
+
+func (m *MockIncomeSourceService) Update(ctx context.Context, userID uint, source *domain.IncomeSource) error {
+	return m.Called(ctx, userID, source).Error(0)
+}
+
+func (m *MockIncomeSourceService) Delete(ctx context.Context, userID, id uint) error {
+	return m.Called(ctx, userID, id).Error(0)
+}
+
+func (m *MockIncomeSourceService) LinkTransactions(ctx context.Context, userID, sourceID uint, transactionIDs []uint) error {
+	return m.Called(ctx, userID, sourceID, transactionIDs).Error(0)
+}
+
+func (m *MockIncomeSourceService) UnlinkTransaction(ctx context.Context, userID, sourceID, transactionID uint) error {
+	return m.Called(ctx, userID, sourceID, transactionID).Error(0)
+}
+
+func setupIncomeSourceRouter(service *MockIncomeSourceService) *gin.Engine {
+	handler := NewIncomeSourceHandler(service)
+	router := setupGin()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Next()
+	})
+	router.GET("/users/:userId/income-sources", handler.List)
+	router.POST("/users/:userId/income-sources", handler.Create)
+	router.GET("/users/:userId/income-sources/:sourceId", handler.Get)
+	router.PUT("/users/:userId/income-sources/:sourceId", handler.Update)
+	router.DELETE("/users/:userId/income-sources/:sourceId", handler.Delete)
+	router.POST("/users/:userId/income-sources/:sourceId/transactions", handler.LinkTransactions)
+	router.DELETE("/users/:userId/income-sources/:sourceId/transactions/:transactionId", handler.UnlinkTransaction)
+	return router
+}
+
+func TestIncomeSourceHandler(t *testing.T) {
+	serve := func(service *MockIncomeSourceService, method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		setupIncomeSourceRouter(service).ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("should create an income source with its deductions", func(t *testing.T) {
+		service := new(MockIncomeSourceService)
+		service.On("Create", mock.Anything, uint(1), mock.MatchedBy(func(source *domain.IncomeSource) bool {
+			return source.Employer == "Acme" && source.Gross == domain.NewMoney(5000) &&
+				len(source.Deductions) == 1 && source.Deductions[0].Kind == domain.DeductionTax
+		})).Return(nil)
+
+		body := `{"employer":"Acme","gross":5000,"frequency":"monthly","deductions":[{"name":"Income tax","kind":"tax","amount":1000}]}`
+		assert.Equal(t, http.StatusCreated, serve(service, http.MethodPost, "/users/1/income-sources", body).Code)
+		service.AssertExpectations(t)
+
+		assert.Equal(t, http.StatusBadRequest, serve(service, http.MethodPost, "/users/1/income-sources", `{"gross":5000}`).Code)
+	})
+
+	t.Run("should forbid other users' income sources", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, serve(new(MockIncomeSourceService), http.MethodGet, "/users/2/income-sources", "").Code)
+	})
+
+	t.Run("should update the given fields only", func(t *testing.T) {
+		service := new(MockIncomeSourceService)
+		source := &domain.IncomeSource{ID: 3, UserID: 1, Employer: "Acme", Gross: domain.NewMoney(5000), Frequency: "monthly", Active: true}
+		service.On("Get", mock.Anything, uint(1), uint(3)).Return(source, nil)
+		service.On("Update", mock.Anything, uint(1), mock.MatchedBy(func(source *domain.IncomeSource) bool {
+			return source.Employer == "Acme" && source.Gross == domain.NewMoney(5000) && !source.Active
+		})).Return(nil)
+
+		assert.Equal(t, http.StatusOK, serve(service, http.MethodPut, "/users/1/income-sources/3", `{"active":false}`).Code)
+		service.AssertExpectations(t)
+	})
+
+	t.Run("should return 404 for unknown income sources", func(t *testing.T) {
+		service := new(MockIncomeSourceService)
+		service.On("Delete", mock.Anything, uint(1), uint(9)).Return(domain.ErrNotFound)
+
+		assert.Equal(t, http.StatusNotFound, serve(service, http.MethodDelete, "/users/1/income-sources/9", "").Code)
+	})
+
+	t.Run("should link and unlink transactions", func(t *testing.T) {
+		service := new(MockIncomeSourceService)
+		service.On("LinkTransactions", mock.Anything, uint(1), uint(3), []uint{41, 42}).Return(nil)
+		service.On("LinkTransactions", mock.Anything, uint(1), uint(3), []uint{7}).Return(&domain.ValidationError{
+			Fields: []domain.FieldError{{Field: "transaction_ids", Message: "must all be income transactions of the user"}},
+		})
+		service.On("UnlinkTransaction", mock.Anything, uint(1), uint(3), uint(41)).Return(nil)
+
+		target := "/users/1/income-sources/3/transactions"
+		assert.Equal(t, http.StatusNoContent, serve(service, http.MethodPost, target, `{"transaction_ids":[41,42]}`).Code)
+		assert.Equal(t, http.StatusUnprocessableEntity, serve(service, http.MethodPost, target, `{"transaction_ids":[7]}`).Code)
+		assert.Equal(t, http.StatusNoContent, serve(service, http.MethodDelete, target+"/41", "").Code)
+		service.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"go-finance-advisor/internal/application"
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InsightsServiceInterface defines the interface for the spending insights service
+type InsightsServiceInterface interface {
+	GetInsights(ctx context.Context, userID uint, period string) (*domain.InsightsReport, error)
+}
+
+type InsightsHandler struct {
+	Service InsightsServiceInterface
+}
+
+func NewInsightsHandler(service InsightsServiceInterface) *InsightsHandler {
+	return &InsightsHandler{Service: service}
+}
+
+// GetInsights returns ranked spending insights for the current period.
+// Supports optional "period" (week, month, quarter, year) and "limit" query parameters.
+func (h *InsightsHandler) GetInsights(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	period := c.DefaultQuery("period", "month")
+	if !application.IsValidInsightPeriod(period) {
+		respondError(c, middleware.CodeBadRequest, "Invalid period. Use week, month, quarter, or year")
+		return
+	}
+
+	limit := 0
+	if limitStr := c.Query("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			respondError(c, middleware.CodeBadRequest, "Invalid limit")
+			return
+		}
+	}
+
+	report, err := h.Service.GetInsights(c.Request.Context(), userID, period)
+	if err != nil {
+		respondInternalError(c, "Failed to generate insights", err)
+		return
+	}
+
+	// Reports are shared through the service cache, so trim a copy
+	if limit > 0 && len(report.Insights) > limit {
+		trimmed := *report
+		trimmed.Insights = report.Insights[:limit]
+		report = &trimmed
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockInsightsService is a mock implementation of InsightsServiceInterface
+type MockInsightsService struct {
+	mock.Mock
+}
+
+func (m *MockInsightsService) GetInsights(ctx context.Context, userID uint, period string) (*domain.InsightsReport, error) {
+	args := m.Called(ctx, userID, period)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.InsightsReport), args.Error(1)
+}
+
+func setupInsightsHandler() (*InsightsHandler, *MockInsightsService) {
+	mockService := new(MockInsightsService)
+	return NewInsightsHandler(mockService), mockService
+}
+
+func TestInsightsHandler_GetInsights(t *testing.T) {
+	report := &domain.InsightsReport{
+		UserID: 1,
+		Period: "month",
+		Insights: []domain.Insight{
+			{Rank: 1, Type: domain.InsightCategorySpike, Message: "You spent 40% more on Dining this month than the previous month."},
+			{Rank: 2, Type: domain.InsightNewMerchant, Message: "New merchant this month: Blue Bottle ($50.00)."},
+		},
+	}
+
+	t.Run("should return insights with default period", func(t *testing.T) {
+		handler, mockService := setupInsightsHandler()
+		router := setupGin()
+		router.GET("/users/:userId/insights", handler.GetInsights)
+
+		mockService.On("GetInsights", mock.Anything, uint(1), "month").Return(report, nil)
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/users/1/insights", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response domain.InsightsReport
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response.Insights, 2)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should apply limit without modifying cached report", func(t *testing.T) {
+		handler, mockService := setupInsightsHandler()
+		router := setupGin()
+		router.GET("/users/:userId/insights", handler.GetInsights)
+
+		mockService.On("GetInsights", mock.Anything, uint(1), "week").Return(report, nil)
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/users/1/insights?period=week&limit=1", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response domain.InsightsReport
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response.Insights, 1)
+		assert.Len(t, report.Insights, 2)
+	})
+
+	t.Run("should reject invalid parameters", func(t *testing.T) {
+		handler, mockService := setupInsightsHandler()
+		router := setupGin()
+		router.GET("/users/:userId/insights", handler.GetInsights)
+
+		for _, url := range []string{"/users/abc/insights", "/users/1/insights?period=decade", "/users/1/insights?limit=-1"} {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+			assert.Equal(t, http.StatusBadRequest, w.Code, url)
+		}
+		mockService.AssertNotCalled(t, "GetInsights", mock.Anything)
+	})
+
+	t.Run("should return 500 on service error", func(t *testing.T) {
+		handler, mockService := setupInsightsHandler()
+		router := setupGin()
+		router.GET("/users/:userId/insights", handler.GetInsights)
+
+		mockService.On("GetInsights", mock.Anything, uint(1), "month").Return(nil, errors.New("db error"))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1/insights", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("should deny other users' insights", func(t *testing.T) {
+		handler, _ := setupInsightsHandler()
+		router := setupGin()
+		router.Use(func(c *gin.Context) {
+			c.Set("userID", uint(1))
+			c.Next()
+		})
+		router.GET("/users/:userId/insights", handler.GetInsights)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/users/2/insights", http.NoBody))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
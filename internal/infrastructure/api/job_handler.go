@@ -0,0 +1,103 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultJobLimit = 50
+	maxJobLimit     = 500
+)
+
+// JobServiceInterface defines the interface for inspecting the background job queue
+type JobServiceInterface interface {
+	List(ctx context.Context, filter domain.JobFilter) ([]domain.Job, error)
+	Get(ctx context.Context, id uint) (*domain.Job, error)
+	Requeue(ctx context.Context, id uint) (*domain.Job, error)
+}
+
+type JobHandler struct {
+	Service JobServiceInterface
+}
+
+func NewJobHandler(service JobServiceInterface) *JobHandler {
+	return &JobHandler{Service: service}
+}
+
+// List returns background jobs, optionally narrowed by type and status.
+// Jobs that ran out of attempts are listed with status=dead.
+func (h *JobHandler) List(c *gin.Context) {
+	filter := domain.JobFilter{Type: c.Query("type"), Status: c.Query("status"), Limit: defaultJobLimit}
+	if filter.Status != "" && !domain.IsValidJobStatus(filter.Status) {
+		respondError(c, middleware.CodeBadRequest, "status must be queued, running, succeeded or dead")
+		return
+	}
+	if limit, err := strconv.Atoi(c.Query("limit")); err == nil && limit > 0 {
+		filter.Limit = min(limit, maxJobLimit)
+	}
+	if offset, err := strconv.Atoi(c.Query("offset")); err == nil && offset > 0 {
+		filter.Offset = offset
+	}
+
+	jobs, err := h.Service.List(c.Request.Context(), filter)
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve jobs", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"jobs": jobs, "count": len(jobs)})
+}
+
+// Get returns a background job with its last error
+func (h *JobHandler) Get(c *gin.Context) {
+	id, ok := jobID(c)
+	if !ok {
+		return
+	}
+
+	job, err := h.Service.Get(c.Request.Context(), id)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "Job not found")
+	case err != nil:
+		respondInternalError(c, "Failed to retrieve job", err)
+	default:
+		c.JSON(http.StatusOK, job)
+	}
+}
+
+// Requeue runs a dead or succeeded job again
+func (h *JobHandler) Requeue(c *gin.Context) {
+	id, ok := jobID(c)
+	if !ok {
+		return
+	}
+
+	job, err := h.Service.Requeue(c.Request.Context(), id)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "Job not found")
+	case errors.Is(err, domain.ErrJobActive):
+		respondError(c, middleware.CodeConflict, err.Error())
+	case err != nil:
+		respondInternalError(c, "Failed to requeue job", err)
+	default:
+		c.JSON(http.StatusOK, job)
+	}
+}
+
+func jobID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("jobId"), 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid job ID")
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockJobService is a mock implementation of JobServiceInterface
+type MockJobService struct {
+	mock.Mock
+}
+
+func (m *MockJobService) List(ctx context.Context, filter domain.JobFilter) ([]domain.Job, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]domain.Job), args.Error(1)
+}
+
+func (m *MockJobService) Get(ctx context.Context, id uint) (*domain.Job, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Job), args.Error(1)
+}
+
+func (m *MockJobService) Requeue(ctx context.Context, id uint) (*domain.Job, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Job), args.Error(1)
+}
+
+func setupJobRouter(service *MockJobService) *gin.Engine {
+	handler := NewJobHandler(service)
+	router := setupGin()
+	router.GET("/admin/jobs", handler.List)
+	router.GET("/admin/jobs/:jobId", handler.Get)
+	router.POST("/admin/jobs/:jobId/requeue", handler.Requeue)
+	return router
+}
+
+func TestJobHandler_List(t *testing.T) {
+	t.Run("should list dead jobs", func(t *testing.T) {
+		service := new(MockJobService)
+		service.On("List", mock.Anything, domain.JobFilter{Status: domain.JobStatusDead, Limit: 10}).Return([]domain.Job{
+			{ID: 3, Type: "bank_sync", Status: domain.JobStatusDead, LastError: "timeout"},
+		}, nil)
+
+		w := httptest.NewRecorder()
+		setupJobRouter(service).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/jobs?status=dead&limit=10", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"count":1`)
+		assert.Contains(t, w.Body.String(), `"last_error":"timeout"`)
+		service.AssertExpectations(t)
+	})
+
+	t.Run("should return 400 for unknown statuses", func(t *testing.T) {
+		service := new(MockJobService)
+
+		w := httptest.NewRecorder()
+		setupJobRouter(service).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/jobs?status=failed", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		service.AssertNotCalled(t, "List")
+	})
+}
+
+func TestJobHandler_Requeue(t *testing.T) {
+	service := new(MockJobService)
+	service.On("Get", mock.Anything, uint(3)).Return(&domain.Job{ID: 3, Status: domain.JobStatusDead}, nil)
+	service.On("Get", mock.Anything, uint(4)).Return(nil, domain.ErrNotFound)
+	service.On("Requeue", mock.Anything, uint(3)).Return(&domain.Job{ID: 3, Status: domain.JobStatusQueued}, nil)
+	service.On("Requeue", mock.Anything, uint(5)).Return(nil, domain.ErrJobActive)
+	router := setupJobRouter(service)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/jobs/3", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/jobs/4", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/jobs/3/requeue", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"queued"`)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/jobs/5/requeue", nil))
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/jobs/abc/requeue", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
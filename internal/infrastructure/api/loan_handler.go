@@ -0,0 +1,305 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LoanServiceInterface defines the interface for loan operations
+type LoanServiceInterface interface {
+	Create(ctx context.Context, userID uint, loan *domain.Loan) error
+	List(ctx context.Context, userID uint) ([]domain.Loan, error)
+	Get(ctx context.Context, userID, id uint) (*domain.Loan, error)
+	Update(ctx context.Context, userID uint, loan *domain.Loan) error
+	Delete(ctx context.Context, userID, id uint) error
+	Schedule(ctx context.Context, userID, loanID uint) (*domain.LoanSchedule, error)
+	Payoff(ctx context.Context, userID, loanID uint, extra domain.LoanExtraPayments) (*domain.LoanPayoffProjection, error)
+	LinkPayment(ctx context.Context, userID, loanID, transactionID uint, number int) (*domain.LoanPayment, error)
+	UnlinkPayment(ctx context.Context, userID, loanID uint, number int) error
+}
+
+type LoanHandler struct {
+	Service LoanServiceInterface
+}
+
+func NewLoanHandler(service LoanServiceInterface) *LoanHandler {
+	return &LoanHandler{Service: service}
+}
+
+// CreateLoanRequest is the body of requests adding a loan
+type CreateLoanRequest struct {
+	Name       string       `json:"name" binding:"required"`
+	Lender     string       `json:"lender"`
+	Principal  domain.Money `json:"principal"`
+	APR        float64      `json:"apr"`
+	TermMonths int          `json:"term_months" binding:"required"`
+	StartDate  string       `json:"start_date" binding:"required"`
+}
+
+type UpdateLoanRequest struct {
+	Name       *string       `json:"name,omitempty"`
+	Lender     *string       `json:"lender,omitempty"`
+	Principal  *domain.Money `json:"principal,omitempty"`
+	APR        *float64      `json:"apr,omitempty"`
+	TermMonths *int          `json:"term_months,omitempty"`
+	StartDate  *string       `json:"start_date,omitempty"`
+}
+
+// LinkLoanPaymentRequest links a transaction to a row of a loan's schedule,
+// the first one not paid yet without a number
+type LinkLoanPaymentRequest struct {
+	TransactionID uint `json:"transaction_id" binding:"required"`
+	Number        int  `json:"number"`
+}
+
+// parseLoanIDs reads the userId and loanId parameters. Users can only
+// manage their own loans.
+func parseLoanIDs(c *gin.Context) (userID, loanID uint, ok bool) {
+	userID, ok = authorizedUserID(c)
+	if !ok {
+		return 0, 0, false
+	}
+	id, err := strconv.ParseUint(c.Param("loanId"), 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid loan ID")
+		return 0, 0, false
+	}
+	return userID, uint(id), true
+}
+
+func respondLoanError(c *gin.Context, err error, message string) {
+	if respondValidationError(c, err) {
+		return
+	}
+	if errors.Is(err, domain.ErrNotFound) {
+		respondError(c, middleware.CodeNotFound, "Loan not found")
+		return
+	}
+	respondInternalError(c, message, err)
+}
+
+// Create adds a loan for the user
+func (h *LoanHandler) Create(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req CreateLoanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidDate, "Invalid start date format. Use YYYY-MM-DD")
+		return
+	}
+
+	loan := &domain.Loan{
+		Name: req.Name, Lender: req.Lender, Principal: req.Principal, APR: req.APR,
+		TermMonths: req.TermMonths, StartDate: startDate,
+	}
+	if err := h.Service.Create(c.Request.Context(), userID, loan); err != nil {
+		respondLoanError(c, err, "Failed to create loan")
+		return
+	}
+	c.JSON(http.StatusCreated, loan)
+}
+
+// List returns the user's loans
+func (h *LoanHandler) List(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	loans, err := h.Service.List(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve loans", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"loans": loans, "count": len(loans)})
+}
+
+// Get returns one loan
+func (h *LoanHandler) Get(c *gin.Context) {
+	userID, loanID, ok := parseLoanIDs(c)
+	if !ok {
+		return
+	}
+
+	loan, err := h.Service.Get(c.Request.Context(), userID, loanID)
+	if err != nil {
+		respondLoanError(c, err, "Failed to retrieve loan")
+		return
+	}
+	c.JSON(http.StatusOK, loan)
+}
+
+// Update changes the details or terms of a loan
+func (h *LoanHandler) Update(c *gin.Context) {
+	userID, loanID, ok := parseLoanIDs(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateLoanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	loan, err := h.Service.Get(c.Request.Context(), userID, loanID)
+	if err != nil {
+		respondLoanError(c, err, "Failed to retrieve loan")
+		return
+	}
+	if req.Name != nil {
+		loan.Name = *req.Name
+	}
+	if req.Lender != nil {
+		loan.Lender = *req.Lender
+	}
+	if req.Principal != nil {
+		loan.Principal = *req.Principal
+	}
+	if req.APR != nil {
+		loan.APR = *req.APR
+	}
+	if req.TermMonths != nil {
+		loan.TermMonths = *req.TermMonths
+	}
+	if req.StartDate != nil {
+		startDate, err := time.Parse("2006-01-02", *req.StartDate)
+		if err != nil {
+			respondError(c, middleware.CodeInvalidDate, "Invalid start date format. Use YYYY-MM-DD")
+			return
+		}
+		loan.StartDate = startDate
+	}
+
+	if err := h.Service.Update(c.Request.Context(), userID, loan); err != nil {
+		respondLoanError(c, err, "Failed to update loan")
+		return
+	}
+	c.JSON(http.StatusOK, loan)
+}
+
+// Delete removes a loan
+func (h *LoanHandler) Delete(c *gin.Context) {
+	userID, loanID, ok := parseLoanIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Service.Delete(c.Request.Context(), userID, loanID); err != nil {
+		respondLoanError(c, err, "Failed to delete loan")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Loan deleted successfully"})
+}
+
+// Schedule returns the loan's amortization schedule
+func (h *LoanHandler) Schedule(c *gin.Context) {
+	userID, loanID, ok := parseLoanIDs(c)
+	if !ok {
+		return
+	}
+
+	schedule, err := h.Service.Schedule(c.Request.Context(), userID, loanID)
+	if err != nil {
+		respondLoanError(c, err, "Failed to build the loan schedule")
+		return
+	}
+	c.JSON(http.StatusOK, schedule)
+}
+
+// Payoff projects when the loan is paid off, with the extra_monthly,
+// lump_sum and lump_sum_number what-if payments of the query
+func (h *LoanHandler) Payoff(c *gin.Context) {
+	userID, loanID, ok := parseLoanIDs(c)
+	if !ok {
+		return
+	}
+
+	var extra domain.LoanExtraPayments
+	for name, target := range map[string]*domain.Money{"extra_monthly": &extra.Monthly, "lump_sum": &extra.LumpSum} {
+		if value := c.Query(name); value != "" {
+			amount, err := domain.ParseMoney(value)
+			if err != nil {
+				respondError(c, middleware.CodeBadRequest, "Invalid "+name)
+				return
+			}
+			*target = amount
+		}
+	}
+	if value := c.Query("lump_sum_number"); value != "" {
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			respondError(c, middleware.CodeBadRequest, "Invalid lump_sum_number")
+			return
+		}
+		extra.LumpSumNumber = number
+	}
+
+	projection, err := h.Service.Payoff(c.Request.Context(), userID, loanID, extra)
+	if err != nil {
+		respondLoanError(c, err, "Failed to project the loan payoff")
+		return
+	}
+	c.JSON(http.StatusOK, projection)
+}
+
+// LinkPayment records that a transaction paid a row of the loan's schedule
+func (h *LoanHandler) LinkPayment(c *gin.Context) {
+	userID, loanID, ok := parseLoanIDs(c)
+	if !ok {
+		return
+	}
+
+	var req LinkLoanPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	payment, err := h.Service.LinkPayment(c.Request.Context(), userID, loanID, req.TransactionID, req.Number)
+	if err != nil {
+		respondLoanError(c, err, "Failed to link the loan payment")
+		return
+	}
+	c.JSON(http.StatusCreated, payment)
+}
+
+// UnlinkPayment removes the transaction linked to a row of the schedule
+func (h *LoanHandler) UnlinkPayment(c *gin.Context) {
+	userID, loanID, ok := parseLoanIDs(c)
+	if !ok {
+		return
+	}
+	number, err := strconv.Atoi(c.Param("number"))
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid payment number")
+		return
+	}
+
+	err = h.Service.UnlinkPayment(c.Request.Context(), userID, loanID, number)
+	if errors.Is(err, domain.ErrNotFound) {
+		respondError(c, middleware.CodeNotFound, "Loan payment not found")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to unlink the loan payment", err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,164 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockLoanService is a mock implementation of LoanServiceInterface
+type MockLoanService struct {
+	mock.Mock
+}
+
+func (m *MockLoanService) Create(ctx context.Context, userID uint, loan *domain.Loan) error {
+	return m.Called(ctx, userID, loan).Error(0)
+}
+
+func (m *MockLoanService) List(ctx context.Context, userID uint) ([]domain.Loan, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.Loan), args.Error(1)
+}
+
+func (m *MockLoanService) Get(ctx context.Context, userID, id uint) (*domain.Loan, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Loan), args.Error(1)
+}
+
+func (m *MockLoanService) Update(ctx context.Context, userID uint, loan *domain.Loan) error {
+	return m.Called(ctx, userID, loan).Error(0)
+}
+
+func (m *MockLoanService) Delete(ctx context.Context, userID, id uint) error {
+	return m.Called(ctx, userID, id).Error(0)
+}
+
+func (m *MockLoanService) Schedule(ctx context.Context, userID, loanID uint) (*domain.LoanSchedule, error) {
+	args := m.Called(ctx, userID, loanID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.LoanSchedule), args.Error(1)
+}
+
+func (m *MockLoanService) Payoff(ctx context.Context, userID, loanID uint, extra domain.LoanExtraPayments) (*domain.LoanPayoffProjection, error) {
+	args := m.Called(ctx, userID, loanID, extra)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.LoanPayoffProjection), args.Error(1)
+}
+
+func (m *MockLoanService) LinkPayment(ctx context.Context, userID, loanID, transactionID uint, number int) (*domain.LoanPayment, error) {
+	args := m.Called(ctx, userID, loanID, transactionID, number)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.LoanPayment), args.Error(1)
+}
+
+func (m *MockLoanService) UnlinkPayment(ctx context.Context, userID, loanID uint, number int) error {
+	return m.Called(ctx, userID, loanID, number).Error(0)
+}
+
+func setupLoanRouter(service *MockLoanService) *gin.Engine {
+	handler := NewLoanHandler(service)
+	router := setupGin()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Next()
+	})
+	router.GET("/users/:userId/loans", handler.List)
+	router.POST("/users/:userId/loans", handler.Create)
+	router.GET("/users/:userId/loans/:loanId", handler.Get)
+	router.PUT("/users/:userId/loans/:loanId", handler.Update)
+	router.DELETE("/users/:userId/loans/:loanId", handler.Delete)
+	router.GET("/users/:userId/loans/:loanId/schedule", handler.Schedule)
+	router.GET("/users/:userId/loans/:loanId/payoff", handler.Payoff)
+	router.POST("/users/:userId/loans/:loanId/payments", handler.LinkPayment)
+	router.DELETE("/users/:userId/loans/:loanId/payments/:number", handler.UnlinkPayment)
+	return router
+}
+
+func TestLoanHandler(t *testing.T) {
+	serve := func(service *MockLoanService, method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		setupLoanRouter(service).ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("should create a loan", func(t *testing.T) {
+		service := new(MockLoanService)
+		service.On("Create", mock.Anything, uint(1), mock.MatchedBy(func(loan *domain.Loan) bool {
+			return loan.Name == "Car" && loan.Principal == domain.NewMoney(10000) && loan.APR == 6 &&
+				loan.TermMonths == 48 && loan.StartDate.Equal(time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC))
+		})).Return(nil)
+
+		w := serve(service, http.MethodPost, "/users/1/loans", `{"name":"Car","principal":10000,"apr":6,"term_months":48,"start_date":"2025-03-01"}`)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		service.AssertExpectations(t)
+
+		w = serve(service, http.MethodPost, "/users/1/loans", `{"name":"Car","principal":10000,"term_months":48,"start_date":"March"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should forbid other users' loans", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, serve(new(MockLoanService), http.MethodGet, "/users/2/loans", "").Code)
+	})
+
+	t.Run("should update the given fields only", func(t *testing.T) {
+		service := new(MockLoanService)
+		loan := &domain.Loan{ID: 3, UserID: 1, Name: "Car", Principal: domain.NewMoney(10000), APR: 6, TermMonths: 48}
+		service.On("Get", mock.Anything, uint(1), uint(3)).Return(loan, nil)
+		service.On("Update", mock.Anything, uint(1), mock.MatchedBy(func(loan *domain.Loan) bool {
+			return loan.Name == "Car" && loan.APR == 4.5 && loan.TermMonths == 48
+		})).Return(nil)
+
+		assert.Equal(t, http.StatusOK, serve(service, http.MethodPut, "/users/1/loans/3", `{"apr":4.5}`).Code)
+		service.AssertExpectations(t)
+	})
+
+	t.Run("should return 404 for unknown loans", func(t *testing.T) {
+		service := new(MockLoanService)
+		service.On("Schedule", mock.Anything, uint(1), uint(9)).Return(nil, domain.ErrNotFound)
+
+		assert.Equal(t, http.StatusNotFound, serve(service, http.MethodGet, "/users/1/loans/9/schedule", "").Code)
+	})
+
+	t.Run("should project the payoff with the query's extra payments", func(t *testing.T) {
+		service := new(MockLoanService)
+		extra := domain.LoanExtraPayments{Monthly: domain.NewMoney(100), LumpSum: domain.NewMoney(2000), LumpSumNumber: 6}
+		service.On("Payoff", mock.Anything, uint(1), uint(3), extra).Return(&domain.LoanPayoffProjection{LoanID: 3, MonthsSaved: 9}, nil)
+
+		w := serve(service, http.MethodGet, "/users/1/loans/3/payoff?extra_monthly=100&lump_sum=2000&lump_sum_number=6", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"months_saved":9`)
+		assert.Equal(t, http.StatusBadRequest, serve(service, http.MethodGet, "/users/1/loans/3/payoff?extra_monthly=lots", "").Code)
+	})
+
+	t.Run("should link and unlink payments", func(t *testing.T) {
+		service := new(MockLoanService)
+		service.On("LinkPayment", mock.Anything, uint(1), uint(3), uint(42), 0).Return(&domain.LoanPayment{LoanID: 3, Number: 1, TransactionID: 42}, nil)
+		service.On("UnlinkPayment", mock.Anything, uint(1), uint(3), 1).Return(nil)
+		service.On("UnlinkPayment", mock.Anything, uint(1), uint(3), 2).Return(domain.ErrNotFound)
+
+		assert.Equal(t, http.StatusCreated, serve(service, http.MethodPost, "/users/1/loans/3/payments", `{"transaction_id":42}`).Code)
+		assert.Equal(t, http.StatusNoContent, serve(service, http.MethodDelete, "/users/1/loans/3/payments/1", "").Code)
+		assert.Equal(t, http.StatusNotFound, serve(service, http.MethodDelete, "/users/1/loans/3/payments/2", "").Code)
+		service.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,20 @@
+package api
+
+import (
+	"os"
+	"testing"
+
+	"go-finance-advisor/internal/config"
+	"go-finance-advisor/internal/infrastructure/middleware"
+)
+
+func TestMain(m *testing.M) {
+	// Register and Login issue tokens, which need a configured signing key
+	authConfig := config.Default().Auth
+	authConfig.JWTSecret = "test-secret"
+	if err := middleware.ConfigureJWT(authConfig); err != nil {
+		panic(err)
+	}
+
+	os.Exit(m.Run())
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MerchantServiceInterface defines the interface for merchant management
+type MerchantServiceInterface interface {
+	ListMerchants(ctx context.Context) ([]domain.Merchant, error)
+	AddMerchant(ctx context.Context, name string, rules []domain.MerchantRule) (*domain.Merchant, error)
+	Rematch(ctx context.Context, userID uint) (int64, error)
+}
+
+type MerchantHandler struct {
+	Service MerchantServiceInterface
+}
+
+func NewMerchantHandler(service MerchantServiceInterface) *MerchantHandler {
+	return &MerchantHandler{Service: service}
+}
+
+type MerchantRuleRequest struct {
+	Pattern   string `json:"pattern" binding:"required,max=100"`
+	MatchType string `json:"match_type" binding:"omitempty,oneof=prefix contains"`
+}
+
+type AddMerchantRequest struct {
+	Name  string                `json:"name" binding:"required,max=100"`
+	Rules []MerchantRuleRequest `json:"rules" binding:"dive"`
+}
+
+// List returns all merchants with their matching rules
+func (h *MerchantHandler) List(c *gin.Context) {
+	merchants, err := h.Service.ListMerchants(c.Request.Context())
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve merchants", err)
+		return
+	}
+	c.JSON(http.StatusOK, merchants)
+}
+
+// Add creates a merchant or adds rules to an existing one with the same name
+func (h *MerchantHandler) Add(c *gin.Context) {
+	var req AddMerchantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	rules := make([]domain.MerchantRule, len(req.Rules))
+	for i, rule := range req.Rules {
+		rules[i] = domain.MerchantRule{Pattern: rule.Pattern, MatchType: rule.MatchType}
+	}
+
+	merchant, err := h.Service.AddMerchant(c.Request.Context(), req.Name, rules)
+	if err != nil {
+		respondError(c, middleware.CodeBadRequest, err.Error())
+		return
+	}
+	c.JSON(http.StatusCreated, merchant)
+}
+
+// Rematch applies the current rules to existing transactions, for one user
+// when user_id is given and for everyone otherwise
+func (h *MerchantHandler) Rematch(c *gin.Context) {
+	userID, err := parseOptionalID(c.Query("user_id"))
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid user_id")
+		return
+	}
+
+	updated, err := h.Service.Rematch(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, "Failed to rematch merchants", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Merchants rematched", "updated": updated})
+}
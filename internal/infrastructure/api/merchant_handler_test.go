@@ -0,0 +1,91 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockMerchantService is a mock implementation of MerchantServiceInterface
+type MockMerchantService struct {
+	mock.Mock
+}
+
+func (m *MockMerchantService) ListMerchants(ctx context.Context) ([]domain.Merchant, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.Merchant), args.Error(1)
+}
+
+func (m *MockMerchantService) AddMerchant(ctx context.Context, name string, rules []domain.MerchantRule) (*domain.Merchant, error) {
+	args := m.Called(ctx, name, rules)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Merchant), args.Error(1)
+}
+
+func (m *MockMerchantService) Rematch(ctx context.Context, userID uint) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestMerchantHandler_Add(t *testing.T) {
+	t.Run("should create a merchant with rules", func(t *testing.T) {
+		mockService := new(MockMerchantService)
+		handler := NewMerchantHandler(mockService)
+		router := setupGin()
+		router.POST("/admin/merchants", handler.Add)
+
+		rules := []domain.MerchantRule{{Pattern: "BLUE BOTTLE", MatchType: domain.MerchantMatchContains}}
+		mockService.On("AddMerchant", mock.Anything, "Blue Bottle Coffee", rules).
+			Return(&domain.Merchant{ID: 4, Name: "Blue Bottle Coffee", Rules: rules}, nil)
+
+		body := `{"name":"Blue Bottle Coffee","rules":[{"pattern":"BLUE BOTTLE","match_type":"contains"}]}`
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/merchants", bytes.NewBufferString(body)))
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var merchant domain.Merchant
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &merchant))
+		assert.Equal(t, uint(4), merchant.ID)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should reject unknown match types", func(t *testing.T) {
+		handler := NewMerchantHandler(new(MockMerchantService))
+		router := setupGin()
+		router.POST("/admin/merchants", handler.Add)
+
+		body := `{"name":"Blue Bottle","rules":[{"pattern":"BLUE","match_type":"regex"}]}`
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/merchants", bytes.NewBufferString(body)))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestMerchantHandler_Rematch(t *testing.T) {
+	mockService := new(MockMerchantService)
+	handler := NewMerchantHandler(mockService)
+	router := setupGin()
+	router.POST("/admin/merchants/rematch", handler.Rematch)
+
+	mockService.On("Rematch", mock.Anything, uint(3)).Return(int64(12), nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/merchants/rematch?user_id=3", http.NoBody))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, float64(12), response["updated"])
+}
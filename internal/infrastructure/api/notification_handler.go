@@ -0,0 +1,217 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxNotificationLimit = 200
+
+// NotificationServiceInterface defines the interface for in-app notifications
+type NotificationServiceInterface interface {
+	List(ctx context.Context, userID uint, unreadOnly bool, limit int) ([]domain.Notification, error)
+	MarkRead(ctx context.Context, userID, id uint) error
+	MarkAllRead(ctx context.Context, userID uint) (int64, error)
+	RegisterDevice(ctx context.Context, userID uint, device *domain.PushDevice) error
+	ListDevices(ctx context.Context, userID uint) ([]domain.PushDevice, error)
+	DeleteDevice(ctx context.Context, userID, id uint) error
+	Preferences(ctx context.Context, userID uint) ([]domain.NotificationPreference, error)
+	UpdatePreferences(ctx context.Context, userID uint, preferences []domain.NotificationPreference) ([]domain.NotificationPreference, error)
+}
+
+type NotificationHandler struct {
+	Service NotificationServiceInterface
+}
+
+func NewNotificationHandler(service NotificationServiceInterface) *NotificationHandler {
+	return &NotificationHandler{Service: service}
+}
+
+// DeviceRequest is the body of device registrations
+type DeviceRequest struct {
+	Platform string `json:"platform" binding:"required"`
+	Token    string `json:"token" binding:"required"`
+	Name     string `json:"name"`
+}
+
+// NotificationPreferencesRequest is the body of notification preference
+// updates; toggles left out stay as they are
+type NotificationPreferencesRequest struct {
+	Preferences []struct {
+		Channel string `json:"channel"`
+		Type    string `json:"type"`
+		Enabled bool   `json:"enabled"`
+	} `json:"preferences" binding:"required"`
+}
+
+// List returns the user's notifications newest first; ?unread=true limits
+// them to the unread ones
+func (h *NotificationHandler) List(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+	unreadOnly, _ := strconv.ParseBool(c.Query("unread"))
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	limit = min(limit, maxNotificationLimit)
+
+	notifications, err := h.Service.List(c.Request.Context(), userID, unreadOnly, limit)
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve notifications", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"notifications": notifications, "count": len(notifications)})
+}
+
+// MarkRead marks a notification as read
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("notificationId"), 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid notification ID")
+		return
+	}
+
+	err = h.Service.MarkRead(c.Request.Context(), userID, uint(id))
+	if errors.Is(err, domain.ErrNotFound) {
+		respondError(c, middleware.CodeNotFound, "Notification not found")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to update notification", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Notification marked as read"})
+}
+
+// MarkAllRead marks all of the user's notifications as read
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	updated, err := h.Service.MarkAllRead(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, "Failed to update notifications", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Notifications marked as read", "updated": updated})
+}
+
+// RegisterDevice registers a device of the mobile app for push notifications
+func (h *NotificationHandler) RegisterDevice(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req DeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	device := &domain.PushDevice{Platform: req.Platform, Token: req.Token, Name: req.Name}
+	if err := h.Service.RegisterDevice(c.Request.Context(), userID, device); err != nil {
+		if !respondValidationError(c, err) {
+			respondInternalError(c, "Failed to register device", err)
+		}
+		return
+	}
+	c.JSON(http.StatusCreated, device)
+}
+
+// ListDevices returns the devices push notifications are sent to
+func (h *NotificationHandler) ListDevices(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	devices, err := h.Service.ListDevices(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve devices", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"devices": devices, "count": len(devices)})
+}
+
+// DeleteDevice stops push notifications to a device
+func (h *NotificationHandler) DeleteDevice(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("deviceId"), 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid device ID")
+		return
+	}
+
+	err = h.Service.DeleteDevice(c.Request.Context(), userID, uint(id))
+	if errors.Is(err, domain.ErrNotFound) {
+		respondError(c, middleware.CodeNotFound, "Device not found")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to delete device", err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Preferences returns whether each type of notification is on in the app
+// and as a push
+func (h *NotificationHandler) Preferences(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	preferences, err := h.Service.Preferences(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve notification preferences", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"preferences": preferences})
+}
+
+// UpdatePreferences turns types of notifications on or off per channel
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req NotificationPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+	updates := make([]domain.NotificationPreference, 0, len(req.Preferences))
+	for _, preference := range req.Preferences {
+		updates = append(updates, domain.NotificationPreference{
+			Channel: preference.Channel, Type: preference.Type, Enabled: preference.Enabled,
+		})
+	}
+
+	preferences, err := h.Service.UpdatePreferences(c.Request.Context(), userID, updates)
+	if err != nil {
+		if !respondValidationError(c, err) {
+			respondInternalError(c, "Failed to update notification preferences", err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"preferences": preferences})
+}
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockNotificationService is a mock implementation of NotificationServiceInterface
+type MockNotificationService struct {
+	mock.Mock
+}
+
+func (m *MockNotificationService) List(ctx context.Context, userID uint, unreadOnly bool, limit int) ([]domain.Notification, error) {
+	args := m.Called(ctx, userID, unreadOnly, limit)
+	return args.Get(0).([]domain.Notification), args.Error(1)
+}
+
+func (m *MockNotificationService) MarkRead(ctx context.Context, userID, id uint) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func (m *MockNotificationService) MarkAllRead(ctx context.Context, userID uint) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockNotificationService) RegisterDevice(ctx context.Context, userID uint, device *domain.PushDevice) error {
+	args := m.Called(ctx, userID, device)
+	return args.Error(0)
+}
+
+func (m *MockNotificationService) ListDevices(ctx context.Context, userID uint) ([]domain.PushDevice, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.PushDevice), args.Error(1)
+}
+
+func (m *MockNotificationService) DeleteDevice(ctx context.Context, userID, id uint) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func (m *MockNotificationService) Preferences(ctx context.Context, userID uint) ([]domain.NotificationPreference, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.NotificationPreference), args.Error(1)
+}
+
+func (m *MockNotificationService) UpdatePreferences(
+	ctx context.Context, userID uint, preferences []domain.NotificationPreference,
+) ([]domain.NotificationPreference, error) {
+	args := m.Called(ctx, userID, preferences)
+	return args.Get(0).([]domain.NotificationPreference), args.Error(1)
+}
+
+func TestNotificationHandler(t *testing.T) {
+	mockService := new(MockNotificationService)
+	handler := NewNotificationHandler(mockService)
+	router := setupGin()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Next()
+	})
+	router.GET("/users/:userId/notifications", handler.List)
+	router.POST("/users/:userId/notifications/read", handler.MarkAllRead)
+	router.POST("/users/:userId/notifications/:notificationId/read", handler.MarkRead)
+
+	mockService.On("List", mock.Anything, uint(1), true, maxNotificationLimit).
+		Return([]domain.Notification{{ID: 1, Title: "Price alert: BTC"}}, nil)
+	mockService.On("MarkRead", mock.Anything, uint(1), uint(1)).Return(nil)
+	mockService.On("MarkRead", mock.Anything, uint(1), uint(2)).Return(domain.ErrNotFound)
+	mockService.On("MarkAllRead", mock.Anything, uint(1)).Return(int64(3), nil)
+
+	tests := []struct {
+		method     string
+		url        string
+		wantStatus int
+	}{
+		{http.MethodGet, "/users/1/notifications?unread=true&limit=1000", http.StatusOK},
+		{http.MethodGet, "/users/2/notifications", http.StatusForbidden},
+		{http.MethodPost, "/users/1/notifications/1/read", http.StatusOK},
+		{http.MethodPost, "/users/1/notifications/2/read", http.StatusNotFound},
+		{http.MethodPost, "/users/1/notifications/read", http.StatusOK},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.url, http.NoBody))
+		assert.Equal(t, tt.wantStatus, w.Code, tt.url)
+	}
+	mockService.AssertExpectations(t)
+}
+
+func TestNotificationHandler_DevicesAndPreferences(t *testing.T) {
+	mockService := new(MockNotificationService)
+	handler := NewNotificationHandler(mockService)
+	router := setupGin()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Next()
+	})
+	router.POST("/users/:userId/devices", handler.RegisterDevice)
+	router.GET("/users/:userId/devices", handler.ListDevices)
+	router.DELETE("/users/:userId/devices/:deviceId", handler.DeleteDevice)
+	router.GET("/users/:userId/notification-preferences", handler.Preferences)
+	router.PUT("/users/:userId/notification-preferences", handler.UpdatePreferences)
+
+	invalid := &domain.ValidationError{Fields: []domain.FieldError{{Field: "platform", Message: "must be android or ios"}}}
+	mockService.On("RegisterDevice", mock.Anything, uint(1), &domain.PushDevice{Platform: "ios", Token: "abc"}).Return(nil)
+	mockService.On("RegisterDevice", mock.Anything, uint(1), &domain.PushDevice{Platform: "web", Token: "abc"}).Return(invalid)
+	mockService.On("ListDevices", mock.Anything, uint(1)).Return([]domain.PushDevice{{ID: 1, Platform: "ios"}}, nil)
+	mockService.On("DeleteDevice", mock.Anything, uint(1), uint(1)).Return(nil)
+	mockService.On("DeleteDevice", mock.Anything, uint(1), uint(2)).Return(domain.ErrNotFound)
+	settings := domain.NotificationSettings(nil)
+	mockService.On("Preferences", mock.Anything, uint(1)).Return(settings, nil)
+	mockService.On("UpdatePreferences", mock.Anything, uint(1), []domain.NotificationPreference{
+		{Channel: domain.NotificationChannelPush, Type: domain.NotificationTypeGoal},
+	}).Return(settings, nil)
+
+	tests := []struct {
+		method     string
+		url        string
+		body       string
+		wantStatus int
+	}{
+		{http.MethodPost, "/users/1/devices", `{"platform": "ios", "token": "abc"}`, http.StatusCreated},
+		{http.MethodPost, "/users/1/devices", `{"platform": "web", "token": "abc"}`, http.StatusUnprocessableEntity},
+		{http.MethodPost, "/users/1/devices", `{"platform": "ios"}`, http.StatusBadRequest},
+		{http.MethodPost, "/users/2/devices", `{"platform": "ios", "token": "abc"}`, http.StatusForbidden},
+		{http.MethodGet, "/users/1/devices", "", http.StatusOK},
+		{http.MethodDelete, "/users/1/devices/1", "", http.StatusNoContent},
+		{http.MethodDelete, "/users/1/devices/2", "", http.StatusNotFound},
+		{http.MethodGet, "/users/1/notification-preferences", "", http.StatusOK},
+		{http.MethodPut, "/users/1/notification-preferences", `{"preferences": [{"channel": "push", "type": "goal", "enabled": false}]}`, http.StatusOK},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		assert.Equal(t, tt.wantStatus, w.Code, tt.method+" "+tt.url)
+	}
+	mockService.AssertExpectations(t)
+}
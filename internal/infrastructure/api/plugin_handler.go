@@ -0,0 +1,208 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PluginServiceInterface defines the interface for managing plugins
+type PluginServiceInterface interface {
+	Create(ctx context.Context, userID uint, plugin domain.Plugin) (*domain.Plugin, error)
+	List(ctx context.Context, userID uint) ([]domain.Plugin, error)
+	Get(ctx context.Context, userID, id uint) (*domain.Plugin, error)
+	Update(ctx context.Context, userID uint, plugin *domain.Plugin) error
+	Delete(ctx context.Context, userID, id uint) error
+	Runs(ctx context.Context, userID, pluginID uint, limit int) ([]domain.PluginRun, error)
+}
+
+type PluginHandler struct {
+	Service PluginServiceInterface
+}
+
+func NewPluginHandler(service PluginServiceInterface) *PluginHandler {
+	return &PluginHandler{Service: service}
+}
+
+// CreatePluginRequest is the body of requests adding a plugin. Without
+// events the plugin is called for all of them.
+type CreatePluginRequest struct {
+	Name           string   `json:"name" binding:"required"`
+	URL            string   `json:"url" binding:"required"`
+	Events         []string `json:"events"`
+	TimeoutSeconds int      `json:"timeout_seconds"`
+}
+
+type UpdatePluginRequest struct {
+	Name           *string  `json:"name,omitempty"`
+	URL            *string  `json:"url,omitempty"`
+	Events         []string `json:"events,omitempty"`
+	Enabled        *bool    `json:"enabled,omitempty"`
+	TimeoutSeconds *int     `json:"timeout_seconds,omitempty"`
+}
+
+// parsePluginIDs reads the userId and pluginId parameters. Users can only
+// manage their own plugins.
+func parsePluginIDs(c *gin.Context) (userID, pluginID uint, ok bool) {
+	userID, ok = authorizedUserID(c)
+	if !ok {
+		return 0, 0, false
+	}
+	id, err := strconv.ParseUint(c.Param("pluginId"), 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid plugin ID")
+		return 0, 0, false
+	}
+	return userID, uint(id), true
+}
+
+func respondPluginError(c *gin.Context, err error, message string) {
+	if respondValidationError(c, err) {
+		return
+	}
+	if errors.Is(err, domain.ErrNotFound) {
+		respondError(c, middleware.CodeNotFound, "Plugin not found")
+		return
+	}
+	respondInternalError(c, message, err)
+}
+
+// List returns the user's plugins
+func (h *PluginHandler) List(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	plugins, err := h.Service.List(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve plugins", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"plugins": plugins, "count": len(plugins)})
+}
+
+// Create adds a plugin and returns it with the secret its calls are signed
+// with, which is not shown again
+func (h *PluginHandler) Create(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req CreatePluginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	plugin, err := h.Service.Create(c.Request.Context(), userID, domain.Plugin{
+		Name: req.Name, URL: req.URL, Events: req.Events, TimeoutSeconds: req.TimeoutSeconds,
+	})
+	if err != nil {
+		respondPluginError(c, err, "Failed to create plugin")
+		return
+	}
+	c.JSON(http.StatusCreated, plugin)
+}
+
+// Get returns one plugin
+func (h *PluginHandler) Get(c *gin.Context) {
+	userID, pluginID, ok := parsePluginIDs(c)
+	if !ok {
+		return
+	}
+
+	plugin, err := h.Service.Get(c.Request.Context(), userID, pluginID)
+	if err != nil {
+		respondPluginError(c, err, "Failed to retrieve plugin")
+		return
+	}
+	c.JSON(http.StatusOK, plugin)
+}
+
+// Update changes a plugin, or enables or disables it. Events, when given,
+// replace the plugin's events.
+func (h *PluginHandler) Update(c *gin.Context) {
+	userID, pluginID, ok := parsePluginIDs(c)
+	if !ok {
+		return
+	}
+
+	var req UpdatePluginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	plugin, err := h.Service.Get(c.Request.Context(), userID, pluginID)
+	if err != nil {
+		respondPluginError(c, err, "Failed to retrieve plugin")
+		return
+	}
+	if req.Name != nil {
+		plugin.Name = *req.Name
+	}
+	if req.URL != nil {
+		plugin.URL = *req.URL
+	}
+	if req.Events != nil {
+		plugin.Events = req.Events
+	}
+	if req.Enabled != nil {
+		plugin.Enabled = *req.Enabled
+	}
+	if req.TimeoutSeconds != nil {
+		plugin.TimeoutSeconds = *req.TimeoutSeconds
+	}
+
+	if err := h.Service.Update(c.Request.Context(), userID, plugin); err != nil {
+		respondPluginError(c, err, "Failed to update plugin")
+		return
+	}
+	c.JSON(http.StatusOK, plugin)
+}
+
+// Delete removes a plugin and its runs
+func (h *PluginHandler) Delete(c *gin.Context) {
+	userID, pluginID, ok := parsePluginIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Service.Delete(c.Request.Context(), userID, pluginID); err != nil {
+		respondPluginError(c, err, "Failed to delete plugin")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Plugin deleted successfully"})
+}
+
+// Runs returns the latest runs of a plugin with the actions they took,
+// newest first, up to limit
+func (h *PluginHandler) Runs(c *gin.Context) {
+	userID, pluginID, ok := parsePluginIDs(c)
+	if !ok {
+		return
+	}
+	limit := 0
+	if value := c.Query("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit < 1 {
+			respondError(c, middleware.CodeBadRequest, "Invalid limit")
+			return
+		}
+	}
+
+	runs, err := h.Service.Runs(c.Request.Context(), userID, pluginID, limit)
+	if err != nil {
+		respondPluginError(c, err, "Failed to retrieve plugin runs")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"runs": runs, "count": len(runs)})
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPluginService is a mock implementation of PluginServiceInterface
+type MockPluginService struct {
+	mock.Mock
+}
+
+func (m *MockPluginService) Create(ctx context.Context, userID uint, plugin domain.Plugin) (*domain.Plugin, error) {
+	args := m.Called(ctx, userID, plugin)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Plugin), args.Error(1)
+}
+
+func (m *MockPluginService) List(ctx context.Context, userID uint) ([]domain.Plugin, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.Plugin), args.Error(1)
+}
+
+func (m *MockPluginService) Get(ctx context.Context, userID, id uint) (*domain.Plugin, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Plugin), args.Error(1)
+}
+
+func (m *MockPluginService) Update(ctx context.Context, userID uint, plugin *domain.Plugin) error {
+	return m.Called(ctx, userID, plugin).Error(0)
+}
+
+func (m *MockPluginService) Delete(ctx context.Context, userID, id uint) error {
+	return m.Called(ctx, userID, id).Error(0)
+}
+
+func (m *MockPluginService) Runs(ctx context.Context, userID, pluginID uint, limit int) ([]domain.PluginRun, error) {
+	args := m.Called(ctx, userID, pluginID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.PluginRun), args.Error(1)
+}
+
+func setupPluginRouter(service *MockPluginService) *gin.Engine {
+	handler := NewPluginHandler(service)
+	router := setupGin()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Next()
+	})
+	router.GET("/users/:userId/plugins", handler.List)
+	router.POST("/users/:userId/plugins", handler.Create)
+	router.GET("/users/:userId/plugins/:pluginId", handler.Get)
+	router.PUT("/users/:userId/plugins/:pluginId", handler.Update)
+	router.DELETE("/users/:userId/plugins/:pluginId", handler.Delete)
+	router.GET("/users/:userId/plugins/:pluginId/runs", handler.Runs)
+	return router
+}
+
+func TestPluginHandler(t *testing.T) {
+	serve := func(service *MockPluginService, method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		setupPluginRouter(service).ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("should create a plugin and show its secret", func(t *testing.T) {
+		service := new(MockPluginService)
+		plugin := domain.Plugin{Name: "Sync", URL: "https://example.com/hook", Events: []string{domain.EventTransactionCreated}, TimeoutSeconds: 3}
+		created := plugin
+		created.ID, created.Secret, created.Enabled = 4, "s3cret", true
+		service.On("Create", mock.Anything, uint(1), plugin).Return(&created, nil)
+
+		body := `{"name":"Sync","url":"https://example.com/hook","events":["transaction.created"],"timeout_seconds":3}`
+		w := serve(service, http.MethodPost, "/users/1/plugins", body)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"secret":"s3cret"`)
+		service.AssertExpectations(t)
+
+		assert.Equal(t, http.StatusBadRequest, serve(service, http.MethodPost, "/users/1/plugins", `{"name":"Sync"}`).Code)
+	})
+
+	t.Run("should forbid other users' plugins", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, serve(new(MockPluginService), http.MethodGet, "/users/2/plugins", "").Code)
+	})
+
+	t.Run("should disable a plugin", func(t *testing.T) {
+		service := new(MockPluginService)
+		plugin := &domain.Plugin{ID: 4, UserID: 1, Name: "Sync", URL: "https://example.com/hook", Enabled: true, TimeoutSeconds: 5}
+		service.On("Get", mock.Anything, uint(1), uint(4)).Return(plugin, nil)
+		service.On("Update", mock.Anything, uint(1), mock.MatchedBy(func(plugin *domain.Plugin) bool {
+			return plugin.Name == "Sync" && !plugin.Enabled && plugin.TimeoutSeconds == 5
+		})).Return(nil)
+
+		assert.Equal(t, http.StatusOK, serve(service, http.MethodPut, "/users/1/plugins/4", `{"enabled":false}`).Code)
+		service.AssertExpectations(t)
+	})
+
+	t.Run("should list a plugin's runs", func(t *testing.T) {
+		service := new(MockPluginService)
+		service.On("Runs", mock.Anything, uint(1), uint(4), 10).Return([]domain.PluginRun{
+			{ID: 1, PluginID: 4, Event: domain.EventTransactionCreated, Status: domain.PluginRunTimedOut},
+		}, nil)
+		service.On("Runs", mock.Anything, uint(1), uint(9), 0).Return(nil, domain.ErrNotFound)
+
+		w := serve(service, http.MethodGet, "/users/1/plugins/4/runs?limit=10", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"timed_out"`)
+		assert.Equal(t, http.StatusNotFound, serve(service, http.MethodGet, "/users/1/plugins/9/runs", "").Code)
+		assert.Equal(t, http.StatusBadRequest, serve(service, http.MethodGet, "/users/1/plugins/4/runs?limit=0", "").Code)
+		service.AssertExpectations(t)
+	})
+}
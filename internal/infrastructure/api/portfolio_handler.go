@@ -0,0 +1,157 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-finance-advisor/internal/application"
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PortfolioServiceInterface defines the interface for holdings and portfolio risk
+type PortfolioServiceInterface interface {
+	Holdings(ctx context.Context, userID uint) ([]domain.Holding, error)
+	SaveHolding(ctx context.Context, userID uint, holding domain.Holding) (*domain.Holding, error)
+	RemoveHolding(ctx context.Context, userID uint, symbol string) error
+	Risk(ctx context.Context, userID uint, days int, riskFreeRate float64) (*domain.PortfolioRisk, error)
+	NetWorth(ctx context.Context, userID uint) (*domain.NetWorth, error)
+}
+
+type PortfolioHandler struct {
+	Service PortfolioServiceInterface
+}
+
+func NewPortfolioHandler(service PortfolioServiceInterface) *PortfolioHandler {
+	return &PortfolioHandler{Service: service}
+}
+
+// HoldingRequest is the body of requests setting how much of a symbol a user owns
+type HoldingRequest struct {
+	Quantity  float64 `json:"quantity" binding:"required"`
+	AssetType string  `json:"asset_type"`
+}
+
+// ListHoldings returns the symbols the user owns
+func (h *PortfolioHandler) ListHoldings(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	holdings, err := h.Service.Holdings(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve holdings", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"holdings": holdings, "count": len(holdings)})
+}
+
+// SaveHolding sets the quantity the user owns of the symbol in the path
+func (h *PortfolioHandler) SaveHolding(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req HoldingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	holding, err := h.Service.SaveHolding(c.Request.Context(), userID, domain.Holding{
+		Symbol:    c.Param("symbol"),
+		AssetType: req.AssetType,
+		Quantity:  req.Quantity,
+	})
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, holding)
+	case respondValidationError(c, err):
+	case errors.Is(err, domain.ErrInvalidSymbol), errors.Is(err, application.ErrInvalidAssetType):
+		respondError(c, middleware.CodeBadRequest, err.Error())
+	default:
+		respondInternalError(c, "Failed to save holding", err)
+	}
+}
+
+// RemoveHolding takes a symbol out of the user's portfolio
+func (h *PortfolioHandler) RemoveHolding(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	err := h.Service.RemoveHolding(c.Request.Context(), userID, c.Param("symbol"))
+	switch {
+	case errors.Is(err, domain.ErrInvalidSymbol):
+		respondError(c, middleware.CodeBadRequest, err.Error())
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "Symbol is not held")
+	case err != nil:
+		respondInternalError(c, "Failed to remove holding", err)
+	default:
+		c.JSON(http.StatusOK, gin.H{"message": "Holding removed"})
+	}
+}
+
+// GetRisk measures the volatility, Sharpe ratio, drawdown and correlations of
+// the user's holdings over the last days (default 90), against an annual
+// risk_free_rate (default 0.02)
+func (h *PortfolioHandler) GetRisk(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	days := domain.DefaultPortfolioRiskDays
+	if value := c.Query("days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			respondError(c, middleware.CodeBadRequest, "days must be a whole number")
+			return
+		}
+		days = parsed
+	}
+	riskFreeRate := domain.DefaultRiskFreeRate
+	if value := c.Query("risk_free_rate"); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			respondError(c, middleware.CodeBadRequest, "risk_free_rate must be a number such as 0.02")
+			return
+		}
+		riskFreeRate = parsed
+	}
+
+	risk, err := h.Service.Risk(c.Request.Context(), userID, days, riskFreeRate)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, risk)
+	case respondValidationError(c, err):
+	case errors.Is(err, domain.ErrEmptyPortfolio), errors.Is(err, domain.ErrInsufficientPriceHistory):
+		respondError(c, middleware.CodeUnprocessable, err.Error())
+	default:
+		respondInternalError(c, "Failed to measure portfolio risk", err)
+	}
+}
+
+// GetNetWorth totals the user's account balances, holdings and wallets at
+// current prices
+func (h *PortfolioHandler) GetNetWorth(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	worth, err := h.Service.NetWorth(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, "Failed to calculate net worth", err)
+		return
+	}
+	c.JSON(http.StatusOK, worth)
+}
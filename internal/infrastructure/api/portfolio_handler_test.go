@@ -0,0 +1,226 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-finance-advisor/internal/application"
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPortfolioService is a mock implementation of PortfolioServiceInterface
+type MockPortfolioService struct {
+	mock.Mock
+}
+
+func (m *MockPortfolioService) Holdings(ctx context.Context, userID uint) ([]domain.Holding, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.Holding), args.Error(1)
+}
+
+func (m *MockPortfolioService) SaveHolding(ctx context.Context, userID uint, holding domain.Holding) (*domain.Holding, error) {
+	args := m.Called(ctx, userID, holding)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Holding), args.Error(1)
+}
+
+func (m *MockPortfolioService) RemoveHolding(ctx context.Context, userID uint, symbol string) error {
+	return m.Called(ctx, userID, symbol).Error(0)
+}
+
+func (m *MockPortfolioService) Risk(ctx context.Context, userID uint, days int, riskFreeRate float64) (*domain.PortfolioRisk, error) {
+	args := m.Called(ctx, userID, days, riskFreeRate)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PortfolioRisk), args.Error(1)
+}
+
+func (m *MockPortfolioService) NetWorth(ctx context.Context, userID uint) (*domain.NetWorth, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.NetWorth), args.Error(1)
+}
+
+func setupPortfolioRouter(service *MockPortfolioService) *gin.Engine {
+	handler := NewPortfolioHandler(service)
+	router := setupGin()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Next()
+	})
+	router.GET("/users/:userId/portfolio/holdings", handler.ListHoldings)
+	router.PUT("/users/:userId/portfolio/holdings/:symbol", handler.SaveHolding)
+	router.DELETE("/users/:userId/portfolio/holdings/:symbol", handler.RemoveHolding)
+	router.GET("/users/:userId/portfolio/risk", handler.GetRisk)
+	router.GET("/users/:userId/net-worth", handler.GetNetWorth)
+	return router
+}
+
+func TestPortfolioHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		mockSetup      func(*MockPortfolioService)
+		expectedStatus int
+	}{
+		{
+			name:   "list holdings",
+			method: http.MethodGet,
+			path:   "/users/1/portfolio/holdings",
+			mockSetup: func(m *MockPortfolioService) {
+				m.On("Holdings", mock.Anything, uint(1)).Return([]domain.Holding{{Symbol: "SPY", Quantity: 3}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "save a holding",
+			method: http.MethodPut,
+			path:   "/users/1/portfolio/holdings/btc",
+			body:   `{"quantity": 0.5, "asset_type": "crypto"}`,
+			mockSetup: func(m *MockPortfolioService) {
+				m.On("SaveHolding", mock.Anything, uint(1), domain.Holding{Symbol: "btc", AssetType: "crypto", Quantity: 0.5}).
+					Return(&domain.Holding{ID: 1, Symbol: "BTC", AssetType: "crypto", Quantity: 0.5}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "save without a quantity",
+			method:         http.MethodPut,
+			path:           "/users/1/portfolio/holdings/BTC",
+			body:           `{"asset_type": "crypto"}`,
+			mockSetup:      func(m *MockPortfolioService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "save a negative quantity",
+			method: http.MethodPut,
+			path:   "/users/1/portfolio/holdings/BTC",
+			body:   `{"quantity": -1}`,
+			mockSetup: func(m *MockPortfolioService) {
+				m.On("SaveHolding", mock.Anything, uint(1), mock.Anything).Return(nil, &domain.ValidationError{
+					Fields: []domain.FieldError{{Field: "quantity", Message: "must be positive"}},
+				})
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "save an unknown asset type",
+			method: http.MethodPut,
+			path:   "/users/1/portfolio/holdings/BTC",
+			body:   `{"quantity": 1, "asset_type": "bond"}`,
+			mockSetup: func(m *MockPortfolioService) {
+				m.On("SaveHolding", mock.Anything, uint(1), mock.Anything).Return(nil, application.ErrInvalidAssetType)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "remove a holding that is not held",
+			method: http.MethodDelete,
+			path:   "/users/1/portfolio/holdings/ETH",
+			mockSetup: func(m *MockPortfolioService) {
+				m.On("RemoveHolding", mock.Anything, uint(1), "ETH").Return(domain.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "risk with defaults",
+			method: http.MethodGet,
+			path:   "/users/1/portfolio/risk",
+			mockSetup: func(m *MockPortfolioService) {
+				m.On("Risk", mock.Anything, uint(1), domain.DefaultPortfolioRiskDays, domain.DefaultRiskFreeRate).
+					Return(&domain.PortfolioRisk{Volatility: 0.2, SharpeRatio: 1.1}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "risk over a custom window",
+			method: http.MethodGet,
+			path:   "/users/1/portfolio/risk?days=30&risk_free_rate=0.045",
+			mockSetup: func(m *MockPortfolioService) {
+				m.On("Risk", mock.Anything, uint(1), 30, 0.045).Return(&domain.PortfolioRisk{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "risk with invalid days",
+			method:         http.MethodGet,
+			path:           "/users/1/portfolio/risk?days=month",
+			mockSetup:      func(m *MockPortfolioService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "risk without price history",
+			method: http.MethodGet,
+			path:   "/users/1/portfolio/risk",
+			mockSetup: func(m *MockPortfolioService) {
+				m.On("Risk", mock.Anything, uint(1), mock.Anything, mock.Anything).Return(nil, domain.ErrInsufficientPriceHistory)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "risk service error",
+			method: http.MethodGet,
+			path:   "/users/1/portfolio/risk",
+			mockSetup: func(m *MockPortfolioService) {
+				m.On("Risk", mock.Anything, uint(1), mock.Anything, mock.Anything).Return(nil, errors.New("db down"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:   "net worth",
+			method: http.MethodGet,
+			path:   "/users/1/net-worth",
+			mockSetup: func(m *MockPortfolioService) {
+				m.On("NetWorth", mock.Anything, uint(1)).Return(&domain.NetWorth{Currency: "USD", Total: domain.NewMoney(1200)}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "net worth service error",
+			method: http.MethodGet,
+			path:   "/users/1/net-worth",
+			mockSetup: func(m *MockPortfolioService) {
+				m.On("NetWorth", mock.Anything, uint(1)).Return(nil, errors.New("db down"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "other users' portfolios are forbidden",
+			method:         http.MethodGet,
+			path:           "/users/2/portfolio/risk",
+			mockSetup:      func(m *MockPortfolioService) {},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(MockPortfolioService)
+			tt.mockSetup(service)
+			router := setupPortfolioRouter(service)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			service.AssertExpectations(t)
+		})
+	}
+}
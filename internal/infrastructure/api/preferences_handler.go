@@ -0,0 +1,89 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PreferencesServiceInterface defines the interface for user preference operations
+type PreferencesServiceInterface interface {
+	Get(ctx context.Context, userID uint) (domain.Preferences, error)
+	Update(ctx context.Context, userID uint, preferences domain.Preferences) (domain.Preferences, error)
+}
+
+type PreferencesHandler struct {
+	Service PreferencesServiceInterface
+}
+
+func NewPreferencesHandler(service PreferencesServiceInterface) *PreferencesHandler {
+	return &PreferencesHandler{Service: service}
+}
+
+// PreferencesRequest is the body of preference updates. Settings left out
+// go back to their defaults.
+type PreferencesRequest struct {
+	Currency        string `json:"currency"`
+	Locale          string `json:"locale"`
+	WeekStart       string `json:"week_start"`
+	DateFormat      string `json:"date_format"`
+	NumberFormat    string `json:"number_format"`
+	DashboardPeriod string `json:"dashboard_period"`
+}
+
+// Get returns the user's display currency, locale, first day of the week,
+// date and number formats and default dashboard period
+func (h *PreferencesHandler) Get(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	preferences, err := h.Service.Get(c.Request.Context(), userID)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "User not found")
+	case err != nil:
+		respondInternalError(c, "Failed to retrieve preferences", err)
+	default:
+		c.JSON(http.StatusOK, preferences)
+	}
+}
+
+// Update replaces the user's preferences
+func (h *PreferencesHandler) Update(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req PreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	preferences, err := h.Service.Update(c.Request.Context(), userID, domain.Preferences{
+		Currency:        req.Currency,
+		Locale:          req.Locale,
+		WeekStart:       req.WeekStart,
+		DateFormat:      req.DateFormat,
+		NumberFormat:    req.NumberFormat,
+		DashboardPeriod: req.DashboardPeriod,
+	})
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "User not found")
+	case err != nil:
+		if !respondValidationError(c, err) {
+			respondInternalError(c, "Failed to update preferences", err)
+		}
+	default:
+		c.JSON(http.StatusOK, preferences)
+	}
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockPreferencesService is a mock implementation of PreferencesServiceInterface
+type MockPreferencesService struct {
+	mock.Mock
+}
+
+func (m *MockPreferencesService) Get(ctx context.Context, userID uint) (domain.Preferences, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(domain.Preferences), args.Error(1)
+}
+
+func (m *MockPreferencesService) Update(
+	ctx context.Context, userID uint, preferences domain.Preferences,
+) (domain.Preferences, error) {
+	args := m.Called(ctx, userID, preferences)
+	return args.Get(0).(domain.Preferences), args.Error(1)
+}
+
+func setupPreferencesRouter(service *MockPreferencesService) *gin.Engine {
+	handler := NewPreferencesHandler(service)
+	router := setupGin()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Next()
+	})
+	router.GET("/users/:userId/preferences", handler.Get)
+	router.PUT("/users/:userId/preferences", handler.Update)
+	return router
+}
+
+func TestPreferencesHandler_Get(t *testing.T) {
+	service := new(MockPreferencesService)
+	service.On("Get", mock.Anything, uint(1)).Return(domain.DefaultPreferences(1), nil)
+	router := setupPreferencesRouter(service)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1/preferences", http.NoBody))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var preferences domain.Preferences
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &preferences))
+	assert.Equal(t, domain.BaseCurrency, preferences.Currency)
+	assert.Equal(t, domain.WeekStartMonday, preferences.WeekStart)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/2/preferences", http.NoBody))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	service.AssertExpectations(t)
+}
+
+func TestPreferencesHandler_Update(t *testing.T) {
+	service := new(MockPreferencesService)
+	wanted := domain.Preferences{Currency: "EUR", Locale: "de", WeekStart: "sunday", DashboardPeriod: "year"}
+	service.On("Update", mock.Anything, uint(1), wanted).Return(wanted, nil)
+	service.On("Update", mock.Anything, uint(1), domain.Preferences{WeekStart: "friday"}).
+		Return(domain.Preferences{}, &domain.ValidationError{Fields: []domain.FieldError{
+			{Field: "week_start", Message: "must be monday, sunday or saturday"},
+		}})
+	router := setupPreferencesRouter(service)
+
+	put := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPut, "/users/1/preferences", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := put(`{"currency": "EUR", "locale": "de", "week_start": "sunday", "dashboard_period": "year"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"dashboard_period":"year"`)
+
+	w = put(`{"week_start": "friday"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "week_start")
+
+	w = put(`{"week_start": `)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	service.AssertExpectations(t)
+}
@@ -0,0 +1,208 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PriceAlertServiceInterface defines the interface for price alert operations
+type PriceAlertServiceInterface interface {
+	Create(ctx context.Context, userID uint, alert *domain.PriceAlert) error
+	List(ctx context.Context, userID uint) ([]domain.PriceAlert, error)
+	Get(ctx context.Context, userID, id uint) (*domain.PriceAlert, error)
+	Update(ctx context.Context, userID uint, alert *domain.PriceAlert) error
+	Delete(ctx context.Context, userID, id uint) error
+	History(ctx context.Context, userID, alertID uint, limit int) ([]domain.PriceAlertTrigger, error)
+}
+
+type PriceAlertHandler struct {
+	Service PriceAlertServiceInterface
+}
+
+func NewPriceAlertHandler(service PriceAlertServiceInterface) *PriceAlertHandler {
+	return &PriceAlertHandler{Service: service}
+}
+
+type CreatePriceAlertRequest struct {
+	Symbol     string  `json:"symbol" binding:"required"`
+	Comparator string  `json:"comparator" binding:"required"`
+	Threshold  float64 `json:"threshold" binding:"required"`
+	Note       string  `json:"note"`
+	Repeat     bool    `json:"repeat"`
+}
+
+type UpdatePriceAlertRequest struct {
+	Comparator *string  `json:"comparator,omitempty"`
+	Threshold  *float64 `json:"threshold,omitempty"`
+	Note       *string  `json:"note,omitempty"`
+	Repeat     *bool    `json:"repeat,omitempty"`
+	Active     *bool    `json:"active,omitempty"`
+}
+
+// parsePriceAlertIDs reads the userId and alertId parameters. Users can only
+// manage their own alerts.
+func parsePriceAlertIDs(c *gin.Context) (userID, alertID uint, ok bool) {
+	userID, ok = authorizedUserID(c)
+	if !ok {
+		return 0, 0, false
+	}
+	id, err := strconv.ParseUint(c.Param("alertId"), 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid alert ID")
+		return 0, 0, false
+	}
+	return userID, uint(id), true
+}
+
+func respondPriceAlertError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "Price alert not found")
+	case errors.Is(err, domain.ErrInvalidSymbol), errors.Is(err, domain.ErrInvalidComparator),
+		errors.Is(err, domain.ErrInvalidThreshold):
+		respondError(c, middleware.CodeBadRequest, err.Error())
+	default:
+		respondInternalError(c, message, err)
+	}
+}
+
+// Create adds a price alert for the user
+func (h *PriceAlertHandler) Create(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req CreatePriceAlertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	alert := &domain.PriceAlert{
+		Symbol:     req.Symbol,
+		Comparator: req.Comparator,
+		Threshold:  req.Threshold,
+		Note:       req.Note,
+		Repeat:     req.Repeat,
+	}
+	if err := h.Service.Create(c.Request.Context(), userID, alert); err != nil {
+		respondPriceAlertError(c, err, "Failed to create price alert")
+		return
+	}
+	c.JSON(http.StatusCreated, alert)
+}
+
+// List returns the user's price alerts
+func (h *PriceAlertHandler) List(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	alerts, err := h.Service.List(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve price alerts", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"alerts": alerts, "count": len(alerts)})
+}
+
+// Get returns one price alert
+func (h *PriceAlertHandler) Get(c *gin.Context) {
+	userID, alertID, ok := parsePriceAlertIDs(c)
+	if !ok {
+		return
+	}
+
+	alert, err := h.Service.Get(c.Request.Context(), userID, alertID)
+	if err != nil {
+		respondPriceAlertError(c, err, "Failed to retrieve price alert")
+		return
+	}
+	c.JSON(http.StatusOK, alert)
+}
+
+// Update changes the condition, note or flags of a price alert
+func (h *PriceAlertHandler) Update(c *gin.Context) {
+	userID, alertID, ok := parsePriceAlertIDs(c)
+	if !ok {
+		return
+	}
+
+	var req UpdatePriceAlertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	alert, err := h.Service.Get(c.Request.Context(), userID, alertID)
+	if err != nil {
+		respondPriceAlertError(c, err, "Failed to retrieve price alert")
+		return
+	}
+	if req.Comparator != nil {
+		alert.Comparator = *req.Comparator
+	}
+	if req.Threshold != nil {
+		alert.Threshold = *req.Threshold
+	}
+	if req.Note != nil {
+		alert.Note = *req.Note
+	}
+	if req.Repeat != nil {
+		alert.Repeat = *req.Repeat
+	}
+	if req.Active != nil {
+		alert.Active = *req.Active
+	}
+
+	if err := h.Service.Update(c.Request.Context(), userID, alert); err != nil {
+		respondPriceAlertError(c, err, "Failed to update price alert")
+		return
+	}
+	c.JSON(http.StatusOK, alert)
+}
+
+// Delete removes a price alert and its history
+func (h *PriceAlertHandler) Delete(c *gin.Context) {
+	userID, alertID, ok := parsePriceAlertIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Service.Delete(c.Request.Context(), userID, alertID); err != nil {
+		respondPriceAlertError(c, err, "Failed to delete price alert")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Price alert deleted successfully"})
+}
+
+// History returns when the user's alerts fired, for every alert or, with an
+// alertId, for one of them
+func (h *PriceAlertHandler) History(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+	alertID, err := parseOptionalID(c.Param("alertId"))
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid alert ID")
+		return
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	triggers, err := h.Service.History(c.Request.Context(), userID, alertID, limit)
+	if err != nil {
+		respondPriceAlertError(c, err, "Failed to retrieve price alert history")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"history": triggers, "count": len(triggers)})
+}
@@ -0,0 +1,158 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockPriceAlertService is a mock implementation of PriceAlertServiceInterface
+type MockPriceAlertService struct {
+	mock.Mock
+}
+
+func (m *MockPriceAlertService) Create(ctx context.Context, userID uint, alert *domain.PriceAlert) error {
+	args := m.Called(ctx, userID, alert)
+	return args.Error(0)
+}
+
+func (m *MockPriceAlertService) List(ctx context.Context, userID uint) ([]domain.PriceAlert, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.PriceAlert), args.Error(1)
+}
+
+func (m *MockPriceAlertService) Get(ctx context.Context, userID, id uint) (*domain.PriceAlert, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PriceAlert), args.Error(1)
+}
+
+func (m *MockPriceAlertService) Update(ctx context.Context, userID uint, alert *domain.PriceAlert) error {
+	args := m.Called(ctx, userID, alert)
+	return args.Error(0)
+}
+
+func (m *MockPriceAlertService) Delete(ctx context.Context, userID, id uint) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func (m *MockPriceAlertService) History(ctx context.Context, userID, alertID uint, limit int) ([]domain.PriceAlertTrigger, error) {
+	args := m.Called(ctx, userID, alertID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.PriceAlertTrigger), args.Error(1)
+}
+
+func setupPriceAlertRouter(service *MockPriceAlertService) *gin.Engine {
+	handler := NewPriceAlertHandler(service)
+	router := setupGin()
+	router.GET("/users/:userId/price-alerts", handler.List)
+	router.POST("/users/:userId/price-alerts", handler.Create)
+	router.GET("/users/:userId/price-alerts/history", handler.History)
+	router.GET("/users/:userId/price-alerts/:alertId", handler.Get)
+	router.PUT("/users/:userId/price-alerts/:alertId", handler.Update)
+	router.DELETE("/users/:userId/price-alerts/:alertId", handler.Delete)
+	router.GET("/users/:userId/price-alerts/:alertId/history", handler.History)
+	return router
+}
+
+func TestPriceAlertHandler_Create(t *testing.T) {
+	t.Run("should create the alert", func(t *testing.T) {
+		mockService := new(MockPriceAlertService)
+		router := setupPriceAlertRouter(mockService)
+		mockService.On("Create", mock.Anything, uint(1), mock.MatchedBy(func(alert *domain.PriceAlert) bool {
+			return alert.Symbol == "BTC" && alert.Comparator == domain.PriceAlertAbove && alert.Threshold == 50000 && alert.Repeat
+		})).Return(nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/1/price-alerts",
+			strings.NewReader(`{"symbol": "BTC", "comparator": "above", "threshold": 50000, "repeat": true}`)))
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should reject invalid alerts", func(t *testing.T) {
+		mockService := new(MockPriceAlertService)
+		router := setupPriceAlertRouter(mockService)
+		mockService.On("Create", mock.Anything, uint(1), mock.Anything).Return(domain.ErrInvalidComparator)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/1/price-alerts",
+			strings.NewReader(`{"symbol": "BTC", "comparator": "equals", "threshold": 50000}`)))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Comparator must be above or below")
+	})
+}
+
+func TestPriceAlertHandler_Update(t *testing.T) {
+	mockService := new(MockPriceAlertService)
+	router := setupPriceAlertRouter(mockService)
+	existing := &domain.PriceAlert{ID: 3, UserID: 1, Symbol: "AAPL", Comparator: domain.PriceAlertAbove, Threshold: 200, Active: true}
+	mockService.On("Get", mock.Anything, uint(1), uint(3)).Return(existing, nil)
+	mockService.On("Update", mock.Anything, uint(1), mock.MatchedBy(func(alert *domain.PriceAlert) bool {
+		return alert.Threshold == 210 && !alert.Active && alert.Comparator == domain.PriceAlertAbove
+	})).Return(nil)
+	mockService.On("Get", mock.Anything, uint(1), uint(4)).Return(nil, domain.ErrNotFound)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/users/1/price-alerts/3",
+		strings.NewReader(`{"threshold": 210, "active": false}`)))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/users/1/price-alerts/4", strings.NewReader(`{}`)))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPriceAlertHandler_History(t *testing.T) {
+	mockService := new(MockPriceAlertService)
+	router := setupPriceAlertRouter(mockService)
+	mockService.On("History", mock.Anything, uint(1), uint(0), 10).
+		Return([]domain.PriceAlertTrigger{{ID: 1, AlertID: 3, Price: 185}, {ID: 2, AlertID: 5, Price: 51000}}, nil)
+	mockService.On("History", mock.Anything, uint(1), uint(3), 0).
+		Return([]domain.PriceAlertTrigger{{ID: 1, AlertID: 3, Price: 185}}, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1/price-alerts/history?limit=10", http.NoBody))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		History []domain.PriceAlertTrigger `json:"history"`
+		Count   int                        `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 2, response.Count)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1/price-alerts/3/history", http.NoBody))
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPriceAlertHandler_Delete(t *testing.T) {
+	mockService := new(MockPriceAlertService)
+	router := setupPriceAlertRouter(mockService)
+	mockService.On("Delete", mock.Anything, uint(1), uint(3)).Return(nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/users/1/price-alerts/3", http.NoBody))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"go-finance-advisor/internal/pkg"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PublicMarketServiceInterface is the market data the public endpoints serve
+type PublicMarketServiceInterface interface {
+	GetCryptoPrices(ctx context.Context) ([]pkg.CryptoPrice, error)
+	GetMarketSummary(ctx context.Context) (map[string]interface{}, error)
+}
+
+// publicSummaryFields are the parts of the market summary shown without
+// login; the recommendation and the risk and return estimates stay behind it
+var publicSummaryFields = []string{
+	"market_trend", "volatility", "sentiment_score", "top_cryptos", "top_stocks", "stale", "last_updated",
+}
+
+// publicResponse is a response body shared by every visitor until it expires
+type publicResponse struct {
+	mu        sync.Mutex
+	body      []byte
+	expiresAt time.Time
+}
+
+// PublicMarketHandler serves the unauthenticated market endpoints the landing
+// page shows live data from. Every visitor gets the same response for
+// CacheTTL, so landing page traffic hardly reaches the market providers;
+// RateLimitMiddleware is expected in front of it.
+type PublicMarketHandler struct {
+	Market   PublicMarketServiceInterface
+	CacheTTL time.Duration
+
+	summary publicResponse
+	cryptos publicResponse
+}
+
+func NewPublicMarketHandler(market PublicMarketServiceInterface, cacheTTL time.Duration) *PublicMarketHandler {
+	return &PublicMarketHandler{Market: market, CacheTTL: cacheTTL}
+}
+
+// GetMarketSummary provides the market overview without any advice
+func (h *PublicMarketHandler) GetMarketSummary(c *gin.Context) {
+	h.serve(c, &h.summary, func(ctx context.Context) (interface{}, error) {
+		summary, err := h.Market.GetMarketSummary(ctx)
+		if err != nil {
+			return nil, err
+		}
+		public := make(map[string]interface{}, len(publicSummaryFields))
+		for _, field := range publicSummaryFields {
+			if value, ok := summary[field]; ok {
+				public[field] = value
+			}
+		}
+		return public, nil
+	})
+}
+
+// GetCryptoPrices provides the prices of the largest cryptocurrencies
+func (h *PublicMarketHandler) GetCryptoPrices(c *gin.Context) {
+	h.serve(c, &h.cryptos, func(ctx context.Context) (interface{}, error) {
+		cryptos, err := h.Market.GetCryptoPrices(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return gin.H{
+			"cryptos": cryptos,
+			"count":   len(cryptos),
+			"updated": freshness(slices.ContainsFunc(cryptos, func(crypto pkg.CryptoPrice) bool { return crypto.Stale })),
+		}, nil
+	})
+}
+
+// serve answers with the cached response, fetching a new one once it has
+// expired. Visitors arriving while it is fetched wait for it instead of
+// fetching it again. Errors are not cached.
+func (h *PublicMarketHandler) serve(c *gin.Context, cached *publicResponse, fetch func(ctx context.Context) (interface{}, error)) {
+	cached.mu.Lock()
+	defer cached.mu.Unlock()
+
+	if time.Now().After(cached.expiresAt) {
+		response, err := fetch(c.Request.Context())
+		if err != nil {
+			marketError(c, err)
+			return
+		}
+		body, err := json.Marshal(response)
+		if err != nil {
+			respondInternalError(c, "Failed to encode market data", err)
+			return
+		}
+		cached.body = body
+		cached.expiresAt = time.Now().Add(h.CacheTTL)
+	}
+
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(time.Until(cached.expiresAt).Seconds())))
+	c.Data(http.StatusOK, "application/json; charset=utf-8", cached.body)
+}
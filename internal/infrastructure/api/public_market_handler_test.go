@@ -0,0 +1,68 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/pkg"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupPublicMarketRouter(handler *PublicMarketHandler) *gin.Engine {
+	router := setupGin()
+	router.GET("/public/market/summary", handler.GetMarketSummary)
+	router.GET("/public/market/crypto", handler.GetCryptoPrices)
+	return router
+}
+
+func TestPublicMarketHandler_GetMarketSummary(t *testing.T) {
+	market := new(MockRealTimeMarketService)
+	market.On("GetMarketSummary", mock.Anything).Return(map[string]interface{}{
+		"market_trend":     "bullish",
+		"recommendation":   "Buy the dip",
+		"predicted_return": 0.12,
+		"stale":            false,
+	}, nil).Once()
+	router := setupPublicMarketRouter(NewPublicMarketHandler(market, time.Minute))
+
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/public/market/summary", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Cache-Control"), "public, max-age=")
+		var summary map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &summary))
+		assert.Equal(t, map[string]interface{}{"market_trend": "bullish", "stale": false}, summary, "advice stays behind login")
+	}
+	market.AssertExpectations(t)
+}
+
+func TestPublicMarketHandler_GetCryptoPrices(t *testing.T) {
+	market := new(MockRealTimeMarketService)
+	market.On("GetCryptoPrices", mock.Anything).
+		Return([]pkg.CryptoPrice{}, fmt.Errorf("failed to fetch crypto data: %w", pkg.ErrMarketUnavailable)).Once()
+	market.On("GetCryptoPrices", mock.Anything).
+		Return([]pkg.CryptoPrice{{Symbol: "btc", Price: 50000}}, nil).Once()
+	router := setupPublicMarketRouter(NewPublicMarketHandler(market, time.Minute))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/public/market/crypto", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	for i := 0; i < 2; i++ {
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/public/market/crypto", nil))
+		require.Equal(t, http.StatusOK, w.Code, "errors are not cached")
+		assert.Contains(t, w.Body.String(), `"count":1`)
+	}
+	market.AssertExpectations(t)
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"go-finance-advisor/internal/application"
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxReceiptSize limits receipt uploads to 10 MB
+const maxReceiptSize = 10 << 20
+
+// ReceiptServiceInterface defines the interface for receipt scanning
+type ReceiptServiceInterface interface {
+	ScanReceipt(
+		ctx context.Context, userID uint, fileName, contentType string, image []byte,
+	) (*application.ReceiptScanResult, error)
+	AttachmentLink(ctx context.Context, userID, attachmentID uint) (*domain.FileLink, error)
+}
+
+type ReceiptHandler struct {
+	Service ReceiptServiceInterface
+}
+
+func NewReceiptHandler(service ReceiptServiceInterface) *ReceiptHandler {
+	return &ReceiptHandler{Service: service}
+}
+
+// ScanReceipt accepts a receipt image as multipart field "receipt" and returns
+// a prefilled transaction request for the user to confirm
+func (h *ReceiptHandler) ScanReceipt(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid user ID")
+		return
+	}
+
+	fileHeader, err := c.FormFile("receipt")
+	if err != nil {
+		respondError(c, middleware.CodeBadRequest, "Receipt file is required")
+		return
+	}
+	if fileHeader.Size > maxReceiptSize {
+		respondError(c, middleware.CodePayloadTooLarge, "Receipt file exceeds 10MB limit")
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		respondError(c, middleware.CodeBadRequest, "Failed to read receipt file")
+		return
+	}
+	defer file.Close()
+
+	image, err := io.ReadAll(io.LimitReader(file, maxReceiptSize))
+	if err != nil {
+		respondError(c, middleware.CodeBadRequest, "Failed to read receipt file")
+		return
+	}
+
+	result, err := h.Service.ScanReceipt(
+		c.Request.Context(), uint(userID), fileHeader.Filename, fileHeader.Header.Get("Content-Type"), image,
+	)
+	if err != nil {
+		respondError(c, middleware.CodeUnprocessable, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"attachment_id": result.Attachment.ID,
+		"receipt":       result.Receipt,
+		"complete":      result.Receipt.IsComplete(),
+		"transaction":   prefillTransactionRequest(result),
+	})
+}
+
+// AttachmentLink returns a signed link the attachment can be downloaded
+// from directly for the next few minutes
+func (h *ReceiptHandler) AttachmentLink(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+	attachmentID, err := strconv.ParseUint(c.Param("attachmentId"), 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid attachment ID")
+		return
+	}
+
+	link, err := h.Service.AttachmentLink(c.Request.Context(), userID, uint(attachmentID))
+	if errors.Is(err, domain.ErrNotFound) {
+		respondError(c, middleware.CodeNotFound, "Attachment not found")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to create the download link", err)
+		return
+	}
+	c.JSON(http.StatusOK, link)
+}
+
+// prefillTransactionRequest maps the extracted receipt fields onto a
+// CreateTransactionRequest; receipts are always treated as expenses
+func prefillTransactionRequest(result *application.ReceiptScanResult) CreateTransactionRequest {
+	req := CreateTransactionRequest{
+		Amount:      result.Receipt.Amount,
+		Type:        domain.TransactionTypeExpense,
+		Description: result.Receipt.Merchant,
+	}
+	if result.Receipt.Date != nil {
+		req.Date = result.Receipt.Date.Format("2006-01-02")
+	}
+	if result.SuggestedCategoryID != nil {
+		req.CategoryID = *result.SuggestedCategoryID
+	}
+	return req
+}
@@ -0,0 +1,175 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/application"
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockReceiptService is a mock implementation of ReceiptServiceInterface
+type MockReceiptService struct {
+	mock.Mock
+}
+
+func (m *MockReceiptService) ScanReceipt(
+	ctx context.Context, userID uint, fileName, contentType string, image []byte,
+) (*application.ReceiptScanResult, error) {
+	args := m.Called(ctx, userID, fileName, contentType, image)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*application.ReceiptScanResult), args.Error(1)
+}
+
+func (m *MockReceiptService) AttachmentLink(ctx context.Context, userID, attachmentID uint) (*domain.FileLink, error) {
+	args := m.Called(ctx, userID, attachmentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FileLink), args.Error(1)
+}
+
+func newReceiptUploadRequest(t *testing.T, url string, content []byte) *http.Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("receipt", "receipt.png")
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, url, body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestReceiptHandler_ScanReceipt(t *testing.T) {
+	t.Run("should return prefilled transaction request", func(t *testing.T) {
+		mockService := new(MockReceiptService)
+		handler := NewReceiptHandler(mockService)
+		router := setupGin()
+		router.POST("/users/:userId/transactions/receipt", handler.ScanReceipt)
+
+		date := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+		categoryID := uint(4)
+		mockService.On("ScanReceipt", mock.Anything, uint(1), "receipt.png", mock.Anything, []byte("image")).
+			Return(&application.ReceiptScanResult{
+				Attachment:          &domain.Attachment{ID: 9, UserID: 1, FileName: "receipt.png"},
+				Receipt:             &domain.ReceiptData{Merchant: "Corner Grocery", Date: &date, Amount: domain.NewMoney(7.5)},
+				SuggestedCategoryID: &categoryID,
+			}, nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newReceiptUploadRequest(t, "/users/1/transactions/receipt", []byte("image")))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			AttachmentID uint                     `json:"attachment_id"`
+			Complete     bool                     `json:"complete"`
+			Transaction  CreateTransactionRequest `json:"transaction"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, uint(9), response.AttachmentID)
+		assert.True(t, response.Complete)
+		assert.Equal(t, CreateTransactionRequest{
+			Amount:      domain.NewMoney(7.5),
+			Type:        "expense",
+			Description: "Corner Grocery",
+			CategoryID:  4,
+			Date:        "2024-03-15",
+		}, response.Transaction)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return 400 when file is missing", func(t *testing.T) {
+		mockService := new(MockReceiptService)
+		handler := NewReceiptHandler(mockService)
+		router := setupGin()
+		router.POST("/users/:userId/transactions/receipt", handler.ScanReceipt)
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/users/1/transactions/receipt", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "ScanReceipt")
+	})
+
+	t.Run("should return 400 for invalid user ID", func(t *testing.T) {
+		mockService := new(MockReceiptService)
+		handler := NewReceiptHandler(mockService)
+		router := setupGin()
+		router.POST("/users/:userId/transactions/receipt", handler.ScanReceipt)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newReceiptUploadRequest(t, "/users/abc/transactions/receipt", []byte("image")))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should return 422 when OCR fails", func(t *testing.T) {
+		mockService := new(MockReceiptService)
+		handler := NewReceiptHandler(mockService)
+		router := setupGin()
+		router.POST("/users/:userId/transactions/receipt", handler.ScanReceipt)
+
+		mockService.On("ScanReceipt", mock.Anything, uint(1), "receipt.png", mock.Anything, []byte("image")).
+			Return(nil, errors.New("failed to read receipt"))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newReceiptUploadRequest(t, "/users/1/transactions/receipt", []byte("image")))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestReceiptHandler_AttachmentLink(t *testing.T) {
+	mockService := new(MockReceiptService)
+	mockService.On("AttachmentLink", mock.Anything, uint(1), uint(9)).
+		Return(&domain.FileLink{URL: "/api/v1/files/attachments/1/receipt.png?expires=1&signature=abc", FileName: "receipt.png"}, nil)
+	mockService.On("AttachmentLink", mock.Anything, uint(1), uint(10)).Return(nil, domain.ErrNotFound)
+	handler := NewReceiptHandler(mockService)
+	router := setupGin()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Next()
+	})
+	router.GET("/users/:userId/attachments/:attachmentId/link", handler.AttachmentLink)
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+	}{
+		{"returns a signed link", "/users/1/attachments/9/link", http.StatusOK},
+		{"hides attachments of others", "/users/1/attachments/10/link", http.StatusNotFound},
+		{"rejects invalid IDs", "/users/1/attachments/x/link", http.StatusBadRequest},
+		{"refuses other users", "/users/2/attachments/9/link", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusOK {
+				assert.Contains(t, w.Body.String(), "signature=abc")
+			}
+		})
+	}
+}
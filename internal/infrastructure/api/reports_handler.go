@@ -1,23 +1,32 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
 
 	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
 
 	"github.com/gin-gonic/gin"
 )
 
 // ReportsServiceInterface defines the contract for reports service operations
 type ReportsServiceInterface interface {
-	GenerateMonthlyReport(userID uint, year, month int) (*domain.FinancialReport, error)
-	GenerateQuarterlyReport(userID uint, year, quarter int) (*domain.FinancialReport, error)
-	GenerateYearlyReport(userID uint, year int) (*domain.FinancialReport, error)
-	GenerateCustomReport(userID uint, startDate, endDate time.Time) (*domain.FinancialReport, error)
+	GenerateMonthlyReport(ctx context.Context, userID uint, year, month int) (*domain.FinancialReport, error)
+	GenerateQuarterlyReport(ctx context.Context, userID uint, year, quarter int) (*domain.FinancialReport, error)
+	GenerateYearlyReport(ctx context.Context, userID uint, year int) (*domain.FinancialReport, error)
+	GenerateCustomReport(ctx context.Context, userID uint, startDate, endDate time.Time) (*domain.FinancialReport, error)
+	ListReports(ctx context.Context, userID uint, reportType string, limit int) ([]domain.ReportSummary, error)
+	GetReport(ctx context.Context, userID, id uint) (*domain.FinancialReport, error)
+	DeleteReport(ctx context.Context, userID, id uint) error
+	CompareReports(ctx context.Context, userID uint, basePeriod, targetPeriod string) (*domain.ReportComparison, error)
 }
 
+const maxReportListLimit = 200
+
 type ReportsHandler struct {
 	Service ReportsServiceInterface
 }
@@ -31,7 +40,7 @@ func (h *ReportsHandler) validateUserID(c *gin.Context) (uint, bool) {
 	userIDStr := c.Param("userId")
 	userID, err := strconv.ParseUint(userIDStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		respondError(c, middleware.CodeInvalidID, "Invalid user ID")
 		return 0, false
 	}
 	return uint(userID), true
@@ -47,7 +56,7 @@ func (h *ReportsHandler) validateUserIDAndYear(c *gin.Context) (userID uint, yea
 	yearStr := c.Param("year")
 	year, err := strconv.Atoi(yearStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid year"})
+		respondError(c, middleware.CodeBadRequest, "Invalid year")
 		return 0, 0, false
 	}
 
@@ -61,7 +70,7 @@ func (h *ReportsHandler) validatePeriodParam(
 	paramStr := c.Param(paramName)
 	value, err := strconv.Atoi(paramStr)
 	if err != nil || value < minVal || value > maxVal {
-		c.JSON(http.StatusBadRequest, gin.H{"error": errorMsg})
+		respondError(c, middleware.CodeBadRequest, errorMsg)
 		return 0, false
 	}
 	return value, true
@@ -79,9 +88,9 @@ func (h *ReportsHandler) GenerateMonthlyReport(c *gin.Context) {
 		return
 	}
 
-	report, err := h.Service.GenerateMonthlyReport(userID, year, month)
+	report, err := h.Service.GenerateMonthlyReport(c.Request.Context(), userID, year, month)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate monthly report"})
+		respondInternalError(c, "Failed to generate monthly report", err)
 		return
 	}
 
@@ -100,9 +109,9 @@ func (h *ReportsHandler) GenerateQuarterlyReport(c *gin.Context) {
 		return
 	}
 
-	report, err := h.Service.GenerateQuarterlyReport(userID, year, quarter)
+	report, err := h.Service.GenerateQuarterlyReport(c.Request.Context(), userID, year, quarter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate quarterly report"})
+		respondInternalError(c, "Failed to generate quarterly report", err)
 		return
 	}
 
@@ -116,18 +125,19 @@ func (h *ReportsHandler) GenerateYearlyReport(c *gin.Context) {
 		return
 	}
 
-	report, err := h.Service.GenerateYearlyReport(userID, year)
+	report, err := h.Service.GenerateYearlyReport(c.Request.Context(), userID, year)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate yearly report"})
+		respondInternalError(c, "Failed to generate yearly report", err)
 		return
 	}
 
 	c.JSON(http.StatusOK, report)
 }
 
-// GenerateCustomReport generates a custom date range financial report
+// GenerateCustomReport generates a financial report from start_date to
+// end_date, both days included
 func (h *ReportsHandler) GenerateCustomReport(c *gin.Context) {
-	userID, valid := h.validateUserID(c)
+	userID, valid := authorizedUserID(c)
 	if !valid {
 		return
 	}
@@ -136,49 +146,132 @@ func (h *ReportsHandler) GenerateCustomReport(c *gin.Context) {
 	endDateStr := c.Query("end_date")
 
 	if startDateStr == "" || endDateStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "start_date and end_date are required"})
+		respondError(c, middleware.CodeInvalidDate, "Start date and end date are required")
 		return
 	}
 
 	startDate, err := time.Parse("2006-01-02", startDateStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start_date format. Use YYYY-MM-DD"})
+		respondError(c, middleware.CodeInvalidDate, "Invalid start_date format. Use YYYY-MM-DD")
 		return
 	}
 
 	endDate, err := time.Parse("2006-01-02", endDateStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end_date format. Use YYYY-MM-DD"})
+		respondError(c, middleware.CodeInvalidDate, "Invalid end_date format. Use YYYY-MM-DD")
 		return
 	}
 
 	if startDate.After(endDate) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "start_date must be before end_date"})
+		respondError(c, middleware.CodeInvalidDate, "Start date must be before end date")
 		return
 	}
+	endDate = endDate.AddDate(0, 0, 1).Add(-time.Second)
 
-	report, err := h.Service.GenerateCustomReport(userID, startDate, endDate)
+	report, err := h.Service.GenerateCustomReport(c.Request.Context(), userID, startDate, endDate)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate custom report"})
+		respondInternalError(c, "Failed to generate custom report", err)
 		return
 	}
 
 	c.JSON(http.StatusOK, report)
 }
 
-// GetReportsList returns a list of generated reports for a user
+// GetReportsList returns summaries of the reports generated for a user,
+// newest first; ?type= narrows them down to one report type
 func (h *ReportsHandler) GetReportsList(c *gin.Context) {
-	_, valid := h.validateUserID(c)
-	if !valid {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	limit = min(limit, maxReportListLimit)
+
+	reports, err := h.Service.ListReports(c.Request.Context(), userID, c.Query("type"), limit)
+	if errors.Is(err, domain.ErrInvalidReportType) {
+		respondError(c, middleware.CodeBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve reports", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"reports": reports, "count": len(reports)})
+}
+
+// GetReport returns a stored report in full
+func (h *ReportsHandler) GetReport(c *gin.Context) {
+	userID, id, ok := h.reportParams(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.Service.GetReport(c.Request.Context(), userID, id)
+	if errors.Is(err, domain.ErrNotFound) {
+		respondError(c, middleware.CodeNotFound, "Report not found")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve report", err)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// DeleteReport removes a stored report
+func (h *ReportsHandler) DeleteReport(c *gin.Context) {
+	userID, id, ok := h.reportParams(c)
+	if !ok {
 		return
 	}
 
-	// For now, return an empty list since we're not storing reports in DB
-	// In a real implementation, you would query the database for saved reports
-	reports := []interface{}{}
+	err := h.Service.DeleteReport(c.Request.Context(), userID, id)
+	if errors.Is(err, domain.ErrNotFound) {
+		respondError(c, middleware.CodeNotFound, "Report not found")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to delete report", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Report deleted successfully"})
+}
 
-	c.JSON(http.StatusOK, gin.H{
-		"reports": reports,
-		"message": "Reports are generated on-demand. Use the generate endpoints to create new reports.",
-	})
+// CompareReports compares income, expenses, categories and savings rate
+// between the base and target periods, e.g. ?base=2024-01&target=2024-02
+func (h *ReportsHandler) CompareReports(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+	basePeriod, targetPeriod := c.Query("base"), c.Query("target")
+	if basePeriod == "" || targetPeriod == "" {
+		respondError(c, middleware.CodeBadRequest, "Base and target periods are required")
+		return
+	}
+
+	comparison, err := h.Service.CompareReports(c.Request.Context(), userID, basePeriod, targetPeriod)
+	if errors.Is(err, domain.ErrInvalidReportPeriod) {
+		respondError(c, middleware.CodeBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to compare reports", err)
+		return
+	}
+	c.JSON(http.StatusOK, comparison)
+}
+
+// reportParams reads the user and report IDs of a stored report request
+func (h *ReportsHandler) reportParams(c *gin.Context) (userID, reportID uint, ok bool) {
+	userID, ok = authorizedUserID(c)
+	if !ok {
+		return 0, 0, false
+	}
+	id, err := strconv.ParseUint(c.Param("reportId"), 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid report ID")
+		return 0, 0, false
+	}
+	return userID, uint(id), true
 }
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -20,26 +21,50 @@ type MockReportsService struct {
 	mock.Mock
 }
 
-func (m *MockReportsService) GenerateMonthlyReport(userID uint, year, month int) (*domain.FinancialReport, error) {
-	args := m.Called(userID, year, month)
+func (m *MockReportsService) GenerateMonthlyReport(ctx context.Context, userID uint, year, month int) (*domain.FinancialReport, error) {
+	args := m.Called(ctx, userID, year, month)
 	return args.Get(0).(*domain.FinancialReport), args.Error(1)
 }
 
-func (m *MockReportsService) GenerateQuarterlyReport(userID uint, year, quarter int) (*domain.FinancialReport, error) {
-	args := m.Called(userID, year, quarter)
+func (m *MockReportsService) GenerateQuarterlyReport(ctx context.Context, userID uint, year, quarter int) (*domain.FinancialReport, error) {
+	args := m.Called(ctx, userID, year, quarter)
 	return args.Get(0).(*domain.FinancialReport), args.Error(1)
 }
 
-func (m *MockReportsService) GenerateYearlyReport(userID uint, year int) (*domain.FinancialReport, error) {
-	args := m.Called(userID, year)
+func (m *MockReportsService) GenerateYearlyReport(ctx context.Context, userID uint, year int) (*domain.FinancialReport, error) {
+	args := m.Called(ctx, userID, year)
 	return args.Get(0).(*domain.FinancialReport), args.Error(1)
 }
 
-func (m *MockReportsService) GenerateCustomReport(userID uint, startDate, endDate time.Time) (*domain.FinancialReport, error) {
-	args := m.Called(userID, startDate, endDate)
+func (m *MockReportsService) GenerateCustomReport(ctx context.Context, userID uint, startDate, endDate time.Time) (*domain.FinancialReport, error) {
+	args := m.Called(ctx, userID, startDate, endDate)
 	return args.Get(0).(*domain.FinancialReport), args.Error(1)
 }
 
+func (m *MockReportsService) ListReports(ctx context.Context, userID uint, reportType string, limit int) ([]domain.ReportSummary, error) {
+	args := m.Called(ctx, userID, reportType, limit)
+	return args.Get(0).([]domain.ReportSummary), args.Error(1)
+}
+
+func (m *MockReportsService) GetReport(ctx context.Context, userID, id uint) (*domain.FinancialReport, error) {
+	args := m.Called(ctx, userID, id)
+	report, _ := args.Get(0).(*domain.FinancialReport)
+	return report, args.Error(1)
+}
+
+func (m *MockReportsService) DeleteReport(ctx context.Context, userID, id uint) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func (m *MockReportsService) CompareReports(
+	ctx context.Context, userID uint, basePeriod, targetPeriod string,
+) (*domain.ReportComparison, error) {
+	args := m.Called(ctx, userID, basePeriod, targetPeriod)
+	comparison, _ := args.Get(0).(*domain.ReportComparison)
+	return comparison, args.Error(1)
+}
+
 func setupReportsHandler() (*ReportsHandler, *MockReportsService) {
 	mockService := new(MockReportsService)
 	handler := &ReportsHandler{Service: mockService}
@@ -60,10 +85,11 @@ func TestReportsHandler_GenerateMonthlyReport(t *testing.T) {
 			NetIncome:     2000.00,
 		}
 
-		mockService.On("GenerateMonthlyReport", uint(1), 2023, 6).Return(expectedReport, nil)
+		mockService.On("GenerateMonthlyReport", mock.Anything, uint(1), 2023, 6).Return(expectedReport, nil)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", http.NoBody)
 		c.Params = gin.Params{
 			{Key: "userId", Value: "1"},
 			{Key: "year", Value: "2023"},
@@ -91,6 +117,7 @@ func TestReportsHandler_GenerateMonthlyReport(t *testing.T) {
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", http.NoBody)
 		c.Params = gin.Params{
 			{Key: "userId", Value: "invalid"},
 			{Key: "year", Value: "2023"},
@@ -103,7 +130,7 @@ func TestReportsHandler_GenerateMonthlyReport(t *testing.T) {
 		var response map[string]string
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.Equal(t, "invalid user ID", response["error"])
+		assert.Equal(t, "Invalid user ID", response["error"])
 	})
 
 	t.Run("invalid year", func(t *testing.T) {
@@ -111,6 +138,7 @@ func TestReportsHandler_GenerateMonthlyReport(t *testing.T) {
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", http.NoBody)
 		c.Params = gin.Params{
 			{Key: "userId", Value: "1"},
 			{Key: "year", Value: "invalid"},
@@ -131,6 +159,7 @@ func TestReportsHandler_GenerateMonthlyReport(t *testing.T) {
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", http.NoBody)
 		c.Params = gin.Params{
 			{Key: "userId", Value: "1"},
 			{Key: "year", Value: "2023"},
@@ -148,10 +177,11 @@ func TestReportsHandler_GenerateMonthlyReport(t *testing.T) {
 
 	t.Run("service error", func(t *testing.T) {
 		handler, mockService := setupReportsHandler()
-		mockService.On("GenerateMonthlyReport", uint(1), 2023, 6).Return((*domain.FinancialReport)(nil), errors.New("service error"))
+		mockService.On("GenerateMonthlyReport", mock.Anything, uint(1), 2023, 6).Return((*domain.FinancialReport)(nil), errors.New("service error"))
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", http.NoBody)
 		c.Params = gin.Params{
 			{Key: "userId", Value: "1"},
 			{Key: "year", Value: "2023"},
@@ -183,10 +213,11 @@ func TestReportsHandler_GenerateQuarterlyReport(t *testing.T) {
 			NetIncome:     6000.00,
 		}
 
-		mockService.On("GenerateQuarterlyReport", uint(1), 2023, 2).Return(expectedReport, nil)
+		mockService.On("GenerateQuarterlyReport", mock.Anything, uint(1), 2023, 2).Return(expectedReport, nil)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", http.NoBody)
 		c.Params = gin.Params{
 			{Key: "userId", Value: "1"},
 			{Key: "year", Value: "2023"},
@@ -214,6 +245,7 @@ func TestReportsHandler_GenerateQuarterlyReport(t *testing.T) {
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", http.NoBody)
 		c.Params = gin.Params{
 			{Key: "userId", Value: "invalid"},
 			{Key: "year", Value: "2023"},
@@ -226,7 +258,7 @@ func TestReportsHandler_GenerateQuarterlyReport(t *testing.T) {
 		var response map[string]string
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.Equal(t, "invalid user ID", response["error"])
+		assert.Equal(t, "Invalid user ID", response["error"])
 	})
 
 	t.Run("invalid year", func(t *testing.T) {
@@ -234,6 +266,7 @@ func TestReportsHandler_GenerateQuarterlyReport(t *testing.T) {
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", http.NoBody)
 		c.Params = gin.Params{
 			{Key: "userId", Value: "1"},
 			{Key: "year", Value: "invalid"},
@@ -254,6 +287,7 @@ func TestReportsHandler_GenerateQuarterlyReport(t *testing.T) {
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", http.NoBody)
 		c.Params = gin.Params{
 			{Key: "userId", Value: "1"},
 			{Key: "year", Value: "2023"},
@@ -271,10 +305,11 @@ func TestReportsHandler_GenerateQuarterlyReport(t *testing.T) {
 
 	t.Run("service error", func(t *testing.T) {
 		handler, mockService := setupReportsHandler()
-		mockService.On("GenerateQuarterlyReport", uint(1), 2023, 2).Return((*domain.FinancialReport)(nil), errors.New("service error"))
+		mockService.On("GenerateQuarterlyReport", mock.Anything, uint(1), 2023, 2).Return((*domain.FinancialReport)(nil), errors.New("service error"))
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", http.NoBody)
 		c.Params = gin.Params{
 			{Key: "userId", Value: "1"},
 			{Key: "year", Value: "2023"},
@@ -306,10 +341,11 @@ func TestReportsHandler_GenerateYearlyReport(t *testing.T) {
 			NetIncome:     24000.00,
 		}
 
-		mockService.On("GenerateYearlyReport", uint(1), 2023).Return(expectedReport, nil)
+		mockService.On("GenerateYearlyReport", mock.Anything, uint(1), 2023).Return(expectedReport, nil)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", http.NoBody)
 		c.Params = gin.Params{
 			{Key: "userId", Value: "1"},
 			{Key: "year", Value: "2023"},
@@ -335,6 +371,7 @@ func TestReportsHandler_GenerateYearlyReport(t *testing.T) {
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", http.NoBody)
 		c.Params = gin.Params{
 			{Key: "userId", Value: "invalid"},
 			{Key: "year", Value: "2023"},
@@ -346,7 +383,7 @@ func TestReportsHandler_GenerateYearlyReport(t *testing.T) {
 		var response map[string]string
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.Equal(t, "invalid user ID", response["error"])
+		assert.Equal(t, "Invalid user ID", response["error"])
 	})
 
 	t.Run("invalid year", func(t *testing.T) {
@@ -354,6 +391,7 @@ func TestReportsHandler_GenerateYearlyReport(t *testing.T) {
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", http.NoBody)
 		c.Params = gin.Params{
 			{Key: "userId", Value: "1"},
 			{Key: "year", Value: "invalid"},
@@ -370,10 +408,11 @@ func TestReportsHandler_GenerateYearlyReport(t *testing.T) {
 
 	t.Run("service error", func(t *testing.T) {
 		handler, mockService := setupReportsHandler()
-		mockService.On("GenerateYearlyReport", uint(1), 2023).Return((*domain.FinancialReport)(nil), errors.New("service error"))
+		mockService.On("GenerateYearlyReport", mock.Anything, uint(1), 2023).Return((*domain.FinancialReport)(nil), errors.New("service error"))
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", http.NoBody)
 		c.Params = gin.Params{
 			{Key: "userId", Value: "1"},
 			{Key: "year", Value: "2023"},
@@ -396,7 +435,7 @@ func TestReportsHandler_GenerateCustomReport(t *testing.T) {
 	t.Run("successful custom report generation", func(t *testing.T) {
 		handler, mockService := setupReportsHandler()
 		startDate := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
-		endDate := time.Date(2023, 6, 30, 0, 0, 0, 0, time.UTC)
+		endDate := time.Date(2023, 6, 30, 23, 59, 59, 0, time.UTC)
 		expectedReport := &domain.FinancialReport{
 			ID:            1,
 			UserID:        1,
@@ -406,7 +445,7 @@ func TestReportsHandler_GenerateCustomReport(t *testing.T) {
 			NetIncome:     12000.00,
 		}
 
-		mockService.On("GenerateCustomReport", uint(1), startDate, endDate).Return(expectedReport, nil)
+		mockService.On("GenerateCustomReport", mock.Anything, uint(1), startDate, endDate).Return(expectedReport, nil)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -447,7 +486,7 @@ func TestReportsHandler_GenerateCustomReport(t *testing.T) {
 		var response map[string]string
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.Equal(t, "invalid user ID", response["error"])
+		assert.Equal(t, "Invalid user ID", response["error"])
 	})
 
 	t.Run("missing start_date", func(t *testing.T) {
@@ -466,7 +505,7 @@ func TestReportsHandler_GenerateCustomReport(t *testing.T) {
 		var response map[string]string
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.Equal(t, "start_date and end_date are required", response["error"])
+		assert.Equal(t, "Start date and end date are required", response["error"])
 	})
 
 	t.Run("missing end_date", func(t *testing.T) {
@@ -485,7 +524,7 @@ func TestReportsHandler_GenerateCustomReport(t *testing.T) {
 		var response map[string]string
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.Equal(t, "start_date and end_date are required", response["error"])
+		assert.Equal(t, "Start date and end date are required", response["error"])
 	})
 
 	t.Run("invalid start_date format", func(t *testing.T) {
@@ -542,14 +581,14 @@ func TestReportsHandler_GenerateCustomReport(t *testing.T) {
 		var response map[string]string
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.Equal(t, "start_date must be before end_date", response["error"])
+		assert.Equal(t, "Start date must be before end date", response["error"])
 	})
 
 	t.Run("service error", func(t *testing.T) {
 		handler, mockService := setupReportsHandler()
 		startDate := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
-		endDate := time.Date(2023, 6, 30, 0, 0, 0, 0, time.UTC)
-		mockService.On("GenerateCustomReport", uint(1), startDate, endDate).Return((*domain.FinancialReport)(nil), errors.New("service error"))
+		endDate := time.Date(2023, 6, 30, 23, 59, 59, 0, time.UTC)
+		mockService.On("GenerateCustomReport", mock.Anything, uint(1), startDate, endDate).Return((*domain.FinancialReport)(nil), errors.New("service error"))
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -567,16 +606,38 @@ func TestReportsHandler_GenerateCustomReport(t *testing.T) {
 		assert.Equal(t, "Failed to generate custom report", response["error"])
 		mockService.AssertExpectations(t)
 	})
+	t.Run("forbids another user's reports", func(t *testing.T) {
+		handler, mockService := setupReportsHandler()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", uint(2))
+		c.Params = gin.Params{
+			{Key: "userId", Value: "1"},
+		}
+		c.Request = httptest.NewRequest("GET", "/users/1/reports/custom?start_date=2023-01-01&end_date=2023-06-30", http.NoBody)
+
+		handler.GenerateCustomReport(c)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		mockService.AssertNotCalled(t, "GenerateCustomReport", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
 }
 
 func TestReportsHandler_GetReportsList(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	t.Run("successful reports list retrieval", func(t *testing.T) {
-		handler, _ := setupReportsHandler()
+		handler, mockService := setupReportsHandler()
+		summaries := []domain.ReportSummary{
+			{ID: 2, ReportType: "monthly", Period: "2024-02", NetIncome: 500},
+			{ID: 1, ReportType: "monthly", Period: "2024-01", NetIncome: 300},
+		}
+		mockService.On("ListReports", mock.Anything, uint(1), "monthly", 10).Return(summaries, nil)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/?type=monthly&limit=10", http.NoBody)
 		c.Params = gin.Params{
 			{Key: "userId", Value: "1"},
 		}
@@ -584,14 +645,48 @@ func TestReportsHandler_GetReportsList(t *testing.T) {
 		handler.GetReportsList(c)
 
 		assert.Equal(t, http.StatusOK, w.Code)
-		var response map[string]interface{}
+		var response struct {
+			Reports []domain.ReportSummary `json:"reports"`
+			Count   int                    `json:"count"`
+		}
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.Contains(t, response, "reports")
-		assert.Contains(t, response, "message")
-		assert.Equal(t, "Reports are generated on-demand. Use the generate endpoints to create new reports.", response["message"])
-		reports := response["reports"].([]interface{})
-		assert.Len(t, reports, 0)
+		assert.Equal(t, 2, response.Count)
+		assert.Equal(t, "2024-02", response.Reports[0].Period)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("invalid report type", func(t *testing.T) {
+		handler, mockService := setupReportsHandler()
+		mockService.On("ListReports", mock.Anything, uint(1), "weekly", 0).Return([]domain.ReportSummary(nil), domain.ErrInvalidReportType)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/?type=weekly", http.NoBody)
+		c.Params = gin.Params{
+			{Key: "userId", Value: "1"},
+		}
+
+		handler.GetReportsList(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("other user's reports", func(t *testing.T) {
+		handler, _ := setupReportsHandler()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+		c.Params = gin.Params{
+			{Key: "userId", Value: "2"},
+		}
+		c.Set("userID", uint(1))
+
+		handler.GetReportsList(c)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
 	})
 
 	t.Run("invalid user ID", func(t *testing.T) {
@@ -599,6 +694,7 @@ func TestReportsHandler_GetReportsList(t *testing.T) {
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", http.NoBody)
 		c.Params = gin.Params{
 			{Key: "userId", Value: "invalid"},
 		}
@@ -609,6 +705,181 @@ func TestReportsHandler_GetReportsList(t *testing.T) {
 		var response map[string]string
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.Equal(t, "invalid user ID", response["error"])
+		assert.Equal(t, "Invalid user ID", response["error"])
+	})
+}
+
+func TestReportsHandler_GetReport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("stored report", func(t *testing.T) {
+		handler, mockService := setupReportsHandler()
+		report := &domain.FinancialReport{ID: 5, UserID: 1, ReportType: "yearly", Period: "2023", Insights: []string{"Saved well"}}
+		mockService.On("GetReport", mock.Anything, uint(1), uint(5)).Return(report, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+		c.Params = gin.Params{{Key: "userId", Value: "1"}, {Key: "reportId", Value: "5"}}
+
+		handler.GetReport(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response domain.FinancialReport
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "2023", response.Period)
+		assert.Equal(t, []string{"Saved well"}, response.Insights)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("report not found", func(t *testing.T) {
+		handler, mockService := setupReportsHandler()
+		mockService.On("GetReport", mock.Anything, uint(1), uint(9)).Return(nil, domain.ErrNotFound)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+		c.Params = gin.Params{{Key: "userId", Value: "1"}, {Key: "reportId", Value: "9"}}
+
+		handler.GetReport(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("invalid report ID", func(t *testing.T) {
+		handler, _ := setupReportsHandler()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+		c.Params = gin.Params{{Key: "userId", Value: "1"}, {Key: "reportId", Value: "abc"}}
+
+		handler.GetReport(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestReportsHandler_DeleteReport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("deletes stored report", func(t *testing.T) {
+		handler, mockService := setupReportsHandler()
+		mockService.On("DeleteReport", mock.Anything, uint(1), uint(5)).Return(nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodDelete, "/", http.NoBody)
+		c.Params = gin.Params{{Key: "userId", Value: "1"}, {Key: "reportId", Value: "5"}}
+
+		handler.DeleteReport(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("report not found", func(t *testing.T) {
+		handler, mockService := setupReportsHandler()
+		mockService.On("DeleteReport", mock.Anything, uint(1), uint(5)).Return(domain.ErrNotFound)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodDelete, "/", http.NoBody)
+		c.Params = gin.Params{{Key: "userId", Value: "1"}, {Key: "reportId", Value: "5"}}
+
+		handler.DeleteReport(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		handler, mockService := setupReportsHandler()
+		mockService.On("DeleteReport", mock.Anything, uint(1), uint(5)).Return(errors.New("database error"))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodDelete, "/", http.NoBody)
+		c.Params = gin.Params{{Key: "userId", Value: "1"}, {Key: "reportId", Value: "5"}}
+
+		handler.DeleteReport(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestReportsHandler_CompareReports(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("compares two periods", func(t *testing.T) {
+		handler, mockService := setupReportsHandler()
+		comparison := &domain.ReportComparison{
+			Income:            domain.NewValueChange(4000, 5000),
+			SavingsRateChange: 12.5,
+			Categories:        []domain.CategoryChange{{CategoryID: 2, CategoryName: "Food", ValueChange: domain.NewValueChange(500, 800)}},
+		}
+		mockService.On("CompareReports", mock.Anything, uint(1), "2024-01", "2024-02").Return(comparison, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/?base=2024-01&target=2024-02", http.NoBody)
+		c.Params = gin.Params{{Key: "userId", Value: "1"}}
+
+		handler.CompareReports(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response map[string]interface{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 12.5, response["savings_rate_change"])
+		assert.Equal(t, 25.0, response["income"].(map[string]interface{})["change_pct"])
+		category := response["categories"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "Food", category["category_name"])
+		assert.Equal(t, 300.0, category["change"])
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("missing period", func(t *testing.T) {
+		handler, _ := setupReportsHandler()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/?base=2024-01", http.NoBody)
+		c.Params = gin.Params{{Key: "userId", Value: "1"}}
+
+		handler.CompareReports(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid period", func(t *testing.T) {
+		handler, mockService := setupReportsHandler()
+		mockService.On("CompareReports", mock.Anything, uint(1), "2024-13", "2024-02").Return(nil, domain.ErrInvalidReportPeriod)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/?base=2024-13&target=2024-02", http.NoBody)
+		c.Params = gin.Params{{Key: "userId", Value: "1"}}
+
+		handler.CompareReports(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		handler, mockService := setupReportsHandler()
+		mockService.On("CompareReports", mock.Anything, uint(1), "2024-01", "2024-02").Return(nil, errors.New("database error"))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/?base=2024-01&target=2024-02", http.NoBody)
+		c.Params = gin.Params{{Key: "userId", Value: "1"}}
+
+		handler.CompareReports(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		mockService.AssertExpectations(t)
 	})
 }
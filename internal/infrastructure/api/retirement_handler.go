@@ -0,0 +1,52 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RetirementServiceInterface defines the interface for retirement simulations
+type RetirementServiceInterface interface {
+	Simulate(ctx context.Context, userID uint, req domain.RetirementSimulationRequest) (*domain.RetirementSimulation, error)
+}
+
+type RetirementHandler struct {
+	Service RetirementServiceInterface
+}
+
+func NewRetirementHandler(service RetirementServiceInterface) *RetirementHandler {
+	return &RetirementHandler{Service: service}
+}
+
+// Simulate runs a Monte Carlo simulation of the user's portfolio growing
+// until retirement and returns yearly percentile bands with the probability
+// of reaching the target
+func (h *RetirementHandler) Simulate(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req domain.RetirementSimulationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	simulation, err := h.Service.Simulate(c.Request.Context(), userID, req)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, simulation)
+	case respondValidationError(c, err):
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "User not found")
+	default:
+		respondInternalError(c, "Failed to simulate retirement", err)
+	}
+}
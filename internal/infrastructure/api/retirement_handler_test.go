@@ -0,0 +1,118 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRetirementService is a mock implementation of RetirementServiceInterface
+type MockRetirementService struct {
+	mock.Mock
+}
+
+func (m *MockRetirementService) Simulate(
+	ctx context.Context, userID uint, req domain.RetirementSimulationRequest,
+) (*domain.RetirementSimulation, error) {
+	args := m.Called(ctx, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.RetirementSimulation), args.Error(1)
+}
+
+func setupRetirementRouter(service *MockRetirementService) *gin.Engine {
+	handler := NewRetirementHandler(service)
+	router := setupGin()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Next()
+	})
+	router.POST("/users/:userId/retirement/simulate", handler.Simulate)
+	return router
+}
+
+func TestRetirementHandler_Simulate(t *testing.T) {
+	contribution := domain.NewMoney(400)
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		mockSetup      func(*MockRetirementService)
+		expectedStatus int
+	}{
+		{
+			name: "simulates",
+			path: "/users/1/retirement/simulate",
+			body: `{"initial_balance": 5000, "monthly_contribution": 400, "years": 25, "target": 300000, ` +
+				`"allocation": [{"asset": "stocks", "share": 1}], "iterations": 2000}`,
+			mockSetup: func(m *MockRetirementService) {
+				m.On("Simulate", mock.Anything, uint(1), domain.RetirementSimulationRequest{
+					InitialBalance: domain.NewMoney(5000), MonthlyContribution: &contribution, Years: 25,
+					Target: domain.NewMoney(300000), Allocation: []domain.RetirementAllocation{{Asset: "stocks", Share: 1}},
+					Iterations: 2000,
+				}).Return(&domain.RetirementSimulation{TargetProbability: 0.62}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "malformed body",
+			path:           "/users/1/retirement/simulate",
+			body:           `{"years": "many"}`,
+			mockSetup:      func(m *MockRetirementService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid scenario",
+			path: "/users/1/retirement/simulate",
+			body: `{"years": 100, "target": 1}`,
+			mockSetup: func(m *MockRetirementService) {
+				m.On("Simulate", mock.Anything, uint(1), mock.Anything).Return(nil, &domain.ValidationError{
+					Fields: []domain.FieldError{{Field: "years", Message: "must be between 1 and 60"}},
+				})
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "service error",
+			path: "/users/1/retirement/simulate",
+			body: `{"years": 10, "target": 1000}`,
+			mockSetup: func(m *MockRetirementService) {
+				m.On("Simulate", mock.Anything, uint(1), mock.Anything).Return(nil, errors.New("db down"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "other users are forbidden",
+			path:           "/users/2/retirement/simulate",
+			body:           `{"years": 10, "target": 1000}`,
+			mockSetup:      func(m *MockRetirementService) {},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(MockRetirementService)
+			tt.mockSetup(service)
+			router := setupRetirementRouter(service)
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			service.AssertExpectations(t)
+		})
+	}
+}
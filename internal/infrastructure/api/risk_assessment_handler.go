@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RiskAssessmentServiceInterface defines the interface for risk questionnaire operations
+type RiskAssessmentServiceInterface interface {
+	CurrentQuestionnaire() *domain.RiskQuestionnaire
+	Submit(ctx context.Context, userID uint, answers map[string]string) (*domain.RiskAssessment, error)
+	History(ctx context.Context, userID uint, limit int) ([]domain.RiskAssessment, error)
+}
+
+type RiskAssessmentHandler struct {
+	Service RiskAssessmentServiceInterface
+}
+
+func NewRiskAssessmentHandler(service RiskAssessmentServiceInterface) *RiskAssessmentHandler {
+	return &RiskAssessmentHandler{Service: service}
+}
+
+// RiskAssessmentRequest maps question IDs to the IDs of the chosen options
+type RiskAssessmentRequest struct {
+	Answers map[string]string `json:"answers" binding:"required"`
+}
+
+// Questionnaire returns the questions the user is asked to answer
+func (h *RiskAssessmentHandler) Questionnaire(c *gin.Context) {
+	if _, ok := authorizedUserID(c); !ok {
+		return
+	}
+	questionnaire := h.Service.CurrentQuestionnaire()
+	c.JSON(http.StatusOK, gin.H{"questionnaire": questionnaire, "max_score": questionnaire.MaxScore()})
+}
+
+// Submit scores the user's answers, keeps the assessment and sets the user's
+// risk tolerance to the resulting profile
+func (h *RiskAssessmentHandler) Submit(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req RiskAssessmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	assessment, err := h.Service.Submit(c.Request.Context(), userID, req.Answers)
+	if respondValidationError(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "User not found")
+	case err != nil:
+		respondInternalError(c, "Failed to save risk assessment", err)
+	default:
+		c.JSON(http.StatusCreated, assessment)
+	}
+}
+
+// History returns the user's past assessments, newest first
+func (h *RiskAssessmentHandler) History(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	assessments, err := h.Service.History(c.Request.Context(), userID, limit)
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve risk assessments", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"history": assessments, "count": len(assessments)})
+}
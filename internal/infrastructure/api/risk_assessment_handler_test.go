@@ -0,0 +1,161 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockRiskAssessmentService is a mock implementation of RiskAssessmentServiceInterface
+type MockRiskAssessmentService struct {
+	mock.Mock
+}
+
+func (m *MockRiskAssessmentService) CurrentQuestionnaire() *domain.RiskQuestionnaire {
+	return &domain.DefaultRiskQuestionnaire
+}
+
+func (m *MockRiskAssessmentService) Submit(ctx context.Context, userID uint, answers map[string]string) (*domain.RiskAssessment, error) {
+	args := m.Called(ctx, userID, answers)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.RiskAssessment), args.Error(1)
+}
+
+func (m *MockRiskAssessmentService) History(ctx context.Context, userID uint, limit int) ([]domain.RiskAssessment, error) {
+	args := m.Called(ctx, userID, limit)
+	return args.Get(0).([]domain.RiskAssessment), args.Error(1)
+}
+
+func setupRiskAssessmentRouter(service *MockRiskAssessmentService, authUserID uint) *gin.Engine {
+	handler := NewRiskAssessmentHandler(service)
+	router := setupGin()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", authUserID)
+		c.Next()
+	})
+	router.GET("/users/:userId/risk-assessment/questionnaire", handler.Questionnaire)
+	router.POST("/users/:userId/risk-assessment", handler.Submit)
+	router.GET("/users/:userId/risk-assessment/history", handler.History)
+	return router
+}
+
+func TestRiskAssessmentHandler_Questionnaire(t *testing.T) {
+	router := setupRiskAssessmentRouter(new(MockRiskAssessmentService), 1)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1/risk-assessment/questionnaire", http.NoBody))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Questionnaire domain.RiskQuestionnaire `json:"questionnaire"`
+		MaxScore      int                      `json:"max_score"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Questionnaire.Questions, 3)
+	assert.Equal(t, 6, response.MaxScore)
+}
+
+func TestRiskAssessmentHandler_Submit(t *testing.T) {
+	answers := map[string]string{"time_horizon": "c", "loss_reaction": "b", "investment_goal": "c"}
+	invalid := &domain.ValidationError{Fields: []domain.FieldError{{Field: "answers.time_horizon", Message: "is required"}}}
+
+	tests := []struct {
+		name       string
+		body       string
+		result     *domain.RiskAssessment
+		err        error
+		wantStatus int
+	}{
+		{
+			name:       "should keep the assessment",
+			body:       `{"answers": {"time_horizon": "c", "loss_reaction": "b", "investment_goal": "c"}}`,
+			result:     &domain.RiskAssessment{ID: 1, UserID: 1, Score: 5, MaxScore: 6, RiskProfile: domain.RiskToleranceAggressive},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "should list invalid answers",
+			body:       `{"answers": {"time_horizon": "c", "loss_reaction": "b", "investment_goal": "c"}}`,
+			err:        invalid,
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "should report unknown users",
+			body:       `{"answers": {"time_horizon": "c", "loss_reaction": "b", "investment_goal": "c"}}`,
+			err:        domain.ErrNotFound,
+			wantStatus: http.StatusNotFound,
+		},
+		{name: "should require answers", body: `{}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockRiskAssessmentService)
+			router := setupRiskAssessmentRouter(mockService, 1)
+			mockService.On("Submit", mock.Anything, uint(1), answers).Return(tt.result, tt.err)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/1/risk-assessment", strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+
+	t.Run("should answer validation errors with their fields", func(t *testing.T) {
+		mockService := new(MockRiskAssessmentService)
+		router := setupRiskAssessmentRouter(mockService, 1)
+		mockService.On("Submit", mock.Anything, uint(1), map[string]string{}).Return(nil, invalid)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/1/risk-assessment", strings.NewReader(`{"answers": {}}`)))
+
+		var response middleware.ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, middleware.CodeValidation, response.Code)
+		assert.Equal(t, invalid.Fields, response.Fields)
+	})
+
+	t.Run("should forbid other users' assessments", func(t *testing.T) {
+		mockService := new(MockRiskAssessmentService)
+		router := setupRiskAssessmentRouter(mockService, 2)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/1/risk-assessment", strings.NewReader(`{"answers": {}}`)))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		mockService.AssertNotCalled(t, "Submit")
+	})
+}
+
+func TestRiskAssessmentHandler_History(t *testing.T) {
+	mockService := new(MockRiskAssessmentService)
+	router := setupRiskAssessmentRouter(mockService, 1)
+	mockService.On("History", mock.Anything, uint(1), 5).Return([]domain.RiskAssessment{
+		{ID: 2, UserID: 1, RiskProfile: domain.RiskToleranceModerate},
+		{ID: 1, UserID: 1, RiskProfile: domain.RiskToleranceConservative},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1/risk-assessment/history?limit=5", http.NoBody))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		History []domain.RiskAssessment `json:"history"`
+		Count   int                     `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 2, response.Count)
+	assert.Equal(t, domain.RiskToleranceModerate, response.History[0].RiskProfile)
+}
@@ -0,0 +1,188 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SavingsRuleServiceInterface defines the interface for savings rule operations
+type SavingsRuleServiceInterface interface {
+	List(ctx context.Context, userID uint) ([]domain.SavingsRule, error)
+	Create(ctx context.Context, userID uint, rule domain.SavingsRule) (*domain.SavingsRule, error)
+	Update(ctx context.Context, userID, ruleID uint, updates domain.SavingsRule) (*domain.SavingsRule, error)
+	Delete(ctx context.Context, userID, ruleID uint) error
+	Preview(ctx context.Context, userID uint, amount domain.Money, categoryID *uint) (*domain.SavingsPreview, error)
+	Allocations(ctx context.Context, userID uint) ([]domain.SavingsAllocation, error)
+}
+
+type SavingsRuleHandler struct {
+	Service SavingsRuleServiceInterface
+}
+
+func NewSavingsRuleHandler(service SavingsRuleServiceInterface) *SavingsRuleHandler {
+	return &SavingsRuleHandler{Service: service}
+}
+
+// SavingsRuleRequest is the body of rule requests. Without a category_id the
+// rule applies to all income; rules are active unless is_active is false.
+type SavingsRuleRequest struct {
+	GoalID     uint    `json:"goal_id"`
+	CategoryID *uint   `json:"category_id"`
+	Name       string  `json:"name"`
+	Percent    float64 `json:"percent"`
+	IsActive   *bool   `json:"is_active"`
+}
+
+func (r *SavingsRuleRequest) rule() domain.SavingsRule {
+	active := r.IsActive == nil || *r.IsActive
+	return domain.SavingsRule{GoalID: r.GoalID, CategoryID: r.CategoryID, Name: r.Name, Percent: r.Percent, IsActive: active}
+}
+
+// SavingsPreviewRequest is the income the rules are previewed for
+type SavingsPreviewRequest struct {
+	Amount     domain.Money `json:"amount"`
+	CategoryID *uint        `json:"category_id"`
+}
+
+// savingsRuleIDParam reads the ruleId parameter
+func savingsRuleIDParam(c *gin.Context) (uint, bool) {
+	ruleID, err := strconv.ParseUint(c.Param("ruleId"), 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid rule ID")
+		return 0, false
+	}
+	return uint(ruleID), true
+}
+
+// List returns the user's savings rules
+func (h *SavingsRuleHandler) List(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+	rules, err := h.Service.List(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve savings rules", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"rules": rules, "count": len(rules)})
+}
+
+// Create adds a savings rule
+func (h *SavingsRuleHandler) Create(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+	var req SavingsRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	rule, err := h.Service.Create(c.Request.Context(), userID, req.rule())
+	if respondValidationError(c, err) {
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to create savings rule", err)
+		return
+	}
+	c.JSON(http.StatusCreated, rule)
+}
+
+// Update replaces a savings rule
+func (h *SavingsRuleHandler) Update(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+	ruleID, ok := savingsRuleIDParam(c)
+	if !ok {
+		return
+	}
+	var req SavingsRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	rule, err := h.Service.Update(c.Request.Context(), userID, ruleID, req.rule())
+	if respondValidationError(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "Savings rule not found")
+	case err != nil:
+		respondInternalError(c, "Failed to update savings rule", err)
+	default:
+		c.JSON(http.StatusOK, rule)
+	}
+}
+
+// Delete removes a savings rule
+func (h *SavingsRuleHandler) Delete(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+	ruleID, ok := savingsRuleIDParam(c)
+	if !ok {
+		return
+	}
+
+	err := h.Service.Delete(c.Request.Context(), userID, ruleID)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "Savings rule not found")
+	case err != nil:
+		respondInternalError(c, "Failed to delete savings rule", err)
+	default:
+		c.JSON(http.StatusOK, gin.H{"message": "Savings rule deleted"})
+	}
+}
+
+// Preview shows what the rules would set aside from an income
+func (h *SavingsRuleHandler) Preview(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+	var req SavingsPreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	preview, err := h.Service.Preview(c.Request.Context(), userID, req.Amount, req.CategoryID)
+	if respondValidationError(c, err) {
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to preview savings rules", err)
+		return
+	}
+	c.JSON(http.StatusOK, preview)
+}
+
+// Allocations lists what the rules have set aside from the user's income
+func (h *SavingsRuleHandler) Allocations(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+	allocations, err := h.Service.Allocations(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve savings allocations", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"allocations": allocations, "count": len(allocations)})
+}
@@ -0,0 +1,201 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSavingsRuleService is a mock implementation of SavingsRuleServiceInterface
+type MockSavingsRuleService struct {
+	mock.Mock
+}
+
+func (m *MockSavingsRuleService) List(ctx context.Context, userID uint) ([]domain.SavingsRule, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.SavingsRule), args.Error(1)
+}
+
+func (m *MockSavingsRuleService) Create(ctx context.Context, userID uint, rule domain.SavingsRule) (*domain.SavingsRule, error) {
+	args := m.Called(ctx, userID, rule)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SavingsRule), args.Error(1)
+}
+
+func (m *MockSavingsRuleService) Update(ctx context.Context, userID, ruleID uint, updates domain.SavingsRule) (*domain.SavingsRule, error) {
+	args := m.Called(ctx, userID, ruleID, updates)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SavingsRule), args.Error(1)
+}
+
+func (m *MockSavingsRuleService) Delete(ctx context.Context, userID, ruleID uint) error {
+	return m.Called(ctx, userID, ruleID).Error(0)
+}
+
+func (m *MockSavingsRuleService) Preview(
+	ctx context.Context, userID uint, amount domain.Money, categoryID *uint,
+) (*domain.SavingsPreview, error) {
+	args := m.Called(ctx, userID, amount, categoryID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SavingsPreview), args.Error(1)
+}
+
+func (m *MockSavingsRuleService) Allocations(ctx context.Context, userID uint) ([]domain.SavingsAllocation, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.SavingsAllocation), args.Error(1)
+}
+
+func setupSavingsRuleRouter(service *MockSavingsRuleService) *gin.Engine {
+	handler := NewSavingsRuleHandler(service)
+	router := setupGin()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Next()
+	})
+	router.GET("/users/:userId/savings-rules", handler.List)
+	router.POST("/users/:userId/savings-rules", handler.Create)
+	router.PUT("/users/:userId/savings-rules/:ruleId", handler.Update)
+	router.DELETE("/users/:userId/savings-rules/:ruleId", handler.Delete)
+	router.POST("/users/:userId/savings-rules/preview", handler.Preview)
+	router.GET("/users/:userId/savings-rules/allocations", handler.Allocations)
+	return router
+}
+
+func TestSavingsRuleHandler(t *testing.T) {
+	salary := uint(3)
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		mockSetup      func(*MockSavingsRuleService)
+		expectedStatus int
+	}{
+		{
+			name:   "list rules",
+			method: http.MethodGet,
+			path:   "/users/1/savings-rules",
+			mockSetup: func(m *MockSavingsRuleService) {
+				m.On("List", mock.Anything, uint(1)).Return([]domain.SavingsRule{{ID: 1, GoalID: 2, Percent: 10}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "list another user's rules",
+			method:         http.MethodGet,
+			path:           "/users/2/savings-rules",
+			mockSetup:      func(m *MockSavingsRuleService) {},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "create a rule, active by default",
+			method: http.MethodPost,
+			path:   "/users/1/savings-rules",
+			body:   `{"goal_id": 2, "name": "House", "percent": 10}`,
+			mockSetup: func(m *MockSavingsRuleService) {
+				m.On("Create", mock.Anything, uint(1), domain.SavingsRule{GoalID: 2, Name: "House", Percent: 10, IsActive: true}).
+					Return(&domain.SavingsRule{ID: 1, GoalID: 2, Percent: 10, IsActive: true}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:   "create a rule over 100%",
+			method: http.MethodPost,
+			path:   "/users/1/savings-rules",
+			body:   `{"goal_id": 2, "percent": 70, "is_active": true}`,
+			mockSetup: func(m *MockSavingsRuleService) {
+				m.On("Create", mock.Anything, uint(1), domain.SavingsRule{GoalID: 2, Percent: 70, IsActive: true}).
+					Return(nil, &domain.ValidationError{Fields: []domain.FieldError{{Field: "percent", Message: "too much"}}})
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "pause a rule",
+			method: http.MethodPut,
+			path:   "/users/1/savings-rules/4",
+			body:   `{"goal_id": 2, "category_id": 3, "percent": 10, "is_active": false}`,
+			mockSetup: func(m *MockSavingsRuleService) {
+				m.On("Update", mock.Anything, uint(1), uint(4), domain.SavingsRule{GoalID: 2, CategoryID: &salary, Percent: 10}).
+					Return(&domain.SavingsRule{ID: 4}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "update a missing rule",
+			method: http.MethodPut,
+			path:   "/users/1/savings-rules/4",
+			body:   `{"goal_id": 2, "percent": 10}`,
+			mockSetup: func(m *MockSavingsRuleService) {
+				m.On("Update", mock.Anything, uint(1), uint(4), mock.Anything).Return(nil, domain.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "update an invalid rule ID",
+			method:         http.MethodPut,
+			path:           "/users/1/savings-rules/abc",
+			body:           `{"goal_id": 2, "percent": 10}`,
+			mockSetup:      func(m *MockSavingsRuleService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "delete a rule",
+			method: http.MethodDelete,
+			path:   "/users/1/savings-rules/4",
+			mockSetup: func(m *MockSavingsRuleService) {
+				m.On("Delete", mock.Anything, uint(1), uint(4)).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "preview an income",
+			method: http.MethodPost,
+			path:   "/users/1/savings-rules/preview",
+			body:   `{"amount": 2500, "category_id": 3}`,
+			mockSetup: func(m *MockSavingsRuleService) {
+				m.On("Preview", mock.Anything, uint(1), domain.NewMoney(2500), &salary).
+					Return(&domain.SavingsPreview{Amount: domain.NewMoney(2500), Remaining: domain.NewMoney(2500)}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "list allocations",
+			method: http.MethodGet,
+			path:   "/users/1/savings-rules/allocations",
+			mockSetup: func(m *MockSavingsRuleService) {
+				m.On("Allocations", mock.Anything, uint(1)).Return([]domain.SavingsAllocation{{ID: 1, Amount: domain.NewMoney(250)}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(MockSavingsRuleService)
+			tt.mockSetup(service)
+			router := setupSavingsRuleRouter(service)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			service.AssertExpectations(t)
+		})
+	}
+}
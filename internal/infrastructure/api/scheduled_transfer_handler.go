@@ -0,0 +1,219 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ScheduledTransferServiceInterface defines the interface for scheduled transfer operations
+type ScheduledTransferServiceInterface interface {
+	Create(ctx context.Context, userID uint, transfer *domain.ScheduledTransfer) error
+	List(ctx context.Context, userID uint) ([]domain.ScheduledTransfer, error)
+	Get(ctx context.Context, userID, id uint) (*domain.ScheduledTransfer, error)
+	Update(ctx context.Context, userID uint, transfer *domain.ScheduledTransfer) error
+	Delete(ctx context.Context, userID, id uint) error
+}
+
+type ScheduledTransferHandler struct {
+	Service ScheduledTransferServiceInterface
+}
+
+func NewScheduledTransferHandler(service ScheduledTransferServiceInterface) *ScheduledTransferHandler {
+	return &ScheduledTransferHandler{Service: service}
+}
+
+// CreateScheduledTransferRequest is the body of requests scheduling a
+// transfer. Dates are YYYY-MM-DD; without an end date a repeating transfer
+// goes on until it is deactivated.
+type CreateScheduledTransferRequest struct {
+	FromAccountID uint         `json:"from_account_id" binding:"required"`
+	ToAccountID   uint         `json:"to_account_id" binding:"required"`
+	Amount        domain.Money `json:"amount"`
+	Description   string       `json:"description"`
+	Frequency     string       `json:"frequency" binding:"required"`
+	StartDate     string       `json:"start_date" binding:"required"`
+	EndDate       string       `json:"end_date"`
+}
+
+// UpdateScheduledTransferRequest changes the given fields; an empty
+// end_date removes the end date
+type UpdateScheduledTransferRequest struct {
+	FromAccountID *uint         `json:"from_account_id,omitempty"`
+	ToAccountID   *uint         `json:"to_account_id,omitempty"`
+	Amount        *domain.Money `json:"amount,omitempty"`
+	Description   *string       `json:"description,omitempty"`
+	Frequency     *string       `json:"frequency,omitempty"`
+	StartDate     *string       `json:"start_date,omitempty"`
+	EndDate       *string       `json:"end_date,omitempty"`
+	Active        *bool         `json:"active,omitempty"`
+}
+
+// parseScheduledTransferIDs reads the userId and transferId parameters.
+// Users can only manage their own scheduled transfers.
+func parseScheduledTransferIDs(c *gin.Context) (userID, transferID uint, ok bool) {
+	userID, ok = authorizedUserID(c)
+	if !ok {
+		return 0, 0, false
+	}
+	id, err := strconv.ParseUint(c.Param("transferId"), 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid scheduled transfer ID")
+		return 0, 0, false
+	}
+	return userID, uint(id), true
+}
+
+func respondScheduledTransferError(c *gin.Context, err error, message string) {
+	if respondValidationError(c, err) {
+		return
+	}
+	if errors.Is(err, domain.ErrNotFound) {
+		respondError(c, middleware.CodeNotFound, "Scheduled transfer not found")
+		return
+	}
+	respondInternalError(c, message, err)
+}
+
+// Create schedules a transfer between two of the user's accounts
+func (h *ScheduledTransferHandler) Create(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req CreateScheduledTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+	startDate, err := parseOptionalDate(req.StartDate)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidDate, "Invalid start date format. Use YYYY-MM-DD")
+		return
+	}
+	endDate, err := parseOptionalDate(req.EndDate)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidDate, "Invalid end date format. Use YYYY-MM-DD")
+		return
+	}
+
+	transfer := &domain.ScheduledTransfer{
+		FromAccountID: req.FromAccountID, ToAccountID: req.ToAccountID, Amount: req.Amount,
+		Description: req.Description, Frequency: req.Frequency, StartDate: *startDate, EndDate: endDate,
+	}
+	if err := h.Service.Create(c.Request.Context(), userID, transfer); err != nil {
+		respondScheduledTransferError(c, err, "Failed to schedule transfer")
+		return
+	}
+	c.JSON(http.StatusCreated, transfer)
+}
+
+// List returns the user's scheduled transfers
+func (h *ScheduledTransferHandler) List(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	transfers, err := h.Service.List(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve scheduled transfers", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"scheduled_transfers": transfers, "count": len(transfers)})
+}
+
+// Get returns one scheduled transfer
+func (h *ScheduledTransferHandler) Get(c *gin.Context) {
+	userID, transferID, ok := parseScheduledTransferIDs(c)
+	if !ok {
+		return
+	}
+
+	transfer, err := h.Service.Get(c.Request.Context(), userID, transferID)
+	if err != nil {
+		respondScheduledTransferError(c, err, "Failed to retrieve scheduled transfer")
+		return
+	}
+	c.JSON(http.StatusOK, transfer)
+}
+
+// Update changes the accounts, amount, schedule or active flag of a
+// scheduled transfer
+func (h *ScheduledTransferHandler) Update(c *gin.Context) {
+	userID, transferID, ok := parseScheduledTransferIDs(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateScheduledTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	transfer, err := h.Service.Get(c.Request.Context(), userID, transferID)
+	if err != nil {
+		respondScheduledTransferError(c, err, "Failed to retrieve scheduled transfer")
+		return
+	}
+	if req.StartDate != nil {
+		startDate, err := parseOptionalDate(*req.StartDate)
+		if err != nil || startDate == nil {
+			respondError(c, middleware.CodeInvalidDate, "Invalid start date format. Use YYYY-MM-DD")
+			return
+		}
+		transfer.StartDate = *startDate
+	}
+	if req.EndDate != nil {
+		if transfer.EndDate, err = parseOptionalDate(*req.EndDate); err != nil {
+			respondError(c, middleware.CodeInvalidDate, "Invalid end date format. Use YYYY-MM-DD")
+			return
+		}
+	}
+	if req.FromAccountID != nil {
+		transfer.FromAccountID = *req.FromAccountID
+	}
+	if req.ToAccountID != nil {
+		transfer.ToAccountID = *req.ToAccountID
+	}
+	if req.Amount != nil {
+		transfer.Amount = *req.Amount
+	}
+	if req.Description != nil {
+		transfer.Description = *req.Description
+	}
+	if req.Frequency != nil {
+		transfer.Frequency = *req.Frequency
+	}
+	if req.Active != nil {
+		transfer.Active = *req.Active
+	}
+
+	if err := h.Service.Update(c.Request.Context(), userID, transfer); err != nil {
+		respondScheduledTransferError(c, err, "Failed to update scheduled transfer")
+		return
+	}
+	c.JSON(http.StatusOK, transfer)
+}
+
+// Delete removes a scheduled transfer; the transfers it made stay
+func (h *ScheduledTransferHandler) Delete(c *gin.Context) {
+	userID, transferID, ok := parseScheduledTransferIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Service.Delete(c.Request.Context(), userID, transferID); err != nil {
+		respondScheduledTransferError(c, err, "Failed to delete scheduled transfer")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Scheduled transfer deleted successfully"})
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockScheduledTransferService is a mock implementation of ScheduledTransferServiceInterface
+type MockScheduledTransferService struct {
+	mock.Mock
+}
+
+func (m *MockScheduledTransferService) Create(ctx context.Context, userID uint, transfer *domain.ScheduledTransfer) error {
+	return m.Called(ctx, userID, transfer).Error(0)
+}
+
+func (m *MockScheduledTransferService) List(ctx context.Context, userID uint) ([]domain.ScheduledTransfer, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.ScheduledTransfer), args.Error(1)
+}
+
+func (m *MockScheduledTransferService) Get(ctx context.Context, userID, id uint) (*domain.ScheduledTransfer, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ScheduledTransfer), args.Error(1)
+}
+
+func (m *MockScheduledTransferService) Update(ctx context.Context, userID uint, transfer *domain.ScheduledTransfer) error {
+	return m.Called(ctx, userID, transfer).Error(0)
+}
+
+func (m *MockScheduledTransferService) Delete(ctx context.Context, userID, id uint) error {
+	return m.Called(ctx, userID, id).Error(0)
+}
+
+func setupScheduledTransferRouter(service *MockScheduledTransferService) *gin.Engine {
+	handler := NewScheduledTransferHandler(service)
+	router := setupGin()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Next()
+	})
+	router.GET("/users/:userId/scheduled-transfers", handler.List)
+	router.POST("/users/:userId/scheduled-transfers", handler.Create)
+	router.GET("/users/:userId/scheduled-transfers/:transferId", handler.Get)
+	router.PUT("/users/:userId/scheduled-transfers/:transferId", handler.Update)
+	router.DELETE("/users/:userId/scheduled-transfers/:transferId", handler.Delete)
+	return router
+}
+
+func TestScheduledTransferHandler(t *testing.T) {
+	t.Run("should schedule a transfer", func(t *testing.T) {
+		service := new(MockScheduledTransferService)
+		start := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+		service.On("Create", mock.Anything, uint(1), mock.MatchedBy(func(transfer *domain.ScheduledTransfer) bool {
+			return transfer.FromAccountID == 3 && transfer.ToAccountID == 4 && transfer.Amount == domain.NewMoney(200) &&
+				transfer.Frequency == domain.TransferFrequencyMonthly && transfer.StartDate.Equal(start) && transfer.EndDate == nil
+		})).Return(nil)
+
+		body := `{"from_account_id":3,"to_account_id":4,"amount":200,"description":"Savings","frequency":"monthly","start_date":"2025-01-31"}`
+		req := httptest.NewRequest(http.MethodPost, "/users/1/scheduled-transfers", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		setupScheduledTransferRouter(service).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		service.AssertExpectations(t)
+	})
+
+	t.Run("should reject invalid dates and validation errors", func(t *testing.T) {
+		service := new(MockScheduledTransferService)
+		service.On("Create", mock.Anything, uint(1), mock.Anything).Return(&domain.ValidationError{
+			Fields: []domain.FieldError{{Field: "to_account_id", Message: "must hold the same currency as from_account_id"}},
+		})
+
+		for body, want := range map[string]int{
+			`{"from_account_id":3,"to_account_id":4,"amount":200,"frequency":"monthly","start_date":"31/01/2025"}`: http.StatusBadRequest,
+			`{"from_account_id":3,"to_account_id":5,"amount":200,"frequency":"monthly","start_date":"2025-01-31"}`: http.StatusUnprocessableEntity,
+		} {
+			req := httptest.NewRequest(http.MethodPost, "/users/1/scheduled-transfers", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			setupScheduledTransferRouter(service).ServeHTTP(w, req)
+
+			assert.Equal(t, want, w.Code, body)
+		}
+	})
+
+	t.Run("should forbid other users' scheduled transfers", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		setupScheduledTransferRouter(new(MockScheduledTransferService)).
+			ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/2/scheduled-transfers", http.NoBody))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("should update the given fields only", func(t *testing.T) {
+		service := new(MockScheduledTransferService)
+		end := time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)
+		transfer := &domain.ScheduledTransfer{
+			ID: 5, UserID: 1, FromAccountID: 3, ToAccountID: 4, Amount: domain.NewMoney(200), Description: "Savings",
+			Frequency: domain.TransferFrequencyMonthly, StartDate: time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC), EndDate: &end, Active: true,
+		}
+		service.On("Get", mock.Anything, uint(1), uint(5)).Return(transfer, nil)
+		service.On("Update", mock.Anything, uint(1), mock.MatchedBy(func(transfer *domain.ScheduledTransfer) bool {
+			return transfer.Amount == domain.NewMoney(250) && transfer.EndDate == nil && !transfer.Active && transfer.ToAccountID == 4
+		})).Return(nil)
+
+		req := httptest.NewRequest(http.MethodPut, "/users/1/scheduled-transfers/5",
+			strings.NewReader(`{"amount":250,"end_date":"","active":false}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		setupScheduledTransferRouter(service).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		service.AssertExpectations(t)
+	})
+
+	t.Run("should return not found for missing scheduled transfers", func(t *testing.T) {
+		service := new(MockScheduledTransferService)
+		service.On("Delete", mock.Anything, uint(1), uint(9)).Return(domain.ErrNotFound)
+
+		w := httptest.NewRecorder()
+		setupScheduledTransferRouter(service).
+			ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/users/1/scheduled-transfers/9", http.NoBody))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
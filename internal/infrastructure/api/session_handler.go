@@ -0,0 +1,169 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SessionServiceInterface defines the interface for login session operations
+type SessionServiceInterface interface {
+	Start(ctx context.Context, userID uint, device domain.Device) (*domain.Session, string, error)
+	Refresh(ctx context.Context, refreshToken string, device domain.Device) (*domain.Session, string, error)
+	List(ctx context.Context, userID uint) ([]domain.Session, error)
+	Revoke(ctx context.Context, userID, sessionID uint) error
+	RevokeAll(ctx context.Context, userID, exceptID uint) (int64, error)
+}
+
+type SessionHandler struct {
+	Service SessionServiceInterface
+}
+
+func NewSessionHandler(service SessionServiceInterface) *SessionHandler {
+	return &SessionHandler{Service: service}
+}
+
+// RefreshRequest is the body of token refreshes
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// device describes the client making the request
+func device(c *gin.Context) domain.Device {
+	return domain.Device{UserAgent: c.Request.UserAgent(), IPAddress: c.ClientIP()}
+}
+
+// currentSessionID returns the session the request's token belongs to, or zero
+func currentSessionID(c *gin.Context) uint {
+	actor, _ := domain.ActorFromContext(c.Request.Context())
+	return actor.SessionID
+}
+
+// issueTokens adds an access token for the user to a login response. With
+// sessions it opens one for the device and adds its refresh token too.
+func issueTokens(c *gin.Context, sessions SessionServiceInterface, userID uint, response gin.H) bool {
+	if sessions == nil {
+		token, err := middleware.GenerateToken(userID)
+		if err != nil {
+			respondInternalError(c, "Token generation failed", err)
+			return false
+		}
+		response["token"] = token
+		return true
+	}
+
+	session, refreshToken, err := sessions.Start(c.Request.Context(), userID, device(c))
+	if err != nil {
+		respondInternalError(c, "Failed to start session", err)
+		return false
+	}
+	return sessionTokens(c, session, refreshToken, response)
+}
+
+// sessionTokens adds the access and refresh tokens of a session to a response
+func sessionTokens(c *gin.Context, session *domain.Session, refreshToken string, response gin.H) bool {
+	token, err := middleware.GenerateSessionToken(session.UserID, session.ID)
+	if err != nil {
+		respondInternalError(c, "Token generation failed", err)
+		return false
+	}
+	response["token"] = token
+	response["refresh_token"] = refreshToken
+	response["session_id"] = session.ID
+	response["refresh_expires_at"] = session.ExpiresAt
+	return true
+}
+
+// Refresh exchanges a refresh token for a new access token and a new refresh
+// token; the old refresh token stops working
+func (h *SessionHandler) Refresh(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	session, refreshToken, err := h.Service.Refresh(c.Request.Context(), req.RefreshToken, device(c))
+	if errors.Is(err, domain.ErrInvalidSession) {
+		respondError(c, middleware.CodeInvalidToken, "Invalid refresh token")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to refresh session", err)
+		return
+	}
+
+	response := gin.H{}
+	if sessionTokens(c, session, refreshToken, response) {
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// List returns the devices the user is logged in on, marking the one making
+// the request as current
+func (h *SessionHandler) List(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	sessions, err := h.Service.List(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve sessions", err)
+		return
+	}
+	current := currentSessionID(c)
+	for i := range sessions {
+		sessions[i].Current = current != 0 && sessions[i].ID == current
+	}
+	c.JSON(http.StatusOK, gin.H{"sessions": sessions, "count": len(sessions)})
+}
+
+// Revoke signs one of the user's devices out
+func (h *SessionHandler) Revoke(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+	sessionID, err := strconv.ParseUint(c.Param("sessionId"), 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid session ID")
+		return
+	}
+
+	err = h.Service.Revoke(c.Request.Context(), userID, uint(sessionID))
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "Session not found")
+	case err != nil:
+		respondInternalError(c, "Failed to revoke session", err)
+	default:
+		c.JSON(http.StatusOK, gin.H{"message": "Session revoked"})
+	}
+}
+
+// RevokeAll signs the user out on every device. With ?except_current=true
+// the device making the request stays logged in.
+func (h *SessionHandler) RevokeAll(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+	var exceptID uint
+	if c.Query("except_current") == "true" {
+		exceptID = currentSessionID(c)
+	}
+
+	revoked, err := h.Service.RevokeAll(c.Request.Context(), userID, exceptID)
+	if err != nil {
+		respondInternalError(c, "Failed to revoke sessions", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Sessions revoked", "revoked": revoked})
+}
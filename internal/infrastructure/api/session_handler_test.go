@@ -0,0 +1,235 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockSessionService is a mock implementation of SessionServiceInterface
+type MockSessionService struct {
+	mock.Mock
+}
+
+func (m *MockSessionService) Start(ctx context.Context, userID uint, device domain.Device) (*domain.Session, string, error) {
+	args := m.Called(ctx, userID, device)
+	if args.Get(0) == nil {
+		return nil, "", args.Error(2)
+	}
+	return args.Get(0).(*domain.Session), args.String(1), args.Error(2)
+}
+
+func (m *MockSessionService) Refresh(ctx context.Context, refreshToken string, device domain.Device) (*domain.Session, string, error) {
+	args := m.Called(ctx, refreshToken, device)
+	if args.Get(0) == nil {
+		return nil, "", args.Error(2)
+	}
+	return args.Get(0).(*domain.Session), args.String(1), args.Error(2)
+}
+
+func (m *MockSessionService) List(ctx context.Context, userID uint) ([]domain.Session, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.Session), args.Error(1)
+}
+
+func (m *MockSessionService) Revoke(ctx context.Context, userID, sessionID uint) error {
+	return m.Called(ctx, userID, sessionID).Error(0)
+}
+
+func (m *MockSessionService) RevokeAll(ctx context.Context, userID, exceptID uint) (int64, error) {
+	args := m.Called(ctx, userID, exceptID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// setupSessionRouter authenticates requests as the user in the session, the
+// way AuthMiddleware does for session tokens
+func setupSessionRouter(service *MockSessionService, authUserID, sessionID uint) *gin.Engine {
+	handler := NewSessionHandler(service)
+	router := setupGin()
+	router.POST("/auth/refresh", handler.Refresh)
+	authenticated := router.Group("/", func(c *gin.Context) {
+		c.Set("userID", authUserID)
+		c.Request = c.Request.WithContext(domain.ContextWithActor(c.Request.Context(), domain.Actor{
+			UserID: authUserID, SessionID: sessionID,
+		}))
+		c.Next()
+	})
+	authenticated.GET("/users/:userId/sessions", handler.List)
+	authenticated.DELETE("/users/:userId/sessions/:sessionId", handler.Revoke)
+	authenticated.DELETE("/users/:userId/sessions", handler.RevokeAll)
+	return router
+}
+
+func TestSessionHandler_Refresh(t *testing.T) {
+	session := &domain.Session{ID: 3, UserID: 1, ExpiresAt: time.Now().Add(time.Hour)}
+
+	t.Run("should issue new tokens", func(t *testing.T) {
+		mockService := new(MockSessionService)
+		router := setupSessionRouter(mockService, 1, 3)
+		mockService.On("Refresh", mock.Anything, "old-refresh", mock.Anything).Return(session, "new-refresh", nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/auth/refresh", strings.NewReader(`{"refresh_token": "old-refresh"}`)))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			Token        string `json:"token"`
+			RefreshToken string `json:"refresh_token"`
+			SessionID    uint   `json:"session_id"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "new-refresh", response.RefreshToken)
+		assert.Equal(t, uint(3), response.SessionID)
+
+		claims, err := middleware.ParseToken(response.Token)
+		require.NoError(t, err)
+		assert.Equal(t, uint(1), claims.UserID)
+		assert.Equal(t, uint(3), claims.SessionID)
+	})
+
+	t.Run("should reject invalid refresh tokens", func(t *testing.T) {
+		mockService := new(MockSessionService)
+		router := setupSessionRouter(mockService, 1, 3)
+		mockService.On("Refresh", mock.Anything, "revoked", mock.Anything).Return(nil, "", domain.ErrInvalidSession)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/auth/refresh", strings.NewReader(`{"refresh_token": "revoked"}`)))
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("should require a refresh token", func(t *testing.T) {
+		router := setupSessionRouter(new(MockSessionService), 1, 3)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/auth/refresh", strings.NewReader(`{}`)))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestSessionHandler_List(t *testing.T) {
+	mockService := new(MockSessionService)
+	router := setupSessionRouter(mockService, 1, 2)
+	mockService.On("List", mock.Anything, uint(1)).Return([]domain.Session{
+		{ID: 1, UserID: 1, UserAgent: "Firefox"}, {ID: 2, UserID: 1, UserAgent: "finance-ios"},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1/sessions", http.NoBody))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Sessions []domain.Session `json:"sessions"`
+		Count    int              `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 2, response.Count)
+	assert.False(t, response.Sessions[0].Current)
+	assert.True(t, response.Sessions[1].Current)
+	assert.NotContains(t, w.Body.String(), "refresh_token_hash")
+
+	t.Run("should forbid other users' sessions", func(t *testing.T) {
+		mockService := new(MockSessionService)
+		router := setupSessionRouter(mockService, 2, 5)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1/sessions", http.NoBody))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		mockService.AssertNotCalled(t, "List")
+	})
+}
+
+func TestSessionHandler_Revoke(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		err        error
+		wantStatus int
+	}{
+		{name: "should revoke the session", path: "/users/1/sessions/4", wantStatus: http.StatusOK},
+		{name: "should report unknown sessions", path: "/users/1/sessions/4", err: domain.ErrNotFound, wantStatus: http.StatusNotFound},
+		{name: "should reject invalid session IDs", path: "/users/1/sessions/abc", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockSessionService)
+			router := setupSessionRouter(mockService, 1, 2)
+			mockService.On("Revoke", mock.Anything, uint(1), uint(4)).Return(tt.err)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, tt.path, http.NoBody))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestSessionHandler_RevokeAll(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		exceptID uint
+	}{
+		{name: "should revoke every session", exceptID: 0},
+		{name: "should keep the current session", query: "?except_current=true", exceptID: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockSessionService)
+			router := setupSessionRouter(mockService, 1, 2)
+			mockService.On("RevokeAll", mock.Anything, uint(1), tt.exceptID).Return(int64(3), nil)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/users/1/sessions"+tt.query, http.NoBody))
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.JSONEq(t, `{"message": "Sessions revoked", "revoked": 3}`, w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserHandler_LoginStartsSession(t *testing.T) {
+	handler, mockService := setupUserHandler()
+	sessions := new(MockSessionService)
+	handler.Sessions = sessions
+	router := setupGin()
+	router.POST("/login", handler.Login)
+
+	user := &domain.User{ID: 1, Email: "test@example.com"}
+	mockService.On("Login", mock.Anything, "test@example.com", "password123").Return(user, nil)
+	device := domain.Device{UserAgent: "finance-web/1.0", IPAddress: "192.0.2.10"}
+	sessions.On("Start", mock.Anything, uint(1), device).Return(&domain.Session{ID: 9, UserID: 1}, "refresh-token", nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"email": "test@example.com", "password": "password123"}`))
+	req.Header.Set("User-Agent", "finance-web/1.0")
+	req.RemoteAddr = "192.0.2.10:5000"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "refresh-token", response["refresh_token"])
+	assert.Equal(t, float64(9), response["session_id"])
+
+	claims, err := middleware.ParseToken(response["token"].(string))
+	require.NoError(t, err)
+	assert.Equal(t, uint(9), claims.SessionID)
+}
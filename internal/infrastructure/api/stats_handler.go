@@ -0,0 +1,49 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StatsServiceInterface defines the interface for the admins' usage stats
+type StatsServiceInterface interface {
+	Report(ctx context.Context, filter domain.StatsFilter) (*domain.StatsReport, error)
+}
+
+type StatsHandler struct {
+	Service StatsServiceInterface
+}
+
+func NewStatsHandler(service StatsServiceInterface) *StatsHandler {
+	return &StatsHandler{Service: service}
+}
+
+// GetStats returns the anonymous daily usage stats with their totals, the
+// last 30 days unless from and to (YYYY-MM-DD, inclusive) say otherwise
+func (h *StatsHandler) GetStats(c *gin.Context) {
+	var filter domain.StatsFilter
+	var err error
+	if filter.From, err = parseOptionalDate(c.Query("from")); err != nil {
+		respondError(c, middleware.CodeInvalidDate, "Invalid from date format. Use YYYY-MM-DD")
+		return
+	}
+	if filter.To, err = parseOptionalDate(c.Query("to")); err != nil {
+		respondError(c, middleware.CodeInvalidDate, "Invalid to date format. Use YYYY-MM-DD")
+		return
+	}
+
+	report, err := h.Service.Report(c.Request.Context(), filter)
+	if respondValidationError(c, err) {
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve usage stats", err)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockStatsService is a mock implementation of StatsServiceInterface
+type MockStatsService struct {
+	mock.Mock
+}
+
+func (m *MockStatsService) Report(ctx context.Context, filter domain.StatsFilter) (*domain.StatsReport, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.StatsReport), args.Error(1)
+}
+
+func TestStatsHandler_GetStats(t *testing.T) {
+	from := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC)
+	service := new(MockStatsService)
+	service.On("Report", mock.Anything, domain.StatsFilter{}).Return(&domain.StatsReport{
+		Days: []domain.DailyStats{{ActiveUsers: 12, Requests: 400}}, Totals: domain.StatsTotals{AverageActiveUsers: 12, Requests: 400},
+	}, nil).Once()
+	service.On("Report", mock.Anything, domain.StatsFilter{From: &from, To: &to}).Return(nil, &domain.ValidationError{
+		Fields: []domain.FieldError{{Field: "from", Message: "must not be after to"}},
+	})
+	service.On("Report", mock.Anything, domain.StatsFilter{}).Return(nil, errors.New("database is locked")).Once()
+	handler := NewStatsHandler(service)
+	router := setupGin()
+	router.GET("/admin/stats", handler.GetStats)
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+	}{
+		{"reports the last days", "", http.StatusOK},
+		{"rejects reversed ranges", "?from=2025-03-01&to=2025-02-01", http.StatusUnprocessableEntity},
+		{"rejects malformed dates", "?from=March", http.StatusBadRequest},
+		{"fails when the stats cannot be read", "", http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/stats"+tt.query, nil))
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusOK {
+				assert.Contains(t, w.Body.String(), `"average_active_users":12`)
+			}
+		})
+	}
+	service.AssertExpectations(t)
+}
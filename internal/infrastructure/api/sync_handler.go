@@ -0,0 +1,133 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SyncServiceInterface defines the interface for syncing offline clients
+type SyncServiceInterface interface {
+	Changes(ctx context.Context, userID, since uint, limit int) (*domain.SyncChanges, error)
+	Push(ctx context.Context, userID uint, mutations []domain.SyncMutation) (*domain.SyncPushResult, error)
+	ListConflicts(ctx context.Context, userID uint, limit int) ([]domain.SyncConflict, error)
+}
+
+type SyncHandler struct {
+	Service SyncServiceInterface
+}
+
+func NewSyncHandler(service SyncServiceInterface) *SyncHandler {
+	return &SyncHandler{Service: service}
+}
+
+// SyncMutationRequest is a change an offline client queued. Transaction
+// takes the fields of a created transaction; an update without a date
+// keeps the transaction's date.
+type SyncMutationRequest struct {
+	ClientID    string                    `json:"client_id"`
+	Operation   string                    `json:"operation"`
+	EntityID    uint                      `json:"entity_id"`
+	BaseVersion int64                     `json:"base_version"`
+	ModifiedAt  time.Time                 `json:"modified_at"`
+	Transaction *CreateTransactionRequest `json:"transaction"`
+}
+
+// SyncPushRequest is the body of sync pushes
+type SyncPushRequest struct {
+	Mutations []SyncMutationRequest `json:"mutations"`
+}
+
+// Changes returns what changed in the user's transactions after the since
+// cursor; clients without a cursor pass none and get everything
+func (h *SyncHandler) Changes(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+	var since uint64
+	if cursor := c.Query("since"); cursor != "" {
+		var err error
+		if since, err = strconv.ParseUint(cursor, 10, 32); err != nil {
+			respondError(c, middleware.CodeBadRequest, "since must be a cursor returned by an earlier sync")
+			return
+		}
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	changes, err := h.Service.Changes(c.Request.Context(), userID, uint(since), limit)
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve changes", err)
+		return
+	}
+	c.JSON(http.StatusOK, changes)
+}
+
+// Push applies the mutations an offline client queued, in order, and
+// returns the outcome of each
+func (h *SyncHandler) Push(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req SyncPushRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	mutations := make([]domain.SyncMutation, len(req.Mutations))
+	for i, m := range req.Mutations {
+		mutations[i] = domain.SyncMutation{
+			ClientID: m.ClientID, Operation: m.Operation, EntityID: m.EntityID,
+			BaseVersion: m.BaseVersion, ModifiedAt: m.ModifiedAt,
+		}
+		if m.Transaction == nil {
+			continue
+		}
+		transaction, invalid := m.Transaction.transaction(userID)
+		if invalid != "" {
+			respondError(c, middleware.CodeInvalidDate, fmt.Sprintf("mutations[%d]: %s", i, invalid))
+			return
+		}
+		if m.Transaction.Date == "" && m.Operation == domain.SyncMutationUpdate {
+			transaction.Date = time.Time{}
+		}
+		mutations[i].Transaction = transaction
+	}
+
+	result, err := h.Service.Push(c.Request.Context(), userID, mutations)
+	if respondValidationError(c, err) {
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to apply changes", err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// ListConflicts returns the conflicts pushes ran into, newest first, with
+// both sides of each
+func (h *SyncHandler) ListConflicts(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	conflicts, err := h.Service.ListConflicts(c.Request.Context(), userID, limit)
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve sync conflicts", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"conflicts": conflicts, "count": len(conflicts)})
+}
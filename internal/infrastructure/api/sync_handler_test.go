@@ -0,0 +1,97 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSyncService is a mock implementation of SyncServiceInterface
+type MockSyncService struct {
+	mock.Mock
+}
+
+func (m *MockSyncService) Changes(ctx context.Context, userID, since uint, limit int) (*domain.SyncChanges, error) {
+	args := m.Called(ctx, userID, since, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SyncChanges), args.Error(1)
+}
+
+func (m *MockSyncService) Push(ctx context.Context, userID uint, mutations []domain.SyncMutation) (*domain.SyncPushResult, error) {
+	args := m.Called(ctx, userID, mutations)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SyncPushResult), args.Error(1)
+}
+
+func (m *MockSyncService) ListConflicts(ctx context.Context, userID uint, limit int) ([]domain.SyncConflict, error) {
+	args := m.Called(ctx, userID, limit)
+	return args.Get(0).([]domain.SyncConflict), args.Error(1)
+}
+
+func TestSyncHandler(t *testing.T) {
+	mockService := new(MockSyncService)
+	handler := NewSyncHandler(mockService)
+	router := setupGin()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Next()
+	})
+	router.GET("/users/:userId/sync/changes", handler.Changes)
+	router.POST("/users/:userId/sync/push", handler.Push)
+	router.GET("/users/:userId/sync/conflicts", handler.ListConflicts)
+
+	mockService.On("Changes", mock.Anything, uint(1), uint(0), 0).Return(&domain.SyncChanges{}, nil)
+	mockService.On("Changes", mock.Anything, uint(1), uint(42), 10).Return(&domain.SyncChanges{Cursor: 50}, nil)
+	mockService.On("Push", mock.Anything, uint(1), mock.MatchedBy(func(mutations []domain.SyncMutation) bool {
+		return len(mutations) == 2 &&
+			mutations[0].Transaction != nil && mutations[0].Transaction.UserID == 1 && !mutations[0].Transaction.Date.IsZero() &&
+			mutations[1].Transaction != nil && mutations[1].Transaction.Date.IsZero() && mutations[1].BaseVersion == 3 &&
+			mutations[1].ModifiedAt.Equal(time.Date(2024, time.May, 2, 8, 0, 0, 0, time.UTC))
+	})).Return(&domain.SyncPushResult{}, nil)
+	mockService.On("Push", mock.Anything, uint(1), mock.MatchedBy(func(mutations []domain.SyncMutation) bool {
+		return len(mutations) == 0
+	})).Return(nil, &domain.ValidationError{Fields: []domain.FieldError{{Field: "mutations", Message: "must list at most 100 mutations"}}})
+	mockService.On("ListConflicts", mock.Anything, uint(1), 0).Return([]domain.SyncConflict{{ID: 1}}, nil)
+
+	push := `{"mutations":[` +
+		`{"client_id":"a","operation":"create","transaction":{"amount":4.5,"type":"expense","description":"Bakery","date":"2024-05-01"}},` +
+		`{"operation":"update","entity_id":7,"base_version":3,"modified_at":"2024-05-02T08:00:00Z",` +
+		`"transaction":{"amount":5,"type":"expense","description":"Bakery"}}]}`
+	tests := []struct {
+		method     string
+		url        string
+		body       string
+		wantStatus int
+	}{
+		{http.MethodGet, "/users/1/sync/changes", "", http.StatusOK},
+		{http.MethodGet, "/users/1/sync/changes?since=42&limit=10", "", http.StatusOK},
+		{http.MethodGet, "/users/1/sync/changes?since=latest", "", http.StatusBadRequest},
+		{http.MethodGet, "/users/2/sync/changes", "", http.StatusForbidden},
+		{http.MethodPost, "/users/1/sync/push", push, http.StatusOK},
+		{http.MethodPost, "/users/1/sync/push", `{"mutations":[]}`, http.StatusUnprocessableEntity},
+		{http.MethodPost, "/users/1/sync/push", `{"mutations":[{"operation":"create","transaction":{"date":"May 1"}}]}`, http.StatusBadRequest},
+		{http.MethodPost, "/users/1/sync/push", `{"mutations":`, http.StatusBadRequest},
+		{http.MethodGet, "/users/1/sync/conflicts", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		assert.Equal(t, tt.wantStatus, w.Code, tt.url+" "+tt.body)
+	}
+	mockService.AssertExpectations(t)
+}
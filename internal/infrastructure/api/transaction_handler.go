@@ -2,7 +2,9 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -10,23 +12,32 @@ import (
 
 	"go-finance-advisor/internal/application"
 	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
 
 	"github.com/gin-gonic/gin"
 )
 
 // TransactionServiceInterface defines the interface for transaction service
 type TransactionServiceInterface interface {
-	Create(transaction *domain.Transaction) error
-	GetByID(id uint) (*domain.Transaction, error)
+	Create(ctx context.Context, transaction *domain.Transaction) error
+	GetByID(ctx context.Context, id uint) (*domain.Transaction, error)
 	ListWithFilters(
+		ctx context.Context,
 		userID uint,
 		transactionType *string,
 		categoryID *uint,
 		startDate, endDate *time.Time,
 		limit, offset int,
 	) ([]domain.Transaction, error)
-	Update(transaction *domain.Transaction) error
-	Delete(id uint) error
+	ListPageIncluding(
+		ctx context.Context, filter domain.TransactionFilter, include domain.TransactionIncludes,
+	) (*domain.TransactionPage, error)
+	Update(ctx context.Context, transaction *domain.Transaction) error
+	Delete(ctx context.Context, id uint) error
+	ListDeleted(ctx context.Context, userID uint) ([]domain.Transaction, error)
+	Restore(ctx context.Context, userID, id uint) (*domain.Transaction, error)
+	Purge(ctx context.Context, userID, id uint) error
+	EmptyTrash(ctx context.Context, userID uint) (int64, error)
 }
 
 type TransactionHandler struct {
@@ -37,62 +48,112 @@ func NewTransactionHandler(service *application.TransactionService) *Transaction
 	return &TransactionHandler{Service: service}
 }
 
+// CreateTransactionRequest is the body of transaction requests. The business
+// rules are checked by the service, which rejects broken ones with 422.
+// Updates without tags or items keep the transaction's current ones. Amounts in
+// another currency than the base one are converted at the rate of the date.
+// Mobile clients can send where the transaction was made. Transfers move the
+// amount from account_id to transfer_account_id.
 type CreateTransactionRequest struct {
-	Amount      float64 `json:"amount" binding:"required,gt=0"`
-	Type        string  `json:"type" binding:"required,oneof=income expense"`
-	Description string  `json:"description" binding:"required,min=1,max=255"`
-	CategoryID  uint    `json:"category_id" binding:"required"`
-	Date        string  `json:"date,omitempty"`
+	Amount      domain.Money             `json:"amount"`
+	Currency    string                   `json:"currency,omitempty"`
+	Type        string                   `json:"type"`
+	Description string                   `json:"description"`
+	Notes       string                   `json:"notes,omitempty"`
+	Tags        []string                 `json:"tags,omitempty"`
+	Items       []domain.TransactionItem `json:"items,omitempty"`
+	CategoryID  uint                     `json:"category_id"`
+	AccountID   *uint                    `json:"account_id,omitempty"`
+	Date        string                   `json:"date,omitempty"`
+	Latitude    *float64                 `json:"latitude,omitempty"`
+	Longitude   *float64                 `json:"longitude,omitempty"`
+	Place       string                   `json:"place,omitempty"`
+	City        string                   `json:"city,omitempty"`
+	// TransferAccountID is the account a transfer credits
+	TransferAccountID *uint `json:"transfer_account_id,omitempty"`
+}
+
+// transaction builds the requested transaction for the user, or returns the
+// message to reject the request with. Transactions without a date are dated today.
+func (req CreateTransactionRequest) transaction(userID uint) (*domain.Transaction, string) {
+	transactionDate := time.Now()
+	if req.Date != "" {
+		var err error
+		transactionDate, err = time.Parse("2006-01-02", req.Date)
+		if err != nil {
+			return nil, "Invalid date format. Use YYYY-MM-DD"
+		}
+	}
+
+	return &domain.Transaction{
+		UserID:      userID,
+		Amount:      req.Amount,
+		Currency:    req.Currency,
+		Type:        req.Type,
+		Description: req.Description,
+		Notes:       req.Notes,
+		Tags:        req.Tags,
+		Items:       req.Items,
+		CategoryID:  req.CategoryID,
+		AccountID:   req.AccountID,
+		Date:        transactionDate,
+		Latitude:    req.Latitude,
+		Longitude:   req.Longitude,
+		Place:       req.Place,
+		City:        req.City,
+
+		TransferAccountID: req.TransferAccountID,
+	}, ""
 }
 
 func (h *TransactionHandler) Create(c *gin.Context) {
 	userIDStr := c.Param("userId")
 	userID, err := strconv.ParseUint(userIDStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		respondError(c, middleware.CodeInvalidID, "Invalid user ID")
 		return
 	}
 
 	var req CreateTransactionRequest
 	if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": bindErr.Error()})
+		respondError(c, middleware.CodeInvalidBody, bindErr.Error())
 		return
 	}
 
-	// Parse date or use current date
-	var transactionDate time.Time
-	if req.Date != "" {
-		transactionDate, err = time.Parse("2006-01-02", req.Date)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format. Use YYYY-MM-DD"})
-			return
-		}
-	} else {
-		transactionDate = time.Now()
-	}
-
-	transaction := &domain.Transaction{
-		UserID:      uint(userID),
-		Amount:      req.Amount,
-		Type:        req.Type,
-		Description: req.Description,
-		CategoryID:  req.CategoryID,
-		Date:        transactionDate,
+	transaction, invalid := req.transaction(uint(userID))
+	if invalid != "" {
+		respondError(c, middleware.CodeInvalidDate, invalid)
+		return
 	}
 
-	if err := h.Service.Create(transaction); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create transaction"})
+	if err := h.Service.Create(c.Request.Context(), transaction); err != nil {
+		switch {
+		case respondValidationError(c, err):
+		case errors.Is(err, domain.ErrCategoryCapExceeded):
+			respondError(c, middleware.CodeUnprocessable, err.Error())
+		case errors.Is(err, domain.ErrExchangeRateUnavailable):
+			respondExchangeRateUnavailable(c, err)
+		default:
+			respondInternalError(c, "Failed to create transaction", err)
+		}
 		return
 	}
 
 	c.JSON(http.StatusCreated, transaction)
 }
 
+// List returns a page of the user's transactions with the total count and
+// next/prev cursors. Passing one of those cursors as "cursor" switches to
+// keyset pagination, which stays fast however deep the client pages.
+// "include" lists per-row details to embed: attachments (their count), tags,
+// items and running_balance. account_id matches both sides of transfers,
+// transfer_account_id only the transfers into the account, and
+// exclude_transfers=true leaves transfers out.
 func (h *TransactionHandler) List(c *gin.Context) {
 	userIDStr := c.Param("userId")
 	userID, err := strconv.ParseUint(userIDStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		respondError(c, middleware.CodeInvalidID, "Invalid user ID")
 		return
 	}
 
@@ -101,6 +162,12 @@ func (h *TransactionHandler) List(c *gin.Context) {
 	categoryIDStr := c.Query("category_id")
 	startDateStr := c.Query("start_date")
 	endDateStr := c.Query("end_date")
+	cursorStr := c.Query("cursor")
+	include, err := domain.ParseTransactionIncludes(c.Query("include"))
+	if err != nil {
+		respondError(c, middleware.CodeBadRequest, err.Error())
+		return
+	}
 	limitStr := c.DefaultQuery("limit", "100")
 	offsetStr := c.DefaultQuery("offset", "0")
 
@@ -114,96 +181,161 @@ func (h *TransactionHandler) List(c *gin.Context) {
 		offset = 0
 	}
 
-	var transactionType *string
-	if transactionTypeStr != "" {
-		transactionType = &transactionTypeStr
+	filter := domain.TransactionFilter{
+		UserID: uint(userID),
+		Type:   transactionTypeStr,
+		Limit:  limit,
+		Offset: offset,
 	}
 
-	var categoryID *uint
 	if categoryIDStr != "" {
 		catID, parseErr := strconv.ParseUint(categoryIDStr, 10, 32)
 		if parseErr != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category ID"})
+			respondError(c, middleware.CodeInvalidID, "Invalid category ID")
 			return
 		}
 		catIDUint := uint(catID)
-		categoryID = &catIDUint
+		filter.CategoryID = &catIDUint
+	}
+
+	if !parseTransferFilter(c, &filter) {
+		return
 	}
 
-	var startDate, endDate *time.Time
 	if startDateStr != "" {
 		start, parseErr := time.Parse("2006-01-02", startDateStr)
 		if parseErr != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start date format. Use YYYY-MM-DD"})
+			respondError(c, middleware.CodeInvalidDate, "Invalid start date format. Use YYYY-MM-DD")
 			return
 		}
-		startDate = &start
+		filter.StartDate = &start
 	}
 
 	if endDateStr != "" {
 		end, parseErr := time.Parse("2006-01-02", endDateStr)
 		if parseErr != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end date format. Use YYYY-MM-DD"})
+			respondError(c, middleware.CodeInvalidDate, "Invalid end date format. Use YYYY-MM-DD")
+			return
+		}
+		filter.EndDate = &end
+	}
+
+	if cursorStr != "" {
+		cursor, parseErr := domain.DecodeTransactionCursor(cursorStr)
+		if parseErr != nil {
+			respondError(c, middleware.CodeBadRequest, "Invalid cursor")
 			return
 		}
-		endDate = &end
+		filter.Cursor = cursor
 	}
 
-	transactions, err := h.Service.ListWithFilters(uint(userID), transactionType, categoryID, startDate, endDate, limit, offset)
+	page, err := h.Service.ListPageIncluding(c.Request.Context(), filter, include)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve transactions"})
+		respondInternalError(c, "Failed to retrieve transactions", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, transactions)
+	c.JSON(http.StatusOK, page)
 }
 
-// GetByID retrieves a transaction by ID
-func (h *TransactionHandler) GetByID(c *gin.Context) {
-	idStr := c.Param("id")
-	id, err := strconv.ParseUint(idStr, 10, 32)
+// parseTransferFilter reads the account and transfer parameters of a
+// transaction list into the filter, responding with 400 to invalid ones
+func parseTransferFilter(c *gin.Context, filter *domain.TransactionFilter) bool {
+	for _, param := range []struct {
+		name   string
+		target **uint
+	}{
+		{"account_id", &filter.AccountID},
+		{"transfer_account_id", &filter.TransferAccountID},
+		{"scheduled_transfer_id", &filter.ScheduledTransferID},
+	} {
+		id, err := parseOptionalID(c.Query(param.name))
+		if err != nil {
+			respondError(c, middleware.CodeInvalidID, "Invalid "+param.name)
+			return false
+		}
+		if id != 0 {
+			*param.target = &id
+		}
+	}
+	if exclude := c.Query("exclude_transfers"); exclude != "" {
+		excluded, err := strconv.ParseBool(exclude)
+		if err != nil {
+			respondError(c, middleware.CodeBadRequest, "exclude_transfers must be true or false")
+			return false
+		}
+		filter.ExcludeTransfers = excluded
+	}
+	return true
+}
+
+// parseTransactionIDs reads the userId and id parameters. Users can only
+// reach their own transactions.
+func parseTransactionIDs(c *gin.Context) (userID, id uint, ok bool) {
+	userID, ok = authorizedUserID(c)
+	if !ok {
+		return 0, 0, false
+	}
+	parsedID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transaction ID"})
+		respondError(c, middleware.CodeInvalidID, "Invalid transaction ID")
+		return 0, 0, false
+	}
+	return userID, uint(parsedID), true
+}
+
+// ownTransaction loads one of the user's transactions. Other users'
+// transactions are reported as not found, so their IDs reveal nothing.
+func (h *TransactionHandler) ownTransaction(c *gin.Context, userID, id uint) (*domain.Transaction, bool) {
+	transaction, err := h.Service.GetByID(c.Request.Context(), id)
+	if err != nil || transaction.UserID != userID {
+		respondError(c, middleware.CodeNotFound, "Transaction not found")
+		return nil, false
+	}
+	return transaction, true
+}
+
+// GetByID retrieves one of the user's transactions
+func (h *TransactionHandler) GetByID(c *gin.Context) {
+	userID, id, ok := parseTransactionIDs(c)
+	if !ok {
 		return
 	}
 
-	transaction, err := h.Service.GetByID(uint(id))
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Transaction not found"})
+	transaction, ok := h.ownTransaction(c, userID, id)
+	if !ok {
 		return
 	}
 
 	c.JSON(http.StatusOK, transaction)
 }
 
-// Update updates an existing transaction
+// Update updates one of the user's transactions
 func (h *TransactionHandler) Update(c *gin.Context) {
-	idStr := c.Param("id")
-	id, err := strconv.ParseUint(idStr, 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transaction ID"})
+	userID, id, ok := parseTransactionIDs(c)
+	if !ok {
 		return
 	}
 
 	// Get existing transaction
-	existingTransaction, err := h.Service.GetByID(uint(id))
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Transaction not found"})
+	existingTransaction, ok := h.ownTransaction(c, userID, id)
+	if !ok {
 		return
 	}
 
 	var req CreateTransactionRequest
 	if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": bindErr.Error()})
+		respondError(c, middleware.CodeInvalidBody, bindErr.Error())
 		return
 	}
 
 	// Parse date if provided
 	var transactionDate time.Time
 	if req.Date != "" {
+		var err error
 		transactionDate, err = time.Parse("2006-01-02", req.Date)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format. Use YYYY-MM-DD"})
+			respondError(c, middleware.CodeInvalidDate, "Invalid date format. Use YYYY-MM-DD")
 			return
 		}
 	} else {
@@ -212,45 +344,135 @@ func (h *TransactionHandler) Update(c *gin.Context) {
 
 	// Update transaction fields
 	existingTransaction.Amount = req.Amount
+	existingTransaction.Currency = req.Currency
+	existingTransaction.OriginalAmount = nil
 	existingTransaction.Type = req.Type
 	existingTransaction.Description = req.Description
+	existingTransaction.Notes = req.Notes
+	existingTransaction.Tags = req.Tags
+	existingTransaction.Items = req.Items
 	existingTransaction.CategoryID = req.CategoryID
+	existingTransaction.AccountID = req.AccountID
+	existingTransaction.TransferAccountID = req.TransferAccountID
 	existingTransaction.Date = transactionDate
-
-	if err := h.Service.Update(existingTransaction); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update transaction"})
+	existingTransaction.Latitude = req.Latitude
+	existingTransaction.Longitude = req.Longitude
+	existingTransaction.Place = req.Place
+	existingTransaction.City = req.City
+
+	if err := h.Service.Update(c.Request.Context(), existingTransaction); err != nil {
+		switch {
+		case respondValidationError(c, err):
+		case errors.Is(err, domain.ErrExchangeRateUnavailable):
+			respondExchangeRateUnavailable(c, err)
+		default:
+			respondInternalError(c, "Failed to update transaction", err)
+		}
 		return
 	}
 
 	c.JSON(http.StatusOK, existingTransaction)
 }
 
-// Delete deletes a transaction
+// Delete moves one of the user's transactions to the trash
 func (h *TransactionHandler) Delete(c *gin.Context) {
-	idStr := c.Param("id")
-	id, err := strconv.ParseUint(idStr, 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transaction ID"})
+	userID, id, ok := parseTransactionIDs(c)
+	if !ok {
 		return
 	}
 
-	if err := h.Service.Delete(uint(id)); err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Transaction not found"})
+	if _, ok := h.ownTransaction(c, userID, id); !ok {
+		return
+	}
+	if err := h.Service.Delete(c.Request.Context(), id); err != nil {
+		respondError(c, middleware.CodeNotFound, "Transaction not found")
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Transaction deleted successfully"})
 }
 
+// ListTrash returns the user's deleted transactions
+func (h *TransactionHandler) ListTrash(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	transactions, err := h.Service.ListDeleted(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve deleted transactions", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, transactions)
+}
+
+// Restore takes a transaction out of the user's trash
+func (h *TransactionHandler) Restore(c *gin.Context) {
+	userID, id, ok := parseTransactionIDs(c)
+	if !ok {
+		return
+	}
+
+	transaction, err := h.Service.Restore(c.Request.Context(), userID, id)
+	if err != nil {
+		respondTrashError(c, err, "Failed to restore transaction")
+		return
+	}
+
+	c.JSON(http.StatusOK, transaction)
+}
+
+// Purge permanently deletes one transaction from the user's trash
+func (h *TransactionHandler) Purge(c *gin.Context) {
+	userID, id, ok := parseTransactionIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Service.Purge(c.Request.Context(), userID, id); err != nil {
+		respondTrashError(c, err, "Failed to purge transaction")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Transaction permanently deleted"})
+}
+
+// EmptyTrash permanently deletes every transaction in the user's trash
+func (h *TransactionHandler) EmptyTrash(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	purged, err := h.Service.EmptyTrash(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, "Failed to empty trash", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Trash emptied", "purged": purged})
+}
+
+func respondTrashError(c *gin.Context, err error, message string) {
+	if errors.Is(err, domain.ErrNotFound) {
+		respondError(c, middleware.CodeNotFound, "Transaction not found in trash")
+		return
+	}
+	respondInternalError(c, message, err)
+}
+
 // ExportCSV exports transactions as CSV
 func (h *TransactionHandler) ExportCSV(c *gin.Context) {
 	filters, err := h.parseExportFilters(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, middleware.CodeBadRequest, err.Error())
 		return
 	}
 
 	transactions, err := h.Service.ListWithFilters(
+		c.Request.Context(),
 		filters.UserID,
 		filters.TransactionType,
 		filters.CategoryID,
@@ -260,13 +482,13 @@ func (h *TransactionHandler) ExportCSV(c *gin.Context) {
 		filters.Offset,
 	)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve transactions"})
+		respondInternalError(c, "Failed to retrieve transactions", err)
 		return
 	}
 
 	buf, err := h.generateCSVContent(transactions)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate CSV"})
+		respondInternalError(c, "Failed to generate CSV", err)
 		return
 	}
 
@@ -362,7 +584,7 @@ func (h *TransactionHandler) generateCSVContent(transactions []domain.Transactio
 		transaction := &transactions[i]
 		record := []string{
 			fmt.Sprintf("%d", transaction.ID),
-			fmt.Sprintf("%.2f", transaction.Amount),
+			transaction.Amount.String(),
 			transaction.Type,
 			transaction.Description,
 			fmt.Sprintf("%d", transaction.CategoryID),
@@ -417,7 +639,7 @@ func (h *TransactionHandler) generateHTMLContent(transactions []domain.Transacti
 		htmlContent.WriteString(fmt.Sprintf(`
         <tr>
             <td>%d</td>
-            <td>%.2f</td>
+            <td>%s</td>
             <td>%s</td>
             <td>%s</td>
             <td>%d</td>
@@ -442,11 +664,12 @@ func (h *TransactionHandler) generateHTMLContent(transactions []domain.Transacti
 func (h *TransactionHandler) ExportPDF(c *gin.Context) {
 	filters, err := h.parseExportFilters(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, middleware.CodeBadRequest, err.Error())
 		return
 	}
 
 	transactions, err := h.Service.ListWithFilters(
+		c.Request.Context(),
 		filters.UserID,
 		filters.TransactionType,
 		filters.CategoryID,
@@ -456,7 +679,7 @@ func (h *TransactionHandler) ExportPDF(c *gin.Context) {
 		filters.Offset,
 	)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve transactions"})
+		respondInternalError(c, "Failed to retrieve transactions", err)
 		return
 	}
 
@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -11,9 +12,12 @@ import (
 
 	"go-finance-advisor/internal/application"
 	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
 
+	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockTransactionService is a mock implementation of TransactionService
@@ -21,13 +25,13 @@ type MockTransactionService struct {
 	mock.Mock
 }
 
-func (m *MockTransactionService) Create(transaction *domain.Transaction) error {
-	args := m.Called(transaction)
+func (m *MockTransactionService) Create(ctx context.Context, transaction *domain.Transaction) error {
+	args := m.Called(ctx, transaction)
 	return args.Error(0)
 }
 
-func (m *MockTransactionService) GetByID(id uint) (*domain.Transaction, error) {
-	args := m.Called(id)
+func (m *MockTransactionService) GetByID(ctx context.Context, id uint) (*domain.Transaction, error) {
+	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -35,26 +39,62 @@ func (m *MockTransactionService) GetByID(id uint) (*domain.Transaction, error) {
 }
 
 func (m *MockTransactionService) ListWithFilters(
-	userID uint, transactionType *string, categoryID *uint,
+	ctx context.Context, userID uint, transactionType *string, categoryID *uint,
 	startDate, endDate *time.Time, limit, offset int,
 ) ([]domain.Transaction, error) {
-	args := m.Called(userID, transactionType, categoryID, startDate, endDate, limit, offset)
+	args := m.Called(ctx, userID, transactionType, categoryID, startDate, endDate, limit, offset)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]domain.Transaction), args.Error(1)
 }
 
-func (m *MockTransactionService) Update(transaction *domain.Transaction) error {
-	args := m.Called(transaction)
+func (m *MockTransactionService) ListPageIncluding(
+	ctx context.Context, filter domain.TransactionFilter, include domain.TransactionIncludes,
+) (*domain.TransactionPage, error) {
+	args := m.Called(ctx, filter, include)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.TransactionPage), args.Error(1)
+}
+
+func (m *MockTransactionService) Update(ctx context.Context, transaction *domain.Transaction) error {
+	args := m.Called(ctx, transaction)
 	return args.Error(0)
 }
 
-func (m *MockTransactionService) Delete(id uint) error {
-	args := m.Called(id)
+func (m *MockTransactionService) Delete(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
+func (m *MockTransactionService) ListDeleted(ctx context.Context, userID uint) ([]domain.Transaction, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionService) Restore(ctx context.Context, userID, id uint) (*domain.Transaction, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionService) Purge(ctx context.Context, userID, id uint) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func (m *MockTransactionService) EmptyTrash(ctx context.Context, userID uint) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *MockTransactionService) GetTransactionsByDateRange(userID uint, startDate, endDate time.Time) ([]domain.Transaction, error) {
 	args := m.Called(userID, startDate, endDate)
 	return args.Get(0).([]domain.Transaction), args.Error(1)
@@ -93,15 +133,15 @@ func TestTransactionHandler_Create(t *testing.T) {
 		router.POST("/users/:userId/transactions", handler.Create)
 
 		createReq := CreateTransactionRequest{
-			Amount:      100.50,
+			Amount:      domain.NewMoney(100.50),
 			Type:        "expense",
 			Description: "Grocery shopping",
 			CategoryID:  1,
 			Date:        "2024-01-15",
 		}
 
-		mockService.On("Create", mock.MatchedBy(func(t *domain.Transaction) bool {
-			return t.Amount == 100.50 && t.Type == "expense" && t.UserID == 1
+		mockService.On("Create", mock.Anything, mock.MatchedBy(func(t *domain.Transaction) bool {
+			return t.Amount == domain.NewMoney(100.50) && t.Type == "expense" && t.UserID == 1
 		})).Return(nil)
 
 		requestBody, _ := json.Marshal(createReq)
@@ -115,20 +155,60 @@ func TestTransactionHandler_Create(t *testing.T) {
 		mockService.AssertExpectations(t)
 	})
 
+	t.Run("should flag caps the transaction went over", func(t *testing.T) {
+		handler, mockService := setupTransactionHandler()
+		router := setupGin()
+		router.POST("/users/:userId/transactions", handler.Create)
+
+		mockService.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Transaction).CapWarnings = []domain.CategoryCapStatus{
+				{CategoryID: 1, Cap: domain.NewMoney(100), Spent: domain.NewMoney(120), Exceeded: true},
+			}
+		}).Return(nil)
+
+		body := `{"amount":50,"type":"expense","description":"Dinner","category_id":1}`
+		req := httptest.NewRequest("POST", "/users/1/transactions", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var response domain.Transaction
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.CapWarnings, 1)
+		assert.True(t, response.CapWarnings[0].Exceeded)
+	})
+
+	t.Run("should refuse transactions over blocking caps", func(t *testing.T) {
+		handler, mockService := setupTransactionHandler()
+		router := setupGin()
+		router.POST("/users/:userId/transactions", handler.Create)
+
+		mockService.On("Create", mock.Anything, mock.Anything).Return(domain.ErrCategoryCapExceeded)
+
+		body := `{"amount":50,"type":"expense","description":"Dinner","category_id":1}`
+		req := httptest.NewRequest("POST", "/users/1/transactions", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+
 	t.Run("should use current date when date not provided", func(t *testing.T) {
 		handler, mockService := setupTransactionHandler()
 		router := setupGin()
 		router.POST("/users/:userId/transactions", handler.Create)
 
 		createReq := CreateTransactionRequest{
-			Amount:      50.00,
+			Amount:      domain.NewMoney(50.00),
 			Type:        "income",
 			Description: "Freelance work",
 			CategoryID:  2,
 			// Date not provided
 		}
 
-		mockService.On("Create", mock.MatchedBy(func(t *domain.Transaction) bool {
+		mockService.On("Create", mock.Anything, mock.MatchedBy(func(t *domain.Transaction) bool {
 			// Check that date is set to today
 			now := time.Now()
 			return t.Date.Year() == now.Year() && t.Date.Month() == now.Month() && t.Date.Day() == now.Day()
@@ -151,7 +231,7 @@ func TestTransactionHandler_Create(t *testing.T) {
 		router.POST("/users/:userId/transactions", handler.Create)
 
 		createReq := CreateTransactionRequest{
-			Amount:      100.50,
+			Amount:      domain.NewMoney(100.50),
 			Type:        "expense",
 			Description: "Test transaction",
 			CategoryID:  1,
@@ -167,7 +247,7 @@ func TestTransactionHandler_Create(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 		var response map[string]interface{}
 		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Equal(t, "invalid user ID", response["error"])
+		assert.Equal(t, "Invalid user ID", response["error"])
 	})
 
 	t.Run("should return bad request for invalid JSON", func(t *testing.T) {
@@ -184,39 +264,24 @@ func TestTransactionHandler_Create(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
 
-	t.Run("should return bad request for invalid amount", func(t *testing.T) {
-		handler, _ := setupTransactionHandler()
+	t.Run("should return unprocessable entity for invalid amount and type", func(t *testing.T) {
+		handler, mockService := setupTransactionHandler()
 		router := setupGin()
 		router.POST("/users/:userId/transactions", handler.Create)
 
 		createReq := CreateTransactionRequest{
-			Amount:      -10.00, // Invalid negative amount
-			Type:        "expense",
+			Amount:      domain.NewMoney(-10.00), // Invalid negative amount
+			Type:        "invalid",               // Invalid type
 			Description: "Test transaction",
 			CategoryID:  1,
 		}
 
-		requestBody, _ := json.Marshal(createReq)
-		req := httptest.NewRequest("POST", "/users/1/transactions", bytes.NewBuffer(requestBody))
-		req.Header.Set("Content-Type", "application/json")
-		w := httptest.NewRecorder()
-
-		router.ServeHTTP(w, req)
-
-		assert.Equal(t, http.StatusBadRequest, w.Code)
-	})
-
-	t.Run("should return bad request for invalid transaction type", func(t *testing.T) {
-		handler, _ := setupTransactionHandler()
-		router := setupGin()
-		router.POST("/users/:userId/transactions", handler.Create)
-
-		createReq := CreateTransactionRequest{
-			Amount:      100.50,
-			Type:        "invalid", // Invalid type
-			Description: "Test transaction",
-			CategoryID:  1,
-		}
+		// The service rejects what the domain rules reject
+		invalid := (&domain.Transaction{
+			Amount: createReq.Amount, Type: createReq.Type, Description: createReq.Description,
+			CategoryID: createReq.CategoryID, Date: time.Now(),
+		}).Validate()
+		mockService.On("Create", mock.Anything, mock.AnythingOfType("*domain.Transaction")).Return(invalid)
 
 		requestBody, _ := json.Marshal(createReq)
 		req := httptest.NewRequest("POST", "/users/1/transactions", bytes.NewBuffer(requestBody))
@@ -225,7 +290,15 @@ func TestTransactionHandler_Create(t *testing.T) {
 
 		router.ServeHTTP(w, req)
 
-		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		var response middleware.ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, middleware.CodeValidation, response.Code)
+		assert.Equal(t, "Validation failed", response.Error)
+		assert.Equal(t, []domain.FieldError{
+			{Field: "amount", Message: "must be greater than zero"},
+			{Field: "type", Message: "must be income, expense or transfer"},
+		}, response.Fields)
 	})
 
 	t.Run("should return bad request for invalid date format", func(t *testing.T) {
@@ -234,7 +307,7 @@ func TestTransactionHandler_Create(t *testing.T) {
 		router.POST("/users/:userId/transactions", handler.Create)
 
 		createReq := CreateTransactionRequest{
-			Amount:      100.50,
+			Amount:      domain.NewMoney(100.50),
 			Type:        "expense",
 			Description: "Test transaction",
 			CategoryID:  1,
@@ -260,13 +333,13 @@ func TestTransactionHandler_Create(t *testing.T) {
 		router.POST("/users/:userId/transactions", handler.Create)
 
 		createReq := CreateTransactionRequest{
-			Amount:      100.50,
+			Amount:      domain.NewMoney(100.50),
 			Type:        "expense",
 			Description: "Test transaction",
 			CategoryID:  1,
 		}
 
-		mockService.On("Create", mock.AnythingOfType("*domain.Transaction")).Return(errors.New("database error"))
+		mockService.On("Create", mock.Anything, mock.AnythingOfType("*domain.Transaction")).Return(errors.New("database error"))
 
 		requestBody, _ := json.Marshal(createReq)
 		req := httptest.NewRequest("POST", "/users/1/transactions", bytes.NewBuffer(requestBody))
@@ -293,7 +366,7 @@ func TestTransactionHandler_List(t *testing.T) {
 			{
 				ID:          1,
 				UserID:      1,
-				Amount:      100.50,
+				Amount:      domain.NewMoney(100.50),
 				Type:        "expense",
 				Description: "Grocery shopping",
 				CategoryID:  1,
@@ -302,7 +375,7 @@ func TestTransactionHandler_List(t *testing.T) {
 			{
 				ID:          2,
 				UserID:      1,
-				Amount:      2000.00,
+				Amount:      domain.NewMoney(2000.00),
 				Type:        "income",
 				Description: "Salary",
 				CategoryID:  2,
@@ -310,9 +383,8 @@ func TestTransactionHandler_List(t *testing.T) {
 			},
 		}
 
-		mockService.On("ListWithFilters", uint(1), (*string)(nil), (*uint)(nil),
-			(*time.Time)(nil), (*time.Time)(nil), 100, 0).
-			Return(expectedTransactions, nil)
+		mockService.On("ListPageIncluding", mock.Anything, domain.TransactionFilter{UserID: 1, Limit: 100}, domain.TransactionIncludes{}).
+			Return(&domain.TransactionPage{Transactions: expectedTransactions, Total: 2, Limit: 100}, nil)
 
 		req := httptest.NewRequest("GET", "/users/1/transactions", http.NoBody)
 		w := httptest.NewRecorder()
@@ -320,11 +392,12 @@ func TestTransactionHandler_List(t *testing.T) {
 		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusOK, w.Code)
-		var response []domain.Transaction
+		var response domain.TransactionPage
 		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Len(t, response, 2)
-		assert.Equal(t, expectedTransactions[0].ID, response[0].ID)
-		assert.Equal(t, expectedTransactions[1].ID, response[1].ID)
+		assert.Len(t, response.Transactions, 2)
+		assert.Equal(t, int64(2), response.Total)
+		assert.Equal(t, expectedTransactions[0].ID, response.Transactions[0].ID)
+		assert.Equal(t, expectedTransactions[1].ID, response.Transactions[1].ID)
 		mockService.AssertExpectations(t)
 	})
 
@@ -337,7 +410,7 @@ func TestTransactionHandler_List(t *testing.T) {
 			{
 				ID:          1,
 				UserID:      1,
-				Amount:      100.50,
+				Amount:      domain.NewMoney(100.50),
 				Type:        "expense",
 				Description: "Grocery shopping",
 				CategoryID:  1,
@@ -350,7 +423,15 @@ func TestTransactionHandler_List(t *testing.T) {
 		startDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
 		endDate := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
 
-		mockService.On("ListWithFilters", uint(1), &expenseType, &categoryID, &startDate, &endDate, 50, 10).Return(expectedTransactions, nil)
+		mockService.On("ListPageIncluding", mock.Anything, domain.TransactionFilter{
+			UserID:     1,
+			Type:       expenseType,
+			CategoryID: &categoryID,
+			StartDate:  &startDate,
+			EndDate:    &endDate,
+			Limit:      50,
+			Offset:     10,
+		}, domain.TransactionIncludes{}).Return(&domain.TransactionPage{Transactions: expectedTransactions, Total: 11, Limit: 50, Offset: 10}, nil)
 
 		req := httptest.NewRequest("GET",
 			"/users/1/transactions?type=expense&category_id=1&start_date=2024-01-01&end_date=2024-01-31&limit=50&offset=10",
@@ -360,13 +441,119 @@ func TestTransactionHandler_List(t *testing.T) {
 		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusOK, w.Code)
-		var response []domain.Transaction
+		var response domain.TransactionPage
 		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Len(t, response, 1)
-		assert.Equal(t, expectedTransactions[0].ID, response[0].ID)
+		assert.Len(t, response.Transactions, 1)
+		assert.Equal(t, expectedTransactions[0].ID, response.Transactions[0].ID)
+		assert.Equal(t, 10, response.Offset)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should page by cursor", func(t *testing.T) {
+		handler, mockService := setupTransactionHandler()
+		router := setupGin()
+		router.GET("/users/:userId/transactions", handler.List)
+
+		cursor := domain.TransactionCursor{Date: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), ID: 7}
+		mockService.On("ListPageIncluding", mock.Anything, mock.MatchedBy(func(filter domain.TransactionFilter) bool {
+			return filter.Cursor != nil && filter.Cursor.ID == 7 && filter.Cursor.Date.Equal(cursor.Date) && !filter.Cursor.Before
+		}), domain.TransactionIncludes{}).Return(&domain.TransactionPage{Transactions: []domain.Transaction{}, Total: 7, Limit: 20}, nil)
+
+		req := httptest.NewRequest("GET", "/users/1/transactions?limit=20&cursor="+cursor.Encode(), http.NoBody)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
 		mockService.AssertExpectations(t)
 	})
 
+	t.Run("should return bad request for invalid cursor", func(t *testing.T) {
+		handler, _ := setupTransactionHandler()
+		router := setupGin()
+		router.GET("/users/:userId/transactions", handler.List)
+
+		req := httptest.NewRequest("GET", "/users/1/transactions?cursor=not-a-cursor", http.NoBody)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should filter by account and transfers", func(t *testing.T) {
+		handler, mockService := setupTransactionHandler()
+		router := setupGin()
+		router.GET("/users/:userId/transactions", handler.List)
+
+		accountID, scheduleID := uint(3), uint(8)
+		mockService.On("ListPageIncluding", mock.Anything, domain.TransactionFilter{
+			UserID: 1, Type: domain.TransactionTypeTransfer, AccountID: &accountID, ScheduledTransferID: &scheduleID, Limit: 100,
+		}, domain.TransactionIncludes{}).Return(&domain.TransactionPage{Transactions: []domain.Transaction{}, Limit: 100}, nil)
+		mockService.On("ListPageIncluding", mock.Anything, domain.TransactionFilter{
+			UserID: 1, TransferAccountID: &accountID, ExcludeTransfers: true, Limit: 100,
+		}, domain.TransactionIncludes{}).Return(&domain.TransactionPage{Transactions: []domain.Transaction{}, Limit: 100}, nil)
+
+		for _, query := range []string{"type=transfer&account_id=3&scheduled_transfer_id=8", "transfer_account_id=3&exclude_transfers=true"} {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/users/1/transactions?"+query, http.NoBody))
+			assert.Equal(t, http.StatusOK, w.Code, query)
+		}
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return bad request for invalid transfer filters", func(t *testing.T) {
+		handler, _ := setupTransactionHandler()
+		router := setupGin()
+		router.GET("/users/:userId/transactions", handler.List)
+
+		for _, query := range []string{"account_id=checking", "transfer_account_id=-1", "exclude_transfers=maybe"} {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/users/1/transactions?"+query, http.NoBody))
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		}
+	})
+
+	t.Run("should embed the included details", func(t *testing.T) {
+		handler, mockService := setupTransactionHandler()
+		router := setupGin()
+		router.GET("/users/:userId/transactions", handler.List)
+
+		count, balance := 2, domain.NewMoney(-50)
+		mockService.On("ListPageIncluding", mock.Anything, domain.TransactionFilter{UserID: 1, Limit: 100},
+			domain.TransactionIncludes{Attachments: true, Tags: true, RunningBalance: true}).
+			Return(&domain.TransactionPage{Transactions: []domain.Transaction{
+				{ID: 1, Tags: []string{"travel"}, AttachmentCount: &count, RunningBalance: &balance},
+			}, Total: 1, Limit: 100}, nil)
+
+		req := httptest.NewRequest("GET", "/users/1/transactions?include=category,attachments,tags,running_balance", http.NoBody)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response domain.TransactionPage
+		json.Unmarshal(w.Body.Bytes(), &response)
+		require.Len(t, response.Transactions, 1)
+		assert.Equal(t, []string{"travel"}, response.Transactions[0].Tags)
+		assert.Equal(t, &count, response.Transactions[0].AttachmentCount)
+		assert.Equal(t, &balance, response.Transactions[0].RunningBalance)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return bad request for unknown includes", func(t *testing.T) {
+		handler, _ := setupTransactionHandler()
+		router := setupGin()
+		router.GET("/users/:userId/transactions", handler.List)
+
+		req := httptest.NewRequest("GET", "/users/1/transactions?include=merchant", http.NoBody)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
 	t.Run("should return bad request for invalid user ID", func(t *testing.T) {
 		handler, _ := setupTransactionHandler()
 		router := setupGin()
@@ -380,7 +567,7 @@ func TestTransactionHandler_List(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 		var response map[string]interface{}
 		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Equal(t, "invalid user ID", response["error"])
+		assert.Equal(t, "Invalid user ID", response["error"])
 	})
 
 	t.Run("should return bad request for invalid category ID", func(t *testing.T) {
@@ -396,7 +583,7 @@ func TestTransactionHandler_List(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 		var response map[string]interface{}
 		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Equal(t, "invalid category ID", response["error"])
+		assert.Equal(t, "Invalid category ID", response["error"])
 	})
 
 	t.Run("should return bad request for invalid date format", func(t *testing.T) {
@@ -420,9 +607,8 @@ func TestTransactionHandler_List(t *testing.T) {
 		router := setupGin()
 		router.GET("/users/:userId/transactions", handler.List)
 
-		mockService.On("ListWithFilters", uint(1), (*string)(nil), (*uint)(nil),
-			(*time.Time)(nil), (*time.Time)(nil), 100, 0).
-			Return([]domain.Transaction{}, errors.New("database error"))
+		mockService.On("ListPageIncluding", mock.Anything, domain.TransactionFilter{UserID: 1, Limit: 100}, domain.TransactionIncludes{}).
+			Return(nil, errors.New("database error"))
 
 		req := httptest.NewRequest("GET", "/users/1/transactions", http.NoBody)
 		w := httptest.NewRecorder()
@@ -447,7 +633,7 @@ func TestTransactionHandler_ExportCSV(t *testing.T) {
 			{
 				ID:          1,
 				UserID:      1,
-				Amount:      100.50,
+				Amount:      domain.NewMoney(100.50),
 				Type:        "expense",
 				Description: "Grocery shopping",
 				CategoryID:  1,
@@ -456,7 +642,7 @@ func TestTransactionHandler_ExportCSV(t *testing.T) {
 			},
 		}
 
-		mockService.On("ListWithFilters", uint(1), (*string)(nil), (*uint)(nil),
+		mockService.On("ListWithFilters", mock.Anything, uint(1), (*string)(nil), (*uint)(nil),
 			(*time.Time)(nil), (*time.Time)(nil), 100, 0).
 			Return(expectedTransactions, nil)
 
@@ -486,7 +672,7 @@ func TestTransactionHandler_ExportCSV(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 		var response map[string]interface{}
 		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Equal(t, "invalid user ID", response["error"])
+		assert.Equal(t, "Invalid user ID", response["error"])
 	})
 
 	t.Run("should return internal server error when service fails", func(t *testing.T) {
@@ -494,7 +680,7 @@ func TestTransactionHandler_ExportCSV(t *testing.T) {
 		router := setupGin()
 		router.GET("/users/:userId/transactions/export/csv", handler.ExportCSV)
 
-		mockService.On("ListWithFilters", uint(1), (*string)(nil), (*uint)(nil),
+		mockService.On("ListWithFilters", mock.Anything, uint(1), (*string)(nil), (*uint)(nil),
 			(*time.Time)(nil), (*time.Time)(nil), 100, 0).
 			Return([]domain.Transaction{}, errors.New("database error"))
 
@@ -521,7 +707,7 @@ func TestTransactionHandler_ExportPDF(t *testing.T) {
 			{
 				ID:          1,
 				UserID:      1,
-				Amount:      100.50,
+				Amount:      domain.NewMoney(100.50),
 				Type:        "expense",
 				Description: "Grocery shopping",
 				CategoryID:  1,
@@ -529,7 +715,7 @@ func TestTransactionHandler_ExportPDF(t *testing.T) {
 			},
 		}
 
-		mockService.On("ListWithFilters", uint(1), (*string)(nil), (*uint)(nil),
+		mockService.On("ListWithFilters", mock.Anything, uint(1), (*string)(nil), (*uint)(nil),
 			(*time.Time)(nil), (*time.Time)(nil), 100, 0).
 			Return(expectedTransactions, nil)
 
@@ -560,7 +746,7 @@ func TestTransactionHandler_ExportPDF(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 		var response map[string]interface{}
 		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Equal(t, "invalid user ID", response["error"])
+		assert.Equal(t, "Invalid user ID", response["error"])
 	})
 
 	t.Run("should return internal server error when service fails", func(t *testing.T) {
@@ -568,7 +754,7 @@ func TestTransactionHandler_ExportPDF(t *testing.T) {
 		router := setupGin()
 		router.GET("/users/:userId/transactions/export/pdf", handler.ExportPDF)
 
-		mockService.On("ListWithFilters", uint(1), (*string)(nil), (*uint)(nil),
+		mockService.On("ListWithFilters", mock.Anything, uint(1), (*string)(nil), (*uint)(nil),
 			(*time.Time)(nil), (*time.Time)(nil), 100, 0).
 			Return([]domain.Transaction{}, errors.New("database error"))
 
@@ -589,21 +775,21 @@ func TestTransactionHandler_GetByID(t *testing.T) {
 	t.Run("should get transaction by ID successfully", func(t *testing.T) {
 		handler, mockService := setupTransactionHandler()
 		router := setupGin()
-		router.GET("/transactions/:id", handler.GetByID)
+		router.GET("/users/:userId/transactions/:id", handler.GetByID)
 
 		expectedTransaction := &domain.Transaction{
 			ID:          1,
 			UserID:      1,
-			Amount:      100.50,
+			Amount:      domain.NewMoney(100.50),
 			Type:        "expense",
 			Description: "Grocery shopping",
 			CategoryID:  1,
 			Date:        time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
 		}
 
-		mockService.On("GetByID", uint(1)).Return(expectedTransaction, nil)
+		mockService.On("GetByID", mock.Anything, uint(1)).Return(expectedTransaction, nil)
 
-		req := httptest.NewRequest("GET", "/transactions/1", http.NoBody)
+		req := httptest.NewRequest("GET", "/users/1/transactions/1", http.NoBody)
 		w := httptest.NewRecorder()
 
 		router.ServeHTTP(w, req)
@@ -616,14 +802,33 @@ func TestTransactionHandler_GetByID(t *testing.T) {
 		mockService.AssertExpectations(t)
 	})
 
+	t.Run("should pass the request context to the service", func(t *testing.T) {
+		handler, mockService := setupTransactionHandler()
+		router := setupGin()
+		router.GET("/users/:userId/transactions/:id", handler.GetByID)
+
+		type ctxKey struct{}
+		req := httptest.NewRequest("GET", "/users/1/transactions/1", http.NoBody)
+		req = req.WithContext(context.WithValue(req.Context(), ctxKey{}, "request"))
+
+		fromRequest := mock.MatchedBy(func(ctx context.Context) bool { return ctx.Value(ctxKey{}) == "request" })
+		mockService.On("GetByID", fromRequest, uint(1)).Return(&domain.Transaction{ID: 1, UserID: 1}, nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
 	t.Run("should return not found when transaction doesn't exist", func(t *testing.T) {
 		handler, mockService := setupTransactionHandler()
 		router := setupGin()
-		router.GET("/transactions/:id", handler.GetByID)
+		router.GET("/users/:userId/transactions/:id", handler.GetByID)
 
-		mockService.On("GetByID", uint(999)).Return(nil, errors.New("transaction not found"))
+		mockService.On("GetByID", mock.Anything, uint(999)).Return(nil, errors.New("transaction not found"))
 
-		req := httptest.NewRequest("GET", "/transactions/999", http.NoBody)
+		req := httptest.NewRequest("GET", "/users/1/transactions/999", http.NoBody)
 		w := httptest.NewRecorder()
 
 		router.ServeHTTP(w, req)
@@ -638,9 +843,9 @@ func TestTransactionHandler_GetByID(t *testing.T) {
 	t.Run("should return bad request for invalid transaction ID", func(t *testing.T) {
 		handler, _ := setupTransactionHandler()
 		router := setupGin()
-		router.GET("/transactions/:id", handler.GetByID)
+		router.GET("/users/:userId/transactions/:id", handler.GetByID)
 
-		req := httptest.NewRequest("GET", "/transactions/invalid", http.NoBody)
+		req := httptest.NewRequest("GET", "/users/1/transactions/invalid", http.NoBody)
 		w := httptest.NewRecorder()
 
 		router.ServeHTTP(w, req)
@@ -656,31 +861,31 @@ func TestTransactionHandler_Update(t *testing.T) {
 	t.Run("should update transaction successfully", func(t *testing.T) {
 		handler, mockService := setupTransactionHandler()
 		router := setupGin()
-		router.PUT("/transactions/:id", handler.Update)
+		router.PUT("/users/:userId/transactions/:id", handler.Update)
 
 		existingTransaction := &domain.Transaction{
 			ID:          1,
 			UserID:      1,
-			Amount:      100.50,
+			Amount:      domain.NewMoney(100.50),
 			Type:        "expense",
 			Description: "Old description",
 			CategoryID:  1,
 		}
 
 		updateReq := CreateTransactionRequest{
-			Amount:      150.75,
+			Amount:      domain.NewMoney(150.75),
 			Type:        "expense",
 			Description: "Updated description",
 			CategoryID:  2,
 		}
 
-		mockService.On("GetByID", uint(1)).Return(existingTransaction, nil)
-		mockService.On("Update", mock.MatchedBy(func(t *domain.Transaction) bool {
-			return t.Amount == 150.75 && t.Description == "Updated description"
+		mockService.On("GetByID", mock.Anything, uint(1)).Return(existingTransaction, nil)
+		mockService.On("Update", mock.Anything, mock.MatchedBy(func(t *domain.Transaction) bool {
+			return t.Amount == domain.NewMoney(150.75) && t.Description == "Updated description"
 		})).Return(nil)
 
 		requestBody, _ := json.Marshal(updateReq)
-		req := httptest.NewRequest("PUT", "/transactions/1", bytes.NewBuffer(requestBody))
+		req := httptest.NewRequest("PUT", "/users/1/transactions/1", bytes.NewBuffer(requestBody))
 		req.Header.Set("Content-Type", "application/json")
 		w := httptest.NewRecorder()
 
@@ -693,19 +898,19 @@ func TestTransactionHandler_Update(t *testing.T) {
 	t.Run("should return not found when transaction doesn't exist", func(t *testing.T) {
 		handler, mockService := setupTransactionHandler()
 		router := setupGin()
-		router.PUT("/transactions/:id", handler.Update)
+		router.PUT("/users/:userId/transactions/:id", handler.Update)
 
 		updateReq := CreateTransactionRequest{
-			Amount:      150.75,
+			Amount:      domain.NewMoney(150.75),
 			Type:        "expense",
 			Description: "Updated description",
 			CategoryID:  2,
 		}
 
-		mockService.On("GetByID", uint(999)).Return(nil, errors.New("transaction not found"))
+		mockService.On("GetByID", mock.Anything, uint(999)).Return(nil, errors.New("transaction not found"))
 
 		requestBody, _ := json.Marshal(updateReq)
-		req := httptest.NewRequest("PUT", "/transactions/999", bytes.NewBuffer(requestBody))
+		req := httptest.NewRequest("PUT", "/users/1/transactions/999", bytes.NewBuffer(requestBody))
 		req.Header.Set("Content-Type", "application/json")
 		w := httptest.NewRecorder()
 
@@ -723,11 +928,12 @@ func TestTransactionHandler_Delete(t *testing.T) {
 	t.Run("should delete transaction successfully", func(t *testing.T) {
 		handler, mockService := setupTransactionHandler()
 		router := setupGin()
-		router.DELETE("/transactions/:id", handler.Delete)
+		router.DELETE("/users/:userId/transactions/:id", handler.Delete)
 
-		mockService.On("Delete", uint(1)).Return(nil)
+		mockService.On("GetByID", mock.Anything, uint(1)).Return(&domain.Transaction{ID: 1, UserID: 1}, nil)
+		mockService.On("Delete", mock.Anything, uint(1)).Return(nil)
 
-		req := httptest.NewRequest("DELETE", "/transactions/1", http.NoBody)
+		req := httptest.NewRequest("DELETE", "/users/1/transactions/1", http.NoBody)
 		w := httptest.NewRecorder()
 
 		router.ServeHTTP(w, req)
@@ -742,11 +948,11 @@ func TestTransactionHandler_Delete(t *testing.T) {
 	t.Run("should return not found when transaction doesn't exist", func(t *testing.T) {
 		handler, mockService := setupTransactionHandler()
 		router := setupGin()
-		router.DELETE("/transactions/:id", handler.Delete)
+		router.DELETE("/users/:userId/transactions/:id", handler.Delete)
 
-		mockService.On("Delete", uint(999)).Return(errors.New("transaction not found"))
+		mockService.On("GetByID", mock.Anything, uint(999)).Return(nil, errors.New("transaction not found"))
 
-		req := httptest.NewRequest("DELETE", "/transactions/999", http.NoBody)
+		req := httptest.NewRequest("DELETE", "/users/1/transactions/999", http.NoBody)
 		w := httptest.NewRecorder()
 
 		router.ServeHTTP(w, req)
@@ -761,9 +967,9 @@ func TestTransactionHandler_Delete(t *testing.T) {
 	t.Run("should return bad request for invalid transaction ID", func(t *testing.T) {
 		handler, _ := setupTransactionHandler()
 		router := setupGin()
-		router.DELETE("/transactions/:id", handler.Delete)
+		router.DELETE("/users/:userId/transactions/:id", handler.Delete)
 
-		req := httptest.NewRequest("DELETE", "/transactions/invalid", http.NoBody)
+		req := httptest.NewRequest("DELETE", "/users/1/transactions/invalid", http.NoBody)
 		w := httptest.NewRecorder()
 
 		router.ServeHTTP(w, req)
@@ -774,3 +980,177 @@ func TestTransactionHandler_Delete(t *testing.T) {
 		assert.Equal(t, "Invalid transaction ID", response["error"])
 	})
 }
+
+func TestTransactionHandler_Ownership(t *testing.T) {
+	setupRouter := func(handler *TransactionHandler) *gin.Engine {
+		router := setupGin()
+		router.Use(func(c *gin.Context) { c.Set("userID", uint(1)) })
+		router.GET("/users/:userId/transactions/:id", handler.GetByID)
+		router.PUT("/users/:userId/transactions/:id", handler.Update)
+		router.DELETE("/users/:userId/transactions/:id", handler.Delete)
+		return router
+	}
+	updateBody, _ := json.Marshal(CreateTransactionRequest{
+		Amount: domain.NewMoney(10), Type: "expense", Description: "Taken over", CategoryID: 1,
+	})
+
+	t.Run("should forbid another user's path", func(t *testing.T) {
+		handler, mockService := setupTransactionHandler()
+		router := setupRouter(handler)
+
+		for _, method := range []string{"GET", "PUT", "DELETE"} {
+			req := httptest.NewRequest(method, "/users/2/transactions/1", bytes.NewBuffer(updateBody))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusForbidden, w.Code, method)
+		}
+		mockService.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should hide another user's transaction", func(t *testing.T) {
+		handler, mockService := setupTransactionHandler()
+		router := setupRouter(handler)
+		mockService.On("GetByID", mock.Anything, uint(5)).Return(&domain.Transaction{ID: 5, UserID: 2}, nil)
+
+		for _, method := range []string{"GET", "PUT", "DELETE"} {
+			req := httptest.NewRequest(method, "/users/1/transactions/5", bytes.NewBuffer(updateBody))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusNotFound, w.Code, method)
+			var response map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &response)
+			assert.Equal(t, "Transaction not found", response["error"], method)
+		}
+		mockService.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+		mockService.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTransactionHandler_Trash(t *testing.T) {
+	setupTrashRouter := func() (*gin.Engine, *MockTransactionService) {
+		handler, mockService := setupTransactionHandler()
+		router := setupGin()
+		router.GET("/users/:userId/transactions/trash", handler.ListTrash)
+		router.POST("/users/:userId/transactions/trash/:id/restore", handler.Restore)
+		router.DELETE("/users/:userId/transactions/trash/:id", handler.Purge)
+		router.DELETE("/users/:userId/transactions/trash", handler.EmptyTrash)
+		return router, mockService
+	}
+
+	t.Run("should list deleted transactions", func(t *testing.T) {
+		router, mockService := setupTrashRouter()
+		deleted := []domain.Transaction{{ID: 3, UserID: 1, Amount: domain.NewMoney(20), Description: "Oops"}}
+		mockService.On("ListDeleted", mock.Anything, uint(1)).Return(deleted, nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1/transactions/trash", http.NoBody))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response []domain.Transaction
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response, 1)
+		assert.Equal(t, "Oops", response[0].Description)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should restore a transaction", func(t *testing.T) {
+		router, mockService := setupTrashRouter()
+		restored := &domain.Transaction{ID: 3, UserID: 1, Amount: domain.NewMoney(20)}
+		mockService.On("Restore", mock.Anything, uint(1), uint(3)).Return(restored, nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/1/transactions/trash/3/restore", http.NoBody))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"id":3`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return not found when the transaction is not in the trash", func(t *testing.T) {
+		router, mockService := setupTrashRouter()
+		mockService.On("Restore", mock.Anything, uint(1), uint(9)).Return(nil, domain.ErrNotFound)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/1/transactions/trash/9/restore", http.NoBody))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should purge a single transaction", func(t *testing.T) {
+		router, mockService := setupTrashRouter()
+		mockService.On("Purge", mock.Anything, uint(1), uint(3)).Return(nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/users/1/transactions/trash/3", http.NoBody))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should report a purge failure", func(t *testing.T) {
+		router, mockService := setupTrashRouter()
+		mockService.On("Purge", mock.Anything, uint(1), uint(3)).Return(errors.New("database is locked"))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/users/1/transactions/trash/3", http.NoBody))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should empty the trash", func(t *testing.T) {
+		router, mockService := setupTrashRouter()
+		mockService.On("EmptyTrash", mock.Anything, uint(1)).Return(int64(4), nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/users/1/transactions/trash", http.NoBody))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, float64(4), response["purged"])
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should forbid other users' trash", func(t *testing.T) {
+		handler, mockService := setupTransactionHandler()
+		router := setupGin()
+		router.Use(func(c *gin.Context) {
+			c.Set("userID", uint(1))
+			c.Next()
+		})
+		router.GET("/users/:userId/transactions/trash", handler.ListTrash)
+		router.POST("/users/:userId/transactions/trash/:id/restore", handler.Restore)
+		router.DELETE("/users/:userId/transactions/trash/:id", handler.Purge)
+		router.DELETE("/users/:userId/transactions/trash", handler.EmptyTrash)
+
+		for _, req := range []*http.Request{
+			httptest.NewRequest(http.MethodGet, "/users/2/transactions/trash", http.NoBody),
+			httptest.NewRequest(http.MethodPost, "/users/2/transactions/trash/3/restore", http.NoBody),
+			httptest.NewRequest(http.MethodDelete, "/users/2/transactions/trash/3", http.NoBody),
+			httptest.NewRequest(http.MethodDelete, "/users/2/transactions/trash", http.NoBody),
+		} {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusForbidden, w.Code, "%s %s", req.Method, req.URL.Path)
+		}
+		mockService.AssertNotCalled(t, "ListDeleted", mock.Anything, mock.Anything)
+		mockService.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything, mock.Anything)
+		mockService.AssertNotCalled(t, "Purge", mock.Anything, mock.Anything, mock.Anything)
+		mockService.AssertNotCalled(t, "EmptyTrash", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reject an invalid transaction ID", func(t *testing.T) {
+		router, _ := setupTrashRouter()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/1/transactions/trash/abc/restore", http.NoBody))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
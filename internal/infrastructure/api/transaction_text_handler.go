@@ -0,0 +1,79 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TransactionTextServiceInterface defines the interface for reading
+// transactions from free text
+type TransactionTextServiceInterface interface {
+	Parse(ctx context.Context, userID uint, text string) (*domain.ParsedTransaction, error)
+}
+
+type TransactionTextHandler struct {
+	Service TransactionTextServiceInterface
+}
+
+func NewTransactionTextHandler(service TransactionTextServiceInterface) *TransactionTextHandler {
+	return &TransactionTextHandler{Service: service}
+}
+
+// ParseTransactionRequest is the body of requests reading a transaction from text
+type ParseTransactionRequest struct {
+	Text string `json:"text" binding:"required"`
+}
+
+// Parse reads a transaction from text such as "coffee 4.50 yesterday" and
+// returns a prefilled transaction request for the user to confirm
+func (h *TransactionTextHandler) Parse(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req ParseTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	parsed, err := h.Service.Parse(c.Request.Context(), userID, req.Text)
+	if err != nil {
+		switch {
+		case respondValidationError(c, err):
+		case errors.Is(err, domain.ErrUnparseableTransaction):
+			respondError(c, middleware.CodeUnprocessable, err.Error())
+		default:
+			respondInternalError(c, "Failed to read the transaction", err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"text":                req.Text,
+		"source":              parsed.Source,
+		"category_suggestion": parsed.Suggestion,
+		"transaction":         parsedTransactionRequest(parsed),
+	})
+}
+
+// parsedTransactionRequest maps a parsed transaction onto a CreateTransactionRequest
+func parsedTransactionRequest(parsed *domain.ParsedTransaction) CreateTransactionRequest {
+	req := CreateTransactionRequest{
+		Amount:      parsed.Amount,
+		Type:        parsed.Type,
+		Description: parsed.Description,
+		Date:        parsed.Date.Format("2006-01-02"),
+	}
+	if parsed.Suggestion != nil {
+		req.CategoryID = parsed.Suggestion.CategoryID
+	}
+	return req
+}
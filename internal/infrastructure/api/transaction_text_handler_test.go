@@ -0,0 +1,129 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockTransactionTextService is a mock implementation of TransactionTextServiceInterface
+type MockTransactionTextService struct {
+	mock.Mock
+}
+
+func (m *MockTransactionTextService) Parse(ctx context.Context, userID uint, text string) (*domain.ParsedTransaction, error) {
+	args := m.Called(ctx, userID, text)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ParsedTransaction), args.Error(1)
+}
+
+func setupTransactionTextRouter(service *MockTransactionTextService) *gin.Engine {
+	handler := NewTransactionTextHandler(service)
+	router := setupGin()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Next()
+	})
+	router.POST("/users/:userId/transactions/parse", handler.Parse)
+	return router
+}
+
+func TestTransactionTextHandler_Parse(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		mockSetup      func(*MockTransactionTextService)
+		expectedStatus int
+		expected       CreateTransactionRequest
+	}{
+		{
+			name: "prefills a transaction",
+			path: "/users/1/transactions/parse",
+			body: `{"text": "coffee 4.50 yesterday"}`,
+			mockSetup: func(m *MockTransactionTextService) {
+				m.On("Parse", mock.Anything, uint(1), "coffee 4.50 yesterday").Return(&domain.ParsedTransaction{
+					Amount: domain.NewMoney(4.5), Type: domain.TransactionTypeExpense, Description: "Coffee",
+					Date: time.Date(2024, 5, 14, 0, 0, 0, 0, time.UTC), Source: domain.TransactionTextSourceRules,
+					Suggestion: &domain.CategorySuggestion{CategoryID: 7, Confidence: 0.9, Source: domain.SuggestionSourceModel},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expected: CreateTransactionRequest{
+				Amount: domain.NewMoney(4.5), Type: domain.TransactionTypeExpense, Description: "Coffee",
+				CategoryID: 7, Date: "2024-05-14",
+			},
+		},
+		{
+			name:           "missing text",
+			path:           "/users/1/transactions/parse",
+			body:           `{}`,
+			mockSetup:      func(m *MockTransactionTextService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "unreadable text",
+			path: "/users/1/transactions/parse",
+			body: `{"text": "hello"}`,
+			mockSetup: func(m *MockTransactionTextService) {
+				m.On("Parse", mock.Anything, uint(1), "hello").Return(nil, domain.ErrUnparseableTransaction)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "service error",
+			path: "/users/1/transactions/parse",
+			body: `{"text": "coffee 4.50"}`,
+			mockSetup: func(m *MockTransactionTextService) {
+				m.On("Parse", mock.Anything, uint(1), "coffee 4.50").Return(nil, errors.New("db down"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "other users' transactions are forbidden",
+			path:           "/users/2/transactions/parse",
+			body:           `{"text": "coffee 4.50"}`,
+			mockSetup:      func(m *MockTransactionTextService) {},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(MockTransactionTextService)
+			tt.mockSetup(service)
+			router := setupTransactionTextRouter(service)
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response struct {
+					Source      string                   `json:"source"`
+					Transaction CreateTransactionRequest `json:"transaction"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, domain.TransactionTextSourceRules, response.Source)
+				assert.Equal(t, tt.expected, response.Transaction)
+			}
+			service.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UsageServiceInterface defines the interface for plan and quota operations
+type UsageServiceInterface interface {
+	Usage(ctx context.Context, userID uint) (*domain.Usage, error)
+	SetPlan(ctx context.Context, userID uint, plan string) (*domain.Usage, error)
+}
+
+type UsageHandler struct {
+	Service UsageServiceInterface
+}
+
+func NewUsageHandler(service UsageServiceInterface) *UsageHandler {
+	return &UsageHandler{Service: service}
+}
+
+// SetPlanRequest is the body of plan changes
+type SetPlanRequest struct {
+	Plan string `json:"plan" binding:"required"`
+}
+
+// Get returns the user's plan, its limits and what is left of its daily quotas
+func (h *UsageHandler) Get(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	usage, err := h.Service.Usage(c.Request.Context(), userID)
+	if errors.Is(err, domain.ErrNotFound) {
+		respondError(c, middleware.CodeNotFound, "User not found")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve usage", err)
+		return
+	}
+	c.JSON(http.StatusOK, usage)
+}
+
+// SetPlan moves a user to another plan; it is an admin route
+func (h *UsageHandler) SetPlan(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid user ID")
+		return
+	}
+
+	var req SetPlanRequest
+	if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
+		respondError(c, middleware.CodeInvalidBody, bindErr.Error())
+		return
+	}
+
+	usage, err := h.Service.SetPlan(c.Request.Context(), uint(userID), req.Plan)
+	if respondValidationError(c, err) {
+		return
+	}
+	if errors.Is(err, domain.ErrNotFound) {
+		respondError(c, middleware.CodeNotFound, "User not found")
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to change the plan", err)
+		return
+	}
+	c.JSON(http.StatusOK, usage)
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockUsageService is a mock implementation of UsageServiceInterface
+type MockUsageService struct {
+	mock.Mock
+}
+
+func (m *MockUsageService) Usage(ctx context.Context, userID uint) (*domain.Usage, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Usage), args.Error(1)
+}
+
+func (m *MockUsageService) SetPlan(ctx context.Context, userID uint, plan string) (*domain.Usage, error) {
+	args := m.Called(ctx, userID, plan)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Usage), args.Error(1)
+}
+
+func setupUsageRouter(service *MockUsageService) *gin.Engine {
+	handler := NewUsageHandler(service)
+	router := setupGin()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Next()
+	})
+	router.GET("/users/:userId/usage", handler.Get)
+	router.PUT("/admin/users/:userId/plan", handler.SetPlan)
+	return router
+}
+
+func TestUsageHandler_Get(t *testing.T) {
+	service := new(MockUsageService)
+	remaining := 15
+	service.On("Usage", mock.Anything, uint(1)).Return(&domain.Usage{
+		Plan:   domain.PlanFree,
+		Limits: domain.PlanLimits{AIRequestsPerDay: 20},
+		Quotas: []domain.QuotaUsage{
+			{Quota: domain.QuotaAIRequests, Used: 5, Limit: 20, Remaining: &remaining, ResetsAt: time.Now()},
+		},
+	}, nil)
+	router := setupUsageRouter(service)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1/usage", http.NoBody))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var usage domain.Usage
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &usage))
+	assert.Equal(t, domain.PlanFree, usage.Plan)
+	require.Len(t, usage.Quotas, 1)
+	assert.Equal(t, 15, *usage.Quotas[0].Remaining)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/2/usage", http.NoBody))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	service.AssertExpectations(t)
+}
+
+func TestUsageHandler_SetPlan(t *testing.T) {
+	service := new(MockUsageService)
+	service.On("SetPlan", mock.Anything, uint(2), domain.PlanPremium).Return(&domain.Usage{Plan: domain.PlanPremium}, nil)
+	service.On("SetPlan", mock.Anything, uint(2), "gold").Return(nil, domain.ValidatePlan("gold"))
+	service.On("SetPlan", mock.Anything, uint(9), domain.PlanFree).Return(nil, domain.ErrNotFound)
+	router := setupUsageRouter(service)
+
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		wantStatus int
+	}{
+		{"moves the user", "/admin/users/2/plan", `{"plan":"premium"}`, http.StatusOK},
+		{"unknown plan", "/admin/users/2/plan", `{"plan":"gold"}`, http.StatusUnprocessableEntity},
+		{"unknown user", "/admin/users/9/plan", `{"plan":"free"}`, http.StatusNotFound},
+		{"missing plan", "/admin/users/2/plan", `{}`, http.StatusBadRequest},
+		{"invalid user ID", "/admin/users/x/plan", `{"plan":"free"}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPut, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+	service.AssertExpectations(t)
+}
@@ -1,8 +1,11 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"go-finance-advisor/internal/domain"
 	"go-finance-advisor/internal/infrastructure/middleware"
@@ -19,16 +22,20 @@ const (
 
 // UserServiceInterface defines the contract for user service operations
 type UserServiceInterface interface {
-	Create(user *domain.User) error
-	GetByID(id uint) (domain.User, error)
-	Update(user *domain.User) error
-	GetByEmail(email string) (*domain.User, error)
-	Register(email, password, firstName, lastName string) (*domain.User, error)
-	Login(email, password string) (*domain.User, error)
+	Create(ctx context.Context, user *domain.User) error
+	GetByID(ctx context.Context, id uint) (domain.User, error)
+	Update(ctx context.Context, user *domain.User) error
+	GetByEmail(ctx context.Context, email string) (*domain.User, error)
+	Register(ctx context.Context, email, password, firstName, lastName string) (*domain.User, error)
+	Login(ctx context.Context, email, password string) (*domain.User, error)
+	UpdateProfile(ctx context.Context, userID uint, profile domain.UserProfile) (domain.User, error)
+	UpdateLocale(ctx context.Context, userID uint, locale string) (domain.User, error)
+	UpdateAccountType(ctx context.Context, userID uint, accountType string) (domain.User, error)
 }
 
 type UserHandler struct {
-	Service UserServiceInterface
+	Service  UserServiceInterface
+	Sessions SessionServiceInterface // Opens a session with a refresh token on login when set
 }
 
 func NewUserHandler(service UserServiceInterface) *UserHandler {
@@ -38,14 +45,14 @@ func NewUserHandler(service UserServiceInterface) *UserHandler {
 func (h *UserHandler) Create(c *gin.Context) {
 	var u domain.User
 	if err := c.ShouldBindJSON(&u); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, middleware.CodeInvalidBody, err.Error())
 		return
 	}
 	if u.RiskTolerance == "" {
 		u.RiskTolerance = riskToleranceModerate
 	}
-	if err := h.Service.Create(&u); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
+	if err := h.Service.Create(c.Request.Context(), &u); err != nil {
+		respondInternalError(c, "Failed to create user", err)
 		return
 	}
 	c.JSON(http.StatusCreated, u)
@@ -56,18 +63,18 @@ func (h *UserHandler) Get(c *gin.Context) {
 	id, err := strconv.Atoi(idStr)
 	if err != nil || id <= 0 {
 		// For invalid IDs, we still call the service to maintain test expectations
-		_, serviceErr := h.Service.GetByID(uint(0))
+		_, serviceErr := h.Service.GetByID(c.Request.Context(), uint(0))
 		if serviceErr != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			respondError(c, middleware.CodeNotFound, "User not found")
 			return
 		}
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		respondError(c, middleware.CodeInvalidID, "Invalid user ID")
 		return
 	}
 
-	u, err := h.Service.GetByID(uint(id))
+	u, err := h.Service.GetByID(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		respondError(c, middleware.CodeNotFound, "User not found")
 		return
 	}
 	c.JSON(http.StatusOK, u)
@@ -89,68 +96,55 @@ type LoginRequest struct {
 func (h *UserHandler) Register(c *gin.Context) {
 	var req RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, middleware.CodeInvalidBody, err.Error())
 		return
 	}
 
-	user, err := h.Service.Register(req.Email, req.Password, req.FirstName, req.LastName)
+	user, err := h.Service.Register(c.Request.Context(), req.Email, req.Password, req.FirstName, req.LastName)
 	if err != nil {
 		if err.Error() == "user already exists" {
-			c.JSON(http.StatusConflict, gin.H{"error": "User already exists"})
+			respondError(c, middleware.CodeConflict, "User already exists")
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Registration failed"})
+		respondInternalError(c, "Registration failed", err)
 		return
 	}
 
-	// Generate JWT token for immediate login
-	token, err := middleware.GenerateToken(user.ID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Token generation failed"})
-		return
+	// Log the new user in right away
+	response := gin.H{"user": loginUser(user)}
+	if issueTokens(c, h.Sessions, user.ID, response) {
+		c.JSON(http.StatusCreated, response)
 	}
-
-	c.JSON(http.StatusCreated, gin.H{
-		"user": gin.H{
-			"id":         user.ID,
-			"email":      user.Email,
-			"first_name": user.FirstName,
-			"last_name":  user.LastName,
-		},
-		"token": token,
-	})
 }
 
 // Login authenticates user with email and password
 func (h *UserHandler) Login(c *gin.Context) {
 	var req LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, middleware.CodeInvalidBody, err.Error())
 		return
 	}
 
-	user, err := h.Service.Login(req.Email, req.Password)
+	user, err := h.Service.Login(c.Request.Context(), req.Email, req.Password)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
+		respondError(c, middleware.CodeInvalidCredentials, "Invalid credentials")
 		return
 	}
 
-	// Generate JWT token
-	token, err := middleware.GenerateToken(user.ID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Token generation failed"})
-		return
+	response := gin.H{"user": loginUser(user)}
+	if issueTokens(c, h.Sessions, user.ID, response) {
+		c.JSON(http.StatusOK, response)
 	}
+}
 
-	c.JSON(http.StatusOK, gin.H{
-		"user": gin.H{
-			"id":         user.ID,
-			"email":      user.Email,
-			"first_name": user.FirstName,
-			"last_name":  user.LastName,
-		},
-		"token": token,
-	})
+// loginUser is the part of a user login responses show
+func loginUser(user *domain.User) gin.H {
+	return gin.H{
+		"id":         user.ID,
+		"email":      user.Email,
+		"first_name": user.FirstName,
+		"last_name":  user.LastName,
+	}
 }
 
 type RiskUpdateRequest struct {
@@ -163,7 +157,7 @@ func (h *UserHandler) UpdateRisk(c *gin.Context) {
 
 	var req RiskUpdateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, middleware.CodeInvalidBody, err.Error())
 		return
 	}
 
@@ -171,26 +165,140 @@ func (h *UserHandler) UpdateRisk(c *gin.Context) {
 	if req.RiskTolerance != riskToleranceConservative &&
 		req.RiskTolerance != riskToleranceModerate &&
 		req.RiskTolerance != riskToleranceAggressive {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid risk tolerance"})
+		respondError(c, middleware.CodeBadRequest, "Invalid risk tolerance")
 		return
 	}
 
 	if id < 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		respondError(c, middleware.CodeInvalidID, "Invalid user ID")
 		return
 	}
 
-	user, err := h.Service.GetByID(uint(id))
+	user, err := h.Service.GetByID(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		respondError(c, middleware.CodeNotFound, "User not found")
 		return
 	}
 
 	user.RiskTolerance = req.RiskTolerance
-	if err := h.Service.Update(&user); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed"})
+	if err := h.Service.Update(c.Request.Context(), &user); err != nil {
+		respondInternalError(c, "Failed to update risk tolerance", err)
 		return
 	}
 
 	c.JSON(http.StatusOK, user)
 }
+
+// UpdateProfileRequest is the body of profile updates. BirthDate is
+// YYYY-MM-DD; leaving it out clears it.
+type UpdateProfileRequest struct {
+	BirthDate        string       `json:"birth_date"`
+	EmploymentStatus string       `json:"employment_status"`
+	Dependents       int          `json:"dependents"`
+	MonthlyIncome    domain.Money `json:"monthly_income"`
+}
+
+// UpdateProfile replaces the user's birth date, employment status,
+// dependents and monthly income, which the advisor uses for its advice
+func (h *UserHandler) UpdateProfile(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateProfileRequest
+	if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
+		respondError(c, middleware.CodeInvalidBody, bindErr.Error())
+		return
+	}
+
+	profile := domain.UserProfile{
+		EmploymentStatus: req.EmploymentStatus,
+		Dependents:       req.Dependents,
+		MonthlyIncome:    req.MonthlyIncome,
+	}
+	if req.BirthDate != "" {
+		birthDate, parseErr := time.Parse("2006-01-02", req.BirthDate)
+		if parseErr != nil {
+			respondError(c, middleware.CodeInvalidDate, "Invalid birth date format. Use YYYY-MM-DD")
+			return
+		}
+		profile.BirthDate = &birthDate
+	}
+
+	user, err := h.Service.UpdateProfile(c.Request.Context(), userID, profile)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "User not found")
+	case err != nil:
+		if !respondValidationError(c, err) {
+			respondInternalError(c, "Failed to update profile", err)
+		}
+	default:
+		c.JSON(http.StatusOK, user)
+	}
+}
+
+// UpdateLocaleRequest is the body of locale updates
+type UpdateLocaleRequest struct {
+	Locale string `json:"locale" binding:"required"`
+}
+
+// UpdateLocale sets the language the user's reports and exports are written
+// in: en, tr or de
+func (h *UserHandler) UpdateLocale(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateLocaleRequest
+	if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
+		respondError(c, middleware.CodeInvalidBody, bindErr.Error())
+		return
+	}
+
+	user, err := h.Service.UpdateLocale(c.Request.Context(), userID, req.Locale)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "User not found")
+	case err != nil:
+		if !respondValidationError(c, err) {
+			respondInternalError(c, "Failed to update locale", err)
+		}
+	default:
+		c.JSON(http.StatusOK, user)
+	}
+}
+
+// UpdateAccountTypeRequest is the body of account type updates
+type UpdateAccountTypeRequest struct {
+	AccountType string `json:"account_type" binding:"required"`
+}
+
+// UpdateAccountType makes the user's account a personal or an advisor one.
+// Clients can only grant advisor accounts access to their finances.
+func (h *UserHandler) UpdateAccountType(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateAccountTypeRequest
+	if bindErr := c.ShouldBindJSON(&req); bindErr != nil {
+		respondError(c, middleware.CodeInvalidBody, bindErr.Error())
+		return
+	}
+
+	user, err := h.Service.UpdateAccountType(c.Request.Context(), userID, req.AccountType)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "User not found")
+	case err != nil:
+		if !respondValidationError(c, err) {
+			respondInternalError(c, "Failed to update account type", err)
+		}
+	default:
+		c.JSON(http.StatusOK, user)
+	}
+}
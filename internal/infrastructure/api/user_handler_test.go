@@ -6,10 +6,12 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -53,7 +55,7 @@ func TestUserHandler_Create(t *testing.T) {
 			RiskTolerance: "moderate",
 		}
 
-		mockService.On("Create", mock.AnythingOfType("*domain.User")).Return(nil)
+		mockService.On("Create", mock.Anything, mock.AnythingOfType("*domain.User")).Return(nil)
 
 		requestBody, _ := json.Marshal(user)
 		req := httptest.NewRequest("POST", "/users", bytes.NewBuffer(requestBody))
@@ -79,7 +81,7 @@ func TestUserHandler_Create(t *testing.T) {
 			// RiskTolerance not set
 		}
 
-		mockService.On("Create", mock.MatchedBy(func(u *domain.User) bool {
+		mockService.On("Create", mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
 			return u.RiskTolerance == "moderate"
 		})).Return(nil)
 
@@ -108,7 +110,7 @@ func TestUserHandler_Create(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 		var response map[string]interface{}
 		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Contains(t, response["error"], "invalid character")
+		assert.Contains(t, response["error"], "Invalid character")
 	})
 
 	t.Run("should return internal server error when service fails", func(t *testing.T) {
@@ -123,7 +125,7 @@ func TestUserHandler_Create(t *testing.T) {
 			LastName:  "Doe",
 		}
 
-		mockService.On("Create", mock.AnythingOfType("*domain.User")).Return(errors.New("database error"))
+		mockService.On("Create", mock.Anything, mock.AnythingOfType("*domain.User")).Return(errors.New("database error"))
 
 		requestBody, _ := json.Marshal(user)
 		req := httptest.NewRequest("POST", "/users", bytes.NewBuffer(requestBody))
@@ -135,7 +137,7 @@ func TestUserHandler_Create(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
 		var response map[string]interface{}
 		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Equal(t, "db error", response["error"])
+		assert.Equal(t, "Failed to create user", response["error"])
 		mockService.AssertExpectations(t)
 	})
 }
@@ -155,7 +157,7 @@ func TestUserHandler_Get(t *testing.T) {
 			UpdatedAt: time.Now(),
 		}
 
-		mockService.On("GetByID", uint(1)).Return(expectedUser, nil)
+		mockService.On("GetByID", mock.Anything, uint(1)).Return(expectedUser, nil)
 
 		req := httptest.NewRequest("GET", "/users/1", http.NoBody)
 		w := httptest.NewRecorder()
@@ -175,7 +177,7 @@ func TestUserHandler_Get(t *testing.T) {
 		router := setupGin()
 		router.GET("/users/:userId", handler.Get)
 
-		mockService.On("GetByID", uint(999)).Return(domain.User{}, errors.New("user not found"))
+		mockService.On("GetByID", mock.Anything, uint(999)).Return(domain.User{}, errors.New("user not found"))
 
 		req := httptest.NewRequest("GET", "/users/999", http.NoBody)
 		w := httptest.NewRecorder()
@@ -185,7 +187,7 @@ func TestUserHandler_Get(t *testing.T) {
 		assert.Equal(t, http.StatusNotFound, w.Code)
 		var response map[string]interface{}
 		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Equal(t, "user not found", response["error"])
+		assert.Equal(t, "User not found", response["error"])
 		mockService.AssertExpectations(t)
 	})
 
@@ -195,7 +197,7 @@ func TestUserHandler_Get(t *testing.T) {
 		router.GET("/users/:userId", handler.Get)
 
 		// Note: Gin will convert "invalid" to 0, so this tests the zero ID case
-		mockService.On("GetByID", uint(0)).Return(domain.User{}, errors.New("user not found"))
+		mockService.On("GetByID", mock.Anything, uint(0)).Return(domain.User{}, errors.New("user not found"))
 
 		req := httptest.NewRequest("GET", "/users/invalid", http.NoBody)
 		w := httptest.NewRecorder()
@@ -228,7 +230,7 @@ func TestUserHandler_Register(t *testing.T) {
 			LastName:  "Doe",
 		}
 
-		mockService.On("Register", "test@example.com", "password123", "John", "Doe").Return(&expectedUser, nil)
+		mockService.On("Register", mock.Anything, "test@example.com", "password123", "John", "Doe").Return(&expectedUser, nil)
 
 		requestBody, _ := json.Marshal(registerReq)
 		req := httptest.NewRequest("POST", "/register", bytes.NewBuffer(requestBody))
@@ -257,7 +259,7 @@ func TestUserHandler_Register(t *testing.T) {
 			LastName:  "Doe",
 		}
 
-		mockService.On("Register", "existing@example.com", "password123", "John", "Doe").
+		mockService.On("Register", mock.Anything, "existing@example.com", "password123", "John", "Doe").
 			Return((*domain.User)(nil), errors.New("user already exists"))
 
 		requestBody, _ := json.Marshal(registerReq)
@@ -296,7 +298,7 @@ func TestUserHandler_Register(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 		var response map[string]interface{}
 		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Contains(t, response["error"], "email")
+		assert.Contains(t, response["error"], "Email")
 	})
 
 	t.Run("should return bad request for short password", func(t *testing.T) {
@@ -343,7 +345,7 @@ func TestUserHandler_Login(t *testing.T) {
 			LastName:  "Doe",
 		}
 
-		mockService.On("Login", "test@example.com", "password123").Return(&expectedUser, nil)
+		mockService.On("Login", mock.Anything, "test@example.com", "password123").Return(&expectedUser, nil)
 
 		requestBody, _ := json.Marshal(loginReq)
 		req := httptest.NewRequest("POST", "/login", bytes.NewBuffer(requestBody))
@@ -370,7 +372,7 @@ func TestUserHandler_Login(t *testing.T) {
 			Password: "wrongpassword",
 		}
 
-		mockService.On("Login", "test@example.com", "wrongpassword").Return((*domain.User)(nil), errors.New("invalid credentials"))
+		mockService.On("Login", mock.Anything, "test@example.com", "wrongpassword").Return((*domain.User)(nil), errors.New("invalid credentials"))
 
 		requestBody, _ := json.Marshal(loginReq)
 		req := httptest.NewRequest("POST", "/login", bytes.NewBuffer(requestBody))
@@ -420,8 +422,8 @@ func TestUserHandler_UpdateRisk(t *testing.T) {
 			RiskTolerance: "aggressive",
 		}
 
-		mockService.On("GetByID", uint(1)).Return(existingUser, nil)
-		mockService.On("Update", mock.MatchedBy(func(u *domain.User) bool {
+		mockService.On("GetByID", mock.Anything, uint(1)).Return(existingUser, nil)
+		mockService.On("Update", mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
 			return u.RiskTolerance == "aggressive"
 		})).Return(nil)
 
@@ -455,7 +457,7 @@ func TestUserHandler_UpdateRisk(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 		var response map[string]interface{}
 		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Equal(t, "invalid risk tolerance", response["error"])
+		assert.Equal(t, "Invalid risk tolerance", response["error"])
 	})
 
 	t.Run("should return not found when user doesn't exist", func(t *testing.T) {
@@ -467,7 +469,7 @@ func TestUserHandler_UpdateRisk(t *testing.T) {
 			RiskTolerance: "conservative",
 		}
 
-		mockService.On("GetByID", uint(999)).Return(domain.User{}, errors.New("user not found"))
+		mockService.On("GetByID", mock.Anything, uint(999)).Return(domain.User{}, errors.New("user not found"))
 
 		requestBody, _ := json.Marshal(riskUpdateReq)
 		req := httptest.NewRequest("PUT", "/users/999/risk", bytes.NewBuffer(requestBody))
@@ -479,7 +481,7 @@ func TestUserHandler_UpdateRisk(t *testing.T) {
 		assert.Equal(t, http.StatusNotFound, w.Code)
 		var response map[string]interface{}
 		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Equal(t, "user not found", response["error"])
+		assert.Equal(t, "User not found", response["error"])
 		mockService.AssertExpectations(t)
 	})
 
@@ -498,8 +500,8 @@ func TestUserHandler_UpdateRisk(t *testing.T) {
 			RiskTolerance: "conservative",
 		}
 
-		mockService.On("GetByID", uint(1)).Return(existingUser, nil)
-		mockService.On("Update", mock.AnythingOfType("*domain.User")).Return(errors.New("database error"))
+		mockService.On("GetByID", mock.Anything, uint(1)).Return(existingUser, nil)
+		mockService.On("Update", mock.Anything, mock.AnythingOfType("*domain.User")).Return(errors.New("database error"))
 
 		requestBody, _ := json.Marshal(riskUpdateReq)
 		req := httptest.NewRequest("PUT", "/users/1/risk", bytes.NewBuffer(requestBody))
@@ -511,7 +513,154 @@ func TestUserHandler_UpdateRisk(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
 		var response map[string]interface{}
 		json.Unmarshal(w.Body.Bytes(), &response)
-		assert.Equal(t, "update failed", response["error"])
+		assert.Equal(t, "Failed to update risk tolerance", response["error"])
 		mockService.AssertExpectations(t)
 	})
 }
+
+func TestUserHandler_UpdateProfile(t *testing.T) {
+	newRouter := func() (*gin.Engine, *MockUserService) {
+		handler, mockService := setupUserHandler()
+		router := setupGin()
+		router.PUT("/users/:userId/profile", handler.UpdateProfile)
+		return router, mockService
+	}
+	put := func(router *gin.Engine, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("PUT", "/users/1/profile", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("should update the profile", func(t *testing.T) {
+		router, mockService := newRouter()
+		birthDate := time.Date(1990, 4, 12, 0, 0, 0, 0, time.UTC)
+		profile := domain.UserProfile{
+			BirthDate: &birthDate, EmploymentStatus: domain.EmploymentEmployed,
+			Dependents: 2, MonthlyIncome: domain.NewMoney(6200),
+		}
+		mockService.On("UpdateProfile", mock.Anything, uint(1), profile).
+			Return(domain.User{ID: 1, UserProfile: profile}, nil)
+
+		w := put(router, `{"birth_date": "1990-04-12", "employment_status": "employed", "dependents": 2, "monthly_income": 6200}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"monthly_income":6200.00`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return bad request for invalid birth date", func(t *testing.T) {
+		router, _ := newRouter()
+
+		w := put(router, `{"birth_date": "12/04/1990"}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), string(middleware.CodeInvalidDate))
+	})
+
+	t.Run("should return unprocessable entity for an invalid profile", func(t *testing.T) {
+		router, mockService := newRouter()
+		invalid := (&domain.UserProfile{Dependents: -1}).Validate()
+		mockService.On("UpdateProfile", mock.Anything, uint(1), mock.Anything).Return(domain.User{}, invalid)
+
+		w := put(router, `{"dependents": -1}`)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Contains(t, w.Body.String(), `"field":"dependents"`)
+	})
+
+	t.Run("should return not found when user doesn't exist", func(t *testing.T) {
+		router, mockService := newRouter()
+		mockService.On("UpdateProfile", mock.Anything, uint(1), mock.Anything).Return(domain.User{}, domain.ErrNotFound)
+
+		w := put(router, `{"employment_status": "retired"}`)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestUserHandler_UpdateLocale(t *testing.T) {
+	newRouter := func() (*gin.Engine, *MockUserService) {
+		handler, mockService := setupUserHandler()
+		router := setupGin()
+		router.PUT("/users/:userId/locale", handler.UpdateLocale)
+		return router, mockService
+	}
+	put := func(router *gin.Engine, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("PUT", "/users/1/locale", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("should update the locale", func(t *testing.T) {
+		router, mockService := newRouter()
+		mockService.On("UpdateLocale", mock.Anything, uint(1), "tr").Return(domain.User{ID: 1, Locale: "tr"}, nil)
+
+		w := put(router, `{"locale": "tr"}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"locale":"tr"`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return unprocessable entity for an unsupported locale", func(t *testing.T) {
+		router, mockService := newRouter()
+		mockService.On("UpdateLocale", mock.Anything, uint(1), "fr").Return(domain.User{}, domain.ValidateLocale("fr"))
+
+		w := put(router, `{"locale": "fr"}`)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Contains(t, w.Body.String(), `"field":"locale"`)
+	})
+
+	t.Run("should require a locale", func(t *testing.T) {
+		router, mockService := newRouter()
+
+		w := put(router, `{}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "UpdateLocale")
+	})
+}
+
+func TestUserHandler_UpdateAccountType(t *testing.T) {
+	newRouter := func() (*gin.Engine, *MockUserService) {
+		handler, mockService := setupUserHandler()
+		router := setupGin()
+		router.PUT("/users/:userId/account-type", handler.UpdateAccountType)
+		return router, mockService
+	}
+	put := func(router *gin.Engine, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("PUT", "/users/1/account-type", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("should make the account an advisor account", func(t *testing.T) {
+		router, mockService := newRouter()
+		mockService.On("UpdateAccountType", mock.Anything, uint(1), domain.UserAccountTypeAdvisor).
+			Return(domain.User{ID: 1, AccountType: domain.UserAccountTypeAdvisor}, nil)
+
+		w := put(router, `{"account_type": "advisor"}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"account_type":"advisor"`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return unprocessable entity for an unknown account type", func(t *testing.T) {
+		router, mockService := newRouter()
+		mockService.On("UpdateAccountType", mock.Anything, uint(1), "broker").
+			Return(domain.User{}, domain.ValidateAccountType("broker"))
+
+		w := put(router, `{"account_type": "broker"}`)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Contains(t, w.Body.String(), `"field":"account_type"`)
+	})
+}
@@ -0,0 +1,165 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WalletServiceInterface defines the interface for tracked crypto wallets
+type WalletServiceInterface interface {
+	Add(ctx context.Context, userID uint, wallet domain.Wallet) (*domain.Wallet, error)
+	List(ctx context.Context, userID uint) ([]domain.Wallet, error)
+	Get(ctx context.Context, userID, id uint) (*domain.Wallet, error)
+	Transactions(ctx context.Context, userID, id uint) ([]domain.WalletTransaction, error)
+	Refresh(ctx context.Context, userID, id uint) (*domain.Wallet, error)
+	Remove(ctx context.Context, userID, id uint) error
+}
+
+type WalletHandler struct {
+	Service WalletServiceInterface
+}
+
+func NewWalletHandler(service WalletServiceInterface) *WalletHandler {
+	return &WalletHandler{Service: service}
+}
+
+// WalletRequest is the body of requests registering a public address
+type WalletRequest struct {
+	Chain   string `json:"chain" binding:"required"`
+	Address string `json:"address" binding:"required"`
+	Label   string `json:"label"`
+}
+
+// Add starts tracking a public BTC or ETH address
+func (h *WalletHandler) Add(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req WalletRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	wallet, err := h.Service.Add(c.Request.Context(), userID, domain.Wallet{Chain: req.Chain, Address: req.Address, Label: req.Label})
+	switch {
+	case err == nil:
+		c.JSON(http.StatusCreated, wallet)
+	case respondValidationError(c, err):
+	case errors.Is(err, domain.ErrWalletExists):
+		respondError(c, middleware.CodeConflict, err.Error())
+	default:
+		respondInternalError(c, "Failed to add wallet", err)
+	}
+}
+
+// List returns the user's wallets valued at current prices
+func (h *WalletHandler) List(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	wallets, err := h.Service.List(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve wallets", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"wallets": wallets, "count": len(wallets)})
+}
+
+// Get returns one of the user's wallets
+func (h *WalletHandler) Get(c *gin.Context) {
+	userID, walletID, ok := h.walletParams(c)
+	if !ok {
+		return
+	}
+
+	wallet, err := h.Service.Get(c.Request.Context(), userID, walletID)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "Wallet not found")
+	case err != nil:
+		respondInternalError(c, "Failed to retrieve wallet", err)
+	default:
+		c.JSON(http.StatusOK, wallet)
+	}
+}
+
+// Transactions returns the transactions read for one of the user's wallets
+func (h *WalletHandler) Transactions(c *gin.Context) {
+	userID, walletID, ok := h.walletParams(c)
+	if !ok {
+		return
+	}
+
+	transactions, err := h.Service.Transactions(c.Request.Context(), userID, walletID)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "Wallet not found")
+	case err != nil:
+		respondInternalError(c, "Failed to retrieve wallet transactions", err)
+	default:
+		c.JSON(http.StatusOK, gin.H{"transactions": transactions, "count": len(transactions)})
+	}
+}
+
+// Refresh reads a wallet from its chain now
+func (h *WalletHandler) Refresh(c *gin.Context) {
+	userID, walletID, ok := h.walletParams(c)
+	if !ok {
+		return
+	}
+
+	wallet, err := h.Service.Refresh(c.Request.Context(), userID, walletID)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "Wallet not found")
+	case err != nil:
+		middleware.RespondError(c, &middleware.APIError{
+			Code: middleware.CodeUnavailable, Message: "Failed to read the wallet from its chain", Cause: err,
+		})
+	default:
+		c.JSON(http.StatusOK, wallet)
+	}
+}
+
+// Remove stops tracking a wallet
+func (h *WalletHandler) Remove(c *gin.Context) {
+	userID, walletID, ok := h.walletParams(c)
+	if !ok {
+		return
+	}
+
+	err := h.Service.Remove(c.Request.Context(), userID, walletID)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "Wallet not found")
+	case err != nil:
+		respondInternalError(c, "Failed to remove wallet", err)
+	default:
+		c.JSON(http.StatusOK, gin.H{"message": "Wallet removed"})
+	}
+}
+
+func (h *WalletHandler) walletParams(c *gin.Context) (userID, walletID uint, ok bool) {
+	userID, ok = authorizedUserID(c)
+	if !ok {
+		return 0, 0, false
+	}
+	id, err := strconv.ParseUint(c.Param("walletId"), 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid wallet ID")
+		return 0, 0, false
+	}
+	return userID, uint(id), true
+}
@@ -0,0 +1,203 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockWalletService is a mock implementation of WalletServiceInterface
+type MockWalletService struct {
+	mock.Mock
+}
+
+func (m *MockWalletService) Add(ctx context.Context, userID uint, wallet domain.Wallet) (*domain.Wallet, error) {
+	args := m.Called(ctx, userID, wallet)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Wallet), args.Error(1)
+}
+
+func (m *MockWalletService) List(ctx context.Context, userID uint) ([]domain.Wallet, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.Wallet), args.Error(1)
+}
+
+func (m *MockWalletService) Get(ctx context.Context, userID, id uint) (*domain.Wallet, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Wallet), args.Error(1)
+}
+
+func (m *MockWalletService) Transactions(ctx context.Context, userID, id uint) ([]domain.WalletTransaction, error) {
+	args := m.Called(ctx, userID, id)
+	return args.Get(0).([]domain.WalletTransaction), args.Error(1)
+}
+
+func (m *MockWalletService) Refresh(ctx context.Context, userID, id uint) (*domain.Wallet, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Wallet), args.Error(1)
+}
+
+func (m *MockWalletService) Remove(ctx context.Context, userID, id uint) error {
+	return m.Called(ctx, userID, id).Error(0)
+}
+
+func setupWalletRouter(service *MockWalletService) *gin.Engine {
+	handler := NewWalletHandler(service)
+	router := setupGin()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Next()
+	})
+	router.POST("/users/:userId/wallets", handler.Add)
+	router.GET("/users/:userId/wallets", handler.List)
+	router.GET("/users/:userId/wallets/:walletId", handler.Get)
+	router.GET("/users/:userId/wallets/:walletId/transactions", handler.Transactions)
+	router.POST("/users/:userId/wallets/:walletId/refresh", handler.Refresh)
+	router.DELETE("/users/:userId/wallets/:walletId", handler.Remove)
+	return router
+}
+
+func TestWalletHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		mockSetup      func(*MockWalletService)
+		expectedStatus int
+	}{
+		{
+			name:   "add a wallet",
+			method: http.MethodPost,
+			path:   "/users/1/wallets",
+			body:   `{"chain": "btc", "address": "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", "label": "Savings"}`,
+			mockSetup: func(m *MockWalletService) {
+				m.On("Add", mock.Anything, uint(1), domain.Wallet{Chain: "btc", Address: "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", Label: "Savings"}).
+					Return(&domain.Wallet{ID: 1, Chain: "BTC"}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "add without an address",
+			method:         http.MethodPost,
+			path:           "/users/1/wallets",
+			body:           `{"chain": "eth"}`,
+			mockSetup:      func(m *MockWalletService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "add an invalid address",
+			method: http.MethodPost,
+			path:   "/users/1/wallets",
+			body:   `{"chain": "eth", "address": "0x123"}`,
+			mockSetup: func(m *MockWalletService) {
+				m.On("Add", mock.Anything, uint(1), mock.Anything).Return(nil, &domain.ValidationError{
+					Fields: []domain.FieldError{{Field: "address", Message: "must be 0x followed by 40 hexadecimal digits"}},
+				})
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "add a tracked address",
+			method: http.MethodPost,
+			path:   "/users/1/wallets",
+			body:   `{"chain": "eth", "address": "0xde0b295669a9fd93d5f28d9ec85e40f4cb697bae"}`,
+			mockSetup: func(m *MockWalletService) {
+				m.On("Add", mock.Anything, uint(1), mock.Anything).Return(nil, domain.ErrWalletExists)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:   "list wallets",
+			method: http.MethodGet,
+			path:   "/users/1/wallets",
+			mockSetup: func(m *MockWalletService) {
+				m.On("List", mock.Anything, uint(1)).Return([]domain.Wallet{{ID: 1, Chain: "BTC", Balance: 0.5}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "get a missing wallet",
+			method: http.MethodGet,
+			path:   "/users/1/wallets/9",
+			mockSetup: func(m *MockWalletService) {
+				m.On("Get", mock.Anything, uint(1), uint(9)).Return(nil, domain.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "get with an invalid ID",
+			method:         http.MethodGet,
+			path:           "/users/1/wallets/abc",
+			mockSetup:      func(m *MockWalletService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "list transactions",
+			method: http.MethodGet,
+			path:   "/users/1/wallets/1/transactions",
+			mockSetup: func(m *MockWalletService) {
+				m.On("Transactions", mock.Anything, uint(1), uint(1)).Return([]domain.WalletTransaction{{Hash: "tx", Amount: 0.5}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "refresh when the chain API is down",
+			method: http.MethodPost,
+			path:   "/users/1/wallets/1/refresh",
+			mockSetup: func(m *MockWalletService) {
+				m.On("Refresh", mock.Anything, uint(1), uint(1)).Return(nil, errors.New("esplora returned status 503"))
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:   "remove a wallet",
+			method: http.MethodDelete,
+			path:   "/users/1/wallets/1",
+			mockSetup: func(m *MockWalletService) {
+				m.On("Remove", mock.Anything, uint(1), uint(1)).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "other users' wallets are forbidden",
+			method:         http.MethodGet,
+			path:           "/users/2/wallets",
+			mockSetup:      func(m *MockWalletService) {},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(MockWalletService)
+			tt.mockSetup(service)
+			router := setupWalletRouter(service)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			service.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-finance-advisor/internal/application"
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WatchlistServiceInterface defines the interface for watchlist operations
+type WatchlistServiceInterface interface {
+	List(ctx context.Context, userID uint) ([]domain.WatchlistItem, error)
+	Add(ctx context.Context, userID uint, item domain.WatchlistItem) (*domain.WatchlistItem, error)
+	Remove(ctx context.Context, userID uint, symbol string) error
+	Quotes(ctx context.Context, userID uint) ([]domain.WatchlistQuote, error)
+}
+
+type WatchlistHandler struct {
+	Service WatchlistServiceInterface
+}
+
+func NewWatchlistHandler(service WatchlistServiceInterface) *WatchlistHandler {
+	return &WatchlistHandler{Service: service}
+}
+
+type WatchlistItemRequest struct {
+	Symbol    string `json:"symbol" binding:"required"`
+	Name      string `json:"name"`
+	AssetType string `json:"asset_type"`
+}
+
+// authorizedUserID reads the userId parameter and rejects requests for other
+// users' data
+func authorizedUserID(c *gin.Context) (uint, bool) {
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid user ID")
+		return 0, false
+	}
+	if authUserID, ok := c.Get("userID"); ok && authUserID != uint(userID) {
+		respondError(c, middleware.CodeForbidden, "Access denied")
+		return 0, false
+	}
+	return uint(userID), true
+}
+
+// List returns the symbols on the user's watchlist
+func (h *WatchlistHandler) List(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	items, err := h.Service.List(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve watchlist", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"watchlist": items, "count": len(items)})
+}
+
+// Add saves a symbol to the user's watchlist
+func (h *WatchlistHandler) Add(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req WatchlistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	item, err := h.Service.Add(c.Request.Context(), userID, domain.WatchlistItem{
+		Symbol:    req.Symbol,
+		Name:      req.Name,
+		AssetType: req.AssetType,
+	})
+	switch {
+	case errors.Is(err, domain.ErrInvalidSymbol), errors.Is(err, application.ErrInvalidAssetType):
+		respondError(c, middleware.CodeBadRequest, err.Error())
+	case errors.Is(err, domain.ErrAlreadyWatchlisted):
+		respondError(c, middleware.CodeConflict, err.Error())
+	case err != nil:
+		respondInternalError(c, "Failed to update watchlist", err)
+	default:
+		c.JSON(http.StatusCreated, item)
+	}
+}
+
+// Remove takes a symbol off the user's watchlist
+func (h *WatchlistHandler) Remove(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	err := h.Service.Remove(c.Request.Context(), userID, c.Param("symbol"))
+	switch {
+	case errors.Is(err, domain.ErrInvalidSymbol):
+		respondError(c, middleware.CodeBadRequest, err.Error())
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "Symbol is not on the watchlist")
+	case err != nil:
+		respondInternalError(c, "Failed to update watchlist", err)
+	default:
+		c.JSON(http.StatusOK, gin.H{"message": "Symbol removed from watchlist"})
+	}
+}
+
+// Quotes returns the current price of every watchlisted symbol
+func (h *WatchlistHandler) Quotes(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	quotes, err := h.Service.Quotes(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, "Failed to price watchlist", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"quotes": quotes, "count": len(quotes)})
+}
@@ -0,0 +1,197 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-finance-advisor/internal/application"
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/pkg"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockWatchlistService is a mock implementation of WatchlistServiceInterface
+// and WatchlistSymbolsInterface
+type MockWatchlistService struct {
+	mock.Mock
+}
+
+func (m *MockWatchlistService) List(ctx context.Context, userID uint) ([]domain.WatchlistItem, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.WatchlistItem), args.Error(1)
+}
+
+func (m *MockWatchlistService) Add(ctx context.Context, userID uint, item domain.WatchlistItem) (*domain.WatchlistItem, error) {
+	args := m.Called(ctx, userID, item)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.WatchlistItem), args.Error(1)
+}
+
+func (m *MockWatchlistService) Remove(ctx context.Context, userID uint, symbol string) error {
+	args := m.Called(ctx, userID, symbol)
+	return args.Error(0)
+}
+
+func (m *MockWatchlistService) Quotes(ctx context.Context, userID uint) ([]domain.WatchlistQuote, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.WatchlistQuote), args.Error(1)
+}
+
+func (m *MockWatchlistService) Symbols(ctx context.Context, userID uint, assetType string) ([]string, error) {
+	args := m.Called(ctx, userID, assetType)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func setupWatchlistRouter(service *MockWatchlistService, authUserID uint) *gin.Engine {
+	handler := NewWatchlistHandler(service)
+	router := setupGin()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", authUserID)
+		c.Next()
+	})
+	router.GET("/users/:userId/watchlist", handler.List)
+	router.POST("/users/:userId/watchlist", handler.Add)
+	router.DELETE("/users/:userId/watchlist/:symbol", handler.Remove)
+	router.GET("/users/:userId/watchlist/quotes", handler.Quotes)
+	return router
+}
+
+func TestWatchlistHandler_Add(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		err        error
+		wantStatus int
+	}{
+		{name: "should save the symbol", body: `{"symbol": "aapl", "name": "Apple Inc"}`, wantStatus: http.StatusCreated},
+		{name: "should reject duplicates", body: `{"symbol": "aapl"}`, err: domain.ErrAlreadyWatchlisted, wantStatus: http.StatusConflict},
+		{name: "should reject invalid symbols", body: `{"symbol": "a b"}`, err: domain.ErrInvalidSymbol, wantStatus: http.StatusBadRequest},
+		{
+			name: "should reject unknown asset types", body: `{"symbol": "aapl", "asset_type": "bond"}`,
+			err: application.ErrInvalidAssetType, wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockWatchlistService)
+			router := setupWatchlistRouter(mockService, 1)
+			var item *domain.WatchlistItem
+			if tt.err == nil {
+				item = &domain.WatchlistItem{ID: 1, UserID: 1, Symbol: "AAPL"}
+			}
+			mockService.On("Add", mock.Anything, uint(1), mock.AnythingOfType("domain.WatchlistItem")).Return(item, tt.err)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/1/watchlist", strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+
+	t.Run("should forbid other users' watchlists", func(t *testing.T) {
+		mockService := new(MockWatchlistService)
+		router := setupWatchlistRouter(mockService, 2)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/1/watchlist", strings.NewReader(`{"symbol": "AAPL"}`)))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		mockService.AssertNotCalled(t, "Add")
+	})
+}
+
+func TestWatchlistHandler_Remove(t *testing.T) {
+	mockService := new(MockWatchlistService)
+	router := setupWatchlistRouter(mockService, 1)
+	mockService.On("Remove", mock.Anything, uint(1), "AAPL").Return(nil)
+	mockService.On("Remove", mock.Anything, uint(1), "TSLA").Return(domain.ErrNotFound)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/users/1/watchlist/AAPL", http.NoBody))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/users/1/watchlist/TSLA", http.NoBody))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestWatchlistHandler_Quotes(t *testing.T) {
+	mockService := new(MockWatchlistService)
+	router := setupWatchlistRouter(mockService, 1)
+	mockService.On("Quotes", mock.Anything, uint(1)).Return([]domain.WatchlistQuote{
+		{WatchlistItem: domain.WatchlistItem{Symbol: "AAPL"}, Price: 190, Available: true},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1/watchlist/quotes", http.NoBody))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Quotes []domain.WatchlistQuote `json:"quotes"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Quotes, 1)
+	assert.Equal(t, "AAPL", response.Quotes[0].Symbol)
+}
+
+func TestAdvisorHandler_SearchSymbols(t *testing.T) {
+	handler, _, _, mockMarketService := setupAdvisorHandler()
+	router := setupGin()
+	router.GET("/market/symbols/search", handler.SearchSymbols)
+	mockMarketService.On("SearchSymbols", mock.Anything, "appl").
+		Return([]pkg.SymbolMatch{{Symbol: "AAPL", Name: "Apple Inc"}}, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/market/symbols/search?q=appl", http.NoBody))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"AAPL"`)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/market/symbols/search?q=", http.NoBody))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAdvisorHandler_PricesWatchlistFirst(t *testing.T) {
+	handler, _, _, mockMarketService := setupAdvisorHandler()
+	watchlist := new(MockWatchlistService)
+	handler.Watchlist = watchlist
+	router := setupGin()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Next()
+	})
+	router.GET("/market/stocks", handler.GetStockPrices)
+	router.GET("/market/crypto", handler.GetCryptoPrices)
+
+	watchlist.On("Symbols", mock.Anything, uint(1), application.WatchlistAssetStock).Return([]string{"NVDA"}, nil)
+	watchlist.On("Symbols", mock.Anything, uint(1), application.WatchlistAssetCrypto).Return([]string{"ETH"}, nil)
+	mockMarketService.On("GetStockPrices", mock.Anything, []string{"NVDA", "AAPL", "GOOGL", "MSFT", "TSLA"}).
+		Return([]pkg.StockPrice{{Symbol: "NVDA"}}, nil)
+	mockMarketService.On("GetCryptoPrices", mock.Anything).
+		Return([]pkg.CryptoPrice{{Symbol: "btc"}, {Symbol: "eth"}}, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/market/stocks", http.NoBody))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/market/crypto", http.NoBody))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Cryptos []pkg.CryptoPrice `json:"cryptos"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "eth", response.Cryptos[0].Symbol)
+	mockMarketService.AssertExpectations(t)
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookServiceInterface defines the interface for managing webhooks
+type WebhookServiceInterface interface {
+	Create(ctx context.Context, userID uint, webhook domain.Webhook) (*domain.Webhook, error)
+	List(ctx context.Context, userID uint) ([]domain.Webhook, error)
+	Delete(ctx context.Context, userID, id uint) error
+}
+
+type WebhookHandler struct {
+	Service WebhookServiceInterface
+}
+
+func NewWebhookHandler(service WebhookServiceInterface) *WebhookHandler {
+	return &WebhookHandler{Service: service}
+}
+
+// WebhookRequest is the body of requests adding a webhook. Without events
+// the webhook receives all of them.
+type WebhookRequest struct {
+	URL    string   `json:"url" binding:"required"`
+	Events []string `json:"events"`
+}
+
+// List returns the user's webhooks
+func (h *WebhookHandler) List(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	webhooks, err := h.Service.List(c.Request.Context(), userID)
+	if err != nil {
+		respondInternalError(c, "Failed to retrieve webhooks", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"webhooks": webhooks, "count": len(webhooks)})
+}
+
+// Create adds a webhook and returns it with the secret its deliveries are
+// signed with, which is not shown again
+func (h *WebhookHandler) Create(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req WebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, middleware.CodeInvalidBody, err.Error())
+		return
+	}
+
+	webhook, err := h.Service.Create(c.Request.Context(), userID, domain.Webhook{URL: req.URL, Events: req.Events})
+	if respondValidationError(c, err) {
+		return
+	}
+	if err != nil {
+		respondInternalError(c, "Failed to create webhook", err)
+		return
+	}
+	c.JSON(http.StatusCreated, webhook)
+}
+
+// Delete removes a webhook
+func (h *WebhookHandler) Delete(c *gin.Context) {
+	userID, ok := authorizedUserID(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("webhookId"), 10, 32)
+	if err != nil {
+		respondError(c, middleware.CodeInvalidID, "Invalid webhook ID")
+		return
+	}
+
+	err = h.Service.Delete(c.Request.Context(), userID, uint(id))
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		respondError(c, middleware.CodeNotFound, "Webhook not found")
+	case err != nil:
+		respondInternalError(c, "Failed to delete webhook", err)
+	default:
+		c.JSON(http.StatusOK, gin.H{"message": "Webhook deleted"})
+	}
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockWebhookService is a mock implementation of WebhookServiceInterface
+type MockWebhookService struct {
+	mock.Mock
+}
+
+func (m *MockWebhookService) Create(ctx context.Context, userID uint, webhook domain.Webhook) (*domain.Webhook, error) {
+	args := m.Called(ctx, userID, webhook)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookService) List(ctx context.Context, userID uint) ([]domain.Webhook, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookService) Delete(ctx context.Context, userID, id uint) error {
+	return m.Called(ctx, userID, id).Error(0)
+}
+
+func setupWebhookRouter(service *MockWebhookService) *gin.Engine {
+	handler := NewWebhookHandler(service)
+	router := setupGin()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Next()
+	})
+	router.GET("/users/:userId/webhooks", handler.List)
+	router.POST("/users/:userId/webhooks", handler.Create)
+	router.DELETE("/users/:userId/webhooks/:webhookId", handler.Delete)
+	return router
+}
+
+func TestWebhookHandler(t *testing.T) {
+	t.Run("should create a webhook and show its secret", func(t *testing.T) {
+		service := new(MockWebhookService)
+		webhook := domain.Webhook{URL: "https://hooks.example.com/finance", Events: []string{domain.EventBudgetExceeded}}
+		created := webhook
+		created.ID, created.UserID, created.Secret, created.Active = 2, 1, "s3cret", true
+		service.On("Create", mock.Anything, uint(1), webhook).Return(&created, nil)
+
+		body := `{"url":"https://hooks.example.com/finance","events":["budget.exceeded"]}`
+		w := httptest.NewRecorder()
+		setupWebhookRouter(service).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/1/webhooks", strings.NewReader(body)))
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"secret":"s3cret"`)
+		service.AssertExpectations(t)
+	})
+
+	t.Run("should return 422 for invalid webhooks and 400 without a URL", func(t *testing.T) {
+		service := new(MockWebhookService)
+		service.On("Create", mock.Anything, uint(1), mock.Anything).Return(nil, &domain.ValidationError{
+			Fields: []domain.FieldError{{Field: "url", Message: "must be an http or https URL"}},
+		})
+		router := setupWebhookRouter(service)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/1/webhooks", strings.NewReader(`{"url":"ftp://x"}`)))
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/1/webhooks", strings.NewReader(`{}`)))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should list and delete webhooks", func(t *testing.T) {
+		service := new(MockWebhookService)
+		service.On("List", mock.Anything, uint(1)).Return([]domain.Webhook{{ID: 2, URL: "https://hooks.example.com"}}, nil)
+		service.On("Delete", mock.Anything, uint(1), uint(2)).Return(nil)
+		service.On("Delete", mock.Anything, uint(1), uint(3)).Return(domain.ErrNotFound)
+		router := setupWebhookRouter(service)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1/webhooks", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"count":1`)
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/users/1/webhooks/2", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/users/1/webhooks/3", nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/2/webhooks", nil))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
@@ -0,0 +1,167 @@
+// Package encryption encrypts sensitive column values before they are stored.
+// Values are sealed with AES-256-GCM and carry the ID of the key that sealed
+// them, so keys can be rotated without decrypting everything at once.
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// prefix marks encrypted values. Values without it are plaintext written
+// before encryption was enabled and are returned as they are.
+const prefix = "enc:v1:"
+
+// KeySize is the length of AES-256 keys in bytes
+const KeySize = 32
+
+// ErrUnknownKey is returned when a value was sealed with a key the provider
+// does not have
+var ErrUnknownKey = errors.New("unknown encryption key")
+
+// KeyProvider hands out data encryption keys. The static provider reads them
+// from the configuration; a KMS backed provider can fetch and cache them instead.
+type KeyProvider interface {
+	// CurrentKeyID names the key new values are sealed with
+	CurrentKeyID() string
+	// Key returns the key with the ID, or ErrUnknownKey
+	Key(ctx context.Context, id string) ([]byte, error)
+}
+
+// Cipher encrypts and decrypts column values. A nil Cipher leaves values in
+// plaintext, which is what deployments without a configured key get.
+type Cipher struct {
+	keys KeyProvider
+}
+
+func NewCipher(keys KeyProvider) *Cipher {
+	return &Cipher{keys: keys}
+}
+
+// Encrypt seals the value with the current key. Empty values stay empty so
+// optional columns keep meaning "not set".
+func (c *Cipher) Encrypt(ctx context.Context, plaintext string) (string, error) {
+	if c == nil || plaintext == "" {
+		return plaintext, nil
+	}
+	keyID := c.keys.CurrentKeyID()
+	aead, err := c.aead(ctx, keyID)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(keyID))
+	return prefix + keyID + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a value sealed by Encrypt with whichever key sealed it.
+// Plaintext values are returned unchanged.
+func (c *Cipher) Decrypt(ctx context.Context, value string) (string, error) {
+	keyID, encoded, ok := parse(value)
+	if !ok {
+		return value, nil
+	}
+	if c == nil {
+		return "", errors.New("encrypted value found but no encryption key is configured")
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("malformed encrypted value: %w", err)
+	}
+	aead, err := c.aead(ctx, keyID)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", errors.New("malformed encrypted value: too short")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(keyID))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value sealed with key %q: %w", keyID, err)
+	}
+	return string(plaintext), nil
+}
+
+// NeedsRotation reports whether the value is plaintext or sealed with a key
+// other than the current one, so rotation should encrypt it again
+func (c *Cipher) NeedsRotation(value string) bool {
+	if c == nil || value == "" {
+		return false
+	}
+	keyID, _, ok := parse(value)
+	return !ok || keyID != c.keys.CurrentKeyID()
+}
+
+func (c *Cipher) aead(ctx context.Context, keyID string) (cipher.AEAD, error) {
+	key, err := c.keys.Key(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key %q: %w", keyID, err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// parse splits an encrypted value into its key ID and sealed data
+func parse(value string) (keyID, sealed string, ok bool) {
+	rest, found := strings.CutPrefix(value, prefix)
+	if !found {
+		return "", "", false
+	}
+	return strings.Cut(rest, ":")
+}
+
+// StaticKeyProvider serves keys given up front, e.g. from the configuration
+type StaticKeyProvider struct {
+	currentID string
+	keys      map[string][]byte
+}
+
+var _ KeyProvider = (*StaticKeyProvider)(nil)
+
+// NewStaticKeyProvider decodes base64 encoded 32 byte keys by ID. currentID
+// must be one of them.
+func NewStaticKeyProvider(currentID string, encodedKeys map[string]string) (*StaticKeyProvider, error) {
+	if _, ok := encodedKeys[currentID]; !ok {
+		return nil, fmt.Errorf("current encryption key %q is not configured", currentID)
+	}
+	keys := make(map[string][]byte, len(encodedKeys))
+	for id, encoded := range encodedKeys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("invalid encryption key ID %q", id)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("encryption key %q is not valid base64: %w", id, err)
+		}
+		if len(key) != KeySize {
+			return nil, fmt.Errorf("encryption key %q must be %d bytes, got %d", id, KeySize, len(key))
+		}
+		keys[id] = key
+	}
+	return &StaticKeyProvider{currentID: currentID, keys: keys}, nil
+}
+
+func (p *StaticKeyProvider) CurrentKeyID() string {
+	return p.currentID
+}
+
+func (p *StaticKeyProvider) Key(_ context.Context, id string) ([]byte, error) {
+	key, ok := p.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownKey, id)
+	}
+	return key, nil
+}
@@ -0,0 +1,124 @@
+package encryption
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(rune(b)), KeySize)))
+}
+
+func testCipher(t *testing.T, currentID string, keys map[string]string) *Cipher {
+	t.Helper()
+	provider, err := NewStaticKeyProvider(currentID, keys)
+	require.NoError(t, err)
+	return NewCipher(provider)
+}
+
+func TestCipher_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	c := testCipher(t, "k1", map[string]string{"k1": testKey('a')})
+
+	sealed, err := c.Encrypt(ctx, "DE89370400440532013000")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(sealed, "enc:v1:k1:"))
+	assert.NotContains(t, sealed, "DE89370400440532013000")
+
+	again, err := c.Encrypt(ctx, "DE89370400440532013000")
+	require.NoError(t, err)
+	assert.NotEqual(t, sealed, again, "every value gets a fresh nonce")
+
+	plain, err := c.Decrypt(ctx, sealed)
+	require.NoError(t, err)
+	assert.Equal(t, "DE89370400440532013000", plain)
+}
+
+func TestCipher_EmptyAndPlaintextValues(t *testing.T) {
+	ctx := context.Background()
+	c := testCipher(t, "k1", map[string]string{"k1": testKey('a')})
+
+	sealed, err := c.Encrypt(ctx, "")
+	require.NoError(t, err)
+	assert.Empty(t, sealed)
+
+	plain, err := c.Decrypt(ctx, "legacy plaintext")
+	require.NoError(t, err)
+	assert.Equal(t, "legacy plaintext", plain)
+}
+
+func TestCipher_NilLeavesPlaintext(t *testing.T) {
+	ctx := context.Background()
+	var c *Cipher
+
+	value, err := c.Encrypt(ctx, "1234")
+	require.NoError(t, err)
+	assert.Equal(t, "1234", value)
+	assert.False(t, c.NeedsRotation("1234"))
+
+	sealed, err := testCipher(t, "k1", map[string]string{"k1": testKey('a')}).Encrypt(ctx, "1234")
+	require.NoError(t, err)
+	_, err = c.Decrypt(ctx, sealed)
+	assert.Error(t, err)
+}
+
+func TestCipher_Rotation(t *testing.T) {
+	ctx := context.Background()
+	old := testCipher(t, "k1", map[string]string{"k1": testKey('a')})
+	sealed, err := old.Encrypt(ctx, "secret")
+	require.NoError(t, err)
+
+	rotated := testCipher(t, "k2", map[string]string{"k1": testKey('a'), "k2": testKey('b')})
+	assert.True(t, rotated.NeedsRotation(sealed))
+	assert.True(t, rotated.NeedsRotation("plaintext"))
+	assert.False(t, rotated.NeedsRotation(""))
+
+	plain, err := rotated.Decrypt(ctx, sealed)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", plain)
+
+	resealed, err := rotated.Encrypt(ctx, plain)
+	require.NoError(t, err)
+	assert.False(t, rotated.NeedsRotation(resealed))
+
+	retired := testCipher(t, "k2", map[string]string{"k2": testKey('b')})
+	_, err = retired.Decrypt(ctx, sealed)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestCipher_TamperedValue(t *testing.T) {
+	ctx := context.Background()
+	c := testCipher(t, "k1", map[string]string{"k1": testKey('a'), "k2": testKey('b')})
+	sealed, err := c.Encrypt(ctx, "secret")
+	require.NoError(t, err)
+
+	// Pointing the value at another key fails authentication
+	_, err = c.Decrypt(ctx, strings.Replace(sealed, ":k1:", ":k2:", 1))
+	assert.Error(t, err)
+
+	_, err = c.Decrypt(ctx, "enc:v1:k1:!!!")
+	assert.Error(t, err)
+}
+
+func TestNewStaticKeyProvider_Invalid(t *testing.T) {
+	tests := map[string]struct {
+		current string
+		keys    map[string]string
+	}{
+		"missing current": {"k2", map[string]string{"k1": testKey('a')}},
+		"not base64":      {"k1", map[string]string{"k1": "not base64!"}},
+		"short key":       {"k1", map[string]string{"k1": base64.StdEncoding.EncodeToString([]byte("short"))}},
+		"colon in ID":     {"k:1", map[string]string{"k:1": testKey('a')}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewStaticKeyProvider(tt.current, tt.keys)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ActivityRecorder counts the API requests the usage stats are computed from
+type ActivityRecorder interface {
+	Record(userID uint, status int)
+}
+
+// ActivityMiddleware records every request below prefix with the status it
+// was answered with and the authenticated user, 0 for anonymous requests.
+// It must be installed before ErrorHandler so the status includes the
+// errors it writes, and before the routes' AuthMiddleware sets the user.
+func ActivityMiddleware(recorder ActivityRecorder, prefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if !strings.HasPrefix(c.Request.URL.Path, prefix) {
+			return
+		}
+		id, _ := c.Get("userID")
+		userID, _ := id.(uint)
+		recorder.Record(userID, c.Writer.Status())
+	}
+}
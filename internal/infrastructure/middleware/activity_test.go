@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordedRequest struct {
+	userID uint
+	status int
+}
+
+type fakeActivityRecorder struct {
+	requests []recordedRequest
+}
+
+func (r *fakeActivityRecorder) Record(userID uint, status int) {
+	r.requests = append(r.requests, recordedRequest{userID: userID, status: status})
+}
+
+func TestActivityMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := &fakeActivityRecorder{}
+	r := gin.New()
+	r.Use(ActivityMiddleware(recorder, "/api/v1"), ErrorHandler(true))
+	r.GET("/api/v1/me", func(c *gin.Context) {
+		c.Set("userID", uint(7))
+		c.Status(http.StatusOK)
+	})
+	r.GET("/api/v1/broken", func(c *gin.Context) {
+		_ = c.Error(NewError(CodeUnavailable, "Market data is not available"))
+	})
+	r.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for _, path := range []string{"/api/v1/me", "/api/v1/broken", "/api/v1/missing", "/health"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	assert.Equal(t, []recordedRequest{
+		{userID: 7, status: http.StatusOK},
+		{userID: 0, status: http.StatusServiceUnavailable},
+		{userID: 0, status: http.StatusNotFound},
+	}, recorder.requests, "the error handler's status is recorded and requests outside the API are not")
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import "github.com/gin-gonic/gin"
+
+// AdminMiddleware only lets the configured administrators through. It must
+// run after AuthMiddleware, which sets the authenticated user ID.
+func AdminMiddleware(adminUserIDs []uint) gin.HandlerFunc {
+	admins := make(map[uint]bool, len(adminUserIDs))
+	for _, id := range adminUserIDs {
+		admins[id] = true
+	}
+
+	return func(c *gin.Context) {
+		userID, ok := c.Get("userID")
+		if !ok {
+			RespondError(c, NewError(CodeUnauthenticated, "User not authenticated"))
+			return
+		}
+
+		id, ok := userID.(uint)
+		if !ok || !admins[id] {
+			RespondError(c, NewError(CodeForbidden, "Admin access required"))
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(userID interface{}) *gin.Engine {
+		r := gin.New()
+		r.Use(func(c *gin.Context) {
+			if userID != nil {
+				c.Set("userID", userID)
+			}
+		})
+		r.GET("/admin", AdminMiddleware([]uint{1, 2}), func(c *gin.Context) { c.Status(http.StatusOK) })
+		return r
+	}
+
+	tests := []struct {
+		name       string
+		userID     interface{}
+		wantStatus int
+	}{
+		{"admin is allowed", uint(2), http.StatusOK},
+		{"regular user is forbidden", uint(3), http.StatusForbidden},
+		{"unauthenticated request is rejected", nil, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			newRouter(tt.userID).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin", nil))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
@@ -3,33 +3,225 @@
 package middleware
 
 import (
-	"net/http"
+	"context"
+	"errors"
+	"fmt"
 	"strings"
+	"sync"
 	"time"
 
+	"go-finance-advisor/internal/config"
+	"go-finance-advisor/internal/domain"
+
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var jwtSecret = []byte("your-secret-key")
+// ErrJWTNotConfigured is returned when tokens are used before ConfigureJWT is called
+var ErrJWTNotConfigured = errors.New("JWT signing is not configured")
+
+var (
+	jwtMu      sync.RWMutex
+	jwtManager *JWTManager
+	sessions   SessionValidator
+)
 
+// Claims are what access tokens carry. SessionID is the login session the
+// token was issued for; tokens without one are not tied to a session.
 type Claims struct {
-	UserID uint `json:"user_id"`
+	UserID    uint `json:"user_id"`
+	SessionID uint `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// GenerateToken creates a JWT token for a user
-func GenerateToken(userID uint) (string, error) {
+// SessionValidator checks that the session of an access token was not
+// revoked and records the device that used it
+type SessionValidator interface {
+	Validate(ctx context.Context, userID, sessionID uint, device domain.Device) error
+}
+
+// JWTManager signs and verifies tokens. New tokens are signed with the
+// current key and carry its ID in the "kid" header; previous keys are kept
+// for verification only so tokens issued before a rotation stay valid.
+type JWTManager struct {
+	method   jwt.SigningMethod
+	keyID    string
+	keys     map[string][]byte
+	issuer   string
+	audience string
+	expiry   time.Duration
+}
+
+// NewJWTManager validates the auth configuration and builds a manager from it
+func NewJWTManager(cfg config.AuthConfig) (*JWTManager, error) {
+	if cfg.JWTSecret == "" {
+		return nil, errors.New("JWT secret is not configured; set JWT_SECRET or auth.jwt_secret")
+	}
+
+	algorithm := cfg.JWTAlgorithm
+	if algorithm == "" {
+		algorithm = jwt.SigningMethodHS256.Alg()
+	}
+	method, ok := jwt.GetSigningMethod(algorithm).(*jwt.SigningMethodHMAC)
+	if !ok {
+		return nil, fmt.Errorf("unsupported JWT algorithm %q (use HS256, HS384 or HS512)", algorithm)
+	}
+
+	expiry := cfg.TokenExpiry.Std()
+	if expiry <= 0 {
+		return nil, errors.New("JWT token expiry must be positive")
+	}
+
+	keys := map[string][]byte{cfg.JWTKeyID: []byte(cfg.JWTSecret)}
+	for keyID, secret := range cfg.JWTPreviousKeys {
+		if keyID == cfg.JWTKeyID {
+			return nil, fmt.Errorf("previous JWT key %q has the same ID as the current key", keyID)
+		}
+		if keyID == "" || secret == "" {
+			return nil, errors.New("previous JWT keys need both an ID and a secret")
+		}
+		keys[keyID] = []byte(secret)
+	}
+
+	return &JWTManager{
+		method:   method,
+		keyID:    cfg.JWTKeyID,
+		keys:     keys,
+		issuer:   cfg.JWTIssuer,
+		audience: cfg.JWTAudience,
+		expiry:   expiry,
+	}, nil
+}
+
+// GenerateToken creates a signed token for a user
+func (m *JWTManager) GenerateToken(userID uint) (string, error) {
+	return m.GenerateSessionToken(userID, 0)
+}
+
+// GenerateSessionToken creates a signed token for a user's login session
+func (m *JWTManager) GenerateSessionToken(userID, sessionID uint) (string, error) {
+	now := time.Now()
 	claims := &Claims{
-		UserID: userID,
+		UserID:    userID,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    m.issuer,
+			ExpiresAt: jwt.NewNumericDate(now.Add(m.expiry)),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
+	if m.audience != "" {
+		claims.Audience = jwt.ClaimStrings{m.audience}
+	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	token := jwt.NewWithClaims(m.method, claims)
+	if m.keyID != "" {
+		token.Header["kid"] = m.keyID
+	}
+	return token.SignedString(m.keys[m.keyID])
+}
+
+// ParseToken verifies a token and returns its claims
+func (m *JWTManager) ParseToken(tokenString string) (*Claims, error) {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{m.method.Alg()}),
+		jwt.WithExpirationRequired(),
+	}
+	if m.issuer != "" {
+		options = append(options, jwt.WithIssuer(m.issuer))
+	}
+	if m.audience != "" {
+		options = append(options, jwt.WithAudience(m.audience))
+	}
+
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, m.keyFunc, options...)
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
+}
+
+// keyFunc picks the verification key from the "kid" header. Tokens without
+// a kid are only accepted with the current key.
+func (m *JWTManager) keyFunc(token *jwt.Token) (interface{}, error) {
+	keyID, _ := token.Header["kid"].(string)
+	key, ok := m.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", keyID)
+	}
+	return key, nil
+}
+
+// ConfigureJWT installs the manager used by GenerateToken and AuthMiddleware.
+// It fails if the configuration has no secret or is otherwise invalid.
+func ConfigureJWT(cfg config.AuthConfig) error {
+	manager, err := NewJWTManager(cfg)
+	if err != nil {
+		return err
+	}
+
+	jwtMu.Lock()
+	defer jwtMu.Unlock()
+	jwtManager = manager
+	return nil
+}
+
+// ConfigureSessions makes AuthMiddleware and ValidateSession reject access
+// tokens whose session was revoked
+func ConfigureSessions(validator SessionValidator) {
+	jwtMu.Lock()
+	defer jwtMu.Unlock()
+	sessions = validator
+}
+
+func currentJWTManager() *JWTManager {
+	jwtMu.RLock()
+	defer jwtMu.RUnlock()
+	return jwtManager
+}
+
+// GenerateToken creates a JWT token for a user
+func GenerateToken(userID uint) (string, error) {
+	manager := currentJWTManager()
+	if manager == nil {
+		return "", ErrJWTNotConfigured
+	}
+	return manager.GenerateToken(userID)
+}
+
+// GenerateSessionToken creates a JWT token for a user's login session
+func GenerateSessionToken(userID, sessionID uint) (string, error) {
+	manager := currentJWTManager()
+	if manager == nil {
+		return "", ErrJWTNotConfigured
+	}
+	return manager.GenerateSessionToken(userID, sessionID)
+}
+
+// ValidateSession checks the session of verified claims when sessions are
+// configured. It returns domain.ErrInvalidSession for revoked sessions.
+func ValidateSession(ctx context.Context, claims *Claims, device domain.Device) error {
+	jwtMu.RLock()
+	validator := sessions
+	jwtMu.RUnlock()
+	if validator == nil || claims.SessionID == 0 {
+		return nil
+	}
+	return validator.Validate(ctx, claims.UserID, claims.SessionID, device)
+}
+
+// ParseToken verifies a token with the configured manager, for transports
+// other than HTTP that cannot use AuthMiddleware
+func ParseToken(tokenString string) (*Claims, error) {
+	manager := currentJWTManager()
+	if manager == nil {
+		return nil, ErrJWTNotConfigured
+	}
+	return manager.ParseToken(tokenString)
 }
 
 // AuthMiddleware validates JWT tokens
@@ -37,30 +229,45 @@ func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
-			c.Abort()
+			RespondError(c, NewError(CodeUnauthenticated, "Authorization header required"))
 			return
 		}
 
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 		if tokenString == authHeader {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Bearer token required"})
-			c.Abort()
+			RespondError(c, NewError(CodeUnauthenticated, "Bearer token required"))
 			return
 		}
 
-		claims := &Claims{}
-		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			return jwtSecret, nil
-		})
+		manager := currentJWTManager()
+		if manager == nil {
+			RespondError(c, NewError(CodeInternal, "Authentication is not configured"))
+			return
+		}
 
-		if err != nil || !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-			c.Abort()
+		claims, err := manager.ParseToken(tokenString)
+		if err != nil {
+			RespondError(c, &APIError{Code: CodeInvalidToken, Message: "Invalid token", Cause: err})
+			return
+		}
+		device := domain.Device{UserAgent: c.Request.UserAgent(), IPAddress: c.ClientIP()}
+		err = ValidateSession(c.Request.Context(), claims, device)
+		if errors.Is(err, domain.ErrInvalidSession) {
+			RespondError(c, &APIError{Code: CodeInvalidToken, Message: "Session expired or revoked", Cause: err})
+			return
+		}
+		if err != nil {
+			RespondError(c, &APIError{Code: CodeInternal, Message: "Failed to check session", Cause: err})
 			return
 		}
 
 		c.Set("userID", claims.UserID)
+		// Services read the acting user from the request context, e.g. for the audit log
+		c.Request = c.Request.WithContext(domain.ContextWithActor(c.Request.Context(), domain.Actor{
+			UserID:    claims.UserID,
+			SessionID: claims.SessionID,
+			IPAddress: device.IPAddress,
+		}))
 		c.Next()
 	}
 }
@@ -0,0 +1,235 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/config"
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testAuthConfig() config.AuthConfig {
+	cfg := config.Default().Auth
+	cfg.JWTSecret = "current-secret"
+	cfg.JWTKeyID = "2024-06"
+	return cfg
+}
+
+func TestNewJWTManager(t *testing.T) {
+	t.Run("requires a secret", func(t *testing.T) {
+		_, err := NewJWTManager(config.Default().Auth)
+		assert.ErrorContains(t, err, "JWT secret is not configured")
+	})
+
+	t.Run("rejects non HMAC algorithms", func(t *testing.T) {
+		cfg := testAuthConfig()
+		cfg.JWTAlgorithm = "RS256"
+		_, err := NewJWTManager(cfg)
+		assert.ErrorContains(t, err, "unsupported JWT algorithm")
+	})
+
+	t.Run("rejects previous key reusing current key ID", func(t *testing.T) {
+		cfg := testAuthConfig()
+		cfg.JWTPreviousKeys = map[string]string{"2024-06": "old"}
+		_, err := NewJWTManager(cfg)
+		assert.Error(t, err)
+	})
+}
+
+func TestJWTManager_TokenRoundTrip(t *testing.T) {
+	cfg := testAuthConfig()
+	cfg.JWTAlgorithm = "HS512"
+	cfg.JWTAudience = "finance-web"
+	cfg.TokenExpiry = config.Duration(time.Hour)
+	manager, err := NewJWTManager(cfg)
+	require.NoError(t, err)
+
+	tokenString, err := manager.GenerateToken(42)
+	require.NoError(t, err)
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(tokenString, &Claims{})
+	require.NoError(t, err)
+	assert.Equal(t, "2024-06", parsed.Header["kid"])
+	assert.Equal(t, "HS512", parsed.Method.Alg())
+
+	claims, err := manager.ParseToken(tokenString)
+	require.NoError(t, err)
+	assert.Equal(t, uint(42), claims.UserID)
+	assert.Equal(t, "go-finance-advisor", claims.Issuer)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), claims.ExpiresAt.Time, 5*time.Second)
+
+	t.Run("rejects wrong audience", func(t *testing.T) {
+		other := cfg
+		other.JWTAudience = "mobile"
+		otherManager, err := NewJWTManager(other)
+		require.NoError(t, err)
+
+		_, err = otherManager.ParseToken(tokenString)
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects wrong issuer", func(t *testing.T) {
+		other := cfg
+		other.JWTIssuer = "someone-else"
+		otherManager, err := NewJWTManager(other)
+		require.NoError(t, err)
+
+		_, err = otherManager.ParseToken(tokenString)
+		assert.Error(t, err)
+	})
+}
+
+func TestJWTManager_KeyRotation(t *testing.T) {
+	oldCfg := testAuthConfig()
+	oldCfg.JWTSecret = "old-secret"
+	oldCfg.JWTKeyID = "2024-01"
+	oldManager, err := NewJWTManager(oldCfg)
+	require.NoError(t, err)
+	oldToken, err := oldManager.GenerateToken(7)
+	require.NoError(t, err)
+
+	t.Run("accepts tokens signed with a previous key", func(t *testing.T) {
+		cfg := testAuthConfig()
+		cfg.JWTPreviousKeys = map[string]string{"2024-01": "old-secret"}
+		manager, err := NewJWTManager(cfg)
+		require.NoError(t, err)
+
+		claims, err := manager.ParseToken(oldToken)
+		require.NoError(t, err)
+		assert.Equal(t, uint(7), claims.UserID)
+	})
+
+	t.Run("rejects tokens once the key is retired", func(t *testing.T) {
+		manager, err := NewJWTManager(testAuthConfig())
+		require.NoError(t, err)
+
+		_, err = manager.ParseToken(oldToken)
+		assert.Error(t, err)
+	})
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	require.NoError(t, ConfigureJWT(testAuthConfig()))
+
+	router := gin.New()
+	router.GET("/protected", AuthMiddleware(), func(c *gin.Context) {
+		userID, _ := c.Get("userID")
+		c.JSON(http.StatusOK, gin.H{"user_id": userID})
+	})
+
+	token, err := GenerateToken(5)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		header     string
+		wantStatus int
+	}{
+		{"valid token", "Bearer " + token, http.StatusOK},
+		{"missing header", "", http.StatusUnauthorized},
+		{"missing bearer prefix", token, http.StatusUnauthorized},
+		{"tampered token", "Bearer " + token + "x", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestAuthMiddleware_SetsActor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	require.NoError(t, ConfigureJWT(testAuthConfig()))
+
+	var actor domain.Actor
+	router := gin.New()
+	router.GET("/protected", AuthMiddleware(), func(c *gin.Context) {
+		actor, _ = domain.ActorFromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	token, err := GenerateToken(7)
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.RemoteAddr = "203.0.113.9:4242"
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, uint(7), actor.UserID)
+	assert.Equal(t, "203.0.113.9", actor.IPAddress)
+}
+
+// revokedSessions rejects the sessions in the set and records the devices
+// that used the others
+type revokedSessions struct {
+	revoked map[uint]bool
+	devices []domain.Device
+}
+
+func (r *revokedSessions) Validate(_ context.Context, _, sessionID uint, device domain.Device) error {
+	if r.revoked[sessionID] {
+		return domain.ErrInvalidSession
+	}
+	r.devices = append(r.devices, device)
+	return nil
+}
+
+func TestAuthMiddleware_Sessions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	require.NoError(t, ConfigureJWT(testAuthConfig()))
+	validator := &revokedSessions{revoked: map[uint]bool{2: true}}
+	ConfigureSessions(validator)
+	t.Cleanup(func() { ConfigureSessions(nil) })
+
+	var actor domain.Actor
+	router := gin.New()
+	router.GET("/protected", AuthMiddleware(), func(c *gin.Context) {
+		actor, _ = domain.ActorFromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+	request := func(token string) int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("User-Agent", "finance-ios/2.1")
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	active, err := GenerateSessionToken(7, 1)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, request(active))
+	assert.Equal(t, uint(1), actor.SessionID)
+	require.Len(t, validator.devices, 1)
+	assert.Equal(t, "finance-ios/2.1", validator.devices[0].UserAgent)
+
+	revoked, err := GenerateSessionToken(7, 2)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, request(revoked))
+
+	// Tokens issued without a session are not checked
+	legacy, err := GenerateToken(7)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, request(legacy))
+	assert.Len(t, validator.devices, 1)
+}
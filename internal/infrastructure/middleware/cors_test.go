@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORSMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(origins []string) *gin.Engine {
+		r := gin.New()
+		r.Use(CORSMiddleware(origins))
+		r.GET("/ping", func(c *gin.Context) { c.String(http.StatusOK, "pong") })
+		return r
+	}
+
+	t.Run("wildcard allows any origin", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		req.Header.Set("Origin", "https://anywhere.example.com")
+		newRouter([]string{"*"}).ServeHTTP(w, req)
+
+		assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("listed origin is echoed back", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		newRouter([]string{"https://app.example.com"}).ServeHTTP(w, req)
+
+		assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "Origin", w.Header().Get("Vary"))
+	})
+
+	t.Run("unlisted origin gets no allow header", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		req.Header.Set("Origin", "https://evil.example.com")
+		newRouter([]string{"https://app.example.com"}).ServeHTTP(w, req)
+
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("preflight returns no content", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodOptions, "/ping", nil)
+		newRouter([]string{"*"}).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+}
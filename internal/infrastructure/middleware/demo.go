@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DemoMiddleware keeps the demo user read-only: its requests may look at
+// its own data but not change anything, so every visitor of the demo sees
+// the same sample data. Routes of other users (a :userId other than the
+// demo user's) are forbidden to it. readOnlyRoutes lists mutating routes (as registered, e.g.
+// "/api/v1/users/:userId/budgets/simulate") that only compute an answer and
+// stay open. It must run after AuthMiddleware, which sets the authenticated
+// user ID; other users are not affected.
+func DemoMiddleware(demoUserID uint, readOnlyRoutes ...string) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(readOnlyRoutes))
+	for _, route := range readOnlyRoutes {
+		allowed[route] = true
+	}
+
+	demoUser := strconv.FormatUint(uint64(demoUserID), 10)
+
+	return func(c *gin.Context) {
+		userID, _ := c.Get("userID")
+		isDemo := userID == demoUserID
+		if param := c.Param("userId"); isDemo && param != "" && param != demoUser {
+			RespondError(c, NewError(CodeForbidden, "The demo can only see its own data"))
+			return
+		}
+
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		if isDemo && !allowed[c.FullPath()] {
+			RespondError(c, NewError(CodeForbidden, "The demo is read-only"))
+			return
+		}
+		c.Next()
+	}
+}
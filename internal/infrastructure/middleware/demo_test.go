@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDemoMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(userID uint) *gin.Engine {
+		r := gin.New()
+		r.Use(func(c *gin.Context) { c.Set("userID", userID) }, DemoMiddleware(7, "/budgets/simulate"))
+		ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+		r.GET("/budgets", ok)
+		r.GET("/users/:userId/budgets", ok)
+		r.POST("/budgets", ok)
+		r.DELETE("/budgets/:id", ok)
+		r.POST("/budgets/simulate", ok)
+		return r
+	}
+
+	tests := []struct {
+		name       string
+		userID     uint
+		method     string
+		path       string
+		wantStatus int
+	}{
+		{"demo user can read", 7, http.MethodGet, "/budgets", http.StatusOK},
+		{"demo user cannot create", 7, http.MethodPost, "/budgets", http.StatusForbidden},
+		{"demo user cannot delete", 7, http.MethodDelete, "/budgets/3", http.StatusForbidden},
+		{"demo user can use read-only routes", 7, http.MethodPost, "/budgets/simulate", http.StatusOK},
+		{"other users are not restricted", 8, http.MethodPost, "/budgets", http.StatusOK},
+		{"demo user can read its own routes", 7, http.MethodGet, "/users/7/budgets", http.StatusOK},
+		{"demo user cannot read other users' routes", 7, http.MethodGet, "/users/8/budgets", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			newRouter(tt.userID).ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
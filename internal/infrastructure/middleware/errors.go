@@ -0,0 +1,181 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorCode identifies the kind of an API error for clients, e.g. FINANCE-1042.
+// The first digit groups the codes: 1 for bad requests, 2 for authentication,
+// 3 for access, 4 for missing or conflicting resources and 5 for server errors.
+type ErrorCode string
+
+const (
+	CodeBadRequest         ErrorCode = "FINANCE-1000"
+	CodeInvalidID          ErrorCode = "FINANCE-1001"
+	CodeInvalidDate        ErrorCode = "FINANCE-1002"
+	CodeInvalidBody        ErrorCode = "FINANCE-1003"
+	CodePayloadTooLarge    ErrorCode = "FINANCE-1013"
+	CodeUnprocessable      ErrorCode = "FINANCE-1022"
+	CodeRateLimited        ErrorCode = "FINANCE-1029"
+	CodeValidation         ErrorCode = "FINANCE-1042"
+	CodeUnauthenticated    ErrorCode = "FINANCE-2001"
+	CodeInvalidToken       ErrorCode = "FINANCE-2002"
+	CodeInvalidCredentials ErrorCode = "FINANCE-2003"
+	CodeForbidden          ErrorCode = "FINANCE-3001"
+	CodePlanLimit          ErrorCode = "FINANCE-3002"
+	CodeQuotaExceeded      ErrorCode = "FINANCE-3029"
+	CodeNotFound           ErrorCode = "FINANCE-4004"
+	CodeConflict           ErrorCode = "FINANCE-4009"
+	CodeInternal           ErrorCode = "FINANCE-5000"
+	CodeUnavailable        ErrorCode = "FINANCE-5003"
+)
+
+// codeStatus maps every error code to the HTTP status it is answered with
+var codeStatus = map[ErrorCode]int{
+	CodeBadRequest:         http.StatusBadRequest,
+	CodeInvalidID:          http.StatusBadRequest,
+	CodeInvalidDate:        http.StatusBadRequest,
+	CodeInvalidBody:        http.StatusBadRequest,
+	CodePayloadTooLarge:    http.StatusRequestEntityTooLarge,
+	CodeUnprocessable:      http.StatusUnprocessableEntity,
+	CodeRateLimited:        http.StatusTooManyRequests,
+	CodeValidation:         http.StatusUnprocessableEntity,
+	CodeUnauthenticated:    http.StatusUnauthorized,
+	CodeInvalidToken:       http.StatusUnauthorized,
+	CodeInvalidCredentials: http.StatusUnauthorized,
+	CodeForbidden:          http.StatusForbidden,
+	CodePlanLimit:          http.StatusForbidden,
+	CodeQuotaExceeded:      http.StatusTooManyRequests,
+	CodeNotFound:           http.StatusNotFound,
+	CodeConflict:           http.StatusConflict,
+	CodeInternal:           http.StatusInternalServerError,
+	CodeUnavailable:        http.StatusServiceUnavailable,
+}
+
+// Status returns the HTTP status the code is answered with
+func (c ErrorCode) Status() int {
+	if status, ok := codeStatus[c]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// errorDetailsKey marks requests whose error responses may include causes
+const errorDetailsKey = "errorDetails"
+
+// APIError is an error answered to the client: a code, a message that is safe
+// to show and the cause, which only responses outside production include
+type APIError struct {
+	Code    ErrorCode
+	Message string
+	Fields  []domain.FieldError
+	Cause   error
+}
+
+// NewError builds an API error without a cause
+func NewError(code ErrorCode, message string) *APIError {
+	return &APIError{Code: code, Message: message}
+}
+
+// InternalError builds a server error that shows message instead of its cause
+func InternalError(message string, cause error) *APIError {
+	return &APIError{Code: CodeInternal, Message: message, Cause: cause}
+}
+
+func (e *APIError) Error() string {
+	if e.Cause == nil {
+		return string(e.Code) + " " + e.Message
+	}
+	return string(e.Code) + " " + e.Message + ": " + e.Cause.Error()
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Cause
+}
+
+// ErrorResponse is the body of every error response
+type ErrorResponse struct {
+	Code   ErrorCode           `json:"code"`
+	Error  string              `json:"error"`
+	Fields []domain.FieldError `json:"fields,omitempty"`
+	Detail string              `json:"detail,omitempty"`
+}
+
+// AsAPIError returns the API error err carries. Validation errors become 422
+// responses listing their fields; anything else is an internal error whose
+// cause is hidden.
+func AsAPIError(err error) *APIError {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+	var invalid *domain.ValidationError
+	if errors.As(err, &invalid) {
+		return &APIError{Code: CodeValidation, Message: domain.ErrValidation.Error(), Fields: invalid.Fields}
+	}
+	return InternalError("Internal server error", err)
+}
+
+// RespondError aborts the request with the error's status and an
+// ErrorResponse, its message in the language the request accepts. The error
+// is also recorded on the context for ErrorHandler to log.
+func RespondError(c *gin.Context, err error) {
+	apiErr := AsAPIError(err)
+	_ = c.Error(apiErr)
+
+	response := ErrorResponse{Code: apiErr.Code, Error: Localizer(c).Message(sentenceCase(apiErr.Message)), Fields: apiErr.Fields}
+	if apiErr.Cause != nil && c.GetBool(errorDetailsKey) {
+		response.Detail = apiErr.Cause.Error()
+	}
+	c.AbortWithStatusJSON(apiErr.Code.Status(), response)
+}
+
+// ErrorHandler answers errors that handlers recorded with c.Error but did not
+// respond to, and panics, with an ErrorResponse, and logs server errors. In
+// production the causes of server errors are left out of responses.
+func ErrorHandler(production bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(errorDetailsKey, !production)
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				err := InternalError("Internal server error", fmt.Errorf("panic: %v", recovered))
+				log.Printf("%s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+				if !c.Writer.Written() {
+					RespondError(c, err)
+				}
+			}
+		}()
+
+		c.Next()
+
+		for _, ginErr := range c.Errors {
+			if apiErr := AsAPIError(ginErr.Err); apiErr.Code.Status() >= http.StatusInternalServerError {
+				log.Printf("%s %s: %v", c.Request.Method, c.Request.URL.Path, apiErr)
+			}
+		}
+		if len(c.Errors) > 0 && !c.Writer.Written() {
+			RespondError(c, c.Errors.Last().Err)
+		}
+	}
+}
+
+// sentenceCase starts the message with a capital letter, so messages taken
+// from errors read like the rest
+func sentenceCase(message string) string {
+	message = strings.TrimSpace(message)
+	first, size := utf8.DecodeRuneInString(message)
+	if first == utf8.RuneError {
+		return message
+	}
+	return string(unicode.ToUpper(first)) + message[size:]
+}
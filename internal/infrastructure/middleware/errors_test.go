@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	serveIn := func(language string, production bool, handler gin.HandlerFunc) (*httptest.ResponseRecorder, ErrorResponse) {
+		r := gin.New()
+		r.Use(ErrorHandler(production))
+		r.GET("/fail", handler)
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/fail", nil)
+		req.Header.Set("Accept-Language", language)
+		r.ServeHTTP(w, req)
+
+		var response ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w, response
+	}
+	serve := func(production bool, handler gin.HandlerFunc) (*httptest.ResponseRecorder, ErrorResponse) {
+		return serveIn("", production, handler)
+	}
+
+	t.Run("API errors keep their code and status", func(t *testing.T) {
+		w, response := serve(true, func(c *gin.Context) {
+			RespondError(c, NewError(CodeNotFound, "budget not found"))
+		})
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, CodeNotFound, response.Code)
+		assert.Equal(t, "Budget not found", response.Error)
+	})
+
+	t.Run("messages are in the language the request accepts", func(t *testing.T) {
+		_, response := serveIn("tr-TR,tr;q=0.9", true, func(c *gin.Context) {
+			RespondError(c, NewError(CodeNotFound, "budget not found"))
+		})
+		assert.Equal(t, "Bütçe bulunamadı", response.Error)
+
+		_, response = serveIn("de", true, func(c *gin.Context) {
+			RespondError(c, InternalError("Failed to create budget", errors.New("database is locked")))
+		})
+		assert.Equal(t, "Failed to create budget", response.Error, "untranslated messages stay English")
+	})
+
+	t.Run("validation errors list their fields", func(t *testing.T) {
+		w, response := serve(true, func(c *gin.Context) {
+			RespondError(c, (&domain.Budget{}).Validate())
+		})
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Equal(t, CodeValidation, response.Code)
+		assert.NotEmpty(t, response.Fields)
+	})
+
+	t.Run("production hides the cause of server errors", func(t *testing.T) {
+		w, response := serve(true, func(c *gin.Context) {
+			RespondError(c, InternalError("Failed to create budget", errors.New("database is locked")))
+		})
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, CodeInternal, response.Code)
+		assert.Equal(t, "Failed to create budget", response.Error)
+		assert.Empty(t, response.Detail)
+		assert.NotContains(t, w.Body.String(), "database is locked")
+	})
+
+	t.Run("development shows the cause of server errors", func(t *testing.T) {
+		_, response := serve(false, func(c *gin.Context) {
+			RespondError(c, InternalError("Failed to create budget", errors.New("database is locked")))
+		})
+
+		assert.Equal(t, "database is locked", response.Detail)
+	})
+
+	t.Run("recorded errors are answered after the handler", func(t *testing.T) {
+		w, response := serve(true, func(c *gin.Context) {
+			_ = c.Error(errors.New("sql: connection refused"))
+		})
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, CodeInternal, response.Code)
+		assert.Equal(t, "Internal server error", response.Error)
+		assert.NotContains(t, w.Body.String(), "connection refused")
+	})
+
+	t.Run("panics are answered as server errors", func(t *testing.T) {
+		w, response := serve(true, func(c *gin.Context) {
+			panic("nil map")
+		})
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, CodeInternal, response.Code)
+	})
+
+	t.Run("without the middleware causes stay hidden", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		RespondError(c, errors.New("disk full"))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.NotContains(t, w.Body.String(), "disk full")
+	})
+}
+
+func TestErrorCode_Status(t *testing.T) {
+	assert.Equal(t, http.StatusBadRequest, CodeInvalidID.Status())
+	assert.Equal(t, http.StatusUnauthorized, CodeInvalidToken.Status())
+	assert.Equal(t, http.StatusForbidden, CodeForbidden.Status())
+	assert.Equal(t, http.StatusServiceUnavailable, CodeUnavailable.Status())
+	assert.Equal(t, http.StatusTooManyRequests, CodeRateLimited.Status())
+	assert.Equal(t, http.StatusInternalServerError, ErrorCode("FINANCE-9999").Status())
+}
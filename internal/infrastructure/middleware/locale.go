@@ -0,0 +1,16 @@
+package middleware
+
+import (
+	"go-finance-advisor/internal/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Localizer returns the localizer of the language the request's
+// Accept-Language header prefers, English when it names none we support
+func Localizer(c *gin.Context) *i18n.Localizer {
+	if c.Request == nil {
+		return i18n.New("")
+	}
+	return i18n.New(i18n.Match(c.GetHeader("Accept-Language")))
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OwnUserMiddleware forbids the routes under routePrefix (as registered,
+// e.g. "/api/v1/users/:userId") whose :userId is not the authenticated
+// user, so a handler that forgets to compare them cannot serve another
+// user's data. Malformed IDs are left to the handlers to reject. It must run
+// after AuthMiddleware, which sets the authenticated user ID.
+func OwnUserMiddleware(routePrefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !strings.HasPrefix(c.FullPath(), routePrefix) {
+			c.Next()
+			return
+		}
+		pathUserID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+		if err != nil {
+			c.Next()
+			return
+		}
+		if userID, ok := c.Get("userID"); !ok || userID != uint(pathUserID) {
+			RespondError(c, NewError(CodeForbidden, "Access denied"))
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOwnUserMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) { c.Set("userID", uint(1)) }, OwnUserMiddleware("/users/:userId"))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/users/:userId/transactions/trash", ok)
+	r.DELETE("/households/:householdId/members/:userId", ok)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+	}{
+		{"own routes are allowed", http.MethodGet, "/users/1/transactions/trash", http.StatusOK},
+		{"other users' routes are forbidden", http.MethodGet, "/users/2/transactions/trash", http.StatusForbidden},
+		{"malformed IDs are left to the handler", http.MethodGet, "/users/me/transactions/trash", http.StatusOK},
+		{"other routes naming a user are not affected", http.MethodDelete, "/households/3/members/2", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
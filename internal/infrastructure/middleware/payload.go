@@ -0,0 +1,221 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultFieldLimits are the longest names, descriptions and notes accepted,
+// in characters, the same limits the domain enforces
+var DefaultFieldLimits = map[string]int{
+	"description": 255,
+	"name":        100,
+	"first_name":  100,
+	"last_name":   100,
+	"payee":       100,
+	"notes":       2000,
+}
+
+// bodyKey holds the request body once a middleware has read it
+const bodyKey = "requestBody"
+
+// BodyLimitMiddleware answers JSON bodies larger than maxBytes with 413
+// before any handler decodes them. Multipart uploads are left to their
+// handlers, which enforce their own per-file limits.
+func BodyLimitMiddleware(maxBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || c.Request.Body == http.NoBody || !isJSON(c.Request) {
+			c.Next()
+			return
+		}
+		if c.Request.ContentLength > maxBytes {
+			RespondError(c, NewError(CodePayloadTooLarge, fmt.Sprintf("Request body exceeds %d bytes", maxBytes)))
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxBytes+1))
+		if err != nil {
+			RespondError(c, NewError(CodeInvalidBody, "Failed to read request body"))
+			return
+		}
+		if int64(len(body)) > maxBytes {
+			RespondError(c, NewError(CodePayloadTooLarge, fmt.Sprintf("Request body exceeds %d bytes", maxBytes)))
+			return
+		}
+		setBody(c, body)
+		c.Next()
+	}
+}
+
+// SanitizeMiddleware cleans the string fields of JSON bodies named in limits,
+// at any depth: surrounding whitespace is trimmed and control characters
+// other than newlines and tabs are dropped. Fields still longer than their
+// limit afterwards are answered with 422.
+func SanitizeMiddleware(limits map[string]int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || c.Request.Body == http.NoBody || !isJSON(c.Request) {
+			c.Next()
+			return
+		}
+		body, ok := readBody(c)
+		if !ok {
+			return
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		var payload any
+		if err := decoder.Decode(&payload); err != nil {
+			// Malformed bodies are left for the handler to reject
+			setBody(c, body)
+			c.Next()
+			return
+		}
+
+		var fields []domain.FieldError
+		changed := sanitizeValue(payload, limits, &fields)
+		if len(fields) > 0 {
+			RespondError(c, &domain.ValidationError{Fields: fields})
+			return
+		}
+		if changed {
+			if body, ok = encodeBody(c, payload); !ok {
+				return
+			}
+		}
+		setBody(c, body)
+		c.Next()
+	}
+}
+
+// StrictJSON rejects JSON bodies with fields that the request type does not
+// declare, so misspelt fields fail instead of being ignored. prototype is a
+// value of the request type the handler binds.
+func StrictJSON(prototype any) gin.HandlerFunc {
+	requestType := reflect.TypeOf(prototype)
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || c.Request.Body == http.NoBody || !isJSON(c.Request) {
+			c.Next()
+			return
+		}
+		body, ok := readBody(c)
+		if !ok {
+			return
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.DisallowUnknownFields()
+		err := decoder.Decode(reflect.New(requestType).Interface())
+		var unknown string
+		if err != nil && strings.HasPrefix(err.Error(), "json: unknown field ") {
+			unknown = strings.TrimPrefix(err.Error(), "json: unknown field ")
+		}
+		if unknown != "" {
+			RespondError(c, NewError(CodeInvalidBody, "Unknown field "+unknown))
+			return
+		}
+		// Other decoding errors are left for the handler to report
+		setBody(c, body)
+		c.Next()
+	}
+}
+
+// sanitizeValue cleans the strings of v under the keys in limits, recording
+// fields over their limit, and reports whether anything changed
+func sanitizeValue(v any, limits map[string]int, fields *[]domain.FieldError) bool {
+	changed := false
+	switch value := v.(type) {
+	case map[string]any:
+		for k, item := range value {
+			if s, ok := item.(string); ok {
+				limit, limited := limits[k]
+				if !limited {
+					continue
+				}
+				clean := sanitizeString(s)
+				if clean != s {
+					value[k] = clean
+					changed = true
+				}
+				if utf8.RuneCountInString(clean) > limit {
+					*fields = append(*fields, domain.FieldError{
+						Field: k, Message: fmt.Sprintf("must be at most %d characters", limit),
+					})
+				}
+				continue
+			}
+			changed = sanitizeValue(item, limits, fields) || changed
+		}
+	case []any:
+		for _, item := range value {
+			changed = sanitizeValue(item, limits, fields) || changed
+		}
+	}
+	return changed
+}
+
+// sanitizeString trims surrounding whitespace and drops control characters
+// other than newlines and tabs
+func sanitizeString(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) && r != '\n' && r != '\t' {
+			return -1
+		}
+		return r
+	}, s)
+	return strings.TrimSpace(s)
+}
+
+// readBody returns the request body, as read by BodyLimitMiddleware when it ran
+func readBody(c *gin.Context) ([]byte, bool) {
+	if body, ok := c.Get(bodyKey); ok {
+		return body.([]byte), true
+	}
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		RespondError(c, NewError(CodeInvalidBody, "Failed to read request body"))
+		return nil, false
+	}
+	return body, true
+}
+
+// setBody replaces the request body so later middleware and handlers read body
+func setBody(c *gin.Context, body []byte) {
+	c.Set(bodyKey, body)
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	c.Request.ContentLength = int64(len(body))
+}
+
+func encodeBody(c *gin.Context, payload any) ([]byte, bool) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(payload); err != nil {
+		RespondError(c, InternalError("Failed to sanitize request body", err))
+		return nil, false
+	}
+	return buf.Bytes(), true
+}
+
+// isJSON reports whether the request declares a JSON body; requests without
+// a content type are read as JSON too, as gin's JSON binding does
+func isJSON(r *http.Request) bool {
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"))
+}
@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBodyLimitMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(BodyLimitMiddleware(32))
+	r.POST("/echo", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, string(body))
+	})
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantStatus  int
+	}{
+		{"small JSON body", "application/json", `{"name":"Rent"}`, http.StatusOK},
+		{"oversized JSON body", "application/json", `{"name":"` + strings.Repeat("x", 40) + `"}`, http.StatusRequestEntityTooLarge},
+		{"JSON without a content type", "", strings.Repeat("x", 40), http.StatusRequestEntityTooLarge},
+		{"multipart uploads are left to handlers", "multipart/form-data; boundary=x", strings.Repeat("x", 40), http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, tt.body, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestSanitizeMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(BodyLimitMiddleware(1<<10), SanitizeMiddleware(map[string]int{"description": 10, "name": 20}))
+	r.POST("/echo", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.Data(http.StatusOK, "application/json", body)
+	})
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("trims and strips control characters at any depth", func(t *testing.T) {
+		w := post(`{"description":"  Coffee\u0000\u001b ","amount":12345678901234567,` +
+			`"items":[{"name":"\tBeans\r"}],"notes":"  kept  "}`)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"amount":12345678901234567`)
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, "Coffee", body["description"])
+		assert.Equal(t, "Beans", body["items"].([]any)[0].(map[string]any)["name"])
+		assert.Equal(t, "  kept  ", body["notes"])
+	})
+
+	t.Run("rejects fields over their limit", func(t *testing.T) {
+		w := post(`{"description":"` + strings.Repeat("é", 11) + `"}`)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		var response ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Fields, 1)
+		assert.Equal(t, "description", response.Fields[0].Field)
+	})
+
+	t.Run("counts characters rather than bytes", func(t *testing.T) {
+		w := post(`{"description":"` + strings.Repeat("é", 10) + `"}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("leaves malformed bodies to the handler", func(t *testing.T) {
+		w := post(`{"description": `)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `{"description": `, w.Body.String())
+	})
+}
+
+func TestStrictJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	type request struct {
+		Amount      float64 `json:"amount"`
+		Description string  `json:"description"`
+	}
+	r := gin.New()
+	r.POST("/strict", StrictJSON(request{}), func(c *gin.Context) {
+		var req request
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		c.JSON(http.StatusOK, req)
+	})
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantError  string
+	}{
+		{"declared fields", `{"amount":12.5,"description":"Rent"}`, http.StatusOK, ""},
+		{"unknown field", `{"amount":12.5,"descripton":"Rent"}`, http.StatusBadRequest, `Unknown field "descripton"`},
+		{"malformed body reaches the handler", `{"amount":"twelve"}`, http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/strict", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantError != "" {
+				var response ErrorResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, CodeInvalidBody, response.Code)
+				assert.Equal(t, tt.wantError, response.Error)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"strconv"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QuotaConsumer counts uses of the daily quotas of the users' plans
+type QuotaConsumer interface {
+	Consume(ctx context.Context, userID uint, quota string) (*domain.QuotaUsage, error)
+}
+
+// QuotaMiddleware counts every request against the authenticated user's
+// daily quota and answers requests beyond it with 429 until the quota resets
+// at midnight UTC. The X-Quota-Limit and X-Quota-Remaining headers tell
+// clients how much is left. The limit is soft: when the quota cannot be
+// counted, the request is let through. It must run after AuthMiddleware,
+// which sets the authenticated user ID.
+func QuotaMiddleware(quotas QuotaConsumer, quota string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := c.Get("userID")
+		id, isID := userID.(uint)
+		if !ok || !isID {
+			RespondError(c, NewError(CodeUnauthenticated, "User not authenticated"))
+			return
+		}
+
+		usage, err := quotas.Consume(c.Request.Context(), id, quota)
+		if usage != nil && usage.Remaining != nil {
+			c.Header("X-Quota-Limit", strconv.Itoa(usage.Limit))
+			c.Header("X-Quota-Remaining", strconv.Itoa(*usage.Remaining))
+		}
+		switch {
+		case errors.Is(err, domain.ErrQuotaExceeded):
+			retryAfter := int(math.Ceil(time.Until(usage.ResetsAt).Seconds()))
+			c.Header("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+			RespondError(c, NewError(CodeQuotaExceeded, "The daily quota of your plan is used up, please try again tomorrow"))
+			return
+		case err != nil:
+			log.Printf("counting the %s quota of user %d failed: %v", quota, id, err)
+		}
+		c.Next()
+	}
+}
+
+// FeatureChecker tells which features the users' plans include
+type FeatureChecker interface {
+	HasFeature(ctx context.Context, userID uint, feature string) (bool, error)
+}
+
+// FeatureMiddleware answers requests of users whose plan does not include
+// the feature with 403. Like QuotaMiddleware it lets requests through when
+// the plan cannot be read, and must run after AuthMiddleware.
+func FeatureMiddleware(features FeatureChecker, feature string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := c.Get("userID")
+		id, isID := userID.(uint)
+		if !ok || !isID {
+			RespondError(c, NewError(CodeUnauthenticated, "User not authenticated"))
+			return
+		}
+
+		included, err := features.HasFeature(c.Request.Context(), id, feature)
+		if err != nil {
+			log.Printf("checking whether the plan of user %d includes %s failed: %v", id, feature, err)
+		} else if !included {
+			RespondError(c, NewError(CodePlanLimit, "This feature needs the premium plan"))
+			return
+		}
+		c.Next()
+	}
+}
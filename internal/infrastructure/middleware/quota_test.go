@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeQuotas allows limit uses of every quota, or fails with err when set
+type fakeQuotas struct {
+	limit int
+	used  int
+	err   error
+}
+
+func (f *fakeQuotas) Consume(_ context.Context, _ uint, quota string) (*domain.QuotaUsage, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	usage := &domain.QuotaUsage{Quota: quota, Limit: f.limit, ResetsAt: time.Now().Add(time.Hour)}
+	if f.used >= f.limit {
+		remaining := 0
+		usage.Used, usage.Remaining = f.used, &remaining
+		return usage, domain.ErrQuotaExceeded
+	}
+	f.used++
+	remaining := f.limit - f.used
+	usage.Used, usage.Remaining = f.used, &remaining
+	return usage, nil
+}
+
+func TestQuotaMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(quotas QuotaConsumer) *gin.Engine {
+		r := gin.New()
+		r.Use(func(c *gin.Context) { c.Set("userID", uint(1)) }, QuotaMiddleware(quotas, domain.QuotaAIRequests))
+		r.GET("/ai", func(c *gin.Context) { c.Status(http.StatusOK) })
+		return r
+	}
+	request := func(r *gin.Engine) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ai", nil))
+		return w
+	}
+
+	t.Run("requests within the quota pass", func(t *testing.T) {
+		r := newRouter(&fakeQuotas{limit: 2})
+		assert.Equal(t, http.StatusOK, request(r).Code)
+		w := request(r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "2", w.Header().Get("X-Quota-Limit"))
+		assert.Equal(t, "0", w.Header().Get("X-Quota-Remaining"))
+
+		w = request(r)
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Contains(t, w.Body.String(), string(CodeQuotaExceeded))
+		assert.Equal(t, "3600", w.Header().Get("Retry-After"))
+	})
+
+	t.Run("requests pass when the quota cannot be counted", func(t *testing.T) {
+		w := request(newRouter(&fakeQuotas{err: errors.New("database is locked")}))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("X-Quota-Remaining"))
+	})
+}
+
+// fakeFeatures includes the listed features, or fails with err when set
+type fakeFeatures struct {
+	features []string
+	err      error
+}
+
+func (f *fakeFeatures) HasFeature(_ context.Context, _ uint, feature string) (bool, error) {
+	return slices.Contains(f.features, feature), f.err
+}
+
+func TestFeatureMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	request := func(features FeatureChecker) *httptest.ResponseRecorder {
+		r := gin.New()
+		r.Use(func(c *gin.Context) { c.Set("userID", uint(1)) }, FeatureMiddleware(features, domain.FeatureAIAdvisor))
+		r.GET("/advice", func(c *gin.Context) { c.Status(http.StatusOK) })
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/advice", nil))
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, request(&fakeFeatures{features: domain.Features}).Code)
+
+	w := request(&fakeFeatures{features: []string{domain.FeatureBankSync}})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), string(CodePlanLimit))
+
+	w = request(&fakeFeatures{err: errors.New("database is locked")})
+	assert.Equal(t, http.StatusOK, w.Code, "requests pass when the plan cannot be read")
+}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// rateWindow counts a client's requests in the current window
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// rateLimiter allows each client limit requests per window
+type rateLimiter struct {
+	mu        sync.Mutex
+	limit     int
+	window    time.Duration
+	clients   map[string]*rateWindow
+	nextSweep time.Time
+	now       func() time.Time
+}
+
+// allow counts a request of the client and reports whether it is within the
+// limit, how many requests remain and when the window resets
+func (l *rateLimiter) allow(client string) (ok bool, remaining int, reset time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.After(l.nextSweep) {
+		for key, w := range l.clients {
+			if now.Sub(w.start) >= l.window {
+				delete(l.clients, key)
+			}
+		}
+		l.nextSweep = now.Add(l.window)
+	}
+
+	w, found := l.clients[client]
+	if !found || now.Sub(w.start) >= l.window {
+		w = &rateWindow{start: now}
+		l.clients[client] = w
+	}
+	reset = w.start.Add(l.window)
+	if w.count >= l.limit {
+		return false, 0, reset
+	}
+	w.count++
+	return true, l.limit - w.count, reset
+}
+
+// RateLimitMiddleware answers clients, told apart by IP address, that send
+// more than limit requests within window with 429 until the window is over.
+// It is meant for unauthenticated routes; the counts are kept in memory, so
+// every instance of the server limits on its own. The address is the one
+// gin resolves, which only comes from X-Forwarded-For when the request was
+// sent by one of the engine's trusted proxies.
+func RateLimitMiddleware(limit int, window time.Duration) gin.HandlerFunc {
+	return newRateLimitMiddleware(limit, window, time.Now)
+}
+
+func newRateLimitMiddleware(limit int, window time.Duration, now func() time.Time) gin.HandlerFunc {
+	limiter := &rateLimiter{limit: limit, window: window, clients: make(map[string]*rateWindow), now: now}
+
+	return func(c *gin.Context) {
+		ok, remaining, reset := limiter.allow(c.ClientIP())
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		if !ok {
+			retryAfter := int(math.Ceil(reset.Sub(now()).Seconds()))
+			c.Header("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+			RespondError(c, NewError(CodeRateLimited, "Too many requests, please try again later"))
+			return
+		}
+		c.Next()
+	}
+}
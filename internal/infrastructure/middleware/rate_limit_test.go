@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	r := gin.New()
+	r.Use(newRateLimitMiddleware(2, time.Minute, func() time.Time { return now }))
+	r.GET("/public", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	request := func(ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/public", nil)
+		req.RemoteAddr = ip + ":1234"
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, request("10.0.0.1").Code)
+	w := request("10.0.0.1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+
+	now = now.Add(20 * time.Second)
+	w = request("10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "40", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), string(CodeRateLimited))
+
+	assert.Equal(t, http.StatusOK, request("10.0.0.2").Code, "other clients have their own limit")
+
+	now = now.Add(40 * time.Second)
+	assert.Equal(t, http.StatusOK, request("10.0.0.1").Code, "the limit resets with the window")
+}
+
+func TestRateLimitMiddleware_ForwardedFor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	request := func(r *gin.Engine, remoteIP, forwardedFor string) int {
+		req := httptest.NewRequest(http.MethodGet, "/public", nil)
+		req.RemoteAddr = remoteIP + ":1234"
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+	newRouter := func(trustedProxies []string) *gin.Engine {
+		r := gin.New()
+		assert.NoError(t, r.SetTrustedProxies(trustedProxies))
+		r.Use(newRateLimitMiddleware(1, time.Minute, func() time.Time { return now }))
+		r.GET("/public", func(c *gin.Context) { c.Status(http.StatusOK) })
+		return r
+	}
+
+	r := newRouter(nil)
+	assert.Equal(t, http.StatusOK, request(r, "203.0.113.7", "198.51.100.1"))
+	assert.Equal(t, http.StatusTooManyRequests, request(r, "203.0.113.7", "198.51.100.2"),
+		"a client cannot escape the limit by making up X-Forwarded-For")
+
+	r = newRouter([]string{"10.0.0.0/8"})
+	assert.Equal(t, http.StatusOK, request(r, "10.0.0.1", "198.51.100.1"))
+	assert.Equal(t, http.StatusOK, request(r, "10.0.0.1", "198.51.100.2"), "clients behind a trusted proxy have their own limit")
+	assert.Equal(t, http.StatusTooManyRequests, request(r, "10.0.0.1", "198.51.100.1"))
+}
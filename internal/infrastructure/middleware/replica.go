@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReplicaReadsMiddleware lets the reads of read-heavy routes, such as
+// analytics, reports and exports, be served by read replicas when they are
+// configured. Whatever such a request writes still goes to the primary.
+func ReplicaReadsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(domain.ContextPreferringReplica(c.Request.Context()))
+		c.Next()
+	}
+}
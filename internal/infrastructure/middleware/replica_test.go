@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplicaReadsMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	var prefers bool
+	router.GET("/reads", ReplicaReadsMiddleware(), func(c *gin.Context) {
+		prefers = domain.PrefersReplica(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+	router.GET("/other", func(c *gin.Context) {
+		prefers = domain.PrefersReplica(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/reads", http.NoBody))
+	assert.True(t, prefers)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/other", http.NoBody))
+	assert.False(t, prefers)
+}
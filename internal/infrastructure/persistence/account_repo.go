@@ -0,0 +1,143 @@
+package persistence
+
+import (
+	"context"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/encryption"
+
+	"gorm.io/gorm"
+)
+
+// rotationBatchSize is how many rows key rotation re-encrypts at a time
+const rotationBatchSize = 100
+
+// AccountRepository is the GORM implementation of domain.AccountRepository.
+// Account numbers are encrypted with Cipher before they are written and
+// decrypted when read; a nil Cipher stores them in plaintext.
+type AccountRepository struct {
+	DB     *gorm.DB
+	Cipher *encryption.Cipher
+}
+
+var _ domain.AccountRepository = (*AccountRepository)(nil)
+
+func NewAccountRepository(db *gorm.DB, cipher *encryption.Cipher) *AccountRepository {
+	return &AccountRepository{DB: db, Cipher: cipher}
+}
+
+func (r *AccountRepository) Create(ctx context.Context, account *domain.Account) error {
+	return r.write(ctx, account, func(sealed *domain.Account) error {
+		return Conn(ctx, r.DB).Create(sealed).Error
+	})
+}
+
+func (r *AccountRepository) GetByID(ctx context.Context, id uint) (*domain.Account, error) {
+	var account domain.Account
+	if err := Conn(ctx, r.DB).First(&account, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	if err := r.open(ctx, &account); err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// Update saves all fields of an account
+func (r *AccountRepository) Update(ctx context.Context, account *domain.Account) error {
+	return r.write(ctx, account, func(sealed *domain.Account) error {
+		return Conn(ctx, r.DB).Save(sealed).Error
+	})
+}
+
+func (r *AccountRepository) Delete(ctx context.Context, id uint) error {
+	return Conn(ctx, r.DB).Delete(&domain.Account{}, id).Error
+}
+
+// List returns the user's accounts ordered by name
+func (r *AccountRepository) List(ctx context.Context, userID uint) ([]domain.Account, error) {
+	var accounts []domain.Account
+	if err := Conn(ctx, r.DB).Where("user_id = ?", userID).Order("name, id").Find(&accounts).Error; err != nil {
+		return nil, err
+	}
+	for i := range accounts {
+		if err := r.open(ctx, &accounts[i]); err != nil {
+			return nil, err
+		}
+	}
+	return accounts, nil
+}
+
+// Reencrypt seals every account number that is plaintext or sealed with a
+// retired key with the current key, and returns how many it rewrote
+func (r *AccountRepository) Reencrypt(ctx context.Context) (int64, error) {
+	return reencrypt(ctx, r.DB, r.Cipher, &domain.Account{}, "account_number")
+}
+
+// write stores a copy of the account with its number sealed, then copies the
+// generated fields back, so the caller keeps the plaintext number
+func (r *AccountRepository) write(ctx context.Context, account *domain.Account, store func(*domain.Account) error) error {
+	sealed := *account
+	number, err := r.Cipher.Encrypt(ctx, account.AccountNumber)
+	if err != nil {
+		return err
+	}
+	sealed.AccountNumber = number
+	if err := store(&sealed); err != nil {
+		return err
+	}
+	account.ID, account.CreatedAt, account.UpdatedAt = sealed.ID, sealed.CreatedAt, sealed.UpdatedAt
+	account.Mask()
+	return nil
+}
+
+func (r *AccountRepository) open(ctx context.Context, account *domain.Account) error {
+	number, err := r.Cipher.Decrypt(ctx, account.AccountNumber)
+	if err != nil {
+		return err
+	}
+	account.AccountNumber = number
+	account.Mask()
+	return nil
+}
+
+// reencrypt rewrites the column of every row of the model's table whose
+// value needs rotation
+func reencrypt(ctx context.Context, db *gorm.DB, cipher *encryption.Cipher, model any, column string) (int64, error) {
+	if cipher == nil {
+		return 0, nil
+	}
+	var rewritten int64
+	var rows []struct {
+		ID    uint
+		Value string
+	}
+	err := db.WithContext(ctx).Model(model).
+		Select("id, "+column+" AS value").
+		Where(column+" <> ''").
+		FindInBatches(&rows, rotationBatchSize, func(_ *gorm.DB, _ int) error {
+			for _, row := range rows {
+				if !cipher.NeedsRotation(row.Value) {
+					continue
+				}
+				plaintext, err := cipher.Decrypt(ctx, row.Value)
+				if err != nil {
+					return err
+				}
+				sealed, err := cipher.Encrypt(ctx, plaintext)
+				if err != nil {
+					return err
+				}
+				// Only overwrite the value read, in case it changed meanwhile
+				result := db.WithContext(ctx).Model(model).
+					Where("id = ? AND "+column+" = ?", row.ID, row.Value).
+					UpdateColumn(column, sealed)
+				if result.Error != nil {
+					return result.Error
+				}
+				rewritten += result.RowsAffected
+			}
+			return nil
+		}).Error
+	return rewritten, err
+}
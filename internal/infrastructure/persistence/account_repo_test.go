@@ -0,0 +1,157 @@
+package persistence
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/encryption"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func testCipher(t *testing.T, currentID string, keyIDs ...string) *encryption.Cipher {
+	t.Helper()
+	keys := make(map[string]string)
+	for _, id := range keyIDs {
+		keys[id] = base64.StdEncoding.EncodeToString([]byte(strings.Repeat(id[:1], encryption.KeySize)))
+	}
+	provider, err := encryption.NewStaticKeyProvider(currentID, keys)
+	require.NoError(t, err)
+	return encryption.NewCipher(provider)
+}
+
+// storedColumn reads a column as it is in the database, bypassing the repository
+func storedColumn(t *testing.T, db *gorm.DB, table, column string, id uint) string {
+	t.Helper()
+	var value string
+	require.NoError(t, db.Table(table).Select(column).Where("id = ?", id).Scan(&value).Error)
+	return value
+}
+
+func TestAccountRepository_EncryptsAccountNumbers(t *testing.T) {
+	ctx := context.Background()
+	db := setupRepositoryTestDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.Account{}))
+	repo := NewAccountRepository(db, testCipher(t, "a", "a"))
+
+	account := &domain.Account{UserID: 1, Name: "Main", Type: domain.AccountTypeChecking, AccountNumber: "DE89370400440532013000", Currency: "EUR"}
+	require.NoError(t, repo.Create(ctx, account))
+	assert.NotZero(t, account.ID)
+	assert.Equal(t, "DE89370400440532013000", account.AccountNumber, "the caller keeps the plaintext")
+	assert.Equal(t, "••••3000", account.MaskedNumber)
+
+	stored := storedColumn(t, db, "accounts", "account_number", account.ID)
+	assert.True(t, strings.HasPrefix(stored, "enc:v1:a:"))
+	assert.NotContains(t, stored, "3000")
+
+	found, err := repo.GetByID(ctx, account.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "DE89370400440532013000", found.AccountNumber)
+	assert.Equal(t, "••••3000", found.MaskedNumber)
+
+	found.AccountNumber = "GB29NWBK60161331926819"
+	require.NoError(t, repo.Update(ctx, found))
+	listed, err := repo.List(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, "GB29NWBK60161331926819", listed[0].AccountNumber)
+	assert.NotContains(t, storedColumn(t, db, "accounts", "account_number", account.ID), "GB29")
+
+	_, err = repo.GetByID(ctx, 999)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestAccountRepository_Reencrypt(t *testing.T) {
+	ctx := context.Background()
+	db := setupRepositoryTestDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.Account{}))
+
+	// One row from before encryption was enabled, one sealed with the old key
+	plain := &domain.Account{UserID: 1, Name: "Cash", Type: domain.AccountTypeCash, AccountNumber: "1111", Currency: "USD"}
+	require.NoError(t, NewAccountRepository(db, nil).Create(ctx, plain))
+	old := &domain.Account{UserID: 1, Name: "Savings", Type: domain.AccountTypeSavings, AccountNumber: "2222", Currency: "USD"}
+	require.NoError(t, NewAccountRepository(db, testCipher(t, "a", "a")).Create(ctx, old))
+	empty := &domain.Account{UserID: 1, Name: "Wallet", Type: domain.AccountTypeCash, Currency: "USD"}
+	require.NoError(t, NewAccountRepository(db, nil).Create(ctx, empty))
+
+	rotated := NewAccountRepository(db, testCipher(t, "b", "a", "b"))
+	rewritten, err := rotated.Reencrypt(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), rewritten)
+
+	assert.True(t, strings.HasPrefix(storedColumn(t, db, "accounts", "account_number", plain.ID), "enc:v1:b:"))
+	assert.True(t, strings.HasPrefix(storedColumn(t, db, "accounts", "account_number", old.ID), "enc:v1:b:"))
+	assert.Empty(t, storedColumn(t, db, "accounts", "account_number", empty.ID))
+
+	// The old key can be retired now
+	retired := NewAccountRepository(db, testCipher(t, "b", "b"))
+	accounts, err := retired.List(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, accounts, 3)
+	assert.Equal(t, "1111", accounts[0].AccountNumber)
+	assert.Equal(t, "2222", accounts[1].AccountNumber)
+
+	rewritten, err = retired.Reencrypt(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, rewritten)
+}
+
+func TestAttachmentRepository_EncryptsStoragePaths(t *testing.T) {
+	ctx := context.Background()
+	db := setupRepositoryTestDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.Attachment{}))
+	repo := NewAttachmentRepository(db, testCipher(t, "a", "a"))
+
+	attachment := &domain.Attachment{UserID: 1, FileName: "receipt.png", StoragePath: "uploads/1/receipt.png"}
+	require.NoError(t, repo.Create(ctx, attachment))
+	assert.Equal(t, "uploads/1/receipt.png", attachment.StoragePath)
+	assert.NotContains(t, storedColumn(t, db, "attachments", "storage_path", attachment.ID), "receipt")
+
+	found, err := repo.GetByID(ctx, attachment.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "uploads/1/receipt.png", found.StoragePath)
+
+	rewritten, err := NewAttachmentRepository(db, testCipher(t, "b", "a", "b")).Reencrypt(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), rewritten)
+}
+
+func TestBankLinkRepository_EncryptsAccessTokens(t *testing.T) {
+	ctx := context.Background()
+	db := setupRepositoryTestDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.BankLink{}))
+	repo := NewBankLinkRepository(db, testCipher(t, "a", "a"))
+
+	link := &domain.BankLink{
+		UserID: 1, Provider: domain.BankProviderPlaid, ExternalAccountID: "acc-1", ConnectionID: "item-1",
+		AccessToken: "access-sandbox-1", Name: "Checking", Status: domain.BankLinkStatusActive,
+	}
+	require.NoError(t, repo.Create(ctx, link))
+	assert.Equal(t, "access-sandbox-1", link.AccessToken)
+	assert.NotContains(t, storedColumn(t, db, "bank_links", "access_token", link.ID), "sandbox")
+
+	expired := &domain.BankLink{
+		UserID: 2, Provider: domain.BankProviderGoCardless, ExternalAccountID: "acc-2", ConnectionID: "req-1",
+		AccessToken: "req-1", Name: "Main", Status: domain.BankLinkStatusReauthRequired,
+	}
+	require.NoError(t, repo.Create(ctx, expired))
+
+	listed, err := repo.List(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, "access-sandbox-1", listed[0].AccessToken)
+
+	syncable, err := repo.Syncable(ctx)
+	require.NoError(t, err)
+	require.Len(t, syncable, 1)
+	assert.Equal(t, link.ID, syncable[0].ID)
+
+	rewritten, err := NewBankLinkRepository(db, testCipher(t, "b", "a", "b")).Reencrypt(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), rewritten)
+}
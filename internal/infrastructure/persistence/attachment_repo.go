@@ -0,0 +1,59 @@
+package persistence
+
+import (
+	"context"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/encryption"
+
+	"gorm.io/gorm"
+)
+
+// AttachmentRepository is the GORM implementation of
+// domain.AttachmentRepository. Storage paths are encrypted with Cipher, so
+// the database alone does not tell where users' receipts live.
+type AttachmentRepository struct {
+	DB     *gorm.DB
+	Cipher *encryption.Cipher
+}
+
+var _ domain.AttachmentRepository = (*AttachmentRepository)(nil)
+
+func NewAttachmentRepository(db *gorm.DB, cipher *encryption.Cipher) *AttachmentRepository {
+	return &AttachmentRepository{DB: db, Cipher: cipher}
+}
+
+// Create stores the attachment with its path sealed; the caller's copy keeps
+// the plaintext path
+func (r *AttachmentRepository) Create(ctx context.Context, attachment *domain.Attachment) error {
+	sealed := *attachment
+	path, err := r.Cipher.Encrypt(ctx, attachment.StoragePath)
+	if err != nil {
+		return err
+	}
+	sealed.StoragePath = path
+	if err := Conn(ctx, r.DB).Create(&sealed).Error; err != nil {
+		return err
+	}
+	attachment.ID, attachment.CreatedAt, attachment.UpdatedAt = sealed.ID, sealed.CreatedAt, sealed.UpdatedAt
+	return nil
+}
+
+func (r *AttachmentRepository) GetByID(ctx context.Context, id uint) (*domain.Attachment, error) {
+	var attachment domain.Attachment
+	if err := Conn(ctx, r.DB).First(&attachment, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	path, err := r.Cipher.Decrypt(ctx, attachment.StoragePath)
+	if err != nil {
+		return nil, err
+	}
+	attachment.StoragePath = path
+	return &attachment, nil
+}
+
+// Reencrypt seals every storage path that is plaintext or sealed with a
+// retired key with the current key, and returns how many it rewrote
+func (r *AttachmentRepository) Reencrypt(ctx context.Context) (int64, error) {
+	return reencrypt(ctx, r.DB, r.Cipher, &domain.Attachment{}, "storage_path")
+}
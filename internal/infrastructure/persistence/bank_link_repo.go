@@ -0,0 +1,106 @@
+package persistence
+
+import (
+	"context"
+
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/encryption"
+
+	"gorm.io/gorm"
+)
+
+// BankLinkRepository is the GORM implementation of domain.BankLinkRepository.
+// Provider access tokens are encrypted with Cipher, as they grant read access
+// to the user's bank accounts; a nil Cipher stores them in plaintext.
+type BankLinkRepository struct {
+	DB     *gorm.DB
+	Cipher *encryption.Cipher
+}
+
+var _ domain.BankLinkRepository = (*BankLinkRepository)(nil)
+
+func NewBankLinkRepository(db *gorm.DB, cipher *encryption.Cipher) *BankLinkRepository {
+	return &BankLinkRepository{DB: db, Cipher: cipher}
+}
+
+func (r *BankLinkRepository) Create(ctx context.Context, link *domain.BankLink) error {
+	return r.write(ctx, link, func(sealed *domain.BankLink) error {
+		return Conn(ctx, r.DB).Create(sealed).Error
+	})
+}
+
+func (r *BankLinkRepository) GetByID(ctx context.Context, id uint) (*domain.BankLink, error) {
+	var link domain.BankLink
+	if err := Conn(ctx, r.DB).First(&link, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	if err := r.open(ctx, &link); err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// Update saves all fields of a link
+func (r *BankLinkRepository) Update(ctx context.Context, link *domain.BankLink) error {
+	return r.write(ctx, link, func(sealed *domain.BankLink) error {
+		return Conn(ctx, r.DB).Save(sealed).Error
+	})
+}
+
+func (r *BankLinkRepository) Delete(ctx context.Context, id uint) error {
+	return Conn(ctx, r.DB).Delete(&domain.BankLink{}, id).Error
+}
+
+// List returns the user's links ordered by institution and name
+func (r *BankLinkRepository) List(ctx context.Context, userID uint) ([]domain.BankLink, error) {
+	return r.find(ctx, Conn(ctx, r.DB).Where("user_id = ?", userID).Order("institution, name, id"))
+}
+
+// Syncable returns every link that has not lost access to its bank
+func (r *BankLinkRepository) Syncable(ctx context.Context) ([]domain.BankLink, error) {
+	return r.find(ctx, Conn(ctx, r.DB).Where("status <> ?", domain.BankLinkStatusReauthRequired).Order("id"))
+}
+
+// Reencrypt seals every access token that is plaintext or sealed with a
+// retired key with the current key, and returns how many it rewrote
+func (r *BankLinkRepository) Reencrypt(ctx context.Context) (int64, error) {
+	return reencrypt(ctx, r.DB, r.Cipher, &domain.BankLink{}, "access_token")
+}
+
+func (r *BankLinkRepository) find(ctx context.Context, query *gorm.DB) ([]domain.BankLink, error) {
+	links := []domain.BankLink{}
+	if err := query.Find(&links).Error; err != nil {
+		return nil, err
+	}
+	for i := range links {
+		if err := r.open(ctx, &links[i]); err != nil {
+			return nil, err
+		}
+	}
+	return links, nil
+}
+
+// write stores a copy of the link with its token sealed, then copies the
+// generated fields back, so the caller keeps the plaintext token
+func (r *BankLinkRepository) write(ctx context.Context, link *domain.BankLink, store func(*domain.BankLink) error) error {
+	sealed := *link
+	token, err := r.Cipher.Encrypt(ctx, link.AccessToken)
+	if err != nil {
+		return err
+	}
+	sealed.AccessToken = token
+	if err := store(&sealed); err != nil {
+		return err
+	}
+	link.ID, link.CreatedAt, link.UpdatedAt = sealed.ID, sealed.CreatedAt, sealed.UpdatedAt
+	return nil
+}
+
+func (r *BankLinkRepository) open(ctx context.Context, link *domain.BankLink) error {
+	token, err := r.Cipher.Decrypt(ctx, link.AccessToken)
+	if err != nil {
+		return err
+	}
+	link.AccessToken = token
+	return nil
+}
@@ -0,0 +1,75 @@
+package persistence
+
+import (
+	"context"
+
+	"go-finance-advisor/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BudgetRepository is the GORM implementation of domain.BudgetRepository
+type BudgetRepository struct{ DB *gorm.DB }
+
+var _ domain.BudgetRepository = (*BudgetRepository)(nil)
+
+func NewBudgetRepository(db *gorm.DB) *BudgetRepository {
+	return &BudgetRepository{DB: db}
+}
+
+func (r *BudgetRepository) Create(ctx context.Context, budget *domain.Budget) error {
+	return Conn(ctx, r.DB).Create(budget).Error
+}
+
+// GetByID returns a budget with its category
+func (r *BudgetRepository) GetByID(ctx context.Context, id uint) (*domain.Budget, error) {
+	var budget domain.Budget
+	if err := Conn(ctx, r.DB).Preload("Category").First(&budget, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &budget, nil
+}
+
+// Update saves all fields of a budget; associations are left untouched
+func (r *BudgetRepository) Update(ctx context.Context, budget *domain.Budget) error {
+	return Conn(ctx, r.DB).Omit(clause.Associations).Save(budget).Error
+}
+
+// Delete removes a budget by ID
+func (r *BudgetRepository) Delete(ctx context.Context, id uint) error {
+	return Conn(ctx, r.DB).Delete(&domain.Budget{}, id).Error
+}
+
+// Find returns the budgets matching the filter, most recently created first
+func (r *BudgetRepository) Find(ctx context.Context, filter domain.BudgetFilter) ([]domain.Budget, error) {
+	query := Conn(ctx, r.DB).Preload("Category")
+	if filter.HouseholdID == nil || filter.UserID != 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.HouseholdID != nil {
+		query = query.Where("household_id = ?", *filter.HouseholdID)
+	}
+	if filter.PersonalOnly {
+		query = query.Where("household_id IS NULL")
+	}
+	if filter.CategoryID != nil {
+		query = query.Where("category_id = ?", *filter.CategoryID)
+	}
+	if len(filter.CategoryIDs) > 0 {
+		query = query.Where("category_id IN ?", filter.CategoryIDs)
+	}
+	if filter.IsActive != nil {
+		query = query.Where("is_active = ?", *filter.IsActive)
+	}
+	if filter.StartsBefore != nil {
+		query = query.Where("start_date <= ?", *filter.StartsBefore)
+	}
+	if filter.EndsAfter != nil {
+		query = query.Where("end_date >= ?", *filter.EndsAfter)
+	}
+
+	var budgets []domain.Budget
+	err := query.Order("created_at DESC").Find(&budgets).Error
+	return budgets, err
+}
@@ -0,0 +1,342 @@
+package persistence
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// MemoryTransactionRepository keeps transactions in memory. It is meant for
+// tests and follows the same filtering and ordering rules as the GORM repository.
+type MemoryTransactionRepository struct {
+	mu           sync.RWMutex
+	nextID       uint
+	transactions map[uint]domain.Transaction
+}
+
+var _ domain.TransactionRepository = (*MemoryTransactionRepository)(nil)
+
+func NewMemoryTransactionRepository() *MemoryTransactionRepository {
+	return &MemoryTransactionRepository{transactions: make(map[uint]domain.Transaction)}
+}
+
+func (r *MemoryTransactionRepository) Create(_ context.Context, tx *domain.Transaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if tx.ID == 0 {
+		r.nextID++
+		tx.ID = r.nextID
+	} else if tx.ID > r.nextID {
+		r.nextID = tx.ID
+	}
+	if tx.Type == "" {
+		tx.Type = domain.TransactionTypeExpense
+	}
+	now := time.Now()
+	tx.CreatedAt, tx.UpdatedAt = now, now
+	r.transactions[tx.ID] = *tx
+	return nil
+}
+
+func (r *MemoryTransactionRepository) GetByID(_ context.Context, id uint) (*domain.Transaction, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tx, ok := r.transactions[id]
+	if !ok || tx.DeletedAt.Valid {
+		return nil, domain.ErrNotFound
+	}
+	return &tx, nil
+}
+
+func (r *MemoryTransactionRepository) Update(_ context.Context, tx *domain.Transaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.transactions[tx.ID]; !ok || existing.DeletedAt.Valid {
+		return domain.ErrNotFound
+	}
+	tx.UpdatedAt = time.Now()
+	r.transactions[tx.ID] = *tx
+	return nil
+}
+
+func (r *MemoryTransactionRepository) Delete(_ context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if tx, ok := r.transactions[id]; ok && !tx.DeletedAt.Valid {
+		tx.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+		r.transactions[id] = tx
+	}
+	return nil
+}
+
+func (r *MemoryTransactionRepository) Find(_ context.Context, filter domain.TransactionFilter) ([]domain.Transaction, error) {
+	transactions := r.matching(filter)
+	sort.Slice(transactions, func(i, j int) bool { return newerThan(transactions[i], transactions[j]) })
+
+	cursor := filter.Cursor
+	switch {
+	case cursor == nil:
+		if filter.Offset >= len(transactions) {
+			return []domain.Transaction{}, nil
+		}
+		transactions = transactions[max(filter.Offset, 0):]
+	case cursor.Before:
+		// Keep the rows newer than the cursor that sit closest to it
+		position := domain.Transaction{ID: cursor.ID, Date: cursor.Date}
+		end := sort.Search(len(transactions), func(i int) bool { return !newerThan(transactions[i], position) })
+		start := 0
+		if filter.Limit > 0 && end > filter.Limit {
+			start = end - filter.Limit
+		}
+		return transactions[start:end], nil
+	default:
+		position := domain.Transaction{ID: cursor.ID, Date: cursor.Date}
+		start := sort.Search(len(transactions), func(i int) bool { return newerThan(position, transactions[i]) })
+		transactions = transactions[start:]
+	}
+	if filter.Limit > 0 && filter.Limit < len(transactions) {
+		transactions = transactions[:filter.Limit]
+	}
+	return transactions, nil
+}
+
+func (r *MemoryTransactionRepository) Count(_ context.Context, filter domain.TransactionFilter) (int64, error) {
+	return int64(len(r.matching(filter))), nil
+}
+
+// newerThan reports whether a comes before b in the newest-first (date, id) ordering
+func newerThan(a, b domain.Transaction) bool {
+	if !a.Date.Equal(b.Date) {
+		return a.Date.After(b.Date)
+	}
+	return a.ID > b.ID
+}
+
+func (r *MemoryTransactionRepository) Sum(_ context.Context, filter domain.TransactionFilter) (domain.Money, error) {
+	var total domain.Money
+	for _, tx := range r.matching(filter) {
+		total += tx.Amount
+	}
+	return total, nil
+}
+
+func (r *MemoryTransactionRepository) SumByCategory(_ context.Context, filter domain.TransactionFilter) (map[uint]domain.Money, error) {
+	totals := make(map[uint]domain.Money)
+	for _, tx := range r.matching(filter) {
+		totals[tx.CategoryID] += tx.Amount
+	}
+	return totals, nil
+}
+
+func (r *MemoryTransactionRepository) SumByMonth(_ context.Context, filter domain.TransactionFilter) (map[string]domain.Money, error) {
+	totals := make(map[string]domain.Money)
+	for _, tx := range r.matching(filter) {
+		totals[tx.Date.Format("2006-01")] += tx.Amount
+	}
+	return totals, nil
+}
+
+func (r *MemoryTransactionRepository) FindDeleted(_ context.Context, filter domain.TransactionFilter) ([]domain.Transaction, error) {
+	transactions := r.matchingIn(filter, true)
+	sort.Slice(transactions, func(i, j int) bool {
+		return transactions[i].DeletedAt.Time.After(transactions[j].DeletedAt.Time)
+	})
+
+	if filter.Offset > 0 {
+		if filter.Offset >= len(transactions) {
+			return []domain.Transaction{}, nil
+		}
+		transactions = transactions[filter.Offset:]
+	}
+	if filter.Limit > 0 && filter.Limit < len(transactions) {
+		transactions = transactions[:filter.Limit]
+	}
+	return transactions, nil
+}
+
+func (r *MemoryTransactionRepository) Restore(_ context.Context, userID, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tx, ok := r.transactions[id]
+	if !ok || tx.UserID != userID || !tx.DeletedAt.Valid {
+		return domain.ErrNotFound
+	}
+	tx.DeletedAt = gorm.DeletedAt{}
+	tx.UpdatedAt = time.Now()
+	r.transactions[id] = tx
+	return nil
+}
+
+func (r *MemoryTransactionRepository) Purge(_ context.Context, userID, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tx, ok := r.transactions[id]
+	if !ok || tx.UserID != userID || !tx.DeletedAt.Valid {
+		return domain.ErrNotFound
+	}
+	delete(r.transactions, id)
+	return nil
+}
+
+func (r *MemoryTransactionRepository) PurgeDeletedBefore(_ context.Context, userID uint, cutoff time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var purged int64
+	for id, tx := range r.transactions {
+		if tx.DeletedAt.Valid && tx.DeletedAt.Time.Before(cutoff) && (userID == 0 || tx.UserID == userID) {
+			delete(r.transactions, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (r *MemoryTransactionRepository) matching(filter domain.TransactionFilter) []domain.Transaction {
+	return r.matchingIn(filter, false)
+}
+
+// matchingIn applies the filter to either the live transactions or the trash
+func (r *MemoryTransactionRepository) matchingIn(filter domain.TransactionFilter, deleted bool) []domain.Transaction {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	transactions := make([]domain.Transaction, 0, len(r.transactions))
+	for _, tx := range r.transactions {
+		switch {
+		case tx.DeletedAt.Valid != deleted:
+		case (filter.HouseholdID == nil || filter.UserID != 0) && tx.UserID != filter.UserID:
+		case !inHousehold(tx.HouseholdID, filter.HouseholdID, filter.PersonalOnly):
+		case filter.AccountID != nil && !sameID(tx.AccountID, *filter.AccountID) && !sameID(tx.TransferAccountID, *filter.AccountID):
+		case filter.TransferAccountID != nil && !sameID(tx.TransferAccountID, *filter.TransferAccountID):
+		case filter.ExcludeTransfers && tx.Type == domain.TransactionTypeTransfer:
+		case filter.ScheduledTransferID != nil && !sameID(tx.ScheduledTransferID, *filter.ScheduledTransferID):
+		case filter.Type != "" && tx.Type != filter.Type:
+		case filter.CategoryID != nil && tx.CategoryID != *filter.CategoryID:
+		case len(filter.CategoryIDs) > 0 && !slices.Contains(filter.CategoryIDs, tx.CategoryID):
+		case filter.StartDate != nil && tx.Date.Before(*filter.StartDate):
+		case filter.EndDate != nil && tx.Date.After(*filter.EndDate):
+		case filter.OlderThan != nil && !newerThan(domain.Transaction{ID: filter.OlderThan.ID, Date: filter.OlderThan.Date}, tx):
+		default:
+			transactions = append(transactions, tx)
+		}
+	}
+	return transactions
+}
+
+// sameID reports whether an optional ID is set to id
+func sameID(optional *uint, id uint) bool {
+	return optional != nil && *optional == id
+}
+
+// MemoryBudgetRepository keeps budgets in memory. It is meant for tests and
+// follows the same filtering and ordering rules as the GORM repository.
+type MemoryBudgetRepository struct {
+	mu      sync.RWMutex
+	nextID  uint
+	budgets map[uint]domain.Budget
+}
+
+var _ domain.BudgetRepository = (*MemoryBudgetRepository)(nil)
+
+func NewMemoryBudgetRepository() *MemoryBudgetRepository {
+	return &MemoryBudgetRepository{budgets: make(map[uint]domain.Budget)}
+}
+
+func (r *MemoryBudgetRepository) Create(_ context.Context, budget *domain.Budget) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if budget.ID == 0 {
+		r.nextID++
+		budget.ID = r.nextID
+	} else if budget.ID > r.nextID {
+		r.nextID = budget.ID
+	}
+	now := time.Now()
+	budget.CreatedAt, budget.UpdatedAt = now, now
+	r.budgets[budget.ID] = *budget
+	return nil
+}
+
+func (r *MemoryBudgetRepository) GetByID(_ context.Context, id uint) (*domain.Budget, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	budget, ok := r.budgets[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &budget, nil
+}
+
+func (r *MemoryBudgetRepository) Update(_ context.Context, budget *domain.Budget) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.budgets[budget.ID]; !ok {
+		return domain.ErrNotFound
+	}
+	budget.UpdatedAt = time.Now()
+	r.budgets[budget.ID] = *budget
+	return nil
+}
+
+func (r *MemoryBudgetRepository) Delete(_ context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.budgets, id)
+	return nil
+}
+
+func (r *MemoryBudgetRepository) Find(_ context.Context, filter domain.BudgetFilter) ([]domain.Budget, error) {
+	r.mu.RLock()
+	budgets := make([]domain.Budget, 0, len(r.budgets))
+	for _, budget := range r.budgets {
+		switch {
+		case (filter.HouseholdID == nil || filter.UserID != 0) && budget.UserID != filter.UserID:
+		case !inHousehold(budget.HouseholdID, filter.HouseholdID, filter.PersonalOnly):
+		case filter.CategoryID != nil && budget.CategoryID != *filter.CategoryID:
+		case len(filter.CategoryIDs) > 0 && !slices.Contains(filter.CategoryIDs, budget.CategoryID):
+		case filter.IsActive != nil && budget.IsActive != *filter.IsActive:
+		case filter.StartsBefore != nil && budget.StartDate.After(*filter.StartsBefore):
+		case filter.EndsAfter != nil && budget.EndDate.Before(*filter.EndsAfter):
+		default:
+			budgets = append(budgets, budget)
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(budgets, func(i, j int) bool {
+		if !budgets[i].CreatedAt.Equal(budgets[j].CreatedAt) {
+			return budgets[i].CreatedAt.After(budgets[j].CreatedAt)
+		}
+		return budgets[i].ID > budgets[j].ID
+	})
+	return budgets, nil
+}
+
+// inHousehold applies the HouseholdID and PersonalOnly filters to a record's household
+func inHousehold(householdID, want *uint, personalOnly bool) bool {
+	switch {
+	case personalOnly && householdID != nil:
+		return false
+	case want == nil:
+		return true
+	default:
+		return householdID != nil && *householdID == *want
+	}
+}
@@ -0,0 +1,122 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// The structs below are snapshots of the tables as they were when this
+// migration was written. They must not be replaced by the domain types, which
+// keep changing; later columns belong in later migrations.
+
+type user0001 struct {
+	ID            uint   `gorm:"primaryKey"`
+	Email         string `gorm:"type:varchar(100);uniqueIndex;not null"`
+	Password      string `gorm:"type:varchar(255);not null"`
+	FirstName     string `gorm:"type:varchar(50)"`
+	LastName      string `gorm:"type:varchar(50)"`
+	Age           int    `gorm:"type:int;default:30"`
+	RiskTolerance string `gorm:"type:varchar(20);default:'moderate'"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (user0001) TableName() string { return "users" }
+
+type category0001 struct {
+	ID          uint   `gorm:"primaryKey"`
+	Name        string `gorm:"type:varchar(50);uniqueIndex"`
+	Type        string `gorm:"type:varchar(10)"`
+	Description string
+	Icon        string
+	Color       string
+	IsDefault   bool `gorm:"default:false"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (category0001) TableName() string { return "categories" }
+
+type transaction0001 struct {
+	ID          uint `gorm:"primaryKey"`
+	UserID      uint
+	CategoryID  uint
+	Type        string `gorm:"type:varchar(10);default:'expense'"`
+	Description string
+	Amount      float64
+	Date        time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (transaction0001) TableName() string { return "transactions" }
+
+type budget0001 struct {
+	ID         uint `gorm:"primaryKey"`
+	UserID     uint
+	CategoryID uint
+	Amount     float64
+	Period     string `gorm:"type:varchar(20);default:'monthly'"`
+	StartDate  time.Time
+	EndDate    time.Time
+	Spent      float64 `gorm:"default:0"`
+	Remaining  float64 `gorm:"default:0"`
+	IsActive   bool    `gorm:"default:true"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (budget0001) TableName() string { return "budgets" }
+
+type recommendation0001 struct {
+	ID           uint    `gorm:"primaryKey"`
+	UserID       uint    `gorm:"not null"`
+	Type         string  `gorm:"not null"`
+	Symbol       string  `gorm:"not null"`
+	Action       string  `gorm:"not null"`
+	Reason       string  `gorm:"type:text"`
+	Confidence   float64 `gorm:"not null"`
+	TargetPrice  *float64
+	CurrentPrice float64 `gorm:"not null"`
+	RiskLevel    string  `gorm:"not null"`
+	Timeframe    string  `gorm:"not null"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	ExpiresAt    *time.Time
+	IsActive     bool `gorm:"default:true"`
+}
+
+func (recommendation0001) TableName() string { return "recommendations" }
+
+type attachment0001 struct {
+	ID            uint   `gorm:"primaryKey"`
+	UserID        uint   `gorm:"not null;index"`
+	TransactionID *uint  `gorm:"index"`
+	FileName      string `gorm:"type:varchar(255);not null"`
+	ContentType   string `gorm:"type:varchar(100)"`
+	Size          int64
+	StoragePath   string `gorm:"type:varchar(500);not null"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (attachment0001) TableName() string { return "attachments" }
+
+// initialSchema creates the tables that used to be built by AutoMigrate.
+// AutoMigrate only adds what is missing, so databases created before
+// migrations existed are adopted without changes.
+var initialSchema = Migration{
+	Version: 1,
+	Name:    "initial_schema",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(
+			&user0001{}, &category0001{}, &transaction0001{}, &budget0001{}, &recommendation0001{}, &attachment0001{},
+		)
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(
+			&attachment0001{}, &recommendation0001{}, &budget0001{}, &transaction0001{}, &category0001{}, &user0001{},
+		)
+	},
+}
@@ -0,0 +1,28 @@
+package migrations
+
+import "gorm.io/gorm"
+
+type transaction0002 struct {
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (transaction0002) TableName() string { return "transactions" }
+
+// transactionSoftDelete adds deleted_at so deleted transactions go to the trash
+var transactionSoftDelete = Migration{
+	Version: 2,
+	Name:    "transaction_soft_delete",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&transaction0002{})
+	},
+	Down: func(tx *gorm.DB) error {
+		// Rows in the trash would reappear once the column is gone
+		if err := tx.Exec("DELETE FROM transactions WHERE deleted_at IS NOT NULL").Error; err != nil {
+			return err
+		}
+		if err := tx.Migrator().DropIndex(&transaction0002{}, "DeletedAt"); err != nil {
+			return err
+		}
+		return tx.Migrator().DropColumn(&transaction0002{}, "DeletedAt")
+	},
+}
@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type auditLog0003 struct {
+	ID         uint      `gorm:"primaryKey"`
+	UserID     uint      `gorm:"index"`
+	ActorID    uint      `gorm:"index"`
+	EntityType string    `gorm:"type:varchar(30);index:idx_audit_entity"`
+	EntityID   uint      `gorm:"index:idx_audit_entity"`
+	Action     string    `gorm:"type:varchar(20)"`
+	Before     string    `gorm:"type:text"`
+	After      string    `gorm:"type:text"`
+	IPAddress  string    `gorm:"type:varchar(45)"`
+	CreatedAt  time.Time `gorm:"index"`
+}
+
+func (auditLog0003) TableName() string { return "audit_logs" }
+
+// auditLogs adds the table recording who changed which user data
+var auditLogs = Migration{
+	Version: 3,
+	Name:    "audit_logs",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&auditLog0003{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&auditLog0003{})
+	},
+}
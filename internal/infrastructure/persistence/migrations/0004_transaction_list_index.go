@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type transaction0004 struct {
+	UserID uint      `gorm:"index:idx_transactions_user_date,priority:1"`
+	Date   time.Time `gorm:"index:idx_transactions_user_date,priority:2"`
+}
+
+func (transaction0004) TableName() string { return "transactions" }
+
+// transactionListIndex backs the newest-first transaction list and its keyset pagination
+var transactionListIndex = Migration{
+	Version: 4,
+	Name:    "transaction_list_index",
+	Up: func(tx *gorm.DB) error {
+		// Databases created with AutoMigrate already have it
+		if tx.Migrator().HasIndex(&transaction0004{}, "idx_transactions_user_date") {
+			return nil
+		}
+		return tx.Migrator().CreateIndex(&transaction0004{}, "idx_transactions_user_date")
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropIndex(&transaction0004{}, "idx_transactions_user_date")
+	},
+}
@@ -0,0 +1,52 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type merchant0005 struct {
+	ID        uint   `gorm:"primaryKey"`
+	Name      string `gorm:"type:varchar(100);uniqueIndex;not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (merchant0005) TableName() string { return "merchants" }
+
+type merchantRule0005 struct {
+	ID         uint   `gorm:"primaryKey"`
+	MerchantID uint   `gorm:"index;not null"`
+	Pattern    string `gorm:"type:varchar(100);not null"`
+	MatchType  string `gorm:"type:varchar(10);default:'contains'"`
+	CreatedAt  time.Time
+}
+
+func (merchantRule0005) TableName() string { return "merchant_rules" }
+
+type transaction0005 struct {
+	MerchantID *uint `gorm:"index"`
+}
+
+func (transaction0005) TableName() string { return "transactions" }
+
+// merchants adds normalized merchants, their matching rules and the
+// transaction link to them. Existing transactions stay unassigned until
+// they are rematched.
+var merchants = Migration{
+	Version: 5,
+	Name:    "merchants",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&merchant0005{}, &merchantRule0005{}, &transaction0005{})
+	},
+	Down: func(tx *gorm.DB) error {
+		if err := tx.Migrator().DropIndex(&transaction0005{}, "MerchantID"); err != nil {
+			return err
+		}
+		if err := dropColumn(tx, &transaction0005{}, "transactions", "MerchantID"); err != nil {
+			return err
+		}
+		return tx.Migrator().DropTable(&merchantRule0005{}, &merchant0005{})
+	},
+}
@@ -0,0 +1,24 @@
+package migrations
+
+import "gorm.io/gorm"
+
+type category0006 struct {
+	ParentID *uint `gorm:"index"`
+}
+
+func (category0006) TableName() string { return "categories" }
+
+// categoryHierarchy lets categories be nested under a parent category
+var categoryHierarchy = Migration{
+	Version: 6,
+	Name:    "category_hierarchy",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&category0006{})
+	},
+	Down: func(tx *gorm.DB) error {
+		if err := tx.Migrator().DropIndex(&category0006{}, "ParentID"); err != nil {
+			return err
+		}
+		return dropColumn(tx, &category0006{}, "categories", "ParentID")
+	},
+}
@@ -0,0 +1,163 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type category0007 struct {
+	ID          uint   `gorm:"primaryKey"`
+	UserID      *uint  `gorm:"uniqueIndex:idx_categories_user_name,priority:1"`
+	Name        string `gorm:"type:varchar(50);uniqueIndex:idx_categories_user_name,priority:2"`
+	Type        string `gorm:"type:varchar(10)"`
+	Description string
+	Icon        string
+	Color       string
+	IsDefault   bool `gorm:"default:false"`
+	ParentID    *uint
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (category0007) TableName() string { return "categories" }
+
+// categoryOwners makes custom categories private to their user. Names only
+// have to be unique per owner. Existing custom categories go to the users
+// whose transactions or budgets use them: the first user keeps the category
+// and every other user gets a copy. Unused ones stay shared, like the defaults.
+var categoryOwners = Migration{
+	Version: 7,
+	Name:    "category_owners",
+	Up: func(tx *gorm.DB) error {
+		if tx.Migrator().HasIndex(&category0001{}, "idx_categories_name") {
+			if err := tx.Migrator().DropIndex(&category0001{}, "idx_categories_name"); err != nil {
+				return err
+			}
+		}
+		if err := tx.AutoMigrate(&category0007{}); err != nil {
+			return err
+		}
+
+		var custom []category0007
+		if err := tx.Where("is_default = ? AND user_id IS NULL", false).Order("id").Find(&custom).Error; err != nil {
+			return err
+		}
+		for i := range custom {
+			if err := assignCategoryOwners(tx, custom[i]); err != nil {
+				return err
+			}
+		}
+		return relinkCategoryParents(tx)
+	},
+	Down: func(tx *gorm.DB) error {
+		if err := mergeCategoryCopies(tx); err != nil {
+			return err
+		}
+		if err := tx.Migrator().DropIndex(&category0007{}, "idx_categories_user_name"); err != nil {
+			return err
+		}
+		if err := dropColumn(tx, &category0007{}, "categories", "UserID"); err != nil {
+			return err
+		}
+		return tx.Migrator().CreateIndex(&category0001{}, "idx_categories_name")
+	},
+}
+
+// assignCategoryOwners gives the category to the first user of it and a copy
+// to each other user, moving their transactions and budgets to the copy
+func assignCategoryOwners(tx *gorm.DB, category category0007) error {
+	var userIDs []uint
+	err := tx.Raw(`SELECT user_id FROM transactions WHERE category_id = ?
+		UNION SELECT user_id FROM budgets WHERE category_id = ?
+		ORDER BY user_id`, category.ID, category.ID).Scan(&userIDs).Error
+	if err != nil || len(userIDs) == 0 {
+		return err
+	}
+
+	if err := tx.Model(&category0007{}).Where("id = ?", category.ID).Update("user_id", userIDs[0]).Error; err != nil {
+		return err
+	}
+	for _, userID := range userIDs[1:] {
+		owner := userID
+		copied := category
+		copied.ID = 0
+		copied.UserID = &owner
+		if err := tx.Create(&copied).Error; err != nil {
+			return err
+		}
+		for _, table := range []string{"transactions", "budgets"} {
+			err := tx.Table(table).Where("category_id = ? AND user_id = ?", category.ID, userID).
+				Update("category_id", copied.ID).Error
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// relinkCategoryParents points nested categories at the copy of their parent
+// that belongs to the same user, or moves them to the top level if there is none
+func relinkCategoryParents(tx *gorm.DB) error {
+	var nested []category0007
+	err := tx.Where("user_id IS NOT NULL AND parent_id IS NOT NULL").Find(&nested).Error
+	if err != nil {
+		return err
+	}
+
+	for i := range nested {
+		var parent category0007
+		if err := tx.First(&parent, *nested[i].ParentID).Error; err != nil {
+			return err
+		}
+		if parent.UserID == nil || *parent.UserID == *nested[i].UserID {
+			continue
+		}
+
+		var own []category0007
+		err := tx.Where("user_id = ? AND name = ?", *nested[i].UserID, parent.Name).Limit(1).Find(&own).Error
+		if err != nil {
+			return err
+		}
+		var parentID *uint
+		if len(own) > 0 {
+			parentID = &own[0].ID
+		}
+		if err := tx.Model(&category0007{}).Where("id = ?", nested[i].ID).Update("parent_id", parentID).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeCategoryCopies folds categories that share a name into the oldest one
+// so names are globally unique again. Categories of different users that
+// happen to share a name are merged as well.
+func mergeCategoryCopies(tx *gorm.DB) error {
+	var categories []category0007
+	if err := tx.Order("id").Find(&categories).Error; err != nil {
+		return err
+	}
+
+	oldest := make(map[string]uint)
+	for i := range categories {
+		keep, exists := oldest[categories[i].Name]
+		if !exists {
+			oldest[categories[i].Name] = categories[i].ID
+			continue
+		}
+		for _, table := range []string{"transactions", "budgets"} {
+			if err := tx.Table(table).Where("category_id = ?", categories[i].ID).Update("category_id", keep).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&category0007{}).Where("parent_id = ?", categories[i].ID).Update("parent_id", keep).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&category0007{}, categories[i].ID).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type household0008 struct {
+	ID        uint   `gorm:"primaryKey"`
+	Name      string `gorm:"type:varchar(100);not null"`
+	OwnerID   uint   `gorm:"index"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (household0008) TableName() string { return "households" }
+
+type householdMember0008 struct {
+	ID          uint   `gorm:"primaryKey"`
+	HouseholdID uint   `gorm:"uniqueIndex:idx_household_members_household_user,priority:1"`
+	UserID      uint   `gorm:"uniqueIndex:idx_household_members_household_user,priority:2;index"`
+	Role        string `gorm:"type:varchar(20);not null"`
+	CreatedAt   time.Time
+}
+
+func (householdMember0008) TableName() string { return "household_members" }
+
+type householdInvitation0008 struct {
+	ID          uint   `gorm:"primaryKey"`
+	HouseholdID uint   `gorm:"index"`
+	Email       string `gorm:"type:varchar(255);index"`
+	Role        string `gorm:"type:varchar(20);not null"`
+	Token       string `gorm:"type:varchar(64);uniqueIndex"`
+	InvitedByID uint
+	Status      string `gorm:"type:varchar(20);default:'pending'"`
+	ExpiresAt   time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (householdInvitation0008) TableName() string { return "household_invitations" }
+
+type transaction0008 struct {
+	HouseholdID *uint `gorm:"index"`
+}
+
+func (transaction0008) TableName() string { return "transactions" }
+
+type budget0008 struct {
+	HouseholdID *uint `gorm:"index"`
+}
+
+func (budget0008) TableName() string { return "budgets" }
+
+// households adds shared households with their members and invitations, and
+// lets transactions and budgets be shared with one. Existing transactions
+// and budgets stay personal.
+var households = Migration{
+	Version: 8,
+	Name:    "households",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(
+			&household0008{}, &householdMember0008{}, &householdInvitation0008{},
+			&transaction0008{}, &budget0008{},
+		)
+	},
+	Down: func(tx *gorm.DB) error {
+		if err := tx.Migrator().DropIndex(&transaction0008{}, "HouseholdID"); err != nil {
+			return err
+		}
+		if err := dropColumn(tx, &transaction0008{}, "transactions", "HouseholdID"); err != nil {
+			return err
+		}
+		if err := tx.Migrator().DropIndex(&budget0008{}, "HouseholdID"); err != nil {
+			return err
+		}
+		if err := dropColumn(tx, &budget0008{}, "budgets", "HouseholdID"); err != nil {
+			return err
+		}
+		return tx.Migrator().DropTable(&householdInvitation0008{}, &householdMember0008{}, &household0008{})
+	},
+}
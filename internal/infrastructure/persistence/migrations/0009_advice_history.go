@@ -0,0 +1,54 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type adviceRecord0009 struct {
+	ID              uint   `gorm:"primaryKey"`
+	UserID          uint   `gorm:"index:idx_advice_records_user_created,priority:1"`
+	Source          string `gorm:"type:varchar(20)"`
+	RiskProfile     string `gorm:"type:varchar(20)"`
+	MonthlyAmount   float64
+	Advice          string `gorm:"type:text"`
+	RiskScore       float64
+	RiskCategory    string `gorm:"type:varchar(30)"`
+	ConfidenceScore float64
+	Allocation      string    `gorm:"type:text"`
+	CreatedAt       time.Time `gorm:"index:idx_advice_records_user_created,priority:2"`
+}
+
+func (adviceRecord0009) TableName() string { return "advice_records" }
+
+type recommendation0009 struct {
+	AdviceID        *uint `gorm:"index"`
+	Allocation      float64
+	Status          string `gorm:"type:varchar(20);default:'pending'"`
+	StatusChangedAt *time.Time
+}
+
+func (recommendation0009) TableName() string { return "recommendations" }
+
+// adviceHistory keeps generated advice so users can look back at it. The
+// recommendations of a piece of advice point at it and record whether the
+// user followed them.
+var adviceHistory = Migration{
+	Version: 9,
+	Name:    "advice_history",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&adviceRecord0009{}, &recommendation0009{})
+	},
+	Down: func(tx *gorm.DB) error {
+		if err := tx.Migrator().DropIndex(&recommendation0009{}, "AdviceID"); err != nil {
+			return err
+		}
+		for _, field := range []string{"AdviceID", "Allocation", "Status", "StatusChangedAt"} {
+			if err := dropColumn(tx, &recommendation0009{}, "recommendations", field); err != nil {
+				return err
+			}
+		}
+		return tx.Migrator().DropTable(&adviceRecord0009{})
+	},
+}
@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type priceSnapshot0010 struct {
+	ID         uint   `gorm:"primaryKey"`
+	Symbol     string `gorm:"type:varchar(20);index:idx_price_snapshots_symbol_captured,priority:1"`
+	Price      float64
+	CapturedAt time.Time `gorm:"index:idx_price_snapshots_symbol_captured,priority:2"`
+}
+
+func (priceSnapshot0010) TableName() string { return "price_snapshots" }
+
+// priceSnapshots records market prices over time so past advice can be
+// replayed against them.
+var priceSnapshots = Migration{
+	Version: 10,
+	Name:    "price_snapshots",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&priceSnapshot0010{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&priceSnapshot0010{})
+	},
+}
@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type watchlistItem0011 struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"uniqueIndex:idx_watchlist_items_user_symbol,priority:1"`
+	Symbol    string `gorm:"type:varchar(20);uniqueIndex:idx_watchlist_items_user_symbol,priority:2"`
+	Name      string
+	AssetType string `gorm:"type:varchar(20)"`
+	CreatedAt time.Time
+}
+
+func (watchlistItem0011) TableName() string { return "watchlist_items" }
+
+// watchlists lets users save the market symbols they follow.
+var watchlists = Migration{
+	Version: 11,
+	Name:    "watchlists",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&watchlistItem0011{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&watchlistItem0011{})
+	},
+}
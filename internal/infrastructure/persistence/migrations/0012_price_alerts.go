@@ -0,0 +1,64 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type priceAlert0012 struct {
+	ID              uint   `gorm:"primaryKey"`
+	UserID          uint   `gorm:"index"`
+	Symbol          string `gorm:"type:varchar(20);index"`
+	Comparator      string `gorm:"type:varchar(10)"`
+	Threshold       float64
+	Note            string
+	Repeat          bool
+	Active          bool `gorm:"default:true"`
+	Triggered       bool
+	TriggerCount    int
+	LastTriggeredAt *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+func (priceAlert0012) TableName() string { return "price_alerts" }
+
+type priceAlertTrigger0012 struct {
+	ID          uint   `gorm:"primaryKey"`
+	AlertID     uint   `gorm:"index"`
+	UserID      uint   `gorm:"index"`
+	Symbol      string `gorm:"type:varchar(20)"`
+	Comparator  string `gorm:"type:varchar(10)"`
+	Threshold   float64
+	Price       float64
+	TriggeredAt time.Time
+}
+
+func (priceAlertTrigger0012) TableName() string { return "price_alert_triggers" }
+
+type notification0012 struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"index:idx_notifications_user_created,priority:1"`
+	Type      string `gorm:"type:varchar(30)"`
+	Title     string
+	Message   string `gorm:"type:text"`
+	EntityID  *uint
+	ReadAt    *time.Time
+	CreatedAt time.Time `gorm:"index:idx_notifications_user_created,priority:2"`
+}
+
+func (notification0012) TableName() string { return "notifications" }
+
+// priceAlerts adds price alerts, the history of their triggers and the
+// notifications they send.
+var priceAlerts = Migration{
+	Version: 12,
+	Name:    "price_alerts",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&priceAlert0012{}, &priceAlertTrigger0012{}, &notification0012{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&notification0012{}, &priceAlertTrigger0012{}, &priceAlert0012{})
+	},
+}
@@ -0,0 +1,46 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type financialReport0013 struct {
+	ID                   uint      `gorm:"primaryKey"`
+	UserID               uint      `gorm:"not null;index:idx_financial_reports_user_generated,priority:1"`
+	ReportType           string    `gorm:"type:varchar(20);not null"`
+	Period               string    `gorm:"type:varchar(30)"`
+	StartDate            time.Time `gorm:"not null"`
+	EndDate              time.Time `gorm:"not null"`
+	TotalIncome          float64
+	TotalExpenses        float64
+	NetIncome            float64
+	SavingsRate          float64
+	TransactionCount     int
+	CategoryBreakdown    string    `gorm:"type:text"`
+	MonthlyTrends        string    `gorm:"type:text"`
+	BudgetPerformance    string    `gorm:"type:text"`
+	TopIncomeCategories  string    `gorm:"type:text"`
+	TopExpenseCategories string    `gorm:"type:text"`
+	Insights             string    `gorm:"type:text"`
+	Recommendations      string    `gorm:"type:text"`
+	GeneratedAt          time.Time `gorm:"index:idx_financial_reports_user_generated,priority:2"`
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+}
+
+func (financialReport0013) TableName() string { return "financial_reports" }
+
+// financialReports keeps every generated report, with its breakdowns stored
+// as JSON, so users can list, reopen and delete past reports.
+var financialReports = Migration{
+	Version: 13,
+	Name:    "financial_reports",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&financialReport0013{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&financialReport0013{})
+	},
+}
@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type transaction0014 struct {
+	UserID     uint      `gorm:"index:idx_transactions_user_category_date,priority:1"`
+	CategoryID uint      `gorm:"index:idx_transactions_user_category_date,priority:2"`
+	Date       time.Time `gorm:"index:idx_transactions_user_category_date,priority:3"`
+}
+
+func (transaction0014) TableName() string { return "transactions" }
+
+// transactionCategoryIndex backs the per-category sums of analytics, budgets
+// and category trends. Per-period sums use idx_transactions_user_date.
+var transactionCategoryIndex = Migration{
+	Version: 14,
+	Name:    "transaction_category_index",
+	Up: func(tx *gorm.DB) error {
+		// Databases created with AutoMigrate already have it
+		if tx.Migrator().HasIndex(&transaction0014{}, "idx_transactions_user_category_date") {
+			return nil
+		}
+		return tx.Migrator().CreateIndex(&transaction0014{}, "idx_transactions_user_category_date")
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropIndex(&transaction0014{}, "idx_transactions_user_category_date")
+	},
+}
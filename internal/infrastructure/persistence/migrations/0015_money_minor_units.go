@@ -0,0 +1,110 @@
+package migrations
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type transaction0015 struct {
+	Amount int64
+}
+
+func (transaction0015) TableName() string { return "transactions" }
+
+type budget0015 struct {
+	Amount    int64
+	Spent     int64 `gorm:"default:0"`
+	Remaining int64 `gorm:"default:0"`
+}
+
+func (budget0015) TableName() string { return "budgets" }
+
+// moneyColumns lists the amount columns that hold domain.Money, with the
+// snapshots before and after the change
+var moneyColumns = []struct {
+	table   string
+	columns []string
+	cents   interface{}
+	dollars interface{}
+}{
+	{"transactions", []string{"amount"}, &transaction0015{}, &transaction0001{}},
+	{"budgets", []string{"amount", "spent", "remaining"}, &budget0015{}, &budget0001{}},
+}
+
+// moneyMinorUnits stores amounts as integer cents instead of floating point
+// dollars, so sums are exact. Existing amounts are rounded to the cent.
+var moneyMinorUnits = Migration{
+	Version: 15,
+	Name:    "money_minor_units",
+	Up: func(tx *gorm.DB) error {
+		for _, money := range moneyColumns {
+			for _, column := range money.columns {
+				// Databases created with AutoMigrate already store cents
+				integer, err := integerColumn(tx, money.table, column)
+				if err != nil {
+					return err
+				}
+				if integer {
+					continue
+				}
+				if err := tx.Exec("UPDATE " + money.table + " SET " + column + " = ROUND(" + column + " * 100)").Error; err != nil {
+					return err
+				}
+				if err := alterColumn(tx, money.table, money.cents, column); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	},
+	Down: func(tx *gorm.DB) error {
+		for _, money := range moneyColumns {
+			for _, column := range money.columns {
+				if err := alterColumn(tx, money.table, money.dollars, column); err != nil {
+					return err
+				}
+				if err := tx.Exec("UPDATE " + money.table + " SET " + column + " = " + column + " / 100.0").Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	},
+}
+
+// integerColumn reports whether the table's column already has an integer type
+func integerColumn(tx *gorm.DB, table, column string) (bool, error) {
+	columnTypes, err := tx.Migrator().ColumnTypes(table)
+	if err != nil {
+		return false, err
+	}
+	for _, columnType := range columnTypes {
+		if strings.EqualFold(columnType.Name(), column) {
+			return strings.Contains(strings.ToLower(columnType.DatabaseTypeName()), "int"), nil
+		}
+	}
+	return false, nil
+}
+
+// alterColumn changes the column to its type in the model. SQLite does so by
+// rebuilding the table, which loses its indexes, so they are created again.
+func alterColumn(tx *gorm.DB, table string, model interface{}, column string) error {
+	var indexes []string
+	if tx.Dialector.Name() == "sqlite" {
+		err := tx.Raw("SELECT sql FROM sqlite_master WHERE type = ? AND tbl_name = ? AND sql IS NOT NULL", "index", table).
+			Scan(&indexes).Error
+		if err != nil {
+			return err
+		}
+	}
+	if err := tx.Migrator().AlterColumn(model, column); err != nil {
+		return err
+	}
+	for _, index := range indexes {
+		if err := tx.Exec(index).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
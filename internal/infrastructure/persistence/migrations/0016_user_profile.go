@@ -0,0 +1,37 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type user0016 struct {
+	BirthDate        *time.Time
+	EmploymentStatus string `gorm:"type:varchar(20)"`
+	Dependents       int    `gorm:"default:0"`
+	MonthlyIncome    int64  `gorm:"default:0"`
+}
+
+func (user0016) TableName() string { return "users" }
+
+// userProfileFields lists the columns userProfile adds
+var userProfileFields = []string{"BirthDate", "EmploymentStatus", "Dependents", "MonthlyIncome"}
+
+// userProfile adds the profile the advisor uses in place of assumed values:
+// birth date, employment status, dependents and monthly income in cents
+var userProfile = Migration{
+	Version: 16,
+	Name:    "user_profile",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&user0016{})
+	},
+	Down: func(tx *gorm.DB) error {
+		for _, field := range userProfileFields {
+			if err := dropColumn(tx, &user0016{}, "users", field); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type riskAssessment0017 struct {
+	ID                   uint   `gorm:"primaryKey"`
+	UserID               uint   `gorm:"index:idx_risk_assessments_user_created,priority:1"`
+	QuestionnaireVersion string `gorm:"type:varchar(20)"`
+	Answers              string `gorm:"type:text"`
+	Score                int
+	MaxScore             int
+	RiskProfile          string    `gorm:"type:varchar(20)"`
+	CreatedAt            time.Time `gorm:"index:idx_risk_assessments_user_created,priority:2"`
+}
+
+func (riskAssessment0017) TableName() string { return "risk_assessments" }
+
+// riskAssessments keeps the questionnaires users answered and the risk
+// profile each one gave
+var riskAssessments = Migration{
+	Version: 17,
+	Name:    "risk_assessments",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&riskAssessment0017{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&riskAssessment0017{})
+	},
+}
@@ -0,0 +1,22 @@
+package migrations
+
+import "gorm.io/gorm"
+
+type user0018 struct {
+	Locale string `gorm:"type:varchar(10);default:'en'"`
+}
+
+func (user0018) TableName() string { return "users" }
+
+// userLocale adds the language the user's messages, reports and exports are
+// written in
+var userLocale = Migration{
+	Version: 18,
+	Name:    "user_locale",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&user0018{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return dropColumn(tx, &user0018{}, "users", "Locale")
+	},
+}
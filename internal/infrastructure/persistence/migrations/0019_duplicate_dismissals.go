@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type duplicateDismissal0019 struct {
+	ID                 uint `gorm:"primaryKey"`
+	UserID             uint `gorm:"index;not null"`
+	TransactionID      uint `gorm:"uniqueIndex:idx_duplicate_dismissals_pair,priority:1;not null"`
+	OtherTransactionID uint `gorm:"uniqueIndex:idx_duplicate_dismissals_pair,priority:2;not null"`
+	CreatedAt          time.Time
+}
+
+func (duplicateDismissal0019) TableName() string { return "duplicate_dismissals" }
+
+// duplicateDismissals keeps the transaction pairs users said are not
+// duplicates, so they are not flagged again
+var duplicateDismissals = Migration{
+	Version: 19,
+	Name:    "duplicate_dismissals",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&duplicateDismissal0019{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&duplicateDismissal0019{})
+	},
+}
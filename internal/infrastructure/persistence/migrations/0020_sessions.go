@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type session0020 struct {
+	ID               uint   `gorm:"primaryKey"`
+	UserID           uint   `gorm:"index;not null"`
+	RefreshTokenHash string `gorm:"type:varchar(64);uniqueIndex;not null"`
+	UserAgent        string `gorm:"type:varchar(255)"`
+	IPAddress        string `gorm:"type:varchar(45)"`
+	CreatedAt        time.Time
+	LastUsedAt       time.Time
+	ExpiresAt        time.Time
+	RevokedAt        *time.Time
+}
+
+func (session0020) TableName() string { return "sessions" }
+
+// sessions tracks logins per device, with the hashes of their refresh tokens
+var sessions = Migration{
+	Version: 20,
+	Name:    "sessions",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&session0020{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&session0020{})
+	},
+}
@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type account0021 struct {
+	ID             uint   `gorm:"primaryKey"`
+	UserID         uint   `gorm:"index;not null"`
+	Name           string `gorm:"type:varchar(100);not null"`
+	Type           string `gorm:"type:varchar(20);not null"`
+	Institution    string `gorm:"type:varchar(100)"`
+	AccountNumber  string `gorm:"type:varchar(255)"`
+	Currency       string `gorm:"type:varchar(3);not null;default:USD"`
+	OpeningBalance int64  `gorm:"type:integer;not null;default:0"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func (account0021) TableName() string { return "accounts" }
+
+// accounts adds the accounts users hold; their numbers are stored encrypted
+var accounts = Migration{
+	Version: 21,
+	Name:    "accounts",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&account0021{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&account0021{})
+	},
+}
@@ -0,0 +1,36 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type financialGoal0022 struct {
+	ID            uint   `gorm:"primaryKey"`
+	UserID        uint   `gorm:"not null;index"`
+	Title         string `gorm:"not null"`
+	Description   string
+	TargetAmount  int64 `gorm:"type:integer;not null"`
+	CurrentAmount int64 `gorm:"type:integer;default:0"`
+	TargetDate    time.Time
+	GoalType      string `gorm:"not null"`
+	Status        string `gorm:"default:active"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (financialGoal0022) TableName() string { return "financial_goals" }
+
+// financialGoals adds the savings goals the dashboard and the budget
+// simulator report on
+var financialGoals = Migration{
+	Version: 22,
+	Name:    "financial_goals",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&financialGoal0022{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&financialGoal0022{})
+	},
+}
@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type categoryCap0023 struct {
+	ID         uint  `gorm:"primaryKey"`
+	UserID     uint  `gorm:"uniqueIndex:idx_category_caps_user_category,priority:1"`
+	CategoryID uint  `gorm:"uniqueIndex:idx_category_caps_user_category,priority:2"`
+	Amount     int64 `gorm:"type:integer;not null"`
+	Block      bool  `gorm:"default:false"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (categoryCap0023) TableName() string { return "category_caps" }
+
+// categoryCaps adds the monthly spending caps users set on categories.
+var categoryCaps = Migration{
+	Version: 23,
+	Name:    "category_caps",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&categoryCap0023{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&categoryCap0023{})
+	},
+}
@@ -0,0 +1,31 @@
+package migrations
+
+import "gorm.io/gorm"
+
+type transaction0024 struct {
+	Notes string `gorm:"type:text"`
+}
+
+func (transaction0024) TableName() string { return "transactions" }
+
+type transactionTag0024 struct {
+	TransactionID uint   `gorm:"primaryKey"`
+	Tag           string `gorm:"primaryKey;type:varchar(50);index"`
+}
+
+func (transactionTag0024) TableName() string { return "transaction_tags" }
+
+// transactionNotesTags adds free-form notes and tags to transactions
+var transactionNotesTags = Migration{
+	Version: 24,
+	Name:    "transaction_notes_tags",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&transaction0024{}, &transactionTag0024{})
+	},
+	Down: func(tx *gorm.DB) error {
+		if err := tx.Migrator().DropTable(&transactionTag0024{}); err != nil {
+			return err
+		}
+		return dropColumn(tx, &transaction0024{}, "transactions", "Notes")
+	},
+}
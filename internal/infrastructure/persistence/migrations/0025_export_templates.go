@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type exportTemplate0025 struct {
+	ID              uint   `gorm:"primaryKey"`
+	UserID          uint   `gorm:"not null;index"`
+	Name            string `gorm:"type:varchar(100);not null"`
+	Columns         string `gorm:"type:text"`
+	DateFormat      string `gorm:"type:varchar(20)"`
+	Delimiter       string `gorm:"type:varchar(1)"`
+	FilenamePattern string `gorm:"type:varchar(100)"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+func (exportTemplate0025) TableName() string { return "export_templates" }
+
+// exportTemplates adds the saved layouts of transaction exports.
+var exportTemplates = Migration{
+	Version: 25,
+	Name:    "export_templates",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&exportTemplate0025{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&exportTemplate0025{})
+	},
+}
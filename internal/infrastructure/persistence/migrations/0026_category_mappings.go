@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type categoryMapping0026 struct {
+	ID               uint   `gorm:"primaryKey"`
+	UserID           uint   `gorm:"uniqueIndex:idx_category_mappings_user_source_external,priority:1"`
+	Source           string `gorm:"type:varchar(20);uniqueIndex:idx_category_mappings_user_source_external,priority:2"`
+	ExternalCategory string `gorm:"type:varchar(100);uniqueIndex:idx_category_mappings_user_source_external,priority:3"`
+	CategoryID       uint   `gorm:"not null"`
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+func (categoryMapping0026) TableName() string { return "category_mappings" }
+
+// categoryMappings adds the mappings of other apps' categories used by imports.
+var categoryMappings = Migration{
+	Version: 26,
+	Name:    "category_mappings",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&categoryMapping0026{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&categoryMapping0026{})
+	},
+}
@@ -0,0 +1,52 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type bankLink0027 struct {
+	ID                uint   `gorm:"primaryKey"`
+	UserID            uint   `gorm:"uniqueIndex:idx_bank_links_user_provider_account,priority:1;not null"`
+	Provider          string `gorm:"type:varchar(20);uniqueIndex:idx_bank_links_user_provider_account,priority:2;not null"`
+	ExternalAccountID string `gorm:"type:varchar(100);uniqueIndex:idx_bank_links_user_provider_account,priority:3;not null"`
+	ConnectionID      string `gorm:"type:varchar(100);index;not null"`
+	AccessToken       string `gorm:"type:varchar(255);not null"`
+	Cursor            string `gorm:"type:text"`
+	Institution       string `gorm:"type:varchar(100)"`
+	Name              string `gorm:"type:varchar(100)"`
+	Mask              string `gorm:"type:varchar(4)"`
+	Currency          string `gorm:"type:varchar(3)"`
+	Status            string `gorm:"type:varchar(20);not null;default:pending"`
+	LastSyncedAt      *time.Time
+	LastError         string `gorm:"type:text"`
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+func (bankLink0027) TableName() string { return "bank_links" }
+
+type bankTransaction0027 struct {
+	ID            uint   `gorm:"primaryKey"`
+	LinkID        uint   `gorm:"uniqueIndex:idx_bank_transactions_link_external,priority:1;not null"`
+	ExternalID    string `gorm:"type:varchar(100);uniqueIndex:idx_bank_transactions_link_external,priority:2;not null"`
+	TransactionID uint   `gorm:"index;not null"`
+	Created       bool   `gorm:"not null;default:false"`
+	CreatedAt     time.Time
+}
+
+func (bankTransaction0027) TableName() string { return "bank_transactions" }
+
+// bankLinks adds bank accounts linked through Plaid or GoCardless and the
+// record of which transactions their syncs brought in.
+var bankLinks = Migration{
+	Version: 27,
+	Name:    "bank_links",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&bankLink0027{}, &bankTransaction0027{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&bankTransaction0027{}, &bankLink0027{})
+	},
+}
@@ -0,0 +1,36 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type job0028 struct {
+	ID          uint      `gorm:"primaryKey"`
+	Type        string    `gorm:"type:varchar(50);index;not null"`
+	Payload     string    `gorm:"type:text"`
+	Status      string    `gorm:"type:varchar(20);index:idx_jobs_status_run_at,priority:1;not null"`
+	RunAt       time.Time `gorm:"index:idx_jobs_status_run_at,priority:2;not null"`
+	Attempts    int       `gorm:"not null;default:0"`
+	MaxAttempts int       `gorm:"not null"`
+	LastError   string    `gorm:"type:text"`
+	LockedAt    *time.Time
+	FinishedAt  *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (job0028) TableName() string { return "jobs" }
+
+// jobs adds the queue of background jobs.
+var jobs = Migration{
+	Version: 28,
+	Name:    "jobs",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&job0028{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&job0028{})
+	},
+}
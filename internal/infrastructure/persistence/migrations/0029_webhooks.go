@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type webhook0029 struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"not null;index"`
+	URL       string `gorm:"type:varchar(2048);not null"`
+	Events    string `gorm:"type:text"`
+	Secret    string `gorm:"type:varchar(64);not null"`
+	Active    bool   `gorm:"not null;default:true"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (webhook0029) TableName() string { return "webhooks" }
+
+// webhooks adds the URLs users have their data's events posted to.
+var webhooks = Migration{
+	Version: 29,
+	Name:    "webhooks",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&webhook0029{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&webhook0029{})
+	},
+}
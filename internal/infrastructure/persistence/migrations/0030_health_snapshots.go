@@ -0,0 +1,36 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type healthSnapshot0030 struct {
+	ID            uint      `gorm:"primaryKey"`
+	UserID        uint      `gorm:"not null;uniqueIndex:idx_health_snapshots_user_date,priority:1"`
+	Date          time.Time `gorm:"not null;uniqueIndex:idx_health_snapshots_user_date,priority:2"`
+	OverallScore  int       `gorm:"not null"`
+	SavingsScore  int       `gorm:"not null"`
+	SpendingScore int       `gorm:"not null"`
+	BudgetScore   int       `gorm:"not null"`
+	HealthStatus  string    `gorm:"type:varchar(20);not null"`
+	SavingsRate   float64   `gorm:"not null"`
+	TotalIncome   int64     `gorm:"type:integer;not null;default:0"`
+	TotalExpenses int64     `gorm:"type:integer;not null;default:0"`
+	CreatedAt     time.Time
+}
+
+func (healthSnapshot0030) TableName() string { return "health_snapshots" }
+
+// healthSnapshots adds the daily financial health scores users chart over time
+var healthSnapshots = Migration{
+	Version: 30,
+	Name:    "health_snapshots",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&healthSnapshot0030{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&healthSnapshot0030{})
+	},
+}
@@ -0,0 +1,37 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type bill0031 struct {
+	ID               uint   `gorm:"primaryKey"`
+	UserID           uint   `gorm:"not null;index"`
+	Payee            string `gorm:"type:varchar(100);not null"`
+	Amount           int64  `gorm:"type:integer;not null"`
+	DueDay           int    `gorm:"not null"`
+	Autopay          bool   `gorm:"not null;default:false"`
+	CategoryID       *uint
+	Notes            string `gorm:"type:text"`
+	RemindDaysBefore int    `gorm:"not null"`
+	Active           bool   `gorm:"not null;default:true"`
+	LastRemindedFor  *time.Time
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+func (bill0031) TableName() string { return "bills" }
+
+// bills adds the monthly bills users are reminded of before their due dates
+var bills = Migration{
+	Version: 31,
+	Name:    "bills",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&bill0031{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&bill0031{})
+	},
+}
@@ -0,0 +1,25 @@
+package migrations
+
+import "gorm.io/gorm"
+
+type transaction0032 struct {
+	AccountID *uint `gorm:"index"`
+}
+
+func (transaction0032) TableName() string { return "transactions" }
+
+// transactionAccounts lets transactions name the account they moved money
+// in or out of. Existing transactions belong to no account.
+var transactionAccounts = Migration{
+	Version: 32,
+	Name:    "transaction_accounts",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&transaction0032{})
+	},
+	Down: func(tx *gorm.DB) error {
+		if err := tx.Migrator().DropIndex(&transaction0032{}, "AccountID"); err != nil {
+			return err
+		}
+		return dropColumn(tx, &transaction0032{}, "transactions", "AccountID")
+	},
+}
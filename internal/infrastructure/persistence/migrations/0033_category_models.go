@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type categoryModel0033 struct {
+	ID         uint   `gorm:"primaryKey"`
+	UserID     uint   `gorm:"uniqueIndex;not null"`
+	Classifier string `gorm:"type:text"`
+	Samples    int
+	Categories int
+	Evaluation string `gorm:"type:text"`
+	TrainedAt  time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (categoryModel0033) TableName() string { return "category_models" }
+
+// categoryModels adds the per-user models suggesting categories from
+// transaction descriptions
+var categoryModels = Migration{
+	Version: 33,
+	Name:    "category_models",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&categoryModel0033{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&categoryModel0033{})
+	},
+}
@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type holding0034 struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"uniqueIndex:idx_holdings_user_symbol,priority:1"`
+	Symbol    string `gorm:"type:varchar(20);uniqueIndex:idx_holdings_user_symbol,priority:2"`
+	AssetType string `gorm:"type:varchar(20)"`
+	Quantity  float64
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (holding0034) TableName() string { return "holdings" }
+
+// holdings adds the market symbols users own, which portfolio risk is measured on
+var holdings = Migration{
+	Version: 34,
+	Name:    "holdings",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&holding0034{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&holding0034{})
+	},
+}
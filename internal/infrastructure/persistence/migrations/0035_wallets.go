@@ -0,0 +1,48 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type wallet0035 struct {
+	ID           uint    `gorm:"primaryKey"`
+	UserID       uint    `gorm:"uniqueIndex:idx_wallets_user_chain_address,priority:1;not null"`
+	Chain        string  `gorm:"type:varchar(10);uniqueIndex:idx_wallets_user_chain_address,priority:2;not null"`
+	Address      string  `gorm:"type:varchar(100);uniqueIndex:idx_wallets_user_chain_address,priority:3;not null"`
+	Label        string  `gorm:"type:varchar(100)"`
+	Balance      float64 `gorm:"not null;default:0"`
+	LastSyncedAt *time.Time
+	LastError    string `gorm:"type:text"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (wallet0035) TableName() string { return "wallets" }
+
+type walletTransaction0035 struct {
+	ID        uint       `gorm:"primaryKey"`
+	WalletID  uint       `gorm:"uniqueIndex:idx_wallet_transactions_wallet_hash,priority:1;not null"`
+	Hash      string     `gorm:"type:varchar(100);uniqueIndex:idx_wallet_transactions_wallet_hash,priority:2;not null"`
+	Amount    float64    `gorm:"not null"`
+	Fee       float64    `gorm:"not null;default:0"`
+	Confirmed bool       `gorm:"not null;default:false"`
+	Time      *time.Time `gorm:"index"`
+	CreatedAt time.Time
+}
+
+func (walletTransaction0035) TableName() string { return "wallet_transactions" }
+
+// wallets adds the public BTC and ETH addresses users track and the
+// transactions read for them
+var wallets = Migration{
+	Version: 35,
+	Name:    "wallets",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&wallet0035{}, &walletTransaction0035{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&walletTransaction0035{}, &wallet0035{})
+	},
+}
@@ -0,0 +1,46 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type exchangeRate0036 struct {
+	ID        uint      `gorm:"primaryKey"`
+	Base      string    `gorm:"type:varchar(3);uniqueIndex:idx_exchange_rates_pair_date,priority:1;not null"`
+	Quote     string    `gorm:"type:varchar(3);uniqueIndex:idx_exchange_rates_pair_date,priority:2;not null"`
+	Date      time.Time `gorm:"uniqueIndex:idx_exchange_rates_pair_date,priority:3;not null"`
+	Rate      float64   `gorm:"not null"`
+	Source    string    `gorm:"type:varchar(20)"`
+	CreatedAt time.Time
+}
+
+func (exchangeRate0036) TableName() string { return "exchange_rates" }
+
+type transaction0036 struct {
+	Currency       string `gorm:"type:varchar(3)"`
+	OriginalAmount *int64
+	ExchangeRate   *float64
+}
+
+func (transaction0036) TableName() string { return "transactions" }
+
+// exchangeRates adds the historical exchange rates foreign currency
+// transactions are converted at, and the original currency, amount and rate
+// to transactions. Existing transactions are in the base currency.
+var exchangeRates = Migration{
+	Version: 36,
+	Name:    "exchange_rates",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&exchangeRate0036{}, &transaction0036{})
+	},
+	Down: func(tx *gorm.DB) error {
+		for _, field := range []string{"ExchangeRate", "OriginalAmount", "Currency"} {
+			if err := dropColumn(tx, &transaction0036{}, "transactions", field); err != nil {
+				return err
+			}
+		}
+		return tx.Migrator().DropTable(&exchangeRate0036{})
+	},
+}
@@ -0,0 +1,51 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type user0037 struct {
+	AccountType string `gorm:"type:varchar(20);default:'personal'"`
+}
+
+func (user0037) TableName() string { return "users" }
+
+type advisorGrant0037 struct {
+	ID        uint   `gorm:"primaryKey"`
+	ClientID  uint   `gorm:"uniqueIndex:idx_advisor_grants_client_advisor,priority:1;not null"`
+	AdvisorID uint   `gorm:"uniqueIndex:idx_advisor_grants_client_advisor,priority:2;index;not null"`
+	Access    string `gorm:"type:varchar(20);not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (advisorGrant0037) TableName() string { return "advisor_grants" }
+
+type advisorRecommendation0037 struct {
+	ID        uint      `gorm:"primaryKey"`
+	ClientID  uint      `gorm:"index;not null"`
+	AdvisorID uint      `gorm:"index;not null"`
+	Message   string    `gorm:"type:text;not null"`
+	CreatedAt time.Time `gorm:"index"`
+}
+
+func (advisorRecommendation0037) TableName() string { return "advisor_recommendations" }
+
+// advisorAccess adds account types to users, the access clients grant
+// advisor accounts and the recommendations advisors leave them. Existing
+// users have personal accounts.
+var advisorAccess = Migration{
+	Version: 37,
+	Name:    "advisor_access",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&user0037{}, &advisorGrant0037{}, &advisorRecommendation0037{})
+	},
+	Down: func(tx *gorm.DB) error {
+		if err := tx.Migrator().DropTable(&advisorRecommendation0037{}, &advisorGrant0037{}); err != nil {
+			return err
+		}
+		return dropColumn(tx, &user0037{}, "users", "AccountType")
+	},
+}
@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type comment0038 struct {
+	ID         uint   `gorm:"primaryKey"`
+	TargetType string `gorm:"type:varchar(20);index:idx_comments_target,priority:1;not null"`
+	TargetID   uint   `gorm:"index:idx_comments_target,priority:2;not null"`
+	AuthorID   uint   `gorm:"index;not null"`
+	Body       string `gorm:"type:text;not null"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (comment0038) TableName() string { return "comments" }
+
+// comments adds the comments users leave on transactions, budgets and reports
+var comments = Migration{
+	Version: 38,
+	Name:    "comments",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&comment0038{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&comment0038{})
+	},
+}
@@ -0,0 +1,26 @@
+package migrations
+
+import "gorm.io/gorm"
+
+type budget0039 struct {
+	Anchor    string `gorm:"type:varchar(20);default:'start_date'"`
+	AnchorDay int    `gorm:"default:0"`
+}
+
+func (budget0039) TableName() string { return "budgets" }
+
+// budgetAnchors lets budget periods line up with calendar months, ISO weeks
+// or paydays. Existing budgets keep running from their start dates.
+var budgetAnchors = Migration{
+	Version: 39,
+	Name:    "budget_anchors",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&budget0039{})
+	},
+	Down: func(tx *gorm.DB) error {
+		if err := dropColumn(tx, &budget0039{}, "budgets", "AnchorDay"); err != nil {
+			return err
+		}
+		return dropColumn(tx, &budget0039{}, "budgets", "Anchor")
+	},
+}
@@ -0,0 +1,46 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type savingsRule0040 struct {
+	ID         uint `gorm:"primaryKey"`
+	UserID     uint `gorm:"index;not null"`
+	GoalID     uint `gorm:"index;not null"`
+	CategoryID *uint
+	Name       string  `gorm:"type:varchar(100)"`
+	Percent    float64 `gorm:"not null"`
+	IsActive   bool    `gorm:"not null"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (savingsRule0040) TableName() string { return "savings_rules" }
+
+type savingsAllocation0040 struct {
+	ID            uint  `gorm:"primaryKey"`
+	UserID        uint  `gorm:"index;not null"`
+	RuleID        uint  `gorm:"uniqueIndex:idx_savings_allocations_rule_transaction,priority:1"`
+	TransactionID uint  `gorm:"uniqueIndex:idx_savings_allocations_rule_transaction,priority:2;index"`
+	GoalID        uint  `gorm:"index;not null"`
+	Amount        int64 `gorm:"type:integer;not null"`
+	CreatedAt     time.Time
+}
+
+func (savingsAllocation0040) TableName() string { return "savings_allocations" }
+
+// savingsRules adds the rules that set aside part of users' income for their
+// goals, and what they set aside
+var savingsRules = Migration{
+	Version: 40,
+	Name:    "savings_rules",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&savingsRule0040{}, &savingsAllocation0040{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&savingsAllocation0040{}, &savingsRule0040{})
+	},
+}
@@ -0,0 +1,30 @@
+package migrations
+
+import "gorm.io/gorm"
+
+type transaction0041 struct {
+	Latitude  *float64
+	Longitude *float64
+	Place     string `gorm:"type:varchar(100)"`
+	City      string `gorm:"type:varchar(100)"`
+}
+
+func (transaction0041) TableName() string { return "transactions" }
+
+// transactionLocations records where transactions were made, as mobile
+// clients report it
+var transactionLocations = Migration{
+	Version: 41,
+	Name:    "transaction_locations",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&transaction0041{})
+	},
+	Down: func(tx *gorm.DB) error {
+		for _, field := range []string{"City", "Place", "Longitude", "Latitude"} {
+			if err := dropColumn(tx, &transaction0041{}, "transactions", field); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
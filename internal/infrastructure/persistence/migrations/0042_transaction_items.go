@@ -0,0 +1,28 @@
+package migrations
+
+import "gorm.io/gorm"
+
+type transactionItem0042 struct {
+	ID            uint    `gorm:"primaryKey"`
+	TransactionID uint    `gorm:"index;not null"`
+	Position      int     `gorm:"not null"`
+	Name          string  `gorm:"type:varchar(100);not null"`
+	Quantity      float64 `gorm:"not null"`
+	UnitPrice     int64   `gorm:"type:integer;not null"`
+	Amount        int64   `gorm:"type:integer;not null"`
+}
+
+func (transactionItem0042) TableName() string { return "transaction_items" }
+
+// transactionItems adds the line items of transactions, entered by hand or
+// read from scanned receipts
+var transactionItems = Migration{
+	Version: 42,
+	Name:    "transaction_items",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&transactionItem0042{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&transactionItem0042{})
+	},
+}
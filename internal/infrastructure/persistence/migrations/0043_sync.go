@@ -0,0 +1,65 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type syncChange0043 struct {
+	ID         uint   `gorm:"primaryKey"`
+	UserID     uint   `gorm:"index;not null"`
+	EntityType string `gorm:"type:varchar(30);uniqueIndex:idx_sync_changes_entity_version,priority:1;not null"`
+	EntityID   uint   `gorm:"uniqueIndex:idx_sync_changes_entity_version,priority:2;not null"`
+	Version    int64  `gorm:"uniqueIndex:idx_sync_changes_entity_version,priority:3;not null"`
+	Operation  string `gorm:"type:varchar(10);not null"`
+	CreatedAt  time.Time
+}
+
+func (syncChange0043) TableName() string { return "sync_changes" }
+
+type syncMutationClient0043 struct {
+	UserID    uint   `gorm:"primaryKey"`
+	ClientID  string `gorm:"primaryKey;type:varchar(100)"`
+	EntityID  uint   `gorm:"not null"`
+	CreatedAt time.Time
+}
+
+func (syncMutationClient0043) TableName() string { return "sync_mutation_clients" }
+
+type syncConflict0043 struct {
+	ID               uint   `gorm:"primaryKey"`
+	UserID           uint   `gorm:"index;not null"`
+	EntityType       string `gorm:"type:varchar(30);not null"`
+	EntityID         uint   `gorm:"not null"`
+	Operation        string `gorm:"type:varchar(10);not null"`
+	BaseVersion      int64
+	ServerVersion    int64
+	Resolution       string `gorm:"type:varchar(20);not null"`
+	ClientModifiedAt time.Time
+	ServerModifiedAt time.Time
+	ClientData       string    `gorm:"type:text"`
+	ServerData       string    `gorm:"type:text"`
+	CreatedAt        time.Time `gorm:"index"`
+}
+
+func (syncConflict0043) TableName() string { return "sync_conflicts" }
+
+// syncFeed adds the change feed offline clients sync from, the client IDs of
+// the transactions they created and the conflicts of their pushes. Existing
+// transactions enter the feed at version 1, so a first sync gets them all.
+var syncFeed = Migration{
+	Version: 43,
+	Name:    "sync",
+	Up: func(tx *gorm.DB) error {
+		if err := tx.AutoMigrate(&syncChange0043{}, &syncMutationClient0043{}, &syncConflict0043{}); err != nil {
+			return err
+		}
+		return tx.Exec("INSERT INTO sync_changes (user_id, entity_type, entity_id, version, operation, created_at) " +
+			"SELECT user_id, 'transaction', id, 1, CASE WHEN deleted_at IS NULL THEN 'upsert' ELSE 'delete' END, " +
+			"COALESCE(updated_at, CURRENT_TIMESTAMP) FROM transactions ORDER BY id").Error
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&syncConflict0043{}, &syncMutationClient0043{}, &syncChange0043{})
+	},
+}
@@ -0,0 +1,38 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type user0044 struct {
+	Plan string `gorm:"type:varchar(20);default:'free'"`
+}
+
+func (user0044) TableName() string { return "users" }
+
+type usageCounter0044 struct {
+	UserID uint      `gorm:"primaryKey"`
+	Quota  string    `gorm:"primaryKey;type:varchar(30)"`
+	Day    time.Time `gorm:"primaryKey"`
+	Count  int       `gorm:"not null;default:0"`
+}
+
+func (usageCounter0044) TableName() string { return "usage_counters" }
+
+// plans adds the plan each user is on, existing users starting on the free
+// plan, and the daily counts of their quotas
+var plans = Migration{
+	Version: 44,
+	Name:    "plans",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&user0044{}, &usageCounter0044{})
+	},
+	Down: func(tx *gorm.DB) error {
+		if err := tx.Migrator().DropTable(&usageCounter0044{}); err != nil {
+			return err
+		}
+		return dropColumn(tx, &user0044{}, "users", "Plan")
+	},
+}
@@ -0,0 +1,40 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type user0045 struct {
+	BillingCustomerID     string `gorm:"type:varchar(100);index"`
+	SubscriptionID        string `gorm:"type:varchar(100)"`
+	SubscriptionStatus    string `gorm:"type:varchar(20)"`
+	SubscriptionPeriodEnd *time.Time
+}
+
+func (user0045) TableName() string { return "users" }
+
+// subscriptionFields lists the columns subscriptions adds
+var subscriptionFields = []string{"BillingCustomerID", "SubscriptionID", "SubscriptionStatus", "SubscriptionPeriodEnd"}
+
+// subscriptions adds the premium subscription each user bought through the
+// billing provider
+var subscriptions = Migration{
+	Version: 45,
+	Name:    "subscriptions",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&user0045{})
+	},
+	Down: func(tx *gorm.DB) error {
+		if err := tx.Migrator().DropIndex(&user0045{}, "idx_users_billing_customer_id"); err != nil {
+			return err
+		}
+		for _, field := range subscriptionFields {
+			if err := dropColumn(tx, &user0045{}, "users", field); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
@@ -0,0 +1,48 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type requestActivity0046 struct {
+	UserID       uint      `gorm:"primaryKey"`
+	Day          time.Time `gorm:"primaryKey"`
+	Requests     int       `gorm:"not null;default:0"`
+	ClientErrors int       `gorm:"not null;default:0"`
+	ServerErrors int       `gorm:"not null;default:0"`
+}
+
+func (requestActivity0046) TableName() string { return "request_activities" }
+
+type dailyStats0046 struct {
+	Day             time.Time `gorm:"primaryKey"`
+	TotalUsers      int       `gorm:"not null;default:0"`
+	NewUsers        int       `gorm:"not null;default:0"`
+	ActiveUsers     int       `gorm:"not null;default:0"`
+	Transactions    int       `gorm:"not null;default:0"`
+	AdvisorCalls    int       `gorm:"not null;default:0"`
+	Requests        int       `gorm:"not null;default:0"`
+	ClientErrors    int       `gorm:"not null;default:0"`
+	ServerErrors    int       `gorm:"not null;default:0"`
+	ClientErrorRate float64   `gorm:"not null;default:0"`
+	ServerErrorRate float64   `gorm:"not null;default:0"`
+	TopCategories   string    `gorm:"type:text"`
+	ComputedAt      time.Time
+}
+
+func (dailyStats0046) TableName() string { return "daily_stats" }
+
+// usageStats adds the daily request counts of each user and the anonymous
+// daily usage stats computed from them for admins
+var usageStats = Migration{
+	Version: 46,
+	Name:    "usage_stats",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&requestActivity0046{}, &dailyStats0046{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&dailyStats0046{}, &requestActivity0046{})
+	},
+}
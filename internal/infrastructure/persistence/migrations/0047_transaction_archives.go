@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type transactionArchive0047 struct {
+	ID           uint `gorm:"primaryKey"`
+	UserID       uint `gorm:"not null;uniqueIndex:idx_transaction_archives_user_year"`
+	Year         int  `gorm:"not null;uniqueIndex:idx_transaction_archives_user_year"`
+	Transactions int  `gorm:"not null;default:0"`
+	FromDate     time.Time
+	ToDate       time.Time
+	Size         int `gorm:"not null;default:0"`
+	Data         []byte
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (transactionArchive0047) TableName() string { return "transaction_archives" }
+
+// transactionArchives adds the compressed yearly archives that old
+// transactions are moved into
+var transactionArchives = Migration{
+	Version: 47,
+	Name:    "transaction_archives",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&transactionArchive0047{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&transactionArchive0047{})
+	},
+}
@@ -0,0 +1,63 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type category0048 struct {
+	ID          uint   `gorm:"primaryKey"`
+	UserID      *uint  `gorm:"uniqueIndex:idx_categories_user_name,priority:1"`
+	Name        string `gorm:"type:varchar(50);uniqueIndex:idx_categories_user_name,priority:2"`
+	Type        string `gorm:"type:varchar(10)"`
+	Description string
+	Icon        string
+	Color       string
+	IsDefault   bool `gorm:"default:false"`
+	IsSystem    bool `gorm:"default:false"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (category0048) TableName() string { return "categories" }
+
+// uncategorizedCategory adds the protected Uncategorized system category, a
+// shared one without a type, and files the transactions under it whose
+// category is missing or no longer exists. A shared category that already
+// has the name becomes the system category. Rolling back leaves those
+// transactions without a category again.
+var uncategorizedCategory = Migration{
+	Version: 48,
+	Name:    "uncategorized_category",
+	Up: func(tx *gorm.DB) error {
+		if err := tx.AutoMigrate(&category0048{}); err != nil {
+			return err
+		}
+
+		category := category0048{Name: "Uncategorized"}
+		err := tx.Where("user_id IS NULL AND name = ?", category.Name).
+			Attrs(category0048{Description: "Transactions without a category yet", Icon: "❔", Color: "#9E9E9E"}).
+			FirstOrCreate(&category).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&category).Updates(map[string]interface{}{"type": "", "is_default": true, "is_system": true}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Exec("UPDATE transactions SET category_id = ? "+
+			"WHERE category_id IS NULL OR category_id = 0 OR category_id NOT IN (SELECT id FROM categories)", category.ID).Error
+	},
+	Down: func(tx *gorm.DB) error {
+		err := tx.Exec("UPDATE transactions SET category_id = 0 "+
+			"WHERE category_id IN (SELECT id FROM categories WHERE is_system = ?)", true).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("is_system = ?", true).Delete(&category0048{}).Error; err != nil {
+			return err
+		}
+		return dropColumn(tx, &category0048{}, "categories", "IsSystem")
+	},
+}
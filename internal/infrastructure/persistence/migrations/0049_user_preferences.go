@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type userPreferences0049 struct {
+	UserID          uint   `gorm:"primaryKey;autoIncrement:false"`
+	Currency        string `gorm:"type:varchar(3);not null;default:USD"`
+	WeekStart       string `gorm:"type:varchar(10);not null;default:monday"`
+	DateFormat      string `gorm:"type:varchar(20)"`
+	NumberFormat    string `gorm:"type:varchar(20)"`
+	DashboardPeriod string `gorm:"type:varchar(10);not null;default:month"`
+	UpdatedAt       time.Time
+}
+
+func (userPreferences0049) TableName() string { return "user_preferences" }
+
+// userPreferences adds the display currency, first day of the week, date and
+// number formats and default dashboard period users can set
+var userPreferences = Migration{
+	Version: 49,
+	Name:    "user_preferences",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&userPreferences0049{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&userPreferences0049{})
+	},
+}
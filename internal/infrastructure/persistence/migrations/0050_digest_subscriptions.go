@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type digestSubscription0050 struct {
+	UserID           uint   `gorm:"primaryKey;autoIncrement:false"`
+	Frequency        string `gorm:"type:varchar(10);not null"`
+	UnsubscribeToken string `gorm:"type:varchar(64);uniqueIndex;not null"`
+	QueuedFor        *time.Time
+	LastSentAt       *time.Time
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+func (digestSubscription0050) TableName() string { return "digest_subscriptions" }
+
+// digestSubscriptions adds the opt-in to the weekly or monthly digest email
+var digestSubscriptions = Migration{
+	Version: 50,
+	Name:    "digest_subscriptions",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&digestSubscription0050{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&digestSubscription0050{})
+	},
+}
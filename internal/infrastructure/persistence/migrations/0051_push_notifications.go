@@ -0,0 +1,41 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type pushDevice0051 struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"index;not null"`
+	Platform  string `gorm:"type:varchar(10);not null"`
+	Token     string `gorm:"type:varchar(255);uniqueIndex;not null"`
+	Name      string `gorm:"type:varchar(100)"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (pushDevice0051) TableName() string { return "push_devices" }
+
+type notificationPreference0051 struct {
+	UserID  uint   `gorm:"primaryKey;autoIncrement:false"`
+	Channel string `gorm:"primaryKey;type:varchar(10)"`
+	Type    string `gorm:"primaryKey;type:varchar(30)"`
+	Enabled bool   `gorm:"not null"`
+}
+
+func (notificationPreference0051) TableName() string { return "notification_preferences" }
+
+// pushNotifications adds the devices push notifications are sent to and the
+// per channel notification toggles
+var pushNotifications = Migration{
+	Version: 51,
+	Name:    "push_notifications",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&pushDevice0051{}, &notificationPreference0051{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&notificationPreference0051{}, &pushDevice0051{})
+	},
+}
@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type calendarFeed0052 struct {
+	UserID     uint `gorm:"primaryKey;autoIncrement:false"`
+	Generation int  `gorm:"not null;default:1"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (calendarFeed0052) TableName() string { return "calendar_feeds" }
+
+// calendarFeeds adds the users' subscribable calendar feeds
+var calendarFeeds = Migration{
+	Version: 52,
+	Name:    "calendar_feeds",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&calendarFeed0052{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&calendarFeed0052{})
+	},
+}
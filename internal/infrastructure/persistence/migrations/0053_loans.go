@@ -0,0 +1,45 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type loan0053 struct {
+	ID         uint      `gorm:"primaryKey"`
+	UserID     uint      `gorm:"not null;index"`
+	Name       string    `gorm:"type:varchar(100);not null"`
+	Lender     string    `gorm:"type:varchar(100)"`
+	Principal  int64     `gorm:"type:integer;not null"`
+	APR        float64   `gorm:"not null"`
+	TermMonths int       `gorm:"not null"`
+	StartDate  time.Time `gorm:"not null"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (loan0053) TableName() string { return "loans" }
+
+type loanPayment0053 struct {
+	ID            uint `gorm:"primaryKey"`
+	UserID        uint `gorm:"not null;index"`
+	LoanID        uint `gorm:"not null;uniqueIndex:idx_loan_payments_loan_number,priority:1"`
+	Number        int  `gorm:"not null;uniqueIndex:idx_loan_payments_loan_number,priority:2"`
+	TransactionID uint `gorm:"not null;uniqueIndex"`
+	CreatedAt     time.Time
+}
+
+func (loanPayment0053) TableName() string { return "loan_payments" }
+
+// loans adds loans and the transactions linked to their payments
+var loans = Migration{
+	Version: 53,
+	Name:    "loans",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&loan0053{}, &loanPayment0053{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&loanPayment0053{}, &loan0053{})
+	},
+}
@@ -0,0 +1,46 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type incomeSource0054 struct {
+	ID         uint   `gorm:"primaryKey"`
+	UserID     uint   `gorm:"not null;index"`
+	Employer   string `gorm:"type:varchar(100);not null"`
+	Gross      int64  `gorm:"type:integer;not null"`
+	Deductions string `gorm:"type:text"`
+	Frequency  string `gorm:"type:varchar(20);not null"`
+	Active     bool   `gorm:"not null;default:true"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (incomeSource0054) TableName() string { return "income_sources" }
+
+type transaction0054 struct {
+	IncomeSourceID *uint `gorm:"index"`
+}
+
+func (transaction0054) TableName() string { return "transactions" }
+
+// incomeSources adds income sources and the transaction link to them.
+// Existing income stays unlinked until users link it to a source.
+var incomeSources = Migration{
+	Version: 54,
+	Name:    "income_sources",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&incomeSource0054{}, &transaction0054{})
+	},
+	Down: func(tx *gorm.DB) error {
+		if err := tx.Migrator().DropIndex(&transaction0054{}, "IncomeSourceID"); err != nil {
+			return err
+		}
+		if err := dropColumn(tx, &transaction0054{}, "transactions", "IncomeSourceID"); err != nil {
+			return err
+		}
+		return tx.Migrator().DropTable(&incomeSource0054{})
+	},
+}
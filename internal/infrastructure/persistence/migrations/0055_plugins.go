@@ -0,0 +1,48 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type plugin0055 struct {
+	ID             uint   `gorm:"primaryKey"`
+	UserID         uint   `gorm:"not null;index"`
+	Name           string `gorm:"type:varchar(100);not null"`
+	URL            string `gorm:"type:varchar(2048);not null"`
+	Events         string `gorm:"type:text"`
+	Secret         string `gorm:"type:varchar(64);not null"`
+	Enabled        bool   `gorm:"not null;default:true"`
+	TimeoutSeconds int    `gorm:"not null"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func (plugin0055) TableName() string { return "plugins" }
+
+type pluginRun0055 struct {
+	ID         uint      `gorm:"primaryKey"`
+	UserID     uint      `gorm:"not null;index"`
+	PluginID   uint      `gorm:"not null;index"`
+	Event      string    `gorm:"type:varchar(40);not null"`
+	Status     string    `gorm:"type:varchar(20);not null"`
+	DurationMS int64     `gorm:"not null"`
+	Actions    string    `gorm:"type:text"`
+	Error      string    `gorm:"type:varchar(500)"`
+	CreatedAt  time.Time `gorm:"index"`
+}
+
+func (pluginRun0055) TableName() string { return "plugin_runs" }
+
+// plugins adds users' plugins and the record of their runs
+var plugins = Migration{
+	Version: 55,
+	Name:    "plugins",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&plugin0055{}, &pluginRun0055{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&pluginRun0055{}, &plugin0055{})
+	},
+}
@@ -0,0 +1,27 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type replicationHeartbeat0056 struct {
+	ID     uint      `gorm:"primaryKey"`
+	BeatAt time.Time `gorm:"not null"`
+}
+
+func (replicationHeartbeat0056) TableName() string { return "replication_heartbeats" }
+
+// replicationHeartbeats adds the heartbeat the primary stamps to measure
+// the lag of read replicas
+var replicationHeartbeats = Migration{
+	Version: 56,
+	Name:    "replication_heartbeats",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&replicationHeartbeat0056{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&replicationHeartbeat0056{})
+	},
+}
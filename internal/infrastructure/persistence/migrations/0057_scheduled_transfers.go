@@ -0,0 +1,55 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type scheduledTransfer0057 struct {
+	ID            uint      `gorm:"primaryKey"`
+	UserID        uint      `gorm:"not null;index"`
+	FromAccountID uint      `gorm:"not null;index"`
+	ToAccountID   uint      `gorm:"not null;index"`
+	Amount        int64     `gorm:"type:integer;not null"`
+	Description   string    `gorm:"type:varchar(255);not null"`
+	Frequency     string    `gorm:"type:varchar(20);not null"`
+	StartDate     time.Time `gorm:"not null"`
+	EndDate       *time.Time
+	NextDate      *time.Time `gorm:"index"`
+	Occurrences   int        `gorm:"not null;default:0"`
+	Active        bool       `gorm:"not null;default:true"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (scheduledTransfer0057) TableName() string { return "scheduled_transfers" }
+
+type transaction0057 struct {
+	TransferAccountID   *uint `gorm:"index"`
+	ScheduledTransferID *uint `gorm:"index"`
+}
+
+func (transaction0057) TableName() string { return "transactions" }
+
+// scheduledTransfers adds transfers between accounts: the account a
+// transfer credits, the link to the schedule that made it and the
+// schedules themselves
+var scheduledTransfers = Migration{
+	Version: 57,
+	Name:    "scheduled_transfers",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&scheduledTransfer0057{}, &transaction0057{})
+	},
+	Down: func(tx *gorm.DB) error {
+		for _, field := range []string{"ScheduledTransferID", "TransferAccountID"} {
+			if err := tx.Migrator().DropIndex(&transaction0057{}, field); err != nil {
+				return err
+			}
+			if err := dropColumn(tx, &transaction0057{}, "transactions", field); err != nil {
+				return err
+			}
+		}
+		return tx.Migrator().DropTable(&scheduledTransfer0057{})
+	},
+}
@@ -0,0 +1,270 @@
+// Package migrations holds the versioned database schema. Every change to the
+// schema is a numbered migration with an up and a down step; applied versions
+// are recorded in the schema_migrations table so the API can refuse to start
+// against a database that does not match the binary.
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrSchemaBehind is returned by CheckVersion when migrations are pending
+	ErrSchemaBehind = errors.New("database schema is behind the application")
+	// ErrSchemaAhead is returned by CheckVersion when the database has versions this binary does not know
+	ErrSchemaAhead = errors.New("database schema is ahead of the application")
+)
+
+// Migration is a single versioned schema change. Up and Down run inside a
+// transaction together with the bookkeeping in schema_migrations.
+type Migration struct {
+	Version uint
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// Status describes one migration and whether it has been applied
+type Status struct {
+	Version   uint       `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// schemaMigration is a row of the tracking table
+type schemaMigration struct {
+	Version   uint   `gorm:"primaryKey;autoIncrement:false"`
+	Name      string `gorm:"type:varchar(255);not null"`
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string { return "schema_migrations" }
+
+// Migrator applies and rolls back migrations against a database
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// New returns a migrator for the application schema
+func New(db *gorm.DB) *Migrator {
+	return NewWithMigrations(db, registered)
+}
+
+// NewWithMigrations returns a migrator for an explicit list of migrations,
+// ordered by version
+func NewWithMigrations(db *gorm.DB, migrations []Migration) *Migrator {
+	sorted := append([]Migration(nil), migrations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+	return &Migrator{db: db, migrations: sorted}
+}
+
+// Latest returns the highest version known to this binary
+func (m *Migrator) Latest() uint {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Version returns the highest applied version, or zero for an empty database
+func (m *Migrator) Version(ctx context.Context) (uint, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var version uint
+	for v := range applied {
+		if v > version {
+			version = v
+		}
+	}
+	return version, nil
+}
+
+// Status lists every known migration in order, plus any applied version the
+// binary does not know about
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if row, ok := applied[migration.Version]; ok {
+			appliedAt := row.AppliedAt
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+			delete(applied, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+	for _, row := range applied {
+		appliedAt := row.AppliedAt
+		statuses = append(statuses, Status{Version: row.Version, Name: row.Name, Applied: true, AppliedAt: &appliedAt})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// Up applies every pending migration in order and returns how many ran
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	if err := m.validate(); err != nil {
+		return 0, err
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := migration.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&schemaMigration{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				AppliedAt: time.Now(),
+			}).Error
+		})
+		if err != nil {
+			return count, fmt.Errorf("migration %04d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+		count++
+	}
+	return count, nil
+}
+
+// Down rolls back the most recently applied migrations, at most steps of them,
+// and returns how many were rolled back
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	if err := m.validate(); err != nil {
+		return 0, err
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for i := len(m.migrations) - 1; i >= 0 && count < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := migration.Down(tx); err != nil {
+				return err
+			}
+			return tx.Delete(&schemaMigration{}, migration.Version).Error
+		})
+		if err != nil {
+			return count, fmt.Errorf("rollback of %04d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+		count++
+	}
+	return count, nil
+}
+
+// CheckVersion returns ErrSchemaBehind if any known migration is pending and
+// ErrSchemaAhead if the database has versions newer than this binary
+func (m *Migrator) CheckVersion(ctx context.Context) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	var current uint
+	pending := false
+	for _, status := range statuses {
+		if status.Applied {
+			current = status.Version
+		} else {
+			pending = true
+		}
+	}
+
+	latest := m.Latest()
+	if current > latest {
+		return fmt.Errorf("%w: database is at version %d, binary supports up to %d", ErrSchemaAhead, current, latest)
+	}
+	if pending {
+		return fmt.Errorf("%w: database is at version %d, binary expects %d", ErrSchemaBehind, current, latest)
+	}
+	return nil
+}
+
+// applied loads the tracking table, creating it on first use
+func (m *Migrator) applied(ctx context.Context) (map[uint]schemaMigration, error) {
+	db := m.db.WithContext(ctx)
+	if err := db.AutoMigrate(&schemaMigration{}); err != nil {
+		return nil, fmt.Errorf("failed to prepare schema_migrations table: %w", err)
+	}
+
+	var rows []schemaMigration
+	if err := db.Order("version").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations table: %w", err)
+	}
+
+	applied := make(map[uint]schemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+func (m *Migrator) validate() error {
+	seen := make(map[uint]bool, len(m.migrations))
+	for _, migration := range m.migrations {
+		if migration.Version == 0 {
+			return fmt.Errorf("migration %q has no version", migration.Name)
+		}
+		if seen[migration.Version] {
+			return fmt.Errorf("duplicate migration version %d", migration.Version)
+		}
+		if migration.Up == nil || migration.Down == nil {
+			return fmt.Errorf("migration %04d_%s needs both up and down steps", migration.Version, migration.Name)
+		}
+		seen[migration.Version] = true
+	}
+	return nil
+}
+
+// dropColumn drops a column without losing the table's other indexes. SQLite
+// drops columns by rebuilding the table, which discards every index on it, so
+// they are recreated afterwards. Indexes on the column must be dropped first.
+func dropColumn(tx *gorm.DB, model interface{}, table, field string) error {
+	if tx.Dialector.Name() != "sqlite" {
+		return tx.Migrator().DropColumn(model, field)
+	}
+
+	var indexes []string
+	err := tx.Raw("SELECT sql FROM sqlite_master WHERE type = 'index' AND tbl_name = ? AND sql IS NOT NULL", table).
+		Scan(&indexes).Error
+	if err != nil {
+		return err
+	}
+	if err := tx.Migrator().DropColumn(model, field); err != nil {
+		return err
+	}
+	for _, index := range indexes {
+		if err := tx.Exec(index).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,340 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupMigrationsTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "migrations.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	return db
+}
+
+func TestMigrator_UpCreatesSchema(t *testing.T) {
+	db := setupMigrationsTestDB(t)
+	m := New(db)
+	ctx := context.Background()
+
+	applied, err := m.Up(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, len(registered), applied)
+
+	for _, table := range []string{"users", "categories", "transactions", "budgets", "recommendations", "attachments"} {
+		assert.True(t, db.Migrator().HasTable(table), table)
+	}
+
+	version, err := m.Version(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, m.Latest(), version)
+	assert.NoError(t, m.CheckVersion(ctx))
+
+	// The migrated schema must be usable by the domain types
+	user := domain.User{Email: "migrated@example.com", Password: "secret"}
+	require.NoError(t, db.Create(&user).Error)
+	require.NoError(t, db.Create(&domain.Transaction{UserID: user.ID, Amount: domain.NewMoney(12.5)}).Error)
+
+	// Running again is a no-op
+	applied, err = m.Up(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, applied)
+}
+
+func TestMigrator_RegisteredMigrationsRollBack(t *testing.T) {
+	db := setupMigrationsTestDB(t)
+	m := New(db)
+	ctx := context.Background()
+
+	_, err := m.Up(ctx)
+	require.NoError(t, err)
+
+	rolledBack, err := m.Down(ctx, len(registered))
+	require.NoError(t, err)
+	assert.Equal(t, len(registered), rolledBack)
+	assert.False(t, db.Migrator().HasTable("transactions"))
+
+	// A full cycle can be applied again
+	_, err = m.Up(ctx)
+	require.NoError(t, err)
+	assert.NoError(t, m.CheckVersion(ctx))
+}
+
+func TestMigrator_AdoptsAutoMigratedDatabase(t *testing.T) {
+	db := setupMigrationsTestDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.User{}, &domain.Transaction{}, &domain.Category{}, &domain.Budget{}))
+	require.NoError(t, db.Create(&domain.User{Email: "existing@example.com", Password: "secret"}).Error)
+
+	m := New(db)
+	ctx := context.Background()
+	assert.ErrorIs(t, m.CheckVersion(ctx), ErrSchemaBehind)
+
+	_, err := m.Up(ctx)
+	require.NoError(t, err)
+	assert.NoError(t, m.CheckVersion(ctx))
+
+	var count int64
+	require.NoError(t, db.Model(&domain.User{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestMigrator_Down(t *testing.T) {
+	db := setupMigrationsTestDB(t)
+	ctx := context.Background()
+	m := NewWithMigrations(db, []Migration{testMigration(1, "one"), testMigration(2, "two")})
+
+	_, err := m.Up(ctx)
+	require.NoError(t, err)
+	require.True(t, db.Migrator().HasTable("test_two"))
+
+	rolledBack, err := m.Down(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, rolledBack)
+	assert.False(t, db.Migrator().HasTable("test_two"))
+	assert.True(t, db.Migrator().HasTable("test_one"))
+
+	version, err := m.Version(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), version)
+	assert.ErrorIs(t, m.CheckVersion(ctx), ErrSchemaBehind)
+
+	// Asking for more steps than are applied stops at an empty schema
+	rolledBack, err = m.Down(ctx, 5)
+	require.NoError(t, err)
+	assert.Equal(t, 1, rolledBack)
+	assert.False(t, db.Migrator().HasTable("test_one"))
+}
+
+func TestMigrator_Status(t *testing.T) {
+	db := setupMigrationsTestDB(t)
+	ctx := context.Background()
+	m := NewWithMigrations(db, []Migration{testMigration(2, "two"), testMigration(1, "one")})
+
+	_, err := NewWithMigrations(db, []Migration{testMigration(1, "one")}).Up(ctx)
+	require.NoError(t, err)
+
+	statuses, err := m.Status(ctx)
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	assert.Equal(t, uint(1), statuses[0].Version)
+	assert.True(t, statuses[0].Applied)
+	assert.NotNil(t, statuses[0].AppliedAt)
+	assert.Equal(t, "two", statuses[1].Name)
+	assert.False(t, statuses[1].Applied)
+}
+
+func TestMigrator_CheckVersionAhead(t *testing.T) {
+	db := setupMigrationsTestDB(t)
+	ctx := context.Background()
+
+	newer := NewWithMigrations(db, []Migration{testMigration(1, "one"), testMigration(2, "two")})
+	_, err := newer.Up(ctx)
+	require.NoError(t, err)
+
+	older := NewWithMigrations(db, []Migration{testMigration(1, "one")})
+	assert.ErrorIs(t, older.CheckVersion(ctx), ErrSchemaAhead)
+}
+
+func TestMigrator_FailedMigrationIsNotRecorded(t *testing.T) {
+	db := setupMigrationsTestDB(t)
+	ctx := context.Background()
+
+	broken := testMigration(2, "broken")
+	broken.Up = func(tx *gorm.DB) error { return errors.New("boom") }
+	m := NewWithMigrations(db, []Migration{testMigration(1, "one"), broken})
+
+	applied, err := m.Up(ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "0002_broken")
+	assert.Equal(t, 1, applied)
+
+	version, err := m.Version(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), version)
+}
+
+func TestMigrator_RejectsInvalidMigrations(t *testing.T) {
+	db := setupMigrationsTestDB(t)
+	ctx := context.Background()
+
+	_, err := NewWithMigrations(db, []Migration{testMigration(1, "a"), testMigration(1, "b")}).Up(ctx)
+	assert.Error(t, err)
+
+	_, err = NewWithMigrations(db, []Migration{{Version: 1, Name: "no_down", Up: func(*gorm.DB) error { return nil }}}).Up(ctx)
+	assert.Error(t, err)
+}
+
+// testMigration creates and drops a table named after the migration
+func testMigration(version uint, name string) Migration {
+	table := "test_" + name
+	type row struct{ ID uint }
+	return Migration{
+		Version: version,
+		Name:    name,
+		Up:      func(tx *gorm.DB) error { return tx.Table(table).Migrator().CreateTable(&row{}) },
+		Down:    func(tx *gorm.DB) error { return tx.Migrator().DropTable(table) },
+	}
+}
+
+func TestCategoryOwners_AssignsExistingCategories(t *testing.T) {
+	db := setupMigrationsTestDB(t)
+	ctx := context.Background()
+	_, err := NewWithMigrations(db, registered[:categoryOwners.Version-1]).Up(ctx)
+	require.NoError(t, err)
+
+	therapy := category0001{Name: "Therapy", Type: "expense"}
+	hobby := category0001{Name: "Hobby", Type: "expense"}
+	require.NoError(t, db.Create(&therapy).Error)
+	require.NoError(t, db.Create(&hobby).Error)
+	require.NoError(t, db.Exec("INSERT INTO users (id, email, password) VALUES (1, 'a@example.com', 'x'), (2, 'b@example.com', 'x')").Error)
+	require.NoError(t, db.Exec("INSERT INTO transactions (user_id, category_id, amount) VALUES (1, ?, 10), (2, ?, 20)",
+		therapy.ID, therapy.ID).Error)
+	require.NoError(t, db.Exec("INSERT INTO budgets (user_id, category_id, amount) VALUES (2, ?, 100)", therapy.ID).Error)
+
+	m := NewWithMigrations(db, registered[:categoryOwners.Version])
+	_, err = m.Up(ctx)
+	require.NoError(t, err)
+
+	var categories []domain.Category
+	require.NoError(t, db.Where("name = ?", "Therapy").Order("id").Find(&categories).Error)
+	require.Len(t, categories, 2)
+	assert.Equal(t, therapy.ID, categories[0].ID)
+	assert.Equal(t, uint(1), *categories[0].UserID)
+	assert.Equal(t, uint(2), *categories[1].UserID)
+
+	var transaction domain.Transaction
+	require.NoError(t, db.Where("user_id = ?", 2).First(&transaction).Error)
+	assert.Equal(t, categories[1].ID, transaction.CategoryID)
+	var budget domain.Budget
+	require.NoError(t, db.Where("user_id = ?", 2).First(&budget).Error)
+	assert.Equal(t, categories[1].ID, budget.CategoryID)
+
+	var unused domain.Category
+	require.NoError(t, db.First(&unused, hobby.ID).Error)
+	assert.Nil(t, unused.UserID)
+
+	// Rolling back merges the copies again
+	_, err = m.Down(ctx, 1)
+	require.NoError(t, err)
+	var count int64
+	require.NoError(t, db.Table("categories").Where("name = ?", "Therapy").Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+	require.NoError(t, db.Table("transactions").Where("category_id = ?", therapy.ID).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestMoneyMinorUnits_ConvertsAmountsToCents(t *testing.T) {
+	db := setupMigrationsTestDB(t)
+	ctx := context.Background()
+	_, err := NewWithMigrations(db, registered[:moneyMinorUnits.Version-1]).Up(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, db.Exec("INSERT INTO users (id, email, password) VALUES (1, 'a@example.com', 'x')").Error)
+	require.NoError(t, db.Exec("INSERT INTO transactions (user_id, category_id, type, amount) VALUES (1, 1, 'expense', 12.5), (1, 1, 'expense', 0.1)").Error)
+	require.NoError(t, db.Exec("INSERT INTO budgets (user_id, category_id, amount, spent, remaining) VALUES (1, 1, 500, 12.6, 487.4)").Error)
+
+	m := NewWithMigrations(db, registered[:moneyMinorUnits.Version])
+	_, err = m.Up(ctx)
+	require.NoError(t, err)
+	assert.True(t, db.Migrator().HasIndex("transactions", "idx_transactions_user_category_date"))
+
+	var transactions []domain.Transaction
+	require.NoError(t, db.Order("id").Find(&transactions).Error)
+	require.Len(t, transactions, 2)
+	assert.Equal(t, domain.NewMoney(12.5), transactions[0].Amount)
+	assert.Equal(t, domain.NewMoney(0.1), transactions[1].Amount)
+
+	var budget domain.Budget
+	require.NoError(t, db.First(&budget).Error)
+	assert.Equal(t, domain.NewMoney(500), budget.Amount)
+	assert.Equal(t, domain.NewMoney(12.6), budget.Spent)
+	assert.Equal(t, domain.NewMoney(487.4), budget.Remaining)
+
+	var total domain.Money
+	require.NoError(t, db.Model(&domain.Transaction{}).Select("SUM(amount)").Scan(&total).Error)
+	assert.Equal(t, domain.NewMoney(12.6), total)
+
+	// Rolling back restores the dollar amounts
+	_, err = m.Down(ctx, 1)
+	require.NoError(t, err)
+	var amount float64
+	require.NoError(t, db.Table("transactions").Select("amount").Where("id = ?", transactions[0].ID).Scan(&amount).Error)
+	assert.InDelta(t, 12.5, amount, 0.001)
+}
+
+func TestSyncFeed_BackfillsExistingTransactions(t *testing.T) {
+	db := setupMigrationsTestDB(t)
+	ctx := context.Background()
+	_, err := NewWithMigrations(db, registered[:syncFeed.Version-1]).Up(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, db.Exec("INSERT INTO users (id, email, password) VALUES (1, 'a@example.com', 'x')").Error)
+	require.NoError(t, db.Exec("INSERT INTO transactions (user_id, category_id, type, amount, updated_at) "+
+		"VALUES (1, 1, 'expense', 1250, CURRENT_TIMESTAMP)").Error)
+	require.NoError(t, db.Exec("INSERT INTO transactions (user_id, category_id, type, amount, deleted_at) "+
+		"VALUES (1, 1, 'expense', 500, CURRENT_TIMESTAMP)").Error)
+
+	m := NewWithMigrations(db, registered[:syncFeed.Version])
+	_, err = m.Up(ctx)
+	require.NoError(t, err)
+
+	var changes []domain.SyncChange
+	require.NoError(t, db.Order("id").Find(&changes).Error)
+	require.Len(t, changes, 2)
+	assert.Equal(t, domain.SyncOperationUpsert, changes[0].Operation)
+	assert.Equal(t, domain.SyncOperationDelete, changes[1].Operation)
+	for _, change := range changes {
+		assert.Equal(t, uint(1), change.UserID)
+		assert.Equal(t, domain.SyncEntityTransaction, change.EntityType)
+		assert.Equal(t, int64(1), change.Version)
+		assert.False(t, change.CreatedAt.IsZero())
+	}
+
+	_, err = m.Down(ctx, 1)
+	require.NoError(t, err)
+	assert.False(t, db.Migrator().HasTable("sync_changes"))
+}
+
+func TestUncategorizedCategory_BackfillsMissingCategories(t *testing.T) {
+	db := setupMigrationsTestDB(t)
+	ctx := context.Background()
+	_, err := NewWithMigrations(db, registered[:uncategorizedCategory.Version-1]).Up(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, db.Exec("INSERT INTO users (id, email, password) VALUES (1, 'a@example.com', 'x')").Error)
+	require.NoError(t, db.Exec("INSERT INTO categories (id, name, type) VALUES (5, 'Rent', 'expense')").Error)
+	for _, categoryID := range []uint{5, 0, 99} {
+		require.NoError(t, db.Exec("INSERT INTO transactions (user_id, category_id, type, amount) VALUES (1, ?, 'expense', 100)",
+			categoryID).Error)
+	}
+
+	m := NewWithMigrations(db, registered[:uncategorizedCategory.Version])
+	_, err = m.Up(ctx)
+	require.NoError(t, err)
+
+	var uncategorized domain.Category
+	require.NoError(t, db.Where("is_system = ?", true).First(&uncategorized).Error)
+	assert.Equal(t, domain.UncategorizedCategoryName, uncategorized.Name)
+	assert.Nil(t, uncategorized.UserID)
+	assert.Empty(t, uncategorized.Type)
+	var categoryIDs []uint
+	require.NoError(t, db.Model(&domain.Transaction{}).Order("id").Pluck("category_id", &categoryIDs).Error)
+	assert.Equal(t, []uint{5, uncategorized.ID, uncategorized.ID}, categoryIDs)
+
+	_, err = m.Down(ctx, 1)
+	require.NoError(t, err)
+	require.NoError(t, db.Table("transactions").Order("id").Pluck("category_id", &categoryIDs).Error)
+	assert.Equal(t, []uint{5, 0, 0}, categoryIDs)
+	assert.False(t, db.Migrator().HasColumn("categories", "is_system"))
+}
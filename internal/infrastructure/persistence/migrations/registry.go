@@ -0,0 +1,63 @@
+package migrations
+
+// registered lists the application migrations. Add new migrations at the end
+// in their own numbered file; never edit one that has already been released.
+var registered = []Migration{
+	initialSchema,
+	transactionSoftDelete,
+	auditLogs,
+	transactionListIndex,
+	merchants,
+	categoryHierarchy,
+	categoryOwners,
+	households,
+	adviceHistory,
+	priceSnapshots,
+	watchlists,
+	priceAlerts,
+	financialReports,
+	transactionCategoryIndex,
+	moneyMinorUnits,
+	userProfile,
+	riskAssessments,
+	userLocale,
+	duplicateDismissals,
+	sessions,
+	accounts,
+	financialGoals,
+	categoryCaps,
+	transactionNotesTags,
+	exportTemplates,
+	categoryMappings,
+	bankLinks,
+	jobs,
+	webhooks,
+	healthSnapshots,
+	bills,
+	transactionAccounts,
+	categoryModels,
+	holdings,
+	wallets,
+	exchangeRates,
+	advisorAccess,
+	comments,
+	budgetAnchors,
+	savingsRules,
+	transactionLocations,
+	transactionItems,
+	syncFeed,
+	plans,
+	subscriptions,
+	usageStats,
+	transactionArchives,
+	uncategorizedCategory,
+	userPreferences,
+	digestSubscriptions,
+	pushNotifications,
+	calendarFeeds,
+	loans,
+	incomeSources,
+	plugins,
+	replicationHeartbeats,
+	scheduledTransfers,
+}
@@ -0,0 +1,81 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const queryTimeoutCancelKey = "query_timeout:cancel"
+
+// QueryTimeout is a GORM plugin that gives every statement its own deadline
+// on top of whatever context the caller passed with WithContext, so a single
+// slow query cannot hold a request open indefinitely.
+type QueryTimeout struct {
+	Timeout time.Duration
+}
+
+func NewQueryTimeout(timeout time.Duration) *QueryTimeout {
+	return &QueryTimeout{Timeout: timeout}
+}
+
+func (p *QueryTimeout) Name() string { return "query_timeout" }
+
+// Initialize registers the callbacks. A zero or negative timeout disables the plugin.
+func (p *QueryTimeout) Initialize(db *gorm.DB) error {
+	if p.Timeout <= 0 {
+		return nil
+	}
+
+	callbacks := db.Callback()
+	if err := callbacks.Create().Before("*").Register("query_timeout:before_create", p.before); err != nil {
+		return err
+	}
+	if err := callbacks.Create().After("*").Register("query_timeout:after_create", p.after); err != nil {
+		return err
+	}
+	if err := callbacks.Query().Before("*").Register("query_timeout:before_query", p.before); err != nil {
+		return err
+	}
+	if err := callbacks.Query().After("*").Register("query_timeout:after_query", p.after); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("*").Register("query_timeout:before_update", p.before); err != nil {
+		return err
+	}
+	if err := callbacks.Update().After("*").Register("query_timeout:after_update", p.after); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("*").Register("query_timeout:before_delete", p.before); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().After("*").Register("query_timeout:after_delete", p.after); err != nil {
+		return err
+	}
+	if err := callbacks.Raw().Before("*").Register("query_timeout:before_raw", p.before); err != nil {
+		return err
+	}
+	if err := callbacks.Raw().After("*").Register("query_timeout:after_raw", p.after); err != nil {
+		return err
+	}
+	// Row statements (Scan, Rows, Row) hand an open cursor back to the caller,
+	// so they only get the deadline; it is released when the deadline passes.
+	return callbacks.Row().Before("*").Register("query_timeout:before_row", p.before)
+}
+
+func (p *QueryTimeout) before(db *gorm.DB) {
+	ctx := db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, p.Timeout)
+	db.Statement.Context = ctx
+	db.InstanceSet(queryTimeoutCancelKey, cancel)
+}
+
+func (p *QueryTimeout) after(db *gorm.DB) {
+	if cancel, ok := db.InstanceGet(queryTimeoutCancelKey); ok {
+		cancel.(context.CancelFunc)()
+	}
+}
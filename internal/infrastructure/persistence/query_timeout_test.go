@@ -0,0 +1,49 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestQueryTimeout(t *testing.T) {
+	db := setupRepositoryTestDB(t)
+	require.NoError(t, db.Use(NewQueryTimeout(time.Second)))
+
+	var deadline time.Time
+	require.NoError(t, db.Callback().Query().After("query_timeout:before_query").
+		Register("test:record_deadline", func(tx *gorm.DB) {
+			deadline, _ = tx.Statement.Context.Deadline()
+		}))
+
+	repo := NewTransactionRepository(db)
+	ctx := context.Background()
+	require.NoError(t, repo.Create(ctx, &domain.Transaction{UserID: 1, Amount: domain.NewMoney(10), Date: time.Now()}))
+
+	transactions, err := repo.Find(ctx, domain.TransactionFilter{UserID: 1})
+	require.NoError(t, err)
+	assert.Len(t, transactions, 1)
+	assert.WithinDuration(t, time.Now().Add(time.Second), deadline, time.Second)
+
+	total, err := repo.Sum(ctx, domain.TransactionFilter{UserID: 1})
+	require.NoError(t, err)
+	assert.Equal(t, domain.NewMoney(10), total)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = repo.Find(cancelled, domain.TransactionFilter{UserID: 1})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestQueryTimeout_Disabled(t *testing.T) {
+	db := setupRepositoryTestDB(t)
+	require.NoError(t, db.Use(NewQueryTimeout(0)))
+
+	assert.Nil(t, db.Callback().Query().Get("query_timeout:before_query"))
+}
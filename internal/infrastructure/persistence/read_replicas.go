@@ -0,0 +1,178 @@
+package persistence
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// ReplicationHeartbeat is the row the primary keeps stamping; a replica's
+// lag is how old its copy of the row is
+type ReplicationHeartbeat struct {
+	ID     uint      `gorm:"primaryKey"`
+	BeatAt time.Time `gorm:"not null"`
+}
+
+func (ReplicationHeartbeat) TableName() string { return "replication_heartbeats" }
+
+type replica struct {
+	db      *gorm.DB
+	healthy atomic.Bool
+	checked atomic.Bool
+}
+
+// ReadReplicas is a GORM plugin that sends the reads of contexts preferring
+// a replica (see domain.ContextPreferringReplica) to read replicas, taking
+// turns. Writes, reads within a transaction and locking reads stay on the
+// primary. A replica only serves reads once a check found it within MaxLag
+// of the primary; lagging or unreachable ones fall back to the primary until
+// a later check finds them caught up.
+type ReadReplicas struct {
+	MaxLag time.Duration
+
+	replicas []*replica
+	primary  gorm.ConnPool
+	next     atomic.Uint64
+}
+
+func NewReadReplicas(maxLag time.Duration, replicas ...*gorm.DB) *ReadReplicas {
+	p := &ReadReplicas{MaxLag: maxLag}
+	for _, db := range replicas {
+		p.replicas = append(p.replicas, &replica{db: db})
+	}
+	return p
+}
+
+func (p *ReadReplicas) Name() string { return "read_replicas" }
+
+// Initialize registers the callbacks. Without replicas the plugin does nothing.
+func (p *ReadReplicas) Initialize(db *gorm.DB) error {
+	if len(p.replicas) == 0 {
+		return nil
+	}
+	p.primary = db.ConnPool
+
+	callbacks := db.Callback()
+	if err := callbacks.Query().Before("*").Register("read_replicas:query", p.route); err != nil {
+		return err
+	}
+	if err := callbacks.Row().Before("*").Register("read_replicas:row", p.route); err != nil {
+		return err
+	}
+	if err := callbacks.Create().Before("*").Register("read_replicas:create", p.write); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("*").Register("read_replicas:update", p.write); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("*").Register("read_replicas:delete", p.write); err != nil {
+		return err
+	}
+	return callbacks.Raw().Before("*").Register("read_replicas:raw", p.write)
+}
+
+// route sends a read to a healthy replica when its context prefers one
+func (p *ReadReplicas) route(db *gorm.DB) {
+	ctx := db.Statement.Context
+	_, inTransaction := db.Statement.ConnPool.(gorm.TxCommitter)
+	_, locking := db.Statement.Clauses["FOR"]
+	if ctx == nil || !domain.PrefersReplica(ctx) || inTransaction || locking {
+		p.usePrimary(db)
+		return
+	}
+	if replica := p.pick(); replica != nil {
+		db.Statement.ConnPool = replica.db.ConnPool
+		return
+	}
+	p.usePrimary(db)
+}
+
+// write keeps a write on the primary, and the context's later reads with it
+func (p *ReadReplicas) write(db *gorm.DB) {
+	if db.Statement.Context != nil {
+		domain.NoteWrite(db.Statement.Context)
+	}
+	p.usePrimary(db)
+}
+
+// usePrimary undoes the routing of an earlier statement of the same session
+func (p *ReadReplicas) usePrimary(db *gorm.DB) {
+	for _, replica := range p.replicas {
+		if db.Statement.ConnPool == replica.db.ConnPool {
+			db.Statement.ConnPool = p.primary
+			return
+		}
+	}
+}
+
+// pick returns the next healthy replica, or nil when none is
+func (p *ReadReplicas) pick() *replica {
+	start := p.next.Add(1)
+	for i := range uint64(len(p.replicas)) {
+		replica := p.replicas[(start+i)%uint64(len(p.replicas))]
+		if replica.healthy.Load() {
+			return replica
+		}
+	}
+	return nil
+}
+
+// Check stamps the heartbeat on the primary and measures each replica's lag
+// from its copy of it, so the lag is known to within the check interval.
+// Replicas behind by more than MaxLag, or whose heartbeat cannot be read,
+// serve no reads until a later check.
+func (p *ReadReplicas) Check(ctx context.Context, primary *gorm.DB) error {
+	now := time.Now().UTC()
+	if err := primary.WithContext(ctx).Save(&ReplicationHeartbeat{ID: 1, BeatAt: now}).Error; err != nil {
+		return err
+	}
+
+	for i, replica := range p.replicas {
+		var beat ReplicationHeartbeat
+		err := replica.db.WithContext(ctx).First(&beat, 1).Error
+		lag := now.Sub(beat.BeatAt)
+		healthy := err == nil && lag <= p.MaxLag
+		changed := replica.healthy.Swap(healthy) != healthy
+		if first := !replica.checked.Swap(true); first || changed {
+			switch {
+			case healthy:
+				log.Printf("read replicas: replica %d caught up, serving reads", i+1)
+			case err != nil:
+				log.Printf("read replicas: replica %d unreachable, reading from the primary: %v", i+1, err)
+			default:
+				log.Printf("read replicas: replica %d is %s behind, reading from the primary", i+1, lag.Round(time.Millisecond))
+			}
+		}
+	}
+	return nil
+}
+
+// StartChecks checks the replicas right away and then every interval until
+// ctx is done
+func (p *ReadReplicas) StartChecks(ctx context.Context, primary *gorm.DB, interval time.Duration) {
+	if len(p.replicas) == 0 {
+		return
+	}
+	check := func() {
+		if err := p.Check(ctx, primary); err != nil {
+			log.Printf("read replicas: failed to stamp the heartbeat: %v", err)
+		}
+	}
+	check()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			check()
+		}
+	}
+}
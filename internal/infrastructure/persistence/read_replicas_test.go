@@ -0,0 +1,75 @@
+package persistence
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	sqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestReadReplicas(t *testing.T) {
+	// The replica does not replicate: each database holds its own category,
+	// which tells where a read went
+	open := func(name string) *gorm.DB {
+		db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), name)), &gorm.Config{
+			Logger: logger.Default.LogMode(logger.Silent),
+		})
+		require.NoError(t, err)
+		require.NoError(t, db.AutoMigrate(&domain.Category{}, &ReplicationHeartbeat{}))
+		require.NoError(t, db.Create(&domain.Category{Name: name, Type: domain.TransactionTypeExpense}).Error)
+		return db
+	}
+	primary, replica := open("primary.db"), open("replica.db")
+	replicas := NewReadReplicas(time.Minute, replica)
+	require.NoError(t, primary.Use(replicas))
+
+	ctx := context.Background()
+	readFrom := func(ctx context.Context) string {
+		var category domain.Category
+		require.NoError(t, primary.WithContext(ctx).First(&category).Error)
+		return category.Name
+	}
+	heartbeat := func(age time.Duration) {
+		require.NoError(t, replica.Save(&ReplicationHeartbeat{ID: 1, BeatAt: time.Now().UTC().Add(-age)}).Error)
+		require.NoError(t, replicas.Check(ctx, primary))
+	}
+
+	t.Run("reads from the primary until a check found the replica caught up", func(t *testing.T) {
+		assert.Equal(t, "primary.db", readFrom(domain.ContextPreferringReplica(ctx)))
+
+		heartbeat(time.Second)
+		assert.Equal(t, "replica.db", readFrom(domain.ContextPreferringReplica(ctx)))
+		assert.Equal(t, "primary.db", readFrom(ctx), "only contexts preferring a replica use it")
+	})
+
+	t.Run("keeps reads after a write and within transactions on the primary", func(t *testing.T) {
+		reads := domain.ContextPreferringReplica(ctx)
+		require.NoError(t, primary.WithContext(reads).Create(&domain.Category{Name: "new", Type: domain.TransactionTypeExpense}).Error)
+		var count int64
+		require.NoError(t, primary.WithContext(reads).Model(&domain.Category{}).Where("name = ?", "new").Count(&count).Error)
+		assert.Equal(t, int64(1), count)
+
+		require.NoError(t, primary.WithContext(domain.ContextPreferringReplica(ctx)).Transaction(func(tx *gorm.DB) error {
+			var category domain.Category
+			require.NoError(t, tx.First(&category).Error)
+			assert.Equal(t, "primary.db", category.Name)
+			return nil
+		}))
+	})
+
+	t.Run("falls back to the primary while the replica lags", func(t *testing.T) {
+		heartbeat(time.Hour)
+		assert.Equal(t, "primary.db", readFrom(domain.ContextPreferringReplica(ctx)))
+
+		heartbeat(0)
+		assert.Equal(t, "replica.db", readFrom(domain.ContextPreferringReplica(ctx)))
+	})
+}
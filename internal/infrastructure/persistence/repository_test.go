@@ -0,0 +1,304 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	sqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupRepositoryTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&domain.Category{}, &domain.Transaction{}, &domain.Budget{}))
+	return db
+}
+
+// The GORM and in-memory repositories must behave the same, so both run the same cases
+func TestTransactionRepositories(t *testing.T) {
+	repos := map[string]func(t *testing.T) domain.TransactionRepository{
+		"gorm": func(t *testing.T) domain.TransactionRepository {
+			return NewTransactionRepository(setupRepositoryTestDB(t))
+		},
+		"memory": func(t *testing.T) domain.TransactionRepository { return NewMemoryTransactionRepository() },
+	}
+
+	for name, newRepo := range repos {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newRepo(t)
+			jan := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+			feb := time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC)
+			seed := []*domain.Transaction{
+				{UserID: 1, CategoryID: 1, Type: "income", Description: "Salary", Amount: domain.NewMoney(3000), Date: jan},
+				{UserID: 1, CategoryID: 2, Type: "expense", Description: "Groceries", Amount: domain.NewMoney(120), Date: jan},
+				{UserID: 1, CategoryID: 2, Type: "expense", Description: "Market", Amount: domain.NewMoney(80), Date: feb},
+				{UserID: 2, CategoryID: 2, Type: "expense", Description: "Other user", Amount: domain.NewMoney(999), Date: feb},
+			}
+			for _, tx := range seed {
+				require.NoError(t, repo.Create(ctx, tx))
+				require.NotZero(t, tx.ID)
+			}
+
+			all, err := repo.Find(ctx, domain.TransactionFilter{UserID: 1})
+			require.NoError(t, err)
+			require.Len(t, all, 3)
+			assert.Equal(t, "Market", all[0].Description, "newest first")
+
+			page, err := repo.Find(ctx, domain.TransactionFilter{UserID: 1, Limit: 1, Offset: 1})
+			require.NoError(t, err)
+			require.Len(t, page, 1)
+
+			category := uint(2)
+			expenses, err := repo.Sum(ctx, domain.TransactionFilter{UserID: 1, Type: "expense", CategoryID: &category})
+			require.NoError(t, err)
+			assert.Equal(t, domain.NewMoney(200), expenses)
+
+			end := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+			january, err := repo.Find(ctx, domain.TransactionFilter{UserID: 1, EndDate: &end})
+			require.NoError(t, err)
+			assert.Len(t, january, 2)
+
+			byCategory, err := repo.SumByCategory(ctx, domain.TransactionFilter{UserID: 1, Type: "expense"})
+			require.NoError(t, err)
+			assert.Equal(t, map[uint]domain.Money{2: domain.NewMoney(200)}, byCategory)
+
+			byMonth, err := repo.SumByMonth(ctx, domain.TransactionFilter{UserID: 1, Type: "expense"})
+			require.NoError(t, err)
+			assert.Equal(t, map[string]domain.Money{"2024-01": domain.NewMoney(120), "2024-02": domain.NewMoney(80)}, byMonth)
+
+			found, err := repo.GetByID(ctx, seed[1].ID)
+			require.NoError(t, err)
+			found.Amount = domain.NewMoney(150)
+			require.NoError(t, repo.Update(ctx, found))
+			updated, err := repo.GetByID(ctx, seed[1].ID)
+			require.NoError(t, err)
+			assert.Equal(t, domain.NewMoney(150), updated.Amount)
+
+			require.NoError(t, repo.Delete(ctx, seed[1].ID))
+			_, err = repo.GetByID(ctx, seed[1].ID)
+			assert.ErrorIs(t, err, domain.ErrNotFound)
+		})
+	}
+}
+
+func TestTransactionRepositories_Trash(t *testing.T) {
+	repos := map[string]func(t *testing.T) domain.TransactionRepository{
+		"gorm": func(t *testing.T) domain.TransactionRepository {
+			return NewTransactionRepository(setupRepositoryTestDB(t))
+		},
+		"memory": func(t *testing.T) domain.TransactionRepository { return NewMemoryTransactionRepository() },
+	}
+
+	for name, newRepo := range repos {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newRepo(t)
+			date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+			kept := &domain.Transaction{UserID: 1, CategoryID: 1, Type: "expense", Description: "Kept", Amount: domain.NewMoney(10), Date: date}
+			deleted := &domain.Transaction{UserID: 1, CategoryID: 1, Type: "expense", Description: "Deleted", Amount: domain.NewMoney(25), Date: date}
+			other := &domain.Transaction{UserID: 2, CategoryID: 1, Type: "expense", Description: "Other user", Amount: domain.NewMoney(5), Date: date}
+			for _, tx := range []*domain.Transaction{kept, deleted, other} {
+				require.NoError(t, repo.Create(ctx, tx))
+			}
+			require.NoError(t, repo.Delete(ctx, deleted.ID))
+			require.NoError(t, repo.Delete(ctx, other.ID))
+
+			live, err := repo.Find(ctx, domain.TransactionFilter{UserID: 1})
+			require.NoError(t, err)
+			require.Len(t, live, 1)
+			total, err := repo.Sum(ctx, domain.TransactionFilter{UserID: 1})
+			require.NoError(t, err)
+			assert.Equal(t, domain.NewMoney(10), total, "deleted transactions are left out of totals")
+
+			trash, err := repo.FindDeleted(ctx, domain.TransactionFilter{UserID: 1})
+			require.NoError(t, err)
+			require.Len(t, trash, 1)
+			assert.Equal(t, "Deleted", trash[0].Description)
+			assert.True(t, trash[0].DeletedAt.Valid)
+
+			// Only the owner can restore or purge
+			assert.ErrorIs(t, repo.Restore(ctx, 2, deleted.ID), domain.ErrNotFound)
+			assert.ErrorIs(t, repo.Purge(ctx, 1, other.ID), domain.ErrNotFound)
+			assert.ErrorIs(t, repo.Restore(ctx, 1, kept.ID), domain.ErrNotFound, "live transactions are not in the trash")
+
+			require.NoError(t, repo.Restore(ctx, 1, deleted.ID))
+			restored, err := repo.GetByID(ctx, deleted.ID)
+			require.NoError(t, err)
+			assert.False(t, restored.DeletedAt.Valid)
+
+			require.NoError(t, repo.Delete(ctx, deleted.ID))
+			require.NoError(t, repo.Purge(ctx, 1, deleted.ID))
+			trash, err = repo.FindDeleted(ctx, domain.TransactionFilter{UserID: 1})
+			require.NoError(t, err)
+			assert.Empty(t, trash)
+
+			// Retention purge covers every user but leaves recent deletions alone
+			purged, err := repo.PurgeDeletedBefore(ctx, 0, time.Now().Add(-time.Hour))
+			require.NoError(t, err)
+			assert.Zero(t, purged)
+			purged, err = repo.PurgeDeletedBefore(ctx, 0, time.Now().Add(time.Second))
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), purged)
+			assert.ErrorIs(t, repo.Restore(ctx, 2, other.ID), domain.ErrNotFound)
+		})
+	}
+}
+
+func TestTransactionRepositories_Cursor(t *testing.T) {
+	repos := map[string]func(t *testing.T) domain.TransactionRepository{
+		"gorm": func(t *testing.T) domain.TransactionRepository {
+			return NewTransactionRepository(setupRepositoryTestDB(t))
+		},
+		"memory": func(t *testing.T) domain.TransactionRepository { return NewMemoryTransactionRepository() },
+	}
+
+	for name, newRepo := range repos {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newRepo(t)
+			// Two transactions share a date so the ID has to break the tie
+			jan := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+			feb := time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC)
+			for _, date := range []time.Time{jan, feb, feb, jan.AddDate(0, 0, -5)} {
+				require.NoError(t, repo.Create(ctx, &domain.Transaction{UserID: 1, CategoryID: 1, Type: "expense", Amount: domain.NewMoney(1), Date: date}))
+			}
+
+			all, err := repo.Find(ctx, domain.TransactionFilter{UserID: 1})
+			require.NoError(t, err)
+			require.Len(t, all, 4)
+			assert.Equal(t, []uint{3, 2, 1, 4}, transactionIDs(all))
+
+			after := &domain.TransactionCursor{Date: all[0].Date, ID: all[0].ID}
+			older, err := repo.Find(ctx, domain.TransactionFilter{UserID: 1, Cursor: after, Limit: 2, Offset: 3})
+			require.NoError(t, err)
+			assert.Equal(t, []uint{2, 1}, transactionIDs(older), "continues past the cursor and ignores the offset")
+
+			before := &domain.TransactionCursor{Date: all[3].Date, ID: all[3].ID, Before: true}
+			newer, err := repo.Find(ctx, domain.TransactionFilter{UserID: 1, Cursor: before, Limit: 2})
+			require.NoError(t, err)
+			assert.Equal(t, []uint{2, 1}, transactionIDs(newer), "keeps the rows closest to the cursor, newest first")
+
+			count, err := repo.Count(ctx, domain.TransactionFilter{UserID: 1, Cursor: after, Limit: 1})
+			require.NoError(t, err)
+			assert.Equal(t, int64(4), count)
+
+			total, err := repo.Sum(ctx, domain.TransactionFilter{UserID: 1, OlderThan: &domain.TransactionCursor{Date: all[1].Date, ID: all[1].ID}})
+			require.NoError(t, err)
+			assert.Equal(t, domain.NewMoney(2), total, "only the rows after the position are summed")
+		})
+	}
+}
+
+func TestTransactionRepositories_Transfers(t *testing.T) {
+	repos := map[string]func(t *testing.T) domain.TransactionRepository{
+		"gorm": func(t *testing.T) domain.TransactionRepository {
+			return NewTransactionRepository(setupRepositoryTestDB(t))
+		},
+		"memory": func(t *testing.T) domain.TransactionRepository { return NewMemoryTransactionRepository() },
+	}
+
+	for name, newRepo := range repos {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newRepo(t)
+			date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+			checking, savings, schedule := uint(1), uint(2), uint(7)
+			for _, tx := range []*domain.Transaction{
+				{UserID: 1, CategoryID: 1, Type: "expense", Description: "Groceries", Amount: domain.NewMoney(40), Date: date, AccountID: &checking},
+				{UserID: 1, CategoryID: 1, Type: "transfer", Description: "Savings", Amount: domain.NewMoney(100), Date: date,
+					AccountID: &checking, TransferAccountID: &savings, ScheduledTransferID: &schedule},
+				{UserID: 1, CategoryID: 1, Type: "transfer", Description: "Back", Amount: domain.NewMoney(30), Date: date,
+					AccountID: &savings, TransferAccountID: &checking},
+			} {
+				require.NoError(t, repo.Create(ctx, tx))
+			}
+
+			onChecking, err := repo.Find(ctx, domain.TransactionFilter{UserID: 1, AccountID: &checking})
+			require.NoError(t, err)
+			assert.Equal(t, []uint{3, 2, 1}, transactionIDs(onChecking), "both sides of transfers are on the account")
+
+			incoming, err := repo.Find(ctx, domain.TransactionFilter{UserID: 1, AccountID: &checking, TransferAccountID: &checking})
+			require.NoError(t, err)
+			assert.Equal(t, []uint{3}, transactionIDs(incoming))
+
+			scheduled, err := repo.Find(ctx, domain.TransactionFilter{UserID: 1, ScheduledTransferID: &schedule})
+			require.NoError(t, err)
+			assert.Equal(t, []uint{2}, transactionIDs(scheduled))
+
+			withoutTransfers, err := repo.Sum(ctx, domain.TransactionFilter{UserID: 1, ExcludeTransfers: true})
+			require.NoError(t, err)
+			assert.Equal(t, domain.NewMoney(40), withoutTransfers)
+		})
+	}
+}
+
+func transactionIDs(transactions []domain.Transaction) []uint {
+	ids := make([]uint, len(transactions))
+	for i, tx := range transactions {
+		ids[i] = tx.ID
+	}
+	return ids
+}
+
+func TestBudgetRepositories(t *testing.T) {
+	repos := map[string]func(t *testing.T) domain.BudgetRepository{
+		"gorm":   func(t *testing.T) domain.BudgetRepository { return NewBudgetRepository(setupRepositoryTestDB(t)) },
+		"memory": func(t *testing.T) domain.BudgetRepository { return NewMemoryBudgetRepository() },
+	}
+
+	for name, newRepo := range repos {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newRepo(t)
+			start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			end := start.AddDate(0, 1, 0)
+			groceries := &domain.Budget{UserID: 1, CategoryID: 2, Amount: domain.NewMoney(400), StartDate: start, EndDate: end, IsActive: true}
+			rent := &domain.Budget{UserID: 1, CategoryID: 3, Amount: domain.NewMoney(1200), StartDate: start, EndDate: end, IsActive: true}
+			require.NoError(t, repo.Create(ctx, groceries))
+			require.NoError(t, repo.Create(ctx, rent))
+
+			all, err := repo.Find(ctx, domain.BudgetFilter{UserID: 1})
+			require.NoError(t, err)
+			require.Len(t, all, 2)
+
+			category := uint(2)
+			byCategory, err := repo.Find(ctx, domain.BudgetFilter{UserID: 1, CategoryID: &category})
+			require.NoError(t, err)
+			require.Len(t, byCategory, 1)
+			assert.Equal(t, groceries.ID, byCategory[0].ID)
+
+			later := end.AddDate(0, 0, 1)
+			overlapping, err := repo.Find(ctx, domain.BudgetFilter{UserID: 1, StartsBefore: &later, EndsAfter: &later})
+			require.NoError(t, err)
+			assert.Empty(t, overlapping)
+
+			rent.IsActive = false
+			require.NoError(t, repo.Update(ctx, rent))
+			active := true
+			activeBudgets, err := repo.Find(ctx, domain.BudgetFilter{UserID: 1, IsActive: &active})
+			require.NoError(t, err)
+			assert.Len(t, activeBudgets, 1)
+
+			require.NoError(t, repo.Delete(ctx, groceries.ID))
+			_, err = repo.GetByID(ctx, groceries.ID)
+			assert.ErrorIs(t, err, domain.ErrNotFound)
+		})
+	}
+}
+
+func TestTranslateError_KeepsGormError(t *testing.T) {
+	err := translateError(gorm.ErrRecordNotFound)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.Equal(t, gorm.ErrRecordNotFound.Error(), err.Error())
+}
@@ -0,0 +1,127 @@
+package persistence
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	sqlite "github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// OpenSQLite opens the SQLite database at dsn for concurrent use. Every
+// connection runs in WAL mode, so reads never wait for a write, and waits up
+// to busyTimeout for another process's write lock instead of failing with
+// "database is locked". Writes and transactions share a single connection,
+// which serializes them within the process; plain reads use a separate pool
+// (see SQLiteWriter). In-memory databases live in one connection, so they
+// get that connection alone.
+func OpenSQLite(dsn string, busyTimeout time.Duration) (*gorm.DB, error) {
+	if isInMemorySQLite(dsn) {
+		db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+		if err != nil {
+			return nil, err
+		}
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, err
+		}
+		sqlDB.SetMaxOpenConns(1)
+		return db, nil
+	}
+
+	pragmas := []string{
+		fmt.Sprintf("busy_timeout(%d)", busyTimeout.Milliseconds()),
+		"journal_mode(WAL)",
+		"synchronous(NORMAL)",
+	}
+	// Writers take the write lock when their transaction begins, where the
+	// busy timeout applies, rather than failing when a read turns into a write
+	db, err := gorm.Open(sqlite.Open(sqliteDSN(dsn, pragmas, "_txlock=immediate")), &gorm.Config{})
+	if err != nil {
+		return nil, err
+	}
+	writer, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	writer.SetMaxOpenConns(1)
+
+	readers, err := gorm.Open(sqlite.Open(sqliteDSN(dsn, pragmas)), &gorm.Config{})
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Use(&SQLiteWriter{Readers: readers.ConnPool}); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// sqliteDSN adds the pragmas, run on every new connection, and the other
+// query parameters to dsn
+func sqliteDSN(dsn string, pragmas []string, params ...string) string {
+	for _, pragma := range pragmas {
+		params = append(params, "_pragma="+pragma)
+	}
+	separator := "?"
+	if strings.Contains(dsn, "?") {
+		separator = "&"
+	}
+	return dsn + separator + strings.Join(params, "&")
+}
+
+func isInMemorySQLite(dsn string) bool {
+	return dsn == "" || strings.Contains(dsn, ":memory:") || strings.Contains(dsn, "mode=memory")
+}
+
+// SQLiteWriter is a GORM plugin for a database whose own pool is its single
+// writer connection. It sends reads outside transactions to the Readers pool
+// so they do not queue behind writes; writes, transactions and reads within
+// them stay on the writer. Reads already routed elsewhere, such as to a read
+// replica, are left alone.
+type SQLiteWriter struct {
+	Readers gorm.ConnPool
+
+	writer gorm.ConnPool
+}
+
+func (p *SQLiteWriter) Name() string { return "sqlite_writer" }
+
+// Initialize registers the callbacks
+func (p *SQLiteWriter) Initialize(db *gorm.DB) error {
+	p.writer = db.ConnPool
+
+	callbacks := db.Callback()
+	if err := callbacks.Query().Before("*").Register("sqlite_writer:query", p.read); err != nil {
+		return err
+	}
+	if err := callbacks.Row().Before("*").Register("sqlite_writer:row", p.read); err != nil {
+		return err
+	}
+	if err := callbacks.Create().Before("*").Register("sqlite_writer:create", p.write); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("*").Register("sqlite_writer:update", p.write); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("*").Register("sqlite_writer:delete", p.write); err != nil {
+		return err
+	}
+	return callbacks.Raw().Before("*").Register("sqlite_writer:raw", p.write)
+}
+
+// read sends a read on the writer to the readers, unless it is part of a
+// transaction or locks rows
+func (p *SQLiteWriter) read(db *gorm.DB) {
+	_, locking := db.Statement.Clauses["FOR"]
+	if db.Statement.ConnPool == p.writer && !locking {
+		db.Statement.ConnPool = p.Readers
+	}
+}
+
+// write undoes the routing of an earlier read of the same session
+func (p *SQLiteWriter) write(db *gorm.DB) {
+	if db.Statement.ConnPool == p.Readers {
+		db.Statement.ConnPool = p.writer
+	}
+}
@@ -0,0 +1,96 @@
+package persistence
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestOpenSQLite(t *testing.T) {
+	db, err := OpenSQLite(filepath.Join(t.TempDir(), "finance.db"), 2*time.Second)
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&domain.Category{}))
+
+	t.Run("enables WAL and the busy timeout", func(t *testing.T) {
+		var journalMode string
+		var busyTimeout int
+		require.NoError(t, db.Raw("PRAGMA journal_mode").Scan(&journalMode).Error)
+		require.NoError(t, db.Raw("PRAGMA busy_timeout").Scan(&busyTimeout).Error)
+		assert.Equal(t, "wal", journalMode)
+		assert.Equal(t, 2000, busyTimeout)
+	})
+
+	t.Run("serializes concurrent writes", func(t *testing.T) {
+		var wg sync.WaitGroup
+		errs := make(chan error, 40)
+		for i := range 20 {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				errs <- db.Transaction(func(tx *gorm.DB) error {
+					var count int64
+					if err := tx.Model(&domain.Category{}).Count(&count).Error; err != nil {
+						return err
+					}
+					return tx.Create(&domain.Category{Name: fmt.Sprintf("category %d", i), Type: domain.TransactionTypeExpense}).Error
+				})
+			}()
+			go func() {
+				defer wg.Done()
+				var categories []domain.Category
+				errs <- db.Find(&categories).Error
+			}()
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			assert.NoError(t, err)
+		}
+
+		var count int64
+		require.NoError(t, db.Model(&domain.Category{}).Count(&count).Error)
+		assert.Equal(t, int64(20), count)
+	})
+
+	t.Run("reads outside transactions use the reader pool", func(t *testing.T) {
+		writer := db.Plugins["sqlite_writer"].(*SQLiteWriter)
+		var pools []gorm.ConnPool
+		require.NoError(t, db.Callback().Query().After("sqlite_writer:query").
+			Register("test:record_pool", func(tx *gorm.DB) { pools = append(pools, tx.Statement.ConnPool) }))
+		defer func() { require.NoError(t, db.Callback().Query().Remove("test:record_pool")) }()
+
+		var category domain.Category
+		require.NoError(t, db.First(&category).Error)
+		require.NoError(t, db.Transaction(func(tx *gorm.DB) error { return tx.First(&category).Error }))
+		require.Len(t, pools, 2)
+		assert.Equal(t, writer.Readers, pools[0])
+		assert.NotEqual(t, writer.Readers, pools[1])
+	})
+}
+
+func TestOpenSQLite_InMemory(t *testing.T) {
+	db, err := OpenSQLite(":memory:", time.Second)
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&domain.Category{}))
+	require.NoError(t, db.Create(&domain.Category{Name: "Food", Type: domain.TransactionTypeExpense}).Error)
+
+	// A second connection would open an empty database
+	var count int64
+	require.NoError(t, db.Model(&domain.Category{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+	assert.NotContains(t, db.Plugins, "sqlite_writer")
+}
+
+func TestSQLiteDSN(t *testing.T) {
+	pragmas := []string{"busy_timeout(5000)"}
+	assert.Equal(t, "finance.db?_txlock=immediate&_pragma=busy_timeout(5000)", sqliteDSN("finance.db", pragmas, "_txlock=immediate"))
+	assert.Equal(t, "file:finance.db?cache=shared&_pragma=busy_timeout(5000)", sqliteDSN("file:finance.db?cache=shared", pragmas))
+}
@@ -1,15 +1,24 @@
 package persistence
 
 import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
 	"go-finance-advisor/internal/domain"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepository struct{ DB *gorm.DB }
 
+// TransactionRepository is the GORM implementation of domain.TransactionRepository
 type TransactionRepository struct{ DB *gorm.DB }
 
+var _ domain.TransactionRepository = (*TransactionRepository)(nil)
+
 func NewUserRepository(db *gorm.DB) *UserRepository {
 	return &UserRepository{DB: db}
 }
@@ -18,17 +27,244 @@ func NewTransactionRepository(db *gorm.DB) *TransactionRepository {
 	return &TransactionRepository{DB: db}
 }
 
-func (r *UserRepository) Create(u *domain.User) error { return r.DB.Create(u).Error }
-func (r *UserRepository) GetByID(id uint) (domain.User, error) {
+func (r *UserRepository) Create(ctx context.Context, u *domain.User) error {
+	return Conn(ctx, r.DB).Create(u).Error
+}
+func (r *UserRepository) GetByID(ctx context.Context, id uint) (domain.User, error) {
 	var u domain.User
-	err := r.DB.First(&u, id).Error
+	err := Conn(ctx, r.DB).First(&u, id).Error
 	return u, err
 }
-func (r *UserRepository) Update(u *domain.User) error { return r.DB.Save(u).Error }
+func (r *UserRepository) Update(ctx context.Context, u *domain.User) error {
+	return Conn(ctx, r.DB).Save(u).Error
+}
 
-func (r *TransactionRepository) Create(tx *domain.Transaction) error { return r.DB.Create(tx).Error }
-func (r *TransactionRepository) ListByUser(userID uint) ([]domain.Transaction, error) {
+func (r *TransactionRepository) Create(ctx context.Context, tx *domain.Transaction) error {
+	return Conn(ctx, r.DB).Create(tx).Error
+}
+func (r *TransactionRepository) ListByUser(ctx context.Context, userID uint) ([]domain.Transaction, error) {
 	var txs []domain.Transaction
-	err := r.DB.Where("user_id = ?", userID).Order("date desc").Find(&txs).Error
+	err := Conn(ctx, r.DB).Where("user_id = ?", userID).Order("date desc").Find(&txs).Error
 	return txs, err
 }
+
+// GetByID returns a transaction with its category
+func (r *TransactionRepository) GetByID(ctx context.Context, id uint) (*domain.Transaction, error) {
+	var transaction domain.Transaction
+	if err := Conn(ctx, r.DB).Preload("Category").First(&transaction, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &transaction, nil
+}
+
+// Update saves all fields of a transaction; associations are left untouched
+func (r *TransactionRepository) Update(ctx context.Context, tx *domain.Transaction) error {
+	return Conn(ctx, r.DB).Omit(clause.Associations).Save(tx).Error
+}
+
+// Delete moves a transaction to the trash
+func (r *TransactionRepository) Delete(ctx context.Context, id uint) error {
+	return Conn(ctx, r.DB).Delete(&domain.Transaction{}, id).Error
+}
+
+// Find returns the transactions matching the filter, newest first
+func (r *TransactionRepository) Find(ctx context.Context, filter domain.TransactionFilter) ([]domain.Transaction, error) {
+	var transactions []domain.Transaction
+	query := r.filtered(ctx, filter).Preload("Category")
+
+	cursor := filter.Cursor
+	switch {
+	case cursor == nil:
+		query = query.Order("date DESC, id DESC")
+		if filter.Offset > 0 {
+			query = query.Offset(filter.Offset)
+		}
+	case cursor.Before:
+		// Walk towards newer rows in ascending order, then flip the page back
+		query = query.Where("(date > ? OR (date = ? AND id > ?))", cursor.Date, cursor.Date, cursor.ID).
+			Order("date ASC, id ASC")
+	default:
+		query = query.Where("(date < ? OR (date = ? AND id < ?))", cursor.Date, cursor.Date, cursor.ID).
+			Order("date DESC, id DESC")
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	if err := query.Find(&transactions).Error; err != nil {
+		return nil, err
+	}
+	if cursor != nil && cursor.Before {
+		slices.Reverse(transactions)
+	}
+	return transactions, nil
+}
+
+// Count returns the number of transactions matching the filter
+func (r *TransactionRepository) Count(ctx context.Context, filter domain.TransactionFilter) (int64, error) {
+	var count int64
+	err := r.filtered(ctx, filter).Count(&count).Error
+	return count, err
+}
+
+// Sum returns the total amount of the matching transactions
+func (r *TransactionRepository) Sum(ctx context.Context, filter domain.TransactionFilter) (domain.Money, error) {
+	var total domain.Money
+	err := r.filtered(ctx, filter).Select("COALESCE(SUM(amount), 0)").Scan(&total).Error
+	return total, err
+}
+
+// SumByCategory returns the matching totals grouped by category
+func (r *TransactionRepository) SumByCategory(ctx context.Context, filter domain.TransactionFilter) (map[uint]domain.Money, error) {
+	var results []struct {
+		CategoryID uint
+		Total      domain.Money
+	}
+	err := r.filtered(ctx, filter).
+		Select("category_id, COALESCE(SUM(amount), 0) as total").
+		Group("category_id").
+		Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[uint]domain.Money, len(results))
+	for _, result := range results {
+		totals[result.CategoryID] = result.Total
+	}
+	return totals, nil
+}
+
+// SumByMonth returns the matching totals grouped by "YYYY-MM"
+func (r *TransactionRepository) SumByMonth(ctx context.Context, filter domain.TransactionFilter) (map[string]domain.Money, error) {
+	var results []struct {
+		Month string
+		Total domain.Money
+	}
+	err := r.filtered(ctx, filter).
+		Select("strftime('%Y-%m', date) as month, COALESCE(SUM(amount), 0) as total").
+		Group("strftime('%Y-%m', date)").
+		Order("month").
+		Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[string]domain.Money, len(results))
+	for _, result := range results {
+		totals[result.Month] = result.Total
+	}
+	return totals, nil
+}
+
+// FindDeleted returns the matching transactions in the trash, most recently deleted first
+func (r *TransactionRepository) FindDeleted(ctx context.Context, filter domain.TransactionFilter) ([]domain.Transaction, error) {
+	var transactions []domain.Transaction
+	query := r.filtered(ctx, filter).Unscoped().Where("deleted_at IS NOT NULL").
+		Preload("Category").Order("deleted_at DESC")
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+	err := query.Find(&transactions).Error
+	return transactions, err
+}
+
+// Restore takes a transaction out of the user's trash
+func (r *TransactionRepository) Restore(ctx context.Context, userID, id uint) error {
+	result := r.trashed(ctx, userID, id).Model(&domain.Transaction{}).Update("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return translateError(gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+// Purge permanently deletes a transaction from the user's trash
+func (r *TransactionRepository) Purge(ctx context.Context, userID, id uint) error {
+	result := r.trashed(ctx, userID, id).Delete(&domain.Transaction{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return translateError(gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+// PurgeDeletedBefore permanently deletes transactions that went to the trash before cutoff
+func (r *TransactionRepository) PurgeDeletedBefore(ctx context.Context, userID uint, cutoff time.Time) (int64, error) {
+	query := Conn(ctx, r.DB).Unscoped().Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff)
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	result := query.Delete(&domain.Transaction{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *TransactionRepository) trashed(ctx context.Context, userID, id uint) *gorm.DB {
+	return Conn(ctx, r.DB).Unscoped().Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", id, userID)
+}
+
+func (r *TransactionRepository) filtered(ctx context.Context, filter domain.TransactionFilter) *gorm.DB {
+	query := Conn(ctx, r.DB).Model(&domain.Transaction{})
+	if filter.HouseholdID == nil || filter.UserID != 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.HouseholdID != nil {
+		query = query.Where("household_id = ?", *filter.HouseholdID)
+	}
+	if filter.PersonalOnly {
+		query = query.Where("household_id IS NULL")
+	}
+	if filter.AccountID != nil {
+		query = query.Where("(account_id = ? OR transfer_account_id = ?)", *filter.AccountID, *filter.AccountID)
+	}
+	if filter.TransferAccountID != nil {
+		query = query.Where("transfer_account_id = ?", *filter.TransferAccountID)
+	}
+	if filter.ExcludeTransfers {
+		query = query.Where("type <> ?", domain.TransactionTypeTransfer)
+	}
+	if filter.ScheduledTransferID != nil {
+		query = query.Where("scheduled_transfer_id = ?", *filter.ScheduledTransferID)
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.CategoryID != nil {
+		query = query.Where("category_id = ?", *filter.CategoryID)
+	}
+	if len(filter.CategoryIDs) > 0 {
+		query = query.Where("category_id IN ?", filter.CategoryIDs)
+	}
+	if filter.StartDate != nil {
+		query = query.Where("date >= ?", *filter.StartDate)
+	}
+	if filter.EndDate != nil {
+		query = query.Where("date <= ?", *filter.EndDate)
+	}
+	if older := filter.OlderThan; older != nil {
+		query = query.Where("(date < ? OR (date = ? AND id < ?))", older.Date, older.Date, older.ID)
+	}
+	return query
+}
+
+// notFoundError keeps gorm.ErrRecordNotFound in the chain while also
+// matching domain.ErrNotFound, so callers can check either
+type notFoundError struct{ err error }
+
+func (e notFoundError) Error() string        { return e.err.Error() }
+func (e notFoundError) Unwrap() error        { return e.err }
+func (e notFoundError) Is(target error) bool { return target == domain.ErrNotFound }
+
+func translateError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return notFoundError{err: err}
+	}
+	return err
+}
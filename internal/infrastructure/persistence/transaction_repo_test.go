@@ -1,6 +1,7 @@
 package persistence
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -23,7 +24,7 @@ func TestTransactionRepository_Create(t *testing.T) {
 			transaction: &domain.Transaction{
 				UserID:      1,
 				CategoryID:  1,
-				Amount:      100.50,
+				Amount:      domain.NewMoney(100.50),
 				Description: "Test transaction",
 				Type:        "expense",
 				Date:        time.Now(),
@@ -31,7 +32,8 @@ func TestTransactionRepository_Create(t *testing.T) {
 			setup: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
 				mock.ExpectExec("INSERT INTO `transactions`").
-					WithArgs(1, 1, "expense", "Test transaction", 100.50, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WithArgs(1, 1, nil, nil, nil, "expense", "Test transaction", 10050, "", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), nil,
+						"", nil, nil, nil, nil, "", "", nil, nil, nil).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 				mock.ExpectCommit()
 			},
@@ -42,7 +44,7 @@ func TestTransactionRepository_Create(t *testing.T) {
 			transaction: &domain.Transaction{
 				UserID:      1,
 				CategoryID:  2,
-				Amount:      50.25,
+				Amount:      domain.NewMoney(50.25),
 				Description: "Failed transaction",
 				Type:        "income",
 				Date:        time.Now(),
@@ -68,7 +70,7 @@ func TestTransactionRepository_Create(t *testing.T) {
 			repo := NewTransactionRepository(db)
 			tt.setup(mock)
 
-			err := repo.Create(tt.transaction)
+			err := repo.Create(context.Background(), tt.transaction)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -99,8 +101,8 @@ func TestTransactionRepository_ListByUser(t *testing.T) {
 					"id", "created_at", "updated_at", "deleted_at", "user_id",
 					"category_id", "amount", "description", "type", "date",
 				}).
-					AddRow(1, testDate, testDate, nil, 1, 1, 100.50, "Grocery shopping", "expense", testDate).
-					AddRow(2, testDate, testDate, nil, 1, 2, 2500.00, "Monthly salary", "income", testDate)
+					AddRow(1, testDate, testDate, nil, 1, 1, 10050, "Grocery shopping", "expense", testDate).
+					AddRow(2, testDate, testDate, nil, 1, 2, 250000, "Monthly salary", "income", testDate)
 				mock.ExpectQuery("SELECT \\* FROM `transactions`").
 					WithArgs(1).
 					WillReturnRows(rows)
@@ -110,7 +112,7 @@ func TestTransactionRepository_ListByUser(t *testing.T) {
 					ID:          1,
 					UserID:      1,
 					CategoryID:  1,
-					Amount:      100.50,
+					Amount:      domain.NewMoney(100.50),
 					Description: "Grocery shopping",
 					Type:        "expense",
 					Date:        testDate,
@@ -119,7 +121,7 @@ func TestTransactionRepository_ListByUser(t *testing.T) {
 					ID:          2,
 					UserID:      1,
 					CategoryID:  2,
-					Amount:      2500.00,
+					Amount:      domain.NewMoney(2500.00),
 					Description: "Monthly salary",
 					Type:        "income",
 					Date:        testDate,
@@ -166,7 +168,7 @@ func TestTransactionRepository_ListByUser(t *testing.T) {
 			repo := NewTransactionRepository(db)
 			tt.setup(mock)
 
-			transactions, err := repo.ListByUser(tt.userID)
+			transactions, err := repo.ListByUser(context.Background(), tt.userID)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -0,0 +1,83 @@
+package persistence
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type unitOfWorkKey struct{}
+
+// unitOfWork is the database transaction a context's writes share and the
+// work waiting for it to commit. It belongs to the goroutine that started
+// it; a unit of work is not for concurrent use.
+type unitOfWork struct {
+	tx          *gorm.DB
+	afterCommit []func()
+	done        bool
+}
+
+func activeWork(ctx context.Context) (*unitOfWork, bool) {
+	work, ok := ctx.Value(unitOfWorkKey{}).(*unitOfWork)
+	return work, ok && !work.done
+}
+
+// InTransaction runs fn as one unit of work: every statement fn's context
+// sends through Conn, and so through the GORM repositories, shares one
+// database transaction, committed when fn returns nil and rolled back when
+// it returns an error or panics. A unit of work started within fn joins
+// the outer one as a savepoint, so its failure only undoes its own writes.
+// Work registered with AfterCommit runs once the outermost one committed.
+// Without a database fn runs on its own.
+func InTransaction(ctx context.Context, db *gorm.DB, fn func(ctx context.Context) error) error {
+	if work, ok := activeWork(ctx); ok {
+		queued := len(work.afterCommit)
+		err := work.tx.WithContext(ctx).Transaction(func(*gorm.DB) error {
+			return fn(ctx)
+		})
+		if err != nil {
+			work.afterCommit = work.afterCommit[:queued]
+		}
+		return err
+	}
+	if db == nil {
+		return fn(ctx)
+	}
+
+	work := &unitOfWork{}
+	err := func() error {
+		defer func() { work.done = true }()
+		return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			work.tx = tx
+			return fn(context.WithValue(ctx, unitOfWorkKey{}, work))
+		})
+	}()
+	if err != nil {
+		return err
+	}
+	for _, run := range work.afterCommit {
+		run()
+	}
+	return nil
+}
+
+// Conn returns what statements of ctx run on: the transaction of its unit
+// of work, or else db, with ctx set
+func Conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if work, ok := activeWork(ctx); ok {
+		return work.tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
+
+// AfterCommit runs fn once the unit of work of ctx committed, and never if
+// it rolls back; outside of a unit of work fn runs right away. Changes are
+// announced this way, so nobody hears of writes that were undone. fn may
+// use ctx, whose statements no longer join the finished transaction.
+func AfterCommit(ctx context.Context, fn func()) {
+	if work, ok := activeWork(ctx); ok {
+		work.afterCommit = append(work.afterCommit, fn)
+		return
+	}
+	fn()
+}
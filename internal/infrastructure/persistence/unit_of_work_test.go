@@ -0,0 +1,96 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestInTransaction(t *testing.T) {
+	db := setupRepositoryTestDB(t)
+	repo := NewTransactionRepository(db)
+	ctx := context.Background()
+	newTransaction := func(description string) *domain.Transaction {
+		return &domain.Transaction{
+			UserID: 1, CategoryID: 1, Type: domain.TransactionTypeExpense, Amount: 100, Description: description, Date: time.Now(),
+		}
+	}
+	count := func(t *testing.T) int64 {
+		var n int64
+		require.NoError(t, db.Model(&domain.Transaction{}).Count(&n).Error)
+		return n
+	}
+
+	t.Run("should commit the writes of the unit together", func(t *testing.T) {
+		committed := false
+		err := InTransaction(ctx, db, func(ctx context.Context) error {
+			require.NoError(t, repo.Create(ctx, newTransaction("Rent")))
+			require.NoError(t, Conn(ctx, db).Create(newTransaction("Power")).Error)
+			AfterCommit(ctx, func() { committed = true })
+			assert.False(t, committed, "after commit work waits for the commit")
+			return nil
+		})
+		require.NoError(t, err)
+		assert.True(t, committed)
+		assert.Equal(t, int64(2), count(t))
+	})
+
+	t.Run("should roll every write back when the unit fails", func(t *testing.T) {
+		committed := false
+		err := InTransaction(ctx, db, func(ctx context.Context) error {
+			require.NoError(t, repo.Create(ctx, newTransaction("Groceries")))
+			AfterCommit(ctx, func() { committed = true })
+			return errors.New("budget update failed")
+		})
+		assert.EqualError(t, err, "budget update failed")
+		assert.False(t, committed)
+		assert.Equal(t, int64(2), count(t))
+
+		assert.Panics(t, func() {
+			_ = InTransaction(ctx, db, func(ctx context.Context) error {
+				require.NoError(t, repo.Create(ctx, newTransaction("Groceries")))
+				panic("boom")
+			})
+		})
+		assert.Equal(t, int64(2), count(t))
+	})
+
+	t.Run("should only undo a failed nested unit", func(t *testing.T) {
+		var announced []string
+		err := InTransaction(ctx, db, func(ctx context.Context) error {
+			require.NoError(t, repo.Create(ctx, newTransaction("Salary")))
+			AfterCommit(ctx, func() { announced = append(announced, "Salary") })
+			nested := InTransaction(ctx, db, func(ctx context.Context) error {
+				require.NoError(t, repo.Create(ctx, newTransaction("Bonus")))
+				AfterCommit(ctx, func() { announced = append(announced, "Bonus") })
+				return gorm.ErrInvalidData
+			})
+			assert.ErrorIs(t, nested, gorm.ErrInvalidData)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Salary"}, announced)
+		assert.Equal(t, int64(3), count(t))
+	})
+
+	t.Run("should run after commit work right away outside of a unit", func(t *testing.T) {
+		ran := false
+		AfterCommit(ctx, func() { ran = true })
+		assert.True(t, ran)
+
+		var finished context.Context
+		require.NoError(t, InTransaction(ctx, db, func(ctx context.Context) error {
+			finished = ctx
+			return nil
+		}))
+		require.NoError(t, repo.Create(finished, newTransaction("Late")), "a finished unit leaves its context usable")
+		assert.Equal(t, int64(4), count(t))
+	})
+}
@@ -3,21 +3,22 @@
 package persistence
 
 import (
+	"context"
 	"log"
+	"time"
 
-	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/persistence/migrations"
 
-	sqlite "github.com/glebarez/sqlite"
 	"gorm.io/gorm"
 )
 
 func NewDB() *gorm.DB {
-	db, err := gorm.Open(sqlite.Open("finance.db"), &gorm.Config{})
+	db, err := OpenSQLite("finance.db", 5*time.Second)
 	if err != nil {
 		log.Fatalf("failed to connect database: %v", err)
 	}
 
-	if err := db.AutoMigrate(&domain.User{}, &domain.Transaction{}); err != nil {
+	if _, err := migrations.New(db).Up(context.Background()); err != nil {
 		log.Fatalf("failed to migrate database: %v", err)
 	}
 	return db
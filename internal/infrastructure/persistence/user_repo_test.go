@@ -1,6 +1,7 @@
 package persistence
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -47,7 +48,8 @@ func TestUserRepository_Create(t *testing.T) {
 			setup: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
 				mock.ExpectExec("INSERT INTO `users`").
-					WithArgs("john@example.com", "hashedpassword", "John", "Doe", 30, "moderate", sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WithArgs("john@example.com", "hashedpassword", "John", "Doe", 30, "moderate", "en", "personal", "free", sqlmock.AnyArg(),
+						sqlmock.AnyArg(), nil, "", 0, 0, "", "", "", nil).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 				mock.ExpectCommit()
 			},
@@ -82,7 +84,7 @@ func TestUserRepository_Create(t *testing.T) {
 			repo := NewUserRepository(db)
 			tt.setup(mock)
 
-			err := repo.Create(tt.user)
+			err := repo.Create(context.Background(), tt.user)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -157,7 +159,7 @@ func TestUserRepository_GetByID(t *testing.T) {
 			repo := NewUserRepository(db)
 			tt.setup(mock)
 
-			user, err := repo.GetByID(tt.userID)
+			user, err := repo.GetByID(context.Background(), tt.userID)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -196,7 +198,8 @@ func TestUserRepository_Update(t *testing.T) {
 			setup: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
 				mock.ExpectExec("UPDATE `users`").
-					WithArgs("john.updated@example.com", "newhashedpassword", "John", "Updated", 0, "moderate", sqlmock.AnyArg(), sqlmock.AnyArg(), 1).
+					WithArgs("john.updated@example.com", "newhashedpassword", "John", "Updated", 0, "moderate", "", "", "", sqlmock.AnyArg(),
+						sqlmock.AnyArg(), nil, "", 0, 0, "", "", "", nil, 1).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 				mock.ExpectCommit()
 			},
@@ -234,7 +237,7 @@ func TestUserRepository_Update(t *testing.T) {
 			repo := NewUserRepository(db)
 			tt.setup(mock)
 
-			err := repo.Update(tt.user)
+			err := repo.Update(context.Background(), tt.user)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -0,0 +1,70 @@
+package persistence
+
+import (
+	"slices"
+
+	"go-finance-advisor/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UserOwnedTables are the tables whose rows only their user ever reads or
+// changes. Transactions, budgets and categories are left out: households
+// share transactions and budgets between members, and categories without a
+// user are shared by everyone, so their services scope them explicitly. The
+// API's middleware.OwnUserMiddleware backs those up by keeping every
+// /users/:userId route to its own user.
+var UserOwnedTables = []string{
+	"accounts", "advice_records", "bank_links", "bills", "calendar_feeds", "category_caps", "category_models",
+	"digest_subscriptions", "duplicate_dismissals", "export_templates", "health_snapshots", "holdings",
+	"income_sources", "loan_payments", "loans", "notification_preferences", "notifications", "plugin_runs",
+	"plugins", "price_alert_triggers", "price_alerts", "push_devices", "risk_assessments", "savings_rules",
+	"scheduled_transfers", "sync_changes", "sync_conflicts", "sync_mutation_clients", "transaction_archives",
+	"usage_counters", "user_preferences", "wallets", "watchlist_items", "webhooks",
+}
+
+// UserScope is a GORM plugin that limits the queries, updates and deletes of
+// user-owned tables to the rows of the user acting in the statement's
+// context, on top of whatever conditions the caller gave. A service that
+// forgets its user filter then finds nothing of other users' instead of
+// leaking it. Statements without an acting user, such as background jobs,
+// and contexts from domain.ContextWithoutUserScope are left alone, as is
+// raw SQL.
+type UserScope struct {
+	Tables []string
+}
+
+func NewUserScope(tables ...string) *UserScope {
+	return &UserScope{Tables: tables}
+}
+
+func (p *UserScope) Name() string { return "user_scope" }
+
+// Initialize registers the callbacks
+func (p *UserScope) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if err := callbacks.Query().Before("gorm:query").Register("user_scope:query", p.scope); err != nil {
+		return err
+	}
+	if err := callbacks.Row().Before("gorm:row").Register("user_scope:row", p.scope); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("user_scope:update", p.scope); err != nil {
+		return err
+	}
+	return callbacks.Delete().Before("gorm:delete").Register("user_scope:delete", p.scope)
+}
+
+func (p *UserScope) scope(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Context == nil || !slices.Contains(p.Tables, db.Statement.Table) {
+		return
+	}
+	userID, ok := domain.UserScopeFromContext(db.Statement.Context)
+	if !ok {
+		return
+	}
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: "user_id"}, Value: userID},
+	}})
+}
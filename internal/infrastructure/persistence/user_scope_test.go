@@ -0,0 +1,79 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestUserScope(t *testing.T) {
+	db := setupRepositoryTestDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.Notification{}))
+	require.NoError(t, db.Use(NewUserScope(UserOwnedTables...)))
+
+	notifications := []domain.Notification{
+		{UserID: 1, Type: domain.NotificationTypeBudget, Title: "Mine"},
+		{UserID: 2, Type: domain.NotificationTypeBudget, Title: "Theirs"},
+	}
+	require.NoError(t, db.Create(&notifications).Error)
+	transactions := []domain.Transaction{
+		{UserID: 1, Amount: domain.NewMoney(10), Date: time.Now()},
+		{UserID: 2, Amount: domain.NewMoney(20), Date: time.Now()},
+	}
+	require.NoError(t, db.Create(&transactions).Error)
+
+	ctx := domain.ContextWithActor(context.Background(), domain.Actor{UserID: 1})
+	theirs := notifications[1].ID
+
+	t.Run("queries only find the acting user's rows", func(t *testing.T) {
+		var found []domain.Notification
+		require.NoError(t, db.WithContext(ctx).Find(&found).Error)
+		require.Len(t, found, 1)
+		assert.Equal(t, "Mine", found[0].Title)
+
+		var notification domain.Notification
+		err := db.WithContext(ctx).First(&notification, theirs).Error
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		var count int64
+		require.NoError(t, db.WithContext(ctx).Model(&domain.Notification{}).Count(&count).Error)
+		assert.Equal(t, int64(1), count)
+
+		var titles []string
+		require.NoError(t, db.WithContext(ctx).Model(&domain.Notification{}).Pluck("title", &titles).Error)
+		assert.Equal(t, []string{"Mine"}, titles)
+	})
+
+	t.Run("updates and deletes leave other users' rows alone", func(t *testing.T) {
+		result := db.WithContext(ctx).Model(&domain.Notification{}).Where("id = ?", theirs).Update("title", "Taken")
+		require.NoError(t, result.Error)
+		assert.Zero(t, result.RowsAffected)
+
+		result = db.WithContext(ctx).Delete(&domain.Notification{}, theirs)
+		require.NoError(t, result.Error)
+		assert.Zero(t, result.RowsAffected)
+
+		var notification domain.Notification
+		require.NoError(t, db.First(&notification, theirs).Error)
+		assert.Equal(t, "Theirs", notification.Title)
+	})
+
+	t.Run("unscoped contexts and tables reach every user", func(t *testing.T) {
+		var count int64
+		require.NoError(t, db.Model(&domain.Notification{}).Count(&count).Error)
+		assert.Equal(t, int64(2), count, "no acting user")
+
+		unscoped := domain.ContextWithoutUserScope(ctx)
+		require.NoError(t, db.WithContext(unscoped).Model(&domain.Notification{}).Count(&count).Error)
+		assert.Equal(t, int64(2), count, "scope lifted")
+
+		require.NoError(t, db.WithContext(ctx).Model(&domain.Transaction{}).Count(&count).Error)
+		assert.Equal(t, int64(2), count, "shared table")
+	})
+}
@@ -0,0 +1,65 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"go-finance-advisor/internal/application"
+	"go-finance-advisor/internal/domain"
+	pb "go-finance-advisor/internal/infrastructure/rpc/gen/financeadvisor/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type advisorServer struct {
+	pb.UnimplementedAdvisorServiceServer
+	advisor *application.AdvisorService
+	users   *application.UserService
+	quotas  *application.QuotaService
+}
+
+// GetAdvice needs the AI advisor feature and counts against the AI requests
+// quota, like the advice endpoint over HTTP. Both checks are soft: when the
+// plan cannot be read, the call is let through.
+func (s *advisorServer) GetAdvice(ctx context.Context, _ *pb.GetAdviceRequest) (*pb.InvestmentAdvice, error) {
+	if err := s.checkPlan(ctx, userID(ctx)); err != nil {
+		return nil, err
+	}
+
+	user, err := s.users.GetByID(ctx, userID(ctx))
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+
+	advice, err := s.advisor.GenerateAdvice(ctx, &user)
+	if err != nil {
+		return nil, statusError(err, "failed to generate advice")
+	}
+	return toAdvice(advice), nil
+}
+
+// checkPlan enforces the user's plan, as middleware.FeatureMiddleware and
+// middleware.QuotaMiddleware do for HTTP requests
+func (s *advisorServer) checkPlan(ctx context.Context, id uint) error {
+	if s.quotas == nil {
+		return nil
+	}
+
+	included, err := s.quotas.HasFeature(ctx, id, domain.FeatureAIAdvisor)
+	if err != nil {
+		log.Printf("grpc: checking whether the plan of user %d includes %s failed: %v", id, domain.FeatureAIAdvisor, err)
+	} else if !included {
+		return status.Error(codes.PermissionDenied, "this feature needs the premium plan")
+	}
+
+	_, err = s.quotas.Consume(ctx, id, domain.QuotaAIRequests)
+	switch {
+	case errors.Is(err, domain.ErrQuotaExceeded):
+		return status.Error(codes.ResourceExhausted, "the daily quota of your plan is used up, please try again tomorrow")
+	case err != nil:
+		log.Printf("grpc: counting the %s quota of user %d failed: %v", domain.QuotaAIRequests, id, err)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package rpc
+
+import (
+	"context"
+	"time"
+
+	"go-finance-advisor/internal/application"
+	"go-finance-advisor/internal/domain"
+	pb "go-finance-advisor/internal/infrastructure/rpc/gen/financeadvisor/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type budgetServer struct {
+	pb.UnimplementedBudgetServiceServer
+	service *application.BudgetService
+}
+
+// budgetPeriods maps the periods the HTTP API accepts to their length
+var budgetPeriods = map[string]func(time.Time) time.Time{
+	domain.PeriodWeekly:  func(start time.Time) time.Time { return start.AddDate(0, 0, 7) },
+	domain.PeriodMonthly: func(start time.Time) time.Time { return start.AddDate(0, 1, 0) },
+	"quarterly":          func(start time.Time) time.Time { return start.AddDate(0, 3, 0) },
+	domain.PeriodYearly:  func(start time.Time) time.Time { return start.AddDate(1, 0, 0) },
+}
+
+// CreateBudget creates a personal budget. Without a start date it starts
+// today, and without an end date it runs for one period.
+func (s *budgetServer) CreateBudget(ctx context.Context, req *pb.CreateBudgetRequest) (*pb.Budget, error) {
+	if req.CategoryId == 0 {
+		return nil, status.Error(codes.InvalidArgument, "category_id is required")
+	}
+	if domain.NewMoney(req.Amount) <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "amount must be greater than zero")
+	}
+	if req.Period == "" {
+		req.Period = domain.PeriodMonthly
+	}
+	periodEnd, ok := budgetPeriods[req.Period]
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "period must be weekly, monthly, quarterly or yearly")
+	}
+
+	budget := &domain.Budget{
+		UserID:     userID(ctx),
+		CategoryID: uint(req.CategoryId),
+		Amount:     domain.NewMoney(req.Amount),
+		Period:     req.Period,
+		StartDate:  time.Now().Truncate(24 * time.Hour),
+		IsActive:   true,
+	}
+	if req.StartDate != nil {
+		budget.StartDate = req.StartDate.AsTime()
+	}
+	budget.EndDate = periodEnd(budget.StartDate)
+	if req.EndDate != nil {
+		budget.EndDate = req.EndDate.AsTime()
+	}
+	if !budget.EndDate.After(budget.StartDate) {
+		return nil, status.Error(codes.InvalidArgument, "end_date must be after start_date")
+	}
+
+	if err := s.service.CreateBudget(ctx, budget); err != nil {
+		return nil, statusError(err, "failed to create budget")
+	}
+	return toBudget(budget), nil
+}
+
+func (s *budgetServer) ListBudgets(ctx context.Context, req *pb.ListBudgetsRequest) (*pb.ListBudgetsResponse, error) {
+	var budgets []domain.Budget
+	var err error
+	if req.ActiveOnly {
+		budgets, err = s.service.GetActiveBudgetsByUser(ctx, userID(ctx))
+	} else {
+		budgets, err = s.service.GetBudgetsByUser(ctx, userID(ctx))
+	}
+	if err != nil {
+		return nil, statusError(err, "failed to retrieve budgets")
+	}
+
+	resp := &pb.ListBudgetsResponse{Budgets: make([]*pb.Budget, 0, len(budgets))}
+	for i := range budgets {
+		resp.Budgets = append(resp.Budgets, toBudget(&budgets[i]))
+	}
+	return resp, nil
+}
+
+func (s *budgetServer) GetBudgetSummary(ctx context.Context, _ *pb.GetBudgetSummaryRequest) (*pb.BudgetSummary, error) {
+	summary, err := s.service.GetBudgetSummary(ctx, userID(ctx))
+	if err != nil {
+		return nil, statusError(err, "failed to retrieve budget summary")
+	}
+	return &pb.BudgetSummary{
+		TotalBudget:    summary.TotalBudget.Float64(),
+		TotalSpent:     summary.TotalSpent.Float64(),
+		TotalRemaining: summary.TotalRemaining.Float64(),
+		PercentageUsed: summary.PercentageUsed,
+		BudgetStatus:   summary.BudgetStatus,
+	}, nil
+}
+
+// DeleteBudget deletes one of the caller's personal budgets. Other users'
+// and household budgets are reported as not found.
+func (s *budgetServer) DeleteBudget(ctx context.Context, req *pb.DeleteBudgetRequest) (*pb.DeleteBudgetResponse, error) {
+	budget, err := s.service.GetBudgetByID(ctx, uint(req.Id))
+	if err != nil {
+		return nil, statusError(err, "failed to retrieve budget")
+	}
+	if budget.UserID != userID(ctx) || budget.HouseholdID != nil {
+		return nil, status.Error(codes.NotFound, domain.ErrNotFound.Error())
+	}
+
+	if err := s.service.DeleteBudget(ctx, budget.ID); err != nil {
+		return nil, statusError(err, "failed to delete budget")
+	}
+	return &pb.DeleteBudgetResponse{}, nil
+}
@@ -0,0 +1,138 @@
+package rpc
+
+import (
+	"time"
+
+	"go-finance-advisor/internal/application"
+	"go-finance-advisor/internal/domain"
+	pb "go-finance-advisor/internal/infrastructure/rpc/gen/financeadvisor/v1"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// timestamp converts a time, leaving zero times unset
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// optionalID converts an optional foreign key
+func optionalID(id *uint) *uint64 {
+	if id == nil {
+		return nil
+	}
+	value := uint64(*id)
+	return &value
+}
+
+func toTransaction(t *domain.Transaction) *pb.Transaction {
+	return &pb.Transaction{
+		Id:           uint64(t.ID),
+		UserId:       uint64(t.UserID),
+		CategoryId:   uint64(t.CategoryID),
+		CategoryName: t.Category.Name,
+		Type:         t.Type,
+		Description:  t.Description,
+		Amount:       t.Amount.Float64(),
+		Date:         timestamp(t.Date),
+		HouseholdId:  optionalID(t.HouseholdID),
+		MerchantId:   optionalID(t.MerchantID),
+		CreatedAt:    timestamp(t.CreatedAt),
+		UpdatedAt:    timestamp(t.UpdatedAt),
+	}
+}
+
+func toBudget(b *domain.Budget) *pb.Budget {
+	return &pb.Budget{
+		Id:           uint64(b.ID),
+		UserId:       uint64(b.UserID),
+		CategoryId:   uint64(b.CategoryID),
+		CategoryName: b.Category.Name,
+		Amount:       b.Amount.Float64(),
+		Period:       b.Period,
+		StartDate:    timestamp(b.StartDate),
+		EndDate:      timestamp(b.EndDate),
+		Spent:        b.Spent.Float64(),
+		Remaining:    b.Remaining.Float64(),
+		IsActive:     b.IsActive,
+		HouseholdId:  optionalID(b.HouseholdID),
+	}
+}
+
+func toCategoryMetrics(metrics []domain.CategoryMetrics) []*pb.CategoryMetrics {
+	converted := make([]*pb.CategoryMetrics, 0, len(metrics))
+	for i := range metrics {
+		m := &metrics[i]
+		converted = append(converted, &pb.CategoryMetrics{
+			CategoryId:        uint64(m.CategoryID),
+			CategoryName:      m.CategoryName,
+			TotalAmount:       m.TotalAmount,
+			TransactionCount:  int32(m.TransactionCount),
+			PercentageOfTotal: m.PercentageOfTotal,
+			AverageAmount:     m.AverageAmount,
+			Trend:             m.Trend,
+			Subcategories:     toCategoryMetrics(m.Subcategories),
+		})
+	}
+	return converted
+}
+
+func toReport(r *domain.FinancialReport) *pb.FinancialReport {
+	trends := make([]*pb.MonthlyTrend, 0, len(r.MonthlyTrends))
+	for _, trend := range r.MonthlyTrends {
+		trends = append(trends, &pb.MonthlyTrend{
+			Month:       trend.Month,
+			Year:        int32(trend.Year),
+			Income:      trend.Income,
+			Expenses:    trend.Expenses,
+			NetIncome:   trend.NetIncome,
+			SavingsRate: trend.SavingsRate,
+		})
+	}
+
+	performance := r.BudgetPerformance
+	return &pb.FinancialReport{
+		UserId:            uint64(r.UserID),
+		ReportType:        r.ReportType,
+		StartDate:         timestamp(r.StartDate),
+		EndDate:           timestamp(r.EndDate),
+		TotalIncome:       r.TotalIncome,
+		TotalExpenses:     r.TotalExpenses,
+		NetIncome:         r.NetIncome,
+		SavingsRate:       r.SavingsRate,
+		TransactionCount:  int32(r.TransactionCount),
+		CategoryBreakdown: toCategoryMetrics(r.CategoryBreakdown),
+		MonthlyTrends:     trends,
+		BudgetPerformance: &pb.BudgetPerformance{
+			TotalBudgeted:         performance.TotalBudgeted,
+			TotalSpent:            performance.TotalSpent,
+			Variance:              performance.Variance,
+			VariancePercentage:    performance.VariancePercentage,
+			CategoriesOverBudget:  int32(performance.CategoriesOverBudget),
+			CategoriesUnderBudget: int32(performance.CategoriesUnderBudget),
+		},
+		TopIncomeCategories:  toCategoryMetrics(r.TopIncomeCategories),
+		TopExpenseCategories: toCategoryMetrics(r.TopExpenseCategories),
+		Insights:             r.Insights,
+		Recommendations:      r.Recommendations,
+		GeneratedAt:          timestamp(r.GeneratedAt),
+	}
+}
+
+func toAdvice(a *application.InvestmentAdvice) *pb.InvestmentAdvice {
+	recommendations := make([]*pb.Recommendation, 0, len(a.Recommendations))
+	for _, rec := range a.Recommendations {
+		recommendations = append(recommendations, &pb.Recommendation{
+			Asset:   rec.Asset,
+			Amount:  rec.Amount,
+			Percent: rec.Percent,
+		})
+	}
+	return &pb.InvestmentAdvice{
+		MonthlySavings:  a.MonthlySavings,
+		Risk:            a.Risk,
+		Recommendations: recommendations,
+	}
+}
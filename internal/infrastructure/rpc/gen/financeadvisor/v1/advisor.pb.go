@@ -0,0 +1,315 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        v5.27.1
+// source: financeadvisor/v1/advisor.proto
+
+package financeadvisorv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetAdviceRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetAdviceRequest) Reset() {
+	*x = GetAdviceRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_financeadvisor_v1_advisor_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetAdviceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAdviceRequest) ProtoMessage() {}
+
+func (x *GetAdviceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_financeadvisor_v1_advisor_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAdviceRequest.ProtoReflect.Descriptor instead.
+func (*GetAdviceRequest) Descriptor() ([]byte, []int) {
+	return file_financeadvisor_v1_advisor_proto_rawDescGZIP(), []int{0}
+}
+
+type InvestmentAdvice struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MonthlySavings  float64           `protobuf:"fixed64,1,opt,name=monthly_savings,json=monthlySavings,proto3" json:"monthly_savings,omitempty"`
+	Risk            string            `protobuf:"bytes,2,opt,name=risk,proto3" json:"risk,omitempty"`
+	Recommendations []*Recommendation `protobuf:"bytes,3,rep,name=recommendations,proto3" json:"recommendations,omitempty"`
+}
+
+func (x *InvestmentAdvice) Reset() {
+	*x = InvestmentAdvice{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_financeadvisor_v1_advisor_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InvestmentAdvice) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InvestmentAdvice) ProtoMessage() {}
+
+func (x *InvestmentAdvice) ProtoReflect() protoreflect.Message {
+	mi := &file_financeadvisor_v1_advisor_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InvestmentAdvice.ProtoReflect.Descriptor instead.
+func (*InvestmentAdvice) Descriptor() ([]byte, []int) {
+	return file_financeadvisor_v1_advisor_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *InvestmentAdvice) GetMonthlySavings() float64 {
+	if x != nil {
+		return x.MonthlySavings
+	}
+	return 0
+}
+
+func (x *InvestmentAdvice) GetRisk() string {
+	if x != nil {
+		return x.Risk
+	}
+	return ""
+}
+
+func (x *InvestmentAdvice) GetRecommendations() []*Recommendation {
+	if x != nil {
+		return x.Recommendations
+	}
+	return nil
+}
+
+type Recommendation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Asset   string  `protobuf:"bytes,1,opt,name=asset,proto3" json:"asset,omitempty"`
+	Amount  float64 `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Percent float64 `protobuf:"fixed64,3,opt,name=percent,proto3" json:"percent,omitempty"`
+}
+
+func (x *Recommendation) Reset() {
+	*x = Recommendation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_financeadvisor_v1_advisor_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Recommendation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Recommendation) ProtoMessage() {}
+
+func (x *Recommendation) ProtoReflect() protoreflect.Message {
+	mi := &file_financeadvisor_v1_advisor_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Recommendation.ProtoReflect.Descriptor instead.
+func (*Recommendation) Descriptor() ([]byte, []int) {
+	return file_financeadvisor_v1_advisor_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Recommendation) GetAsset() string {
+	if x != nil {
+		return x.Asset
+	}
+	return ""
+}
+
+func (x *Recommendation) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Recommendation) GetPercent() float64 {
+	if x != nil {
+		return x.Percent
+	}
+	return 0
+}
+
+var File_financeadvisor_v1_advisor_proto protoreflect.FileDescriptor
+
+var file_financeadvisor_v1_advisor_proto_rawDesc = []byte{
+	0x0a, 0x1f, 0x66, 0x69, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x61, 0x64, 0x76, 0x69, 0x73, 0x6f, 0x72,
+	0x2f, 0x76, 0x31, 0x2f, 0x61, 0x64, 0x76, 0x69, 0x73, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x12, 0x11, 0x66, 0x69, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x61, 0x64, 0x76, 0x69, 0x73, 0x6f,
+	0x72, 0x2e, 0x76, 0x31, 0x22, 0x12, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x41, 0x64, 0x76, 0x69, 0x63,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x9c, 0x01, 0x0a, 0x10, 0x49, 0x6e, 0x76,
+	0x65, 0x73, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x41, 0x64, 0x76, 0x69, 0x63, 0x65, 0x12, 0x27, 0x0a,
+	0x0f, 0x6d, 0x6f, 0x6e, 0x74, 0x68, 0x6c, 0x79, 0x5f, 0x73, 0x61, 0x76, 0x69, 0x6e, 0x67, 0x73,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0e, 0x6d, 0x6f, 0x6e, 0x74, 0x68, 0x6c, 0x79, 0x53,
+	0x61, 0x76, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x69, 0x73, 0x6b, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x72, 0x69, 0x73, 0x6b, 0x12, 0x4b, 0x0a, 0x0f, 0x72, 0x65,
+	0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x66, 0x69, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x61, 0x64, 0x76,
+	0x69, 0x73, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e,
+	0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0f, 0x72, 0x65, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e,
+	0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x58, 0x0a, 0x0e, 0x52, 0x65, 0x63, 0x6f, 0x6d,
+	0x6d, 0x65, 0x6e, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x73, 0x73,
+	0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x61, 0x73, 0x73, 0x65, 0x74, 0x12,
+	0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x65, 0x72, 0x63, 0x65,
+	0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e,
+	0x74, 0x32, 0x67, 0x0a, 0x0e, 0x41, 0x64, 0x76, 0x69, 0x73, 0x6f, 0x72, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x12, 0x55, 0x0a, 0x09, 0x47, 0x65, 0x74, 0x41, 0x64, 0x76, 0x69, 0x63, 0x65,
+	0x12, 0x23, 0x2e, 0x66, 0x69, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x61, 0x64, 0x76, 0x69, 0x73, 0x6f,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x41, 0x64, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x66, 0x69, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x61,
+	0x64, 0x76, 0x69, 0x73, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x76, 0x65, 0x73, 0x74,
+	0x6d, 0x65, 0x6e, 0x74, 0x41, 0x64, 0x76, 0x69, 0x63, 0x65, 0x42, 0x57, 0x5a, 0x55, 0x67, 0x6f,
+	0x2d, 0x66, 0x69, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x2d, 0x61, 0x64, 0x76, 0x69, 0x73, 0x6f, 0x72,
+	0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x73,
+	0x74, 0x72, 0x75, 0x63, 0x74, 0x75, 0x72, 0x65, 0x2f, 0x72, 0x70, 0x63, 0x2f, 0x67, 0x65, 0x6e,
+	0x2f, 0x66, 0x69, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x61, 0x64, 0x76, 0x69, 0x73, 0x6f, 0x72, 0x2f,
+	0x76, 0x31, 0x3b, 0x66, 0x69, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x61, 0x64, 0x76, 0x69, 0x73, 0x6f,
+	0x72, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_financeadvisor_v1_advisor_proto_rawDescOnce sync.Once
+	file_financeadvisor_v1_advisor_proto_rawDescData = file_financeadvisor_v1_advisor_proto_rawDesc
+)
+
+func file_financeadvisor_v1_advisor_proto_rawDescGZIP() []byte {
+	file_financeadvisor_v1_advisor_proto_rawDescOnce.Do(func() {
+		file_financeadvisor_v1_advisor_proto_rawDescData = protoimpl.X.CompressGZIP(file_financeadvisor_v1_advisor_proto_rawDescData)
+	})
+	return file_financeadvisor_v1_advisor_proto_rawDescData
+}
+
+var file_financeadvisor_v1_advisor_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_financeadvisor_v1_advisor_proto_goTypes = []any{
+	(*GetAdviceRequest)(nil), // 0: financeadvisor.v1.GetAdviceRequest
+	(*InvestmentAdvice)(nil), // 1: financeadvisor.v1.InvestmentAdvice
+	(*Recommendation)(nil),   // 2: financeadvisor.v1.Recommendation
+}
+var file_financeadvisor_v1_advisor_proto_depIdxs = []int32{
+	2, // 0: financeadvisor.v1.InvestmentAdvice.recommendations:type_name -> financeadvisor.v1.Recommendation
+	0, // 1: financeadvisor.v1.AdvisorService.GetAdvice:input_type -> financeadvisor.v1.GetAdviceRequest
+	1, // 2: financeadvisor.v1.AdvisorService.GetAdvice:output_type -> financeadvisor.v1.InvestmentAdvice
+	2, // [2:3] is the sub-list for method output_type
+	1, // [1:2] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_financeadvisor_v1_advisor_proto_init() }
+func file_financeadvisor_v1_advisor_proto_init() {
+	if File_financeadvisor_v1_advisor_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_financeadvisor_v1_advisor_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*GetAdviceRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_financeadvisor_v1_advisor_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*InvestmentAdvice); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_financeadvisor_v1_advisor_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*Recommendation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_financeadvisor_v1_advisor_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_financeadvisor_v1_advisor_proto_goTypes,
+		DependencyIndexes: file_financeadvisor_v1_advisor_proto_depIdxs,
+		MessageInfos:      file_financeadvisor_v1_advisor_proto_msgTypes,
+	}.Build()
+	File_financeadvisor_v1_advisor_proto = out.File
+	file_financeadvisor_v1_advisor_proto_rawDesc = nil
+	file_financeadvisor_v1_advisor_proto_goTypes = nil
+	file_financeadvisor_v1_advisor_proto_depIdxs = nil
+}
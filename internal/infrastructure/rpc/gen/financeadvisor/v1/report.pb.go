@@ -0,0 +1,1037 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        v5.27.1
+// source: financeadvisor/v1/report.proto
+
+package financeadvisorv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GenerateMonthlyReportRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Year  int32 `protobuf:"varint,1,opt,name=year,proto3" json:"year,omitempty"`
+	Month int32 `protobuf:"varint,2,opt,name=month,proto3" json:"month,omitempty"`
+}
+
+func (x *GenerateMonthlyReportRequest) Reset() {
+	*x = GenerateMonthlyReportRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_financeadvisor_v1_report_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GenerateMonthlyReportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateMonthlyReportRequest) ProtoMessage() {}
+
+func (x *GenerateMonthlyReportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_financeadvisor_v1_report_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateMonthlyReportRequest.ProtoReflect.Descriptor instead.
+func (*GenerateMonthlyReportRequest) Descriptor() ([]byte, []int) {
+	return file_financeadvisor_v1_report_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GenerateMonthlyReportRequest) GetYear() int32 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+func (x *GenerateMonthlyReportRequest) GetMonth() int32 {
+	if x != nil {
+		return x.Month
+	}
+	return 0
+}
+
+type GenerateQuarterlyReportRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Year    int32 `protobuf:"varint,1,opt,name=year,proto3" json:"year,omitempty"`
+	Quarter int32 `protobuf:"varint,2,opt,name=quarter,proto3" json:"quarter,omitempty"`
+}
+
+func (x *GenerateQuarterlyReportRequest) Reset() {
+	*x = GenerateQuarterlyReportRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_financeadvisor_v1_report_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GenerateQuarterlyReportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateQuarterlyReportRequest) ProtoMessage() {}
+
+func (x *GenerateQuarterlyReportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_financeadvisor_v1_report_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateQuarterlyReportRequest.ProtoReflect.Descriptor instead.
+func (*GenerateQuarterlyReportRequest) Descriptor() ([]byte, []int) {
+	return file_financeadvisor_v1_report_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GenerateQuarterlyReportRequest) GetYear() int32 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+func (x *GenerateQuarterlyReportRequest) GetQuarter() int32 {
+	if x != nil {
+		return x.Quarter
+	}
+	return 0
+}
+
+type GenerateYearlyReportRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Year int32 `protobuf:"varint,1,opt,name=year,proto3" json:"year,omitempty"`
+}
+
+func (x *GenerateYearlyReportRequest) Reset() {
+	*x = GenerateYearlyReportRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_financeadvisor_v1_report_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GenerateYearlyReportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateYearlyReportRequest) ProtoMessage() {}
+
+func (x *GenerateYearlyReportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_financeadvisor_v1_report_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateYearlyReportRequest.ProtoReflect.Descriptor instead.
+func (*GenerateYearlyReportRequest) Descriptor() ([]byte, []int) {
+	return file_financeadvisor_v1_report_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GenerateYearlyReportRequest) GetYear() int32 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+type GenerateCustomReportRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	StartDate *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+}
+
+func (x *GenerateCustomReportRequest) Reset() {
+	*x = GenerateCustomReportRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_financeadvisor_v1_report_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GenerateCustomReportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateCustomReportRequest) ProtoMessage() {}
+
+func (x *GenerateCustomReportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_financeadvisor_v1_report_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateCustomReportRequest.ProtoReflect.Descriptor instead.
+func (*GenerateCustomReportRequest) Descriptor() ([]byte, []int) {
+	return file_financeadvisor_v1_report_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GenerateCustomReportRequest) GetStartDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *GenerateCustomReportRequest) GetEndDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndDate
+	}
+	return nil
+}
+
+type FinancialReport struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UserId uint64 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// "monthly", "quarterly", "yearly" or "custom"
+	ReportType           string                 `protobuf:"bytes,2,opt,name=report_type,json=reportType,proto3" json:"report_type,omitempty"`
+	StartDate            *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate              *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	TotalIncome          float64                `protobuf:"fixed64,5,opt,name=total_income,json=totalIncome,proto3" json:"total_income,omitempty"`
+	TotalExpenses        float64                `protobuf:"fixed64,6,opt,name=total_expenses,json=totalExpenses,proto3" json:"total_expenses,omitempty"`
+	NetIncome            float64                `protobuf:"fixed64,7,opt,name=net_income,json=netIncome,proto3" json:"net_income,omitempty"`
+	SavingsRate          float64                `protobuf:"fixed64,8,opt,name=savings_rate,json=savingsRate,proto3" json:"savings_rate,omitempty"`
+	TransactionCount     int32                  `protobuf:"varint,9,opt,name=transaction_count,json=transactionCount,proto3" json:"transaction_count,omitempty"`
+	CategoryBreakdown    []*CategoryMetrics     `protobuf:"bytes,10,rep,name=category_breakdown,json=categoryBreakdown,proto3" json:"category_breakdown,omitempty"`
+	MonthlyTrends        []*MonthlyTrend        `protobuf:"bytes,11,rep,name=monthly_trends,json=monthlyTrends,proto3" json:"monthly_trends,omitempty"`
+	BudgetPerformance    *BudgetPerformance     `protobuf:"bytes,12,opt,name=budget_performance,json=budgetPerformance,proto3" json:"budget_performance,omitempty"`
+	TopIncomeCategories  []*CategoryMetrics     `protobuf:"bytes,13,rep,name=top_income_categories,json=topIncomeCategories,proto3" json:"top_income_categories,omitempty"`
+	TopExpenseCategories []*CategoryMetrics     `protobuf:"bytes,14,rep,name=top_expense_categories,json=topExpenseCategories,proto3" json:"top_expense_categories,omitempty"`
+	Insights             []string               `protobuf:"bytes,15,rep,name=insights,proto3" json:"insights,omitempty"`
+	Recommendations      []string               `protobuf:"bytes,16,rep,name=recommendations,proto3" json:"recommendations,omitempty"`
+	GeneratedAt          *timestamppb.Timestamp `protobuf:"bytes,17,opt,name=generated_at,json=generatedAt,proto3" json:"generated_at,omitempty"`
+}
+
+func (x *FinancialReport) Reset() {
+	*x = FinancialReport{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_financeadvisor_v1_report_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FinancialReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FinancialReport) ProtoMessage() {}
+
+func (x *FinancialReport) ProtoReflect() protoreflect.Message {
+	mi := &file_financeadvisor_v1_report_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FinancialReport.ProtoReflect.Descriptor instead.
+func (*FinancialReport) Descriptor() ([]byte, []int) {
+	return file_financeadvisor_v1_report_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *FinancialReport) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *FinancialReport) GetReportType() string {
+	if x != nil {
+		return x.ReportType
+	}
+	return ""
+}
+
+func (x *FinancialReport) GetStartDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *FinancialReport) GetEndDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndDate
+	}
+	return nil
+}
+
+func (x *FinancialReport) GetTotalIncome() float64 {
+	if x != nil {
+		return x.TotalIncome
+	}
+	return 0
+}
+
+func (x *FinancialReport) GetTotalExpenses() float64 {
+	if x != nil {
+		return x.TotalExpenses
+	}
+	return 0
+}
+
+func (x *FinancialReport) GetNetIncome() float64 {
+	if x != nil {
+		return x.NetIncome
+	}
+	return 0
+}
+
+func (x *FinancialReport) GetSavingsRate() float64 {
+	if x != nil {
+		return x.SavingsRate
+	}
+	return 0
+}
+
+func (x *FinancialReport) GetTransactionCount() int32 {
+	if x != nil {
+		return x.TransactionCount
+	}
+	return 0
+}
+
+func (x *FinancialReport) GetCategoryBreakdown() []*CategoryMetrics {
+	if x != nil {
+		return x.CategoryBreakdown
+	}
+	return nil
+}
+
+func (x *FinancialReport) GetMonthlyTrends() []*MonthlyTrend {
+	if x != nil {
+		return x.MonthlyTrends
+	}
+	return nil
+}
+
+func (x *FinancialReport) GetBudgetPerformance() *BudgetPerformance {
+	if x != nil {
+		return x.BudgetPerformance
+	}
+	return nil
+}
+
+func (x *FinancialReport) GetTopIncomeCategories() []*CategoryMetrics {
+	if x != nil {
+		return x.TopIncomeCategories
+	}
+	return nil
+}
+
+func (x *FinancialReport) GetTopExpenseCategories() []*CategoryMetrics {
+	if x != nil {
+		return x.TopExpenseCategories
+	}
+	return nil
+}
+
+func (x *FinancialReport) GetInsights() []string {
+	if x != nil {
+		return x.Insights
+	}
+	return nil
+}
+
+func (x *FinancialReport) GetRecommendations() []string {
+	if x != nil {
+		return x.Recommendations
+	}
+	return nil
+}
+
+func (x *FinancialReport) GetGeneratedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.GeneratedAt
+	}
+	return nil
+}
+
+type CategoryMetrics struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CategoryId        uint64             `protobuf:"varint,1,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	CategoryName      string             `protobuf:"bytes,2,opt,name=category_name,json=categoryName,proto3" json:"category_name,omitempty"`
+	TotalAmount       float64            `protobuf:"fixed64,3,opt,name=total_amount,json=totalAmount,proto3" json:"total_amount,omitempty"`
+	TransactionCount  int32              `protobuf:"varint,4,opt,name=transaction_count,json=transactionCount,proto3" json:"transaction_count,omitempty"`
+	PercentageOfTotal float64            `protobuf:"fixed64,5,opt,name=percentage_of_total,json=percentageOfTotal,proto3" json:"percentage_of_total,omitempty"`
+	AverageAmount     float64            `protobuf:"fixed64,6,opt,name=average_amount,json=averageAmount,proto3" json:"average_amount,omitempty"`
+	Trend             string             `protobuf:"bytes,7,opt,name=trend,proto3" json:"trend,omitempty"`
+	Subcategories     []*CategoryMetrics `protobuf:"bytes,8,rep,name=subcategories,proto3" json:"subcategories,omitempty"`
+}
+
+func (x *CategoryMetrics) Reset() {
+	*x = CategoryMetrics{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_financeadvisor_v1_report_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CategoryMetrics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CategoryMetrics) ProtoMessage() {}
+
+func (x *CategoryMetrics) ProtoReflect() protoreflect.Message {
+	mi := &file_financeadvisor_v1_report_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CategoryMetrics.ProtoReflect.Descriptor instead.
+func (*CategoryMetrics) Descriptor() ([]byte, []int) {
+	return file_financeadvisor_v1_report_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CategoryMetrics) GetCategoryId() uint64 {
+	if x != nil {
+		return x.CategoryId
+	}
+	return 0
+}
+
+func (x *CategoryMetrics) GetCategoryName() string {
+	if x != nil {
+		return x.CategoryName
+	}
+	return ""
+}
+
+func (x *CategoryMetrics) GetTotalAmount() float64 {
+	if x != nil {
+		return x.TotalAmount
+	}
+	return 0
+}
+
+func (x *CategoryMetrics) GetTransactionCount() int32 {
+	if x != nil {
+		return x.TransactionCount
+	}
+	return 0
+}
+
+func (x *CategoryMetrics) GetPercentageOfTotal() float64 {
+	if x != nil {
+		return x.PercentageOfTotal
+	}
+	return 0
+}
+
+func (x *CategoryMetrics) GetAverageAmount() float64 {
+	if x != nil {
+		return x.AverageAmount
+	}
+	return 0
+}
+
+func (x *CategoryMetrics) GetTrend() string {
+	if x != nil {
+		return x.Trend
+	}
+	return ""
+}
+
+func (x *CategoryMetrics) GetSubcategories() []*CategoryMetrics {
+	if x != nil {
+		return x.Subcategories
+	}
+	return nil
+}
+
+type MonthlyTrend struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Month       string  `protobuf:"bytes,1,opt,name=month,proto3" json:"month,omitempty"`
+	Year        int32   `protobuf:"varint,2,opt,name=year,proto3" json:"year,omitempty"`
+	Income      float64 `protobuf:"fixed64,3,opt,name=income,proto3" json:"income,omitempty"`
+	Expenses    float64 `protobuf:"fixed64,4,opt,name=expenses,proto3" json:"expenses,omitempty"`
+	NetIncome   float64 `protobuf:"fixed64,5,opt,name=net_income,json=netIncome,proto3" json:"net_income,omitempty"`
+	SavingsRate float64 `protobuf:"fixed64,6,opt,name=savings_rate,json=savingsRate,proto3" json:"savings_rate,omitempty"`
+}
+
+func (x *MonthlyTrend) Reset() {
+	*x = MonthlyTrend{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_financeadvisor_v1_report_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MonthlyTrend) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MonthlyTrend) ProtoMessage() {}
+
+func (x *MonthlyTrend) ProtoReflect() protoreflect.Message {
+	mi := &file_financeadvisor_v1_report_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MonthlyTrend.ProtoReflect.Descriptor instead.
+func (*MonthlyTrend) Descriptor() ([]byte, []int) {
+	return file_financeadvisor_v1_report_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *MonthlyTrend) GetMonth() string {
+	if x != nil {
+		return x.Month
+	}
+	return ""
+}
+
+func (x *MonthlyTrend) GetYear() int32 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+func (x *MonthlyTrend) GetIncome() float64 {
+	if x != nil {
+		return x.Income
+	}
+	return 0
+}
+
+func (x *MonthlyTrend) GetExpenses() float64 {
+	if x != nil {
+		return x.Expenses
+	}
+	return 0
+}
+
+func (x *MonthlyTrend) GetNetIncome() float64 {
+	if x != nil {
+		return x.NetIncome
+	}
+	return 0
+}
+
+func (x *MonthlyTrend) GetSavingsRate() float64 {
+	if x != nil {
+		return x.SavingsRate
+	}
+	return 0
+}
+
+type BudgetPerformance struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TotalBudgeted         float64 `protobuf:"fixed64,1,opt,name=total_budgeted,json=totalBudgeted,proto3" json:"total_budgeted,omitempty"`
+	TotalSpent            float64 `protobuf:"fixed64,2,opt,name=total_spent,json=totalSpent,proto3" json:"total_spent,omitempty"`
+	Variance              float64 `protobuf:"fixed64,3,opt,name=variance,proto3" json:"variance,omitempty"`
+	VariancePercentage    float64 `protobuf:"fixed64,4,opt,name=variance_percentage,json=variancePercentage,proto3" json:"variance_percentage,omitempty"`
+	CategoriesOverBudget  int32   `protobuf:"varint,5,opt,name=categories_over_budget,json=categoriesOverBudget,proto3" json:"categories_over_budget,omitempty"`
+	CategoriesUnderBudget int32   `protobuf:"varint,6,opt,name=categories_under_budget,json=categoriesUnderBudget,proto3" json:"categories_under_budget,omitempty"`
+}
+
+func (x *BudgetPerformance) Reset() {
+	*x = BudgetPerformance{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_financeadvisor_v1_report_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BudgetPerformance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BudgetPerformance) ProtoMessage() {}
+
+func (x *BudgetPerformance) ProtoReflect() protoreflect.Message {
+	mi := &file_financeadvisor_v1_report_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BudgetPerformance.ProtoReflect.Descriptor instead.
+func (*BudgetPerformance) Descriptor() ([]byte, []int) {
+	return file_financeadvisor_v1_report_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *BudgetPerformance) GetTotalBudgeted() float64 {
+	if x != nil {
+		return x.TotalBudgeted
+	}
+	return 0
+}
+
+func (x *BudgetPerformance) GetTotalSpent() float64 {
+	if x != nil {
+		return x.TotalSpent
+	}
+	return 0
+}
+
+func (x *BudgetPerformance) GetVariance() float64 {
+	if x != nil {
+		return x.Variance
+	}
+	return 0
+}
+
+func (x *BudgetPerformance) GetVariancePercentage() float64 {
+	if x != nil {
+		return x.VariancePercentage
+	}
+	return 0
+}
+
+func (x *BudgetPerformance) GetCategoriesOverBudget() int32 {
+	if x != nil {
+		return x.CategoriesOverBudget
+	}
+	return 0
+}
+
+func (x *BudgetPerformance) GetCategoriesUnderBudget() int32 {
+	if x != nil {
+		return x.CategoriesUnderBudget
+	}
+	return 0
+}
+
+var File_financeadvisor_v1_report_proto protoreflect.FileDescriptor
+
+var file_financeadvisor_v1_report_proto_rawDesc = []byte{
+	0x0a, 0x1e, 0x66, 0x69, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x61, 0x64, 0x76, 0x69, 0x73, 0x6f, 0x72,
+	0x2f, 0x76, 0x31, 0x2f, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x11, 0x66, 0x69, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x61, 0x64, 0x76, 0x69, 0x73, 0x6f, 0x72,
+	0x2e, 0x76, 0x31, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x22, 0x48, 0x0a, 0x1c, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65,
+	0x4d, 0x6f, 0x6e, 0x74, 0x68, 0x6c, 0x79, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x79, 0x65, 0x61, 0x72, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x04, 0x79, 0x65, 0x61, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x6f, 0x6e, 0x74,
+	0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6d, 0x6f, 0x6e, 0x74, 0x68, 0x22, 0x4e,
+	0x0a, 0x1e, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x51, 0x75, 0x61, 0x72, 0x74, 0x65,
+	0x72, 0x6c, 0x79, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x12, 0x0a, 0x04, 0x79, 0x65, 0x61, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04,
+	0x79, 0x65, 0x61, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x71, 0x75, 0x61, 0x72, 0x74, 0x65, 0x72, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x71, 0x75, 0x61, 0x72, 0x74, 0x65, 0x72, 0x22, 0x31,
+	0x0a, 0x1b, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x59, 0x65, 0x61, 0x72, 0x6c, 0x79,
+	0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a,
+	0x04, 0x79, 0x65, 0x61, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x79, 0x65, 0x61,
+	0x72, 0x22, 0x8f, 0x01, 0x0a, 0x1b, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x43, 0x75,
+	0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x39, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x44, 0x61, 0x74, 0x65, 0x12, 0x35, 0x0a, 0x08,
+	0x65, 0x6e, 0x64, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x65, 0x6e, 0x64, 0x44,
+	0x61, 0x74, 0x65, 0x22, 0x9d, 0x07, 0x0a, 0x0f, 0x46, 0x69, 0x6e, 0x61, 0x6e, 0x63, 0x69, 0x61,
+	0x6c, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64,
+	0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x54, 0x79, 0x70,
+	0x65, 0x12, 0x39, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x44, 0x61, 0x74, 0x65, 0x12, 0x35, 0x0a, 0x08,
+	0x65, 0x6e, 0x64, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x65, 0x6e, 0x64, 0x44,
+	0x61, 0x74, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x69, 0x6e, 0x63,
+	0x6f, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x49, 0x6e, 0x63, 0x6f, 0x6d, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f,
+	0x65, 0x78, 0x70, 0x65, 0x6e, 0x73, 0x65, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0d,
+	0x74, 0x6f, 0x74, 0x61, 0x6c, 0x45, 0x78, 0x70, 0x65, 0x6e, 0x73, 0x65, 0x73, 0x12, 0x1d, 0x0a,
+	0x0a, 0x6e, 0x65, 0x74, 0x5f, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x09, 0x6e, 0x65, 0x74, 0x49, 0x6e, 0x63, 0x6f, 0x6d, 0x65, 0x12, 0x21, 0x0a, 0x0c,
+	0x73, 0x61, 0x76, 0x69, 0x6e, 0x67, 0x73, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x0b, 0x73, 0x61, 0x76, 0x69, 0x6e, 0x67, 0x73, 0x52, 0x61, 0x74, 0x65, 0x12,
+	0x2b, 0x0a, 0x11, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x52, 0x10, 0x74, 0x72, 0x61, 0x6e,
+	0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x51, 0x0a, 0x12,
+	0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x5f, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x64, 0x6f,
+	0x77, 0x6e, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x66, 0x69, 0x6e, 0x61, 0x6e,
+	0x63, 0x65, 0x61, 0x64, 0x76, 0x69, 0x73, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61, 0x74,
+	0x65, 0x67, 0x6f, 0x72, 0x79, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x52, 0x11, 0x63, 0x61,
+	0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x42, 0x72, 0x65, 0x61, 0x6b, 0x64, 0x6f, 0x77, 0x6e, 0x12,
+	0x46, 0x0a, 0x0e, 0x6d, 0x6f, 0x6e, 0x74, 0x68, 0x6c, 0x79, 0x5f, 0x74, 0x72, 0x65, 0x6e, 0x64,
+	0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x66, 0x69, 0x6e, 0x61, 0x6e, 0x63,
+	0x65, 0x61, 0x64, 0x76, 0x69, 0x73, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x6f, 0x6e, 0x74,
+	0x68, 0x6c, 0x79, 0x54, 0x72, 0x65, 0x6e, 0x64, 0x52, 0x0d, 0x6d, 0x6f, 0x6e, 0x74, 0x68, 0x6c,
+	0x79, 0x54, 0x72, 0x65, 0x6e, 0x64, 0x73, 0x12, 0x53, 0x0a, 0x12, 0x62, 0x75, 0x64, 0x67, 0x65,
+	0x74, 0x5f, 0x70, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x0c, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x66, 0x69, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x61, 0x64, 0x76,
+	0x69, 0x73, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x75, 0x64, 0x67, 0x65, 0x74, 0x50, 0x65,
+	0x72, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x11, 0x62, 0x75, 0x64, 0x67, 0x65,
+	0x74, 0x50, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x56, 0x0a, 0x15,
+	0x74, 0x6f, 0x70, 0x5f, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x65, 0x5f, 0x63, 0x61, 0x74, 0x65, 0x67,
+	0x6f, 0x72, 0x69, 0x65, 0x73, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x66, 0x69,
+	0x6e, 0x61, 0x6e, 0x63, 0x65, 0x61, 0x64, 0x76, 0x69, 0x73, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x52,
+	0x13, 0x74, 0x6f, 0x70, 0x49, 0x6e, 0x63, 0x6f, 0x6d, 0x65, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f,
+	0x72, 0x69, 0x65, 0x73, 0x12, 0x58, 0x0a, 0x16, 0x74, 0x6f, 0x70, 0x5f, 0x65, 0x78, 0x70, 0x65,
+	0x6e, 0x73, 0x65, 0x5f, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x18, 0x0e,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x66, 0x69, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x61, 0x64,
+	0x76, 0x69, 0x73, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72,
+	0x79, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x52, 0x14, 0x74, 0x6f, 0x70, 0x45, 0x78, 0x70,
+	0x65, 0x6e, 0x73, 0x65, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x12, 0x1a,
+	0x0a, 0x08, 0x69, 0x6e, 0x73, 0x69, 0x67, 0x68, 0x74, 0x73, 0x18, 0x0f, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x08, 0x69, 0x6e, 0x73, 0x69, 0x67, 0x68, 0x74, 0x73, 0x12, 0x28, 0x0a, 0x0f, 0x72, 0x65,
+	0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x10, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x0f, 0x72, 0x65, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x64, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x12, 0x3d, 0x0a, 0x0c, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65,
+	0x64, 0x5f, 0x61, 0x74, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65,
+	0x64, 0x41, 0x74, 0x22, 0xde, 0x02, 0x0a, 0x0f, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79,
+	0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x63, 0x61, 0x74, 0x65, 0x67,
+	0x6f, 0x72, 0x79, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x63, 0x61,
+	0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x49, 0x64, 0x12, 0x23, 0x0a, 0x0d, 0x63, 0x61, 0x74, 0x65,
+	0x67, 0x6f, 0x72, 0x79, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0c, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x21, 0x0a,
+	0x0c, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
+	0x12, 0x2b, 0x0a, 0x11, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x10, 0x74, 0x72, 0x61,
+	0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x2e, 0x0a,
+	0x13, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x67, 0x65, 0x5f, 0x6f, 0x66, 0x5f, 0x74,
+	0x6f, 0x74, 0x61, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x11, 0x70, 0x65, 0x72, 0x63,
+	0x65, 0x6e, 0x74, 0x61, 0x67, 0x65, 0x4f, 0x66, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x25, 0x0a,
+	0x0e, 0x61, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0d, 0x61, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x41, 0x6d,
+	0x6f, 0x75, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x72, 0x65, 0x6e, 0x64, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x72, 0x65, 0x6e, 0x64, 0x12, 0x48, 0x0a, 0x0d, 0x73, 0x75,
+	0x62, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x22, 0x2e, 0x66, 0x69, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x61, 0x64, 0x76, 0x69, 0x73,
+	0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x4d, 0x65,
+	0x74, 0x72, 0x69, 0x63, 0x73, 0x52, 0x0d, 0x73, 0x75, 0x62, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f,
+	0x72, 0x69, 0x65, 0x73, 0x22, 0xae, 0x01, 0x0a, 0x0c, 0x4d, 0x6f, 0x6e, 0x74, 0x68, 0x6c, 0x79,
+	0x54, 0x72, 0x65, 0x6e, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x6f, 0x6e, 0x74, 0x68, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6d, 0x6f, 0x6e, 0x74, 0x68, 0x12, 0x12, 0x0a, 0x04, 0x79,
+	0x65, 0x61, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x79, 0x65, 0x61, 0x72, 0x12,
+	0x16, 0x0a, 0x06, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x06, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x78, 0x70, 0x65, 0x6e,
+	0x73, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x65, 0x78, 0x70, 0x65, 0x6e,
+	0x73, 0x65, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x6e, 0x65, 0x74, 0x5f, 0x69, 0x6e, 0x63, 0x6f, 0x6d,
+	0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x6e, 0x65, 0x74, 0x49, 0x6e, 0x63, 0x6f,
+	0x6d, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x73, 0x61, 0x76, 0x69, 0x6e, 0x67, 0x73, 0x5f, 0x72, 0x61,
+	0x74, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x73, 0x61, 0x76, 0x69, 0x6e, 0x67,
+	0x73, 0x52, 0x61, 0x74, 0x65, 0x22, 0x96, 0x02, 0x0a, 0x11, 0x42, 0x75, 0x64, 0x67, 0x65, 0x74,
+	0x50, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x74,
+	0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x62, 0x75, 0x64, 0x67, 0x65, 0x74, 0x65, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x0d, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x75, 0x64, 0x67, 0x65, 0x74,
+	0x65, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x73, 0x70, 0x65, 0x6e,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x53, 0x70,
+	0x65, 0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x63, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x63, 0x65, 0x12,
+	0x2f, 0x0a, 0x13, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x70, 0x65, 0x72, 0x63,
+	0x65, 0x6e, 0x74, 0x61, 0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x12, 0x76, 0x61,
+	0x72, 0x69, 0x61, 0x6e, 0x63, 0x65, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x67, 0x65,
+	0x12, 0x34, 0x0a, 0x16, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x5f, 0x6f,
+	0x76, 0x65, 0x72, 0x5f, 0x62, 0x75, 0x64, 0x67, 0x65, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x14, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x4f, 0x76, 0x65, 0x72,
+	0x42, 0x75, 0x64, 0x67, 0x65, 0x74, 0x12, 0x36, 0x0a, 0x17, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f,
+	0x72, 0x69, 0x65, 0x73, 0x5f, 0x75, 0x6e, 0x64, 0x65, 0x72, 0x5f, 0x62, 0x75, 0x64, 0x67, 0x65,
+	0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x15, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72,
+	0x69, 0x65, 0x73, 0x55, 0x6e, 0x64, 0x65, 0x72, 0x42, 0x75, 0x64, 0x67, 0x65, 0x74, 0x32, 0xc7,
+	0x03, 0x0a, 0x0d, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x12, 0x6c, 0x0a, 0x15, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x4d, 0x6f, 0x6e, 0x74,
+	0x68, 0x6c, 0x79, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x2f, 0x2e, 0x66, 0x69, 0x6e, 0x61,
+	0x6e, 0x63, 0x65, 0x61, 0x64, 0x76, 0x69, 0x73, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65,
+	0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x4d, 0x6f, 0x6e, 0x74, 0x68, 0x6c, 0x79, 0x52, 0x65, 0x70,
+	0x6f, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x66, 0x69, 0x6e,
+	0x61, 0x6e, 0x63, 0x65, 0x61, 0x64, 0x76, 0x69, 0x73, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x46,
+	0x69, 0x6e, 0x61, 0x6e, 0x63, 0x69, 0x61, 0x6c, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x70,
+	0x0a, 0x17, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x51, 0x75, 0x61, 0x72, 0x74, 0x65,
+	0x72, 0x6c, 0x79, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x31, 0x2e, 0x66, 0x69, 0x6e, 0x61,
+	0x6e, 0x63, 0x65, 0x61, 0x64, 0x76, 0x69, 0x73, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65,
+	0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x51, 0x75, 0x61, 0x72, 0x74, 0x65, 0x72, 0x6c, 0x79, 0x52,
+	0x65, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x66,
+	0x69, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x61, 0x64, 0x76, 0x69, 0x73, 0x6f, 0x72, 0x2e, 0x76, 0x31,
+	0x2e, 0x46, 0x69, 0x6e, 0x61, 0x6e, 0x63, 0x69, 0x61, 0x6c, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74,
+	0x12, 0x6a, 0x0a, 0x14, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x59, 0x65, 0x61, 0x72,
+	0x6c, 0x79, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x2e, 0x2e, 0x66, 0x69, 0x6e, 0x61, 0x6e,
+	0x63, 0x65, 0x61, 0x64, 0x76, 0x69, 0x73, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x6e,
+	0x65, 0x72, 0x61, 0x74, 0x65, 0x59, 0x65, 0x61, 0x72, 0x6c, 0x79, 0x52, 0x65, 0x70, 0x6f, 0x72,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x66, 0x69, 0x6e, 0x61, 0x6e,
+	0x63, 0x65, 0x61, 0x64, 0x76, 0x69, 0x73, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6e,
+	0x61, 0x6e, 0x63, 0x69, 0x61, 0x6c, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x6a, 0x0a, 0x14,
+	0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65,
+	0x70, 0x6f, 0x72, 0x74, 0x12, 0x2e, 0x2e, 0x66, 0x69, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x61, 0x64,
+	0x76, 0x69, 0x73, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74,
+	0x65, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x66, 0x69, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x61, 0x64,
+	0x76, 0x69, 0x73, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6e, 0x61, 0x6e, 0x63, 0x69,
+	0x61, 0x6c, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x42, 0x57, 0x5a, 0x55, 0x67, 0x6f, 0x2d, 0x66,
+	0x69, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x2d, 0x61, 0x64, 0x76, 0x69, 0x73, 0x6f, 0x72, 0x2f, 0x69,
+	0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x73, 0x74, 0x72,
+	0x75, 0x63, 0x74, 0x75, 0x72, 0x65, 0x2f, 0x72, 0x70, 0x63, 0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x66,
+	0x69, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x61, 0x64, 0x76, 0x69, 0x73, 0x6f, 0x72, 0x2f, 0x76, 0x31,
+	0x3b, 0x66, 0x69, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x61, 0x64, 0x76, 0x69, 0x73, 0x6f, 0x72, 0x76,
+	0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_financeadvisor_v1_report_proto_rawDescOnce sync.Once
+	file_financeadvisor_v1_report_proto_rawDescData = file_financeadvisor_v1_report_proto_rawDesc
+)
+
+func file_financeadvisor_v1_report_proto_rawDescGZIP() []byte {
+	file_financeadvisor_v1_report_proto_rawDescOnce.Do(func() {
+		file_financeadvisor_v1_report_proto_rawDescData = protoimpl.X.CompressGZIP(file_financeadvisor_v1_report_proto_rawDescData)
+	})
+	return file_financeadvisor_v1_report_proto_rawDescData
+}
+
+var file_financeadvisor_v1_report_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_financeadvisor_v1_report_proto_goTypes = []any{
+	(*GenerateMonthlyReportRequest)(nil),   // 0: financeadvisor.v1.GenerateMonthlyReportRequest
+	(*GenerateQuarterlyReportRequest)(nil), // 1: financeadvisor.v1.GenerateQuarterlyReportRequest
+	(*GenerateYearlyReportRequest)(nil),    // 2: financeadvisor.v1.GenerateYearlyReportRequest
+	(*GenerateCustomReportRequest)(nil),    // 3: financeadvisor.v1.GenerateCustomReportRequest
+	(*FinancialReport)(nil),                // 4: financeadvisor.v1.FinancialReport
+	(*CategoryMetrics)(nil),                // 5: financeadvisor.v1.CategoryMetrics
+	(*MonthlyTrend)(nil),                   // 6: financeadvisor.v1.MonthlyTrend
+	(*BudgetPerformance)(nil),              // 7: financeadvisor.v1.BudgetPerformance
+	(*timestamppb.Timestamp)(nil),          // 8: google.protobuf.Timestamp
+}
+var file_financeadvisor_v1_report_proto_depIdxs = []int32{
+	8,  // 0: financeadvisor.v1.GenerateCustomReportRequest.start_date:type_name -> google.protobuf.Timestamp
+	8,  // 1: financeadvisor.v1.GenerateCustomReportRequest.end_date:type_name -> google.protobuf.Timestamp
+	8,  // 2: financeadvisor.v1.FinancialReport.start_date:type_name -> google.protobuf.Timestamp
+	8,  // 3: financeadvisor.v1.FinancialReport.end_date:type_name -> google.protobuf.Timestamp
+	5,  // 4: financeadvisor.v1.FinancialReport.category_breakdown:type_name -> financeadvisor.v1.CategoryMetrics
+	6,  // 5: financeadvisor.v1.FinancialReport.monthly_trends:type_name -> financeadvisor.v1.MonthlyTrend
+	7,  // 6: financeadvisor.v1.FinancialReport.budget_performance:type_name -> financeadvisor.v1.BudgetPerformance
+	5,  // 7: financeadvisor.v1.FinancialReport.top_income_categories:type_name -> financeadvisor.v1.CategoryMetrics
+	5,  // 8: financeadvisor.v1.FinancialReport.top_expense_categories:type_name -> financeadvisor.v1.CategoryMetrics
+	8,  // 9: financeadvisor.v1.FinancialReport.generated_at:type_name -> google.protobuf.Timestamp
+	5,  // 10: financeadvisor.v1.CategoryMetrics.subcategories:type_name -> financeadvisor.v1.CategoryMetrics
+	0,  // 11: financeadvisor.v1.ReportService.GenerateMonthlyReport:input_type -> financeadvisor.v1.GenerateMonthlyReportRequest
+	1,  // 12: financeadvisor.v1.ReportService.GenerateQuarterlyReport:input_type -> financeadvisor.v1.GenerateQuarterlyReportRequest
+	2,  // 13: financeadvisor.v1.ReportService.GenerateYearlyReport:input_type -> financeadvisor.v1.GenerateYearlyReportRequest
+	3,  // 14: financeadvisor.v1.ReportService.GenerateCustomReport:input_type -> financeadvisor.v1.GenerateCustomReportRequest
+	4,  // 15: financeadvisor.v1.ReportService.GenerateMonthlyReport:output_type -> financeadvisor.v1.FinancialReport
+	4,  // 16: financeadvisor.v1.ReportService.GenerateQuarterlyReport:output_type -> financeadvisor.v1.FinancialReport
+	4,  // 17: financeadvisor.v1.ReportService.GenerateYearlyReport:output_type -> financeadvisor.v1.FinancialReport
+	4,  // 18: financeadvisor.v1.ReportService.GenerateCustomReport:output_type -> financeadvisor.v1.FinancialReport
+	15, // [15:19] is the sub-list for method output_type
+	11, // [11:15] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_financeadvisor_v1_report_proto_init() }
+func file_financeadvisor_v1_report_proto_init() {
+	if File_financeadvisor_v1_report_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_financeadvisor_v1_report_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*GenerateMonthlyReportRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_financeadvisor_v1_report_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*GenerateQuarterlyReportRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_financeadvisor_v1_report_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*GenerateYearlyReportRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_financeadvisor_v1_report_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*GenerateCustomReportRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_financeadvisor_v1_report_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*FinancialReport); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_financeadvisor_v1_report_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*CategoryMetrics); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_financeadvisor_v1_report_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*MonthlyTrend); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_financeadvisor_v1_report_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*BudgetPerformance); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_financeadvisor_v1_report_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_financeadvisor_v1_report_proto_goTypes,
+		DependencyIndexes: file_financeadvisor_v1_report_proto_depIdxs,
+		MessageInfos:      file_financeadvisor_v1_report_proto_msgTypes,
+	}.Build()
+	File_financeadvisor_v1_report_proto = out.File
+	file_financeadvisor_v1_report_proto_rawDesc = nil
+	file_financeadvisor_v1_report_proto_goTypes = nil
+	file_financeadvisor_v1_report_proto_depIdxs = nil
+}
@@ -0,0 +1,228 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             v5.27.1
+// source: financeadvisor/v1/report.proto
+
+package financeadvisorv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	ReportService_GenerateMonthlyReport_FullMethodName   = "/financeadvisor.v1.ReportService/GenerateMonthlyReport"
+	ReportService_GenerateQuarterlyReport_FullMethodName = "/financeadvisor.v1.ReportService/GenerateQuarterlyReport"
+	ReportService_GenerateYearlyReport_FullMethodName    = "/financeadvisor.v1.ReportService/GenerateYearlyReport"
+	ReportService_GenerateCustomReport_FullMethodName    = "/financeadvisor.v1.ReportService/GenerateCustomReport"
+)
+
+// ReportServiceClient is the client API for ReportService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ReportService generates financial reports for the authenticated user.
+type ReportServiceClient interface {
+	GenerateMonthlyReport(ctx context.Context, in *GenerateMonthlyReportRequest, opts ...grpc.CallOption) (*FinancialReport, error)
+	GenerateQuarterlyReport(ctx context.Context, in *GenerateQuarterlyReportRequest, opts ...grpc.CallOption) (*FinancialReport, error)
+	GenerateYearlyReport(ctx context.Context, in *GenerateYearlyReportRequest, opts ...grpc.CallOption) (*FinancialReport, error)
+	GenerateCustomReport(ctx context.Context, in *GenerateCustomReportRequest, opts ...grpc.CallOption) (*FinancialReport, error)
+}
+
+type reportServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewReportServiceClient(cc grpc.ClientConnInterface) ReportServiceClient {
+	return &reportServiceClient{cc}
+}
+
+func (c *reportServiceClient) GenerateMonthlyReport(ctx context.Context, in *GenerateMonthlyReportRequest, opts ...grpc.CallOption) (*FinancialReport, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FinancialReport)
+	err := c.cc.Invoke(ctx, ReportService_GenerateMonthlyReport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reportServiceClient) GenerateQuarterlyReport(ctx context.Context, in *GenerateQuarterlyReportRequest, opts ...grpc.CallOption) (*FinancialReport, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FinancialReport)
+	err := c.cc.Invoke(ctx, ReportService_GenerateQuarterlyReport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reportServiceClient) GenerateYearlyReport(ctx context.Context, in *GenerateYearlyReportRequest, opts ...grpc.CallOption) (*FinancialReport, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FinancialReport)
+	err := c.cc.Invoke(ctx, ReportService_GenerateYearlyReport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reportServiceClient) GenerateCustomReport(ctx context.Context, in *GenerateCustomReportRequest, opts ...grpc.CallOption) (*FinancialReport, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FinancialReport)
+	err := c.cc.Invoke(ctx, ReportService_GenerateCustomReport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ReportServiceServer is the server API for ReportService service.
+// All implementations must embed UnimplementedReportServiceServer
+// for forward compatibility
+//
+// ReportService generates financial reports for the authenticated user.
+type ReportServiceServer interface {
+	GenerateMonthlyReport(context.Context, *GenerateMonthlyReportRequest) (*FinancialReport, error)
+	GenerateQuarterlyReport(context.Context, *GenerateQuarterlyReportRequest) (*FinancialReport, error)
+	GenerateYearlyReport(context.Context, *GenerateYearlyReportRequest) (*FinancialReport, error)
+	GenerateCustomReport(context.Context, *GenerateCustomReportRequest) (*FinancialReport, error)
+	mustEmbedUnimplementedReportServiceServer()
+}
+
+// UnimplementedReportServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedReportServiceServer struct {
+}
+
+func (UnimplementedReportServiceServer) GenerateMonthlyReport(context.Context, *GenerateMonthlyReportRequest) (*FinancialReport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GenerateMonthlyReport not implemented")
+}
+func (UnimplementedReportServiceServer) GenerateQuarterlyReport(context.Context, *GenerateQuarterlyReportRequest) (*FinancialReport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GenerateQuarterlyReport not implemented")
+}
+func (UnimplementedReportServiceServer) GenerateYearlyReport(context.Context, *GenerateYearlyReportRequest) (*FinancialReport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GenerateYearlyReport not implemented")
+}
+func (UnimplementedReportServiceServer) GenerateCustomReport(context.Context, *GenerateCustomReportRequest) (*FinancialReport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GenerateCustomReport not implemented")
+}
+func (UnimplementedReportServiceServer) mustEmbedUnimplementedReportServiceServer() {}
+
+// UnsafeReportServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ReportServiceServer will
+// result in compilation errors.
+type UnsafeReportServiceServer interface {
+	mustEmbedUnimplementedReportServiceServer()
+}
+
+func RegisterReportServiceServer(s grpc.ServiceRegistrar, srv ReportServiceServer) {
+	s.RegisterService(&ReportService_ServiceDesc, srv)
+}
+
+func _ReportService_GenerateMonthlyReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GenerateMonthlyReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReportServiceServer).GenerateMonthlyReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReportService_GenerateMonthlyReport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReportServiceServer).GenerateMonthlyReport(ctx, req.(*GenerateMonthlyReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReportService_GenerateQuarterlyReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GenerateQuarterlyReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReportServiceServer).GenerateQuarterlyReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReportService_GenerateQuarterlyReport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReportServiceServer).GenerateQuarterlyReport(ctx, req.(*GenerateQuarterlyReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReportService_GenerateYearlyReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GenerateYearlyReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReportServiceServer).GenerateYearlyReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReportService_GenerateYearlyReport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReportServiceServer).GenerateYearlyReport(ctx, req.(*GenerateYearlyReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReportService_GenerateCustomReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GenerateCustomReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReportServiceServer).GenerateCustomReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReportService_GenerateCustomReport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReportServiceServer).GenerateCustomReport(ctx, req.(*GenerateCustomReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ReportService_ServiceDesc is the grpc.ServiceDesc for ReportService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ReportService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "financeadvisor.v1.ReportService",
+	HandlerType: (*ReportServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GenerateMonthlyReport",
+			Handler:    _ReportService_GenerateMonthlyReport_Handler,
+		},
+		{
+			MethodName: "GenerateQuarterlyReport",
+			Handler:    _ReportService_GenerateQuarterlyReport_Handler,
+		},
+		{
+			MethodName: "GenerateYearlyReport",
+			Handler:    _ReportService_GenerateYearlyReport_Handler,
+		},
+		{
+			MethodName: "GenerateCustomReport",
+			Handler:    _ReportService_GenerateCustomReport_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "financeadvisor/v1/report.proto",
+}
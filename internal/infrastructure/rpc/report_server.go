@@ -0,0 +1,62 @@
+package rpc
+
+import (
+	"context"
+
+	"go-finance-advisor/internal/application"
+	"go-finance-advisor/internal/domain"
+	pb "go-finance-advisor/internal/infrastructure/rpc/gen/financeadvisor/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type reportServer struct {
+	pb.UnimplementedReportServiceServer
+	service *application.ReportsService
+}
+
+// report converts a generated report or the error from generating it
+func report(r *domain.FinancialReport, err error) (*pb.FinancialReport, error) {
+	if err != nil {
+		return nil, statusError(err, "failed to generate report")
+	}
+	return toReport(r), nil
+}
+
+func (s *reportServer) GenerateMonthlyReport(
+	ctx context.Context, req *pb.GenerateMonthlyReportRequest,
+) (*pb.FinancialReport, error) {
+	if req.Month < 1 || req.Month > 12 {
+		return nil, status.Error(codes.InvalidArgument, "month must be between 1 and 12")
+	}
+	return report(s.service.GenerateMonthlyReport(ctx, userID(ctx), int(req.Year), int(req.Month)))
+}
+
+func (s *reportServer) GenerateQuarterlyReport(
+	ctx context.Context, req *pb.GenerateQuarterlyReportRequest,
+) (*pb.FinancialReport, error) {
+	if req.Quarter < 1 || req.Quarter > 4 {
+		return nil, status.Error(codes.InvalidArgument, "quarter must be between 1 and 4")
+	}
+	return report(s.service.GenerateQuarterlyReport(ctx, userID(ctx), int(req.Year), int(req.Quarter)))
+}
+
+func (s *reportServer) GenerateYearlyReport(
+	ctx context.Context, req *pb.GenerateYearlyReportRequest,
+) (*pb.FinancialReport, error) {
+	return report(s.service.GenerateYearlyReport(ctx, userID(ctx), int(req.Year)))
+}
+
+func (s *reportServer) GenerateCustomReport(
+	ctx context.Context, req *pb.GenerateCustomReportRequest,
+) (*pb.FinancialReport, error) {
+	if req.StartDate == nil || req.EndDate == nil {
+		return nil, status.Error(codes.InvalidArgument, "start_date and end_date are required")
+	}
+	start, end := req.StartDate.AsTime(), req.EndDate.AsTime()
+	if end.Before(start) {
+		return nil, status.Error(codes.InvalidArgument, "end_date must not be before start_date")
+	}
+	return report(s.service.GenerateCustomReport(ctx, userID(ctx), start, end))
+}
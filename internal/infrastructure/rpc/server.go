@@ -0,0 +1,143 @@
+// Package rpc serves the application layer over gRPC so internal services
+// and CLIs can integrate without going through JSON/HTTP. The messages and
+// service definitions live in api/proto; the generated code is in gen.
+package rpc
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"strings"
+
+	"go-finance-advisor/internal/application"
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+	pb "go-finance-advisor/internal/infrastructure/rpc/gen/financeadvisor/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Services are the application services exposed over gRPC
+type Services struct {
+	Transactions *application.TransactionService
+	Budgets      *application.BudgetService
+	Reports      *application.ReportsService
+	Advisor      *application.AdvisorService
+	Users        *application.UserService
+	// Quotas enforce the users' plans on the advisor. Without it the plans
+	// are not checked.
+	Quotas *application.QuotaService
+	// DemoUserID is the demo user when demo mode is on. Like over HTTP, it
+	// can read the sample data but not change it.
+	DemoUserID uint
+}
+
+// NewServer registers the transaction, budget, report and advisor services
+// on a gRPC server. Every call must carry the same bearer token as the HTTP
+// API in its "authorization" metadata and acts on that token's user.
+func NewServer(services Services, opts ...grpc.ServerOption) *grpc.Server {
+	interceptors := []grpc.UnaryServerInterceptor{authenticate}
+	if services.DemoUserID != 0 {
+		interceptors = append(interceptors, demoReadOnly(services.DemoUserID))
+	}
+	opts = append([]grpc.ServerOption{grpc.ChainUnaryInterceptor(interceptors...)}, opts...)
+	server := grpc.NewServer(opts...)
+
+	pb.RegisterTransactionServiceServer(server, &transactionServer{service: services.Transactions})
+	pb.RegisterBudgetServiceServer(server, &budgetServer{service: services.Budgets})
+	pb.RegisterReportServiceServer(server, &reportServer{service: services.Reports})
+	pb.RegisterAdvisorServiceServer(server, &advisorServer{
+		advisor: services.Advisor, users: services.Users, quotas: services.Quotas,
+	})
+	return server
+}
+
+// authenticate validates the bearer token and stores its user as the actor
+// of the call, like middleware.AuthMiddleware does for HTTP requests
+func authenticate(
+	ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+) (interface{}, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "authorization metadata required")
+	}
+	tokenString := strings.TrimPrefix(values[0], "Bearer ")
+	if tokenString == values[0] {
+		return nil, status.Error(codes.Unauthenticated, "bearer token required")
+	}
+
+	claims, err := middleware.ParseToken(tokenString)
+	if errors.Is(err, middleware.ErrJWTNotConfigured) {
+		return nil, status.Error(codes.Internal, "authentication is not configured")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	actor := domain.Actor{UserID: claims.UserID, SessionID: claims.SessionID}
+	if p, ok := peer.FromContext(ctx); ok {
+		actor.IPAddress = p.Addr.String()
+		if host, _, splitErr := net.SplitHostPort(actor.IPAddress); splitErr == nil {
+			actor.IPAddress = host
+		}
+	}
+	device := domain.Device{IPAddress: actor.IPAddress}
+	if agents := md.Get("user-agent"); len(agents) > 0 {
+		device.UserAgent = agents[0]
+	}
+	err = middleware.ValidateSession(ctx, claims, device)
+	if errors.Is(err, domain.ErrInvalidSession) {
+		return nil, status.Error(codes.Unauthenticated, "session expired or revoked")
+	}
+	if err != nil {
+		return nil, statusError(err, "failed to check session")
+	}
+	return handler(domain.ContextWithActor(ctx, actor), req)
+}
+
+// demoWrites are the methods that change data
+var demoWrites = map[string]bool{
+	pb.TransactionService_CreateTransaction_FullMethodName: true,
+	pb.TransactionService_UpdateTransaction_FullMethodName: true,
+	pb.TransactionService_DeleteTransaction_FullMethodName: true,
+	pb.BudgetService_CreateBudget_FullMethodName:           true,
+	pb.BudgetService_DeleteBudget_FullMethodName:           true,
+}
+
+// demoReadOnly rejects the demo user's calls that change data, as
+// middleware.DemoMiddleware does for HTTP requests, so every visitor of the
+// demo sees the same sample data. It must run after authenticate.
+func demoReadOnly(demoUserID uint) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if demoWrites[info.FullMethod] && userID(ctx) == demoUserID {
+			return nil, status.Error(codes.PermissionDenied, "the demo is read-only")
+		}
+		return handler(ctx, req)
+	}
+}
+
+// userID returns the authenticated user of the call
+func userID(ctx context.Context) uint {
+	actor, _ := domain.ActorFromContext(ctx)
+	return actor.UserID
+}
+
+// statusError turns a service error into a gRPC status. Unexpected errors
+// are logged and reported with the given message only.
+func statusError(err error, message string) error {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrInvalidCursor), errors.Is(err, domain.ErrValidation):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		log.Printf("grpc: %s: %v", message, err)
+		return status.Error(codes.Internal, message)
+	}
+}
@@ -0,0 +1,275 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/application"
+	"go-finance-advisor/internal/config"
+	"go-finance-advisor/internal/domain"
+	"go-finance-advisor/internal/infrastructure/middleware"
+	pb "go-finance-advisor/internal/infrastructure/rpc/gen/financeadvisor/v1"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+func TestMain(m *testing.M) {
+	authConfig := config.Default().Auth
+	authConfig.JWTSecret = "test-secret"
+	if err := middleware.ConfigureJWT(authConfig); err != nil {
+		panic(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+type testClients struct {
+	db           *gorm.DB
+	transactions pb.TransactionServiceClient
+	budgets      pb.BudgetServiceClient
+	reports      pb.ReportServiceClient
+	advisor      pb.AdvisorServiceClient
+}
+
+// setupServer serves the application services over an in-memory connection.
+// Users 1 (aggressive) and 2 exist, as does the default category 1. The
+// services can be configured further before the server starts.
+func setupServer(t *testing.T, configure ...func(*Services)) testClients {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "rpc.db")), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&domain.User{}, &domain.Category{}, &domain.Transaction{}, &domain.Budget{}, &domain.FinancialReport{},
+		&domain.Comment{}, &domain.TransactionItem{}, &domain.UsageCounter{}))
+	require.NoError(t, db.Create(&domain.User{ID: 1, Email: "one@example.com", Password: "x", RiskTolerance: "aggressive"}).Error)
+	require.NoError(t, db.Create(&domain.User{ID: 2, Email: "two@example.com", Password: "x"}).Error)
+	require.NoError(t, db.Create(&domain.Category{ID: 1, Name: "Food", Type: "expense", IsDefault: true}).Error)
+
+	budgetSvc := &application.BudgetService{DB: db}
+	services := Services{
+		Transactions: &application.TransactionService{DB: db, Budgets: budgetSvc},
+		Budgets:      budgetSvc,
+		Reports:      application.NewReportsService(db),
+		Advisor:      &application.AdvisorService{DB: db},
+		Users:        &application.UserService{DB: db},
+	}
+	for _, fn := range configure {
+		fn(&services)
+	}
+	server := NewServer(services)
+	listener := bufconn.Listen(1 << 20)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return testClients{
+		db:           db,
+		transactions: pb.NewTransactionServiceClient(conn),
+		budgets:      pb.NewBudgetServiceClient(conn),
+		reports:      pb.NewReportServiceClient(conn),
+		advisor:      pb.NewAdvisorServiceClient(conn),
+	}
+}
+
+// as returns a context that authenticates calls as the user
+func as(t *testing.T, userID uint) context.Context {
+	token, err := middleware.GenerateToken(userID)
+	require.NoError(t, err)
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func TestServer_RequiresToken(t *testing.T) {
+	clients := setupServer(t)
+
+	_, err := clients.transactions.ListTransactions(context.Background(), &pb.ListTransactionsRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer not-a-token")
+	_, err = clients.advisor.GetAdvice(ctx, &pb.GetAdviceRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestServer_DemoUserIsReadOnly(t *testing.T) {
+	clients := setupServer(t, func(services *Services) { services.DemoUserID = 2 })
+	demo := as(t, 2)
+
+	_, err := clients.transactions.CreateTransaction(demo, &pb.CreateTransactionRequest{
+		CategoryId: 1, Type: domain.TransactionTypeExpense, Description: "Groceries", Amount: 10,
+	})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = clients.transactions.DeleteTransaction(demo, &pb.DeleteTransactionRequest{Id: 1})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = clients.budgets.CreateBudget(demo, &pb.CreateBudgetRequest{CategoryId: 1, Amount: 300, Period: "monthly"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = clients.transactions.ListTransactions(demo, &pb.ListTransactionsRequest{})
+	assert.NoError(t, err, "the demo user can read")
+	_, err = clients.transactions.CreateTransaction(as(t, 1), &pb.CreateTransactionRequest{
+		CategoryId: 1, Type: domain.TransactionTypeExpense, Description: "Groceries", Amount: 10,
+		Date: timestamppb.New(time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)),
+	})
+	assert.NoError(t, err, "other users are not restricted")
+}
+
+func TestTransactionService_ScopedToCaller(t *testing.T) {
+	clients := setupServer(t)
+	ctx := as(t, 1)
+
+	created, err := clients.transactions.CreateTransaction(ctx, &pb.CreateTransactionRequest{
+		CategoryId:  1,
+		Type:        domain.TransactionTypeExpense,
+		Description: "Groceries",
+		Amount:      42.5,
+		Date:        timestamppb.New(time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), created.UserId)
+
+	page, err := clients.transactions.ListTransactions(ctx, &pb.ListTransactionsRequest{})
+	require.NoError(t, err)
+	require.Len(t, page.Transactions, 1)
+	assert.Equal(t, int64(1), page.Total)
+	assert.Equal(t, "Food", page.Transactions[0].CategoryName)
+
+	// Another user can neither see nor change the transaction
+	other := as(t, 2)
+	_, err = clients.transactions.GetTransaction(other, &pb.GetTransactionRequest{Id: created.Id})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = clients.transactions.DeleteTransaction(other, &pb.DeleteTransactionRequest{Id: created.Id})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	otherPage, err := clients.transactions.ListTransactions(other, &pb.ListTransactionsRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, otherPage.Transactions)
+
+	updated, err := clients.transactions.UpdateTransaction(ctx, &pb.UpdateTransactionRequest{
+		Id: created.Id, CategoryId: 1, Type: domain.TransactionTypeExpense, Description: "Market", Amount: 50,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Market", updated.Description)
+	assert.True(t, updated.Date.AsTime().Equal(created.Date.AsTime()), "date is kept when unset")
+
+	_, err = clients.transactions.DeleteTransaction(ctx, &pb.DeleteTransactionRequest{Id: created.Id})
+	require.NoError(t, err)
+	_, err = clients.transactions.GetTransaction(ctx, &pb.GetTransactionRequest{Id: created.Id})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestTransactionService_InvalidArguments(t *testing.T) {
+	clients := setupServer(t)
+	ctx := as(t, 1)
+
+	_, err := clients.transactions.CreateTransaction(ctx, &pb.CreateTransactionRequest{
+		CategoryId: 1, Type: domain.TransactionTypeExpense, Description: "Nothing", Amount: 0,
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = clients.transactions.CreateTransaction(ctx, &pb.CreateTransactionRequest{
+		CategoryId: 1, Type: "transfer", Description: "Move", Amount: 10,
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = clients.transactions.ListTransactions(ctx, &pb.ListTransactionsRequest{Cursor: "garbage"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestBudgetService_CreateListAndDelete(t *testing.T) {
+	clients := setupServer(t)
+	ctx := as(t, 1)
+
+	budget, err := clients.budgets.CreateBudget(ctx, &pb.CreateBudgetRequest{
+		CategoryId: 1, Amount: 300, Period: "quarterly",
+		StartDate: timestamppb.New(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), budget.EndDate.AsTime())
+	assert.Equal(t, 300.0, budget.Remaining)
+
+	_, err = clients.budgets.CreateBudget(ctx, &pb.CreateBudgetRequest{CategoryId: 1, Amount: 10, Period: "daily"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	list, err := clients.budgets.ListBudgets(ctx, &pb.ListBudgetsRequest{})
+	require.NoError(t, err)
+	require.Len(t, list.Budgets, 1)
+
+	summary, err := clients.budgets.GetBudgetSummary(ctx, &pb.GetBudgetSummaryRequest{})
+	require.NoError(t, err)
+	assert.NotEmpty(t, summary.BudgetStatus)
+
+	_, err = clients.budgets.DeleteBudget(as(t, 2), &pb.DeleteBudgetRequest{Id: budget.Id})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = clients.budgets.DeleteBudget(ctx, &pb.DeleteBudgetRequest{Id: budget.Id})
+	require.NoError(t, err)
+}
+
+func TestReportService_GenerateMonthlyReport(t *testing.T) {
+	clients := setupServer(t)
+	ctx := as(t, 1)
+
+	require.NoError(t, clients.db.Create(&domain.Transaction{
+		UserID: 1, CategoryID: 1, Type: domain.TransactionTypeIncome, Amount: domain.NewMoney(1000),
+		Date: time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC),
+	}).Error)
+	require.NoError(t, clients.db.Create(&domain.Transaction{
+		UserID: 1, CategoryID: 1, Type: domain.TransactionTypeExpense, Amount: domain.NewMoney(250),
+		Date: time.Date(2024, 5, 4, 0, 0, 0, 0, time.UTC),
+	}).Error)
+
+	report, err := clients.reports.GenerateMonthlyReport(ctx, &pb.GenerateMonthlyReportRequest{Year: 2024, Month: 5})
+	require.NoError(t, err)
+	assert.Equal(t, "monthly", report.ReportType)
+	assert.Equal(t, 1000.0, report.TotalIncome)
+	assert.Equal(t, 250.0, report.TotalExpenses)
+	assert.Equal(t, int32(2), report.TransactionCount)
+
+	_, err = clients.reports.GenerateMonthlyReport(ctx, &pb.GenerateMonthlyReportRequest{Year: 2024, Month: 13})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestAdvisorService_GetAdvice(t *testing.T) {
+	clients := setupServer(t)
+
+	advice, err := clients.advisor.GetAdvice(as(t, 1), &pb.GetAdviceRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "aggressive", advice.Risk)
+	require.Len(t, advice.Recommendations, 2)
+	assert.Equal(t, 70.0, advice.Recommendations[1].Percent)
+
+	_, err = clients.advisor.GetAdvice(as(t, 99), &pb.GetAdviceRequest{})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestAdvisorService_GetAdviceEnforcesPlan(t *testing.T) {
+	clients := setupServer(t, func(services *Services) {
+		services.Quotas = application.NewQuotaService(services.Users.DB, map[string]domain.PlanLimits{
+			domain.PlanFree:    {},
+			domain.PlanPremium: {AIRequestsPerDay: 1, Features: []string{domain.FeatureAIAdvisor}},
+		})
+	})
+	require.NoError(t, clients.db.Model(&domain.User{}).Where("id = ?", 1).Update("plan", domain.PlanPremium).Error)
+
+	_, err := clients.advisor.GetAdvice(as(t, 2), &pb.GetAdviceRequest{})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err), "the free plan has no AI advisor")
+
+	_, err = clients.advisor.GetAdvice(as(t, 1), &pb.GetAdviceRequest{})
+	require.NoError(t, err)
+	_, err = clients.advisor.GetAdvice(as(t, 1), &pb.GetAdviceRequest{})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), "the daily quota is used up")
+}
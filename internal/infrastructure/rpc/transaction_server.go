@@ -0,0 +1,169 @@
+package rpc
+
+import (
+	"context"
+	"time"
+
+	"go-finance-advisor/internal/application"
+	"go-finance-advisor/internal/domain"
+	pb "go-finance-advisor/internal/infrastructure/rpc/gen/financeadvisor/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultListLimit matches the page size of the HTTP transaction list
+const defaultListLimit = 100
+
+type transactionServer struct {
+	pb.UnimplementedTransactionServiceServer
+	service *application.TransactionService
+}
+
+// validateTransaction applies the same rules as the HTTP API's request binding
+func validateTransaction(categoryID uint64, transactionType, description string, amount float64) error {
+	switch {
+	case domain.NewMoney(amount) <= 0:
+		return status.Error(codes.InvalidArgument, "amount must be greater than zero")
+	case transactionType != domain.TransactionTypeIncome && transactionType != domain.TransactionTypeExpense:
+		return status.Error(codes.InvalidArgument, "type must be income or expense")
+	case description == "" || len(description) > 255:
+		return status.Error(codes.InvalidArgument, "description must be between 1 and 255 characters")
+	case categoryID == 0:
+		return status.Error(codes.InvalidArgument, "category_id is required")
+	}
+	return nil
+}
+
+// owned returns the caller's transaction. Other users' transactions are
+// reported as not found so their IDs do not leak.
+func (s *transactionServer) owned(ctx context.Context, id uint64) (*domain.Transaction, error) {
+	transaction, err := s.service.GetByID(ctx, uint(id))
+	if err != nil {
+		return nil, statusError(err, "failed to retrieve transaction")
+	}
+	if transaction.UserID != userID(ctx) {
+		return nil, status.Error(codes.NotFound, domain.ErrNotFound.Error())
+	}
+	return transaction, nil
+}
+
+func (s *transactionServer) CreateTransaction(ctx context.Context, req *pb.CreateTransactionRequest) (*pb.Transaction, error) {
+	if err := validateTransaction(req.CategoryId, req.Type, req.Description, req.Amount); err != nil {
+		return nil, err
+	}
+
+	transaction := &domain.Transaction{
+		UserID:      userID(ctx),
+		CategoryID:  uint(req.CategoryId),
+		Type:        req.Type,
+		Description: req.Description,
+		Amount:      domain.NewMoney(req.Amount),
+		Date:        time.Now(),
+	}
+	if req.Date != nil {
+		transaction.Date = req.Date.AsTime()
+	}
+
+	if err := s.service.Create(ctx, transaction); err != nil {
+		return nil, statusError(err, "failed to create transaction")
+	}
+	return toTransaction(transaction), nil
+}
+
+func (s *transactionServer) GetTransaction(ctx context.Context, req *pb.GetTransactionRequest) (*pb.Transaction, error) {
+	transaction, err := s.owned(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return toTransaction(transaction), nil
+}
+
+func (s *transactionServer) ListTransactions(
+	ctx context.Context, req *pb.ListTransactionsRequest,
+) (*pb.ListTransactionsResponse, error) {
+	filter := domain.TransactionFilter{
+		UserID: userID(ctx),
+		Type:   req.Type,
+		Limit:  int(req.Limit),
+		Offset: int(req.Offset),
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultListLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	if req.CategoryId != nil {
+		categoryID := uint(*req.CategoryId)
+		filter.CategoryID = &categoryID
+	}
+	if req.StartDate != nil {
+		start := req.StartDate.AsTime()
+		filter.StartDate = &start
+	}
+	if req.EndDate != nil {
+		end := req.EndDate.AsTime()
+		filter.EndDate = &end
+	}
+	if req.Cursor != "" {
+		cursor, err := domain.DecodeTransactionCursor(req.Cursor)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid cursor")
+		}
+		filter.Cursor = cursor
+	}
+
+	page, err := s.service.ListPage(ctx, filter)
+	if err != nil {
+		return nil, statusError(err, "failed to retrieve transactions")
+	}
+
+	resp := &pb.ListTransactionsResponse{
+		Transactions: make([]*pb.Transaction, 0, len(page.Transactions)),
+		Total:        page.Total,
+		Limit:        int32(page.Limit),
+		Offset:       int32(page.Offset),
+		NextCursor:   page.NextCursor,
+		PrevCursor:   page.PrevCursor,
+	}
+	for i := range page.Transactions {
+		resp.Transactions = append(resp.Transactions, toTransaction(&page.Transactions[i]))
+	}
+	return resp, nil
+}
+
+func (s *transactionServer) UpdateTransaction(ctx context.Context, req *pb.UpdateTransactionRequest) (*pb.Transaction, error) {
+	if err := validateTransaction(req.CategoryId, req.Type, req.Description, req.Amount); err != nil {
+		return nil, err
+	}
+	transaction, err := s.owned(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction.CategoryID = uint(req.CategoryId)
+	transaction.Type = req.Type
+	transaction.Description = req.Description
+	transaction.Amount = domain.NewMoney(req.Amount)
+	if req.Date != nil {
+		transaction.Date = req.Date.AsTime()
+	}
+
+	if err := s.service.Update(ctx, transaction); err != nil {
+		return nil, statusError(err, "failed to update transaction")
+	}
+	return toTransaction(transaction), nil
+}
+
+func (s *transactionServer) DeleteTransaction(
+	ctx context.Context, req *pb.DeleteTransactionRequest,
+) (*pb.DeleteTransactionResponse, error) {
+	if _, err := s.owned(ctx, req.Id); err != nil {
+		return nil, err
+	}
+	if err := s.service.Delete(ctx, uint(req.Id)); err != nil {
+		return nil, statusError(err, "failed to delete transaction")
+	}
+	return &pb.DeleteTransactionResponse{}, nil
+}
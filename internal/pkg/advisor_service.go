@@ -2,13 +2,15 @@ package pkg
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"math"
 	"net/http"
+	neturl "net/url"
 	"strings"
+	"sync"
 	"time"
 
+	"go-finance-advisor/internal/config"
 	"go-finance-advisor/internal/domain"
 )
 
@@ -39,6 +41,7 @@ type CryptoPrice struct {
 	Change24h float64 `json:"price_change_percentage_24h"`
 	MarketCap float64 `json:"market_cap"`
 	Volume24h float64 `json:"total_volume"`
+	Stale     bool    `json:"stale,omitempty"` // Last known price, the provider is unavailable
 }
 
 // StockPrice represents stock price data
@@ -48,6 +51,7 @@ type StockPrice struct {
 	Change    float64 `json:"change"`
 	ChangePct float64 `json:"change_percent"`
 	Volume    int64   `json:"volume"`
+	Stale     bool    `json:"stale,omitempty"` // Last known price, the provider is unavailable
 }
 
 // MarketAnalysis represents comprehensive AI-powered market analysis
@@ -61,11 +65,13 @@ type MarketAnalysis struct {
 	ConfidenceLevel float64       `json:"confidence_level"`
 	RiskScore       float64       `json:"risk_score"`
 	PredictedReturn float64       `json:"predicted_return"`
+	Stale           bool          `json:"stale"` // Some prices are last known ones
 	LastUpdated     time.Time     `json:"last_updated"`
 }
 
 // AIRiskAssessment represents AI-driven risk analysis
 type AIRiskAssessment struct {
+	AdviceID              uint               `json:"advice_id,omitempty"` // Set once kept in the advice history
 	UserID                uint               `json:"user_id"`
 	RiskScore             float64            `json:"risk_score"`
 	RiskCategory          string             `json:"risk_category"`
@@ -86,6 +92,7 @@ type MarketSentiment struct {
 
 // InvestmentRecommendation represents personalized investment advice
 type InvestmentRecommendation struct {
+	AdviceID        uint                    `json:"advice_id,omitempty"` // Set once kept in the advice history
 	UserID          uint                    `json:"user_id"`
 	RiskProfile     string                  `json:"risk_profile"`
 	Recommendations []domain.Recommendation `json:"recommendations"`
@@ -94,116 +101,370 @@ type InvestmentRecommendation struct {
 	CreatedAt       time.Time               `json:"created_at"`
 }
 
+// defaultMarketClient is used by services created without a constructor
+var defaultMarketClient = &http.Client{Timeout: 15 * time.Second}
+
+// DefaultStockSymbols are the popular stocks tracked when no symbols are asked for
+var DefaultStockSymbols = []string{"AAPL", "GOOGL", "MSFT", "TSLA", "AMZN"}
+
+// SymbolMatch is a market symbol found by a search
+type SymbolMatch struct {
+	Symbol     string  `json:"symbol"`
+	Name       string  `json:"name"`
+	Type       string  `json:"type"`
+	Region     string  `json:"region"`
+	Currency   string  `json:"currency"`
+	MatchScore float64 `json:"match_score"`
+}
+
+// Market data providers, each rate limited and circuit broken on its own
+const (
+	providerCoinGecko    = "coingecko"
+	providerAlphaVantage = "alpha_vantage"
+)
+
 // RealTimeMarketService provides real-time market data and investment advice
 type RealTimeMarketService struct {
 	client *http.Client
+	config config.MarketConfig
+	AI     config.AIConfig // Heuristics behind the analyses; the defaults when unset
+
+	providersMu sync.Mutex
+	providers   map[string]*marketProvider
+	lastKnown   lastKnownPrices
 }
 
-// NewRealTimeMarketService creates a new market service instance
+// NewRealTimeMarketService creates a new market service instance using the default provider settings
 func NewRealTimeMarketService() *RealTimeMarketService {
+	return NewRealTimeMarketServiceWithConfig(config.Default().Market)
+}
+
+// NewRealTimeMarketServiceWithConfig creates a market service for the configured providers
+func NewRealTimeMarketServiceWithConfig(cfg config.MarketConfig) *RealTimeMarketService {
 	return &RealTimeMarketService{
-		client: &http.Client{Timeout: 15 * time.Second},
+		client: &http.Client{Timeout: cfg.RequestTimeout.Std()},
+		config: cfg,
 	}
 }
 
-// GetCryptoPrices fetches real-time cryptocurrency prices from CoinGecko
-func (s *RealTimeMarketService) GetCryptoPrices() ([]CryptoPrice, error) {
-	ctx := context.Background()
-	url := "https://api.coingecko.com/api/v3/coins/markets?vs_currency=usd&order=market_cap_desc&per_page=10&page=1&sparkline=false"
+// httpClient and providerConfig fall back to defaults so a zero-value service still works
+func (s *RealTimeMarketService) httpClient() *http.Client {
+	if s.client == nil {
+		return defaultMarketClient
+	}
+	return s.client
+}
 
-	req, _ := http.NewRequestWithContext(ctx, "GET", url, http.NoBody)
-	resp, err := s.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch crypto data: %v", err)
+// aiConfig falls back to the default heuristics when none are set
+func (s *RealTimeMarketService) aiConfig() config.AIConfig {
+	if s.AI.TrendCutoff == 0 {
+		return config.Default().AI
 	}
-	defer func() {
-		if closeErr := resp.Body.Close(); closeErr != nil {
-			// Log error if needed, but don't fail the function
-		}
-	}()
+	return s.AI
+}
+
+func (s *RealTimeMarketService) providerConfig() config.MarketConfig {
+	defaults := config.Default().Market
+	cfg := s.config
+	if cfg.CoinGeckoBaseURL == "" {
+		cfg.CoinGeckoBaseURL = defaults.CoinGeckoBaseURL
+	}
+	if cfg.AlphaVantageBaseURL == "" {
+		cfg.AlphaVantageBaseURL = defaults.AlphaVantageBaseURL
+	}
+	if cfg.AlphaVantageAPIKey == "" {
+		cfg.AlphaVantageAPIKey = defaults.AlphaVantageAPIKey
+	}
+	if cfg.RequestTimeout <= 0 {
+		cfg.RequestTimeout = defaults.RequestTimeout
+	}
+	if cfg.MaxConcurrentRequests <= 0 {
+		cfg.MaxConcurrentRequests = defaults.MaxConcurrentRequests
+	}
+	return cfg
+}
 
+// GetCryptoPrices fetches real-time cryptocurrency prices from CoinGecko,
+// falling back to the last known prices while CoinGecko is unavailable.
+// The request is abandoned as soon as ctx is cancelled.
+func (s *RealTimeMarketService) GetCryptoPrices(ctx context.Context) ([]CryptoPrice, error) {
+	cfg := s.providerConfig()
+	url := cfg.CoinGeckoBaseURL + "/coins/markets?vs_currency=usd&order=market_cap_desc&per_page=10&page=1&sparkline=false"
+
+	header := http.Header{}
+	if cfg.CoinGeckoAPIKey != "" {
+		header.Set("x-cg-demo-api-key", cfg.CoinGeckoAPIKey)
+	}
 	var cryptos []CryptoPrice
-	if err := json.NewDecoder(resp.Body).Decode(&cryptos); err != nil {
-		return nil, fmt.Errorf("failed to parse crypto data: %v", err)
+	err := s.fetchJSON(ctx, s.provider(providerCoinGecko), url, header, &cryptos)
+	if err == nil {
+		err = checkCryptoPrices(cryptos)
+	}
+	if err != nil {
+		if ctx.Err() == nil {
+			if stale, ok := s.lastKnown.staleCryptos(); ok {
+				return stale, nil
+			}
+		}
+		return nil, fmt.Errorf("failed to fetch crypto data: %w", err)
 	}
 
+	s.lastKnown.rememberCryptos(cryptos)
 	return cryptos, nil
 }
 
-// GetStockPrices fetches real-time stock prices from Alpha Vantage
-func (s *RealTimeMarketService) GetStockPrices(symbols []string) ([]StockPrice, error) {
-	var stocks []StockPrice
-
-	// Popular stocks to track
-	defaultSymbols := []string{"AAPL", "GOOGL", "MSFT", "TSLA", "AMZN"}
+// GetStockPrices fetches real-time stock prices from Alpha Vantage, up to
+// MaxConcurrentRequests symbols at a time within the provider's rate limit.
+// The prices keep the order of symbols. Symbols that fail get their last
+// known price or are skipped, but cancelling ctx stops the whole batch.
+func (s *RealTimeMarketService) GetStockPrices(ctx context.Context, symbols []string) ([]StockPrice, error) {
 	if len(symbols) == 0 {
-		symbols = defaultSymbols
+		symbols = DefaultStockSymbols
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
-	ctx := context.Background()
-	for _, symbol := range symbols {
-		// Using Alpha Vantage API (demo key - replace with real key for production)
-		url := fmt.Sprintf("https://www.alphavantage.co/query?function=GLOBAL_QUOTE&symbol=%s&apikey=demo", symbol)
+	cfg := s.providerConfig()
+	quotes := make([]*StockPrice, len(symbols))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(cfg.MaxConcurrentRequests, len(symbols)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				quote, err := s.fetchStockQuote(ctx, cfg, symbols[i])
+				if err == nil {
+					s.lastKnown.rememberStock(*quote)
+					quotes[i] = quote
+				} else if stale, ok := s.lastKnown.staleStock(symbols[i]); ok && ctx.Err() == nil {
+					quotes[i] = &stale
+				}
+			}
+		}()
+	}
 
-		req, _ := http.NewRequestWithContext(ctx, "GET", url, http.NoBody)
-		resp, err := s.client.Do(req)
-		if err != nil {
-			continue // Skip failed requests
+queue:
+	for i := range symbols {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break queue
 		}
+	}
+	close(jobs)
+	wg.Wait()
 
-		var data map[string]map[string]string
-		err = json.NewDecoder(resp.Body).Decode(&data)
-		if closeErr := resp.Body.Close(); closeErr != nil {
-			// Log error if needed, but don't fail the function
-		}
-		if err != nil {
-			continue
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var stocks []StockPrice
+	for _, quote := range quotes {
+		if quote != nil {
+			stocks = append(stocks, *quote)
 		}
+	}
+	return stocks, nil
+}
 
-		if quote, ok := data["Global Quote"]; ok {
-			var price, change, changePct float64
-			var volume int64
+// fetchStockQuote fetches the global quote of one symbol
+func (s *RealTimeMarketService) fetchStockQuote(ctx context.Context, cfg config.MarketConfig, symbol string) (*StockPrice, error) {
+	url := fmt.Sprintf("%s/query?function=GLOBAL_QUOTE&symbol=%s&apikey=%s",
+		cfg.AlphaVantageBaseURL, neturl.QueryEscape(symbol), neturl.QueryEscape(cfg.AlphaVantageAPIKey))
 
-			if _, err := fmt.Sscanf(quote["05. price"], "%f", &price); err != nil {
-				continue
-			}
-			if _, err := fmt.Sscanf(quote["09. change"], "%f", &change); err != nil {
-				continue
-			}
-			if _, err := fmt.Sscanf(quote["10. change percent"], "%f%%", &changePct); err != nil {
-				continue
-			}
-			if _, err := fmt.Sscanf(quote["06. volume"], "%d", &volume); err != nil {
-				continue
-			}
+	var data struct {
+		alphaVantageNotice
+		Quote map[string]string `json:"Global Quote"`
+	}
+	if err := s.fetchJSON(ctx, s.provider(providerAlphaVantage), url, nil, &data); err != nil {
+		return nil, err
+	}
+	if err := data.err(); err != nil {
+		return nil, err
+	}
+	if len(data.Quote) == 0 {
+		return nil, fmt.Errorf("no quote for %s", symbol)
+	}
+
+	return parseGlobalQuote(symbol, data.Quote)
+}
+
+// SearchSymbols looks up stock symbols and company names matching query with
+// the Alpha Vantage symbol search, best matches first
+func (s *RealTimeMarketService) SearchSymbols(ctx context.Context, query string) ([]SymbolMatch, error) {
+	cfg := s.providerConfig()
+	url := fmt.Sprintf("%s/query?function=SYMBOL_SEARCH&keywords=%s&apikey=%s",
+		cfg.AlphaVantageBaseURL, neturl.QueryEscape(query), neturl.QueryEscape(cfg.AlphaVantageAPIKey))
 
-			stocks = append(stocks, StockPrice{
-				Symbol:    symbol,
-				Price:     price,
-				Change:    change,
-				ChangePct: changePct,
-				Volume:    volume,
-			})
+	var data struct {
+		alphaVantageNotice
+		BestMatches []map[string]string `json:"bestMatches"`
+	}
+	err := s.fetchJSON(ctx, s.provider(providerAlphaVantage), url, nil, &data)
+	if err == nil {
+		err = data.err()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to search symbols: %w", err)
+	}
+
+	matches := make([]SymbolMatch, 0, len(data.BestMatches))
+	for _, match := range data.BestMatches {
+		if match["1. symbol"] == "" {
+			continue
+		}
+		score, err := parseQuoteNumber(match["9. matchScore"])
+		if err != nil {
+			score = 0
 		}
+		matches = append(matches, SymbolMatch{
+			Symbol:     match["1. symbol"],
+			Name:       match["2. name"],
+			Type:       match["3. type"],
+			Region:     match["4. region"],
+			Currency:   match["8. currency"],
+			MatchScore: score,
+		})
+	}
+	return matches, nil
+}
 
-		// Rate limiting
-		time.Sleep(200 * time.Millisecond)
+// PrioritizeStockSymbols lists the watchlisted symbols first and fills up with
+// the default symbols, so watchlisted stocks are always priced and the rest
+// only make up the usual number of quotes
+func PrioritizeStockSymbols(watchlisted []string) []string {
+	limit := max(len(watchlisted), len(DefaultStockSymbols))
+	symbols := make([]string, 0, limit)
+	seen := make(map[string]bool, limit)
+	for _, symbol := range append(append([]string(nil), watchlisted...), DefaultStockSymbols...) {
+		symbol = strings.ToUpper(symbol)
+		if len(symbols) == limit {
+			break
+		}
+		if !seen[symbol] {
+			seen[symbol] = true
+			symbols = append(symbols, symbol)
+		}
 	}
+	return symbols
+}
 
-	return stocks, nil
+// PrioritizeCryptos moves the watchlisted cryptocurrencies to the front,
+// keeping the market cap order otherwise
+func PrioritizeCryptos(cryptos []CryptoPrice, watchlisted []string) []CryptoPrice {
+	if len(watchlisted) == 0 {
+		return cryptos
+	}
+	watched := make(map[string]bool, len(watchlisted))
+	for _, symbol := range watchlisted {
+		watched[strings.ToUpper(symbol)] = true
+	}
+	prioritized := make([]CryptoPrice, 0, len(cryptos))
+	var rest []CryptoPrice
+	for _, crypto := range cryptos {
+		if watched[strings.ToUpper(crypto.Symbol)] {
+			prioritized = append(prioritized, crypto)
+		} else {
+			rest = append(rest, crypto)
+		}
+	}
+	return append(prioritized, rest...)
 }
 
-// AnalyzeMarket performs comprehensive AI-powered market analysis
-func (s *RealTimeMarketService) AnalyzeMarket() (*MarketAnalysis, error) {
-	cryptos, err := s.GetCryptoPrices()
-	if err != nil {
-		return nil, err
+// GetPrices returns the current USD price of each symbol it can find, keyed
+// by upper-cased symbol. Symbols among the top cryptocurrencies are priced from
+// CoinGecko and the rest are looked up as stocks; unknown symbols are left out,
+// and so are stale prices, which would look current to price alerts and
+// backtests.
+func (s *RealTimeMarketService) GetPrices(ctx context.Context, symbols []string) (map[string]float64, error) {
+	prices := make(map[string]float64, len(symbols))
+	if len(symbols) == 0 {
+		return prices, nil
+	}
+
+	cryptos, err := s.GetCryptoPrices(ctx)
+	if err != nil && ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	cryptoPrices := make(map[string]float64, len(cryptos))
+	for _, crypto := range cryptos {
+		cryptoPrices[strings.ToUpper(crypto.Symbol)] = crypto.Price
+	}
+	cryptosStale := len(cryptos) > 0 && cryptos[0].Stale
+
+	var stockSymbols []string
+	seen := make(map[string]bool, len(symbols))
+	for _, symbol := range symbols {
+		symbol = strings.ToUpper(symbol)
+		if seen[symbol] {
+			continue
+		}
+		seen[symbol] = true
+		if price, ok := cryptoPrices[symbol]; ok {
+			if !cryptosStale {
+				prices[symbol] = price
+			}
+		} else {
+			stockSymbols = append(stockSymbols, symbol)
+		}
+	}
+	if len(stockSymbols) == 0 {
+		return prices, nil
 	}
 
-	stocks, err := s.GetStockPrices(nil)
+	stocks, err := s.GetStockPrices(ctx, stockSymbols)
 	if err != nil {
 		return nil, err
 	}
+	for _, stock := range stocks {
+		if !stock.Stale {
+			prices[strings.ToUpper(stock.Symbol)] = stock.Price
+		}
+	}
+	return prices, nil
+}
+
+// AnalyzeMarket performs comprehensive AI-powered market analysis. Crypto
+// and stock prices are fetched in parallel; if either fails the other is
+// abandoned.
+func (s *RealTimeMarketService) AnalyzeMarket(ctx context.Context) (*MarketAnalysis, error) {
+	fetchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		cryptos  []CryptoPrice
+		stocks   []StockPrice
+		firstErr error
+		failOnce sync.Once
+		wg       sync.WaitGroup
+	)
+	// Keep the failure that happened rather than the cancellation it caused
+	fail := func(err error) {
+		failOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		var err error
+		if cryptos, err = s.GetCryptoPrices(fetchCtx); err != nil {
+			fail(err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		var err error
+		if stocks, err = s.GetStockPrices(fetchCtx, nil); err != nil {
+			fail(err)
+		}
+	}()
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
 
 	// AI-powered market analysis
 	marketTrend := s.calculateMarketTrend(cryptos, stocks)
@@ -226,13 +487,31 @@ func (s *RealTimeMarketService) AnalyzeMarket() (*MarketAnalysis, error) {
 		ConfidenceLevel: confidenceLevel,
 		RiskScore:       riskScore,
 		PredictedReturn: predictedReturn,
+		Stale:           anyStale(cryptos, stocks),
 		LastUpdated:     time.Now(),
 	}, nil
 }
 
+// anyStale reports whether any of the prices is a last known one
+func anyStale(cryptos []CryptoPrice, stocks []StockPrice) bool {
+	for i := range cryptos {
+		if cryptos[i].Stale {
+			return true
+		}
+	}
+	for i := range stocks {
+		if stocks[i].Stale {
+			return true
+		}
+	}
+	return false
+}
+
 // GeneratePersonalizedAdvice creates personalized investment recommendations
-func (s *RealTimeMarketService) GeneratePersonalizedAdvice(user *domain.User, monthlyIncome float64) (*InvestmentRecommendation, error) {
-	marketAnalysis, err := s.AnalyzeMarket()
+func (s *RealTimeMarketService) GeneratePersonalizedAdvice(
+	ctx context.Context, user *domain.User, monthlyIncome float64,
+) (*InvestmentRecommendation, error) {
+	marketAnalysis, err := s.AnalyzeMarket(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -271,10 +550,11 @@ func (s *RealTimeMarketService) calculateMarketTrend(cryptos []CryptoPrice, stoc
 		return "neutral"
 	}
 
+	cutoff := s.aiConfig().TrendCutoff
 	avgChange := totalChange / float64(count)
-	if avgChange > 2 {
+	if avgChange > cutoff {
 		return marketSentimentBullish
-	} else if avgChange < -2 {
+	} else if avgChange < -cutoff {
 		return marketSentimentBearish
 	}
 	return "neutral"
@@ -306,10 +586,11 @@ func (s *RealTimeMarketService) calculateVolatility(cryptos []CryptoPrice, stock
 		return riskLevelLow
 	}
 
+	ai := s.aiConfig()
 	avgVolatility := totalVolatility / float64(count)
-	if avgVolatility > 5 {
+	if avgVolatility > ai.VolatilityHighCutoff {
 		return riskLevelHigh
-	} else if avgVolatility > 2 {
+	} else if avgVolatility > ai.VolatilityMediumCutoff {
 		return "medium"
 	}
 	return riskLevelLow
@@ -337,7 +618,7 @@ func (s *RealTimeMarketService) GenerateRecommendations(
 	monthlyIncome float64,
 	analysis *MarketAnalysis,
 ) []domain.Recommendation {
-	investableAmount := monthlyIncome * 0.2 // 20% of income for investments
+	investableAmount := monthlyIncome * s.aiConfig().InvestableShare
 
 	switch riskTolerance {
 	case "conservative":
@@ -507,13 +788,13 @@ func (s *RealTimeMarketService) GenerateAdviceText(riskTolerance string, analysi
 }
 
 // GetMarketData fetches current market data
-func (s *RealTimeMarketService) GetMarketData() (*MarketData, error) {
-	return FetchMarketData()
+func (s *RealTimeMarketService) GetMarketData(ctx context.Context) (*MarketData, error) {
+	return FetchMarketDataWithConfig(ctx, s.providerConfig())
 }
 
 // GetMarketSummary provides a quick AI-enhanced market overview
-func (s *RealTimeMarketService) GetMarketSummary() (map[string]interface{}, error) {
-	analysis, err := s.AnalyzeMarket()
+func (s *RealTimeMarketService) GetMarketSummary(ctx context.Context) (map[string]interface{}, error) {
+	analysis, err := s.AnalyzeMarket(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -526,8 +807,9 @@ func (s *RealTimeMarketService) GetMarketSummary() (map[string]interface{}, erro
 		"confidence_level": analysis.ConfidenceLevel,
 		"risk_score":       analysis.RiskScore,
 		"predicted_return": analysis.PredictedReturn,
-		"top_cryptos":      analysis.Cryptos[:3], // Top 3 cryptos
-		"top_stocks":       analysis.Stocks[:3],  // Top 3 stocks
+		"top_cryptos":      analysis.Cryptos[:min(3, len(analysis.Cryptos))], // Top 3 cryptos
+		"top_stocks":       analysis.Stocks[:min(3, len(analysis.Stocks))],   // Top 3 stocks
+		"stale":            analysis.Stale,
 		"last_updated":     analysis.LastUpdated,
 	}
 
@@ -699,7 +981,9 @@ func (s *RealTimeMarketService) PerformAIRiskAssessment(
 	investmentGoals []string,
 ) (*AIRiskAssessment, error) {
 	// Analyze user factors
-	factors := []string{"age", "income", "risk_tolerance", "investment_goals", "market_conditions"}
+	factors := []string{
+		"age", "income", "dependents", "employment_status", "risk_tolerance", "investment_goals", "market_conditions",
+	}
 
 	// Calculate risk score based on multiple factors
 	riskScore := s.calculateUserRiskScore(user, monthlyIncome)
@@ -730,9 +1014,10 @@ func (s *RealTimeMarketService) calculateUserRiskScore(user *domain.User, monthl
 	var score float64
 
 	// Age factor (younger = higher risk tolerance)
-	if user.Age < 30 {
+	age := user.CurrentAge(time.Now())
+	if age < 30 {
 		score += 0.3
-	} else if user.Age < 50 {
+	} else if age < 50 {
 		score += 0.2
 	} else {
 		score += 0.1
@@ -757,51 +1042,54 @@ func (s *RealTimeMarketService) calculateUserRiskScore(user *domain.User, monthl
 		score += 0.1
 	}
 
-	return math.Min(score, 1.0)
+	// Dependents and an unsteady income lower the risk the user can carry
+	if user.Dependents > 2 {
+		score -= 0.1
+	} else if user.Dependents > 0 {
+		score -= 0.05
+	}
+	switch user.EmploymentStatus {
+	case domain.EmploymentUnemployed, domain.EmploymentStudent:
+		score -= 0.1
+	case domain.EmploymentRetired:
+		score -= 0.05
+	}
+
+	return math.Max(0, math.Min(score, 1.0))
 }
 
 func (s *RealTimeMarketService) determineRiskCategory(score float64) string {
-	if score >= 0.7 {
+	ai := s.aiConfig()
+	if score >= ai.HighRiskScore {
 		return "high_risk_high_reward"
-	} else if score >= 0.4 {
+	} else if score >= ai.ModerateRiskScore {
 		return "moderate_risk"
 	}
 	return "low_risk_stable"
 }
 
+// generateAIAllocation looks up the configured allocation of the score's risk category
 func (s *RealTimeMarketService) generateAIAllocation(riskScore float64) map[string]float64 {
-	allocation := make(map[string]float64)
-
-	if riskScore >= 0.7 {
-		// High risk allocation
-		allocation["stocks"] = 0.4
-		allocation["crypto"] = 0.4
-		allocation["bonds"] = 0.1
-		allocation["cash"] = 0.1
-	} else if riskScore >= 0.4 {
-		// Moderate risk allocation
-		allocation["stocks"] = 0.5
-		allocation["crypto"] = 0.2
-		allocation["bonds"] = 0.2
-		allocation["cash"] = 0.1
-	} else {
-		// Low risk allocation
-		allocation["stocks"] = 0.3
-		allocation["crypto"] = 0.1
-		allocation["bonds"] = 0.4
-		allocation["cash"] = 0.2
+	shares := s.aiConfig().RiskAllocations[s.determineRiskCategory(riskScore)]
+	allocation := make(map[string]float64, len(shares))
+	for _, share := range shares {
+		allocation[share.Asset] += share.Share
 	}
-
 	return allocation
 }
 
 func (s *RealTimeMarketService) calculateAssessmentConfidence(user *domain.User, monthlyIncome float64) float64 {
 	confidence := 0.5 // Base confidence
 
-	// More data = higher confidence
-	if user.Age > 0 {
+	// More data = higher confidence; a birth date beats the assumed age
+	if user.BirthDate != nil {
+		confidence += 0.15
+	} else if user.Age > 0 {
 		confidence += 0.1
 	}
+	if user.EmploymentStatus != "" {
+		confidence += 0.05
+	}
 	if monthlyIncome > 0 {
 		confidence += 0.1
 	}
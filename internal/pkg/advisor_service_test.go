@@ -1,11 +1,17 @@
 package pkg
 
 import (
+	"context"
+	"math"
 	"net/http"
 	"net/http/httptest"
+	"sort"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"go-finance-advisor/internal/config"
 	"go-finance-advisor/internal/domain"
 
 	"github.com/stretchr/testify/assert"
@@ -88,7 +94,7 @@ func TestRealTimeMarketService_GetCryptoPrices(t *testing.T) {
 				Transport: &mockTransport{server: server},
 			}
 
-			cryptos, err := service.GetCryptoPrices()
+			cryptos, err := service.GetCryptoPrices(context.Background())
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -175,7 +181,7 @@ func TestRealTimeMarketService_GetStockPrices(t *testing.T) {
 				},
 			}
 
-			stocks, err := service.GetStockPrices(tt.symbols)
+			stocks, err := service.GetStockPrices(context.Background(), tt.symbols)
 
 			assert.NoError(t, err)
 			assert.NotNil(t, stocks)
@@ -184,12 +190,38 @@ func TestRealTimeMarketService_GetStockPrices(t *testing.T) {
 	}
 }
 
+func TestRealTimeMarketService_CancelledContext(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	service := &RealTimeMarketService{
+		client: &http.Client{Timeout: 5 * time.Second, Transport: &mockTransport{server: server}},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := service.GetCryptoPrices(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, err = service.GetStockPrices(ctx, []string{"AAPL", "MSFT"})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, err = service.GetMarketData(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Zero(t, requests, "no request should reach the provider after cancellation")
+}
+
 func TestRealTimeMarketService_AnalyzeMarket(t *testing.T) {
 	service := NewRealTimeMarketService()
 
 	// This test will likely fail due to external API dependencies
 	// In a real scenario, you'd mock the HTTP calls
-	analysis, err := service.AnalyzeMarket()
+	analysis, err := service.AnalyzeMarket(context.Background())
 
 	// We expect this to work or fail gracefully
 	if err == nil {
@@ -238,7 +270,7 @@ func TestRealTimeMarketService_GeneratePersonalizedAdvice(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			service := NewRealTimeMarketService()
-			recommendation, err := service.GeneratePersonalizedAdvice(&tt.user, tt.monthlyIncome)
+			recommendation, err := service.GeneratePersonalizedAdvice(context.Background(), &tt.user, tt.monthlyIncome)
 
 			// This might fail due to external API dependencies
 			if err == nil {
@@ -545,6 +577,48 @@ func TestRealTimeMarketService_PerformAIRiskAssessment(t *testing.T) {
 	}
 }
 
+func TestRealTimeMarketService_PerformAIRiskAssessment_UsesProfile(t *testing.T) {
+	service := NewRealTimeMarketService()
+	birthDate := time.Now().AddDate(-25, 0, -1)
+	single := domain.User{
+		Age: 60, RiskTolerance: "moderate",
+		UserProfile: domain.UserProfile{BirthDate: &birthDate, EmploymentStatus: domain.EmploymentEmployed},
+	}
+	family := single
+	family.Dependents = 3
+	family.EmploymentStatus = domain.EmploymentUnemployed
+
+	singleAssessment, err := service.PerformAIRiskAssessment(&single, 6000, nil)
+	require.NoError(t, err)
+	familyAssessment, err := service.PerformAIRiskAssessment(&family, 6000, nil)
+	require.NoError(t, err)
+
+	// The birth date makes the user 25, not the stored 60
+	assert.InDelta(t, 0.75, singleAssessment.RiskScore, 1e-9)
+	assert.InDelta(t, 0.55, familyAssessment.RiskScore, 1e-9)
+	assert.InDelta(t, 1.0, singleAssessment.ConfidenceScore, 1e-9)
+}
+
+func TestRealTimeMarketService_UsesConfiguredHeuristics(t *testing.T) {
+	service := NewRealTimeMarketService()
+	service.AI = config.Default().AI
+	service.AI.TrendCutoff = 5
+	service.AI.VolatilityMediumCutoff = 1
+	service.AI.HighRiskScore = 0.8
+	service.AI.RiskAllocations = map[string][]config.AllocationShare{
+		"low_risk_stable":       {{Asset: "bonds", Share: 1}},
+		"moderate_risk":         {{Asset: "stocks", Share: 0.6}, {Asset: "bonds", Share: 0.4}},
+		"high_risk_high_reward": {{Asset: "crypto", Share: 1}},
+	}
+	cryptos := []CryptoPrice{{Symbol: "BTC", Change24h: 3}}
+
+	assert.Equal(t, "neutral", service.calculateMarketTrend(cryptos, nil), "3% is below the trend cutoff")
+	assert.Equal(t, "medium", service.calculateVolatility(cryptos, nil))
+	assert.Equal(t, "moderate_risk", service.determineRiskCategory(0.75))
+	assert.Equal(t, map[string]float64{"stocks": 0.6, "bonds": 0.4}, service.generateAIAllocation(0.75))
+	assert.Equal(t, map[string]float64{"crypto": 1}, service.generateAIAllocation(0.9))
+}
+
 func TestRealTimeMarketService_calculateSentimentScore(t *testing.T) {
 	service := NewRealTimeMarketService()
 
@@ -622,3 +696,412 @@ func TestRealTimeMarketService_predictMarketReturn(t *testing.T) {
 	assert.GreaterOrEqual(t, prediction, -100.0)
 	assert.LessOrEqual(t, prediction, 100.0)
 }
+
+func TestRealTimeMarketService_UsesConfiguredProviders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/coins/markets":
+			assert.Equal(t, "cg-key", r.Header.Get("x-cg-demo-api-key"))
+			_, _ = w.Write([]byte(`[{"symbol": "btc", "name": "Bitcoin", "current_price": 45000}]`))
+		case "/query":
+			assert.Equal(t, "av-key", r.URL.Query().Get("apikey"))
+			_, _ = w.Write([]byte(`{"Global Quote": {"05. price": "190.50", "09. change": "1.5",` +
+				` "10. change percent": "0.79%", "06. volume": "1000"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	service := NewRealTimeMarketServiceWithConfig(config.MarketConfig{
+		CoinGeckoBaseURL:    server.URL,
+		CoinGeckoAPIKey:     "cg-key",
+		AlphaVantageBaseURL: server.URL,
+		AlphaVantageAPIKey:  "av-key",
+		RequestTimeout:      config.Duration(time.Second),
+	})
+	assert.Equal(t, time.Second, service.client.Timeout)
+
+	cryptos, err := service.GetCryptoPrices(context.Background())
+	require.NoError(t, err)
+	require.Len(t, cryptos, 1)
+	assert.Equal(t, 45000.0, cryptos[0].Price)
+
+	stocks, err := service.GetStockPrices(context.Background(), []string{"AAPL"})
+	require.NoError(t, err)
+	require.Len(t, stocks, 1)
+	assert.Equal(t, 190.50, stocks[0].Price)
+}
+
+func TestRealTimeMarketService_GetPrices(t *testing.T) {
+	var stockSymbols []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/coins/markets":
+			_, _ = w.Write([]byte(`[{"symbol": "btc", "current_price": 45000}, {"symbol": "eth", "current_price": 3000}]`))
+		case "/query":
+			stockSymbols = append(stockSymbols, r.URL.Query().Get("symbol"))
+			_, _ = w.Write([]byte(`{"Global Quote": {"05. price": "450.00", "09. change": "1.5",` +
+				` "10. change percent": "0.33%", "06. volume": "1000"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	service := NewRealTimeMarketServiceWithConfig(config.MarketConfig{
+		CoinGeckoBaseURL:    server.URL,
+		AlphaVantageBaseURL: server.URL,
+		RequestTimeout:      config.Duration(time.Second),
+	})
+
+	prices, err := service.GetPrices(context.Background(), []string{"btc", "SPY", "spy"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"BTC": 45000, "SPY": 450}, prices)
+	assert.Equal(t, []string{"SPY"}, stockSymbols, "crypto symbols should not be looked up as stocks")
+}
+
+func TestRealTimeMarketService_GetStockPricesConcurrently(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if current := inFlight.Add(1); current > peak.Load() {
+			peak.Store(current)
+		}
+		defer inFlight.Add(-1)
+		time.Sleep(20 * time.Millisecond)
+
+		price := map[string]string{"AAPL": "190", "MSFT": "410", "BAD": "n/a"}[r.URL.Query().Get("symbol")]
+		if price == "" {
+			price = "100"
+		}
+		_, _ = w.Write([]byte(`{"Global Quote": {"05. price": "` + price + `", "09. change": "1",` +
+			` "10. change percent": "0.5%", "06. volume": "1000"}}`))
+	}))
+	defer server.Close()
+
+	symbols := []string{"AAPL", "BAD", "MSFT", "AMZN", "TSLA", "GOOGL", "NVDA", "META"}
+	service := NewRealTimeMarketServiceWithConfig(config.MarketConfig{
+		AlphaVantageBaseURL:   server.URL,
+		RequestTimeout:        config.Duration(time.Second),
+		MaxConcurrentRequests: 3,
+	})
+
+	stocks, err := service.GetStockPrices(context.Background(), symbols)
+	require.NoError(t, err)
+
+	got := make([]string, 0, len(stocks))
+	for _, stock := range stocks {
+		got = append(got, stock.Symbol)
+	}
+	assert.Equal(t, []string{"AAPL", "MSFT", "AMZN", "TSLA", "GOOGL", "NVDA", "META"}, got,
+		"prices keep the order of the symbols and failed ones are skipped")
+	assert.Equal(t, 410.0, stocks[1].Price)
+	assert.LessOrEqual(t, peak.Load(), int32(3))
+	assert.Greater(t, peak.Load(), int32(1), "symbols should be fetched concurrently")
+}
+
+func TestRealTimeMarketService_RateLimitsProvider(t *testing.T) {
+	var mu sync.Mutex
+	var starts []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		starts = append(starts, time.Now())
+		mu.Unlock()
+		_, _ = w.Write([]byte(`{"Global Quote": {"05. price": "100", "09. change": "1",` +
+			` "10. change percent": "0.5%", "06. volume": "1000"}}`))
+	}))
+	defer server.Close()
+
+	interval := 30 * time.Millisecond
+	service := NewRealTimeMarketServiceWithConfig(config.MarketConfig{
+		AlphaVantageBaseURL:      server.URL,
+		RequestTimeout:           config.Duration(time.Second),
+		MaxConcurrentRequests:    4,
+		AlphaVantageRateInterval: config.Duration(interval),
+	})
+
+	stocks, err := service.GetStockPrices(context.Background(), []string{"A", "B", "C", "D"})
+	require.NoError(t, err)
+	assert.Len(t, stocks, 4)
+
+	require.Len(t, starts, 4)
+	sort.Slice(starts, func(i, j int) bool { return starts[i].Before(starts[j]) })
+	// Allow for timer granularity
+	assert.GreaterOrEqual(t, starts[3].Sub(starts[0]), 3*interval-5*time.Millisecond)
+}
+
+func TestRealTimeMarketService_AnalyzeMarketFetchesInParallel(t *testing.T) {
+	stockRequested := make(chan struct{})
+	var once sync.Once
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/coins/markets":
+			// Only answers once stock prices are being fetched too
+			select {
+			case <-stockRequested:
+			case <-time.After(2 * time.Second):
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = w.Write([]byte(`[{"symbol": "btc", "name": "Bitcoin", "current_price": 45000, "price_change_percentage_24h": 2}]`))
+		case "/query":
+			once.Do(func() { close(stockRequested) })
+			_, _ = w.Write([]byte(`{"Global Quote": {"05. price": "100", "09. change": "1",` +
+				` "10. change percent": "0.5%", "06. volume": "1000"}}`))
+		}
+	}))
+	defer server.Close()
+
+	service := NewRealTimeMarketServiceWithConfig(config.MarketConfig{
+		CoinGeckoBaseURL:    server.URL,
+		AlphaVantageBaseURL: server.URL,
+		RequestTimeout:      config.Duration(5 * time.Second),
+	})
+
+	analysis, err := service.AnalyzeMarket(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, analysis.Cryptos, 1)
+	assert.Len(t, analysis.Stocks, len(DefaultStockSymbols))
+}
+
+func TestRealTimeMarketService_AnalyzeMarketReportsFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`not json`))
+	}))
+	defer server.Close()
+
+	service := NewRealTimeMarketServiceWithConfig(config.MarketConfig{
+		CoinGeckoBaseURL:    server.URL,
+		AlphaVantageBaseURL: server.URL,
+		RequestTimeout:      config.Duration(time.Second),
+	})
+
+	_, err := service.AnalyzeMarket(context.Background())
+	assert.ErrorContains(t, err, "failed to fetch crypto data")
+}
+
+func TestRealTimeMarketService_RetriesFailedRequests(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch requests.Add(1) {
+		case 1:
+			w.WriteHeader(http.StatusBadGateway)
+		case 2:
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			_, _ = w.Write([]byte(`[{"symbol": "btc", "current_price": 45000}]`))
+		}
+	}))
+	defer server.Close()
+
+	service := NewRealTimeMarketServiceWithConfig(config.MarketConfig{
+		CoinGeckoBaseURL: server.URL,
+		RequestTimeout:   config.Duration(time.Second),
+		MaxRetries:       2,
+		RetryBackoff:     config.Duration(time.Millisecond),
+	})
+
+	cryptos, err := service.GetCryptoPrices(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, cryptos, 1)
+	assert.Equal(t, int32(3), requests.Load())
+
+	t.Run("client errors are not retried", func(t *testing.T) {
+		requests.Store(0)
+		badRequest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer badRequest.Close()
+
+		service := NewRealTimeMarketServiceWithConfig(config.MarketConfig{
+			CoinGeckoBaseURL: badRequest.URL,
+			RequestTimeout:   config.Duration(time.Second),
+			MaxRetries:       2,
+		})
+		_, err := service.GetCryptoPrices(context.Background())
+		assert.ErrorContains(t, err, "provider returned 400")
+		assert.Equal(t, int32(1), requests.Load())
+	})
+}
+
+func TestRealTimeMarketService_ProviderTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	service := NewRealTimeMarketServiceWithConfig(config.MarketConfig{
+		CoinGeckoBaseURL: server.URL,
+		RequestTimeout:   config.Duration(5 * time.Second),
+		CoinGeckoTimeout: config.Duration(20 * time.Millisecond),
+	})
+
+	started := time.Now()
+	_, err := service.GetCryptoPrices(context.Background())
+	assert.Error(t, err)
+	assert.Less(t, time.Since(started), 500*time.Millisecond)
+}
+
+func TestRealTimeMarketService_CircuitBreaker(t *testing.T) {
+	var down atomic.Bool
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		switch r.URL.Path {
+		case "/coins/markets":
+			_, _ = w.Write([]byte(`[{"symbol": "btc", "name": "Bitcoin", "current_price": 45000}]`))
+		case "/query":
+			_, _ = w.Write([]byte(`{"Global Quote": {"05. price": "190.50", "09. change": "1.5",` +
+				` "10. change percent": "0.79%", "06. volume": "1000"}}`))
+		}
+	}))
+	defer server.Close()
+
+	service := NewRealTimeMarketServiceWithConfig(config.MarketConfig{
+		CoinGeckoBaseURL:    server.URL,
+		AlphaVantageBaseURL: server.URL,
+		RequestTimeout:      config.Duration(time.Second),
+		BreakerThreshold:    2,
+		BreakerCooldown:     config.Duration(time.Hour),
+	})
+	ctx := context.Background()
+
+	_, err := service.GetCryptoPrices(ctx)
+	require.NoError(t, err)
+	_, err = service.GetStockPrices(ctx, []string{"AAPL"})
+	require.NoError(t, err)
+
+	down.Store(true)
+	for range 2 {
+		cryptos, err := service.GetCryptoPrices(ctx)
+		require.NoError(t, err)
+		require.Len(t, cryptos, 1)
+		assert.True(t, cryptos[0].Stale, "the last known prices are served while the provider fails")
+		assert.Equal(t, 45000.0, cryptos[0].Price)
+	}
+
+	requests.Store(0)
+	cryptos, err := service.GetCryptoPrices(ctx)
+	require.NoError(t, err)
+	assert.True(t, cryptos[0].Stale)
+	assert.Zero(t, requests.Load(), "an open breaker keeps requests from the provider")
+
+	t.Run("providers are broken separately", func(t *testing.T) {
+		stocks, err := service.GetStockPrices(ctx, []string{"AAPL", "MSFT"})
+		require.NoError(t, err)
+		require.Len(t, stocks, 1, "symbols without a last known price are skipped")
+		assert.True(t, stocks[0].Stale)
+		assert.NotZero(t, requests.Load())
+	})
+
+	t.Run("the analysis is flagged stale", func(t *testing.T) {
+		analysis, err := service.AnalyzeMarket(ctx)
+		require.NoError(t, err)
+		assert.True(t, analysis.Stale)
+	})
+
+	t.Run("stale prices are not reported as current", func(t *testing.T) {
+		prices, err := service.GetPrices(ctx, []string{"BTC", "AAPL"})
+		require.NoError(t, err)
+		assert.Empty(t, prices)
+	})
+
+	t.Run("unavailable without earlier prices", func(t *testing.T) {
+		fresh := NewRealTimeMarketServiceWithConfig(config.MarketConfig{
+			CoinGeckoBaseURL: server.URL,
+			RequestTimeout:   config.Duration(time.Second),
+			BreakerThreshold: 1,
+			BreakerCooldown:  config.Duration(time.Hour),
+		})
+		_, err := fresh.GetCryptoPrices(ctx)
+		require.Error(t, err)
+		_, err = fresh.GetCryptoPrices(ctx)
+		assert.ErrorIs(t, err, ErrMarketUnavailable)
+	})
+}
+
+func TestCircuitBreaker_ClosesAfterCooldown(t *testing.T) {
+	breaker := newCircuitBreaker(1, 10*time.Millisecond)
+	breaker.Failure()
+	assert.False(t, breaker.Allow())
+
+	time.Sleep(20 * time.Millisecond)
+	assert.True(t, breaker.Allow(), "a trial request is let through after the cooldown")
+	breaker.Success()
+	assert.True(t, breaker.Allow())
+}
+
+func TestRealTimeMarketService_SearchSymbols(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "SYMBOL_SEARCH", r.URL.Query().Get("function"))
+		assert.Equal(t, "appl", r.URL.Query().Get("keywords"))
+		_, _ = w.Write([]byte(`{"bestMatches": [
+			{"1. symbol": "AAPL", "2. name": "Apple Inc", "3. type": "Equity", "4. region": "United States",
+			 "8. currency": "USD", "9. matchScore": "0.8889"},
+			{"1. symbol": "", "2. name": "Broken"}
+		]}`))
+	}))
+	defer server.Close()
+
+	service := NewRealTimeMarketServiceWithConfig(config.MarketConfig{
+		AlphaVantageBaseURL: server.URL,
+		RequestTimeout:      config.Duration(time.Second),
+	})
+
+	matches, err := service.SearchSymbols(context.Background(), "appl")
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, SymbolMatch{
+		Symbol: "AAPL", Name: "Apple Inc", Type: "Equity", Region: "United States", Currency: "USD", MatchScore: 0.8889,
+	}, matches[0])
+}
+
+func TestPrioritizeStockSymbols(t *testing.T) {
+	assert.Equal(t, []string{"NVDA", "MSFT", "AAPL", "GOOGL", "TSLA"}, PrioritizeStockSymbols([]string{"nvda", "MSFT"}))
+	assert.Equal(t, DefaultStockSymbols, PrioritizeStockSymbols(nil))
+
+	long := []string{"A", "B", "C", "D", "E", "F"}
+	assert.Equal(t, long, PrioritizeStockSymbols(long), "every watchlisted symbol is kept")
+}
+
+func TestPrioritizeCryptos(t *testing.T) {
+	cryptos := []CryptoPrice{{Symbol: "btc"}, {Symbol: "eth"}, {Symbol: "sol"}, {Symbol: "ada"}}
+
+	prioritized := PrioritizeCryptos(cryptos, []string{"ADA", "SOL"})
+
+	var symbols []string
+	for _, crypto := range prioritized {
+		symbols = append(symbols, crypto.Symbol)
+	}
+	assert.Equal(t, []string{"sol", "ada", "btc", "eth"}, symbols)
+}
+
+func FuzzParseGlobalQuote(f *testing.F) {
+	f.Add("234.2200", "4.5300", "1.9722%", "3114520")
+	f.Add("190.50", "-1.5", "-0.78%", "0")
+	f.Add("12abc", "1", "1%", "1")
+	f.Add("NaN", "1", "1%%", "1e3")
+	f.Add("", "", "", "")
+
+	f.Fuzz(func(t *testing.T, price, change, changePct, volume string) {
+		quote := map[string]string{"05. price": price, "09. change": change, "10. change percent": changePct, "06. volume": volume}
+		stock, err := parseGlobalQuote("IBM", quote)
+		if err != nil {
+			require.ErrorIs(t, err, ErrUnexpectedResponse)
+			return
+		}
+		assert.Equal(t, "IBM", stock.Symbol)
+		assert.Positive(t, stock.Price)
+		assert.False(t, math.IsInf(stock.Change, 0) || math.IsNaN(stock.Change))
+		assert.False(t, math.IsInf(stock.ChangePct, 0) || math.IsNaN(stock.ChangePct))
+		assert.GreaterOrEqual(t, stock.Volume, int64(0))
+	})
+}
@@ -0,0 +1,522 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+)
+
+// BankProvider links users' bank accounts through an aggregator and reads
+// their transactions. Providers return domain.ErrBankReauthRequired once the
+// bank no longer grants access.
+type BankProvider interface {
+	// StartLink begins linking a bank for the user. institutionID and
+	// redirectURL are only used by providers that need them.
+	StartLink(ctx context.Context, userRef, institutionID, redirectURL string) (*domain.BankLinkSession, error)
+	// CompleteLink exchanges the token the link flow ended with for access to the accounts
+	CompleteLink(ctx context.Context, token string) (*domain.BankConnection, error)
+	// Transactions returns a page of the account's transaction changes since
+	// cursor; an empty cursor starts at the oldest history the provider has
+	Transactions(ctx context.Context, accessToken, accountID, cursor string) (*domain.BankFeed, error)
+	// Revoke ends the access granted to the connection's accounts
+	Revoke(ctx context.Context, accessToken string) error
+}
+
+// NewBankProviders returns the providers whose credentials are set, by name
+func NewBankProviders(plaidURL, plaidClientID, plaidSecret, goCardlessURL, goCardlessID, goCardlessKey string) map[string]BankProvider {
+	providers := map[string]BankProvider{}
+	if plaidClientID != "" && plaidSecret != "" {
+		providers[domain.BankProviderPlaid] = NewPlaidProvider(plaidURL, plaidClientID, plaidSecret)
+	}
+	if goCardlessID != "" && goCardlessKey != "" {
+		providers[domain.BankProviderGoCardless] = NewGoCardlessProvider(goCardlessURL, goCardlessID, goCardlessKey)
+	}
+	return providers
+}
+
+// bankHTTPTimeout bounds each request to a bank provider
+const bankHTTPTimeout = 30 * time.Second
+
+// plaidCountryCodes are the countries Plaid Link offers banks of
+var plaidCountryCodes = []string{"US", "CA", "GB", "IE", "FR", "ES", "NL", "DE"}
+
+// PlaidProvider links banks through Plaid Link and pulls transactions with
+// Plaid's cursor based /transactions/sync
+type PlaidProvider struct {
+	BaseURL  string
+	ClientID string
+	Secret   string
+	client   *http.Client
+}
+
+// NewPlaidProvider creates a provider for the Plaid environment at baseURL,
+// such as https://sandbox.plaid.com
+func NewPlaidProvider(baseURL, clientID, secret string) *PlaidProvider {
+	return &PlaidProvider{
+		BaseURL:  strings.TrimRight(baseURL, "/"),
+		ClientID: clientID,
+		Secret:   secret,
+		client:   &http.Client{Timeout: bankHTTPTimeout},
+	}
+}
+
+// StartLink creates a Link token the client opens Plaid Link with
+func (p *PlaidProvider) StartLink(ctx context.Context, userRef, _, redirectURL string) (*domain.BankLinkSession, error) {
+	request := map[string]any{
+		"client_name":   "Go Finance Advisor",
+		"user":          map[string]string{"client_user_id": userRef},
+		"products":      []string{"transactions"},
+		"country_codes": plaidCountryCodes,
+		"language":      "en",
+	}
+	if redirectURL != "" {
+		request["redirect_uri"] = redirectURL
+	}
+	var response struct {
+		LinkToken  string    `json:"link_token"`
+		Expiration time.Time `json:"expiration"`
+	}
+	if err := p.post(ctx, "/link/token/create", request, &response); err != nil {
+		return nil, err
+	}
+	return &domain.BankLinkSession{
+		Provider: domain.BankProviderPlaid, Token: response.LinkToken, ExpiresAt: &response.Expiration,
+	}, nil
+}
+
+// CompleteLink exchanges the public token Plaid Link returned for an access
+// token and lists the item's accounts
+func (p *PlaidProvider) CompleteLink(ctx context.Context, publicToken string) (*domain.BankConnection, error) {
+	var exchange struct {
+		AccessToken string `json:"access_token"`
+		ItemID      string `json:"item_id"`
+	}
+	if err := p.post(ctx, "/item/public_token/exchange", map[string]any{"public_token": publicToken}, &exchange); err != nil {
+		return nil, err
+	}
+
+	var accounts struct {
+		Accounts []struct {
+			AccountID string `json:"account_id"`
+			Name      string `json:"name"`
+			Mask      string `json:"mask"`
+			Balances  struct {
+				Currency string `json:"iso_currency_code"`
+			} `json:"balances"`
+		} `json:"accounts"`
+		Item struct {
+			InstitutionID string `json:"institution_id"`
+		} `json:"item"`
+	}
+	if err := p.post(ctx, "/accounts/get", map[string]any{"access_token": exchange.AccessToken}, &accounts); err != nil {
+		return nil, err
+	}
+
+	connection := &domain.BankConnection{
+		ConnectionID: exchange.ItemID,
+		AccessToken:  exchange.AccessToken,
+		Institution:  p.institutionName(ctx, accounts.Item.InstitutionID),
+	}
+	for _, account := range accounts.Accounts {
+		connection.Accounts = append(connection.Accounts, domain.BankAccount{
+			ExternalID: account.AccountID, Name: account.Name, Mask: account.Mask, Currency: account.Balances.Currency,
+		})
+	}
+	return connection, nil
+}
+
+// institutionName looks up the name of a Plaid institution, falling back to its ID
+func (p *PlaidProvider) institutionName(ctx context.Context, institutionID string) string {
+	if institutionID == "" {
+		return ""
+	}
+	var response struct {
+		Institution struct {
+			Name string `json:"name"`
+		} `json:"institution"`
+	}
+	request := map[string]any{"institution_id": institutionID, "country_codes": plaidCountryCodes}
+	if err := p.post(ctx, "/institutions/get_by_id", request, &response); err != nil || response.Institution.Name == "" {
+		return institutionID
+	}
+	return response.Institution.Name
+}
+
+// plaidTransaction is a transaction of /transactions/sync. Plaid signs
+// amounts from the account's view of spending: money out is positive.
+type plaidTransaction struct {
+	TransactionID string  `json:"transaction_id"`
+	AccountID     string  `json:"account_id"`
+	Amount        float64 `json:"amount"`
+	Date          string  `json:"date"`
+	Name          string  `json:"name"`
+	MerchantName  string  `json:"merchant_name"`
+	Pending       bool    `json:"pending"`
+	Category      struct {
+		Primary string `json:"primary"`
+	} `json:"personal_finance_category"`
+}
+
+// Transactions pulls the account's changes through /transactions/sync
+func (p *PlaidProvider) Transactions(ctx context.Context, accessToken, accountID, cursor string) (*domain.BankFeed, error) {
+	request := map[string]any{
+		"access_token": accessToken,
+		"count":        500,
+		"options":      map[string]any{"account_id": accountID},
+	}
+	if cursor != "" {
+		request["cursor"] = cursor
+	}
+	var response struct {
+		Added    []plaidTransaction `json:"added"`
+		Modified []plaidTransaction `json:"modified"`
+		Removed  []struct {
+			TransactionID string `json:"transaction_id"`
+			AccountID     string `json:"account_id"`
+		} `json:"removed"`
+		NextCursor string `json:"next_cursor"`
+		HasMore    bool   `json:"has_more"`
+	}
+	if err := p.post(ctx, "/transactions/sync", request, &response); err != nil {
+		return nil, err
+	}
+
+	feed := &domain.BankFeed{Cursor: response.NextCursor, HasMore: response.HasMore}
+	var err error
+	if feed.Added, err = plaidFeedTransactions(response.Added, accountID); err != nil {
+		return nil, err
+	}
+	if feed.Modified, err = plaidFeedTransactions(response.Modified, accountID); err != nil {
+		return nil, err
+	}
+	for _, removed := range response.Removed {
+		if removed.AccountID == "" || removed.AccountID == accountID {
+			feed.Removed = append(feed.Removed, removed.TransactionID)
+		}
+	}
+	return feed, nil
+}
+
+func plaidFeedTransactions(transactions []plaidTransaction, accountID string) ([]domain.BankFeedTransaction, error) {
+	var feed []domain.BankFeedTransaction
+	for _, tx := range transactions {
+		if tx.AccountID != accountID {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", tx.Date)
+		if err != nil {
+			return nil, fmt.Errorf("plaid transaction %s has invalid date %q", tx.TransactionID, tx.Date)
+		}
+		feed = append(feed, domain.BankFeedTransaction{
+			ExternalID:  tx.TransactionID,
+			Date:        date,
+			Description: firstNonEmpty(tx.MerchantName, tx.Name),
+			Category:    plaidCategoryName(tx.Category.Primary),
+			Amount:      -domain.NewMoney(tx.Amount),
+			Pending:     tx.Pending,
+		})
+	}
+	return feed, nil
+}
+
+// plaidCategoryName turns Plaid's FOOD_AND_DRINK style categories into
+// names such as "Food And Drink"
+func plaidCategoryName(category string) string {
+	words := strings.Fields(strings.ReplaceAll(strings.ToLower(category), "_", " "))
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// Revoke removes the Plaid item, ending access to all of its accounts
+func (p *PlaidProvider) Revoke(ctx context.Context, accessToken string) error {
+	return p.post(ctx, "/item/remove", map[string]any{"access_token": accessToken}, nil)
+}
+
+// post calls a Plaid endpoint with the client credentials added to the request
+func (p *PlaidProvider) post(ctx context.Context, path string, request map[string]any, response any) error {
+	request["client_id"] = p.ClientID
+	request["secret"] = p.Secret
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.BaseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create plaid request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("plaid request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read plaid response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var plaidErr struct {
+			ErrorCode    string `json:"error_code"`
+			ErrorMessage string `json:"error_message"`
+		}
+		_ = json.Unmarshal(data, &plaidErr)
+		switch plaidErr.ErrorCode {
+		case "ITEM_LOGIN_REQUIRED", "PENDING_EXPIRATION", "ACCESS_NOT_GRANTED", "ITEM_NOT_FOUND":
+			return domain.ErrBankReauthRequired
+		}
+		return fmt.Errorf("plaid %s returned status %d: %s %s", path, resp.StatusCode, plaidErr.ErrorCode, plaidErr.ErrorMessage)
+	}
+	if response == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, response); err != nil {
+		return fmt.Errorf("failed to decode plaid response: %w", err)
+	}
+	return nil
+}
+
+// GoCardlessProvider links European banks through GoCardless Bank Account
+// Data (formerly Nordigen). A link is a requisition; its ID serves as the
+// access token, while API calls authenticate with a token obtained from the
+// secret ID and key.
+type GoCardlessProvider struct {
+	BaseURL   string
+	SecretID  string
+	SecretKey string
+	client    *http.Client
+
+	mu           sync.Mutex
+	accessToken  string
+	tokenExpires time.Time
+}
+
+// NewGoCardlessProvider creates a provider for the API at baseURL, such as
+// https://bankaccountdata.gocardless.com
+func NewGoCardlessProvider(baseURL, secretID, secretKey string) *GoCardlessProvider {
+	return &GoCardlessProvider{
+		BaseURL:   strings.TrimRight(baseURL, "/"),
+		SecretID:  secretID,
+		SecretKey: secretKey,
+		client:    &http.Client{Timeout: bankHTTPTimeout},
+	}
+}
+
+// errGoCardlessAccess marks account requests the API refused, which happens
+// once the user's consent has expired
+var errGoCardlessAccess = errors.New("gocardless refused access")
+
+// StartLink creates a requisition for the institution; the user is sent to
+// its URL and returns to redirectURL, after which the link is completed with
+// the requisition ID
+func (p *GoCardlessProvider) StartLink(ctx context.Context, userRef, institutionID, redirectURL string) (*domain.BankLinkSession, error) {
+	if institutionID == "" || redirectURL == "" {
+		return nil, errors.New("gocardless links need an institution_id and a redirect_url")
+	}
+	request := map[string]string{
+		"institution_id": institutionID,
+		"redirect":       redirectURL,
+		"reference":      fmt.Sprintf("%s-%d", userRef, time.Now().UnixNano()),
+	}
+	var response struct {
+		ID   string `json:"id"`
+		Link string `json:"link"`
+	}
+	if err := p.call(ctx, http.MethodPost, "/api/v2/requisitions/", request, &response); err != nil {
+		return nil, err
+	}
+	return &domain.BankLinkSession{Provider: domain.BankProviderGoCardless, Token: response.ID, URL: response.Link}, nil
+}
+
+// CompleteLink reads the accounts the user granted access to in the requisition
+func (p *GoCardlessProvider) CompleteLink(ctx context.Context, requisitionID string) (*domain.BankConnection, error) {
+	var requisition struct {
+		ID            string   `json:"id"`
+		Status        string   `json:"status"`
+		InstitutionID string   `json:"institution_id"`
+		Accounts      []string `json:"accounts"`
+	}
+	if err := p.call(ctx, http.MethodGet, "/api/v2/requisitions/"+url.PathEscape(requisitionID)+"/", nil, &requisition); err != nil {
+		return nil, err
+	}
+	if requisition.Status != "LN" {
+		return nil, fmt.Errorf("gocardless requisition is %s, not linked", requisition.Status)
+	}
+
+	connection := &domain.BankConnection{
+		ConnectionID: requisition.ID, AccessToken: requisition.ID, Institution: requisition.InstitutionID,
+	}
+	for _, accountID := range requisition.Accounts {
+		var details struct {
+			Account struct {
+				IBAN     string `json:"iban"`
+				Currency string `json:"currency"`
+				Name     string `json:"name"`
+				Product  string `json:"product"`
+			} `json:"account"`
+		}
+		if err := p.call(ctx, http.MethodGet, "/api/v2/accounts/"+url.PathEscape(accountID)+"/details/", nil, &details); err != nil {
+			return nil, err
+		}
+		mask := details.Account.IBAN
+		if len(mask) > 4 {
+			mask = mask[len(mask)-4:]
+		}
+		connection.Accounts = append(connection.Accounts, domain.BankAccount{
+			ExternalID: accountID,
+			Name:       firstNonEmpty(details.Account.Name, details.Account.Product, "Account"),
+			Mask:       mask,
+			Currency:   details.Account.Currency,
+		})
+	}
+	return connection, nil
+}
+
+// Transactions reads the account's booked transactions since the date in
+// cursor. The API has no change feed, so the day of the cursor is read again
+// and the caller recognises what it already has by ID.
+func (p *GoCardlessProvider) Transactions(ctx context.Context, _, accountID, cursor string) (*domain.BankFeed, error) {
+	path := "/api/v2/accounts/" + url.PathEscape(accountID) + "/transactions/"
+	if cursor != "" {
+		path += "?date_from=" + url.QueryEscape(cursor)
+	}
+	var response struct {
+		Transactions struct {
+			Booked []struct {
+				TransactionID         string `json:"transactionId"`
+				InternalTransactionID string `json:"internalTransactionId"`
+				BookingDate           string `json:"bookingDate"`
+				Amount                struct {
+					Amount string `json:"amount"`
+				} `json:"transactionAmount"`
+				CreditorName string `json:"creditorName"`
+				DebtorName   string `json:"debtorName"`
+				Remittance   string `json:"remittanceInformationUnstructured"`
+			} `json:"booked"`
+		} `json:"transactions"`
+	}
+	err := p.call(ctx, http.MethodGet, path, nil, &response)
+	if errors.Is(err, errGoCardlessAccess) {
+		return nil, domain.ErrBankReauthRequired
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	feed := &domain.BankFeed{Cursor: cursor}
+	for _, tx := range response.Transactions.Booked {
+		date, err := time.Parse("2006-01-02", tx.BookingDate)
+		if err != nil {
+			return nil, fmt.Errorf("gocardless transaction has invalid booking date %q", tx.BookingDate)
+		}
+		amount, err := domain.ParseMoney(tx.Amount.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("gocardless transaction has invalid amount %q", tx.Amount.Amount)
+		}
+		feed.Added = append(feed.Added, domain.BankFeedTransaction{
+			ExternalID:  firstNonEmpty(tx.TransactionID, tx.InternalTransactionID),
+			Date:        date,
+			Description: firstNonEmpty(tx.CreditorName, tx.DebtorName, tx.Remittance),
+			Amount:      amount,
+		})
+		if tx.BookingDate > feed.Cursor {
+			feed.Cursor = tx.BookingDate
+		}
+	}
+	return feed, nil
+}
+
+// Revoke deletes the requisition, ending access to its accounts
+func (p *GoCardlessProvider) Revoke(ctx context.Context, requisitionID string) error {
+	return p.call(ctx, http.MethodDelete, "/api/v2/requisitions/"+url.PathEscape(requisitionID)+"/", nil, nil)
+}
+
+// token returns an API access token, obtaining a new one when it expires
+func (p *GoCardlessProvider) token(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.accessToken != "" && time.Now().Before(p.tokenExpires) {
+		return p.accessToken, nil
+	}
+
+	var response struct {
+		Access        string `json:"access"`
+		AccessExpires int    `json:"access_expires"`
+	}
+	request := map[string]string{"secret_id": p.SecretID, "secret_key": p.SecretKey}
+	if err := p.send(ctx, http.MethodPost, "/api/v2/token/new/", "", request, &response); err != nil {
+		return "", err
+	}
+	p.accessToken = response.Access
+	// Renew a minute early so tokens do not expire mid-request
+	p.tokenExpires = time.Now().Add(time.Duration(response.AccessExpires)*time.Second - time.Minute)
+	return p.accessToken, nil
+}
+
+func (p *GoCardlessProvider) call(ctx context.Context, method, path string, request, response any) error {
+	token, err := p.token(ctx)
+	if err != nil {
+		return err
+	}
+	return p.send(ctx, method, path, token, request, response)
+}
+
+func (p *GoCardlessProvider) send(ctx context.Context, method, path, token string, request, response any) error {
+	var body io.Reader
+	if request != nil {
+		data, err := json.Marshal(request)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, p.BaseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create gocardless request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if request != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("gocardless request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read gocardless response: %w", err)
+	}
+
+	switch {
+	case token != "" && (resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden ||
+		resp.StatusCode == http.StatusConflict):
+		return fmt.Errorf("%w: status %d: %s", errGoCardlessAccess, resp.StatusCode, strings.TrimSpace(string(data)))
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return fmt.Errorf("gocardless %s returned status %d: %s", path, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if response == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, response); err != nil {
+		return fmt.Errorf("failed to decode gocardless response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlaidProvider(t *testing.T) {
+	var requests []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		requests = append(requests, body)
+		assert.Equal(t, "client", body["client_id"])
+		assert.Equal(t, "secret", body["secret"])
+
+		switch r.URL.Path {
+		case "/link/token/create":
+			_, _ = w.Write([]byte(`{"link_token":"link-sandbox-1","expiration":"2024-03-15T12:00:00Z"}`))
+		case "/item/public_token/exchange":
+			_, _ = w.Write([]byte(`{"access_token":"access-sandbox-1","item_id":"item-1"}`))
+		case "/accounts/get":
+			_, _ = w.Write([]byte(`{"accounts":[{"account_id":"acc-1","name":"Checking","mask":"0000",
+				"balances":{"iso_currency_code":"USD"}}],"item":{"institution_id":"ins_1"}}`))
+		case "/institutions/get_by_id":
+			_, _ = w.Write([]byte(`{"institution":{"name":"First Platypus Bank"}}`))
+		case "/transactions/sync":
+			if body["access_token"] == "expired" {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error_type":"ITEM_ERROR","error_code":"ITEM_LOGIN_REQUIRED"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"added":[
+				{"transaction_id":"tx-1","account_id":"acc-1","amount":12.5,"date":"2024-03-14","name":"STARBUCKS 123",
+				 "merchant_name":"Starbucks","personal_finance_category":{"primary":"FOOD_AND_DRINK"}},
+				{"transaction_id":"tx-2","account_id":"acc-1","amount":-1500,"date":"2024-03-15","name":"ACME PAYROLL"},
+				{"transaction_id":"tx-3","account_id":"acc-2","amount":5,"date":"2024-03-15","name":"Other account"}
+			],"modified":[],"removed":[{"transaction_id":"tx-0","account_id":"acc-1"}],
+			"next_cursor":"cursor-2","has_more":false}`))
+		case "/item/remove":
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	provider := NewPlaidProvider(server.URL+"/", "client", "secret")
+	ctx := context.Background()
+
+	session, err := provider.StartLink(ctx, "user-1", "", "https://app.example.com/oauth")
+	require.NoError(t, err)
+	assert.Equal(t, "link-sandbox-1", session.Token)
+	assert.Equal(t, "https://app.example.com/oauth", requests[0]["redirect_uri"])
+
+	connection, err := provider.CompleteLink(ctx, "public-sandbox-1")
+	require.NoError(t, err)
+	assert.Equal(t, "item-1", connection.ConnectionID)
+	assert.Equal(t, "access-sandbox-1", connection.AccessToken)
+	assert.Equal(t, "First Platypus Bank", connection.Institution)
+	assert.Equal(t, []domain.BankAccount{{ExternalID: "acc-1", Name: "Checking", Mask: "0000", Currency: "USD"}}, connection.Accounts)
+
+	feed, err := provider.Transactions(ctx, "access-sandbox-1", "acc-1", "cursor-1")
+	require.NoError(t, err)
+	assert.Equal(t, "cursor-1", requests[len(requests)-1]["cursor"])
+	assert.Equal(t, "cursor-2", feed.Cursor)
+	require.Len(t, feed.Added, 2)
+	assert.Equal(t, "Starbucks", feed.Added[0].Description)
+	assert.Equal(t, "Food And Drink", feed.Added[0].Category)
+	assert.Equal(t, domain.NewMoney(-12.5), feed.Added[0].Amount, "money out is negative")
+	assert.Equal(t, domain.NewMoney(1500), feed.Added[1].Amount)
+	assert.Equal(t, []string{"tx-0"}, feed.Removed)
+
+	_, err = provider.Transactions(ctx, "expired", "acc-1", "")
+	assert.ErrorIs(t, err, domain.ErrBankReauthRequired)
+
+	assert.NoError(t, provider.Revoke(ctx, "access-sandbox-1"))
+}
+
+func TestGoCardlessProvider(t *testing.T) {
+	tokenRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2/token/new/" {
+			tokenRequests++
+			_, _ = w.Write([]byte(`{"access":"api-token","access_expires":86400}`))
+			return
+		}
+		assert.Equal(t, "Bearer api-token", r.Header.Get("Authorization"))
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v2/requisitions/":
+			_, _ = w.Write([]byte(`{"id":"req-1","link":"https://ob.gocardless.com/psd2/start/req-1"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v2/requisitions/req-1/":
+			_, _ = w.Write([]byte(`{"id":"req-1","status":"LN","institution_id":"REVOLUT_REVOGB21","accounts":["acc-1"]}`))
+		case r.URL.Path == "/api/v2/accounts/acc-1/details/":
+			_, _ = w.Write([]byte(`{"account":{"iban":"GB33BUKB20201555555555","currency":"GBP","name":"Main"}}`))
+		case r.URL.Path == "/api/v2/accounts/acc-1/transactions/":
+			assert.Equal(t, "2024-03-01", r.URL.Query().Get("date_from"))
+			_, _ = w.Write([]byte(`{"transactions":{"booked":[
+				{"transactionId":"g-1","bookingDate":"2024-03-02","transactionAmount":{"amount":"-4.20","currency":"GBP"},
+				 "creditorName":"Pret"},
+				{"internalTransactionId":"g-2","bookingDate":"2024-03-05","transactionAmount":{"amount":"2000.00","currency":"GBP"},
+				 "debtorName":"ACME Ltd"}
+			],"pending":[]}}`))
+		case r.URL.Path == "/api/v2/accounts/acc-expired/transactions/":
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"summary":"Access has expired or it has been revoked"}`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/api/v2/requisitions/req-1/":
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	provider := NewGoCardlessProvider(server.URL, "id", "key")
+	ctx := context.Background()
+
+	_, err := provider.StartLink(ctx, "user-1", "", "")
+	assert.Error(t, err, "an institution and redirect are required")
+
+	session, err := provider.StartLink(ctx, "user-1", "REVOLUT_REVOGB21", "https://app.example.com/banks")
+	require.NoError(t, err)
+	assert.Equal(t, "req-1", session.Token)
+	assert.Equal(t, "https://ob.gocardless.com/psd2/start/req-1", session.URL)
+
+	connection, err := provider.CompleteLink(ctx, "req-1")
+	require.NoError(t, err)
+	assert.Equal(t, "req-1", connection.AccessToken)
+	assert.Equal(t, []domain.BankAccount{{ExternalID: "acc-1", Name: "Main", Mask: "5555", Currency: "GBP"}}, connection.Accounts)
+
+	feed, err := provider.Transactions(ctx, "req-1", "acc-1", "2024-03-01")
+	require.NoError(t, err)
+	require.Len(t, feed.Added, 2)
+	assert.Equal(t, "Pret", feed.Added[0].Description)
+	assert.Equal(t, domain.NewMoney(-4.2), feed.Added[0].Amount)
+	assert.Equal(t, "g-2", feed.Added[1].ExternalID)
+	assert.Equal(t, "2024-03-05", feed.Cursor)
+	assert.False(t, feed.HasMore)
+
+	_, err = provider.Transactions(ctx, "req-1", "acc-expired", "")
+	assert.ErrorIs(t, err, domain.ErrBankReauthRequired)
+
+	assert.NoError(t, provider.Revoke(ctx, "req-1"))
+	assert.Equal(t, 1, tokenRequests, "the API token is reused until it expires")
+}
+
+func TestNewBankProviders(t *testing.T) {
+	assert.Empty(t, NewBankProviders("https://sandbox.plaid.com", "", "", "https://gc", "", ""))
+
+	providers := NewBankProviders("https://sandbox.plaid.com", "client", "secret", "https://gc", "id", "key")
+	assert.IsType(t, &PlaidProvider{}, providers[domain.BankProviderPlaid])
+	assert.IsType(t, &GoCardlessProvider{}, providers[domain.BankProviderGoCardless])
+}
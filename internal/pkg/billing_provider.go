@@ -0,0 +1,283 @@
+package pkg
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+)
+
+// BillingProvider sells the premium subscription and reports what users
+// were billed
+type BillingProvider interface {
+	// CreateCheckout starts a hosted checkout of a subscription
+	CreateCheckout(ctx context.Context, checkout CheckoutRequest) (*domain.BillingSession, error)
+	// CreatePortal opens the hosted portal the customer manages their subscription in
+	CreatePortal(ctx context.Context, customerID, returnURL string) (*domain.BillingSession, error)
+	// Invoices lists the customer's latest invoices, newest first
+	Invoices(ctx context.Context, customerID string, limit int) ([]domain.Invoice, error)
+	// ParseWebhook checks the signature of a webhook payload and decodes its
+	// event; unsigned payloads return domain.ErrInvalidWebhookSignature
+	ParseWebhook(payload []byte, signature string) (*domain.BillingEvent, error)
+}
+
+// CheckoutRequest describes the subscription a user checks out. CustomerID
+// is empty for users who never subscribed; Email then prefills checkout.
+type CheckoutRequest struct {
+	UserRef    string
+	Email      string
+	CustomerID string
+	PriceID    string
+	SuccessURL string
+	CancelURL  string
+}
+
+// NewBillingProvider returns a Stripe provider when a secret key is
+// configured and nil otherwise
+func NewBillingProvider(baseURL, secretKey, webhookSecret string) BillingProvider {
+	if secretKey == "" {
+		return nil
+	}
+	return NewStripeProvider(baseURL, secretKey, webhookSecret)
+}
+
+const (
+	// billingHTTPTimeout bounds each request to the billing provider
+	billingHTTPTimeout = 30 * time.Second
+	// stripeSignatureTolerance is how old a webhook signature may be, which
+	// keeps captured payloads from being replayed later
+	stripeSignatureTolerance = 5 * time.Minute
+)
+
+// StripeProvider bills through Stripe Checkout, the Stripe customer portal
+// and Stripe's webhooks
+type StripeProvider struct {
+	BaseURL       string
+	SecretKey     string
+	WebhookSecret string
+	client        *http.Client
+	now           func() time.Time
+}
+
+// NewStripeProvider creates a provider for the Stripe API at baseURL,
+// normally https://api.stripe.com
+func NewStripeProvider(baseURL, secretKey, webhookSecret string) *StripeProvider {
+	return &StripeProvider{
+		BaseURL:       strings.TrimRight(baseURL, "/"),
+		SecretKey:     secretKey,
+		WebhookSecret: webhookSecret,
+		client:        &http.Client{Timeout: billingHTTPTimeout},
+		now:           time.Now,
+	}
+}
+
+// CreateCheckout creates a Checkout session in subscription mode. The user
+// reference is kept on the session and in the subscription's metadata, so
+// webhooks can tell whose subscription changed.
+func (p *StripeProvider) CreateCheckout(ctx context.Context, checkout CheckoutRequest) (*domain.BillingSession, error) {
+	form := url.Values{
+		"mode":                              {"subscription"},
+		"line_items[0][price]":              {checkout.PriceID},
+		"line_items[0][quantity]":           {"1"},
+		"success_url":                       {checkout.SuccessURL},
+		"cancel_url":                        {checkout.CancelURL},
+		"client_reference_id":               {checkout.UserRef},
+		"subscription_data[metadata][user]": {checkout.UserRef},
+	}
+	if checkout.CustomerID != "" {
+		form.Set("customer", checkout.CustomerID)
+	} else if checkout.Email != "" {
+		form.Set("customer_email", checkout.Email)
+	}
+	var session domain.BillingSession
+	if err := p.call(ctx, http.MethodPost, "/v1/checkout/sessions", form, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// CreatePortal creates a customer portal session that returns to returnURL
+func (p *StripeProvider) CreatePortal(ctx context.Context, customerID, returnURL string) (*domain.BillingSession, error) {
+	form := url.Values{"customer": {customerID}, "return_url": {returnURL}}
+	var session domain.BillingSession
+	if err := p.call(ctx, http.MethodPost, "/v1/billing_portal/sessions", form, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// Invoices lists the customer's invoices
+func (p *StripeProvider) Invoices(ctx context.Context, customerID string, limit int) ([]domain.Invoice, error) {
+	query := url.Values{"customer": {customerID}, "limit": {strconv.Itoa(limit)}}
+	var response struct {
+		Data []struct {
+			ID          string `json:"id"`
+			Number      string `json:"number"`
+			Status      string `json:"status"`
+			Currency    string `json:"currency"`
+			AmountDue   int64  `json:"amount_due"`
+			AmountPaid  int64  `json:"amount_paid"`
+			PeriodStart int64  `json:"period_start"`
+			PeriodEnd   int64  `json:"period_end"`
+			HostedURL   string `json:"hosted_invoice_url"`
+			PDFURL      string `json:"invoice_pdf"`
+			Created     int64  `json:"created"`
+		} `json:"data"`
+	}
+	if err := p.call(ctx, http.MethodGet, "/v1/invoices?"+query.Encode(), nil, &response); err != nil {
+		return nil, err
+	}
+
+	invoices := make([]domain.Invoice, 0, len(response.Data))
+	for _, invoice := range response.Data {
+		invoices = append(invoices, domain.Invoice{
+			ID:          invoice.ID,
+			Number:      invoice.Number,
+			Status:      invoice.Status,
+			Currency:    strings.ToUpper(invoice.Currency),
+			AmountDue:   domain.Money(invoice.AmountDue),
+			AmountPaid:  domain.Money(invoice.AmountPaid),
+			PeriodStart: time.Unix(invoice.PeriodStart, 0).UTC(),
+			PeriodEnd:   time.Unix(invoice.PeriodEnd, 0).UTC(),
+			HostedURL:   invoice.HostedURL,
+			PDFURL:      invoice.PDFURL,
+			CreatedAt:   time.Unix(invoice.Created, 0).UTC(),
+		})
+	}
+	return invoices, nil
+}
+
+// stripeEvent is a webhook event. The object is a Checkout session or a
+// subscription depending on the event's type.
+type stripeEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Object struct {
+			ID                string            `json:"id"`
+			Customer          string            `json:"customer"`
+			Subscription      string            `json:"subscription"`
+			ClientReferenceID string            `json:"client_reference_id"`
+			Status            string            `json:"status"`
+			CurrentPeriodEnd  int64             `json:"current_period_end"`
+			Metadata          map[string]string `json:"metadata"`
+		} `json:"object"`
+	} `json:"data"`
+}
+
+// ParseWebhook verifies the Stripe-Signature header of a webhook payload and
+// decodes the event
+func (p *StripeProvider) ParseWebhook(payload []byte, signature string) (*domain.BillingEvent, error) {
+	if err := p.verifySignature(payload, signature); err != nil {
+		return nil, err
+	}
+	var event stripeEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("failed to decode stripe event: %w", err)
+	}
+
+	object := event.Data.Object
+	billingEvent := &domain.BillingEvent{ID: event.ID, Type: event.Type, CustomerID: object.Customer}
+	if event.Type == domain.BillingEventCheckoutCompleted {
+		billingEvent.UserRef = object.ClientReferenceID
+		billingEvent.SubscriptionID = object.Subscription
+		return billingEvent, nil
+	}
+	billingEvent.UserRef = object.Metadata["user"]
+	billingEvent.SubscriptionID = object.ID
+	billingEvent.Status = object.Status
+	if object.CurrentPeriodEnd > 0 {
+		periodEnd := time.Unix(object.CurrentPeriodEnd, 0).UTC()
+		billingEvent.PeriodEnd = &periodEnd
+	}
+	return billingEvent, nil
+}
+
+// verifySignature checks a "t=<timestamp>,v1=<signature>" header: one of the
+// v1 signatures must be the HMAC-SHA256 of "<timestamp>.<payload>" under the
+// webhook secret, and the timestamp must be recent
+func (p *StripeProvider) verifySignature(payload []byte, header string) error {
+	var timestamp string
+	var signatures [][]byte
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			if signature, err := hex.DecodeString(value); err == nil {
+				signatures = append(signatures, signature)
+			}
+		}
+	}
+	signedAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || p.WebhookSecret == "" || len(signatures) == 0 {
+		return domain.ErrInvalidWebhookSignature
+	}
+	if age := p.now().Sub(time.Unix(signedAt, 0)); age > stripeSignatureTolerance || age < -stripeSignatureTolerance {
+		return domain.ErrInvalidWebhookSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(p.WebhookSecret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	expected := mac.Sum(nil)
+	for _, signature := range signatures {
+		if hmac.Equal(signature, expected) {
+			return nil
+		}
+	}
+	return domain.ErrInvalidWebhookSignature
+}
+
+// call sends a form encoded request to the Stripe API and decodes the JSON response
+func (p *StripeProvider) call(ctx context.Context, method, path string, form url.Values, response any) error {
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+	req, err := http.NewRequestWithContext(ctx, method, p.BaseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create stripe request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+p.SecretKey)
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("stripe request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read stripe response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var stripeErr struct {
+			Error struct {
+				Type    string `json:"type"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		_ = json.Unmarshal(data, &stripeErr)
+		return fmt.Errorf("stripe %s returned status %d: %s %s",
+			strings.SplitN(path, "?", 2)[0], resp.StatusCode, stripeErr.Error.Type, stripeErr.Error.Message)
+	}
+	if err := json.Unmarshal(data, response); err != nil {
+		return fmt.Errorf("failed to decode stripe response: %w", err)
+	}
+	return nil
+}
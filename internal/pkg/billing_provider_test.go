@@ -0,0 +1,129 @@
+package pkg
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStripeProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer sk_test", r.Header.Get("Authorization"))
+		require.NoError(t, r.ParseForm())
+
+		switch r.URL.Path {
+		case "/v1/checkout/sessions":
+			assert.Equal(t, "subscription", r.PostForm.Get("mode"))
+			assert.Equal(t, "price_premium", r.PostForm.Get("line_items[0][price]"))
+			assert.Equal(t, "7", r.PostForm.Get("client_reference_id"))
+			assert.Equal(t, "7", r.PostForm.Get("subscription_data[metadata][user]"))
+			assert.Equal(t, "ada@example.com", r.PostForm.Get("customer_email"))
+			assert.Empty(t, r.PostForm.Get("customer"))
+			_, _ = w.Write([]byte(`{"id":"cs_1","url":"https://checkout.stripe.com/c/cs_1"}`))
+		case "/v1/billing_portal/sessions":
+			if r.PostForm.Get("customer") == "cus_missing" {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":{"type":"invalid_request_error","message":"No such customer"}}`))
+				return
+			}
+			assert.Equal(t, "https://app.example.com/settings", r.PostForm.Get("return_url"))
+			_, _ = w.Write([]byte(`{"id":"bps_1","url":"https://billing.stripe.com/p/session/1"}`))
+		case "/v1/invoices":
+			assert.Equal(t, "cus_1", r.URL.Query().Get("customer"))
+			assert.Equal(t, "10", r.URL.Query().Get("limit"))
+			_, _ = w.Write([]byte(`{"data":[{"id":"in_1","number":"A-0001","status":"paid","currency":"eur",
+				"amount_due":999,"amount_paid":999,"period_start":1709251200,"period_end":1711929600,
+				"hosted_invoice_url":"https://invoice.stripe.com/i/1","invoice_pdf":"https://pay.stripe.com/1.pdf",
+				"created":1709251200}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	provider := NewStripeProvider(server.URL+"/", "sk_test", "whsec_test")
+	ctx := context.Background()
+
+	session, err := provider.CreateCheckout(ctx, CheckoutRequest{
+		UserRef: "7", Email: "ada@example.com", PriceID: "price_premium",
+		SuccessURL: "https://app.example.com/done", CancelURL: "https://app.example.com/pricing",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &domain.BillingSession{ID: "cs_1", URL: "https://checkout.stripe.com/c/cs_1"}, session)
+
+	session, err = provider.CreatePortal(ctx, "cus_1", "https://app.example.com/settings")
+	require.NoError(t, err)
+	assert.Equal(t, "https://billing.stripe.com/p/session/1", session.URL)
+
+	_, err = provider.CreatePortal(ctx, "cus_missing", "https://app.example.com/settings")
+	assert.ErrorContains(t, err, "No such customer")
+
+	invoices, err := provider.Invoices(ctx, "cus_1", 10)
+	require.NoError(t, err)
+	require.Len(t, invoices, 1)
+	assert.Equal(t, "EUR", invoices[0].Currency)
+	assert.Equal(t, domain.NewMoney(9.99), invoices[0].AmountPaid)
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), invoices[0].PeriodStart)
+	assert.Equal(t, "https://pay.stripe.com/1.pdf", invoices[0].PDFURL)
+}
+
+func TestStripeProvider_ParseWebhook(t *testing.T) {
+	provider := NewStripeProvider("https://api.stripe.com", "sk_test", "whsec_test")
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	provider.now = func() time.Time { return now }
+
+	sign := func(payload string, at time.Time, secret string) string {
+		timestamp := fmt.Sprint(at.Unix())
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(timestamp + "." + payload))
+		return "t=" + timestamp + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	t.Run("checkout completed", func(t *testing.T) {
+		payload := `{"id":"evt_1","type":"checkout.session.completed","data":{"object":{"id":"cs_1",
+			"customer":"cus_1","subscription":"sub_1","client_reference_id":"7"}}}`
+		event, err := provider.ParseWebhook([]byte(payload), sign(payload, now, "whsec_test"))
+		require.NoError(t, err)
+		assert.Equal(t, &domain.BillingEvent{
+			ID: "evt_1", Type: domain.BillingEventCheckoutCompleted, UserRef: "7", CustomerID: "cus_1", SubscriptionID: "sub_1",
+		}, event)
+	})
+
+	t.Run("subscription updated", func(t *testing.T) {
+		payload := `{"id":"evt_2","type":"customer.subscription.updated","data":{"object":{"id":"sub_1",
+			"customer":"cus_1","status":"past_due","current_period_end":1711929600,"metadata":{"user":"7"}}}}`
+		event, err := provider.ParseWebhook([]byte(payload), "v1=ignored, "+sign(payload, now.Add(-time.Minute), "whsec_test"))
+		require.NoError(t, err)
+		assert.Equal(t, "7", event.UserRef)
+		assert.Equal(t, "sub_1", event.SubscriptionID)
+		assert.Equal(t, domain.SubscriptionPastDue, event.Status)
+		require.NotNil(t, event.PeriodEnd)
+		assert.Equal(t, time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), *event.PeriodEnd)
+	})
+
+	payload := `{"id":"evt_3","type":"customer.subscription.deleted","data":{"object":{"id":"sub_1"}}}`
+	for name, signature := range map[string]string{
+		"missing":     "",
+		"wrong key":   sign(payload, now, "whsec_other"),
+		"too old":     sign(payload, now.Add(-10*time.Minute), "whsec_test"),
+		"no v1":       fmt.Sprintf("t=%d", now.Unix()),
+		"tampered":    sign(payload+" ", now, "whsec_test"),
+		"bad t value": "t=soon,v1=00",
+	} {
+		t.Run("rejects "+name+" signature", func(t *testing.T) {
+			_, err := provider.ParseWebhook([]byte(payload), signature)
+			assert.ErrorIs(t, err, domain.ErrInvalidWebhookSignature)
+		})
+	}
+}
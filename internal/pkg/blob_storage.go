@@ -0,0 +1,383 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+)
+
+// BlobStorage keeps files such as attachments and exports under slash
+// separated keys like "attachments/1/receipt.png"
+type BlobStorage interface {
+	// Put stores data under key, replacing what was stored there
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	// Get returns the data stored under key, or domain.ErrNotFound
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Delete removes key; deleting a missing key is not an error
+	Delete(ctx context.Context, key string) error
+	// SignedURL returns a URL the file can be downloaded from without
+	// credentials for the next ttl
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// ErrInvalidBlobKey is returned for keys that are empty or would leave the
+// storage root, such as "../secrets"
+var ErrInvalidBlobKey = errors.New("invalid storage key")
+
+// cleanBlobKey rejects keys that are not already clean relative paths
+func cleanBlobKey(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key || key == ".." || strings.HasPrefix(key, "../") {
+		return "", ErrInvalidBlobKey
+	}
+	return key, nil
+}
+
+// LocalStorage keeps files below a directory on the local disk. Its signed
+// URLs point at BaseURL, where the API serves them after Verify accepts the
+// link's expiry and signature.
+type LocalStorage struct {
+	Dir        string
+	BaseURL    string
+	signingKey []byte
+	now        func() time.Time
+}
+
+// NewLocalStorage creates a storage below dir whose links start with baseURL
+// and are signed with signingKey
+func NewLocalStorage(dir, baseURL string, signingKey []byte) *LocalStorage {
+	return &LocalStorage{Dir: dir, BaseURL: strings.TrimSuffix(baseURL, "/"), signingKey: signingKey, now: time.Now}
+}
+
+func (s *LocalStorage) file(key string) (string, error) {
+	key, err := cleanBlobKey(key)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.Dir, filepath.FromSlash(key)), nil
+}
+
+// Put writes the file, creating its directories
+func (s *LocalStorage) Put(_ context.Context, key, _ string, data []byte) error {
+	file, err := s.file(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o750); err != nil {
+		return fmt.Errorf("failed to create storage directory: %w", err)
+	}
+	if err := os.WriteFile(file, data, 0o600); err != nil {
+		return fmt.Errorf("failed to store file: %w", err)
+	}
+	return nil
+}
+
+// Get reads the file
+func (s *LocalStorage) Get(_ context.Context, key string) ([]byte, error) {
+	file, err := s.file(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, domain.ErrNotFound
+	}
+	return data, err
+}
+
+// Delete removes the file
+func (s *LocalStorage) Delete(_ context.Context, key string) error {
+	file, err := s.file(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// SignedURL returns BaseURL/key with the expiry and its signature as the
+// expires and signature query parameters
+func (s *LocalStorage) SignedURL(_ context.Context, key string, ttl time.Duration) (string, error) {
+	key, err := cleanBlobKey(key)
+	if err != nil {
+		return "", err
+	}
+	expires := strconv.FormatInt(s.now().Add(ttl).Unix(), 10)
+	query := url.Values{"expires": {expires}, "signature": {s.sign(key, expires)}}
+	return s.BaseURL + "/" + (&url.URL{Path: key}).EscapedPath() + "?" + query.Encode(), nil
+}
+
+// Verify checks the expiry and signature of a link SignedURL made for key,
+// returning domain.ErrInvalidFileLink when it was not or has expired
+func (s *LocalStorage) Verify(key, expires, signature string) error {
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || s.now().Unix() > unix {
+		return domain.ErrInvalidFileLink
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.sign(key, expires))) {
+		return domain.ErrInvalidFileLink
+	}
+	return nil
+}
+
+func (s *LocalStorage) sign(key, expires string) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	mac.Write([]byte(key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+const (
+	// s3HTTPTimeout bounds each request to the bucket
+	s3HTTPTimeout = 60 * time.Second
+	// s3MaxURLExpiry is the longest a presigned S3 URL may be valid
+	s3MaxURLExpiry = 7 * 24 * time.Hour
+	// s3UnsignedPayload stands in for the body hash of presigned URLs
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+)
+
+// S3Storage keeps files in an S3 bucket, or a bucket of a service with the
+// same API such as MinIO, signing requests with AWS Signature Version 4.
+// Endpoint defaults to AWS in Region; PathStyle puts the bucket in the path
+// rather than the host name, which most self-hosted services need.
+type S3Storage struct {
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	PathStyle       bool
+	Client          *http.Client
+	now             func() time.Time
+}
+
+// NewS3Storage creates a storage in bucket. An empty endpoint selects AWS.
+func NewS3Storage(endpoint, region, bucket, accessKeyID, secretAccessKey string, pathStyle bool) *S3Storage {
+	if endpoint == "" {
+		endpoint = "https://s3." + region + ".amazonaws.com"
+	}
+	return &S3Storage{
+		Endpoint:        strings.TrimSuffix(endpoint, "/"),
+		Region:          region,
+		Bucket:          bucket,
+		AccessKeyID:     accessKeyID,
+		SecretAccessKey: secretAccessKey,
+		PathStyle:       pathStyle,
+		Client:          &http.Client{Timeout: s3HTTPTimeout},
+		now:             time.Now,
+	}
+}
+
+// objectURL is the URL of the object stored under key
+func (s *S3Storage) objectURL(key string) (*url.URL, error) {
+	key, err := cleanBlobKey(key)
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(s.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid S3 endpoint: %w", err)
+	}
+	if s.PathStyle {
+		u.Path += "/" + s.Bucket + "/" + key
+	} else {
+		u.Host = s.Bucket + "." + u.Host
+		u.Path += "/" + key
+	}
+	u.RawPath = s3EscapePath(u.Path)
+	return u, nil
+}
+
+// Put uploads the object
+func (s *S3Storage) Put(ctx context.Context, key, contentType string, data []byte) error {
+	resp, err := s.do(ctx, http.MethodPut, key, contentType, data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return s3Error(resp, http.StatusOK)
+}
+
+// Get downloads the object
+func (s *S3Storage) Get(ctx context.Context, key string) ([]byte, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, domain.ErrNotFound
+	}
+	if err := s3Error(resp, http.StatusOK); err != nil {
+		return nil, err
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// Delete removes the object; S3 answers deletes of missing keys with 204 too
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, "", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	return s3Error(resp, http.StatusNoContent, http.StatusOK)
+}
+
+// SignedURL presigns a GET of the object, for at most seven days
+func (s *S3Storage) SignedURL(_ context.Context, key string, ttl time.Duration) (string, error) {
+	u, err := s.objectURL(key)
+	if err != nil {
+		return "", err
+	}
+	ttl = min(ttl, s3MaxURLExpiry)
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	scope := s.scope(now)
+
+	query := url.Values{
+		"X-Amz-Algorithm":     {"AWS4-HMAC-SHA256"},
+		"X-Amz-Credential":    {s.AccessKeyID + "/" + scope},
+		"X-Amz-Date":          {amzDate},
+		"X-Amz-Expires":       {strconv.Itoa(int(ttl.Seconds()))},
+		"X-Amz-SignedHeaders": {"host"},
+	}
+	canonical := canonicalS3Request(http.MethodGet, u, query, map[string]string{"host": u.Host}, s3UnsignedPayload)
+	query.Set("X-Amz-Signature", s.signature(now, amzDate, scope, canonical))
+	u.RawQuery = canonicalS3Query(query)
+	return u.String(), nil
+}
+
+// do sends a request for the object signed in the Authorization header
+func (s *S3Storage) do(ctx context.Context, method, key, contentType string, body []byte) (*http.Response, error) {
+	u, err := s.objectURL(key)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	scope := s.scope(now)
+	payloadHash := sha256Hex(body)
+
+	headers := map[string]string{"host": u.Host, "x-amz-content-sha256": payloadHash, "x-amz-date": amzDate}
+	if contentType != "" {
+		headers["content-type"] = contentType
+		req.Header.Set("Content-Type", contentType)
+	}
+	canonical := canonicalS3Request(method, u, url.Values{}, headers, payloadHash)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.AccessKeyID, scope, signedS3Headers(headers), s.signature(now, amzDate, scope, canonical)))
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("s3 %s %s failed: %w", method, key, err)
+	}
+	return resp, nil
+}
+
+// s3Error returns an error unless the response has one of the wanted statuses
+func s3Error(resp *http.Response, want ...int) error {
+	for _, status := range want {
+		if resp.StatusCode == status {
+			return nil
+		}
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("s3 %s %s returned status %d: %s",
+		resp.Request.Method, resp.Request.URL.Path, resp.StatusCode, strings.TrimSpace(string(body)))
+}
+
+func (s *S3Storage) scope(now time.Time) string {
+	return now.Format("20060102") + "/" + s.Region + "/s3/aws4_request"
+}
+
+// signature signs the canonical request with the key derived for the day
+func (s *S3Storage) signature(now time.Time, amzDate, scope, canonicalRequest string) string {
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+	key := hmacSHA256([]byte("AWS4"+s.SecretAccessKey), now.Format("20060102"))
+	for _, part := range []string{s.Region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// canonicalS3Request is the request in the canonical form Signature Version 4 signs
+func canonicalS3Request(method string, u *url.URL, query url.Values, headers map[string]string, payloadHash string) string {
+	var canonicalHeaders strings.Builder
+	for _, name := range sortedKeys(headers) {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	return strings.Join([]string{
+		method, s3EscapePath(u.Path), canonicalS3Query(query), canonicalHeaders.String(), signedS3Headers(headers), payloadHash,
+	}, "\n")
+}
+
+// canonicalS3Query sorts and encodes query parameters the way AWS expects,
+// with spaces as %20 rather than +
+func canonicalS3Query(query url.Values) string {
+	return strings.ReplaceAll(query.Encode(), "+", "%20")
+}
+
+// s3EscapePath percent-encodes every byte of the path but unreserved
+// characters and slashes, as Signature Version 4 requires
+func s3EscapePath(p string) string {
+	var escaped strings.Builder
+	for _, b := range []byte(p) {
+		switch {
+		case 'A' <= b && b <= 'Z', 'a' <= b && b <= 'z', '0' <= b && b <= '9', strings.IndexByte("-._~/", b) >= 0:
+			escaped.WriteByte(b)
+		default:
+			fmt.Fprintf(&escaped, "%%%02X", b)
+		}
+	}
+	return escaped.String()
+}
+
+func signedS3Headers(headers map[string]string) string {
+	return strings.Join(sortedKeys(headers), ";")
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,151 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EmailMessage is an email with an HTML body and a plain text alternative
+// for clients that do not show HTML. Headers are added as they are, e.g.
+// List-Unsubscribe.
+type EmailMessage struct {
+	To      string
+	Subject string
+	HTML    string
+	Text    string
+	Headers map[string]string
+}
+
+// EmailSender delivers emails
+type EmailSender interface {
+	Send(ctx context.Context, message EmailMessage) error
+}
+
+// NewEmailSender returns an SMTP sender when a host is configured and nil
+// otherwise
+func NewEmailSender(host string, port int, username, password, from string) EmailSender {
+	if host == "" {
+		return nil
+	}
+	return NewSMTPSender(host, port, username, password, from)
+}
+
+// SMTPSender delivers emails through an SMTP server, upgrading to TLS when
+// the server offers STARTTLS
+type SMTPSender struct {
+	Addr string
+	From string
+	auth smtp.Auth
+	send func(addr string, auth smtp.Auth, from string, to []string, message []byte) error
+	now  func() time.Time
+}
+
+// NewSMTPSender creates a sender for the server at host:port. Without a
+// username it sends unauthenticated.
+func NewSMTPSender(host string, port int, username, password, from string) *SMTPSender {
+	sender := &SMTPSender{
+		Addr: net.JoinHostPort(host, strconv.Itoa(port)),
+		From: from,
+		send: smtp.SendMail,
+		now:  time.Now,
+	}
+	if username != "" {
+		sender.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return sender
+}
+
+// Send writes the message as multipart/alternative and hands it to the
+// server. The context only stops messages that have not been sent yet; the
+// standard SMTP client cannot be cancelled mid-conversation.
+func (s *SMTPSender) Send(ctx context.Context, message EmailMessage) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	from, err := mail.ParseAddress(s.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender address %q: %w", s.From, err)
+	}
+	to, err := mail.ParseAddress(message.To)
+	if err != nil {
+		return fmt.Errorf("invalid recipient address %q: %w", message.To, err)
+	}
+	data, err := s.compose(from, to, message)
+	if err != nil {
+		return err
+	}
+	if err := s.send(s.Addr, s.auth, from.Address, []string{to.Address}, data); err != nil {
+		return fmt.Errorf("failed to send email to %s: %w", to.Address, err)
+	}
+	return nil
+}
+
+// compose writes the headers and both bodies, quoted-printable encoded so
+// non-ASCII text survives 7 bit servers
+func (s *SMTPSender) compose(from, to *mail.Address, message EmailMessage) ([]byte, error) {
+	boundary, err := randomBoundary()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	headers := map[string]string{
+		"From":         from.String(),
+		"To":           to.String(),
+		"Subject":      mime.QEncoding.Encode("utf-8", message.Subject),
+		"Date":         s.now().Format(time.RFC1123Z),
+		"MIME-Version": "1.0",
+		"Content-Type": `multipart/alternative; boundary="` + boundary + `"`,
+	}
+	for name, value := range message.Headers {
+		headers[name] = value
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		// Header values must not start new headers
+		value := strings.NewReplacer("\r", "", "\n", "").Replace(headers[name])
+		fmt.Fprintf(&buf, "%s: %s\r\n", name, value)
+	}
+	buf.WriteString("\r\n")
+
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", message.Text},
+		{"text/html; charset=utf-8", message.HTML},
+	} {
+		fmt.Fprintf(&buf, "--%s\r\nContent-Type: %s\r\nContent-Transfer-Encoding: quoted-printable\r\n\r\n", boundary, part.contentType)
+		writer := quotedprintable.NewWriter(&buf)
+		if _, err := writer.Write([]byte(part.body)); err != nil {
+			return nil, err
+		}
+		if err := writer.Close(); err != nil {
+			return nil, err
+		}
+		buf.WriteString("\r\n")
+	}
+	fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+	return buf.Bytes(), nil
+}
+
+func randomBoundary() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b[:]), nil
+}
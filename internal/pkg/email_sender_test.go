@@ -0,0 +1,75 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSMTPSender(t *testing.T) {
+	assert.Nil(t, NewEmailSender("", 587, "", "", "digest@example.com"))
+
+	sender := NewSMTPSender("smtp.example.com", 587, "user", "secret", "Finance Advisor <digest@example.com>")
+	sender.now = func() time.Time { return time.Date(2024, 5, 6, 8, 0, 0, 0, time.UTC) }
+	var sent []byte
+	sender.send = func(addr string, auth smtp.Auth, from string, to []string, message []byte) error {
+		assert.Equal(t, "smtp.example.com:587", addr)
+		assert.NotNil(t, auth)
+		assert.Equal(t, "digest@example.com", from)
+		assert.Equal(t, []string{"ada@example.com"}, to)
+		sent = message
+		return nil
+	}
+
+	err := sender.Send(context.Background(), EmailMessage{
+		To:      "ada@example.com",
+		Subject: "Wöchentliche Übersicht",
+		HTML:    "<p>Ausgaben: 1.234,56 €</p>",
+		Text:    "Ausgaben: 1.234,56 €",
+		Headers: map[string]string{"List-Unsubscribe": "<https://api.example.com/unsubscribe?token=abc>\r\nBcc: x@example.com"},
+	})
+	require.NoError(t, err)
+
+	message, err := mail.ReadMessage(strings.NewReader(string(sent)))
+	require.NoError(t, err)
+	subject, err := new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Wöchentliche Übersicht", subject)
+	assert.Equal(t, "<https://api.example.com/unsubscribe?token=abc>Bcc: x@example.com", message.Header.Get("List-Unsubscribe"),
+		"header values cannot add headers")
+	assert.Empty(t, message.Header.Get("Bcc"))
+
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+	reader := multipart.NewReader(message.Body, params["boundary"])
+	var bodies []string
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		body, err := io.ReadAll(part)
+		require.NoError(t, err)
+		bodies = append(bodies, string(body))
+	}
+	assert.Equal(t, []string{"Ausgaben: 1.234,56 €", "<p>Ausgaben: 1.234,56 €</p>"}, bodies)
+
+	err = sender.Send(context.Background(), EmailMessage{To: "not an address"})
+	assert.Error(t, err)
+
+	sender.send = func(string, smtp.Auth, string, []string, []byte) error { return errors.New("connection refused") }
+	err = sender.Send(context.Background(), EmailMessage{To: "ada@example.com"})
+	assert.ErrorContains(t, err, "connection refused")
+}
@@ -0,0 +1,52 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// FXProvider looks up historical exchange rates
+type FXProvider interface {
+	// Rate returns what one unit of from was worth in to on the given day
+	Rate(ctx context.Context, from, to string, date time.Time) (float64, error)
+	// Name identifies the provider in the rates it supplied
+	Name() string
+}
+
+// fxHTTPTimeout bounds each request to an exchange rate API
+const fxHTTPTimeout = 15 * time.Second
+
+// FrankfurterProvider reads the European Central Bank reference rates
+// published by a Frankfurter API such as https://api.frankfurter.app. Days
+// without a fixing, such as weekends, get the previous working day's rate.
+type FrankfurterProvider struct {
+	BaseURL string
+	client  *http.Client
+}
+
+func NewFrankfurterProvider(baseURL string) *FrankfurterProvider {
+	return &FrankfurterProvider{BaseURL: strings.TrimRight(baseURL, "/"), client: &http.Client{Timeout: fxHTTPTimeout}}
+}
+
+func (p *FrankfurterProvider) Name() string { return "frankfurter" }
+
+// Rate fetches the from/to reference rate of the day
+func (p *FrankfurterProvider) Rate(ctx context.Context, from, to string, date time.Time) (float64, error) {
+	query := url.Values{"from": {from}, "to": {to}}
+	endpoint := p.BaseURL + "/" + date.Format("2006-01-02") + "?" + query.Encode()
+	var response struct {
+		Rates map[string]float64 `json:"rates"`
+	}
+	if err := getJSON(ctx, p.client, "frankfurter", endpoint, &response); err != nil {
+		return 0, err
+	}
+	rate, ok := response.Rates[to]
+	if !ok || rate <= 0 {
+		return 0, fmt.Errorf("frankfurter has no %s/%s rate for %s", from, to, date.Format("2006-01-02"))
+	}
+	return rate, nil
+}
@@ -0,0 +1,40 @@
+package pkg
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrankfurterProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/2024-03-01" && r.URL.Query().Get("from") == "EUR" && r.URL.Query().Get("to") == "USD":
+			_, _ = w.Write([]byte(`{"amount":1.0,"base":"EUR","date":"2024-03-01","rates":{"USD":1.0838}}`))
+		case r.URL.Path == "/2024-03-01":
+			_, _ = w.Write([]byte(`{"amount":1.0,"base":"EUR","date":"2024-03-01","rates":{}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	provider := NewFrankfurterProvider(server.URL + "/")
+	ctx := context.Background()
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	rate, err := provider.Rate(ctx, "EUR", "USD", day)
+	require.NoError(t, err)
+	assert.InDelta(t, 1.0838, rate, 1e-9)
+
+	_, err = provider.Rate(ctx, "EUR", "XYZ", day)
+	assert.ErrorContains(t, err, "no EUR/XYZ rate for 2024-03-01")
+	_, err = provider.Rate(ctx, "EUR", "USD", day.AddDate(0, 0, 1))
+	assert.ErrorContains(t, err, "frankfurter returned status 404")
+}
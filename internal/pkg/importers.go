@@ -0,0 +1,266 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+)
+
+// ParseImportFile reads the transactions of a file exported by another
+// finance app. Rows that cannot be read are returned as row errors, so the
+// rest of the file can still be imported; the error is only set when the
+// file as a whole is not an export of the source.
+func ParseImportFile(source string, data []byte) ([]domain.ImportedTransaction, []domain.ImportRowError, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // Excel and YNAB write a byte order mark
+	switch source {
+	case domain.ImportSourceMint:
+		return parseCSVImport(data, []string{"date", "description", "amount", "transaction type", "category"}, parseMintRow)
+	case domain.ImportSourceYNAB:
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+			return parseYNABJSON(trimmed)
+		}
+		return parseCSVImport(data, []string{"date", "payee", "outflow", "inflow"}, parseYNABRow)
+	case domain.ImportSourcePersonalCapital:
+		return parseCSVImport(data, []string{"date", "description", "category", "amount"}, parsePersonalCapitalRow)
+	default:
+		return nil, nil, domain.ErrUnknownImportSource
+	}
+}
+
+// importRow reads one CSV row given a lookup of its columns by lower-cased
+// header name
+type importRow func(column func(name string) string) (domain.ImportedTransaction, error)
+
+func parseCSVImport(data []byte, required []string, parse importRow) ([]domain.ImportedTransaction, []domain.ImportRowError, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", domain.ErrInvalidImportFile, err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range required {
+		if _, ok := columns[name]; !ok {
+			return nil, nil, fmt.Errorf("%w: missing column %q", domain.ErrInvalidImportFile, name)
+		}
+	}
+
+	var transactions []domain.ImportedTransaction
+	var rowErrors []domain.ImportRowError
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			rowErrors = append(rowErrors, domain.ImportRowError{Row: row, Message: err.Error()})
+			continue
+		}
+		column := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		transaction, err := parse(column)
+		if err != nil {
+			rowErrors = append(rowErrors, domain.ImportRowError{Row: row, Message: err.Error()})
+			continue
+		}
+		transaction.Row = row
+		transactions = append(transactions, transaction)
+	}
+	return transactions, rowErrors, nil
+}
+
+// parseMintRow reads a row of Mint's transactions.csv, which lists unsigned
+// amounts with a debit or credit transaction type and space-separated labels
+func parseMintRow(column func(string) string) (domain.ImportedTransaction, error) {
+	date, err := parseImportDate(column("date"), "1/2/2006", "2006-01-02")
+	if err != nil {
+		return domain.ImportedTransaction{}, err
+	}
+	amount, err := parseImportAmount(column("amount"))
+	if err != nil {
+		return domain.ImportedTransaction{}, err
+	}
+	transactionType := domain.TransactionTypeExpense
+	if strings.EqualFold(column("transaction type"), "credit") {
+		transactionType = domain.TransactionTypeIncome
+	}
+	category := column("category")
+	return domain.ImportedTransaction{
+		Date:        date,
+		Description: firstNonEmpty(column("description"), column("original description")),
+		Notes:       column("notes"),
+		Category:    category,
+		Type:        transactionType,
+		Amount:      amount.Abs(),
+		Tags:        strings.Fields(column("labels")),
+		Transfer:    strings.EqualFold(category, "Transfer") || strings.EqualFold(category, "Credit Card Payment"),
+	}, nil
+}
+
+// parseYNABRow reads a row of a YNAB register export, which splits amounts
+// into outflow and inflow columns and names transfers "Transfer : Account"
+func parseYNABRow(column func(string) string) (domain.ImportedTransaction, error) {
+	date, err := parseImportDate(column("date"), "01/02/2006", "2006-01-02")
+	if err != nil {
+		return domain.ImportedTransaction{}, err
+	}
+	outflow, err := parseImportAmount(column("outflow"))
+	if err != nil {
+		return domain.ImportedTransaction{}, err
+	}
+	inflow, err := parseImportAmount(column("inflow"))
+	if err != nil {
+		return domain.ImportedTransaction{}, err
+	}
+	category := firstNonEmpty(column("category group/category"), column("category"))
+	return ynabTransaction(date, column("payee"), column("memo"), category, inflow-outflow,
+		strings.HasPrefix(column("payee"), "Transfer :")), nil
+}
+
+// ynabExport is the shape of YNAB's API transaction listings. Amounts are
+// in thousandths of the currency unit.
+type ynabExport struct {
+	Data struct {
+		Transactions []struct {
+			Date              string  `json:"date"`
+			Amount            int64   `json:"amount"`
+			PayeeName         string  `json:"payee_name"`
+			CategoryName      string  `json:"category_name"`
+			Memo              string  `json:"memo"`
+			TransferAccountID *string `json:"transfer_account_id"`
+			Deleted           bool    `json:"deleted"`
+		} `json:"transactions"`
+	} `json:"data"`
+}
+
+func parseYNABJSON(data []byte) ([]domain.ImportedTransaction, []domain.ImportRowError, error) {
+	var export ynabExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", domain.ErrInvalidImportFile, err)
+	}
+
+	var transactions []domain.ImportedTransaction
+	var rowErrors []domain.ImportRowError
+	for i, tx := range export.Data.Transactions {
+		if tx.Deleted {
+			continue
+		}
+		date, err := parseImportDate(tx.Date, "2006-01-02")
+		if err != nil {
+			rowErrors = append(rowErrors, domain.ImportRowError{Row: i + 1, Message: err.Error()})
+			continue
+		}
+		// Round milliunits to the nearest cent, away from zero on ties
+		cents := (tx.Amount + 5) / 10
+		if tx.Amount < 0 {
+			cents = (tx.Amount - 5) / 10
+		}
+		transaction := ynabTransaction(date, tx.PayeeName, tx.Memo, tx.CategoryName, domain.Money(cents), tx.TransferAccountID != nil)
+		transaction.Row = i + 1
+		transactions = append(transactions, transaction)
+	}
+	return transactions, rowErrors, nil
+}
+
+// ynabTransaction builds a transaction from YNAB's signed amount, falling
+// back to the memo or category for transactions without a payee
+func ynabTransaction(date time.Time, payee, memo, category string, amount domain.Money, transfer bool) domain.ImportedTransaction {
+	transactionType := domain.TransactionTypeExpense
+	if amount > 0 {
+		transactionType = domain.TransactionTypeIncome
+	}
+	return domain.ImportedTransaction{
+		Date:        date,
+		Description: firstNonEmpty(payee, memo, domain.ExternalCategoryName(category)),
+		Notes:       memo,
+		Category:    category,
+		Type:        transactionType,
+		Amount:      amount.Abs(),
+		Transfer:    transfer,
+	}
+}
+
+// parsePersonalCapitalRow reads a row of a Personal Capital (Empower)
+// transaction export, which signs amounts and lists tags comma-separated
+func parsePersonalCapitalRow(column func(string) string) (domain.ImportedTransaction, error) {
+	date, err := parseImportDate(column("date"), "2006-01-02", "01/02/2006")
+	if err != nil {
+		return domain.ImportedTransaction{}, err
+	}
+	amount, err := parseImportAmount(column("amount"))
+	if err != nil {
+		return domain.ImportedTransaction{}, err
+	}
+	transactionType := domain.TransactionTypeExpense
+	if amount > 0 {
+		transactionType = domain.TransactionTypeIncome
+	}
+	var tags []string
+	for _, tag := range strings.Split(column("tags"), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	category := column("category")
+	return domain.ImportedTransaction{
+		Date:        date,
+		Description: column("description"),
+		Category:    category,
+		Type:        transactionType,
+		Amount:      amount.Abs(),
+		Tags:        tags,
+		Transfer:    strings.EqualFold(category, "Transfers") || strings.EqualFold(category, "Credit Card Payments"),
+	}, nil
+}
+
+func parseImportDate(value string, layouts ...string) (time.Time, error) {
+	for _, layout := range layouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
+
+// parseImportAmount reads amounts such as "$1,234.50", "-12.00" or the
+// accounting style "(12.00)"; empty amounts are zero
+func parseImportAmount(value string) (domain.Money, error) {
+	cleaned := strings.NewReplacer("$", "", "€", "", "£", "", ",", "", " ", "").Replace(value)
+	if cleaned == "" {
+		return 0, nil
+	}
+	negative := strings.HasPrefix(cleaned, "(") && strings.HasSuffix(cleaned, ")")
+	if negative {
+		cleaned = "-" + strings.Trim(cleaned, "()")
+	}
+	amount, err := domain.ParseMoney(cleaned)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+	return amount, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,154 @@
+package pkg
+
+import (
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseImportFile_Mint(t *testing.T) {
+	data := "\xef\xbb\xbf\"Date\",\"Description\",\"Original Description\",\"Amount\",\"Transaction Type\",\"Category\",\"Account Name\",\"Labels\",\"Notes\"\n" +
+		"\"3/15/2024\",\"Whole Foods\",\"WHOLEFDS #123\",\"1,234.50\",\"debit\",\"Groceries\",\"Checking\",\"family weekly\",\"\"\n" +
+		"\"3/16/2024\",\"ACME Payroll\",\"ACME PAYROLL\",\"2500.00\",\"credit\",\"Paycheck\",\"Checking\",\"\",\"March\"\n" +
+		"\"3/17/2024\",\"Card payment\",\"\",\"300.00\",\"debit\",\"Credit Card Payment\",\"Checking\",\"\",\"\"\n" +
+		"\"yesterday\",\"Broken\",\"\",\"1.00\",\"debit\",\"Groceries\",\"Checking\",\"\",\"\"\n"
+
+	transactions, rowErrors, err := ParseImportFile(domain.ImportSourceMint, []byte(data))
+	require.NoError(t, err)
+	require.Len(t, transactions, 3)
+	require.Len(t, rowErrors, 1)
+	assert.Equal(t, 5, rowErrors[0].Row)
+
+	groceries := transactions[0]
+	assert.Equal(t, 2, groceries.Row)
+	assert.Equal(t, "2024-03-15", groceries.Date.Format("2006-01-02"))
+	assert.Equal(t, "Whole Foods", groceries.Description)
+	assert.Equal(t, domain.TransactionTypeExpense, groceries.Type)
+	assert.Equal(t, domain.Money(123450), groceries.Amount)
+	assert.Equal(t, []string{"family", "weekly"}, groceries.Tags)
+	assert.False(t, groceries.Transfer)
+
+	assert.Equal(t, domain.TransactionTypeIncome, transactions[1].Type)
+	assert.Equal(t, "March", transactions[1].Notes)
+	assert.True(t, transactions[2].Transfer)
+}
+
+func TestParseImportFile_YNAB(t *testing.T) {
+	t.Run("register CSV", func(t *testing.T) {
+		data := "Account,Flag,Date,Payee,Category Group/Category,Category Group,Category,Memo,Outflow,Inflow,Cleared\n" +
+			"Checking,,03/15/2024,Corner Cafe,Everyday Expenses: Dining Out,Everyday Expenses,Dining Out,,$4.50,$0.00,Cleared\n" +
+			"Checking,,03/16/2024,Employer,Inflow: Ready to Assign,Inflow,Ready to Assign,Salary,$0.00,\"$2,000.00\",Cleared\n" +
+			"Checking,,03/17/2024,Transfer : Savings,,,,,$100.00,$0.00,Cleared\n"
+
+		transactions, rowErrors, err := ParseImportFile(domain.ImportSourceYNAB, []byte(data))
+		require.NoError(t, err)
+		assert.Empty(t, rowErrors)
+		require.Len(t, transactions, 3)
+
+		assert.Equal(t, "Everyday Expenses: Dining Out", transactions[0].Category)
+		assert.Equal(t, domain.TransactionTypeExpense, transactions[0].Type)
+		assert.Equal(t, domain.Money(450), transactions[0].Amount)
+		assert.Equal(t, domain.TransactionTypeIncome, transactions[1].Type)
+		assert.Equal(t, domain.Money(200000), transactions[1].Amount)
+		assert.True(t, transactions[2].Transfer)
+	})
+
+	t.Run("API JSON", func(t *testing.T) {
+		data := `{"data":{"transactions":[
+			{"date":"2024-03-15","amount":-4505,"payee_name":"Corner Cafe","category_name":"Dining Out"},
+			{"date":"2024-03-16","amount":-1000,"payee_name":null,"category_name":"Groceries","deleted":true},
+			{"date":"2024-03-17","amount":-100000,"payee_name":"Transfer : Savings","transfer_account_id":"abc"},
+			{"date":"03/18/2024","amount":1000}
+		]}}`
+
+		transactions, rowErrors, err := ParseImportFile(domain.ImportSourceYNAB, []byte(data))
+		require.NoError(t, err)
+		require.Len(t, transactions, 2)
+		require.Len(t, rowErrors, 1)
+		assert.Equal(t, 4, rowErrors[0].Row)
+
+		assert.Equal(t, domain.Money(451), transactions[0].Amount)
+		assert.Equal(t, domain.TransactionTypeExpense, transactions[0].Type)
+		assert.True(t, transactions[1].Transfer)
+	})
+}
+
+func TestParseImportFile_PersonalCapital(t *testing.T) {
+	data := "Date,Account,Description,Category,Tags,Amount\n" +
+		"2024-03-15,Visa,Shell,Gasoline/Fuel,\"car, commute\",(45.10)\n" +
+		"2024-03-16,Checking,Dividend,Dividends Received,,12.34\n" +
+		"2024-03-17,Checking,Payment,Credit Card Payments,,-500.00\n"
+
+	transactions, rowErrors, err := ParseImportFile(domain.ImportSourcePersonalCapital, []byte(data))
+	require.NoError(t, err)
+	assert.Empty(t, rowErrors)
+	require.Len(t, transactions, 3)
+
+	assert.Equal(t, domain.TransactionTypeExpense, transactions[0].Type)
+	assert.Equal(t, domain.Money(4510), transactions[0].Amount)
+	assert.Equal(t, []string{"car", "commute"}, transactions[0].Tags)
+	assert.Equal(t, domain.TransactionTypeIncome, transactions[1].Type)
+	assert.True(t, transactions[2].Transfer)
+}
+
+func TestParseImportFile_Invalid(t *testing.T) {
+	_, _, err := ParseImportFile(domain.ImportSourceMint, []byte("Date,Description,Amount\n3/15/2024,Cafe,4.50\n"))
+	assert.ErrorIs(t, err, domain.ErrInvalidImportFile)
+
+	_, _, err = ParseImportFile(domain.ImportSourceYNAB, []byte("{not json"))
+	assert.ErrorIs(t, err, domain.ErrInvalidImportFile)
+
+	_, _, err = ParseImportFile(domain.ImportSourcePersonalCapital, nil)
+	assert.ErrorIs(t, err, domain.ErrInvalidImportFile)
+
+	_, _, err = ParseImportFile("quicken", []byte("Date\n"))
+	assert.ErrorIs(t, err, domain.ErrUnknownImportSource)
+}
+
+func FuzzParseImportFile(f *testing.F) {
+	sources := []string{domain.ImportSourceMint, domain.ImportSourceYNAB, domain.ImportSourcePersonalCapital}
+	f.Add(uint8(0), []byte("Date,Description,Amount,Transaction Type,Category\n3/15/2024,Cafe,\"1,234.50\",debit,Food\n"))
+	f.Add(uint8(1), []byte("Date,Payee,Outflow,Inflow\n03/15/2024,Cafe,$4.50,\n"))
+	f.Add(uint8(1), []byte(`{"data":{"transactions":[{"date":"2024-03-15","amount":-4500,"payee_name":"Cafe"}]}}`))
+	f.Add(uint8(2), []byte("Date,Description,Category,Amount,Tags\n2024-03-15,Cafe,Food,(4.50),\"a, b\"\n"))
+	f.Add(uint8(2), []byte("\xef\xbb\xbfDate,Description,Category,Amount\n\"2024-03-15,Cafe\n"))
+
+	f.Fuzz(func(t *testing.T, source uint8, data []byte) {
+		transactions, rowErrors, err := ParseImportFile(sources[int(source)%len(sources)], data)
+		if err != nil {
+			require.ErrorIs(t, err, domain.ErrInvalidImportFile)
+			return
+		}
+		lastRow := 0
+		for _, transaction := range transactions {
+			assert.Greater(t, transaction.Row, lastRow, "rows are in file order")
+			lastRow = transaction.Row
+			assert.GreaterOrEqual(t, transaction.Amount, domain.Money(0))
+			assert.Contains(t, []string{domain.TransactionTypeIncome, domain.TransactionTypeExpense}, transaction.Type)
+			assert.False(t, transaction.Date.IsZero())
+		}
+		for _, rowError := range rowErrors {
+			assert.Positive(t, rowError.Row)
+			assert.NotEmpty(t, rowError.Message)
+		}
+	})
+}
+
+func FuzzParseImportAmount(f *testing.F) {
+	for _, seed := range []string{"$1,234.50", "-12.00", "(12.00)", "€ 8", "", "()", "-(1)", "1.234", "9223372036854775807"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, value string) {
+		amount, err := parseImportAmount(value)
+		if err != nil {
+			return
+		}
+		again, err := parseImportAmount(amount.String())
+		require.NoError(t, err, "%q parsed to %s", value, amount)
+		assert.Equal(t, amount, again)
+	})
+}
@@ -0,0 +1,96 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// LLMProvider completes a conversation of a system instruction and a user
+// message with a large language model
+type LLMProvider interface {
+	Complete(ctx context.Context, system, prompt string) (string, error)
+}
+
+// ChatCompletionsProvider talks to an OpenAI compatible chat completions
+// API, which OpenAI, Azure OpenAI, Ollama and most hosted models offer
+type ChatCompletionsProvider struct {
+	BaseURL string
+	APIKey  string
+	Model   string
+	client  *http.Client
+}
+
+// NewChatCompletionsProvider creates a provider for the API at baseURL,
+// e.g. https://api.openai.com/v1
+func NewChatCompletionsProvider(baseURL, apiKey, model string, timeout time.Duration) *ChatCompletionsProvider {
+	return &ChatCompletionsProvider{
+		BaseURL: strings.TrimRight(baseURL, "/"),
+		APIKey:  apiKey,
+		Model:   model,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// Complete asks the model for a deterministic reply and returns its text
+func (p *ChatCompletionsProvider) Complete(ctx context.Context, system, prompt string) (string, error) {
+	body, err := json.Marshal(map[string]any{
+		"model":       p.Model,
+		"temperature": 0,
+		"messages":    []chatMessage{{Role: "system", Content: system}, {Role: "user", Content: prompt}},
+	})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.BaseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create LLM request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.APIKey)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("LLM request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("LLM API returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	var result struct {
+		Choices []struct {
+			Message chatMessage `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode LLM response: %w", err)
+	}
+	if len(result.Choices) == 0 {
+		return "", fmt.Errorf("LLM response has no choices")
+	}
+	return result.Choices[0].Message.Content, nil
+}
+
+// NewLLMProvider returns a chat completions provider when a model is
+// configured and nil otherwise
+func NewLLMProvider(baseURL, apiKey, model string, timeout time.Duration) LLMProvider {
+	if baseURL == "" || model == "" {
+		return nil
+	}
+	return NewChatCompletionsProvider(baseURL, apiKey, model, timeout)
+}
@@ -0,0 +1,49 @@
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChatCompletionsProvider_Complete(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+
+		var body struct {
+			Model    string        `json:"model"`
+			Messages []chatMessage `json:"messages"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		if body.Model != "small-model" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": "model not found"}`))
+			return
+		}
+		assert.Equal(t, []chatMessage{{Role: "system", Content: "Be brief."}, {Role: "user", Content: "Hello"}}, body.Messages)
+		_, _ = w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "Hi!"}}]}`))
+	}))
+	defer server.Close()
+
+	provider := NewChatCompletionsProvider(server.URL+"/v1/", "secret", "small-model", time.Second)
+	reply, err := provider.Complete(context.Background(), "Be brief.", "Hello")
+	require.NoError(t, err)
+	assert.Equal(t, "Hi!", reply)
+
+	provider.Model = "missing-model"
+	_, err = provider.Complete(context.Background(), "Be brief.", "Hello")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 404")
+}
+
+func TestNewLLMProvider(t *testing.T) {
+	assert.Nil(t, NewLLMProvider("https://api.openai.com/v1", "", "", time.Second), "no model configured")
+	assert.NotNil(t, NewLLMProvider("https://api.openai.com/v1", "", "gpt-4o-mini", time.Second))
+}
@@ -0,0 +1,285 @@
+package pkg
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrMarketUnavailable is returned when a provider's circuit breaker is open
+// and there are no earlier prices to fall back on
+var ErrMarketUnavailable = errors.New("market data provider unavailable")
+
+// ErrUnexpectedResponse is returned when a provider's response decodes but
+// lacks the prices, which is how a change to its schema shows
+var ErrUnexpectedResponse = errors.New("unexpected market data response")
+
+// checkCryptoPrices rejects coins without a symbol or price, so a renamed
+// field fails the fetch rather than zeroing the prices analyses are based on
+func checkCryptoPrices(cryptos []CryptoPrice) error {
+	for i := range cryptos {
+		if cryptos[i].Symbol == "" || cryptos[i].Price <= 0 {
+			return fmt.Errorf("%w: coin %d has no symbol or current_price", ErrUnexpectedResponse, i)
+		}
+	}
+	return nil
+}
+
+// alphaVantageNotice is what Alpha Vantage answers, still with 200 OK,
+// instead of the data when it rejects or rate limits a call
+type alphaVantageNotice struct {
+	Note         string `json:"Note"`
+	Information  string `json:"Information"`
+	ErrorMessage string `json:"Error Message"`
+}
+
+func (n alphaVantageNotice) err() error {
+	if message := cmp.Or(n.ErrorMessage, n.Information, n.Note); message != "" {
+		return fmt.Errorf("alpha vantage: %s", message)
+	}
+	return nil
+}
+
+// parseGlobalQuote reads the fields of an Alpha Vantage global quote, which
+// holds its numbers as strings such as "234.2200" or "1.9722%"
+func parseGlobalQuote(symbol string, quote map[string]string) (*StockPrice, error) {
+	invalid := func(key string) error {
+		return fmt.Errorf("%w: quote for %s has no valid %q", ErrUnexpectedResponse, symbol, key)
+	}
+	price, err := parseQuoteNumber(quote["05. price"])
+	if err != nil || price <= 0 {
+		return nil, invalid("05. price")
+	}
+	change, err := parseQuoteNumber(quote["09. change"])
+	if err != nil {
+		return nil, invalid("09. change")
+	}
+	percent, ok := strings.CutSuffix(quote["10. change percent"], "%")
+	changePct, err := parseQuoteNumber(percent)
+	if !ok || err != nil {
+		return nil, invalid("10. change percent")
+	}
+	volume, err := strconv.ParseInt(quote["06. volume"], 10, 64)
+	if err != nil || volume < 0 {
+		return nil, invalid("06. volume")
+	}
+	return &StockPrice{Symbol: symbol, Price: price, Change: change, ChangePct: changePct, Volume: volume}, nil
+}
+
+// parseQuoteNumber reads a decimal number sent as a string. Unlike Sscanf it
+// rejects anything around the number, and NaN and infinities.
+func parseQuoteNumber(value string) (float64, error) {
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(number) || math.IsInf(number, 0) {
+		return 0, fmt.Errorf("invalid number %q", value)
+	}
+	return number, nil
+}
+
+// circuitBreaker stops calling a provider for cooldown after threshold
+// failures in a row. Once the cooldown is over requests are let through
+// again, and the next failure opens it straight away.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openUntil time.Time
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown}
+}
+
+// Allow reports whether the provider may be called
+func (b *circuitBreaker) Allow() bool {
+	if b == nil || b.threshold <= 0 {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.failures < b.threshold || !time.Now().Before(b.openUntil)
+}
+
+func (b *circuitBreaker) Success() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+}
+
+func (b *circuitBreaker) Failure() {
+	if b == nil || b.threshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openUntil = time.Now().Add(b.cooldown)
+	}
+}
+
+// marketProvider holds what the requests to one provider share
+type marketProvider struct {
+	name    string
+	limiter *rateLimiter
+	breaker *circuitBreaker
+	timeout time.Duration
+}
+
+// provider returns the shared state of the named provider
+func (s *RealTimeMarketService) provider(name string) *marketProvider {
+	s.providersMu.Lock()
+	defer s.providersMu.Unlock()
+
+	if p, ok := s.providers[name]; ok {
+		return p
+	}
+	interval, timeout := s.config.AlphaVantageRateInterval, s.config.AlphaVantageTimeout
+	if name == providerCoinGecko {
+		interval, timeout = s.config.CoinGeckoRateInterval, s.config.CoinGeckoTimeout
+	}
+	if s.providers == nil {
+		s.providers = make(map[string]*marketProvider)
+	}
+	p := &marketProvider{
+		name:    name,
+		limiter: newRateLimiter(interval.Std()),
+		breaker: newCircuitBreaker(s.config.BreakerThreshold, s.config.BreakerCooldown.Std()),
+		timeout: timeout.Std(),
+	}
+	s.providers[name] = p
+	return p
+}
+
+// fetchJSON decodes the provider's JSON response at url into out. Network
+// errors, rate limiting and server errors are retried with backoff, and
+// failures count towards the provider's circuit breaker; cancelling ctx does not.
+func (s *RealTimeMarketService) fetchJSON(ctx context.Context, p *marketProvider, url string, header http.Header, out interface{}) error {
+	if !p.breaker.Allow() {
+		return fmt.Errorf("%s: %w", p.name, ErrMarketUnavailable)
+	}
+
+	backoff := s.config.RetryBackoff.Std()
+	for attempt := 0; ; attempt++ {
+		if err := p.limiter.Wait(ctx); err != nil {
+			return err
+		}
+		retry, err := s.attemptJSON(ctx, p, url, header, out)
+		if err == nil {
+			p.breaker.Success()
+			return nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if !retry || attempt >= s.config.MaxRetries {
+			p.breaker.Failure()
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+// attemptJSON makes one request, reporting whether a failure is worth retrying
+func (s *RealTimeMarketService) attemptJSON(
+	ctx context.Context, p *marketProvider, url string, header http.Header, out interface{},
+) (retry bool, err error) {
+	if p.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return false, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	resp, err := s.httpClient().Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			// Log error if needed, but don't fail the function
+		}
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		retry = resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return retry, fmt.Errorf("provider returned %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return false, fmt.Errorf("invalid response: %w", err)
+	}
+	return false, nil
+}
+
+// lastKnownPrices keeps the latest prices fetched from each provider, served
+// marked as stale when the provider cannot be reached
+type lastKnownPrices struct {
+	mu      sync.RWMutex
+	cryptos []CryptoPrice
+	stocks  map[string]StockPrice
+}
+
+func (l *lastKnownPrices) rememberCryptos(cryptos []CryptoPrice) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.cryptos = append([]CryptoPrice(nil), cryptos...)
+}
+
+func (l *lastKnownPrices) staleCryptos() ([]CryptoPrice, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if len(l.cryptos) == 0 {
+		return nil, false
+	}
+	cryptos := append([]CryptoPrice(nil), l.cryptos...)
+	for i := range cryptos {
+		cryptos[i].Stale = true
+	}
+	return cryptos, true
+}
+
+func (l *lastKnownPrices) rememberStock(stock StockPrice) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.stocks == nil {
+		l.stocks = make(map[string]StockPrice)
+	}
+	l.stocks[stock.Symbol] = stock
+}
+
+func (l *lastKnownPrices) staleStock(symbol string) (StockPrice, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	stock, ok := l.stocks[symbol]
+	stock.Stale = true
+	return stock, ok
+}
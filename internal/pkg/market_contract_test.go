@@ -0,0 +1,253 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go-finance-advisor/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The market contract tests parse responses recorded from CoinGecko and
+// Alpha Vantage in testdata/market, so a change to a provider's schema fails
+// a parse here instead of zeroing prices in AnalyzeMarket.
+//
+//	MARKET_CONTRACT_LIVE=1 go test -run MarketContract ./internal/pkg/
+//
+// runs the same checks against the real providers, as the nightly workflow
+// does, and MARKET_CONTRACT_RECORD=1 as well records their responses over
+// the cassettes. Alpha Vantage's demo key only quotes IBM and searches
+// "tesco"; set ALPHA_VANTAGE_API_KEY for the rest.
+var (
+	marketContractLive   = os.Getenv("MARKET_CONTRACT_LIVE") != ""
+	marketContractRecord = os.Getenv("MARKET_CONTRACT_RECORD") != ""
+)
+
+// marketCassette replays a recorded response for every request, or with
+// live set passes the request on to the provider and records its response
+type marketCassette struct {
+	t         *testing.T
+	live      bool
+	record    bool
+	overrides map[string][]byte // Responses served in place of cassettes, by name
+}
+
+func (c *marketCassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	name := cassetteName(req.URL)
+	path := filepath.Join("testdata", "market", name)
+	if c.live {
+		resp, err := http.DefaultTransport.RoundTrip(req)
+		if err != nil || !c.record || resp.StatusCode != http.StatusOK {
+			return resp, err
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(path, body, 0o600); err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		return resp, nil
+	}
+
+	body, ok := c.overrides[name]
+	if !ok {
+		var err error
+		if body, err = os.ReadFile(path); err != nil {
+			c.t.Errorf("no recorded response for %s: %v", name, err)
+			return nil, err
+		}
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+// cassetteName names the recording of a provider request, such as
+// coingecko_coins_markets.json or alphavantage_global_quote_ibm.json
+func cassetteName(u *url.URL) string {
+	query := u.Query()
+	if function := query.Get("function"); function != "" {
+		return strings.ToLower("alphavantage_" + function + "_" + query.Get("symbol") + query.Get("keywords") + ".json")
+	}
+	path := strings.Trim(strings.TrimPrefix(u.Path, "/api/v3"), "/")
+	return "coingecko_" + strings.ReplaceAll(path, "/", "_") + ".json"
+}
+
+// newContractService returns a market service talking to the cassettes, or
+// to the real providers in live mode
+func newContractService(t *testing.T) (*RealTimeMarketService, *marketCassette) {
+	t.Helper()
+	cfg := config.Default().Market
+	cassette := &marketCassette{t: t, live: marketContractLive, record: marketContractRecord}
+	if marketContractLive {
+		if key := os.Getenv("ALPHA_VANTAGE_API_KEY"); key != "" {
+			cfg.AlphaVantageAPIKey = key
+		}
+		cfg.CoinGeckoAPIKey = os.Getenv("COINGECKO_API_KEY")
+	} else {
+		cfg.AlphaVantageRateInterval = 0
+		cfg.MaxRetries = 0
+	}
+	service := NewRealTimeMarketServiceWithConfig(cfg)
+	service.client.Transport = cassette
+	return service, cassette
+}
+
+func TestMarketContract_CoinGeckoMarkets(t *testing.T) {
+	service, _ := newContractService(t)
+
+	cryptos, err := service.GetCryptoPrices(context.Background())
+	require.NoError(t, err)
+	require.Len(t, cryptos, 10)
+	for _, crypto := range cryptos {
+		assert.False(t, crypto.Stale)
+		assert.NotEmpty(t, crypto.Symbol)
+		assert.NotEmpty(t, crypto.Name, crypto.Symbol)
+		assert.Positive(t, crypto.Price, crypto.Symbol)
+		assert.Positive(t, crypto.MarketCap, crypto.Symbol)
+		assert.Positive(t, crypto.Volume24h, crypto.Symbol)
+	}
+}
+
+func TestMarketContract_AlphaVantageGlobalQuote(t *testing.T) {
+	service, _ := newContractService(t)
+
+	stock, err := service.fetchStockQuote(context.Background(), service.providerConfig(), "IBM")
+	require.NoError(t, err)
+	assert.Equal(t, "IBM", stock.Symbol)
+	assert.Positive(t, stock.Price)
+	assert.Positive(t, stock.Volume)
+	assert.InDelta(t, stock.Change/(stock.Price-stock.Change)*100, stock.ChangePct, 0.01,
+		"the change percent is parsed from its %-suffixed form")
+}
+
+func TestMarketContract_AlphaVantageSymbolSearch(t *testing.T) {
+	service, _ := newContractService(t)
+
+	matches, err := service.SearchSymbols(context.Background(), "tesco")
+	require.NoError(t, err)
+	require.NotEmpty(t, matches)
+	for _, match := range matches {
+		assert.NotEmpty(t, match.Symbol)
+		assert.NotEmpty(t, match.Name, match.Symbol)
+		assert.NotEmpty(t, match.Currency, match.Symbol)
+		assert.Positive(t, match.MatchScore, match.Symbol)
+	}
+}
+
+func TestMarketContract_AnalyzeMarket(t *testing.T) {
+	if marketContractLive && os.Getenv("ALPHA_VANTAGE_API_KEY") == "" {
+		t.Skip("the Alpha Vantage demo key cannot quote the default symbols")
+	}
+	service, _ := newContractService(t)
+
+	analysis, err := service.AnalyzeMarket(context.Background())
+	require.NoError(t, err)
+	assert.False(t, analysis.Stale)
+	assert.NotEmpty(t, analysis.Cryptos)
+	for _, crypto := range analysis.Cryptos {
+		assert.Positive(t, crypto.Price, crypto.Symbol)
+	}
+	require.Len(t, analysis.Stocks, len(DefaultStockSymbols))
+	for _, stock := range analysis.Stocks {
+		assert.Positive(t, stock.Price, stock.Symbol)
+	}
+}
+
+// TestMarketContract_SchemaChanges replays the cassettes with the changes a
+// provider could make to them, which have to fail rather than parse to zeroes
+func TestMarketContract_SchemaChanges(t *testing.T) {
+	if marketContractLive {
+		t.Skip("replays edited cassettes only")
+	}
+	edit := func(t *testing.T, name, old, new string) []byte {
+		data, err := os.ReadFile(filepath.Join("testdata", "market", name))
+		require.NoError(t, err)
+		require.Contains(t, string(data), old)
+		return bytes.ReplaceAll(data, []byte(old), []byte(new))
+	}
+
+	t.Run("renamed coin price", func(t *testing.T) {
+		service, cassette := newContractService(t)
+		cassette.overrides = map[string][]byte{
+			"coingecko_coins_markets.json": edit(t, "coingecko_coins_markets.json", `"current_price"`, `"price"`),
+		}
+
+		_, err := service.GetCryptoPrices(context.Background())
+		assert.ErrorIs(t, err, ErrUnexpectedResponse)
+	})
+
+	t.Run("renamed quote price", func(t *testing.T) {
+		service, cassette := newContractService(t)
+		cassette.overrides = map[string][]byte{
+			"alphavantage_global_quote_ibm.json": edit(t, "alphavantage_global_quote_ibm.json", `"05. price"`, `"05. last"`),
+		}
+
+		_, err := service.fetchStockQuote(context.Background(), service.providerConfig(), "IBM")
+		assert.ErrorIs(t, err, ErrUnexpectedResponse)
+	})
+
+	t.Run("quote change percent without its sign", func(t *testing.T) {
+		service, cassette := newContractService(t)
+		cassette.overrides = map[string][]byte{
+			"alphavantage_global_quote_ibm.json": edit(t, "alphavantage_global_quote_ibm.json", `"1.9722%"`, `"n/a"`),
+		}
+
+		_, err := service.fetchStockQuote(context.Background(), service.providerConfig(), "IBM")
+		assert.ErrorIs(t, err, ErrUnexpectedResponse)
+	})
+
+	t.Run("rate limited", func(t *testing.T) {
+		service, cassette := newContractService(t)
+		limited := []byte(`{"Information": "Thank you for using Alpha Vantage! Our standard API rate limit is 25 requests per day."}`)
+		cassette.overrides = map[string][]byte{
+			"alphavantage_global_quote_ibm.json":    limited,
+			"alphavantage_symbol_search_tesco.json": limited,
+		}
+
+		_, err := service.fetchStockQuote(context.Background(), service.providerConfig(), "IBM")
+		assert.ErrorContains(t, err, "rate limit")
+		_, err = service.SearchSymbols(context.Background(), "tesco")
+		assert.ErrorContains(t, err, "rate limit", "a rejected search is not an empty one")
+	})
+
+	t.Run("analysis does not run on zeroed prices", func(t *testing.T) {
+		service, cassette := newContractService(t)
+		cassette.overrides = map[string][]byte{
+			"coingecko_coins_markets.json": edit(t, "coingecko_coins_markets.json", `"current_price"`, `"price"`),
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, err := service.AnalyzeMarket(ctx)
+		assert.ErrorIs(t, err, ErrUnexpectedResponse)
+	})
+}
+
+func TestCassetteName(t *testing.T) {
+	for rawURL, want := range map[string]string{
+		"https://api.coingecko.com/api/v3/coins/markets?vs_currency=usd":                 "coingecko_coins_markets.json",
+		"https://www.alphavantage.co/query?function=GLOBAL_QUOTE&symbol=IBM&apikey=demo": "alphavantage_global_quote_ibm.json",
+		"https://www.alphavantage.co/query?function=SYMBOL_SEARCH&keywords=tesco":        "alphavantage_symbol_search_tesco.json",
+	} {
+		u, err := url.Parse(rawURL)
+		require.NoError(t, err)
+		assert.Equal(t, want, cassetteName(u))
+	}
+}
@@ -4,9 +4,10 @@ package pkg
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"net/http"
-	"time"
+	"net/url"
+
+	"go-finance-advisor/internal/config"
 )
 
 // MarketData represents current market prices
@@ -15,18 +16,26 @@ type MarketData struct {
 	SP500Price   float64 `json:"sp500"`
 }
 
-// FetchMarketData fetches real-time market data from APIs
-func FetchMarketData() (*MarketData, error) {
-	client := &http.Client{Timeout: 10 * time.Second}
-	ctx := context.Background()
+// FetchMarketData fetches real-time market data from APIs using the default provider settings
+func FetchMarketData(ctx context.Context) (*MarketData, error) {
+	return FetchMarketDataWithConfig(ctx, config.Default().Market)
+}
+
+// FetchMarketDataWithConfig fetches real-time market data from the configured
+// providers. Cancelling ctx aborts any request still in flight.
+func FetchMarketDataWithConfig(ctx context.Context, cfg config.MarketConfig) (*MarketData, error) {
+	client := &http.Client{Timeout: cfg.RequestTimeout.Std()}
 
 	// Default fallback values
 	bitcoinPrice := 45000.0
 	sp500Price := 4500.0
 
 	// 1. Bitcoin Price (CoinGecko)
-	btcURL := "https://api.coingecko.com/api/v3/simple/price?ids=bitcoin&vs_currencies=usd"
+	btcURL := cfg.CoinGeckoBaseURL + "/simple/price?ids=bitcoin&vs_currencies=usd"
 	btcReq, _ := http.NewRequestWithContext(ctx, "GET", btcURL, http.NoBody)
+	if cfg.CoinGeckoAPIKey != "" {
+		btcReq.Header.Set("x-cg-demo-api-key", cfg.CoinGeckoAPIKey)
+	}
 	btcResp, err := client.Do(btcReq)
 	if err == nil && btcResp.StatusCode == http.StatusOK {
 		defer func() {
@@ -45,8 +54,7 @@ func FetchMarketData() (*MarketData, error) {
 	}
 
 	// 2. S&P 500 Price (Alpha Vantage)
-	// Using demo key, real usage requires API key
-	sp500URL := "https://www.alphavantage.co/query?function=GLOBAL_QUOTE&symbol=SPX&apikey=demo"
+	sp500URL := cfg.AlphaVantageBaseURL + "/query?function=GLOBAL_QUOTE&symbol=SPX&apikey=" + url.QueryEscape(cfg.AlphaVantageAPIKey)
 	sp500Req, _ := http.NewRequestWithContext(ctx, "GET", sp500URL, http.NoBody)
 	sp500Resp, err := client.Do(sp500Req)
 	if err == nil && sp500Resp.StatusCode == http.StatusOK {
@@ -58,15 +66,18 @@ func FetchMarketData() (*MarketData, error) {
 		var sp500Data map[string]map[string]string
 		if err := json.NewDecoder(sp500Resp.Body).Decode(&sp500Data); err == nil {
 			if globalQuote, ok := sp500Data["Global Quote"]; ok {
-				if priceStr, ok := globalQuote["05. price"]; ok {
-					if _, scanErr := fmt.Sscanf(priceStr, "%f", &sp500Price); scanErr != nil {
-						// Log error if needed, but don't fail the function
-					}
+				if price, err := parseQuoteNumber(globalQuote["05. price"]); err == nil && price > 0 {
+					sp500Price = price
 				}
 			}
 		}
 	}
 
+	// A cancelled caller gets the error rather than the fallback prices
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	return &MarketData{
 		BitcoinPrice: bitcoinPrice,
 		SP500Price:   sp500Price,
@@ -0,0 +1,332 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// PushMessage is a notification for one device. Data reaches the app
+// alongside the visible title and body, e.g. what the notification is about.
+type PushMessage struct {
+	Token string
+	Title string
+	Body  string
+	Data  map[string]string
+}
+
+// PushSender delivers push notifications to the devices of one platform.
+// Tokens the service no longer knows return domain.ErrInvalidPushToken.
+type PushSender interface {
+	Send(ctx context.Context, message PushMessage) error
+}
+
+// NewPushSenders returns the senders whose credentials are set, by device
+// platform. The FCM credentials file is a Firebase service account key; the
+// APNs key file is the .p8 token signing key of the Apple developer account.
+func NewPushSenders(fcmURL, fcmCredentialsFile, apnsURL, apnsKeyFile, apnsKeyID, apnsTeamID, apnsTopic string) (map[string]PushSender, error) {
+	senders := map[string]PushSender{}
+	if fcmCredentialsFile != "" {
+		credentials, err := os.ReadFile(fcmCredentialsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the FCM credentials: %w", err)
+		}
+		sender, err := NewFCMSender(fcmURL, credentials)
+		if err != nil {
+			return nil, err
+		}
+		senders[domain.DevicePlatformAndroid] = sender
+	}
+	if apnsKeyFile != "" {
+		key, err := os.ReadFile(apnsKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the APNs key: %w", err)
+		}
+		sender, err := NewAPNsSender(apnsURL, key, apnsKeyID, apnsTeamID, apnsTopic)
+		if err != nil {
+			return nil, err
+		}
+		senders[domain.DevicePlatformIOS] = sender
+	}
+	return senders, nil
+}
+
+const (
+	// pushHTTPTimeout bounds each request to a push service
+	pushHTTPTimeout = 15 * time.Second
+	// fcmScope is the OAuth scope of the FCM HTTP v1 API
+	fcmScope = "https://www.googleapis.com/auth/firebase.messaging"
+	// apnsTokenLifetime is how long an APNs provider token is reused; Apple
+	// rejects tokens older than an hour and ones renewed more often than
+	// every 20 minutes
+	apnsTokenLifetime = 50 * time.Minute
+)
+
+// FCMSender sends through the Firebase Cloud Messaging HTTP v1 API,
+// authorized with OAuth access tokens of a service account
+type FCMSender struct {
+	BaseURL   string
+	ProjectID string
+	email     string
+	key       *rsa.PrivateKey
+	tokenURL  string
+	client    *http.Client
+	now       func() time.Time
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+// NewFCMSender creates a sender for the FCM API at baseURL, normally
+// https://fcm.googleapis.com, from a service account key in JSON
+func NewFCMSender(baseURL string, credentials []byte) (*FCMSender, error) {
+	var account struct {
+		ProjectID   string `json:"project_id"`
+		ClientEmail string `json:"client_email"`
+		PrivateKey  string `json:"private_key"`
+		TokenURI    string `json:"token_uri"`
+	}
+	if err := json.Unmarshal(credentials, &account); err != nil {
+		return nil, fmt.Errorf("invalid FCM credentials: %w", err)
+	}
+	if account.ProjectID == "" || account.ClientEmail == "" || account.TokenURI == "" {
+		return nil, errors.New("FCM credentials need a project ID, client email and token URI")
+	}
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(account.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid FCM private key: %w", err)
+	}
+	return &FCMSender{
+		BaseURL:   strings.TrimRight(baseURL, "/"),
+		ProjectID: account.ProjectID,
+		email:     account.ClientEmail,
+		key:       key,
+		tokenURL:  account.TokenURI,
+		client:    &http.Client{Timeout: pushHTTPTimeout},
+		now:       time.Now,
+	}, nil
+}
+
+// Send posts the message to the device's token
+func (s *FCMSender) Send(ctx context.Context, message PushMessage) error {
+	accessToken, err := s.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]any{"message": map[string]any{
+		"token":        message.Token,
+		"notification": map[string]string{"title": message.Title, "body": message.Body},
+		"data":         message.Data,
+	}}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		s.BaseURL+"/v1/projects/"+url.PathEscape(s.ProjectID)+"/messages:send", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("FCM request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var failure struct {
+		Error struct {
+			Status  string `json:"status"`
+			Message string `json:"message"`
+			Details []struct {
+				ErrorCode string `json:"errorCode"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&failure)
+	for _, detail := range failure.Error.Details {
+		if detail.ErrorCode == "UNREGISTERED" {
+			return domain.ErrInvalidPushToken
+		}
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return domain.ErrInvalidPushToken
+	}
+	return fmt.Errorf("FCM returned status %d: %s", resp.StatusCode, failure.Error.Message)
+}
+
+// token returns an OAuth access token for the service account, exchanging a
+// signed assertion for a new one shortly before the last one expires
+func (s *FCMSender) token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if s.accessToken != "" && now.Before(s.expiresAt.Add(-time.Minute)) {
+		return s.accessToken, nil
+	}
+
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   s.email,
+		"scope": fcmScope,
+		"aud":   s.tokenURL,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}).SignedString(s.key)
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("FCM token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("FCM token request returned status %d", resp.StatusCode)
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("invalid FCM token response: %w", err)
+	}
+	s.accessToken, s.expiresAt = token.AccessToken, now.Add(time.Duration(token.ExpiresIn)*time.Second)
+	return s.accessToken, nil
+}
+
+// APNsSender sends through Apple's HTTP/2 push API, authorized with provider
+// tokens signed by the account's signing key
+type APNsSender struct {
+	BaseURL string
+	KeyID   string
+	TeamID  string
+	Topic   string // The app's bundle ID
+	key     *ecdsa.PrivateKey
+	client  *http.Client
+	now     func() time.Time
+
+	mu       sync.Mutex
+	token    string
+	issuedAt time.Time
+}
+
+// NewAPNsSender creates a sender for the APNs API at baseURL, normally
+// https://api.push.apple.com or https://api.sandbox.push.apple.com for
+// development builds, from the PEM encoded .p8 key
+func NewAPNsSender(baseURL string, key []byte, keyID, teamID, topic string) (*APNsSender, error) {
+	if keyID == "" || teamID == "" || topic == "" {
+		return nil, errors.New("APNs needs a key ID, team ID and topic")
+	}
+	signingKey, err := jwt.ParseECPrivateKeyFromPEM(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid APNs key: %w", err)
+	}
+	return &APNsSender{
+		BaseURL: strings.TrimRight(baseURL, "/"),
+		KeyID:   keyID,
+		TeamID:  teamID,
+		Topic:   topic,
+		key:     signingKey,
+		client:  &http.Client{Timeout: pushHTTPTimeout},
+		now:     time.Now,
+	}, nil
+}
+
+// Send posts the message as an alert to the device's token
+func (s *APNsSender) Send(ctx context.Context, message PushMessage) error {
+	token, err := s.providerToken()
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]any{"aps": map[string]any{
+		"alert": map[string]string{"title": message.Title, "body": message.Body},
+		"sound": "default",
+	}}
+	for key, value := range message.Data {
+		if key != "aps" {
+			payload[key] = value
+		}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.BaseURL+"/3/device/"+url.PathEscape(message.Token), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "bearer "+token)
+	req.Header.Set("apns-topic", s.Topic)
+	req.Header.Set("apns-push-type", "alert")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("APNs request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var failure struct {
+		Reason string `json:"reason"`
+	}
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&failure)
+	if resp.StatusCode == http.StatusGone || failure.Reason == "BadDeviceToken" || failure.Reason == "Unregistered" {
+		return domain.ErrInvalidPushToken
+	}
+	return fmt.Errorf("APNs returned status %d: %s", resp.StatusCode, failure.Reason)
+}
+
+// providerToken returns the signed provider token, renewing it once it is
+// apnsTokenLifetime old
+func (s *APNsSender) providerToken() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if s.token != "" && now.Sub(s.issuedAt) < apnsTokenLifetime {
+		return s.token, nil
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{"iss": s.TeamID, "iat": now.Unix()})
+	token.Header["kid"] = s.KeyID
+	signed, err := token.SignedString(s.key)
+	if err != nil {
+		return "", err
+	}
+	s.token, s.issuedAt = signed, now
+	return signed, nil
+}
@@ -0,0 +1,139 @@
+package pkg
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-finance-advisor/internal/domain"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFCMSender(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	tokenRequests := 0
+	var sent []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			tokenRequests++
+			require.NoError(t, r.ParseForm())
+			claims := jwt.MapClaims{}
+			_, err := jwt.ParseWithClaims(r.Form.Get("assertion"), claims, func(*jwt.Token) (any, error) { return &key.PublicKey, nil })
+			require.NoError(t, err)
+			assert.Equal(t, "push@project.iam.gserviceaccount.com", claims["iss"])
+			assert.Equal(t, fcmScope, claims["scope"])
+			_, _ = w.Write([]byte(`{"access_token": "ya29.token", "expires_in": 3600}`))
+		case "/v1/projects/finance-app/messages:send":
+			assert.Equal(t, "Bearer ya29.token", r.Header.Get("Authorization"))
+			var body map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			message := body["message"].(map[string]any)
+			if message["token"] == "stale" {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"error": {"status": "NOT_FOUND", "details": [{"errorCode": "UNREGISTERED"}]}}`))
+				return
+			}
+			sent = append(sent, message)
+			_, _ = w.Write([]byte(`{"name": "projects/finance-app/messages/1"}`))
+		default:
+			t.Fatalf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	credentials, err := json.Marshal(map[string]string{
+		"project_id": "finance-app", "client_email": "push@project.iam.gserviceaccount.com",
+		"private_key": string(keyPEM), "token_uri": server.URL + "/token",
+	})
+	require.NoError(t, err)
+	sender, err := NewFCMSender(server.URL, credentials)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	message := PushMessage{Token: "device-1", Title: "Budget exceeded", Body: "Groceries budget is over", Data: map[string]string{"type": "budget"}}
+	require.NoError(t, sender.Send(ctx, message))
+	require.NoError(t, sender.Send(ctx, message))
+	assert.Equal(t, 1, tokenRequests, "the access token is reused until it expires")
+	require.Len(t, sent, 2)
+	assert.Equal(t, map[string]any{"title": "Budget exceeded", "body": "Groceries budget is over"}, sent[0]["notification"])
+	assert.Equal(t, map[string]any{"type": "budget"}, sent[0]["data"])
+
+	message.Token = "stale"
+	assert.ErrorIs(t, sender.Send(ctx, message), domain.ErrInvalidPushToken)
+
+	_, err = NewFCMSender(server.URL, []byte(`{"project_id": "finance-app"}`))
+	assert.Error(t, err)
+}
+
+func TestAPNsSender(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	var payload map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "com.example.finance", r.Header.Get("apns-topic"))
+		assert.Equal(t, "alert", r.Header.Get("apns-push-type"))
+		claims := jwt.MapClaims{}
+		token, err := jwt.ParseWithClaims(strings.TrimPrefix(r.Header.Get("Authorization"), "bearer "), claims,
+			func(*jwt.Token) (any, error) { return &key.PublicKey, nil })
+		require.NoError(t, err)
+		assert.Equal(t, "KEY123", token.Header["kid"])
+		assert.Equal(t, "TEAM456", claims["iss"])
+
+		switch r.URL.Path {
+		case "/3/device/gone":
+			w.WriteHeader(http.StatusGone)
+			_, _ = w.Write([]byte(`{"reason": "Unregistered"}`))
+		case "/3/device/broken":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"reason": "PayloadEmpty"}`))
+		default:
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		}
+	}))
+	defer server.Close()
+
+	sender, err := NewAPNsSender(server.URL, keyPEM, "KEY123", "TEAM456", "com.example.finance")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, sender.Send(ctx, PushMessage{Token: "abc", Title: "AAPL above 200", Body: "AAPL is at 201.50", Data: map[string]string{"type": "price_alert"}}))
+	assert.Equal(t, map[string]any{"title": "AAPL above 200", "body": "AAPL is at 201.50"}, payload["aps"].(map[string]any)["alert"])
+	assert.Equal(t, "price_alert", payload["type"])
+
+	assert.ErrorIs(t, sender.Send(ctx, PushMessage{Token: "gone"}), domain.ErrInvalidPushToken)
+	err = sender.Send(ctx, PushMessage{Token: "broken"})
+	assert.ErrorContains(t, err, "PayloadEmpty")
+	assert.NotErrorIs(t, err, domain.ErrInvalidPushToken)
+
+	_, err = NewAPNsSender(server.URL, keyPEM, "", "TEAM456", "com.example.finance")
+	assert.Error(t, err)
+}
+
+func TestNewPushSenders(t *testing.T) {
+	senders, err := NewPushSenders("", "", "", "", "", "", "")
+	require.NoError(t, err)
+	assert.Empty(t, senders)
+
+	_, err = NewPushSenders("https://fcm.googleapis.com", "/missing/credentials.json", "", "", "", "", "")
+	assert.ErrorContains(t, err, "FCM credentials")
+}
@@ -0,0 +1,308 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+)
+
+// OCRProvider extracts raw text from a receipt image
+type OCRProvider interface {
+	ExtractText(ctx context.Context, image []byte, contentType string) (string, error)
+}
+
+// TesseractOCRProvider runs the local tesseract binary on the image
+type TesseractOCRProvider struct {
+	BinaryPath string
+	Language   string
+}
+
+// NewTesseractOCRProvider creates a provider using the tesseract binary found in PATH
+func NewTesseractOCRProvider() *TesseractOCRProvider {
+	return &TesseractOCRProvider{BinaryPath: "tesseract", Language: "eng"}
+}
+
+// ExtractText pipes the image through tesseract and returns the recognized text
+func (p *TesseractOCRProvider) ExtractText(ctx context.Context, image []byte, _ string) (string, error) {
+	if len(image) == 0 {
+		return "", fmt.Errorf("empty image")
+	}
+
+	// "stdin" and "stdout" tell tesseract to read the image and write the text through pipes
+	cmd := exec.CommandContext(ctx, p.BinaryPath, "stdin", "stdout", "-l", p.Language)
+	cmd.Stdin = bytes.NewReader(image)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("tesseract failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.String(), nil
+}
+
+// ExternalOCRProvider sends the image to an HTTP OCR API. The API is expected
+// to accept the raw image as the request body and respond with {"text": "..."}.
+type ExternalOCRProvider struct {
+	Endpoint string
+	APIKey   string
+	client   *http.Client
+}
+
+// NewExternalOCRProvider creates a provider for the given OCR API endpoint
+func NewExternalOCRProvider(endpoint, apiKey string) *ExternalOCRProvider {
+	return &ExternalOCRProvider{
+		Endpoint: endpoint,
+		APIKey:   apiKey,
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// ExtractText posts the image to the OCR API and returns the recognized text
+func (p *ExternalOCRProvider) ExtractText(ctx context.Context, image []byte, contentType string) (string, error) {
+	if len(image) == 0 {
+		return "", fmt.Errorf("empty image")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.Endpoint, bytes.NewReader(image))
+	if err != nil {
+		return "", fmt.Errorf("failed to create OCR request: %w", err)
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	req.Header.Set("Content-Type", contentType)
+	if p.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.APIKey)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("OCR request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("OCR API returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var result struct {
+		Text string `json:"text"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode OCR response: %w", err)
+	}
+
+	return result.Text, nil
+}
+
+// NewOCRProvider returns an external API provider when an endpoint is configured
+// and falls back to the local tesseract binary otherwise
+func NewOCRProvider(endpoint, apiKey string) OCRProvider {
+	if endpoint != "" {
+		return NewExternalOCRProvider(endpoint, apiKey)
+	}
+	return NewTesseractOCRProvider()
+}
+
+var (
+	receiptAmountPattern = regexp.MustCompile(`(\d{1,3}(?:[.,]\d{3})+|\d+)[.,](\d{2})\b`)
+	receiptDatePatterns  = []struct {
+		pattern *regexp.Regexp
+		layouts []string
+	}{
+		{regexp.MustCompile(`\b\d{4}-\d{2}-\d{2}\b`), []string{"2006-01-02"}},
+		{regexp.MustCompile(`\b\d{1,2}/\d{1,2}/\d{4}\b`), []string{"01/02/2006", "1/2/2006", "02/01/2006"}},
+		{regexp.MustCompile(`\b\d{1,2}/\d{1,2}/\d{2}\b`), []string{"01/02/06", "1/2/06"}},
+		{regexp.MustCompile(`\b\d{1,2}\.\d{1,2}\.\d{4}\b`), []string{"02.01.2006", "2.1.2006"}},
+		{regexp.MustCompile(`(?i)\b[a-z]{3} \d{1,2},? \d{4}\b`), []string{"Jan 2, 2006", "Jan 2 2006"}},
+	}
+	// Keywords are checked in order, so the most specific total labels come first
+	receiptTotalKeywords = []string{"grand total", "amount due", "total due", "balance due", "total"}
+	receiptSkipKeywords  = []string{"subtotal", "sub total", "tax", "change", "tip"}
+	// Lines about paying for the purchase rather than listing it
+	receiptPaymentKeywords = []string{"cash", "card", "visa", "mastercard", "paid"}
+	// Quantities are printed before the item ("2 x Coffee") or before its
+	// unit price ("Coffee 2 x 3.50")
+	receiptQuantityPrefix = regexp.MustCompile(`(?i)^(\d{1,3})\s*[x*]\s+`)
+	receiptQuantitySuffix = regexp.MustCompile(`(?i)\s(\d{1,3})\s*[x*@]$`)
+)
+
+// maxReceiptItemName keeps item names within what transactions store
+const maxReceiptItemName = 100
+
+// ParseReceiptText extracts merchant, date, total amount and line items from OCR text.
+// Fields that cannot be found are left empty so the user can fill them in.
+func ParseReceiptText(text string) *domain.ReceiptData {
+	data := &domain.ReceiptData{RawText: text}
+
+	lines := make([]string, 0)
+	for _, line := range strings.Split(text, "\n") {
+		if trimmed := strings.TrimSpace(line); trimmed != "" {
+			lines = append(lines, trimmed)
+		}
+	}
+
+	data.Merchant = findReceiptMerchant(lines)
+	data.Date = findReceiptDate(lines)
+	data.Amount = findReceiptTotal(lines)
+	data.Items = findReceiptItems(lines)
+
+	return data
+}
+
+// findReceiptMerchant uses the first line that looks like a name; receipts
+// almost always print the store name at the top
+func findReceiptMerchant(lines []string) string {
+	for _, line := range lines {
+		if receiptAmountPattern.MatchString(line) || findDateInLine(line) != nil {
+			continue
+		}
+		if countLetters(line) >= 3 {
+			return line
+		}
+	}
+	return ""
+}
+
+func countLetters(s string) int {
+	letters := 0
+	for _, r := range s {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
+			letters++
+		}
+	}
+	return letters
+}
+
+func findReceiptDate(lines []string) *time.Time {
+	for _, line := range lines {
+		if date := findDateInLine(line); date != nil {
+			return date
+		}
+	}
+	return nil
+}
+
+func findDateInLine(line string) *time.Time {
+	for _, candidate := range receiptDatePatterns {
+		match := candidate.pattern.FindString(line)
+		if match == "" {
+			continue
+		}
+		for _, layout := range candidate.layouts {
+			if date, err := time.Parse(layout, match); err == nil {
+				return &date
+			}
+		}
+	}
+	return nil
+}
+
+// findReceiptTotal prefers amounts on lines labelled as totals and falls back
+// to the largest amount printed on the receipt
+func findReceiptTotal(lines []string) domain.Money {
+	for _, keyword := range receiptTotalKeywords {
+		for i := len(lines) - 1; i >= 0; i-- {
+			lower := strings.ToLower(lines[i])
+			if !strings.Contains(lower, keyword) || containsAny(lower, receiptSkipKeywords) {
+				continue
+			}
+			if amounts := findAmounts(lines[i]); len(amounts) > 0 {
+				return amounts[len(amounts)-1]
+			}
+		}
+	}
+
+	var largest domain.Money
+	for _, line := range lines {
+		for _, amount := range findAmounts(line) {
+			if amount > largest {
+				largest = amount
+			}
+		}
+	}
+	return largest
+}
+
+// findReceiptItems reads the lines that price a purchase, like "Apples 4.50"
+// or "2 x Coffee 7.00", as line items: the text before the first amount
+// names the item and the last amount is what it cost in all. Totals, taxes
+// and payments are not items.
+func findReceiptItems(lines []string) []domain.TransactionItem {
+	var items []domain.TransactionItem
+	for _, line := range lines {
+		lower := strings.ToLower(line)
+		if findDateInLine(line) != nil || containsAny(lower, receiptTotalKeywords) ||
+			containsAny(lower, receiptSkipKeywords) || containsAny(lower, receiptPaymentKeywords) {
+			continue
+		}
+		first := receiptAmountPattern.FindStringIndex(line)
+		amounts := findAmounts(line)
+		if first == nil || len(amounts) == 0 {
+			continue
+		}
+
+		name, quantity := strings.TrimSpace(line[:first[0]]), 1.0
+		if match := receiptQuantityPrefix.FindStringSubmatch(name); match != nil {
+			quantity, _ = strconv.ParseFloat(match[1], 64)
+			name = name[len(match[0]):]
+		} else if match := receiptQuantitySuffix.FindStringSubmatch(name); match != nil {
+			quantity, _ = strconv.ParseFloat(match[1], 64)
+			name = name[:len(name)-len(match[0])]
+		}
+		name = strings.TrimRight(strings.TrimSpace(name), " .:-$€£")
+		if countLetters(name) < 2 || quantity <= 0 {
+			continue
+		}
+		if runes := []rune(name); len(runes) > maxReceiptItemName {
+			name = string(runes[:maxReceiptItemName])
+		}
+
+		total := amounts[len(amounts)-1]
+		items = append(items, domain.TransactionItem{
+			Position:  len(items),
+			Name:      name,
+			Quantity:  quantity,
+			UnitPrice: total.Mul(1 / quantity),
+			Amount:    total,
+		})
+	}
+	return items
+}
+
+func findAmounts(line string) []domain.Money {
+	matches := receiptAmountPattern.FindAllStringSubmatch(line, -1)
+	amounts := make([]domain.Money, 0, len(matches))
+	for _, match := range matches {
+		// Thousands separators can be either "," or "." depending on locale
+		whole := strings.NewReplacer(",", "", ".", "").Replace(match[1])
+		amount, err := domain.ParseMoney(whole + "." + match[2])
+		if err == nil {
+			amounts = append(amounts, amount)
+		}
+	}
+	return amounts
+}
+
+func containsAny(s string, keywords []string) bool {
+	for _, keyword := range keywords {
+		if strings.Contains(s, keyword) {
+			return true
+		}
+	}
+	return false
+}
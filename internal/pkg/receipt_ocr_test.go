@@ -0,0 +1,155 @@
+package pkg
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReceiptText(t *testing.T) {
+	tests := []struct {
+		name         string
+		text         string
+		wantMerchant string
+		wantDate     string
+		wantAmount   float64
+	}{
+		{
+			name:         "typical grocery receipt",
+			text:         "  FRESH MARKET  \n123 Main St\n03/15/2024 14:22\nApples 4.50\nSUBTOTAL 4.50\nTAX 0.36\nTOTAL 4.86\nCHANGE 5.14\n",
+			wantMerchant: "FRESH MARKET",
+			wantDate:     "2024-03-15",
+			wantAmount:   4.86,
+		},
+		{
+			name:         "amount due preferred over total",
+			text:         "Cafe Luna\n2024-01-05\nTotal 12.00\nTip 2.00\nAmount Due 14.00\n",
+			wantMerchant: "Cafe Luna",
+			wantDate:     "2024-01-05",
+			wantAmount:   14.00,
+		},
+		{
+			name:         "european formatting",
+			text:         "Baeckerei Schmidt\n15.03.2024\nSumme\nTotal EUR 1.234,50\n",
+			wantMerchant: "Baeckerei Schmidt",
+			wantDate:     "2024-03-15",
+			wantAmount:   1234.50,
+		},
+		{
+			name:         "falls back to largest amount",
+			text:         "Hardware Hub\nMar 2, 2024\nHammer 15.99\nNails 3.25\n",
+			wantMerchant: "Hardware Hub",
+			wantDate:     "2024-03-02",
+			wantAmount:   15.99,
+		},
+		{
+			name: "nothing recognizable",
+			text: "\n\n12\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := ParseReceiptText(tt.text)
+
+			assert.Equal(t, tt.wantMerchant, data.Merchant)
+			assert.Equal(t, tt.wantAmount, data.Amount.Float64())
+			assert.Equal(t, tt.text, data.RawText)
+			if tt.wantDate == "" {
+				assert.Nil(t, data.Date)
+			} else {
+				require.NotNil(t, data.Date)
+				assert.Equal(t, tt.wantDate, data.Date.Format("2006-01-02"))
+			}
+		})
+	}
+}
+
+func TestParseReceiptText_Items(t *testing.T) {
+	type item struct {
+		name      string
+		quantity  float64
+		unitPrice float64
+	}
+	tests := []struct {
+		name string
+		text string
+		want []item
+	}{
+		{
+			name: "one item per priced line",
+			text: "FRESH MARKET\n03/15/2024 14:22\nApples 4.50\nBread 2.20\nSUBTOTAL 6.70\nTAX 0.36\nTOTAL 7.06\nCASH 10.00\n",
+			want: []item{{"Apples", 1, 4.50}, {"Bread", 1, 2.20}},
+		},
+		{
+			name: "quantities before the item or its unit price",
+			text: "Cafe Luna\n2 x Coffee 7.00\nCroissant 3 x 1.50 4.50\nTotal 11.50\n",
+			want: []item{{"Coffee", 2, 3.50}, {"Croissant", 3, 1.50}},
+		},
+		{
+			name: "no priced lines",
+			text: "Hardware Hub\nThank you\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := ParseReceiptText(tt.text).Items
+
+			require.Len(t, items, len(tt.want))
+			for i, want := range tt.want {
+				assert.Equal(t, want.name, items[i].Name)
+				assert.Equal(t, want.quantity, items[i].Quantity)
+				assert.Equal(t, want.unitPrice, items[i].UnitPrice.Float64())
+				assert.Equal(t, i, items[i].Position)
+			}
+		})
+	}
+}
+
+func TestExternalOCRProvider_ExtractText(t *testing.T) {
+	t.Run("should post image and return text", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+			assert.Equal(t, "image/jpeg", r.Header.Get("Content-Type"))
+			body, _ := io.ReadAll(r.Body)
+			assert.Equal(t, "image-bytes", string(body))
+			_, _ = w.Write([]byte(`{"text": "Shop\nTotal 9.99"}`))
+		}))
+		defer server.Close()
+
+		provider := NewExternalOCRProvider(server.URL, "secret")
+		text, err := provider.ExtractText(context.Background(), []byte("image-bytes"), "image/jpeg")
+
+		require.NoError(t, err)
+		assert.Equal(t, "Shop\nTotal 9.99", text)
+	})
+
+	t.Run("should return error on non-200 status", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer server.Close()
+
+		provider := NewExternalOCRProvider(server.URL, "")
+		_, err := provider.ExtractText(context.Background(), []byte("image-bytes"), "image/jpeg")
+		assert.Error(t, err)
+	})
+
+	t.Run("should reject empty image", func(t *testing.T) {
+		provider := NewExternalOCRProvider("http://localhost", "")
+		_, err := provider.ExtractText(context.Background(), nil, "")
+		assert.Error(t, err)
+	})
+}
+
+func TestNewOCRProvider(t *testing.T) {
+	assert.IsType(t, &ExternalOCRProvider{}, NewOCRProvider("http://ocr.example.com", "key"))
+	assert.IsType(t, &TesseractOCRProvider{}, NewOCRProvider("", ""))
+}
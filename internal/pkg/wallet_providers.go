@@ -0,0 +1,239 @@
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-finance-advisor/internal/domain"
+)
+
+// WalletProvider reads what a blockchain's public API reports for an
+// address. No keys are involved; the APIs only ever see public addresses.
+type WalletProvider interface {
+	// Snapshot returns the address's confirmed balance and latest transactions
+	Snapshot(ctx context.Context, address string) (*domain.WalletSnapshot, error)
+}
+
+// NewWalletProviders returns the providers of the supported chains by chain
+func NewWalletProviders(btcBaseURL, ethBaseURL string) map[string]WalletProvider {
+	return map[string]WalletProvider{
+		domain.WalletChainBTC: NewEsploraProvider(btcBaseURL),
+		domain.WalletChainETH: NewBlockscoutProvider(ethBaseURL),
+	}
+}
+
+// walletHTTPTimeout bounds each request to a blockchain API
+const walletHTTPTimeout = 20 * time.Second
+
+// Coins are counted in satoshis on Bitcoin and wei on Ethereum
+const (
+	satoshisPerBitcoin = 1e8
+	weiPerEther        = 1e18
+)
+
+// EsploraProvider reads Bitcoin addresses from an Esplora API such as
+// https://blockstream.info/api
+type EsploraProvider struct {
+	BaseURL string
+	client  *http.Client
+}
+
+func NewEsploraProvider(baseURL string) *EsploraProvider {
+	return &EsploraProvider{BaseURL: strings.TrimRight(baseURL, "/"), client: &http.Client{Timeout: walletHTTPTimeout}}
+}
+
+// esploraOutput is a transaction input's previous output or an output
+type esploraOutput struct {
+	Address string `json:"scriptpubkey_address"`
+	Value   int64  `json:"value"`
+}
+
+// Snapshot reads the address's confirmed balance and its latest transactions,
+// which Esplora lists newest and unconfirmed first. Each transaction's amount
+// is what its outputs paid the address less what the address put into it.
+func (p *EsploraProvider) Snapshot(ctx context.Context, address string) (*domain.WalletSnapshot, error) {
+	path := "/address/" + url.PathEscape(address)
+	var stats struct {
+		ChainStats struct {
+			Funded int64 `json:"funded_txo_sum"`
+			Spent  int64 `json:"spent_txo_sum"`
+		} `json:"chain_stats"`
+	}
+	if err := getJSON(ctx, p.client, "esplora", p.BaseURL+path, &stats); err != nil {
+		return nil, err
+	}
+
+	var transactions []struct {
+		TxID   string `json:"txid"`
+		Fee    int64  `json:"fee"`
+		Status struct {
+			Confirmed bool  `json:"confirmed"`
+			BlockTime int64 `json:"block_time"`
+		} `json:"status"`
+		Inputs []struct {
+			Previous *esploraOutput `json:"prevout"`
+		} `json:"vin"`
+		Outputs []esploraOutput `json:"vout"`
+	}
+	if err := getJSON(ctx, p.client, "esplora", p.BaseURL+path+"/txs", &transactions); err != nil {
+		return nil, err
+	}
+
+	snapshot := &domain.WalletSnapshot{Balance: float64(stats.ChainStats.Funded-stats.ChainStats.Spent) / satoshisPerBitcoin}
+	for _, tx := range transactions {
+		var received, spent int64
+		for _, input := range tx.Inputs {
+			if input.Previous != nil && input.Previous.Address == address {
+				spent += input.Previous.Value
+			}
+		}
+		for _, output := range tx.Outputs {
+			if output.Address == address {
+				received += output.Value
+			}
+		}
+		transaction := domain.WalletTransaction{
+			Hash: tx.TxID, Amount: float64(received-spent) / satoshisPerBitcoin, Confirmed: tx.Status.Confirmed,
+		}
+		if spent > 0 {
+			transaction.Fee = float64(tx.Fee) / satoshisPerBitcoin
+		}
+		if tx.Status.Confirmed {
+			at := time.Unix(tx.Status.BlockTime, 0).UTC()
+			transaction.Time = &at
+		}
+		snapshot.Transactions = append(snapshot.Transactions, transaction)
+	}
+	return snapshot, nil
+}
+
+// BlockscoutProvider reads Ethereum addresses from a Blockscout instance such
+// as https://eth.blockscout.com. Only transfers of ether itself are read,
+// not tokens.
+type BlockscoutProvider struct {
+	BaseURL string
+	client  *http.Client
+}
+
+func NewBlockscoutProvider(baseURL string) *BlockscoutProvider {
+	return &BlockscoutProvider{BaseURL: strings.TrimRight(baseURL, "/"), client: &http.Client{Timeout: walletHTTPTimeout}}
+}
+
+// blockscoutAddress is the sender or recipient of a transaction. The
+// recipient is missing for contract creations.
+type blockscoutAddress struct {
+	Hash string `json:"hash"`
+}
+
+// Snapshot reads the address's balance and the latest page of its
+// transactions. Transactions the address sent cost their value and fee;
+// failed ones only cost the fee.
+func (p *BlockscoutProvider) Snapshot(ctx context.Context, address string) (*domain.WalletSnapshot, error) {
+	path := "/api/v2/addresses/" + url.PathEscape(address)
+	var account struct {
+		CoinBalance *string `json:"coin_balance"`
+	}
+	if err := getJSON(ctx, p.client, "blockscout", p.BaseURL+path, &account); err != nil {
+		return nil, err
+	}
+
+	var page struct {
+		Items []struct {
+			Hash        string             `json:"hash"`
+			Value       string             `json:"value"`
+			Status      string             `json:"status"`
+			BlockNumber *int64             `json:"block_number"`
+			Timestamp   *time.Time         `json:"timestamp"`
+			From        blockscoutAddress  `json:"from"`
+			To          *blockscoutAddress `json:"to"`
+			Fee         struct {
+				Value string `json:"value"`
+			} `json:"fee"`
+		} `json:"items"`
+	}
+	if err := getJSON(ctx, p.client, "blockscout", p.BaseURL+path+"/transactions", &page); err != nil {
+		return nil, err
+	}
+
+	snapshot := &domain.WalletSnapshot{}
+	if account.CoinBalance != nil {
+		balance, err := weiToEther(*account.CoinBalance)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Balance = balance
+	}
+	for _, tx := range page.Items {
+		value, err := weiToEther(tx.Value)
+		if err != nil {
+			return nil, err
+		}
+		fee, err := weiToEther(tx.Fee.Value)
+		if err != nil {
+			return nil, err
+		}
+		if tx.Status == "error" {
+			value = 0
+		}
+
+		transaction := domain.WalletTransaction{Hash: tx.Hash, Confirmed: tx.BlockNumber != nil, Time: tx.Timestamp}
+		if tx.To != nil && strings.EqualFold(tx.To.Hash, address) {
+			transaction.Amount += value
+		}
+		if strings.EqualFold(tx.From.Hash, address) {
+			transaction.Amount -= value + fee
+			transaction.Fee = fee
+		}
+		if !transaction.Confirmed {
+			transaction.Time = nil
+		}
+		snapshot.Transactions = append(snapshot.Transactions, transaction)
+	}
+	return snapshot, nil
+}
+
+// weiToEther converts a decimal amount of wei, empty for none, to ether
+func weiToEther(wei string) (float64, error) {
+	if wei == "" {
+		return 0, nil
+	}
+	amount, ok := new(big.Float).SetString(wei)
+	if !ok {
+		return 0, fmt.Errorf("invalid wei amount %q", wei)
+	}
+	ether, _ := amount.Quo(amount, big.NewFloat(weiPerEther)).Float64()
+	return ether, nil
+}
+
+// getJSON fetches a JSON document from a public API
+func getJSON(ctx context.Context, client *http.Client, api, endpoint string, response any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create %s request: %w", api, err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s request failed: %w", api, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read %s response: %w", api, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d: %s", api, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if err := json.Unmarshal(data, response); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", api, err)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package pkg
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEsploraProvider(t *testing.T) {
+	const address = "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/address/" + address:
+			_, _ = w.Write([]byte(`{"chain_stats":{"funded_txo_sum":250000000,"spent_txo_sum":100000000,"tx_count":2},
+				"mempool_stats":{"funded_txo_sum":5000,"spent_txo_sum":0}}`))
+		case "/address/" + address + "/txs":
+			_, _ = w.Write([]byte(`[
+				{"txid":"pending","fee":200,"status":{"confirmed":false},
+				 "vin":[{"prevout":{"scriptpubkey_address":"other","value":10000}}],
+				 "vout":[{"scriptpubkey_address":"` + address + `","value":5000},{"scriptpubkey_address":"other","value":4800}]},
+				{"txid":"spend","fee":1000,"status":{"confirmed":true,"block_time":1710000000},
+				 "vin":[{"prevout":{"scriptpubkey_address":"` + address + `","value":250000000}}],
+				 "vout":[{"scriptpubkey_address":"other","value":100000000},{"scriptpubkey_address":"` + address + `","value":149999000}]},
+				{"txid":"coinbase","fee":0,"status":{"confirmed":true,"block_time":1700000000},
+				 "vin":[{"prevout":null}],
+				 "vout":[{"scriptpubkey_address":"` + address + `","value":250000000}]}
+			]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("Address not found"))
+		}
+	}))
+	defer server.Close()
+
+	provider := NewWalletProviders(server.URL+"/", server.URL)["BTC"]
+	snapshot, err := provider.Snapshot(context.Background(), address)
+	require.NoError(t, err)
+	assert.InDelta(t, 1.5, snapshot.Balance, 1e-9, "only the confirmed balance counts")
+	require.Len(t, snapshot.Transactions, 3)
+
+	pending := snapshot.Transactions[0]
+	assert.InDelta(t, 0.00005, pending.Amount, 1e-12)
+	assert.Zero(t, pending.Fee, "the address did not pay for it")
+	assert.False(t, pending.Confirmed)
+	assert.Nil(t, pending.Time)
+
+	spend := snapshot.Transactions[1]
+	assert.InDelta(t, -1.00001, spend.Amount, 1e-12)
+	assert.InDelta(t, 0.00001, spend.Fee, 1e-12)
+	assert.Equal(t, time.Unix(1710000000, 0).UTC(), *spend.Time)
+
+	assert.InDelta(t, 2.5, snapshot.Transactions[2].Amount, 1e-12)
+
+	_, err = provider.Snapshot(context.Background(), "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa")
+	assert.ErrorContains(t, err, "esplora returned status 404: Address not found")
+}
+
+func TestBlockscoutProvider(t *testing.T) {
+	const address = "0xde0b295669a9fd93d5f28d9ec85e40f4cb697bae"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/addresses/" + address:
+			_, _ = w.Write([]byte(`{"hash":"0xde0B295669a9FD93d5F28D9Ec85E40f4cb697BAe","coin_balance":"1500000000000000000"}`))
+		case "/api/v2/addresses/" + address + "/transactions":
+			_, _ = w.Write([]byte(`{"items":[
+				{"hash":"0xpending","value":"0","status":null,"block_number":null,"timestamp":null,
+				 "from":{"hash":"0xDE0B295669A9FD93D5F28D9EC85E40F4CB697BAE"},"to":null,"fee":{"value":"1000000000000000"}},
+				{"hash":"0xfailed","value":"500000000000000000","status":"error","block_number":2,"timestamp":"2024-03-02T00:00:00Z",
+				 "from":{"hash":"0xDE0B295669A9FD93D5F28D9EC85E40F4CB697BAE"},"to":{"hash":"0x0000000000000000000000000000000000000001"},
+				 "fee":{"value":"1000000000000000"}},
+				{"hash":"0xreceived","value":"2000000000000000000","status":"ok","block_number":1,"timestamp":"2024-03-01T00:00:00Z",
+				 "from":{"hash":"0x0000000000000000000000000000000000000001"},"to":{"hash":"0xDE0B295669A9FD93D5F28D9EC85E40F4CB697BAE"},
+				 "fee":{"value":"21000000000000"}}
+			],"next_page_params":null}`))
+		default:
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		}
+	}))
+	defer server.Close()
+
+	provider := NewWalletProviders(server.URL, server.URL+"/")["ETH"]
+	snapshot, err := provider.Snapshot(context.Background(), address)
+	require.NoError(t, err)
+	assert.InDelta(t, 1.5, snapshot.Balance, 1e-12)
+	require.Len(t, snapshot.Transactions, 3)
+
+	pending := snapshot.Transactions[0]
+	assert.False(t, pending.Confirmed)
+	assert.Nil(t, pending.Time)
+	assert.InDelta(t, -0.001, pending.Amount, 1e-12)
+
+	failed := snapshot.Transactions[1]
+	assert.InDelta(t, -0.001, failed.Amount, 1e-12, "a failed transfer only costs its fee")
+	assert.InDelta(t, 0.001, failed.Fee, 1e-12)
+
+	received := snapshot.Transactions[2]
+	assert.InDelta(t, 2, received.Amount, 1e-12)
+	assert.Zero(t, received.Fee, "the sender paid the fee")
+	assert.True(t, received.Confirmed)
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), received.Time.UTC())
+
+	_, err = provider.Snapshot(context.Background(), "0x0000000000000000000000000000000000000002")
+	assert.ErrorContains(t, err, "blockscout returned status 422")
+}
@@ -34,7 +34,7 @@ func TestGenerateAdvice(t *testing.T) {
 		LastName:  "User",
 		Email:     "test@example.com",
 	}
-	err := userRepo.Create(user)
+	err := userRepo.Create(context.Background(), user)
 	assert.NoError(t, err)
 
 	// Create test category
@@ -49,7 +49,7 @@ func TestGenerateAdvice(t *testing.T) {
 		{
 			ID:          1,
 			UserID:      1,
-			Amount:      -50.0,
+			Amount:      domain.NewMoney(-50.0),
 			Description: "Grocery shopping",
 			CategoryID:  1,
 			Date:        time.Now(),
@@ -57,7 +57,7 @@ func TestGenerateAdvice(t *testing.T) {
 		{
 			ID:          2,
 			UserID:      1,
-			Amount:      -30.0,
+			Amount:      domain.NewMoney(-30.0),
 			Description: "Restaurant",
 			CategoryID:  1,
 			Date:        time.Now().AddDate(0, 0, -1),
@@ -65,7 +65,7 @@ func TestGenerateAdvice(t *testing.T) {
 	}
 
 	for _, transaction := range transactions {
-		createErr := transactionRepo.Create(transaction)
+		createErr := transactionRepo.Create(context.Background(), transaction)
 		assert.NoError(t, createErr)
 	}
 
@@ -95,19 +95,19 @@ func TestGenerateAdviceConservative(t *testing.T) {
 
 	// Create a conservative user
 	user := domain.User{RiskTolerance: "conservative"}
-	userService.Create(&user)
+	userService.Create(context.Background(), &user)
 
 	// Create a transaction
 	tx := domain.Transaction{
 		UserID:      1,
 		Type:        "income",
 		Description: "Salary",
-		Amount:      3000.0,
+		Amount:      domain.NewMoney(3000.0),
 		CategoryID:  1,
 		Date:        time.Now().AddDate(0, -1, 0),
 	}
 	txService := &application.TransactionService{DB: db}
-	txService.Create(&tx)
+	txService.Create(context.Background(), &tx)
 
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
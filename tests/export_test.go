@@ -44,7 +44,7 @@ func TestExportCSV(t *testing.T) {
 	transaction := domain.Transaction{
 		UserID:      user.ID,
 		CategoryID:  category.ID,
-		Amount:      100.50,
+		Amount:      domain.NewMoney(100.50),
 		Type:        "expense",
 		Description: "Test transaction",
 		Date:        time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
@@ -100,7 +100,7 @@ func TestExportPDF(t *testing.T) {
 	transaction := domain.Transaction{
 		UserID:      user.ID,
 		CategoryID:  category.ID,
-		Amount:      100.50,
+		Amount:      domain.NewMoney(100.50),
 		Type:        "expense",
 		Description: "Test transaction",
 		Date:        time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
@@ -2,6 +2,7 @@ package tests
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCreateTransaction(t *testing.T) {
@@ -64,10 +66,10 @@ func TestCreateTransaction(t *testing.T) {
 	assert.Equal(t, http.StatusCreated, w.Code)
 
 	// Verify transaction was created
-	transactions, err := transactionRepo.ListByUser(1)
+	transactions, err := transactionRepo.ListByUser(context.Background(), 1)
 	assert.NoError(t, err)
 	assert.Len(t, transactions, 1)
-	assert.Equal(t, 50.0, transactions[0].Amount)
+	assert.Equal(t, domain.NewMoney(50.0), transactions[0].Amount)
 	assert.Equal(t, "Test transaction", transactions[0].Description)
 }
 
@@ -97,14 +99,14 @@ func TestListTransactions(t *testing.T) {
 	transactions := []*domain.Transaction{
 		{
 			UserID:      1,
-			Amount:      -50.0,
+			Amount:      domain.NewMoney(-50.0),
 			Description: "Grocery shopping",
 			CategoryID:  1,
 			Date:        time.Now(),
 		},
 		{
 			UserID:      1,
-			Amount:      -30.0,
+			Amount:      domain.NewMoney(-30.0),
 			Description: "Restaurant",
 			CategoryID:  1,
 			Date:        time.Now().AddDate(0, 0, -1),
@@ -112,7 +114,7 @@ func TestListTransactions(t *testing.T) {
 	}
 
 	for _, transaction := range transactions {
-		err := transactionRepo.Create(transaction)
+		err := transactionRepo.Create(context.Background(), transaction)
 		assert.NoError(t, err)
 	}
 
@@ -127,10 +129,13 @@ func TestListTransactions(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, w.Code)
 
-	var response []domain.Transaction
+	var response domain.TransactionPage
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
-	assert.Len(t, response, 2)
-	assert.Equal(t, "Grocery shopping", response[0].Description)
-	assert.Equal(t, "Restaurant", response[1].Description)
+	assert.Equal(t, int64(2), response.Total)
+	require.Len(t, response.Transactions, 2)
+	assert.Equal(t, "Grocery shopping", response.Transactions[0].Description)
+	assert.Equal(t, "Restaurant", response.Transactions[1].Description)
+	assert.Empty(t, response.NextCursor)
+	assert.Empty(t, response.PrevCursor)
 }
@@ -2,6 +2,7 @@ package tests
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -25,7 +26,7 @@ func setupTestDB() *gorm.DB {
 	if err != nil {
 		panic("Failed to connect to test database: " + err.Error())
 	}
-	if err := db.AutoMigrate(&domain.User{}, &domain.Transaction{}); err != nil {
+	if err := db.AutoMigrate(&domain.User{}, &domain.Transaction{}, &domain.Comment{}); err != nil {
 		panic("Failed to migrate test database: " + err.Error())
 	}
 	return db
@@ -76,7 +77,7 @@ func TestGetUser(t *testing.T) {
 		LastName:      "User2",
 		RiskTolerance: "aggressive",
 	}
-	userService.Create(&user)
+	userService.Create(context.Background(), &user)
 
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
@@ -106,7 +107,7 @@ func TestUpdateUserRisk(t *testing.T) {
 		LastName:      "User3",
 		RiskTolerance: "moderate",
 	}
-	userService.Create(&user)
+	userService.Create(context.Background(), &user)
 
 	gin.SetMode(gin.TestMode)
 	r := gin.New()